@@ -0,0 +1,400 @@
+package main
+
+// Operator maintenance commands:
+//   go run ./cmd/admin replay-analysis [-apply] <analysis-id>...
+//   go run ./cmd/admin migrate-local-storage [-apply] [-batch n]
+//   go run ./cmd/admin encrypt-pii [-apply] [-batch n]
+//   go run ./cmd/admin replay-events -source audit|funnel [-apply] [-out file] [-since t] [-until t] [-events a,b] [-after t/key]
+//   go run ./cmd/admin rehash-job-descriptions [-apply] [-batch n]
+//
+// replay-analysis re-runs validation and normalization on stored LLM output
+// without calling the LLM, printing one JSON report per line. Pass "-" to read
+// analysis IDs from stdin, one per line, for backfills.
+//
+// migrate-local-storage copies documents uploaded before the move to S3 from
+// LOCAL_STORE_DIR into the uploads bucket and points them at the copies, or
+// flags them when their upload is gone. It prints one JSON report per document.
+//
+// encrypt-pii seals PII column values written before PII_KEYS was set and
+// re-seals values under retired keys with the first key in PII_KEYS. It prints
+// one JSON report per column; run it after every key rotation.
+//
+// replay-events re-emits historical events from the audit log or the funnel
+// milestones to the configured ANALYTICS_SINK, or as JSON lines to -out, so
+// downstream projections can be rebuilt. Events keep their idempotency keys, so
+// replaying a range twice is safe. It prints one JSON report per batch; pass a
+// report's cursor as -after to resume.
+//
+// rehash-job-descriptions recomputes job_description_hash in Go, so analyses
+// hashed by the SQL backfill in migration 0019 match new requests for the same
+// job description again. It prints one JSON report.
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/audit"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/events"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/fieldcrypt"
+	localstore "resume-backend/internal/shared/storage/object/local"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch os.Args[1] {
+	case "replay-analysis":
+		os.Exit(replayAnalysis(ctx, os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	case "migrate-local-storage":
+		os.Exit(migrateLocalStorage(ctx, os.Args[2:], os.Stdout, os.Stderr))
+	case "encrypt-pii":
+		os.Exit(encryptPII(ctx, os.Args[2:], os.Stdout, os.Stderr))
+	case "replay-events":
+		os.Exit(replayEvents(ctx, os.Args[2:], os.Stdout, os.Stderr))
+	case "rehash-job-descriptions":
+		os.Exit(rehashJobDescriptions(ctx, os.Args[2:], os.Stdout, os.Stderr))
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: admin replay-analysis [-apply] <analysis-id>... | -")
+	fmt.Fprintln(os.Stderr, "       admin migrate-local-storage [-apply] [-batch n]")
+	fmt.Fprintln(os.Stderr, "       admin encrypt-pii [-apply] [-batch n]")
+	fmt.Fprintln(os.Stderr, "       admin replay-events -source audit|funnel [-apply] [-out file] [-since t] [-until t] [-events a,b] [-after t/key]")
+	fmt.Fprintln(os.Stderr, "       admin rehash-job-descriptions [-apply] [-batch n]")
+	os.Exit(2)
+}
+
+func replayAnalysis(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("replay-analysis", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apply := flags.Bool("apply", false, "store passing results (default is a dry run)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	ids, err := analysisIDs(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "read analysis ids: %v\n", err)
+		return 2
+	}
+	if len(ids) == 0 {
+		fmt.Fprintln(stderr, "at least one analysis id is required")
+		return 2
+	}
+
+	app, err := bootstrap.Build(config.Load())
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB != nil {
+		defer app.DB.Close()
+	}
+
+	enc := json.NewEncoder(stdout)
+	var passed, failed, errored int
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		report, err := app.AnalysesService.ReplayAnalysis(ctx, id, analyses.ReplayOptions{Apply: *apply})
+		if err != nil {
+			errored++
+			fmt.Fprintf(stderr, "%s: %v\n", id, err)
+			continue
+		}
+		if report.Passed {
+			passed++
+		} else {
+			failed++
+		}
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "write report: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Fprintf(stderr, "replayed=%d passed=%d rejected=%d errors=%d apply=%v\n", passed+failed, passed, failed, errored, *apply)
+	if failed > 0 || errored > 0 {
+		return 1
+	}
+	return 0
+}
+
+func migrateLocalStorage(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate-local-storage", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apply := flags.Bool("apply", false, "copy uploads and update documents (default is a dry run)")
+	batch := flags.Int("batch", 100, "documents listed per query")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.Load()
+	app, err := bootstrap.Build(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB == nil {
+		fmt.Fprintln(stderr, "DATABASE_URL is required")
+		return 2
+	}
+	defer app.DB.Close()
+	writer, ok := app.S3Documents.(documents.ObjectWriter)
+	if !ok {
+		fmt.Fprintln(stderr, "UPLOADS_S3_BUCKET is required")
+		return 2
+	}
+
+	migration := &documents.StorageMigration{
+		Repo:      app.DocumentsRepo,
+		Local:     localstore.New(cfg.LocalStoreDir),
+		S3:        writer,
+		Apply:     *apply,
+		BatchSize: *batch,
+	}
+	enc := json.NewEncoder(stdout)
+	counts := map[string]int{}
+	err = migration.Run(ctx, func(report documents.StorageMigrationReport) error {
+		counts[report.Outcome]++
+		return enc.Encode(report)
+	})
+	fmt.Fprintf(stderr, "moved=%d unreachable=%d failed=%d apply=%v\n",
+		counts[documents.MigrationMoved], counts[documents.MigrationUnreachable], counts[documents.MigrationFailed], *apply)
+	if err != nil {
+		fmt.Fprintf(stderr, "migrate: %v\n", err)
+		return 1
+	}
+	if counts[documents.MigrationFailed] > 0 {
+		return 1
+	}
+	return 0
+}
+
+func encryptPII(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("encrypt-pii", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apply := flags.Bool("apply", false, "rewrite values (default is a dry run)")
+	batch := flags.Int("batch", 500, "rows read per query")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	app, err := bootstrap.Build(config.Load())
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB == nil {
+		fmt.Fprintln(stderr, "DATABASE_URL is required")
+		return 2
+	}
+	defer app.DB.Close()
+	if app.FieldCodec == nil {
+		fmt.Fprintln(stderr, "PII_KEYS is required")
+		return 2
+	}
+
+	backfill := &fieldcrypt.Backfill{
+		DB:        app.DB,
+		Codec:     app.FieldCodec,
+		Columns:   app.EncryptedColumns,
+		Apply:     *apply,
+		BatchSize: *batch,
+	}
+	enc := json.NewEncoder(stdout)
+	var rewritten, failed int
+	err = backfill.Run(ctx, func(report fieldcrypt.BackfillReport) error {
+		rewritten += report.Rewritten
+		failed += report.Failed
+		if report.Error != "" {
+			failed++
+		}
+		return enc.Encode(report)
+	})
+	fmt.Fprintf(stderr, "key=%s rewritten=%d failed=%d apply=%v\n", app.FieldCodec.ActiveKeyID(), rewritten, failed, *apply)
+	if err != nil {
+		fmt.Fprintf(stderr, "encrypt-pii: %v\n", err)
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func rehashJobDescriptions(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("rehash-job-descriptions", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	apply := flags.Bool("apply", false, "rewrite hashes (default is a dry run)")
+	batch := flags.Int("batch", 500, "rows read per query")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	app, err := bootstrap.Build(config.Load())
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB == nil {
+		fmt.Fprintln(stderr, "DATABASE_URL is required")
+		return 2
+	}
+	defer app.DB.Close()
+
+	rehash := &analyses.Rehash{DB: app.DB, Codec: app.FieldCodec, Apply: *apply, BatchSize: *batch}
+	report, err := rehash.Run(ctx)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if encErr := json.NewEncoder(stdout).Encode(report); encErr != nil {
+		fmt.Fprintf(stderr, "write report: %v\n", encErr)
+		return 1
+	}
+	fmt.Fprintf(stderr, "scanned=%d rewritten=%d failed=%d apply=%v\n", report.Scanned, report.Rewritten, report.Failed, *apply)
+	if err != nil {
+		fmt.Fprintf(stderr, "rehash-job-descriptions: %v\n", err)
+		return 1
+	}
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func replayEvents(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("replay-events", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	source := flags.String("source", "", "history to replay: audit or funnel")
+	apply := flags.Bool("apply", false, "send the events (default is a dry run)")
+	out := flags.String("out", "", "write JSON lines to this file instead of ANALYTICS_SINK")
+	since := flags.String("since", "", "replay events at or after this RFC 3339 time")
+	until := flags.String("until", "", "replay events before this RFC 3339 time")
+	names := flags.String("events", "", "comma-separated event names or audit actions to replay")
+	after := flags.String("after", "", "resume after a report cursor, given as <time>/<key>")
+	batch := flags.Int("batch", 500, "events read and sent per batch")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	var rng events.Range
+	var cursor events.Cursor
+	var err error
+	if rng.Since, err = parseReplayTime(*since); err != nil {
+		fmt.Fprintf(stderr, "-since: %v\n", err)
+		return 2
+	}
+	if rng.Until, err = parseReplayTime(*until); err != nil {
+		fmt.Fprintf(stderr, "-until: %v\n", err)
+		return 2
+	}
+	for _, name := range strings.Split(*names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			rng.Names = append(rng.Names, name)
+		}
+	}
+	if *after != "" {
+		at, key, _ := strings.Cut(*after, "/")
+		if cursor.At, err = parseReplayTime(at); err != nil || key == "" {
+			fmt.Fprintln(stderr, "-after must be <time>/<key> from a report cursor")
+			return 2
+		}
+		cursor.Key = key
+	}
+
+	cfg := config.Load()
+	app, err := bootstrap.Build(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB == nil {
+		fmt.Fprintln(stderr, "DATABASE_URL is required")
+		return 2
+	}
+	defer app.DB.Close()
+
+	replay := &events.Replay{Range: rng, After: cursor, Apply: *apply, BatchSize: *batch}
+	switch *source {
+	case "audit":
+		replay.Source = &audit.ReplaySource{Repo: app.AuditService.Repo, HashSalt: cfg.AnalyticsHashSalt}
+	case "funnel":
+		replay.Source = &events.MilestoneSource{DB: app.DB}
+	default:
+		fmt.Fprintln(stderr, "-source must be audit or funnel")
+		return 2
+	}
+	switch {
+	case *out != "":
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "open %s: %v\n", *out, err)
+			return 1
+		}
+		defer f.Close()
+		replay.Sink = &events.WriterSink{W: f}
+	case app.Events != nil:
+		replay.Sink = app.Events.Sink
+	case *apply:
+		fmt.Fprintln(stderr, "ANALYTICS_SINK or -out is required")
+		return 2
+	}
+
+	enc := json.NewEncoder(stdout)
+	var total, sent int
+	err = replay.Run(ctx, func(report events.ReplayReport) error {
+		total += report.Events
+		if report.Sent {
+			sent += report.Events
+		}
+		return enc.Encode(report)
+	})
+	fmt.Fprintf(stderr, "source=%s events=%d sent=%d apply=%v\n", *source, total, sent, *apply)
+	if err != nil {
+		fmt.Fprintf(stderr, "replay-events: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseReplayTime parses an RFC 3339 time; an empty value is the zero time.
+func parseReplayTime(raw string) (time.Time, error) {
+	if strings.TrimSpace(raw) == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, strings.TrimSpace(raw))
+}
+
+// analysisIDs returns the IDs given as arguments, or read from stdin for "-".
+func analysisIDs(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) != 1 || args[0] != "-" {
+		return args, nil
+	}
+	var ids []string
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, scanner.Err()
+}
@@ -1,24 +1,79 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"resume-backend/internal/bootstrap"
 	"resume-backend/internal/shared/config"
 	"resume-backend/internal/shared/server"
 )
 
+// fairnessInterval is how often the opt-in fairness monitor recomputes its report.
+const fairnessInterval = 6 * time.Hour
+
+// rolloutInterval is how often a running prompt rollout is checked for promotion
+// when no new outcomes have arrived.
+const rolloutInterval = 5 * time.Minute
+
+// sloInterval is how often SLO burn rates are checked for alerts.
+const sloInterval = time.Minute
+
 func main() {
-	cfg := config.Load()
+	cfg := config.Load().WithRole(config.RoleAPI)
 	app, err := bootstrap.Build(cfg)
 	if err != nil {
 		log.Fatalf("failed to bootstrap app: %v", err)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if app.RuntimeConfig != nil {
+		go app.RuntimeConfig.Run(ctx)
+	}
+
+	if app.FairnessMonitor != nil {
+		go app.FairnessMonitor.Run(context.Background(), fairnessInterval)
+		log.Printf("fairness monitoring enabled interval=%s", fairnessInterval)
+	}
+
+	if interval := cfg.LLMHealthProbeInterval; app.LLMHealth != nil && interval > 0 {
+		go app.LLMHealth.Run(ctx, interval)
+		log.Printf("llm health probe enabled model=%s interval=%s", app.LLMHealth.Model, interval)
+	}
+
+	go app.PromptRollout.Run(context.Background(), rolloutInterval)
+	go app.Events.Run(context.Background())
+	go app.SLO.Run(context.Background(), sloInterval)
+
+	waitJobs := func() {}
+	if cfg.Standalone() {
+		waitJobs = startStandalone(ctx, app, cfg.ShutdownGracePeriod)
+	}
+
 	addr := server.Addr(cfg.Port)
 	log.Printf("Starting API server on %s", addr)
 
-	if err := app.Router.Run(addr); err != nil {
+	srv := &http.Server{Addr: addr, Handler: app.Router}
+	err = server.Serve(ctx, srv, server.ShutdownOptions{
+		Readiness:   app.Readiness,
+		DrainDelay:  cfg.ShutdownDrainDelay,
+		GracePeriod: cfg.ShutdownGracePeriod,
+	})
+	waitJobs()
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("shutdown grace period elapsed; closed remaining connections")
+		return
+	}
+	if err != nil {
 		log.Fatalf("server error: %v", err)
 	}
+	log.Printf("API server stopped")
 }
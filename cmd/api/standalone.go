@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/workerproc"
+)
+
+// Maintenance intervals under RA_PROFILE=standalone, matching the SQS
+// worker's defaults.
+const (
+	standaloneGuestCleanupInterval = time.Hour
+	standaloneArchivePurgeInterval = time.Hour
+	standaloneRescoreInterval      = 5 * time.Minute
+	standaloneBudgetReleaseEvery   = 5 * time.Minute
+)
+
+// startStandalone runs the analysis worker and its maintenance loops inside the
+// API process, so a standalone deployment needs no separate worker. The
+// returned func waits for jobs in progress once ctx is done, for at most grace.
+func startStandalone(ctx context.Context, app *bootstrap.App, grace time.Duration) func() {
+	cfg := app.Config
+	if app.RetentionService.Enabled() {
+		go app.RetentionService.Run(ctx, standaloneGuestCleanupInterval)
+	}
+	if app.LLMArchive.Enabled() {
+		go app.LLMArchive.Run(ctx, standaloneArchivePurgeInterval)
+	}
+	if app.Rescore != nil {
+		go app.Rescore.Run(ctx, standaloneRescoreInterval)
+	}
+	if app.AnalysesService != nil {
+		go app.AnalysesService.RunBudgetReleases(ctx, standaloneBudgetReleaseEvery)
+	}
+	if interval := cfg.StuckScanInterval; app.Stuck != nil && interval > 0 {
+		go app.Stuck.Run(ctx, interval)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		workerproc.ConsumeLocal(ctx, app, app.LocalQueue, workerproc.LocalOptions{Concurrency: cfg.LocalWorkerConcurrency})
+	}()
+	log.Printf("standalone worker started concurrency=%d", cfg.LocalWorkerConcurrency)
+
+	return func() {
+		select {
+		case <-done:
+		case <-time.After(grace):
+			log.Printf("standalone worker: grace period elapsed with jobs in progress")
+		}
+	}
+}
@@ -21,15 +21,17 @@ var (
 	initOnce  sync.Once
 	initErr   error
 	ginLambda *ginadapter.GinLambdaV2
+	app       *bootstrap.App
 )
 
 func initApp() {
-	cfg := config.Load()
-	app, err := bootstrap.Build(cfg)
+	cfg := config.Load().WithRole(config.RoleAPI)
+	built, err := bootstrap.Build(cfg)
 	if err != nil {
 		initErr = err
 		return
 	}
+	app = built
 	ginLambda = ginadapter.NewV2(app.Router)
 }
 
@@ -44,6 +46,19 @@ func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.AP
 			Headers:    map[string]string{"Content-Type": "application/json"},
 		}, initErr
 	}
+	// Background goroutines are frozen between invocations, so poll here.
+	if app.RuntimeConfig != nil {
+		app.RuntimeConfig.Refresh(ctx)
+	}
+	// Scheduled warm pings are not API Gateway requests and carry no HTTP method.
+	if req.RequestContext.HTTP.Method == "" {
+		_ = app.Warmup(ctx)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 200,
+			Body:       `{"warm":true}`,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+		}, nil
+	}
 	if ginLambda == nil {
 		return events.APIGatewayV2HTTPResponse{
 			StatusCode: 500,
@@ -51,9 +66,17 @@ func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.AP
 			Headers:    map[string]string{"Content-Type": "application/json"},
 		}, nil
 	}
-	return ginLambda.ProxyWithContext(ctx, req)
+	resp, err := ginLambda.ProxyWithContext(ctx, req)
+	// The process may be frozen between invocations, so deliver analytics now.
+	app.Events.Flush(ctx)
+	return resp, err
 }
 
 func main() {
+	// Initialize during the Lambda init phase so provisioned concurrency absorbs the cost.
+	initOnce.Do(initApp)
+	if app != nil {
+		_ = app.Warmup(context.Background())
+	}
 	lambda.Start(handler)
 }
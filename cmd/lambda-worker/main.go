@@ -5,17 +5,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
 	"resume-backend/internal/bootstrap"
 	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
 	"resume-backend/internal/workerproc"
 )
 
+const (
+	defaultTimeoutReserveSeconds = 10
+	defaultMinRecordSeconds      = 30
+)
+
 var (
 	initOnce sync.Once
 	initErr  error
@@ -23,7 +35,7 @@ var (
 )
 
 func initApp() {
-	cfg := config.Load()
+	cfg := config.Load().WithRole(config.RoleWorker)
 	built, err := bootstrap.Build(cfg)
 	if err != nil {
 		initErr = err
@@ -43,16 +55,99 @@ func handler(ctx context.Context, event events.SQSEvent) (events.SQSEventRespons
 		return events.SQSEventResponse{BatchItemFailures: failures}, initErr
 	}
 
+	// Background goroutines are frozen between invocations, so poll here.
+	if app.RuntimeConfig != nil {
+		app.RuntimeConfig.Refresh(ctx)
+	}
+	// Scheduled warm pings arrive without SQS records.
+	if len(event.Records) == 0 {
+		_ = app.Warmup(ctx)
+		return events.SQSEventResponse{}, nil
+	}
+
+	failures := processBatch(ctx, budgetFromEnv(), event.Records, func(ctx context.Context, record events.SQSMessage) error {
+		// Duplicates are reported as failures too, so the message stays queued
+		// until the invocation that claimed it deletes it.
+		return workerproc.HandleMessage(workerproc.WithMessageID(ctx, record.MessageId), app, record.Body)
+	})
+	return events.SQSEventResponse{BatchItemFailures: failures}, nil
+}
+
+// batchBudget splits the invocation's time between the records of a batch, so
+// one slow record cannot run into the Lambda timeout and fail the records that
+// already completed along with it.
+type batchBudget struct {
+	// Reserve is kept back at the end of the invocation to return the response.
+	Reserve time.Duration
+	// MinRecord is the least time a record is started with. Records that
+	// would get less are returned as failures without being processed.
+	MinRecord time.Duration
+}
+
+func budgetFromEnv() batchBudget {
+	return batchBudget{
+		Reserve:   time.Duration(envInt("RA_LAMBDA_TIMEOUT_RESERVE_SECONDS", defaultTimeoutReserveSeconds)) * time.Second,
+		MinRecord: time.Duration(envInt("RA_LAMBDA_MIN_RECORD_SECONDS", defaultMinRecordSeconds)) * time.Second,
+	}
+}
+
+// processBatch handles records one at a time, each with a deadline of the
+// invocation's deadline less the reserve. Once less than MinRecord is left, the
+// remaining records are returned as batch item failures for SQS to redeliver.
+func processBatch(ctx context.Context, budget batchBudget, records []events.SQSMessage, handle func(ctx context.Context, record events.SQSMessage) error) []events.SQSBatchItemFailure {
 	failures := make([]events.SQSBatchItemFailure, 0)
-	for _, record := range event.Records {
-		if err := workerproc.HandleMessage(ctx, app, record.Body); err != nil {
+	deadline, hasDeadline := ctx.Deadline()
+	stop := deadline.Add(-budget.Reserve)
+	for i, record := range records {
+		if hasDeadline && time.Until(stop) < budget.MinRecord {
+			deferred := records[i:]
+			for _, record := range deferred {
+				failures = append(failures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+			}
+			metrics.RecordWorkerBudgetExhausted(len(deferred))
+			telemetry.InfoContext(ctx, "worker.batch.budget_exhausted", map[string]any{
+				"records":      len(records),
+				"processed":    i,
+				"deferred":     len(deferred),
+				"remaining_ms": time.Until(deadline).Milliseconds(),
+			})
+			break
+		}
+		recordCtx, cancel := ctx, context.CancelFunc(func() {})
+		if hasDeadline {
+			recordCtx, cancel = context.WithDeadline(ctx, stop)
+		}
+		err := handle(recordCtx, record)
+		timedOut := err != nil && errors.Is(recordCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if timedOut {
+			metrics.IncWorkerRecordsTimedOut()
+			telemetry.InfoContext(ctx, "worker.record.budget_exceeded", map[string]any{"message_id": record.MessageId})
+		}
+		if err != nil {
 			failures = append(failures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
 		}
 	}
+	return failures
+}
 
-	return events.SQSEventResponse{BatchItemFailures: failures}, nil
+func envInt(key string, def int) int {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def
+	}
+	val, err := strconv.Atoi(raw)
+	if err != nil || val < 0 {
+		return def
+	}
+	return val
 }
 
 func main() {
+	// Initialize during the Lambda init phase so provisioned concurrency absorbs the cost.
+	initOnce.Do(initApp)
+	if app != nil {
+		_ = app.Warmup(context.Background())
+	}
 	lambda.Start(handler)
 }
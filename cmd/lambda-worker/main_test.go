@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"resume-backend/internal/shared/metrics"
+)
+
+func records(ids ...string) []events.SQSMessage {
+	out := make([]events.SQSMessage, len(ids))
+	for i, id := range ids {
+		out[i] = events.SQSMessage{MessageId: id}
+	}
+	return out
+}
+
+func failureIDs(failures []events.SQSBatchItemFailure) string {
+	ids := make([]string, len(failures))
+	for i, f := range failures {
+		ids[i] = f.ItemIdentifier
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestProcessBatchDefersRecordsOnceTheBudgetRunsOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	budget := batchBudget{Reserve: 500 * time.Millisecond, MinRecord: 400 * time.Millisecond}
+
+	var handled []string
+	failures := processBatch(ctx, budget, records("m1", "m2", "m3", "m4"), func(ctx context.Context, record events.SQSMessage) error {
+		handled = append(handled, record.MessageId)
+		switch record.MessageId {
+		case "m1":
+			return errors.New("bad payload")
+		case "m2":
+			// A slow record is cut short at the invocation deadline less the reserve.
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if strings.Join(handled, ",") != "m1,m2" {
+		t.Fatalf("expected processing to stop after the slow record, handled %v", handled)
+	}
+	if got := failureIDs(failures); got != "m1,m2,m3,m4" {
+		t.Fatalf("expected the failed, timed out and deferred records back, got %s", got)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected the batch to return before the invocation deadline")
+	}
+	rendered := metrics.Render()
+	for _, name := range []string{"worker_batches_budget_exhausted_total", "worker_records_deferred_total", "worker_records_timed_out_total"} {
+		if !strings.Contains(rendered, name+" ") || strings.Contains(rendered, name+" 0\n") {
+			t.Fatalf("expected %s to be recorded:\n%s", name, rendered)
+		}
+	}
+}
+
+func TestProcessBatchWithoutDeadlineHandlesEveryRecord(t *testing.T) {
+	failures := processBatch(context.Background(), batchBudget{Reserve: time.Hour, MinRecord: time.Hour}, records("m1", "m2"), func(ctx context.Context, record events.SQSMessage) error {
+		if record.MessageId == "m2" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if got := failureIDs(failures); got != "m2" {
+		t.Fatalf("expected only m2 to fail, got %s", got)
+	}
+}
@@ -0,0 +1,472 @@
+package main
+
+// Stress-tests the analysis queue pipeline before a traffic spike:
+//   go run ./cmd/loadgen -jobs 500 -concurrency 16 -llm-latency 2s
+//
+// One seeded user uploads the fixture resume, then N analyses are started
+// through the analyses service, so every job is enqueued the way the API does
+// it, and consumed with the worker's own message handler. A mock LLM answers
+// with recorded output after -llm-latency, failing -llm-failure-rate of calls,
+// so nothing calls OpenAI. With RA_SQS_QUEUE_URL set the jobs go through that
+// SQS queue, which no worker should be reading during the run; otherwise an
+// in-process queue stands in. One JSON report is printed with throughput,
+// enqueue-to-completion latency percentiles and failure counts by error code.
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/users"
+	"resume-backend/internal/workerproc"
+)
+
+const sqsRegion = "us-east-1"
+
+const jobDescription = "Senior Backend Engineer, Payments Platform. You will design and operate the Go services that move money for millions of merchants: ledgers, payouts and reconciliation. We run on AWS with Kubernetes and PostgreSQL, and every service owns its SLOs. You have 5+ years building distributed systems, have run services across multiple regions, take part in incident response, and enjoy mentoring. Experience with Kafka and PCI DSS is a plus."
+
+//go:embed testdata
+var fixtures embed.FS
+
+// options are the command line flags.
+type options struct {
+	Jobs           int
+	Concurrency    int
+	LLMLatency     time.Duration
+	LLMFailureRate float64
+	Timeout        time.Duration
+}
+
+type report struct {
+	Queue            string         `json:"queue"`
+	Jobs             int            `json:"jobs"`
+	Enqueued         int            `json:"enqueued"`
+	Completed        int            `json:"completed"`
+	Failed           int            `json:"failed"`
+	FailureRate      float64        `json:"failureRate"`
+	DurationMs       int64          `json:"durationMs"`
+	ThroughputPerSec float64        `json:"throughputPerSec"`
+	LatencyMs        latencyReport  `json:"latencyMs"`
+	Errors           map[string]int `json:"errors,omitempty"`
+}
+
+// latencyReport covers jobs that finished, from enqueue to the end of processing.
+type latencyReport struct {
+	P50 int64 `json:"p50"`
+	P95 int64 `json:"p95"`
+	Max int64 `json:"max"`
+}
+
+func main() {
+	opts := options{}
+	flag.IntVar(&opts.Jobs, "jobs", 100, "analysis jobs to run")
+	flag.IntVar(&opts.Concurrency, "concurrency", 8, "jobs processed at once")
+	flag.DurationVar(&opts.LLMLatency, "llm-latency", 500*time.Millisecond, "time the mock LLM takes per call")
+	flag.Float64Var(&opts.LLMFailureRate, "llm-failure-rate", 0, "share of mock LLM calls that fail, 0 to 1")
+	flag.DurationVar(&opts.Timeout, "timeout", 10*time.Minute, "give up on jobs still unfinished after this long")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, config.Load(), opts, os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, cfg config.Config, opts options, stdout, stderr io.Writer) int {
+	if cfg.Env == "production" {
+		fmt.Fprintln(stderr, "loadgen: refusing to run with ENV=production")
+		return 2
+	}
+	if opts.Jobs <= 0 || opts.Concurrency <= 0 || opts.LLMFailureRate < 0 || opts.LLMFailureRate > 1 {
+		fmt.Fprintln(stderr, "loadgen: -jobs and -concurrency must be positive and -llm-failure-rate between 0 and 1")
+		return 2
+	}
+	output, err := fixtures.ReadFile("testdata/result_v2_3.json")
+	if err != nil {
+		fmt.Fprintf(stderr, "load fixtures: %v\n", err)
+		return 1
+	}
+
+	app, err := bootstrap.Build(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB != nil {
+		defer app.DB.Close()
+	}
+
+	var q jobQueue
+	if app.Queue != nil {
+		if q, err = newSQSQueue(ctx, app.Queue, os.Getenv("RA_SQS_QUEUE_URL")); err != nil {
+			fmt.Fprintf(stderr, "sqs: %v\n", err)
+			return 1
+		}
+	} else {
+		q = newMemoryQueue(opts.Jobs)
+	}
+
+	out, err := generate(ctx, app, q, opts, &mockLLM{output: output, latency: opts.LLMLatency, failureRate: opts.LLMFailureRate})
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: %v\n", err)
+		return 1
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(out)
+	return 0
+}
+
+// jobQueue is the queue the jobs travel through: the service sends to it and
+// consume delivers each message body to handle from workers goroutines until
+// ctx is done.
+type jobQueue interface {
+	queue.Client
+	name() string
+	consume(ctx context.Context, workers int, handle func(ctx context.Context, body, messageID string))
+}
+
+// generate seeds a user and document, enqueues opts.Jobs analyses and waits
+// for all of them to finish or opts.Timeout to pass.
+func generate(ctx context.Context, app *bootstrap.App, q jobQueue, opts options, model llm.Client) (report, error) {
+	userID := "loadgen:" + uuid.NewString()
+	ctx = ctxmeta.WithDataOwner(ctxmeta.WithUserID(ctx, userID), userID)
+	documentID, err := seedDocument(ctx, app, userID)
+	if err != nil {
+		return report{}, err
+	}
+
+	svc := app.AnalysesService
+	svc.LLM = model
+	svc.JobQueue = q
+	// Quotas would cap the run at the free plan's allowance.
+	svc.Usage = nil
+
+	out := report{Queue: q.name(), Jobs: opts.Jobs, Errors: map[string]int{}}
+	type outcome struct {
+		code    string
+		latency time.Duration
+	}
+	var (
+		mu        sync.Mutex
+		pending   = map[string]bool{}
+		latencies []time.Duration
+		enqueuing = true
+		done      = make(chan struct{})
+		// early holds outcomes of jobs that finished before their enqueue
+		// call returned and marked them pending.
+		early = map[string]outcome{}
+	)
+	// record must be called with mu held.
+	record := func(o outcome) {
+		if o.code == "" {
+			out.Completed++
+			latencies = append(latencies, o.latency)
+		} else {
+			out.Failed++
+			out.Errors[o.code]++
+		}
+	}
+	settle := func(analysisID, code string, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !pending[analysisID] {
+			early[analysisID] = outcome{code: code, latency: latency}
+			return
+		}
+		delete(pending, analysisID)
+		record(outcome{code: code, latency: latency})
+		if len(pending) == 0 && !enqueuing {
+			close(done)
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	go q.consume(runCtx, opts.Concurrency, func(ctx context.Context, body, messageID string) {
+		msg, _, err := workerproc.ParseMessage(body)
+		if err != nil {
+			return
+		}
+		if messageID != "" {
+			ctx = workerproc.WithMessageID(ctx, messageID)
+		}
+		// A processing error is recorded on the analysis; the stored status says which.
+		_ = workerproc.HandleMessage(workerproc.WithParsedMessage(ctx, msg), app, body)
+		finished := time.Now()
+		enqueuedAt, _ := time.Parse(time.RFC3339Nano, msg.EnqueuedAt)
+		stored, err := app.AnalysesRepo.GetByID(ctx, msg.AnalysisID)
+		switch {
+		case err != nil:
+			settle(msg.AnalysisID, "lookup_failed", 0)
+		case stored.Status == analyses.StatusCompleted:
+			settle(msg.AnalysisID, "", finished.Sub(enqueuedAt))
+		default:
+			settle(msg.AnalysisID, failureCode(stored), 0)
+		}
+	})
+
+	start := time.Now()
+	for i := range opts.Jobs {
+		// Each job gets its own job description so none reuses another's analysis.
+		jd := fmt.Sprintf("%s\n\nLoad test job %d.", jobDescription, i+1)
+		analysis, _, err := svc.StartOrReuseWithOptions(ctx, documentID, userID, jd, "v2_3", analyses.ModeJobMatch, false, analyses.StartOptions{ForceNew: true})
+		mu.Lock()
+		switch {
+		case err != nil:
+			out.Failed++
+			out.Errors["enqueue_failed"]++
+		case analysis.Status != analyses.StatusQueued:
+			// Budget deferred analyses are never enqueued.
+			out.Failed++
+			out.Errors[analysis.Status]++
+		default:
+			out.Enqueued++
+			if o, ok := early[analysis.ID]; ok {
+				delete(early, analysis.ID)
+				record(o)
+			} else {
+				pending[analysis.ID] = true
+			}
+		}
+		mu.Unlock()
+	}
+	mu.Lock()
+	enqueuing = false
+	if len(pending) == 0 {
+		close(done)
+	}
+	mu.Unlock()
+
+	select {
+	case <-done:
+	case <-runCtx.Done():
+	}
+	elapsed := time.Since(start)
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Jobs still pending timed out, or were cut short by a signal.
+	out.Failed += len(pending)
+	if len(pending) > 0 {
+		out.Errors["timeout"] += len(pending)
+	}
+	out.FailureRate = float64(out.Failed) / float64(out.Jobs)
+	out.DurationMs = elapsed.Milliseconds()
+	if elapsed > 0 {
+		out.ThroughputPerSec = math.Round(float64(out.Completed)/elapsed.Seconds()*100) / 100
+	}
+	out.LatencyMs = summarizeLatencies(latencies)
+	if len(out.Errors) == 0 {
+		out.Errors = nil
+	}
+	return out, nil
+}
+
+func failureCode(a analyses.Analysis) string {
+	if a.Status != analyses.StatusFailed {
+		return a.Status
+	}
+	if a.ErrorCode == "" {
+		return "failed"
+	}
+	return a.ErrorCode
+}
+
+// seedDocument creates the load test user and uploads and extracts the fixture
+// resume for them.
+func seedDocument(ctx context.Context, app *bootstrap.App, userID string) (string, error) {
+	now := time.Now().UTC()
+	if err := app.UsersRepo.Upsert(ctx, users.User{
+		ID:        userID,
+		Email:     strings.TrimPrefix(userID, "loadgen:") + "@loadgen.example.com",
+		FullName:  "Load Test",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}); err != nil {
+		return "", fmt.Errorf("create user: %w", err)
+	}
+	resume, err := fixtures.ReadFile("testdata/resume.docx")
+	if err != nil {
+		return "", err
+	}
+	doc, err := app.DocumentsService.Upload(ctx, userID, "resume.docx", "", bytes.NewReader(resume))
+	if err != nil {
+		return "", fmt.Errorf("upload resume: %w", err)
+	}
+	if doc, err = app.DocumentExtractor.ExtractDocument(ctx, userID, doc.ID); err != nil {
+		return "", fmt.Errorf("extract resume: %w", err)
+	}
+	return doc.ID, nil
+}
+
+// summarizeLatencies returns nearest-rank percentiles in milliseconds.
+func summarizeLatencies(latencies []time.Duration) latencyReport {
+	if len(latencies) == 0 {
+		return latencyReport{}
+	}
+	sorted := slices.Clone(latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := func(p float64) int64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)].Milliseconds()
+	}
+	return latencyReport{P50: rank(0.50), P95: rank(0.95), Max: sorted[len(sorted)-1].Milliseconds()}
+}
+
+// mockLLM answers every analysis call with recorded output after latency,
+// failing failureRate of calls.
+type mockLLM struct {
+	output      []byte
+	latency     time.Duration
+	failureRate float64
+}
+
+var errInjectedFailure = errors.New("loadgen: injected llm failure")
+
+func (m *mockLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	_ = input
+	if m.latency > 0 {
+		timer := time.NewTimer(m.latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if m.failureRate > 0 && rand.Float64() < m.failureRate {
+		return nil, errInjectedFailure
+	}
+	return json.RawMessage(m.output), nil
+}
+
+// memoryQueue is an in-process queue. Messages are encoded as they would be
+// for SQS, so the worker handler sees the same bodies.
+type memoryQueue struct {
+	bodies chan string
+}
+
+func newMemoryQueue(size int) *memoryQueue {
+	return &memoryQueue{bodies: make(chan string, size)}
+}
+
+func (q *memoryQueue) name() string { return "memory" }
+
+func (q *memoryQueue) Send(ctx context.Context, msg queue.Message) error {
+	payload, err := queue.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	select {
+	case q.bodies <- string(payload):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *memoryQueue) ApproximateDepth(ctx context.Context) (int, error) {
+	_ = ctx
+	return len(q.bodies), nil
+}
+
+func (q *memoryQueue) consume(ctx context.Context, workers int, handle func(ctx context.Context, body, messageID string)) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case body := <-q.bodies:
+					handle(ctx, body, "")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// sqsQueue sends through the app's SQS client and receives from the same
+// queue. Every delivery is deleted once handled, failed or not, so a failing
+// job is counted once instead of being retried.
+type sqsQueue struct {
+	queue.Client
+	client   *sqs.Client
+	queueURL string
+}
+
+func newSQSQueue(ctx context.Context, sender queue.Client, queueURL string) (*sqsQueue, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(sqsRegion))
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return &sqsQueue{Client: sender, client: sqs.NewFromConfig(awsCfg), queueURL: strings.TrimSpace(queueURL)}, nil
+}
+
+func (q *sqsQueue) name() string { return "sqs" }
+
+func (q *sqsQueue) consume(ctx context.Context, workers int, handle func(ctx context.Context, body, messageID string)) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				resp, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+					QueueUrl:            aws.String(q.queueURL),
+					MaxNumberOfMessages: 1,
+					WaitTimeSeconds:     5,
+				})
+				if err != nil {
+					if ctx.Err() == nil {
+						time.Sleep(time.Second)
+					}
+					continue
+				}
+				for _, msg := range resp.Messages {
+					handle(ctx, aws.ToString(msg.Body), aws.ToString(msg.MessageId))
+					_, _ = q.client.DeleteMessage(context.WithoutCancel(ctx), &sqs.DeleteMessageInput{
+						QueueUrl:      aws.String(q.queueURL),
+						ReceiptHandle: msg.ReceiptHandle,
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+var (
+	_ jobQueue = (*memoryQueue)(nil)
+	_ jobQueue = (*sqsQueue)(nil)
+)
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/config"
+)
+
+func TestGenerateRunsJobsThroughTheQueue(t *testing.T) {
+	app, err := bootstrap.Build(config.Config{Env: "dev", LocalStoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	output, err := fixtures.ReadFile("testdata/result_v2_3.json")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	opts := options{Jobs: 12, Concurrency: 4, LLMLatency: 5 * time.Millisecond, Timeout: time.Minute}
+	out, err := generate(context.Background(), app, newMemoryQueue(opts.Jobs), opts, &mockLLM{output: output, latency: opts.LLMLatency})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if out.Queue != "memory" || out.Enqueued != 12 || out.Completed != 12 || out.Failed != 0 || out.Errors != nil {
+		t.Fatalf("expected every job to complete, got %+v", out)
+	}
+	if out.LatencyMs.P95 < out.LatencyMs.P50 || out.LatencyMs.Max < out.LatencyMs.P95 || out.ThroughputPerSec <= 0 {
+		t.Fatalf("unexpected latency or throughput: %+v", out)
+	}
+}
+
+func TestGenerateReportsInjectedFailures(t *testing.T) {
+	app, err := bootstrap.Build(config.Config{Env: "dev", LocalStoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+
+	opts := options{Jobs: 5, Concurrency: 2, Timeout: time.Minute}
+	out, err := generate(context.Background(), app, newMemoryQueue(opts.Jobs), opts, &mockLLM{failureRate: 1})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if out.Completed != 0 || out.Failed != 5 || out.FailureRate != 1 || len(out.Errors) == 0 {
+		t.Fatalf("expected every job to fail, got %+v", out)
+	}
+}
+
+func TestSummarizeLatencies(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	got := summarizeLatencies(latencies)
+	if got != (latencyReport{P50: 50, P95: 95, Max: 100}) {
+		t.Fatalf("unexpected percentiles: %+v", got)
+	}
+}
+
+func TestLoadgenRefusesProduction(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run(context.Background(), config.Config{Env: "production"}, options{Jobs: 1, Concurrency: 1}, &bytes.Buffer{}, &stderr); code != 2 {
+		t.Fatalf("expected exit code 2, got %d (%s)", code, stderr.String())
+	}
+}
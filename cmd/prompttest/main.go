@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"resume-backend/internal/analyses"
+	"resume-backend/internal/analyses/roles"
 	"resume-backend/internal/extract"
 	"resume-backend/internal/llm"
 	openai "resume-backend/internal/llm/openai"
@@ -22,6 +23,7 @@ func main() {
 
 	resumePath := flag.String("resume", "", "Path to resume file (pdf or docx)")
 	jdPath := flag.String("jd", "", "Path to job description file (optional)")
+	targetRole := flag.String("target-role", "", "Target role, used when there is no job description (optional)")
 	promptVersion := flag.String("prompt-version", "v1", "Prompt version")
 	outPath := flag.String("out", "", "Path to write raw JSON output (optional)")
 	provider := flag.String("provider", cfg.LLMProvider, "LLM provider")
@@ -66,7 +68,10 @@ func main() {
 		ResumeText:     resumeText,
 		JobDescription: jobDescription,
 		PromptVersion:  *promptVersion,
-		TargetRole:     "",
+		TargetRole:     analyses.NormalizeTargetRole(*targetRole),
+	}
+	if profile, ok := roles.Lookup(input.TargetRole); ok {
+		input.TargetRoleKeywords = profile.Keywords
 	}
 
 	var raw json.RawMessage
@@ -70,9 +70,9 @@ func sampleResumeModel() model.ResumeModel {
 			Email:    "jordan.lee@example.com",
 			Phone:    "+1-555-0102",
 			Location: "Austin, TX",
-			Links: []string{
-				"https://www.linkedin.com/in/jordanlee",
-				"https://github.com/jordanlee",
+			Links: []model.ResumeLink{
+				{Label: "LinkedIn", URL: "https://www.linkedin.com/in/jordanlee"},
+				{Label: "GitHub", URL: "https://github.com/jordanlee"},
 			},
 		},
 		Summary: []string{
@@ -0,0 +1,380 @@
+package main
+
+// Fills a dev database and object store with realistic fixtures:
+//   go run ./cmd/seed
+//
+// Each persona in testdata/personas.json becomes a signed-in user with an
+// uploaded resume (PDF or DOCX), analyses across prompt versions and, where
+// marked, an apply run and a generated resume. Analyses go through the real
+// pipelines with recorded LLM output instead of calling the LLM, so completed
+// and failed results look exactly like production ones. Personas whose user
+// already exists are skipped, so the command can be re-run. One JSON report is
+// printed per persona, with a 24h bearer token when JWT_SECRET is set.
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/usage"
+	"resume-backend/internal/users"
+	"resume-backend/resume/model"
+	resumeservice "resume-backend/resume/service"
+)
+
+//go:embed testdata
+var fixtures embed.FS
+
+// persona is one seeded user and what is created for them.
+type persona struct {
+	UserID     string         `json:"userId"`
+	Email      string         `json:"email"`
+	GivenName  string         `json:"givenName"`
+	FamilyName string         `json:"familyName"`
+	Resume     string         `json:"resume"`
+	Model      string         `json:"model"`
+	Analyses   []seedAnalysis `json:"analyses"`
+}
+
+// seedAnalysis is an analysis run against recorded LLM output. Apply adds an
+// apply run with a rendered document version; Generate adds a generated resume.
+type seedAnalysis struct {
+	Mode           analyses.AnalysisMode `json:"mode"`
+	PromptVersion  string                `json:"promptVersion"`
+	JobDescription string                `json:"jobDescription"`
+	Output         string                `json:"output"`
+	Apply          bool                  `json:"apply"`
+	Generate       bool                  `json:"generate"`
+}
+
+type personaReport struct {
+	UserID     string           `json:"userId"`
+	Skipped    bool             `json:"skipped,omitempty"`
+	DocumentID string           `json:"documentId,omitempty"`
+	Analyses   []analysisReport `json:"analyses,omitempty"`
+	Token      string           `json:"token,omitempty"`
+}
+
+type analysisReport struct {
+	ID                string `json:"id"`
+	Mode              string `json:"mode"`
+	PromptVersion     string `json:"promptVersion"`
+	Status            string `json:"status"`
+	ErrorCode         string `json:"errorCode,omitempty"`
+	ApplyRunID        string `json:"applyRunId,omitempty"`
+	GeneratedResumeID string `json:"generatedResumeId,omitempty"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, config.Load(), os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, cfg config.Config, stdout, stderr io.Writer) int {
+	if cfg.Env == "production" {
+		fmt.Fprintln(stderr, "seed: refusing to run with ENV=production")
+		return 2
+	}
+	personas, err := loadPersonas()
+	if err != nil {
+		fmt.Fprintf(stderr, "load personas: %v\n", err)
+		return 1
+	}
+
+	app, err := bootstrap.Build(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap build: %v\n", err)
+		return 1
+	}
+	if app.DB == nil {
+		fmt.Fprintln(stderr, "seed: DATABASE_URL is required; in-memory repos are lost when the command exits")
+		return 2
+	}
+	defer app.DB.Close()
+
+	enc := json.NewEncoder(stdout)
+	for _, p := range personas {
+		report, err := seedPersona(ctx, app, p)
+		if err != nil {
+			fmt.Fprintf(stderr, "seed %s: %v\n", p.UserID, err)
+			return 1
+		}
+		_ = enc.Encode(report)
+	}
+	return 0
+}
+
+func loadPersonas() ([]persona, error) {
+	raw, err := fixtures.ReadFile("testdata/personas.json")
+	if err != nil {
+		return nil, err
+	}
+	var personas []persona
+	if err := json.Unmarshal(raw, &personas); err != nil {
+		return nil, err
+	}
+	return personas, nil
+}
+
+func seedPersona(ctx context.Context, app *bootstrap.App, p persona) (personaReport, error) {
+	report := personaReport{UserID: p.UserID}
+	if _, err := app.UsersRepo.GetByID(ctx, p.UserID); err == nil {
+		report.Skipped = true
+		return report, nil
+	} else if !errors.Is(err, users.ErrNotFound) {
+		return report, err
+	}
+
+	resumeModel, err := loadModel(p.Model)
+	if err != nil {
+		return report, err
+	}
+	ctx = ctxmeta.WithDataOwner(ctxmeta.WithUserID(ctx, p.UserID), p.UserID)
+	// Apply and resume generation rebuild the resume model with the LLM; the
+	// persona's model stands in for it.
+	llmClient := recordedCompletion{model: resumeModel}
+	app.ApplyService.LLM = llmClient
+	now := time.Now().UTC()
+	if err := app.UsersRepo.Upsert(ctx, users.User{
+		ID:         p.UserID,
+		Email:      p.Email,
+		FullName:   p.GivenName + " " + p.FamilyName,
+		GivenName:  p.GivenName,
+		FamilyName: p.FamilyName,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}); err != nil {
+		return report, fmt.Errorf("create user: %w", err)
+	}
+
+	resume, err := fixtures.ReadFile("testdata/" + p.Resume)
+	if err != nil {
+		return report, err
+	}
+	doc, err := app.DocumentsService.Upload(ctx, p.UserID, path.Base(p.Resume), "", bytes.NewReader(resume))
+	if err != nil {
+		return report, fmt.Errorf("upload %s: %w", p.Resume, err)
+	}
+	if doc, err = app.DocumentExtractor.ExtractDocument(ctx, p.UserID, doc.ID); err != nil {
+		return report, fmt.Errorf("extract %s: %w", p.Resume, err)
+	}
+	report.DocumentID = doc.ID
+
+	for _, a := range p.Analyses {
+		out, err := seedAnalysisRun(ctx, app, p.UserID, doc.ID, a)
+		if err != nil {
+			return report, fmt.Errorf("analysis %s: %w", a.Output, err)
+		}
+		if a.Apply {
+			if out.ApplyRunID, err = seedApplyRun(ctx, app, llmClient, p.UserID, doc.ExtractedTextKey, out.ID, resumeModel.Header); err != nil {
+				return report, fmt.Errorf("apply run for %s: %w", a.Output, err)
+			}
+		}
+		if a.Generate {
+			generated, err := app.ApplyService.Apply(ctx, p.UserID, out.ID, "", false)
+			if err != nil {
+				return report, fmt.Errorf("generate resume for %s: %w", a.Output, err)
+			}
+			out.GeneratedResumeID = generated.ID
+		}
+		report.Analyses = append(report.Analyses, out)
+	}
+
+	if token, err := auth.SignJWT(auth.Claims{Sub: p.UserID, Email: p.Email, Name: p.GivenName + " " + p.FamilyName}); err == nil {
+		report.Token = token
+	}
+	return report, nil
+}
+
+// seedAnalysisRun creates a queued analysis and processes it with the recorded
+// output standing in for the LLM. Output that fails validation leaves a failed
+// analysis, which is how failed fixtures are made.
+func seedAnalysisRun(ctx context.Context, app *bootstrap.App, userID, documentID string, a seedAnalysis) (analysisReport, error) {
+	output, err := fixtures.ReadFile("testdata/" + a.Output)
+	if err != nil {
+		return analysisReport{}, err
+	}
+	svc := app.AnalysesService
+	analysis := analyses.Analysis{
+		ID:                 uuid.NewString(),
+		DocumentID:         documentID,
+		UserID:             userID,
+		JobDescription:     a.JobDescription,
+		JobDescriptionHash: analyses.HashJobDescription(a.JobDescription),
+		PromptVersion:      a.PromptVersion,
+		Mode:               a.Mode,
+		AnalysisVersion:    svc.AnalysisVersion,
+		Provider:           svc.Provider,
+		Model:              svc.Model,
+		Status:             analyses.StatusQueued,
+		CreatedAt:          time.Now().UTC(),
+	}
+	if err := app.AnalysesRepo.Create(ctx, analysis); err != nil {
+		return analysisReport{}, err
+	}
+
+	svc.LLM = recordedLLM{output: output}
+	// A processing error is expected for failing fixtures; the stored status says which.
+	_ = svc.ProcessAnalysis(ctx, analysis.ID)
+	stored, err := app.AnalysesRepo.GetByID(ctx, analysis.ID)
+	if err != nil {
+		return analysisReport{}, err
+	}
+	return analysisReport{
+		ID:            stored.ID,
+		Mode:          string(stored.Mode),
+		PromptVersion: stored.PromptVersion,
+		Status:        stored.Status,
+		ErrorCode:     stored.ErrorCode,
+	}, nil
+}
+
+// seedApplyRun plans and executes an apply run for a completed v2_3 analysis,
+// as POST /apply-runs and POST /apply-runs/:id/execute do.
+func seedApplyRun(ctx context.Context, app *bootstrap.App, llmClient resumeservice.LLMClient, userID, extractedTextKey, analysisID string, header model.ResumeHeader) (string, error) {
+	analysis, err := app.AnalysesRepo.GetByID(ctx, analysisID)
+	if err != nil {
+		return "", err
+	}
+	if analysis.Status != analyses.StatusCompleted {
+		return "", fmt.Errorf("analysis is %s", analysis.Status)
+	}
+	payload, err := json.Marshal(analysis.Result)
+	if err != nil {
+		return "", err
+	}
+	var result resumeservice.AnalysisResultV2_3
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return "", err
+	}
+
+	plan := app.UsageService.BuildApplyPlan(result)
+	runRecord := usage.ApplyRun{
+		ID:                   uuid.NewString(),
+		UserID:               userID,
+		AnalysisID:           analysis.ID,
+		Status:               usage.ApplyRunStatusPlanned,
+		AutoFixesCount:       len(plan.AutoFixes),
+		SafeRewritesCount:    len(plan.SafeRewrites),
+		BlockedRewritesCount: len(plan.BlockedRewrites),
+		NeedsInputCount:      len(plan.NeedsInput),
+		CreatedAt:            time.Now().UTC(),
+	}
+	if err := app.UsageService.CreateApplyRun(ctx, runRecord); err != nil {
+		return "", err
+	}
+
+	text, err := readText(ctx, app, extractedTextKey)
+	if err != nil {
+		return "", err
+	}
+	inputs := resumeservice.ApplyHeaderInputs{
+		Name:     header.Name,
+		Title:    header.Title,
+		Email:    header.Email,
+		Phone:    header.Phone,
+		Location: header.Location,
+		Links:    header.Links,
+	}
+	executed, err := resumeservice.ExecuteApply(ctx, llmClient, text, result, inputs, false)
+	if err != nil {
+		return "", err
+	}
+
+	fileName := "resume_applied.docx"
+	storageKey, size, mimeType, err := app.Store.Save(ctx, userID, fileName, bytes.NewReader(executed.DocxBytes))
+	if err != nil {
+		return "", err
+	}
+	version := usage.DocumentVersion{
+		ID:         uuid.NewString(),
+		DocumentID: analysis.DocumentID,
+		UserID:     userID,
+		ApplyRunID: runRecord.ID,
+		FileName:   fileName,
+		MimeType:   mimeType,
+		SizeBytes:  size,
+		StorageKey: storageKey,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := app.UsageService.CompleteApplyRun(ctx, []usage.DocumentVersion{version}, usage.ApplyRunUpdate{
+		ID:                    runRecord.ID,
+		UserID:                userID,
+		Status:                executed.Status,
+		AutoFixesCount:        len(executed.Plan.AutoFixes),
+		SafeRewritesCount:     executed.SafeRewritesApplied,
+		BlockedRewritesCount:  len(executed.Plan.BlockedRewrites),
+		NeedsInputCount:       len(executed.Plan.NeedsInput),
+		PlaceholdersRemaining: executed.PlaceholdersRemaining,
+		DocumentVersionID:     version.ID,
+	}); err != nil {
+		return "", err
+	}
+	return runRecord.ID, nil
+}
+
+func loadModel(name string) (model.ResumeModel, error) {
+	raw, err := fixtures.ReadFile("testdata/" + name)
+	if err != nil {
+		return model.ResumeModel{}, err
+	}
+	var m model.ResumeModel
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return model.ResumeModel{}, fmt.Errorf("decode %s: %w", name, err)
+	}
+	return m, nil
+}
+
+func readText(ctx context.Context, app *bootstrap.App, key string) (string, error) {
+	reader, err := app.Store.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// recordedLLM answers every analysis call with the same recorded output.
+type recordedLLM struct {
+	output []byte
+}
+
+func (r recordedLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	_ = ctx
+	_ = input
+	return json.RawMessage(r.output), nil
+}
+
+// recordedCompletion answers the apply pipeline with the persona's resume model.
+type recordedCompletion struct {
+	model model.ResumeModel
+}
+
+func (r recordedCompletion) Complete(ctx context.Context, prompt string) (string, error) {
+	_ = ctx
+	_ = prompt
+	payload, err := json.Marshal(r.model)
+	return string(payload), err
+}
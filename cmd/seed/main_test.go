@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/config"
+)
+
+func TestSeedPersonasProducesRealisticRecords(t *testing.T) {
+	// The renderer loads its template relative to the repo root, where the command runs.
+	t.Chdir("../..")
+	app, err := bootstrap.Build(config.Config{Env: "dev", LocalStoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	personas, err := loadPersonas()
+	if err != nil {
+		t.Fatalf("load personas: %v", err)
+	}
+
+	ctx := context.Background()
+	statuses := map[string]int{}
+	versions := map[string]bool{}
+	applyRuns, generated := 0, 0
+	for _, p := range personas {
+		report, err := seedPersona(ctx, app, p)
+		if err != nil {
+			t.Fatalf("seed %s: %v", p.UserID, err)
+		}
+		if report.DocumentID == "" || len(report.Analyses) != len(p.Analyses) {
+			t.Fatalf("incomplete report for %s: %+v", p.UserID, report)
+		}
+		doc, err := app.DocumentsRepo.GetByID(ctx, p.UserID, report.DocumentID)
+		if err != nil || doc.ExtractedTextKey == "" {
+			t.Fatalf("expected extracted text for %s, got %+v err=%v", p.Resume, doc, err)
+		}
+		text, err := readText(ctx, app, doc.ExtractedTextKey)
+		if err != nil || !strings.Contains(text, p.GivenName+" "+p.FamilyName) {
+			t.Fatalf("extracted text for %s does not name the candidate: %q err=%v", p.Resume, text, err)
+		}
+		for _, a := range report.Analyses {
+			statuses[a.Status]++
+			versions[a.PromptVersion] = true
+			if a.ApplyRunID != "" {
+				applyRuns++
+			}
+			if a.GeneratedResumeID != "" {
+				generated++
+			}
+		}
+	}
+	if statuses[analyses.StatusCompleted] < 4 || statuses[analyses.StatusFailed] != 1 {
+		t.Fatalf("expected completed analyses and one failure, got %v", statuses)
+	}
+	if len(versions) < 3 || applyRuns != 2 || generated != 2 {
+		t.Fatalf("expected several prompt versions, apply runs and generated resumes; versions=%v applyRuns=%d generated=%d", versions, applyRuns, generated)
+	}
+
+	// Re-running skips personas that already exist.
+	report, err := seedPersona(ctx, app, personas[0])
+	if err != nil || !report.Skipped {
+		t.Fatalf("expected a skipped persona, got %+v err=%v", report, err)
+	}
+}
+
+func TestSeedRefusesProduction(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run(context.Background(), config.Config{Env: "production"}, &bytes.Buffer{}, &stderr); code != 2 {
+		t.Fatalf("expected exit code 2, got %d (%s)", code, stderr.String())
+	}
+}
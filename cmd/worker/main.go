@@ -4,11 +4,11 @@ import (
 	"context"
 	"errors"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
@@ -17,26 +17,50 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 
+	"resume-backend/internal/analyses"
 	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/rescore"
 	"resume-backend/internal/shared/config"
 	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/server"
 	"resume-backend/internal/shared/telemetry"
 	"resume-backend/internal/workerproc"
 )
 
 const (
-	sqsRegion                 = "us-east-1"
-	defaultVisibilitySeconds  = 1200
-	defaultWorkerConcurrency  = 4
-	defaultShutdownTimeoutSec = 30
+	sqsRegion                = "us-east-1"
+	defaultVisibilitySeconds = 1200
+	defaultWorkerConcurrency = 4
+	defaultGuestCleanupMins  = 60
+	defaultRescoreMins       = 5
+	defaultRescoreHour       = 2
+	defaultUploadsPrefix     = "documents/"
+	defaultBudgetReleaseMins = 5
+	defaultArchivePurgeMins  = 60
+
+	// stageUploads consumes S3 event notifications for presigned uploads
+	// rather than queue.Message jobs.
+	stageUploads = "uploads"
 )
 
 func main() {
-	cfg := config.Load()
+	cfg := config.Load().WithRole(config.RoleWorker)
 
-	queueURL := strings.TrimSpace(os.Getenv("RA_SQS_QUEUE_URL"))
+	// RA_WORKER_STAGE=extract runs a worker for the dedicated extraction queue so
+	// extraction and analysis can scale independently. RA_WORKER_STAGE=uploads
+	// registers documents from the S3 events of the uploads bucket.
+	stage := strings.TrimSpace(os.Getenv("RA_WORKER_STAGE"))
+	queueEnv := "RA_SQS_QUEUE_URL"
+	switch stage {
+	case queue.StageExtract:
+		queueEnv = "RA_SQS_EXTRACT_QUEUE_URL"
+	case stageUploads:
+		queueEnv = "RA_SQS_UPLOADS_QUEUE_URL"
+	}
+	queueURL := strings.TrimSpace(os.Getenv(queueEnv))
 	if queueURL == "" {
-		log.Fatal("RA_SQS_QUEUE_URL is required")
+		log.Fatalf("%s is required", queueEnv)
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -44,7 +68,6 @@ func main() {
 
 	visibilitySeconds := envInt("RA_SQS_VISIBILITY_TIMEOUT_SECONDS", defaultVisibilitySeconds)
 	concurrency := envInt("RA_WORKER_CONCURRENCY", defaultWorkerConcurrency)
-	shutdownTimeout := time.Duration(envInt("RA_SHUTDOWN_TIMEOUT_SECONDS", defaultShutdownTimeoutSec)) * time.Second
 
 	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(sqsRegion))
 	if err != nil {
@@ -56,61 +79,119 @@ func main() {
 	if err != nil {
 		log.Fatalf("bootstrap build: %v", err)
 	}
+	_ = app.Warmup(ctx)
 
-	sem := make(chan struct{}, max(1, concurrency))
-	var wg sync.WaitGroup
+	// Keep enough idle S3 connections for every concurrent job.
+	if opts := analyses.S3DocumentOptionsFromEnv(); app.S3Documents != nil && app.AnalysesService != nil && concurrency > opts.MaxIdleConnsPerHost {
+		opts.MaxIdleConnsPerHost = concurrency
+		reader, err := analyses.NewS3DocumentReader(ctx, opts)
+		if err != nil {
+			log.Fatalf("s3 document reader: %v", err)
+		}
+		app.S3Documents = reader
+		app.AnalysesService.S3Docs = reader
+	}
 
-	log.Printf("worker started queue=%s concurrency=%d visibility=%ds", queueURL, concurrency, visibilitySeconds)
+	if app.RuntimeConfig != nil {
+		goSafe("runtime_config", func() { app.RuntimeConfig.Run(ctx) })
+	}
 
-pollLoop:
-	for {
-		select {
-		case <-ctx.Done():
-			break pollLoop
-		default:
-		}
+	if app.RetentionService.Enabled() {
+		cleanupInterval := time.Duration(envInt("RA_GUEST_CLEANUP_INTERVAL_MINUTES", defaultGuestCleanupMins)) * time.Minute
+		goSafe("guest_cleanup", func() { app.RetentionService.Run(ctx, cleanupInterval) })
+		log.Printf("guest cleanup enabled ttl=%s interval=%s", app.Config.GuestRetention, cleanupInterval)
+	}
 
-		resp, err := sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
-			QueueUrl:            aws.String(queueURL),
-			MaxNumberOfMessages: 10,
-			WaitTimeSeconds:     20,
-			VisibilityTimeout:   int32(visibilitySeconds),
-			AttributeNames:      []sqstypes.QueueAttributeName{sqstypes.QueueAttributeName("ApproximateReceiveCount")},
-		})
-		if err != nil {
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
-				break pollLoop
-			}
-			log.Printf("receive message: %v", err)
-			continue
-		}
+	// Archived LLM calls are purged once their retention ends. Analysis
+	// workers run it, like the other maintenance loops.
+	if purgeMins := envInt("RA_LLM_ARCHIVE_PURGE_INTERVAL_MINUTES", defaultArchivePurgeMins); app.LLMArchive.Enabled() && stageName(stage) == queue.StageAnalysis && purgeMins > 0 {
+		goSafe("llm_archive_purge", func() { app.LLMArchive.Run(ctx, time.Duration(purgeMins)*time.Minute) })
+	}
 
-		for _, msg := range resp.Messages {
-			select {
-			case <-ctx.Done():
-				break pollLoop
-			case sem <- struct{}{}:
-			}
-			metrics.IncAnalysisJobsReceived()
-			wg.Add(1)
-			go func(m sqstypes.Message) {
-				defer wg.Done()
-				defer func() { <-sem }()
-				handleMessage(ctx, app, sqsClient, queueURL, m)
-			}(msg)
+	// Re-scoring batches run on analysis workers only; they call the LLM like
+	// analyses do, not extraction.
+	if rescoreMins := envInt("RA_RESCORE_INTERVAL_MINUTES", defaultRescoreMins); app.Rescore != nil && stageName(stage) == queue.StageAnalysis && rescoreMins > 0 {
+		app.Rescore.Schedule = rescoreSchedule()
+		goSafe("rescore", func() { app.Rescore.Run(ctx, time.Duration(rescoreMins)*time.Minute) })
+		log.Printf("rescore batches enabled interval=%dm nightly=%t", rescoreMins, app.Rescore.Schedule != nil)
+	}
+
+	// Analyses deferred by a spent LLM budget are queued once it resets. This
+	// also runs with budgets off, so none are stranded when a cap is removed.
+	if releaseMins := envInt("RA_BUDGET_RELEASE_INTERVAL_MINUTES", defaultBudgetReleaseMins); app.AnalysesService != nil && stageName(stage) == queue.StageAnalysis && releaseMins > 0 {
+		goSafe("budget_release", func() { app.AnalysesService.RunBudgetReleases(ctx, time.Duration(releaseMins)*time.Minute) })
+	}
+
+	// Each analysis worker probes the provider itself so its breaker opens
+	// without waiting on the API.
+	if interval := app.Config.LLMHealthProbeInterval; app.LLMHealth != nil && stageName(stage) == queue.StageAnalysis && interval > 0 {
+		goSafe("llm_health", func() { app.LLMHealth.Run(ctx, interval) })
+		log.Printf("llm health probe enabled model=%s interval=%s", app.LLMHealth.Model, interval)
+	}
+
+	// Stuck-state scans run on analysis workers, which own the records the
+	// scan may repair.
+	if interval := app.Config.StuckScanInterval; app.Stuck != nil && stageName(stage) == queue.StageAnalysis && interval > 0 {
+		goSafe("stuck_states", func() { app.Stuck.Run(ctx, interval) })
+		log.Printf("stuck-state scan enabled interval=%s remediate=%t", interval, app.Stuck.Remediate)
+	}
+
+	log.Printf("worker started stage=%s queue=%s concurrency=%d visibility=%ds", stageName(stage), queueURL, concurrency, visibilitySeconds)
+
+	handle := func(ctx context.Context, msg sqstypes.Message) {
+		handleMessage(ctx, app, sqsClient, queueURL, msg)
+	}
+	if stage == stageUploads {
+		events := uploadEventsFromEnv()
+		log.Printf("upload events bucket=%s prefix=%s", events.Bucket, events.Prefix)
+		handle = func(ctx context.Context, msg sqstypes.Message) {
+			handleUploadMessage(ctx, app, events, sqsClient, queueURL, msg)
 		}
 	}
 
-	log.Printf("shutdown requested, waiting up to %s for in-flight jobs", shutdownTimeout)
-	waitDone := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(waitDone)
-	}()
-	select {
-	case <-waitDone:
-	case <-time.After(shutdownTimeout):
-		log.Printf("shutdown timeout reached; exiting with in-flight jobs")
+	p := &poller{
+		Client:            sqsClient,
+		QueueURL:          queueURL,
+		Concurrency:       concurrency,
+		VisibilitySeconds: visibilitySeconds,
+		ShutdownTimeout:   cfg.ShutdownGracePeriod,
+		Handle:            handle,
+	}
+	stopHealth := serveHealth(ctx, app.Readiness)
+	if err := p.Run(ctx); err != nil {
+		log.Printf("worker stopped: %v", err)
+	}
+	stopHealth()
+}
+
+// serveHealth exposes the readiness probe on RA_WORKER_HEALTH_PORT for ECS
+// container health checks. The probe fails as soon as shutdown starts and the
+// listener stays up until the returned func is called after in-flight jobs end.
+func serveHealth(ctx context.Context, ready *server.Readiness) func() {
+	port := strings.TrimSpace(os.Getenv("RA_WORKER_HEALTH_PORT"))
+	if port == "" {
+		return func() {}
+	}
+	mux := http.NewServeMux()
+	mux.Handle(server.ReadyPath, ready)
+	srv := &http.Server{Addr: server.Addr(port), Handler: mux}
+
+	serveCtx, stopServe := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	goSafe("health_server", func() {
+		defer close(done)
+		if err := server.Serve(serveCtx, srv, server.ShutdownOptions{GracePeriod: time.Second}); err != nil {
+			log.Printf("health server: %v", err)
+		}
+	})
+	goSafe("health_drain", func() {
+		<-ctx.Done()
+		ready.Drain()
+	})
+	log.Printf("worker health probe listening on %s%s", srv.Addr, server.ReadyPath)
+	return func() {
+		stopServe()
+		<-done
 	}
 }
 
@@ -170,9 +251,18 @@ func handleMessage(ctx context.Context, app *bootstrap.App, client sqsAPI, queue
 
 	telemetry.Info("worker.analysis.received", baseFields(msg, decoded.AnalysisID, decoded.RequestID))
 
-	ctxWithParsed := workerproc.WithParsedMessage(ctx, decoded)
+	ctxWithParsed := workerproc.WithMessageID(workerproc.WithParsedMessage(ctx, decoded), aws.ToString(msg.MessageId))
 	if err := workerproc.HandleMessage(ctxWithParsed, app, body); err != nil {
+		if _, ok := err.(workerproc.ErrDuplicate); ok {
+			// Another worker holds this message; it deletes it once done.
+			return
+		}
 		if procErr, ok := err.(workerproc.ErrProcess); ok {
+			if errors.Is(procErr.Err, analyses.ErrExtractionPending) {
+				// Leave the message for redelivery once the extract stage finishes.
+				telemetry.Info("worker.analysis.awaiting_extraction", baseFields(msg, procErr.AnalysisID, procErr.RequestID))
+				return
+			}
 			fields := baseFields(msg, procErr.AnalysisID, procErr.RequestID)
 			fields["error"] = procErr.Err.Error()
 			telemetry.Error("worker.analysis.failed", fields)
@@ -193,6 +283,46 @@ func handleMessage(ctx context.Context, app *bootstrap.App, client sqsAPI, queue
 	}
 }
 
+// handleUploadMessage registers the uploads in an S3 event notification. Bodies
+// that cannot be decoded are deleted; failed registrations and extractions are
+// left for redelivery and, eventually, the dead-letter queue.
+func handleUploadMessage(ctx context.Context, app *bootstrap.App, events workerproc.UploadEvents, client sqsAPI, queueURL string, msg sqstypes.Message) {
+	err := workerproc.HandleUploadEvent(ctx, app, events, aws.ToString(msg.Body))
+	var decodeErr workerproc.ErrDecode
+	var emptyErr workerproc.ErrEmptyBody
+	switch {
+	case errors.As(err, &decodeErr) || errors.As(err, &emptyErr):
+		fields := baseFields(msg, "", "")
+		fields["error"] = err.Error()
+		telemetry.Error("worker.upload.decode_failed", fields)
+		if deleteMessage(ctx, client, queueURL, msg, "", "") {
+			metrics.IncAnalysisJobsDeletedUnrecoverable()
+		}
+	case err != nil:
+		fields := baseFields(msg, "", "")
+		fields["error"] = err.Error()
+		telemetry.Error("worker.upload.failed", fields)
+	default:
+		deleteMessage(ctx, client, queueURL, msg, "", "")
+	}
+}
+
+// uploadEventsFromEnv reads the uploads bucket and prefix the presign endpoint
+// uses, so only keys it hands out are registered.
+func uploadEventsFromEnv() workerproc.UploadEvents {
+	prefix := strings.TrimSpace(os.Getenv("UPLOADS_S3_PREFIX"))
+	if prefix == "" {
+		prefix = defaultUploadsPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return workerproc.UploadEvents{
+		Bucket: strings.TrimSpace(os.Getenv("UPLOADS_S3_BUCKET")),
+		Prefix: prefix,
+	}
+}
+
 func deleteMessage(ctx context.Context, client sqsAPI, queueURL string, msg sqstypes.Message, analysisID, requestID string) bool {
 	receipt := aws.ToString(msg.ReceiptHandle)
 	if receipt == "" {
@@ -240,6 +370,33 @@ func receiveCount(msg sqstypes.Message) int {
 	return parsed
 }
 
+func stageName(stage string) string {
+	if stage == "" {
+		return queue.StageAnalysis
+	}
+	return stage
+}
+
+// rescoreSchedule reads the nightly re-scoring batch from the environment. It
+// is off unless RA_RESCORE_NIGHTLY_PROMPT_VERSION is set.
+func rescoreSchedule() *rescore.Schedule {
+	version := strings.TrimSpace(os.Getenv("RA_RESCORE_NIGHTLY_PROMPT_VERSION"))
+	if version == "" {
+		return nil
+	}
+	return &rescore.Schedule{
+		Hour: envInt("RA_RESCORE_NIGHTLY_HOUR", defaultRescoreHour),
+		Request: rescore.CreateRequest{
+			PromptVersion:     version,
+			Model:             strings.TrimSpace(os.Getenv("RA_RESCORE_NIGHTLY_MODEL")),
+			Mode:              strings.TrimSpace(os.Getenv("RA_RESCORE_NIGHTLY_MODE")),
+			SinceDays:         envInt("RA_RESCORE_NIGHTLY_SINCE_DAYS", 1),
+			MaxAnalyses:       envInt("RA_RESCORE_NIGHTLY_MAX_ANALYSES", 0),
+			RequestsPerMinute: envInt("RA_RESCORE_NIGHTLY_REQUESTS_PER_MINUTE", 0),
+		},
+	}
+}
+
 func envInt(key string, def int) int {
 	raw := strings.TrimSpace(os.Getenv(key))
 	if raw == "" {
@@ -251,10 +408,3 @@ func envInt(key string, def int) int {
 	}
 	return val
 }
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
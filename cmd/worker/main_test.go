@@ -3,14 +3,18 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 
 	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/documents"
 	"resume-backend/internal/queue"
+	"resume-backend/internal/workerproc"
 )
 
 type fakeSQS struct {
@@ -76,6 +80,44 @@ func TestWorkerDoesNotDeleteOnFailure(t *testing.T) {
 	}
 }
 
+type countingProcessor struct {
+	err   error
+	calls int
+}
+
+func (p *countingProcessor) ProcessAnalysis(ctx context.Context, analysisID string) error {
+	p.calls++
+	return p.err
+}
+
+func TestWorkerSkipsDuplicateDeliveries(t *testing.T) {
+	client := &fakeSQS{}
+	processor := &countingProcessor{err: errors.New("boom")}
+	app := &bootstrap.App{AnalysisProcessor: processor, Dedup: queue.NewMemoryDedup(time.Minute)}
+	msgBody, _ := queue.EncodeMessage(queue.Message{AnalysisID: "analysis-4", RequestID: "req-4"})
+	delivery := func(receipt string) sqstypes.Message {
+		return sqstypes.Message{
+			MessageId:     aws.String("m4"),
+			ReceiptHandle: aws.String(receipt),
+			Body:          aws.String(string(msgBody)),
+		}
+	}
+
+	// A failed attempt releases its claim, so the redelivery is processed.
+	handleMessage(context.Background(), app, client, "queue", delivery("r4a"))
+	processor.err = nil
+	handleMessage(context.Background(), app, client, "queue", delivery("r4b"))
+	if processor.calls != 2 || len(client.deleted) != 1 {
+		t.Fatalf("expected the retry to be processed, calls=%d deleted=%v", processor.calls, client.deleted)
+	}
+
+	// A duplicate of the successful delivery is skipped and left on the queue.
+	handleMessage(context.Background(), app, client, "queue", delivery("r4c"))
+	if processor.calls != 2 || len(client.deleted) != 1 {
+		t.Fatalf("expected the duplicate to be skipped, calls=%d deleted=%v", processor.calls, client.deleted)
+	}
+}
+
 func TestWorkerDeletesOnInvalidJSON(t *testing.T) {
 	client := &fakeSQS{}
 	app := &bootstrap.App{AnalysisProcessor: fakeProcessor{}}
@@ -91,3 +133,83 @@ func TestWorkerDeletesOnInvalidJSON(t *testing.T) {
 		t.Fatalf("expected delete, got %d", len(client.deleted))
 	}
 }
+
+type fakeDocumentExtractor struct {
+	repo  documents.DocumentsRepo
+	err   error
+	calls int
+}
+
+func (f *fakeDocumentExtractor) ExtractDocument(ctx context.Context, userID, documentID string) (documents.Document, error) {
+	f.calls++
+	if f.err != nil {
+		return documents.Document{}, f.err
+	}
+	if err := f.repo.UpdateExtraction(ctx, userID, documentID, "text-key", time.Now().UTC()); err != nil {
+		return documents.Document{}, err
+	}
+	return f.repo.GetByID(ctx, userID, documentID)
+}
+
+func TestUploadEventRegistersAndExtractsDocument(t *testing.T) {
+	const (
+		docID = "0b7c4a52-5f0e-4c1f-9a65-0c1d2b3a4e5f"
+		key   = "documents/u1/" + docID + "/9d8e7f60-1a2b-4c3d-8e9f-a0b1c2d3e4f5-resume.pdf"
+	)
+	repo := documents.NewMemoryRepo()
+	extractor := &fakeDocumentExtractor{repo: repo, err: errors.New("s3 unavailable")}
+	app := &bootstrap.App{
+		DocumentsService:  &documents.Service{Repo: documents.NewWorkerRepo(repo)},
+		DocumentExtractor: extractor,
+	}
+	events := workerproc.UploadEvents{Bucket: "uploads", Prefix: "documents/"}
+	body := `{"Records":[{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"uploads"},"object":{"key":"` + key + `","size":1024}}}]}`
+	msg := sqstypes.Message{MessageId: aws.String("m4"), ReceiptHandle: aws.String("r4"), Body: aws.String(body)}
+
+	// A failed extraction leaves the event for redelivery.
+	client := &fakeSQS{}
+	handleUploadMessage(context.Background(), app, events, client, "queue", msg)
+	if len(client.deleted) != 0 {
+		t.Fatalf("expected no delete after a failed extraction, got %d", len(client.deleted))
+	}
+
+	extractor.err = nil
+	handleUploadMessage(context.Background(), app, events, client, "queue", msg)
+	if len(client.deleted) != 1 {
+		t.Fatalf("expected delete, got %d", len(client.deleted))
+	}
+	doc, err := repo.GetByID(context.Background(), "u1", docID)
+	if err != nil || doc.Status() != documents.StatusReady {
+		t.Fatalf("expected a ready document, got %+v err=%v", doc, err)
+	}
+
+	// Redelivery finds the ready document and extracts nothing.
+	handleUploadMessage(context.Background(), app, events, client, "queue", msg)
+	if extractor.calls != 2 || len(client.deleted) != 2 {
+		t.Fatalf("expected no extraction on redelivery, calls=%d deleted=%d", extractor.calls, len(client.deleted))
+	}
+}
+
+func TestUploadEventSkipsForeignKeysAndDropsBadBodies(t *testing.T) {
+	repo := documents.NewMemoryRepo()
+	app := &bootstrap.App{
+		DocumentsService:  &documents.Service{Repo: repo},
+		DocumentExtractor: &fakeDocumentExtractor{repo: repo},
+	}
+	events := workerproc.UploadEvents{Bucket: "uploads", Prefix: "documents/"}
+	client := &fakeSQS{}
+	for i, body := range []string{
+		`{"Records":[{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"uploads"},"object":{"key":"exports/report.pdf","size":10}}}]}`,
+		`{"Service":"Amazon S3","Event":"s3:TestEvent"}`,
+		`{bad-json`,
+	} {
+		msg := sqstypes.Message{MessageId: aws.String("m"), ReceiptHandle: aws.String(fmt.Sprint("r", i)), Body: aws.String(body)}
+		handleUploadMessage(context.Background(), app, events, client, "queue", msg)
+	}
+	if len(client.deleted) != 3 {
+		t.Fatalf("expected every message deleted, got %d", len(client.deleted))
+	}
+	if docs, _ := repo.ListByUser(context.Background(), "u1", 10, 0); len(docs) != 0 {
+		t.Fatalf("expected no documents, got %d", len(docs))
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"golang.org/x/sync/errgroup"
+
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// errShutdownTimeout is returned by poller.Run when in-flight jobs outlive the
+// shutdown timeout.
+var errShutdownTimeout = errors.New("shutdown timeout reached with in-flight jobs")
+
+// receiveErrorBackoff spaces out ReceiveMessage retries while SQS is failing.
+const receiveErrorBackoff = time.Second
+
+// poller receives SQS messages and runs up to Concurrency handlers at once.
+type poller struct {
+	Client            sqsAPI
+	QueueURL          string
+	Concurrency       int
+	VisibilitySeconds int
+	// ShutdownTimeout is how long in-flight jobs may keep running once polling
+	// stops. Their context is canceled when it expires.
+	ShutdownTimeout time.Duration
+	// Handle processes one message. A panic is recovered and logged, and the
+	// message is left for redelivery.
+	Handle func(ctx context.Context, msg sqstypes.Message)
+}
+
+// Run polls until ctx is canceled, then waits for in-flight jobs. Jobs do not see
+// ctx's cancellation, so a shutdown signal does not abort work that is nearly
+// done; they are canceled only if ShutdownTimeout passes first.
+func (p *poller) Run(ctx context.Context) error {
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	var jobs errgroup.Group
+	jobs.SetLimit(max(1, p.Concurrency))
+
+	for ctx.Err() == nil {
+		resp, err := p.Client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(p.QueueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     20,
+			VisibilityTimeout:   int32(p.VisibilitySeconds),
+			AttributeNames:      []sqstypes.QueueAttributeName{sqstypes.QueueAttributeName("ApproximateReceiveCount")},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("receive message: %v", err)
+			sleepCtx(ctx, receiveErrorBackoff)
+			continue
+		}
+		for _, msg := range resp.Messages {
+			metrics.IncAnalysisJobsReceived()
+			// Go blocks while Concurrency jobs are running.
+			jobs.Go(func() error {
+				if ctx.Err() != nil {
+					// Polling stopped while this message waited for a slot; SQS
+					// redelivers it after the visibility timeout.
+					return nil
+				}
+				p.runJob(jobCtx, msg)
+				return nil
+			})
+		}
+	}
+
+	log.Printf("shutdown requested, waiting up to %s for in-flight jobs", p.ShutdownTimeout)
+	done := make(chan struct{})
+	go func() {
+		_ = jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(p.ShutdownTimeout):
+		cancelJobs()
+		return errShutdownTimeout
+	}
+}
+
+// runJob runs Handle, keeping a panic from taking the worker down.
+func (p *poller) runJob(ctx context.Context, msg sqstypes.Message) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			fields := baseFields(msg, "", "")
+			fields["error"] = fmt.Sprint(rec)
+			fields["stack"] = string(debug.Stack())
+			telemetry.Error("worker.analysis.panic", fields)
+			metrics.IncAnalysisJobsFailed()
+		}
+	}()
+	p.Handle(ctx, msg)
+}
+
+// goSafe runs fn in a goroutine that logs and swallows panics.
+func goSafe(name string, fn func()) {
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				telemetry.Error("worker.goroutine.panic", map[string]any{
+					"goroutine": name,
+					"error":     fmt.Sprint(rec),
+					"stack":     string(debug.Stack()),
+				})
+			}
+		}()
+		fn()
+	}()
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
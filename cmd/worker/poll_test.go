@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// scriptedSQS returns one batch per ReceiveMessage call, then blocks until the
+// poll context is canceled, like a long poll on an empty queue.
+type scriptedSQS struct {
+	mu       sync.Mutex
+	batches  [][]sqstypes.Message
+	receives int
+}
+
+func newScriptedSQS(batches ...[]sqstypes.Message) *scriptedSQS {
+	return &scriptedSQS{batches: batches}
+}
+
+func (f *scriptedSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	f.receives++
+	if len(f.batches) > 0 {
+		batch := f.batches[0]
+		f.batches = f.batches[1:]
+		f.mu.Unlock()
+		return &sqs.ReceiveMessageOutput{Messages: batch}, nil
+	}
+	f.mu.Unlock()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *scriptedSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func messages(ids ...string) []sqstypes.Message {
+	out := make([]sqstypes.Message, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, sqstypes.Message{MessageId: aws.String(id), ReceiptHandle: aws.String("r-" + id)})
+	}
+	return out
+}
+
+func TestPollerBoundsConcurrencyAndSurvivesPanics(t *testing.T) {
+	client := newScriptedSQS(messages("m1", "m2", "boom", "m4"), messages("m5", "m6"))
+	var running, peak, handled atomic.Int32
+	release := make(chan struct{})
+
+	p := &poller{
+		Client:          client,
+		QueueURL:        "queue",
+		Concurrency:     2,
+		ShutdownTimeout: 5 * time.Second,
+		Handle: func(ctx context.Context, msg sqstypes.Message) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			<-release
+			if aws.ToString(msg.MessageId) == "boom" {
+				panic("handler exploded")
+			}
+			handled.Add(1)
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- p.Run(ctx) }()
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for handled.Load() < 5 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("poller did not stop")
+	}
+	if got := handled.Load(); got != 5 {
+		t.Fatalf("expected 5 handled messages around the panic, got %d", got)
+	}
+	if got := peak.Load(); got > 2 {
+		t.Fatalf("expected at most 2 concurrent handlers, saw %d", got)
+	}
+}
+
+func TestPollerLetsInFlightJobsFinishAfterCancel(t *testing.T) {
+	client := newScriptedSQS(messages("m1"))
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var jobErr error
+
+	p := &poller{
+		Client:          client,
+		Concurrency:     1,
+		ShutdownTimeout: 5 * time.Second,
+		Handle: func(ctx context.Context, msg sqstypes.Message) {
+			close(started)
+			<-finish
+			jobErr = ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- p.Run(ctx) }()
+
+	<-started
+	cancel()
+	select {
+	case <-result:
+		t.Fatalf("run returned before the in-flight job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(finish)
+
+	if err := <-result; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if jobErr != nil {
+		t.Fatalf("in-flight job saw cancellation: %v", jobErr)
+	}
+	if client.receives != 2 {
+		t.Fatalf("expected polling to stop after cancel, got %d receives", client.receives)
+	}
+}
+
+func TestPollerCancelsJobsAfterShutdownTimeout(t *testing.T) {
+	client := newScriptedSQS(messages("m1"))
+	started := make(chan struct{})
+	jobCanceled := make(chan struct{})
+
+	p := &poller{
+		Client:          client,
+		Concurrency:     1,
+		ShutdownTimeout: 20 * time.Millisecond,
+		Handle: func(ctx context.Context, msg sqstypes.Message) {
+			close(started)
+			<-ctx.Done()
+			close(jobCanceled)
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- p.Run(ctx) }()
+
+	<-started
+	cancel()
+	if err := <-result; !errors.Is(err, errShutdownTimeout) {
+		t.Fatalf("expected shutdown timeout, got %v", err)
+	}
+	select {
+	case <-jobCanceled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("job context was not canceled after the shutdown timeout")
+	}
+}
@@ -0,0 +1,374 @@
+// Package abuse catches guests who reset their identity to farm the free tier.
+// Guests are grouped into clusters by client IP and device fingerprint; a
+// cluster that churns through guest identities, or whose guests together go
+// past its shadow limit, is flagged for an admin to review.
+package abuse
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+const (
+	// sweepEvery is how many checks pass between sweeps of idle clusters.
+	sweepEvery = 1024
+	// maxFlagGuests bounds the guest IDs kept on a flag.
+	maxFlagGuests = 50
+)
+
+// Detector applies the velocity rules. Activity is counted in memory, so with
+// several API instances each counts only the requests it serves; flags and
+// review decisions are shared through Repo.
+type Detector struct {
+	Repo       Repo
+	Mode       string
+	Thresholds Thresholds
+	// HashKey keys the hashes that stand in for IPs and fingerprints; empty
+	// uses plain SHA-256.
+	HashKey []byte
+	// Audit records reviews; nil skips auditing.
+	Audit *audit.Service
+	Now   func() time.Time
+
+	mu       sync.Mutex
+	clusters map[string]*activity
+	checks   int
+}
+
+// activity is what one cluster did within the window.
+type activity struct {
+	guests   map[string]time.Time
+	requests []time.Time
+}
+
+// cluster is one of the groups a request belongs to.
+type cluster struct {
+	key         string
+	kind        string
+	maxGuests   int
+	maxRequests int
+}
+
+// NewDetector constructs a Detector. A zero Window or ReviewTTL takes its
+// default.
+func NewDetector(repo Repo, mode string, thresholds Thresholds) *Detector {
+	if thresholds.Window <= 0 {
+		thresholds.Window = DefaultThresholds.Window
+	}
+	if thresholds.ReviewTTL <= 0 {
+		thresholds.ReviewTTL = DefaultThresholds.ReviewTTL
+	}
+	return &Detector{
+		Repo:       repo,
+		Mode:       mode,
+		Thresholds: thresholds,
+		clusters:   make(map[string]*activity),
+	}
+}
+
+// Enabled reports whether the detector checks requests.
+func (d *Detector) Enabled() bool {
+	return d != nil && (d.Mode == ModeShadow || d.Mode == ModeEnforce)
+}
+
+// Check counts the guest against its clusters and decides whether the request
+// may go ahead. Failing to read or write flags lets the request through.
+func (d *Detector) Check(ctx context.Context, id Identity) Decision {
+	if !d.Enabled() {
+		return Decision{}
+	}
+	now := d.now()
+	var decision Decision
+	for _, cl := range d.clustersOf(id) {
+		guests, requests := d.observe(cl.key, id.GuestID, now)
+
+		trusted := false
+		latest, err := d.Repo.Latest(ctx, cl.key)
+		switch {
+		case err == nil && d.inEffect(latest, now):
+			if latest.Status == StatusConfirmed {
+				metrics.IncAbuseRequestBlocked()
+				return Decision{Block: true, Rule: latest.Rule, FlagID: latest.ID}
+			}
+			trusted = latest.Status == StatusDismissed
+		case err != nil && !errors.Is(err, ErrNotFound):
+			telemetry.ErrorContext(ctx, "abuse.flag_lookup_failed", map[string]any{"kind": cl.kind, "error": err.Error()})
+		}
+		if trusted {
+			continue
+		}
+
+		rule := ""
+		switch {
+		case cl.maxGuests > 0 && len(guests) > cl.maxGuests:
+			rule = RuleGuests
+		case cl.maxRequests > 0 && requests >= cl.maxRequests:
+			rule = RuleRequests
+		}
+		if rule == "" {
+			continue
+		}
+		flagID := d.raise(ctx, cl, rule, guests, requests, now)
+		if decision.Rule == "" {
+			decision.Rule, decision.FlagID = rule, flagID
+		}
+	}
+	if decision.Rule == "" {
+		return decision
+	}
+	if d.Mode == ModeEnforce {
+		decision.Block = true
+		metrics.IncAbuseRequestBlocked()
+	} else {
+		decision.Shadowed = true
+		metrics.IncAbuseRequestShadowed()
+	}
+	return decision
+}
+
+// Record counts a request the guest's clusters were allowed to make.
+func (d *Detector) Record(id Identity) {
+	if !d.Enabled() {
+		return
+	}
+	now := d.now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cl := range d.clustersOf(id) {
+		act := d.activityLocked(cl.key)
+		act.requests = append(act.requests, now)
+	}
+}
+
+// raise opens or refreshes the cluster's flag and returns its ID.
+func (d *Detector) raise(ctx context.Context, cl cluster, rule string, guests []string, requests int, now time.Time) string {
+	flagged := guests
+	if len(flagged) > maxFlagGuests {
+		flagged = flagged[:maxFlagGuests]
+	}
+	flag, created, err := d.Repo.Raise(ctx, Flag{
+		ID:           uuid.NewString(),
+		Key:          cl.key,
+		Kind:         cl.kind,
+		Rule:         rule,
+		GuestIDs:     flagged,
+		GuestCount:   len(guests),
+		RequestCount: requests,
+		Status:       StatusOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		telemetry.ErrorContext(ctx, "abuse.flag_raise_failed", map[string]any{"kind": cl.kind, "rule": rule, "error": err.Error()})
+		return ""
+	}
+	if created {
+		metrics.IncAbuseFlagRaised()
+		telemetry.InfoContext(ctx, "abuse.flag_raised", map[string]any{
+			"flag_id":       flag.ID,
+			"kind":          cl.kind,
+			"rule":          rule,
+			"guest_count":   len(guests),
+			"request_count": requests,
+			"mode":          d.Mode,
+		})
+	}
+	return flag.ID
+}
+
+// inEffect reports whether an admin's review of the flag still applies.
+func (d *Detector) inEffect(flag Flag, now time.Time) bool {
+	return flag.ReviewedAt != nil && now.Before(flag.ReviewedAt.Add(d.Thresholds.ReviewTTL))
+}
+
+// observe adds the guest to the cluster and returns the cluster's guests and
+// recorded requests within the window.
+func (d *Detector) observe(key, guestID string, now time.Time) ([]string, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checks++
+	if d.checks%sweepEvery == 0 {
+		d.sweepLocked(now)
+	}
+	act := d.activityLocked(key)
+	act.prune(now.Add(-d.Thresholds.Window))
+	if guestID != "" {
+		act.guests[guestID] = now
+	}
+	guests := make([]string, 0, len(act.guests))
+	for guest := range act.guests {
+		guests = append(guests, guest)
+	}
+	sort.Strings(guests)
+	return guests, len(act.requests)
+}
+
+func (d *Detector) activityLocked(key string) *activity {
+	if d.clusters == nil {
+		d.clusters = make(map[string]*activity)
+	}
+	act, ok := d.clusters[key]
+	if !ok {
+		act = &activity{guests: make(map[string]time.Time)}
+		d.clusters[key] = act
+	}
+	return act
+}
+
+// sweepLocked drops clusters with nothing left in the window.
+func (d *Detector) sweepLocked(now time.Time) {
+	cutoff := now.Add(-d.Thresholds.Window)
+	for key, act := range d.clusters {
+		act.prune(cutoff)
+		if len(act.guests) == 0 && len(act.requests) == 0 {
+			delete(d.clusters, key)
+		}
+	}
+}
+
+func (a *activity) prune(cutoff time.Time) {
+	for guest, seen := range a.guests {
+		if seen.Before(cutoff) {
+			delete(a.guests, guest)
+		}
+	}
+	kept := a.requests[:0]
+	for _, at := range a.requests {
+		if !at.Before(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	a.requests = kept
+}
+
+func (d *Detector) clustersOf(id Identity) []cluster {
+	var out []cluster
+	if device := strings.TrimSpace(id.Device); device != "" {
+		out = append(out, cluster{
+			key:         d.clusterKey(KindDevice, device),
+			kind:        KindDevice,
+			maxGuests:   d.Thresholds.MaxGuestsPerDevice,
+			maxRequests: d.Thresholds.MaxRequestsPerDevice,
+		})
+	}
+	if ip := strings.TrimSpace(id.IP); ip != "" {
+		out = append(out, cluster{
+			key:         d.clusterKey(KindIP, ip),
+			kind:        KindIP,
+			maxGuests:   d.Thresholds.MaxGuestsPerIP,
+			maxRequests: d.Thresholds.MaxRequestsPerIP,
+		})
+	}
+	return out
+}
+
+func (d *Detector) clusterKey(kind, value string) string {
+	var sum []byte
+	if len(d.HashKey) > 0 {
+		mac := hmac.New(sha256.New, d.HashKey)
+		mac.Write([]byte(kind + ":" + value))
+		sum = mac.Sum(nil)
+	} else {
+		digest := sha256.Sum256([]byte(kind + ":" + value))
+		sum = digest[:]
+	}
+	return kind + ":" + hex.EncodeToString(sum[:16])
+}
+
+func (d *Detector) now() time.Time {
+	if d.Now != nil {
+		return d.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// List returns flags newest first, optionally only those with status.
+func (d *Detector) List(ctx context.Context, status string, limit int) ([]Flag, error) {
+	switch status {
+	case "", StatusOpen, StatusConfirmed, StatusDismissed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidInput, status)
+	}
+	return d.Repo.List(ctx, status, limit)
+}
+
+// Review records an admin's decision on an open flag. Confirming blocks the
+// cluster's guests, in shadow mode too; dismissing exempts the cluster from
+// the rules. Either lasts for Thresholds.ReviewTTL.
+func (d *Detector) Review(ctx context.Context, reviewerID, flagID, decision string) (Flag, error) {
+	var status string
+	switch decision {
+	case "confirm":
+		status = StatusConfirmed
+	case "dismiss":
+		status = StatusDismissed
+	default:
+		return Flag{}, fmt.Errorf("%w: decision must be confirm or dismiss", ErrInvalidInput)
+	}
+	flag, err := d.Repo.Review(ctx, flagID, status, reviewerID, d.now())
+	if err != nil {
+		return flag, err
+	}
+	telemetry.InfoContext(ctx, "abuse.flag_reviewed", map[string]any{
+		"flag_id":  flag.ID,
+		"kind":     flag.Kind,
+		"status":   flag.Status,
+		"reviewer": reviewerID,
+	})
+	if d.Audit != nil {
+		_ = d.Audit.Record(ctx, audit.Entry{
+			Action:      "abuse.flag_reviewed",
+			ActorUserID: reviewerID,
+			Details: map[string]any{
+				"flagId":     flag.ID,
+				"clusterKey": flag.Key,
+				"status":     flag.Status,
+				"guestCount": flag.GuestCount,
+			},
+		})
+	}
+	return flag, nil
+}
+
+// Stats reports the detector's mode, thresholds and review backlog for the
+// admin stats endpoint.
+func (d *Detector) Stats(ctx context.Context) (any, error) {
+	if !d.Enabled() {
+		return map[string]any{"mode": ModeOff}, nil
+	}
+	open, err := d.Repo.CountOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	tracked := len(d.clusters)
+	d.mu.Unlock()
+	t := d.Thresholds
+	return map[string]any{
+		"mode":            d.Mode,
+		"openFlags":       open,
+		"trackedClusters": tracked,
+		"thresholds": map[string]any{
+			"windowMinutes":        int(t.Window / time.Minute),
+			"maxGuestsPerDevice":   t.MaxGuestsPerDevice,
+			"maxGuestsPerIp":       t.MaxGuestsPerIP,
+			"maxRequestsPerDevice": t.MaxRequestsPerDevice,
+			"maxRequestsPerIp":     t.MaxRequestsPerIP,
+			"reviewTtlDays":        int(t.ReviewTTL / (24 * time.Hour)),
+		},
+	}, nil
+}
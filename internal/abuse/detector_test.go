@@ -0,0 +1,133 @@
+package abuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestDetector(mode string) (*Detector, *MemoryRepo, *time.Time) {
+	repo := NewMemoryRepo()
+	d := NewDetector(repo, mode, Thresholds{
+		MaxGuestsPerDevice:   2,
+		MaxGuestsPerIP:       10,
+		MaxRequestsPerDevice: 5,
+		MaxRequestsPerIP:     50,
+	})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	d.Now = func() time.Time { return now }
+	return d, repo, &now
+}
+
+func TestGuestVelocityShadowVersusEnforce(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		mode  string
+		block bool
+	}{
+		{ModeShadow, false},
+		{ModeEnforce, true},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			d, repo, _ := newTestDetector(tc.mode)
+			for i := 1; i <= 2; i++ {
+				if got := d.Check(ctx, Identity{GuestID: fmt.Sprintf("g%d", i), IP: "10.0.0.1", Device: "fp-1"}); got.Rule != "" {
+					t.Fatalf("guest %d: expected no violation, got %+v", i, got)
+				}
+			}
+			got := d.Check(ctx, Identity{GuestID: "g3", IP: "10.0.0.1", Device: "fp-1"})
+			if got.Rule != RuleGuests || got.Block != tc.block || got.Shadowed == tc.block || got.FlagID == "" {
+				t.Fatalf("third guest: got %+v", got)
+			}
+
+			// Another device on the same IP is not caught by the device rule.
+			if got := d.Check(ctx, Identity{GuestID: "g4", IP: "10.0.0.1", Device: "fp-2"}); got.Rule != "" {
+				t.Fatalf("other device: expected no violation, got %+v", got)
+			}
+
+			flags, err := repo.List(ctx, StatusOpen, 10)
+			if err != nil || len(flags) != 1 {
+				t.Fatalf("expected one open flag, got %+v (err %v)", flags, err)
+			}
+			if flags[0].Kind != KindDevice || flags[0].GuestCount != 3 || flags[0].Key == "device:fp-1" {
+				t.Fatalf("unexpected flag %+v", flags[0])
+			}
+		})
+	}
+}
+
+func TestShadowLimitCountsRecordedRequests(t *testing.T) {
+	ctx := context.Background()
+	d, _, now := newTestDetector(ModeEnforce)
+	id := Identity{GuestID: "g1", IP: "10.0.0.1", Device: "fp-1"}
+	for i := 0; i < 5; i++ {
+		if got := d.Check(ctx, id); got.Block {
+			t.Fatalf("request %d: unexpected block %+v", i, got)
+		}
+		d.Record(id)
+	}
+	if got := d.Check(ctx, id); !got.Block || got.Rule != RuleRequests {
+		t.Fatalf("expected the shadow limit to block, got %+v", got)
+	}
+
+	*now = now.Add(25 * time.Hour)
+	if got := d.Check(ctx, id); got.Block {
+		t.Fatalf("expected the window to have passed, got %+v", got)
+	}
+}
+
+func TestReviewConfirmBlocksAndDismissTrusts(t *testing.T) {
+	ctx := context.Background()
+	d, _, now := newTestDetector(ModeShadow)
+	farm := func(device string) Decision {
+		var last Decision
+		for i := 1; i <= 3; i++ {
+			last = d.Check(ctx, Identity{GuestID: fmt.Sprintf("%s-g%d", device, i), Device: device})
+		}
+		return last
+	}
+
+	confirmed := farm("fp-bad")
+	if _, err := d.Review(ctx, "admin-1", confirmed.FlagID, "confirm"); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	// Confirmed clusters are blocked even in shadow mode, new guests included.
+	if got := d.Check(ctx, Identity{GuestID: "fresh", Device: "fp-bad"}); !got.Block {
+		t.Fatalf("expected confirmed cluster to be blocked, got %+v", got)
+	}
+
+	dismissed := farm("fp-family")
+	if _, err := d.Review(ctx, "admin-1", dismissed.FlagID, "dismiss"); err != nil {
+		t.Fatalf("dismiss: %v", err)
+	}
+	if got := d.Check(ctx, Identity{GuestID: "fp-family-g4", Device: "fp-family"}); got.Rule != "" || got.Block {
+		t.Fatalf("expected dismissed cluster to be trusted, got %+v", got)
+	}
+
+	if _, err := d.Review(ctx, "admin-2", dismissed.FlagID, "confirm"); !errors.Is(err, ErrAlreadyReviewed) {
+		t.Fatalf("expected ErrAlreadyReviewed, got %v", err)
+	}
+	if _, err := d.Review(ctx, "admin-1", dismissed.FlagID, "ban"); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+
+	// Reviews expire, after which the rules apply again.
+	*now = now.Add(31 * 24 * time.Hour)
+	if got := d.Check(ctx, Identity{GuestID: "fresh", Device: "fp-bad"}); got.Block {
+		t.Fatalf("expected the confirmation to have expired, got %+v", got)
+	}
+}
+
+func TestOffModeChecksNothing(t *testing.T) {
+	d, repo, _ := newTestDetector(ModeOff)
+	for i := 0; i < 10; i++ {
+		if got := d.Check(context.Background(), Identity{GuestID: fmt.Sprintf("g%d", i), Device: "fp-1"}); got != (Decision{}) {
+			t.Fatalf("expected no decision, got %+v", got)
+		}
+	}
+	if n, _ := repo.CountOpen(context.Background()); n != 0 {
+		t.Fatalf("expected no flags, got %d", n)
+	}
+}
@@ -0,0 +1,14 @@
+package abuse
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the flag does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrAlreadyReviewed indicates the flag was already confirmed or dismissed.
+	ErrAlreadyReviewed = errors.New("flag already reviewed")
+)
@@ -0,0 +1,91 @@
+package abuse
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// Handler serves the abuse review queue to admins.
+type Handler struct {
+	Detector *Detector
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(detector *Detector) *Handler {
+	return &Handler{Detector: detector}
+}
+
+// RegisterRoutes attaches review routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/abuse/flags", h.list)
+	rg.POST("/abuse/flags/:id/review", h.review)
+}
+
+// list returns flags newest first; ?status= defaults to open ones, and
+// status=all lists every flag.
+func (h *Handler) list(c *gin.Context) {
+	status := strings.TrimSpace(c.DefaultQuery("status", StatusOpen))
+	if status == "all" {
+		status = ""
+	}
+	limit := defaultListLimit
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxListLimit {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "limit must be between 1 and 200", []map[string]string{
+				{"field": "limit", "issue": "out_of_range"},
+			})
+			return
+		}
+		limit = n
+	}
+	flags, err := h.Detector.List(c.Request.Context(), status, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"flags": flags})
+}
+
+type reviewRequest struct {
+	Decision string `json:"decision"`
+}
+
+func (h *Handler) review(c *gin.Context) {
+	var req reviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	flag, err := h.Detector.Review(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"), strings.TrimSpace(req.Decision))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, flag)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "flag not found", nil)
+	case errors.Is(err, ErrAlreadyReviewed):
+		respond.Error(c, http.StatusConflict, "conflict", "flag was already reviewed", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load abuse flags", nil)
+	}
+}
@@ -0,0 +1,45 @@
+package abuse
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/usage"
+)
+
+// DeviceHeader carries the client's device fingerprint. Clients that do not
+// send one are clustered by IP alone.
+const DeviceHeader = "X-Device-Fingerprint"
+
+// Guard checks guest requests to the routes guarded picks, meant to be those
+// that spend LLM tokens. Signed-in users are not checked: their usage is tied
+// to an account they cannot reset. A blocked guest gets the same response as
+// any guest out of analyses, so the block does not tell farmers what tripped.
+func (d *Detector) Guard(guarded func(*gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !d.Enabled() || !middleware.IsGuest(c) || !guarded(c) {
+			c.Next()
+			return
+		}
+		id := Identity{
+			GuestID: middleware.UserIDFromContext(c),
+			IP:      c.ClientIP(),
+			Device:  strings.TrimSpace(c.GetHeader(DeviceHeader)),
+		}
+		if decision := d.Check(c.Request.Context(), id); decision.Block {
+			c.Header(usage.RemainingHeader, "0")
+			respond.Error(c, http.StatusTooManyRequests, "limit_reached", "You've reached the free analysis limit. Sign in to continue.", []map[string]string{
+				{"field": "usage", "issue": "limit_reached"},
+			})
+			return
+		}
+		c.Next()
+		if c.Writer.Status() < http.StatusBadRequest {
+			d.Record(id)
+		}
+	}
+}
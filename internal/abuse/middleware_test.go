@@ -0,0 +1,66 @@
+package abuse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/usage"
+)
+
+func TestGuardBlocksFarmingGuestsOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	d, _, _ := newTestDetector(ModeEnforce)
+
+	router := gin.New()
+	router.Use(middleware.Auth("dev"), d.Guard(func(c *gin.Context) bool {
+		return c.FullPath() == "/analyze"
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusAccepted) }
+	router.POST("/analyze", ok)
+	router.POST("/other", ok)
+
+	send := func(path, header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(header, value)
+		req.Header.Set(DeviceHeader, "fp-1")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	for i := 1; i <= 2; i++ {
+		if resp := send("/analyze", "X-Guest-Id", fmt.Sprintf("g%d", i)); resp.Code != http.StatusAccepted {
+			t.Fatalf("guest %d: status %d", i, resp.Code)
+		}
+	}
+	resp := send("/analyze", "X-Guest-Id", "g3")
+	if resp.Code != http.StatusTooManyRequests || resp.Header().Get(usage.RemainingHeader) != "0" {
+		t.Fatalf("expected 429 with no remaining usage, got %d %v", resp.Code, resp.Header())
+	}
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.Error.Code != "limit_reached" {
+		t.Fatalf("expected limit_reached, got %s (err %v)", resp.Body, err)
+	}
+
+	if resp := send("/other", "X-Guest-Id", "g4"); resp.Code != http.StatusAccepted {
+		t.Fatalf("unguarded route: status %d", resp.Code)
+	}
+	token, err := auth.SignJWT(auth.Claims{Sub: "user-1"})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	if resp := send("/analyze", "Authorization", "Bearer "+token); resp.Code != http.StatusAccepted {
+		t.Fatalf("signed-in user: status %d", resp.Code)
+	}
+}
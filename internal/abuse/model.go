@@ -0,0 +1,103 @@
+package abuse
+
+import "time"
+
+// Modes select what the detector does with a rule violation.
+const (
+	// ModeOff disables detection.
+	ModeOff = "off"
+	// ModeShadow opens review flags and counts would-be blocks, but lets the
+	// requests through. Only clusters an admin confirmed are blocked.
+	ModeShadow = "shadow"
+	// ModeEnforce also blocks requests from clusters over a threshold.
+	ModeEnforce = "enforce"
+)
+
+// Cluster kinds. Guests are grouped by the client IP and, when the client
+// sends one, the device fingerprint.
+const (
+	KindIP     = "ip"
+	KindDevice = "device"
+)
+
+// Rules a cluster can break.
+const (
+	// RuleGuests: too many guest identities from one cluster in the window.
+	RuleGuests = "guest_velocity"
+	// RuleRequests: the cluster's guests together made more LLM requests in
+	// the window than its shadow limit.
+	RuleRequests = "shadow_limit"
+)
+
+// Flag statuses.
+const (
+	StatusOpen      = "open"
+	StatusConfirmed = "confirmed"
+	StatusDismissed = "dismissed"
+)
+
+// Flag is a cluster waiting for, or given, an admin's review. A cluster has
+// at most one open flag, whose counts are refreshed while it stays over a
+// threshold.
+type Flag struct {
+	ID string `json:"id"`
+	// Key identifies the cluster by kind and a hash of the IP or fingerprint;
+	// the raw values are never stored.
+	Key          string     `json:"key"`
+	Kind         string     `json:"kind"`
+	Rule         string     `json:"rule"`
+	GuestIDs     []string   `json:"guestIds"`
+	GuestCount   int        `json:"guestCount"`
+	RequestCount int        `json:"requestCount"`
+	Status       string     `json:"status"`
+	ReviewedBy   string     `json:"reviewedBy,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// Thresholds configure the velocity rules. A zero threshold disables its rule.
+type Thresholds struct {
+	// Window is how far back guests and requests are counted.
+	Window time.Duration
+	// MaxGuestsPerDevice and MaxGuestsPerIP bound the guest identities seen
+	// from one cluster. IPs are often shared by offices, campuses and mobile
+	// carriers, so their limit should be far higher.
+	MaxGuestsPerDevice int
+	MaxGuestsPerIP     int
+	// MaxRequestsPerDevice and MaxRequestsPerIP are the shadow limits: how
+	// many LLM requests all of a cluster's guests may make together.
+	MaxRequestsPerDevice int
+	MaxRequestsPerIP     int
+	// ReviewTTL is how long an admin's decision applies to a cluster before
+	// its rules are checked again.
+	ReviewTTL time.Duration
+}
+
+// DefaultThresholds are the thresholds used when none are configured.
+var DefaultThresholds = Thresholds{
+	Window:               24 * time.Hour,
+	MaxGuestsPerDevice:   3,
+	MaxGuestsPerIP:       25,
+	MaxRequestsPerDevice: 10,
+	MaxRequestsPerIP:     60,
+	ReviewTTL:            30 * 24 * time.Hour,
+}
+
+// Identity is who made a request, as far as the detector can tell.
+type Identity struct {
+	GuestID string
+	IP      string
+	// Device is the client's device fingerprint; empty when not sent.
+	Device string
+}
+
+// Decision is the detector's verdict on one request.
+type Decision struct {
+	// Block rejects the request.
+	Block bool
+	// Shadowed marks a request that enforce mode would have blocked.
+	Shadowed bool
+	Rule     string
+	FlagID   string
+}
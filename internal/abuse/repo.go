@@ -0,0 +1,22 @@
+package abuse
+
+import (
+	"context"
+	"time"
+)
+
+// Repo persists review flags.
+type Repo interface {
+	// Raise opens a flag for the cluster, or refreshes the rule and counts of
+	// its open flag. It reports whether a new flag was opened.
+	Raise(ctx context.Context, flag Flag) (Flag, bool, error)
+	GetByID(ctx context.Context, id string) (Flag, error)
+	// Latest returns the cluster's newest flag, or ErrNotFound.
+	Latest(ctx context.Context, key string) (Flag, error)
+	// List returns flags newest first; an empty status lists every flag.
+	List(ctx context.Context, status string, limit int) ([]Flag, error)
+	// Review sets an open flag's status, or returns ErrAlreadyReviewed.
+	Review(ctx context.Context, id, status, reviewer string, at time.Time) (Flag, error)
+	// CountOpen returns how many flags wait for review.
+	CountOpen(ctx context.Context) (int, error)
+}
@@ -0,0 +1,134 @@
+package abuse
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepo stores flags in memory.
+type MemoryRepo struct {
+	mu    sync.RWMutex
+	flags []Flag
+}
+
+// NewMemoryRepo constructs an empty MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Raise opens a flag for the cluster or refreshes its open one.
+func (r *MemoryRepo) Raise(ctx context.Context, flag Flag) (Flag, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return Flag{}, false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.flags {
+		existing := &r.flags[i]
+		if existing.Key != flag.Key || existing.Status != StatusOpen {
+			continue
+		}
+		existing.Rule = flag.Rule
+		existing.GuestIDs = append([]string(nil), flag.GuestIDs...)
+		existing.GuestCount = flag.GuestCount
+		existing.RequestCount = flag.RequestCount
+		existing.UpdatedAt = flag.UpdatedAt
+		return *existing, false, nil
+	}
+	flag.GuestIDs = append([]string(nil), flag.GuestIDs...)
+	r.flags = append(r.flags, flag)
+	return flag, true, nil
+}
+
+// GetByID returns a flag.
+func (r *MemoryRepo) GetByID(ctx context.Context, id string) (Flag, error) {
+	if err := ctx.Err(); err != nil {
+		return Flag{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.flags {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return Flag{}, ErrNotFound
+}
+
+// Latest returns the cluster's newest flag.
+func (r *MemoryRepo) Latest(ctx context.Context, key string) (Flag, error) {
+	if err := ctx.Err(); err != nil {
+		return Flag{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := len(r.flags) - 1; i >= 0; i-- {
+		if r.flags[i].Key == key {
+			return r.flags[i], nil
+		}
+	}
+	return Flag{}, ErrNotFound
+}
+
+// List returns flags newest first.
+func (r *MemoryRepo) List(ctx context.Context, status string, limit int) ([]Flag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Flag, 0)
+	for i := len(r.flags) - 1; i >= 0; i-- {
+		if status != "" && r.flags[i].Status != status {
+			continue
+		}
+		out = append(out, r.flags[i])
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Review sets an open flag's status.
+func (r *MemoryRepo) Review(ctx context.Context, id, status, reviewer string, at time.Time) (Flag, error) {
+	if err := ctx.Err(); err != nil {
+		return Flag{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.flags {
+		if r.flags[i].ID != id {
+			continue
+		}
+		if r.flags[i].Status != StatusOpen {
+			return r.flags[i], ErrAlreadyReviewed
+		}
+		reviewedAt := at
+		r.flags[i].Status = status
+		r.flags[i].ReviewedBy = reviewer
+		r.flags[i].ReviewedAt = &reviewedAt
+		r.flags[i].UpdatedAt = at
+		return r.flags[i], nil
+	}
+	return Flag{}, ErrNotFound
+}
+
+// CountOpen returns how many flags wait for review.
+func (r *MemoryRepo) CountOpen(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := 0
+	for _, f := range r.flags {
+		if f.Status == StatusOpen {
+			n++
+		}
+	}
+	return n, nil
+}
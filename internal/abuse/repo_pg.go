@@ -0,0 +1,144 @@
+package abuse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const flagColumns = `id, cluster_key, kind, rule, guest_ids, guest_count, request_count, status, reviewed_by, reviewed_at, created_at, updated_at`
+
+// Raise opens a flag for the cluster or refreshes its open one. The partial
+// unique index on open flags keeps concurrent instances on one flag.
+func (r *PGRepo) Raise(ctx context.Context, flag Flag) (Flag, bool, error) {
+	const query = `
+INSERT INTO abuse_flags (` + flagColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 'open', '', NULL, $8, $8)
+ON CONFLICT (cluster_key) WHERE status = 'open' DO UPDATE SET
+    rule = EXCLUDED.rule,
+    guest_ids = EXCLUDED.guest_ids,
+    guest_count = EXCLUDED.guest_count,
+    request_count = EXCLUDED.request_count,
+    updated_at = EXCLUDED.updated_at
+RETURNING ` + flagColumns
+	guestIDs, err := json.Marshal(flag.GuestIDs)
+	if err != nil {
+		return Flag{}, false, err
+	}
+	stored, err := scanFlag(r.DB.QueryRowContext(ctx, query,
+		flag.ID,
+		flag.Key,
+		flag.Kind,
+		flag.Rule,
+		guestIDs,
+		flag.GuestCount,
+		flag.RequestCount,
+		flag.CreatedAt,
+	))
+	if err != nil {
+		return Flag{}, false, err
+	}
+	return stored, stored.ID == flag.ID, nil
+}
+
+// GetByID returns a flag.
+func (r *PGRepo) GetByID(ctx context.Context, id string) (Flag, error) {
+	const query = `SELECT ` + flagColumns + ` FROM abuse_flags WHERE id = $1`
+	f, err := scanFlag(r.DB.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Flag{}, ErrNotFound
+	}
+	return f, err
+}
+
+// Latest returns the cluster's newest flag.
+func (r *PGRepo) Latest(ctx context.Context, key string) (Flag, error) {
+	const query = `SELECT ` + flagColumns + ` FROM abuse_flags WHERE cluster_key = $1 ORDER BY created_at DESC LIMIT 1`
+	f, err := scanFlag(r.DB.QueryRowContext(ctx, query, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Flag{}, ErrNotFound
+	}
+	return f, err
+}
+
+// List returns flags newest first.
+func (r *PGRepo) List(ctx context.Context, status string, limit int) ([]Flag, error) {
+	const query = `
+SELECT ` + flagColumns + `
+FROM abuse_flags
+WHERE $1 = '' OR status = $1
+ORDER BY created_at DESC
+LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Flag
+	for rows.Next() {
+		f, err := scanFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// Review sets an open flag's status.
+func (r *PGRepo) Review(ctx context.Context, id, status, reviewer string, at time.Time) (Flag, error) {
+	const query = `
+UPDATE abuse_flags
+SET status = $2, reviewed_by = $3, reviewed_at = $4, updated_at = $4
+WHERE id = $1 AND status = 'open'
+RETURNING ` + flagColumns
+	f, err := scanFlag(r.DB.QueryRowContext(ctx, query, id, status, reviewer, at))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return f, err
+	}
+	existing, err := r.GetByID(ctx, id)
+	if err != nil {
+		return Flag{}, err
+	}
+	return existing, ErrAlreadyReviewed
+}
+
+// CountOpen returns how many flags wait for review.
+func (r *PGRepo) CountOpen(ctx context.Context) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM abuse_flags WHERE status = 'open'`).Scan(&n)
+	return n, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFlag(row rowScanner) (Flag, error) {
+	var (
+		f          Flag
+		guestIDs   []byte
+		reviewedAt sql.NullTime
+	)
+	if err := row.Scan(&f.ID, &f.Key, &f.Kind, &f.Rule, &guestIDs, &f.GuestCount, &f.RequestCount, &f.Status, &f.ReviewedBy, &reviewedAt, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return Flag{}, err
+	}
+	if len(guestIDs) > 0 {
+		if err := json.Unmarshal(guestIDs, &f.GuestIDs); err != nil {
+			return Flag{}, err
+		}
+	}
+	if reviewedAt.Valid {
+		f.ReviewedAt = &reviewedAt.Time
+	}
+	return f, nil
+}
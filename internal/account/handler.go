@@ -7,12 +7,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"resume-backend/internal/events"
 	"resume-backend/internal/shared/server/middleware"
 	"resume-backend/internal/shared/server/respond"
 )
 
 type Handler struct {
 	Svc *Service
+	// Events receives funnel analytics; nil disables them.
+	Events *events.Emitter
 }
 
 func NewHandler(svc *Service) *Handler {
@@ -61,5 +64,10 @@ func (h *Handler) claimGuest(c *gin.Context) {
 		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to claim guest data", nil)
 		return
 	}
+	h.Events.Track(c.Request.Context(), events.GuestClaimed, authedUserID, map[string]any{
+		"previous_distinct_id": h.Events.DistinctID(guestUserID),
+		"migrated_documents":   result.MigratedDocuments,
+		"migrated_analyses":    result.MigratedAnalyses,
+	})
 	respond.JSON(c, http.StatusOK, result)
 }
@@ -2,17 +2,20 @@ package account
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"strings"
 
 	"resume-backend/internal/analyses"
 	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/db"
 )
 
 type Service struct {
 	DocRepo      documents.DocumentsRepo
 	AnalysisRepo analyses.Repo
+	// UnitOfWork moves documents and analyses together; nil moves them one
+	// after the other.
+	UnitOfWork db.UnitOfWork
 }
 
 type ClaimResult struct {
@@ -29,46 +32,19 @@ func (s *Service) ClaimGuest(ctx context.Context, guestUserID, authedUserID stri
 		return ClaimResult{}, errors.New("guestUserID and authedUserID are required")
 	}
 
-	if docPG, ok := s.DocRepo.(*documents.PGRepo); ok && docPG != nil && docPG.DB != nil {
-		if analysisPG, ok := s.AnalysisRepo.(*analyses.PGRepo); ok && analysisPG != nil && analysisPG.DB != nil {
-			return claimWithTx(ctx, docPG.DB, guestUserID, authedUserID)
+	var result ClaimResult
+	err := db.Run(ctx, s.UnitOfWork, func(ctx context.Context) error {
+		var err error
+		if result.MigratedDocuments, err = claimDocs(ctx, s.DocRepo, guestUserID, authedUserID); err != nil {
+			return err
 		}
-	}
-
-	docCount, err := claimDocs(ctx, s.DocRepo, guestUserID, authedUserID)
-	if err != nil {
-		return ClaimResult{}, err
-	}
-	analysisCount, err := claimAnalyses(ctx, s.AnalysisRepo, guestUserID, authedUserID)
+		result.MigratedAnalyses, err = claimAnalyses(ctx, s.AnalysisRepo, guestUserID, authedUserID)
+		return err
+	})
 	if err != nil {
 		return ClaimResult{}, err
 	}
-	return ClaimResult{MigratedDocuments: docCount, MigratedAnalyses: analysisCount}, nil
-}
-
-func claimWithTx(ctx context.Context, db *sql.DB, guestUserID, authedUserID string) (ClaimResult, error) {
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return ClaimResult{}, err
-	}
-	defer tx.Rollback()
-
-	docRes, err := tx.ExecContext(ctx, `UPDATE documents SET user_id = $1 WHERE user_id = $2 AND deleted_at IS NULL`, authedUserID, guestUserID)
-	if err != nil {
-		return ClaimResult{}, err
-	}
-	docCount, _ := docRes.RowsAffected()
-
-	analysisRes, err := tx.ExecContext(ctx, `UPDATE analyses SET user_id = $1 WHERE user_id = $2 AND deleted_at IS NULL`, authedUserID, guestUserID)
-	if err != nil {
-		return ClaimResult{}, err
-	}
-	analysisCount, _ := analysisRes.RowsAffected()
-
-	if err := tx.Commit(); err != nil {
-		return ClaimResult{}, err
-	}
-	return ClaimResult{MigratedDocuments: int(docCount), MigratedAnalyses: int(analysisCount)}, nil
+	return result, nil
 }
 
 type guestDocClaimer interface {
@@ -0,0 +1,43 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/db"
+)
+
+// failingClaimRepo fails to move analyses after the documents have moved.
+type failingClaimRepo struct {
+	*analyses.MemoryRepo
+}
+
+func (failingClaimRepo) ClaimGuest(ctx context.Context, guestUserID, authedUserID string) (int, error) {
+	return 0, errors.New("analyses unavailable")
+}
+
+func TestClaimGuestRollsBackDocumentsWhenAnalysesFail(t *testing.T) {
+	ctx := context.Background()
+	docRepo := documents.NewMemoryRepo()
+	guestUserID := "guest:11111111-1111-1111-1111-111111111111"
+	if err := docRepo.Create(ctx, documents.Document{ID: "doc-1", UserID: guestUserID, FileName: "resume.pdf", CreatedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("create document: %v", err)
+	}
+
+	svc := NewService(docRepo, failingClaimRepo{analyses.NewMemoryRepo()})
+	svc.UnitOfWork = &db.MemoryUnitOfWork{}
+	if _, err := svc.ClaimGuest(ctx, guestUserID, "user-1"); err == nil {
+		t.Fatal("expected the claim to fail")
+	}
+
+	if _, err := docRepo.GetByID(ctx, guestUserID, "doc-1"); err != nil {
+		t.Fatalf("expected the document to stay with the guest, got %v", err)
+	}
+	if _, err := docRepo.GetByID(ctx, "user-1", "doc-1"); err == nil {
+		t.Fatal("expected the document not to move to the user")
+	}
+}
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// StatsFunc produces one section of the admin stats response.
+type StatsFunc func(ctx context.Context) (any, error)
+
+type statsSection struct {
+	name string
+	fn   StatsFunc
+}
+
+// Handler serves operator endpoints under /admin, restricted to configured admins.
+type Handler struct {
+	AdminUserIDs []string
+	sections     []statsSection
+	routes       []func(rg *gin.RouterGroup)
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(adminUserIDs []string) *Handler {
+	return &Handler{AdminUserIDs: adminUserIDs}
+}
+
+// AddStats registers a named section of GET /admin/stats.
+func (h *Handler) AddStats(name string, fn StatsFunc) {
+	h.sections = append(h.sections, statsSection{name: name, fn: fn})
+}
+
+// AddRoutes registers feature routes to mount under /admin behind the admin check.
+func (h *Handler) AddRoutes(register func(rg *gin.RouterGroup)) {
+	h.routes = append(h.routes, register)
+}
+
+// RegisterRoutes attaches admin routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	admin := rg.Group("/admin", middleware.RequireAdmin(h.AdminUserIDs))
+	admin.GET("/stats", h.stats)
+	for _, register := range h.routes {
+		register(admin)
+	}
+}
+
+// stats reports every registered section; a failing section is reported inline
+// rather than failing the whole response.
+func (h *Handler) stats(c *gin.Context) {
+	resp := gin.H{"generatedAt": time.Now().UTC()}
+	for _, section := range h.sections {
+		value, err := section.fn(c.Request.Context())
+		if err != nil {
+			telemetry.Error("admin.stats_section_failed", map[string]any{
+				"section": section.name,
+				"error":   err.Error(),
+			})
+			resp[section.name] = gin.H{"error": "unavailable"}
+			continue
+		}
+		resp[section.name] = value
+	}
+	respond.JSON(c, http.StatusOK, resp)
+}
@@ -0,0 +1,336 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Annotation targets: the kind of result item an annotation points at.
+const (
+	AnnotationTargetIssue         = "issue"
+	AnnotationTargetBulletRewrite = "bulletRewrite"
+	AnnotationTargetKeyword       = "keyword"
+)
+
+// Annotation kinds.
+const (
+	// AnnotationNotApplicable marks an item that does not apply to the user,
+	// such as an issue about a section they deliberately left out.
+	AnnotationNotApplicable = "not_applicable"
+	// AnnotationFalsePositive marks an item the analysis got wrong, such as a
+	// keyword reported missing that the resume does contain.
+	AnnotationFalsePositive = "false_positive"
+)
+
+const (
+	// maxAnnotationNote caps the free-text note on an annotation, in characters.
+	maxAnnotationNote = 500
+	// annotationStatsWindow is how far back the admin stats count annotations.
+	annotationStatsWindow = 30 * 24 * time.Hour
+)
+
+var (
+	// ErrAnnotationInvalid is returned for an annotation that does not point at
+	// an item of the analysis result.
+	ErrAnnotationInvalid = errors.New("invalid annotation")
+	// ErrAnalysisNotCompleted is returned when annotating an analysis without a result.
+	ErrAnalysisNotCompleted = errors.New("analysis not completed")
+	// ErrAnnotationsUnsupported is returned when the repo cannot store annotations.
+	ErrAnnotationsUnsupported = errors.New("annotations not supported")
+)
+
+// Annotation is a user's correction to one item of an analysis result. Issues
+// and bullet rewrites are addressed by their position in the result, keywords
+// by their text. A later annotation of the same item replaces the earlier one.
+type Annotation struct {
+	ID         string `json:"id"`
+	AnalysisID string `json:"analysisId"`
+	Target     string `json:"target"`
+	Index      *int   `json:"index,omitempty"`
+	Keyword    string `json:"keyword,omitempty"`
+	Kind       string `json:"kind"`
+	Note       string `json:"note,omitempty"`
+	// PromptVersion is the analysis's prompt version, kept for quality reporting.
+	PromptVersion string    `json:"-"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// ref identifies the annotated item within its target.
+func (a Annotation) ref() string {
+	if a.Target == AnnotationTargetKeyword {
+		return strings.ToLower(strings.TrimSpace(a.Keyword))
+	}
+	if a.Index == nil {
+		return ""
+	}
+	return strconv.Itoa(*a.Index)
+}
+
+// AnnotationCount is how many annotations of one kind and target the analyses
+// of one prompt version received.
+type AnnotationCount struct {
+	PromptVersion string `json:"promptVersion"`
+	Target        string `json:"target"`
+	Kind          string `json:"kind"`
+	Count         int    `json:"count"`
+}
+
+// annotationStore is implemented by repos that persist annotations.
+type annotationStore interface {
+	// UpsertAnnotation stores a, replacing any annotation of the same item, and
+	// returns the stored annotation.
+	UpsertAnnotation(ctx context.Context, a Annotation) (Annotation, error)
+	// ListAnnotations returns an analysis's annotations, oldest first.
+	ListAnnotations(ctx context.Context, analysisID string) ([]Annotation, error)
+	// AnnotationCounts counts annotations created since the given time.
+	AnnotationCounts(ctx context.Context, since time.Time) ([]AnnotationCount, error)
+}
+
+var (
+	_ annotationStore = (*MemoryRepo)(nil)
+	_ annotationStore = (*PGRepo)(nil)
+)
+
+// Annotate records a user's annotation of a completed analysis they own. The
+// stored result is left as the model produced it; annotations are merged in
+// when the result is rendered.
+func (s *Service) Annotate(ctx context.Context, userID, analysisID string, a Annotation) (Annotation, error) {
+	store, ok := s.Repo.(annotationStore)
+	if !ok {
+		return Annotation{}, ErrAnnotationsUnsupported
+	}
+	analysis, err := s.Get(ctx, analysisID)
+	if err != nil {
+		return Annotation{}, err
+	}
+	if analysis.UserID != userID {
+		return Annotation{}, ErrNotFound
+	}
+	if analysis.Status != StatusCompleted || analysis.Result == nil {
+		return Annotation{}, ErrAnalysisNotCompleted
+	}
+	if err := validateAnnotation(analysis.Result, &a); err != nil {
+		return Annotation{}, err
+	}
+	a.ID = uuid.NewString()
+	a.AnalysisID = analysis.ID
+	a.PromptVersion = analysis.PromptVersion
+	a.CreatedAt = time.Now().UTC()
+	stored, err := store.UpsertAnnotation(ctx, a)
+	if err != nil {
+		return Annotation{}, err
+	}
+	telemetry.InfoContext(ctx, "analysis.annotated", map[string]any{
+		"analysis_id":    analysis.ID,
+		"prompt_version": analysis.PromptVersion,
+		"target":         a.Target,
+		"kind":           a.Kind,
+	})
+	return stored, nil
+}
+
+// AnnotatedResult returns the analysis result with the user's annotations
+// merged in, or the stored result when there are none.
+func (s *Service) AnnotatedResult(ctx context.Context, analysis Analysis) map[string]any {
+	return AnnotatedResult(ctx, s.Repo, analysis)
+}
+
+// AnnotatedResult returns analysis.Result with the annotations stored in repo
+// merged in. Annotated issues and bullet rewrites get a userAnnotation field;
+// annotated keywords are dropped from the missing keyword lists. All
+// annotations are also listed under annotations. Scores are left unchanged.
+// Lookup failures are logged and leave the result as stored.
+func AnnotatedResult(ctx context.Context, repo Repo, analysis Analysis) map[string]any {
+	store, ok := repo.(annotationStore)
+	if !ok || analysis.Result == nil {
+		return analysis.Result
+	}
+	annotations, err := store.ListAnnotations(ctx, analysis.ID)
+	if err != nil {
+		telemetry.ErrorContext(ctx, "analysis.annotations_failed", map[string]any{
+			"analysis_id": analysis.ID,
+			"error":       err.Error(),
+		})
+		return analysis.Result
+	}
+	if len(annotations) == 0 {
+		return analysis.Result
+	}
+	result, err := copyResult(analysis.Result)
+	if err != nil {
+		return analysis.Result
+	}
+	mergeAnnotations(result, annotations)
+	return result
+}
+
+// AnnotationStats reports annotation counts by prompt version over the last
+// 30 days for the admin stats endpoint, so prompt changes can be judged by how
+// often users correct their output.
+func (s *Service) AnnotationStats(ctx context.Context) (any, error) {
+	store, ok := s.Repo.(annotationStore)
+	if !ok {
+		return map[string]any{"enabled": false}, nil
+	}
+	counts, err := store.AnnotationCounts(ctx, time.Now().UTC().Add(-annotationStatsWindow))
+	if err != nil {
+		return nil, err
+	}
+	if counts == nil {
+		counts = []AnnotationCount{}
+	}
+	return map[string]any{"windowDays": int(annotationStatsWindow.Hours() / 24), "counts": counts}, nil
+}
+
+// validateAnnotation checks that a points at an item of result and normalizes
+// its fields.
+func validateAnnotation(result map[string]any, a *Annotation) error {
+	a.Kind = strings.TrimSpace(a.Kind)
+	if a.Kind != AnnotationNotApplicable && a.Kind != AnnotationFalsePositive {
+		return fmt.Errorf("%w: kind must be %s or %s", ErrAnnotationInvalid, AnnotationNotApplicable, AnnotationFalsePositive)
+	}
+	a.Note = strings.TrimSpace(a.Note)
+	if utf8.RuneCountInString(a.Note) > maxAnnotationNote {
+		return fmt.Errorf("%w: note must be at most %d characters", ErrAnnotationInvalid, maxAnnotationNote)
+	}
+	switch a.Target {
+	case AnnotationTargetIssue, AnnotationTargetBulletRewrite:
+		a.Keyword = ""
+		items, _ := result[annotationItemsKey(a.Target)].([]any)
+		if a.Index == nil || *a.Index < 0 || *a.Index >= len(items) {
+			return fmt.Errorf("%w: index must point at one of the %d %s items", ErrAnnotationInvalid, len(items), a.Target)
+		}
+	case AnnotationTargetKeyword:
+		a.Index = nil
+		keyword := strings.TrimSpace(a.Keyword)
+		found := ""
+		for _, missing := range missingKeywords(result) {
+			if strings.EqualFold(missing, keyword) {
+				found = missing
+				break
+			}
+		}
+		if keyword == "" || found == "" {
+			return fmt.Errorf("%w: keyword is not among the missing keywords", ErrAnnotationInvalid)
+		}
+		a.Keyword = found
+	default:
+		return fmt.Errorf("%w: target must be %s, %s or %s", ErrAnnotationInvalid, AnnotationTargetIssue, AnnotationTargetBulletRewrite, AnnotationTargetKeyword)
+	}
+	return nil
+}
+
+func annotationItemsKey(target string) string {
+	if target == AnnotationTargetBulletRewrite {
+		return "bulletRewrites"
+	}
+	return "issues"
+}
+
+// missingKeywords lists the keywords reported missing, in both the v1 list
+// form and the grouped form of later schemas.
+func missingKeywords(result map[string]any) []string {
+	ats, _ := result["ats"].(map[string]any)
+	var out []string
+	collect := func(items any) {
+		list, _ := items.([]any)
+		for _, item := range list {
+			if text, ok := item.(string); ok {
+				out = append(out, text)
+			}
+		}
+	}
+	switch missing := ats["missingKeywords"].(type) {
+	case []any:
+		collect(missing)
+	case map[string]any:
+		collect(missing["fromJobDescription"])
+		collect(missing["industryCommon"])
+	}
+	return out
+}
+
+func mergeAnnotations(result map[string]any, annotations []Annotation) {
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].CreatedAt.Before(annotations[j].CreatedAt)
+	})
+	dismissed := map[string]bool{}
+	listed := make([]any, 0, len(annotations))
+	for _, a := range annotations {
+		listed = append(listed, map[string]any{
+			"id":        a.ID,
+			"target":    a.Target,
+			"index":     a.Index,
+			"keyword":   a.Keyword,
+			"kind":      a.Kind,
+			"note":      a.Note,
+			"createdAt": a.CreatedAt,
+		})
+		if a.Target == AnnotationTargetKeyword {
+			dismissed[a.ref()] = true
+			continue
+		}
+		items, _ := result[annotationItemsKey(a.Target)].([]any)
+		if a.Index == nil || *a.Index >= len(items) {
+			continue
+		}
+		if item, ok := items[*a.Index].(map[string]any); ok {
+			item["userAnnotation"] = map[string]any{"kind": a.Kind, "note": a.Note}
+		}
+	}
+	result["annotations"] = listed
+
+	if len(dismissed) == 0 {
+		return
+	}
+	keep := func(items any) any {
+		list, ok := items.([]any)
+		if !ok {
+			return items
+		}
+		out := make([]any, 0, len(list))
+		for _, item := range list {
+			if text, ok := item.(string); ok && dismissed[strings.ToLower(strings.TrimSpace(text))] {
+				continue
+			}
+			out = append(out, item)
+		}
+		return out
+	}
+	ats, _ := result["ats"].(map[string]any)
+	switch missing := ats["missingKeywords"].(type) {
+	case []any:
+		ats["missingKeywords"] = keep(missing)
+	case map[string]any:
+		for _, group := range []string{"fromJobDescription", "industryCommon"} {
+			if _, ok := missing[group]; ok {
+				missing[group] = keep(missing[group])
+			}
+		}
+	}
+}
+
+// copyResult deep-copies a stored result so merging annotations does not
+// change the repo's copy.
+func copyResult(result map[string]any) (map[string]any, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
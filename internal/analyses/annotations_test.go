@@ -0,0 +1,155 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func annotatedFixture() map[string]any {
+	return map[string]any{
+		"ats": map[string]any{
+			"score": 70.0,
+			"missingKeywords": map[string]any{
+				"fromJobDescription": []any{"Kafka", "Terraform"},
+				"industryCommon":     []any{"CI/CD"},
+			},
+		},
+		"issues": []any{
+			map[string]any{"section": "Experience", "problem": "No metrics"},
+			map[string]any{"section": "Summary", "problem": "Missing summary"},
+		},
+		"bulletRewrites": []any{
+			map[string]any{"before": "Did work", "after": "Shipped work"},
+		},
+	}
+}
+
+func TestAnnotationsAreMergedIntoTheRenderedResult(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, analysisRepo, _, _ := setupAnalysisRouter(t)
+	ctx := context.Background()
+	analysis := Analysis{
+		ID:            "analysis-annotated",
+		DocumentID:    "doc-1",
+		UserID:        "guest:test-guest",
+		PromptVersion: "v2_3",
+		Status:        StatusCompleted,
+		Result:        annotatedFixture(),
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := analysisRepo.Create(ctx, analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	annotate := func(body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/analyses/"+analysis.ID+"/annotations", bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := annotate(map[string]any{"target": "issue", "index": 1, "kind": "not_applicable", "note": "I left the summary out on purpose"}); resp.Code != http.StatusCreated {
+		t.Fatalf("annotate issue: status %d body %s", resp.Code, resp.Body)
+	}
+	if resp := annotate(map[string]any{"target": "keyword", "keyword": "kafka", "kind": "false_positive"}); resp.Code != http.StatusCreated {
+		t.Fatalf("annotate keyword: status %d body %s", resp.Code, resp.Body)
+	}
+	// Re-annotating an item replaces the earlier annotation.
+	if resp := annotate(map[string]any{"target": "issue", "index": 1, "kind": "false_positive"}); resp.Code != http.StatusCreated {
+		t.Fatalf("re-annotate issue: status %d body %s", resp.Code, resp.Body)
+	}
+	for _, bad := range []map[string]any{
+		{"target": "issue", "index": 2, "kind": "not_applicable"},
+		{"target": "keyword", "keyword": "Rust", "kind": "false_positive"},
+		{"target": "bulletRewrite", "index": 0, "kind": "wrong"},
+		{"target": "summary", "kind": "not_applicable"},
+	} {
+		if resp := annotate(bad); resp.Code != http.StatusBadRequest {
+			t.Fatalf("annotation %v: expected 400, got %d", bad, resp.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analyses/"+analysis.ID, nil)
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("get analysis: status %d", resp.Code)
+	}
+	var got struct {
+		Result struct {
+			ATS struct {
+				MissingKeywords struct {
+					FromJobDescription []string `json:"fromJobDescription"`
+					IndustryCommon     []string `json:"industryCommon"`
+				} `json:"missingKeywords"`
+			} `json:"ats"`
+			Issues      []map[string]any `json:"issues"`
+			Annotations []map[string]any `json:"annotations"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if kw := got.Result.ATS.MissingKeywords; len(kw.FromJobDescription) != 1 || kw.FromJobDescription[0] != "Terraform" || len(kw.IndustryCommon) != 1 {
+		t.Fatalf("missing keywords = %+v", kw)
+	}
+	ann, _ := got.Result.Issues[1]["userAnnotation"].(map[string]any)
+	if ann["kind"] != AnnotationFalsePositive {
+		t.Fatalf("issue annotation = %v", got.Result.Issues[1])
+	}
+	if _, ok := got.Result.Issues[0]["userAnnotation"]; ok {
+		t.Fatal("unannotated issue should not carry an annotation")
+	}
+	if len(got.Result.Annotations) != 2 {
+		t.Fatalf("annotations = %v", got.Result.Annotations)
+	}
+
+	stored, _ := analysisRepo.GetByID(ctx, analysis.ID)
+	if _, ok := stored.Result["annotations"]; ok {
+		t.Fatal("the stored result should not be rewritten")
+	}
+
+	svc := &Service{Repo: analysisRepo}
+	stats, err := svc.AnnotationStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	counts, _ := stats.(map[string]any)["counts"].([]AnnotationCount)
+	if len(counts) != 2 || counts[0] != (AnnotationCount{PromptVersion: "v2_3", Target: "issue", Kind: AnnotationFalsePositive, Count: 1}) {
+		t.Fatalf("counts = %+v", counts)
+	}
+}
+
+func TestAnnotateRejectsOtherUsersAndUnfinishedAnalyses(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	svc := &Service{Repo: repo}
+	for _, a := range []Analysis{
+		{ID: "done", UserID: "user-1", Status: StatusCompleted, Result: annotatedFixture()},
+		{ID: "queued", UserID: "user-1", Status: StatusQueued},
+	} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	index := 0
+	annotation := Annotation{Target: AnnotationTargetIssue, Index: &index, Kind: AnnotationNotApplicable}
+	if _, err := svc.Annotate(ctx, "user-2", "done", annotation); err != ErrNotFound {
+		t.Fatalf("other user: err = %v", err)
+	}
+	if _, err := svc.Annotate(ctx, "user-1", "queued", annotation); err != ErrAnalysisNotCompleted {
+		t.Fatalf("queued analysis: err = %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package analyses
+
+import (
+	"context"
+	"strings"
+
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/internal/usage"
+)
+
+// markAppliedRewrites flags the result's bullet rewrites that an earlier apply
+// run already wrote into the document. Re-analyzing the original upload tends
+// to suggest the same rewrites again, and analyzing the applied version tends
+// to suggest rewriting the rewrite. Both are marked with alreadyApplied and
+// left in place, so clients can hide or badge them.
+func (s *Service) markAppliedRewrites(ctx context.Context, result map[string]any, analysis Analysis) {
+	if s.Usage == nil || result == nil {
+		return
+	}
+	applied, err := s.Usage.ListAppliedRewrites(ctx, analysis.UserID, analysis.DocumentID)
+	if err != nil {
+		telemetry.ErrorContext(ctx, "analysis.applied_rewrites_failed", map[string]any{
+			"analysis_id": analysis.ID,
+			"document_id": analysis.DocumentID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	if marked := markRewritesApplied(result, applied); marked > 0 {
+		telemetry.InfoContext(ctx, "analysis.applied_rewrites_marked", map[string]any{
+			"analysis_id": analysis.ID,
+			"document_id": analysis.DocumentID,
+			"marked":      marked,
+		})
+	}
+}
+
+// markRewritesApplied sets alreadyApplied on each bullet rewrite whose before
+// text was rewritten by an applied rewrite or is the text one produced. It
+// returns how many were marked.
+func markRewritesApplied(result map[string]any, applied []usage.AppliedRewrite) int {
+	if len(applied) == 0 {
+		return 0
+	}
+	rewritten := make(map[string]bool, len(applied))
+	produced := make(map[string]bool, len(applied))
+	for _, a := range applied {
+		rewritten[rewriteKey(a.Before)] = true
+		produced[rewriteKey(a.After)] = true
+	}
+	items, _ := result["bulletRewrites"].([]any)
+	marked := 0
+	for _, item := range items {
+		rewrite, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		before, _ := rewrite["before"].(string)
+		key := rewriteKey(before)
+		if key == "" || (!rewritten[key] && !produced[key]) {
+			continue
+		}
+		rewrite["alreadyApplied"] = true
+		marked++
+	}
+	return marked
+}
+
+// rewriteKey compares bullets ignoring case, spacing, bullet markers and a
+// closing full stop.
+func rewriteKey(text string) string {
+	text = strings.TrimSpace(text)
+	if stripped, ok := stripBulletMarker(text); ok {
+		text = stripped
+	}
+	return strings.TrimSuffix(normalizeLine(text), ".")
+}
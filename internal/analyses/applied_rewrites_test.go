@@ -0,0 +1,61 @@
+package analyses
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"resume-backend/internal/usage"
+)
+
+func TestProcessAnalysisMarksAppliedRewrites(t *testing.T) {
+	_, analysisRepo, svc := setupAnalysisRouterWithLLM(t, loadFixture(t, "testdata/v2_3_good.json"))
+	svc.Usage = usage.NewService()
+
+	ctx := context.Background()
+	userID, documentID := "guest:test-guest", "doc-guest:test-guest"
+	if err := svc.Usage.RecordAppliedRewrites(ctx, []usage.AppliedRewrite{{
+		ID: "r1", DocumentID: documentID, UserID: userID, ApplyRunID: "run-1",
+		Section: "experience", Before: "• improved  sales", After: "Grew sales 20% in 2023.", CreatedAt: time.Now().UTC(),
+	}}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := analysisRepo.Create(ctx, Analysis{
+		ID: "a1", DocumentID: documentID, UserID: userID, PromptVersion: "v2_3", Mode: ModeATS,
+		Status: StatusQueued, CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, "a1"); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	got, _ := analysisRepo.GetByID(ctx, "a1")
+	rewrites, _ := got.Result["bulletRewrites"].([]any)
+	if len(rewrites) == 0 {
+		t.Fatalf("expected bullet rewrites, got %v", got.Result["bulletRewrites"])
+	}
+	first := rewrites[0].(map[string]any)
+	if first["before"] != "Improved sales." || first["alreadyApplied"] != true {
+		t.Fatalf("expected the applied rewrite marked, got %v", first)
+	}
+}
+
+func TestMarkRewritesApplied(t *testing.T) {
+	result := map[string]any{"bulletRewrites": []any{
+		map[string]any{"before": "Managed a team", "after": "Managed a team of 8 engineers"},
+		map[string]any{"before": "Managed a team of 8 engineers.", "after": "Led a team of 8 engineers"},
+		map[string]any{"before": "Wrote documentation", "after": "Wrote onboarding docs"},
+	}}
+	applied := []usage.AppliedRewrite{{Before: "managed a team", After: "Managed a team of 8 engineers"}}
+
+	if marked := markRewritesApplied(result, applied); marked != 2 {
+		t.Fatalf("expected 2 marked, got %d", marked)
+	}
+	items := result["bulletRewrites"].([]any)
+	for i, want := range []bool{true, true, false} {
+		if _, got := items[i].(map[string]any)["alreadyApplied"]; got != want {
+			t.Fatalf("rewrite %d: expected alreadyApplied=%v, got %v", i, want, items[i])
+		}
+	}
+}
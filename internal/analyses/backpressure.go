@@ -0,0 +1,207 @@
+package analyses
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// completionLatencySource is implemented by repos that can report how long recent
+// analyses took from creation to completion.
+type completionLatencySource interface {
+	// CompletionLatencyP90 returns the 90th percentile latency of analyses completed
+	// since the given time, and how many there were.
+	CompletionLatencyP90(ctx context.Context, since time.Time) (time.Duration, int, error)
+}
+
+// BackpressurePolicy turns load signals into client advice.
+type BackpressurePolicy struct {
+	// BasePollMs is the poll interval under normal load.
+	BasePollMs int
+	// MaxPollMs caps the poll interval however loaded the pipeline is.
+	MaxPollMs int
+	// TargetLatency is the completion latency the base interval is tuned for; the
+	// interval grows in proportion beyond it.
+	TargetLatency time.Duration
+	// TargetDepth is the queue depth the base interval is tuned for.
+	TargetDepth int
+	// OverloadLatency and OverloadDepth mark the pipeline as overloaded; either one
+	// reached is enough.
+	OverloadLatency time.Duration
+	OverloadDepth   int
+	// LatencyWindow is how far back completions are sampled.
+	LatencyWindow time.Duration
+	// MinLatencySamples ignores the latency signal until enough analyses completed.
+	MinLatencySamples int
+	// RefreshInterval is how long a reading is reused before the sources are asked again.
+	RefreshInterval time.Duration
+}
+
+// DefaultBackpressurePolicy returns the policy used for zero-valued fields.
+func DefaultBackpressurePolicy() BackpressurePolicy {
+	return BackpressurePolicy{
+		BasePollMs:        defaultPollAfterMs,
+		MaxPollMs:         30000,
+		TargetLatency:     30 * time.Second,
+		TargetDepth:       50,
+		OverloadLatency:   5 * time.Minute,
+		OverloadDepth:     1000,
+		LatencyWindow:     10 * time.Minute,
+		MinLatencySamples: 5,
+		RefreshInterval:   15 * time.Second,
+	}
+}
+
+// LoadReading is the latest backpressure signal.
+type LoadReading struct {
+	QueueDepth          int       `json:"queueDepth"`
+	QueueDepthKnown     bool      `json:"queueDepthKnown"`
+	CompletionLatencyMs int64     `json:"completionLatencyMs"`
+	LatencySamples      int       `json:"latencySamples"`
+	PollAfterMs         int       `json:"pollAfterMs"`
+	Overloaded          bool      `json:"overloaded"`
+	RetryAfterSeconds   int       `json:"retryAfterSeconds,omitempty"`
+	ObservedAt          time.Time `json:"observedAt"`
+}
+
+// Backpressure watches queue depth and completion latency so clients poll less
+// often, and guests can be turned away, while the pipeline is saturated.
+type Backpressure struct {
+	Queue   queue.DepthReporter
+	Latency completionLatencySource
+	Policy  BackpressurePolicy
+	// ShedGuests rejects new guest analyses with 503 while overloaded.
+	ShedGuests bool
+	Now        func() time.Time
+
+	mu      sync.Mutex
+	reading LoadReading
+	fetched bool
+}
+
+// NewBackpressure builds a Backpressure from whichever signals are available. The
+// queue contributes depth when it can report it; the repo contributes latency when
+// it implements the lookup.
+func NewBackpressure(q queue.Client, repo Repo) *Backpressure {
+	b := &Backpressure{Policy: DefaultBackpressurePolicy()}
+	if reporter, ok := q.(queue.DepthReporter); ok {
+		b.Queue = reporter
+	}
+	if source, ok := repo.(completionLatencySource); ok {
+		b.Latency = source
+	}
+	return b
+}
+
+// Reading returns the current load, refreshing it when stale. A nil receiver
+// always reports normal load.
+func (b *Backpressure) Reading(ctx context.Context) LoadReading {
+	if b == nil {
+		return LoadReading{PollAfterMs: defaultPollAfterMs}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	p := b.policy()
+	if b.fetched && now.Sub(b.reading.ObservedAt) < p.RefreshInterval {
+		return b.reading
+	}
+	b.reading = b.measure(ctx, now, p)
+	b.fetched = true
+	return b.reading
+}
+
+// PollAfterMs is the poll interval to advertise right now.
+func (b *Backpressure) PollAfterMs(ctx context.Context) int {
+	return b.Reading(ctx).PollAfterMs
+}
+
+// Stats reports the current reading for the admin stats endpoint.
+func (b *Backpressure) Stats(ctx context.Context) (any, error) {
+	return b.Reading(ctx), nil
+}
+
+func (b *Backpressure) measure(ctx context.Context, now time.Time, p BackpressurePolicy) LoadReading {
+	reading := LoadReading{ObservedAt: now}
+	if b.Queue != nil {
+		depth, err := b.Queue.ApproximateDepth(ctx)
+		if err != nil {
+			telemetry.Error("analysis.backpressure_depth_failed", map[string]any{"error": err.Error()})
+		} else {
+			reading.QueueDepth = depth
+			reading.QueueDepthKnown = true
+			metrics.SetAnalysisQueueDepth(depth)
+		}
+	}
+	var latency time.Duration
+	if b.Latency != nil {
+		p90, samples, err := b.Latency.CompletionLatencyP90(ctx, now.Add(-p.LatencyWindow))
+		if err != nil {
+			telemetry.Error("analysis.backpressure_latency_failed", map[string]any{"error": err.Error()})
+		} else {
+			reading.LatencySamples = samples
+			reading.CompletionLatencyMs = p90.Milliseconds()
+			metrics.SetAnalysisCompletionLatencyMs(float64(reading.CompletionLatencyMs))
+			if samples >= p.MinLatencySamples {
+				latency = p90
+			}
+		}
+	}
+
+	factor := math.Max(1, float64(latency)/float64(p.TargetLatency))
+	factor = math.Max(factor, float64(reading.QueueDepth)/float64(p.TargetDepth))
+	reading.PollAfterMs = min(int(float64(p.BasePollMs)*factor), p.MaxPollMs)
+
+	reading.Overloaded = reading.QueueDepth >= p.OverloadDepth || latency >= p.OverloadLatency
+	if reading.Overloaded {
+		// Ask clients to come back after roughly one completion cycle.
+		retry := max(latency, time.Duration(reading.PollAfterMs)*time.Millisecond, 30*time.Second)
+		reading.RetryAfterSeconds = int(min(retry, 10*time.Minute) / time.Second)
+	}
+	return reading
+}
+
+func (b *Backpressure) policy() BackpressurePolicy {
+	p := b.Policy
+	def := DefaultBackpressurePolicy()
+	if p.BasePollMs <= 0 {
+		p.BasePollMs = def.BasePollMs
+	}
+	if p.MaxPollMs < p.BasePollMs {
+		p.MaxPollMs = max(def.MaxPollMs, p.BasePollMs)
+	}
+	if p.TargetLatency <= 0 {
+		p.TargetLatency = def.TargetLatency
+	}
+	if p.TargetDepth <= 0 {
+		p.TargetDepth = def.TargetDepth
+	}
+	if p.OverloadLatency <= 0 {
+		p.OverloadLatency = def.OverloadLatency
+	}
+	if p.OverloadDepth <= 0 {
+		p.OverloadDepth = def.OverloadDepth
+	}
+	if p.LatencyWindow <= 0 {
+		p.LatencyWindow = def.LatencyWindow
+	}
+	if p.MinLatencySamples <= 0 {
+		p.MinLatencySamples = def.MinLatencySamples
+	}
+	if p.RefreshInterval <= 0 {
+		p.RefreshInterval = def.RefreshInterval
+	}
+	return p
+}
+
+func (b *Backpressure) now() time.Time {
+	if b.Now != nil {
+		return b.Now().UTC()
+	}
+	return time.Now().UTC()
+}
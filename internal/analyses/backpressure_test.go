@@ -0,0 +1,105 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/server/middleware"
+	local "resume-backend/internal/shared/storage/object/local"
+)
+
+type fixedDepth struct {
+	depth int
+	calls int
+}
+
+func (f *fixedDepth) ApproximateDepth(ctx context.Context) (int, error) {
+	f.calls++
+	return f.depth, nil
+}
+
+func TestBackpressureScalesPollInterval(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewMemoryRepo()
+	for i := 0; i < 10; i++ {
+		created := now.Add(-5 * time.Minute)
+		completed := created.Add(90 * time.Second)
+		a := Analysis{ID: fmt.Sprintf("a-%d", i), UserID: "user-1", Status: StatusCompleted, CreatedAt: created, CompletedAt: &completed}
+		if err := repo.Create(context.Background(), a); err != nil {
+			t.Fatalf("create analysis: %v", err)
+		}
+	}
+
+	depth := &fixedDepth{depth: 10}
+	b := &Backpressure{Queue: depth, Latency: repo, Policy: DefaultBackpressurePolicy(), Now: func() time.Time { return now }}
+
+	reading := b.Reading(context.Background())
+	if reading.CompletionLatencyMs != 90000 || reading.LatencySamples != 10 {
+		t.Fatalf("unexpected latency reading: %+v", reading)
+	}
+	// 90s against a 30s target triples the base interval.
+	if reading.PollAfterMs != 3*defaultPollAfterMs {
+		t.Fatalf("expected pollAfterMs %d, got %d", 3*defaultPollAfterMs, reading.PollAfterMs)
+	}
+	if reading.Overloaded {
+		t.Fatalf("did not expect overload at depth 10")
+	}
+
+	depth.depth = 5000
+	b.Reading(context.Background())
+	if depth.calls != 1 {
+		t.Fatalf("expected cached reading within refresh interval, got %d depth calls", depth.calls)
+	}
+
+	now = now.Add(time.Minute)
+	reading = b.Reading(context.Background())
+	if reading.PollAfterMs != b.Policy.MaxPollMs {
+		t.Fatalf("expected pollAfterMs capped at %d, got %d", b.Policy.MaxPollMs, reading.PollAfterMs)
+	}
+	if !reading.Overloaded || reading.RetryAfterSeconds <= 0 {
+		t.Fatalf("expected overload with retry-after, got %+v", reading)
+	}
+}
+
+func TestStartAnalysisShedsGuestsWhenOverloaded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docRepo := documents.NewMemoryRepo()
+	store := local.New(t.TempDir())
+	queueStub := &stubQueue{}
+	svc := &Service{Repo: NewMemoryRepo(), DocRepo: docRepo, Store: store, LLM: stubLLM{}, JobQueue: queueStub}
+	handler := NewHandler(svc, docRepo)
+	handler.Backpressure = &Backpressure{Queue: &fixedDepth{depth: 5000}, ShedGuests: true}
+
+	router := gin.New()
+	router.Use(middleware.Auth("dev"))
+	handler.RegisterRoutes(router.Group("/api/v1"))
+
+	documentID := seedDocument(t, docRepo, store, "guest:test-guest")
+	body, _ := json.Marshal(map[string]string{"jobDescription": strings.Repeat("a", 300)})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/"+documentID+"/analyze", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+	if len(queueStub.messages) != 0 {
+		t.Fatalf("expected no queued work, got %d messages", len(queueStub.messages))
+	}
+}
@@ -0,0 +1,132 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"resume-backend/internal/llmbudget"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// StatusBudgetDeferred marks an analysis created while a daily LLM budget was
+// spent. It is queued by ReleaseBudgetDeferred once the budget resets.
+const StatusBudgetDeferred = "budget_deferred"
+
+// budgetReleaseBatch bounds how many deferred analyses one release pass queues.
+const budgetReleaseBatch = 100
+
+// budgetDeferredLister finds deferred analyses, oldest first.
+type budgetDeferredLister interface {
+	ListBudgetDeferred(ctx context.Context, limit int) ([]Analysis, error)
+}
+
+// admitBudget decides what happens to a new analysis charged to orgID while a
+// budget may be spent. It returns the status the analysis starts in, or an
+// error wrapping llmbudget.ErrExceeded when such analyses are rejected.
+func (s *Service) admitBudget(ctx context.Context, orgID string) (string, error) {
+	status, err := s.Budget.Check(ctx, orgID)
+	if err != nil {
+		// Budgets guard against runaway cost; a failed lookup must not stop analyses.
+		telemetry.ErrorContext(ctx, "analysis.budget_check_failed", map[string]any{
+			"org_id": orgID,
+			"error":  err.Error(),
+		})
+		return StatusQueued, nil
+	}
+	if !status.Exceeded {
+		return StatusQueued, nil
+	}
+	if s.Budget.Defers() {
+		return StatusBudgetDeferred, nil
+	}
+	return "", fmt.Errorf("%w: %s budget of $%.2f is spent until %s", llmbudget.ErrExceeded, status.Scope, status.BudgetUSD, status.ResetsAt.Format(time.RFC3339))
+}
+
+// recordSpend charges the tokens an analysis used to the LLM budgets.
+func (s *Service) recordSpend(ctx context.Context, analysis Analysis, usage *ctxmeta.TokenUsage) {
+	if err := s.Budget.Record(ctx, analysis.OrgID, usage); err != nil {
+		telemetry.ErrorContext(ctx, "analysis.budget_record_failed", map[string]any{
+			"analysis_id": analysis.ID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// ReleaseBudgetDeferred queues deferred analyses whose budgets have room again,
+// oldest first, and returns how many it queued.
+func (s *Service) ReleaseBudgetDeferred(ctx context.Context) (int, error) {
+	lister, ok := s.Repo.(budgetDeferredLister)
+	if !ok || s.JobQueue == nil {
+		return 0, nil
+	}
+	deferred, err := lister.ListBudgetDeferred(ctx, budgetReleaseBatch)
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	spent := map[string]bool{}
+	for _, analysis := range deferred {
+		exceeded, checked := spent[analysis.OrgID]
+		if !checked {
+			status, err := s.Budget.Check(ctx, analysis.OrgID)
+			if err != nil {
+				return released, err
+			}
+			exceeded = status.Exceeded
+			spent[analysis.OrgID] = exceeded
+		}
+		if exceeded {
+			continue
+		}
+		if err := s.Repo.UpdateStatus(ctx, analysis.ID, StatusQueued, nil); err != nil {
+			return released, fmt.Errorf("queue deferred analysis %s: %w", analysis.ID, err)
+		}
+		analysis.Status = StatusQueued
+		if err := s.enqueue(ctx, analysis); err != nil {
+			err = fmt.Errorf("enqueue analysis: %w", err)
+			s.failAnalysis(ctx, analysis.ID, analysis.UserID, analysis.DocumentID, err, nil)
+			return released, err
+		}
+		released++
+		telemetry.InfoContext(ctx, "analysis.status", map[string]any{
+			"user_id":           analysis.UserID,
+			"document_id":       analysis.DocumentID,
+			"analysis_id":       analysis.ID,
+			"status":            StatusQueued,
+			"status_transition": StatusBudgetDeferred + "->queued",
+		})
+	}
+	return released, nil
+}
+
+// RunBudgetReleases calls ReleaseBudgetDeferred every interval until ctx is done.
+func (s *Service) RunBudgetReleases(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ReleaseBudgetDeferred(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			telemetry.Error("analysis.budget_release_failed", map[string]any{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func logBudgetDeferred(ctx context.Context, analysis Analysis) {
+	telemetry.InfoContext(ctx, "analysis.status", map[string]any{
+		"user_id":     analysis.UserID,
+		"document_id": analysis.DocumentID,
+		"analysis_id": analysis.ID,
+		"org_id":      analysis.OrgID,
+		"status":      StatusBudgetDeferred,
+	})
+}
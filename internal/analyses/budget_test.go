@@ -0,0 +1,137 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/llmbudget"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+// meteredLLM reports token usage the way the OpenAI clients do.
+type meteredLLM struct {
+	staticLLM
+	prompt, completion int
+}
+
+func (m meteredLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	ctxmeta.AddTokenUsage(ctx, m.prompt, m.completion)
+	return m.staticLLM.AnalyzeResume(ctx, input)
+}
+
+func spentBudget(t *testing.T, action string, now *time.Time) *llmbudget.Service {
+	t.Helper()
+	budget := llmbudget.NewService(llmbudget.NewMemoryRepo(), llmbudget.Pricing{PromptPer1K: 1}, 1, 0, action)
+	budget.Now = func() time.Time { return *now }
+	if err := budget.Record(context.Background(), "", &ctxmeta.TokenUsage{PromptTokens: 1000}); err != nil {
+		t.Fatalf("record spend: %v", err)
+	}
+	return budget
+}
+
+func TestBudgetDeferredAnalysisIsReleasedNextDay(t *testing.T) {
+	router, _, svc := setupAnalysisRouterWithLLM(t, loadFixture(t, "testdata/v2_3_good.json"))
+	now := time.Date(2026, 5, 4, 18, 0, 0, 0, time.UTC)
+	svc.Budget = spentBudget(t, llmbudget.ActionDefer, &now)
+	queue := svc.JobQueue.(*stubQueue)
+
+	id := startAnalysis(t, router, "v2_3")
+	if again := startAnalysis(t, router, "v2_3"); again != id {
+		t.Fatalf("expected the deferred analysis to be reused, got %s and %s", id, again)
+	}
+	if len(queue.messages) != 0 {
+		t.Fatalf("deferred analysis should not be queued, got %d messages", len(queue.messages))
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analyses/"+id, nil)
+	req.Header.Set("X-Guest-Id", "test-guest")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var deferred struct {
+		Status        string `json:"status"`
+		DeferredUntil string `json:"deferredUntil"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &deferred); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if deferred.Status != StatusBudgetDeferred || deferred.DeferredUntil != "2026-05-05T00:00:00Z" {
+		t.Fatalf("unexpected deferred analysis: %+v", deferred)
+	}
+
+	ctx := context.Background()
+	if released, err := svc.ReleaseBudgetDeferred(ctx); err != nil || released != 0 {
+		t.Fatalf("release before reset: released=%d err=%v", released, err)
+	}
+	now = now.Add(7 * time.Hour)
+	if released, err := svc.ReleaseBudgetDeferred(ctx); err != nil || released != 1 {
+		t.Fatalf("release after reset: released=%d err=%v", released, err)
+	}
+	if len(queue.messages) != 1 || queue.messages[0].AnalysisID != id {
+		t.Fatalf("expected %s to be queued, got %+v", id, queue.messages)
+	}
+	if resp := getAnalysis(t, router, id); resp.Status != StatusQueued {
+		t.Fatalf("expected queued after release, got %s", resp.Status)
+	}
+}
+
+func TestBudgetRejectReturns429(t *testing.T) {
+	router, _, svc := setupAnalysisRouterWithLLM(t, loadFixture(t, "testdata/v2_3_good.json"))
+	now := time.Date(2026, 5, 4, 18, 0, 0, 0, time.UTC)
+	svc.Budget = spentBudget(t, llmbudget.ActionReject, &now)
+
+	_, _, err := svc.StartOrReuseWithOptions(context.Background(), "doc-guest:test-guest", "guest:test-guest", strings.Repeat("a", 300), "v2_3", ModeJobMatch, false, StartOptions{})
+	if !errors.Is(err, llmbudget.ErrExceeded) {
+		t.Fatalf("expected ErrExceeded, got %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]string{"jobDescription": strings.Repeat("a", 300), "promptVersion": "v2_3"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/doc-guest:test-guest/analyze", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Guest-Id", "test-guest")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), "llm_budget_exceeded") {
+		t.Fatalf("expected 429 llm_budget_exceeded, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("expected a Retry-After header")
+	}
+}
+
+func TestProcessAnalysisRecordsTokenSpend(t *testing.T) {
+	_, analysisRepo, svc := setupAnalysisRouterWithLLM(t, loadFixture(t, "testdata/v2_3_good.json"))
+	svc.LLM = meteredLLM{staticLLM: svc.LLM.(staticLLM), prompt: 1500, completion: 500}
+	budget := llmbudget.NewService(llmbudget.NewMemoryRepo(), llmbudget.Pricing{PromptPer1K: 1, CompletionPer1K: 2}, 5, 1, "")
+	svc.Budget = budget
+
+	ctx := context.Background()
+	if err := analysisRepo.Create(ctx, Analysis{
+		ID: "a1", DocumentID: "doc-guest:test-guest", UserID: "guest:test-guest", OrgID: "org-1",
+		PromptVersion: "v2_3", Mode: ModeATS, Status: StatusQueued, CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, "a1"); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	statuses, err := budget.Statuses(ctx, "org-1")
+	if err != nil {
+		t.Fatalf("statuses: %v", err)
+	}
+	for _, status := range statuses {
+		if status.PromptTokens != 1500 || status.CompletionTokens != 500 || status.SpentUSD != 2.5 {
+			t.Fatalf("unexpected spend for %s: %+v", status.Scope, status)
+		}
+	}
+	if len(statuses) != 2 || !statuses[1].Exceeded {
+		t.Fatalf("expected the org budget to be spent, got %+v", statuses)
+	}
+}
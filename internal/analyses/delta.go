@@ -0,0 +1,351 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"resume-backend/internal/analyses/scoring"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// deltaPromptVersion is the prompt that evaluates only the changed sections of a resume.
+const deltaPromptVersion = "delta_v1"
+
+// maxDiffLines bounds the line diff; longer documents always get a full analysis.
+const maxDiffLines = 2000
+
+// previousAnalysisFinder is implemented by repos that can find the analysis a
+// re-analysis may build on.
+type previousAnalysisFinder interface {
+	// LatestCompletedForJob returns the user's most recent completed analysis for the
+	// same job description and mode, excluding excludeID.
+	LatestCompletedForJob(ctx context.Context, userID, jobDescriptionHash string, mode AnalysisMode, excludeID string) (Analysis, error)
+}
+
+// Revision describes how a delta analysis was derived; it is stored under
+// result.revision.
+type Revision struct {
+	PreviousAnalysisID string   `json:"previousAnalysisId"`
+	Similarity         float64  `json:"similarity"`
+	ChangedSections    []string `json:"changedSections"`
+	Delta              bool     `json:"delta"`
+}
+
+// deltaResultV1 is the output of the delta prompt: findings for the changed
+// sections plus a refreshed score and summary.
+type deltaResultV1 struct {
+	Summary        SummaryV1           `json:"summary"`
+	ATS            deltaATSV1          `json:"ats"`
+	Issues         []IssueV2_2         `json:"issues"`
+	BulletRewrites []BulletRewriteV2_3 `json:"bulletRewrites"`
+}
+
+type deltaATSV1 struct {
+	Score          float64  `json:"score"`
+	ScoreReasoning []string `json:"scoreReasoning"`
+}
+
+// tryDeltaAnalysis runs the cheaper delta prompt when the resume is a small edit of
+// one the user already analyzed against the same job. It returns nil when the
+// analysis should fall back to the full prompt.
+func (s *Service) tryDeltaAnalysis(ctx context.Context, client llm.Client, analysis Analysis, resumeText string, input llm.AnalyzeInput) (json.RawMessage, *Revision) {
+	if s.DeltaMinSimilarity <= 0 || len(analysis.SupportingDocuments) > 0 {
+		return nil, nil
+	}
+	finder, ok := s.Repo.(previousAnalysisFinder)
+	if !ok {
+		return nil, nil
+	}
+	prev, err := finder.LatestCompletedForJob(ctx, analysis.UserID, jobDescriptionHash(analysis), analysis.Mode, analysis.ID)
+	// Re-running the same document is an explicit request for a fresh analysis.
+	if err != nil || prev.DocumentID == analysis.DocumentID || prev.PromptVersion != analysis.PromptVersion || len(prev.SupportingDocuments) > 0 {
+		return nil, nil
+	}
+	var previous AnalysisResultV2_3
+	if err := remarshal(prev.AnalysisRaw, &previous); err != nil || previous.Validate() != nil {
+		return nil, nil
+	}
+	// A result scored with another config cannot be carried forward.
+	if previous.ATS.ScoreExplanation.scoringVersion() != scoring.CurrentVersion {
+		return nil, nil
+	}
+	prevDoc, err := s.DocRepo.GetByID(ctx, prev.UserID, prev.DocumentID)
+	if err != nil || prevDoc.ExtractedTextKey == "" {
+		return nil, nil
+	}
+	prevText, err := s.resumeText(ctx, prevDoc)
+	if err != nil {
+		return nil, nil
+	}
+
+	diff, ok := diffResumes(prevText, resumeText)
+	if !ok || diff.similarity*100 < float64(s.DeltaMinSimilarity) {
+		return nil, nil
+	}
+	revision := &Revision{
+		PreviousAnalysisID: prev.ID,
+		Similarity:         round2(diff.similarity),
+		ChangedSections:    diff.sectionNames(),
+		Delta:              true,
+	}
+
+	merged := previous
+	if len(diff.changed) > 0 {
+		delta, err := s.runDeltaPrompt(ctx, client, previous, diff, input)
+		if err != nil {
+			telemetry.Info("analysis.delta_fallback", map[string]any{
+				"analysis_id": analysis.ID,
+				"error":       sanitizeError(err),
+			})
+			return nil, nil
+		}
+		merged = mergeDelta(previous, delta, diff, resumeText)
+		if err := merged.Validate(); err != nil {
+			return nil, nil
+		}
+		if err := validateContentV2_3(&merged, guardrailForText(resumeText)); err != nil {
+			return nil, nil
+		}
+	}
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil
+	}
+	telemetry.Info("analysis.delta", map[string]any{
+		"analysis_id":          analysis.ID,
+		"previous_analysis_id": prev.ID,
+		"similarity":           revision.Similarity,
+		"changed_sections":     len(revision.ChangedSections),
+	})
+	return raw, revision
+}
+
+func (s *Service) runDeltaPrompt(ctx context.Context, client llm.Client, previous AnalysisResultV2_3, diff resumeDiff, input llm.AnalyzeInput) (deltaResultV1, error) {
+	prior := struct {
+		Summary        SummaryV1 `json:"summary"`
+		Score          float64   `json:"score"`
+		ScoreReasoning []string  `json:"scoreReasoning"`
+	}{previous.Summary, previous.ATS.Score, previous.ATS.ScoreReasoning}
+	previousJSON, err := json.Marshal(prior)
+	if err != nil {
+		return deltaResultV1{}, err
+	}
+	deltaInput := input
+	deltaInput.PromptVersion = deltaPromptVersion
+	deltaInput.ResumeText = diff.changedText()
+	// The delta schema has no score breakdown for the scan to ground.
+	deltaInput.Quantification = nil
+	ctx = ctxmeta.WithExtraSystemMessage(ctx, "Previous analysis of the unedited resume:\n"+string(previousJSON))
+
+	raw, err := client.AnalyzeResume(ctx, deltaInput)
+	if err != nil {
+		return deltaResultV1{}, err
+	}
+	var out deltaResultV1
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return deltaResultV1{}, fmt.Errorf("delta output parse: %w", err)
+	}
+	if strings.TrimSpace(out.Summary.OverallAssessment) == "" {
+		return deltaResultV1{}, errors.New("delta output missing summary.overallAssessment")
+	}
+	return out, nil
+}
+
+// mergeDelta keeps the previous findings for untouched sections whose evidence is
+// still in the resume, and takes everything else from the delta output.
+func mergeDelta(previous AnalysisResultV2_3, delta deltaResultV1, diff resumeDiff, resumeText string) AnalysisResultV2_3 {
+	merged := previous
+	changed := diff.sectionSet()
+	stillPresent := func(evidence string) bool {
+		evidence = strings.TrimSpace(evidence)
+		return evidence == "" || strings.EqualFold(evidence, "notFound") || strings.Contains(normalizeLine(resumeText), normalizeLine(evidence))
+	}
+
+	merged.Issues = nil
+	for _, issue := range previous.Issues {
+		if !changed[sectionKey(issue.Section)] && stillPresent(issue.Evidence) {
+			merged.Issues = append(merged.Issues, issue)
+		}
+	}
+	merged.Issues = append(merged.Issues, delta.Issues...)
+
+	merged.BulletRewrites = nil
+	for _, rewrite := range previous.BulletRewrites {
+		if !changed[sectionKey(rewrite.Section)] && stillPresent(rewrite.Before) {
+			merged.BulletRewrites = append(merged.BulletRewrites, rewrite)
+		}
+	}
+	merged.BulletRewrites = append(merged.BulletRewrites, delta.BulletRewrites...)
+
+	merged.Summary = delta.Summary
+	merged.ATS.Score = clampScore(delta.ATS.Score)
+	if len(delta.ATS.ScoreReasoning) > 0 {
+		merged.ATS.ScoreReasoning = delta.ATS.ScoreReasoning
+	}
+	return merged
+}
+
+// resumeDiff is a line-level comparison of two extracted resume texts.
+type resumeDiff struct {
+	similarity float64
+	// changed holds added or edited lines of the new text, grouped by section in
+	// document order.
+	changed []changedSection
+}
+
+type changedSection struct {
+	name  string
+	lines []string
+}
+
+func (d resumeDiff) sectionNames() []string {
+	names := make([]string, 0, len(d.changed))
+	for _, sec := range d.changed {
+		names = append(names, sec.name)
+	}
+	return names
+}
+
+func (d resumeDiff) sectionSet() map[string]bool {
+	set := make(map[string]bool, len(d.changed))
+	for _, sec := range d.changed {
+		set[sectionKey(sec.name)] = true
+	}
+	return set
+}
+
+func (d resumeDiff) changedText() string {
+	var b strings.Builder
+	for _, sec := range d.changed {
+		fmt.Fprintf(&b, "[%s]\n%s\n\n", sec.name, strings.Join(sec.lines, "\n"))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// diffResumes compares resumes line by line. Similarity is 2*LCS/(len(a)+len(b)),
+// so 1 means identical and 0 means nothing in common.
+func diffResumes(before, after string) (resumeDiff, bool) {
+	a, b := resumeLines(before), resumeLines(after)
+	if len(a) == 0 || len(b) == 0 || len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return resumeDiff{}, false
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if normalizeLine(a[i]) == normalizeLine(b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	matched := make([]bool, len(b))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case normalizeLine(a[i]) == normalizeLine(b[j]):
+			matched[j] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	diff := resumeDiff{similarity: 2 * float64(lcs[0][0]) / float64(len(a)+len(b))}
+	section := "General"
+	for j, line := range b {
+		if isSectionHeading(line) {
+			section = strings.TrimSuffix(strings.TrimSpace(line), ":")
+		}
+		if matched[j] {
+			continue
+		}
+		if n := len(diff.changed); n == 0 || diff.changed[n-1].name != section {
+			diff.changed = append(diff.changed, changedSection{name: section})
+		}
+		last := &diff.changed[len(diff.changed)-1]
+		last.lines = append(last.lines, line)
+	}
+	return diff, true
+}
+
+func resumeLines(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func normalizeLine(line string) string {
+	return strings.ToLower(strings.Join(strings.Fields(line), " "))
+}
+
+var commonSectionHeadings = map[string]bool{
+	"summary": true, "profile": true, "experience": true, "work experience": true,
+	"professional experience": true, "education": true, "skills": true, "technical skills": true,
+	"projects": true, "certifications": true, "awards": true, "publications": true,
+	"volunteering": true, "languages": true, "interests": true,
+}
+
+// isSectionHeading recognizes short lines that name a well-known section, end with
+// a colon, or are written in capitals.
+func isSectionHeading(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || len(strings.Fields(line)) > 4 {
+		return false
+	}
+	if commonSectionHeadings[sectionKey(line)] || strings.HasSuffix(line, ":") {
+		return true
+	}
+	hasLetter := false
+	for _, r := range line {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	return hasLetter
+}
+
+func sectionKey(name string) string {
+	return strings.TrimSuffix(normalizeLine(name), ":")
+}
+
+func remarshal(in any, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func round2(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
+
+// withRevision records the delta derivation on a normalized result.
+func withRevision(result map[string]any, revision *Revision) {
+	if revision == nil || result == nil {
+		return
+	}
+	result["revision"] = revision
+}
@@ -0,0 +1,208 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/llm"
+)
+
+const deltaBaseResume = `Jane Doe
+SUMMARY
+Sales lead with ten years of experience.
+EXPERIENCE
+Acme Corp, Sales Manager
+Improved sales.
+Managed a team of 8 account executives.
+Opened the Berlin office.
+Ran quarterly pipeline reviews.
+EDUCATION
+BA Economics, State University
+SKILLS
+Salesforce, HubSpot
+Negotiation, forecasting
+Public speaking`
+
+type recordingLLM struct {
+	inputs    []llm.AnalyzeInput
+	responses map[string]string
+}
+
+func (r *recordingLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	r.inputs = append(r.inputs, input)
+	return json.RawMessage(r.responses[input.PromptVersion]), nil
+}
+
+func TestDiffResumesGroupsChangedLinesBySection(t *testing.T) {
+	edited := strings.Replace(deltaBaseResume, "Salesforce, HubSpot", "Salesforce, HubSpot, Gong", 1)
+
+	diff, ok := diffResumes(deltaBaseResume, edited)
+	if !ok {
+		t.Fatal("expected diff")
+	}
+	if diff.similarity < 0.9 || diff.similarity >= 1 {
+		t.Fatalf("expected high but imperfect similarity, got %v", diff.similarity)
+	}
+	if got := diff.sectionNames(); len(got) != 1 || got[0] != "SKILLS" {
+		t.Fatalf("expected only SKILLS to change, got %v", got)
+	}
+	if got := diff.changedText(); got != "[SKILLS]\nSalesforce, HubSpot, Gong" {
+		t.Fatalf("unexpected changed text %q", got)
+	}
+
+	same, _ := diffResumes(deltaBaseResume, "  "+strings.ReplaceAll(deltaBaseResume, "\n", "\n\n"))
+	if same.similarity != 1 || len(same.changed) != 0 {
+		t.Fatalf("expected whitespace-only edits to be identical, got %+v", same)
+	}
+
+	unrelated, _ := diffResumes(deltaBaseResume, "John Smith\nEngineer\nGo, Rust")
+	if unrelated.similarity > 0.2 {
+		t.Fatalf("expected low similarity for a different resume, got %v", unrelated.similarity)
+	}
+}
+
+func TestProcessAnalysisRunsDeltaForSmallEdits(t *testing.T) {
+	client := &recordingLLM{responses: map[string]string{
+		deltaPromptVersion: `{
+  "summary": {"overallAssessment": "Stronger tooling coverage.", "strengths": ["CRM depth"], "weaknesses": []},
+  "ats": {"score": 84, "scoreReasoning": ["Adds a sales intelligence tool.", "Experience unchanged.", "Formatting unchanged."]},
+  "issues": [{
+    "severity": "low", "section": "SKILLS", "problem": "Tools listed without context", "whyItMatters": "Recruiters look for usage",
+    "suggestion": "Mention where Gong was used", "evidence": "Salesforce, HubSpot, Gong", "fixEffort": "5min",
+    "priority": 3, "autoFixable": false, "requiresUserInput": ["crm_tools"]
+  }],
+  "bulletRewrites": []
+}`,
+	}}
+	svc, repo, docRepo, _ := setupServiceWithDoc(t, client)
+	svc.DeltaMinSimilarity = 90
+	ctx := context.Background()
+
+	edited := strings.Replace(deltaBaseResume, "Salesforce, HubSpot", "Salesforce, HubSpot, Gong", 1)
+	createTextDoc(t, svc, docRepo, "doc-prev", deltaBaseResume)
+	createTextDoc(t, svc, docRepo, "doc-next", edited)
+
+	completedAt := time.Now().UTC().Add(-time.Hour)
+	prev := Analysis{
+		ID:             "analysis-prev",
+		DocumentID:     "doc-prev",
+		UserID:         "user-1",
+		JobDescription: "jd",
+		PromptVersion:  "v2_3",
+		Status:         StatusCompleted,
+		CompletedAt:    &completedAt,
+		CreatedAt:      completedAt,
+	}
+	if err := repo.Create(ctx, prev); err != nil {
+		t.Fatalf("create previous: %v", err)
+	}
+	var previousRaw map[string]any
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &previousRaw); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	if err := repo.UpdateAnalysisRaw(ctx, prev.ID, previousRaw); err != nil {
+		t.Fatalf("store previous raw: %v", err)
+	}
+
+	next := Analysis{
+		ID:             "analysis-next",
+		DocumentID:     "doc-next",
+		UserID:         "user-1",
+		JobDescription: "jd",
+		PromptVersion:  "v2_3",
+		Status:         StatusQueued,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := repo.Create(ctx, next); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, next.ID); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	if len(client.inputs) != 1 || client.inputs[0].PromptVersion != deltaPromptVersion {
+		t.Fatalf("expected a single delta prompt call, got %+v", client.inputs)
+	}
+	if got := client.inputs[0].ResumeText; got != "[SKILLS]\nSalesforce, HubSpot, Gong" {
+		t.Fatalf("expected only changed lines to be sent, got %q", got)
+	}
+
+	got, err := repo.GetByID(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if got.Status != StatusCompleted {
+		t.Fatalf("expected completed analysis, got %s (%v)", got.Status, got.ErrorMessage)
+	}
+	var merged AnalysisResultV2_3
+	if err := remarshal(got.AnalysisRaw, &merged); err != nil {
+		t.Fatalf("decode merged raw: %v", err)
+	}
+	if merged.ATS.Score != 84 || merged.Summary.OverallAssessment != "Stronger tooling coverage." {
+		t.Fatalf("expected refreshed score and summary, got %v %q", merged.ATS.Score, merged.Summary.OverallAssessment)
+	}
+	if len(merged.Issues) != 2 || len(merged.BulletRewrites) != 1 {
+		t.Fatalf("expected previous findings plus the delta issue, got %d issues and %d rewrites", len(merged.Issues), len(merged.BulletRewrites))
+	}
+
+	revision, ok := got.Result["revision"].(*Revision)
+	if !ok {
+		t.Fatalf("expected revision on result, got %#v", got.Result["revision"])
+	}
+	if revision.PreviousAnalysisID != prev.ID || !revision.Delta || len(revision.ChangedSections) != 1 {
+		t.Fatalf("unexpected revision %+v", revision)
+	}
+}
+
+func TestProcessAnalysisSkipsDeltaBelowThreshold(t *testing.T) {
+	client := &recordingLLM{responses: map[string]string{"v2_3": "{not-json"}}
+	svc, repo, docRepo, _ := setupServiceWithDoc(t, client)
+	svc.DeltaMinSimilarity = 90
+	ctx := context.Background()
+
+	createTextDoc(t, svc, docRepo, "doc-prev", deltaBaseResume)
+	createTextDoc(t, svc, docRepo, "doc-next", "John Smith\nEXPERIENCE\nBackend engineer\nSKILLS\nGo, Postgres")
+
+	completedAt := time.Now().UTC()
+	prev := Analysis{ID: "analysis-prev", DocumentID: "doc-prev", UserID: "user-1", JobDescription: "jd", PromptVersion: "v2_3", Status: StatusCompleted, CompletedAt: &completedAt}
+	if err := repo.Create(ctx, prev); err != nil {
+		t.Fatalf("create previous: %v", err)
+	}
+	if err := repo.UpdateAnalysisRaw(ctx, prev.ID, json.RawMessage(loadFixture(t, "testdata/v2_3_good.json"))); err != nil {
+		t.Fatalf("store previous raw: %v", err)
+	}
+	next := Analysis{ID: "analysis-next", DocumentID: "doc-next", UserID: "user-1", JobDescription: "jd", PromptVersion: "v2_3", Status: StatusQueued}
+	if err := repo.Create(ctx, next); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	_ = svc.ProcessAnalysis(ctx, next.ID)
+
+	if len(client.inputs) == 0 || client.inputs[0].PromptVersion != "v2_3" {
+		t.Fatalf("expected the full prompt for a substantial rewrite, got %+v", client.inputs)
+	}
+}
+
+func createTextDoc(t *testing.T, svc *Service, docRepo *documents.MemoryRepo, id, text string) {
+	t.Helper()
+	key, _, _, err := svc.Store.Save(context.Background(), "user-1", id+".txt", bytes.NewReader([]byte(text)))
+	if err != nil {
+		t.Fatalf("save extracted text: %v", err)
+	}
+	doc := documents.Document{
+		ID:               id,
+		UserID:           "user-1",
+		FileName:         id + ".txt",
+		MimeType:         "text/plain",
+		StorageKey:       "original-" + id,
+		ExtractedTextKey: key,
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := docRepo.Create(context.Background(), doc); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+}
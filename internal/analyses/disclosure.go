@@ -0,0 +1,155 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/llmarchive"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Data retention policies reported by the AI disclosure.
+const (
+	RetentionGuestExpiry  = "guest_expiry"
+	RetentionUntilDeleted = "until_deleted"
+)
+
+// ArchivePolicy reports the LLM call archive policy of an owner.
+type ArchivePolicy interface {
+	PolicyFor(ctx context.Context, ownerID string) (llmarchive.Mode, time.Duration, error)
+}
+
+// DisclosureSettings are the deployment facts the AI disclosure reports.
+type DisclosureSettings struct {
+	// GuestRetention is how long guest documents and analyses are kept; zero
+	// means until deleted.
+	GuestRetention time.Duration
+	// Archive is the LLM call archive; nil reports archiving as off.
+	Archive ArchivePolicy
+}
+
+// AIDisclosure is the machine-readable account of how AI processed a user's
+// content, for AI-transparency requirements. Content is never used to train
+// models, so UsedForTraining is always false.
+type AIDisclosure struct {
+	// Analysis is set when the disclosure is for one analysis.
+	Analysis        *DisclosedAnalysis  `json:"analysis,omitempty"`
+	Provider        string              `json:"provider"`
+	Model           string              `json:"model"`
+	UsedForTraining bool                `json:"usedForTraining"`
+	Retention       DisclosureRetention `json:"retention"`
+}
+
+// DisclosedAnalysis identifies the model run that produced an analysis.
+type DisclosedAnalysis struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"`
+	PromptVersion string     `json:"promptVersion,omitempty"`
+	ProcessedAt   *time.Time `json:"processedAt,omitempty"`
+}
+
+// DisclosureRetention says how long the user's content is kept. Documents and
+// analyses follow Policy; the LLM call archive keeps prompts and responses for
+// LLMArchiveDays in LLMArchiveMode ("off" keeps nothing).
+type DisclosureRetention struct {
+	Policy         string `json:"policy"`
+	Days           int    `json:"days,omitempty"`
+	LLMArchiveMode string `json:"llmArchiveMode"`
+	LLMArchiveDays int    `json:"llmArchiveDays,omitempty"`
+}
+
+// Disclosure describes the AI processing of the user's content. With an
+// analysisID it names the provider and model recorded in that analysis's
+// provenance; without one, those the deployment currently uses.
+func (s *Service) Disclosure(ctx context.Context, userID string, guest bool, analysisID string, settings DisclosureSettings) (AIDisclosure, error) {
+	out := AIDisclosure{
+		Provider:  fallbackString(s.Provider, "unknown"),
+		Model:     fallbackString(s.Model, "unknown"),
+		Retention: DisclosureRetention{Policy: RetentionUntilDeleted, LLMArchiveMode: string(llmarchive.ModeOff)},
+	}
+	if analysisID != "" {
+		analysis, err := s.Get(ctx, analysisID)
+		if err != nil {
+			return AIDisclosure{}, err
+		}
+		if analysis.UserID != userID {
+			return AIDisclosure{}, ErrNotFound
+		}
+		out.Provider, out.Model = disclosedModel(analysis)
+		out.Analysis = &DisclosedAnalysis{
+			ID:            analysis.ID,
+			Status:        analysis.Status,
+			PromptVersion: analysis.PromptVersion,
+			ProcessedAt:   analysis.AnalysisCompletedAt,
+		}
+	}
+	if guest && settings.GuestRetention > 0 {
+		out.Retention.Policy = RetentionGuestExpiry
+		out.Retention.Days = int(settings.GuestRetention / (24 * time.Hour))
+	}
+	if settings.Archive != nil {
+		mode, retention, err := settings.Archive.PolicyFor(ctx, userID)
+		if err != nil {
+			return AIDisclosure{}, err
+		}
+		if mode != "" {
+			out.Retention.LLMArchiveMode = string(mode)
+		}
+		if mode != llmarchive.ModeOff {
+			out.Retention.LLMArchiveDays = int(retention / (24 * time.Hour))
+		}
+	}
+	return out, nil
+}
+
+// disclosedModel prefers the provenance stored with the result, which records
+// the model that actually answered, over the analysis's requested model.
+func disclosedModel(analysis Analysis) (string, string) {
+	provider := fallbackString(analysis.Provider, "unknown")
+	model := fallbackString(analysis.Model, "unknown")
+	raw, ok := storedProvenance(analysis.Result)
+	if !ok {
+		return provider, model
+	}
+	var p struct {
+		Provider string `json:"provider"`
+		Model    string `json:"model"`
+	}
+	switch v := raw.(type) {
+	case ProvenanceV1:
+		p.Provider, p.Model = v.Provider, v.Model
+	case map[string]any:
+		p.Provider, _ = v["provider"].(string)
+		p.Model, _ = v["model"].(string)
+	}
+	if p.Provider != "" && p.Provider != "unknown" {
+		provider = p.Provider
+	}
+	if p.Model != "" && p.Model != "unknown" {
+		model = p.Model
+	}
+	return provider, model
+}
+
+// aiDisclosure serves GET /meta/ai-disclosure, optionally for ?analysisId=.
+func (h *Handler) aiDisclosure(c *gin.Context) {
+	analysisID := strings.TrimSpace(c.Query("analysisId"))
+	disclosure, err := h.Svc.Disclosure(c.Request.Context(), middleware.UserIDFromContext(c), middleware.IsGuest(c), analysisID, h.Disclosure)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "analysis not found", nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to build disclosure", nil)
+		}
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	respond.JSON(c, http.StatusOK, disclosure)
+}
@@ -0,0 +1,104 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/llmarchive"
+)
+
+type stubArchivePolicy struct {
+	mode      llmarchive.Mode
+	retention time.Duration
+}
+
+func (s stubArchivePolicy) PolicyFor(ctx context.Context, ownerID string) (llmarchive.Mode, time.Duration, error) {
+	return s.mode, s.retention, nil
+}
+
+func TestAIDisclosureReportsTheAnalysisModel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, analysisRepo, _, _ := setupAnalysisRouter(t)
+	analysis := Analysis{
+		ID:       "analysis-disclosed",
+		UserID:   "guest:test-guest",
+		Status:   StatusCompleted,
+		Provider: "openai",
+		Model:    "gpt-5-mini",
+		Result: map[string]any{
+			"meta": map[string]any{"provenance": map[string]any{"provider": "openai", "model": "gpt-5-mini-2025-08-07"}},
+		},
+	}
+	if err := analysisRepo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/meta/ai-disclosure"+query, nil)
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("?analysisId=" + analysis.ID)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d body %s", resp.Code, resp.Body)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got["model"] != "gpt-5-mini-2025-08-07" || got["provider"] != "openai" {
+		t.Fatalf("expected the provenance model, got %v", got)
+	}
+	if training, ok := got["usedForTraining"].(bool); !ok || training {
+		t.Fatalf("usedForTraining = %v", got["usedForTraining"])
+	}
+	if a, _ := got["analysis"].(map[string]any); a["id"] != analysis.ID {
+		t.Fatalf("analysis = %v", got["analysis"])
+	}
+
+	if resp := get("?analysisId=missing"); resp.Code != http.StatusNotFound {
+		t.Fatalf("missing analysis: expected 404, got %d", resp.Code)
+	}
+	if resp := get(""); resp.Code != http.StatusOK {
+		t.Fatalf("general disclosure: status %d", resp.Code)
+	}
+}
+
+func TestDisclosureRetention(t *testing.T) {
+	ctx := context.Background()
+	svc := &Service{Repo: NewMemoryRepo(), Provider: "openai", Model: "gpt-5-mini"}
+	settings := DisclosureSettings{
+		GuestRetention: 14 * 24 * time.Hour,
+		Archive:        stubArchivePolicy{mode: llmarchive.ModeHashes, retention: 90 * 24 * time.Hour},
+	}
+
+	guest, err := svc.Disclosure(ctx, "guest:g1", true, "", settings)
+	if err != nil {
+		t.Fatalf("disclosure: %v", err)
+	}
+	want := DisclosureRetention{Policy: RetentionGuestExpiry, Days: 14, LLMArchiveMode: "hashes", LLMArchiveDays: 90}
+	if guest.Retention != want || guest.Model != "gpt-5-mini" || guest.Analysis != nil {
+		t.Fatalf("guest disclosure = %+v", guest)
+	}
+
+	settings.Archive = stubArchivePolicy{mode: llmarchive.ModeOff, retention: 90 * 24 * time.Hour}
+	user, _ := svc.Disclosure(ctx, "user-1", false, "", settings)
+	if user.Retention != (DisclosureRetention{Policy: RetentionUntilDeleted, LLMArchiveMode: "off"}) {
+		t.Fatalf("user retention = %+v", user.Retention)
+	}
+
+	_ = svc.Repo.Create(ctx, Analysis{ID: "a1", UserID: "user-2"})
+	if _, err := svc.Disclosure(ctx, "user-1", false, "a1", settings); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("other user's analysis: expected ErrNotFound, got %v", err)
+	}
+}
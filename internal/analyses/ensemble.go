@@ -0,0 +1,75 @@
+package analyses
+
+import (
+	"context"
+	"math"
+
+	"resume-backend/internal/featureflags"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// ensembleScoreKeys are the top-level result scores averaged across samples.
+var ensembleScoreKeys = []string{"finalScore", "matchScore"}
+
+// withEnsemble runs the pipeline a second time when ensemble scoring is on for
+// the analysis owner and replaces the result's scores with the mean of both
+// samples. Everything else in the result comes from the first sample. A failed
+// second sample leaves the result unchanged.
+func (s *Service) withEnsemble(ctx context.Context, pipeline Pipeline, run *PipelineRun, result map[string]any) {
+	if result == nil || !s.Flags.Enabled(ctx, featureflags.EnsembleScoring, featureflags.Target{UserID: run.Analysis.UserID}) {
+		return
+	}
+	sample := *run
+	second, err := s.generateSample(ctx, pipeline, &sample)
+	if err != nil {
+		telemetry.ErrorContext(ctx, "analysis.ensemble_failed", map[string]any{
+			"analysis_id": run.Analysis.ID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	first, _ := extractFinalScore(result, run.Analysis.Mode)
+	averageScores(result, second)
+	final, _ := extractFinalScore(result, run.Analysis.Mode)
+	secondScore, _ := extractFinalScore(second, run.Analysis.Mode)
+	result["ensemble"] = map[string]any{
+		"samples": 2,
+		"scores":  []float64{first, secondScore},
+	}
+	telemetry.InfoContext(ctx, "analysis.ensemble", map[string]any{
+		"analysis_id": run.Analysis.ID,
+		"scores":      []float64{first, secondScore},
+		"final_score": final,
+	})
+}
+
+func (s *Service) generateSample(ctx context.Context, pipeline Pipeline, run *PipelineRun) (map[string]any, error) {
+	raw, err := pipeline.Generate(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	normalize := pipeline.Normalize
+	if normalize == nil {
+		normalize = normalizeAnalysisResult
+	}
+	return normalize(raw, run.Analysis)
+}
+
+// averageScores sets each score present in both results to their rounded mean.
+func averageScores(result, other map[string]any) {
+	average := func(into, from map[string]any, key string) {
+		a, okA := extractFloatAny(into[key])
+		b, okB := extractFloatAny(from[key])
+		if okA && okB {
+			into[key] = math.Round((a + b) / 2)
+		}
+	}
+	for _, key := range ensembleScoreKeys {
+		average(result, other, key)
+	}
+	ats, okA := result["ats"].(map[string]any)
+	otherATS, okB := other["ats"].(map[string]any)
+	if okA && okB {
+		average(ats, otherATS, "score")
+	}
+}
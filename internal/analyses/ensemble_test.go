@@ -0,0 +1,75 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"resume-backend/internal/featureflags"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+type flaggedLLM struct {
+	response   []byte
+	mu         sync.Mutex
+	calls      int
+	structured int
+}
+
+func (r *flaggedLLM) AnalyzeResume(ctx context.Context, _ llm.AnalyzeInput) (json.RawMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if ctxmeta.StructuredOutput(ctx) {
+		r.structured++
+	}
+	return json.RawMessage(r.response), nil
+}
+
+func TestProcessAnalysisFlagsEnsembleAndStructuredOutput(t *testing.T) {
+	fixture := loadFixture(t, "testdata/v2_3_good.json")
+	_, analysisRepo, svc := setupAnalysisRouterWithLLM(t, fixture)
+	client := &flaggedLLM{response: fixture}
+	svc.LLM = client
+
+	ctx := context.Background()
+	userID := "guest:test-guest"
+	repo := featureflags.NewMemoryRepo()
+	svc.Flags = featureflags.NewService(repo, nil, &featureflags.RepoProvider{Repo: repo})
+	for _, flag := range []featureflags.Flag{featureflags.EnsembleScoring, featureflags.StructuredOutput} {
+		if _, err := svc.Flags.Set(ctx, "admin", featureflags.Rule{Key: flag.Key, Users: []string{userID}}); err != nil {
+			t.Fatalf("set %s: %v", flag.Key, err)
+		}
+	}
+	if err := analysisRepo.Create(ctx, Analysis{
+		ID: "a1", DocumentID: "doc-" + userID, UserID: userID, PromptVersion: "v2_3", Mode: ModeATS,
+		Status: StatusQueued, CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, "a1"); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	if client.calls != 2 || client.structured != 2 {
+		t.Fatalf("expected two structured-output samples, got %d calls, %d structured", client.calls, client.structured)
+	}
+	got, _ := analysisRepo.GetByID(ctx, "a1")
+	ensemble, ok := got.Result["ensemble"].(map[string]any)
+	if !ok || fmt.Sprint(ensemble["samples"]) != "2" {
+		t.Fatalf("expected ensemble metadata, got %v", got.Result["ensemble"])
+	}
+}
+
+func TestAverageScores(t *testing.T) {
+	result := map[string]any{"finalScore": 70.0, "matchScore": 60.0, "ats": map[string]any{"score": 70.0}}
+	other := map[string]any{"finalScore": 81.0, "ats": map[string]any{"score": 81.0}}
+	averageScores(result, other)
+	if result["finalScore"] != 76.0 || result["matchScore"] != 60.0 || result["ats"].(map[string]any)["score"] != 76.0 {
+		t.Fatalf("unexpected averaged scores: %v", result)
+	}
+}
@@ -6,12 +6,22 @@ var (
 	ErrNotFound              = errors.New("not found")
 	ErrRetryRequired         = errors.New("retry required")
 	ErrJobQueueNotConfigured = errors.New("job queue not configured")
+	// ErrExtractionPending means the extract stage has not finished the document yet;
+	// the analysis message should be retried later.
+	ErrExtractionPending = errors.New("document extraction pending")
+	// ErrLearningPlanRequiresJobMatch is returned when a learning plan is requested for another mode.
+	ErrLearningPlanRequiresJobMatch = errors.New("learning plan requires job match mode")
+	// ErrNotPermitted is returned by WorkerRepo for operations the worker role may not perform.
+	ErrNotPermitted = errors.New("operation not permitted for worker role")
 )
 
 const (
 	ErrorCodeValidation        = "VALIDATION_ERROR"
 	ErrorCodeLLMTimeout        = "LLM_TIMEOUT"
 	ErrorCodeLLMSchemaMismatch = "LLM_SCHEMA_MISMATCH"
+	ErrorCodeLLMUnavailable    = "LLM_UNAVAILABLE"
+	ErrorCodeStalled           = "PROCESSING_STALLED"
 	ErrorCodeStorage           = "STORAGE_ERROR"
+	ErrorCodeQueue             = "QUEUE_ERROR"
 	ErrorCodeInternal          = "INTERNAL_ERROR"
 )
@@ -0,0 +1,141 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// enqueue routes a new analysis to the extract stage when its document still needs
+// text extraction and that stage is configured, and to the analysis queue otherwise.
+func (s *Service) enqueue(ctx context.Context, analysis Analysis) error {
+	msg := queue.Message{
+		AnalysisID: analysis.ID,
+		RequestID:  ctxmeta.RequestID(ctx),
+		EnqueuedAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Version:    1,
+
+		SupportingDocumentIDs: supportingDocumentIDs(analysis.SupportingDocuments),
+	}
+	if s.needsExtraction(ctx, analysis) {
+		msg.Stage = queue.StageExtract
+		return s.ExtractQueue.Send(ctx, msg)
+	}
+	return s.JobQueue.Send(ctx, msg)
+}
+
+// needsExtraction reports whether the analysis must pass through the extract stage.
+func (s *Service) needsExtraction(ctx context.Context, analysis Analysis) bool {
+	if s.ExtractQueue == nil || s.DocRepo == nil {
+		return false
+	}
+	doc, err := s.DocRepo.GetByID(ctx, analysis.UserID, analysis.DocumentID)
+	// A missing document fails later in ProcessAnalysis with a clearer error.
+	return err == nil && doc.ExtractedTextKey == ""
+}
+
+// awaitExtraction holds back an analysis whose document the extract stage has not
+// finished, so the queue redelivers it instead of extracting here.
+func (s *Service) awaitExtraction(ctx context.Context, analysis Analysis) error {
+	if !s.needsExtraction(ctx, analysis) {
+		return nil
+	}
+	telemetry.InfoContext(ctx, "analysis.awaiting_extraction", map[string]any{
+		"analysis_id": analysis.ID,
+		"document_id": analysis.DocumentID,
+	})
+	return ErrExtractionPending
+}
+
+// ExtractForAnalysis runs the extract stage for an analysis: it extracts and stores
+// the document text, then publishes a document.extracted message to the analysis
+// queue. Documents that were already extracted skip straight to the event.
+func (s *Service) ExtractForAnalysis(ctx context.Context, analysisID string) error {
+	analysis, err := s.Repo.GetByID(ctx, analysisID)
+	if err != nil {
+		err = fmt.Errorf("analysis lookup: %w", err)
+		s.failAnalysis(ctx, analysisID, "", "", err, nil)
+		return err
+	}
+	if analysis.Status == StatusCompleted || analysis.Status == StatusFailed {
+		return nil
+	}
+	ctx = ctxmeta.WithDataOwner(ctx, analysis.UserID)
+	if s.DocRepo == nil || s.Store == nil {
+		err = errors.New("missing document store dependencies")
+		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, nil)
+		return err
+	}
+	if s.JobQueue == nil {
+		return ErrJobQueueNotConfigured
+	}
+
+	doc, err := s.DocRepo.GetByID(ctx, analysis.UserID, analysis.DocumentID)
+	if err != nil {
+		err = fmt.Errorf("document lookup id=%s: %w", analysis.DocumentID, err)
+		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, nil)
+		return err
+	}
+	if doc.ExtractedTextKey == "" {
+		startedAt := time.Now().UTC()
+		if _, err := s.resumeText(ctx, doc); err != nil {
+			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, nil)
+			return err
+		}
+		extractedAt := time.Now().UTC()
+		telemetry.InfoContext(ctx, "analysis.extracted", map[string]any{
+			"analysis_id": analysisID,
+			"document_id": doc.ID,
+			"duration_ms": durationMs(&startedAt, &extractedAt),
+		})
+	}
+
+	if err := s.JobQueue.Send(ctx, queue.Message{
+		AnalysisID: analysisID,
+		RequestID:  ctxmeta.RequestID(ctx),
+		EnqueuedAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Version:    1,
+		Stage:      queue.StageAnalysis,
+		Event:      queue.EventDocumentExtracted,
+
+		SupportingDocumentIDs: supportingDocumentIDs(analysis.SupportingDocuments),
+	}); err != nil {
+		// Leave the analysis queued; the extract message is redelivered and the
+		// document is already extracted, so the retry only resends the event.
+		return fmt.Errorf("publish extracted event: %w", err)
+	}
+	return nil
+}
+
+// ExtractDocument extracts and stores a document's text ahead of any analysis,
+// for uploads the worker learns about from S3 events. Recording the extraction
+// is what marks the document ready. Documents already extracted are left alone.
+func (s *Service) ExtractDocument(ctx context.Context, userID, documentID string) (documents.Document, error) {
+	if s.DocRepo == nil || s.Store == nil {
+		return documents.Document{}, errors.New("missing document store dependencies")
+	}
+	ctx = ctxmeta.WithDataOwner(ctx, userID)
+	doc, err := s.DocRepo.GetByID(ctx, userID, documentID)
+	if err != nil {
+		return documents.Document{}, fmt.Errorf("document lookup id=%s: %w", documentID, err)
+	}
+	if doc.ExtractedTextKey != "" {
+		return doc, nil
+	}
+	startedAt := time.Now().UTC()
+	if _, err := s.resumeText(ctx, doc); err != nil {
+		return documents.Document{}, err
+	}
+	extractedAt := time.Now().UTC()
+	telemetry.InfoContext(ctx, "document.extracted", map[string]any{
+		"document_id": doc.ID,
+		"duration_ms": durationMs(&startedAt, &extractedAt),
+	})
+	return s.DocRepo.GetByID(ctx, userID, documentID)
+}
@@ -0,0 +1,117 @@
+package analyses
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/extract"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// DefaultExtractionLease is how long an extraction claim lasts when
+// Service.ExtractionLease is unset. It covers a slow converter run with room
+// to spare; a crashed extractor delays the others by at most this long.
+const DefaultExtractionLease = 2 * time.Minute
+
+// extractionPollInterval is how often a caller waiting on another's
+// extraction checks whether it has finished.
+var extractionPollInterval = 250 * time.Millisecond
+
+// extractOnce extracts and stores the document's text, unless another caller
+// is already doing so. Then it waits for that extraction and reuses its key,
+// returning no text so the caller loads it. Repos without claims extract
+// straight away.
+func (s *Service) extractOnce(ctx context.Context, doc documents.Document, storageProvider string) (string, string, error) {
+	claimer, ok := s.DocRepo.(documents.ExtractionClaimer)
+	if !ok {
+		return s.extractAndStore(ctx, doc, storageProvider)
+	}
+	lease := s.ExtractionLease
+	if lease <= 0 {
+		lease = DefaultExtractionLease
+	}
+	claimID := uuid.NewString()
+	waiting := false
+	for {
+		now := time.Now().UTC()
+		claimed, err := claimer.ClaimExtraction(ctx, doc.UserID, doc.ID, claimID, now, now.Add(lease))
+		if err != nil {
+			return "", "", fmt.Errorf("document %s: claim extraction: %w", doc.ID, err)
+		}
+		if claimed {
+			defer s.releaseExtraction(ctx, claimer, doc, claimID)
+			return s.extractAndStore(ctx, doc, storageProvider)
+		}
+
+		current, err := s.DocRepo.GetByID(ctx, doc.UserID, doc.ID)
+		if err != nil {
+			return "", "", fmt.Errorf("document %s: lookup during extraction: %w", doc.ID, err)
+		}
+		if current.ExtractedTextKey != "" {
+			return "", current.ExtractedTextKey, nil
+		}
+		if !waiting {
+			waiting = true
+			telemetry.InfoContext(ctx, "analysis.extraction_waiting", map[string]any{"document_id": doc.ID})
+		}
+		timer := time.NewTimer(extractionPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// releaseExtraction ends the claim even when ctx is done. A failed release is
+// only logged: the claim lapses on its own.
+func (s *Service) releaseExtraction(ctx context.Context, claimer documents.ExtractionClaimer, doc documents.Document, claimID string) {
+	if err := claimer.ReleaseExtraction(context.WithoutCancel(ctx), doc.UserID, doc.ID, claimID); err != nil {
+		telemetry.ErrorContext(ctx, "analysis.extraction_release_failed", map[string]any{
+			"document_id": doc.ID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// extractAndStore extracts the document's text, saves it next to the upload
+// and records the extraction. The local store path returns no text; it is
+// read back from the store.
+func (s *Service) extractAndStore(ctx context.Context, doc documents.Document, storageProvider string) (string, string, error) {
+	extractedKey := doc.StorageKey + ".extracted.txt"
+	switch storageProvider {
+	case "s3":
+		s3Client, err := s.s3Documents(ctx)
+		if err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: s3 client: %w", doc.ID, doc.MimeType, err)
+		}
+		raw, err := s3Client.GetObjectBytes(ctx, doc.StorageKey)
+		if err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: s3 read: %w", doc.ID, doc.MimeType, err)
+		}
+		extracted, err := extract.ExtractTextFromBytes(ctx, raw, doc.ExtractionMimeType(), doc.FileName)
+		if err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: %w", doc.ID, doc.MimeType, err)
+		}
+		if err := s3Client.PutText(ctx, extractedKey, extracted); err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: store extracted: %w", doc.ID, doc.MimeType, err)
+		}
+		if err := s.DocRepo.UpdateExtraction(ctx, doc.UserID, doc.ID, extractedKey, time.Now().UTC()); err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: update extraction: %w", doc.ID, doc.MimeType, err)
+		}
+		return extracted, extractedKey, nil
+	default:
+		if _, err := extract.ExtractText(ctx, s.Store, doc.StorageKey, doc.ExtractionMimeType(), doc.FileName); err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: %w", doc.ID, doc.MimeType, err)
+		}
+		if err := s.DocRepo.UpdateExtraction(ctx, doc.UserID, doc.ID, extractedKey, time.Now().UTC()); err != nil {
+			return "", "", fmt.Errorf("document %s mime %s: update extraction: %w", doc.ID, doc.MimeType, err)
+		}
+		return "", extractedKey, nil
+	}
+}
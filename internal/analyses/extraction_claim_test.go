@@ -0,0 +1,104 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+// countingStore counts writes of extracted text.
+type countingStore struct {
+	object.ObjectStore
+	saves atomic.Int32
+}
+
+func (s *countingStore) SaveWithKey(ctx context.Context, storageKey string, contentType string, r io.Reader) (int64, error) {
+	s.saves.Add(1)
+	// Widen the window in which a second extraction could start.
+	time.Sleep(20 * time.Millisecond)
+	return s.ObjectStore.(interface {
+		SaveWithKey(context.Context, string, string, io.Reader) (int64, error)
+	}).SaveWithKey(ctx, storageKey, contentType, r)
+}
+
+func TestConcurrentAnalysesExtractADocumentOnce(t *testing.T) {
+	ctx := context.Background()
+	prev := extractionPollInterval
+	extractionPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { extractionPollInterval = prev })
+
+	store := &countingStore{ObjectStore: local.New(t.TempDir())}
+	storageKey, _, _, err := store.Save(ctx, "user-1", "resume.docx", bytes.NewReader(minimalDOCX(t, "Led the payments team.")))
+	if err != nil {
+		t.Fatalf("save resume: %v", err)
+	}
+	docRepo := documents.NewMemoryRepo()
+	doc := documents.Document{
+		ID:         "doc-1",
+		UserID:     "user-1",
+		FileName:   "resume.docx",
+		MimeType:   "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		StorageKey: storageKey,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := docRepo.Create(ctx, doc); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+	svc := &Service{Repo: NewMemoryRepo(), DocRepo: docRepo, Store: store}
+
+	var wg sync.WaitGroup
+	texts := make([]string, 4)
+	errs := make([]error, 4)
+	for i := range texts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			texts[i], errs[i] = svc.resumeText(ctx, doc)
+		}()
+	}
+	wg.Wait()
+	for i := range texts {
+		if errs[i] != nil || !bytes.Contains([]byte(texts[i]), []byte("payments team")) {
+			t.Fatalf("caller %d: text %q err %v", i, texts[i], errs[i])
+		}
+	}
+	if saves := store.saves.Load(); saves != 1 {
+		t.Fatalf("expected one extraction, got %d", saves)
+	}
+}
+
+func TestExtractionClaimLapses(t *testing.T) {
+	ctx := context.Background()
+	repo := documents.NewMemoryRepo()
+	if err := repo.Create(ctx, documents.Document{ID: "doc-1", UserID: "user-1"}); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+	now := time.Now().UTC()
+	if ok, _ := repo.ClaimExtraction(ctx, "user-1", "doc-1", "a", now, now.Add(time.Minute)); !ok {
+		t.Fatal("first claim should win")
+	}
+	if ok, _ := repo.ClaimExtraction(ctx, "user-1", "doc-1", "b", now, now.Add(time.Minute)); ok {
+		t.Fatal("a live claim blocks others")
+	}
+	if ok, _ := repo.ClaimExtraction(ctx, "user-1", "doc-1", "b", now.Add(time.Minute), now.Add(2*time.Minute)); !ok {
+		t.Fatal("a lapsed claim can be taken over")
+	}
+	// A stale release by the first claimant leaves the takeover in place.
+	_ = repo.ReleaseExtraction(ctx, "user-1", "doc-1", "a")
+	if ok, _ := repo.ClaimExtraction(ctx, "user-1", "doc-1", "c", now.Add(time.Minute), now.Add(2*time.Minute)); ok {
+		t.Fatal("only the holder's release ends a claim")
+	}
+	_ = repo.UpdateExtraction(ctx, "user-1", "doc-1", "key", now)
+	_ = repo.ReleaseExtraction(ctx, "user-1", "doc-1", "b")
+	if ok, _ := repo.ClaimExtraction(ctx, "user-1", "doc-1", "c", now, now.Add(time.Minute)); ok {
+		t.Fatal("an extracted document cannot be claimed")
+	}
+}
@@ -0,0 +1,183 @@
+package analyses
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func TestExtractStageRunsBeforeAnalysis(t *testing.T) {
+	ctx := context.Background()
+	store := local.New(t.TempDir())
+	storageKey, _, _, err := store.Save(ctx, "user-1", "resume.docx", bytes.NewReader(minimalDOCX(t, "Led the payments team.")))
+	if err != nil {
+		t.Fatalf("save resume: %v", err)
+	}
+	docRepo := documents.NewMemoryRepo()
+	if err := docRepo.Create(ctx, documents.Document{
+		ID:         "doc-1",
+		UserID:     "user-1",
+		FileName:   "resume.docx",
+		MimeType:   "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		StorageKey: storageKey,
+		CreatedAt:  time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+
+	analysisQueue, extractQueue := &stubQueue{}, &stubQueue{}
+	repo := NewMemoryRepo()
+	svc := &Service{
+		Repo:         repo,
+		DocRepo:      docRepo,
+		Store:        store,
+		LLM:          staticLLMResponse{resp: "{not-json"},
+		JobQueue:     analysisQueue,
+		ExtractQueue: extractQueue,
+	}
+
+	analysis, _, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", "jd", "v1", ModeJobMatch, false, StartOptions{})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if len(extractQueue.messages) != 1 || len(analysisQueue.messages) != 0 || extractQueue.messages[0].Stage != queue.StageExtract {
+		t.Fatalf("expected one extract message, got extract=%+v analysis=%+v", extractQueue.messages, analysisQueue.messages)
+	}
+
+	// An analysis message that overtakes extraction waits instead of failing.
+	if err := svc.ProcessAnalysis(ctx, analysis.ID); !errors.Is(err, ErrExtractionPending) {
+		t.Fatalf("expected ErrExtractionPending, got %v", err)
+	}
+	if got, _ := repo.GetByID(ctx, analysis.ID); got.Status != StatusQueued {
+		t.Fatalf("expected analysis to stay queued, got %s", got.Status)
+	}
+
+	if err := svc.ExtractForAnalysis(ctx, analysis.ID); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	doc, _ := docRepo.GetByID(ctx, "user-1", "doc-1")
+	if doc.ExtractedTextKey == "" {
+		t.Fatalf("expected extracted text key to be stored")
+	}
+	if len(analysisQueue.messages) != 1 || analysisQueue.messages[0].Event != queue.EventDocumentExtracted {
+		t.Fatalf("expected extracted event on analysis queue, got %+v", analysisQueue.messages)
+	}
+
+	// Past extraction, processing proceeds; the stub LLM output is invalid so it fails there.
+	_ = svc.ProcessAnalysis(ctx, analysis.ID)
+	got, _ := repo.GetByID(ctx, analysis.ID)
+	if got.Status != StatusFailed || got.ErrorCode != ErrorCodeLLMSchemaMismatch {
+		t.Fatalf("expected analysis to reach the LLM step, got status=%s code=%v", got.Status, got.ErrorCode)
+	}
+
+	// Later analyses of the extracted document skip the extract stage.
+	if _, _, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", "jd", "v1", ModeJobMatch, false, StartOptions{ForceNew: true}); err != nil {
+		t.Fatalf("start again: %v", err)
+	}
+	if len(extractQueue.messages) != 1 || len(analysisQueue.messages) != 2 {
+		t.Fatalf("expected direct analysis enqueue, got extract=%d analysis=%d", len(extractQueue.messages), len(analysisQueue.messages))
+	}
+}
+
+type memoryS3 map[string][]byte
+
+func (m memoryS3) GetObjectBytes(ctx context.Context, key string) ([]byte, error) {
+	data, ok := m[key]
+	if !ok {
+		return nil, errors.New("no such key")
+	}
+	return data, nil
+}
+
+func (m memoryS3) PutText(ctx context.Context, key string, text string) error {
+	m[key] = []byte(text)
+	return nil
+}
+
+func TestExtractDocumentMarksS3UploadReady(t *testing.T) {
+	ctx := context.Background()
+	objects := memoryS3{"documents/u1/doc-1/f-resume.docx": minimalDOCX(t, "Shipped the billing rewrite.")}
+	docRepo := documents.NewMemoryRepo()
+	if err := docRepo.Create(ctx, documents.Document{
+		ID:              "doc-1",
+		UserID:          "u1",
+		FileName:        "resume.docx",
+		MimeType:        "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		StorageProvider: "s3",
+		StorageKey:      "documents/u1/doc-1/f-resume.docx",
+		CreatedAt:       time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+	svc := &Service{DocRepo: docRepo, Store: local.New(t.TempDir()), S3Docs: objects}
+
+	doc, err := svc.ExtractDocument(ctx, "u1", "doc-1")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if doc.Status() != documents.StatusReady || doc.ExtractedTextKey == "" {
+		t.Fatalf("expected a ready document, got %+v", doc)
+	}
+	if text := string(objects[doc.ExtractedTextKey]); text == "" {
+		t.Fatalf("expected extracted text at %s", doc.ExtractedTextKey)
+	}
+
+	// A second call, as for a redelivered event, does not extract again.
+	delete(objects, "documents/u1/doc-1/f-resume.docx")
+	if _, err := svc.ExtractDocument(ctx, "u1", "doc-1"); err != nil {
+		t.Fatalf("extract again: %v", err)
+	}
+}
+
+func minimalDOCX(t *testing.T, text string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("create docx entry: %v", err)
+	}
+	if _, err := w.Write([]byte(`<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:body></w:document>`)); err != nil {
+		t.Fatalf("write docx entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close docx: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestResumeTextFallsBackToTheOtherStore(t *testing.T) {
+	ctx := context.Background()
+	store := local.New(t.TempDir())
+	localKey, _, _, err := store.Save(ctx, "u1", "resume.docx", bytes.NewReader(minimalDOCX(t, "Led the local migration.")))
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	objects := memoryS3{documents.LegacyKeyPrefix + "u1/moved.docx.extracted.txt": []byte("Moved to S3.")}
+	svc := &Service{DocRepo: documents.NewMemoryRepo(), Store: store, S3Docs: objects}
+
+	// A legacy document already copied to S3 while its row still says local.
+	moved := documents.Document{ID: "doc-1", UserID: "u1", FileName: "moved.docx", StorageProvider: "local", StorageKey: "u1/moved.docx", ExtractedTextKey: "u1/moved.docx.extracted.txt"}
+	if _, err := svc.resumeText(ctx, moved); err == nil {
+		t.Fatal("expected a read error without the fallback")
+	}
+	svc.StorageFallback = true
+	if text, err := svc.resumeText(ctx, moved); err != nil || text != "Moved to S3." {
+		t.Fatalf("fallback to s3 = %q, %v", text, err)
+	}
+
+	// A document already pointed at S3 whose copy has not landed yet.
+	pending := documents.Document{ID: "doc-2", UserID: "u1", FileName: "resume.docx", StorageProvider: "s3", StorageKey: documents.LegacyKeyPrefix + localKey}
+	text, err := svc.resumeText(ctx, pending)
+	if err != nil || !strings.Contains(text, "Led the local migration.") {
+		t.Fatalf("fallback to local = %q, %v", text, err)
+	}
+}
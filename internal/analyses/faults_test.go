@@ -0,0 +1,126 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"resume-backend/internal/shared/faults"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func assertNotStuck(t *testing.T, repo *MemoryRepo, userID string) {
+	t.Helper()
+	items, err := repo.ListByUser(context.Background(), userID, 50, 0)
+	if err != nil {
+		t.Fatalf("list analyses: %v", err)
+	}
+	for _, item := range items {
+		if item.Status == StatusQueued || item.Status == StatusProcessing {
+			t.Fatalf("analysis %s left in %s after injected fault", item.ID, item.Status)
+		}
+	}
+}
+
+func runInjectedAnalysis(t *testing.T, svc *Service, repo *MemoryRepo, docID string) Analysis {
+	t.Helper()
+	analysis := Analysis{
+		ID:             "analysis-fault",
+		DocumentID:     docID,
+		UserID:         "user-1",
+		JobDescription: "jd",
+		PromptVersion:  "v1",
+		Status:         StatusQueued,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := repo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(context.Background(), analysis.ID); err == nil {
+		t.Fatalf("expected processing error under injected fault")
+	}
+	got, err := repo.GetByID(context.Background(), analysis.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	return got
+}
+
+func TestInjectedStorageFaultIsRetryable(t *testing.T) {
+	store := faults.WrapStore(local.New(t.TempDir()), faults.Config{ErrorRate: 1})
+	svc, repo, _, docID := setupServiceWithDocAndStore(t, stubLLM{}, store, "extracted-key")
+
+	got := runInjectedAnalysis(t, svc, repo, docID)
+	if got.Status != StatusFailed || got.ErrorCode != ErrorCodeStorage || !got.ErrorRetryable {
+		t.Fatalf("expected retryable storage failure, got status=%s code=%s retryable=%v", got.Status, got.ErrorCode, got.ErrorRetryable)
+	}
+	assertNotStuck(t, repo, "user-1")
+}
+
+func TestInjectedLLMFaultIsRetryable(t *testing.T) {
+	llmClient := faults.WrapLLM(stubLLM{}, faults.Config{ErrorRate: 1})
+	svc, repo, _, docID := setupServiceWithDoc(t, llmClient)
+
+	got := runInjectedAnalysis(t, svc, repo, docID)
+	if got.Status != StatusFailed || got.ErrorCode != ErrorCodeLLMTimeout || !got.ErrorRetryable {
+		t.Fatalf("expected retryable llm timeout, got status=%s code=%s retryable=%v", got.Status, got.ErrorCode, got.ErrorRetryable)
+	}
+	assertNotStuck(t, repo, "user-1")
+}
+
+func TestInjectedQueueFaultFailsAnalysisAndAllowsRetry(t *testing.T) {
+	svc, repo, _, docID := setupServiceWithDoc(t, stubLLM{})
+	base := &stubQueue{}
+	svc.JobQueue = faults.WrapQueue(base, faults.Config{ErrorRate: 1})
+
+	failed, created, err := svc.StartOrReuse(context.Background(), docID, "user-1", "jd", "v1", ModeJobMatch, false)
+	if !errors.Is(err, faults.ErrInjected) || !created {
+		t.Fatalf("expected injected enqueue error on a new analysis, got created=%v err=%v", created, err)
+	}
+	got, err := repo.GetByID(context.Background(), failed.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if got.Status != StatusFailed || got.ErrorCode != ErrorCodeQueue || !got.ErrorRetryable {
+		t.Fatalf("expected retryable queue failure, got status=%s code=%s retryable=%v", got.Status, got.ErrorCode, got.ErrorRetryable)
+	}
+	assertNotStuck(t, repo, "user-1")
+
+	svc.JobQueue = base
+	retried, created, err := svc.StartOrReuse(context.Background(), docID, "user-1", "jd", "v1", ModeJobMatch, true)
+	if err != nil || !created || retried.ID == failed.ID {
+		t.Fatalf("expected a fresh analysis on retry, got created=%v id=%s err=%v", created, retried.ID, err)
+	}
+	if len(base.messages) != 1 {
+		t.Fatalf("expected retry to enqueue once, got %d", len(base.messages))
+	}
+}
+
+func TestInjectedLatencyWithinDeadlineCompletes(t *testing.T) {
+	svc, repo, _, docID := setupServiceWithDoc(t, faults.WrapLLM(stubLLM{}, faults.Config{Latency: 5 * time.Millisecond}))
+	svc.Store = faults.WrapStore(svc.Store, faults.Config{Latency: 5 * time.Millisecond})
+
+	analysis := Analysis{
+		ID:             "analysis-latency",
+		DocumentID:     docID,
+		UserID:         "user-1",
+		JobDescription: "jd",
+		PromptVersion:  "v1",
+		Status:         StatusQueued,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := repo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(context.Background(), analysis.ID); err != nil {
+		t.Fatalf("expected completion despite latency, got %v", err)
+	}
+	got, err := repo.GetByID(context.Background(), analysis.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if got.Status != StatusCompleted {
+		t.Fatalf("expected completed, got %s", got.Status)
+	}
+}
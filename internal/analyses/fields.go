@@ -0,0 +1,124 @@
+package analyses
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxSelectedFields caps the paths one fields parameter may list.
+	maxSelectedFields = 50
+	// maxFieldDepth caps the segments of one dotted path.
+	maxFieldDepth = 8
+)
+
+// identityFields are kept in every projected response so clients can match it
+// to the analysis they asked for.
+var identityFields = []string{"id", "analysisId"}
+
+// parseFieldSelection reads the fields and include query parameters: comma
+// separated dotted paths such as status,finalScore,ats.score. Both may repeat
+// and are merged. It returns nil when neither is set.
+func parseFieldSelection(c *gin.Context) ([]string, error) {
+	var raw []string
+	raw = append(raw, c.QueryArray("fields")...)
+	raw = append(raw, c.QueryArray("include")...)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	seen := map[string]bool{}
+	var fields []string
+	for _, value := range raw {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" || seen[field] {
+				continue
+			}
+			segments := strings.Split(field, ".")
+			if len(segments) > maxFieldDepth {
+				return nil, fmt.Errorf("field %q is nested deeper than %d levels", field, maxFieldDepth)
+			}
+			for _, segment := range segments {
+				if segment == "" {
+					return nil, fmt.Errorf("field %q is not a valid path", field)
+				}
+			}
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields must list at least one field")
+	}
+	if len(fields) > maxSelectedFields {
+		return nil, fmt.Errorf("fields must list at most %d fields", maxSelectedFields)
+	}
+	return fields, nil
+}
+
+// selectFields projects a response down to the requested paths. A path whose
+// first segment is a key of base is taken from base; any other path is looked
+// up in result, and may be written with or without a leading "result.". Result
+// values stay nested under "result", so a projected response has the same
+// shape as the full one. Paths that match nothing are left out.
+func selectFields(base gin.H, result map[string]any, fields []string) gin.H {
+	out := gin.H{}
+	for _, key := range identityFields {
+		if v, ok := base[key]; ok {
+			out[key] = v
+		}
+	}
+	projected := map[string]any{}
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		if _, ok := base[path[0]]; ok && path[0] != "result" {
+			copyPath(out, base, path)
+			continue
+		}
+		if path[0] == "result" {
+			path = path[1:]
+		}
+		if len(path) == 0 {
+			if result != nil {
+				out["result"] = result
+				projected = nil
+			}
+			continue
+		}
+		if projected != nil {
+			copyPath(projected, result, path)
+		}
+	}
+	if len(projected) > 0 {
+		out["result"] = projected
+	}
+	return out
+}
+
+// copyPath copies the value at path in src into dst, creating the enclosing
+// maps. Missing paths, and paths through values that are not objects, are
+// skipped.
+func copyPath(dst, src map[string]any, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+	next, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	child, ok := dst[path[0]].(map[string]any)
+	if !ok {
+		child = map[string]any{}
+	}
+	copyPath(child, next, path[1:])
+	if len(child) > 0 {
+		dst[path[0]] = child
+	}
+}
@@ -0,0 +1,123 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSelectFieldsKeepsShape(t *testing.T) {
+	base := gin.H{"id": "a1", "status": StatusCompleted, "mode": ModeJobMatch, "finalScore": 70.0}
+	result := annotatedFixture()
+
+	got := selectFields(base, result, []string{"status", "finalScore", "ats.score", "result.issues", "missing.path", "ats.score.deeper"})
+	want := gin.H{
+		"id":         "a1",
+		"status":     StatusCompleted,
+		"finalScore": 70.0,
+		"result": map[string]any{
+			"ats":    map[string]any{"score": 70.0},
+			"issues": result["issues"],
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected projection:\n got %#v\nwant %#v", got, want)
+	}
+
+	if got := selectFields(base, nil, []string{"ats.score"}); !reflect.DeepEqual(got, gin.H{"id": "a1"}) {
+		t.Fatalf("expected only the id without a result, got %#v", got)
+	}
+}
+
+func TestGetAnalysisProjectsSelectedFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, analysisRepo, _, _ := setupAnalysisRouter(t)
+	analysis := Analysis{
+		ID:         "analysis-fields",
+		DocumentID: "doc-1",
+		UserID:     "guest:test-guest",
+		Mode:       ModeATS,
+		Status:     StatusCompleted,
+		Result:     annotatedFixture(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := analysisRepo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analyses/"+analysis.ID+query, nil)
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("?fields=status,finalScore&include=ats.score")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := map[string]any{
+		"id":         analysis.ID,
+		"status":     string(StatusCompleted),
+		"finalScore": 70.0,
+		"result":     map[string]any{"ats": map[string]any{"score": 70.0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected body:\n got %#v\nwant %#v", got, want)
+	}
+
+	if resp := get("?fields=ats..score"); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid path, got %d", resp.Code)
+	}
+}
+
+func TestGetAnalysisRevalidatesWithETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, analysisRepo, _, _ := setupAnalysisRouter(t)
+	analysis := Analysis{
+		ID:         "analysis-etag",
+		DocumentID: "doc-1",
+		UserID:     "guest:test-guest",
+		Status:     StatusCompleted,
+		Result:     annotatedFixture(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := analysisRepo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	get := func(query, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analyses/"+analysis.ID+query, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", first.Code, etag)
+	}
+	if resp := get("", "W/"+etag); resp.Code != http.StatusNotModified || resp.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d", resp.Code)
+	}
+	// A projection is a different representation with its own tag.
+	if resp := get("?fields=status", etag); resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a projection, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,208 @@
+package analyses
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"resume-backend/internal/shared/language"
+)
+
+const (
+	englishFigurePlaceholder = "X% (replace with exact figure)"
+	englishNeutralImpact     = "measurable"
+)
+
+// localizedGuardrail holds the content guardrail for one non-English document
+// language. Figure terms claim a size without a number and are rewritten to
+// the figure placeholder; vague terms are rewritten to the neutral adjective.
+type localizedGuardrail struct {
+	figureTerms       []string
+	vagueTerms        []string
+	figurePlaceholder string
+	neutral           string
+	repairMessage     string
+}
+
+// localizedGuardrails are keyed by the codes language.Detect returns. Repair
+// messages keep schema field names and values in English because the schema
+// does not change with the document language.
+var localizedGuardrails = map[string]localizedGuardrail{
+	"es": {
+		figureTerms:       []string{"de dos dígitos", "doble dígito"},
+		vagueTerms:        []string{"significativo", "significativa", "significativos", "significativas", "sustancial", "sustanciales", "masivo", "masiva", "masivos", "masivas", "notable", "notables"},
+		figurePlaceholder: "X% (sustituir por la cifra exacta)",
+		neutral:           "medible",
+		repairMessage:     "Elimina cualquier afirmación de impacto sin respaldo (p. ej., de dos dígitos, significativo) salvo que aparezca explícitamente en el currículum. Si falta un valor exacto, usa el marcador \"X% (sustituir por la cifra exacta)\", establece claimSupport=placeholder, metricsSource=placeholder y añade placeholdersNeeded (p. ej., revenue_growth_pct). Mantén los textos en español. Devuelve solo JSON.",
+	},
+	"fr": {
+		figureTerms:       []string{"à deux chiffres"},
+		vagueTerms:        []string{"significatif", "significative", "significatifs", "significatives", "substantiel", "substantielle", "substantiels", "substantielles", "massif", "massive", "massifs", "massives", "remarquable", "remarquables"},
+		figurePlaceholder: "X % (remplacer par le chiffre exact)",
+		neutral:           "mesurable",
+		repairMessage:     "Supprime toute affirmation d'impact non étayée (par ex. à deux chiffres, significatif) sauf si elle figure explicitement dans le CV. Si une valeur exacte manque, utilise le marqueur \"X % (remplacer par le chiffre exact)\", définis claimSupport=placeholder, metricsSource=placeholder et ajoute placeholdersNeeded (par ex. revenue_growth_pct). Garde les textes en français. Réponds uniquement en JSON.",
+	},
+	"de": {
+		figureTerms:       germanForms("zweistellig"),
+		vagueTerms:        germanForms("signifikant", "erheblich", "substanziell", "massiv", "bemerkenswert"),
+		figurePlaceholder: "X % (durch die genaue Zahl ersetzen)",
+		neutral:           "messbar",
+		repairMessage:     "Entferne alle nicht belegten Wirkungsangaben (z. B. zweistellig, signifikant), sofern sie nicht ausdrücklich im Lebenslauf stehen. Fehlt ein genauer Wert, verwende den Platzhalter \"X % (durch die genaue Zahl ersetzen)\", setze claimSupport=placeholder, metricsSource=placeholder und ergänze placeholdersNeeded (z. B. revenue_growth_pct). Behalte die Texte auf Deutsch. Antworte nur mit JSON.",
+	},
+	"pt": {
+		figureTerms:       []string{"de dois dígitos"},
+		vagueTerms:        []string{"significativo", "significativa", "significativos", "significativas", "substancial", "substanciais", "massivo", "massiva", "massivos", "massivas", "notável", "notáveis"},
+		figurePlaceholder: "X% (substituir pelo valor exato)",
+		neutral:           "mensurável",
+		repairMessage:     "Remova qualquer afirmação de impacto sem respaldo (por ex., de dois dígitos, significativo), a menos que esteja explícita no currículo. Se faltar um valor exato, use o marcador \"X% (substituir pelo valor exato)\", defina claimSupport=placeholder, metricsSource=placeholder e adicione placeholdersNeeded (por ex., revenue_growth_pct). Mantenha os textos em português. Responda apenas com JSON.",
+	},
+	"it": {
+		figureTerms:       []string{"a due cifre"},
+		vagueTerms:        []string{"significativo", "significativa", "significativi", "significative", "sostanziale", "sostanziali", "massiccio", "massiccia", "massicci", "massicce", "notevole", "notevoli"},
+		figurePlaceholder: "X% (sostituire con la cifra esatta)",
+		neutral:           "misurabile",
+		repairMessage:     "Rimuovi qualsiasi affermazione di impatto non supportata (ad es. a due cifre, significativo) a meno che non compaia esplicitamente nel curriculum. Se manca un valore esatto, usa il segnaposto \"X% (sostituire con la cifra esatta)\", imposta claimSupport=placeholder, metricsSource=placeholder e aggiungi placeholdersNeeded (ad es. revenue_growth_pct). Mantieni i testi in italiano. Rispondi solo con JSON.",
+	},
+	"nl": {
+		figureTerms:       []string{"dubbelcijferig", "dubbelcijferige", "dubbele cijfers"},
+		vagueTerms:        []string{"significant", "significante", "aanzienlijk", "aanzienlijke", "substantieel", "substantiële", "enorm", "enorme", "opmerkelijk", "opmerkelijke"},
+		figurePlaceholder: "X% (vervangen door het exacte cijfer)",
+		neutral:           "meetbaar",
+		repairMessage:     "Verwijder alle niet-onderbouwde impactclaims (bijv. dubbelcijferig, significant) tenzij ze letterlijk in het cv staan. Ontbreekt een exacte waarde, gebruik dan de placeholder \"X% (vervangen door het exacte cijfer)\", zet claimSupport=placeholder, metricsSource=placeholder en voeg placeholdersNeeded toe (bijv. revenue_growth_pct). Houd de teksten in het Nederlands. Antwoord alleen met JSON.",
+	},
+}
+
+// germanForms lists each adjective with its inflected endings, since terms
+// match whole words only.
+func germanForms(stems ...string) []string {
+	var out []string
+	for _, stem := range stems {
+		for _, ending := range []string{"", "e", "em", "en", "er", "es"} {
+			out = append(out, stem+ending)
+		}
+	}
+	return out
+}
+
+// contentGuardrail is the forbidden impact term check for one document
+// language.
+type contentGuardrail struct {
+	language string
+	// terms are matched as whole words against normalized text; figure
+	// terms are the ones replaced with figurePlaceholder.
+	terms             []string
+	figure            map[string]bool
+	figurePlaceholder string
+	neutral           string
+	repairMessage     string
+}
+
+// guardrailForText picks the guardrail for the language text is written in.
+func guardrailForText(text string) contentGuardrail {
+	return guardrailFor(language.Detect(text))
+}
+
+// guardrailFor returns the guardrail for a language code. Every language
+// checks the English list, which SetForbiddenImpactTerms controls, since
+// models sometimes answer in English; languages with their own list add it and
+// use their own replacements and repair message. Others get English alone.
+// An empty English list turns the guardrail off for every language.
+func guardrailFor(lang string) contentGuardrail {
+	g := contentGuardrail{
+		language:          language.English,
+		terms:             currentForbiddenImpactTerms(),
+		figure:            map[string]bool{},
+		figurePlaceholder: englishFigurePlaceholder,
+		neutral:           englishNeutralImpact,
+		repairMessage:     contentRepairSystemMessage,
+	}
+	for _, term := range g.terms {
+		if strings.HasPrefix(term, "double") || strings.HasPrefix(term, "triple") {
+			g.figure[term] = true
+		}
+	}
+	local, ok := localizedGuardrails[lang]
+	if !ok || len(g.terms) == 0 {
+		return g
+	}
+	g.language = lang
+	g.terms = append(append(append([]string(nil), g.terms...), local.figureTerms...), local.vagueTerms...)
+	for _, term := range local.figureTerms {
+		g.figure[term] = true
+	}
+	g.figurePlaceholder = local.figurePlaceholder
+	g.neutral = local.neutral
+	g.repairMessage = local.repairMessage
+	return g
+}
+
+// find reports the first guardrail term text contains as a whole word.
+func (g contentGuardrail) find(text string) (string, bool) {
+	lower := normalizeForMatch(text)
+	for _, term := range g.terms {
+		if indexWord(lower, term) >= 0 {
+			return term, true
+		}
+	}
+	return "", false
+}
+
+// replacement is what a forbidden term is rewritten to: a figure placeholder
+// for numeric claims, otherwise a neutral adjective.
+func (g contentGuardrail) replacement(term string) string {
+	if g.figure[term] {
+		return g.figurePlaceholder
+	}
+	return g.neutral
+}
+
+// indexWord returns where term first appears in text as a whole word, or -1.
+func indexWord(text, term string) int {
+	if term == "" {
+		return -1
+	}
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return -1
+		}
+		start := offset + i
+		if atWordBoundary(text, start, start+len(term)) {
+			return start
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return -1
+}
+
+// replaceWord replaces the whole-word occurrences of term in text.
+func replaceWord(text, term, replacement string) string {
+	var b strings.Builder
+	for {
+		i := indexWord(text, term)
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:i])
+		b.WriteString(replacement)
+		text = text[i+len(term):]
+	}
+}
+
+// atWordBoundary reports whether text[start:end] has no letter or digit
+// directly before or after it.
+func atWordBoundary(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,107 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+var spanishResume = strings.Repeat("Lideré la migración de la plataforma de pagos y reduje la latencia del sistema con un equipo de cinco personas para los clientes. ", 4)
+
+// repairRecordingLLM returns first, then second, recording the extra system
+// message each call carried.
+type repairRecordingLLM struct {
+	first, second json.RawMessage
+	messages      []string
+}
+
+func (r *repairRecordingLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	message, _ := ctxmeta.ExtraSystemMessage(ctx)
+	r.messages = append(r.messages, message)
+	if len(r.messages) == 1 {
+		return r.first, nil
+	}
+	return r.second, nil
+}
+
+func withBulletAfter(t *testing.T, after string) json.RawMessage {
+	t.Helper()
+	var doc map[string]any
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &doc); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	rewrite := doc["bulletRewrites"].([]any)[0].(map[string]any)
+	rewrite["after"] = after
+	rewrite["metricsSource"] = "resume"
+	rewrite["claimSupport"] = "supported"
+	rewrite["evidence"] = "Aumenté las ventas."
+	rewrite["placeholdersNeeded"] = []string{}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("encode fixture: %v", err)
+	}
+	return raw
+}
+
+func TestGuardrailForTextPicksDocumentLanguage(t *testing.T) {
+	if g := guardrailForText(spanishResume); g.language != "es" {
+		t.Fatalf("expected the Spanish guardrail, got %q", g.language)
+	}
+	if g := guardrailForText("Go engineer"); g.language != "en" {
+		t.Fatalf("expected short text to fall back to English, got %q", g.language)
+	}
+	es := guardrailFor("es")
+	if _, ok := es.find("Delivered significant growth."); !ok {
+		t.Fatalf("expected the English list to apply to Spanish documents too")
+	}
+	updated, _ := es.replace("Crecimiento de dos dígitos y un impacto significativo.")
+	if !strings.Contains(updated, "X% (sustituir por la cifra exacta)") || !strings.Contains(updated, "impacto medible") {
+		t.Fatalf("expected Spanish replacements, got %q", updated)
+	}
+}
+
+func TestGuardrailMatchesWholeWords(t *testing.T) {
+	nl := guardrailFor("nl")
+	if term, ok := nl.find("Enormous gains in throughput."); ok {
+		t.Fatalf("expected no match inside a longer word, got %q", term)
+	}
+	if _, ok := nl.find("Een enorme groei."); !ok {
+		t.Fatalf("expected the inflected Dutch form to match")
+	}
+	updated, _ := nl.replace("Enorme groei, enorm resultaat en enormous scale.")
+	if updated != "meetbaar groei, meetbaar resultaat en enormous scale." {
+		t.Fatalf("unexpected replacement %q", updated)
+	}
+}
+
+func TestEmptyForbiddenTermsDisableEveryLanguage(t *testing.T) {
+	SetForbiddenImpactTerms([]string{})
+	t.Cleanup(func() { SetForbiddenImpactTerms(nil) })
+	if term, ok := guardrailFor("es").find("Un impacto significativo de dos dígitos."); ok {
+		t.Fatalf("expected an empty list to disable the guardrail, matched %q", term)
+	}
+}
+
+func TestValidateV2_3WithRetryUsesLocalizedRepair(t *testing.T) {
+	client := &repairRecordingLLM{
+		first:  withBulletAfter(t, "Aumenté las ventas de forma significativa."),
+		second: loadFixture(t, "testdata/v2_3_good.json"),
+	}
+	if _, err := ValidateV2_3WithRetry(context.Background(), client, llm.AnalyzeInput{ResumeText: spanishResume}); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if len(client.messages) != 2 || !strings.Contains(client.messages[1], "Mantén los textos en español") {
+		t.Fatalf("expected a Spanish repair message on retry, got %q", client.messages)
+	}
+
+	// The same output passes for an English resume: the Spanish term is not on
+	// the English list.
+	english := &repairRecordingLLM{first: client.first}
+	if _, err := ValidateV2_3WithRetry(context.Background(), english, llm.AnalyzeInput{ResumeText: "Go engineer"}); err != nil || len(english.messages) != 1 {
+		t.Fatalf("expected no retry for an English resume, got %d calls, %v", len(english.messages), err)
+	}
+}
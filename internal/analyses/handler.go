@@ -1,17 +1,26 @@
 package analyses
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 
+	"resume-backend/internal/analyses/scoring"
+	"resume-backend/internal/audit"
 	"resume-backend/internal/documents"
+	"resume-backend/internal/events"
+	"resume-backend/internal/llmbudget"
+	"resume-backend/internal/resumes"
+	"resume-backend/internal/shared/ctxmeta"
 	"resume-backend/internal/shared/server/middleware"
 	"resume-backend/internal/shared/server/respond"
 	"resume-backend/internal/shared/telemetry"
@@ -22,6 +31,32 @@ import (
 type Handler struct {
 	Svc     *Service
 	DocRepo documents.DocumentsRepo
+	// Events receives funnel analytics; nil disables them.
+	Events *events.Emitter
+	// Backpressure stretches poll intervals under load; nil keeps the default.
+	Backpressure *Backpressure
+	// Audit records admin actions; nil skips auditing.
+	Audit *audit.Service
+	// InlineDocuments turns pasted resume text into a document; nil disables
+	// inline analyses.
+	InlineDocuments InlineDocumentCreator
+	// BuiltResumes turns a resume written in the builder into a document; nil
+	// disables analyzing built resumes.
+	BuiltResumes BuiltResumeDocuments
+	// Disclosure holds the retention facts GET /meta/ai-disclosure reports.
+	Disclosure DisclosureSettings
+}
+
+// InlineDocumentCreator creates a ready-to-analyze document from pasted text
+// and reports whether an existing document was reused.
+type InlineDocumentCreator interface {
+	CreateFromText(ctx context.Context, userID, text string) (documents.Document, bool, error)
+}
+
+// BuiltResumeDocuments creates a ready-to-analyze document from the rendered
+// form of a built resume and reports whether an existing document was reused.
+type BuiltResumeDocuments interface {
+	CreateDocument(ctx context.Context, userID, resumeID string) (documents.Document, bool, error)
 }
 
 // NewHandler constructs a Handler.
@@ -35,21 +70,165 @@ func NewHandler(svc *Service, docRepo documents.DocumentsRepo) *Handler {
 // RegisterRoutes attaches analysis routes to the router group.
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.POST("/documents/:id/analyze", h.startAnalysis)
+	rg.POST("/analyses/inline", h.startInlineAnalysis)
+	rg.POST("/resumes/:id/analyze", h.startBuiltResumeAnalysis)
 	rg.GET("/analyses", h.listAnalyses)
 	rg.GET("/analyses/:id", h.getAnalysis)
+	rg.POST("/analyses/:id/annotations", h.annotateAnalysis)
+	rg.PUT("/analyses/:id/recommendations/:recommendationId", h.setRecommendationState)
+	rg.GET("/meta/ai-disclosure", h.aiDisclosure)
+}
+
+// ScoringConfig serves the scoring config analyses are validated against, so
+// the frontend labels and weights the score breakdown the same way. The
+// version query picks the config a result's meta.scoringVersion names;
+// without it the current config is served. It changes only with a deploy, so
+// clients may cache it.
+func ScoringConfig(c *gin.Context) {
+	version := strings.TrimSpace(c.Query("version"))
+	if version == "" {
+		version = scoring.CurrentVersion
+	}
+	cfg, ok := scoring.Lookup(version)
+	if !ok {
+		respond.Error(c, http.StatusNotFound, "not_found", "scoring version not found", nil)
+		return
+	}
+	respond.CachedJSON(c, "public, max-age=3600", cfg)
+}
+
+// ActionReclassifyFailures is the audit action for an applied reclassification run.
+const ActionReclassifyFailures = "analyses.reclassify_failures"
+
+// ActionReplayAnalysis is the audit action for an applied analysis replay.
+const ActionReplayAnalysis = "analyses.replay"
+
+// RegisterAdminRoutes attaches analysis maintenance routes to an admin-only router group.
+func (h *Handler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.POST("/analyses/reclassify-failures", h.reclassifyFailures)
+	rg.POST("/analyses/:id/replay", h.replayAnalysis)
+}
+
+type reclassifyFailuresRequest struct {
+	Apply bool   `json:"apply"`
+	Limit int    `json:"limit"`
+	After string `json:"after"`
+}
+
+// reclassifyFailures re-runs failure classification over stored failures. It is a
+// dry run unless the body sets apply, so operators can review the report first.
+func (h *Handler) reclassifyFailures(c *gin.Context) {
+	req := reclassifyFailuresRequest{}
+	if err := decodeOptionalJSON(c.Request.Body, &req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+	if req.Limit < 0 {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "limit must not be negative", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	report, err := h.Svc.ReclassifyFailures(ctx, ReclassifyOptions{Apply: req.Apply, Limit: req.Limit, After: req.After})
+	if errors.Is(err, ErrReclassifyUnsupported) {
+		respond.Error(c, http.StatusNotImplemented, "not_supported", err.Error(), nil)
+		return
+	}
+	if req.Apply && report.Updated > 0 && h.Audit != nil {
+		_ = h.Audit.Record(ctx, audit.Entry{
+			Action:      ActionReclassifyFailures,
+			ActorUserID: middleware.UserIDFromContext(c),
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Details: map[string]any{
+				"scanned":     report.Scanned,
+				"updated":     report.Updated,
+				"transitions": report.Transitions,
+			},
+		})
+	}
+	if err != nil {
+		// A failed apply may already have rewritten some rows; the report says which.
+		telemetry.ErrorContext(ctx, "analysis.reclassify_failed", map[string]any{"error": err.Error(), "updated": report.Updated})
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to reclassify failures", gin.H{"report": report})
+		return
+	}
+	telemetry.InfoContext(ctx, "analysis.failures_reclassified", map[string]any{
+		"dry_run": report.DryRun,
+		"scanned": report.Scanned,
+		"changed": report.Changed,
+		"updated": report.Updated,
+	})
+	respond.JSON(c, http.StatusOK, report)
+}
+
+type replayAnalysisRequest struct {
+	Apply bool `json:"apply"`
+}
+
+// replayAnalysis re-runs validation and normalization on an analysis's stored raw
+// output. Like reclassification it is a dry run unless the body sets apply.
+func (h *Handler) replayAnalysis(c *gin.Context) {
+	req := replayAnalysisRequest{}
+	if err := decodeOptionalJSON(c.Request.Body, &req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+	ctx := c.Request.Context()
+	analysisID := c.Param("id")
+	report, err := h.Svc.ReplayAnalysis(ctx, analysisID, ReplayOptions{Apply: req.Apply})
+	if report.Applied && h.Audit != nil {
+		_ = h.Audit.Record(ctx, audit.Entry{
+			Action:      ActionReplayAnalysis,
+			ActorUserID: middleware.UserIDFromContext(c),
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Details: map[string]any{
+				"analysisId":     analysisID,
+				"previousStatus": report.PreviousStatus,
+				"changed":        report.Changed,
+			},
+		})
+	}
+	switch {
+	case err == nil:
+		respond.JSON(c, http.StatusOK, report)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "analysis not found", nil)
+	case errors.Is(err, ErrNoAnalysisRaw), errors.Is(err, ErrAnalysisInFlight):
+		respond.Error(c, http.StatusConflict, "replay_unavailable", err.Error(), nil)
+	case errors.Is(err, ErrUnsupportedPipeline):
+		respond.Error(c, http.StatusUnprocessableEntity, "unsupported_pipeline", err.Error(), nil)
+	default:
+		telemetry.ErrorContext(ctx, "analysis.replay_failed", map[string]any{"analysis_id": analysisID, "error": err.Error()})
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to replay analysis", nil)
+	}
 }
 
 type startAnalysisRequest struct {
-	JobDescription string `json:"jobDescription"`
-	PromptVersion  string `json:"promptVersion"`
-	Mode           string `json:"mode"`
+	JobDescription      string                      `json:"jobDescription"`
+	PromptVersion       string                      `json:"promptVersion"`
+	Mode                string                      `json:"mode"`
+	SupportingDocuments []supportingDocumentRequest `json:"supportingDocuments"`
+	ForceNew            bool                        `json:"forceNew"`
+	LearningPlan        bool                        `json:"learningPlan"`
+	// TargetRole names the role the user is aiming for, such as "Backend
+	// Engineer". ATS analyses without a job description are evaluated against it.
+	TargetRole string `json:"targetRole"`
+	// ForceJobDescription starts a job-match analysis despite quality issues
+	// that need confirmation.
+	ForceJobDescription bool `json:"forceJobDescription"`
+}
+
+type supportingDocumentRequest struct {
+	DocumentID string `json:"documentId"`
+	Kind       string `json:"kind"`
 }
 
 const defaultPollAfterMs = 2000
 
 func (h *Handler) startAnalysis(c *gin.Context) {
 	userID := middleware.UserIDFromContext(c)
-	ctx := withRequestID(c.Request.Context(), middleware.RequestIDFromContext(c))
+	ctx := ctxmeta.WithRequestID(c.Request.Context(), middleware.RequestIDFromContext(c))
 	documentID := c.Param("id")
 	c.Set("documentId", documentID)
 	if documentID == "" {
@@ -57,11 +236,176 @@ func (h *Handler) startAnalysis(c *gin.Context) {
 		return
 	}
 
-	req := startAnalysisRequest{PromptVersion: "v2_3"}
+	req := startAnalysisRequest{}
 	if err := decodeOptionalJSON(c.Request.Body, &req); err != nil {
 		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
 		return
 	}
+	mode, jdQuality, ok := h.validateStart(c, documentID, &req)
+	if !ok {
+		return
+	}
+	telemetry.Info("analysis.start", map[string]any{
+		"request_id":  middleware.RequestIDFromContext(c),
+		"user_id":     userID,
+		"document_id": documentID,
+		"mode":        mode,
+	})
+
+	doc, err := h.DocRepo.GetByID(c.Request.Context(), userID, documentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, documents.ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "document not found", err)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to start analysis", err)
+		}
+		return
+	}
+
+	h.startForDocument(ctx, c, userID, doc, req, mode, jdQuality, nil)
+}
+
+type startInlineAnalysisRequest struct {
+	startAnalysisRequest
+	ResumeText string `json:"resumeText"`
+}
+
+// startInlineAnalysis analyzes resume text pasted into the request instead of
+// an uploaded file. The text becomes a document whose extraction is already
+// done, and the analysis then runs as if it had been uploaded.
+func (h *Handler) startInlineAnalysis(c *gin.Context) {
+	if h.InlineDocuments == nil {
+		respond.Error(c, http.StatusNotImplemented, "not_supported", "inline analyses are not enabled", nil)
+		return
+	}
+	userID := middleware.UserIDFromContext(c)
+	ctx := ctxmeta.WithRequestID(c.Request.Context(), middleware.RequestIDFromContext(c))
+
+	req := startInlineAnalysisRequest{}
+	if err := decodeOptionalJSON(c.Request.Body, &req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+	textLen := utf8.RuneCountInString(strings.TrimSpace(req.ResumeText))
+	switch {
+	case textLen == 0:
+		respond.Error(c, http.StatusBadRequest, "validation_error", "resumeText is required", []map[string]string{
+			{"field": "resumeText", "issue": "required"},
+		})
+		return
+	case textLen < documents.MinInlineTextChars:
+		respond.Error(c, http.StatusBadRequest, "validation_error", fmt.Sprintf("resumeText must be at least %d characters", documents.MinInlineTextChars), []map[string]string{
+			{"field": "resumeText", "issue": "min_length"},
+		})
+		return
+	case textLen > documents.MaxInlineTextChars:
+		respond.Error(c, http.StatusBadRequest, "validation_error", fmt.Sprintf("resumeText must be at most %d characters", documents.MaxInlineTextChars), []map[string]string{
+			{"field": "resumeText", "issue": "max_length"},
+		})
+		return
+	}
+	mode, jdQuality, ok := h.validateStart(c, "", &req.startAnalysisRequest)
+	if !ok {
+		return
+	}
+	// Shed before storing anything, so a turned-away guest leaves no document.
+	if h.shedGuest(c) {
+		return
+	}
+
+	doc, linked, err := h.InlineDocuments.CreateFromText(ctx, userID, req.ResumeText)
+	if err != nil {
+		switch {
+		case errors.Is(err, documents.ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), []map[string]string{
+				{"field": "resumeText", "issue": "invalid"},
+			})
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to store resume text", err)
+		}
+		return
+	}
+	c.Set("documentId", doc.ID)
+	if !linked {
+		h.Events.Track(ctx, events.DocumentUploaded, userID, map[string]any{
+			"mime_type":  doc.MimeType,
+			"size_bytes": doc.SizeBytes,
+			"source":     "inline",
+		})
+	}
+	telemetry.Info("analysis.start", map[string]any{
+		"request_id":  middleware.RequestIDFromContext(c),
+		"user_id":     userID,
+		"document_id": doc.ID,
+		"mode":        mode,
+		"inline":      true,
+	})
+
+	h.startForDocument(ctx, c, userID, doc, req.startAnalysisRequest, mode, jdQuality, gin.H{"documentId": doc.ID})
+}
+
+// startBuiltResumeAnalysis analyzes a resume written in the builder. The resume
+// is rendered, and the text of the rendered document is analyzed like pasted
+// text; analyzing an unchanged resume again reuses the document.
+func (h *Handler) startBuiltResumeAnalysis(c *gin.Context) {
+	if h.BuiltResumes == nil {
+		respond.Error(c, http.StatusNotImplemented, "not_supported", "built resume analyses are not enabled", nil)
+		return
+	}
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to use the resume builder", nil)
+		return
+	}
+	userID := middleware.UserIDFromContext(c)
+	ctx := ctxmeta.WithRequestID(c.Request.Context(), middleware.RequestIDFromContext(c))
+	resumeID := c.Param("id")
+
+	req := startAnalysisRequest{}
+	if err := decodeOptionalJSON(c.Request.Body, &req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		return
+	}
+	mode, jdQuality, ok := h.validateStart(c, "", &req)
+	if !ok {
+		return
+	}
+
+	doc, linked, err := h.BuiltResumes.CreateDocument(ctx, userID, resumeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, resumes.ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "resume not found", nil)
+		case errors.Is(err, resumes.ErrIncomplete):
+			respond.Error(c, http.StatusUnprocessableEntity, "resume_incomplete", err.Error(), nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to render resume", nil)
+		}
+		return
+	}
+	c.Set("documentId", doc.ID)
+	if !linked {
+		h.Events.Track(ctx, events.DocumentUploaded, userID, map[string]any{
+			"mime_type":  doc.MimeType,
+			"size_bytes": doc.SizeBytes,
+			"source":     "builder",
+		})
+	}
+	telemetry.Info("analysis.start", map[string]any{
+		"request_id":  middleware.RequestIDFromContext(c),
+		"user_id":     userID,
+		"document_id": doc.ID,
+		"resume_id":   resumeID,
+		"mode":        mode,
+	})
+
+	h.startForDocument(ctx, c, userID, doc, req, mode, jdQuality, gin.H{"documentId": doc.ID, "resumeId": resumeID})
+}
+
+// validateStart checks the mode and job description of a start request and
+// runs the job description quality check. It responds and reports false when
+// the request is rejected.
+func (h *Handler) validateStart(c *gin.Context, documentID string, req *startAnalysisRequest) (AnalysisMode, *JDQuality, bool) {
 	modeInput := strings.TrimSpace(req.Mode)
 	if modeInput == "" {
 		modeInput = string(ModeJobMatch)
@@ -71,7 +415,7 @@ func (h *Handler) startAnalysis(c *gin.Context) {
 		respond.Error(c, http.StatusBadRequest, "validation_error", "mode is invalid", []map[string]string{
 			{"field": "mode", "issue": "invalid"},
 		})
-		return
+		return "", nil, false
 	}
 	req.Mode = string(mode)
 	if mode == ModeJobMatch {
@@ -79,36 +423,53 @@ func (h *Handler) startAnalysis(c *gin.Context) {
 			respond.Error(c, http.StatusBadRequest, "validation_error", "jobDescription is required", []map[string]string{
 				{"field": "jobDescription", "issue": "required"},
 			})
-			return
+			return "", nil, false
 		}
 		if utf8.RuneCountInString(req.JobDescription) < 300 {
 			respond.Error(c, http.StatusBadRequest, "validation_error", "jobDescription too short", []map[string]string{
 				{"field": "jobDescription", "issue": "min_length"},
 			})
-			return
+			return "", nil, false
 		}
 	}
+	req.TargetRole = NormalizeTargetRole(req.TargetRole)
+	if utf8.RuneCountInString(req.TargetRole) > MaxTargetRoleRunes {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "targetRole too long", []map[string]string{
+			{"field": "targetRole", "issue": "max_length"},
+		})
+		return "", nil, false
+	}
 	if utf8.RuneCountInString(req.JobDescription) > 50000 {
 		respond.Error(c, http.StatusBadRequest, "validation_error", "jobDescription too long", []map[string]string{
 			{"field": "jobDescription", "issue": "max_length"},
 		})
-		return
+		return "", nil, false
 	}
-	telemetry.Info("analysis.start", map[string]any{
-		"request_id":  middleware.RequestIDFromContext(c),
-		"user_id":     userID,
-		"document_id": documentID,
-		"mode":        mode,
-	})
-
-	doc, err := h.DocRepo.GetByID(c.Request.Context(), userID, documentID)
-	if err != nil {
-		switch {
-		case errors.Is(err, documents.ErrNotFound):
-			respond.Error(c, http.StatusNotFound, "not_found", "document not found", err)
-		default:
-			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to start analysis", err)
+	var jdQuality *JDQuality
+	if mode == ModeJobMatch {
+		quality := CheckJobDescription(req.JobDescription)
+		if len(quality.Issues) > 0 {
+			jdQuality = &quality
+		}
+		forceJD := req.ForceJobDescription || strings.EqualFold(c.Query("forceJobDescription"), "true")
+		if quality.NeedsConfirmation && !forceJD {
+			telemetry.Info("analysis.jd_quality_blocked", map[string]any{
+				"request_id":  middleware.RequestIDFromContext(c),
+				"document_id": documentID,
+				"issues":      jdIssueCodes(quality),
+			})
+			respond.Error(c, http.StatusUnprocessableEntity, "job_description_needs_confirmation", "the job description may be incomplete; review it or resend with forceJobDescription=true", quality)
+			return "", nil, false
 		}
+	}
+	return mode, jdQuality, true
+}
+
+// startForDocument starts or reuses an analysis of doc and responds. extra is
+// added to the response body.
+func (h *Handler) startForDocument(ctx context.Context, c *gin.Context, userID string, doc documents.Document, req startAnalysisRequest, mode AnalysisMode, jdQuality *JDQuality, extra gin.H) {
+	supporting, ok := h.resolveSupportingDocuments(c, userID, doc.ID, req.SupportingDocuments)
+	if !ok {
 		return
 	}
 
@@ -120,17 +481,64 @@ func (h *Handler) startAnalysis(c *gin.Context) {
 		allowRetry = true
 	}
 
-	analysis, created, err := h.Svc.StartOrReuse(ctx, doc.ID, userID, req.JobDescription, req.PromptVersion, mode, allowRetry)
+	forceNew := req.ForceNew || strings.EqualFold(c.Query("forceNew"), "true")
+
+	if h.shedGuest(c) {
+		return
+	}
+
+	// Reuse is keyed on the job description, so drift is measured against the
+	// document's previous analysis, looked up before this request adds one.
+	previous, prevErr := h.Svc.LatestForDocument(ctx, userID, doc.ID, mode)
+	if prevErr != nil && !errors.Is(prevErr, ErrNotFound) {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to start analysis", prevErr)
+		return
+	}
+
+	orgID := strings.TrimSpace(c.GetHeader("X-Org-Id"))
+	analysis, created, err := h.Svc.StartOrReuseWithOptions(ctx, doc.ID, userID, req.JobDescription, req.PromptVersion, mode, allowRetry, StartOptions{
+		SupportingDocuments: supporting,
+		ForceNew:            forceNew,
+		OrgID:               orgID,
+		LearningPlan:        req.LearningPlan,
+		TargetRole:          req.TargetRole,
+	})
 	if err != nil {
 		switch {
+		case errors.Is(err, ErrPromptVersionBlocked):
+			respond.Error(c, http.StatusBadRequest, "validation_error", "promptVersion has been retired", []map[string]string{
+				{"field": "promptVersion", "issue": "retired"},
+			})
+		case errors.Is(err, ErrUnsupportedPipeline):
+			respond.Error(c, http.StatusBadRequest, "validation_error", "promptVersion is not supported for this mode", []map[string]string{
+				{"field": "promptVersion", "issue": "unsupported"},
+			})
+		case errors.Is(err, ErrLearningPlanRequiresJobMatch):
+			respond.Error(c, http.StatusBadRequest, "validation_error", "learningPlan requires mode JOB_MATCH", []map[string]string{
+				{"field": "learningPlan", "issue": "unsupported_mode"},
+			})
+		case errors.Is(err, usage.ErrFeatureNotEntitled):
+			respond.Error(c, http.StatusForbidden, "plan_required", "Learning plans are not included in your plan. Upgrade your plan to use them.", []map[string]string{
+				{"field": "learningPlan", "issue": "plan_required"},
+			})
 		case errors.Is(err, ErrRetryRequired):
 			respond.Error(c, http.StatusConflict, "retry_required", "analysis failed; set retry=true or X-Retry-Analysis: true to retry", nil)
 		case errors.Is(err, ErrJobQueueNotConfigured):
 			respond.Error(c, http.StatusInternalServerError, "internal_error", err.Error(), err)
 		case errors.Is(err, usage.ErrLimitReached):
+			c.Header(usage.RemainingHeader, "0")
 			respond.Error(c, http.StatusTooManyRequests, "limit_reached", "You've reached your analysis limit. Upgrade your plan to continue.", []map[string]string{
 				{"field": "usage", "issue": "limit_reached"},
 			})
+		case errors.Is(err, llmbudget.ErrExceeded):
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(h.Svc.Budget.NextReset()).Seconds())+1))
+			respond.Error(c, http.StatusTooManyRequests, "llm_budget_exceeded", "Analyses are paused because today's analysis budget is spent. Try again after midnight UTC.", []map[string]string{
+				{"field": "budget", "issue": "exceeded"},
+			})
+		case errors.Is(err, usage.ErrOrgNotFound), errors.Is(err, usage.ErrNotOrgMember):
+			respond.Error(c, http.StatusForbidden, "forbidden", "not a member of this organization", []map[string]string{
+				{"field": "X-Org-Id", "issue": "not_member"},
+			})
 		default:
 			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to start analysis", err)
 		}
@@ -138,20 +546,156 @@ func (h *Handler) startAnalysis(c *gin.Context) {
 	}
 	c.Set("analysisId", analysis.ID)
 
+	// Tell the caller when this posting differs substantially from the one the
+	// document was last analyzed against, so results are not read as comparable.
+	var drift *JDDrift
+	if prevErr == nil && previous.ID != analysis.ID {
+		if d := DetectJDDrift(previous, req.JobDescription); d.Detected {
+			drift = &d
+			telemetry.Info("analysis.jd_drift", map[string]any{
+				"request_id":  middleware.RequestIDFromContext(c),
+				"analysis_id": analysis.ID,
+				"similarity":  d.Similarity,
+			})
+		}
+	}
+
 	if !created && analysis.Status == StatusCompleted && analysis.Result != nil {
-		respond.JSON(c, http.StatusOK, gin.H{
+		resp := gin.H{
 			"analysisId": analysis.ID,
 			"status":     analysis.Status,
 			"result":     analysis.Result,
-		})
+		}
+		addDrift(resp, drift)
+		addJDQuality(resp, jdQuality)
+		h.addSoftLimitWarning(c, resp, userID, orgID)
+		for k, v := range extra {
+			resp[k] = v
+		}
+		respond.JSON(c, http.StatusOK, resp)
 		return
 	}
 
-	respond.JSON(c, http.StatusAccepted, gin.H{
+	resp := gin.H{
 		"analysisId":  analysis.ID,
 		"status":      analysis.Status,
-		"pollAfterMs": defaultPollAfterMs,
+		"pollAfterMs": h.Backpressure.PollAfterMs(c.Request.Context()),
+	}
+	addDrift(resp, drift)
+	addJDQuality(resp, jdQuality)
+	h.addSoftLimitWarning(c, resp, userID, orgID)
+	for k, v := range extra {
+		resp[k] = v
+	}
+	respond.JSON(c, http.StatusAccepted, resp)
+}
+
+func addJDQuality(resp gin.H, quality *JDQuality) {
+	if quality == nil {
+		return
+	}
+	resp["jdQuality"] = quality
+}
+
+func jdIssueCodes(quality JDQuality) []string {
+	codes := make([]string, 0, len(quality.Issues))
+	for _, issue := range quality.Issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func addDrift(resp gin.H, drift *JDDrift) {
+	if drift == nil {
+		return
+	}
+	resp["jdDrift"] = drift
+	resp["warning"] = "job_description_changed"
+}
+
+// shedGuest turns guests away while the pipeline is overloaded so signed-in users
+// keep their place in the queue. It reports whether the request was rejected.
+func (h *Handler) shedGuest(c *gin.Context) bool {
+	if h.Backpressure == nil || !h.Backpressure.ShedGuests || !middleware.IsGuest(c) {
+		return false
+	}
+	reading := h.Backpressure.Reading(c.Request.Context())
+	if !reading.Overloaded {
+		return false
+	}
+	telemetry.Info("analysis.guest_shed", map[string]any{
+		"request_id":  middleware.RequestIDFromContext(c),
+		"queue_depth": reading.QueueDepth,
+		"latency_ms":  reading.CompletionLatencyMs,
 	})
+	c.Header("Retry-After", strconv.Itoa(reading.RetryAfterSeconds))
+	respond.Error(c, http.StatusServiceUnavailable, "overloaded", "analysis is busy right now; try again shortly", nil)
+	return true
+}
+
+// addSoftLimitWarning flags responses once usage crosses the soft limit so clients can
+// nudge an upgrade before analyses start failing, and writes X-Usage-Remaining.
+// Lookup errors leave both out.
+func (h *Handler) addSoftLimitWarning(c *gin.Context, resp gin.H, userID, orgID string) {
+	if h.Svc.Usage == nil {
+		return
+	}
+	_, u, err := h.Svc.Usage.CanConsume(c.Request.Context(), userID, orgID, 0)
+	if err != nil {
+		return
+	}
+	usage.SetRemainingHeader(c, u)
+	resp["softLimitWarning"] = h.Svc.Usage.SoftLimitWarning(u)
+}
+
+// resolveSupportingDocuments validates supporting document references and writes the error response when invalid.
+func (h *Handler) resolveSupportingDocuments(c *gin.Context, userID, primaryID string, reqs []supportingDocumentRequest) ([]SupportingDocument, bool) {
+	if len(reqs) == 0 {
+		return nil, true
+	}
+	if len(reqs) > MaxSupportingDocuments {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "too many supporting documents", []map[string]string{
+			{"field": "supportingDocuments", "issue": "max_items"},
+		})
+		return nil, false
+	}
+	seen := map[string]struct{}{primaryID: {}}
+	out := make([]SupportingDocument, 0, len(reqs))
+	for _, item := range reqs {
+		id := strings.TrimSpace(item.DocumentID)
+		if id == "" {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "supporting documentId is required", []map[string]string{
+				{"field": "supportingDocuments.documentId", "issue": "required"},
+			})
+			return nil, false
+		}
+		if _, dup := seen[id]; dup {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "supporting documents must be distinct from each other and the resume", []map[string]string{
+				{"field": "supportingDocuments.documentId", "issue": "duplicate"},
+			})
+			return nil, false
+		}
+		seen[id] = struct{}{}
+		kind, err := ParseSupportingKind(item.Kind)
+		if err != nil {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "supporting document kind is invalid", []map[string]string{
+				{"field": "supportingDocuments.kind", "issue": "invalid"},
+			})
+			return nil, false
+		}
+		if _, err := h.DocRepo.GetByID(c.Request.Context(), userID, id); err != nil {
+			if errors.Is(err, documents.ErrNotFound) {
+				respond.Error(c, http.StatusNotFound, "not_found", "supporting document not found", []map[string]string{
+					{"field": "supportingDocuments.documentId", "issue": "not_found"},
+				})
+				return nil, false
+			}
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to start analysis", err)
+			return nil, false
+		}
+		out = append(out, SupportingDocument{DocumentID: id, Kind: kind})
+	}
+	return out, true
 }
 
 func (h *Handler) getAnalysis(c *gin.Context) {
@@ -160,6 +704,13 @@ func (h *Handler) getAnalysis(c *gin.Context) {
 		respond.Error(c, http.StatusBadRequest, "validation_error", "analysis id is required", nil)
 		return
 	}
+	fields, err := parseFieldSelection(c)
+	if err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), []map[string]string{
+			{"field": "fields", "issue": "invalid"},
+		})
+		return
+	}
 
 	analysis, err := h.Svc.Get(c.Request.Context(), analysisID)
 	if err != nil {
@@ -183,6 +734,9 @@ func (h *Handler) getAnalysis(c *gin.Context) {
 		"status": analysis.Status,
 		"mode":   analysis.Mode,
 	}
+	if len(analysis.SupportingDocuments) > 0 {
+		resp["supportingDocuments"] = analysis.SupportingDocuments
+	}
 	if analysis.StartedAt != nil {
 		resp["startedAt"] = analysis.StartedAt
 	}
@@ -198,14 +752,111 @@ func (h *Handler) getAnalysis(c *gin.Context) {
 			resp["errorMessage"] = ""
 		}
 	}
+	var result map[string]any
 	if analysis.Status == StatusCompleted && analysis.Result != nil {
-		resp["result"] = analysis.Result
+		result = h.Svc.AnnotatedResult(c.Request.Context(), analysis)
+		result = h.Svc.WithRecommendationStates(c.Request.Context(), analysis.UserID, result)
+		resp["result"] = result
+		h.Events.TrackFirst(c.Request.Context(), events.FirstAnalysisViewed, analysis.UserID, map[string]any{
+			"mode":           string(analysis.Mode),
+			"prompt_version": analysis.PromptVersion,
+		})
+	}
+	if analysis.Status == StatusBudgetDeferred {
+		resp["deferredUntil"] = h.Svc.Budget.NextReset()
 	}
 	if analysis.Status == StatusQueued || analysis.Status == StatusProcessing {
-		resp["pollAfterMs"] = defaultPollAfterMs
+		resp["pollAfterMs"] = h.Backpressure.PollAfterMs(c.Request.Context())
+	}
+	h.addSoftLimitWarning(c, resp, analysis.UserID, "")
+
+	if fields != nil {
+		delete(resp, "result")
+		if finalScore, ok := extractFinalScore(result, analysis.Mode); ok {
+			resp["finalScore"] = finalScore
+		}
+		resp = selectFields(resp, result, fields)
 	}
+	respond.CachedJSON(c, respond.CacheRevalidate, resp)
+}
 
-	respond.JSON(c, http.StatusOK, resp)
+type annotateAnalysisRequest struct {
+	Target  string `json:"target"`
+	Index   *int   `json:"index"`
+	Keyword string `json:"keyword"`
+	Kind    string `json:"kind"`
+	Note    string `json:"note"`
+}
+
+// annotateAnalysis records the user's correction to one item of a completed
+// analysis. Re-annotating an item replaces the earlier annotation.
+func (h *Handler) annotateAnalysis(c *gin.Context) {
+	var req annotateAnalysisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid json body", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	analysisID := c.Param("id")
+	c.Set("analysisId", analysisID)
+	annotation, err := h.Svc.Annotate(ctx, middleware.UserIDFromContext(c), analysisID, Annotation{
+		Target:  req.Target,
+		Index:   req.Index,
+		Keyword: req.Keyword,
+		Kind:    req.Kind,
+		Note:    req.Note,
+	})
+	switch {
+	case err == nil:
+		respond.JSON(c, http.StatusCreated, annotation)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "analysis not found", nil)
+	case errors.Is(err, ErrAnnotationInvalid):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrAnalysisNotCompleted):
+		respond.Error(c, http.StatusConflict, "analysis_not_completed", "only completed analyses can be annotated", nil)
+	default:
+		telemetry.ErrorContext(ctx, "analysis.annotate_failed", map[string]any{"analysis_id": analysisID, "error": err.Error()})
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to save annotation", nil)
+	}
+}
+
+type setRecommendationStateRequest struct {
+	Status string `json:"status"`
+}
+
+// setRecommendationState marks one recommendation of a completed analysis
+// completed or dismissed, or reopens it, and returns the checklist progress.
+func (h *Handler) setRecommendationState(c *gin.Context) {
+	var req setRecommendationStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid json body", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	analysisID := c.Param("id")
+	c.Set("analysisId", analysisID)
+	state, progress, err := h.Svc.SetRecommendationState(ctx, middleware.UserIDFromContext(c), analysisID, c.Param("recommendationId"), req.Status)
+	switch {
+	case err == nil:
+		respond.JSON(c, http.StatusOK, gin.H{
+			"recommendationId": state.RecommendationID,
+			"status":           state.Status,
+			"updatedAt":        state.UpdatedAt,
+			"progress":         progress,
+		})
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "analysis not found", nil)
+	case errors.Is(err, ErrRecommendationNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "recommendation not found", nil)
+	case errors.Is(err, ErrRecommendationStateInvalid):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrAnalysisNotCompleted):
+		respond.Error(c, http.StatusConflict, "analysis_not_completed", "only completed analyses have recommendations", nil)
+	default:
+		telemetry.ErrorContext(ctx, "analysis.recommendation_state_failed", map[string]any{"analysis_id": analysisID, "error": err.Error()})
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to save recommendation state", nil)
+	}
 }
 
 func (h *Handler) listAnalyses(c *gin.Context) {
@@ -217,6 +868,13 @@ func (h *Handler) listAnalyses(c *gin.Context) {
 	}
 
 	userID := middleware.UserIDFromContext(c)
+	fields, err := parseFieldSelection(c)
+	if err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), []map[string]string{
+			{"field": "fields", "issue": "invalid"},
+		})
+		return
+	}
 
 	limit := 20
 	offset := 0
@@ -273,10 +931,22 @@ func (h *Handler) listAnalyses(c *gin.Context) {
 				item["summary"] = summary
 			}
 		}
+		if fields != nil {
+			var result map[string]any
+			if a.Status == StatusCompleted {
+				result = a.Result
+			}
+			item = selectFields(item, result, fields)
+		}
 		resp = append(resp, item)
 	}
 
-	respond.JSON(c, http.StatusOK, resp)
+	respond.ListJSON(c, respond.CacheRevalidate, resp)
+}
+
+// FinalScore returns the headline score of a completed analysis, as shown in history.
+func FinalScore(a Analysis) (float64, bool) {
+	return extractFinalScore(a.Result, a.Mode)
 }
 
 func extractFinalScore(result map[string]any, mode AnalysisMode) (float64, bool) {
@@ -299,12 +299,14 @@ func TestStartAnalysisCompletedReturnsResult(t *testing.T) {
 		"finalScore": 74.0,
 	}
 	analysis := Analysis{
-		ID:         "analysis-completed",
-		DocumentID: documentID,
-		UserID:     userID,
-		Status:     StatusCompleted,
-		Result:     result,
-		CreatedAt:  time.Now().UTC(),
+		ID:             "analysis-completed",
+		DocumentID:     documentID,
+		UserID:         userID,
+		JobDescription: strings.Repeat("a", 300),
+		Mode:           ModeJobMatch,
+		Status:         StatusCompleted,
+		Result:         result,
+		CreatedAt:      time.Now().UTC(),
 	}
 	if err := analysisRepo.Create(context.Background(), analysis); err != nil {
 		t.Fatalf("create analysis: %v", err)
@@ -365,12 +367,14 @@ func TestStartAnalysisFailedRequiresRetry(t *testing.T) {
 
 	msg := "boom"
 	analysis := Analysis{
-		ID:           "analysis-failed",
-		DocumentID:   documentID,
-		UserID:       userID,
-		Status:       StatusFailed,
-		ErrorMessage: &msg,
-		CreatedAt:    time.Now().UTC(),
+		ID:             "analysis-failed",
+		DocumentID:     documentID,
+		UserID:         userID,
+		JobDescription: strings.Repeat("a", 300),
+		Mode:           ModeJobMatch,
+		Status:         StatusFailed,
+		ErrorMessage:   &msg,
+		CreatedAt:      time.Now().UTC(),
 	}
 	if err := analysisRepo.Create(context.Background(), analysis); err != nil {
 		t.Fatalf("create analysis: %v", err)
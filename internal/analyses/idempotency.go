@@ -0,0 +1,40 @@
+package analyses
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// HashJobDescription returns the idempotency hash for a job description.
+// Leading, trailing and repeated whitespace do not change the hash.
+func HashJobDescription(jobDescription string) string {
+	normalized := strings.Join(strings.Fields(jobDescription), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// jobDescriptionHash returns the stored hash, computing it when the caller left it empty.
+func jobDescriptionHash(a Analysis) string {
+	if a.JobDescriptionHash != "" {
+		return a.JobDescriptionHash
+	}
+	return HashJobDescription(a.JobDescription)
+}
+
+// HashSupportingDocuments returns the reuse key of an analysis's supporting
+// documents. It ignores their order and is empty when there are none, so an
+// analysis started with supporting documents never reuses one started without.
+func HashSupportingDocuments(docs []SupportingDocument) string {
+	if len(docs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		keys = append(keys, doc.DocumentID+"/"+doc.Kind)
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(sum[:])
+}
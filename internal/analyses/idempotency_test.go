@@ -0,0 +1,100 @@
+package analyses
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHashJobDescriptionNormalizesWhitespace(t *testing.T) {
+	base := HashJobDescription("Go engineer with Postgres")
+	if got := HashJobDescription("  Go   engineer\nwith\tPostgres \n"); got != base {
+		t.Fatalf("expected whitespace variants to hash equally, got %s vs %s", got, base)
+	}
+	if got := HashJobDescription("Go engineer with MySQL"); got == base {
+		t.Fatalf("expected different JDs to hash differently")
+	}
+}
+
+func TestMemoryRepoGetOrCreateKeysOnJDAndMode(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	existing := Analysis{
+		ID:             "analysis-1",
+		DocumentID:     "doc-1",
+		UserID:         "user-1",
+		JobDescription: "Backend engineer",
+		Mode:           ModeJobMatch,
+		Status:         StatusQueued,
+		CreatedAt:      now,
+	}
+	if _, _, err := repo.GetOrCreateForDocument(ctx, existing, false, nil); err != nil {
+		t.Fatalf("seed analysis: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		jd      string
+		mode    AnalysisMode
+		created bool
+	}{
+		{name: "same key", jd: "Backend  engineer\n", mode: ModeJobMatch, created: false},
+		{name: "different jd", jd: "Frontend engineer", mode: ModeJobMatch, created: true},
+		{name: "different mode", jd: "Backend engineer", mode: ModeATS, created: true},
+	}
+	for i, tc := range cases {
+		candidate := existing
+		candidate.ID = "analysis-candidate-" + string(rune('a'+i))
+		candidate.JobDescription = tc.jd
+		candidate.Mode = tc.mode
+		got, created, err := repo.GetOrCreateForDocument(ctx, candidate, false, nil)
+		if err != nil {
+			t.Fatalf("%s: get or create: %v", tc.name, err)
+		}
+		if created != tc.created {
+			t.Fatalf("%s: expected created=%v, got %v", tc.name, tc.created, created)
+		}
+		if !tc.created && got.ID != existing.ID {
+			t.Fatalf("%s: expected reuse of %s, got %s", tc.name, existing.ID, got.ID)
+		}
+	}
+}
+
+func TestMemoryRepoGetOrCreateKeysOnSupportingDocuments(t *testing.T) {
+	repo := NewMemoryRepo()
+	ctx := context.Background()
+	portfolio := SupportingDocument{DocumentID: "portfolio-1", Kind: SupportingKindPortfolio}
+	letter := SupportingDocument{DocumentID: "letter-1", Kind: SupportingKindCoverLetter}
+
+	start := func(id string, docs ...SupportingDocument) (Analysis, bool) {
+		t.Helper()
+		got, created, err := repo.GetOrCreateForDocument(ctx, Analysis{
+			ID:                  id,
+			DocumentID:          "doc-1",
+			UserID:              "user-1",
+			JobDescription:      "Backend engineer",
+			Mode:                ModeJobMatch,
+			Status:              StatusQueued,
+			CreatedAt:           time.Now().UTC(),
+			SupportingDocuments: docs,
+		}, false, nil)
+		if err != nil {
+			t.Fatalf("get or create %s: %v", id, err)
+		}
+		return got, created
+	}
+
+	without, _ := start("analysis-1")
+	with, created := start("analysis-2", portfolio, letter)
+	if !created || with.ID == without.ID {
+		t.Fatalf("expected supporting documents to start a new analysis, got %s", with.ID)
+	}
+	if got, created := start("analysis-3", letter, portfolio); created || got.ID != with.ID {
+		t.Fatalf("expected reordered supporting documents to reuse %s, got %s created=%v", with.ID, got.ID, created)
+	}
+	if got, created := start("analysis-4"); created || got.ID != without.ID {
+		t.Fatalf("expected no supporting documents to reuse %s, got %s created=%v", without.ID, got.ID, created)
+	}
+}
@@ -0,0 +1,117 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/server/middleware"
+	local "resume-backend/internal/shared/storage/object/local"
+)
+
+func setupInlineRouter(t *testing.T) (*gin.Engine, *documents.MemoryRepo, *MemoryRepo, *stubQueue) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	docRepo := documents.NewMemoryRepo()
+	analysisRepo := NewMemoryRepo()
+	store := local.New(t.TempDir())
+	queueStub := &stubQueue{}
+	svc := &Service{Repo: analysisRepo, DocRepo: docRepo, Store: store, LLM: stubLLM{}, JobQueue: queueStub}
+	handler := NewHandler(svc, docRepo)
+	handler.InlineDocuments = &documents.Service{Store: store, Repo: docRepo}
+
+	router := gin.New()
+	router.Use(middleware.Auth("dev"))
+	handler.RegisterRoutes(router.Group("/api/v1"))
+	return router, docRepo, analysisRepo, queueStub
+}
+
+func postInline(t *testing.T, router *gin.Engine, payload map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/analyses/inline", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestStartInlineAnalysisCreatesExtractedDocument(t *testing.T) {
+	router, docRepo, analysisRepo, queueStub := setupInlineRouter(t)
+	resumeText := strings.Repeat("Built payment services in Go and Postgres. ", 10)
+
+	resp := postInline(t, router, map[string]any{"mode": "ATS", "resumeText": resumeText})
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		AnalysisID string `json:"analysisId"`
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if created.AnalysisID == "" || created.DocumentID == "" {
+		t.Fatalf("expected analysisId and documentId, got %+v", created)
+	}
+
+	doc, err := docRepo.GetByID(context.Background(), "guest:test-guest", created.DocumentID)
+	if err != nil {
+		t.Fatalf("get document: %v", err)
+	}
+	if doc.ExtractedAt == nil || doc.ExtractedTextKey == "" {
+		t.Fatalf("expected document to be extracted, got %+v", doc)
+	}
+	if doc.MimeType != "text/plain" {
+		t.Fatalf("expected text/plain, got %q", doc.MimeType)
+	}
+	analysis, err := analysisRepo.GetByID(context.Background(), created.AnalysisID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if analysis.DocumentID != doc.ID {
+		t.Fatalf("expected analysis of %s, got %s", doc.ID, analysis.DocumentID)
+	}
+	if len(queueStub.messages) != 1 {
+		t.Fatalf("expected one queued job, got %d", len(queueStub.messages))
+	}
+
+	// Pasting the same text again reuses the document and the analysis.
+	resp = postInline(t, router, map[string]any{"mode": "ATS", "resumeText": resumeText})
+	var again struct {
+		AnalysisID string `json:"analysisId"`
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&again); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if again.DocumentID != created.DocumentID || again.AnalysisID != created.AnalysisID {
+		t.Fatalf("expected reuse of %+v, got %+v", created, again)
+	}
+}
+
+func TestStartInlineAnalysisRejectsShortText(t *testing.T) {
+	router, _, _, queueStub := setupInlineRouter(t)
+
+	resp := postInline(t, router, map[string]any{"mode": "ATS", "resumeText": "Go developer"})
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.Code)
+	}
+	if !strings.Contains(resp.Body.String(), "min_length") {
+		t.Fatalf("expected min_length issue, got %s", resp.Body.String())
+	}
+	if len(queueStub.messages) != 0 {
+		t.Fatalf("expected no queued job, got %d", len(queueStub.messages))
+	}
+}
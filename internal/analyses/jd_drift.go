@@ -0,0 +1,97 @@
+package analyses
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// JDDriftThreshold is the cosine similarity below which a job description is treated as a different posting.
+const JDDriftThreshold = 0.6
+
+// JDDrift reports how far a requested job description is from the one the document's previous analysis used.
+type JDDrift struct {
+	Detected           bool    `json:"detected"`
+	Similarity         float64 `json:"similarity"`
+	Threshold          float64 `json:"threshold"`
+	PreviousAnalysisID string  `json:"previousAnalysisId"`
+}
+
+var jdStopwords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "for": {}, "from": {},
+	"in": {}, "is": {}, "it": {}, "of": {}, "on": {}, "or": {}, "our": {}, "that": {}, "the": {}, "this": {},
+	"to": {}, "we": {}, "will": {}, "with": {}, "you": {}, "your": {},
+}
+
+// documentAnalysisFinder is implemented by repos that can find the analysis a
+// start request is compared against for drift.
+type documentAnalysisFinder interface {
+	// LatestForDocument returns the newest analysis of the document in mode,
+	// whatever its job description, or ErrNotFound.
+	LatestForDocument(ctx context.Context, userID, documentID string, mode AnalysisMode) (Analysis, error)
+}
+
+// LatestForDocument returns the newest analysis of the document in mode. It
+// returns ErrNotFound when there is none or the repo cannot look it up.
+func (s *Service) LatestForDocument(ctx context.Context, userID, documentID string, mode AnalysisMode) (Analysis, error) {
+	finder, ok := s.Repo.(documentAnalysisFinder)
+	if !ok {
+		return Analysis{}, ErrNotFound
+	}
+	if mode == "" {
+		mode = ModeJobMatch
+	}
+	return finder.LatestForDocument(ctx, userID, documentID, mode)
+}
+
+// DetectJDDrift compares the job description of the document's previous analysis with the newly requested one.
+func DetectJDDrift(previous Analysis, requestedJD string) JDDrift {
+	similarity := JobDescriptionSimilarity(previous.JobDescription, requestedJD)
+	return JDDrift{
+		Detected:           similarity < JDDriftThreshold,
+		Similarity:         math.Round(similarity*1000) / 1000,
+		Threshold:          JDDriftThreshold,
+		PreviousAnalysisID: previous.ID,
+	}
+}
+
+// JobDescriptionSimilarity returns the cosine similarity of term frequencies in [0,1].
+// Two empty descriptions are identical; one empty and one non-empty share nothing.
+func JobDescriptionSimilarity(a, b string) float64 {
+	ta, tb := jdTermFrequencies(a), jdTermFrequencies(b)
+	if len(ta) == 0 && len(tb) == 0 {
+		return 1
+	}
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for term, ca := range ta {
+		na += ca * ca
+		if cb, ok := tb[term]; ok {
+			dot += ca * cb
+		}
+	}
+	for _, cb := range tb {
+		nb += cb * cb
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func jdTermFrequencies(text string) map[string]float64 {
+	terms := map[string]float64{}
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+	for _, field := range fields {
+		if len(field) < 2 {
+			continue
+		}
+		if _, stop := jdStopwords[field]; stop {
+			continue
+		}
+		terms[field]++
+	}
+	return terms
+}
@@ -0,0 +1,110 @@
+package analyses
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	backendJD  = "Senior backend engineer. Required: Go, PostgreSQL, Kubernetes, distributed systems, gRPC. Nice to have: Kafka and Terraform. You will own payment APIs and on-call rotations. "
+	designerJD = "Product designer. Required: Figma, user research, interaction design, prototyping, accessibility audits. You will partner with marketing on brand campaigns and landing pages. "
+)
+
+func TestJobDescriptionSimilarity(t *testing.T) {
+	if got := JobDescriptionSimilarity(backendJD, backendJD+" Remote friendly."); got < JDDriftThreshold {
+		t.Fatalf("expected near-identical JDs above threshold, got %.3f", got)
+	}
+	if got := JobDescriptionSimilarity(backendJD, designerJD); got >= JDDriftThreshold {
+		t.Fatalf("expected unrelated JDs below threshold, got %.3f", got)
+	}
+	if got := JobDescriptionSimilarity("", ""); got != 1 {
+		t.Fatalf("expected empty JDs to match, got %.3f", got)
+	}
+	if got := JobDescriptionSimilarity("", backendJD); got != 0 {
+		t.Fatalf("expected empty vs non-empty to be 0, got %.3f", got)
+	}
+}
+
+type startResponse struct {
+	AnalysisID string   `json:"analysisId"`
+	Warning    string   `json:"warning"`
+	JDDrift    *JDDrift `json:"jdDrift"`
+}
+
+func decodeStart(t *testing.T, body []byte) startResponse {
+	t.Helper()
+	var out startResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return out
+}
+
+func TestDetectJDDrift(t *testing.T) {
+	previous := Analysis{ID: "analysis-prev", JobDescription: strings.Repeat(backendJD, 3)}
+
+	if drift := DetectJDDrift(previous, previous.JobDescription); drift.Detected {
+		t.Fatalf("expected no drift for identical JD, got %+v", drift)
+	}
+	drift := DetectJDDrift(previous, strings.Repeat(designerJD, 3))
+	if !drift.Detected || drift.PreviousAnalysisID != previous.ID || drift.Threshold != JDDriftThreshold {
+		t.Fatalf("expected drift against previous analysis, got %+v", drift)
+	}
+}
+
+func TestStartAnalysisReuseKeyedOnJDAndForceNew(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, docRepo, _, store, queueStub := setupAnalysisRouter(t)
+	documentID := seedDocument(t, docRepo, store, "guest:test-guest")
+	jdA := strings.Repeat(backendJD, 3)
+	jdB := strings.Repeat(designerJD, 3)
+
+	first := postAnalyze(t, router, documentID, map[string]any{"jobDescription": jdA})
+	if first.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", first.Code, first.Body.String())
+	}
+	original := decodeStart(t, first.Body.Bytes())
+
+	same := decodeStart(t, postAnalyze(t, router, documentID, map[string]any{"jobDescription": "  " + strings.ReplaceAll(jdA, " ", "\n ")}).Body.Bytes())
+	if same.AnalysisID != original.AnalysisID || same.JDDrift != nil {
+		t.Fatalf("expected silent reuse for a whitespace-only JD change, got %+v", same)
+	}
+
+	other := decodeStart(t, postAnalyze(t, router, documentID, map[string]any{"jobDescription": jdB}).Body.Bytes())
+	if other.AnalysisID == original.AnalysisID {
+		t.Fatalf("expected a new analysis for a different JD, got %+v", other)
+	}
+	if other.JDDrift == nil || !other.JDDrift.Detected || other.JDDrift.PreviousAnalysisID != original.AnalysisID || other.Warning != "job_description_changed" {
+		t.Fatalf("expected drift against the previous analysis, got %+v", other)
+	}
+
+	ats := decodeStart(t, postAnalyze(t, router, documentID, map[string]any{"jobDescription": jdA, "mode": string(ModeATS)}).Body.Bytes())
+	if ats.AnalysisID == original.AnalysisID || ats.AnalysisID == other.AnalysisID || ats.JDDrift != nil {
+		t.Fatalf("expected a new analysis without drift for a different mode, got %+v", ats)
+	}
+
+	forcedResp := postAnalyze(t, router, documentID, map[string]any{"jobDescription": jdA, "forceNew": true})
+	if forcedResp.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", forcedResp.Code)
+	}
+	forced := decodeStart(t, forcedResp.Body.Bytes())
+	if forced.AnalysisID == original.AnalysisID {
+		t.Fatalf("expected a fresh analysis with forceNew, got %+v", forced)
+	}
+	if forced.JDDrift == nil || forced.JDDrift.PreviousAnalysisID != other.AnalysisID {
+		t.Fatalf("expected drift against the latest job-match analysis, got %+v", forced)
+	}
+	if len(queueStub.messages) != 4 {
+		t.Fatalf("expected four enqueued analyses, got %d", len(queueStub.messages))
+	}
+
+	after := decodeStart(t, postAnalyze(t, router, documentID, map[string]any{"jobDescription": jdA}).Body.Bytes())
+	if after.AnalysisID != forced.AnalysisID || after.JDDrift != nil {
+		t.Fatalf("expected silent reuse of the forced analysis, got %+v", after)
+	}
+}
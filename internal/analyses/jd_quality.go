@@ -0,0 +1,125 @@
+package analyses
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Job description quality issue codes.
+const (
+	JDIssueShort                   = "short"
+	JDIssueTruncated               = "truncated"
+	JDIssueMissingResponsibilities = "missing_responsibilities"
+	JDIssueMissingRequirements     = "missing_requirements"
+	JDIssueBoilerplate             = "boilerplate"
+	JDIssueDuplicatedContent       = "duplicated_content"
+)
+
+// Job description quality severities. A confirm issue stops the analysis
+// until the caller resends it with forceJobDescription.
+const (
+	JDSeverityWarning = "warning"
+	JDSeverityConfirm = "confirm"
+)
+
+const (
+	// jdShortWords flags postings that passed the length check but are still
+	// too thin to match against.
+	jdShortWords = 80
+	// jdBoilerplateShare is the share of sentences that may be company,
+	// benefits or job-board boilerplate before the posting is mostly noise.
+	jdBoilerplateShare = 0.5
+	// jdDuplicateShare is the share of repeated sentences that marks a posting
+	// pasted twice.
+	jdDuplicateShare = 0.3
+	// jdMinLineRunes ignores headings and fragments too short to compare.
+	jdMinLineRunes = 25
+)
+
+// JDQualityIssue is one finding of CheckJobDescription.
+type JDQualityIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// JDQuality is the outcome of CheckJobDescription.
+type JDQuality struct {
+	Words  int              `json:"words"`
+	Issues []JDQualityIssue `json:"issues"`
+	// NeedsConfirmation is set when any issue has severity confirm.
+	NeedsConfirmation bool `json:"needsConfirmation"`
+}
+
+var (
+	jdResponsibilityPattern = regexp.MustCompile(`(?i)\b(responsibilities|responsible for|duties|what you'?ll do|what you will do|day[- ]to[- ]day|in this role|the role|you will|you'?ll be)\b`)
+	jdRequirementPattern    = regexp.MustCompile(`(?i)\b(requirements|qualifications|what you'?ll bring|what we'?re looking for|must have|nice to have|experience (with|in)|years of experience|you have|skills)\b`)
+	jdBoilerplatePattern    = regexp.MustCompile(`(?i)(equal opportunity|equal employment|without regard to|reasonable accommodation|benefits|401\(k\)|paid time off|health insurance|about us|our mission|apply now|easy apply|save job|share this job|report this job|cookie|privacy policy|terms of (use|service)|sign in|log in|posted \d+|applicants|show more|see more)`)
+	jdTruncatedPattern      = regexp.MustCompile(`(?i)(\.\.\.|…|\b(show|see|read) more)\s*$`)
+)
+
+// CheckJobDescription looks for signs that a pasted job description is
+// truncated, thin or mostly boilerplate, which makes for poor job-match
+// analyses. It only inspects the text; nothing is sent to the model.
+func CheckJobDescription(jd string) JDQuality {
+	quality := JDQuality{Words: len(strings.Fields(jd)), Issues: []JDQualityIssue{}}
+	add := func(code, severity, message string) {
+		quality.Issues = append(quality.Issues, JDQualityIssue{Code: code, Severity: severity, Message: message})
+		if severity == JDSeverityConfirm {
+			quality.NeedsConfirmation = true
+		}
+	}
+
+	if quality.Words < jdShortWords {
+		add(JDIssueShort, JDSeverityWarning, "The job description is short; paste the full posting for a better match.")
+	}
+	if jdTruncatedPattern.MatchString(strings.TrimSpace(jd)) {
+		add(JDIssueTruncated, JDSeverityConfirm, "The job description looks cut off; expand the posting before copying it.")
+	}
+	if !jdResponsibilityPattern.MatchString(jd) {
+		add(JDIssueMissingResponsibilities, JDSeverityWarning, "No responsibilities section was found.")
+	}
+	if !jdRequirementPattern.MatchString(jd) {
+		add(JDIssueMissingRequirements, JDSeverityWarning, "No requirements or qualifications section was found.")
+	}
+
+	lines := jdSentences(jd)
+	if len(lines) > 0 {
+		boilerplate, duplicates := 0, 0
+		seen := map[string]bool{}
+		for _, line := range lines {
+			if jdBoilerplatePattern.MatchString(line) {
+				boilerplate++
+			}
+			key := normalizeLine(line)
+			if len([]rune(key)) < jdMinLineRunes {
+				continue
+			}
+			if seen[key] {
+				duplicates++
+			}
+			seen[key] = true
+		}
+		if float64(boilerplate)/float64(len(lines)) >= jdBoilerplateShare {
+			add(JDIssueBoilerplate, JDSeverityConfirm, "Most of the text is company, benefits or job-board boilerplate rather than the role.")
+		}
+		if float64(duplicates)/float64(len(lines)) >= jdDuplicateShare {
+			add(JDIssueDuplicatedContent, JDSeverityWarning, "Large parts of the text are repeated; the posting may have been pasted twice.")
+		}
+	}
+	return quality
+}
+
+var jdSentenceBreak = regexp.MustCompile(`[\n•]+|[.!?;]\s+`)
+
+// jdSentences splits a posting into lines and sentences, so postings pasted
+// without line breaks are measured the same way.
+func jdSentences(jd string) []string {
+	var out []string
+	for _, line := range jdSentenceBreak.Split(jd, -1) {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package analyses
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const fullJD = `Senior Backend Engineer
+
+About the role
+You will design and own the payment APIs that thousands of merchants rely on every day. In this role you will work closely with product, security and support engineers.
+
+Responsibilities
+- Build and operate Go services on Kubernetes with high availability.
+- Design PostgreSQL schemas and tune queries for heavy write loads.
+- Lead incident reviews and improve on-call tooling for the team.
+- Mentor engineers through code review and design documents.
+
+Requirements
+- 5+ years of experience with backend systems in production.
+- Strong knowledge of distributed systems, gRPC and event streaming.
+- Experience with Terraform and AWS is a plus.
+- Clear written communication across time zones.`
+
+func issueCodes(q JDQuality) string {
+	return strings.Join(jdIssueCodes(q), ",")
+}
+
+func TestCheckJobDescription(t *testing.T) {
+	if q := CheckJobDescription(fullJD); len(q.Issues) != 0 || q.NeedsConfirmation {
+		t.Fatalf("expected a complete posting to pass, got %+v", q)
+	}
+
+	truncated := CheckJobDescription(fullJD + "\n- Experience running payment systems at scale and ...")
+	if truncated.NeedsConfirmation == false || !strings.Contains(issueCodes(truncated), JDIssueTruncated) {
+		t.Fatalf("expected truncation to need confirmation, got %+v", truncated)
+	}
+	if q := CheckJobDescription(fullJD + "\nShow more"); !strings.Contains(issueCodes(q), JDIssueTruncated) {
+		t.Fatalf("expected a show-more tail to count as truncated, got %+v", q)
+	}
+
+	boilerplate := CheckJobDescription(`Acme Corp. About us: our mission is to make payments simple.
+We offer great benefits, health insurance and paid time off.
+Acme is an equal opportunity employer and hires without regard to race or religion.
+We provide reasonable accommodation during the hiring process.
+Apply now. Save job. Share this job. Report this job.
+Senior engineer, Go.`)
+	codes := issueCodes(boilerplate)
+	if !boilerplate.NeedsConfirmation || !strings.Contains(codes, JDIssueBoilerplate) || !strings.Contains(codes, JDIssueMissingRequirements) {
+		t.Fatalf("expected boilerplate and missing sections flagged, got %+v", boilerplate)
+	}
+
+	twice := CheckJobDescription(fullJD + "\n\n" + fullJD)
+	if twice.NeedsConfirmation || issueCodes(twice) != JDIssueDuplicatedContent {
+		t.Fatalf("expected a duplicated posting to only warn, got %+v", twice)
+	}
+}
+
+func TestStartAnalysisJDQualityConfirmation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, docRepo, _, store, _ := setupAnalysisRouter(t)
+	documentID := seedDocument(t, docRepo, store, "guest:test-guest")
+	truncated := fullJD + "\n- Experience running payment systems at scale and…"
+
+	blocked := postAnalyze(t, router, documentID, map[string]any{"jobDescription": truncated})
+	if blocked.Code != http.StatusUnprocessableEntity || !strings.Contains(blocked.Body.String(), "job_description_needs_confirmation") {
+		t.Fatalf("expected 422 needing confirmation, got %d: %s", blocked.Code, blocked.Body.String())
+	}
+
+	forced := postAnalyze(t, router, documentID, map[string]any{"jobDescription": truncated, "forceJobDescription": true})
+	if forced.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 with forceJobDescription, got %d: %s", forced.Code, forced.Body.String())
+	}
+	var resp struct {
+		JDQuality *JDQuality `json:"jdQuality"`
+	}
+	if err := json.Unmarshal(forced.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.JDQuality == nil || issueCodes(*resp.JDQuality) != JDIssueTruncated {
+		t.Fatalf("expected the issues echoed as jdQuality, got %s", forced.Body.String())
+	}
+
+	ats := postAnalyze(t, router, documentID, map[string]any{"mode": "ATS", "jobDescription": "Show more"})
+	if ats.Code != http.StatusAccepted {
+		t.Fatalf("expected ATS analyses to skip the check, got %d: %s", ats.Code, ats.Body.String())
+	}
+}
@@ -0,0 +1,107 @@
+package analyses
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"resume-backend/internal/shared/fieldcrypt"
+)
+
+// RehashReport counts what a job description rehash did.
+type RehashReport struct {
+	Scanned   int `json:"scanned"`
+	Rewritten int `json:"rewritten"`
+	// Skipped rows changed between the read and the write.
+	Skipped int    `json:"skipped"`
+	Failed  int    `json:"failed"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Rehash recomputes job_description_hash with HashJobDescription. The SQL
+// backfill in migration 0019 trims and collapses whitespace differently from
+// strings.Fields, so rows it hashed may never match a new request for the same
+// job description. Without Apply it only counts the rows it would rewrite.
+type Rehash struct {
+	DB *sql.DB
+	// Codec opens job descriptions sealed with PII_KEYS; nil reads plaintext only.
+	Codec *fieldcrypt.Codec
+
+	Apply     bool
+	BatchSize int
+}
+
+// Run walks every analysis by id. It stops early when ctx is cancelled.
+func (r *Rehash) Run(ctx context.Context) (RehashReport, error) {
+	report := RehashReport{Applied: r.Apply}
+	if r.DB == nil {
+		return report, errors.New("database is required")
+	}
+	batch := r.BatchSize
+	if batch <= 0 {
+		batch = 500
+	}
+	const page = `
+SELECT id::text, COALESCE(job_description, ''), job_description_hash
+FROM analyses
+WHERE id::text > $1
+ORDER BY id::text
+LIMIT $2`
+	const update = `UPDATE analyses SET job_description_hash = $1 WHERE id::text = $2 AND job_description_hash = $3`
+
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		rows, err := r.DB.QueryContext(ctx, page, afterID, batch)
+		if err != nil {
+			return report, err
+		}
+		type row struct{ id, jd, hash string }
+		var found []row
+		for rows.Next() {
+			var rw row
+			if err := rows.Scan(&rw.id, &rw.jd, &rw.hash); err != nil {
+				rows.Close()
+				return report, err
+			}
+			found = append(found, rw)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return report, err
+		}
+
+		for _, rw := range found {
+			report.Scanned++
+			afterID = rw.id
+			jd, err := r.Codec.Decrypt(jobDescriptionColumn, rw.jd)
+			if err != nil {
+				report.Failed++
+				continue
+			}
+			hash := HashJobDescription(jd)
+			if hash == rw.hash {
+				continue
+			}
+			if !r.Apply {
+				report.Rewritten++
+				continue
+			}
+			res, err := r.DB.ExecContext(ctx, update, hash, rw.id, rw.hash)
+			if err != nil {
+				return report, err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				report.Skipped++
+				continue
+			}
+			report.Rewritten++
+		}
+		if len(found) < batch {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package analyses
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRehashRewritesHashesThatDisagreeWithGo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Postgres \s does not match a no-break space, so the migration kept it.
+	jd := "Go\u00a0engineer\n"
+	stale := "sql-backfilled"
+	fresh := HashJobDescription("Backend engineer")
+
+	mock.ExpectQuery(`SELECT id::text, COALESCE\(job_description, ''\), job_description_hash`).
+		WithArgs("", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "job_description", "job_description_hash"}).
+			AddRow("a-1", jd, stale).
+			AddRow("a-2", "Backend engineer", fresh))
+	mock.ExpectExec(`UPDATE analyses SET job_description_hash = \$1`).
+		WithArgs(HashJobDescription("Go engineer"), "a-1", stale).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id::text`).
+		WithArgs("a-2", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "job_description", "job_description_hash"}))
+
+	report, err := (&Rehash{DB: db, Apply: true, BatchSize: 2}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Scanned != 2 || report.Rewritten != 1 || report.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
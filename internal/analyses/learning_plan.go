@@ -0,0 +1,221 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/llm/prompts"
+)
+
+const (
+	// maxLearningPlanSkills bounds how many missing skills get a learning path.
+	maxLearningPlanSkills = 8
+	maxLearningPlanSteps  = 4
+	maxLearningStepRunes  = 200
+	maxLearningPlanHours  = 200
+	// learningPlanResumeRunes keeps the prompt small; the plan only needs a
+	// sense of the candidate's adjacent experience.
+	learningPlanResumeRunes = 6000
+)
+
+// learningResourceCategories are the allowed resourceCategories values.
+var learningResourceCategories = map[string]bool{
+	"course":        true,
+	"documentation": true,
+	"book":          true,
+	"project":       true,
+	"certification": true,
+	"video":         true,
+	"community":     true,
+}
+
+// PromptCompleter completes a single prompt and returns the raw model output.
+type PromptCompleter interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// LearningPlanV1 is the optional learningPlan section of a job-match result.
+type LearningPlanV1 struct {
+	Skills     []SkillLearningPathV1 `json:"skills"`
+	TotalHours int                   `json:"totalHours"`
+}
+
+// SkillLearningPathV1 is a short path to pick up one missing skill.
+type SkillLearningPathV1 struct {
+	Skill              string   `json:"skill"`
+	Steps              []string `json:"steps"`
+	ResourceCategories []string `json:"resourceCategories"`
+	EstimatedHours     int      `json:"estimatedHours"`
+}
+
+// generateLearningPlan asks the LearningPlan model for a path per missing skill
+// and validates the response against its schema. A rejected response is retried
+// once with the validation error as feedback.
+func (s *Service) generateLearningPlan(ctx context.Context, analysis Analysis, resumeText string, skills []string) (LearningPlanV1, error) {
+	if len(skills) == 0 {
+		return LearningPlanV1{Skills: []SkillLearningPathV1{}}, nil
+	}
+	if s.LearningPlan == nil {
+		return LearningPlanV1{}, errors.New("learning plan model is not configured")
+	}
+	prompt := buildLearningPlanPrompt(analysis.JobDescription, resumeText, skills)
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		attemptPrompt := prompt
+		if lastErr != nil {
+			attemptPrompt += "\n\nYour previous output was rejected: " + lastErr.Error() + ". Return corrected JSON only."
+		}
+		raw, err := s.LearningPlan.Complete(ctx, attemptPrompt)
+		if err != nil {
+			return LearningPlanV1{}, fmt.Errorf("llm complete: %w", err)
+		}
+		plan, err := parseLearningPlan(raw, skills)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return plan, nil
+	}
+	return LearningPlanV1{}, fmt.Errorf("invalid learning plan: %w", lastErr)
+}
+
+func buildLearningPlanPrompt(jobDescription, resumeText string, skills []string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(prompts.LearningPlan))
+	b.WriteString("\n\nMissing skills:\n")
+	for _, skill := range skills {
+		b.WriteString("- ")
+		b.WriteString(skill)
+		b.WriteString("\n")
+	}
+	b.WriteString("\nJob description:\n")
+	b.WriteString(strings.TrimSpace(jobDescription))
+	b.WriteString("\n\nResume:\n")
+	b.WriteString(truncateRunes(strings.TrimSpace(resumeText), learningPlanResumeRunes))
+	return b.String()
+}
+
+func parseLearningPlan(raw string, skills []string) (LearningPlanV1, error) {
+	payload := strings.TrimSpace(raw)
+	if start, end := strings.Index(payload, "{"), strings.LastIndex(payload, "}"); start >= 0 && end > start {
+		payload = payload[start : end+1]
+	}
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.DisallowUnknownFields()
+	var parsed struct {
+		Skills *[]SkillLearningPathV1 `json:"skills"`
+	}
+	if err := dec.Decode(&parsed); err != nil {
+		return LearningPlanV1{}, fmt.Errorf("schema: %w", err)
+	}
+	if parsed.Skills == nil {
+		return LearningPlanV1{}, errors.New("schema: skills is required")
+	}
+	if len(*parsed.Skills) != len(skills) {
+		return LearningPlanV1{}, fmt.Errorf("schema: skills must have %d items, one per missing skill", len(skills))
+	}
+	plan := LearningPlanV1{Skills: make([]SkillLearningPathV1, 0, len(skills))}
+	for i, path := range *parsed.Skills {
+		if !strings.EqualFold(strings.TrimSpace(path.Skill), skills[i]) {
+			return LearningPlanV1{}, fmt.Errorf("schema: skills[%d].skill must be %q", i, skills[i])
+		}
+		path.Skill = skills[i]
+		if len(path.Steps) == 0 || len(path.Steps) > maxLearningPlanSteps {
+			return LearningPlanV1{}, fmt.Errorf("schema: skills[%d].steps must have 1-%d items", i, maxLearningPlanSteps)
+		}
+		for j, step := range path.Steps {
+			step = strings.TrimSpace(step)
+			if step == "" || utf8.RuneCountInString(step) > maxLearningStepRunes {
+				return LearningPlanV1{}, fmt.Errorf("schema: skills[%d].steps[%d] must be 1-%d chars", i, j, maxLearningStepRunes)
+			}
+			path.Steps[j] = step
+		}
+		if len(path.ResourceCategories) == 0 || len(path.ResourceCategories) > 3 {
+			return LearningPlanV1{}, fmt.Errorf("schema: skills[%d].resourceCategories must have 1-3 items", i)
+		}
+		seen := map[string]bool{}
+		categories := make([]string, 0, len(path.ResourceCategories))
+		for _, category := range path.ResourceCategories {
+			category = strings.ToLower(strings.TrimSpace(category))
+			if !learningResourceCategories[category] {
+				return LearningPlanV1{}, fmt.Errorf("schema: skills[%d].resourceCategories contains invalid value: %s", i, category)
+			}
+			if !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+		path.ResourceCategories = categories
+		if path.EstimatedHours < 1 || path.EstimatedHours > maxLearningPlanHours {
+			return LearningPlanV1{}, fmt.Errorf("schema: skills[%d].estimatedHours must be between 1 and %d", i, maxLearningPlanHours)
+		}
+		plan.TotalHours += path.EstimatedHours
+		plan.Skills = append(plan.Skills, path)
+	}
+	return plan, nil
+}
+
+// missingJobSkills returns the job description keywords the normalized result
+// found missing from the resume, deduplicated and capped.
+func missingJobSkills(result map[string]any) []string {
+	ats, _ := result["ats"].(map[string]any)
+	missing, _ := ats["missingKeywords"].(map[string]any)
+	var out []string
+	seen := map[string]bool{}
+	for _, keyword := range extractStringSlice(missing["fromJobDescription"]) {
+		keyword = strings.TrimSpace(keyword)
+		key := strings.ToLower(keyword)
+		if keyword == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, keyword)
+		if len(out) == maxLearningPlanSkills {
+			break
+		}
+	}
+	return out
+}
+
+// withLearningPlan adds a learning plan to a job-match result when one was
+// requested. A plan that cannot be generated does not fail the analysis; it is
+// noted in meta.limitations instead.
+func (s *Service) withLearningPlan(ctx context.Context, result map[string]any, analysis Analysis, resumeText string) {
+	if !analysis.LearningPlan || analysis.Mode != ModeJobMatch || result == nil {
+		return
+	}
+	plan, err := s.generateLearningPlan(ctx, analysis, resumeText, missingJobSkills(result))
+	if err != nil {
+		telemetry.ErrorContext(ctx, "analysis.learning_plan.failed", map[string]any{"err": err.Error()})
+		addLimitation(result, "learningPlan is unavailable because the plan could not be generated")
+		return
+	}
+	result["learningPlan"] = plan
+}
+
+func addLimitation(result map[string]any, limitation string) {
+	meta, ok := result["meta"].(map[string]any)
+	if !ok {
+		return
+	}
+	switch existing := meta["limitations"].(type) {
+	case []any:
+		meta["limitations"] = append(existing, limitation)
+	case []string:
+		meta["limitations"] = append(existing, limitation)
+	default:
+		meta["limitations"] = []any{limitation}
+	}
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
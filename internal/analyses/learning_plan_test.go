@@ -0,0 +1,205 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/documents"
+	local "resume-backend/internal/shared/storage/object/local"
+	"resume-backend/internal/usage"
+)
+
+type stubCompleter struct {
+	responses []string
+	prompts   []string
+}
+
+func (s *stubCompleter) Complete(ctx context.Context, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	if len(s.responses) == 0 {
+		return "", errors.New("no response")
+	}
+	out := s.responses[0]
+	s.responses = s.responses[1:]
+	return out, nil
+}
+
+func TestParseLearningPlanValidatesSchema(t *testing.T) {
+	skills := []string{"Kubernetes", "Terraform"}
+	good := `{"skills":[
+		{"skill":"kubernetes","steps":["Take an intro course"],"resourceCategories":["Course","project"],"estimatedHours":20},
+		{"skill":"Terraform","steps":["Read the docs","Build a small module"],"resourceCategories":["documentation"],"estimatedHours":8}
+	]}`
+	plan, err := parseLearningPlan(good, skills)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if plan.TotalHours != 28 || plan.Skills[0].Skill != "Kubernetes" || plan.Skills[0].ResourceCategories[0] != "course" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	for name, bad := range map[string]string{
+		"missing skill":   `{"skills":[{"skill":"Kubernetes","steps":["a"],"resourceCategories":["course"],"estimatedHours":1}]}`,
+		"wrong order":     `{"skills":[{"skill":"Terraform","steps":["a"],"resourceCategories":["course"],"estimatedHours":1},{"skill":"Kubernetes","steps":["a"],"resourceCategories":["course"],"estimatedHours":1}]}`,
+		"unknown field":   `{"skills":[],"notes":"x"}`,
+		"bad category":    `{"skills":[{"skill":"Kubernetes","steps":["a"],"resourceCategories":["bootcamp"],"estimatedHours":1},{"skill":"Terraform","steps":["a"],"resourceCategories":["course"],"estimatedHours":1}]}`,
+		"hours too large": `{"skills":[{"skill":"Kubernetes","steps":["a"],"resourceCategories":["course"],"estimatedHours":500},{"skill":"Terraform","steps":["a"],"resourceCategories":["course"],"estimatedHours":1}]}`,
+		"no steps":        `{"skills":[{"skill":"Kubernetes","steps":[],"resourceCategories":["course"],"estimatedHours":5},{"skill":"Terraform","steps":["a"],"resourceCategories":["course"],"estimatedHours":1}]}`,
+	} {
+		if _, err := parseLearningPlan(bad, skills); err == nil {
+			t.Fatalf("%s: expected rejection", name)
+		}
+	}
+}
+
+func TestProcessAnalysisAddsRequestedLearningPlan(t *testing.T) {
+	var fixture map[string]any
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &fixture); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	fixture["meta"].(map[string]any)["jobDescriptionProvided"] = true
+	fixture["ats"].(map[string]any)["missingKeywords"].(map[string]any)["fromJobDescription"] = []string{"Kubernetes", "Terraform"}
+	_, analysisRepo, svc := setupAnalysisRouterWithLLM(t, mustMarshal(t, fixture))
+
+	// The first response is rejected and retried with the validation error.
+	completer := &stubCompleter{responses: []string{
+		`{"skills":[]}`,
+		`{"skills":[{"skill":"Kubernetes","steps":["Deploy a sample app to a local cluster"],"resourceCategories":["project"],"estimatedHours":15},` +
+			`{"skill":"Terraform","steps":["Follow the getting started guide"],"resourceCategories":["documentation"],"estimatedHours":6}]}`,
+	}}
+	svc.LearningPlan = completer
+
+	ctx := context.Background()
+	for _, a := range []Analysis{
+		{ID: "with-plan", LearningPlan: true},
+		{ID: "without-plan"},
+	} {
+		a.DocumentID = "doc-guest:test-guest"
+		a.UserID = "guest:test-guest"
+		a.JobDescription = strings.Repeat("Kubernetes and Terraform required. ", 10)
+		a.PromptVersion = "v2_3"
+		a.Mode = ModeJobMatch
+		a.Status = StatusQueued
+		a.CreatedAt = time.Now().UTC()
+		if err := analysisRepo.Create(ctx, a); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if err := svc.ProcessAnalysis(ctx, a.ID); err != nil {
+			t.Fatalf("process %s: %v", a.ID, err)
+		}
+	}
+
+	got, _ := analysisRepo.GetByID(ctx, "with-plan")
+	plan, ok := got.Result["learningPlan"].(LearningPlanV1)
+	if !ok {
+		t.Fatalf("expected a learning plan, got %T", got.Result["learningPlan"])
+	}
+	if len(plan.Skills) != 2 || plan.TotalHours != 21 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(completer.prompts) != 2 || !strings.Contains(completer.prompts[1], "previous output was rejected") {
+		t.Fatalf("expected one retry with feedback, got %d prompts", len(completer.prompts))
+	}
+	if !strings.Contains(completer.prompts[0], "- Kubernetes\n- Terraform\n") {
+		t.Fatalf("expected the missing skills in the prompt")
+	}
+
+	got, _ = analysisRepo.GetByID(ctx, "without-plan")
+	if _, ok := got.Result["learningPlan"]; ok {
+		t.Fatalf("expected no learning plan unless requested")
+	}
+}
+
+func TestLearningPlanFailureDoesNotFailAnalysis(t *testing.T) {
+	var fixture map[string]any
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &fixture); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	fixture["meta"].(map[string]any)["jobDescriptionProvided"] = true
+	fixture["ats"].(map[string]any)["missingKeywords"].(map[string]any)["fromJobDescription"] = []string{"Go"}
+	_, analysisRepo, svc := setupAnalysisRouterWithLLM(t, mustMarshal(t, fixture))
+	svc.LearningPlan = &stubCompleter{}
+
+	ctx := context.Background()
+	if err := analysisRepo.Create(ctx, Analysis{
+		ID: "a1", DocumentID: "doc-guest:test-guest", UserID: "guest:test-guest", JobDescription: strings.Repeat("Go ", 120),
+		PromptVersion: "v2_3", Mode: ModeJobMatch, Status: StatusQueued, LearningPlan: true, CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, "a1"); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	got, _ := analysisRepo.GetByID(ctx, "a1")
+	if got.Status != StatusCompleted {
+		t.Fatalf("expected completed, got %s", got.Status)
+	}
+	meta, _ := got.Result["meta"].(map[string]any)
+	limitations, _ := meta["limitations"].([]any)
+	if len(limitations) == 0 || !strings.Contains(limitations[len(limitations)-1].(string), "learningPlan") {
+		t.Fatalf("expected the missing plan noted in limitations, got %v", meta["limitations"])
+	}
+}
+
+func TestLearningPlanRequiresEntitlementAndJobMatch(t *testing.T) {
+	ctx := context.Background()
+	usageSvc := usage.NewService()
+	svc := &Service{
+		Repo:     NewMemoryRepo(),
+		Usage:    usageSvc,
+		DocRepo:  documents.NewMemoryRepo(),
+		Store:    local.New(t.TempDir()),
+		LLM:      stubLLM{},
+		JobQueue: &stubQueue{},
+	}
+	jd := strings.Repeat("a", 300)
+	opts := StartOptions{LearningPlan: true, ForceNew: true}
+
+	if _, _, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", jd, "v2_3", ModeJobMatch, false, opts); !errors.Is(err, usage.ErrFeatureNotEntitled) {
+		t.Fatalf("starter plan: expected ErrFeatureNotEntitled, got %v", err)
+	}
+	if _, _, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", "", "v2_3", ModeATS, false, opts); !errors.Is(err, ErrLearningPlanRequiresJobMatch) {
+		t.Fatalf("ats mode: expected ErrLearningPlanRequiresJobMatch, got %v", err)
+	}
+
+	if _, err := usageSvc.SetOrgQuota(ctx, "acme", "Team", 100, 0); err != nil {
+		t.Fatalf("set org quota: %v", err)
+	}
+	if _, err := usageSvc.AddOrgMember(ctx, "acme", "user-1"); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	opts.OrgID = "acme"
+	analysis, created, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", jd, "v2_3", ModeJobMatch, false, opts)
+	if err != nil || !created || !analysis.LearningPlan {
+		t.Fatalf("team plan: expected a created analysis with a learning plan, got %+v created=%v err=%v", analysis, created, err)
+	}
+}
+
+func TestLearningPlanRequestDoesNotReuseAnalysisWithoutPlan(t *testing.T) {
+	ctx := context.Background()
+	svc := &Service{
+		Repo:     NewMemoryRepo(),
+		DocRepo:  documents.NewMemoryRepo(),
+		Store:    local.New(t.TempDir()),
+		LLM:      stubLLM{},
+		JobQueue: &stubQueue{},
+	}
+	jd := strings.Repeat("a", 300)
+
+	plain, _, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", jd, "v2_3", ModeJobMatch, false, StartOptions{})
+	if err != nil {
+		t.Fatalf("start without plan: %v", err)
+	}
+	withPlan, created, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", jd, "v2_3", ModeJobMatch, false, StartOptions{LearningPlan: true})
+	if err != nil || !created || withPlan.ID == plain.ID || !withPlan.LearningPlan {
+		t.Fatalf("expected a new analysis with a plan, got %+v created=%v err=%v", withPlan, created, err)
+	}
+	again, created, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", jd, "v2_3", ModeJobMatch, false, StartOptions{LearningPlan: true})
+	if err != nil || created || again.ID != withPlan.ID {
+		t.Fatalf("expected reuse of %s, got %s created=%v err=%v", withPlan.ID, again.ID, created, err)
+	}
+}
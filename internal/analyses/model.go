@@ -4,16 +4,25 @@ import "time"
 
 // Analysis represents a document analysis job.
 type Analysis struct {
-	ID                  string         `json:"id"`
-	DocumentID          string         `json:"documentId"`
-	UserID              string         `json:"userId"`
-	JobDescription      string         `json:"jobDescription"`
-	PromptVersion       string         `json:"promptVersion"`
-	Mode                AnalysisMode   `json:"mode"`
-	AnalysisVersion     string         `json:"analysisVersion"`
-	PromptHash          string         `json:"promptHash"`
-	Provider            string         `json:"provider"`
-	Model               string         `json:"model"`
+	ID         string `json:"id"`
+	DocumentID string `json:"documentId"`
+	UserID     string `json:"userId"`
+	// OrgID is the organization the analysis is charged to, if any.
+	OrgID               string               `json:"orgId,omitempty"`
+	JobDescription      string               `json:"jobDescription"`
+	JobDescriptionHash  string               `json:"-"`
+	PromptVersion       string               `json:"promptVersion"`
+	Mode                AnalysisMode         `json:"mode"`
+	AnalysisVersion     string               `json:"analysisVersion"`
+	PromptHash          string               `json:"promptHash"`
+	Provider            string               `json:"provider"`
+	Model               string               `json:"model"`
+	SupportingDocuments []SupportingDocument `json:"supportingDocuments,omitempty"`
+	// LearningPlan requests a skill gap learning plan with a job-match result.
+	LearningPlan bool `json:"learningPlan,omitempty"`
+	// TargetRole is the role the user is aiming for, as they entered it. ATS
+	// analyses without a job description are evaluated against it.
+	TargetRole          string         `json:"targetRole,omitempty"`
 	ErrorCode           string         `json:"errorCode,omitempty"`
 	ErrorMessage        *string        `json:"errorMessage,omitempty"`
 	ErrorRetryable      bool           `json:"retryable,omitempty"`
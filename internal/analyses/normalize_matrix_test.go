@@ -0,0 +1,96 @@
+package analyses
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Regenerate the normalization golden files after an intended output change with:
+//
+//	go test ./internal/analyses -run TestNormalizeVersionMatrix -update
+//
+// and review the diff of testdata/normalized before committing it.
+var updateNormalized = flag.Bool("update", false, "rewrite golden files in testdata/normalized")
+
+const normalizedGoldenDir = "testdata/normalized"
+
+// normalizeMatrixFixtures lists one or more stored model outputs per prompt
+// version. Every version in resultNormalizers needs at least one.
+var normalizeMatrixFixtures = []struct {
+	name    string
+	version string
+}{
+	{name: "v1_good", version: "v1"},
+	{name: "v1_shuffled", version: "v1"},
+	{name: "v2_good", version: "v2"},
+	{name: "v2_1_good", version: "v2_1"},
+	{name: "v2_2_good", version: "v2_2"},
+	{name: "v2_3_good", version: "v2_3"},
+}
+
+var normalizeMatrixModes = []AnalysisMode{ModeATS, ModeJobMatch}
+
+func TestNormalizeVersionMatrixCoversEveryVersion(t *testing.T) {
+	covered := map[string]bool{}
+	for _, fixture := range normalizeMatrixFixtures {
+		covered[fixture.version] = true
+	}
+	if !covered["v1"] {
+		t.Fatalf("expected a v1 fixture in the matrix")
+	}
+	for version := range resultNormalizers {
+		if !covered[version] {
+			t.Fatalf("prompt version %s has a normalizer but no matrix fixture", version)
+		}
+	}
+}
+
+func TestNormalizeVersionMatrix(t *testing.T) {
+	for _, fixture := range normalizeMatrixFixtures {
+		raw := loadFixture(t, filepath.Join("testdata", fixture.name+".json"))
+		for _, mode := range normalizeMatrixModes {
+			name := fixture.name + "_" + strings.ToLower(string(mode))
+			t.Run(name, func(t *testing.T) {
+				analysis := Analysis{
+					PromptVersion:  fixture.version,
+					Model:          "test-model",
+					Mode:           mode,
+					JobDescription: "Senior Go engineer building distributed systems on AWS.",
+				}
+				result, err := normalizeAnalysisResult(raw, analysis)
+				if err != nil {
+					t.Fatalf("normalize: %v", err)
+				}
+				got, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					t.Fatalf("marshal: %v", err)
+				}
+				got = append(got, '\n')
+				path := filepath.Join(normalizedGoldenDir, name+".json")
+
+				if *updateNormalized {
+					if err := os.MkdirAll(normalizedGoldenDir, 0o755); err != nil {
+						t.Fatalf("create golden dir: %v", err)
+					}
+					if err := os.WriteFile(path, got, 0o644); err != nil {
+						t.Fatalf("write golden file: %v", err)
+					}
+					return
+				}
+
+				want, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("read golden file (run with -update to create it): %v", err)
+				}
+				if !bytes.Equal(want, got) {
+					t.Fatalf("%s does not match the normalized output (run with -update if the change is intended):\n%s", path, got)
+				}
+			})
+		}
+	}
+}
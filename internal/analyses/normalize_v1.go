@@ -0,0 +1,59 @@
+package analyses
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// normalizerV1 reads results without meta. Older v1 outputs put
+// missingKeywords and formattingIssues at the top level; those win over the
+// copies inside ats.
+type normalizerV1 struct{}
+
+func (normalizerV1) normalize(raw json.RawMessage, top map[string]any, analysis Analysis) (NormalizedAnalysisResult, error) {
+	var parsed AnalysisResultV1
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return NormalizedAnalysisResult{}, err
+	}
+	v2 := upgradeV1(parsed, analysis, extractStringSlice(top["missingKeywords"]), extractStringSlice(top["formattingIssues"]))
+	return normalizeFromV2_3(upgradeV2_2(upgradeV2_1(upgradeV2(v2))), analysis), nil
+}
+
+// upgradeV1 fills the meta v1 lacks from the analysis record.
+func upgradeV1(r AnalysisResultV1, analysis Analysis, topMissing, topFormatting []string) AnalysisResultV2 {
+	missingKeywords := []string(r.ATS.MissingKeywords)
+	if len(topMissing) > 0 {
+		missingKeywords = topMissing
+	}
+	formattingIssues := r.ATS.FormattingIssues
+	if len(topFormatting) > 0 {
+		formattingIssues = topFormatting
+	}
+	issues := make([]IssueV2, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		issues = append(issues, IssueV2{
+			Severity:     issue.Severity,
+			Section:      issue.Section,
+			Problem:      issue.Problem,
+			WhyItMatters: issue.WhyItMatters,
+			Suggestion:   issue.Suggestion,
+		})
+	}
+	return AnalysisResultV2{
+		Meta: MetaV2{
+			PromptVersion:          fallbackString(analysis.PromptVersion, "v1"),
+			Model:                  analysis.Model,
+			JobDescriptionProvided: strings.TrimSpace(analysis.JobDescription) != "",
+		},
+		Summary: r.Summary,
+		ATS: ATSV2{
+			Score:            r.ATS.Score,
+			MissingKeywords:  MissingKeywordsV2{FromJobDescription: missingKeywords},
+			FormattingIssues: formattingIssues,
+		},
+		Issues:             issues,
+		BulletRewrites:     r.BulletRewrites,
+		MissingInformation: r.MissingInformation,
+		ActionPlan:         r.ActionPlan,
+	}
+}
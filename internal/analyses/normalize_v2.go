@@ -0,0 +1,48 @@
+package analyses
+
+import "encoding/json"
+
+type normalizerV2 struct{}
+
+func (normalizerV2) normalize(raw json.RawMessage, top map[string]any, analysis Analysis) (NormalizedAnalysisResult, error) {
+	var parsed AnalysisResultV2
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return NormalizedAnalysisResult{}, err
+	}
+	return normalizeFromV2_3(upgradeV2_2(upgradeV2_1(upgradeV2(parsed))), analysis), nil
+}
+
+// upgradeV2 adds issue priorities and rewrite metrics sources, both left
+// unset so later steps apply their defaults.
+func upgradeV2(r AnalysisResultV2) AnalysisResultV2_1 {
+	issues := make([]IssueV2_1, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		issues = append(issues, IssueV2_1{
+			Severity:     issue.Severity,
+			Section:      issue.Section,
+			Problem:      issue.Problem,
+			WhyItMatters: issue.WhyItMatters,
+			Suggestion:   issue.Suggestion,
+			Evidence:     issue.Evidence,
+			FixEffort:    issue.FixEffort,
+		})
+	}
+	bullets := make([]BulletRewriteV2_1, 0, len(r.BulletRewrites))
+	for _, br := range r.BulletRewrites {
+		bullets = append(bullets, BulletRewriteV2_1{
+			Section:   br.Section,
+			Before:    br.Before,
+			After:     br.After,
+			Rationale: br.Rationale,
+		})
+	}
+	return AnalysisResultV2_1{
+		Meta:               r.Meta,
+		Summary:            r.Summary,
+		ATS:                r.ATS,
+		Issues:             issues,
+		BulletRewrites:     bullets,
+		MissingInformation: r.MissingInformation,
+		ActionPlan:         r.ActionPlan,
+	}
+}
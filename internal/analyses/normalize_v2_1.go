@@ -0,0 +1,44 @@
+package analyses
+
+import "encoding/json"
+
+type normalizerV2_1 struct{}
+
+func (normalizerV2_1) normalize(raw json.RawMessage, top map[string]any, analysis Analysis) (NormalizedAnalysisResult, error) {
+	var parsed AnalysisResultV2_1
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return NormalizedAnalysisResult{}, err
+	}
+	return normalizeFromV2_3(upgradeV2_2(upgradeV2_1(parsed)), analysis), nil
+}
+
+// upgradeV2_1 adds score reasoning and the apply flags on issues, all empty.
+func upgradeV2_1(r AnalysisResultV2_1) AnalysisResultV2_2 {
+	issues := make([]IssueV2_2, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		issues = append(issues, IssueV2_2{
+			Severity:     issue.Severity,
+			Section:      issue.Section,
+			Problem:      issue.Problem,
+			WhyItMatters: issue.WhyItMatters,
+			Suggestion:   issue.Suggestion,
+			Evidence:     issue.Evidence,
+			FixEffort:    issue.FixEffort,
+			Priority:     issue.Priority,
+		})
+	}
+	return AnalysisResultV2_2{
+		Meta:    r.Meta,
+		Summary: r.Summary,
+		ATS: ATSV2_2{
+			Score:            r.ATS.Score,
+			ScoreBreakdown:   r.ATS.ScoreBreakdown,
+			MissingKeywords:  r.ATS.MissingKeywords,
+			FormattingIssues: r.ATS.FormattingIssues,
+		},
+		Issues:             issues,
+		BulletRewrites:     r.BulletRewrites,
+		MissingInformation: r.MissingInformation,
+		ActionPlan:         r.ActionPlan,
+	}
+}
@@ -0,0 +1,44 @@
+package analyses
+
+import "encoding/json"
+
+type normalizerV2_2 struct{}
+
+func (normalizerV2_2) normalize(raw json.RawMessage, top map[string]any, analysis Analysis) (NormalizedAnalysisResult, error) {
+	var parsed AnalysisResultV2_2
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return NormalizedAnalysisResult{}, err
+	}
+	return normalizeFromV2_3(upgradeV2_2(parsed), analysis), nil
+}
+
+// upgradeV2_2 adds the score explanation and rewrite claim support, both
+// empty, so rewrites fall back to inferred claims without evidence.
+func upgradeV2_2(r AnalysisResultV2_2) AnalysisResultV2_3 {
+	bullets := make([]BulletRewriteV2_3, 0, len(r.BulletRewrites))
+	for _, br := range r.BulletRewrites {
+		bullets = append(bullets, BulletRewriteV2_3{
+			Section:            br.Section,
+			Before:             br.Before,
+			After:              br.After,
+			Rationale:          br.Rationale,
+			MetricsSource:      br.MetricsSource,
+			PlaceholdersNeeded: br.PlaceholdersNeeded,
+		})
+	}
+	return AnalysisResultV2_3{
+		Meta:    r.Meta,
+		Summary: r.Summary,
+		ATS: ATSV2_3{
+			Score:            r.ATS.Score,
+			ScoreBreakdown:   r.ATS.ScoreBreakdown,
+			ScoreReasoning:   r.ATS.ScoreReasoning,
+			MissingKeywords:  r.ATS.MissingKeywords,
+			FormattingIssues: r.ATS.FormattingIssues,
+		},
+		Issues:             r.Issues,
+		BulletRewrites:     bullets,
+		MissingInformation: r.MissingInformation,
+		ActionPlan:         r.ActionPlan,
+	}
+}
@@ -0,0 +1,53 @@
+package analyses
+
+import "encoding/json"
+
+// normalizerV2_3 reads the newest schema. Older normalizers upgrade their
+// results step by step to it and share normalizeFromV2_3, so a new version
+// only needs an upgrade from v2_3 and a new target here.
+type normalizerV2_3 struct{}
+
+func (normalizerV2_3) normalize(raw json.RawMessage, top map[string]any, analysis Analysis) (NormalizedAnalysisResult, error) {
+	var parsed AnalysisResultV2_3
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return NormalizedAnalysisResult{}, err
+	}
+	out := normalizeFromV2_3(parsed, analysis)
+	out.Meta.ScoringVersion = parsed.ATS.ScoreExplanation.scoringVersion()
+	reconcileScoreExplanation(&out)
+	return out, nil
+}
+
+func normalizeFromV2_3(r AnalysisResultV2_3, analysis Analysis) NormalizedAnalysisResult {
+	ats := NormalizedATS{
+		Score:            r.ATS.Score,
+		ScoreBreakdown:   r.ATS.ScoreBreakdown,
+		ScoreReasoning:   r.ATS.ScoreReasoning,
+		ScoreExplanation: r.ATS.ScoreExplanation,
+		MissingKeywords:  r.ATS.MissingKeywords,
+		FormattingIssues: r.ATS.FormattingIssues,
+	}
+	bullets := make([]NormalizedBulletRewrite, 0, len(r.BulletRewrites))
+	for _, br := range r.BulletRewrites {
+		bullets = append(bullets, NormalizedBulletRewrite{
+			Section:            br.Section,
+			Before:             br.Before,
+			After:              br.After,
+			Rationale:          br.Rationale,
+			MetricsSource:      normalizeMetricsSource(br.MetricsSource),
+			PlaceholdersNeeded: ensureStringSlice(br.PlaceholdersNeeded),
+			ClaimSupport:       normalizeClaimSupport(br.ClaimSupport),
+			Evidence:           normalizeEvidence(br.Evidence),
+		})
+	}
+	return NormalizedAnalysisResult{
+		Meta:               normalizeMeta(r.Meta, analysis),
+		Summary:            normalizeSummary(r.Summary),
+		ATS:                normalizeATS(ats),
+		Issues:             ensureIssueList(r.Issues),
+		BulletRewrites:     ensureBulletList(bullets),
+		MissingInformation: ensureStringSlice(r.MissingInformation),
+		ActionPlan:         normalizeActionPlan(r.ActionPlan),
+		Recommendations:    []Recommendation{},
+	}
+}
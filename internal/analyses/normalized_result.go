@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	"resume-backend/internal/analyses/recommendations"
@@ -41,6 +42,9 @@ type NormalizedBulletRewrite struct {
 	PlaceholdersNeeded []string `json:"placeholdersNeeded"`
 	ClaimSupport       string   `json:"claimSupport"`
 	Evidence           string   `json:"evidence"`
+	// AlreadyApplied is set after normalization when an earlier apply run wrote
+	// this rewrite, or the text it starts from, into the document.
+	AlreadyApplied bool `json:"alreadyApplied,omitempty"`
 }
 
 func normalizeAnalysisResult(raw json.RawMessage, analysis Analysis) (map[string]any, error) {
@@ -60,6 +64,22 @@ func normalizeAnalysisResult(raw json.RawMessage, analysis Analysis) (map[string
 	return result, nil
 }
 
+// resultNormalizer maps one prompt version's stored output onto
+// NormalizedAnalysisResult. Recommendations, scores and validation are applied
+// afterwards, the same way for every version.
+type resultNormalizer interface {
+	normalize(raw json.RawMessage, top map[string]any, analysis Analysis) (NormalizedAnalysisResult, error)
+}
+
+// resultNormalizers is keyed by lower-case meta.promptVersion. Results without
+// meta, or with a version not listed here, are read as v1.
+var resultNormalizers = map[string]resultNormalizer{
+	"v2":   normalizerV2{},
+	"v2_1": normalizerV2_1{},
+	"v2_2": normalizerV2_2{},
+	"v2_3": normalizerV2_3{},
+}
+
 func normalizeToFinal(raw json.RawMessage, analysis Analysis) (NormalizedAnalysisResult, error) {
 	if len(raw) == 0 {
 		return NormalizedAnalysisResult{}, errors.New("empty analysis result")
@@ -72,67 +92,52 @@ func normalizeToFinal(raw json.RawMessage, analysis Analysis) (NormalizedAnalysi
 		return NormalizedAnalysisResult{}, err
 	}
 
+	out, err := normalizerFor(raw, top).normalize(raw, top, analysis)
+	if err != nil {
+		return NormalizedAnalysisResult{}, err
+	}
+	applyResultLimits(&out, currentResultLimits())
+	applyScores(&out, analysis.Mode, extractFloat(top["matchScore"]))
+	return out, validateNormalized(out)
+}
+
+// applyResultLimits caps issues and bullet rewrites, then generates
+// recommendations from what was kept, and records any drops in
+// meta.overflowCounts.
+func applyResultLimits(out *NormalizedAnalysisResult, limits ResultLimits) {
+	var overflow OverflowCountsV1
+	out.Issues, overflow.Issues = capIssues(out.Issues, limits.Issues)
+	out.BulletRewrites, overflow.BulletRewrites = capBulletRewrites(out.BulletRewrites, limits.BulletRewrites)
+	recs, dropped := recommendations.Generate(buildRecommendationInput(*out), limits.Recommendations)
+	overflow.Recommendations = recommendationOverflow(len(recs), dropped)
+	out.Recommendations = normalizeRecommendations(recs)
+	out.Meta.OverflowCounts = nil
+	if overflow != (OverflowCountsV1{}) {
+		out.Meta.OverflowCounts = &overflow
+	}
+}
+
+// recommendationOverflow counts the recommendations the response cap dropped.
+// The engine always ranks more candidates than it shows, so those beyond
+// recommendations.DefaultLimit are ordinary trimming, not overflow.
+func recommendationOverflow(kept, dropped int) int {
+	return max(0, min(kept+dropped, recommendations.DefaultLimit)-kept)
+}
+
+func normalizerFor(raw json.RawMessage, top map[string]any) resultNormalizer {
+	if _, ok := top["meta"]; !ok {
+		return normalizerV1{}
+	}
 	var envelope struct {
 		Meta struct {
 			PromptVersion string `json:"promptVersion"`
 		} `json:"meta"`
 	}
 	_ = json.Unmarshal(raw, &envelope)
-
-	hasMeta := false
-	if _, ok := top["meta"]; ok {
-		hasMeta = true
-	}
-
-	switch {
-	case hasMeta && strings.EqualFold(envelope.Meta.PromptVersion, "v2_3"):
-		var parsed AnalysisResultV2_3
-		if err := json.Unmarshal(raw, &parsed); err != nil {
-			return NormalizedAnalysisResult{}, err
-		}
-		out := normalizeFromV2_3(parsed, analysis)
-		out.Recommendations = normalizeRecommendations(recommendations.GenerateRecommendations(buildRecommendationInput(out)))
-		applyScores(&out, analysis.Mode, extractFloat(top["matchScore"]))
-		return out, validateNormalized(out)
-	case hasMeta && strings.EqualFold(envelope.Meta.PromptVersion, "v2_2"):
-		var parsed AnalysisResultV2_2
-		if err := json.Unmarshal(raw, &parsed); err != nil {
-			return NormalizedAnalysisResult{}, err
-		}
-		out := normalizeFromV2_2(parsed, analysis)
-		out.Recommendations = normalizeRecommendations(recommendations.GenerateRecommendations(buildRecommendationInput(out)))
-		applyScores(&out, analysis.Mode, extractFloat(top["matchScore"]))
-		return out, validateNormalized(out)
-	case hasMeta && strings.EqualFold(envelope.Meta.PromptVersion, "v2_1"):
-		var parsed AnalysisResultV2_1
-		if err := json.Unmarshal(raw, &parsed); err != nil {
-			return NormalizedAnalysisResult{}, err
-		}
-		out := normalizeFromV2_1(parsed, analysis)
-		out.Recommendations = normalizeRecommendations(recommendations.GenerateRecommendations(buildRecommendationInput(out)))
-		applyScores(&out, analysis.Mode, extractFloat(top["matchScore"]))
-		return out, validateNormalized(out)
-	case hasMeta && strings.EqualFold(envelope.Meta.PromptVersion, "v2"):
-		var parsed AnalysisResultV2
-		if err := json.Unmarshal(raw, &parsed); err != nil {
-			return NormalizedAnalysisResult{}, err
-		}
-		out := normalizeFromV2(parsed, analysis)
-		out.Recommendations = normalizeRecommendations(recommendations.GenerateRecommendations(buildRecommendationInput(out)))
-		applyScores(&out, analysis.Mode, extractFloat(top["matchScore"]))
-		return out, validateNormalized(out)
-	default:
-		var parsed AnalysisResultV1
-		if err := json.Unmarshal(raw, &parsed); err != nil {
-			return NormalizedAnalysisResult{}, err
-		}
-		topMissing := extractStringSlice(top["missingKeywords"])
-		topFormatting := extractStringSlice(top["formattingIssues"])
-		out := normalizeFromV1(parsed, analysis, topMissing, topFormatting)
-		out.Recommendations = normalizeRecommendations(recommendations.GenerateRecommendations(buildRecommendationInput(out)))
-		applyScores(&out, analysis.Mode, extractFloat(top["matchScore"]))
-		return out, validateNormalized(out)
+	if n, ok := resultNormalizers[strings.ToLower(envelope.Meta.PromptVersion)]; ok {
+		return n
 	}
+	return normalizerV1{}
 }
 
 func requireTopLevelFields(raw map[string]any) error {
@@ -155,252 +160,20 @@ func validateNormalized(out NormalizedAnalysisResult) error {
 	if out.Recommendations == nil {
 		return errors.New("recommendations must be a list")
 	}
-	return nil
-}
-
-func normalizeFromV1(r AnalysisResultV1, analysis Analysis, topMissing, topFormatting []string) NormalizedAnalysisResult {
-	meta := MetaV2{
-		PromptVersion:          fallbackString(analysis.PromptVersion, "v1"),
-		Model:                  analysis.Model,
-		JobDescriptionProvided: strings.TrimSpace(analysis.JobDescription) != "",
-		Confidence:             0,
-		Assumptions:            []string{},
-		Limitations:            []string{},
-		Mode:                   "",
-		PrimaryScoreType:       "",
-	}
-	if meta.Model == "" {
-		meta.Model = "unknown"
-	}
-	missingKeywords := []string(r.ATS.MissingKeywords)
-	if len(topMissing) > 0 {
-		missingKeywords = topMissing
-	}
-	formattingIssues := r.ATS.FormattingIssues
-	if len(topFormatting) > 0 {
-		formattingIssues = topFormatting
-	}
-	ats := NormalizedATS{
-		Score:            clampScore(r.ATS.Score),
-		ScoreBreakdown:   ScoreBreakdownV2{},
-		ScoreReasoning:   []string{},
-		ScoreExplanation: ScoreExplanationV1{},
-		MissingKeywords:  MissingKeywordsV2{FromJobDescription: ensureStringSlice(missingKeywords), IndustryCommon: []string{}},
-		FormattingIssues: ensureStringSlice(formattingIssues),
-	}
-	issues := make([]IssueV2_2, 0, len(r.Issues))
-	for _, issue := range r.Issues {
-		issues = append(issues, IssueV2_2{
-			Severity:          issue.Severity,
-			Section:           issue.Section,
-			Problem:           issue.Problem,
-			WhyItMatters:      issue.WhyItMatters,
-			Suggestion:        issue.Suggestion,
-			Evidence:          "",
-			FixEffort:         "",
-			Priority:          0,
-			AutoFixable:       false,
-			RequiresUserInput: []string{},
-		})
-	}
-	bullets := make([]NormalizedBulletRewrite, 0, len(r.BulletRewrites))
-	for _, br := range r.BulletRewrites {
-		bullets = append(bullets, NormalizedBulletRewrite{
-			Section:            br.Section,
-			Before:             br.Before,
-			After:              br.After,
-			Rationale:          br.Rationale,
-			MetricsSource:      "resume",
-			PlaceholdersNeeded: []string{},
-			ClaimSupport:       "inferred",
-			Evidence:           "notFound",
-		})
-	}
-
-	out := NormalizedAnalysisResult{
-		Meta:               normalizeMeta(meta, analysis),
-		Summary:            normalizeSummary(r.Summary),
-		ATS:                normalizeATS(ats),
-		Issues:             ensureIssueList(issues),
-		BulletRewrites:     ensureBulletList(bullets),
-		MissingInformation: ensureStringSlice(r.MissingInformation),
-		ActionPlan:         normalizeActionPlan(r.ActionPlan),
-		Recommendations:    []Recommendation{},
-	}
-	return out
-}
-
-func normalizeFromV2(r AnalysisResultV2, analysis Analysis) NormalizedAnalysisResult {
-	meta := normalizeMeta(r.Meta, analysis)
-	ats := NormalizedATS{
-		Score:            clampScore(r.ATS.Score),
-		ScoreBreakdown:   clampScoreBreakdown(r.ATS.ScoreBreakdown),
-		ScoreReasoning:   []string{},
-		ScoreExplanation: ScoreExplanationV1{},
-		MissingKeywords:  normalizeMissingKeywords(r.ATS.MissingKeywords),
-		FormattingIssues: ensureStringSlice(r.ATS.FormattingIssues),
-	}
-	issues := make([]IssueV2_2, 0, len(r.Issues))
-	for _, issue := range r.Issues {
-		issues = append(issues, IssueV2_2{
-			Severity:          issue.Severity,
-			Section:           issue.Section,
-			Problem:           issue.Problem,
-			WhyItMatters:      issue.WhyItMatters,
-			Suggestion:        issue.Suggestion,
-			Evidence:          issue.Evidence,
-			FixEffort:         issue.FixEffort,
-			Priority:          0,
-			AutoFixable:       false,
-			RequiresUserInput: []string{},
-		})
-	}
-	bullets := make([]NormalizedBulletRewrite, 0, len(r.BulletRewrites))
-	for _, br := range r.BulletRewrites {
-		bullets = append(bullets, NormalizedBulletRewrite{
-			Section:            br.Section,
-			Before:             br.Before,
-			After:              br.After,
-			Rationale:          br.Rationale,
-			MetricsSource:      "resume",
-			PlaceholdersNeeded: []string{},
-			ClaimSupport:       "inferred",
-			Evidence:           "notFound",
-		})
-	}
-	return NormalizedAnalysisResult{
-		Meta:               meta,
-		Summary:            normalizeSummary(r.Summary),
-		ATS:                normalizeATS(ats),
-		Issues:             ensureIssueList(issues),
-		BulletRewrites:     ensureBulletList(bullets),
-		MissingInformation: ensureStringSlice(r.MissingInformation),
-		ActionPlan:         normalizeActionPlan(r.ActionPlan),
-		Recommendations:    []Recommendation{},
-	}
-}
-
-func normalizeFromV2_1(r AnalysisResultV2_1, analysis Analysis) NormalizedAnalysisResult {
-	meta := normalizeMeta(r.Meta, analysis)
-	ats := NormalizedATS{
-		Score:            clampScore(r.ATS.Score),
-		ScoreBreakdown:   clampScoreBreakdown(r.ATS.ScoreBreakdown),
-		ScoreReasoning:   []string{},
-		ScoreExplanation: ScoreExplanationV1{},
-		MissingKeywords:  normalizeMissingKeywords(r.ATS.MissingKeywords),
-		FormattingIssues: ensureStringSlice(r.ATS.FormattingIssues),
-	}
-	issues := make([]IssueV2_2, 0, len(r.Issues))
-	for _, issue := range r.Issues {
-		issues = append(issues, IssueV2_2{
-			Severity:          issue.Severity,
-			Section:           issue.Section,
-			Problem:           issue.Problem,
-			WhyItMatters:      issue.WhyItMatters,
-			Suggestion:        issue.Suggestion,
-			Evidence:          issue.Evidence,
-			FixEffort:         issue.FixEffort,
-			Priority:          issue.Priority,
-			AutoFixable:       false,
-			RequiresUserInput: []string{},
-		})
-	}
-	bullets := make([]NormalizedBulletRewrite, 0, len(r.BulletRewrites))
-	for _, br := range r.BulletRewrites {
-		bullets = append(bullets, NormalizedBulletRewrite{
-			Section:            br.Section,
-			Before:             br.Before,
-			After:              br.After,
-			Rationale:          br.Rationale,
-			MetricsSource:      normalizeMetricsSource(br.MetricsSource),
-			PlaceholdersNeeded: ensureStringSlice(br.PlaceholdersNeeded),
-			ClaimSupport:       "inferred",
-			Evidence:           "notFound",
-		})
-	}
-	return NormalizedAnalysisResult{
-		Meta:               meta,
-		Summary:            normalizeSummary(r.Summary),
-		ATS:                normalizeATS(ats),
-		Issues:             ensureIssueList(issues),
-		BulletRewrites:     ensureBulletList(bullets),
-		MissingInformation: ensureStringSlice(r.MissingInformation),
-		ActionPlan:         normalizeActionPlan(r.ActionPlan),
-		Recommendations:    []Recommendation{},
-	}
-}
-
-func normalizeFromV2_2(r AnalysisResultV2_2, analysis Analysis) NormalizedAnalysisResult {
-	meta := normalizeMeta(r.Meta, analysis)
-	ats := NormalizedATS{
-		Score:            clampScore(r.ATS.Score),
-		ScoreBreakdown:   clampScoreBreakdown(r.ATS.ScoreBreakdown),
-		ScoreReasoning:   ensureStringSlice(r.ATS.ScoreReasoning),
-		ScoreExplanation: ScoreExplanationV1{},
-		MissingKeywords:  normalizeMissingKeywords(r.ATS.MissingKeywords),
-		FormattingIssues: ensureStringSlice(r.ATS.FormattingIssues),
-	}
-	bullets := make([]NormalizedBulletRewrite, 0, len(r.BulletRewrites))
-	for _, br := range r.BulletRewrites {
-		bullets = append(bullets, NormalizedBulletRewrite{
-			Section:            br.Section,
-			Before:             br.Before,
-			After:              br.After,
-			Rationale:          br.Rationale,
-			MetricsSource:      normalizeMetricsSource(br.MetricsSource),
-			PlaceholdersNeeded: ensureStringSlice(br.PlaceholdersNeeded),
-			ClaimSupport:       "inferred",
-			Evidence:           "notFound",
-		})
-	}
-	return NormalizedAnalysisResult{
-		Meta:               meta,
-		Summary:            normalizeSummary(r.Summary),
-		ATS:                normalizeATS(ats),
-		Issues:             ensureIssueList(r.Issues),
-		BulletRewrites:     ensureBulletList(bullets),
-		MissingInformation: ensureStringSlice(r.MissingInformation),
-		ActionPlan:         normalizeActionPlan(r.ActionPlan),
-		Recommendations:    []Recommendation{},
-	}
-}
-
-func normalizeFromV2_3(r AnalysisResultV2_3, analysis Analysis) NormalizedAnalysisResult {
-	meta := normalizeMeta(r.Meta, analysis)
-	ats := NormalizedATS{
-		Score:            clampScore(r.ATS.Score),
-		ScoreBreakdown:   clampScoreBreakdown(r.ATS.ScoreBreakdown),
-		ScoreReasoning:   ensureStringSlice(r.ATS.ScoreReasoning),
-		ScoreExplanation: r.ATS.ScoreExplanation,
-		MissingKeywords:  normalizeMissingKeywords(r.ATS.MissingKeywords),
-		FormattingIssues: ensureStringSlice(r.ATS.FormattingIssues),
-	}
-	bullets := make([]NormalizedBulletRewrite, 0, len(r.BulletRewrites))
-	for _, br := range r.BulletRewrites {
-		bullets = append(bullets, NormalizedBulletRewrite{
-			Section:            br.Section,
-			Before:             br.Before,
-			After:              br.After,
-			Rationale:          br.Rationale,
-			MetricsSource:      normalizeMetricsSource(br.MetricsSource),
-			PlaceholdersNeeded: ensureStringSlice(br.PlaceholdersNeeded),
-			ClaimSupport:       normalizeClaimSupport(br.ClaimSupport),
-			Evidence:           normalizeEvidence(br.Evidence),
-		})
-	}
-	return NormalizedAnalysisResult{
-		Meta:               meta,
-		Summary:            normalizeSummary(r.Summary),
-		ATS:                normalizeATS(ats),
-		Issues:             ensureIssueList(r.Issues),
-		BulletRewrites:     ensureBulletList(bullets),
-		MissingInformation: ensureStringSlice(r.MissingInformation),
-		ActionPlan:         normalizeActionPlan(r.ActionPlan),
-		Recommendations:    []Recommendation{},
+	if len(out.ATS.ScoreExplanation.Components) > 0 {
+		componentScore, ok := weightedComponentScore(out.ATS.ScoreExplanation)
+		if !ok {
+			return errors.New("ats.scoreExplanation.components weights must total 100")
+		}
+		if math.Abs(out.ATS.Score-math.Round(componentScore)) > ScoreReconcileThreshold {
+			return fmt.Errorf("ats.score %.0f does not reconcile with weighted components %.0f", out.ATS.Score, componentScore)
+		}
 	}
+	return nil
 }
 
 func normalizeMeta(meta MetaV2, analysis Analysis) MetaV2 {
+	meta.ScoringVersion = ""
 	meta.PromptVersion = fallbackString(meta.PromptVersion, analysis.PromptVersion)
 	meta.Model = fallbackString(meta.Model, analysis.Model)
 	if meta.Model == "" {
@@ -425,6 +198,7 @@ func normalizeMeta(meta MetaV2, analysis Analysis) MetaV2 {
 	if meta.Limitations == nil {
 		meta.Limitations = []string{}
 	}
+	meta.ScoreReconciliation = nil
 	return meta
 }
 
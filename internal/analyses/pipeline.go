@@ -0,0 +1,220 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+// ErrUnsupportedPipeline means no pipeline is registered for an analysis's mode
+// and prompt version.
+var ErrUnsupportedPipeline = errors.New("unsupported analysis mode and prompt version")
+
+// Pipeline turns a resume into a stored analysis result for one mode and prompt
+// version. The prompt text itself is picked by the LLM client from
+// AnalyzeInput.PromptVersion.
+type Pipeline struct {
+	// BuildInput finishes the LLM input before the prompt is built; nil leaves it
+	// unchanged.
+	BuildInput func(run *PipelineRun)
+	// Generate calls the LLM and validates its output. Raw output returned
+	// alongside an error is still stored for debugging.
+	Generate func(ctx context.Context, run *PipelineRun) (json.RawMessage, error)
+	// Validate re-checks stored output against the schema Generate enforces,
+	// without calling the LLM. It is used by ReplayAnalysis; nil skips the check.
+	Validate func(raw json.RawMessage) error
+	// Normalize converts validated output into the stored result; nil uses
+	// normalizeAnalysisResult.
+	Normalize func(raw json.RawMessage, analysis Analysis) (map[string]any, error)
+}
+
+// PipelineRun carries one analysis through its pipeline.
+type PipelineRun struct {
+	Analysis   Analysis
+	ResumeText string
+	Input      llm.AnalyzeInput
+	Client     llm.Client
+	// Revision and Quantification are attached to the result when set.
+	Revision       *Revision
+	Quantification *QuantificationReport
+
+	// fullOnly skips delta analysis, as shadow runs do.
+	fullOnly bool
+	svc      *Service
+}
+
+type pipelineKey struct {
+	mode          AnalysisMode
+	promptVersion string
+}
+
+// PipelineRegistry maps (mode, prompt version) to the pipeline that runs it.
+type PipelineRegistry struct {
+	mu        sync.RWMutex
+	pipelines map[pipelineKey]Pipeline
+}
+
+// NewPipelineRegistry returns an empty registry.
+func NewPipelineRegistry() *PipelineRegistry {
+	return &PipelineRegistry{pipelines: make(map[pipelineKey]Pipeline)}
+}
+
+// Register adds or replaces the pipeline for a mode and prompt version.
+func (r *PipelineRegistry) Register(mode AnalysisMode, promptVersion string, p Pipeline) {
+	if p.Generate == nil {
+		panic(fmt.Sprintf("analyses: pipeline %s/%s has no Generate step", mode, promptVersion))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pipelines[pipelineKey{mode: mode, promptVersion: promptVersion}] = p
+}
+
+// Lookup returns the pipeline for a mode and prompt version. An empty mode means
+// JOB_MATCH, matching how analyses are created.
+func (r *PipelineRegistry) Lookup(mode AnalysisMode, promptVersion string) (Pipeline, bool) {
+	if mode == "" {
+		mode = ModeJobMatch
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.pipelines[pipelineKey{mode: mode, promptVersion: promptVersion}]
+	return p, ok
+}
+
+// PromptVersions lists the prompt versions registered for a mode, sorted.
+func (r *PipelineRegistry) PromptVersions(mode AnalysisMode) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []string
+	for key := range r.pipelines {
+		if key.mode == mode {
+			out = append(out, key.promptVersion)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DefaultPipelines returns a registry with the built-in prompt versions for every
+// mode.
+func DefaultPipelines() *PipelineRegistry {
+	r := NewPipelineRegistry()
+	for _, mode := range []AnalysisMode{ModeATS, ModeJobMatch} {
+		r.Register(mode, "v1", Pipeline{Generate: generateV1, Validate: validateV1})
+		r.Register(mode, "v2_1", Pipeline{Generate: generateV1, Validate: validateV1})
+		r.Register(mode, "v2", Pipeline{Generate: validated("v2", ValidateV2WithRetry), Validate: validateV2})
+		r.Register(mode, "v2_2", Pipeline{Generate: validated("v2_2", ValidateV2_2WithRetry), Validate: validateStoredV2_2})
+		r.Register(mode, "v2_3", Pipeline{
+			BuildInput: func(run *PipelineRun) {
+				run.Quantification = scoreQuantification(run.ResumeText)
+				run.Input.Quantification = run.Quantification.signal()
+			},
+			Generate: generateV2_3,
+			Validate: validateStoredV2_3,
+		})
+	}
+	return r
+}
+
+var (
+	defaultPipelinesOnce sync.Once
+	defaultPipelines     *PipelineRegistry
+)
+
+func (s *Service) pipelines() *PipelineRegistry {
+	if s.Pipelines != nil {
+		return s.Pipelines
+	}
+	defaultPipelinesOnce.Do(func() { defaultPipelines = DefaultPipelines() })
+	return defaultPipelines
+}
+
+// checkPipeline rejects analyses that no pipeline could process.
+func (s *Service) checkPipeline(mode AnalysisMode, promptVersion string) error {
+	if _, ok := s.pipelines().Lookup(mode, promptVersion); !ok {
+		return fmt.Errorf("%w: mode=%s promptVersion=%q", ErrUnsupportedPipeline, mode, promptVersion)
+	}
+	return nil
+}
+
+// validated wraps a schema validator that retries on invalid output.
+func validated[T ~[]byte](version string, validate func(context.Context, llm.Client, llm.AnalyzeInput) (T, error)) func(context.Context, *PipelineRun) (json.RawMessage, error) {
+	return func(ctx context.Context, run *PipelineRun) (json.RawMessage, error) {
+		raw, err := validate(ctx, run.Client, run.Input)
+		if err != nil {
+			return nil, fmt.Errorf("llm validate %s: %w", version, err)
+		}
+		return json.RawMessage(raw), nil
+	}
+}
+
+// generateV2_3 tries a delta analysis against the user's previous resume before
+// falling back to the full prompt.
+func generateV2_3(ctx context.Context, run *PipelineRun) (json.RawMessage, error) {
+	if !run.fullOnly {
+		raw, revision := run.svc.tryDeltaAnalysis(ctx, run.Client, run.Analysis, run.ResumeText, run.Input)
+		if raw != nil {
+			run.Revision = revision
+			return raw, nil
+		}
+	}
+	return validated("v2_3", ValidateV2_3WithRetry)(ctx, run)
+}
+
+// generateV1 runs the unversioned-schema prompts, asking the model once to repair
+// output that is not valid JSON.
+func generateV1(ctx context.Context, run *PipelineRun) (json.RawMessage, error) {
+	raw, err := run.Client.AnalyzeResume(ctx, run.Input)
+	if err != nil {
+		return nil, fmt.Errorf("llm analyze: %w", err)
+	}
+	var parsed AnalysisResultV1
+	if err := json.Unmarshal(raw, &parsed); err == nil {
+		return raw, nil
+	}
+	rawRetry, err := run.Client.AnalyzeResume(ctxmeta.WithFixJSON(ctx, string(raw)), run.Input)
+	if err != nil {
+		return raw, fmt.Errorf("llm analyze retry: %w", err)
+	}
+	if err := json.Unmarshal(rawRetry, &parsed); err != nil {
+		return rawRetry, fmt.Errorf("llm output invalid: %w", err)
+	}
+	return rawRetry, nil
+}
+
+// validateV1 accepts output that generateV1 would have accepted.
+func validateV1(raw json.RawMessage) error {
+	var parsed AnalysisResultV1
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	return nil
+}
+
+// validateStoredV2_2 runs the schema and content checks of ValidateV2_2WithRetry.
+func validateStoredV2_2(raw json.RawMessage) error {
+	var parsed AnalysisResultV2_2
+	if err := parseAndValidateV2_2(raw, &parsed); err != nil {
+		return err
+	}
+	return ValidateContentV2_2(&parsed)
+}
+
+// validateStoredV2_3 runs the schema and content checks of ValidateV2_3WithRetry.
+func validateStoredV2_3(raw json.RawMessage) error {
+	var parsed AnalysisResultV2_3
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	SanitizeV2_3(&parsed)
+	if err := parsed.Validate(); err != nil {
+		return err
+	}
+	return ValidateContentV2_3(&parsed)
+}
@@ -0,0 +1,67 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPipelineCountsUnsupported is returned when the repo cannot count
+// analyses by pipeline stage.
+var ErrPipelineCountsUnsupported = errors.New("pipeline counts not supported")
+
+// unknownErrorCode stands in for failures recorded without an error code.
+const unknownErrorCode = "unknown"
+
+// PipelineCounts summarizes the unfinished analyses and recent failures for
+// the operator pipeline snapshot.
+type PipelineCounts struct {
+	// InFlight counts unfinished analyses by stage: queued, budget_deferred
+	// and processing. Every stage is present, zero or not.
+	InFlight map[string]int `json:"inFlight"`
+	// OldestQueuedAt is when the oldest analysis still queued was created.
+	OldestQueuedAt *time.Time `json:"oldestQueuedAt,omitempty"`
+	// ErrorCodes counts analyses failed since the requested time by error code.
+	ErrorCodes map[string]int `json:"errorCodes"`
+}
+
+// pipelineCountsSource is implemented by repos that can count analyses by
+// stage.
+type pipelineCountsSource interface {
+	// PipelineCounts counts unfinished analyses and those failed since the
+	// given time.
+	PipelineCounts(ctx context.Context, failedSince time.Time) (PipelineCounts, error)
+}
+
+var (
+	_ pipelineCountsSource = (*MemoryRepo)(nil)
+	_ pipelineCountsSource = (*PGRepo)(nil)
+)
+
+// inFlightStages are the statuses of analyses that have not finished.
+var inFlightStages = []string{StatusQueued, StatusBudgetDeferred, StatusProcessing}
+
+// PipelineCounts counts unfinished analyses by stage and the analyses failed
+// since the given time by error code.
+func (s *Service) PipelineCounts(ctx context.Context, failedSince time.Time) (PipelineCounts, error) {
+	source, ok := s.Repo.(pipelineCountsSource)
+	if !ok {
+		return PipelineCounts{}, ErrPipelineCountsUnsupported
+	}
+	counts, err := source.PipelineCounts(ctx, failedSince)
+	if err != nil {
+		return PipelineCounts{}, err
+	}
+	if counts.InFlight == nil {
+		counts.InFlight = map[string]int{}
+	}
+	for _, stage := range inFlightStages {
+		if _, ok := counts.InFlight[stage]; !ok {
+			counts.InFlight[stage] = 0
+		}
+	}
+	if counts.ErrorCodes == nil {
+		counts.ErrorCodes = map[string]int{}
+	}
+	return counts, nil
+}
@@ -0,0 +1,50 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPipelineCountsByStageAndErrorCode(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	now := time.Now().UTC()
+	recent, old := now.Add(-10*time.Minute), now.Add(-2*time.Hour)
+	for _, a := range []Analysis{
+		{ID: "q1", UserID: "u", Status: StatusQueued, CreatedAt: now.Add(-time.Minute)},
+		{ID: "q2", UserID: "u", Status: StatusQueued, CreatedAt: now.Add(-5 * time.Minute)},
+		{ID: "p1", UserID: "u", Status: StatusProcessing, CreatedAt: now},
+		{ID: "f1", UserID: "u", Status: StatusFailed, ErrorCode: "LLM_TIMEOUT", CompletedAt: &recent},
+		{ID: "f2", UserID: "u", Status: StatusFailed, CompletedAt: &recent},
+		{ID: "f3", UserID: "u", Status: StatusFailed, ErrorCode: "LLM_TIMEOUT", CompletedAt: &old},
+		{ID: "c1", UserID: "u", Status: StatusCompleted, CompletedAt: &recent},
+	} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	svc := &Service{Repo: repo}
+	counts, err := svc.PipelineCounts(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("counts: %v", err)
+	}
+	want := map[string]int{StatusQueued: 2, StatusProcessing: 1, StatusBudgetDeferred: 0}
+	for stage, n := range want {
+		if got, ok := counts.InFlight[stage]; !ok || got != n {
+			t.Errorf("in flight %s = %d (%v), want %d", stage, got, ok, n)
+		}
+	}
+	if counts.OldestQueuedAt == nil || !counts.OldestQueuedAt.Equal(now.Add(-5*time.Minute)) {
+		t.Errorf("oldest queued = %v", counts.OldestQueuedAt)
+	}
+	if counts.ErrorCodes["LLM_TIMEOUT"] != 1 || counts.ErrorCodes[unknownErrorCode] != 1 || len(counts.ErrorCodes) != 2 {
+		t.Errorf("error codes = %v", counts.ErrorCodes)
+	}
+
+	if _, err := (&Service{Repo: NewWorkerRepo(repo)}).PipelineCounts(ctx, now); !errors.Is(err, ErrPipelineCountsUnsupported) {
+		t.Fatalf("worker repo: expected ErrPipelineCountsUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStartOrReuseRejectsUnregisteredPipeline(t *testing.T) {
+	svc := &Service{Repo: NewMemoryRepo(), JobQueue: &stubQueue{}}
+
+	_, _, err := svc.StartOrReuse(context.Background(), "doc-1", "user-1", "", "v9", ModeATS, false)
+	if !errors.Is(err, ErrUnsupportedPipeline) {
+		t.Fatalf("expected ErrUnsupportedPipeline, got %v", err)
+	}
+
+	svc.Pipelines = NewPipelineRegistry()
+	svc.Pipelines.Register(ModeJobMatch, "v2_3", Pipeline{Generate: generateV1})
+	if _, _, err := svc.StartOrReuse(context.Background(), "doc-1", "user-1", "", "v2_3", ModeATS, false); !errors.Is(err, ErrUnsupportedPipeline) {
+		t.Fatalf("expected v2_3 to be rejected for ATS, got %v", err)
+	}
+	if _, _, err := svc.StartOrReuse(context.Background(), "doc-1", "user-1", "", "v2_3", ModeJobMatch, false); err != nil {
+		t.Fatalf("expected registered pipeline to be accepted, got %v", err)
+	}
+}
+
+func TestStartAnalysisUnknownPromptVersionReturns400(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, docRepo, _, store, queueStub := setupAnalysisRouter(t)
+	documentID := seedDocument(t, docRepo, store, "guest:test-guest")
+
+	body, _ := json.Marshal(map[string]string{"jobDescription": strings.Repeat("a", 300), "promptVersion": "v9"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/"+documentID+"/analyze", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if len(queueStub.messages) != 0 {
+		t.Fatalf("expected nothing to be queued, got %d messages", len(queueStub.messages))
+	}
+}
+
+func TestProcessAnalysisRunsRegisteredPipeline(t *testing.T) {
+	svc, repo, _, documentID := setupServiceWithDoc(t, &recordingLLM{})
+	fixture := loadFixture(t, "testdata/v2_3_good.json")
+
+	var gotInput string
+	svc.Pipelines = DefaultPipelines()
+	svc.Pipelines.Register(ModeATS, "custom_v1", Pipeline{
+		BuildInput: func(run *PipelineRun) {
+			run.Input.TargetRole = "staff engineer"
+		},
+		Generate: func(ctx context.Context, run *PipelineRun) (json.RawMessage, error) {
+			gotInput = run.Input.TargetRole + "|" + run.ResumeText
+			return fixture, nil
+		},
+		Normalize: func(raw json.RawMessage, analysis Analysis) (map[string]any, error) {
+			result, err := normalizeAnalysisResult(raw, analysis)
+			if err == nil {
+				result["pipeline"] = "custom_v1"
+			}
+			return result, err
+		},
+	})
+
+	ctx := context.Background()
+	analysis := Analysis{
+		ID:            "analysis-custom",
+		DocumentID:    documentID,
+		UserID:        "user-1",
+		PromptVersion: "custom_v1",
+		Mode:          ModeATS,
+		Status:        StatusQueued,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := repo.Create(ctx, analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, analysis.ID); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	if gotInput != "staff engineer|resume text" {
+		t.Fatalf("expected the pipeline to see the built input, got %q", gotInput)
+	}
+	got, _ := repo.GetByID(ctx, analysis.ID)
+	if got.Status != StatusCompleted || got.Result["pipeline"] != "custom_v1" {
+		t.Fatalf("expected the custom normalizer's result, got status %s result %v", got.Status, got.Result["pipeline"])
+	}
+}
@@ -0,0 +1,160 @@
+package analyses
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/extract"
+)
+
+// BuildVersion overrides the code version recorded in provenance. Release builds
+// can set it with -ldflags "-X resume-backend/internal/analyses.BuildVersion=...";
+// otherwise the VCS revision from the Go build info is used.
+var BuildVersion string
+
+// Extraction quality levels.
+const (
+	ExtractionQualityHigh   = "high"
+	ExtractionQualityMedium = "medium"
+	ExtractionQualityLow    = "low"
+)
+
+// ProvenanceV1 records how a result was produced. It is stored under
+// meta.provenance so consumers can decide how far to trust a result, for
+// example by skipping results extracted with low quality.
+type ProvenanceV1 struct {
+	ExtractionMethod  string              `json:"extractionMethod"`
+	ExtractionQuality string              `json:"extractionQuality"`
+	Provider          string              `json:"provider"`
+	Model             string              `json:"model"`
+	PromptHash        string              `json:"promptHash"`
+	CodeVersion       string              `json:"codeVersion"`
+	DurationsMs       ProvenanceDurations `json:"durationsMs"`
+}
+
+// ProvenanceDurations are per-stage processing times in milliseconds.
+type ProvenanceDurations struct {
+	Queue         float64 `json:"queue"`
+	Extraction    float64 `json:"extraction"`
+	Generation    float64 `json:"generation"`
+	Normalization float64 `json:"normalization"`
+	LearningPlan  float64 `json:"learningPlan,omitempty"`
+	Total         float64 `json:"total"`
+}
+
+func newProvenance(analysis Analysis, doc documents.Document, resumeText, promptHash string) ProvenanceV1 {
+	return ProvenanceV1{
+		ExtractionMethod:  fallbackString(extract.Method(doc.ExtractionMimeType(), doc.FileName), "unknown"),
+		ExtractionQuality: extractionQuality(resumeText),
+		Provider:          fallbackString(analysis.Provider, "unknown"),
+		Model:             fallbackString(analysis.Model, "unknown"),
+		PromptHash:        promptHash,
+		CodeVersion:       codeVersion(),
+	}
+}
+
+// withProvenance adds p to the normalized result's meta.
+func withProvenance(result map[string]any, p ProvenanceV1) {
+	if result == nil {
+		return
+	}
+	meta, ok := result["meta"].(map[string]any)
+	if !ok {
+		meta = map[string]any{}
+		result["meta"] = meta
+	}
+	meta["provenance"] = p
+}
+
+// storedProvenance returns the provenance of a stored result, if any.
+func storedProvenance(result map[string]any) (any, bool) {
+	meta, ok := result["meta"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	p, ok := meta["provenance"]
+	return p, ok && p != nil
+}
+
+// extractionQuality grades extracted text by how much of it reads as words.
+// Scanned PDFs and broken encodings yield little text or mostly symbols and
+// replacement characters.
+func extractionQuality(text string) string {
+	var letters, visible int
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		visible++
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letters++
+		}
+	}
+	words := len(strings.Fields(text))
+	if visible == 0 {
+		return ExtractionQualityLow
+	}
+	ratio := float64(letters) / float64(visible)
+	switch {
+	case words < 50 || ratio < 0.6:
+		return ExtractionQualityLow
+	case words < 150 || ratio < 0.8:
+		return ExtractionQualityMedium
+	default:
+		return ExtractionQualityHigh
+	}
+}
+
+var (
+	codeVersionOnce  sync.Once
+	codeVersionValue string
+)
+
+// codeVersion is BuildVersion, or the VCS revision the binary was built from
+// with a "-dirty" suffix for uncommitted changes.
+func codeVersion() string {
+	if BuildVersion != "" {
+		return BuildVersion
+	}
+	codeVersionOnce.Do(func() {
+		codeVersionValue = "unknown"
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		var revision string
+		var modified bool
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		switch {
+		case revision != "":
+			if len(revision) > 12 {
+				revision = revision[:12]
+			}
+			if modified {
+				revision += "-dirty"
+			}
+			codeVersionValue = revision
+		case info.Main.Version != "" && info.Main.Version != "(devel)":
+			codeVersionValue = info.Main.Version
+		}
+	})
+	return codeVersionValue
+}
+
+func msBetween(from, to time.Time) float64 {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return durationMs(&from, &to)
+}
@@ -0,0 +1,71 @@
+package analyses
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProcessAnalysisRecordsProvenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fixture := loadFixture(t, "testdata/v2_3_good.json")
+	router, analysisRepo, svc := setupAnalysisRouterWithLLM(t, fixture)
+
+	analysisID := startAnalysis(t, router, "v2_3")
+	if err := svc.ProcessAnalysis(context.Background(), analysisID); err != nil {
+		t.Fatalf("process analysis: %v", err)
+	}
+	waitForStatus(t, analysisRepo, analysisID, StatusCompleted)
+
+	resp := getAnalysis(t, router, analysisID)
+	meta, _ := resp.Result["meta"].(map[string]any)
+	provenance, ok := meta["provenance"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected meta.provenance, got meta=%v", meta)
+	}
+	if provenance["extractionMethod"] != "unknown" {
+		t.Fatalf("expected a text upload to have no extraction method, got %v", provenance["extractionMethod"])
+	}
+	if provenance["extractionQuality"] != ExtractionQualityLow {
+		t.Fatalf("expected two words of resume text to grade low, got %v", provenance["extractionQuality"])
+	}
+	if provenance["model"] == "" || provenance["codeVersion"] == "" {
+		t.Fatalf("expected model and code version, got %v", provenance)
+	}
+	durations, ok := provenance["durationsMs"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected per-stage durations, got %v", provenance["durationsMs"])
+	}
+	for _, stage := range []string{"queue", "extraction", "generation", "normalization", "total"} {
+		if _, ok := durations[stage].(float64); !ok {
+			t.Fatalf("expected %s duration, got %v", stage, durations)
+		}
+	}
+
+	report, err := svc.ReplayAnalysis(context.Background(), analysisID, ReplayOptions{})
+	if err != nil || !report.Passed {
+		t.Fatalf("replay: %+v err=%v", report, err)
+	}
+	if report.Changed {
+		t.Fatalf("expected replay to keep the stored provenance")
+	}
+}
+
+func TestExtractionQuality(t *testing.T) {
+	prose := strings.Repeat("Led a team of five engineers shipping payments features. ", 30)
+	cases := map[string]string{
+		"":                           ExtractionQualityLow,
+		"Jane Doe":                   ExtractionQualityLow,
+		prose:                        ExtractionQualityHigh,
+		strings.Repeat("word ", 100): ExtractionQualityMedium,
+		strings.Repeat("�� w ", 200): ExtractionQualityLow,
+	}
+	for text, want := range cases {
+		if got := extractionQuality(text); got != want {
+			t.Fatalf("extractionQuality(%.30q) = %s, want %s", text, got, want)
+		}
+	}
+}
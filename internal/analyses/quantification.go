@@ -0,0 +1,228 @@
+package analyses
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"resume-backend/internal/llm"
+)
+
+// QuantificationReport is a deterministic count of experience bullets that carry a
+// metric: a number, percentage, currency amount or multiplier.
+type QuantificationReport struct {
+	Score               int                  `json:"score"`
+	QuantifiedBullets   int                  `json:"quantifiedBullets"`
+	TotalBullets        int                  `json:"totalBullets"`
+	Roles               []RoleQuantification `json:"roles"`
+	UnquantifiedBullets []string             `json:"unquantifiedBullets"`
+}
+
+// RoleQuantification is the per-role share of quantified bullets.
+type RoleQuantification struct {
+	Role              string   `json:"role"`
+	Score             int      `json:"score"`
+	QuantifiedBullets int      `json:"quantifiedBullets"`
+	TotalBullets      int      `json:"totalBullets"`
+	Metrics           []string `json:"metrics"`
+}
+
+// Metric kinds reported by detectMetrics.
+const (
+	MetricPercent    = "percent"
+	MetricCurrency   = "currency"
+	MetricMultiplier = "multiplier"
+	MetricNumber     = "number"
+)
+
+// BulletMetric is one metric found in a bullet.
+type BulletMetric struct {
+	Kind string
+	Text string
+}
+
+var (
+	percentPattern    = regexp.MustCompile(`(?i)\d+(?:[.,]\d+)?\s?(?:%|percent\b|pct\b)`)
+	currencyPattern   = regexp.MustCompile(`(?i)(?:[$€£¥₹]\s?\d[\d,]*(?:\.\d+)?\s?(?:k|m|mm|bn?|million|billion|thousand)?\b|\b\d[\d,]*(?:\.\d+)?\s?(?:k|m|million|billion)?\s?(?:usd|eur|gbp|dollars|euros)\b)`)
+	multiplierPattern = regexp.MustCompile(`(?i)\b\d+(?:\.\d+)?\s?x\b`)
+	numberPattern     = regexp.MustCompile(`(?i)\b\d[\d,]*(?:\.\d+)?\+?(?:\s?(?:k|m|million|billion|thousand)\b)?`)
+	// Dates are stripped before counting plain numbers so employment periods do not count as metrics.
+	datePattern = regexp.MustCompile(`(?i)\b(?:(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+)?(?:19|20)\d{2}(?:\s?[-/]\s?\d{1,2})?\b|\b\d{1,2}\s?/\s?(?:19|20)?\d{2}\b`)
+
+	bulletMarkers = []string{"•", "▪", "◦", "●", "·", "–", "-", "*", "‣", "→"}
+
+	experienceSections = []string{"experience", "employment", "work history", "career history"}
+)
+
+// detectMetrics returns the metrics found in a single bullet.
+func detectMetrics(text string) []BulletMetric {
+	var out []BulletMetric
+	rest := text
+	take := func(pattern *regexp.Regexp, kind string) {
+		for _, m := range pattern.FindAllString(rest, -1) {
+			out = append(out, BulletMetric{Kind: kind, Text: strings.TrimSpace(m)})
+		}
+		rest = pattern.ReplaceAllString(rest, " ")
+	}
+	take(percentPattern, MetricPercent)
+	take(currencyPattern, MetricCurrency)
+	take(multiplierPattern, MetricMultiplier)
+	rest = datePattern.ReplaceAllString(rest, " ")
+	take(numberPattern, MetricNumber)
+	return out
+}
+
+// scoreQuantification scans the experience sections of a resume. It returns nil
+// when no experience bullets can be found.
+func scoreQuantification(resumeText string) *QuantificationReport {
+	roles := experienceRoles(resumeText)
+	report := &QuantificationReport{Roles: []RoleQuantification{}, UnquantifiedBullets: []string{}}
+	for _, role := range roles {
+		rq := RoleQuantification{Role: role.title, TotalBullets: len(role.bullets), Metrics: []string{}}
+		for _, bullet := range role.bullets {
+			metrics := detectMetrics(bullet)
+			if len(metrics) == 0 {
+				report.UnquantifiedBullets = append(report.UnquantifiedBullets, bullet)
+				continue
+			}
+			rq.QuantifiedBullets++
+			for _, m := range metrics {
+				rq.Metrics = append(rq.Metrics, m.Text)
+			}
+		}
+		rq.Score = percentOf(rq.QuantifiedBullets, rq.TotalBullets)
+		report.QuantifiedBullets += rq.QuantifiedBullets
+		report.TotalBullets += rq.TotalBullets
+		report.Roles = append(report.Roles, rq)
+	}
+	if report.TotalBullets == 0 {
+		return nil
+	}
+	report.Score = percentOf(report.QuantifiedBullets, report.TotalBullets)
+	return report
+}
+
+// signal converts the report into the hint sent to the model.
+func (r *QuantificationReport) signal() *llm.QuantificationSignal {
+	if r == nil {
+		return nil
+	}
+	return &llm.QuantificationSignal{
+		Score:               r.Score,
+		QuantifiedBullets:   r.QuantifiedBullets,
+		TotalBullets:        r.TotalBullets,
+		UnquantifiedBullets: r.UnquantifiedBullets,
+	}
+}
+
+// withQuantification records the scan on a normalized result.
+func withQuantification(result map[string]any, report *QuantificationReport) {
+	if report == nil || result == nil {
+		return
+	}
+	result["quantification"] = report
+}
+
+type experienceRole struct {
+	title   string
+	bullets []string
+}
+
+// experienceRoles groups experience bullets under the role heading above them. A
+// non-bullet line after bullets starts a new role. When the extraction lost the
+// bullet markers, sentence-length lines are treated as bullets instead.
+func experienceRoles(resumeText string) []experienceRole {
+	var sections [][]string
+	inExperience := false
+	for _, line := range resumeLines(resumeText) {
+		// Company names are often written in capitals; inside an experience section
+		// only a well-known heading ends it.
+		if isSectionHeading(line) && (!inExperience || isKnownSectionHeading(line)) {
+			inExperience = isExperienceHeading(line)
+			if inExperience {
+				sections = append(sections, nil)
+			}
+			continue
+		}
+		if inExperience {
+			sections[len(sections)-1] = append(sections[len(sections)-1], line)
+		}
+	}
+
+	var roles []experienceRole
+	for _, lines := range sections {
+		marked := false
+		for _, line := range lines {
+			if _, ok := stripBulletMarker(line); ok {
+				marked = true
+				break
+			}
+		}
+		current := -1
+		for _, line := range lines {
+			bullet, ok := stripBulletMarker(line)
+			if !marked {
+				bullet, ok = line, len(strings.Fields(line)) >= 8
+			}
+			if !ok {
+				if current == -1 || len(roles[current].bullets) > 0 {
+					roles = append(roles, experienceRole{title: line})
+					current = len(roles) - 1
+				}
+				continue
+			}
+			if current == -1 {
+				roles = append(roles, experienceRole{})
+				current = len(roles) - 1
+			}
+			roles[current].bullets = append(roles[current].bullets, bullet)
+		}
+	}
+
+	out := roles[:0]
+	for _, role := range roles {
+		if len(role.bullets) > 0 {
+			out = append(out, role)
+		}
+	}
+	return out
+}
+
+func isKnownSectionHeading(line string) bool {
+	return commonSectionHeadings[sectionKey(line)] || strings.HasSuffix(strings.TrimSpace(line), ":") || isExperienceHeading(line)
+}
+
+func isExperienceHeading(line string) bool {
+	key := sectionKey(line)
+	for _, name := range experienceSections {
+		if strings.Contains(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func stripBulletMarker(line string) (string, bool) {
+	for _, marker := range bulletMarkers {
+		if rest, ok := strings.CutPrefix(line, marker); ok {
+			rest = strings.TrimSpace(rest)
+			if rest == "" {
+				return "", false
+			}
+			// "-" and "*" only count as markers when followed by a space, so negative
+			// numbers and emphasis are left alone.
+			if (marker == "-" || marker == "*") && !strings.HasPrefix(line, marker+" ") {
+				return "", false
+			}
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+func percentOf(part, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(float64(part) * 100 / float64(total)))
+}
@@ -0,0 +1,110 @@
+package analyses
+
+import (
+	"context"
+	"testing"
+)
+
+const quantifiedResume = `Jane Doe
+EXPERIENCE
+ACME CORP
+Sales Manager, Jan 2019 - 2023
+• Grew regional revenue 35% year over year
+• Closed $1.2M in new enterprise contracts
+• Improved the onboarding process for new hires
+• Managed a team of 8 account executives
+Beta Inc, Account Executive
+2016 - 2019
+• Cut sales cycle length 2x through better qualification
+• Built relationships with key accounts
+EDUCATION
+BA Economics, 2015`
+
+func TestDetectMetrics(t *testing.T) {
+	cases := []struct {
+		text string
+		kind string
+	}{
+		{"Grew revenue 35% year over year", MetricPercent},
+		{"Raised conversion by 12 percent", MetricPercent},
+		{"Closed $1.2M in new contracts", MetricCurrency},
+		{"Saved 40k USD in hosting", MetricCurrency},
+		{"Made deploys 3x faster", MetricMultiplier},
+		{"Led a team of 8 engineers", MetricNumber},
+		{"Served 10,000+ customers", MetricNumber},
+	}
+	for _, tc := range cases {
+		metrics := detectMetrics(tc.text)
+		if len(metrics) != 1 || metrics[0].Kind != tc.kind {
+			t.Fatalf("%q: expected one %s metric, got %+v", tc.text, tc.kind, metrics)
+		}
+	}
+
+	for _, text := range []string{"Joined in March 2021", "Worked there 2019-2021", "Promoted 06/2020", "Improved onboarding"} {
+		if metrics := detectMetrics(text); len(metrics) != 0 {
+			t.Fatalf("%q: expected no metrics, got %+v", text, metrics)
+		}
+	}
+}
+
+func TestScoreQuantificationPerRole(t *testing.T) {
+	report := scoreQuantification(quantifiedResume)
+	if report == nil {
+		t.Fatal("expected a report")
+	}
+	if report.TotalBullets != 6 || report.QuantifiedBullets != 4 || report.Score != 67 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	if len(report.Roles) != 2 {
+		t.Fatalf("expected 2 roles, got %+v", report.Roles)
+	}
+	if report.Roles[0].Role != "ACME CORP" || report.Roles[0].Score != 75 {
+		t.Fatalf("unexpected first role: %+v", report.Roles[0])
+	}
+	if report.Roles[1].Role != "Beta Inc, Account Executive" || report.Roles[1].Score != 50 {
+		t.Fatalf("unexpected second role: %+v", report.Roles[1])
+	}
+	want := []string{"Improved the onboarding process for new hires", "Built relationships with key accounts"}
+	if len(report.UnquantifiedBullets) != len(want) {
+		t.Fatalf("expected unquantified %v, got %v", want, report.UnquantifiedBullets)
+	}
+	for i := range want {
+		if report.UnquantifiedBullets[i] != want[i] {
+			t.Fatalf("expected unquantified %v, got %v", want, report.UnquantifiedBullets)
+		}
+	}
+
+	if scoreQuantification("Jane Doe\nSKILLS\nGo") != nil {
+		t.Fatal("expected no report without experience bullets")
+	}
+}
+
+func TestProcessAnalysisSendsQuantificationSignal(t *testing.T) {
+	client := &recordingLLM{}
+	svc, repo, docRepo, _ := setupServiceWithDoc(t, client)
+	client.responses = map[string]string{"v2_3": string(loadFixture(t, "testdata/v2_3_good.json"))}
+	ctx := context.Background()
+
+	createTextDoc(t, svc, docRepo, "doc-quant", quantifiedResume)
+	analysis := Analysis{ID: "analysis-quant", DocumentID: "doc-quant", UserID: "user-1", JobDescription: "jd", PromptVersion: "v2_3", Status: StatusQueued}
+	if err := repo.Create(ctx, analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, analysis.ID); err != nil {
+		t.Fatalf("process analysis: %v", err)
+	}
+
+	if len(client.inputs) == 0 || client.inputs[0].Quantification == nil {
+		t.Fatalf("expected quantification signal in the prompt input")
+	}
+	if got := client.inputs[0].Quantification; got.Score != 67 || len(got.UnquantifiedBullets) != 2 {
+		t.Fatalf("unexpected signal: %+v", got)
+	}
+	stored, err := repo.GetByID(ctx, analysis.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if _, ok := stored.Result["quantification"]; !ok {
+		t.Fatalf("expected quantification in result")
+	}
+}
@@ -0,0 +1,156 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	reclassifyBatchSize    = 200
+	defaultReclassifyLimit = 1000
+	maxReclassifyLimit     = 10000
+	maxReclassifySamples   = 50
+)
+
+// ErrReclassifyUnsupported is returned when the analyses repo cannot list and
+// update failures.
+var ErrReclassifyUnsupported = errors.New("failure reclassification is not supported by this repository")
+
+// failureReclassifier is implemented by repos that can page through failed
+// analyses and rewrite their error classification.
+type failureReclassifier interface {
+	// ListFailed returns failed analyses with IDs after afterID, in ID order.
+	ListFailed(ctx context.Context, afterID string, limit int) ([]Analysis, error)
+	// UpdateFailureClassification sets the error code and retryable flag of a
+	// failed analysis. It returns ErrNotFound if the analysis is no longer failed.
+	UpdateFailureClassification(ctx context.Context, analysisID, errorCode string, retryable bool) error
+}
+
+// ReclassifyOptions controls a failure reclassification run.
+type ReclassifyOptions struct {
+	// Apply writes the new classifications; otherwise the run only reports them.
+	Apply bool
+	// Limit caps how many failures are scanned; runs resume from NextCursor.
+	Limit int
+	// After resumes a previous run from its NextCursor.
+	After string
+}
+
+// ReclassifiedFailure is one failure whose stored classification differs from
+// what classifyFailure returns today.
+type ReclassifiedFailure struct {
+	AnalysisID    string `json:"analysisId"`
+	ErrorMessage  string `json:"errorMessage"`
+	FromCode      string `json:"fromCode"`
+	ToCode        string `json:"toCode"`
+	FromRetryable bool   `json:"fromRetryable"`
+	ToRetryable   bool   `json:"toRetryable"`
+}
+
+// ReclassifyReport summarizes a reclassification run. Transitions counts changes by
+// "FROM->TO" error code; Samples lists the first few changes.
+type ReclassifyReport struct {
+	DryRun      bool                  `json:"dryRun"`
+	Scanned     int                   `json:"scanned"`
+	Changed     int                   `json:"changed"`
+	Updated     int                   `json:"updated"`
+	Transitions map[string]int        `json:"transitions"`
+	Samples     []ReclassifiedFailure `json:"samples"`
+	// NextCursor is set when the run stopped at its limit; pass it as After to continue.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ReclassifyFailures re-runs classifyFailure on the stored error messages of failed
+// analyses so old failures carry the current error codes and retryable flags.
+func (s *Service) ReclassifyFailures(ctx context.Context, opts ReclassifyOptions) (ReclassifyReport, error) {
+	repo, ok := s.Repo.(failureReclassifier)
+	if !ok {
+		return ReclassifyReport{}, ErrReclassifyUnsupported
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultReclassifyLimit
+	}
+	if limit > maxReclassifyLimit {
+		limit = maxReclassifyLimit
+	}
+
+	report := ReclassifyReport{
+		DryRun:      !opts.Apply,
+		Transitions: make(map[string]int),
+		Samples:     make([]ReclassifiedFailure, 0),
+	}
+	cursor := opts.After
+	for report.Scanned < limit {
+		batch, err := repo.ListFailed(ctx, cursor, min(reclassifyBatchSize, limit-report.Scanned))
+		if err != nil {
+			return report, fmt.Errorf("list failed analyses: %w", err)
+		}
+		if len(batch) == 0 {
+			return report, nil
+		}
+		for _, analysis := range batch {
+			cursor = analysis.ID
+			report.Scanned++
+			change, changed := reclassify(analysis)
+			if !changed {
+				continue
+			}
+			report.Changed++
+			report.Transitions[change.FromCode+"->"+change.ToCode]++
+			if len(report.Samples) < maxReclassifySamples {
+				report.Samples = append(report.Samples, change)
+			}
+			if !opts.Apply {
+				continue
+			}
+			err := repo.UpdateFailureClassification(ctx, analysis.ID, change.ToCode, change.ToRetryable)
+			if errors.Is(err, ErrNotFound) {
+				// Retried or deleted since it was listed.
+				continue
+			}
+			if err != nil {
+				return report, fmt.Errorf("update analysis %s: %w", analysis.ID, err)
+			}
+			report.Updated++
+		}
+	}
+	report.NextCursor = cursor
+	return report, nil
+}
+
+// reclassify compares a failure's stored classification with the current one.
+func reclassify(analysis Analysis) (ReclassifiedFailure, bool) {
+	var msg string
+	if analysis.ErrorMessage != nil {
+		msg = *analysis.ErrorMessage
+	}
+	code, retryable := classifyStoredFailure(msg)
+	if code == analysis.ErrorCode && retryable == analysis.ErrorRetryable {
+		return ReclassifiedFailure{}, false
+	}
+	return ReclassifiedFailure{
+		AnalysisID:    analysis.ID,
+		ErrorMessage:  msg,
+		FromCode:      analysis.ErrorCode,
+		ToCode:        code,
+		FromRetryable: analysis.ErrorRetryable,
+		ToRetryable:   retryable,
+	}, true
+}
+
+// classifyStoredFailure classifies a stored error message. The original error
+// chain is gone, so deadline errors are recognized by their text.
+func classifyStoredFailure(msg string) (string, bool) {
+	if strings.Contains(strings.ToLower(msg), context.DeadlineExceeded.Error()) {
+		return ErrorCodeLLMTimeout, true
+	}
+	return classifyFailure(errors.New(msg))
+}
+
+var (
+	_ failureReclassifier = (*MemoryRepo)(nil)
+	_ failureReclassifier = (*PGRepo)(nil)
+)
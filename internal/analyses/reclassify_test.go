@@ -0,0 +1,85 @@
+package analyses
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func seedFailure(t *testing.T, repo *MemoryRepo, id, code string, retryable bool, msg string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := repo.Create(ctx, Analysis{ID: id, DocumentID: "doc-" + id, UserID: "user-1", Status: StatusQueued, CreatedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := repo.UpdateStatusResultAndError(ctx, id, StatusFailed, nil, &code, &msg, &retryable, nil, nil); err != nil {
+		t.Fatalf("fail analysis: %v", err)
+	}
+}
+
+func TestReclassifyFailuresDryRunThenApply(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	seedFailure(t, repo, "a1", ErrorCodeInternal, false, "openai request timeout after 60s")
+	seedFailure(t, repo, "a2", ErrorCodeInternal, false, "process: context deadline exceeded")
+	seedFailure(t, repo, "a3", ErrorCodeStorage, true, "document lookup id=doc-a3: not found")
+	svc := &Service{Repo: repo}
+
+	report, err := svc.ReclassifyFailures(ctx, ReclassifyOptions{})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !report.DryRun || report.Scanned != 3 || report.Changed != 2 || report.Updated != 0 {
+		t.Fatalf("unexpected dry-run report: %+v", report)
+	}
+	if report.Transitions[ErrorCodeInternal+"->"+ErrorCodeLLMTimeout] != 2 {
+		t.Fatalf("unexpected transitions: %v", report.Transitions)
+	}
+	if stored, _ := repo.GetByID(ctx, "a1"); stored.ErrorCode != ErrorCodeInternal {
+		t.Fatalf("dry run must not update, got %s", stored.ErrorCode)
+	}
+
+	report, err = svc.ReclassifyFailures(ctx, ReclassifyOptions{Apply: true})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if report.DryRun || report.Updated != 2 {
+		t.Fatalf("unexpected apply report: %+v", report)
+	}
+	stored, _ := repo.GetByID(ctx, "a2")
+	if stored.ErrorCode != ErrorCodeLLMTimeout || !stored.ErrorRetryable {
+		t.Fatalf("expected a2 to be reclassified, got %s retryable=%v", stored.ErrorCode, stored.ErrorRetryable)
+	}
+
+	report, _ = svc.ReclassifyFailures(ctx, ReclassifyOptions{})
+	if report.Changed != 0 {
+		t.Fatalf("expected nothing left to change, got %+v", report)
+	}
+}
+
+func TestReclassifyFailuresResumesFromCursor(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	for _, id := range []string{"a1", "a2", "a3"} {
+		seedFailure(t, repo, id, ErrorCodeInternal, false, "enqueue analysis: queue unavailable")
+	}
+	svc := &Service{Repo: repo}
+
+	first, err := svc.ReclassifyFailures(ctx, ReclassifyOptions{Apply: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.Scanned != 2 || first.NextCursor != "a2" {
+		t.Fatalf("unexpected first report: %+v", first)
+	}
+	second, err := svc.ReclassifyFailures(ctx, ReclassifyOptions{Apply: true, Limit: 2, After: first.NextCursor})
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if second.Scanned != 1 || second.Updated != 1 || second.NextCursor != "" {
+		t.Fatalf("unexpected second report: %+v", second)
+	}
+	if stored, _ := repo.GetByID(ctx, "a3"); stored.ErrorCode != ErrorCodeQueue {
+		t.Fatalf("expected a3 to be reclassified, got %s", stored.ErrorCode)
+	}
+}
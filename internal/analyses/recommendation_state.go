@@ -0,0 +1,209 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Recommendation states a user can set. A recommendation without a stored
+// state is open.
+const (
+	RecommendationOpen      = "open"
+	RecommendationCompleted = "completed"
+	RecommendationDismissed = "dismissed"
+)
+
+var (
+	// ErrRecommendationStateInvalid is returned for a state other than open,
+	// completed or dismissed.
+	ErrRecommendationStateInvalid = errors.New("invalid recommendation state")
+	// ErrRecommendationNotFound is returned when the analysis result has no
+	// recommendation with the given ID.
+	ErrRecommendationNotFound = errors.New("recommendation not found")
+	// ErrRecommendationStatesUnsupported is returned when the repo cannot store
+	// recommendation states.
+	ErrRecommendationStatesUnsupported = errors.New("recommendation states not supported")
+)
+
+// RecommendationState is a user's progress on one recommendation. It is keyed
+// by user and recommendation ID rather than by analysis: recommendation IDs are
+// derived from their content, so re-analyzing the same resume produces the
+// same IDs and the user's checklist carries over.
+type RecommendationState struct {
+	RecommendationID string    `json:"recommendationId"`
+	Status           string    `json:"status"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// RecommendationProgress summarizes a result's recommendations for the
+// checklist view. Percent is completed out of the recommendations that were
+// not dismissed, rounded down; it is 100 when every one was dismissed and 0
+// when there are none.
+type RecommendationProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Dismissed int `json:"dismissed"`
+	Open      int `json:"open"`
+	Percent   int `json:"percent"`
+}
+
+// recommendationStateStore is implemented by repos that persist
+// recommendation states.
+type recommendationStateStore interface {
+	// SetRecommendationState stores st for the user, replacing any earlier
+	// state of the same recommendation.
+	SetRecommendationState(ctx context.Context, userID string, st RecommendationState) error
+	// ClearRecommendationState removes the user's state of a recommendation.
+	ClearRecommendationState(ctx context.Context, userID, recommendationID string) error
+	// ListRecommendationStates returns the user's states.
+	ListRecommendationStates(ctx context.Context, userID string) ([]RecommendationState, error)
+}
+
+var (
+	_ recommendationStateStore = (*MemoryRepo)(nil)
+	_ recommendationStateStore = (*PGRepo)(nil)
+)
+
+// SetRecommendationState records the user's state of one recommendation of a
+// completed analysis they own and returns the analysis's updated progress.
+// Setting a recommendation back to open removes its stored state.
+func (s *Service) SetRecommendationState(ctx context.Context, userID, analysisID, recommendationID, status string) (RecommendationState, RecommendationProgress, error) {
+	store, ok := s.Repo.(recommendationStateStore)
+	if !ok {
+		return RecommendationState{}, RecommendationProgress{}, ErrRecommendationStatesUnsupported
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status != RecommendationOpen && status != RecommendationCompleted && status != RecommendationDismissed {
+		return RecommendationState{}, RecommendationProgress{}, fmt.Errorf("%w: status must be %s, %s or %s", ErrRecommendationStateInvalid, RecommendationOpen, RecommendationCompleted, RecommendationDismissed)
+	}
+	analysis, err := s.Get(ctx, analysisID)
+	if err != nil {
+		return RecommendationState{}, RecommendationProgress{}, err
+	}
+	if analysis.UserID != userID {
+		return RecommendationState{}, RecommendationProgress{}, ErrNotFound
+	}
+	if analysis.Status != StatusCompleted || analysis.Result == nil {
+		return RecommendationState{}, RecommendationProgress{}, ErrAnalysisNotCompleted
+	}
+	ids := recommendationIDs(analysis.Result)
+	if !slices.Contains(ids, recommendationID) {
+		return RecommendationState{}, RecommendationProgress{}, ErrRecommendationNotFound
+	}
+
+	st := RecommendationState{RecommendationID: recommendationID, Status: status, UpdatedAt: time.Now().UTC()}
+	if status == RecommendationOpen {
+		err = store.ClearRecommendationState(ctx, userID, recommendationID)
+	} else {
+		err = store.SetRecommendationState(ctx, userID, st)
+	}
+	if err != nil {
+		return RecommendationState{}, RecommendationProgress{}, err
+	}
+	telemetry.InfoContext(ctx, "analysis.recommendation_state_set", map[string]any{
+		"analysis_id":       analysis.ID,
+		"recommendation_id": recommendationID,
+		"status":            status,
+	})
+
+	states, err := store.ListRecommendationStates(ctx, userID)
+	if err != nil {
+		return RecommendationState{}, RecommendationProgress{}, err
+	}
+	return st, recommendationProgress(ids, statesByID(states)), nil
+}
+
+// WithRecommendationStates returns result with the user's recommendation
+// states merged in. Each recommendation with a state gets a userState field,
+// and recommendationProgress summarizes them. Lookup failures are logged and
+// leave the result as given.
+func (s *Service) WithRecommendationStates(ctx context.Context, userID string, result map[string]any) map[string]any {
+	store, ok := s.Repo.(recommendationStateStore)
+	if !ok || result == nil {
+		return result
+	}
+	states, err := store.ListRecommendationStates(ctx, userID)
+	if err != nil {
+		telemetry.ErrorContext(ctx, "analysis.recommendation_states_failed", map[string]any{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return result
+	}
+	merged, err := copyResult(result)
+	if err != nil {
+		return result
+	}
+	byID := statesByID(states)
+	items, _ := merged["recommendations"].([]any)
+	for _, item := range items {
+		rec, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := rec["id"].(string)
+		if st, ok := byID[id]; ok {
+			rec["userState"] = map[string]any{"status": st.Status, "updatedAt": st.UpdatedAt}
+		}
+	}
+	merged["recommendationProgress"] = recommendationProgress(recommendationIDs(merged), byID)
+	return merged
+}
+
+// recommendationIDs lists the IDs of the result's recommendations in order.
+func recommendationIDs(result map[string]any) []string {
+	var ids []string
+	switch items := result["recommendations"].(type) {
+	case []any:
+		for _, item := range items {
+			if rec, ok := item.(map[string]any); ok {
+				if id, _ := rec["id"].(string); id != "" {
+					ids = append(ids, id)
+				}
+			}
+		}
+	case []Recommendation:
+		for _, rec := range items {
+			if rec.ID != "" {
+				ids = append(ids, rec.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func recommendationProgress(ids []string, states map[string]RecommendationState) RecommendationProgress {
+	progress := RecommendationProgress{Total: len(ids)}
+	for _, id := range ids {
+		switch states[id].Status {
+		case RecommendationCompleted:
+			progress.Completed++
+		case RecommendationDismissed:
+			progress.Dismissed++
+		default:
+			progress.Open++
+		}
+	}
+	switch actionable := progress.Total - progress.Dismissed; {
+	case progress.Total == 0:
+	case actionable == 0:
+		progress.Percent = 100
+	default:
+		progress.Percent = progress.Completed * 100 / actionable
+	}
+	return progress
+}
+
+func statesByID(states []RecommendationState) map[string]RecommendationState {
+	out := make(map[string]RecommendationState, len(states))
+	for _, st := range states {
+		out[st.RecommendationID] = st
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/storage/db"
+)
+
+func recommendationFixture() map[string]any {
+	return map[string]any{
+		"recommendations": []any{
+			map[string]any{"id": "ISSUE_NO_METRICS", "title": "Add metrics"},
+			map[string]any{"id": "ATS_MISSING_JD_KEYWORDS", "title": "Add keywords"},
+			map[string]any{"id": "MISSING_INFO_LINKEDIN", "title": "Add LinkedIn"},
+		},
+	}
+}
+
+func TestRecommendationStatesAreMergedIntoTheRenderedResult(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _, analysisRepo, _, _ := setupAnalysisRouter(t)
+	ctx := context.Background()
+	analysis := Analysis{
+		ID:         "analysis-recs",
+		DocumentID: "doc-1",
+		UserID:     "guest:test-guest",
+		Status:     StatusCompleted,
+		Result:     recommendationFixture(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := analysisRepo.Create(ctx, analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	set := func(recommendationID, status string) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, _ := json.Marshal(map[string]any{"status": status})
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/analyses/"+analysis.ID+"/recommendations/"+recommendationID, bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := set("ISSUE_NO_METRICS", "completed"); resp.Code != http.StatusOK {
+		t.Fatalf("complete: status %d body %s", resp.Code, resp.Body)
+	}
+	resp := set("MISSING_INFO_LINKEDIN", "dismissed")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("dismiss: status %d body %s", resp.Code, resp.Body)
+	}
+	var setBody struct {
+		Status   string                 `json:"status"`
+		Progress RecommendationProgress `json:"progress"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &setBody); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if setBody.Status != RecommendationDismissed || setBody.Progress != (RecommendationProgress{Total: 3, Completed: 1, Dismissed: 1, Open: 1, Percent: 50}) {
+		t.Fatalf("unexpected response: %+v", setBody)
+	}
+	if resp := set("UNKNOWN", "completed"); resp.Code != http.StatusNotFound {
+		t.Fatalf("unknown recommendation: expected 404, got %d", resp.Code)
+	}
+	if resp := set("ISSUE_NO_METRICS", "done"); resp.Code != http.StatusBadRequest {
+		t.Fatalf("bad status: expected 400, got %d", resp.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analyses/"+analysis.ID, nil)
+	addGuestHeader(req)
+	getResp := httptest.NewRecorder()
+	router.ServeHTTP(getResp, req)
+	if getResp.Code != http.StatusOK {
+		t.Fatalf("get: status %d body %s", getResp.Code, getResp.Body)
+	}
+	var got struct {
+		Result struct {
+			Recommendations        []map[string]any       `json:"recommendations"`
+			RecommendationProgress RecommendationProgress `json:"recommendationProgress"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(getResp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	state, _ := got.Result.Recommendations[0]["userState"].(map[string]any)
+	if state["status"] != RecommendationCompleted {
+		t.Fatalf("first recommendation = %v", got.Result.Recommendations[0])
+	}
+	if _, ok := got.Result.Recommendations[1]["userState"]; ok {
+		t.Fatal("an open recommendation should not carry a state")
+	}
+	if got.Result.RecommendationProgress.Percent != 50 {
+		t.Fatalf("progress = %+v", got.Result.RecommendationProgress)
+	}
+
+	// Reopening removes the state.
+	if resp := set("ISSUE_NO_METRICS", "open"); resp.Code != http.StatusOK {
+		t.Fatalf("reopen: status %d", resp.Code)
+	}
+	states, _ := analysisRepo.ListRecommendationStates(ctx, analysis.UserID)
+	if len(states) != 1 || states[0].RecommendationID != "MISSING_INFO_LINKEDIN" {
+		t.Fatalf("states = %+v", states)
+	}
+	stored, _ := analysisRepo.GetByID(ctx, analysis.ID)
+	if _, ok := stored.Result["recommendationProgress"]; ok {
+		t.Fatal("the stored result should not be rewritten")
+	}
+}
+
+func TestSetRecommendationStateRejectsOtherUsersAndUnfinishedAnalyses(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	svc := &Service{Repo: repo}
+	for _, a := range []Analysis{
+		{ID: "done", UserID: "user-1", Status: StatusCompleted, Result: recommendationFixture()},
+		{ID: "pending", UserID: "user-1", Status: StatusQueued},
+	} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if _, _, err := svc.SetRecommendationState(ctx, "user-2", "done", "ISSUE_NO_METRICS", "completed"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("other user: expected ErrNotFound, got %v", err)
+	}
+	if _, _, err := svc.SetRecommendationState(ctx, "user-1", "pending", "ISSUE_NO_METRICS", "completed"); !errors.Is(err, ErrAnalysisNotCompleted) {
+		t.Fatalf("pending: expected ErrAnalysisNotCompleted, got %v", err)
+	}
+}
+
+func TestRecommendationProgress(t *testing.T) {
+	ids := []string{"a", "b"}
+	cases := []struct {
+		states map[string]RecommendationState
+		want   RecommendationProgress
+	}{
+		{nil, RecommendationProgress{Total: 2, Open: 2}},
+		{map[string]RecommendationState{"a": {Status: RecommendationCompleted}}, RecommendationProgress{Total: 2, Completed: 1, Open: 1, Percent: 50}},
+		{map[string]RecommendationState{"a": {Status: RecommendationDismissed}, "b": {Status: RecommendationDismissed}}, RecommendationProgress{Total: 2, Dismissed: 2, Percent: 100}},
+	}
+	for _, tc := range cases {
+		if got := recommendationProgress(ids, tc.states); got != tc.want {
+			t.Errorf("progress(%v) = %+v, want %+v", tc.states, got, tc.want)
+		}
+	}
+	if got := recommendationProgress(nil, nil); got != (RecommendationProgress{}) {
+		t.Errorf("empty progress = %+v", got)
+	}
+}
+
+func TestClaimGuestMovesRecommendationStates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	guest := "guest:11111111-1111-1111-1111-111111111111"
+	_ = repo.SetRecommendationState(ctx, guest, RecommendationState{RecommendationID: "a", Status: RecommendationCompleted})
+	_ = repo.SetRecommendationState(ctx, guest, RecommendationState{RecommendationID: "b", Status: RecommendationDismissed})
+	_ = repo.SetRecommendationState(ctx, "user-1", RecommendationState{RecommendationID: "b", Status: RecommendationCompleted})
+
+	uow := &db.MemoryUnitOfWork{}
+	err := uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := repo.ClaimGuest(ctx, guest, "user-1"); err != nil {
+			return err
+		}
+		return errors.New("later write failed")
+	})
+	if err == nil {
+		t.Fatal("expected the unit to fail")
+	}
+	if states, _ := repo.ListRecommendationStates(ctx, guest); len(states) != 2 {
+		t.Fatalf("expected the guest's states restored, got %+v", states)
+	}
+
+	if _, err := repo.ClaimGuest(ctx, guest, "user-1"); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	states, _ := repo.ListRecommendationStates(ctx, "user-1")
+	if len(states) != 2 || states[0].Status != RecommendationCompleted || states[1].Status != RecommendationCompleted {
+		t.Fatalf("expected the guest's state added and the user's kept, got %+v", states)
+	}
+	if states, _ := repo.ListRecommendationStates(ctx, guest); len(states) != 0 {
+		t.Fatalf("expected the guest's states gone, got %+v", states)
+	}
+}
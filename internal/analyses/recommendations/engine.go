@@ -6,8 +6,18 @@ import (
 	"unicode"
 )
 
+// DefaultLimit is how many recommendations GenerateRecommendations keeps.
+const DefaultLimit = 7
+
 // GenerateRecommendations builds deterministic recommendations from a normalized analysis result.
 func GenerateRecommendations(input Input) []Recommendation {
+	recs, _ := Generate(input, DefaultLimit)
+	return recs
+}
+
+// Generate is GenerateRecommendations with a caller-chosen limit. It also
+// returns how many ranked recommendations fell beyond the limit.
+func Generate(input Input, limit int) ([]Recommendation, int) {
 	candidates := make([]Recommendation, 0, 16)
 	mappers := []func(Input) []Recommendation{
 		func(in Input) []Recommendation {
@@ -32,13 +42,15 @@ func GenerateRecommendations(input Input) []Recommendation {
 
 	deduped := dedupe(candidates)
 	sortRecommendations(deduped)
-	if len(deduped) > 7 {
-		deduped = deduped[:7]
+	dropped := 0
+	if len(deduped) > limit {
+		dropped = len(deduped) - limit
+		deduped = deduped[:limit]
 	}
 	for i := range deduped {
 		deduped[i].Order = i + 1
 	}
-	return deduped
+	return deduped, dropped
 }
 
 func severityRank(value string) int {
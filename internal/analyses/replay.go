@@ -0,0 +1,204 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Replay stages reported when stored output is rejected.
+const (
+	ReplayStageValidate  = "validate"
+	ReplayStageNormalize = "normalize"
+)
+
+var (
+	// ErrNoAnalysisRaw is returned when an analysis has no stored LLM output to replay.
+	ErrNoAnalysisRaw = errors.New("analysis has no stored raw output")
+	// ErrAnalysisInFlight is returned when replaying an analysis that is still queued or processing.
+	ErrAnalysisInFlight = errors.New("analysis is still running")
+)
+
+// ReplayOptions controls an analysis replay.
+type ReplayOptions struct {
+	// Apply stores the replayed result; otherwise the replay only reports it.
+	Apply bool
+}
+
+// ReplayReport is the outcome of replaying one analysis.
+type ReplayReport struct {
+	AnalysisID     string `json:"analysisId"`
+	Mode           string `json:"mode"`
+	PromptVersion  string `json:"promptVersion"`
+	PreviousStatus string `json:"previousStatus"`
+	DryRun         bool   `json:"dryRun"`
+	// Passed reports whether the stored output got through validation and
+	// normalization. Otherwise Stage and Error say where it was rejected.
+	Passed bool   `json:"passed"`
+	Stage  string `json:"stage,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Changed reports whether Result differs from the stored result.
+	Changed bool           `json:"changed"`
+	Applied bool           `json:"applied"`
+	Result  map[string]any `json:"result,omitempty"`
+}
+
+// ReplayAnalysis re-runs the validation and normalization stages of an
+// analysis's pipeline on its stored analysis_raw, without calling the LLM. The
+// resume text is read again for the pipeline's input step and evidence
+// attribution. With Apply, a passing replay replaces the stored result and marks
+// the analysis completed, clearing a previous failure.
+func (s *Service) ReplayAnalysis(ctx context.Context, analysisID string, opts ReplayOptions) (ReplayReport, error) {
+	ctx = ctxmeta.WithAnalysisID(ctx, analysisID)
+	analysis, err := s.Repo.GetByID(ctx, analysisID)
+	if err != nil {
+		return ReplayReport{}, err
+	}
+	if analysis.Status == StatusQueued || analysis.Status == StatusProcessing {
+		return ReplayReport{}, fmt.Errorf("%w: status is %s", ErrAnalysisInFlight, analysis.Status)
+	}
+	// The admin reads the owner's resume text, which lives in the owner's region.
+	ctx = ctxmeta.WithDataOwner(ctx, analysis.UserID)
+	raw, ok := storedRaw(analysis.AnalysisRaw)
+	if !ok {
+		return ReplayReport{}, ErrNoAnalysisRaw
+	}
+	pipeline, ok := s.pipelines().Lookup(analysis.Mode, analysis.PromptVersion)
+	if !ok {
+		return ReplayReport{}, s.checkPipeline(analysis.Mode, analysis.PromptVersion)
+	}
+
+	report := ReplayReport{
+		AnalysisID:     analysis.ID,
+		Mode:           string(analysis.Mode),
+		PromptVersion:  analysis.PromptVersion,
+		PreviousStatus: analysis.Status,
+		DryRun:         !opts.Apply,
+	}
+	if pipeline.Validate != nil {
+		if err := pipeline.Validate(raw); err != nil {
+			report.Stage = ReplayStageValidate
+			report.Error = err.Error()
+			return report, nil
+		}
+	}
+
+	run := &PipelineRun{Analysis: analysis, svc: s}
+	if pipeline.BuildInput != nil || len(analysis.SupportingDocuments) > 0 {
+		if s.DocRepo == nil || s.Store == nil {
+			return ReplayReport{}, errors.New("missing document store dependencies")
+		}
+		doc, err := s.DocRepo.GetByID(ctx, analysis.UserID, analysis.DocumentID)
+		if err != nil {
+			return ReplayReport{}, fmt.Errorf("document lookup id=%s: %w", analysis.DocumentID, err)
+		}
+		if run.ResumeText, err = s.resumeText(ctx, doc); err != nil {
+			return ReplayReport{}, err
+		}
+		if run.Input.SupportingDocuments, err = s.loadSupportingDocuments(ctx, analysis); err != nil {
+			return ReplayReport{}, err
+		}
+	}
+	if pipeline.BuildInput != nil {
+		pipeline.BuildInput(run)
+	}
+
+	normalize := pipeline.Normalize
+	if normalize == nil {
+		normalize = normalizeAnalysisResult
+	}
+	result, err := normalize(raw, analysis)
+	if err != nil {
+		report.Stage = ReplayStageNormalize
+		report.Error = fmt.Sprintf("llm output invalid: %v", err)
+		return report, nil
+	}
+	withStoredTruncation(result, analysis.Result)
+	attributeEvidence(result, analysis.DocumentID, run.ResumeText, run.Input.SupportingDocuments)
+	// A revision comes from the delta comparison that produced the raw output,
+	// which is not repeated here.
+	if revision, ok := analysis.Result["revision"]; ok && result != nil {
+		result["revision"] = revision
+	}
+	// Provenance describes the run that produced the raw output.
+	if provenance, ok := storedProvenance(analysis.Result); ok && result != nil {
+		if meta, ok := result["meta"].(map[string]any); ok {
+			meta["provenance"] = provenance
+		}
+	}
+	withQuantification(result, run.Quantification)
+
+	report.Passed = true
+	report.Result = result
+	report.Changed = !sameJSON(analysis.Result, result)
+	if !opts.Apply {
+		return report, nil
+	}
+
+	if analysis.Status == StatusFailed {
+		empty := ""
+		retryable := false
+		if err := s.Repo.UpdateStatusResultAndError(ctx, analysis.ID, StatusCompleted, nil, &empty, &empty, &retryable, nil, nil); err != nil {
+			return report, fmt.Errorf("clear failure: %w", err)
+		}
+	}
+	completedAt := replayCompletedAt(analysis)
+	if err := s.Repo.UpdateAnalysisResult(ctx, analysis.ID, result, &completedAt); err != nil {
+		return report, fmt.Errorf("set analysis result failed: %w", err)
+	}
+	report.Applied = true
+	telemetry.InfoContext(ctx, "analysis.replayed", map[string]any{
+		"analysis_id":     analysis.ID,
+		"previous_status": analysis.Status,
+		"changed":         report.Changed,
+	})
+	return report, nil
+}
+
+// storedRaw turns analysis_raw back into the LLM output it was stored from.
+// Output that was not JSON is stored as {"rawText": ...}.
+func storedRaw(stored any) (json.RawMessage, bool) {
+	if stored == nil {
+		return nil, false
+	}
+	if m, ok := stored.(map[string]any); ok && len(m) == 1 {
+		if text, ok := m["rawText"].(string); ok {
+			return json.RawMessage(text), text != ""
+		}
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}
+
+// replayCompletedAt keeps the original completion time of a completed analysis.
+func replayCompletedAt(analysis Analysis) time.Time {
+	switch {
+	case analysis.Status == StatusCompleted && analysis.AnalysisCompletedAt != nil:
+		return *analysis.AnalysisCompletedAt
+	case analysis.Status == StatusCompleted && analysis.CompletedAt != nil:
+		return *analysis.CompletedAt
+	default:
+		return time.Now().UTC()
+	}
+}
+
+func sameJSON(a, b map[string]any) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(left, right)
+}
@@ -0,0 +1,116 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func seedReplay(t *testing.T, repo *MemoryRepo, id, promptVersion string, raw []byte) {
+	t.Helper()
+	ctx := context.Background()
+	if err := repo.Create(ctx, Analysis{ID: id, DocumentID: "doc-" + id, UserID: "user-1", Mode: ModeATS, PromptVersion: promptVersion, Status: StatusQueued, CreatedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	var stored any
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		t.Fatalf("decode raw: %v", err)
+	}
+	if err := repo.UpdateAnalysisRaw(ctx, id, stored); err != nil {
+		t.Fatalf("store raw: %v", err)
+	}
+	code, msg, retryable := ErrorCodeLLMSchemaMismatch, "llm output invalid: boom", false
+	if err := repo.UpdateStatusResultAndError(ctx, id, StatusFailed, nil, &code, &msg, &retryable, nil, nil); err != nil {
+		t.Fatalf("fail analysis: %v", err)
+	}
+}
+
+func TestReplayAnalysisVerifiesFixAgainstFailedPayload(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	seedReplay(t, repo, "a1", "v2", loadFixture(t, "testdata/v2_good.json"))
+
+	// The stored payload failed under a buggy normalizer.
+	buggy := NewPipelineRegistry()
+	buggy.Register(ModeATS, "v2", Pipeline{
+		Generate: func(context.Context, *PipelineRun) (json.RawMessage, error) {
+			t.Fatal("replay must not call Generate")
+			return nil, nil
+		},
+		Validate:  validateV2,
+		Normalize: func(json.RawMessage, Analysis) (map[string]any, error) { return nil, errors.New("boom") },
+	})
+	svc := &Service{Repo: repo, Pipelines: buggy}
+	report, err := svc.ReplayAnalysis(ctx, "a1", ReplayOptions{})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if report.Passed || report.Stage != ReplayStageNormalize || !report.DryRun {
+		t.Fatalf("expected normalize failure, got %+v", report)
+	}
+
+	// With the fix in place the same payload replays cleanly.
+	svc.Pipelines = DefaultPipelines()
+	report, err = svc.ReplayAnalysis(ctx, "a1", ReplayOptions{})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !report.Passed || !report.Changed || report.Applied || report.Result == nil {
+		t.Fatalf("expected passing dry run, got %+v", report)
+	}
+	if stored, _ := repo.GetByID(ctx, "a1"); stored.Status != StatusFailed {
+		t.Fatalf("dry run must not update, got status %s", stored.Status)
+	}
+
+	report, err = svc.ReplayAnalysis(ctx, "a1", ReplayOptions{Apply: true})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !report.Applied {
+		t.Fatalf("expected applied report, got %+v", report)
+	}
+	stored, _ := repo.GetByID(ctx, "a1")
+	if stored.Status != StatusCompleted || stored.ErrorCode != "" || stored.Result == nil {
+		t.Fatalf("expected completed analysis without error, got status=%s code=%s", stored.Status, stored.ErrorCode)
+	}
+	if _, ok := FinalScore(stored); !ok {
+		t.Fatalf("expected replayed result to carry a score")
+	}
+
+	report, err = svc.ReplayAnalysis(ctx, "a1", ReplayOptions{})
+	if err != nil || report.Changed {
+		t.Fatalf("expected replay of applied result to be unchanged, got %+v err=%v", report, err)
+	}
+}
+
+func TestReplayAnalysisRejectsInvalidOutputAndMissingRaw(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	seedReplay(t, repo, "bad", "v2_2", loadFixture(t, "testdata/v2_2_bad_sum.json"))
+	if err := repo.Create(ctx, Analysis{ID: "no-raw", UserID: "user-1", Mode: ModeATS, PromptVersion: "v2", Status: StatusFailed}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := repo.Create(ctx, Analysis{ID: "running", UserID: "user-1", Mode: ModeATS, PromptVersion: "v2", Status: StatusProcessing, AnalysisRaw: map[string]any{}}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	svc := &Service{Repo: repo}
+
+	report, err := svc.ReplayAnalysis(ctx, "bad", ReplayOptions{Apply: true})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if report.Passed || report.Stage != ReplayStageValidate || report.Applied {
+		t.Fatalf("expected validation failure, got %+v", report)
+	}
+	if _, err := svc.ReplayAnalysis(ctx, "no-raw", ReplayOptions{}); !errors.Is(err, ErrNoAnalysisRaw) {
+		t.Fatalf("expected ErrNoAnalysisRaw, got %v", err)
+	}
+	if _, err := svc.ReplayAnalysis(ctx, "running", ReplayOptions{}); !errors.Is(err, ErrAnalysisInFlight) {
+		t.Fatalf("expected ErrAnalysisInFlight, got %v", err)
+	}
+	if _, err := svc.ReplayAnalysis(ctx, "missing", ReplayOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
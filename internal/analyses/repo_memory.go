@@ -2,23 +2,32 @@ package analyses
 
 import (
 	"context"
+	"math"
 	"sort"
 	"sync"
 	"time"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 // MemoryRepo stores analyses in memory and is safe for concurrent use.
 type MemoryRepo struct {
-	mu     sync.RWMutex
-	byID   map[string]Analysis
-	byUser map[string][]Analysis
+	mu          sync.RWMutex
+	byID        map[string]Analysis
+	byUser      map[string][]Analysis
+	annotations map[string][]Annotation
+	// recommendationStates maps user ID to recommendation ID to state.
+	recommendationStates map[string]map[string]RecommendationState
 }
 
 // NewMemoryRepo constructs a MemoryRepo.
 func NewMemoryRepo() *MemoryRepo {
 	return &MemoryRepo{
-		byID:   make(map[string]Analysis),
-		byUser: make(map[string][]Analysis),
+		byID:        make(map[string]Analysis),
+		byUser:      make(map[string][]Analysis),
+		annotations: make(map[string][]Annotation),
+
+		recommendationStates: make(map[string]map[string]RecommendationState),
 	}
 }
 
@@ -30,11 +39,23 @@ func (r *MemoryRepo) GetOrCreateForDocument(ctx context.Context, analysis Analys
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	mode := analysis.Mode
+	if mode == "" {
+		mode = ModeJobMatch
+	}
+	jdHash := jobDescriptionHash(analysis)
+	supportingHash := HashSupportingDocuments(analysis.SupportingDocuments)
 	var latest *Analysis
 	for _, existing := range r.byUser[analysis.UserID] {
 		if existing.DocumentID != analysis.DocumentID {
 			continue
 		}
+		if jobDescriptionHash(existing) != jdHash || existing.Mode != mode {
+			continue
+		}
+		if HashSupportingDocuments(existing.SupportingDocuments) != supportingHash || existing.LearningPlan != analysis.LearningPlan || existing.TargetRole != analysis.TargetRole {
+			continue
+		}
 		if latest == nil || existing.CreatedAt.After(latest.CreatedAt) {
 			copy := existing
 			latest = &copy
@@ -43,7 +64,7 @@ func (r *MemoryRepo) GetOrCreateForDocument(ctx context.Context, analysis Analys
 
 	if latest != nil {
 		switch latest.Status {
-		case StatusQueued, StatusProcessing:
+		case StatusQueued, StatusProcessing, StatusBudgetDeferred:
 			return *latest, false, nil
 		case StatusCompleted:
 			return *latest, false, nil
@@ -292,14 +313,511 @@ func (r *MemoryRepo) ClaimGuest(ctx context.Context, guestUserID, authedUserID s
 	defer r.mu.Unlock()
 
 	guestAnalyses := r.byUser[guestUserID]
+	if guestStates, added := r.moveRecommendationStates(guestUserID, authedUserID); len(guestStates) > 0 {
+		db.OnRollback(ctx, func() { r.restoreRecommendationStates(guestUserID, authedUserID, guestStates, added) })
+	}
 	if len(guestAnalyses) == 0 {
 		return 0, nil
 	}
+	claimed := make(map[string]bool, len(guestAnalyses))
 	for i := range guestAnalyses {
 		guestAnalyses[i].UserID = authedUserID
 		r.byID[guestAnalyses[i].ID] = guestAnalyses[i]
+		claimed[guestAnalyses[i].ID] = true
 	}
 	r.byUser[authedUserID] = append(r.byUser[authedUserID], guestAnalyses...)
 	delete(r.byUser, guestUserID)
+	db.OnRollback(ctx, func() { r.moveAnalyses(authedUserID, guestUserID, claimed) })
 	return len(guestAnalyses), nil
 }
+
+// moveRecommendationStates gives the guest's recommendation states to the
+// user, keeping the user's own state where both have one. It returns the
+// guest's states and the IDs the user gained, for undoing the move. The caller
+// must hold r.mu.
+func (r *MemoryRepo) moveRecommendationStates(from, to string) (map[string]RecommendationState, []string) {
+	guest := r.recommendationStates[from]
+	var added []string
+	for id, st := range guest {
+		if _, ok := r.recommendationStates[to][id]; ok {
+			continue
+		}
+		if r.recommendationStates[to] == nil {
+			r.recommendationStates[to] = make(map[string]RecommendationState)
+		}
+		r.recommendationStates[to][id] = st
+		added = append(added, id)
+	}
+	delete(r.recommendationStates, from)
+	return guest, added
+}
+
+// restoreRecommendationStates undoes moveRecommendationStates.
+func (r *MemoryRepo) restoreRecommendationStates(guestUserID, authedUserID string, guest map[string]RecommendationState, added []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range added {
+		delete(r.recommendationStates[authedUserID], id)
+	}
+	r.recommendationStates[guestUserID] = guest
+}
+
+// moveAnalyses gives the analyses with the given IDs from one user to another.
+func (r *MemoryRepo) moveAnalyses(from, to string, ids map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kept []Analysis
+	for _, analysis := range r.byUser[from] {
+		if !ids[analysis.ID] {
+			kept = append(kept, analysis)
+			continue
+		}
+		analysis.UserID = to
+		r.byID[analysis.ID] = analysis
+		r.byUser[to] = append(r.byUser[to], analysis)
+	}
+	if len(kept) == 0 {
+		delete(r.byUser, from)
+	} else {
+		r.byUser[from] = kept
+	}
+}
+
+// SoftDeleteByDocument removes every analysis of a document from the store.
+func (r *MemoryRepo) SoftDeleteByDocument(ctx context.Context, userID, documentID string, deletedAt time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.byUser[userID][:0]
+	var removed []Analysis
+	for _, analysis := range r.byUser[userID] {
+		if analysis.DocumentID == documentID {
+			delete(r.byID, analysis.ID)
+			removed = append(removed, analysis)
+			continue
+		}
+		kept = append(kept, analysis)
+	}
+	r.byUser[userID] = kept
+	if len(removed) > 0 {
+		db.OnRollback(ctx, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			for _, analysis := range removed {
+				r.byID[analysis.ID] = analysis
+			}
+			r.byUser[userID] = append(r.byUser[userID], removed...)
+		})
+	}
+	return len(removed), nil
+}
+
+// ListCompletedSince returns completed analyses finished at or after since, newest first.
+// Sample analyses are left out.
+func (r *MemoryRepo) ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Analysis, 0)
+	for _, a := range r.byID {
+		if a.Status != StatusCompleted || a.CompletedAt == nil || a.CompletedAt.Before(since) || a.Provider == SampleProvider {
+			continue
+		}
+		out = append(out, a)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CompletedAt.After(*out[j].CompletedAt)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// LatestByDocuments returns the most recently created analysis of each listed
+// document, keyed by document ID. Documents without analyses are left out.
+func (r *MemoryRepo) LatestByDocuments(ctx context.Context, documentIDs []string) (map[string]Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(documentIDs))
+	for _, id := range documentIDs {
+		wanted[id] = true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]Analysis)
+	for _, a := range r.byID {
+		if !wanted[a.DocumentID] {
+			continue
+		}
+		if latest, ok := out[a.DocumentID]; ok && !a.CreatedAt.After(latest.CreatedAt) {
+			continue
+		}
+		out[a.DocumentID] = a
+	}
+	return out, nil
+}
+
+// LatestForDocument returns the newest analysis of the document in mode.
+func (r *MemoryRepo) LatestForDocument(ctx context.Context, userID, documentID string, mode AnalysisMode) (Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return Analysis{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *Analysis
+	for _, a := range r.byUser[userID] {
+		if a.DocumentID != documentID || a.Mode != mode {
+			continue
+		}
+		if latest == nil || a.CreatedAt.After(latest.CreatedAt) {
+			copy := a
+			latest = &copy
+		}
+	}
+	if latest == nil {
+		return Analysis{}, ErrNotFound
+	}
+	return *latest, nil
+}
+
+// LatestCompletedForJob returns the user's most recent completed analysis for the
+// same job description and mode, excluding excludeID.
+func (r *MemoryRepo) LatestCompletedForJob(ctx context.Context, userID, jobDescriptionHash string, mode AnalysisMode, excludeID string) (Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return Analysis{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest Analysis
+	found := false
+	for _, a := range r.byID {
+		if a.UserID != userID || a.ID == excludeID || a.Status != StatusCompleted || a.CompletedAt == nil || a.Mode != mode {
+			continue
+		}
+		if a.JobDescriptionHash != jobDescriptionHash && HashJobDescription(a.JobDescription) != jobDescriptionHash {
+			continue
+		}
+		if !found || a.CompletedAt.After(*latest.CompletedAt) {
+			latest = a
+			found = true
+		}
+	}
+	if !found {
+		return Analysis{}, ErrNotFound
+	}
+	return latest, nil
+}
+
+// CompletionLatencyP90 returns the 90th percentile creation-to-completion latency of
+// analyses completed since the given time, and how many there were. Sample
+// analyses are left out.
+func (r *MemoryRepo) CompletionLatencyP90(ctx context.Context, since time.Time) (time.Duration, int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latencies []time.Duration
+	for _, a := range r.byID {
+		if a.Status != StatusCompleted || a.CompletedAt == nil || a.CompletedAt.Before(since) || a.Provider == SampleProvider {
+			continue
+		}
+		latencies = append(latencies, a.CompletedAt.Sub(a.CreatedAt))
+	}
+	if len(latencies) == 0 {
+		return 0, 0, nil
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	idx := int(math.Ceil(0.9*float64(len(latencies)))) - 1
+	return latencies[idx], len(latencies), nil
+}
+
+// ListFailed returns failed analyses with IDs after afterID, in ID order.
+func (r *MemoryRepo) ListFailed(ctx context.Context, afterID string, limit int) ([]Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Analysis, 0)
+	for _, a := range r.byID {
+		if a.Status == StatusFailed && a.ID > afterID {
+			out = append(out, a)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// UpdateFailureClassification sets the error code and retryable flag of a failed analysis.
+func (r *MemoryRepo) UpdateFailureClassification(ctx context.Context, analysisID, errorCode string, retryable bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	analysis, ok := r.byID[analysisID]
+	if !ok || analysis.Status != StatusFailed {
+		return ErrNotFound
+	}
+	analysis.ErrorCode = errorCode
+	analysis.ErrorRetryable = retryable
+	analysis.UpdatedAt = time.Now().UTC()
+	r.byID[analysisID] = analysis
+
+	userAnalyses := r.byUser[analysis.UserID]
+	for i := range userAnalyses {
+		if userAnalyses[i].ID == analysisID {
+			userAnalyses[i] = analysis
+			break
+		}
+	}
+	return nil
+}
+
+// ListBudgetDeferred returns analyses waiting on an LLM budget, oldest first.
+func (r *MemoryRepo) ListBudgetDeferred(ctx context.Context, limit int) ([]Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Analysis, 0)
+	for _, a := range r.byID {
+		if a.Status == StatusBudgetDeferred {
+			out = append(out, a)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// UpsertAnnotation stores a, replacing any annotation of the same item.
+func (r *MemoryRepo) UpsertAnnotation(ctx context.Context, a Annotation) (Annotation, error) {
+	if err := ctx.Err(); err != nil {
+		return Annotation{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing := r.annotations[a.AnalysisID]
+	for i := range existing {
+		if existing[i].Target == a.Target && existing[i].ref() == a.ref() {
+			a.ID = existing[i].ID
+			existing[i] = a
+			return a, nil
+		}
+	}
+	r.annotations[a.AnalysisID] = append(existing, a)
+	return a, nil
+}
+
+// ListAnnotations returns an analysis's annotations, oldest first.
+func (r *MemoryRepo) ListAnnotations(ctx context.Context, analysisID string) ([]Annotation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := append([]Annotation(nil), r.annotations[analysisID]...)
+	r.mu.RUnlock()
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// SetRecommendationState stores st for the user, replacing any earlier state
+// of the same recommendation.
+func (r *MemoryRepo) SetRecommendationState(ctx context.Context, userID string, st RecommendationState) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recommendationStates[userID] == nil {
+		r.recommendationStates[userID] = make(map[string]RecommendationState)
+	}
+	r.recommendationStates[userID][st.RecommendationID] = st
+	return nil
+}
+
+// ClearRecommendationState removes the user's state of a recommendation.
+func (r *MemoryRepo) ClearRecommendationState(ctx context.Context, userID, recommendationID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.recommendationStates[userID], recommendationID)
+	return nil
+}
+
+// ListRecommendationStates returns the user's states ordered by recommendation ID.
+func (r *MemoryRepo) ListRecommendationStates(ctx context.Context, userID string) ([]RecommendationState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]RecommendationState, 0, len(r.recommendationStates[userID]))
+	for _, st := range r.recommendationStates[userID] {
+		out = append(out, st)
+	}
+	r.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].RecommendationID < out[j].RecommendationID })
+	return out, nil
+}
+
+// AnnotationCounts counts annotations created since the given time by prompt
+// version, target and kind.
+func (r *MemoryRepo) AnnotationCounts(ctx context.Context, since time.Time) ([]AnnotationCount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	counts := map[AnnotationCount]int{}
+	for _, list := range r.annotations {
+		for _, a := range list {
+			if a.CreatedAt.Before(since) {
+				continue
+			}
+			counts[AnnotationCount{PromptVersion: a.PromptVersion, Target: a.Target, Kind: a.Kind}]++
+		}
+	}
+	r.mu.RUnlock()
+
+	out := make([]AnnotationCount, 0, len(counts))
+	for key, n := range counts {
+		key.Count = n
+		out = append(out, key)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PromptVersion != out[j].PromptVersion {
+			return out[i].PromptVersion < out[j].PromptVersion
+		}
+		if out[i].Target != out[j].Target {
+			return out[i].Target < out[j].Target
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out, nil
+}
+
+// ListStalledProcessing returns processing analyses last updated before the given time, oldest first.
+func (r *MemoryRepo) ListStalledProcessing(ctx context.Context, before time.Time, limit int) ([]Analysis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Analysis, 0)
+	for _, a := range r.byID {
+		if a.Status == StatusProcessing && a.UpdatedAt.Before(before) {
+			out = append(out, a)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.Before(out[j].UpdatedAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// FailStalled fails an analysis that is still processing and was last updated before the given time.
+func (r *MemoryRepo) FailStalled(ctx context.Context, analysisID string, before time.Time, code, message string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	analysis, ok := r.byID[analysisID]
+	if !ok || analysis.Status != StatusProcessing || !analysis.UpdatedAt.Before(before) {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	analysis.Status = StatusFailed
+	analysis.ErrorCode = code
+	analysis.ErrorMessage = &message
+	analysis.ErrorRetryable = true
+	analysis.CompletedAt = &now
+	analysis.UpdatedAt = now
+	r.byID[analysisID] = analysis
+
+	userAnalyses := r.byUser[analysis.UserID]
+	for i := range userAnalyses {
+		if userAnalyses[i].ID == analysisID {
+			userAnalyses[i] = analysis
+			break
+		}
+	}
+	return true, nil
+}
+
+// PipelineCounts counts unfinished analyses by status and those failed since
+// the given time by error code.
+func (r *MemoryRepo) PipelineCounts(ctx context.Context, failedSince time.Time) (PipelineCounts, error) {
+	if err := ctx.Err(); err != nil {
+		return PipelineCounts{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := PipelineCounts{InFlight: map[string]int{}, ErrorCodes: map[string]int{}}
+	for _, a := range r.byID {
+		switch a.Status {
+		case StatusQueued, StatusBudgetDeferred, StatusProcessing:
+			counts.InFlight[a.Status]++
+			if a.Status == StatusQueued && (counts.OldestQueuedAt == nil || a.CreatedAt.Before(*counts.OldestQueuedAt)) {
+				createdAt := a.CreatedAt
+				counts.OldestQueuedAt = &createdAt
+			}
+		case StatusFailed:
+			if a.CompletedAt == nil || a.CompletedAt.Before(failedSince) {
+				continue
+			}
+			code := a.ErrorCode
+			if code == "" {
+				code = unknownErrorCode
+			}
+			counts.ErrorCodes[code]++
+		}
+	}
+	return counts, nil
+}
+
+// PromptVersionCounts counts the analyses of the listed prompt versions
+// created since the given time, by version, leaving out sample analyses.
+func (r *MemoryRepo) PromptVersionCounts(ctx context.Context, versions []string, since time.Time) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		wanted[v] = true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := map[string]int{}
+	for _, a := range r.byID {
+		if wanted[a.PromptVersion] && !a.CreatedAt.Before(since) && a.Provider != SampleProvider {
+			counts[a.PromptVersion]++
+		}
+	}
+	return counts, nil
+}
@@ -6,11 +6,25 @@ import (
 	"encoding/json"
 	"errors"
 	"time"
+
+	"resume-backend/internal/shared/fieldcrypt"
+	"resume-backend/internal/shared/storage/db"
 )
 
+// jobDescriptionColumn names analyses.job_description to the codec.
+const jobDescriptionColumn = "analyses.job_description"
+
 // PGRepo implements Repo using Postgres.
 type PGRepo struct {
 	DB *sql.DB
+	// Codec encrypts job descriptions; nil stores them in the clear. The
+	// job_description_hash used for reuse is always taken from the plaintext.
+	Codec *fieldcrypt.Codec
+}
+
+// EncryptedColumns lists the columns the PII backfill rewrites.
+func (r *PGRepo) EncryptedColumns() []fieldcrypt.Column {
+	return []fieldcrypt.Column{{Table: "analyses", Name: "job_description"}}
 }
 
 // GetOrCreateForDocument returns the latest analysis for a document or creates a new one.
@@ -26,10 +40,14 @@ func (r *PGRepo) GetOrCreateForDocument(ctx context.Context, analysis Analysis,
 		return Analysis{}, false, err
 	}
 
-	latest, err := getLatestForDocument(ctx, tx, analysis.UserID, analysis.DocumentID)
+	mode := analysis.Mode
+	if mode == "" {
+		mode = ModeJobMatch
+	}
+	latest, err := getLatestForDocument(ctx, tx, r.Codec, analysis, mode)
 	if err == nil {
 		switch latest.Status {
-		case StatusQueued, StatusProcessing:
+		case StatusQueued, StatusProcessing, StatusBudgetDeferred:
 			if err := tx.Commit(); err != nil {
 				return Analysis{}, false, err
 			}
@@ -57,7 +75,7 @@ func (r *PGRepo) GetOrCreateForDocument(ctx context.Context, analysis Analysis,
 		}
 	}
 
-	if err := createWithTx(ctx, tx, analysis); err != nil {
+	if err := createWithTx(ctx, tx, r.Codec, analysis); err != nil {
 		return Analysis{}, false, err
 	}
 	if err := tx.Commit(); err != nil {
@@ -71,9 +89,10 @@ func (r *PGRepo) Create(ctx context.Context, analysis Analysis) error {
 	const query = `
 INSERT INTO analyses (
 	id, document_id, user_id, status, result, analysis_raw, analysis_result, analysis_completed_at,
-	job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model, created_at
+	job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model, created_at,
+	supporting_documents, job_description_hash, learning_plan, org_id, supporting_documents_hash, target_role
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`
 	rawPayload, err := marshalJSONB(analysis.AnalysisRaw)
 	if err != nil {
 		return err
@@ -82,6 +101,14 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 	if err != nil {
 		return err
 	}
+	supportingPayload, err := marshalSupportingDocuments(analysis.SupportingDocuments)
+	if err != nil {
+		return err
+	}
+	jobDescription, err := r.Codec.Encrypt(jobDescriptionColumn, analysis.JobDescription)
+	if err != nil {
+		return err
+	}
 	mode := analysis.Mode
 	if mode == "" {
 		mode = ModeJobMatch
@@ -95,7 +122,7 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 		rawPayload,
 		resultPayload,
 		nil,
-		analysis.JobDescription,
+		jobDescription,
 		analysis.PromptVersion,
 		mode,
 		analysis.AnalysisVersion,
@@ -103,6 +130,12 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 		analysis.Provider,
 		analysis.Model,
 		analysis.CreatedAt,
+		supportingPayload,
+		jobDescriptionHash(analysis),
+		analysis.LearningPlan,
+		analysis.OrgID,
+		HashSupportingDocuments(analysis.SupportingDocuments),
+		analysis.TargetRole,
 	)
 	return err
 }
@@ -113,20 +146,54 @@ func (r *PGRepo) ClaimGuest(ctx context.Context, guestUserID, authedUserID strin
 UPDATE analyses
 SET user_id = $1
 WHERE user_id = $2 AND deleted_at IS NULL`
-	res, err := r.DB.ExecContext(ctx, query, authedUserID, guestUserID)
+	// The guest's recommendation states move too; the user's own state wins
+	// where both have one.
+	const moveStates = `
+INSERT INTO recommendation_states (user_id, recommendation_id, status, updated_at)
+SELECT $1, recommendation_id, status, updated_at
+FROM recommendation_states
+WHERE user_id = $2
+ON CONFLICT (user_id, recommendation_id) DO NOTHING`
+	const deleteStates = `DELETE FROM recommendation_states WHERE user_id = $1`
+	var updated int64
+	err := db.InTx(ctx, r.DB, func(conn db.Conn) error {
+		res, err := conn.ExecContext(ctx, query, authedUserID, guestUserID)
+		if err != nil {
+			return err
+		}
+		updated, _ = res.RowsAffected()
+		if _, err := conn.ExecContext(ctx, moveStates, authedUserID, guestUserID); err != nil {
+			return err
+		}
+		_, err = conn.ExecContext(ctx, deleteStates, guestUserID)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
-	updated, _ := res.RowsAffected()
 	return int(updated), nil
 }
 
+// SoftDeleteByDocument marks every analysis of a document as deleted.
+func (r *PGRepo) SoftDeleteByDocument(ctx context.Context, userID, documentID string, deletedAt time.Time) (int, error) {
+	const query = `
+UPDATE analyses
+SET deleted_at = $1
+WHERE user_id = $2 AND document_id = $3 AND deleted_at IS NULL`
+	res, err := db.ConnFor(ctx, r.DB).ExecContext(ctx, query, deletedAt, userID, documentID)
+	if err != nil {
+		return 0, err
+	}
+	deleted, _ := res.RowsAffected()
+	return int(deleted), nil
+}
+
 // GetByID returns an analysis by ID.
 func (r *PGRepo) GetByID(ctx context.Context, analysisID string) (Analysis, error) {
 	const query = `
 SELECT id, document_id, user_id, status, result, analysis_raw, analysis_result, analysis_completed_at,
-       job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model,
-       error_code, error_message, error_retryable, started_at, completed_at, created_at, updated_at
+       job_description, job_description_hash, prompt_version, mode, analysis_version, prompt_hash, provider, model, supporting_documents,
+       error_code, error_message, error_retryable, started_at, completed_at, created_at, updated_at, learning_plan, org_id, target_role
 FROM analyses
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1`
@@ -136,12 +203,14 @@ LIMIT 1`
 	var analysisResult sql.NullString
 	var analysisCompletedAt sql.NullTime
 	var jobDescription sql.NullString
+	var jobDescriptionHash sql.NullString
 	var promptVersion sql.NullString
 	var mode sql.NullString
 	var analysisVersion sql.NullString
 	var promptHash sql.NullString
 	var provider sql.NullString
 	var model sql.NullString
+	var supportingDocs sql.NullString
 	var errorCode sql.NullString
 	var errorMessage sql.NullString
 	var errorRetryable sql.NullBool
@@ -157,12 +226,14 @@ LIMIT 1`
 		&analysisResult,
 		&analysisCompletedAt,
 		&jobDescription,
+		&jobDescriptionHash,
 		&promptVersion,
 		&mode,
 		&analysisVersion,
 		&promptHash,
 		&provider,
 		&model,
+		&supportingDocs,
 		&errorCode,
 		&errorMessage,
 		&errorRetryable,
@@ -170,6 +241,9 @@ LIMIT 1`
 		&completedAt,
 		&a.CreatedAt,
 		&a.UpdatedAt,
+		&a.LearningPlan,
+		&a.OrgID,
+		&a.TargetRole,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -195,7 +269,12 @@ LIMIT 1`
 		}
 	}
 	if jobDescription.Valid {
-		a.JobDescription = jobDescription.String
+		if a.JobDescription, err = r.Codec.Decrypt(jobDescriptionColumn, jobDescription.String); err != nil {
+			return Analysis{}, err
+		}
+	}
+	if jobDescriptionHash.Valid {
+		a.JobDescriptionHash = jobDescriptionHash.String
 	}
 	if promptVersion.Valid {
 		a.PromptVersion = promptVersion.String
@@ -224,6 +303,7 @@ LIMIT 1`
 	if model.Valid {
 		a.Model = model.String
 	}
+	a.SupportingDocuments = decodeSupportingDocuments(supportingDocs)
 	if errorCode.Valid {
 		a.ErrorCode = errorCode.String
 	}
@@ -372,8 +452,8 @@ func (r *PGRepo) ListByUser(ctx context.Context, userID string, limit, offset in
 
 	const query = `
 SELECT id, document_id, user_id, status, result, analysis_raw, analysis_result, analysis_completed_at,
-       job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model,
-       error_code, error_message, error_retryable, started_at, completed_at, created_at, updated_at
+       job_description, job_description_hash, prompt_version, mode, analysis_version, prompt_hash, provider, model, supporting_documents,
+       error_code, error_message, error_retryable, started_at, completed_at, created_at, updated_at, learning_plan, org_id, target_role
 FROM analyses
 WHERE user_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -393,12 +473,14 @@ LIMIT $2 OFFSET $3`
 		var analysisResult sql.NullString
 		var analysisCompletedAt sql.NullTime
 		var jobDescription sql.NullString
+		var jobDescriptionHash sql.NullString
 		var promptVersion sql.NullString
 		var mode sql.NullString
 		var analysisVersion sql.NullString
 		var promptHash sql.NullString
 		var provider sql.NullString
 		var model sql.NullString
+		var supportingDocs sql.NullString
 		var errorCode sql.NullString
 		var errorMessage sql.NullString
 		var errorRetryable sql.NullBool
@@ -414,12 +496,14 @@ LIMIT $2 OFFSET $3`
 			&analysisResult,
 			&analysisCompletedAt,
 			&jobDescription,
+			&jobDescriptionHash,
 			&promptVersion,
 			&mode,
 			&analysisVersion,
 			&promptHash,
 			&provider,
 			&model,
+			&supportingDocs,
 			&errorCode,
 			&errorMessage,
 			&errorRetryable,
@@ -427,6 +511,9 @@ LIMIT $2 OFFSET $3`
 			&completedAt,
 			&a.CreatedAt,
 			&a.UpdatedAt,
+			&a.LearningPlan,
+			&a.OrgID,
+			&a.TargetRole,
 		); err != nil {
 			return nil, err
 		}
@@ -447,7 +534,12 @@ LIMIT $2 OFFSET $3`
 			}
 		}
 		if jobDescription.Valid {
-			a.JobDescription = jobDescription.String
+			if a.JobDescription, err = r.Codec.Decrypt(jobDescriptionColumn, jobDescription.String); err != nil {
+				return nil, err
+			}
+		}
+		if jobDescriptionHash.Valid {
+			a.JobDescriptionHash = jobDescriptionHash.String
 		}
 		if promptVersion.Valid {
 			a.PromptVersion = promptVersion.String
@@ -476,6 +568,7 @@ LIMIT $2 OFFSET $3`
 		if model.Valid {
 			a.Model = model.String
 		}
+		a.SupportingDocuments = decodeSupportingDocuments(supportingDocs)
 		if errorCode.Valid {
 			a.ErrorCode = errorCode.String
 		}
@@ -505,13 +598,14 @@ func marshalJSONB(value any) ([]byte, error) {
 	return json.Marshal(value)
 }
 
-func createWithTx(ctx context.Context, tx *sql.Tx, analysis Analysis) error {
+func createWithTx(ctx context.Context, tx *sql.Tx, codec *fieldcrypt.Codec, analysis Analysis) error {
 	const query = `
 INSERT INTO analyses (
 	id, document_id, user_id, status, result, analysis_raw, analysis_result, analysis_completed_at,
-	job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model, created_at
+	job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model, created_at,
+	supporting_documents, job_description_hash, learning_plan, org_id, supporting_documents_hash, target_role
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`
 
 	rawPayload, err := marshalJSONB(analysis.AnalysisRaw)
 	if err != nil {
@@ -521,6 +615,14 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 	if err != nil {
 		return err
 	}
+	supportingPayload, err := marshalSupportingDocuments(analysis.SupportingDocuments)
+	if err != nil {
+		return err
+	}
+	jobDescription, err := codec.Encrypt(jobDescriptionColumn, analysis.JobDescription)
+	if err != nil {
+		return err
+	}
 
 	mode := analysis.Mode
 	if mode == "" {
@@ -535,7 +637,7 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 		rawPayload,
 		resultPayload,
 		nil,
-		analysis.JobDescription,
+		jobDescription,
 		analysis.PromptVersion,
 		mode,
 		analysis.AnalysisVersion,
@@ -543,19 +645,31 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 		analysis.Provider,
 		analysis.Model,
 		analysis.CreatedAt,
+		supportingPayload,
+		jobDescriptionHash(analysis),
+		analysis.LearningPlan,
+		analysis.OrgID,
+		HashSupportingDocuments(analysis.SupportingDocuments),
+		analysis.TargetRole,
 	)
 	return err
 }
 
-func getLatestForDocument(ctx context.Context, q queryer, userID, documentID string) (Analysis, error) {
+// getLatestForDocument returns the newest analysis that a start request for
+// analysis may reuse: same document, job description, mode, supporting
+// documents, learning plan option and target role.
+func getLatestForDocument(ctx context.Context, q queryer, codec *fieldcrypt.Codec, analysis Analysis, analysisMode AnalysisMode) (Analysis, error) {
 	const query = `
 SELECT id, document_id, user_id, status, result, analysis_raw, analysis_result, analysis_completed_at,
-       job_description, prompt_version, mode, analysis_version, prompt_hash, provider, model,
-       error_code, error_message, error_retryable, started_at, completed_at, created_at, updated_at
+       job_description, job_description_hash, prompt_version, mode, analysis_version, prompt_hash, provider, model, supporting_documents,
+       error_code, error_message, error_retryable, started_at, completed_at, created_at, updated_at, learning_plan, org_id, target_role
 FROM analyses
-WHERE document_id = $1 AND user_id = $2 AND deleted_at IS NULL
+WHERE document_id = $1 AND user_id = $2 AND job_description_hash = $3 AND mode = $4
+  AND supporting_documents_hash = $5 AND learning_plan = $6 AND target_role = $7 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT 1`
+	jdHash := jobDescriptionHash(analysis)
+	supportingHash := HashSupportingDocuments(analysis.SupportingDocuments)
 
 	var a Analysis
 	var result sql.NullString
@@ -563,19 +677,21 @@ LIMIT 1`
 	var analysisResult sql.NullString
 	var analysisCompletedAt sql.NullTime
 	var jobDescription sql.NullString
+	var jobDescriptionHash sql.NullString
 	var promptVersion sql.NullString
 	var mode sql.NullString
 	var analysisVersion sql.NullString
 	var promptHash sql.NullString
 	var provider sql.NullString
 	var model sql.NullString
+	var supportingDocs sql.NullString
 	var errorCode sql.NullString
 	var errorMessage sql.NullString
 	var errorRetryable sql.NullBool
 	var startedAt sql.NullTime
 	var completedAt sql.NullTime
 
-	err := q.QueryRowContext(ctx, query, documentID, userID).Scan(
+	err := q.QueryRowContext(ctx, query, analysis.DocumentID, analysis.UserID, jdHash, analysisMode, supportingHash, analysis.LearningPlan, analysis.TargetRole).Scan(
 		&a.ID,
 		&a.DocumentID,
 		&a.UserID,
@@ -585,12 +701,14 @@ LIMIT 1`
 		&analysisResult,
 		&analysisCompletedAt,
 		&jobDescription,
+		&jobDescriptionHash,
 		&promptVersion,
 		&mode,
 		&analysisVersion,
 		&promptHash,
 		&provider,
 		&model,
+		&supportingDocs,
 		&errorCode,
 		&errorMessage,
 		&errorRetryable,
@@ -598,6 +716,9 @@ LIMIT 1`
 		&completedAt,
 		&a.CreatedAt,
 		&a.UpdatedAt,
+		&a.LearningPlan,
+		&a.OrgID,
+		&a.TargetRole,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -620,7 +741,12 @@ LIMIT 1`
 		}
 	}
 	if jobDescription.Valid {
-		a.JobDescription = jobDescription.String
+		if a.JobDescription, err = codec.Decrypt(jobDescriptionColumn, jobDescription.String); err != nil {
+			return Analysis{}, err
+		}
+	}
+	if jobDescriptionHash.Valid {
+		a.JobDescriptionHash = jobDescriptionHash.String
 	}
 	if promptVersion.Valid {
 		a.PromptVersion = promptVersion.String
@@ -649,6 +775,7 @@ LIMIT 1`
 	if model.Valid {
 		a.Model = model.String
 	}
+	a.SupportingDocuments = decodeSupportingDocuments(supportingDocs)
 	if errorCode.Valid {
 		a.ErrorCode = errorCode.String
 	}
@@ -670,3 +797,492 @@ LIMIT 1`
 type queryer interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
+
+// ListCompletedSince returns completed analyses finished at or after since, newest first.
+// Sample analyses are left out.
+// Only the fields needed for aggregate reporting are loaded.
+func (r *PGRepo) ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]Analysis, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	const query = `
+SELECT id, document_id, user_id, status, COALESCE(analysis_result, result), prompt_version, mode, model, completed_at
+FROM analyses
+WHERE status = $1 AND deleted_at IS NULL AND completed_at >= $2 AND provider IS DISTINCT FROM $4
+ORDER BY completed_at DESC
+LIMIT $3`
+	rows, err := r.DB.QueryContext(ctx, query, StatusCompleted, since, limit, SampleProvider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Analysis
+	for rows.Next() {
+		var a Analysis
+		var result sql.NullString
+		var promptVersion sql.NullString
+		var mode sql.NullString
+		var model sql.NullString
+		var completedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.DocumentID, &a.UserID, &a.Status, &result, &promptVersion, &mode, &model, &completedAt); err != nil {
+			return nil, err
+		}
+		if result.Valid {
+			if err := json.Unmarshal([]byte(result.String), &a.Result); err != nil {
+				a.Result = nil
+			}
+		}
+		a.PromptVersion = promptVersion.String
+		a.Mode = ModeJobMatch
+		if mode.Valid {
+			if parsed, err := ParseMode(mode.String); err == nil {
+				a.Mode = parsed
+			}
+		}
+		a.Model = model.String
+		if completedAt.Valid {
+			a.CompletedAt = &completedAt.Time
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// LatestByDocuments returns the most recently created analysis of each listed
+// document, keyed by document ID. Documents without analyses are left out.
+func (r *PGRepo) LatestByDocuments(ctx context.Context, documentIDs []string) (map[string]Analysis, error) {
+	out := make(map[string]Analysis)
+	if len(documentIDs) == 0 {
+		return out, nil
+	}
+	const query = `
+SELECT DISTINCT ON (document_id)
+       id, document_id, user_id, status, COALESCE(analysis_result, result), prompt_version, mode, created_at, completed_at
+FROM analyses
+WHERE document_id::text = ANY($1) AND deleted_at IS NULL
+ORDER BY document_id, created_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, documentIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a Analysis
+		var result sql.NullString
+		var promptVersion sql.NullString
+		var mode sql.NullString
+		var completedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.DocumentID, &a.UserID, &a.Status, &result, &promptVersion, &mode, &a.CreatedAt, &completedAt); err != nil {
+			return nil, err
+		}
+		if result.Valid {
+			if err := json.Unmarshal([]byte(result.String), &a.Result); err != nil {
+				a.Result = nil
+			}
+		}
+		a.PromptVersion = promptVersion.String
+		a.Mode = ModeJobMatch
+		if mode.Valid {
+			if parsed, err := ParseMode(mode.String); err == nil {
+				a.Mode = parsed
+			}
+		}
+		if completedAt.Valid {
+			a.CompletedAt = &completedAt.Time
+		}
+		out[a.DocumentID] = a
+	}
+	return out, rows.Err()
+}
+
+// LatestForDocument returns the newest analysis of the document in mode.
+func (r *PGRepo) LatestForDocument(ctx context.Context, userID, documentID string, mode AnalysisMode) (Analysis, error) {
+	const query = `
+SELECT id
+FROM analyses
+WHERE user_id = $1 AND document_id = $2 AND mode = $3 AND deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT 1`
+	var id string
+	if err := r.DB.QueryRowContext(ctx, query, userID, documentID, string(mode)).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Analysis{}, ErrNotFound
+		}
+		return Analysis{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// LatestCompletedForJob returns the user's most recent completed analysis for the
+// same job description and mode, excluding excludeID.
+func (r *PGRepo) LatestCompletedForJob(ctx context.Context, userID, jobDescriptionHash string, mode AnalysisMode, excludeID string) (Analysis, error) {
+	const query = `
+SELECT id
+FROM analyses
+WHERE user_id = $1 AND job_description_hash = $2 AND mode = $3 AND status = $4
+  AND deleted_at IS NULL AND id <> $5
+ORDER BY completed_at DESC
+LIMIT 1`
+	var id string
+	if err := r.DB.QueryRowContext(ctx, query, userID, jobDescriptionHash, string(mode), StatusCompleted, excludeID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Analysis{}, ErrNotFound
+		}
+		return Analysis{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// CompletionLatencyP90 returns the 90th percentile creation-to-completion latency of
+// analyses completed since the given time, and how many there were. Sample
+// analyses are left out.
+func (r *PGRepo) CompletionLatencyP90(ctx context.Context, since time.Time) (time.Duration, int, error) {
+	const query = `
+SELECT COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - created_at)), 0),
+       COUNT(*)
+FROM analyses
+WHERE status = $1 AND completed_at >= $2 AND provider IS DISTINCT FROM $3`
+	var seconds float64
+	var samples int
+	if err := r.DB.QueryRowContext(ctx, query, StatusCompleted, since, SampleProvider).Scan(&seconds, &samples); err != nil {
+		return 0, 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), samples, nil
+}
+
+// ListFailed returns failed analyses with IDs after afterID, in ID order. Only the
+// identity and error fields are loaded.
+func (r *PGRepo) ListFailed(ctx context.Context, afterID string, limit int) ([]Analysis, error) {
+	const query = `
+SELECT id, document_id, user_id, status, error_code, error_message, error_retryable
+FROM analyses
+WHERE status = $1 AND ($2 = '' OR id > $2::uuid)
+ORDER BY id
+LIMIT $3`
+	rows, err := r.DB.QueryContext(ctx, query, StatusFailed, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Analysis
+	for rows.Next() {
+		var a Analysis
+		var errorCode sql.NullString
+		var errorMessage sql.NullString
+		var errorRetryable sql.NullBool
+		if err := rows.Scan(&a.ID, &a.DocumentID, &a.UserID, &a.Status, &errorCode, &errorMessage, &errorRetryable); err != nil {
+			return nil, err
+		}
+		a.ErrorCode = errorCode.String
+		if errorMessage.Valid {
+			a.ErrorMessage = &errorMessage.String
+		}
+		a.ErrorRetryable = errorRetryable.Bool
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// UpdateFailureClassification sets the error code and retryable flag of a failed analysis.
+func (r *PGRepo) UpdateFailureClassification(ctx context.Context, analysisID, errorCode string, retryable bool) error {
+	const query = `
+UPDATE analyses
+SET error_code = $1, error_retryable = $2, updated_at = now()
+WHERE id = $3::uuid AND status = $4`
+	res, err := r.DB.ExecContext(ctx, query, errorCode, retryable, analysisID, StatusFailed)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListBudgetDeferred returns analyses waiting on an LLM budget, oldest first.
+func (r *PGRepo) ListBudgetDeferred(ctx context.Context, limit int) ([]Analysis, error) {
+	const query = `
+SELECT id
+FROM analyses
+WHERE status = $1 AND deleted_at IS NULL
+ORDER BY created_at
+LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, StatusBudgetDeferred, limit)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]Analysis, 0, len(ids))
+	for _, id := range ids {
+		a, err := r.GetByID(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// UpsertAnnotation stores a, replacing any annotation of the same item.
+func (r *PGRepo) UpsertAnnotation(ctx context.Context, a Annotation) (Annotation, error) {
+	const query = `
+INSERT INTO analysis_annotations (id, analysis_id, target, ref, item_index, keyword, kind, note, prompt_version, created_at)
+VALUES ($1, $2::uuid, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (analysis_id, target, ref) DO UPDATE
+SET keyword = EXCLUDED.keyword, kind = EXCLUDED.kind, note = EXCLUDED.note,
+    prompt_version = EXCLUDED.prompt_version, created_at = EXCLUDED.created_at
+RETURNING id`
+	var index sql.NullInt64
+	if a.Index != nil {
+		index = sql.NullInt64{Int64: int64(*a.Index), Valid: true}
+	}
+	if err := r.DB.QueryRowContext(ctx, query, a.ID, a.AnalysisID, a.Target, a.ref(), index, a.Keyword, a.Kind, a.Note, a.PromptVersion, a.CreatedAt).Scan(&a.ID); err != nil {
+		return Annotation{}, err
+	}
+	return a, nil
+}
+
+// ListAnnotations returns an analysis's annotations, oldest first.
+func (r *PGRepo) ListAnnotations(ctx context.Context, analysisID string) ([]Annotation, error) {
+	const query = `
+SELECT id, analysis_id, target, item_index, keyword, kind, note, prompt_version, created_at
+FROM analysis_annotations
+WHERE analysis_id = $1::uuid
+ORDER BY created_at, id`
+	rows, err := r.DB.QueryContext(ctx, query, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Annotation
+	for rows.Next() {
+		var a Annotation
+		var index sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.AnalysisID, &a.Target, &index, &a.Keyword, &a.Kind, &a.Note, &a.PromptVersion, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if index.Valid {
+			i := int(index.Int64)
+			a.Index = &i
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetRecommendationState stores st for the user, replacing any earlier state
+// of the same recommendation.
+func (r *PGRepo) SetRecommendationState(ctx context.Context, userID string, st RecommendationState) error {
+	const query = `
+INSERT INTO recommendation_states (user_id, recommendation_id, status, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, recommendation_id)
+DO UPDATE SET status = EXCLUDED.status, updated_at = EXCLUDED.updated_at`
+	_, err := r.DB.ExecContext(ctx, query, userID, st.RecommendationID, st.Status, st.UpdatedAt)
+	return err
+}
+
+// ClearRecommendationState removes the user's state of a recommendation.
+func (r *PGRepo) ClearRecommendationState(ctx context.Context, userID, recommendationID string) error {
+	const query = `DELETE FROM recommendation_states WHERE user_id = $1 AND recommendation_id = $2`
+	_, err := r.DB.ExecContext(ctx, query, userID, recommendationID)
+	return err
+}
+
+// ListRecommendationStates returns the user's states ordered by recommendation ID.
+func (r *PGRepo) ListRecommendationStates(ctx context.Context, userID string) ([]RecommendationState, error) {
+	const query = `
+SELECT recommendation_id, status, updated_at
+FROM recommendation_states
+WHERE user_id = $1
+ORDER BY recommendation_id`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []RecommendationState
+	for rows.Next() {
+		var st RecommendationState
+		if err := rows.Scan(&st.RecommendationID, &st.Status, &st.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// AnnotationCounts counts annotations created since the given time by prompt
+// version, target and kind.
+func (r *PGRepo) AnnotationCounts(ctx context.Context, since time.Time) ([]AnnotationCount, error) {
+	const query = `
+SELECT prompt_version, target, kind, COUNT(*)
+FROM analysis_annotations
+WHERE created_at >= $1
+GROUP BY prompt_version, target, kind
+ORDER BY prompt_version, target, kind`
+	rows, err := r.DB.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []AnnotationCount
+	for rows.Next() {
+		var c AnnotationCount
+		if err := rows.Scan(&c.PromptVersion, &c.Target, &c.Kind, &c.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// ListStalledProcessing returns processing analyses last updated before the given time, oldest first.
+func (r *PGRepo) ListStalledProcessing(ctx context.Context, before time.Time, limit int) ([]Analysis, error) {
+	const query = `
+SELECT id, document_id, user_id, status, started_at, updated_at
+FROM analyses
+WHERE status = $1 AND updated_at < $2 AND deleted_at IS NULL
+ORDER BY updated_at
+LIMIT $3`
+	rows, err := r.DB.QueryContext(ctx, query, StatusProcessing, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Analysis
+	for rows.Next() {
+		var a Analysis
+		var startedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.DocumentID, &a.UserID, &a.Status, &startedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if startedAt.Valid {
+			a.StartedAt = &startedAt.Time
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// FailStalled fails an analysis that is still processing and was last updated
+// before the given time. The condition is checked in the update itself, so a
+// worker that reports progress meanwhile wins.
+func (r *PGRepo) FailStalled(ctx context.Context, analysisID string, before time.Time, code, message string) (bool, error) {
+	const query = `
+UPDATE analyses
+SET status = $1, error_code = $2, error_message = $3, error_retryable = true,
+    completed_at = now(), updated_at = now()
+WHERE id = $4::uuid AND status = $5 AND updated_at < $6`
+	res, err := r.DB.ExecContext(ctx, query, StatusFailed, code, message, analysisID, StatusProcessing, before)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// PipelineCounts counts unfinished analyses by status and those failed since
+// the given time by error code.
+func (r *PGRepo) PipelineCounts(ctx context.Context, failedSince time.Time) (PipelineCounts, error) {
+	const inFlightQuery = `
+SELECT status, COUNT(*), MIN(created_at)
+FROM analyses
+WHERE status IN ($1, $2, $3) AND deleted_at IS NULL
+GROUP BY status`
+	const failedQuery = `
+SELECT COALESCE(NULLIF(error_code, ''), $3), COUNT(*)
+FROM analyses
+WHERE status = $1 AND completed_at >= $2 AND deleted_at IS NULL
+GROUP BY 1`
+	counts := PipelineCounts{InFlight: map[string]int{}, ErrorCodes: map[string]int{}}
+	rows, err := r.DB.QueryContext(ctx, inFlightQuery, StatusQueued, StatusBudgetDeferred, StatusProcessing)
+	if err != nil {
+		return PipelineCounts{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			status string
+			n      int
+			oldest time.Time
+		)
+		if err := rows.Scan(&status, &n, &oldest); err != nil {
+			return PipelineCounts{}, err
+		}
+		counts.InFlight[status] = n
+		if status == StatusQueued {
+			counts.OldestQueuedAt = &oldest
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return PipelineCounts{}, err
+	}
+
+	failed, err := r.DB.QueryContext(ctx, failedQuery, StatusFailed, failedSince, unknownErrorCode)
+	if err != nil {
+		return PipelineCounts{}, err
+	}
+	defer failed.Close()
+	for failed.Next() {
+		var (
+			code string
+			n    int
+		)
+		if err := failed.Scan(&code, &n); err != nil {
+			return PipelineCounts{}, err
+		}
+		counts.ErrorCodes[code] = n
+	}
+	return counts, failed.Err()
+}
+
+// PromptVersionCounts counts the analyses of the listed prompt versions
+// created since the given time, by version, leaving out sample analyses.
+func (r *PGRepo) PromptVersionCounts(ctx context.Context, versions []string, since time.Time) (map[string]int, error) {
+	const query = `
+SELECT prompt_version, COUNT(*)
+FROM analyses
+WHERE prompt_version = ANY($1) AND created_at >= $2 AND provider IS DISTINCT FROM $3
+GROUP BY prompt_version`
+	rows, err := r.DB.QueryContext(ctx, query, versions, since, SampleProvider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := map[string]int{}
+	for rows.Next() {
+		var (
+			version string
+			n       int
+		)
+		if err := rows.Scan(&version, &n); err != nil {
+			return nil, err
+		}
+		counts[version] = n
+	}
+	return counts, rows.Err()
+}
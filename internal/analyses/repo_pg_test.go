@@ -2,10 +2,13 @@ package analyses
 
 import (
 	"context"
+	"database/sql/driver"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+
+	"resume-backend/internal/shared/fieldcrypt"
 )
 
 func TestPGRepoCreateIncludesPromptMetadata(t *testing.T) {
@@ -48,6 +51,12 @@ func TestPGRepoCreateIncludesPromptMetadata(t *testing.T) {
 			analysis.Provider,
 			analysis.Model,
 			sqlmock.AnyArg(),
+			[]byte("[]"), // supporting_documents
+			HashJobDescription(analysis.JobDescription),
+			false, // learning_plan
+			"",    // org_id
+			"",    // supporting_documents_hash
+			"",    // target_role
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -58,3 +67,56 @@ func TestPGRepoCreateIncludesPromptMetadata(t *testing.T) {
 		t.Fatalf("ExpectationsWereMet: %v", err)
 	}
 }
+
+// sealedJobDescription matches an encrypted job description that opens to want.
+type sealedJobDescription struct {
+	codec *fieldcrypt.Codec
+	want  string
+}
+
+func (m sealedJobDescription) Match(v driver.Value) bool {
+	stored, ok := v.(string)
+	if !ok || !fieldcrypt.IsEncrypted(stored) {
+		return false
+	}
+	plain, err := m.codec.Decrypt(jobDescriptionColumn, stored)
+	return err == nil && plain == m.want
+}
+
+func TestPGRepoCreateEncryptsJobDescription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	codec, err := fieldcrypt.New([]fieldcrypt.Key{{ID: "k1", Material: make([]byte, fieldcrypt.KeySize)}})
+	if err != nil {
+		t.Fatalf("fieldcrypt.New: %v", err)
+	}
+	repo := &PGRepo{DB: db, Codec: codec}
+	analysis := Analysis{
+		ID:             "analysis-1",
+		DocumentID:     "doc-1",
+		UserID:         "user-1",
+		Status:         StatusQueued,
+		JobDescription: "Senior Go engineer",
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	args := make([]driver.Value, 22)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[8] = sealedJobDescription{codec: codec, want: analysis.JobDescription}
+	// The reuse hash is still taken from the plaintext.
+	args[17] = HashJobDescription(analysis.JobDescription)
+	mock.ExpectExec("INSERT INTO analyses").WithArgs(args...).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := repo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package analyses
+
+import (
+	"context"
+	"time"
+)
+
+// WorkerRepo restricts a Repo to what the worker needs to process analyses and
+// purge guest data. Creating and listing analyses belong to the API, so a leaked
+// worker credential cannot be used to enumerate users' analyses through it.
+type WorkerRepo struct {
+	repo Repo
+}
+
+var _ Repo = (*WorkerRepo)(nil)
+
+// NewWorkerRepo wraps repo for the worker role.
+func NewWorkerRepo(repo Repo) *WorkerRepo {
+	return &WorkerRepo{repo: repo}
+}
+
+func (r *WorkerRepo) Create(ctx context.Context, analysis Analysis) error {
+	return ErrNotPermitted
+}
+
+func (r *WorkerRepo) GetOrCreateForDocument(ctx context.Context, analysis Analysis, allowRetry bool, allowCreate func() error) (Analysis, bool, error) {
+	return Analysis{}, false, ErrNotPermitted
+}
+
+func (r *WorkerRepo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]Analysis, error) {
+	return nil, ErrNotPermitted
+}
+
+func (r *WorkerRepo) GetByID(ctx context.Context, analysisID string) (Analysis, error) {
+	return r.repo.GetByID(ctx, analysisID)
+}
+
+func (r *WorkerRepo) UpdateStatus(ctx context.Context, analysisID, status string, result map[string]any) error {
+	return r.repo.UpdateStatus(ctx, analysisID, status, result)
+}
+
+func (r *WorkerRepo) UpdateStatusResultAndError(ctx context.Context, analysisID, status string, result map[string]any, errorCode *string, errorMessage *string, errorRetryable *bool, startedAt *time.Time, completedAt *time.Time) error {
+	return r.repo.UpdateStatusResultAndError(ctx, analysisID, status, result, errorCode, errorMessage, errorRetryable, startedAt, completedAt)
+}
+
+func (r *WorkerRepo) UpdateAnalysisRaw(ctx context.Context, analysisID string, raw any) error {
+	return r.repo.UpdateAnalysisRaw(ctx, analysisID, raw)
+}
+
+func (r *WorkerRepo) UpdateAnalysisResult(ctx context.Context, analysisID string, result map[string]any, completedAt *time.Time) error {
+	return r.repo.UpdateAnalysisResult(ctx, analysisID, result, completedAt)
+}
+
+func (r *WorkerRepo) UpdatePromptMetadata(ctx context.Context, analysisID, analysisVersion, promptHash string) error {
+	return r.repo.UpdatePromptMetadata(ctx, analysisID, analysisVersion, promptHash)
+}
+
+// LatestCompletedForJob lets delta re-analysis find the analysis it builds on.
+func (r *WorkerRepo) LatestCompletedForJob(ctx context.Context, userID, jobDescriptionHash string, mode AnalysisMode, excludeID string) (Analysis, error) {
+	finder, ok := r.repo.(previousAnalysisFinder)
+	if !ok {
+		return Analysis{}, ErrNotFound
+	}
+	return finder.LatestCompletedForJob(ctx, userID, jobDescriptionHash, mode, excludeID)
+}
+
+// SoftDeleteByDocument lets guest retention delete a purged document's analyses.
+func (r *WorkerRepo) SoftDeleteByDocument(ctx context.Context, userID, documentID string, deletedAt time.Time) (int, error) {
+	deleter, ok := r.repo.(interface {
+		SoftDeleteByDocument(ctx context.Context, userID, documentID string, deletedAt time.Time) (int, error)
+	})
+	if !ok {
+		return 0, ErrNotPermitted
+	}
+	return deleter.SoftDeleteByDocument(ctx, userID, documentID, deletedAt)
+}
+
+// ListCompletedSince lets batch re-scoring select the analyses it scores again.
+func (r *WorkerRepo) ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]Analysis, error) {
+	lister, ok := r.repo.(interface {
+		ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]Analysis, error)
+	})
+	if !ok {
+		return nil, ErrNotPermitted
+	}
+	return lister.ListCompletedSince(ctx, since, limit)
+}
+
+// ListBudgetDeferred lets the worker queue analyses once their LLM budget resets.
+func (r *WorkerRepo) ListBudgetDeferred(ctx context.Context, limit int) ([]Analysis, error) {
+	lister, ok := r.repo.(budgetDeferredLister)
+	if !ok {
+		return nil, ErrNotPermitted
+	}
+	return lister.ListBudgetDeferred(ctx, limit)
+}
+
+// ListStalledProcessing lets the stuck-state detector find analyses whose worker stopped.
+func (r *WorkerRepo) ListStalledProcessing(ctx context.Context, before time.Time, limit int) ([]Analysis, error) {
+	store, ok := r.repo.(stalledStore)
+	if !ok {
+		return nil, ErrNotPermitted
+	}
+	return store.ListStalledProcessing(ctx, before, limit)
+}
+
+// FailStalled lets the stuck-state detector fail a stalled analysis.
+func (r *WorkerRepo) FailStalled(ctx context.Context, analysisID string, before time.Time, code, message string) (bool, error) {
+	store, ok := r.repo.(stalledStore)
+	if !ok {
+		return false, ErrNotPermitted
+	}
+	return store.FailStalled(ctx, analysisID, before, code, message)
+}
@@ -0,0 +1,38 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkerRepoAllowsOnlyWorkerOperations(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemoryRepo()
+	if err := inner.Create(ctx, Analysis{ID: "a1", DocumentID: "d1", UserID: "u1", Status: "queued", CreatedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	repo := NewWorkerRepo(inner)
+
+	if err := repo.Create(ctx, Analysis{ID: "a2", DocumentID: "d2", UserID: "u1"}); !errors.Is(err, ErrNotPermitted) {
+		t.Fatalf("create: expected ErrNotPermitted, got %v", err)
+	}
+	if _, _, err := repo.GetOrCreateForDocument(ctx, Analysis{ID: "a2", DocumentID: "d2", UserID: "u1"}, false, nil); !errors.Is(err, ErrNotPermitted) {
+		t.Fatalf("get or create: expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := repo.ListByUser(ctx, "u1", 10, 0); !errors.Is(err, ErrNotPermitted) {
+		t.Fatalf("list: expected ErrNotPermitted, got %v", err)
+	}
+
+	if err := repo.UpdateStatus(ctx, "a1", "processing", nil); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	got, err := repo.GetByID(ctx, "a1")
+	if err != nil || got.Status != "processing" {
+		t.Fatalf("expected the worker to read its own update, got %+v err=%v", got, err)
+	}
+	if _, err := repo.SoftDeleteByDocument(ctx, "u1", "d1", time.Now().UTC()); err != nil {
+		t.Fatalf("soft delete by document: %v", err)
+	}
+}
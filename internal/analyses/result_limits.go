@@ -0,0 +1,140 @@
+package analyses
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"resume-backend/internal/analyses/recommendations"
+)
+
+// ResultLimits caps the lists a normalized result may hold, so a chatty model
+// cannot bloat stored results or the UI. Zero fields use the defaults.
+type ResultLimits struct {
+	Issues          int
+	BulletRewrites  int
+	Recommendations int
+}
+
+// DefaultResultLimits apply until SetResultLimits overrides them.
+var DefaultResultLimits = ResultLimits{
+	Issues:          40,
+	BulletRewrites:  25,
+	Recommendations: recommendations.DefaultLimit,
+}
+
+// OverflowCountsV1 records how many entries of each list normalization dropped
+// to stay within ResultLimits. Lists that fit are omitted.
+type OverflowCountsV1 struct {
+	Issues          int `json:"issues,omitempty"`
+	BulletRewrites  int `json:"bulletRewrites,omitempty"`
+	Recommendations int `json:"recommendations,omitempty"`
+}
+
+var (
+	resultLimitsMu sync.RWMutex
+	resultLimits   = DefaultResultLimits
+)
+
+// SetResultLimits replaces the list caps applied at normalization. Zero or
+// negative fields restore the matching default.
+func SetResultLimits(limits ResultLimits) {
+	if limits.Issues <= 0 {
+		limits.Issues = DefaultResultLimits.Issues
+	}
+	if limits.BulletRewrites <= 0 {
+		limits.BulletRewrites = DefaultResultLimits.BulletRewrites
+	}
+	if limits.Recommendations <= 0 {
+		limits.Recommendations = DefaultResultLimits.Recommendations
+	}
+	resultLimitsMu.Lock()
+	resultLimits = limits
+	resultLimitsMu.Unlock()
+}
+
+func currentResultLimits() ResultLimits {
+	resultLimitsMu.RLock()
+	defer resultLimitsMu.RUnlock()
+	return resultLimits
+}
+
+// capIssues keeps the limit most important issues: by severity, then by the
+// model's priority (1 first, unset last), then by position. Kept issues stay in
+// their original order.
+func capIssues(issues []IssueV2_2, limit int) ([]IssueV2_2, int) {
+	return keepTop(issues, limit, func(a, b IssueV2_2) bool {
+		if ra, rb := issueSeverityRank(a.Severity), issueSeverityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		return issuePriorityRank(a.Priority) < issuePriorityRank(b.Priority)
+	})
+}
+
+// capBulletRewrites keeps the limit rewrites best backed by the resume:
+// supported claims, then inferred, then placeholders, then by position. Kept
+// rewrites stay in their original order.
+func capBulletRewrites(rewrites []NormalizedBulletRewrite, limit int) ([]NormalizedBulletRewrite, int) {
+	return keepTop(rewrites, limit, func(a, b NormalizedBulletRewrite) bool {
+		return claimSupportRank(a.ClaimSupport) < claimSupportRank(b.ClaimSupport)
+	})
+}
+
+// keepTop returns the first limit items under less, ties broken by position,
+// in their original order, and how many were dropped.
+func keepTop[T any](items []T, limit int, less func(a, b T) bool) ([]T, int) {
+	if len(items) <= limit {
+		return items, 0
+	}
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(items[order[i]], items[order[j]])
+	})
+	keep := make([]bool, len(items))
+	for _, i := range order[:limit] {
+		keep[i] = true
+	}
+	kept := make([]T, 0, limit)
+	for i, item := range items {
+		if keep[i] {
+			kept = append(kept, item)
+		}
+	}
+	return kept, len(items) - limit
+}
+
+func issueSeverityRank(severity IssueSeverityV1) int {
+	switch IssueSeverityV1(strings.ToLower(strings.TrimSpace(string(severity)))) {
+	case IssueSeverityCritical:
+		return 0
+	case IssueSeverityHigh:
+		return 1
+	case IssueSeverityMedium:
+		return 2
+	case IssueSeverityLow:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func issuePriorityRank(priority int) int {
+	if priority < 1 {
+		return 11
+	}
+	return priority
+}
+
+func claimSupportRank(support string) int {
+	switch support {
+	case "supported":
+		return 0
+	case "inferred":
+		return 1
+	default:
+		return 2
+	}
+}
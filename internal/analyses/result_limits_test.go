@@ -0,0 +1,93 @@
+package analyses
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCapsListsAndRecordsOverflow(t *testing.T) {
+	SetResultLimits(ResultLimits{Issues: 3, BulletRewrites: 2, Recommendations: 2})
+	t.Cleanup(func() { SetResultLimits(ResultLimits{}) })
+
+	severities := []string{"low", "medium", "critical", "low", "high", "medium"}
+	issues := make([]string, 0, len(severities))
+	for i, severity := range severities {
+		issues = append(issues, fmt.Sprintf(`{"severity":%q,"section":"Experience","problem":"p%d","whyItMatters":"w","suggestion":"s"}`, severity, i))
+	}
+	rewrites := make([]string, 0, 4)
+	for i := range 4 {
+		rewrites = append(rewrites, fmt.Sprintf(`{"section":"Experience","before":"b%d","after":"a%d","rationale":"r"}`, i, i))
+	}
+	raw := []byte(`{
+  "summary": {"overallAssessment": "ok", "strengths": [], "weaknesses": []},
+  "ats": {"score": 70, "missingKeywords": ["go", "aws"], "formattingIssues": ["tables"]},
+  "issues": [` + strings.Join(issues, ",") + `],
+  "bulletRewrites": [` + strings.Join(rewrites, ",") + `],
+  "missingInformation": [],
+  "actionPlan": {"quickWins": [], "mediumEffort": [], "deepFixes": []}
+}`)
+
+	result, err := normalizeToFinal(raw, Analysis{PromptVersion: "v1", Model: "test-model", Mode: ModeATS})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+
+	var problems []string
+	for _, issue := range result.Issues {
+		problems = append(problems, issue.Problem)
+	}
+	if got := strings.Join(problems, ","); got != "p1,p2,p4" {
+		t.Fatalf("expected the three most severe issues in original order, got %s", got)
+	}
+	if len(result.Recommendations) != 2 {
+		t.Fatalf("expected 2 recommendations, got %d", len(result.Recommendations))
+	}
+
+	overflow := result.Meta.OverflowCounts
+	if overflow == nil || overflow.Issues != 3 || overflow.BulletRewrites != 2 || overflow.Recommendations != 3 {
+		payload, _ := json.Marshal(overflow)
+		t.Fatalf("unexpected overflow counts: %s", payload)
+	}
+}
+
+func TestRecommendationOverflowIgnoresOrdinaryTrimming(t *testing.T) {
+	for _, tc := range []struct{ kept, dropped, want int }{
+		{kept: 7, dropped: 4, want: 0},
+		{kept: 2, dropped: 9, want: 5},
+		{kept: 2, dropped: 3, want: 3},
+		{kept: 10, dropped: 1, want: 0},
+	} {
+		if got := recommendationOverflow(tc.kept, tc.dropped); got != tc.want {
+			t.Errorf("recommendationOverflow(%d, %d) = %d, want %d", tc.kept, tc.dropped, got, tc.want)
+		}
+	}
+}
+
+func TestCapBulletRewritesPrefersSupportedClaims(t *testing.T) {
+	var rewrites []NormalizedBulletRewrite
+	for i, support := range []string{"placeholder", "supported", "inferred", "supported"} {
+		rewrites = append(rewrites, NormalizedBulletRewrite{Before: fmt.Sprintf("b%d", i), ClaimSupport: support})
+	}
+	kept, dropped := capBulletRewrites(rewrites, 3)
+	var befores []string
+	for _, rewrite := range kept {
+		befores = append(befores, rewrite.Before)
+	}
+	if got := strings.Join(befores, ","); got != "b1,b2,b3" || dropped != 1 {
+		t.Fatalf("expected b1,b2,b3 with 1 dropped, got %s with %d dropped", got, dropped)
+	}
+}
+
+func TestNormalizeOmitsOverflowWithinLimits(t *testing.T) {
+	raw := loadFixture(t, "testdata/v1_good.json")
+	result, err := normalizeAnalysisResult(raw, Analysis{PromptVersion: "v1", Model: "test-model"})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	meta, _ := result["meta"].(map[string]any)
+	if _, ok := meta["overflowCounts"]; ok {
+		t.Fatalf("expected no overflowCounts when every list fits, got %v", meta["overflowCounts"])
+	}
+}
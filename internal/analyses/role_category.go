@@ -0,0 +1,43 @@
+package analyses
+
+import (
+	"strings"
+
+	"resume-backend/internal/analyses/roles"
+)
+
+// MaxTargetRoleRunes bounds the length of a target role.
+const MaxTargetRoleRunes = 120
+
+// NormalizeTargetRole trims a target role and collapses its whitespace, so
+// "Senior  iOS Developer " and "Senior iOS Developer" reuse one analysis.
+func NormalizeTargetRole(targetRole string) string {
+	return strings.Join(strings.Fields(targetRole), " ")
+}
+
+// withRoleCategory records the role category of the job description under
+// meta.roleCategory, so aggregate reports can group results by role without
+// reading job descriptions. The target role is used when the job description
+// names no role. Results without a recognizable role get none.
+func withRoleCategory(result map[string]any, jobDescription, targetRole string) {
+	category := roles.Classify(jobDescription)
+	if category == "" {
+		category = roles.Classify(targetRole)
+	}
+	if result == nil || category == "" {
+		return
+	}
+	meta, ok := result["meta"].(map[string]any)
+	if !ok {
+		meta = map[string]any{}
+		result["meta"] = meta
+	}
+	meta["roleCategory"] = category
+}
+
+// RoleCategory returns the role category recorded with a result, if any.
+func RoleCategory(result map[string]any) string {
+	meta, _ := result["meta"].(map[string]any)
+	category, _ := meta["roleCategory"].(string)
+	return category
+}
@@ -0,0 +1,165 @@
+// Package roles sorts job descriptions into broad role categories, so results
+// can be aggregated by the kind of role without keeping the description, and
+// maps a target role to the keywords analyses without a job description are
+// evaluated against.
+package roles
+
+import (
+	"slices"
+	"strings"
+)
+
+// Role categories.
+const (
+	Backend   = "backend"
+	Frontend  = "frontend"
+	Fullstack = "fullstack"
+	Mobile    = "mobile"
+	Data      = "data"
+	ML        = "ml"
+	DevOps    = "devops"
+	Security  = "security"
+	QA        = "qa"
+	Product   = "product"
+	Design    = "design"
+)
+
+// titleWindow is how much of the start of a job description is searched for
+// a title before the whole text is.
+const titleWindow = 300
+
+// category lists the phrases that identify a role category and the keywords
+// resumes for it usually carry. Categories are checked in order, so a
+// fullstack title is not taken for a backend one.
+type category struct {
+	name     string
+	label    string
+	phrases  []string
+	keywords []string
+}
+
+var categories = []category{
+	{Fullstack, "Full-stack engineer",
+		[]string{"full stack", "full-stack", "fullstack"},
+		[]string{"JavaScript", "TypeScript", "React", "Node.js", "REST APIs", "SQL", "CI/CD", "cloud deployment"}},
+	{ML, "Machine learning engineer",
+		[]string{"machine learning", "ml engineer", "deep learning", "ai engineer", "applied scientist", "llm"},
+		[]string{"Python", "PyTorch", "TensorFlow", "model training", "feature engineering", "MLOps", "model evaluation", "LLMs"}},
+	{Data, "Data professional",
+		[]string{"data engineer", "data scientist", "data analyst", "analytics engineer", "business intelligence", "etl"},
+		[]string{"SQL", "Python", "ETL", "data modeling", "data warehousing", "Spark", "dbt", "dashboards"}},
+	{Mobile, "Mobile engineer",
+		[]string{"ios", "android", "mobile", "react native", "flutter", "swift", "kotlin"},
+		[]string{"Swift", "Kotlin", "iOS", "Android", "React Native", "mobile UI", "App Store releases", "offline sync"}},
+	{Frontend, "Frontend engineer",
+		[]string{"frontend", "front-end", "front end", "react", "angular", "vue", "ui engineer", "web developer"},
+		[]string{"JavaScript", "TypeScript", "React", "HTML", "CSS", "accessibility", "web performance", "component libraries"}},
+	{DevOps, "DevOps engineer",
+		[]string{"devops", "site reliability", "sre", "platform engineer", "infrastructure engineer", "cloud engineer", "kubernetes"},
+		[]string{"Kubernetes", "Docker", "Terraform", "CI/CD", "AWS", "monitoring", "incident response", "Linux"}},
+	{Security, "Security engineer",
+		[]string{"security engineer", "security analyst", "appsec", "penetration", "cybersecurity", "infosec"},
+		[]string{"threat modeling", "vulnerability management", "SIEM", "penetration testing", "IAM", "incident response", "OWASP", "compliance"}},
+	{QA, "QA engineer",
+		[]string{"qa engineer", "quality assurance", "test engineer", "sdet", "test automation"},
+		[]string{"test automation", "Selenium", "Cypress", "test plans", "regression testing", "API testing", "CI/CD", "bug tracking"}},
+	{Product, "Product manager",
+		[]string{"product manager", "product owner", "program manager"},
+		[]string{"roadmapping", "stakeholder management", "user research", "prioritization", "OKRs", "A/B testing", "go-to-market", "analytics"}},
+	{Design, "Product designer",
+		[]string{"ux designer", "ui designer", "product designer", "ux researcher", "visual designer"},
+		[]string{"Figma", "user research", "prototyping", "wireframing", "design systems", "usability testing", "interaction design", "accessibility"}},
+	{Backend, "Backend engineer",
+		[]string{"backend", "back-end", "back end", "server-side", "api engineer", "golang", "java developer", "microservices"},
+		[]string{"REST APIs", "SQL", "microservices", "distributed systems", "Go", "Java", "caching", "message queues"}},
+}
+
+// Categories lists the role categories in a stable order.
+func Categories() []string {
+	out := make([]string, len(categories))
+	for i, c := range categories {
+		out[i] = c.name
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Valid reports whether name is a role category.
+func Valid(name string) bool {
+	return slices.Contains(Categories(), name)
+}
+
+// Classify returns the role category of a job description, or "" when none
+// fits. A category named near the start, where the title usually is, wins;
+// otherwise the category whose phrases occur most often does.
+func Classify(jobDescription string) string {
+	text := strings.ToLower(jobDescription)
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	head := text
+	if len(head) > titleWindow {
+		head = head[:titleWindow]
+	}
+	for _, c := range categories {
+		if countPhrases(head, c.phrases) > 0 {
+			return c.name
+		}
+	}
+	best, bestCount := "", 0
+	for _, c := range categories {
+		if n := countPhrases(text, c.phrases); n > bestCount {
+			best, bestCount = c.name, n
+		}
+	}
+	return best
+}
+
+// Profile is what the taxonomy knows about a target role.
+type Profile struct {
+	// Category is the role category the target role falls into.
+	Category string
+	// Label names the category for prompts.
+	Label string
+	// Keywords are skills and terms resumes for the category usually carry.
+	Keywords []string
+}
+
+// Lookup returns the profile of a target role such as "Senior iOS Developer".
+// It reports false when the role fits no category.
+func Lookup(targetRole string) (Profile, bool) {
+	name := Classify(targetRole)
+	for _, c := range categories {
+		if c.name == name {
+			return Profile{Category: c.name, Label: c.label, Keywords: slices.Clone(c.keywords)}, true
+		}
+	}
+	return Profile{}, false
+}
+
+// countPhrases counts occurrences of the phrases as whole words.
+func countPhrases(text string, phrases []string) int {
+	n := 0
+	for _, phrase := range phrases {
+		for rest := text; ; {
+			i := strings.Index(rest, phrase)
+			if i < 0 {
+				break
+			}
+			end := i + len(phrase)
+			if boundary(rest, i-1) && boundary(rest, end) {
+				n++
+			}
+			rest = rest[end:]
+		}
+	}
+	return n
+}
+
+func boundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	b := text[i]
+	return !(b >= 'a' && b <= 'z' || b >= '0' && b <= '9')
+}
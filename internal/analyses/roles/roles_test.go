@@ -0,0 +1,68 @@
+package roles
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		jd   string
+		want string
+	}{
+		{"Senior Backend Engineer\nWe build APIs in Go and Postgres.", Backend},
+		{"Full-Stack Developer (React/Node)\nShip features end to end.", Fullstack},
+		{"Data Scientist, Growth\nModel experiments with Python.", Data},
+		{"We are hiring! " + pad() + " You will build React components and work with our frontend team on Vue migrations.", Frontend},
+		{"Site Reliability Engineer - on-call for Kubernetes clusters", DevOps},
+		{"Barista wanted for our downtown cafe", ""},
+		{"", ""},
+		// "sre" must not match inside "presentation".
+		{"Account executive. Strong presentation skills.", ""},
+	}
+	for _, tc := range cases {
+		if got := Classify(tc.jd); got != tc.want {
+			t.Errorf("Classify(%.40q) = %q, want %q", tc.jd, got, tc.want)
+		}
+	}
+}
+
+func TestCategoriesAreValid(t *testing.T) {
+	for _, name := range Categories() {
+		if !Valid(name) {
+			t.Fatalf("%s should be valid", name)
+		}
+	}
+	if Valid("astronaut") {
+		t.Fatal("unknown categories are not valid")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	profile, ok := Lookup("Senior iOS Developer")
+	if !ok || profile.Category != Mobile || profile.Label == "" || !slices.Contains(profile.Keywords, "Swift") {
+		t.Fatalf("Lookup = %+v, %v", profile, ok)
+	}
+	profile.Keywords[0] = "changed"
+	if again, _ := Lookup("Senior iOS Developer"); again.Keywords[0] == "changed" {
+		t.Fatal("Lookup should return a copy of the keywords")
+	}
+	for _, name := range Categories() {
+		for _, c := range categories {
+			if c.name == name && len(c.keywords) == 0 {
+				t.Errorf("%s has no keywords", name)
+			}
+		}
+	}
+	if _, ok := Lookup("Barista"); ok {
+		t.Fatal("an unknown role should have no profile")
+	}
+}
+
+func pad() string {
+	out := ""
+	for len(out) < titleWindow {
+		out += "Great benefits and a friendly team. "
+	}
+	return out
+}
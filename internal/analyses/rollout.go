@@ -0,0 +1,47 @@
+package analyses
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPromptVersion is used when neither the caller nor a rollout picks one.
+const DefaultPromptVersion = "v2_3"
+
+// PromptOutcome summarizes how one analysis went for prompt health tracking.
+type PromptOutcome struct {
+	Failed        bool
+	SchemaFailure bool
+	// Sanitized is set when the result only passed validation after the
+	// content-sanitization fallback rewrote it.
+	Sanitized  bool
+	DurationMs float64
+}
+
+// PromptRollout chooses prompt versions for new analyses and receives their outcomes.
+type PromptRollout interface {
+	// SelectPromptVersion returns the version for a new analysis identified by key,
+	// or "" to use the default.
+	SelectPromptVersion(ctx context.Context, key string) string
+	RecordOutcome(ctx context.Context, promptVersion string, outcome PromptOutcome)
+}
+
+func (s *Service) selectPromptVersion(ctx context.Context, requested, key string) string {
+	if requested != "" {
+		return requested
+	}
+	if s.Rollout != nil {
+		if version := s.Rollout.SelectPromptVersion(ctx, key); version != "" {
+			return version
+		}
+	}
+	return DefaultPromptVersion
+}
+
+func (s *Service) recordPromptOutcome(ctx context.Context, promptVersion string, outcome PromptOutcome, startedAt, completedAt *time.Time) {
+	if s.Rollout == nil || promptVersion == "" {
+		return
+	}
+	outcome.DurationMs = durationMs(startedAt, completedAt)
+	s.Rollout.RecordOutcome(ctx, promptVersion, outcome)
+}
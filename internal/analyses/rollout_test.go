@@ -0,0 +1,73 @@
+package analyses
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeRollout struct {
+	version  string
+	keys     []string
+	outcomes map[string][]PromptOutcome
+}
+
+func (f *fakeRollout) SelectPromptVersion(ctx context.Context, key string) string {
+	f.keys = append(f.keys, key)
+	return f.version
+}
+
+func (f *fakeRollout) RecordOutcome(ctx context.Context, promptVersion string, outcome PromptOutcome) {
+	if f.outcomes == nil {
+		f.outcomes = make(map[string][]PromptOutcome)
+	}
+	f.outcomes[promptVersion] = append(f.outcomes[promptVersion], outcome)
+}
+
+func TestRolloutSelectsVersionOnlyWhenUnspecified(t *testing.T) {
+	svc, _, _, docID := setupServiceWithDoc(t, staticLLMResponse{resp: "{}"})
+	svc.JobQueue = &stubQueue{}
+	rollout := &fakeRollout{version: "v2_2"}
+	svc.Rollout = rollout
+
+	got, _, err := svc.StartOrReuseWithOptions(context.Background(), docID, "user-1", "jd", "", ModeJobMatch, false, StartOptions{ForceNew: true})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if got.PromptVersion != "v2_2" || len(rollout.keys) != 1 || rollout.keys[0] != got.ID {
+		t.Fatalf("expected rollout to pick v2_2 keyed by analysis id, got %q keys=%v", got.PromptVersion, rollout.keys)
+	}
+
+	got, _, err = svc.StartOrReuseWithOptions(context.Background(), docID, "user-1", "jd", "v2", ModeJobMatch, false, StartOptions{ForceNew: true})
+	if err != nil {
+		t.Fatalf("start explicit: %v", err)
+	}
+	if got.PromptVersion != "v2" || len(rollout.keys) != 1 {
+		t.Fatalf("expected explicit version to bypass rollout, got %q", got.PromptVersion)
+	}
+}
+
+func TestRolloutReceivesSchemaFailureOutcome(t *testing.T) {
+	svc, repo, _, docID := setupServiceWithDoc(t, staticLLMResponse{resp: "{not-json"})
+	rollout := &fakeRollout{}
+	svc.Rollout = rollout
+
+	analysis := Analysis{
+		ID:             "analysis-rollout-fail",
+		DocumentID:     docID,
+		UserID:         "user-1",
+		JobDescription: "jd",
+		PromptVersion:  "v2_3",
+		Status:         StatusQueued,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := repo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	_ = svc.ProcessAnalysis(context.Background(), analysis.ID)
+
+	outcomes := rollout.outcomes["v2_3"]
+	if len(outcomes) != 1 || !outcomes[0].Failed || !outcomes[0].SchemaFailure {
+		t.Fatalf("expected one schema failure outcome, got %+v", rollout.outcomes)
+	}
+}
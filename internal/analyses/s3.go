@@ -1,48 +1,126 @@
 package analyses
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"resume-backend/internal/documents"
+	s3store "resume-backend/internal/shared/storage/object/s3"
 )
 
 const maxS3DocBytes int64 = 5 << 20
 
+const (
+	defaultS3Region          = "us-east-1"
+	defaultS3MaxIdleConns    = 32
+	defaultS3IdleConnTimeout = 90 * time.Second
+)
+
+// S3DocumentReader reads uploads and stores extracted text for documents kept in
+// the uploads bucket. It is long-lived and shared by every job.
+type S3DocumentReader interface {
+	GetObjectBytes(ctx context.Context, key string) ([]byte, error)
+	PutText(ctx context.Context, key string, text string) error
+}
+
+// S3DocumentOptions configures NewS3DocumentReader.
+type S3DocumentOptions struct {
+	Bucket string
+	Region string
+	// Endpoint overrides the regional S3 endpoint, e.g. for a VPC endpoint.
+	Endpoint string
+	// MaxIdleConnsPerHost bounds the keep-alive pool; size it to the worker
+	// concurrency so concurrent jobs do not open new connections.
+	MaxIdleConnsPerHost int
+}
+
+// S3DocumentOptionsFromEnv reads UPLOADS_S3_BUCKET (or S3_BUCKET),
+// UPLOADS_S3_REGION (or AWS_REGION), UPLOADS_S3_ENDPOINT and
+// RA_S3_MAX_IDLE_CONNS.
+func S3DocumentOptionsFromEnv() S3DocumentOptions {
+	opts := S3DocumentOptions{
+		Bucket:              firstEnv("UPLOADS_S3_BUCKET", "S3_BUCKET"),
+		Region:              firstEnv("UPLOADS_S3_REGION", "AWS_REGION"),
+		Endpoint:            firstEnv("UPLOADS_S3_ENDPOINT"),
+		MaxIdleConnsPerHost: defaultS3MaxIdleConns,
+	}
+	if n, err := strconv.Atoi(firstEnv("RA_S3_MAX_IDLE_CONNS")); err == nil && n > 0 {
+		opts.MaxIdleConnsPerHost = n
+	}
+	return opts
+}
+
 type s3DocClient struct {
 	client *s3.Client
 	bucket string
 }
 
-func newS3DocClient(ctx context.Context) (*s3DocClient, error) {
-	region := strings.TrimSpace(os.Getenv("AWS_REGION"))
-	if region == "" {
-		region = "us-east-1"
-	}
-	bucket := strings.TrimSpace(os.Getenv("UPLOADS_S3_BUCKET"))
-	if bucket == "" {
-		bucket = strings.TrimSpace(os.Getenv("S3_BUCKET"))
-	}
+var _ documents.ObjectWriter = (*s3DocClient)(nil)
+
+// NewS3DocumentReader resolves the AWS config once and returns a reader whose
+// HTTP transport keeps connections to the regional endpoint alive across jobs.
+func NewS3DocumentReader(ctx context.Context, opts S3DocumentOptions) (S3DocumentReader, error) {
+	bucket := strings.TrimSpace(opts.Bucket)
 	if bucket == "" {
 		return nil, fmt.Errorf("s3 bucket is required")
 	}
+	region := strings.TrimSpace(opts.Region)
+	if region == "" {
+		region = defaultS3Region
+	}
+	maxIdle := opts.MaxIdleConnsPerHost
+	if maxIdle <= 0 {
+		maxIdle = defaultS3MaxIdleConns
+	}
 
-	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		tr.MaxIdleConns = maxIdle
+		tr.MaxIdleConnsPerHost = maxIdle
+		tr.IdleConnTimeout = defaultS3IdleConnTimeout
+	})
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region), awsconfig.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("load aws config: %w", err)
 	}
 
+	clientOpts := s3store.ClientOptions()
+	if endpoint := strings.TrimSpace(opts.Endpoint); endpoint != "" {
+		clientOpts = append(clientOpts, func(o *s3.Options) { o.BaseEndpoint = aws.String(endpoint) })
+	}
 	return &s3DocClient{
-		client: s3.NewFromConfig(cfg),
+		client: s3.NewFromConfig(cfg, clientOpts...),
 		bucket: bucket,
 	}, nil
 }
 
+// s3Documents returns the injected reader, or builds one from the environment on
+// first use and keeps it for later jobs.
+func (s *Service) s3Documents(ctx context.Context) (S3DocumentReader, error) {
+	s.s3Mu.Lock()
+	defer s.s3Mu.Unlock()
+	if s.S3Docs != nil {
+		return s.S3Docs, nil
+	}
+	reader, err := NewS3DocumentReader(ctx, S3DocumentOptionsFromEnv())
+	if err != nil {
+		return nil, err
+	}
+	s.S3Docs = reader
+	return reader, nil
+}
+
 func (c *s3DocClient) GetObjectBytes(ctx context.Context, key string) ([]byte, error) {
 	out, err := c.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(c.bucket),
@@ -80,3 +158,29 @@ func (c *s3DocClient) PutText(ctx context.Context, key string, text string) erro
 	}
 	return nil
 }
+
+// PutObject stores data under key, so legacy local uploads can be copied into
+// the uploads bucket.
+func (c *s3DocClient) PutObject(ctx context.Context, key, contentType string, data []byte) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if _, err := c.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("s3 put object key=%s: %w", key, err)
+	}
+	return nil
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
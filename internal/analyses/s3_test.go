@@ -0,0 +1,137 @@
+package analyses
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeS3 serves path-style GetObject and PutObject requests from memory and counts
+// the TCP connections clients open.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	conns   atomic.Int64
+}
+
+func newFakeS3(tb testing.TB) (*fakeS3, *httptest.Server) {
+	tb.Helper()
+	fake := &fakeS3{objects: map[string][]byte{"/uploads/resume.pdf": []byte("resume bytes")}}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(fake.serve))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			fake.conns.Add(1)
+		}
+	}
+	server.Start()
+	tb.Cleanup(server.Close)
+
+	tb.Setenv("AWS_ACCESS_KEY_ID", "test")
+	tb.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	tb.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	tb.Setenv("RA_S3_FORCE_PATH_STYLE", "true")
+	tb.Setenv("AWS_RESPONSE_CHECKSUM_VALIDATION", "when_required")
+	return fake, server
+}
+
+func (f *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func testS3Options(server *httptest.Server) S3DocumentOptions {
+	return S3DocumentOptions{Bucket: "uploads", Region: "eu-west-1", Endpoint: server.URL}
+}
+
+func TestS3DocumentReaderReusesConnections(t *testing.T) {
+	fake, server := newFakeS3(t)
+	ctx := context.Background()
+	reader, err := NewS3DocumentReader(ctx, testS3Options(server))
+	if err != nil {
+		t.Fatalf("new reader: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		body, err := reader.GetObjectBytes(ctx, "resume.pdf")
+		if err != nil {
+			t.Fatalf("get object: %v", err)
+		}
+		if string(body) != "resume bytes" {
+			t.Fatalf("unexpected body %q", body)
+		}
+	}
+	if err := reader.PutText(ctx, "resume.pdf.extracted.txt", "text"); err != nil {
+		t.Fatalf("put text: %v", err)
+	}
+	if got := fake.conns.Load(); got != 1 {
+		t.Fatalf("expected one reused connection, got %d", got)
+	}
+}
+
+func TestServiceBuildsS3ReaderOnce(t *testing.T) {
+	_, server := newFakeS3(t)
+	t.Setenv("UPLOADS_S3_BUCKET", "uploads")
+	t.Setenv("UPLOADS_S3_ENDPOINT", server.URL)
+	svc := &Service{}
+
+	first, err := svc.s3Documents(context.Background())
+	if err != nil {
+		t.Fatalf("s3 documents: %v", err)
+	}
+	second, _ := svc.s3Documents(context.Background())
+	if first != second {
+		t.Fatalf("expected the reader to be reused")
+	}
+}
+
+// The two benchmarks below compare one job's S3 read when the client is built per
+// job, as ProcessAnalysis used to, against a shared reader:
+//
+//	go test ./internal/analyses -run '^$' -bench S3DocumentRead
+func BenchmarkS3DocumentReadPerJobClient(b *testing.B) {
+	_, server := newFakeS3(b)
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		reader, err := NewS3DocumentReader(ctx, testS3Options(server))
+		if err != nil {
+			b.Fatalf("new reader: %v", err)
+		}
+		if _, err := reader.GetObjectBytes(ctx, "resume.pdf"); err != nil {
+			b.Fatalf("get object: %v", err)
+		}
+	}
+}
+
+func BenchmarkS3DocumentReadSharedClient(b *testing.B) {
+	_, server := newFakeS3(b)
+	ctx := context.Background()
+	reader, err := NewS3DocumentReader(ctx, testS3Options(server))
+	if err != nil {
+		b.Fatalf("new reader: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := reader.GetObjectBytes(ctx, "resume.pdf"); err != nil {
+			b.Fatalf("get object: %v", err)
+		}
+	}
+}
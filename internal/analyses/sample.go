@@ -0,0 +1,122 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// SampleProvider is the provider recorded on analyses copied from recorded
+// output rather than generated. Aggregates over completed analyses leave
+// these out, and they carry no role category.
+const SampleProvider = "sample"
+
+// SampleAnalysis is recorded LLM output stored as an analysis without calling
+// the LLM, such as the onboarding sample.
+type SampleAnalysis struct {
+	Mode           AnalysisMode
+	PromptVersion  string
+	JobDescription string
+	// Output is the recorded LLM output, as the pipeline's Generate step
+	// returned it.
+	Output json.RawMessage
+}
+
+// CreateSampleAnalysis stores sample as a completed analysis of the user's
+// document. The output is validated and normalized as live output is, but no
+// usage is consumed and nothing is queued. An existing analysis of the document
+// with the same inputs is returned instead, with created false.
+func (s *Service) CreateSampleAnalysis(ctx context.Context, userID, documentID string, sample SampleAnalysis) (Analysis, bool, error) {
+	if documentID == "" || userID == "" {
+		return Analysis{}, false, errors.New("documentID and userID are required")
+	}
+	mode := sample.Mode
+	if mode == "" {
+		mode = ModeJobMatch
+	}
+	pipeline, ok := s.pipelines().Lookup(mode, sample.PromptVersion)
+	if !ok {
+		return Analysis{}, false, s.checkPipeline(mode, sample.PromptVersion)
+	}
+	if pipeline.Validate != nil {
+		if err := pipeline.Validate(sample.Output); err != nil {
+			return Analysis{}, false, fmt.Errorf("sample output invalid: %w", err)
+		}
+	}
+
+	doc, err := s.ExtractDocument(ctx, userID, documentID)
+	if err != nil {
+		return Analysis{}, false, err
+	}
+	ctx = ctxmeta.WithDataOwner(ctx, userID)
+	extracted, err := s.resumeText(ctx, doc)
+	if err != nil {
+		return Analysis{}, false, err
+	}
+
+	analysis := Analysis{
+		ID:                 uuid.NewString(),
+		DocumentID:         documentID,
+		UserID:             userID,
+		JobDescription:     sample.JobDescription,
+		JobDescriptionHash: HashJobDescription(sample.JobDescription),
+		PromptVersion:      sample.PromptVersion,
+		Mode:               mode,
+		AnalysisVersion:    normalizeAnalysisVersion(s.AnalysisVersion),
+		Provider:           SampleProvider,
+		Model:              "recorded",
+		Status:             StatusCompleted,
+		AnalysisRaw:        buildRawPayload(sample.Output),
+		CreatedAt:          time.Now().UTC(),
+	}
+	run := &PipelineRun{
+		Analysis:   analysis,
+		ResumeText: extracted,
+		Input: llm.AnalyzeInput{
+			ResumeText:     extracted,
+			JobDescription: sample.JobDescription,
+			PromptVersion:  sample.PromptVersion,
+		},
+		svc: s,
+	}
+	if pipeline.BuildInput != nil {
+		pipeline.BuildInput(run)
+	}
+	normalize := pipeline.Normalize
+	if normalize == nil {
+		normalize = normalizeAnalysisResult
+	}
+	result, err := normalize(sample.Output, analysis)
+	if err != nil {
+		return Analysis{}, false, fmt.Errorf("sample output invalid: %w", err)
+	}
+	attributeEvidence(result, documentID, extracted, nil)
+	withQuantification(result, run.Quantification)
+	withProvenance(result, newProvenance(analysis, doc, extracted, ""))
+	analysis.Result = result
+
+	stored, created, err := s.Repo.GetOrCreateForDocument(ctx, analysis, true, nil)
+	if err != nil || !created {
+		return stored, false, err
+	}
+	completedAt := time.Now().UTC()
+	if err := s.Repo.UpdateAnalysisResult(ctx, analysis.ID, result, &completedAt); err != nil {
+		return Analysis{}, false, fmt.Errorf("set analysis result failed: %w", err)
+	}
+	telemetry.InfoContext(ctx, "analysis.sample_created", map[string]any{
+		"user_id":        userID,
+		"document_id":    documentID,
+		"analysis_id":    analysis.ID,
+		"prompt_version": analysis.PromptVersion,
+	})
+	stored, err = s.Repo.GetByID(ctx, analysis.ID)
+	return stored, err == nil, err
+}
@@ -0,0 +1,79 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func TestCreateSampleAnalysisStoresCompletedResultOnce(t *testing.T) {
+	ctx := context.Background()
+	store := local.New(t.TempDir())
+	storageKey, _, _, err := store.Save(ctx, "guest-1", "resume.docx", bytes.NewReader(minimalDOCX(t, "Cut checkout latency by 40% across 3 regions.")))
+	if err != nil {
+		t.Fatalf("save resume: %v", err)
+	}
+	docRepo := documents.NewMemoryRepo()
+	if err := docRepo.Create(ctx, documents.Document{
+		ID:         "doc-1",
+		UserID:     "guest-1",
+		FileName:   "resume.docx",
+		MimeType:   "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		StorageKey: storageKey,
+		CreatedAt:  time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+	repo := NewMemoryRepo()
+	// No LLM, queue or usage: the sample must not need any of them.
+	svc := &Service{Repo: repo, DocRepo: docRepo, Store: store}
+	sample := SampleAnalysis{
+		Mode:           ModeJobMatch,
+		PromptVersion:  "v2_3",
+		JobDescription: "Senior Backend Engineer building payment services in Go.",
+		Output:         loadFixture(t, "testdata/v2_3_good.json"),
+	}
+
+	analysis, created, err := svc.CreateSampleAnalysis(ctx, "guest-1", "doc-1", sample)
+	if err != nil {
+		t.Fatalf("create sample: %v", err)
+	}
+	if !created || analysis.Status != StatusCompleted || analysis.CompletedAt == nil || analysis.Provider != SampleProvider {
+		t.Fatalf("expected a completed sample analysis, got %+v", analysis)
+	}
+	if _, ok := FinalScore(analysis); !ok {
+		t.Fatalf("expected the sample result to carry a score")
+	}
+	if meta, _ := analysis.Result["meta"].(map[string]any); meta["provenance"] == nil {
+		t.Fatalf("expected provenance on the sample result, got %v", analysis.Result["meta"])
+	}
+	if role := RoleCategory(analysis.Result); role != "" {
+		t.Fatalf("expected no role category on a sample, got %q", role)
+	}
+	if completed, err := repo.ListCompletedSince(ctx, time.Time{}, 0); err != nil || len(completed) != 0 {
+		t.Fatalf("expected samples to be left out of aggregates, got %d (err %v)", len(completed), err)
+	}
+
+	again, created, err := svc.CreateSampleAnalysis(ctx, "guest-1", "doc-1", sample)
+	if err != nil {
+		t.Fatalf("create sample again: %v", err)
+	}
+	if created || again.ID != analysis.ID {
+		t.Fatalf("expected the existing sample to be reused, got created=%v id=%s", created, again.ID)
+	}
+}
+
+func TestCreateSampleAnalysisRejectsInvalidOutput(t *testing.T) {
+	svc := &Service{Repo: NewMemoryRepo()}
+	_, _, err := svc.CreateSampleAnalysis(context.Background(), "guest-1", "doc-1", SampleAnalysis{
+		PromptVersion: "v2_3",
+		Output:        loadFixture(t, "testdata/v2_3_bad_evidence.json"),
+	})
+	if err == nil {
+		t.Fatal("expected invalid sample output to be rejected")
+	}
+}
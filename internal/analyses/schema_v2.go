@@ -26,6 +26,13 @@ type MetaV2 struct {
 	Limitations            []string `json:"limitations"`
 	Mode                   string   `json:"mode,omitempty"`
 	PrimaryScoreType       string   `json:"primaryScoreType,omitempty"`
+	// ScoringVersion is the scoring config ats.scoreExplanation follows. It is
+	// set by normalization, never by the model.
+	ScoringVersion string `json:"scoringVersion,omitempty"`
+	// ScoreReconciliation is set by normalization, never by the model.
+	ScoreReconciliation *ScoreReconciliationV1 `json:"scoreReconciliation,omitempty"`
+	// OverflowCounts is set by normalization when a list exceeded ResultLimits.
+	OverflowCounts *OverflowCountsV1 `json:"overflowCounts,omitempty"`
 }
 
 type ATSV2 struct {
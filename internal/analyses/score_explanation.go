@@ -5,11 +5,29 @@ import (
 	"fmt"
 	"math"
 	"strings"
+
+	"resume-backend/internal/analyses/scoring"
 )
 
 // ScoreExplanationV1 explains how the ATS score is calculated.
 type ScoreExplanationV1 struct {
-	Components []ScoreComponentV1 `json:"components"`
+	// ScoringVersion is the scoring config the components follow. It is set
+	// when the model output is validated, never by the model, and moves to
+	// meta.scoringVersion on normalization.
+	ScoringVersion string             `json:"scoringVersion,omitempty"`
+	Components     []ScoreComponentV1 `json:"components"`
+}
+
+// legacyScoringVersion is the scoring config of results stored before the
+// version was recorded with them; scoring_v1 was the only config then.
+const legacyScoringVersion = "scoring_v1"
+
+// scoringVersion returns the recorded scoring version.
+func (e ScoreExplanationV1) scoringVersion() string {
+	if v := strings.TrimSpace(e.ScoringVersion); v != "" {
+		return v
+	}
+	return legacyScoringVersion
 }
 
 // ScoreComponentV1 represents a weighted score component.
@@ -23,29 +41,42 @@ type ScoreComponentV1 struct {
 	Dragged     []string `json:"dragged"`
 }
 
-var scoreExplanationKeys = map[string]string{
-	"atsReadability":      "ATS Readability",
-	"skillMatch":          "Skill Match",
-	"experienceRelevance": "Experience Relevance",
-	"resumeStructure":     "Resume Structure",
+// ScoreReconcileThreshold is how many points ats.score may differ from the
+// weighted component scores before normalization recomputes it.
+const ScoreReconcileThreshold = 5.0
+
+// ScoreReconciliationV1 is set in meta when ats.score was replaced by the
+// weighted component scores.
+type ScoreReconciliationV1 struct {
+	OriginalScore  float64 `json:"originalScore"`
+	ComponentScore float64 `json:"componentScore"`
+	Drift          float64 `json:"drift"`
 }
 
+// validateScoreExplanationV1 checks the components against the scoring config
+// they were produced for: one per configured key, each with the configured
+// weight.
 func validateScoreExplanationV1(e *ScoreExplanationV1) error {
 	if e == nil {
 		return errors.New("ats.scoreExplanation is required")
 	}
-	if len(e.Components) != len(scoreExplanationKeys) {
-		return fmt.Errorf("ats.scoreExplanation.components must contain %d items", len(scoreExplanationKeys))
+	cfg, ok := scoring.Lookup(e.scoringVersion())
+	if !ok {
+		return fmt.Errorf("ats.scoreExplanation.scoringVersion %q is unknown", e.scoringVersion())
 	}
-	seen := make(map[string]bool, len(scoreExplanationKeys))
+	if len(e.Components) != len(cfg.Components) {
+		return fmt.Errorf("ats.scoreExplanation.components must contain %d items", len(cfg.Components))
+	}
+	seen := make(map[string]bool, len(cfg.Components))
 	totalWeight := 0.0
 	for i, c := range e.Components {
 		key := strings.TrimSpace(c.Key)
 		if key == "" {
 			return fmt.Errorf("ats.scoreExplanation.components[%d].key is required", i)
 		}
-		if _, ok := scoreExplanationKeys[key]; !ok {
-			return fmt.Errorf("ats.scoreExplanation.components[%d].key must be one of: atsReadability, skillMatch, experienceRelevance, resumeStructure", i)
+		component, ok := cfg.Component(key)
+		if !ok {
+			return fmt.Errorf("ats.scoreExplanation.components[%d].key must be one of: %s", i, strings.Join(cfg.Keys(), ", "))
 		}
 		if seen[key] {
 			return fmt.Errorf("ats.scoreExplanation.components[%d].key must be unique", i)
@@ -66,6 +97,9 @@ func validateScoreExplanationV1(e *ScoreExplanationV1) error {
 		if !isInteger(c.Weight) {
 			return fmt.Errorf("ats.scoreExplanation.components[%d].weight must be an integer", i)
 		}
+		if int(c.Weight) != component.Weight {
+			return fmt.Errorf("ats.scoreExplanation.components[%d].weight for %s must be %d (%s)", i, key, component.Weight, cfg.Version)
+		}
 		totalWeight += c.Weight
 		if strings.TrimSpace(c.Explanation) == "" {
 			return fmt.Errorf("ats.scoreExplanation.components[%d].explanation is required", i)
@@ -87,7 +121,7 @@ func validateScoreExplanationV1(e *ScoreExplanationV1) error {
 			}
 		}
 	}
-	if len(seen) != len(scoreExplanationKeys) {
+	if len(seen) != len(cfg.Components) {
 		return errors.New("ats.scoreExplanation.components must include all components")
 	}
 	if math.Abs(totalWeight-100) > 0.000001 {
@@ -97,6 +131,7 @@ func validateScoreExplanationV1(e *ScoreExplanationV1) error {
 }
 
 func normalizeScoreExplanation(value ScoreExplanationV1) ScoreExplanationV1 {
+	value.ScoringVersion = ""
 	if value.Components == nil {
 		value.Components = []ScoreComponentV1{}
 	}
@@ -110,3 +145,47 @@ func normalizeScoreExplanation(value ScoreExplanationV1) ScoreExplanationV1 {
 	}
 	return value
 }
+
+// weightedComponentScore is the sum of component scores weighted by their
+// weights. It reports false when there are no components or the weights do not
+// total 100, since the math cannot be trusted then.
+func weightedComponentScore(e ScoreExplanationV1) (float64, bool) {
+	if len(e.Components) == 0 {
+		return 0, false
+	}
+	totalWeight := 0.0
+	weighted := 0.0
+	for _, c := range e.Components {
+		totalWeight += c.Weight
+		weighted += c.Score * c.Weight
+	}
+	if math.Abs(totalWeight-100) > 0.000001 {
+		return 0, false
+	}
+	return weighted / 100, true
+}
+
+// reconcileScoreExplanation recomputes ats.score from the explanation
+// components when the two drift apart by more than ScoreReconcileThreshold,
+// so the breakdown shown next to the score always adds up. The original score
+// is kept in meta.scoreReconciliation.
+func reconcileScoreExplanation(out *NormalizedAnalysisResult) {
+	if out == nil {
+		return
+	}
+	componentScore, ok := weightedComponentScore(out.ATS.ScoreExplanation)
+	if !ok {
+		return
+	}
+	componentScore = clampScore(math.Round(componentScore))
+	drift := math.Abs(out.ATS.Score - componentScore)
+	if drift <= ScoreReconcileThreshold {
+		return
+	}
+	out.Meta.ScoreReconciliation = &ScoreReconciliationV1{
+		OriginalScore:  out.ATS.Score,
+		ComponentScore: componentScore,
+		Drift:          drift,
+	}
+	out.ATS.Score = componentScore
+}
@@ -0,0 +1,146 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses/scoring"
+	"resume-backend/internal/llm"
+)
+
+func TestNormalizeReconcilesDriftedATSScore(t *testing.T) {
+	var fixture map[string]any
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &fixture); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	analysis := Analysis{PromptVersion: "v2_3", Model: "gpt-test", Mode: ModeATS}
+
+	// Components weigh in at 77.7; a score of 80 is within the threshold.
+	out, err := normalizeToFinal(mustMarshal(t, fixture), analysis)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if out.ATS.Score != 80 || out.Meta.ScoreReconciliation != nil {
+		t.Fatalf("expected a consistent score to be kept, got %v %+v", out.ATS.Score, out.Meta.ScoreReconciliation)
+	}
+
+	fixture["ats"].(map[string]any)["score"] = 95
+	out, err = normalizeToFinal(mustMarshal(t, fixture), analysis)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if out.ATS.Score != 78 || out.FinalScore != 78 {
+		t.Fatalf("expected ats and final score recomputed to 78, got ats=%v final=%v", out.ATS.Score, out.FinalScore)
+	}
+	rec := out.Meta.ScoreReconciliation
+	if rec == nil || rec.OriginalScore != 95 || rec.ComponentScore != 78 || rec.Drift != 17 {
+		t.Fatalf("expected the reconciliation flagged in meta, got %+v", rec)
+	}
+}
+
+func TestValidateNormalizedRejectsUnreconciledScore(t *testing.T) {
+	out := NormalizedAnalysisResult{
+		Meta:            MetaV2{PromptVersion: "v2_3", Model: "gpt-test"},
+		Summary:         SummaryV1{OverallAssessment: "ok"},
+		Recommendations: []Recommendation{},
+		ATS: NormalizedATS{Score: 40, ScoreExplanation: ScoreExplanationV1{Components: []ScoreComponentV1{
+			{Key: "atsReadability", Score: 90, Weight: 50},
+			{Key: "skillMatch", Score: 90, Weight: 50},
+		}}},
+	}
+	if err := validateNormalized(out); err == nil {
+		t.Fatalf("expected a score far from its components to be rejected")
+	}
+	out.ATS.Score = 90
+	if err := validateNormalized(out); err != nil {
+		t.Fatalf("expected a reconciled score to pass: %v", err)
+	}
+	out.ATS.ScoreExplanation.Components[1].Weight = 40
+	if err := validateNormalized(out); err == nil {
+		t.Fatalf("expected weights not totaling 100 to be rejected")
+	}
+}
+
+func mustMarshal(t *testing.T, v any) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
+
+func TestValidateRejectsWeightsThatDifferFromTheScoringConfig(t *testing.T) {
+	var out AnalysisResultV2_3
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &out); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	// Still totals 100, but not with the configured split.
+	out.ATS.ScoreExplanation.Components[0].Weight = 30
+	out.ATS.ScoreExplanation.Components[3].Weight = 10
+
+	err := out.Validate()
+	if err == nil || !strings.Contains(err.Error(), "must be 25 (scoring_v1)") {
+		t.Fatalf("expected the weight to be rejected, got %v", err)
+	}
+}
+
+func TestValidateUsesTheRecordedScoringVersion(t *testing.T) {
+	var out AnalysisResultV2_3
+	if err := json.Unmarshal(loadFixture(t, "testdata/v2_3_good.json"), &out); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	out.ATS.ScoreExplanation.ScoringVersion = "scoring_v0"
+	if err := out.Validate(); err == nil || !strings.Contains(err.Error(), `scoringVersion "scoring_v0" is unknown`) {
+		t.Fatalf("expected the unknown version to be rejected, got %v", err)
+	}
+
+	// Output validated fresh records the version, and normalization moves it
+	// to meta.
+	client := &repairRecordingLLM{first: loadFixture(t, "testdata/v2_3_good.json")}
+	raw, err := ValidateV2_3WithRetry(context.Background(), client, llm.AnalyzeInput{ResumeText: "Go engineer"})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := json.Unmarshal(raw, &out); err != nil || out.ATS.ScoreExplanation.ScoringVersion != scoring.CurrentVersion {
+		t.Fatalf("expected %s recorded, got %q (err %v)", scoring.CurrentVersion, out.ATS.ScoreExplanation.ScoringVersion, err)
+	}
+	result, err := normalizeToFinal(raw, Analysis{PromptVersion: "v2_3", Model: "test-model"})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if result.Meta.ScoringVersion != scoring.CurrentVersion || result.ATS.ScoreExplanation.ScoringVersion != "" {
+		t.Fatalf("expected the version in meta only, got meta %q explanation %q", result.Meta.ScoringVersion, result.ATS.ScoreExplanation.ScoringVersion)
+	}
+}
+
+func TestScoringConfigEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/meta/scoring", ScoringConfig)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/meta/scoring", nil))
+	if resp.Code != http.StatusOK || resp.Header().Get("ETag") == "" {
+		t.Fatalf("expected a cacheable 200, got %d", resp.Code)
+	}
+	var got scoring.Config
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Version != scoring.CurrentVersion || len(got.Components) != 4 || got.Validate() != nil {
+		t.Fatalf("unexpected config: %+v", got)
+	}
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/meta/scoring?version=scoring_v0", nil))
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown version, got %d", resp.Code)
+	}
+}
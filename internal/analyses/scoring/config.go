@@ -0,0 +1,137 @@
+// Package scoring defines the ATS score components and their weights as
+// versioned data. The analysis prompt, the validation of model output and the
+// frontend's score breakdown all read the same config, so they cannot drift.
+package scoring
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//go:embed configs/*.json
+var configFiles embed.FS
+
+// CurrentVersion is the config version new analyses are scored with.
+const CurrentVersion = "scoring_v1"
+
+// Component is one weighted part of the ATS score.
+type Component struct {
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+	Weight      int    `json:"weight"`
+	Description string `json:"description"`
+}
+
+// Config is a versioned set of score components. Weights total 100.
+type Config struct {
+	Version    string      `json:"version"`
+	Components []Component `json:"components"`
+}
+
+var configs = mustLoad()
+
+// Current returns the config new analyses are scored with.
+func Current() Config {
+	cfg, _ := Lookup(CurrentVersion)
+	return cfg
+}
+
+// Lookup returns the config with the given version.
+func Lookup(version string) (Config, bool) {
+	cfg, ok := configs[version]
+	if !ok {
+		return Config{}, false
+	}
+	cfg.Components = append([]Component(nil), cfg.Components...)
+	return cfg, true
+}
+
+// Component returns the component with the given key.
+func (c Config) Component(key string) (Component, bool) {
+	for _, component := range c.Components {
+		if component.Key == key {
+			return component, true
+		}
+	}
+	return Component{}, false
+}
+
+// Keys lists the component keys in config order.
+func (c Config) Keys() []string {
+	keys := make([]string, len(c.Components))
+	for i, component := range c.Components {
+		keys[i] = component.Key
+	}
+	return keys
+}
+
+// PromptRules renders the components as prompt instructions, so the model is
+// told the exact keys, labels and weights the output is validated against.
+func (c Config) PromptRules() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "- Provide %d components, one per key, using exactly these labels and weights (%s):", len(c.Components), c.Version)
+	for _, component := range c.Components {
+		fmt.Fprintf(&b, "\n  - key=%s label=%q weight=%d: %s", component.Key, component.Label, component.Weight, component.Description)
+	}
+	b.WriteString("\n- Do not change the weights; they total 100.")
+	return b.String()
+}
+
+// Validate checks that the config has components with unique keys, labels
+// and weights totalling 100.
+func (c Config) Validate() error {
+	if strings.TrimSpace(c.Version) == "" {
+		return errors.New("version is required")
+	}
+	if len(c.Components) == 0 {
+		return errors.New("components are required")
+	}
+	seen := make(map[string]bool, len(c.Components))
+	total := 0
+	for i, component := range c.Components {
+		if strings.TrimSpace(component.Key) == "" || strings.TrimSpace(component.Label) == "" {
+			return fmt.Errorf("components[%d] needs a key and label", i)
+		}
+		if seen[component.Key] {
+			return fmt.Errorf("components[%d].key %q is duplicated", i, component.Key)
+		}
+		seen[component.Key] = true
+		if component.Weight <= 0 || component.Weight > 100 {
+			return fmt.Errorf("components[%d].weight must be between 1 and 100", i)
+		}
+		total += component.Weight
+	}
+	if total != 100 {
+		return fmt.Errorf("weights must total 100, got %d", total)
+	}
+	return nil
+}
+
+func mustLoad() map[string]Config {
+	entries, err := configFiles.ReadDir("configs")
+	if err != nil {
+		panic(fmt.Sprintf("scoring: read configs: %v", err))
+	}
+	out := make(map[string]Config, len(entries))
+	for _, entry := range entries {
+		raw, err := configFiles.ReadFile("configs/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("scoring: read %s: %v", entry.Name(), err))
+		}
+		var cfg Config
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			panic(fmt.Sprintf("scoring: decode %s: %v", entry.Name(), err))
+		}
+		if err := cfg.Validate(); err != nil {
+			panic(fmt.Sprintf("scoring: %s: %v", entry.Name(), err))
+		}
+		out[cfg.Version] = cfg
+	}
+	if _, ok := out[CurrentVersion]; !ok {
+		panic("scoring: no config for " + CurrentVersion)
+	}
+	return out
+}
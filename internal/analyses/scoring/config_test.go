@@ -0,0 +1,55 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCurrentConfigIsValid(t *testing.T) {
+	cfg := Current()
+	if cfg.Version != CurrentVersion {
+		t.Fatalf("version = %q", cfg.Version)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	want := map[string]int{"atsReadability": 25, "skillMatch": 30, "experienceRelevance": 30, "resumeStructure": 15}
+	for key, weight := range want {
+		component, ok := cfg.Component(key)
+		if !ok || component.Weight != weight {
+			t.Fatalf("component %s = %+v", key, component)
+		}
+	}
+
+	// Callers get their own copy of the components.
+	cfg.Components[0].Weight = 99
+	if Current().Components[0].Weight == 99 {
+		t.Fatal("Current returned the shared components")
+	}
+}
+
+func TestValidateRejectsBadConfigs(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"no version":    {Components: []Component{{Key: "a", Label: "A", Weight: 100}}},
+		"duplicate":     {Version: "v", Components: []Component{{Key: "a", Label: "A", Weight: 50}, {Key: "a", Label: "A", Weight: 50}}},
+		"bad total":     {Version: "v", Components: []Component{{Key: "a", Label: "A", Weight: 60}, {Key: "b", Label: "B", Weight: 30}}},
+		"zero weight":   {Version: "v", Components: []Component{{Key: "a", Label: "A", Weight: 100}, {Key: "b", Label: "B"}}},
+		"missing label": {Version: "v", Components: []Component{{Key: "a", Weight: 100}}},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPromptRulesListEveryComponent(t *testing.T) {
+	rules := Current().PromptRules()
+	for _, component := range Current().Components {
+		if !strings.Contains(rules, "key="+component.Key) || !strings.Contains(rules, component.Label) {
+			t.Fatalf("rules missing %s:\n%s", component.Key, rules)
+		}
+	}
+	if !strings.Contains(rules, "weight=25") || !strings.Contains(rules, CurrentVersion) {
+		t.Fatalf("rules missing weights or version:\n%s", rules)
+	}
+}
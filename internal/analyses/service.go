@@ -8,14 +8,19 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"resume-backend/internal/analyses/roles"
 	"resume-backend/internal/documents"
 	"resume-backend/internal/extract"
+	"resume-backend/internal/featureflags"
 	"resume-backend/internal/llm"
+	"resume-backend/internal/llmbudget"
 	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/ctxmeta"
 	"resume-backend/internal/shared/metrics"
 	"resume-backend/internal/shared/storage/object"
 	"resume-backend/internal/shared/telemetry"
@@ -40,6 +45,46 @@ type Service struct {
 	Provider        string
 	Model           string
 	AnalysisVersion string
+	// Rollout optionally picks the prompt version for analyses that do not request one.
+	Rollout PromptRollout
+	// Sunset moves new analyses off deprecated prompt versions and rejects
+	// blocked ones; nil applies no policy.
+	Sunset *PromptSunset
+	// ExtractQueue, when set, runs text extraction as a separate stage ahead of the
+	// analysis queue.
+	ExtractQueue queue.Client
+	// DeltaMinSimilarity is the percentage of unchanged lines, relative to the user's
+	// previous resume for the same job, above which only the changed sections are
+	// re-analyzed. Zero disables delta analysis.
+	DeltaMinSimilarity int
+	// Pipelines maps mode and prompt version to the pipeline that runs them; nil
+	// uses DefaultPipelines.
+	Pipelines *PipelineRegistry
+	// S3Docs reads documents stored with the s3 provider. When nil, one is built
+	// from the environment on first use.
+	S3Docs S3DocumentReader
+	// StorageFallback reads a document from the other store when its own
+	// provider does not have it, for documents moved between local storage and
+	// S3 while the storage_provider column still names the old store.
+	StorageFallback bool
+	// InputTokenLimit caps the resume and job description tokens sent to the
+	// LLM; the model's context window caps it further. Zero uses
+	// DefaultInputTokenLimit. Longer inputs are truncated with explicit markers.
+	InputTokenLimit int
+	// LearningPlan generates the optional skill gap learning plan of job-match
+	// results. When nil, requested plans are reported as unavailable.
+	LearningPlan PromptCompleter
+	// Flags turns on ensemble scoring and structured output per user; nil
+	// leaves every flag at its default.
+	Flags *featureflags.Service
+	// Budget defers or rejects new analyses once a daily LLM budget is spent and
+	// is charged with the tokens each analysis uses; nil disables budgets.
+	Budget *llmbudget.Service
+	// ExtractionLease is how long a caller may hold a document's extraction
+	// claim before others take over; zero uses DefaultExtractionLease.
+	ExtractionLease time.Duration
+
+	s3Mu sync.Mutex
 }
 
 // Create enqueues a new analysis and kicks off asynchronous completion.
@@ -47,12 +92,17 @@ func (s *Service) Create(ctx context.Context, documentID, userID, jobDescription
 	if documentID == "" || userID == "" {
 		return Analysis{}, errors.New("documentID and userID are required")
 	}
-	if promptVersion == "" {
-		promptVersion = "v2_3"
+	analysisID := uuid.NewString()
+	promptVersion, err := s.resolvePromptVersion(ctx, s.selectPromptVersion(ctx, promptVersion, analysisID))
+	if err != nil {
+		return Analysis{}, err
+	}
+	if err := s.checkPipeline(ModeJobMatch, promptVersion); err != nil {
+		return Analysis{}, err
 	}
 
 	if s.Usage != nil {
-		ok, _, err := s.Usage.CanConsume(ctx, userID, 1)
+		ok, _, err := s.Usage.CanConsume(ctx, userID, "", 1)
 		if err != nil {
 			return Analysis{}, err
 		}
@@ -60,19 +110,24 @@ func (s *Service) Create(ctx context.Context, documentID, userID, jobDescription
 			return Analysis{}, usage.ErrLimitReached
 		}
 	}
+	status, err := s.admitBudget(ctx, "")
+	if err != nil {
+		return Analysis{}, err
+	}
 
 	analysis := Analysis{
-		ID:              uuid.NewString(),
-		DocumentID:      documentID,
-		UserID:          userID,
-		JobDescription:  jobDescription,
-		PromptVersion:   promptVersion,
-		Mode:            ModeJobMatch,
-		AnalysisVersion: normalizeAnalysisVersion(s.AnalysisVersion),
-		Provider:        normalizeProvider(s.Provider),
-		Model:           s.Model,
-		Status:          StatusQueued,
-		CreatedAt:       time.Now().UTC(),
+		ID:                 analysisID,
+		DocumentID:         documentID,
+		UserID:             userID,
+		JobDescription:     jobDescription,
+		JobDescriptionHash: HashJobDescription(jobDescription),
+		PromptVersion:      promptVersion,
+		Mode:               ModeJobMatch,
+		AnalysisVersion:    normalizeAnalysisVersion(s.AnalysisVersion),
+		Provider:           normalizeProvider(s.Provider),
+		Model:              s.Model,
+		Status:             status,
+		CreatedAt:          time.Now().UTC(),
 	}
 
 	if err := s.Repo.Create(ctx, analysis); err != nil {
@@ -80,20 +135,21 @@ func (s *Service) Create(ctx context.Context, documentID, userID, jobDescription
 	}
 
 	if s.Usage != nil {
-		if _, err := s.Usage.Consume(ctx, userID, 1); err != nil {
+		if _, err := s.Usage.Consume(ctx, userID, "", 1); err != nil {
 			return Analysis{}, err
 		}
 	}
+	if analysis.Status == StatusBudgetDeferred {
+		logBudgetDeferred(ctx, analysis)
+		return analysis, nil
+	}
 
 	if s.JobQueue == nil {
 		return Analysis{}, ErrJobQueueNotConfigured
 	}
-	if err := s.JobQueue.Send(ctx, queue.Message{
-		AnalysisID: analysis.ID,
-		RequestID:  requestIDFromContext(ctx),
-		EnqueuedAt: time.Now().UTC().Format(time.RFC3339Nano),
-		Version:    1,
-	}); err != nil {
+	if err := s.enqueue(ctx, analysis); err != nil {
+		err = fmt.Errorf("enqueue analysis: %w", err)
+		s.failAnalysis(ctx, analysis.ID, userID, documentID, err, nil)
 		return Analysis{}, err
 	}
 
@@ -102,34 +158,89 @@ func (s *Service) Create(ctx context.Context, documentID, userID, jobDescription
 
 // StartOrReuse enqueues a new analysis or reuses an existing one for idempotent requests.
 func (s *Service) StartOrReuse(ctx context.Context, documentID, userID, jobDescription, promptVersion string, mode AnalysisMode, allowRetry bool) (Analysis, bool, error) {
+	return s.StartOrReuseWithOptions(ctx, documentID, userID, jobDescription, promptVersion, mode, allowRetry, StartOptions{})
+}
+
+// StartOptions carries optional inputs for StartOrReuseWithOptions.
+type StartOptions struct {
+	// SupportingDocuments are attached to a newly created analysis.
+	SupportingDocuments []SupportingDocument
+	// ForceNew skips idempotent reuse and always creates a new analysis.
+	ForceNew bool
+	// OrgID charges a new analysis to the organization's pooled quota instead of the user's.
+	OrgID string
+	// LearningPlan adds a skill gap learning plan to a job-match result. The
+	// user's or organization's plan must include usage.FeatureLearningPlan.
+	LearningPlan bool
+	// TargetRole is the role the user is aiming for. It is part of the reuse
+	// key, so the same resume analyzed for another role is analyzed again.
+	TargetRole string
+}
+
+// StartOrReuseWithOptions behaves like StartOrReuse with supporting documents and forced creation.
+func (s *Service) StartOrReuseWithOptions(ctx context.Context, documentID, userID, jobDescription, promptVersion string, mode AnalysisMode, allowRetry bool, opts StartOptions) (Analysis, bool, error) {
 	if documentID == "" || userID == "" {
 		return Analysis{}, false, errors.New("documentID and userID are required")
 	}
-	if promptVersion == "" {
-		promptVersion = "v2_3"
-	}
 	if mode == "" {
 		mode = ModeJobMatch
 	}
+	analysisID := uuid.NewString()
+	promptVersion, err := s.resolvePromptVersion(ctx, s.selectPromptVersion(ctx, promptVersion, analysisID))
+	if err != nil {
+		return Analysis{}, false, err
+	}
+	if err := s.checkPipeline(mode, promptVersion); err != nil {
+		return Analysis{}, false, err
+	}
+	if opts.LearningPlan {
+		if mode != ModeJobMatch {
+			return Analysis{}, false, ErrLearningPlanRequiresJobMatch
+		}
+		if s.Usage != nil {
+			ok, err := s.Usage.HasFeature(ctx, userID, opts.OrgID, usage.FeatureLearningPlan)
+			if err != nil {
+				return Analysis{}, false, err
+			}
+			if !ok {
+				return Analysis{}, false, usage.ErrFeatureNotEntitled
+			}
+		}
+	}
 
+	// Only a newly created analysis is deferred or rejected for budget; reused
+	// ones are returned as they are.
+	status, budgetErr := s.admitBudget(ctx, opts.OrgID)
+	if budgetErr != nil {
+		status = StatusQueued
+	}
 	analysis := Analysis{
-		ID:              uuid.NewString(),
-		DocumentID:      documentID,
-		UserID:          userID,
-		JobDescription:  jobDescription,
-		PromptVersion:   promptVersion,
-		Mode:            mode,
-		AnalysisVersion: normalizeAnalysisVersion(s.AnalysisVersion),
-		Provider:        normalizeProvider(s.Provider),
-		Model:           s.Model,
-		Status:          StatusQueued,
-		CreatedAt:       time.Now().UTC(),
+		ID:                 analysisID,
+		DocumentID:         documentID,
+		UserID:             userID,
+		OrgID:              opts.OrgID,
+		JobDescription:     jobDescription,
+		JobDescriptionHash: HashJobDescription(jobDescription),
+		PromptVersion:      promptVersion,
+		Mode:               mode,
+		AnalysisVersion:    normalizeAnalysisVersion(s.AnalysisVersion),
+		Provider:           normalizeProvider(s.Provider),
+		Model:              s.Model,
+		Status:             status,
+		CreatedAt:          time.Now().UTC(),
+
+		SupportingDocuments: opts.SupportingDocuments,
+		LearningPlan:        opts.LearningPlan,
+		TargetRole:          NormalizeTargetRole(opts.TargetRole),
 	}
 
 	var allowCreate func() error
-	if s.Usage != nil {
+	if s.Usage != nil || budgetErr != nil {
 		allowCreate = func() error {
-			ok, _, err := s.Usage.CanConsume(ctx, userID, 1)
+			if budgetErr != nil {
+				return budgetErr
+			}
+			ok, _, err := s.Usage.CanConsume(ctx, userID, opts.OrgID, 1)
 			if err != nil {
 				return err
 			}
@@ -140,25 +251,43 @@ func (s *Service) StartOrReuse(ctx context.Context, documentID, userID, jobDescr
 		}
 	}
 
-	createdAnalysis, created, err := s.Repo.GetOrCreateForDocument(ctx, analysis, allowRetry, allowCreate)
-	if err != nil {
-		return createdAnalysis, false, err
+	var (
+		createdAnalysis Analysis
+		created         bool
+	)
+	if opts.ForceNew {
+		if allowCreate != nil {
+			if err := allowCreate(); err != nil {
+				return Analysis{}, false, err
+			}
+		}
+		if err := s.Repo.Create(ctx, analysis); err != nil {
+			return Analysis{}, false, err
+		}
+		createdAnalysis, created = analysis, true
+	} else {
+		createdAnalysis, created, err = s.Repo.GetOrCreateForDocument(ctx, analysis, allowRetry, allowCreate)
+		if err != nil {
+			return createdAnalysis, false, err
+		}
 	}
 	if created && s.Usage != nil {
-		if _, err := s.Usage.Consume(ctx, userID, 1); err != nil {
+		if _, err := s.Usage.Consume(ctx, userID, opts.OrgID, 1); err != nil {
 			return createdAnalysis, false, err
 		}
 	}
+	if created && createdAnalysis.Status == StatusBudgetDeferred {
+		logBudgetDeferred(ctx, createdAnalysis)
+		return createdAnalysis, created, nil
+	}
 	if created {
 		if s.JobQueue == nil {
 			return createdAnalysis, created, ErrJobQueueNotConfigured
 		}
-		if err := s.JobQueue.Send(ctx, queue.Message{
-			AnalysisID: createdAnalysis.ID,
-			RequestID:  requestIDFromContext(ctx),
-			EnqueuedAt: time.Now().UTC().Format(time.RFC3339Nano),
-			Version:    1,
-		}); err != nil {
+		if err := s.enqueue(ctx, createdAnalysis); err != nil {
+			// Leaving the row queued would make every later request reuse it forever.
+			err = fmt.Errorf("enqueue analysis: %w", err)
+			s.failAnalysis(ctx, createdAnalysis.ID, userID, documentID, err, nil)
 			return createdAnalysis, created, err
 		}
 	}
@@ -208,6 +337,7 @@ func normalizeStorageProvider(provider string) string {
 
 // ProcessAnalysis executes analysis processing synchronously.
 func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err error) {
+	ctx = ctxmeta.WithAnalysisID(ctx, analysisID)
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic: %v", r)
@@ -224,6 +354,17 @@ func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err e
 	if analysis.Status == StatusCompleted || analysis.Status == StatusFailed {
 		return nil
 	}
+	// Storage and the LLM are pinned to the owner's residency region.
+	ctx = ctxmeta.WithDataOwner(ctx, analysis.UserID)
+	var tokens ctxmeta.TokenUsage
+	ctx = ctxmeta.WithTokenCapture(ctx, &tokens)
+	defer s.recordSpend(ctx, analysis, &tokens)
+	if s.Flags.Enabled(ctx, featureflags.StructuredOutput, featureflags.Target{UserID: analysis.UserID}) {
+		ctx = ctxmeta.WithStructuredOutput(ctx)
+	}
+	if err := s.awaitExtraction(ctx, analysis); err != nil {
+		return err
+	}
 
 	startedAt := time.Now().UTC()
 	if err := s.Repo.UpdateStatusResultAndError(ctx, analysisID, StatusProcessing, nil, nil, nil, nil, &startedAt, nil); err != nil {
@@ -234,8 +375,7 @@ func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err e
 	}
 
 	metrics.IncAnalysisStarted()
-	telemetry.Info("analysis.status", map[string]any{
-		"request_id":        requestIDFromContext(ctx),
+	telemetry.InfoContext(ctx, "analysis.status", map[string]any{
 		"user_id":           analysis.UserID,
 		"document_id":       analysis.DocumentID,
 		"analysis_id":       analysis.ID,
@@ -252,183 +392,71 @@ func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err e
 		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
 		return err
 	}
-	requestID := requestIDFromContext(ctx)
+	pipeline, ok := s.pipelines().Lookup(analysis.Mode, analysis.PromptVersion)
+	if !ok {
+		err = s.checkPipeline(analysis.Mode, analysis.PromptVersion)
+		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
+		return err
+	}
+	requestID := ctxmeta.RequestID(ctx)
 	llmClient := newRetryingLLM(s.LLM, analysisID, requestID)
 
+	extractionStart := time.Now().UTC()
 	doc, err := s.DocRepo.GetByID(ctx, analysis.UserID, analysis.DocumentID)
 	if err != nil {
 		err = fmt.Errorf("document lookup id=%s: %w", analysis.DocumentID, err)
 		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
 		return err
 	}
-	storageProvider := normalizeStorageProvider(doc.StorageProvider)
-	telemetry.Info("analysis.document.storage", map[string]any{
-		"request_id":       requestID,
-		"document_id":      doc.ID,
-		"storage_provider": storageProvider,
-	})
-
-	extractedKey := doc.ExtractedTextKey
-	var extracted string
-	if extractedKey == "" {
-		switch storageProvider {
-		case "s3":
-			s3Client, err := newS3DocClient(ctx)
-			if err != nil {
-				err = fmt.Errorf("document %s mime %s: s3 client: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			raw, err := s3Client.GetObjectBytes(ctx, doc.StorageKey)
-			if err != nil {
-				err = fmt.Errorf("document %s mime %s: s3 read: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			extracted, err = extract.ExtractTextFromBytes(ctx, raw, doc.MimeType, doc.FileName)
-			if err != nil {
-				err = fmt.Errorf("document %s mime %s: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			extractedKey = doc.StorageKey + ".extracted.txt"
-			if err := s3Client.PutText(ctx, extractedKey, extracted); err != nil {
-				err = fmt.Errorf("document %s mime %s: store extracted: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			if err := s.DocRepo.UpdateExtraction(ctx, doc.UserID, doc.ID, extractedKey, time.Now().UTC()); err != nil {
-				err = fmt.Errorf("document %s mime %s: update extraction: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-		default:
-			if _, err := extract.ExtractText(ctx, s.Store, doc.StorageKey, doc.MimeType, doc.FileName); err != nil {
-				err = fmt.Errorf("document %s mime %s: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			extractedKey = doc.StorageKey + ".extracted.txt"
-			if err := s.DocRepo.UpdateExtraction(ctx, doc.UserID, doc.ID, extractedKey, time.Now().UTC()); err != nil {
-				err = fmt.Errorf("document %s mime %s: update extraction: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-		}
+	extracted, err := s.resumeText(ctx, doc)
+	if err != nil {
+		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
+		return err
 	}
+	extractionEnd := time.Now().UTC()
 
-	if extracted == "" {
-		switch storageProvider {
-		case "s3":
-			s3Client, err := newS3DocClient(ctx)
-			if err != nil {
-				err = fmt.Errorf("document %s mime %s: s3 client: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			raw, err := s3Client.GetObjectBytes(ctx, extractedKey)
-			if err != nil {
-				err = fmt.Errorf("document %s mime %s: load extracted text: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			extracted = string(raw)
-		default:
-			var err error
-			extracted, err = loadText(ctx, s.Store, extractedKey)
-			if err != nil {
-				err = fmt.Errorf("document %s mime %s: load extracted text: %w", doc.ID, doc.MimeType, err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-		}
+	supporting, err := s.loadSupportingDocuments(ctx, analysis)
+	if err != nil {
+		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
+		return err
 	}
 
-	input := llm.AnalyzeInput{
-		ResumeText:     extracted,
-		JobDescription: analysis.JobDescription,
-		PromptVersion:  analysis.PromptVersion,
-		TargetRole:     "",
+	input := analyzeInput(analysis, extracted, supporting)
+	truncations := s.fitInput(&input)
+	for _, t := range truncations {
+		telemetry.InfoContext(ctx, "analysis.input.truncated", map[string]any{
+			"analysis_id":    analysis.ID,
+			"input":          t.Input,
+			"original_chars": t.OriginalChars,
+			"kept_chars":     t.KeptChars,
+			"sections":       t.Sections,
+		})
 	}
 	var promptHash string
-	ctxWithHash := llm.WithPromptHashCapture(ctx, &promptHash)
-
-	var raw json.RawMessage
-	if analysis.PromptVersion == "v2" {
-		raw, err = ValidateV2WithRetry(ctxWithHash, llmClient, input)
-		if err != nil {
-			err = fmt.Errorf("llm validate v2: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-		if err := s.storeAnalysisRaw(ctx, analysisID, raw); err != nil {
-			err = fmt.Errorf("set analysis raw failed: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-	} else if analysis.PromptVersion == "v2_2" {
-		raw, err = ValidateV2_2WithRetry(ctxWithHash, llmClient, input)
-		if err != nil {
-			err = fmt.Errorf("llm validate v2_2: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-		if err := s.storeAnalysisRaw(ctx, analysisID, raw); err != nil {
-			err = fmt.Errorf("set analysis raw failed: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-	} else if analysis.PromptVersion == "v2_3" {
-		raw, err = ValidateV2_3WithRetry(ctxWithHash, llmClient, input)
-		if err != nil {
-			err = fmt.Errorf("llm validate v2_3: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-		if err := s.storeAnalysisRaw(ctx, analysisID, raw); err != nil {
-			err = fmt.Errorf("set analysis raw failed: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-	} else {
-		raw, err = llmClient.AnalyzeResume(ctxWithHash, input)
-		if err != nil {
-			err = fmt.Errorf("llm analyze: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-		if err := s.storeAnalysisRaw(ctx, analysisID, raw); err != nil {
-			err = fmt.Errorf("set analysis raw failed: %w", err)
-			s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-			return err
-		}
-
-		var parsed AnalysisResultV1
-		if err := json.Unmarshal(raw, &parsed); err != nil {
-			rawRetry, retryErr := llmClient.AnalyzeResume(llm.WithFixJSON(ctxWithHash, string(raw)), input)
-			if retryErr != nil {
-				err = fmt.Errorf("llm analyze retry: %w", retryErr)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			if err := json.Unmarshal(rawRetry, &parsed); err != nil {
-				if storeErr := s.storeAnalysisRaw(ctx, analysisID, rawRetry); storeErr != nil {
-					err = fmt.Errorf("set analysis raw failed: %w", storeErr)
-					s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-					return err
-				}
-				err = fmt.Errorf("llm output invalid: %w", err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
-			raw = rawRetry
-			if err := s.storeAnalysisRaw(ctx, analysisID, raw); err != nil {
-				err = fmt.Errorf("set analysis raw failed: %w", err)
-				s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
-				return err
-			}
+	var sanitized bool
+	ctxWithHash := ctxmeta.WithSanitizedCapture(ctxmeta.WithPromptHashCapture(ctx, &promptHash), &sanitized)
+
+	run := &PipelineRun{
+		Analysis:   analysis,
+		ResumeText: extracted,
+		Input:      input,
+		Client:     llmClient,
+		svc:        s,
+	}
+	if pipeline.BuildInput != nil {
+		pipeline.BuildInput(run)
+	}
+	raw, err := pipeline.Generate(ctxWithHash, run)
+	generationEnd := time.Now().UTC()
+	if len(raw) > 0 {
+		if storeErr := s.storeAnalysisRaw(ctx, analysisID, raw); storeErr != nil {
+			err = fmt.Errorf("set analysis raw failed: %w", storeErr)
 		}
 	}
+	if err != nil {
+		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
+		return err
+	}
 	if promptHash == "" {
 		// TODO: Ensure prompt_hash is captured for non-OpenAI providers if/when added.
 		promptHash = ""
@@ -439,14 +467,37 @@ func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err e
 		return err
 	}
 
-	result, err := normalizeAnalysisResult(raw, analysis)
+	normalize := pipeline.Normalize
+	if normalize == nil {
+		normalize = normalizeAnalysisResult
+	}
+	result, err := normalize(raw, analysis)
 	if err != nil {
 		err = fmt.Errorf("llm output invalid: %w", err)
 		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
 		return err
 	}
+	s.withEnsemble(ctx, pipeline, run, result)
+	withTruncation(result, truncations)
+	attributeEvidence(result, analysis.DocumentID, extracted, supporting)
+	withRevision(result, run.Revision)
+	withQuantification(result, run.Quantification)
+	s.markAppliedRewrites(ctx, result, analysis)
+	normalizationEnd := time.Now().UTC()
+	s.withLearningPlan(ctx, result, analysis, extracted)
 
 	completedAt := time.Now().UTC()
+	provenance := newProvenance(analysis, doc, extracted, promptHash)
+	provenance.DurationsMs = ProvenanceDurations{
+		Queue:         msBetween(analysis.CreatedAt, startedAt),
+		Extraction:    msBetween(extractionStart, extractionEnd),
+		Generation:    msBetween(extractionEnd, generationEnd),
+		Normalization: msBetween(generationEnd, normalizationEnd),
+		LearningPlan:  msBetween(normalizationEnd, completedAt),
+		Total:         msBetween(startedAt, completedAt),
+	}
+	withProvenance(result, provenance)
+	withRoleCategory(result, analysis.JobDescription, analysis.TargetRole)
 	if err := s.Repo.UpdateAnalysisResult(ctx, analysisID, result, &completedAt); err != nil {
 		err = fmt.Errorf("set analysis result failed: %w", err)
 		s.failAnalysis(ctx, analysisID, analysis.UserID, analysis.DocumentID, err, &startedAt)
@@ -454,8 +505,8 @@ func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err e
 	}
 	metrics.IncAnalysisCompleted()
 	metrics.ObserveAnalysisDurationMs(durationMs(&startedAt, &completedAt))
-	telemetry.Info("analysis.status", map[string]any{
-		"request_id":        requestIDFromContext(ctx),
+	s.recordPromptOutcome(ctx, analysis.PromptVersion, PromptOutcome{Sanitized: sanitized}, &startedAt, &completedAt)
+	telemetry.InfoContext(ctx, "analysis.status", map[string]any{
 		"user_id":           analysis.UserID,
 		"document_id":       analysis.DocumentID,
 		"analysis_id":       analysis.ID,
@@ -466,6 +517,114 @@ func (s *Service) ProcessAnalysis(ctx context.Context, analysisID string) (err e
 	return nil
 }
 
+// resumeText returns the document's extracted text, extracting and storing it
+// first when that has not happened yet. With StorageFallback, a document its
+// own store cannot serve is read from the other one.
+func (s *Service) resumeText(ctx context.Context, doc documents.Document) (string, error) {
+	storageProvider := normalizeStorageProvider(doc.StorageProvider)
+	telemetry.InfoContext(ctx, "analysis.document.storage", map[string]any{
+		"document_id":      doc.ID,
+		"storage_provider": storageProvider,
+	})
+
+	text, err := s.resumeTextFrom(ctx, doc, storageProvider)
+	if err == nil || !s.StorageFallback || ctx.Err() != nil {
+		return text, err
+	}
+	fallback := "s3"
+	if storageProvider == "s3" {
+		fallback = "local"
+	}
+	text, fallbackErr := s.fallbackText(ctx, doc, fallback)
+	if fallbackErr != nil {
+		return "", err
+	}
+	telemetry.InfoContext(ctx, "analysis.document.storage_fallback", map[string]any{
+		"document_id":      doc.ID,
+		"storage_provider": storageProvider,
+		"read_from":        fallback,
+		"error":            err.Error(),
+	})
+	return text, nil
+}
+
+// resumeTextFrom loads the document's text from the given store, extracting and
+// saving it there first when the document has not been extracted yet.
+func (s *Service) resumeTextFrom(ctx context.Context, doc documents.Document, storageProvider string) (string, error) {
+	extractedKey := doc.ExtractedTextKey
+	var extracted string
+	if extractedKey == "" {
+		var err error
+		if extracted, extractedKey, err = s.extractOnce(ctx, doc, storageProvider); err != nil {
+			return "", err
+		}
+	}
+
+	if extracted == "" {
+		switch storageProvider {
+		case "s3":
+			s3Client, err := s.s3Documents(ctx)
+			if err != nil {
+				return "", fmt.Errorf("document %s mime %s: s3 client: %w", doc.ID, doc.MimeType, err)
+			}
+			raw, err := s3Client.GetObjectBytes(ctx, extractedKey)
+			if err != nil {
+				return "", fmt.Errorf("document %s mime %s: load extracted text: %w", doc.ID, doc.MimeType, err)
+			}
+			extracted = string(raw)
+		default:
+			text, err := loadText(ctx, s.Store, extractedKey)
+			if err != nil {
+				return "", fmt.Errorf("document %s mime %s: load extracted text: %w", doc.ID, doc.MimeType, err)
+			}
+			extracted = text
+		}
+	}
+	return extracted, nil
+}
+
+// fallbackText reads the document's text from a store its storage_provider does
+// not name, under the keys the legacy storage migration uses there. Nothing is
+// written back: text missing from that store is extracted in memory.
+func (s *Service) fallbackText(ctx context.Context, doc documents.Document, storageProvider string) (string, error) {
+	key, extractedKey := doc.StorageKey, doc.ExtractedTextKey
+	if storageProvider == "s3" {
+		key = documents.LegacyKeyPrefix + key
+		if extractedKey != "" {
+			extractedKey = documents.LegacyKeyPrefix + extractedKey
+		}
+	} else {
+		key = strings.TrimPrefix(key, documents.LegacyKeyPrefix)
+		extractedKey = strings.TrimPrefix(extractedKey, documents.LegacyKeyPrefix)
+	}
+	if extractedKey != "" {
+		if raw, err := s.readStoredObject(ctx, storageProvider, extractedKey); err == nil {
+			return string(raw), nil
+		}
+	}
+	raw, err := s.readStoredObject(ctx, storageProvider, key)
+	if err != nil {
+		return "", err
+	}
+	return extract.ExtractTextFromBytes(ctx, raw, doc.ExtractionMimeType(), doc.FileName)
+}
+
+func (s *Service) readStoredObject(ctx context.Context, storageProvider, key string) ([]byte, error) {
+	if storageProvider == "s3" {
+		s3Client, err := s.s3Documents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return s3Client.GetObjectBytes(ctx, key)
+	}
+	body, err := s.Store.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
 func (s *Service) completeAsync(ctx context.Context, analysisID string) {
 	_ = s.ProcessAnalysis(ctx, analysisID)
 }
@@ -480,9 +639,17 @@ func (s *Service) failAnalysis(ctx context.Context, analysisID, userID, document
 	metrics.IncAnalysisFailed()
 	if startedAt != nil {
 		metrics.ObserveAnalysisDurationMs(durationMs(startedAt, &completedAt))
+		// Only failures after processing began say anything about the prompt.
+		if s.Rollout != nil {
+			if analysis, getErr := s.Repo.GetByID(context.Background(), analysisID); getErr == nil {
+				s.recordPromptOutcome(ctx, analysis.PromptVersion, PromptOutcome{
+					Failed:        true,
+					SchemaFailure: code == ErrorCodeLLMSchemaMismatch,
+				}, startedAt, &completedAt)
+			}
+		}
 	}
-	telemetry.Info("analysis.status", map[string]any{
-		"request_id":        requestIDFromContext(ctx),
+	telemetry.InfoContext(ctx, "analysis.status", map[string]any{
 		"user_id":           userID,
 		"document_id":       documentID,
 		"analysis_id":       analysisID,
@@ -507,6 +674,14 @@ func classifyFailure(err error) (string, bool) {
 		return ErrorCodeLLMTimeout, true
 	}
 	msg := strings.ToLower(err.Error())
+	// The LLM circuit breaker is open; retry once the provider recovers.
+	if strings.Contains(msg, "llm provider unavailable") {
+		return ErrorCodeLLMUnavailable, true
+	}
+	// The stuck-state detector gave up on a worker that stopped making progress.
+	if strings.Contains(msg, "processing stalled") {
+		return ErrorCodeStalled, true
+	}
 	if strings.Contains(msg, "openai request timeout") {
 		return ErrorCodeLLMTimeout, true
 	}
@@ -522,6 +697,9 @@ func classifyFailure(err error) (string, bool) {
 	if strings.Contains(msg, "validation") && !strings.Contains(msg, "llm") {
 		return ErrorCodeValidation, false
 	}
+	if strings.Contains(msg, "enqueue analysis") {
+		return ErrorCodeQueue, true
+	}
 	if strings.Contains(msg, "document") || strings.Contains(msg, "storage") || strings.Contains(msg, "analysis raw") || strings.Contains(msg, "analysis result") || strings.Contains(msg, "prompt metadata") || strings.Contains(msg, "set processing") {
 		return ErrorCodeStorage, true
 	}
@@ -623,3 +801,19 @@ func normalizeStringArray(container map[string]any, key string) {
 		container[key] = out
 	}
 }
+
+// analyzeInput is the LLM input for an analysis, shared by the primary and
+// shadow runs so both prompt the model the same way.
+func analyzeInput(analysis Analysis, resumeText string, supporting []llm.SupportingDocument) llm.AnalyzeInput {
+	input := llm.AnalyzeInput{
+		ResumeText:          resumeText,
+		JobDescription:      analysis.JobDescription,
+		PromptVersion:       analysis.PromptVersion,
+		TargetRole:          analysis.TargetRole,
+		SupportingDocuments: supporting,
+	}
+	if profile, ok := roles.Lookup(analysis.TargetRole); ok {
+		input.TargetRoleKeywords = profile.Keywords
+	}
+	return input
+}
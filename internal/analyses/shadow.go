@@ -0,0 +1,124 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+// ErrNotCompleted is returned when shadow-scoring an analysis that has no
+// completed result to compare against.
+var ErrNotCompleted = errors.New("analysis is not completed")
+
+// ShadowOptions selects what a shadow run scores an analysis with.
+type ShadowOptions struct {
+	// PromptVersion replaces the analysis's prompt version; empty keeps it.
+	PromptVersion string
+	// Client replaces the service's LLM client, for example with one for another
+	// model; nil keeps it.
+	Client llm.Client
+}
+
+// ShadowResult is the outcome of a shadow run.
+type ShadowResult struct {
+	PromptVersion string         `json:"promptVersion"`
+	Result        map[string]any `json:"result"`
+	// Score and OriginalScore are the final scores of the shadow and stored
+	// results, when they have one.
+	Score         *float64 `json:"score,omitempty"`
+	OriginalScore *float64 `json:"originalScore,omitempty"`
+	DurationMs    int64    `json:"durationMs"`
+}
+
+// ShadowRun scores a completed analysis again with the given prompt version and
+// client, without storing anything on the analysis. It always runs the full
+// prompt: a delta against the user's previous resume would reuse sections scored
+// by the old prompt.
+func (s *Service) ShadowRun(ctx context.Context, analysisID string, opts ShadowOptions) (ShadowResult, error) {
+	ctx = ctxmeta.WithAnalysisID(ctx, analysisID)
+	analysis, err := s.Repo.GetByID(ctx, analysisID)
+	if err != nil {
+		return ShadowResult{}, err
+	}
+	if analysis.Status != StatusCompleted {
+		return ShadowResult{}, fmt.Errorf("%w: status is %s", ErrNotCompleted, analysis.Status)
+	}
+	// Storage and the LLM are pinned to the owner's residency region.
+	ctx = ctxmeta.WithDataOwner(ctx, analysis.UserID)
+	original := analysis.Result
+	if opts.PromptVersion != "" {
+		analysis.PromptVersion = opts.PromptVersion
+	}
+	client := opts.Client
+	if client == nil {
+		client = s.LLM
+	}
+	if client == nil {
+		return ShadowResult{}, errors.New("missing llm client")
+	}
+	pipeline, ok := s.pipelines().Lookup(analysis.Mode, analysis.PromptVersion)
+	if !ok {
+		return ShadowResult{}, s.checkPipeline(analysis.Mode, analysis.PromptVersion)
+	}
+	if s.DocRepo == nil || s.Store == nil {
+		return ShadowResult{}, errors.New("missing document store dependencies")
+	}
+
+	startedAt := time.Now()
+	doc, err := s.DocRepo.GetByID(ctx, analysis.UserID, analysis.DocumentID)
+	if err != nil {
+		return ShadowResult{}, fmt.Errorf("document lookup id=%s: %w", analysis.DocumentID, err)
+	}
+	extracted, err := s.resumeText(ctx, doc)
+	if err != nil {
+		return ShadowResult{}, err
+	}
+	supporting, err := s.loadSupportingDocuments(ctx, analysis)
+	if err != nil {
+		return ShadowResult{}, err
+	}
+	run := &PipelineRun{
+		Analysis:   analysis,
+		ResumeText: extracted,
+		Input:      analyzeInput(analysis, extracted, supporting),
+		Client:     newRetryingLLM(client, analysisID, ctxmeta.RequestID(ctx)),
+		fullOnly:   true,
+		svc:        s,
+	}
+	truncations := s.fitInput(&run.Input)
+	if pipeline.BuildInput != nil {
+		pipeline.BuildInput(run)
+	}
+	raw, err := pipeline.Generate(ctx, run)
+	if err != nil {
+		return ShadowResult{}, err
+	}
+	normalize := pipeline.Normalize
+	if normalize == nil {
+		normalize = normalizeAnalysisResult
+	}
+	result, err := normalize(raw, analysis)
+	if err != nil {
+		return ShadowResult{}, fmt.Errorf("llm output invalid: %w", err)
+	}
+	withTruncation(result, truncations)
+	attributeEvidence(result, analysis.DocumentID, extracted, supporting)
+	withQuantification(result, run.Quantification)
+
+	out := ShadowResult{
+		PromptVersion: analysis.PromptVersion,
+		Result:        result,
+		DurationMs:    time.Since(startedAt).Milliseconds(),
+	}
+	if score, ok := extractFinalScore(result, analysis.Mode); ok {
+		out.Score = &score
+	}
+	if score, ok := extractFinalScore(original, analysis.Mode); ok {
+		out.OriginalScore = &score
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package analyses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/llm"
+)
+
+type countingLLM struct {
+	response []byte
+	calls    atomic.Int32
+	// input is the last input the client was called with.
+	input atomic.Pointer[llm.AnalyzeInput]
+}
+
+func (c *countingLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	c.calls.Add(1)
+	c.input.Store(&input)
+	return json.RawMessage(c.response), nil
+}
+
+func TestShadowRunLeavesStoredResultAlone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fixture := loadFixture(t, "testdata/v2_3_good.json")
+	router, analysisRepo, svc := setupAnalysisRouterWithLLM(t, fixture)
+	analysisID := startAnalysis(t, router, "v2_3")
+	if err := svc.ProcessAnalysis(context.Background(), analysisID); err != nil {
+		t.Fatalf("process analysis: %v", err)
+	}
+	waitForStatus(t, analysisRepo, analysisID, StatusCompleted)
+	before, _ := analysisRepo.GetByID(context.Background(), analysisID)
+
+	client := &countingLLM{response: fixture}
+	shadow, err := svc.ShadowRun(context.Background(), analysisID, ShadowOptions{PromptVersion: "v2_3", Client: client})
+	if err != nil {
+		t.Fatalf("shadow run: %v", err)
+	}
+	if client.calls.Load() == 0 {
+		t.Fatalf("expected the shadow client to be called")
+	}
+	if shadow.Result == nil || shadow.Score == nil || shadow.OriginalScore == nil {
+		t.Fatalf("expected a scored shadow result, got %+v", shadow)
+	}
+	if *shadow.Score != *shadow.OriginalScore {
+		t.Fatalf("expected the same output to score the same, got %v and %v", *shadow.Score, *shadow.OriginalScore)
+	}
+	after, _ := analysisRepo.GetByID(context.Background(), analysisID)
+	if !sameJSON(before.Result, after.Result) || after.PromptVersion != before.PromptVersion {
+		t.Fatalf("shadow run must not change the stored analysis")
+	}
+}
+
+func TestShadowRunRejectsUnfinishedAnalyses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, _, svc := setupAnalysisRouterWithLLM(t, loadFixture(t, "testdata/v2_3_good.json"))
+	analysisID := startAnalysis(t, router, "v2_3")
+	if _, err := svc.ShadowRun(context.Background(), analysisID, ShadowOptions{}); !errors.Is(err, ErrNotCompleted) {
+		t.Fatalf("expected ErrNotCompleted, got %v", err)
+	}
+}
+
+func TestShadowRunPromptsWithTheTargetRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fixture := loadFixture(t, "testdata/v2_3_good.json")
+	router, analysisRepo, svc := setupAnalysisRouterWithLLM(t, fixture)
+	analysisID := startAnalysis(t, router, "v2_3")
+	if err := svc.ProcessAnalysis(context.Background(), analysisID); err != nil {
+		t.Fatalf("process analysis: %v", err)
+	}
+	waitForStatus(t, analysisRepo, analysisID, StatusCompleted)
+	analysisRepo.mu.Lock()
+	analysis := analysisRepo.byID[analysisID]
+	analysis.TargetRole = "Backend Engineer"
+	analysisRepo.byID[analysisID] = analysis
+	analysisRepo.mu.Unlock()
+
+	client := &countingLLM{response: fixture}
+	if _, err := svc.ShadowRun(context.Background(), analysisID, ShadowOptions{PromptVersion: "v2_3", Client: client}); err != nil {
+		t.Fatalf("shadow run: %v", err)
+	}
+	input := client.input.Load()
+	if input == nil || input.TargetRole != "Backend Engineer" || len(input.TargetRoleKeywords) == 0 {
+		t.Fatalf("expected the target role and its keywords in the shadow input, got %+v", input)
+	}
+}
@@ -0,0 +1,70 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/storage/object/local"
+	"resume-backend/internal/usage"
+)
+
+func TestStartAnalysisFlagsSoftLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docRepo := documents.NewMemoryRepo()
+	store := local.New(t.TempDir())
+	usageSvc := usage.NewService()
+	usageSvc.SoftLimitPercent = 50
+	svc := &Service{Repo: NewMemoryRepo(), DocRepo: docRepo, Store: store, LLM: stubLLM{}, JobQueue: &stubQueue{}, Usage: usageSvc}
+	router := gin.New()
+	router.Use(middleware.Auth("dev"))
+	NewHandler(svc, docRepo).RegisterRoutes(router.Group("/api/v1"))
+
+	userID := "guest:test-guest"
+	documentID := seedDocument(t, docRepo, store, userID)
+
+	start := func() map[string]any {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"jobDescription": strings.Repeat("a", 300), "forceNew": true})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/"+documentID+"/analyze", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusAccepted {
+			t.Fatalf("expected status 202, got %d: %s", resp.Code, resp.Body.String())
+		}
+		var out map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		u, err := usageSvc.Get(context.Background(), userID)
+		if err != nil {
+			t.Fatalf("get usage: %v", err)
+		}
+		if got, want := resp.Header().Get(usage.RemainingHeader), strconv.Itoa(u.Limit-u.Used); got != want {
+			t.Fatalf("expected %s %s, got %q", usage.RemainingHeader, want, got)
+		}
+		return out
+	}
+
+	if got := start()["softLimitWarning"]; got != false {
+		t.Fatalf("expected no warning after first analysis, got %v", got)
+	}
+	if _, err := usageSvc.Consume(context.Background(), userID, "", 3); err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+	if got := start()["softLimitWarning"]; got != true {
+		t.Fatalf("expected warning at 50%% usage, got %v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// stalledStore is implemented by repos that can find and fail analyses whose
+// worker stopped making progress.
+type stalledStore interface {
+	// ListStalledProcessing returns processing analyses last updated before
+	// the given time, oldest first.
+	ListStalledProcessing(ctx context.Context, before time.Time, limit int) ([]Analysis, error)
+	// FailStalled fails the analysis with a retryable error if it is still
+	// processing and was last updated before the given time. It reports whether
+	// the analysis was failed.
+	FailStalled(ctx context.Context, analysisID string, before time.Time, code, message string) (bool, error)
+}
+
+var (
+	_ stalledStore = (*MemoryRepo)(nil)
+	_ stalledStore = (*PGRepo)(nil)
+	_ stalledStore = (*WorkerRepo)(nil)
+)
+
+// ErrStalledUnsupported is returned when the repo cannot look for stalled analyses.
+var ErrStalledUnsupported = errors.New("stalled analysis lookup not supported")
+
+// ListStalled returns analyses that have been processing without an update
+// since before. Every status change and stored output updates an analysis, so
+// its updated time serves as the worker's heartbeat.
+func (s *Service) ListStalled(ctx context.Context, before time.Time, limit int) ([]Analysis, error) {
+	store, ok := s.Repo.(stalledStore)
+	if !ok {
+		return nil, ErrStalledUnsupported
+	}
+	return store.ListStalledProcessing(ctx, before, limit)
+}
+
+// FailStalled fails a stalled analysis with the retryable PROCESSING_STALLED
+// code so the user can start it again. An analysis that made progress since
+// before is left alone; the result reports whether it was failed.
+func (s *Service) FailStalled(ctx context.Context, analysis Analysis, before time.Time) (bool, error) {
+	store, ok := s.Repo.(stalledStore)
+	if !ok {
+		return false, ErrStalledUnsupported
+	}
+	msg := fmt.Sprintf("processing stalled: no progress since %s", analysis.UpdatedAt.UTC().Format(time.RFC3339))
+	failed, err := store.FailStalled(ctx, analysis.ID, before, ErrorCodeStalled, msg)
+	if err != nil || !failed {
+		return false, err
+	}
+	metrics.IncAnalysisFailed()
+	telemetry.InfoContext(ctx, "analysis.status", map[string]any{
+		"user_id":           analysis.UserID,
+		"document_id":       analysis.DocumentID,
+		"analysis_id":       analysis.ID,
+		"status":            StatusFailed,
+		"status_transition": "processing->failed",
+		"error_code":        ErrorCodeStalled,
+	})
+	return true, nil
+}
+
+// ExtractedTextMissing reports whether a document's extracted text object is
+// gone from its store, and, with StorageFallback, from the other store too.
+// Documents without an extraction are never missing text.
+func (s *Service) ExtractedTextMissing(ctx context.Context, doc documents.Document) (bool, error) {
+	if doc.ExtractedTextKey == "" {
+		return false, nil
+	}
+	provider := normalizeStorageProvider(doc.StorageProvider)
+	err := s.probeStoredObject(ctx, provider, doc.ExtractedTextKey)
+	if !isObjectMissing(err) || !s.StorageFallback {
+		return isObjectMissing(err), ignoreMissing(err)
+	}
+	fallback, key := "s3", documents.LegacyKeyPrefix+doc.ExtractedTextKey
+	if provider == "s3" {
+		fallback = "local"
+		key = strings.TrimPrefix(doc.ExtractedTextKey, documents.LegacyKeyPrefix)
+	}
+	err = s.probeStoredObject(ctx, fallback, key)
+	return isObjectMissing(err), ignoreMissing(err)
+}
+
+func (s *Service) probeStoredObject(ctx context.Context, storageProvider, key string) error {
+	if storageProvider == "s3" {
+		_, err := s.readStoredObject(ctx, storageProvider, key)
+		return err
+	}
+	body, err := s.Store.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(io.Discard, io.LimitReader(body, 1))
+	body.Close()
+	return err
+}
+
+// isObjectMissing reports whether err says the object does not exist, as
+// opposed to the store failing.
+func isObjectMissing(err error) bool {
+	var noSuchKey *s3types.NoSuchKey
+	return errors.Is(err, fs.ErrNotExist) || errors.As(err, &noSuchKey)
+}
+
+func ignoreMissing(err error) error {
+	if isObjectMissing(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,275 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Prompt version statuses in a sunset policy.
+const (
+	PromptStatusActive = "active"
+	// PromptStatusDeprecated versions are replaced by their successor on new
+	// analyses.
+	PromptStatusDeprecated = "deprecated"
+	// PromptStatusBlocked versions are rejected for new analyses.
+	PromptStatusBlocked = "blocked"
+)
+
+// sunsetStatsWindow is how far back the admin stats count stored analyses.
+const sunsetStatsWindow = 30 * 24 * time.Hour
+
+// ErrPromptVersionBlocked means a new analysis asked for a retired prompt
+// version.
+var ErrPromptVersionBlocked = errors.New("prompt version is retired")
+
+// PromptPolicy is the sunset status of one prompt version.
+type PromptPolicy struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	// Successor replaces a deprecated version.
+	Successor string `json:"successor,omitempty"`
+}
+
+// DefaultPromptPolicies deprecate the versions that still run the v1 and v2
+// code paths in favor of DefaultPromptVersion.
+func DefaultPromptPolicies() []PromptPolicy {
+	return []PromptPolicy{
+		{Version: "v1", Status: PromptStatusDeprecated, Successor: DefaultPromptVersion},
+		{Version: "v2", Status: PromptStatusDeprecated, Successor: DefaultPromptVersion},
+		{Version: "v2_1", Status: PromptStatusDeprecated, Successor: DefaultPromptVersion},
+	}
+}
+
+// ParsePromptPolicies reads policies written as comma-separated
+// version=status entries, with the successor after a colon for deprecated
+// versions: "v1=deprecated:v2_3,v2=blocked".
+func ParsePromptPolicies(spec string) ([]PromptPolicy, error) {
+	var out []PromptPolicy
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		version, rule, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("prompt policy %q: want version=status", entry)
+		}
+		status, successor, _ := strings.Cut(rule, ":")
+		out = append(out, PromptPolicy{
+			Version:   strings.TrimSpace(version),
+			Status:    strings.TrimSpace(status),
+			Successor: strings.TrimSpace(successor),
+		})
+	}
+	return out, nil
+}
+
+// PromptSunset applies prompt version sunset policies to new analyses and
+// counts the requests that asked for a deprecated or blocked version.
+// Versions without a policy are active.
+type PromptSunset struct {
+	mu       sync.Mutex
+	policies map[string]PromptPolicy
+	remapped map[string]int
+	rejected map[string]int
+}
+
+// NewPromptSunset validates policies and builds a PromptSunset. A later
+// policy for a version replaces an earlier one, so overrides can be appended
+// to DefaultPromptPolicies. Deprecated versions need a successor, and
+// following successors must end at a version that is not deprecated or
+// blocked.
+func NewPromptSunset(policies ...PromptPolicy) (*PromptSunset, error) {
+	byVersion := make(map[string]PromptPolicy, len(policies))
+	for _, p := range policies {
+		if p.Version == "" {
+			return nil, errors.New("prompt policy has no version")
+		}
+		switch p.Status {
+		case PromptStatusActive, PromptStatusBlocked:
+			p.Successor = ""
+		case PromptStatusDeprecated:
+			if p.Successor == "" || p.Successor == p.Version {
+				return nil, fmt.Errorf("prompt policy %s: deprecated versions need a successor", p.Version)
+			}
+		default:
+			return nil, fmt.Errorf("prompt policy %s: unknown status %q", p.Version, p.Status)
+		}
+		byVersion[p.Version] = p
+	}
+	for _, p := range byVersion {
+		if p.Status == PromptStatusActive {
+			delete(byVersion, p.Version)
+		}
+	}
+	s := &PromptSunset{
+		policies: byVersion,
+		remapped: make(map[string]int),
+		rejected: make(map[string]int),
+	}
+	for version, p := range byVersion {
+		if p.Status != PromptStatusDeprecated {
+			continue
+		}
+		if _, err := s.successor(version); err != nil {
+			if errors.Is(err, ErrPromptVersionBlocked) {
+				return nil, fmt.Errorf("prompt policy %s: its successors end at a blocked version", version)
+			}
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Policy returns the policy for version.
+func (s *PromptSunset) Policy(version string) PromptPolicy {
+	if s != nil {
+		if p, ok := s.policies[version]; ok {
+			return p
+		}
+	}
+	return PromptPolicy{Version: version, Status: PromptStatusActive}
+}
+
+// Resolve returns the version a new analysis asking for version runs with,
+// following successors of deprecated versions. It returns
+// ErrPromptVersionBlocked for blocked versions. A nil PromptSunset returns
+// version unchanged.
+func (s *PromptSunset) Resolve(version string) (string, error) {
+	if s == nil {
+		return version, nil
+	}
+	resolved, err := s.successor(version)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case err != nil:
+		s.rejected[version]++
+		return "", err
+	case resolved != version:
+		s.remapped[version]++
+	}
+	return resolved, nil
+}
+
+// successor follows deprecated versions to the first version that is not.
+func (s *PromptSunset) successor(version string) (string, error) {
+	seen := map[string]bool{}
+	for {
+		p := s.Policy(version)
+		switch p.Status {
+		case PromptStatusBlocked:
+			return "", fmt.Errorf("%w: %s", ErrPromptVersionBlocked, version)
+		case PromptStatusDeprecated:
+			if seen[version] {
+				return "", fmt.Errorf("prompt policy %s: successors loop", version)
+			}
+			seen[version] = true
+			version = p.Successor
+		default:
+			return version, nil
+		}
+	}
+}
+
+// Policies lists the deprecated and blocked versions, sorted.
+func (s *PromptSunset) Policies() []PromptPolicy {
+	out := []PromptPolicy{}
+	if s == nil {
+		return out
+	}
+	for _, p := range s.policies {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+// RetiredVersions lists the versions with a deprecated or blocked policy.
+func (s *PromptSunset) RetiredVersions() []string {
+	var out []string
+	for _, p := range s.Policies() {
+		out = append(out, p.Version)
+	}
+	return out
+}
+
+// Requests returns, by requested version, how many new analyses were moved to
+// a successor and how many were rejected since the process started.
+func (s *PromptSunset) Requests() (remapped, rejected map[string]int) {
+	remapped, rejected = map[string]int{}, map[string]int{}
+	if s == nil {
+		return remapped, rejected
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.remapped {
+		remapped[k] = v
+	}
+	for k, v := range s.rejected {
+		rejected[k] = v
+	}
+	return remapped, rejected
+}
+
+// promptVersionCounter is implemented by repos that can count analyses by
+// prompt version.
+type promptVersionCounter interface {
+	// PromptVersionCounts counts the analyses of the listed prompt versions
+	// created since the given time, by version.
+	PromptVersionCounts(ctx context.Context, versions []string, since time.Time) (map[string]int, error)
+}
+
+var (
+	_ promptVersionCounter = (*MemoryRepo)(nil)
+	_ promptVersionCounter = (*PGRepo)(nil)
+)
+
+// resolvePromptVersion applies the sunset policy to the version picked for a
+// new analysis.
+func (s *Service) resolvePromptVersion(ctx context.Context, promptVersion string) (string, error) {
+	resolved, err := s.Sunset.Resolve(promptVersion)
+	if err != nil {
+		telemetry.InfoContext(ctx, "analysis.prompt_version_blocked", map[string]any{"prompt_version": promptVersion})
+		return "", err
+	}
+	if resolved != promptVersion {
+		telemetry.InfoContext(ctx, "analysis.prompt_version_remapped", map[string]any{
+			"requested_prompt_version": promptVersion,
+			"prompt_version":           resolved,
+		})
+	}
+	return resolved, nil
+}
+
+// SunsetStats reports the sunset policies and how much deprecated and blocked
+// versions are still asked for, for the admin stats endpoint. stored counts
+// the analyses of those versions created in the last 30 days, including ones
+// created before the policy applied.
+func (s *Service) SunsetStats(ctx context.Context) (any, error) {
+	remapped, rejected := s.Sunset.Requests()
+	stats := map[string]any{
+		"policies": s.Sunset.Policies(),
+		"remapped": remapped,
+		"rejected": rejected,
+	}
+	counter, ok := s.Repo.(promptVersionCounter)
+	versions := s.Sunset.RetiredVersions()
+	if !ok || len(versions) == 0 {
+		return stats, nil
+	}
+	stored, err := counter.PromptVersionCounts(ctx, versions, time.Now().UTC().Add(-sunsetStatsWindow))
+	if err != nil {
+		return nil, err
+	}
+	stats["windowDays"] = int(sunsetStatsWindow.Hours() / 24)
+	stats["stored"] = stored
+	return stats, nil
+}
@@ -0,0 +1,84 @@
+package analyses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPromptSunsetMapsDeprecatedAndRejectsBlocked(t *testing.T) {
+	overrides, err := ParsePromptPolicies("v2_2=deprecated:v2_1, v2=blocked")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	sunset, err := NewPromptSunset(append(DefaultPromptPolicies(), overrides...)...)
+	if err != nil {
+		t.Fatalf("new sunset: %v", err)
+	}
+	svc := &Service{Repo: NewMemoryRepo(), JobQueue: &stubQueue{}, Sunset: sunset}
+	ctx := context.Background()
+
+	// v2_2 moves to v2_1, which is itself deprecated in favor of v2_3.
+	for _, requested := range []string{"v1", "v2_2"} {
+		analysis, _, err := svc.StartOrReuse(ctx, "doc-"+requested, "user-1", "", requested, ModeJobMatch, false)
+		if err != nil {
+			t.Fatalf("%s: %v", requested, err)
+		}
+		if analysis.PromptVersion != DefaultPromptVersion {
+			t.Fatalf("%s: expected %s, got %s", requested, DefaultPromptVersion, analysis.PromptVersion)
+		}
+	}
+	if _, _, err := svc.StartOrReuse(ctx, "doc-v2", "user-1", "", "v2", ModeJobMatch, false); !errors.Is(err, ErrPromptVersionBlocked) {
+		t.Fatalf("expected ErrPromptVersionBlocked, got %v", err)
+	}
+	if _, err := svc.Create(ctx, "doc-create", "user-1", "jd", "v1"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// An analysis stored before the policy still counts as deprecated usage.
+	if err := svc.Repo.Create(ctx, Analysis{ID: "old", DocumentID: "doc-old", UserID: "user-1", PromptVersion: "v1", Status: StatusCompleted, CreatedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	raw, err := svc.SunsetStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	stats := raw.(map[string]any)
+	if remapped := stats["remapped"].(map[string]int); remapped["v1"] != 2 || remapped["v2_2"] != 1 {
+		t.Fatalf("remapped = %v", remapped)
+	}
+	if rejected := stats["rejected"].(map[string]int); rejected["v2"] != 1 {
+		t.Fatalf("rejected = %v", rejected)
+	}
+	if stored := stats["stored"].(map[string]int); stored["v1"] != 1 || len(stored) != 1 {
+		t.Fatalf("stored = %v", stored)
+	}
+	if policies := stats["policies"].([]PromptPolicy); len(policies) != 4 {
+		t.Fatalf("policies = %+v", policies)
+	}
+}
+
+func TestNewPromptSunsetRejectsBadPolicies(t *testing.T) {
+	for name, policies := range map[string][]PromptPolicy{
+		"no successor":      {{Version: "v1", Status: PromptStatusDeprecated}},
+		"unknown status":    {{Version: "v1", Status: "retired"}},
+		"blocked end":       {{Version: "v1", Status: PromptStatusDeprecated, Successor: "v2"}, {Version: "v2", Status: PromptStatusBlocked}},
+		"successor loop":    {{Version: "v1", Status: PromptStatusDeprecated, Successor: "v2"}, {Version: "v2", Status: PromptStatusDeprecated, Successor: "v1"}},
+		"missing version":   {{Status: PromptStatusBlocked}},
+		"self as successor": {{Version: "v1", Status: PromptStatusDeprecated, Successor: "v1"}},
+	} {
+		if _, err := NewPromptSunset(policies...); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// An active override lifts a default policy.
+	sunset, err := NewPromptSunset(append(DefaultPromptPolicies(), PromptPolicy{Version: "v1", Status: PromptStatusActive})...)
+	if err != nil {
+		t.Fatalf("new sunset: %v", err)
+	}
+	if got, err := sunset.Resolve("v1"); err != nil || got != "v1" {
+		t.Fatalf("expected v1 to stay active, got %q (err %v)", got, err)
+	}
+}
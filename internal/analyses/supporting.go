@@ -0,0 +1,165 @@
+package analyses
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"resume-backend/internal/extract"
+	"resume-backend/internal/llm"
+)
+
+// Supporting document kinds accepted alongside the primary resume.
+const (
+	SupportingKindPortfolio   = "portfolio"
+	SupportingKindCoverLetter = "cover_letter"
+	SupportingKindOther       = "other"
+)
+
+// MaxSupportingDocuments caps how many supporting documents one analysis may reference.
+const MaxSupportingDocuments = 3
+
+// maxSupportingChars bounds the text taken from each supporting document so the resume stays dominant in the prompt.
+const maxSupportingChars = 12000
+
+// SupportingDocument references a non-resume document that informs evidence for an analysis.
+type SupportingDocument struct {
+	DocumentID string `json:"documentId"`
+	Kind       string `json:"kind"`
+}
+
+// ParseSupportingKind normalizes a supporting document kind, defaulting to "other".
+func ParseSupportingKind(kind string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "":
+		return SupportingKindOther, nil
+	case SupportingKindPortfolio:
+		return SupportingKindPortfolio, nil
+	case SupportingKindCoverLetter, "cover-letter", "coverletter":
+		return SupportingKindCoverLetter, nil
+	case SupportingKindOther:
+		return SupportingKindOther, nil
+	default:
+		return "", fmt.Errorf("unsupported supporting document kind %q", kind)
+	}
+}
+
+func supportingDocumentIDs(docs []SupportingDocument) []string {
+	if len(docs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.DocumentID)
+	}
+	return ids
+}
+
+func marshalSupportingDocuments(docs []SupportingDocument) ([]byte, error) {
+	if docs == nil {
+		docs = []SupportingDocument{}
+	}
+	return json.Marshal(docs)
+}
+
+func decodeSupportingDocuments(raw sql.NullString) []SupportingDocument {
+	if !raw.Valid || strings.TrimSpace(raw.String) == "" {
+		return nil
+	}
+	var docs []SupportingDocument
+	if err := json.Unmarshal([]byte(raw.String), &docs); err != nil || len(docs) == 0 {
+		return nil
+	}
+	return docs
+}
+
+// loadSupportingDocuments extracts text for each supporting document referenced by the analysis.
+func (s *Service) loadSupportingDocuments(ctx context.Context, analysis Analysis) ([]llm.SupportingDocument, error) {
+	if len(analysis.SupportingDocuments) == 0 {
+		return nil, nil
+	}
+	out := make([]llm.SupportingDocument, 0, len(analysis.SupportingDocuments))
+	for _, ref := range analysis.SupportingDocuments {
+		doc, err := s.DocRepo.GetByID(ctx, analysis.UserID, ref.DocumentID)
+		if err != nil {
+			return nil, fmt.Errorf("supporting document lookup id=%s: %w", ref.DocumentID, err)
+		}
+		var text string
+		if doc.ExtractedTextKey != "" {
+			text, err = loadText(ctx, s.Store, doc.ExtractedTextKey)
+		} else {
+			text, err = extract.ExtractText(ctx, s.Store, doc.StorageKey, doc.ExtractionMimeType(), doc.FileName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("supporting document %s mime %s: %w", doc.ID, doc.MimeType, err)
+		}
+		text = strings.TrimSpace(text)
+		if runes := []rune(text); len(runes) > maxSupportingChars {
+			text = string(runes[:maxSupportingChars])
+		}
+		out = append(out, llm.SupportingDocument{
+			DocumentID: doc.ID,
+			Kind:       ref.Kind,
+			FileName:   doc.FileName,
+			Text:       text,
+		})
+	}
+	return out, nil
+}
+
+// attributeEvidence records which document each evidence snippet came from and lists the
+// documents that fed the analysis. Results without supporting documents are left untouched.
+func attributeEvidence(result map[string]any, primaryID, primaryText string, supporting []llm.SupportingDocument) {
+	if result == nil || len(supporting) == 0 {
+		return
+	}
+	sources := []map[string]any{{"documentId": primaryID, "role": "primary"}}
+	for _, doc := range supporting {
+		sources = append(sources, map[string]any{
+			"documentId": doc.DocumentID,
+			"role":       "supporting",
+			"kind":       doc.Kind,
+			"fileName":   doc.FileName,
+		})
+	}
+	result["sources"] = sources
+
+	primaryNorm := normalizeForMatch(primaryText)
+	supportingNorm := make([]string, len(supporting))
+	for i, doc := range supporting {
+		supportingNorm[i] = normalizeForMatch(doc.Text)
+	}
+	locate := func(evidence string) string {
+		snippet := normalizeForMatch(evidence)
+		if snippet == "" || snippet == "notfound" {
+			return ""
+		}
+		if strings.Contains(primaryNorm, snippet) {
+			return primaryID
+		}
+		for i, text := range supportingNorm {
+			if strings.Contains(text, snippet) {
+				return supporting[i].DocumentID
+			}
+		}
+		return ""
+	}
+	for _, key := range []string{"issues", "bulletRewrites"} {
+		items, ok := result[key].([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			entry, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			evidence, _ := entry["evidence"].(string)
+			if source := locate(evidence); source != "" {
+				entry["evidenceDocumentId"] = source
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/storage/object"
+)
+
+func seedSupportingDocument(t *testing.T, repo *documents.MemoryRepo, store object.ObjectStore, userID, id, text string) string {
+	t.Helper()
+	key, _, _, err := store.Save(context.Background(), userID, id+".txt", bytes.NewReader([]byte(text)))
+	if err != nil {
+		t.Fatalf("save supporting text: %v", err)
+	}
+	doc := documents.Document{
+		ID:               id,
+		UserID:           userID,
+		FileName:         id + ".pdf",
+		MimeType:         "application/pdf",
+		StorageKey:       "supporting-key",
+		ExtractedTextKey: key,
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := repo.Create(context.Background(), doc); err != nil {
+		t.Fatalf("create supporting document: %v", err)
+	}
+	return doc.ID
+}
+
+func postAnalyze(t *testing.T, router *gin.Engine, documentID string, payload map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/"+documentID+"/analyze", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestStartAnalysisWithSupportingDocuments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, docRepo, analysisRepo, store, queueStub := setupAnalysisRouter(t)
+	userID := "guest:test-guest"
+	documentID := seedDocument(t, docRepo, store, userID)
+	portfolioID := seedSupportingDocument(t, docRepo, store, userID, "portfolio-1", "Built a payments dashboard")
+
+	resp := postAnalyze(t, router, documentID, map[string]any{
+		"jobDescription": strings.Repeat("a", 300),
+		"supportingDocuments": []map[string]string{
+			{"documentId": portfolioID, "kind": "portfolio"},
+		},
+	})
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		AnalysisID string `json:"analysisId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	analysis, err := analysisRepo.GetByID(context.Background(), created.AnalysisID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if len(analysis.SupportingDocuments) != 1 || analysis.SupportingDocuments[0].Kind != SupportingKindPortfolio {
+		t.Fatalf("unexpected supporting documents: %+v", analysis.SupportingDocuments)
+	}
+	if len(queueStub.messages) != 1 || len(queueStub.messages[0].SupportingDocumentIDs) != 1 || queueStub.messages[0].SupportingDocumentIDs[0] != portfolioID {
+		t.Fatalf("expected supporting ids on queue message, got %+v", queueStub.messages)
+	}
+}
+
+func TestStartAnalysisRejectsInvalidSupportingDocuments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router, docRepo, _, store, queueStub := setupAnalysisRouter(t)
+	userID := "guest:test-guest"
+	documentID := seedDocument(t, docRepo, store, userID)
+	otherID := seedSupportingDocument(t, docRepo, store, userID, "letter-1", "Dear hiring manager")
+
+	cases := []struct {
+		name       string
+		supporting []map[string]string
+		status     int
+	}{
+		{"unknown kind", []map[string]string{{"documentId": otherID, "kind": "transcript"}}, http.StatusBadRequest},
+		{"primary reused", []map[string]string{{"documentId": documentID}}, http.StatusBadRequest},
+		{"missing document", []map[string]string{{"documentId": "nope"}}, http.StatusNotFound},
+		{"too many", []map[string]string{{"documentId": "a"}, {"documentId": "b"}, {"documentId": "c"}, {"documentId": "d"}}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postAnalyze(t, router, documentID, map[string]any{
+				"jobDescription":      strings.Repeat("a", 300),
+				"supportingDocuments": tc.supporting,
+			})
+			if resp.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, resp.Code, resp.Body.String())
+			}
+		})
+	}
+	if len(queueStub.messages) != 0 {
+		t.Fatalf("expected nothing enqueued, got %d", len(queueStub.messages))
+	}
+}
+
+type capturingLLM struct {
+	input llm.AnalyzeInput
+}
+
+func (c *capturingLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	c.input = input
+	return stubLLM{}.AnalyzeResume(ctx, input)
+}
+
+func TestProcessAnalysisPassesSupportingDocumentsToLLM(t *testing.T) {
+	capture := &capturingLLM{}
+	svc, repo, docRepo, docID := setupServiceWithDoc(t, capture)
+	letterID := seedSupportingDocument(t, docRepo, svc.Store, "user-1", "letter-1", "I led the Kafka migration.")
+
+	analysis := Analysis{
+		ID:                  "analysis-supporting",
+		DocumentID:          docID,
+		UserID:              "user-1",
+		JobDescription:      "jd",
+		PromptVersion:       "v1",
+		Status:              StatusQueued,
+		CreatedAt:           time.Now().UTC(),
+		SupportingDocuments: []SupportingDocument{{DocumentID: letterID, Kind: SupportingKindCoverLetter}},
+	}
+	if err := repo.Create(context.Background(), analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(context.Background(), analysis.ID); err != nil {
+		t.Fatalf("process analysis: %v", err)
+	}
+
+	if len(capture.input.SupportingDocuments) != 1 || capture.input.SupportingDocuments[0].Text != "I led the Kafka migration." {
+		t.Fatalf("unexpected supporting input: %+v", capture.input.SupportingDocuments)
+	}
+	got, err := repo.GetByID(context.Background(), analysis.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	sources, ok := got.Result["sources"].([]map[string]any)
+	if !ok || len(sources) != 2 || sources[1]["documentId"] != letterID {
+		t.Fatalf("expected primary and supporting sources, got %#v", got.Result["sources"])
+	}
+}
+
+func TestAttributeEvidenceTagsSourceDocument(t *testing.T) {
+	result := map[string]any{
+		"issues": []any{
+			map[string]any{"evidence": "Led   the Kafka migration"},
+			map[string]any{"evidence": "Python developer"},
+			map[string]any{"evidence": "notFound"},
+		},
+		"bulletRewrites": []any{
+			map[string]any{"evidence": "not in any document"},
+		},
+	}
+	supporting := []llm.SupportingDocument{{DocumentID: "letter-1", Kind: SupportingKindCoverLetter, Text: "I led the Kafka migration in 2023."}}
+
+	attributeEvidence(result, "resume-1", "Senior Python developer", supporting)
+
+	issues := result["issues"].([]any)
+	if issues[0].(map[string]any)["evidenceDocumentId"] != "letter-1" {
+		t.Fatalf("expected cover letter attribution, got %v", issues[0])
+	}
+	if issues[1].(map[string]any)["evidenceDocumentId"] != "resume-1" {
+		t.Fatalf("expected resume attribution, got %v", issues[1])
+	}
+	if _, ok := issues[2].(map[string]any)["evidenceDocumentId"]; ok {
+		t.Fatalf("expected notFound evidence to stay unattributed")
+	}
+	rewrite := result["bulletRewrites"].([]any)[0].(map[string]any)
+	if _, ok := rewrite["evidenceDocumentId"]; ok {
+		t.Fatalf("expected unmatched evidence to stay unattributed")
+	}
+}
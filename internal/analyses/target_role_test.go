@@ -0,0 +1,112 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses/roles"
+	"resume-backend/internal/documents"
+	local "resume-backend/internal/shared/storage/object/local"
+)
+
+func TestStartAnalysisStoresTargetRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, docRepo, analysisRepo, store, _ := setupAnalysisRouter(t)
+	documentID := seedDocument(t, docRepo, store, "guest:test-guest")
+
+	start := func(targetRole string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, _ := json.Marshal(map[string]string{"mode": "ATS", "targetRole": targetRole})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/"+documentID+"/analyze", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addGuestHeader(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := start(strings.Repeat("a", MaxTargetRoleRunes+1)); resp.Code != http.StatusBadRequest {
+		t.Fatalf("long target role: expected 400, got %d", resp.Code)
+	}
+	resp := start("  Senior   iOS Developer ")
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d body %s", resp.Code, resp.Body)
+	}
+	var created struct {
+		AnalysisID string `json:"analysisId"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	analysis, err := analysisRepo.GetByID(context.Background(), created.AnalysisID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	if analysis.TargetRole != "Senior iOS Developer" {
+		t.Fatalf("target role = %q", analysis.TargetRole)
+	}
+}
+
+func TestTargetRoleIsPartOfTheReuseKey(t *testing.T) {
+	ctx := context.Background()
+	svc := &Service{
+		Repo:     NewMemoryRepo(),
+		DocRepo:  documents.NewMemoryRepo(),
+		Store:    local.New(t.TempDir()),
+		LLM:      stubLLM{},
+		JobQueue: &stubQueue{},
+	}
+
+	backend, _, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", "", "v2_3", ModeATS, false, StartOptions{TargetRole: "Backend Engineer"})
+	if err != nil {
+		t.Fatalf("start backend: %v", err)
+	}
+	mobile, created, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", "", "v2_3", ModeATS, false, StartOptions{TargetRole: "iOS Developer"})
+	if err != nil || !created || mobile.ID == backend.ID {
+		t.Fatalf("expected a new analysis for another role, got %+v created=%v err=%v", mobile, created, err)
+	}
+	again, created, err := svc.StartOrReuseWithOptions(ctx, "doc-1", "user-1", "", "v2_3", ModeATS, false, StartOptions{TargetRole: " Backend  Engineer"})
+	if err != nil || created || again.ID != backend.ID {
+		t.Fatalf("expected reuse of %s, got %s created=%v err=%v", backend.ID, again.ID, created, err)
+	}
+}
+
+func TestProcessAnalysisPassesTargetRoleToTheModel(t *testing.T) {
+	fixture := loadFixture(t, "testdata/v2_3_good.json")
+	_, analysisRepo, svc := setupAnalysisRouterWithLLM(t, fixture)
+	client := &recordingLLM{responses: map[string]string{"v2_3": string(fixture)}}
+	svc.LLM = client
+
+	ctx := context.Background()
+	userID := "guest:test-guest"
+	if err := analysisRepo.Create(ctx, Analysis{
+		ID: "a1", DocumentID: "doc-" + userID, UserID: userID, PromptVersion: "v2_3", Mode: ModeATS,
+		TargetRole: "Senior iOS Developer", Status: StatusQueued, CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, "a1"); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	if len(client.inputs) == 0 {
+		t.Fatal("expected the model to be called")
+	}
+	input := client.inputs[0]
+	if input.TargetRole != "Senior iOS Developer" || !slices.Contains(input.TargetRoleKeywords, "Swift") {
+		t.Fatalf("unexpected input role %q keywords %v", input.TargetRole, input.TargetRoleKeywords)
+	}
+	got, _ := analysisRepo.GetByID(ctx, "a1")
+	if category := RoleCategory(got.Result); category != roles.Mobile {
+		t.Fatalf("role category = %q", category)
+	}
+}
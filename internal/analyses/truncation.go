@@ -0,0 +1,356 @@
+package analyses
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"resume-backend/internal/llm"
+)
+
+const (
+	// DefaultInputTokenLimit bounds the resume and job description text sent to
+	// the LLM when Service.InputTokenLimit is unset.
+	DefaultInputTokenLimit = 24000
+
+	// TruncationMarker replaces text cut from an input so the model, and anyone
+	// reading the prompt, can tell something was left out.
+	TruncationMarker = "[truncated]"
+
+	// charsPerToken converts token budgets to characters; English text averages
+	// about four characters per token.
+	charsPerToken = 4
+	// reservedPromptTokens is kept free of resume and job description text for
+	// the prompt template and the model's answer.
+	reservedPromptTokens = 8000
+	// jdBudgetShare is the largest share of the input budget the job
+	// description may take; the resume gets the rest.
+	jdBudgetShare = 3
+	// minLineKeep is how much of a long line must remain for it to be cut
+	// mid-line rather than dropped.
+	minLineKeep = 80
+	// truncatedLimitation is in every meta.limitations entry withTruncation adds.
+	truncatedLimitation = "was truncated to fit the model context"
+)
+
+// modelContextWindows lists context windows in tokens by model name prefix,
+// most specific first.
+var modelContextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-5", 400000},
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+}
+
+// Section priorities for truncation. Sections with a higher number are cut
+// first; the header with the candidate's name and contacts is cut last.
+const (
+	priorityHeader = iota
+	priorityCore
+	prioritySummary
+	priorityCredentials
+	priorityOther
+	priorityIncidental
+)
+
+var sectionPriorities = []struct {
+	keyword  string
+	priority int
+}{
+	{"reference", priorityIncidental},
+	{"interest", priorityIncidental},
+	{"hobbies", priorityIncidental},
+	{"skill", priorityCore},
+	{"experience", priorityCore},
+	{"employment", priorityCore},
+	{"work history", priorityCore},
+	{"summary", prioritySummary},
+	{"profile", prioritySummary},
+	{"objective", prioritySummary},
+	{"education", priorityCredentials},
+	{"certification", priorityCredentials},
+	{"license", priorityCredentials},
+}
+
+// InputTruncation describes how one input was cut to fit the budget.
+type InputTruncation struct {
+	// Input is "resume" or "jobDescription".
+	Input         string
+	OriginalChars int
+	KeptChars     int
+	// Sections names the resume sections that were shortened or removed, in
+	// the order they appear.
+	Sections []string
+}
+
+// limitation is the meta.limitations entry for t.
+func (t InputTruncation) limitation() string {
+	name := "resume text"
+	if t.Input == "jobDescription" {
+		name = "job description"
+	}
+	msg := fmt.Sprintf("%s %s (%d of %d characters kept", name, truncatedLimitation, t.KeptChars, t.OriginalChars)
+	if len(t.Sections) > 0 {
+		msg += "; shortened: " + strings.Join(t.Sections, ", ")
+	}
+	return msg + "); removed text is marked " + TruncationMarker
+}
+
+// inputBudget returns how many characters of resume and job description text
+// fit in one request: InputTokenLimit, further capped by the model's context
+// window less the room kept for the prompt and answer.
+func (s *Service) inputBudget() int {
+	limit := s.InputTokenLimit
+	if limit <= 0 {
+		limit = DefaultInputTokenLimit
+	}
+	if window, ok := contextWindowTokens(s.Model); ok && window-reservedPromptTokens < limit {
+		limit = window - reservedPromptTokens
+	}
+	return limit * charsPerToken
+}
+
+func contextWindowTokens(model string) (int, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	for _, entry := range modelContextWindows {
+		if strings.HasPrefix(model, entry.prefix) {
+			return entry.tokens, true
+		}
+	}
+	return 0, false
+}
+
+// fitInput truncates the resume and job description of input to the service's
+// budget. The job description may take up to a third of the budget; the
+// resume is cut section by section, least important first. Truncation is
+// deterministic, so the same inputs always produce the same prompt.
+func (s *Service) fitInput(input *llm.AnalyzeInput) []InputTruncation {
+	budget := s.inputBudget()
+	var out []InputTruncation
+
+	jdBudget := budget / jdBudgetShare
+	jd, jdCut := truncateTail(input.JobDescription, jdBudget)
+	if jdCut {
+		out = append(out, InputTruncation{
+			Input:         "jobDescription",
+			OriginalChars: utf8.RuneCountInString(input.JobDescription),
+			KeptChars:     utf8.RuneCountInString(jd),
+		})
+		input.JobDescription = jd
+	}
+
+	resumeBudget := budget - min(utf8.RuneCountInString(input.JobDescription), jdBudget)
+	if resume, sections, cut := truncateResume(input.ResumeText, resumeBudget); cut {
+		out = append(out, InputTruncation{
+			Input:         "resume",
+			OriginalChars: utf8.RuneCountInString(input.ResumeText),
+			KeptChars:     utf8.RuneCountInString(resume),
+			Sections:      sections,
+		})
+		input.ResumeText = resume
+	}
+	return out
+}
+
+// withTruncation notes each truncated input in meta.limitations.
+func withTruncation(result map[string]any, truncations []InputTruncation) {
+	for _, t := range truncations {
+		addLimitation(result, t.limitation())
+	}
+}
+
+// withStoredTruncation copies the truncation notes of a stored result onto a
+// replayed one, since the replay does not rebuild the prompt.
+func withStoredTruncation(result, stored map[string]any) {
+	meta, _ := stored["meta"].(map[string]any)
+	var limitations []string
+	switch items := meta["limitations"].(type) {
+	case []string:
+		limitations = items
+	case []any:
+		for _, item := range items {
+			if text, ok := item.(string); ok {
+				limitations = append(limitations, text)
+			}
+		}
+	}
+	for _, text := range limitations {
+		if strings.Contains(text, truncatedLimitation) {
+			addLimitation(result, text)
+		}
+	}
+}
+
+type resumeSection struct {
+	name     string
+	heading  string
+	body     []string
+	priority int
+	cut      bool
+}
+
+func (sec resumeSection) runes() int {
+	n := 0
+	if sec.heading != "" {
+		n += utf8.RuneCountInString(sec.heading) + 1
+	}
+	for _, line := range sec.body {
+		n += utf8.RuneCountInString(line) + 1
+	}
+	return n
+}
+
+// truncateResume cuts text to at most budget characters. Whole sections are
+// shortened from the end, lowest priority first and later sections before
+// earlier ones, and each cut ends with a TruncationMarker line. It returns the
+// names of the shortened sections in resume order.
+func truncateResume(text string, budget int) (string, []string, bool) {
+	total := utf8.RuneCountInString(text)
+	if total <= budget {
+		return text, nil, false
+	}
+
+	sections := splitResumeSections(text)
+	order := make([]int, len(sections))
+	for i := range order {
+		order[i] = i
+	}
+	// Lowest priority first; later sections first within a priority.
+	sort.Slice(order, func(a, b int) bool {
+		sa, sb := sections[order[a]], sections[order[b]]
+		if sa.priority != sb.priority {
+			return sa.priority > sb.priority
+		}
+		return order[a] > order[b]
+	})
+
+	size := 0
+	for _, sec := range sections {
+		size += sec.runes()
+	}
+	markerCost := utf8.RuneCountInString(TruncationMarker) + 1
+	for _, i := range order {
+		if size <= budget {
+			break
+		}
+		sec := &sections[i]
+		if len(sec.body) == 0 {
+			continue
+		}
+		before := sec.runes()
+		excess := size - budget + markerCost
+		for excess > 0 && len(sec.body) > 0 {
+			last := sec.body[len(sec.body)-1]
+			n := utf8.RuneCountInString(last)
+			if n-excess >= minLineKeep {
+				kept := cutAtSpace(last, n-excess)
+				sec.body[len(sec.body)-1] = kept
+				excess -= n - utf8.RuneCountInString(kept)
+				continue
+			}
+			sec.body = sec.body[:len(sec.body)-1]
+			excess -= n + 1
+		}
+		sec.body = append(sec.body, TruncationMarker)
+		sec.cut = true
+		size += sec.runes() - before
+	}
+
+	var b strings.Builder
+	var names []string
+	for _, sec := range sections {
+		if sec.heading != "" {
+			b.WriteString(sec.heading)
+			b.WriteByte('\n')
+		}
+		for _, line := range sec.body {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+		if sec.cut {
+			names = append(names, sec.name)
+		}
+	}
+	out := strings.TrimSuffix(b.String(), "\n")
+	if utf8.RuneCountInString(out) > budget {
+		// Headings alone still do not fit.
+		out, _ = truncateTail(out, budget)
+	}
+	return out, names, true
+}
+
+// splitResumeSections splits text at recognized section headings. Text before
+// the first heading is the header section.
+func splitResumeSections(text string) []resumeSection {
+	sections := []resumeSection{{name: "Header", priority: priorityHeader}}
+	for _, line := range strings.Split(text, "\n") {
+		if priority, ok := truncationHeading(line); ok {
+			sections = append(sections, resumeSection{
+				name:     strings.TrimSuffix(strings.TrimSpace(line), ":"),
+				heading:  line,
+				priority: priority,
+			})
+			continue
+		}
+		last := &sections[len(sections)-1]
+		last.body = append(last.body, line)
+	}
+	return sections
+}
+
+// truncationHeading reports whether line starts a section and how soon that
+// section is cut.
+func truncationHeading(line string) (int, bool) {
+	if len(strings.Fields(line)) > 4 {
+		return 0, false
+	}
+	key := sectionKey(line)
+	for _, entry := range sectionPriorities {
+		if strings.Contains(key, entry.keyword) {
+			return entry.priority, true
+		}
+	}
+	if isKnownSectionHeading(line) {
+		return priorityOther, true
+	}
+	return 0, false
+}
+
+// truncateTail keeps the start of text, cut at a line break when one is
+// close, and appends a TruncationMarker line.
+func truncateTail(text string, budget int) (string, bool) {
+	if utf8.RuneCountInString(text) <= budget {
+		return text, false
+	}
+	keep := budget - utf8.RuneCountInString(TruncationMarker) - 1
+	if keep <= 0 {
+		return TruncationMarker, true
+	}
+	head := string([]rune(text)[:keep])
+	if i := strings.LastIndexByte(head, '\n'); i >= len(head)*3/4 {
+		head = head[:i]
+	} else {
+		head = cutAtSpace(head, utf8.RuneCountInString(head))
+	}
+	return strings.TrimRight(head, " \t\n") + "\n" + TruncationMarker, true
+}
+
+// cutAtSpace returns the first keep runes of line, backed off to the last
+// space when there is one in the final quarter.
+func cutAtSpace(line string, keep int) string {
+	runes := []rune(line)
+	if keep >= len(runes) {
+		return line
+	}
+	head := string(runes[:keep])
+	if i := strings.LastIndexByte(head, ' '); i >= len(head)*3/4 {
+		head = head[:i]
+	}
+	return head
+}
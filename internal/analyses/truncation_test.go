@@ -0,0 +1,142 @@
+package analyses
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func longResume() string {
+	var b strings.Builder
+	b.WriteString("Jane Doe\njane@example.com\n\nExperience\n")
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&b, "- Built billing service %d handling 2M requests per day\n", i)
+	}
+	b.WriteString("Projects\n")
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&b, "- Side project %d, a small tool for parsing spreadsheets\n", i)
+	}
+	b.WriteString("Skills\nGo, Postgres, Kafka\nReferences\n")
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&b, "Reference %d, former manager, available on request\n", i)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func TestTruncateResumeCutsLowPrioritySectionsFirst(t *testing.T) {
+	text := longResume()
+	budget := utf8.RuneCountInString(text) - 200
+
+	out, sections, cut := truncateResume(text, budget)
+	if !cut || utf8.RuneCountInString(out) > budget {
+		t.Fatalf("expected text cut to %d chars, got %d", budget, utf8.RuneCountInString(out))
+	}
+	if strings.Join(sections, ",") != "References" {
+		t.Fatalf("expected only references cut, got %v", sections)
+	}
+	if !strings.HasSuffix(out, "\n"+TruncationMarker) || strings.Contains(out, "Reference 5") {
+		t.Fatalf("expected the end of references replaced by a marker:\n%s", out)
+	}
+	for _, keep := range []string{"Jane Doe", "billing service 5", "Side project 5", "Go, Postgres, Kafka"} {
+		if !strings.Contains(out, keep) {
+			t.Fatalf("expected %q to survive:\n%s", keep, out)
+		}
+	}
+
+	// A tighter budget shortens projects next, still leaving experience and skills.
+	out, sections, _ = truncateResume(text, utf8.RuneCountInString(text)-550)
+	if strings.Join(sections, ",") != "Projects,References" {
+		t.Fatalf("expected projects and references cut, got %v", sections)
+	}
+	if !strings.Contains(out, "billing service 5") || !strings.Contains(out, "Go, Postgres, Kafka") {
+		t.Fatalf("expected experience and skills intact:\n%s", out)
+	}
+	again, _, _ := truncateResume(text, utf8.RuneCountInString(text)-550)
+	if again != out {
+		t.Fatal("truncation should be deterministic")
+	}
+
+	if out, _, cut := truncateResume(text, len(text)); cut || out != text {
+		t.Fatal("text within budget should be left alone")
+	}
+}
+
+func TestTruncateResumeWithoutHeadingsCutsTheTail(t *testing.T) {
+	text := strings.Repeat("word ", 400)
+	out, sections, cut := truncateResume(text, 500)
+	if !cut || utf8.RuneCountInString(out) > 500 || !strings.HasSuffix(out, TruncationMarker) {
+		t.Fatalf("unexpected cut (%d chars): %q", utf8.RuneCountInString(out), out)
+	}
+	if len(sections) != 1 || sections[0] != "Header" {
+		t.Fatalf("sections = %v", sections)
+	}
+}
+
+func TestInputBudgetHonorsTheModelContextWindow(t *testing.T) {
+	svc := &Service{Model: "gpt-4-0613"}
+	if got := svc.inputBudget(); got != (8192-reservedPromptTokens)*charsPerToken {
+		t.Fatalf("gpt-4 budget = %d", got)
+	}
+	svc = &Service{Model: "gpt-4o-mini", InputTokenLimit: 1000}
+	if got := svc.inputBudget(); got != 1000*charsPerToken {
+		t.Fatalf("configured budget = %d", got)
+	}
+	svc = &Service{Model: "unknown-model"}
+	if got := svc.inputBudget(); got != DefaultInputTokenLimit*charsPerToken {
+		t.Fatalf("default budget = %d", got)
+	}
+}
+
+func TestProcessAnalysisRecordsTruncationInLimitations(t *testing.T) {
+	ctx := context.Background()
+	store := local.New(t.TempDir())
+	key, _, _, err := store.Save(ctx, "user-1", "resume.txt", bytes.NewReader([]byte(longResume())))
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	capture := &capturingLLM{}
+	svc, repo, _, docID := setupServiceWithDocAndStore(t, capture, store, key)
+	svc.InputTokenLimit = 150
+
+	analysis := Analysis{
+		ID:             "analysis-truncated",
+		DocumentID:     docID,
+		UserID:         "user-1",
+		JobDescription: strings.Repeat("Must know Go and Kafka.\n", 40),
+		PromptVersion:  "v1",
+		Status:         StatusQueued,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := repo.Create(ctx, analysis); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	if err := svc.ProcessAnalysis(ctx, analysis.ID); err != nil {
+		t.Fatalf("process analysis: %v", err)
+	}
+
+	if !strings.HasSuffix(capture.input.JobDescription, TruncationMarker) || !strings.Contains(capture.input.ResumeText, TruncationMarker) {
+		t.Fatalf("expected markers in both inputs, got jd=%q resume=%q", capture.input.JobDescription, capture.input.ResumeText)
+	}
+	if total := utf8.RuneCountInString(capture.input.JobDescription) + utf8.RuneCountInString(capture.input.ResumeText); total > 150*charsPerToken {
+		t.Fatalf("inputs exceed the budget: %d chars", total)
+	}
+
+	got, err := repo.GetByID(ctx, analysis.ID)
+	if err != nil {
+		t.Fatalf("get analysis: %v", err)
+	}
+	meta, _ := got.Result["meta"].(map[string]any)
+	limitations := fmt.Sprint(meta["limitations"])
+	if !strings.Contains(limitations, "job description "+truncatedLimitation) || !strings.Contains(limitations, "resume text "+truncatedLimitation) {
+		t.Fatalf("expected truncation limitations, got %s", limitations)
+	}
+	if !strings.Contains(limitations, "References") {
+		t.Fatalf("expected the cut sections to be named, got %s", limitations)
+	}
+}
@@ -8,28 +8,76 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 
+	"resume-backend/internal/analyses/scoring"
 	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/language"
 )
 
 const contentRepairSystemMessage = "Remove any unsupported impact claims (e.g., double-digit, significant) unless explicitly stated in resume. Never use \"double-digit\" unless it appears verbatim in resume evidence. If an exact value is missing, replace with placeholder \"X% (replace with exact figure)\", set claimSupport=placeholder, metricsSource=placeholder, and add placeholdersNeeded (e.g., revenue_growth_pct). Keep JSON only."
 
-var forbiddenImpactTerms = []string{
+// DefaultForbiddenImpactTerms are the vague impact claims a rewrite may only make
+// with a placeholder or resume evidence.
+var DefaultForbiddenImpactTerms = []string{
 	"double-digit",
 	"double digit",
 	"significant",
+	"significantly",
 	"substantial",
+	"substantially",
 	"massive",
+	"massively",
 	"remarkable",
+	"remarkably",
 }
 
-// ValidateContentV2_2 enforces content guardrails for v2_2 outputs.
+var (
+	guardrailMu          sync.RWMutex
+	forbiddenImpactTerms = DefaultForbiddenImpactTerms
+)
+
+// SetForbiddenImpactTerms replaces the guardrail term list used by content
+// validation and sanitization. Nil restores DefaultForbiddenImpactTerms; an
+// empty list disables the check.
+func SetForbiddenImpactTerms(terms []string) {
+	if terms == nil {
+		terms = DefaultForbiddenImpactTerms
+	}
+	normalized := make([]string, 0, len(terms))
+	seen := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		term = normalizeForMatch(term)
+		if _, dup := seen[term]; term == "" || dup {
+			continue
+		}
+		seen[term] = struct{}{}
+		normalized = append(normalized, term)
+	}
+	guardrailMu.Lock()
+	forbiddenImpactTerms = normalized
+	guardrailMu.Unlock()
+}
+
+func currentForbiddenImpactTerms() []string {
+	guardrailMu.RLock()
+	defer guardrailMu.RUnlock()
+	return forbiddenImpactTerms
+}
+
+// ValidateContentV2_2 enforces the English content guardrails for v2_2
+// outputs.
 func ValidateContentV2_2(r *AnalysisResultV2_2) error {
+	return validateContentV2_2(r, guardrailFor(language.English))
+}
+
+func validateContentV2_2(r *AnalysisResultV2_2, g contentGuardrail) error {
 	if r == nil {
 		return errors.New("analysis result is nil")
 	}
 	for i, br := range r.BulletRewrites {
-		if term, ok := containsForbiddenTerm(br.After); ok {
+		if term, ok := g.find(br.After); ok {
 			switch strings.ToLower(strings.TrimSpace(br.MetricsSource)) {
 			case "resume":
 				return fmt.Errorf("bulletRewrites[%d].after contains unsupported term %q", i, term)
@@ -43,13 +91,18 @@ func ValidateContentV2_2(r *AnalysisResultV2_2) error {
 	return nil
 }
 
-// ValidateContentV2_3 enforces content guardrails for v2_3 outputs.
+// ValidateContentV2_3 enforces the English content guardrails for v2_3
+// outputs.
 func ValidateContentV2_3(r *AnalysisResultV2_3) error {
+	return validateContentV2_3(r, guardrailFor(language.English))
+}
+
+func validateContentV2_3(r *AnalysisResultV2_3, g contentGuardrail) error {
 	if r == nil {
 		return errors.New("analysis result is nil")
 	}
 	for i, br := range r.BulletRewrites {
-		if term, ok := containsForbiddenTerm(br.After); ok {
+		if term, ok := g.find(br.After); ok {
 			switch strings.ToLower(strings.TrimSpace(br.MetricsSource)) {
 			case "resume":
 				return fmt.Errorf("bulletRewrites[%d].after contains unsupported term %q", i, term)
@@ -63,8 +116,10 @@ func ValidateContentV2_3(r *AnalysisResultV2_3) error {
 	return nil
 }
 
-// ValidateV2_2WithRetry validates v2_2 schema and content guardrails with one retry.
+// ValidateV2_2WithRetry validates v2_2 schema and content guardrails with one
+// retry. The guardrail follows the language of the resume.
 func ValidateV2_2WithRetry(ctx context.Context, client llm.Client, input llm.AnalyzeInput) (rawJSON []byte, err error) {
+	g := guardrailForText(input.ResumeText)
 	raw, err := client.AnalyzeResume(ctx, input)
 	if err != nil {
 		return nil, err
@@ -73,9 +128,9 @@ func ValidateV2_2WithRetry(ctx context.Context, client llm.Client, input llm.Ana
 	if err := parseAndValidateV2_2(raw, &parsed); err != nil {
 		return nil, err
 	}
-	if err := ValidateContentV2_2(&parsed); err != nil {
+	if err := validateContentV2_2(&parsed, g); err != nil {
 		log.Printf("v2_2 content attempt=1 error=%s", sanitizeError(err))
-		ctxRetry := llm.WithExtraSystemMessage(ctx, contentRepairSystemMessage)
+		ctxRetry := ctxmeta.WithExtraSystemMessage(ctx, g.repairMessage)
 		rawRetry, retryErr := client.AnalyzeResume(ctxRetry, input)
 		if retryErr != nil {
 			return nil, retryErr
@@ -83,7 +138,7 @@ func ValidateV2_2WithRetry(ctx context.Context, client llm.Client, input llm.Ana
 		if err := parseAndValidateV2_2(rawRetry, &parsed); err != nil {
 			return nil, err
 		}
-		if err := ValidateContentV2_2(&parsed); err != nil {
+		if err := validateContentV2_2(&parsed, g); err != nil {
 			log.Printf("v2_2 content attempt=2 error=%s", sanitizeError(err))
 			return nil, err
 		}
@@ -92,8 +147,11 @@ func ValidateV2_2WithRetry(ctx context.Context, client llm.Client, input llm.Ana
 	return raw, nil
 }
 
-// ValidateV2_3WithRetry validates v2_3 schema and content guardrails with one retry.
+// ValidateV2_3WithRetry validates v2_3 schema and content guardrails with one
+// retry. The guardrail follows the language of the resume. The output is
+// checked against, and returned with, the current scoring version.
 func ValidateV2_3WithRetry(ctx context.Context, client llm.Client, input llm.AnalyzeInput) (rawJSON []byte, err error) {
+	g := guardrailForText(input.ResumeText)
 	raw, err := client.AnalyzeResume(ctx, input)
 	if err != nil {
 		return nil, err
@@ -102,13 +160,14 @@ func ValidateV2_3WithRetry(ctx context.Context, client llm.Client, input llm.Ana
 	if err := json.Unmarshal(raw, &parsed); err != nil {
 		return nil, err
 	}
+	parsed.ATS.ScoreExplanation.ScoringVersion = scoring.CurrentVersion
 	SanitizeV2_3(&parsed)
 	if err := parsed.Validate(); err != nil {
 		return nil, err
 	}
-	if err := ValidateContentV2_3(&parsed); err != nil {
+	if err := validateContentV2_3(&parsed, g); err != nil {
 		log.Printf("v2_3 content attempt=1 error=%s", sanitizeError(err))
-		ctxRetry := llm.WithExtraSystemMessage(ctx, contentRepairSystemMessage)
+		ctxRetry := ctxmeta.WithExtraSystemMessage(ctx, g.repairMessage)
 		rawRetry, retryErr := client.AnalyzeResume(ctxRetry, input)
 		if retryErr != nil {
 			return nil, retryErr
@@ -116,18 +175,20 @@ func ValidateV2_3WithRetry(ctx context.Context, client llm.Client, input llm.Ana
 		if err := json.Unmarshal(rawRetry, &parsed); err != nil {
 			return nil, err
 		}
+		parsed.ATS.ScoreExplanation.ScoringVersion = scoring.CurrentVersion
 		SanitizeV2_3(&parsed)
 		if err := parsed.Validate(); err != nil {
 			return nil, err
 		}
-		if err := ValidateContentV2_3(&parsed); err != nil {
+		if err := validateContentV2_3(&parsed, g); err != nil {
 			log.Printf("v2_3 content attempt=2 error=%s", sanitizeError(err))
-			changed, _ := sanitizeBulletRewriteTerms(&parsed)
+			changed, _ := sanitizeBulletRewriteTerms(&parsed, g)
 			if changed {
 				if err := parsed.Validate(); err != nil {
 					return nil, err
 				}
-				if err := ValidateContentV2_3(&parsed); err == nil {
+				if err := validateContentV2_3(&parsed, g); err == nil {
+					ctxmeta.MarkSanitized(ctx)
 					payload, marshalErr := json.Marshal(parsed)
 					if marshalErr != nil {
 						return nil, marshalErr
@@ -137,9 +198,8 @@ func ValidateV2_3WithRetry(ctx context.Context, client llm.Client, input llm.Ana
 			}
 			return nil, err
 		}
-		return rawRetry, nil
 	}
-	return raw, nil
+	return json.Marshal(parsed)
 }
 
 func parseAndValidateV2_2(raw []byte, out *AnalysisResultV2_2) error {
@@ -160,17 +220,13 @@ func parseAndValidateV2_3(raw []byte, out *AnalysisResultV2_3) error {
 	return out.Validate()
 }
 
-func containsForbiddenTerm(text string) (string, bool) {
-	lower := normalizeForMatch(text)
-	for _, term := range forbiddenImpactTerms {
-		if strings.Contains(lower, term) {
-			return term, true
-		}
-	}
-	return "", false
+// ForbiddenImpactTerm reports the first guardrail term text contains, so other
+// rewrite features apply the same list as analyses.
+func ForbiddenImpactTerm(text string) (string, bool) {
+	return guardrailFor(language.English).find(text)
 }
 
-func sanitizeBulletRewriteTerms(r *AnalysisResultV2_3) (bool, []string) {
+func sanitizeBulletRewriteTerms(r *AnalysisResultV2_3, g contentGuardrail) (bool, []string) {
 	if r == nil {
 		return false, nil
 	}
@@ -181,7 +237,7 @@ func sanitizeBulletRewriteTerms(r *AnalysisResultV2_3) (bool, []string) {
 		if after == "" {
 			continue
 		}
-		updated, replacements := replaceForbiddenTerms(after)
+		updated, replacements := g.replace(after)
 		if len(replacements) == 0 {
 			continue
 		}
@@ -202,20 +258,14 @@ func sanitizeBulletRewriteTerms(r *AnalysisResultV2_3) (bool, []string) {
 	return changed, notes
 }
 
-func replaceForbiddenTerms(input string) (string, []string) {
-	replacements := map[string]string{
-		"double-digit": "X% (replace with exact figure)",
-		"double digit": "X% (replace with exact figure)",
-		"significant":  "measurable",
-		"substantial":  "measurable",
-		"massive":      "measurable",
-		"remarkable":   "measurable",
-	}
+// replace rewrites every guardrail term in input and lists the replacements.
+func (g contentGuardrail) replace(input string) (string, []string) {
 	updated := input
 	normalized := normalizeForMatch(updated)
 	var applied []string
-	for term, repl := range replacements {
-		if strings.Contains(normalized, term) {
+	for _, term := range g.terms {
+		repl := g.replacement(term)
+		if indexWord(normalized, term) >= 0 {
 			for _, variant := range termVariants(term) {
 				updated = replaceInsensitive(updated, variant, repl)
 			}
@@ -249,9 +299,9 @@ func termVariants(term string) []string {
 
 func replaceInsensitive(input, term, replacement string) string {
 	out := input
-	out = strings.ReplaceAll(out, term, replacement)
-	out = strings.ReplaceAll(out, strings.ToUpper(term), replacement)
-	out = strings.ReplaceAll(out, strings.Title(term), replacement)
+	out = replaceWord(out, term, replacement)
+	out = replaceWord(out, strings.ToUpper(term), replacement)
+	out = replaceWord(out, strings.Title(term), replacement)
 	return out
 }
 
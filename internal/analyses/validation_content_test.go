@@ -3,6 +3,8 @@ package analyses
 import (
 	"strings"
 	"testing"
+
+	"resume-backend/internal/shared/language"
 )
 
 func TestValidateContentV2_2RejectsUnsupportedClaim(t *testing.T) {
@@ -106,7 +108,7 @@ func TestSanitizeBulletRewriteTermsReplacesForbiddenTerms(t *testing.T) {
 		},
 	}
 
-	changed, _ := sanitizeBulletRewriteTerms(&r)
+	changed, _ := sanitizeBulletRewriteTerms(&r, guardrailFor(language.English))
 	if !changed {
 		t.Fatalf("expected sanitizer to change bullet rewrite")
 	}
@@ -168,7 +170,7 @@ func TestSanitizeBulletRewriteTermsNoChange(t *testing.T) {
 		},
 	}
 
-	changed, _ := sanitizeBulletRewriteTerms(&r)
+	changed, _ := sanitizeBulletRewriteTerms(&r, guardrailFor(language.English))
 	if changed {
 		t.Fatalf("expected sanitizer to leave bullet rewrite unchanged")
 	}
@@ -7,6 +7,7 @@ import (
 	"log"
 
 	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
 )
 
 const v2RepairSystemMessage = "Fix the JSON to satisfy all schema constraints. Keep content same, but ensure ats.scoreBreakdown integers sum to 100. Output JSON only."
@@ -23,7 +24,7 @@ func ValidateV2WithRetry(ctx context.Context, client llm.Client, input llm.Analy
 		log.Printf("v2 validation attempt=1 error=%s", sanitizeError(err))
 	}
 
-	ctxRetry := llm.WithExtraSystemMessage(ctx, v2RepairSystemMessage)
+	ctxRetry := ctxmeta.WithExtraSystemMessage(ctx, v2RepairSystemMessage)
 	rawRetry, err := client.AnalyzeResume(ctxRetry, input)
 	if err != nil {
 		return nil, err
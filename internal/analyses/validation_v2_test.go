@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
 )
 
 type mockLLM struct {
@@ -56,7 +57,7 @@ func TestValidateV2WithRetry(t *testing.T) {
 	if mock.calls != 2 {
 		t.Fatalf("expected 2 calls, got %d", mock.calls)
 	}
-	if msg, ok := llm.ExtraSystemMessageFromContext(mock.lastCtx); !ok || !strings.Contains(msg, "Fix the JSON") {
+	if msg, ok := ctxmeta.ExtraSystemMessage(mock.lastCtx); !ok || !strings.Contains(msg, "Fix the JSON") {
 		t.Fatalf("expected retry context to include extra system message")
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"resume-backend/internal/generatedresumes"
+	"resume-backend/resume/render"
 )
 
 // GeneratedResumeResponse is the outward-facing representation of a generated resume.
@@ -15,6 +16,8 @@ type GeneratedResumeResponse struct {
 	MimeType          string    `json:"mimeType"`
 	SizeBytes         int64     `json:"sizeBytes"`
 	CreatedAt         time.Time `json:"createdAt"`
+
+	RenderWarnings []render.ContentWarning `json:"renderWarnings,omitempty"`
 }
 
 func toGeneratedResumeResponse(resume generatedresumes.GeneratedResume) GeneratedResumeResponse {
@@ -26,5 +29,6 @@ func toGeneratedResumeResponse(resume generatedresumes.GeneratedResume) Generate
 		MimeType:          resume.MimeType,
 		SizeBytes:         resume.SizeBytes,
 		CreatedAt:         resume.CreatedAt,
+		RenderWarnings:    resume.RenderWarnings,
 	}
 }
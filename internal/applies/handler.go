@@ -6,14 +6,25 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"resume-backend/internal/artifacts"
+	"resume-backend/internal/events"
 	"resume-backend/internal/generatedresumes"
 	"resume-backend/internal/shared/server/middleware"
 	"resume-backend/internal/shared/server/respond"
 	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/usage"
 	"resume-backend/resume/contract"
+	"resume-backend/resume/export"
+)
+
+// Formats of GET /generated-resumes/:id/download.
+const (
+	downloadFormatDOCX = "docx"
+	downloadFormatHTML = "html"
 )
 
 // Handler wires HTTP handlers to the apply service.
@@ -21,6 +32,12 @@ type Handler struct {
 	Svc           *Service
 	GeneratedRepo generatedresumes.Repo
 	Store         object.ObjectStore
+	// Downloads audits generated resume downloads when set.
+	Downloads *artifacts.Service
+	// Events receives funnel analytics; nil disables them.
+	Events *events.Emitter
+	// Usage writes X-Usage-Remaining on apply responses; nil leaves it out.
+	Usage *usage.Service
 }
 
 // NewHandler constructs a Handler.
@@ -85,6 +102,8 @@ func (h *Handler) apply(c *gin.Context) {
 		return
 	}
 
+	h.Events.TrackFirst(c.Request.Context(), events.FirstApply, userID, map[string]any{"template_id": resume.TemplateID})
+	h.Usage.WriteRemainingHeader(c.Request.Context(), c, userID, "")
 	respond.JSON(c, http.StatusCreated, toGeneratedResumeResponse(resume))
 }
 
@@ -177,6 +196,11 @@ func (h *Handler) download(c *gin.Context) {
 		respond.Error(c, http.StatusBadRequest, "validation_error", "generated resume id is required", nil)
 		return
 	}
+	format := strings.ToLower(c.DefaultQuery("format", downloadFormatDOCX))
+	if format != downloadFormatDOCX && format != downloadFormatHTML {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "unsupported format", gin.H{"supported": []string{downloadFormatDOCX, downloadFormatHTML}})
+		return
+	}
 
 	resume, err := h.GeneratedRepo.GetByID(c.Request.Context(), userID, resumeID)
 	if err != nil {
@@ -193,6 +217,11 @@ func (h *Handler) download(c *gin.Context) {
 		return
 	}
 
+	if format == downloadFormatHTML {
+		h.downloadHTML(c, userID, resume)
+		return
+	}
+
 	reader, err := h.Store.Open(c.Request.Context(), resume.StorageKey)
 	if err != nil {
 		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load generated resume", nil)
@@ -206,10 +235,44 @@ func (h *Handler) download(c *gin.Context) {
 		return
 	}
 
+	h.recordDownload(c, userID, resume)
 	c.Header("Content-Disposition", "attachment; filename=\"generated_resume.docx\"")
 	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", data)
 }
 
+// downloadHTML renders the resume's stored model as email-safe HTML, for
+// pasting into email bodies and application forms.
+func (h *Handler) downloadHTML(c *gin.Context, userID string, resume generatedresumes.GeneratedResume) {
+	if resume.ModelKey == "" {
+		respond.Error(c, http.StatusConflict, "conflict", "this resume was generated before HTML export; generate it again", nil)
+		return
+	}
+	resumeModel, err := generatedresumes.LoadModel(c.Request.Context(), h.Store, resume.ModelKey)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load generated resume", nil)
+		return
+	}
+	data, err := export.ToHTML(resumeModel)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to render generated resume", nil)
+		return
+	}
+
+	h.recordDownload(c, userID, resume)
+	c.Header("Content-Disposition", "attachment; filename=\"generated_resume.html\"")
+	c.Data(http.StatusOK, export.HTMLContentType, data)
+}
+
+func (h *Handler) recordDownload(c *gin.Context, userID string, resume generatedresumes.GeneratedResume) {
+	if h.Downloads != nil {
+		h.Downloads.RecordSessionDownload(c.Request.Context(), artifacts.Artifact{
+			Type:        artifacts.ArtifactGeneratedResume,
+			ID:          resume.ID,
+			OwnerUserID: resume.UserID,
+		}, userID, c.ClientIP())
+	}
+}
+
 func decodeOptionalJSON(body io.ReadCloser, out any) error {
 	if body == nil {
 		return nil
@@ -16,6 +16,7 @@ import (
 	"resume-backend/internal/generatedresumes"
 	"resume-backend/internal/shared/storage/object"
 	"resume-backend/internal/shared/storage/object/local"
+	"resume-backend/resume/model"
 )
 
 func TestGeneratedResumeDownloadGuestOwn(t *testing.T) {
@@ -124,6 +125,62 @@ func TestGeneratedResumeDownloadUserOwn(t *testing.T) {
 	}
 }
 
+func TestGeneratedResumeDownloadHTML(t *testing.T) {
+	router, genRepo, store := newDownloadRouter(t, "user-1", false)
+	resume := seedGeneratedResume(t, genRepo, store, "user-1", "resume-html")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/generated-resumes/"+resume.ID+"/download?format=html", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 without a stored model, got %d", resp.Code)
+	}
+
+	modelKey, err := generatedresumes.SaveModel(context.Background(), store, "user-1", "template-1", model.ResumeModel{
+		Header:     model.ResumeHeader{Name: "Jane Doe", Email: "jane@example.com"},
+		Experience: []model.ResumeExperience{{Role: "Engineer", Company: "Acme", Highlights: []string{"Cut latency 40%"}}},
+	})
+	if err != nil {
+		t.Fatalf("save model: %v", err)
+	}
+	resume.ID = "resume-html-model"
+	resume.ModelKey = modelKey
+	if err := genRepo.Create(context.Background(), resume); err != nil {
+		t.Fatalf("create generated resume: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/generated-resumes/"+resume.ID+"/download?format=html", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+	if cd := resp.Header().Get("Content-Disposition"); cd != "attachment; filename=\"generated_resume.html\"" {
+		t.Fatalf("unexpected content disposition: %s", cd)
+	}
+	if body := resp.Body.String(); !strings.Contains(body, "Jane Doe") || !strings.Contains(body, "Cut latency 40%") {
+		t.Fatalf("expected resume content in html, got %s", body)
+	}
+}
+
+func TestGeneratedResumeDownloadUnsupportedFormat(t *testing.T) {
+	router, genRepo, store := newDownloadRouter(t, "user-1", false)
+	resume := seedGeneratedResume(t, genRepo, store, "user-1", "resume-pdf")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/generated-resumes/"+resume.ID+"/download?format=pdf", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.Code)
+	}
+}
+
 func TestGeneratedResumeDownloadReadFailureReturnsJSON(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
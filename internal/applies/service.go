@@ -3,7 +3,6 @@ package applies
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"io"
 	"log"
@@ -15,15 +14,15 @@ import (
 	"resume-backend/internal/analyses"
 	"resume-backend/internal/documents"
 	"resume-backend/internal/generatedresumes"
-	"resume-backend/internal/llm"
 	"resume-backend/internal/shared/storage/object"
 	"resume-backend/resume/contract"
 	"resume-backend/resume/model"
 	"resume-backend/resume/render"
+	resumeservice "resume-backend/resume/service"
 	"resume-backend/resume/skills"
 )
 
-const defaultTemplateID = "resume_modern_ats_v1"
+const defaultTemplateID = render.DefaultTemplateID
 
 var (
 	ErrNotFound            = errors.New("not found")
@@ -34,7 +33,7 @@ var (
 	ErrInvalidResumeModel  = errors.New("invalid resume model")
 )
 
-// LLMClient generates ResumeModel payloads from prompts.
+// LLMClient completes the prompts that build a ResumeModel from resume text.
 type LLMClient interface {
 	Complete(ctx context.Context, prompt string) (string, error)
 }
@@ -45,7 +44,8 @@ type Service struct {
 	DocumentsRepo documents.DocumentsRepo
 	GeneratedRepo generatedresumes.Repo
 	Store         object.ObjectStore
-	LLM           LLMClient
+	// LLM builds the resume model; nil parses the resume text without one.
+	LLM LLMClient
 }
 
 // Apply generates, renders, and stores a resume for an analysis.
@@ -59,7 +59,7 @@ func (s *Service) Apply(ctx context.Context, userID, analysisID, templateID stri
 	if templateID != defaultTemplateID {
 		return generatedresumes.GeneratedResume{}, ErrInvalidInput
 	}
-	if s.AnalysisRepo == nil || s.DocumentsRepo == nil || s.GeneratedRepo == nil || s.Store == nil || s.LLM == nil {
+	if s.AnalysisRepo == nil || s.DocumentsRepo == nil || s.GeneratedRepo == nil || s.Store == nil {
 		return generatedresumes.GeneratedResume{}, errors.New("missing dependencies")
 	}
 
@@ -93,25 +93,12 @@ func (s *Service) Apply(ctx context.Context, userID, analysisID, templateID stri
 		return generatedresumes.GeneratedResume{}, err
 	}
 
-	prompt, err := buildPrompt(extracted, analysis.Result)
+	resumeModel, err := resumeservice.ResumeModelFromText(ctx, s.LLM, extracted)
 	if err != nil {
-		return generatedresumes.GeneratedResume{}, err
-	}
-
-	raw, err := s.LLM.Complete(ctx, prompt)
-	if err != nil {
-		return generatedresumes.GeneratedResume{}, err
-	}
-
-	jsonPayload, err := extractJSONObject(raw)
-	if err != nil {
-		log.Printf("apply pipeline invalid json analysis_id=%s: %v", analysis.ID, err)
-		return generatedresumes.GeneratedResume{}, ErrInvalidLLMOutput
-	}
-
-	var resumeModel model.ResumeModel
-	if err := json.Unmarshal([]byte(jsonPayload), &resumeModel); err != nil {
-		log.Printf("apply pipeline decode failed analysis_id=%s: %v", analysis.ID, err)
+		log.Printf("apply pipeline resume model failed analysis_id=%s: %v", analysis.ID, err)
+		if s.LLM == nil {
+			return generatedresumes.GeneratedResume{}, ErrInvalidResumeModel
+		}
 		return generatedresumes.GeneratedResume{}, ErrInvalidLLMOutput
 	}
 
@@ -124,7 +111,7 @@ func (s *Service) Apply(ctx context.Context, userID, analysisID, templateID stri
 		return generatedresumes.GeneratedResume{}, ErrInvalidResumeModel
 	}
 
-	docxBytes, err := render.RenderResume(resumeModel)
+	docxBytes, warnings, err := render.RenderResumeWithReport(resumeModel, render.RenderOptions{})
 	if err != nil {
 		return generatedresumes.GeneratedResume{}, err
 	}
@@ -135,6 +122,11 @@ func (s *Service) Apply(ctx context.Context, userID, analysisID, templateID stri
 		return generatedresumes.GeneratedResume{}, err
 	}
 
+	modelKey, err := generatedresumes.SaveModel(ctx, s.Store, userID, templateID, resumeModel)
+	if err != nil {
+		return generatedresumes.GeneratedResume{}, err
+	}
+
 	resume := generatedresumes.GeneratedResume{
 		ID:         uuid.NewString(),
 		UserID:     userID,
@@ -144,11 +136,13 @@ func (s *Service) Apply(ctx context.Context, userID, analysisID, templateID stri
 		StorageKey: storageKey,
 		MimeType:   mimeType,
 		SizeBytes:  size,
+		ModelKey:   modelKey,
 		CreatedAt:  time.Now().UTC(),
 	}
 	if err := s.GeneratedRepo.Create(ctx, resume); err != nil {
 		return generatedresumes.GeneratedResume{}, err
 	}
+	resume.RenderWarnings = warnings
 	return resume, nil
 }
 
@@ -166,41 +160,6 @@ func loadText(ctx context.Context, store object.ObjectStore, key string) (string
 	return string(data), nil
 }
 
-func buildPrompt(resumeText string, analysisResult map[string]any) (string, error) {
-	analysisJSON, err := json.Marshal(analysisResult)
-	if err != nil {
-		return "", err
-	}
-	template := llm.ResumeGenPromptV1()
-	replacer := strings.NewReplacer(
-		"{{RESUME_TEXT}}", resumeText,
-		"{{ANALYSIS_JSON}}", string(analysisJSON),
-	)
-	return replacer.Replace(template), nil
-}
-
-func extractJSONObject(raw string) (string, error) {
-	payload := strings.TrimSpace(raw)
-	if payload == "" {
-		return "", errors.New("empty llm response")
-	}
-	if json.Valid([]byte(payload)) {
-		return payload, nil
-	}
-
-	start := strings.Index(payload, "{")
-	end := strings.LastIndex(payload, "}")
-	if start == -1 || end == -1 || end <= start {
-		return "", errors.New("no json object found")
-	}
-
-	candidate := payload[start : end+1]
-	if !json.Valid([]byte(candidate)) {
-		return "", errors.New("invalid json object")
-	}
-	return candidate, nil
-}
-
 func validateResumeModel(resumeModel model.ResumeModel) error {
 	if strings.TrimSpace(resumeModel.Header.Name) == "" {
 		return ErrInvalidResumeModel
@@ -0,0 +1,76 @@
+package applies_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/applies"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/generatedresumes"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func TestApplyWithoutLLMParsesResumeText(t *testing.T) {
+	// The renderer loads its template relative to the repository root.
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(cwd, "..", "..")); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	ctx := context.Background()
+	store := local.New(t.TempDir())
+	docRepo := documents.NewMemoryRepo()
+	analysisRepo := analyses.NewMemoryRepo()
+	genRepo := generatedresumes.NewMemoryRepo()
+
+	text := "Jane Doe\njane@example.com | 555-123-4567\n\nExperience\nEngineer, Acme\n- Cut checkout latency by 40%\n"
+	extractedKey, _, _, err := store.Save(ctx, "user-1", "resume.txt", bytes.NewReader([]byte(text)))
+	if err != nil {
+		t.Fatalf("save extracted text: %v", err)
+	}
+	if err := docRepo.Create(ctx, documents.Document{
+		ID:               "doc-1",
+		UserID:           "user-1",
+		FileName:         "resume.txt",
+		MimeType:         "text/plain",
+		StorageKey:       "original",
+		ExtractedTextKey: extractedKey,
+		CreatedAt:        time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create doc: %v", err)
+	}
+	if err := analysisRepo.Create(ctx, analyses.Analysis{
+		ID:         "analysis-1",
+		DocumentID: "doc-1",
+		UserID:     "user-1",
+		Status:     analyses.StatusCompleted,
+		Result:     map[string]any{"summary": map[string]any{"overallAssessment": "ok"}},
+		CreatedAt:  time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	// No LLM, as with APPLY_MODE=deterministic.
+	svc := &applies.Service{
+		AnalysisRepo:  analysisRepo,
+		DocumentsRepo: docRepo,
+		GeneratedRepo: genRepo,
+		Store:         store,
+	}
+	resume, err := svc.Apply(ctx, "user-1", "analysis-1", "", false)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if resume.ID == "" || resume.StorageKey == "" || resume.ModelKey == "" {
+		t.Fatalf("expected a stored generated resume, got %+v", resume)
+	}
+}
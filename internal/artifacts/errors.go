@@ -0,0 +1,20 @@
+package artifacts
+
+import "errors"
+
+var (
+	// ErrNotFound indicates an entity was not found.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrShareLinkExpired indicates the share link is past its expiry.
+	ErrShareLinkExpired = errors.New("share link expired")
+
+	// ErrShareLinkExhausted indicates the share link reached its download limit.
+	ErrShareLinkExhausted = errors.New("share link download limit reached")
+
+	// ErrShareLinkRevoked indicates the owner revoked the share link.
+	ErrShareLinkRevoked = errors.New("share link revoked")
+)
@@ -0,0 +1,166 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// SharedPathPrefix is served without authentication; the token is the credential.
+const SharedPathPrefix = "/api/v1/shared/"
+
+// Handler wires HTTP handlers to the artifacts service.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches download audit and share link routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/documents/:id/download", h.downloadDocument)
+	rg.GET("/documents/:id/downloads", h.stats(ArtifactDocument))
+	rg.GET("/generated-resumes/:id/downloads", h.stats(ArtifactGeneratedResume))
+	rg.POST("/share-links", h.createShareLink)
+	rg.DELETE("/share-links/:id", h.revokeShareLink)
+	rg.GET("/shared/:token", h.downloadShared)
+}
+
+func (h *Handler) downloadDocument(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	artifact, body, err := h.Svc.OpenForOwner(c.Request.Context(), userID, ArtifactDocument, c.Param("id"), c.ClientIP())
+	if err != nil {
+		writeError(c, err, "document")
+		return
+	}
+	defer body.Close()
+	serveArtifact(c, artifact, body)
+}
+
+func (h *Handler) stats(artifactType ArtifactType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := middleware.UserIDFromContext(c)
+		stats, err := h.Svc.Stats(c.Request.Context(), userID, artifactType, c.Param("id"))
+		if err != nil {
+			writeError(c, err, string(artifactType))
+			return
+		}
+		respond.JSON(c, http.StatusOK, stats)
+	}
+}
+
+type createShareLinkRequest struct {
+	ArtifactType   string `json:"artifactType"`
+	ArtifactID     string `json:"artifactId"`
+	ExpiresInHours int    `json:"expiresInHours"`
+	MaxDownloads   int    `json:"maxDownloads"`
+}
+
+type shareLinkResponse struct {
+	ShareLinkID  string    `json:"shareLinkId"`
+	Token        string    `json:"token"`
+	URL          string    `json:"url"`
+	ArtifactType string    `json:"artifactType"`
+	ArtifactID   string    `json:"artifactId"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	MaxDownloads int       `json:"maxDownloads"`
+}
+
+func (h *Handler) createShareLink(c *gin.Context) {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to share files", nil)
+		return
+	}
+	userID := middleware.UserIDFromContext(c)
+
+	var req createShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	artifactType, err := ParseArtifactType(req.ArtifactType)
+	if err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "artifactType is invalid", []map[string]string{
+			{"field": "artifactType", "issue": "invalid"},
+		})
+		return
+	}
+	if req.ExpiresInHours < 0 {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "expiresInHours must not be negative", []map[string]string{
+			{"field": "expiresInHours", "issue": "invalid"},
+		})
+		return
+	}
+
+	link, token, err := h.Svc.CreateShareLink(c.Request.Context(), userID, artifactType, req.ArtifactID, time.Duration(req.ExpiresInHours)*time.Hour, req.MaxDownloads)
+	if err != nil {
+		writeError(c, err, string(artifactType))
+		return
+	}
+	respond.JSON(c, http.StatusCreated, shareLinkResponse{
+		ShareLinkID:  link.ID,
+		Token:        token,
+		URL:          SharedPathPrefix + token,
+		ArtifactType: string(link.ArtifactType),
+		ArtifactID:   link.ArtifactID,
+		ExpiresAt:    link.ExpiresAt,
+		MaxDownloads: link.MaxDownloads,
+	})
+}
+
+func (h *Handler) revokeShareLink(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	if err := h.Svc.RevokeShareLink(c.Request.Context(), userID, c.Param("id")); err != nil {
+		writeError(c, err, "share link")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) downloadShared(c *gin.Context) {
+	artifact, body, err := h.Svc.OpenShared(c.Request.Context(), c.Param("token"), c.ClientIP())
+	if err != nil {
+		writeError(c, err, "share link")
+		return
+	}
+	defer body.Close()
+	c.Header("Cache-Control", "no-store")
+	serveArtifact(c, artifact, body)
+}
+
+func serveArtifact(c *gin.Context, artifact Artifact, body io.Reader) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to read file", nil)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", artifact.FileName))
+	c.Data(http.StatusOK, artifact.MimeType, data)
+}
+
+func writeError(c *gin.Context, err error, subject string) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", subject+" not found", nil)
+	case errors.Is(err, ErrShareLinkExpired):
+		respond.Error(c, http.StatusGone, "share_link_expired", "share link has expired", nil)
+	case errors.Is(err, ErrShareLinkExhausted):
+		respond.Error(c, http.StatusGone, "share_link_exhausted", "share link download limit reached", nil)
+	case errors.Is(err, ErrShareLinkRevoked):
+		respond.Error(c, http.StatusGone, "share_link_revoked", "share link has been revoked", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to process "+subject, nil)
+	}
+}
@@ -0,0 +1,214 @@
+package artifacts_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/artifacts"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+func newTestApp(t *testing.T) *bootstrap.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	return app
+}
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func doRequest(router http.Handler, method, path, authorization string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func uploadText(t *testing.T, router http.Handler, authorization, content string) string {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, err := writer.CreateFormFile("file", "resume.txt")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fileWriter.Write([]byte(content)); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", authorization)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode upload response: %v", err)
+	}
+	return created.DocumentID
+}
+
+type shareLinkResponse struct {
+	ShareLinkID string `json:"shareLinkId"`
+	Token       string `json:"token"`
+	URL         string `json:"url"`
+}
+
+func createShareLink(t *testing.T, router http.Handler, authorization, docID string, maxDownloads int) shareLinkResponse {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]any{
+		"artifactType": "document",
+		"artifactId":   docID,
+		"maxDownloads": maxDownloads,
+	})
+	resp := doRequest(router, http.MethodPost, "/api/v1/share-links", authorization, payload)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create share link: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var link shareLinkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&link); err != nil {
+		t.Fatalf("decode share link: %v", err)
+	}
+	if link.Token == "" || link.URL != artifacts.SharedPathPrefix+link.Token {
+		t.Fatalf("unexpected share link response: %+v", link)
+	}
+	return link
+}
+
+func getStats(t *testing.T, router http.Handler, authorization, docID string) artifacts.DownloadStats {
+	t.Helper()
+	resp := doRequest(router, http.MethodGet, "/api/v1/documents/"+docID+"/downloads", authorization, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("stats: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var stats artifacts.DownloadStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	return stats
+}
+
+func TestShareLinkDownloadsAreLimitedAndAudited(t *testing.T) {
+	app := newTestApp(t)
+	router := app.Router
+	owner := bearer(t, "user-share-owner")
+	docID := uploadText(t, router, owner, "Jane Doe resume")
+
+	resp := doRequest(router, http.MethodGet, "/api/v1/documents/"+docID+"/download", owner, nil)
+	if resp.Code != http.StatusOK || resp.Body.String() != "Jane Doe resume" {
+		t.Fatalf("owner download: got %d %q", resp.Code, resp.Body.String())
+	}
+
+	link := createShareLink(t, router, owner, docID, 2)
+	for i := 0; i < 2; i++ {
+		resp = doRequest(router, http.MethodGet, link.URL, "", nil)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("shared download %d: expected 200, got %d: %s", i+1, resp.Code, resp.Body.String())
+		}
+		if resp.Header().Get("Cache-Control") != "no-store" {
+			t.Fatalf("expected no-store cache header on shared download")
+		}
+	}
+	resp = doRequest(router, http.MethodGet, link.URL, "", nil)
+	if resp.Code != http.StatusGone {
+		t.Fatalf("exhausted link: expected 410, got %d", resp.Code)
+	}
+
+	stats := getStats(t, router, owner, docID)
+	if stats.Total != 3 || stats.ViaSession != 1 || stats.ViaShareLink != 2 || stats.LastDownloadedAt == nil {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// Another user cannot see the owner's stats.
+	resp = doRequest(router, http.MethodGet, "/api/v1/documents/"+docID+"/downloads", bearer(t, "user-other"), nil)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("foreign stats: expected 404, got %d", resp.Code)
+	}
+}
+
+func TestShareLinkExpiryAndRevocation(t *testing.T) {
+	app := newTestApp(t)
+	router := app.Router
+	owner := bearer(t, "user-share-expiry")
+	docID := uploadText(t, router, owner, "resume body")
+
+	expiring := createShareLink(t, router, owner, docID, 0)
+	app.ArtifactsService.Now = func() time.Time { return time.Now().Add(artifacts.DefaultShareLinkTTL + time.Minute) }
+	resp := doRequest(router, http.MethodGet, expiring.URL, "", nil)
+	if resp.Code != http.StatusGone {
+		t.Fatalf("expired link: expected 410, got %d", resp.Code)
+	}
+	app.ArtifactsService.Now = nil
+
+	revoked := createShareLink(t, router, owner, docID, 0)
+	resp = doRequest(router, http.MethodDelete, "/api/v1/share-links/"+revoked.ShareLinkID, bearer(t, "user-other"), nil)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("foreign revoke: expected 404, got %d", resp.Code)
+	}
+	resp = doRequest(router, http.MethodDelete, "/api/v1/share-links/"+revoked.ShareLinkID, owner, nil)
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("revoke: expected 204, got %d", resp.Code)
+	}
+	resp = doRequest(router, http.MethodGet, revoked.URL, "", nil)
+	if resp.Code != http.StatusGone {
+		t.Fatalf("revoked link: expected 410, got %d", resp.Code)
+	}
+
+	resp = doRequest(router, http.MethodGet, artifacts.SharedPathPrefix+"not-a-token", "", nil)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("unknown token: expected 404, got %d", resp.Code)
+	}
+}
+
+func TestGuestsCannotCreateShareLinks(t *testing.T) {
+	app := newTestApp(t)
+	payload := []byte(`{"artifactType":"document","artifactId":"doc-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/share-links", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Guest-Id", "test-guest")
+	resp := httptest.NewRecorder()
+	app.Router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for guest, got %d", resp.Code)
+	}
+}
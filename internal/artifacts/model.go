@@ -0,0 +1,65 @@
+package artifacts
+
+import "time"
+
+// ArtifactType identifies what kind of stored file a download or share link refers to.
+type ArtifactType string
+
+const (
+	ArtifactGeneratedResume ArtifactType = "generated_resume"
+	ArtifactDocument        ArtifactType = "document"
+)
+
+// Via records how a download was authorized.
+type Via string
+
+const (
+	ViaSession   Via = "session"
+	ViaShareLink Via = "share_link"
+)
+
+// Download is one audited fetch of an artifact.
+type Download struct {
+	ID               string
+	ArtifactType     ArtifactType
+	ArtifactID       string
+	OwnerUserID      string
+	DownloaderUserID string
+	ClientIP         string
+	Via              Via
+	ShareLinkID      string
+	CreatedAt        time.Time
+}
+
+// DownloadStats summarizes downloads of one artifact for its owner.
+type DownloadStats struct {
+	Total            int        `json:"total"`
+	ViaSession       int        `json:"viaSession"`
+	ViaShareLink     int        `json:"viaShareLink"`
+	LastDownloadedAt *time.Time `json:"lastDownloadedAt,omitempty"`
+}
+
+// ShareLink grants unauthenticated downloads of an artifact until it expires,
+// runs out of downloads or is revoked. Only a hash of the token is stored.
+type ShareLink struct {
+	ID            string
+	TokenHash     string
+	ArtifactType  ArtifactType
+	ArtifactID    string
+	OwnerUserID   string
+	ExpiresAt     time.Time
+	MaxDownloads  int
+	DownloadCount int
+	RevokedAt     *time.Time
+	CreatedAt     time.Time
+}
+
+// Artifact is a resolved, owner-verified stored file.
+type Artifact struct {
+	Type        ArtifactType
+	ID          string
+	OwnerUserID string
+	StorageKey  string
+	FileName    string
+	MimeType    string
+}
@@ -0,0 +1,30 @@
+package artifacts
+
+import (
+	"context"
+	"time"
+)
+
+// Repo defines persistence for download audits and share links.
+type Repo interface {
+	RecordDownload(ctx context.Context, download Download) error
+	DownloadStats(ctx context.Context, ownerUserID string, artifactType ArtifactType, artifactID string) (DownloadStats, error)
+	CreateShareLink(ctx context.Context, link ShareLink) error
+	// ConsumeShareLink validates the link and counts one download atomically.
+	ConsumeShareLink(ctx context.Context, tokenHash string, now time.Time) (ShareLink, error)
+	RevokeShareLink(ctx context.Context, ownerUserID, linkID string, revokedAt time.Time) error
+}
+
+// shareLinkState explains why a link cannot be used; it is shared by both repos.
+func shareLinkState(link ShareLink, now time.Time) error {
+	switch {
+	case link.RevokedAt != nil:
+		return ErrShareLinkRevoked
+	case !now.Before(link.ExpiresAt):
+		return ErrShareLinkExpired
+	case link.MaxDownloads > 0 && link.DownloadCount >= link.MaxDownloads:
+		return ErrShareLinkExhausted
+	default:
+		return nil
+	}
+}
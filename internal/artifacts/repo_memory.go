@@ -0,0 +1,107 @@
+package artifacts
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepo stores download audits and share links in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu        sync.RWMutex
+	downloads []Download
+	links     map[string]ShareLink // token hash -> link
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{links: make(map[string]ShareLink)}
+}
+
+// RecordDownload appends a download audit entry.
+func (r *MemoryRepo) RecordDownload(ctx context.Context, download Download) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downloads = append(r.downloads, download)
+	return nil
+}
+
+// DownloadStats counts downloads of an artifact owned by ownerUserID.
+func (r *MemoryRepo) DownloadStats(ctx context.Context, ownerUserID string, artifactType ArtifactType, artifactID string) (DownloadStats, error) {
+	if err := ctx.Err(); err != nil {
+		return DownloadStats{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var stats DownloadStats
+	for _, d := range r.downloads {
+		if d.OwnerUserID != ownerUserID || d.ArtifactType != artifactType || d.ArtifactID != artifactID {
+			continue
+		}
+		stats.Total++
+		if d.Via == ViaShareLink {
+			stats.ViaShareLink++
+		} else {
+			stats.ViaSession++
+		}
+		if stats.LastDownloadedAt == nil || d.CreatedAt.After(*stats.LastDownloadedAt) {
+			at := d.CreatedAt
+			stats.LastDownloadedAt = &at
+		}
+	}
+	return stats, nil
+}
+
+// CreateShareLink stores a new share link.
+func (r *MemoryRepo) CreateShareLink(ctx context.Context, link ShareLink) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.links[link.TokenHash] = link
+	return nil
+}
+
+// ConsumeShareLink validates the link and counts one download.
+func (r *MemoryRepo) ConsumeShareLink(ctx context.Context, tokenHash string, now time.Time) (ShareLink, error) {
+	if err := ctx.Err(); err != nil {
+		return ShareLink{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	link, ok := r.links[tokenHash]
+	if !ok {
+		return ShareLink{}, ErrNotFound
+	}
+	if err := shareLinkState(link, now); err != nil {
+		return ShareLink{}, err
+	}
+	link.DownloadCount++
+	r.links[tokenHash] = link
+	return link, nil
+}
+
+// RevokeShareLink disables a share link owned by ownerUserID.
+func (r *MemoryRepo) RevokeShareLink(ctx context.Context, ownerUserID, linkID string, revokedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, link := range r.links {
+		if link.ID == linkID && link.OwnerUserID == ownerUserID {
+			if link.RevokedAt == nil {
+				link.RevokedAt = &revokedAt
+				r.links[hash] = link
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+var _ Repo = (*MemoryRepo)(nil)
@@ -0,0 +1,176 @@
+package artifacts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+// RecordDownload inserts a download audit row.
+func (r *PGRepo) RecordDownload(ctx context.Context, download Download) error {
+	const query = `
+INSERT INTO artifact_downloads (
+    id,
+    artifact_type,
+    artifact_id,
+    owner_user_id,
+    downloader_user_id,
+    client_ip,
+    via,
+    share_link_id,
+    created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.DB.ExecContext(ctx, query,
+		download.ID,
+		string(download.ArtifactType),
+		download.ArtifactID,
+		download.OwnerUserID,
+		nullString(download.DownloaderUserID),
+		nullString(download.ClientIP),
+		string(download.Via),
+		nullString(download.ShareLinkID),
+		download.CreatedAt,
+	)
+	return err
+}
+
+// DownloadStats counts downloads of an artifact owned by ownerUserID.
+func (r *PGRepo) DownloadStats(ctx context.Context, ownerUserID string, artifactType ArtifactType, artifactID string) (DownloadStats, error) {
+	const query = `
+SELECT
+    COUNT(*),
+    COUNT(*) FILTER (WHERE via = 'session'),
+    COUNT(*) FILTER (WHERE via = 'share_link'),
+    MAX(created_at)
+FROM artifact_downloads
+WHERE owner_user_id = $1 AND artifact_type = $2 AND artifact_id = $3`
+	var stats DownloadStats
+	var last sql.NullTime
+	if err := r.DB.QueryRowContext(ctx, query, ownerUserID, string(artifactType), artifactID).Scan(
+		&stats.Total,
+		&stats.ViaSession,
+		&stats.ViaShareLink,
+		&last,
+	); err != nil {
+		return DownloadStats{}, err
+	}
+	if last.Valid {
+		stats.LastDownloadedAt = &last.Time
+	}
+	return stats, nil
+}
+
+// CreateShareLink inserts a share link.
+func (r *PGRepo) CreateShareLink(ctx context.Context, link ShareLink) error {
+	const query = `
+INSERT INTO artifact_share_links (
+    id,
+    token_hash,
+    artifact_type,
+    artifact_id,
+    owner_user_id,
+    expires_at,
+    max_downloads,
+    download_count,
+    created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, 0, $8)`
+	_, err := r.DB.ExecContext(ctx, query,
+		link.ID,
+		link.TokenHash,
+		string(link.ArtifactType),
+		link.ArtifactID,
+		link.OwnerUserID,
+		link.ExpiresAt,
+		link.MaxDownloads,
+		link.CreatedAt,
+	)
+	return err
+}
+
+const shareLinkColumns = `id, token_hash, artifact_type, artifact_id, owner_user_id, expires_at, max_downloads, download_count, revoked_at, created_at`
+
+// ConsumeShareLink increments the download count only while the link is usable, so
+// concurrent downloads cannot exceed max_downloads.
+func (r *PGRepo) ConsumeShareLink(ctx context.Context, tokenHash string, now time.Time) (ShareLink, error) {
+	const consume = `
+UPDATE artifact_share_links
+SET download_count = download_count + 1
+WHERE token_hash = $1
+  AND revoked_at IS NULL
+  AND expires_at > $2
+  AND (max_downloads = 0 OR download_count < max_downloads)
+RETURNING ` + shareLinkColumns
+	link, err := scanShareLink(r.DB.QueryRowContext(ctx, consume, tokenHash, now))
+	if err == nil {
+		return link, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return ShareLink{}, err
+	}
+
+	const lookup = `SELECT ` + shareLinkColumns + ` FROM artifact_share_links WHERE token_hash = $1`
+	link, err = scanShareLink(r.DB.QueryRowContext(ctx, lookup, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ShareLink{}, ErrNotFound
+		}
+		return ShareLink{}, err
+	}
+	if stateErr := shareLinkState(link, now); stateErr != nil {
+		return ShareLink{}, stateErr
+	}
+	return ShareLink{}, ErrShareLinkExhausted
+}
+
+// RevokeShareLink disables a share link owned by ownerUserID.
+func (r *PGRepo) RevokeShareLink(ctx context.Context, ownerUserID, linkID string, revokedAt time.Time) error {
+	const query = `
+UPDATE artifact_share_links
+SET revoked_at = COALESCE(revoked_at, $1)
+WHERE id = $2 AND owner_user_id = $3`
+	res, err := r.DB.ExecContext(ctx, query, revokedAt, linkID, ownerUserID)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanShareLink(row *sql.Row) (ShareLink, error) {
+	var link ShareLink
+	var artifactType string
+	var revokedAt sql.NullTime
+	if err := row.Scan(
+		&link.ID,
+		&link.TokenHash,
+		&artifactType,
+		&link.ArtifactID,
+		&link.OwnerUserID,
+		&link.ExpiresAt,
+		&link.MaxDownloads,
+		&link.DownloadCount,
+		&revokedAt,
+		&link.CreatedAt,
+	); err != nil {
+		return ShareLink{}, err
+	}
+	link.ArtifactType = ArtifactType(artifactType)
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+	return link, nil
+}
+
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
+
+var _ Repo = (*PGRepo)(nil)
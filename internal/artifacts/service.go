@@ -0,0 +1,243 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/generatedresumes"
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/telemetry"
+)
+
+const (
+	// DefaultShareLinkTTL applies when the owner does not pick an expiry.
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+	// MaxShareLinkTTL caps how long a share link may stay valid.
+	MaxShareLinkTTL = 30 * 24 * time.Hour
+	// MaxShareLinkDownloads caps the per-link download limit.
+	MaxShareLinkDownloads = 1000
+)
+
+// Service audits artifact downloads and manages share links.
+type Service struct {
+	Repo          Repo
+	DocRepo       documents.DocumentsRepo
+	GeneratedRepo generatedresumes.Repo
+	Store         object.ObjectStore
+	Now           func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, docRepo documents.DocumentsRepo, generatedRepo generatedresumes.Repo, store object.ObjectStore) *Service {
+	return &Service{Repo: repo, DocRepo: docRepo, GeneratedRepo: generatedRepo, Store: store}
+}
+
+// ParseArtifactType validates a client-supplied artifact type.
+func ParseArtifactType(raw string) (ArtifactType, error) {
+	switch ArtifactType(raw) {
+	case ArtifactGeneratedResume, ArtifactDocument:
+		return ArtifactType(raw), nil
+	default:
+		return "", fmt.Errorf("%w: unknown artifact type %q", ErrInvalidInput, raw)
+	}
+}
+
+// Resolve loads an artifact owned by ownerUserID.
+func (s *Service) Resolve(ctx context.Context, ownerUserID string, artifactType ArtifactType, artifactID string) (Artifact, error) {
+	if ownerUserID == "" || artifactID == "" {
+		return Artifact{}, ErrInvalidInput
+	}
+	switch artifactType {
+	case ArtifactGeneratedResume:
+		resume, err := s.GeneratedRepo.GetByID(ctx, ownerUserID, artifactID)
+		if err != nil {
+			if errors.Is(err, generatedresumes.ErrNotFound) || errors.Is(err, generatedresumes.ErrForbidden) {
+				return Artifact{}, ErrNotFound
+			}
+			return Artifact{}, err
+		}
+		mimeType := resume.MimeType
+		if mimeType == "" {
+			mimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		}
+		return Artifact{
+			Type:        artifactType,
+			ID:          resume.ID,
+			OwnerUserID: resume.UserID,
+			StorageKey:  resume.StorageKey,
+			FileName:    "generated_resume.docx",
+			MimeType:    mimeType,
+		}, nil
+	case ArtifactDocument:
+		doc, err := s.DocRepo.GetByID(ctx, ownerUserID, artifactID)
+		if err != nil {
+			if errors.Is(err, documents.ErrNotFound) {
+				return Artifact{}, ErrNotFound
+			}
+			return Artifact{}, err
+		}
+		return Artifact{
+			Type:        artifactType,
+			ID:          doc.ID,
+			OwnerUserID: doc.UserID,
+			StorageKey:  doc.StorageKey,
+			FileName:    filepath.Base(doc.FileName),
+			MimeType:    doc.ExtractionMimeType(),
+		}, nil
+	default:
+		return Artifact{}, ErrInvalidInput
+	}
+}
+
+// OpenForOwner opens an owned artifact and records a session download.
+func (s *Service) OpenForOwner(ctx context.Context, userID string, artifactType ArtifactType, artifactID, clientIP string) (Artifact, io.ReadCloser, error) {
+	artifact, err := s.Resolve(ctx, userID, artifactType, artifactID)
+	if err != nil {
+		return Artifact{}, nil, err
+	}
+	body, err := s.Store.Open(ctx, artifact.StorageKey)
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("open artifact: %w", err)
+	}
+	s.RecordSessionDownload(ctx, artifact, userID, clientIP)
+	return artifact, body, nil
+}
+
+// RecordSessionDownload audits a download made by a signed-in or guest session.
+func (s *Service) RecordSessionDownload(ctx context.Context, artifact Artifact, downloaderUserID, clientIP string) {
+	s.record(ctx, Download{
+		ArtifactType:     artifact.Type,
+		ArtifactID:       artifact.ID,
+		OwnerUserID:      artifact.OwnerUserID,
+		DownloaderUserID: downloaderUserID,
+		ClientIP:         clientIP,
+		Via:              ViaSession,
+	})
+}
+
+// CreateShareLink issues a share link and returns it with the plaintext token, which is not stored.
+// A zero ttl uses DefaultShareLinkTTL; zero maxDownloads means unlimited until expiry.
+func (s *Service) CreateShareLink(ctx context.Context, ownerUserID string, artifactType ArtifactType, artifactID string, ttl time.Duration, maxDownloads int) (ShareLink, string, error) {
+	if ttl == 0 {
+		ttl = DefaultShareLinkTTL
+	}
+	if ttl < 0 || ttl > MaxShareLinkTTL {
+		return ShareLink{}, "", fmt.Errorf("%w: expiry must be at most %d days", ErrInvalidInput, int(MaxShareLinkTTL.Hours()/24))
+	}
+	if maxDownloads < 0 || maxDownloads > MaxShareLinkDownloads {
+		return ShareLink{}, "", fmt.Errorf("%w: maxDownloads must be between 0 and %d", ErrInvalidInput, MaxShareLinkDownloads)
+	}
+	artifact, err := s.Resolve(ctx, ownerUserID, artifactType, artifactID)
+	if err != nil {
+		return ShareLink{}, "", err
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return ShareLink{}, "", err
+	}
+	now := s.now()
+	link := ShareLink{
+		ID:           uuid.NewString(),
+		TokenHash:    hashShareToken(token),
+		ArtifactType: artifact.Type,
+		ArtifactID:   artifact.ID,
+		OwnerUserID:  artifact.OwnerUserID,
+		ExpiresAt:    now.Add(ttl),
+		MaxDownloads: maxDownloads,
+		CreatedAt:    now,
+	}
+	if err := s.Repo.CreateShareLink(ctx, link); err != nil {
+		return ShareLink{}, "", err
+	}
+	return link, token, nil
+}
+
+// OpenShared consumes one download from a share link, opens the artifact and audits it.
+func (s *Service) OpenShared(ctx context.Context, token, clientIP string) (Artifact, io.ReadCloser, error) {
+	if token == "" {
+		return Artifact{}, nil, ErrNotFound
+	}
+	link, err := s.Repo.ConsumeShareLink(ctx, hashShareToken(token), s.now())
+	if err != nil {
+		return Artifact{}, nil, err
+	}
+	artifact, err := s.Resolve(ctx, link.OwnerUserID, link.ArtifactType, link.ArtifactID)
+	if err != nil {
+		return Artifact{}, nil, err
+	}
+	body, err := s.Store.Open(ctx, artifact.StorageKey)
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("open artifact: %w", err)
+	}
+	s.record(ctx, Download{
+		ArtifactType: artifact.Type,
+		ArtifactID:   artifact.ID,
+		OwnerUserID:  artifact.OwnerUserID,
+		ClientIP:     clientIP,
+		Via:          ViaShareLink,
+		ShareLinkID:  link.ID,
+	})
+	return artifact, body, nil
+}
+
+// Stats returns download counts for an artifact owned by ownerUserID.
+func (s *Service) Stats(ctx context.Context, ownerUserID string, artifactType ArtifactType, artifactID string) (DownloadStats, error) {
+	if _, err := s.Resolve(ctx, ownerUserID, artifactType, artifactID); err != nil {
+		return DownloadStats{}, err
+	}
+	return s.Repo.DownloadStats(ctx, ownerUserID, artifactType, artifactID)
+}
+
+// RevokeShareLink disables a share link.
+func (s *Service) RevokeShareLink(ctx context.Context, ownerUserID, linkID string) error {
+	if ownerUserID == "" || linkID == "" {
+		return ErrInvalidInput
+	}
+	return s.Repo.RevokeShareLink(ctx, ownerUserID, linkID, s.now())
+}
+
+// record stores the audit row. A failed audit write is logged rather than failing the download.
+func (s *Service) record(ctx context.Context, d Download) {
+	d.ID = uuid.NewString()
+	d.CreatedAt = s.now()
+	if err := s.Repo.RecordDownload(ctx, d); err != nil {
+		telemetry.Error("artifact.download_audit_failed", map[string]any{
+			"artifact_type": string(d.ArtifactType),
+			"artifact_id":   d.ArtifactID,
+			"via":           string(d.Via),
+			"error":         err.Error(),
+		})
+	}
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func newShareToken() (string, error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,8 @@
+package assist
+
+import "errors"
+
+var (
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrInvalidLLMOutput = errors.New("invalid llm output")
+)
@@ -0,0 +1,74 @@
+package assist
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Handler exposes the editing helpers over HTTP.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches assist routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/assist/quantify-bullet", h.quantifyBullet)
+}
+
+func (h *Handler) quantifyBullet(c *gin.Context) {
+	var req QuantifyInput
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid JSON body", nil)
+		return
+	}
+	if strings.TrimSpace(req.Bullet) == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "bullet is required", []map[string]string{
+			{"field": "bullet", "issue": "required"},
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.Bullet) > MaxBulletRunes {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "bullet too long", []map[string]string{
+			{"field": "bullet", "issue": "max_length"},
+		})
+		return
+	}
+
+	out, err := h.Svc.QuantifyBullet(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		case errors.Is(err, ErrInvalidLLMOutput):
+			respond.Error(c, http.StatusBadGateway, "invalid_llm_output", "invalid model output", nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to quantify bullet", nil)
+		}
+		return
+	}
+
+	placeholders := 0
+	for _, opt := range out.Options {
+		placeholders += len(opt.PlaceholdersNeeded)
+	}
+	telemetry.Info("assist.bullet.quantified", map[string]any{
+		"request_id":   middleware.RequestIDFromContext(c),
+		"options":      len(out.Options),
+		"placeholders": placeholders,
+	})
+	respond.JSON(c, http.StatusOK, out)
+}
@@ -0,0 +1,147 @@
+package assist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+)
+
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (s *scriptedLLM) Complete(ctx context.Context, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	if len(s.responses) == 0 {
+		return "", errors.New("no response scripted")
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+const validOptions = `{
+  "options": [
+    {"text": "Cut checkout latency by X% by rewriting the pricing service in Go", "claimSupport": "placeholder",
+     "placeholdersNeeded": [{"key": "latency_reduction_pct", "guidance": "How much did p95 checkout latency drop? Check your dashboards."}],
+     "rationale": "Leads with speed."},
+    {"text": "Rewrote the pricing service in Go for 3 teams, serving 1,200 requests per second", "claimSupport": "supported",
+     "placeholdersNeeded": [], "rationale": "Leads with scale."},
+    {"text": "Rewrote the pricing service in Go for 3 teams, serving 1,200 requests per second", "claimSupport": "supported",
+     "placeholdersNeeded": [], "rationale": "Duplicate."}
+  ]
+}`
+
+func setupRouter(llm LLMClient) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Auth("dev"))
+	NewHandler(NewService(llm)).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func postQuantify(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/assist/quantify-bullet", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Guest-Id", "test-guest")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestQuantifyBulletReturnsOptions(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{"```json\n" + validOptions + "\n```"}}
+	resp := postQuantify(setupRouter(llm), `{"bullet":"Rewrote the pricing service in Go","context":{"role":"Backend Engineer","details":"Used by 3 teams, about 1200 requests per second."}}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var out QuantifyResult
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(out.Options) != 2 {
+		t.Fatalf("expected duplicate option dropped, got %+v", out.Options)
+	}
+	if out.Options[0].ClaimSupport != "placeholder" || out.Options[0].PlaceholdersNeeded[0].Key != "latency_reduction_pct" {
+		t.Fatalf("unexpected placeholder option: %+v", out.Options[0])
+	}
+	if out.Options[1].PlaceholdersNeeded == nil {
+		t.Fatalf("expected empty placeholdersNeeded array")
+	}
+	prompt := llm.prompts[0]
+	if !strings.Contains(prompt, "Bullet:\nRewrote the pricing service in Go") || !strings.Contains(prompt, "Role: Backend Engineer") {
+		t.Fatalf("expected bullet and context in prompt:\n%s", prompt)
+	}
+}
+
+func TestQuantifyBulletRetriesOnGuardrailViolation(t *testing.T) {
+	cases := map[string]string{
+		"forbidden term": `{"options": [
+			{"text": "Drove significant savings on the pricing service", "claimSupport": "inferred", "placeholdersNeeded": [], "rationale": "x"},
+			{"text": "Rewrote the pricing service in Go", "claimSupport": "inferred", "placeholdersNeeded": [], "rationale": "y"}]}`,
+		"invented figure": `{"options": [
+			{"text": "Cut costs by 40% on the pricing service", "claimSupport": "supported", "placeholdersNeeded": [], "rationale": "x"},
+			{"text": "Rewrote the pricing service in Go", "claimSupport": "inferred", "placeholdersNeeded": [], "rationale": "y"}]}`,
+		"placeholder without guidance": `{"options": [
+			{"text": "Cut costs by X% on the pricing service", "claimSupport": "placeholder", "placeholdersNeeded": [], "rationale": "x"},
+			{"text": "Rewrote the pricing service in Go", "claimSupport": "inferred", "placeholdersNeeded": [], "rationale": "y"}]}`,
+	}
+	wantFeedback := map[string]string{
+		"forbidden term":               `unsupported term "significant"`,
+		"invented figure":              `figure "40"`,
+		"placeholder without guidance": "placeholdersNeeded required",
+	}
+	for name, bad := range cases {
+		t.Run(name, func(t *testing.T) {
+			llm := &scriptedLLM{responses: []string{bad, validOptions}}
+			out, err := NewService(llm).QuantifyBullet(context.Background(), QuantifyInput{
+				Bullet:  "Rewrote the pricing service in Go for 3 teams",
+				Context: QuantifyContext{Details: "1,200 requests per second"},
+			})
+			if err != nil {
+				t.Fatalf("quantify: %v", err)
+			}
+			if len(out.Options) != 2 {
+				t.Fatalf("expected retry result, got %+v", out)
+			}
+			if len(llm.prompts) != 2 || !strings.Contains(llm.prompts[1], wantFeedback[name]) {
+				t.Fatalf("expected retry prompt with %q, got %v", wantFeedback[name], llm.prompts[len(llm.prompts)-1])
+			}
+		})
+	}
+}
+
+func TestQuantifyBulletRejectsInvalidOutput(t *testing.T) {
+	single := `{"options": [{"text": "Rewrote the pricing service", "claimSupport": "inferred", "placeholdersNeeded": [], "rationale": "x"}]}`
+	llm := &scriptedLLM{responses: []string{single, `{"options": [], "extra": true}`}}
+	resp := postQuantify(setupRouter(llm), `{"bullet":"Rewrote the pricing service"}`)
+	if resp.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestQuantifyBulletValidatesRequest(t *testing.T) {
+	router := setupRouter(&scriptedLLM{})
+	if resp := postQuantify(router, `{"bullet":"   "}`); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty bullet, got %d", resp.Code)
+	}
+	long, _ := json.Marshal(map[string]string{"bullet": strings.Repeat("a", MaxBulletRunes+1)})
+	if resp := postQuantify(router, string(long)); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for long bullet, got %d", resp.Code)
+	}
+	details, _ := json.Marshal(map[string]any{"bullet": "Led migrations", "context": map[string]string{"details": strings.Repeat("a", MaxContextDetailsRunes+1)}})
+	if resp := postQuantify(router, string(details)); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for long context, got %d", resp.Code)
+	}
+}
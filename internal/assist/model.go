@@ -0,0 +1,35 @@
+package assist
+
+// QuantifyInput is a single bullet to rewrite with optional background.
+type QuantifyInput struct {
+	Bullet  string          `json:"bullet"`
+	Context QuantifyContext `json:"context"`
+}
+
+// QuantifyContext is what the candidate tells us about the bullet. Figures
+// stated here may be used in rewrites.
+type QuantifyContext struct {
+	Role    string `json:"role"`
+	Company string `json:"company"`
+	Details string `json:"details"`
+}
+
+// QuantifyResult holds the rewrite options for a bullet.
+type QuantifyResult struct {
+	Options []QuantifiedBullet `json:"options"`
+}
+
+// QuantifiedBullet is one rewrite option.
+type QuantifiedBullet struct {
+	Text string `json:"text"`
+	// ClaimSupport is supported, inferred or placeholder, as in analysis bullet rewrites.
+	ClaimSupport       string        `json:"claimSupport"`
+	PlaceholdersNeeded []Placeholder `json:"placeholdersNeeded"`
+	Rationale          string        `json:"rationale"`
+}
+
+// Placeholder tells the candidate which figure to fill in.
+type Placeholder struct {
+	Key      string `json:"key"`
+	Guidance string `json:"guidance"`
+}
@@ -0,0 +1,250 @@
+package assist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/llm/prompts"
+)
+
+const (
+	MaxBulletRunes         = 500
+	MaxContextFieldRunes   = 120
+	MaxContextDetailsRunes = 2000
+
+	minOptions       = 2
+	maxOptions       = 3
+	maxOptionRunes   = 400
+	maxPlaceholders  = 5
+	maxGuidanceRunes = 200
+)
+
+// LLMClient completes a single prompt.
+type LLMClient interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// Service runs the interactive editing helpers.
+type Service struct {
+	LLM LLMClient
+}
+
+// NewService constructs a Service.
+func NewService(llm LLMClient) *Service {
+	return &Service{LLM: llm}
+}
+
+// QuantifyBullet asks the LLM for quantified rewrites of one bullet. Options
+// are held to the analysis guardrails: no vague impact terms, and any figure
+// not stated in the bullet or context must be a placeholder. A response that
+// fails validation is retried once with the validation error as feedback.
+func (s *Service) QuantifyBullet(ctx context.Context, in QuantifyInput) (QuantifyResult, error) {
+	in = cleanInput(in)
+	if err := validateInput(in); err != nil {
+		return QuantifyResult{}, err
+	}
+	if s.LLM == nil {
+		return QuantifyResult{}, errors.New("llm client is not configured")
+	}
+
+	prompt := strings.TrimSpace(prompts.QuantifyBullet) + "\n\n" + quantifyPromptInput(in)
+	known := figures(in.Bullet + "\n" + in.Context.Role + "\n" + in.Context.Company + "\n" + in.Context.Details)
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		attemptPrompt := prompt
+		if lastErr != nil {
+			attemptPrompt += "\n\nYour previous output was rejected: " + lastErr.Error() + ". Return corrected JSON only."
+		}
+		raw, err := s.LLM.Complete(ctx, attemptPrompt)
+		if err != nil {
+			return QuantifyResult{}, fmt.Errorf("llm complete: %w", err)
+		}
+		out, err := parseOptions(raw, known)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return out, nil
+	}
+	return QuantifyResult{}, fmt.Errorf("%w: %v", ErrInvalidLLMOutput, lastErr)
+}
+
+func cleanInput(in QuantifyInput) QuantifyInput {
+	in.Bullet = strings.Join(strings.Fields(in.Bullet), " ")
+	in.Context.Role = strings.TrimSpace(in.Context.Role)
+	in.Context.Company = strings.TrimSpace(in.Context.Company)
+	in.Context.Details = strings.TrimSpace(in.Context.Details)
+	return in
+}
+
+func validateInput(in QuantifyInput) error {
+	switch {
+	case in.Bullet == "":
+		return fmt.Errorf("%w: bullet is required", ErrInvalidInput)
+	case utf8.RuneCountInString(in.Bullet) > MaxBulletRunes:
+		return fmt.Errorf("%w: bullet must be at most %d characters", ErrInvalidInput, MaxBulletRunes)
+	case utf8.RuneCountInString(in.Context.Role) > MaxContextFieldRunes,
+		utf8.RuneCountInString(in.Context.Company) > MaxContextFieldRunes:
+		return fmt.Errorf("%w: context role and company must be at most %d characters", ErrInvalidInput, MaxContextFieldRunes)
+	case utf8.RuneCountInString(in.Context.Details) > MaxContextDetailsRunes:
+		return fmt.Errorf("%w: context details must be at most %d characters", ErrInvalidInput, MaxContextDetailsRunes)
+	}
+	return nil
+}
+
+func quantifyPromptInput(in QuantifyInput) string {
+	var b strings.Builder
+	b.WriteString("Bullet:\n" + in.Bullet + "\n")
+	if in.Context.Role != "" {
+		b.WriteString("\nRole: " + in.Context.Role + "\n")
+	}
+	if in.Context.Company != "" {
+		b.WriteString("Company: " + in.Context.Company + "\n")
+	}
+	if in.Context.Details != "" {
+		b.WriteString("\nContext from the candidate:\n" + in.Context.Details + "\n")
+	}
+	return b.String()
+}
+
+// rawResult uses a pointer so a missing options key can be told apart from an
+// empty array.
+type rawResult struct {
+	Options *[]QuantifiedBullet `json:"options"`
+}
+
+func parseOptions(raw string, known map[string]struct{}) (QuantifyResult, error) {
+	payload, err := extractJSONObject(raw)
+	if err != nil {
+		return QuantifyResult{}, err
+	}
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.DisallowUnknownFields()
+	var parsed rawResult
+	if err := dec.Decode(&parsed); err != nil {
+		return QuantifyResult{}, fmt.Errorf("schema: %w", err)
+	}
+	if parsed.Options == nil {
+		return QuantifyResult{}, errors.New("schema: options is required")
+	}
+
+	options := make([]QuantifiedBullet, 0, maxOptions)
+	seen := map[string]struct{}{}
+	for i, opt := range *parsed.Options {
+		opt, err := checkOption(i, opt, known)
+		if err != nil {
+			return QuantifyResult{}, err
+		}
+		key := strings.ToLower(opt.Text)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		options = append(options, opt)
+	}
+	if len(options) < minOptions {
+		return QuantifyResult{}, fmt.Errorf("schema: options must have %d-%d distinct items", minOptions, maxOptions)
+	}
+	if len(options) > maxOptions {
+		options = options[:maxOptions]
+	}
+	return QuantifyResult{Options: options}, nil
+}
+
+// checkOption applies the rewrite guardrails to one option.
+func checkOption(i int, opt QuantifiedBullet, known map[string]struct{}) (QuantifiedBullet, error) {
+	opt.Text = strings.Join(strings.Fields(opt.Text), " ")
+	opt.Rationale = strings.TrimSpace(opt.Rationale)
+	if opt.Text == "" {
+		return opt, fmt.Errorf("schema: options[%d].text is required", i)
+	}
+	if utf8.RuneCountInString(opt.Text) > maxOptionRunes {
+		return opt, fmt.Errorf("schema: options[%d].text must be at most %d characters", i, maxOptionRunes)
+	}
+	if term, ok := analyses.ForbiddenImpactTerm(opt.Text); ok {
+		return opt, fmt.Errorf("options[%d].text contains unsupported term %q", i, term)
+	}
+	for _, figure := range figureList(opt.Text) {
+		if _, ok := known[figure]; !ok {
+			return opt, fmt.Errorf("options[%d].text uses figure %q that is not in the bullet or context; use a placeholder", i, figure)
+		}
+	}
+
+	switch opt.ClaimSupport {
+	case "supported", "inferred":
+		if len(opt.PlaceholdersNeeded) > 0 {
+			return opt, fmt.Errorf("options[%d].placeholdersNeeded must be empty when claimSupport=%s", i, opt.ClaimSupport)
+		}
+	case "placeholder":
+		if len(opt.PlaceholdersNeeded) == 0 {
+			return opt, fmt.Errorf("options[%d].placeholdersNeeded required when claimSupport=placeholder", i)
+		}
+	default:
+		return opt, fmt.Errorf("schema: options[%d].claimSupport must be supported, inferred, or placeholder", i)
+	}
+	if len(opt.PlaceholdersNeeded) > maxPlaceholders {
+		return opt, fmt.Errorf("schema: options[%d].placeholdersNeeded has more than %d items", i, maxPlaceholders)
+	}
+	if opt.PlaceholdersNeeded == nil {
+		opt.PlaceholdersNeeded = []Placeholder{}
+	}
+	for j := range opt.PlaceholdersNeeded {
+		p := &opt.PlaceholdersNeeded[j]
+		p.Key = strings.TrimSpace(p.Key)
+		p.Guidance = strings.TrimSpace(p.Guidance)
+		if p.Key == "" || p.Guidance == "" {
+			return opt, fmt.Errorf("schema: options[%d].placeholdersNeeded[%d] needs key and guidance", i, j)
+		}
+		if utf8.RuneCountInString(p.Guidance) > maxGuidanceRunes {
+			return opt, fmt.Errorf("schema: options[%d].placeholdersNeeded[%d].guidance must be at most %d characters", i, j, maxGuidanceRunes)
+		}
+	}
+	return opt, nil
+}
+
+var figurePattern = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+
+// figureList returns the numbers in text, with thousands separators removed so
+// "1,200" and "1200" match.
+func figureList(text string) []string {
+	matches := figurePattern.FindAllString(text, -1)
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, strings.ReplaceAll(m, ",", ""))
+	}
+	return out
+}
+
+func figures(text string) map[string]struct{} {
+	out := map[string]struct{}{}
+	for _, f := range figureList(text) {
+		out[f] = struct{}{}
+	}
+	return out
+}
+
+func extractJSONObject(raw string) (string, error) {
+	payload := strings.TrimSpace(raw)
+	if payload == "" {
+		return "", errors.New("empty llm response")
+	}
+	if json.Valid([]byte(payload)) {
+		return payload, nil
+	}
+	start := strings.Index(payload, "{")
+	end := strings.LastIndex(payload, "}")
+	if start == -1 || end <= start {
+		return "", errors.New("no json object found")
+	}
+	candidate := payload[start : end+1]
+	if !json.Valid([]byte(candidate)) {
+		return "", errors.New("invalid json object")
+	}
+	return candidate, nil
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the audit log admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches audit routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/audit", h.list)
+}
+
+func (h *Handler) list(c *gin.Context) {
+	filter := Filter{
+		ActorUserID:     c.Query("actorUserId"),
+		SubjectUserID:   c.Query("subjectUserId"),
+		ImpersonationID: c.Query("impersonationId"),
+		Action:          c.Query("action"),
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "limit must be a positive integer", nil)
+			return
+		}
+		filter.Limit = limit
+	}
+	entries, err := h.Svc.List(c.Request.Context(), filter)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load audit log", nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"entries": entries})
+}
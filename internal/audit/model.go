@@ -0,0 +1,69 @@
+package audit
+
+import "time"
+
+// Entry is one audited action. SubjectUserID is the user the action was performed
+// on or as, which differs from ActorUserID for support actions.
+type Entry struct {
+	ID              string         `json:"id"`
+	Action          string         `json:"action"`
+	ActorUserID     string         `json:"actorUserId"`
+	SubjectUserID   string         `json:"subjectUserId,omitempty"`
+	ImpersonationID string         `json:"impersonationId,omitempty"`
+	Method          string         `json:"method,omitempty"`
+	Route           string         `json:"route,omitempty"`
+	Status          int            `json:"status,omitempty"`
+	Details         map[string]any `json:"details,omitempty"`
+	CreatedAt       time.Time      `json:"createdAt"`
+}
+
+// Filter narrows List results. Empty fields match everything.
+type Filter struct {
+	ActorUserID     string
+	SubjectUserID   string
+	ImpersonationID string
+	Action          string
+	Limit           int
+}
+
+// ScanFilter selects entries oldest first, resuming after a position. Empty
+// fields match everything.
+type ScanFilter struct {
+	// Since is inclusive and Until exclusive.
+	Since   time.Time
+	Until   time.Time
+	Actions []string
+	// AfterAt and AfterID skip entries up to and including that position in
+	// (created_at, id) order.
+	AfterAt time.Time
+	AfterID string
+	Limit   int
+}
+
+func (f ScanFilter) matches(e Entry) bool {
+	if !f.Since.IsZero() && e.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.CreatedAt.Before(f.Until) {
+		return false
+	}
+	if !f.AfterAt.IsZero() && (e.CreatedAt.Before(f.AfterAt) || (e.CreatedAt.Equal(f.AfterAt) && e.ID <= f.AfterID)) {
+		return false
+	}
+	if len(f.Actions) == 0 {
+		return true
+	}
+	for _, action := range f.Actions {
+		if action == e.Action {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Filter) matches(e Entry) bool {
+	return (f.ActorUserID == "" || e.ActorUserID == f.ActorUserID) &&
+		(f.SubjectUserID == "" || e.SubjectUserID == f.SubjectUserID) &&
+		(f.ImpersonationID == "" || e.ImpersonationID == f.ImpersonationID) &&
+		(f.Action == "" || e.Action == f.Action)
+}
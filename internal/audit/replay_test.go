@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/events"
+)
+
+func TestReplaySourcePagesOldestFirst(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{ID: "e3", Action: "templates.publish", ActorUserID: "admin-1", CreatedAt: base.Add(time.Hour)},
+		{ID: "e2", Action: "impersonation.request", ActorUserID: "admin-1", SubjectUserID: "user-1", ImpersonationID: "imp-1", Method: "GET", Route: "/api/v1/documents", Status: 200, Details: map[string]any{"note": "free text"}, CreatedAt: base},
+		{ID: "e1", Action: "impersonation.start", ActorUserID: "admin-1", CreatedAt: base},
+	} {
+		if err := repo.Record(ctx, e); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	src := &ReplaySource{Repo: repo, HashSalt: "salt"}
+
+	page, err := src.Page(ctx, events.Range{}, events.Cursor{}, 2)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if len(page) != 2 || page[0].IdempotencyKey != "audit/e1" || page[1].IdempotencyKey != "audit/e2" {
+		t.Fatalf("unexpected first page %+v", page)
+	}
+	ev := page[1]
+	if ev.DistinctID != events.HashUserID("salt", "admin-1") || strings.Contains(ev.DistinctID, "admin") {
+		t.Fatalf("expected a hashed actor, got %q", ev.DistinctID)
+	}
+	if ev.Properties["impersonated"] != true || ev.Properties["route"] != "/api/v1/documents" || ev.Properties["note"] != nil {
+		t.Fatalf("unexpected properties %v", ev.Properties)
+	}
+
+	next, err := src.Page(ctx, events.Range{}, events.Cursor{At: ev.Timestamp, Key: ev.IdempotencyKey}, 2)
+	if err != nil || len(next) != 1 || next[0].Name != "templates.publish" {
+		t.Fatalf("unexpected second page %+v (err %v)", next, err)
+	}
+	filtered, err := src.Page(ctx, events.Range{Names: []string{"impersonation.start"}}, events.Cursor{}, 10)
+	if err != nil || len(filtered) != 1 || filtered[0].ID != events.KeyID("audit/e1") {
+		t.Fatalf("unexpected filtered page %+v (err %v)", filtered, err)
+	}
+}
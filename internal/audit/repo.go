@@ -0,0 +1,12 @@
+package audit
+
+import "context"
+
+// Repo persists audit entries. Entries are append-only.
+type Repo interface {
+	Record(ctx context.Context, entry Entry) error
+	// List returns matching entries, newest first.
+	List(ctx context.Context, filter Filter) ([]Entry, error)
+	// Scan returns matching entries oldest first, ordered by (created_at, id).
+	Scan(ctx context.Context, filter ScanFilter) ([]Entry, error)
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo stores audit entries in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+// Record appends an entry.
+func (r *MemoryRepo) Record(ctx context.Context, entry Entry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// List returns matching entries, newest first.
+func (r *MemoryRepo) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Entry, 0)
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if !filter.matches(r.entries[i]) {
+			continue
+		}
+		out = append(out, r.entries[i])
+		if len(out) == filter.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Scan returns matching entries oldest first, ordered by (created_at, id).
+func (r *MemoryRepo) Scan(ctx context.Context, filter ScanFilter) ([]Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Entry, 0)
+	for _, e := range r.entries {
+		if filter.matches(e) {
+			out = append(out, e)
+		}
+	}
+	r.mu.RUnlock()
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
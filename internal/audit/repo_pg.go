@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// Record inserts an entry.
+func (r *PGRepo) Record(ctx context.Context, entry Entry) error {
+	var details []byte
+	if len(entry.Details) > 0 {
+		encoded, err := json.Marshal(entry.Details)
+		if err != nil {
+			return err
+		}
+		details = encoded
+	}
+	const query = `
+INSERT INTO audit_log (
+    id, action, actor_user_id, subject_user_id, impersonation_id,
+    method, route, status, details, created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := r.DB.ExecContext(ctx, query,
+		entry.ID,
+		entry.Action,
+		entry.ActorUserID,
+		entry.SubjectUserID,
+		entry.ImpersonationID,
+		entry.Method,
+		entry.Route,
+		entry.Status,
+		details,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+// List returns matching entries, newest first.
+func (r *PGRepo) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	var where []string
+	var args []any
+	add := func(column, value string) {
+		if value == "" {
+			return
+		}
+		args = append(args, value)
+		where = append(where, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	add("actor_user_id", filter.ActorUserID)
+	add("subject_user_id", filter.SubjectUserID)
+	add("impersonation_id", filter.ImpersonationID)
+	add("action", filter.Action)
+
+	query := selectEntries
+	if len(where) > 0 {
+		query += "\nWHERE " + strings.Join(where, " AND ")
+	}
+	query += "\nORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf("\nLIMIT $%d", len(args))
+	}
+
+	return r.query(ctx, query, args...)
+}
+
+// Scan returns matching entries oldest first, ordered by (created_at, id).
+func (r *PGRepo) Scan(ctx context.Context, filter ScanFilter) ([]Entry, error) {
+	var where []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "created_at >= "+arg(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "created_at < "+arg(filter.Until))
+	}
+	if len(filter.Actions) > 0 {
+		actions := make([]string, 0, len(filter.Actions))
+		for _, action := range filter.Actions {
+			actions = append(actions, arg(action))
+		}
+		where = append(where, "action IN ("+strings.Join(actions, ", ")+")")
+	}
+	if !filter.AfterAt.IsZero() {
+		where = append(where, fmt.Sprintf("(created_at, id) > (%s, %s)", arg(filter.AfterAt), arg(filter.AfterID)))
+	}
+
+	query := selectEntries
+	if len(where) > 0 {
+		query += "\nWHERE " + strings.Join(where, " AND ")
+	}
+	query += "\nORDER BY created_at, id"
+	if filter.Limit > 0 {
+		query += "\nLIMIT " + arg(filter.Limit)
+	}
+	return r.query(ctx, query, args...)
+}
+
+const selectEntries = `
+SELECT id, action, actor_user_id, subject_user_id, impersonation_id, method, route, status, details, created_at
+FROM audit_log`
+
+func (r *PGRepo) query(ctx context.Context, query string, args ...any) ([]Entry, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Entry
+	for rows.Next() {
+		var e Entry
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.Action, &e.ActorUserID, &e.SubjectUserID, &e.ImpersonationID,
+			&e.Method, &e.Route, &e.Status, &details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				e.Details = nil
+			}
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
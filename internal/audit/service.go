@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 500
+)
+
+// Service records and lists audit entries.
+type Service struct {
+	Repo Repo
+	Now  func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo) *Service {
+	return &Service{Repo: repo}
+}
+
+// Record stores an entry, filling in its ID and timestamp. A failed write is logged
+// and returned so callers that must not proceed unaudited can stop.
+func (s *Service) Record(ctx context.Context, entry Entry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = s.now()
+	}
+	if err := s.Repo.Record(ctx, entry); err != nil {
+		telemetry.Error("audit.record_failed", map[string]any{
+			"action":           entry.Action,
+			"actor_user_id":    entry.ActorUserID,
+			"impersonation_id": entry.ImpersonationID,
+			"error":            err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// List returns matching entries, newest first.
+func (s *Service) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	return s.Repo.List(ctx, filter)
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,106 @@
+package badges
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// PublicPathPrefix is served without authentication; the token is the credential.
+const PublicPathPrefix = "/api/v1/badge/"
+
+// badgeMaxAge lets image proxies cache badges while still picking up re-analyses
+// within the hour.
+const badgeMaxAge = "public, max-age=3600"
+
+// Handler wires HTTP handlers to the badges service.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches badge routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/analyses/:id/badges", h.create)
+	rg.DELETE("/badges/:id", h.revoke)
+	rg.GET("/badge/:file", h.serve)
+}
+
+func (h *Handler) create(c *gin.Context) {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to share a score badge", nil)
+		return
+	}
+	badge, token, err := h.Svc.Create(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusCreated, gin.H{
+		"id":         badge.ID,
+		"analysisId": badge.AnalysisID,
+		"token":      token,
+		"url":        PublicPathPrefix + token + ".svg",
+		"createdAt":  badge.CreatedAt,
+	})
+}
+
+func (h *Handler) revoke(c *gin.Context) {
+	if err := h.Svc.Revoke(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) serve(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("file"), ".svg")
+	if !ok {
+		c.Header("Cache-Control", "no-store")
+		writeError(c, ErrNotFound)
+		return
+	}
+	view, err := h.Svc.Resolve(c.Request.Context(), token)
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		writeError(c, err)
+		return
+	}
+
+	svg := RenderSVG(view)
+	sum := sha256.Sum256(svg)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("Cache-Control", badgeMaxAge)
+	c.Header("ETag", etag)
+	if !view.AnalyzedAt.IsZero() {
+		c.Header("Last-Modified", view.AnalyzedAt.Format(http.TimeFormat))
+	}
+	if match := c.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", svg)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "badge not found", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to process badge", nil)
+	}
+}
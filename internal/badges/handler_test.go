@@ -0,0 +1,135 @@
+package badges_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+func newTestApp(t *testing.T) *bootstrap.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	return app
+}
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func doRequest(router http.Handler, method, path, authorization string, headers map[string]string) *httptest.ResponseRecorder {
+	var body io.Reader = http.NoBody
+	if method == http.MethodPost {
+		body = bytes.NewReader([]byte("{}"))
+	}
+	req := httptest.NewRequest(method, path, body)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestScoreBadgeIsPublicAndCacheable(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	completedAt := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	for _, a := range []analyses.Analysis{
+		{ID: "analysis-done", DocumentID: "doc-1", UserID: "user-1", Mode: analyses.ModeJobMatch, Status: analyses.StatusCompleted, CompletedAt: &completedAt, Result: map[string]any{"finalScore": 86.4}, CreatedAt: completedAt},
+		{ID: "analysis-queued", DocumentID: "doc-2", UserID: "user-1", Mode: analyses.ModeJobMatch, Status: analyses.StatusQueued, CreatedAt: completedAt},
+	} {
+		if err := app.AnalysesRepo.Create(ctx, a); err != nil {
+			t.Fatalf("create analysis: %v", err)
+		}
+	}
+	owner := bearer(t, "user-1")
+
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/analyses/analysis-done/badges", bearer(t, "user-2"), nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("other user: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/analyses/analysis-queued/badges", owner, nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("queued analysis: expected 400, got %d", resp.Code)
+	}
+	resp := doRequest(app.Router, http.MethodPost, "/api/v1/analyses/analysis-done/badges", owner, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create badge: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode badge: %v", err)
+	}
+	if !strings.HasPrefix(created.URL, "/api/v1/badge/") || !strings.HasSuffix(created.URL, ".svg") {
+		t.Fatalf("unexpected badge url %q", created.URL)
+	}
+
+	badge := doRequest(app.Router, http.MethodGet, created.URL, "", nil)
+	if badge.Code != http.StatusOK {
+		t.Fatalf("badge: expected 200, got %d: %s", badge.Code, badge.Body.String())
+	}
+	if ct := badge.Header().Get("Content-Type"); !strings.HasPrefix(ct, "image/svg+xml") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cc := badge.Header().Get("Cache-Control"); !strings.Contains(cc, "max-age") {
+		t.Fatalf("expected cacheable badge, got Cache-Control %q", cc)
+	}
+	svg := badge.Body.String()
+	if !strings.Contains(svg, "86/100") || !strings.Contains(svg, "Mar 2026") {
+		t.Fatalf("badge is missing score or date: %s", svg)
+	}
+	etag := badge.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected an ETag")
+	}
+	if resp := doRequest(app.Router, http.MethodGet, created.URL, "", map[string]string{"If-None-Match": etag}); resp.Code != http.StatusNotModified {
+		t.Fatalf("conditional request: expected 304, got %d", resp.Code)
+	}
+
+	if resp := doRequest(app.Router, http.MethodGet, strings.TrimSuffix(created.URL, ".svg")+".png", "", nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("non-svg badge: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodDelete, "/api/v1/badges/"+created.ID, bearer(t, "user-2"), nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("revoke by other user: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodDelete, "/api/v1/badges/"+created.ID, owner, nil); resp.Code != http.StatusNoContent {
+		t.Fatalf("revoke: expected 204, got %d", resp.Code)
+	}
+	resp = doRequest(app.Router, http.MethodGet, created.URL, "", nil)
+	if resp.Code != http.StatusNotFound || resp.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("revoked badge: expected uncached 404, got %d %q", resp.Code, resp.Header().Get("Cache-Control"))
+	}
+}
@@ -0,0 +1,21 @@
+package badges
+
+import "time"
+
+// Badge publishes the score of one analysis at an unguessable URL. Only a hash
+// of the token is stored.
+type Badge struct {
+	ID         string
+	TokenHash  string
+	AnalysisID string
+	UserID     string
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// View is what a badge shows.
+type View struct {
+	// Score is nil until the analysis has a final score.
+	Score      *float64
+	AnalyzedAt time.Time
+}
@@ -0,0 +1,14 @@
+package badges
+
+import (
+	"context"
+	"time"
+)
+
+// Repo defines persistence for badges.
+type Repo interface {
+	Create(ctx context.Context, badge Badge) error
+	// GetByTokenHash returns an unrevoked badge.
+	GetByTokenHash(ctx context.Context, tokenHash string) (Badge, error)
+	Revoke(ctx context.Context, userID, id string, revokedAt time.Time) error
+}
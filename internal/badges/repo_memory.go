@@ -0,0 +1,62 @@
+package badges
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepo stores badges in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu   sync.RWMutex
+	byID map[string]Badge
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{byID: make(map[string]Badge)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Create stores a new badge.
+func (r *MemoryRepo) Create(ctx context.Context, badge Badge) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byID[badge.ID] = badge
+	return nil
+}
+
+// GetByTokenHash returns an unrevoked badge.
+func (r *MemoryRepo) GetByTokenHash(ctx context.Context, tokenHash string) (Badge, error) {
+	if err := ctx.Err(); err != nil {
+		return Badge{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, badge := range r.byID {
+		if badge.TokenHash == tokenHash && badge.RevokedAt == nil {
+			return badge, nil
+		}
+	}
+	return Badge{}, ErrNotFound
+}
+
+// Revoke disables one of the user's badges.
+func (r *MemoryRepo) Revoke(ctx context.Context, userID, id string, revokedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	badge, ok := r.byID[id]
+	if !ok || badge.UserID != userID || badge.RevokedAt != nil {
+		return ErrNotFound
+	}
+	badge.RevokedAt = &revokedAt
+	r.byID[id] = badge
+	return nil
+}
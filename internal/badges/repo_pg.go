@@ -0,0 +1,72 @@
+package badges
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// Create inserts a badge.
+func (r *PGRepo) Create(ctx context.Context, badge Badge) error {
+	const query = `
+INSERT INTO analysis_badges (
+    id,
+    token_hash,
+    analysis_id,
+    user_id,
+    created_at
+) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.DB.ExecContext(ctx, query, badge.ID, badge.TokenHash, badge.AnalysisID, badge.UserID, badge.CreatedAt)
+	return err
+}
+
+// GetByTokenHash returns an unrevoked badge.
+func (r *PGRepo) GetByTokenHash(ctx context.Context, tokenHash string) (Badge, error) {
+	const query = `
+SELECT id, token_hash, analysis_id, user_id, created_at
+FROM analysis_badges
+WHERE token_hash = $1 AND revoked_at IS NULL`
+	var badge Badge
+	err := r.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&badge.ID,
+		&badge.TokenHash,
+		&badge.AnalysisID,
+		&badge.UserID,
+		&badge.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Badge{}, ErrNotFound
+	}
+	if err != nil {
+		return Badge{}, err
+	}
+	return badge, nil
+}
+
+// Revoke disables one of the user's badges.
+func (r *PGRepo) Revoke(ctx context.Context, userID, id string, revokedAt time.Time) error {
+	const query = `
+UPDATE analysis_badges
+SET revoked_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, query, id, userID, revokedAt)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
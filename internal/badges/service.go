@@ -0,0 +1,141 @@
+package badges
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+)
+
+// AnalysisLookup loads analyses by ID.
+type AnalysisLookup interface {
+	GetByID(ctx context.Context, analysisID string) (analyses.Analysis, error)
+}
+
+// Service issues badge links and resolves them for the public badge endpoint.
+type Service struct {
+	Repo     Repo
+	Analyses AnalysisLookup
+	Now      func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, analysisLookup AnalysisLookup) *Service {
+	return &Service{Repo: repo, Analyses: analysisLookup}
+}
+
+// Create issues a badge for a completed analysis owned by userID and returns it
+// with the plaintext token, which is not stored.
+func (s *Service) Create(ctx context.Context, userID, analysisID string) (Badge, string, error) {
+	if userID == "" || analysisID == "" {
+		return Badge{}, "", ErrInvalidInput
+	}
+	analysis, err := s.analysis(ctx, analysisID)
+	if err != nil {
+		return Badge{}, "", err
+	}
+	if analysis.UserID != userID {
+		return Badge{}, "", ErrNotFound
+	}
+	if analysis.Status != analyses.StatusCompleted {
+		return Badge{}, "", fmt.Errorf("%w: analysis is not completed", ErrInvalidInput)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return Badge{}, "", err
+	}
+	badge := Badge{
+		ID:         uuid.NewString(),
+		TokenHash:  hashToken(token),
+		AnalysisID: analysis.ID,
+		UserID:     userID,
+		CreatedAt:  s.now(),
+	}
+	if err := s.Repo.Create(ctx, badge); err != nil {
+		return Badge{}, "", err
+	}
+	return badge, token, nil
+}
+
+// Revoke disables one of the user's badges.
+func (s *Service) Revoke(ctx context.Context, userID, id string) error {
+	if userID == "" || id == "" {
+		return ErrInvalidInput
+	}
+	return s.Repo.Revoke(ctx, userID, id, s.now())
+}
+
+// Resolve returns what the badge behind token shows. Revoked badges and badges
+// whose analysis was deleted are not found.
+func (s *Service) Resolve(ctx context.Context, token string) (View, error) {
+	if token == "" {
+		return View{}, ErrNotFound
+	}
+	badge, err := s.Repo.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return View{}, err
+	}
+	analysis, err := s.analysis(ctx, badge.AnalysisID)
+	if err != nil {
+		return View{}, err
+	}
+	if analysis.UserID != badge.UserID {
+		return View{}, ErrNotFound
+	}
+	view := View{AnalyzedAt: analyzedAt(analysis)}
+	if score, ok := analyses.FinalScore(analysis); ok {
+		view.Score = &score
+	}
+	return view, nil
+}
+
+func (s *Service) analysis(ctx context.Context, analysisID string) (analyses.Analysis, error) {
+	analysis, err := s.Analyses.GetByID(ctx, analysisID)
+	if err != nil {
+		if errors.Is(err, analyses.ErrNotFound) {
+			return analyses.Analysis{}, ErrNotFound
+		}
+		return analyses.Analysis{}, err
+	}
+	return analysis, nil
+}
+
+func analyzedAt(a analyses.Analysis) time.Time {
+	switch {
+	case a.AnalysisCompletedAt != nil:
+		return a.AnalysisCompletedAt.UTC()
+	case a.CompletedAt != nil:
+		return a.CompletedAt.UTC()
+	default:
+		return a.UpdatedAt.UTC()
+	}
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func newToken() (string, error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate badge token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
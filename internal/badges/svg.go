@@ -0,0 +1,71 @@
+package badges
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+)
+
+const (
+	badgeLabel = "ATS score"
+	// charWidth approximates the advance of an 11px Verdana character; badges
+	// only hold short ASCII text, so an estimate keeps rendering dependency-free.
+	charWidth  = 7
+	badgePad   = 10
+	badgeGrey  = "#555"
+	noScoreHex = "#9f9f9f"
+)
+
+// RenderSVG draws a flat two-part badge: the label on the left, the score and
+// the date it was analyzed on the right.
+func RenderSVG(view View) []byte {
+	value := "pending"
+	color := noScoreHex
+	if view.Score != nil {
+		score := math.Round(*view.Score)
+		value = fmt.Sprintf("%.0f/100", score)
+		color = scoreColor(score)
+	}
+	if !view.AnalyzedAt.IsZero() {
+		value += " · " + view.AnalyzedAt.Format("Jan 2006")
+	}
+
+	labelWidth := textWidth(badgeLabel)
+	valueWidth := textWidth(value)
+	width := labelWidth + valueWidth
+	title := html.EscapeString(badgeLabel + ": " + value)
+	label := html.EscapeString(badgeLabel)
+	text := html.EscapeString(value)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`, width, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	b.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="%s"/>`, labelWidth, badgeGrey)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, valueWidth, color)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	b.WriteString(`</g>`)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth+valueWidth/2, text)
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
+
+func textWidth(s string) int {
+	return len([]rune(s))*charWidth + 2*badgePad
+}
+
+func scoreColor(score float64) string {
+	switch {
+	case score >= 80:
+		return "#4c1"
+	case score >= 60:
+		return "#dfb317"
+	default:
+		return "#e05d44"
+	}
+}
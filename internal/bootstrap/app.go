@@ -2,48 +2,96 @@ package bootstrap
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
 
+	"resume-backend/internal/abuse"
 	"resume-backend/internal/account"
+	"resume-backend/internal/admin"
 	"resume-backend/internal/analyses"
 	"resume-backend/internal/applies"
+	"resume-backend/internal/artifacts"
+	"resume-backend/internal/assist"
+	"resume-backend/internal/audit"
 	googleauth "resume-backend/internal/auth"
+	"resume-backend/internal/badges"
 	"resume-backend/internal/documents"
+	"resume-backend/internal/events"
+	"resume-backend/internal/extract"
+	"resume-backend/internal/fairness"
+	"resume-backend/internal/featureflags"
 	"resume-backend/internal/generatedresumes"
+	"resume-backend/internal/impersonation"
+	"resume-backend/internal/insights"
+	"resume-backend/internal/integrations"
+	"resume-backend/internal/jobdescriptions"
 	"resume-backend/internal/llm"
 	openai "resume-backend/internal/llm/openai"
+	"resume-backend/internal/llmarchive"
+	"resume-backend/internal/llmbudget"
+	"resume-backend/internal/llmhealth"
+	"resume-backend/internal/onboarding"
+	"resume-backend/internal/pipelinestatus"
+	"resume-backend/internal/pools"
+	"resume-backend/internal/profilestrength"
 	"resume-backend/internal/queue"
+	"resume-backend/internal/rescore"
+	"resume-backend/internal/residency"
+	"resume-backend/internal/resumes"
+	"resume-backend/internal/retention"
+	"resume-backend/internal/rollout"
 	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/faults"
+	"resume-backend/internal/shared/fieldcrypt"
+	"resume-backend/internal/shared/runtimeconfig"
+	"resume-backend/internal/shared/secrets"
 	"resume-backend/internal/shared/server"
+	"resume-backend/internal/shared/server/middleware"
 	"resume-backend/internal/shared/storage/db"
 	"resume-backend/internal/shared/storage/object"
 	localstore "resume-backend/internal/shared/storage/object/local"
 	s3store "resume-backend/internal/shared/storage/object/s3"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/internal/slo"
+	"resume-backend/internal/stuck"
+	"resume-backend/internal/templates"
 	"resume-backend/internal/usage"
 	"resume-backend/internal/users"
+	"resume-backend/resume/model"
+	resumeservice "resume-backend/resume/service"
 )
 
 const (
 	uploadsDefaultRegion = "us-east-1"
 	uploadsDefaultPrefix = "documents/"
+	converterTimeout     = 30 * time.Second
 )
 
 // App holds shared dependencies. Router is intentionally left nil for now.
 type App struct {
-	Config                  config.Config
-	Router                  *gin.Engine
-	DB                      *sql.DB
-	Store                   object.ObjectStore
-	Queue                   queue.Client
+	Config config.Config
+	Router *gin.Engine
+	DB     *sql.DB
+	// UnitOfWork makes writes that span repos atomic.
+	UnitOfWork db.UnitOfWork
+	Store      object.ObjectStore
+	// StorageLatency times the calls made to Store.
+	StorageLatency *pipelinestatus.StorageLatency
+	Queue          queue.Client
+	// LocalQueue is the queue behind Queue under RA_PROFILE=standalone, which
+	// the API process consumes itself; nil otherwise.
+	LocalQueue              queue.LocalQueue
 	UploadsPresign          *s3.PresignClient
 	UploadsBucket           string
 	UploadsPrefix           string
@@ -54,19 +102,69 @@ type App struct {
 	DocumentsService        *documents.Service
 	UsageService            *usage.Service
 	AnalysesService         *analyses.Service
+	S3Documents             analyses.S3DocumentReader
 	AnalysisProcessor       AnalysisProcessor
+	AnalysisExtractor       AnalysisExtractor
+	DocumentExtractor       DocumentExtractor
+	ExtractQueue            queue.Client
+	Dedup                   queue.Deduper
 	GeneratedResumesService *generatedresumes.Service
 	ApplyService            *applies.Service
 	AccountService          *account.Service
+	RetentionService        *retention.Service
+	ArtifactsService        *artifacts.Service
+	BadgesService           *badges.Service
+	FairnessMonitor         *fairness.Monitor
+	PromptRollout           *rollout.Service
+	FeatureFlags            *featureflags.Service
+	LLMBudget               *llmbudget.Service
+	LLMHealth               *llmhealth.Monitor
+	Stuck                   *stuck.Detector
+	PipelineStatus          *pipelinestatus.Service
+	Rescore                 *rescore.Service
+	SLO                     *slo.Tracker
+	PoolsService            *pools.Service
+	TemplatesService        *templates.Service
+	IntegrationsService     *integrations.Service
+	Residency               *residency.Service
+	Events                  *events.Emitter
+	AuditService            *audit.Service
+	Impersonation           *impersonation.Service
 	UsersService            *users.Service
 	DocumentsHandler        *documents.Handler
 	AnalysisHandler         *analyses.Handler
+	JobDescriptionsHandler  *jobdescriptions.Handler
+	AssistHandler           *assist.Handler
 	ApplyHandler            *applies.Handler
+	ArtifactsHandler        *artifacts.Handler
+	BadgesHandler           *badges.Handler
+	AdminHandler            *admin.Handler
 	AccountHandler          *account.Handler
 	UsageHandler            *usage.Handler
 	UsersHandler            *users.Handler
+	PoolsHandler            *pools.Handler
+	ResumesService          *resumes.Service
+	ResumesHandler          *resumes.Handler
+	IntegrationsHandler     *integrations.Handler
+	ProfileHandler          *profilestrength.Handler
+	OnboardingHandler       *onboarding.Handler
+	InsightsHandler         *insights.Handler
 	GoogleAuth              *googleauth.GoogleService
-	Services                map[string]any
+	// FieldCodec encrypts PII columns; nil when PII_KEYS is unset in dev.
+	FieldCodec *fieldcrypt.Codec
+	// EncryptedColumns are the columns FieldCodec protects, for the backfill.
+	EncryptedColumns []fieldcrypt.Column
+	// LLMArchive keeps the compliance archive of LLM calls.
+	LLMArchive *llmarchive.Service
+	// Abuse flags guest clusters farming the free tier.
+	Abuse *abuse.Detector
+	// Readiness flips to not-ready while the process drains on shutdown.
+	Readiness *server.Readiness
+	// RateLimits are the API rate limit rules; runtime config reloads replace them.
+	RateLimits *middleware.RateLimitRules
+	// RuntimeConfig reloads operational settings; nil when no source is configured.
+	RuntimeConfig *runtimeconfig.Watcher
+	Services      map[string]any
 }
 
 // AnalysisProcessor allows callers to override analysis processing for tests.
@@ -74,6 +172,16 @@ type AnalysisProcessor interface {
 	ProcessAnalysis(ctx context.Context, analysisID string) error
 }
 
+// AnalysisExtractor runs the extract stage of an analysis.
+type AnalysisExtractor interface {
+	ExtractForAnalysis(ctx context.Context, analysisID string) error
+}
+
+// DocumentExtractor extracts a document's text outside of any analysis.
+type DocumentExtractor interface {
+	ExtractDocument(ctx context.Context, userID, documentID string) (documents.Document, error)
+}
+
 // Build prepares shared dependencies without wiring routes.
 func Build(cfg config.Config) (*App, error) {
 	if strings.TrimSpace(cfg.Env) == "" {
@@ -82,6 +190,12 @@ func Build(cfg config.Config) (*App, error) {
 	if strings.TrimSpace(cfg.ObjectStoreType) == "" {
 		cfg.ObjectStoreType = "local"
 	}
+	if strings.TrimSpace(cfg.DefaultResidency) == "" {
+		cfg.DefaultResidency = string(residency.RegionUS)
+	}
+	if err := telemetry.ConfigureFromEnv(cfg.Env); err != nil {
+		return nil, err
+	}
 	ctx := context.Background()
 
 	sqlDB, err := buildDB(ctx, cfg)
@@ -94,25 +208,72 @@ func Build(cfg config.Config) (*App, error) {
 		return nil, err
 	}
 
-	queueClient, err := buildQueue(ctx)
-	if err != nil {
-		return nil, err
+	var (
+		queueClient, extractQueue queue.Client
+		localQueue                queue.LocalQueue
+	)
+	if cfg.Standalone() {
+		if localQueue, err = buildStandalone(ctx, cfg, sqlDB); err != nil {
+			return nil, err
+		}
+		queueClient = localQueue
+	} else {
+		if queueClient, err = buildQueue(ctx); err != nil {
+			return nil, err
+		}
+		if extractQueue, err = buildExtractQueue(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	extract.ConfigureCommandConverters(os.Getenv("RA_DOC_CONVERTER_CMD"), os.Getenv("RA_PAGES_CONVERTER_CMD"), converterTimeout)
+
+	if config.IsDevLike(cfg.Env) {
+		store = faults.WrapStore(store, faults.ConfigFromEnv("store"))
+		queueClient = faults.WrapQueue(queueClient, faults.ConfigFromEnv("queue"))
+		if extractQueue != nil {
+			extractQueue = faults.WrapQueue(extractQueue, faults.ConfigFromEnv("queue"))
+		}
 	}
 
-	presign, bucket, prefix, err := buildUploadsPresign(ctx)
+	regional, err := buildRegionalStore(ctx, cfg, store)
 	if err != nil {
 		return nil, err
 	}
+	// Storage calls are timed for the operator pipeline snapshot.
+	storageLatency := pipelinestatus.NewStorageLatency()
+	regional.Observe = storageLatency.Observe
+	store = regional
+
+	var (
+		presign        *s3.PresignClient
+		bucket, prefix string
+		s3Docs         analyses.S3DocumentReader
+	)
+	if !cfg.Standalone() {
+		if presign, bucket, prefix, err = buildUploadsPresign(ctx); err != nil {
+			return nil, err
+		}
+		if s3Docs, err = buildS3Documents(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	app := &App{
 		Config:         cfg,
 		Router:         nil,
 		DB:             sqlDB,
 		Store:          store,
+		StorageLatency: storageLatency,
 		Queue:          queueClient,
+		LocalQueue:     localQueue,
+		ExtractQueue:   extractQueue,
 		UploadsPresign: presign,
 		UploadsBucket:  bucket,
 		UploadsPrefix:  prefix,
+		S3Documents:    s3Docs,
+		Readiness:      &server.Readiness{},
+		RateLimits:     middleware.NewRateLimitRules(server.DefaultRateLimits()),
 		Services:       map[string]any{},
 	}
 
@@ -121,22 +282,69 @@ func Build(cfg config.Config) (*App, error) {
 	}
 
 	app.Router = server.NewRouter(server.RouterDeps{
-		Config:          app.Config,
-		AccountHandler:  app.AccountHandler,
-		AnalysisHandler: app.AnalysisHandler,
-		ApplyHandler:    app.ApplyHandler,
-		DocumentHandler: app.DocumentsHandler,
-		UsageHandler:    app.UsageHandler,
-		UserHandler:     app.UsersHandler,
-		GoogleAuth:      app.GoogleAuth,
+		Config:              app.Config,
+		AccountHandler:      app.AccountHandler,
+		AnalysisHandler:     app.AnalysisHandler,
+		ApplyHandler:        app.ApplyHandler,
+		DocumentHandler:     app.DocumentsHandler,
+		JobDescHandler:      app.JobDescriptionsHandler,
+		AssistHandler:       app.AssistHandler,
+		ArtifactHandler:     app.ArtifactsHandler,
+		BadgeHandler:        app.BadgesHandler,
+		AdminHandler:        app.AdminHandler,
+		UsageHandler:        app.UsageHandler,
+		UserHandler:         app.UsersHandler,
+		PoolsHandler:        app.PoolsHandler,
+		ResumesHandler:      app.ResumesHandler,
+		IntegrationsHandler: app.IntegrationsHandler,
+		ProfileHandler:      app.ProfileHandler,
+		OnboardingHandler:   app.OnboardingHandler,
+		Abuse:               app.Abuse,
+		InsightsHandler:     app.InsightsHandler,
+		GoogleAuth:          app.GoogleAuth,
+		Events:              app.Events,
+		Impersonation:       app.Impersonation,
+		Readiness:           app.Readiness,
+		RateLimits:          app.RateLimits,
 	})
 
+	app.RuntimeConfig = runtimeconfig.FromEnv(app.ApplyRuntimeSettings)
+	if app.RuntimeConfig != nil {
+		// A bad document is logged and the defaults stay in effect.
+		_ = app.RuntimeConfig.Reload(ctx)
+	}
+
 	return app, nil
 }
 
+// ApplyRuntimeSettings installs reloadable settings. Settings the document omits
+// return to their defaults.
+func (app *App) ApplyRuntimeSettings(settings runtimeconfig.Settings) error {
+	level, err := telemetry.ParseLevel(settings.LogLevel)
+	if err != nil {
+		return err
+	}
+	if err := rollout.SetStages(settings.RolloutStages); err != nil {
+		return err
+	}
+	rules := server.DefaultRateLimits()
+	for group, limit := range settings.RateLimits {
+		rules[group] = middleware.RateLimitRule{Rate: limit.Rate, Burst: limit.Burst}
+	}
+	app.RateLimits.Set(rules)
+	telemetry.SetLevel(level)
+	analyses.SetForbiddenImpactTerms(settings.ForbiddenImpactTerms)
+	var limits analyses.ResultLimits
+	if l := settings.ResultLimits; l != nil {
+		limits = analyses.ResultLimits{Issues: l.Issues, BulletRewrites: l.BulletRewrites, Recommendations: l.Recommendations}
+	}
+	analyses.SetResultLimits(limits)
+	return nil
+}
+
 func buildDB(ctx context.Context, cfg config.Config) (*sql.DB, error) {
 	if strings.TrimSpace(cfg.DatabaseURL) == "" {
-		if isDevLike(cfg.Env) {
+		if config.IsDevLike(cfg.Env) || cfg.Standalone() {
 			log.Printf("bootstrap: DATABASE_URL empty; using in-memory repositories")
 			return nil, nil
 		}
@@ -148,14 +356,12 @@ func buildDB(ctx context.Context, cfg config.Config) (*sql.DB, error) {
 		err   error
 	)
 	if db.IsLambdaRuntime() {
-		opts := db.OptionsFromEnv(db.DefaultLambdaOptions())
-		sqlDB, err = db.GetSingleton(ctx, cfg.DatabaseURL, opts)
+		sqlDB, err = db.GetSingleton(ctx, cfg.DatabaseURL, dbOptions())
 	} else {
-		opts := db.OptionsFromEnv(db.DefaultServerOptions())
-		sqlDB, err = db.Connect(ctx, cfg.DatabaseURL, opts)
+		sqlDB, err = db.Connect(ctx, cfg.DatabaseURL, dbOptions())
 	}
 	if err != nil {
-		if isDevLike(cfg.Env) {
+		if config.IsDevLike(cfg.Env) {
 			log.Printf("bootstrap: database connect failed; using in-memory repositories: %v", err)
 			return nil, nil
 		}
@@ -165,6 +371,13 @@ func buildDB(ctx context.Context, cfg config.Config) (*sql.DB, error) {
 	return sqlDB, nil
 }
 
+func dbOptions() db.Options {
+	if db.IsLambdaRuntime() {
+		return db.OptionsFromEnv(db.DefaultLambdaOptions())
+	}
+	return db.OptionsFromEnv(db.DefaultServerOptions())
+}
+
 func buildStore(ctx context.Context, cfg config.Config) (object.ObjectStore, error) {
 	switch cfg.ObjectStoreType {
 	case "s3":
@@ -177,6 +390,47 @@ func buildStore(ctx context.Context, cfg config.Config) (object.ObjectStore, err
 	}
 }
 
+// buildRegionalStore routes objects by data residency. The default region uses
+// base; other regions use S3_BUCKET_<REGION> (with optional AWS_REGION_<REGION>
+// and SSE_KMS_KEY_ID_<REGION>) or, for the local store, a subdirectory.
+func buildRegionalStore(ctx context.Context, cfg config.Config, base object.ObjectStore) (*residency.Store, error) {
+	def, err := residency.ParseRegion(cfg.DefaultResidency)
+	if err != nil {
+		return nil, fmt.Errorf("DEFAULT_RESIDENCY: %w", err)
+	}
+	regional := residency.NewStore(def, base)
+	for _, region := range residency.Regions {
+		if region == def {
+			continue
+		}
+		suffix := strings.ToUpper(string(region))
+		var store object.ObjectStore
+		switch cfg.ObjectStoreType {
+		case "s3":
+			bucket := strings.TrimSpace(os.Getenv("S3_BUCKET_" + suffix))
+			if bucket == "" {
+				log.Printf("bootstrap: S3_BUCKET_%s is not set; %s residency disabled", suffix, region)
+				continue
+			}
+			awsRegion := strings.TrimSpace(os.Getenv("AWS_REGION_" + suffix))
+			if awsRegion == "" {
+				awsRegion = cfg.AWSRegion
+			}
+			store, err = s3store.New(ctx, awsRegion, bucket, cfg.S3Prefix, os.Getenv("SSE_KMS_KEY_ID_"+suffix))
+			if err != nil {
+				return nil, err
+			}
+		default:
+			store = localstore.New(filepath.Join(cfg.LocalStoreDir, string(region)))
+		}
+		if config.IsDevLike(cfg.Env) {
+			store = faults.WrapStore(store, faults.ConfigFromEnv("store"))
+		}
+		regional.Stores[region] = store
+	}
+	return regional, nil
+}
+
 func buildQueue(ctx context.Context) (queue.Client, error) {
 	if strings.TrimSpace(os.Getenv("RA_SQS_QUEUE_URL")) == "" {
 		return nil, nil
@@ -184,13 +438,13 @@ func buildQueue(ctx context.Context) (queue.Client, error) {
 	return queue.NewSQSClient(ctx)
 }
 
-func isDevLike(env string) bool {
-	switch strings.ToLower(strings.TrimSpace(env)) {
-	case "dev", "local":
-		return true
-	default:
-		return false
+// buildExtractQueue enables the dedicated extract stage when its queue is configured.
+func buildExtractQueue(ctx context.Context) (queue.Client, error) {
+	queueURL := strings.TrimSpace(os.Getenv("RA_SQS_EXTRACT_QUEUE_URL"))
+	if queueURL == "" {
+		return nil, nil
 	}
+	return queue.NewSQSClientForURL(ctx, queueURL)
 }
 
 func buildUploadsPresign(ctx context.Context) (*s3.PresignClient, string, string, error) {
@@ -220,29 +474,237 @@ func buildUploadsPresign(ctx context.Context) (*s3.PresignClient, string, string
 	return s3.NewPresignClient(client), bucket, prefix, nil
 }
 
+// buildS3Documents builds the shared reader for documents in the uploads bucket,
+// or returns nil when no bucket is configured.
+func buildS3Documents(ctx context.Context) (analyses.S3DocumentReader, error) {
+	opts := analyses.S3DocumentOptionsFromEnv()
+	if opts.Bucket == "" {
+		return nil, nil
+	}
+	reader, err := analyses.NewS3DocumentReader(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("s3 document reader: %w", err)
+	}
+	return reader, nil
+}
+
+// buildEvents returns the funnel analytics emitter, or nil when analytics is off.
+// Outside dev-like envs a hash salt is required so user IDs cannot be recovered.
+func buildEvents(app *App) *events.Emitter {
+	cfg := app.Config
+	var sink events.Sink
+	switch cfg.AnalyticsSink {
+	case "":
+		return nil
+	case "log":
+		sink = events.LogSink{}
+	case "segment":
+		sink = events.NewSegmentSink(cfg.AnalyticsKey, cfg.AnalyticsHost)
+	case "posthog":
+		sink = events.NewPostHogSink(cfg.AnalyticsKey, cfg.AnalyticsHost)
+	default:
+		log.Printf("bootstrap: unknown ANALYTICS_SINK %q; analytics disabled", cfg.AnalyticsSink)
+		return nil
+	}
+	if cfg.AnalyticsSink != "log" && strings.TrimSpace(cfg.AnalyticsKey) == "" {
+		log.Printf("bootstrap: ANALYTICS_KEY is required for %s; analytics disabled", cfg.AnalyticsSink)
+		return nil
+	}
+	if strings.TrimSpace(cfg.AnalyticsHashSalt) == "" && !config.IsDevLike(cfg.Env) {
+		log.Printf("bootstrap: ANALYTICS_HASH_SALT is required outside dev; analytics disabled")
+		return nil
+	}
+	var once events.OnceStore
+	if app.DB != nil {
+		once = &events.PGOnceStore{DB: app.DB}
+	}
+	opts := events.DefaultOptions()
+	opts.HashSalt = cfg.AnalyticsHashSalt
+	return events.NewEmitter(sink, once, opts)
+}
+
+// buildSecrets returns the credential store, or nil when SECRETS_KEY is unset
+// outside dev. Dev-like envs fall back to a per-process key, so stored
+// credentials do not survive a restart.
+func buildSecrets(cfg config.Config, backend secrets.Backend) (*secrets.Store, error) {
+	if strings.TrimSpace(cfg.SecretsKey) != "" {
+		key, err := secrets.ParseKey(cfg.SecretsKey)
+		if err != nil {
+			return nil, fmt.Errorf("SECRETS_KEY: %w", err)
+		}
+		return secrets.New(key, backend)
+	}
+	if !config.IsDevLike(cfg.Env) {
+		log.Printf("bootstrap: SECRETS_KEY is not set; integrations disabled")
+		return nil, nil
+	}
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("bootstrap: SECRETS_KEY is not set; using an ephemeral key")
+	return secrets.New(key, backend)
+}
+
+// buildFieldCodec returns the PII column codec, or nil when PII_KEYS is unset
+// in dev. Production keys are KMS-wrapped and unwrapped once at startup.
+func buildFieldCodec(ctx context.Context, cfg config.Config) (*fieldcrypt.Codec, error) {
+	if strings.TrimSpace(cfg.PIIKeys) == "" {
+		if !config.IsDevLike(cfg.Env) {
+			return nil, errors.New("PII_KEYS is required outside dev")
+		}
+		return nil, nil
+	}
+	return buildCodec(ctx, cfg, "PII_KEYS", cfg.PIIKeys)
+}
+
+// buildArchive configures the LLM call archive. Full archiving as the default
+// mode requires LLM_ARCHIVE_KEYS.
+func buildArchive(ctx context.Context, cfg config.Config, repo llmarchive.Repo, members llmarchive.MemberChecker) (*llmarchive.Service, error) {
+	mode, err := llmarchive.ParseMode(cfg.LLMArchiveMode)
+	if err != nil {
+		return nil, fmt.Errorf("LLM_ARCHIVE_MODE must be off, hashes or full, got %q", cfg.LLMArchiveMode)
+	}
+	var codec *fieldcrypt.Codec
+	if strings.TrimSpace(cfg.LLMArchiveKeys) != "" {
+		if codec, err = buildCodec(ctx, cfg, "LLM_ARCHIVE_KEYS", cfg.LLMArchiveKeys); err != nil {
+			return nil, err
+		}
+	} else if mode == llmarchive.ModeFull {
+		return nil, errors.New("LLM_ARCHIVE_MODE=full requires LLM_ARCHIVE_KEYS")
+	}
+	return llmarchive.NewService(repo, codec, members, mode, cfg.LLMArchiveRetention), nil
+}
+
+// buildCodec unwraps the data keys in raw, named by its environment variable
+// in errors, as PII_KEY_WRAPPING says.
+func buildCodec(ctx context.Context, cfg config.Config, name, raw string) (*fieldcrypt.Codec, error) {
+	var unwrap fieldcrypt.Unwrapper
+	switch cfg.PIIKeyWrapping {
+	case "", "kms":
+		kmsKeys, err := fieldcrypt.NewKMSKeys(ctx, cfg.AWSRegion)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		unwrap = kmsKeys
+	case "none":
+		if !config.IsDevLike(cfg.Env) {
+			log.Printf("bootstrap: PII_KEY_WRAPPING=none; %s are configured unwrapped", name)
+		}
+		unwrap = fieldcrypt.PlainKeys{}
+	default:
+		return nil, fmt.Errorf("PII_KEY_WRAPPING must be kms or none, got %q", cfg.PIIKeyWrapping)
+	}
+	keys, err := fieldcrypt.ParseKeys(ctx, raw, unwrap)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return fieldcrypt.New(keys)
+}
+
 func buildServices(app *App) error {
 	var docRepo documents.DocumentsRepo
 	var analysisRepo analyses.Repo
 	var generatedResumeRepo generatedresumes.Repo
 	var userRepo users.Repo
+	var artifactRepo artifacts.Repo
+	var rolloutRepo rollout.Repo
+	var rescoreRepo rescore.Repo
+	var auditRepo audit.Repo
+	var impersonationRepo impersonation.Repo
+	var badgeRepo badges.Repo
+	var poolRepo pools.Repo
+	var resumeRepo resumes.Repo
+	var templateRepo templates.Repo
+	var integrationRepo integrations.Repo
+	var residencyRepo residency.Repo
+	var flagRepo featureflags.Repo
+	var budgetRepo llmbudget.Repo
+	var archiveRepo llmarchive.Repo
+	var abuseRepo abuse.Repo
+	var secretBackend secrets.Backend
 
 	if app.DB != nil {
+		codec, err := buildFieldCodec(context.Background(), app.Config)
+		if err != nil {
+			return err
+		}
+		pgAnalyses := &analyses.PGRepo{DB: app.DB, Codec: codec}
+		pgUsers := &users.PGRepo{DB: app.DB, Codec: codec}
+		pgResumes := &resumes.PGRepo{DB: app.DB, Codec: codec}
+		app.FieldCodec = codec
+		app.EncryptedColumns = append(append(pgUsers.EncryptedColumns(), pgAnalyses.EncryptedColumns()...), pgResumes.EncryptedColumns()...)
+
 		docRepo = &documents.PGRepo{DB: app.DB}
-		analysisRepo = &analyses.PGRepo{DB: app.DB}
+		analysisRepo = pgAnalyses
 		generatedResumeRepo = &generatedresumes.PGRepo{DB: app.DB}
-		userRepo = &users.PGRepo{DB: app.DB}
+		userRepo = pgUsers
+		artifactRepo = &artifacts.PGRepo{DB: app.DB}
+		rolloutRepo = &rollout.PGRepo{DB: app.DB}
+		rescoreRepo = &rescore.PGRepo{DB: app.DB}
+		auditRepo = &audit.PGRepo{DB: app.DB}
+		impersonationRepo = &impersonation.PGRepo{DB: app.DB}
+		badgeRepo = &badges.PGRepo{DB: app.DB}
+		poolRepo = &pools.PGRepo{DB: app.DB}
+		resumeRepo = pgResumes
+		templateRepo = &templates.PGRepo{DB: app.DB}
+		integrationRepo = &integrations.PGRepo{DB: app.DB}
+		residencyRepo = &residency.PGRepo{DB: app.DB}
+		flagRepo = &featureflags.PGRepo{DB: app.DB}
+		budgetRepo = &llmbudget.PGRepo{DB: app.DB}
+		archiveRepo = &llmarchive.PGRepo{DB: app.DB}
+		abuseRepo = &abuse.PGRepo{DB: app.DB}
+		secretBackend = &secrets.PGBackend{DB: app.DB}
 	} else {
 		docRepo = documents.NewMemoryRepo()
 		analysisRepo = analyses.NewMemoryRepo()
 		generatedResumeRepo = generatedresumes.NewMemoryRepo()
 		userRepo = users.NewMemoryRepo()
+		artifactRepo = artifacts.NewMemoryRepo()
+		rolloutRepo = rollout.NewMemoryRepo()
+		rescoreRepo = rescore.NewMemoryRepo()
+		auditRepo = audit.NewMemoryRepo()
+		impersonationRepo = impersonation.NewMemoryRepo()
+		badgeRepo = badges.NewMemoryRepo()
+		poolRepo = pools.NewMemoryRepo()
+		resumeRepo = resumes.NewMemoryRepo()
+		templateRepo = templates.NewMemoryRepo()
+		integrationRepo = integrations.NewMemoryRepo()
+		residencyRepo = residency.NewMemoryRepo()
+		flagRepo = featureflags.NewMemoryRepo()
+		budgetRepo = llmbudget.NewMemoryRepo()
+		archiveRepo = llmarchive.NewMemoryRepo()
+		abuseRepo = abuse.NewMemoryRepo()
+		secretBackend = secrets.NewMemoryBackend()
+	}
+	if window := app.Config.QueueDedupWindow; window > 0 {
+		if app.DB != nil {
+			app.Dedup = &queue.PGDedup{DB: app.DB, Window: window}
+		} else {
+			app.Dedup = queue.NewMemoryDedup(window)
+		}
+	}
+	if app.Config.Role == config.RoleWorker {
+		docRepo = documents.NewWorkerRepo(docRepo)
+		analysisRepo = analyses.NewWorkerRepo(analysisRepo)
+	}
+
+	// Writes that span repos share a transaction on Postgres; the memory repos
+	// undo their part instead.
+	if app.DB != nil {
+		app.UnitOfWork = db.TxUnitOfWork{DB: app.DB}
+	} else {
+		app.UnitOfWork = &db.MemoryUnitOfWork{}
 	}
 
 	docSvc := &documents.Service{
 		Store:           app.Store,
 		Repo:            docRepo,
 		StorageProvider: app.Config.ObjectStoreType,
+		Fetcher:         documents.NewURLFetcher(),
+		UnitOfWork:      app.UnitOfWork,
 	}
+	docSvc.AnalysisDeleter, _ = analysisRepo.(documents.AnalysisDeleter)
 
 	var usageSvc *usage.Service
 	if app.DB != nil {
@@ -250,55 +712,156 @@ func buildServices(app *App) error {
 	} else {
 		usageSvc = usage.NewService()
 	}
+	usageSvc.SoftLimitPercent = app.Config.UsageSoftLimitPercent
+	usageSvc.UnitOfWork = app.UnitOfWork
+
+	defaultRegion, err := residency.ParseRegion(app.Config.DefaultResidency)
+	if err != nil {
+		return fmt.Errorf("DEFAULT_RESIDENCY: %w", err)
+	}
+	residencySvc := residency.NewService(residencyRepo, usageSvc, nil, defaultRegion)
+	if regional, ok := app.Store.(*residency.Store); ok {
+		regional.Regions = residencySvc
+	}
+
+	archiveSvc, err := buildArchive(context.Background(), app.Config, archiveRepo, usageSvc)
+	if err != nil {
+		return err
+	}
 
-	llmClient := llm.Client(llm.PlaceholderClient{})
+	regionalLLM := &residency.LLM{Regions: residencySvc, Default: defaultRegion, Clients: map[residency.Region]llm.Client{}}
+	regionalPrompt := &residency.PromptLLM{Regions: residencySvc, Default: defaultRegion, Clients: map[residency.Region]residency.PromptClient{}}
+	var rescoreClients rescore.ClientFactory
 	if app.Config.LLMProvider == "openai" {
 		openaiClient, err := openai.NewClient(os.Getenv("OPENAI_API_KEY"), app.Config.LLMModel)
 		if err != nil {
 			return err
 		}
-		llmClient = openaiClient
-	}
-
-	applyLLMClient := applies.LLMClient(promptPlaceholder{})
-	if app.Config.LLMProvider == "openai" {
 		promptClient, err := openai.NewPromptClient(os.Getenv("OPENAI_API_KEY"), app.Config.LLMModel)
 		if err != nil {
 			return err
 		}
-		applyLLMClient = promptClient
+		for region, endpoint := range openAIEndpoints(defaultRegion) {
+			if endpoint != "" {
+				regionalLLM.Clients[region] = openaiClient.WithEndpoint(endpoint)
+				regionalPrompt.Clients[region] = promptClient.WithEndpoint(endpoint)
+			} else {
+				regionalLLM.Clients[region] = openaiClient
+				regionalPrompt.Clients[region] = promptClient
+			}
+		}
+		// Re-scoring batches may use another model, on the same regional endpoints.
+		rescoreClients = func(model string) (llm.Client, error) {
+			client, err := openai.NewClient(os.Getenv("OPENAI_API_KEY"), model)
+			if err != nil {
+				return nil, err
+			}
+			regional := &residency.LLM{Regions: residencySvc, Default: defaultRegion, Clients: map[residency.Region]llm.Client{}}
+			for region, endpoint := range openAIEndpoints(defaultRegion) {
+				if endpoint != "" {
+					regional.Clients[region] = client.WithEndpoint(endpoint)
+				} else {
+					regional.Clients[region] = client
+				}
+			}
+			return llmarchive.Wrap(regional, archiveSvc), nil
+		}
+	} else {
+		for _, region := range residency.Regions {
+			regionalLLM.Clients[region] = llm.PlaceholderClient{}
+			regionalPrompt.Clients[region] = promptPlaceholder{}
+		}
 	}
+	// Calls are archived where they leave for a provider, so calls refused by
+	// the breaker or an injected fault are not.
+	llmClient := llmarchive.Wrap(regionalLLM, archiveSvc)
+	if prober, ok := regionalLLM.Clients[defaultRegion].(llm.Prober); ok {
+		app.LLMHealth = llmhealth.NewMonitor(prober, app.Config.LLMProvider)
+		app.LLMHealth.Model = app.Config.LLMHealthModel
+		app.LLMHealth.FailureThreshold = app.Config.LLMHealthFailureThreshold
+		app.Readiness.AddCheck("llm", app.Config.LLMHealthGatesReadiness, app.LLMHealth.Ready)
+		llmClient = app.LLMHealth.Guard(llmClient)
+	}
+	if config.IsDevLike(app.Config.Env) {
+		llmClient = faults.WrapLLM(llmClient, faults.ConfigFromEnv("llm"))
+	}
+	applyLLMClient := applies.LLMClient(llmarchive.WrapPrompts(regionalPrompt, archiveSvc))
+	// resumeModelClient rebuilds resumes for the apply flow; nil parses them
+	// without the LLM.
+	resumeModelClient, err := applyModelClient(app.Config.ApplyMode, app.Config.LLMProvider == "openai", applyLLMClient)
+	if err != nil {
+		return err
+	}
+	docSvc.Parser = resumeParser{client: resumeModelClient}
+
+	flagSvc, err := buildFeatureFlags(app.Config, flagRepo)
+	if err != nil {
+		return err
+	}
+
+	abuseDetector, err := buildAbuseDetector(app.Config, abuseRepo)
+	if err != nil {
+		return err
+	}
+
+	budgetSvc := llmbudget.NewService(budgetRepo, llmbudget.Pricing{
+		PromptPer1K:     app.Config.LLMPromptPricePer1K,
+		CompletionPer1K: app.Config.LLMCompletionPricePer1K,
+	}, app.Config.LLMDailyBudgetUSD, app.Config.LLMOrgDailyBudgetUSD, app.Config.LLMBudgetAction)
 
+	promptSunset, err := buildPromptSunset(app.Config)
+	if err != nil {
+		return err
+	}
+
+	promptRollout := rollout.NewService(rolloutRepo)
 	analysisSvc := &analyses.Service{
-		Repo:            analysisRepo,
-		Usage:           usageSvc,
-		DocRepo:         docRepo,
-		Store:           app.Store,
-		LLM:             llmClient,
-		JobQueue:        app.Queue,
-		Provider:        app.Config.LLMProvider,
-		Model:           app.Config.LLMModel,
-		AnalysisVersion: app.Config.AnalysisVersion,
+		Repo:               analysisRepo,
+		Usage:              usageSvc,
+		DocRepo:            docRepo,
+		Store:              app.Store,
+		LLM:                llmClient,
+		JobQueue:           app.Queue,
+		Provider:           app.Config.LLMProvider,
+		Model:              app.Config.LLMModel,
+		AnalysisVersion:    app.Config.AnalysisVersion,
+		Rollout:            promptRollout,
+		Sunset:             promptSunset,
+		ExtractQueue:       app.ExtractQueue,
+		DeltaMinSimilarity: app.Config.DeltaAnalysisMinSimilarity,
+		S3Docs:             app.S3Documents,
+		StorageFallback:    app.Config.StorageReadFallback,
+		InputTokenLimit:    app.Config.LLMInputTokenLimit,
+		LearningPlan:       applyLLMClient,
+		Flags:              flagSvc,
+		Budget:             budgetSvc,
 	}
 
 	analysisAdapter := analysisAdapter{repo: analysisRepo}
+	docSvc.Analyses = analysisAdapter
 	generatedResumeSvc := &generatedresumes.Service{
 		Repo:         generatedResumeRepo,
 		AnalysisRepo: analysisAdapter,
 		DocRepo:      docRepo,
 		Store:        app.Store,
+		LLM:          resumeModelClient,
 	}
 
 	usageHandler := usage.NewHandler(usageSvc, analysisAdapter, docRepo, app.Store, generatedResumeSvc)
+	usageHandler.Flags = flagSvc
+	usageHandler.LLM = resumeModelClient
 	applySvc := &applies.Service{
 		AnalysisRepo:  analysisRepo,
 		DocumentsRepo: docRepo,
 		GeneratedRepo: generatedResumeRepo,
 		Store:         app.Store,
-		LLM:           applyLLMClient,
+		LLM:           resumeModelClient,
 	}
 
+	app.Events = buildEvents(app)
+
 	userSvc := users.NewService(userRepo)
+	userSvc.Events = app.Events
 	googleAuthSvc := googleauth.NewGoogleService(
 		app.Config.GoogleClientID,
 		app.Config.GoogleClientSecret,
@@ -315,16 +878,135 @@ func buildServices(app *App) error {
 	app.UsageService = usageSvc
 	app.AnalysesService = analysisSvc
 	app.AnalysisProcessor = analysisSvc
+	app.AnalysisExtractor = analysisSvc
+	app.DocumentExtractor = analysisSvc
 	app.GeneratedResumesService = generatedResumeSvc
 	app.ApplyService = applySvc
 	app.AccountService = account.NewService(docRepo, analysisRepo)
+	app.AccountService.UnitOfWork = app.UnitOfWork
 	app.UsersService = userSvc
 	app.DocumentsHandler = documents.NewHandler(docSvc)
+	app.DocumentsHandler.GuestRetention = app.Config.GuestRetention
+	app.DocumentsHandler.Events = app.Events
+	app.RetentionService = retention.NewService(docRepo, analysisRepo, app.Store, app.Config.GuestRetention)
+	app.RetentionService.GeneratedResumes = generatedResumeRepo
+	app.RetentionService.Applies = usageSvc
+	app.RetentionService.UnitOfWork = app.UnitOfWork
 	app.AnalysisHandler = analyses.NewHandler(analysisSvc, docRepo)
+	app.AnalysisHandler.Events = app.Events
+	app.AnalysisHandler.InlineDocuments = docSvc
+	backpressure := analyses.NewBackpressure(app.Queue, analysisRepo)
+	backpressure.ShedGuests = app.Config.BackpressureShedGuests
+	app.AnalysisHandler.Backpressure = backpressure
+	app.JobDescriptionsHandler = jobdescriptions.NewHandler(jobdescriptions.NewService(applyLLMClient))
+	app.AssistHandler = assist.NewHandler(assist.NewService(applyLLMClient))
+	app.ArtifactsService = artifacts.NewService(artifactRepo, docRepo, generatedResumeRepo, app.Store)
+	app.ArtifactsHandler = artifacts.NewHandler(app.ArtifactsService)
+	app.BadgesService = badges.NewService(badgeRepo, analysisRepo)
+	app.BadgesHandler = badges.NewHandler(app.BadgesService)
 	app.ApplyHandler = applies.NewHandler(applySvc, generatedResumeRepo, app.Store)
+	app.ApplyHandler.Downloads = app.ArtifactsService
+	app.ApplyHandler.Events = app.Events
+	app.ApplyHandler.Usage = usageSvc
 	app.AccountHandler = account.NewHandler(app.AccountService)
+	app.AccountHandler.Events = app.Events
 	app.UsageHandler = usageHandler
+	app.AdminHandler = admin.NewHandler(app.Config.AdminUserIDs)
+	if source, ok := analysisRepo.(fairness.AnalysisSource); ok && app.Config.FairnessMonitoring {
+		app.FairnessMonitor = fairness.NewMonitor(source, docRepo, app.Store)
+		app.AdminHandler.AddStats("fairness", app.FairnessMonitor.Stats)
+	} else {
+		app.AdminHandler.AddStats("fairness", func(context.Context) (any, error) {
+			return map[string]any{"enabled": false}, nil
+		})
+	}
+	app.PromptRollout = promptRollout
+	app.AdminHandler.AddStats("promptRollout", promptRollout.Stats)
+	app.AdminHandler.AddStats("promptSunset", analysisSvc.SunsetStats)
+	app.AdminHandler.AddStats("backpressure", backpressure.Stats)
+	app.AdminHandler.AddStats("annotations", analysisSvc.AnnotationStats)
+	sloSource, _ := analysisRepo.(slo.CompletionSource)
+	app.SLO = slo.NewTracker(slo.DefaultObjectives(), sloSource)
+	app.SLO.Subscribe()
+	app.AdminHandler.AddStats("slo", app.SLO.Stats)
+	app.AdminHandler.AddRoutes(rollout.NewHandler(promptRollout).RegisterRoutes)
+	app.AuditService = audit.NewService(auditRepo)
+	app.AdminHandler.AddRoutes(audit.NewHandler(app.AuditService).RegisterRoutes)
+	app.AnalysisHandler.Audit = app.AuditService
+	flagSvc.Audit = app.AuditService
+	app.FeatureFlags = flagSvc
+	app.AdminHandler.AddRoutes(featureflags.NewHandler(flagSvc).RegisterRoutes)
+	app.LLMBudget = budgetSvc
+	app.AdminHandler.AddRoutes(llmbudget.NewHandler(budgetSvc).RegisterRoutes)
+	if app.LLMHealth != nil {
+		app.AdminHandler.AddStats("llmHealth", app.LLMHealth.Stats)
+	}
+	app.AdminHandler.AddRoutes(llmhealth.NewHandler(app.LLMHealth).RegisterRoutes)
+	rescoreSource, _ := analysisRepo.(rescore.CohortSource)
+	app.Rescore = rescore.NewService(rescoreRepo, analysisSvc, rescoreSource)
+	app.Rescore.Clients = rescoreClients
+	rescoreHandler := rescore.NewHandler(app.Rescore)
+	rescoreHandler.Audit = app.AuditService
+	app.AdminHandler.AddRoutes(rescoreHandler.RegisterRoutes)
+	app.AdminHandler.AddRoutes(app.AnalysisHandler.RegisterAdminRoutes)
+	app.Stuck = stuck.NewDetector(analysisSvc, docRepo, usageSvc)
+	app.Stuck.Remediate = app.Config.StuckRemediate
+	app.Stuck.Config.AnalysisAfter = app.Config.StuckAnalysisAfter
+	app.Stuck.Config.ApplyRunAfter = app.Config.StuckApplyRunAfter
+	app.AdminHandler.AddStats("stuckStates", app.Stuck.Stats)
+	app.AdminHandler.AddRoutes(stuck.NewHandler(app.Stuck).RegisterRoutes)
+	analysisQueue := pipelinestatus.QueueFor("analysis", app.Queue)
+	analysisQueue.Analyses = true
+	app.PipelineStatus = &pipelinestatus.Service{
+		Queues:   []pipelinestatus.Queue{analysisQueue},
+		Analyses: analysisSvc,
+		LLM:      app.LLMHealth,
+		Storage:  app.StorageLatency,
+	}
+	if app.ExtractQueue != nil {
+		app.PipelineStatus.Queues = append(app.PipelineStatus.Queues, pipelinestatus.QueueFor("extract", app.ExtractQueue))
+	}
+	app.AdminHandler.AddRoutes(pipelinestatus.NewHandler(app.PipelineStatus).RegisterRoutes)
+	app.Impersonation = impersonation.NewService(impersonationRepo, app.AuditService, userSvc, app.Config.AdminUserIDs)
+	app.AdminHandler.AddRoutes(impersonation.NewHandler(app.Impersonation).RegisterRoutes)
+	app.TemplatesService = templates.NewService(templateRepo, app.Store, app.AuditService)
+	app.AdminHandler.AddRoutes(templates.NewHandler(app.TemplatesService).RegisterRoutes)
+	residencySvc.Audit = app.AuditService
+	app.Residency = residencySvc
+	app.AdminHandler.AddRoutes(residency.NewHandler(residencySvc).RegisterRoutes)
+	archiveSvc.Audit = app.AuditService
+	app.LLMArchive = archiveSvc
+	app.AdminHandler.AddRoutes(llmarchive.NewHandler(archiveSvc).RegisterRoutes)
+	abuseDetector.Audit = app.AuditService
+	app.Abuse = abuseDetector
+	app.AdminHandler.AddStats("abuse", abuseDetector.Stats)
+	app.AdminHandler.AddRoutes(abuse.NewHandler(abuseDetector).RegisterRoutes)
+	app.AnalysisHandler.Disclosure = analyses.DisclosureSettings{GuestRetention: app.Config.GuestRetention}
+	if archiveSvc.Enabled() {
+		app.AnalysisHandler.Disclosure.Archive = archiveSvc
+	}
 	app.UsersHandler = users.NewHandler(userSvc)
+	poolAnalyses, _ := analysisRepo.(pools.AnalysisSource)
+	app.PoolsService = pools.NewService(poolRepo, usageSvc, docRepo, poolAnalyses)
+	app.PoolsHandler = pools.NewHandler(app.PoolsService)
+	app.ResumesService = resumes.NewService(resumeRepo, docSvc)
+	app.ResumesHandler = resumes.NewHandler(app.ResumesService)
+	app.AnalysisHandler.BuiltResumes = app.ResumesService
+	app.ProfileHandler = profilestrength.NewHandler(profilestrength.NewService(analysisSvc, docSvc))
+	app.OnboardingHandler = onboarding.NewHandler(onboarding.NewService(docSvc, analysisSvc))
+	if source, ok := analysisRepo.(insights.AnalysisSource); ok {
+		app.InsightsHandler = insights.NewHandler(insights.NewService(source))
+	}
+	secretStore, err := buildSecrets(app.Config, secretBackend)
+	if err != nil {
+		return err
+	}
+	app.IntegrationsService = integrations.NewService(integrationRepo, secretStore, usageSvc, analysisRepo)
+	if webhook, ok := app.IntegrationsService.Adapters[integrations.ProviderWebhook].(*integrations.WebhookAdapter); ok {
+		webhook.AllowInsecure = config.IsDevLike(app.Config.Env)
+	}
+	app.IntegrationsHandler = integrations.NewHandler(app.IntegrationsService)
+	app.UsageHandler.Notifier = app.IntegrationsService
 	app.GoogleAuth = googleAuthSvc
 
 	if app.DocumentsHandler == nil || app.AnalysisHandler == nil || app.UsageHandler == nil {
@@ -334,6 +1016,85 @@ func buildServices(app *App) error {
 	return nil
 }
 
+// buildFeatureFlags layers flag rules: FEATURE_FLAGS pins a deployment's
+// flags, then rules edited through the admin API, then FEATURE_FLAGS_URL.
+func buildFeatureFlags(cfg config.Config, repo featureflags.Repo) (*featureflags.Service, error) {
+	env, err := featureflags.NewEnvProvider(cfg.FeatureFlags)
+	if err != nil {
+		return nil, err
+	}
+	providers := []featureflags.Provider{env, &featureflags.RepoProvider{Repo: repo}}
+	if url := strings.TrimSpace(cfg.FeatureFlagsURL); url != "" {
+		providers = append(providers, featureflags.NewRemoteProvider(url))
+	}
+	return featureflags.NewService(repo, nil, providers...), nil
+}
+
+// buildPromptSunset returns the built-in prompt version sunset policies with
+// PROMPT_SUNSET applied over them.
+func buildPromptSunset(cfg config.Config) (*analyses.PromptSunset, error) {
+	overrides, err := analyses.ParsePromptPolicies(cfg.PromptSunset)
+	if err != nil {
+		return nil, fmt.Errorf("PROMPT_SUNSET: %w", err)
+	}
+	sunset, err := analyses.NewPromptSunset(append(analyses.DefaultPromptPolicies(), overrides...)...)
+	if err != nil {
+		return nil, fmt.Errorf("PROMPT_SUNSET: %w", err)
+	}
+	return sunset, nil
+}
+
+// buildAbuseDetector returns the guest abuse detector for ABUSE_MODE, which
+// config defaults to shadow; "off", or an empty mode in a bare Config,
+// disables it. Outside dev an enabled detector needs ABUSE_HASH_KEY, since
+// unkeyed hashes of IPs can be reversed by enumerating them. Dev-like envs
+// fall back to a per-process key, so clusters do not survive a restart.
+func buildAbuseDetector(cfg config.Config, repo abuse.Repo) (*abuse.Detector, error) {
+	switch cfg.AbuseMode {
+	case "", abuse.ModeOff, abuse.ModeShadow, abuse.ModeEnforce:
+	default:
+		return nil, fmt.Errorf("unknown ABUSE_MODE %q", cfg.AbuseMode)
+	}
+	key := strings.TrimSpace(cfg.AbuseHashKey)
+	if key == "" && cfg.AbuseMode != "" && cfg.AbuseMode != abuse.ModeOff {
+		if !config.IsDevLike(cfg.Env) {
+			return nil, errors.New("ABUSE_HASH_KEY is required unless ABUSE_MODE is off")
+		}
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		key = string(random)
+		log.Printf("bootstrap: ABUSE_HASH_KEY is not set; using an ephemeral key")
+	}
+	detector := abuse.NewDetector(repo, cfg.AbuseMode, abuse.Thresholds{
+		Window:               cfg.AbuseWindow,
+		MaxGuestsPerDevice:   cfg.AbuseMaxGuestsPerDevice,
+		MaxGuestsPerIP:       cfg.AbuseMaxGuestsPerIP,
+		MaxRequestsPerDevice: cfg.AbuseMaxRequestsPerDevice,
+		MaxRequestsPerIP:     cfg.AbuseMaxRequestsPerIP,
+		ReviewTTL:            cfg.AbuseReviewTTL,
+	})
+	if key != "" {
+		detector.HashKey = []byte(key)
+	}
+	return detector, nil
+}
+
+// openAIEndpoints returns the regions that get an OpenAI client and the endpoint
+// of each; "" means the default endpoint. OPENAI_API_URL_<REGION> pins a region
+// to its own endpoint. Regions other than the default have no LLM without one.
+func openAIEndpoints(defaultRegion residency.Region) map[residency.Region]string {
+	out := make(map[residency.Region]string)
+	for _, region := range residency.Regions {
+		endpoint := strings.TrimSpace(os.Getenv("OPENAI_API_URL_" + strings.ToUpper(string(region))))
+		if endpoint != "" || region == defaultRegion {
+			out[region] = endpoint
+		}
+	}
+	return out
+}
+
 type analysisAdapter struct {
 	repo analyses.Repo
 }
@@ -351,7 +1112,7 @@ func (a analysisAdapter) GetByID(ctx context.Context, analysisID string) (usage.
 		UserID:     analysis.UserID,
 		DocumentID: analysis.DocumentID,
 		Status:     analysis.Status,
-		Result:     analysis.Result,
+		Result:     analyses.AnnotatedResult(ctx, a.repo, analysis),
 	}, nil
 }
 
@@ -368,10 +1129,44 @@ func (a analysisAdapter) GetAnalysisByID(ctx context.Context, analysisID string)
 		UserID:     analysis.UserID,
 		DocumentID: analysis.DocumentID,
 		Status:     analysis.Status,
-		Result:     analysis.Result,
+		Result:     analyses.AnnotatedResult(ctx, a.repo, analysis),
 	}, nil
 }
 
+// exportAnalysisPages bounds how far back LatestCompletedForDocument looks
+// through the user's analyses, newest first.
+const exportAnalysisPages = 5
+
+// LatestCompletedForDocument finds the analysis an HR Open export reports.
+func (a analysisAdapter) LatestCompletedForDocument(ctx context.Context, userID, documentID string) (documents.ExportAnalysis, error) {
+	const pageSize = 100
+	for page := 0; page < exportAnalysisPages; page++ {
+		items, err := a.repo.ListByUser(ctx, userID, pageSize, page*pageSize)
+		if err != nil {
+			return documents.ExportAnalysis{}, err
+		}
+		for _, analysis := range items {
+			if analysis.DocumentID != documentID || analysis.Status != analyses.StatusCompleted || analysis.Result == nil {
+				continue
+			}
+			mode := analysis.Mode
+			if mode == "" {
+				mode = analyses.ModeJobMatch
+			}
+			return documents.ExportAnalysis{
+				ID:          analysis.ID,
+				Mode:        string(mode),
+				Result:      analyses.AnnotatedResult(ctx, a.repo, analysis),
+				CompletedAt: analysis.CompletedAt,
+			}, nil
+		}
+		if len(items) < pageSize {
+			break
+		}
+	}
+	return documents.ExportAnalysis{}, documents.ErrNotFound
+}
+
 type promptPlaceholder struct{}
 
 func (promptPlaceholder) Complete(ctx context.Context, prompt string) (string, error) {
@@ -379,3 +1174,30 @@ func (promptPlaceholder) Complete(ctx context.Context, prompt string) (string, e
 	_ = prompt
 	return "", errors.New("llm prompt client not configured")
 }
+
+// applyModelClient returns the client the apply flow rebuilds resumes with,
+// or nil when mode asks for the deterministic parser. Auto mode parses
+// without the LLM when no provider is configured, instead of failing.
+func applyModelClient(mode string, llmConfigured bool, client resumeservice.LLMClient) (resumeservice.LLMClient, error) {
+	switch mode {
+	case config.ApplyModeLLM:
+		return client, nil
+	case config.ApplyModeDeterministic:
+		return nil, nil
+	case config.ApplyModeAuto, "":
+		if llmConfigured {
+			return client, nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("APPLY_MODE: unknown mode %q", mode)
+	}
+}
+
+type resumeParser struct {
+	client resumeservice.LLMClient
+}
+
+func (p resumeParser) ParseResume(ctx context.Context, resumeText string) (model.ResumeModel, error) {
+	return resumeservice.ResumeModelFromText(ctx, p.client, resumeText)
+}
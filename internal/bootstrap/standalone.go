@@ -0,0 +1,45 @@
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/storage/db"
+)
+
+// standaloneQueueSize bounds the in-memory queue used without a database.
+const standaloneQueueSize = 1024
+
+// standaloneIgnoredEnv are AWS settings the standalone profile does not use.
+var standaloneIgnoredEnv = []string{
+	"RA_SQS_QUEUE_URL",
+	"RA_SQS_EXTRACT_QUEUE_URL",
+	"UPLOADS_S3_BUCKET",
+	"S3_BUCKET",
+}
+
+// buildStandalone prepares what RA_PROFILE=standalone needs in place of AWS:
+// the schema is migrated on startup, and analysis jobs go through a Postgres
+// table, or process memory without a database. The API process consumes the
+// queue itself (see workerproc.ConsumeLocal).
+func buildStandalone(ctx context.Context, cfg config.Config, sqlDB *sql.DB) (queue.LocalQueue, error) {
+	for _, name := range standaloneIgnoredEnv {
+		if strings.TrimSpace(os.Getenv(name)) != "" {
+			log.Printf("bootstrap: RA_PROFILE=standalone ignores %s", name)
+		}
+	}
+	if sqlDB == nil {
+		log.Printf("bootstrap: standalone without a database; queued jobs are lost on restart")
+		return queue.NewMemoryQueue(standaloneQueueSize), nil
+	}
+	if err := db.RunMigrations(ctx, sqlDB); err != nil {
+		return nil, fmt.Errorf("standalone migrations: %w", err)
+	}
+	return &queue.PGQueue{DB: sqlDB, Name: "analysis", Visibility: cfg.LocalQueueVisibility}, nil
+}
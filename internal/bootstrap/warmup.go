@@ -0,0 +1,46 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sharedauth "resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/storage/db"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/resume/render"
+)
+
+// Warmup primes dependencies that are otherwise initialized lazily on the first request:
+// pooled DB connections, the default DOCX template and the JWT signing secret.
+// It is safe to call repeatedly; failures are reported but leave the App usable.
+func (a *App) Warmup(ctx context.Context) error {
+	start := time.Now()
+	var errs []error
+
+	if a.DB != nil {
+		if err := db.Prewarm(ctx, a.DB, dbOptions().MaxIdleConns); err != nil {
+			errs = append(errs, fmt.Errorf("db: %w", err))
+		}
+	}
+	if err := render.PreloadDefaultTemplate(); err != nil {
+		errs = append(errs, fmt.Errorf("template: %w", err))
+	}
+	if err := sharedauth.CheckSecret(); err != nil {
+		errs = append(errs, fmt.Errorf("secrets: %w", err))
+	}
+
+	err := errors.Join(errs...)
+	fields := map[string]any{
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+		"ok":          err == nil,
+	}
+	if err != nil {
+		fields["err"] = err.Error()
+		telemetry.Error("warmup.complete", fields)
+		return err
+	}
+	telemetry.Info("warmup.complete", fields)
+	return nil
+}
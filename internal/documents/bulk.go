@@ -0,0 +1,203 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"resume-backend/internal/shared/storage/db"
+)
+
+// MaxBulkDocuments bounds how many documents one bulk request may name.
+const MaxBulkDocuments = 100
+
+// Bulk result statuses.
+const (
+	BulkDeleted    = "deleted"
+	BulkArchived   = "archived"
+	BulkUnarchived = "unarchived"
+	BulkNotFound   = "not_found"
+	BulkFailed     = "failed"
+	// BulkPending marks a recorded item that has not been attempted yet, for
+	// example because the request was cut off part way through.
+	BulkPending = "pending"
+)
+
+// BulkResult is what happened to one document of a bulk request.
+type BulkResult struct {
+	DocumentID string `json:"documentId"`
+	Status     string `json:"status"`
+	// AnalysesDeleted counts the analyses deleted with the document.
+	AnalysesDeleted int    `json:"analysesDeleted,omitempty"`
+	Error           string `json:"error,omitempty"`
+	// Attempts counts how many times a recorded job tried this document.
+	Attempts int `json:"attempts,omitempty"`
+}
+
+// AnalysisDeleter soft-deletes the analyses of a deleted document. The
+// analyses repos implement it.
+type AnalysisDeleter interface {
+	SoftDeleteByDocument(ctx context.Context, userID, documentID string, deletedAt time.Time) (int, error)
+}
+
+type softDeleter interface {
+	SoftDelete(ctx context.Context, userId, documentID string, deletedAt time.Time) error
+}
+
+type objectDeleter interface {
+	Delete(ctx context.Context, storageKey string) error
+}
+
+// BulkDelete deletes each of the user's documents with its analyses and
+// stored objects, the way guest retention purges expired uploads: objects
+// first, so a failure leaves the document in place to be retried rather than
+// a deleted row pointing at leaked files. Each document succeeds or fails on
+// its own. The returned job has an ID when the repo records bulk jobs.
+func (s *Service) BulkDelete(ctx context.Context, userId string, ids []string) (BulkJob, error) {
+	ids, err := bulkIDs(userId, ids)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	if _, ok := s.Repo.(softDeleter); !ok {
+		return BulkJob{}, fmt.Errorf("%w: document deletion is not supported", ErrNotPermitted)
+	}
+	job, err := s.newBulkJob(ctx, userId, BulkKindDelete, ids)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	return s.runBulkJob(ctx, job, false)
+}
+
+func (s *Service) deleteItem(ctx context.Context, userId, id string, now time.Time) BulkResult {
+	docs, ok := s.Repo.(softDeleter)
+	if !ok {
+		return BulkResult{DocumentID: id, Status: BulkFailed, Error: "document deletion is not supported"}
+	}
+	result := BulkResult{DocumentID: id, Status: BulkDeleted}
+	deleted, err := s.deleteOne(ctx, docs, userId, id, now)
+	result.AnalysesDeleted = deleted
+	if err != nil {
+		result = failedResult(id, err, "document could not be deleted")
+		log.Printf("bulk delete document %s for user %s: %v", id, userId, err)
+	}
+	return result
+}
+
+func (s *Service) deleteOne(ctx context.Context, docs softDeleter, userId, id string, now time.Time) (int, error) {
+	doc, err := s.Repo.GetByID(ctx, userId, id)
+	if err != nil {
+		return 0, err
+	}
+	if deleter, ok := s.Store.(objectDeleter); ok {
+		for _, key := range []string{doc.StorageKey, doc.ExtractedTextKey} {
+			if key == "" {
+				continue
+			}
+			if err := deleter.Delete(ctx, key); err != nil {
+				return 0, fmt.Errorf("delete stored object: %w", err)
+			}
+		}
+	}
+	deleted := 0
+	err = db.Run(ctx, s.UnitOfWork, func(ctx context.Context) error {
+		if s.AnalysisDeleter != nil {
+			n, err := s.AnalysisDeleter.SoftDeleteByDocument(ctx, userId, id, now)
+			if err != nil {
+				return fmt.Errorf("delete analyses: %w", err)
+			}
+			deleted = n
+		}
+		return docs.SoftDelete(ctx, userId, id, now)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// BulkArchive archives each of the user's documents, or restores them when
+// archived is false. Archived documents keep their analyses and stored
+// objects. The returned job has an ID when the repo records bulk jobs.
+func (s *Service) BulkArchive(ctx context.Context, userId string, ids []string, archived bool) (BulkJob, error) {
+	ids, err := bulkIDs(userId, ids)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	if _, ok := s.Repo.(Archiver); !ok {
+		return BulkJob{}, fmt.Errorf("%w: document archiving is not supported", ErrNotPermitted)
+	}
+	kind := BulkKindUnarchive
+	if archived {
+		kind = BulkKindArchive
+	}
+	job, err := s.newBulkJob(ctx, userId, kind, ids)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	return s.runBulkJob(ctx, job, false)
+}
+
+func (s *Service) archiveItem(ctx context.Context, userId, id string, archived bool, now time.Time) BulkResult {
+	archiver, ok := s.Repo.(Archiver)
+	if !ok {
+		return BulkResult{DocumentID: id, Status: BulkFailed, Error: "document archiving is not supported"}
+	}
+	var at *time.Time
+	status := BulkUnarchived
+	if archived {
+		at = &now
+		status = BulkArchived
+	}
+	if err := archiver.SetArchived(ctx, userId, id, at); err != nil {
+		return failedResult(id, err, "document could not be updated")
+	}
+	return BulkResult{DocumentID: id, Status: status}
+}
+
+// ListArchived returns a user's archived documents, most recently archived
+// first.
+func (s *Service) ListArchived(ctx context.Context, userId string, limit, offset int) ([]Document, error) {
+	if userId == "" {
+		return nil, errors.New("user id required")
+	}
+	archiver, ok := s.Repo.(Archiver)
+	if !ok {
+		return []Document{}, nil
+	}
+	return archiver.ListArchivedByUser(ctx, userId, limit, offset)
+}
+
+// bulkIDs validates a bulk request's document IDs and drops blanks and
+// repeats, keeping the request's order.
+func bulkIDs(userId string, ids []string) ([]string, error) {
+	if userId == "" {
+		return nil, ErrInvalidInput
+	}
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: documentIds is required", ErrInvalidInput)
+	}
+	if len(out) > MaxBulkDocuments {
+		return nil, fmt.Errorf("%w: at most %d documentIds are allowed", ErrInvalidInput, MaxBulkDocuments)
+	}
+	return out, nil
+}
+
+func failedResult(id string, err error, message string) BulkResult {
+	if errors.Is(err, ErrNotFound) {
+		return BulkResult{DocumentID: id, Status: BulkNotFound}
+	}
+	return BulkResult{DocumentID: id, Status: BulkFailed, Error: message}
+}
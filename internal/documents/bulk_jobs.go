@@ -0,0 +1,167 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bulk job kinds.
+const (
+	BulkKindDelete    = "delete"
+	BulkKindArchive   = "archive"
+	BulkKindUnarchive = "unarchive"
+)
+
+// Bulk job statuses.
+const (
+	// BulkJobCompleted means every document reached a final status.
+	BulkJobCompleted = "completed"
+	// BulkJobIncomplete means some documents failed or were never attempted;
+	// resuming the job retries them.
+	BulkJobIncomplete = "incomplete"
+)
+
+// ErrBulkJobsUnsupported indicates the repo does not record bulk jobs, so they
+// cannot be read back or resumed.
+var ErrBulkJobsUnsupported = errors.New("bulk jobs are not recorded")
+
+// BulkJob is a recorded bulk request with one result per document, in request
+// order.
+type BulkJob struct {
+	// ID is empty when the repo does not record bulk jobs.
+	ID        string       `json:"jobId,omitempty"`
+	UserID    string       `json:"-"`
+	Kind      string       `json:"kind"`
+	Status    string       `json:"status"`
+	Results   []BulkResult `json:"results"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// BulkJobStore records bulk jobs and the progress of each document, so a job
+// cut short by a failure can be resumed. MemoryRepo and PGRepo implement it.
+type BulkJobStore interface {
+	// CreateBulkJob records a job with its results, all pending.
+	CreateBulkJob(ctx context.Context, job BulkJob) error
+	// GetBulkJob returns a user's job, or ErrNotFound.
+	GetBulkJob(ctx context.Context, userId, jobID string) (BulkJob, error)
+	// SaveBulkResult records the result of the document at index.
+	SaveBulkResult(ctx context.Context, jobID string, index int, result BulkResult, at time.Time) error
+}
+
+// GetBulkJob returns one of the user's recorded bulk jobs.
+func (s *Service) GetBulkJob(ctx context.Context, userId, jobID string) (BulkJob, error) {
+	store, ok := s.Repo.(BulkJobStore)
+	if !ok {
+		return BulkJob{}, ErrBulkJobsUnsupported
+	}
+	job, err := store.GetBulkJob(ctx, userId, jobID)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	return summarizeBulkJob(job), nil
+}
+
+// ResumeBulkJob retries the documents of a recorded job that failed or were
+// never attempted. Documents that already succeeded, or were not found, are
+// left alone, so resuming a completed job changes nothing.
+func (s *Service) ResumeBulkJob(ctx context.Context, userId, jobID string) (BulkJob, error) {
+	store, ok := s.Repo.(BulkJobStore)
+	if !ok {
+		return BulkJob{}, ErrBulkJobsUnsupported
+	}
+	job, err := store.GetBulkJob(ctx, userId, jobID)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	return s.runBulkJob(ctx, job, true)
+}
+
+// newBulkJob builds a job with every document pending and records it when the
+// repo supports it.
+func (s *Service) newBulkJob(ctx context.Context, userId, kind string, ids []string) (BulkJob, error) {
+	now := time.Now().UTC()
+	job := BulkJob{
+		UserID:    userId,
+		Kind:      kind,
+		Results:   make([]BulkResult, len(ids)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for i, id := range ids {
+		job.Results[i] = BulkResult{DocumentID: id, Status: BulkPending}
+	}
+	if store, ok := s.Repo.(BulkJobStore); ok {
+		job.ID = uuid.NewString()
+		if err := store.CreateBulkJob(ctx, job); err != nil {
+			return BulkJob{}, fmt.Errorf("record bulk job: %w", err)
+		}
+	}
+	return job, nil
+}
+
+// runBulkJob attempts each pending document, and each failed one when resuming,
+// saving every result as it lands so progress survives a request cut short.
+func (s *Service) runBulkJob(ctx context.Context, job BulkJob, resuming bool) (BulkJob, error) {
+	store, record := s.Repo.(BulkJobStore)
+	record = record && job.ID != ""
+	for i, prev := range job.Results {
+		if prev.Status != BulkPending && !(resuming && prev.Status == BulkFailed) {
+			continue
+		}
+		now := time.Now().UTC()
+		result := s.bulkItem(ctx, job.UserID, job.Kind, prev.DocumentID, now)
+		// A delete that failed after removing the document, for example
+		// while reporting back, finds nothing on retry: it is done.
+		if job.Kind == BulkKindDelete && prev.Status == BulkFailed && result.Status == BulkNotFound {
+			result = BulkResult{DocumentID: prev.DocumentID, Status: BulkDeleted}
+		}
+		result.Attempts = prev.Attempts + 1
+		job.Results[i] = result
+		job.UpdatedAt = now
+		if record {
+			if err := store.SaveBulkResult(ctx, job.ID, i, result, now); err != nil {
+				log.Printf("record bulk job %s result for document %s: %v", job.ID, result.DocumentID, err)
+			}
+		}
+	}
+	return summarizeBulkJob(job), nil
+}
+
+func (s *Service) bulkItem(ctx context.Context, userId, kind, id string, now time.Time) BulkResult {
+	switch kind {
+	case BulkKindDelete:
+		return s.deleteItem(ctx, userId, id, now)
+	case BulkKindArchive:
+		return s.archiveItem(ctx, userId, id, true, now)
+	case BulkKindUnarchive:
+		return s.archiveItem(ctx, userId, id, false, now)
+	default:
+		return BulkResult{DocumentID: id, Status: BulkFailed, Error: "unknown bulk job kind"}
+	}
+}
+
+// summarizeBulkJob sets the job's counts and status from its results.
+func summarizeBulkJob(job BulkJob) BulkJob {
+	job.Succeeded, job.Failed = 0, 0
+	job.Status = BulkJobCompleted
+	for _, result := range job.Results {
+		switch result.Status {
+		case BulkNotFound:
+			job.Failed++
+		case BulkFailed, BulkPending:
+			job.Failed++
+			job.Status = BulkJobIncomplete
+		default:
+			job.Succeeded++
+		}
+	}
+	return job
+}
@@ -0,0 +1,107 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyStore fails to delete the keys in failing, once each.
+type flakyStore struct {
+	failing map[string]bool
+	deleted []string
+}
+
+func (s *flakyStore) Save(ctx context.Context, userId, fileName string, r io.Reader) (string, int64, string, error) {
+	return userId + "/" + fileName, 0, "text/plain", nil
+}
+
+func (s *flakyStore) Open(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (s *flakyStore) Delete(ctx context.Context, storageKey string) error {
+	if s.failing[storageKey] {
+		delete(s.failing, storageKey)
+		return errors.New("connection reset")
+	}
+	s.deleted = append(s.deleted, storageKey)
+	return nil
+}
+
+func TestResumeBulkJobRetriesOnlyFailedDocuments(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	for _, id := range []string{"doc-a", "doc-b", "doc-c"} {
+		if err := repo.Create(ctx, Document{ID: id, UserID: "u1", StorageKey: "u1/" + id}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	store := &flakyStore{failing: map[string]bool{"u1/doc-b": true}}
+	svc := &Service{Repo: repo, Store: store}
+
+	job, err := svc.BulkDelete(ctx, "u1", []string{"doc-a", "doc-b", "doc-c", "missing"})
+	if err != nil {
+		t.Fatalf("bulk delete: %v", err)
+	}
+	if job.ID == "" || job.Status != BulkJobIncomplete || job.Succeeded != 2 || job.Failed != 2 {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	if job.Results[1].Status != BulkFailed || job.Results[3].Status != BulkNotFound {
+		t.Fatalf("unexpected results %+v", job.Results)
+	}
+
+	resumed, err := svc.ResumeBulkJob(ctx, "u1", job.ID)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if resumed.Status != BulkJobCompleted || resumed.Succeeded != 3 || resumed.Results[1].Status != BulkDeleted {
+		t.Fatalf("unexpected resumed job %+v", resumed)
+	}
+	if got := []int{resumed.Results[0].Attempts, resumed.Results[1].Attempts, resumed.Results[3].Attempts}; got[0] != 1 || got[1] != 2 || got[2] != 1 {
+		t.Fatalf("expected only the failed document retried, attempts %v", got)
+	}
+	if len(store.deleted) != 3 {
+		t.Fatalf("expected each object deleted once, got %v", store.deleted)
+	}
+
+	again, err := svc.ResumeBulkJob(ctx, "u1", job.ID)
+	if err != nil || again.Results[1].Attempts != 2 {
+		t.Fatalf("expected resuming a completed job to change nothing, got %+v, %v", again, err)
+	}
+	if _, err := svc.ResumeBulkJob(ctx, "u2", job.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected another user's job not found, got %v", err)
+	}
+}
+
+func TestResumeBulkJobAttemptsPendingDocuments(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepo()
+	if err := repo.Create(ctx, Document{ID: "doc-a", UserID: "u1"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// A job recorded by a request that stopped before attempting its document.
+	now := time.Now().UTC()
+	if err := repo.CreateBulkJob(ctx, BulkJob{
+		ID: "job-1", UserID: "u1", Kind: BulkKindArchive, CreatedAt: now, UpdatedAt: now,
+		Results: []BulkResult{{DocumentID: "doc-a", Status: BulkPending}},
+	}); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	svc := &Service{Repo: repo}
+
+	pending, err := svc.GetBulkJob(ctx, "u1", "job-1")
+	if err != nil || pending.Status != BulkJobIncomplete {
+		t.Fatalf("expected an incomplete job, got %+v, %v", pending, err)
+	}
+	resumed, err := svc.ResumeBulkJob(ctx, "u1", "job-1")
+	if err != nil || resumed.Status != BulkJobCompleted || resumed.Results[0].Status != BulkArchived {
+		t.Fatalf("expected the pending document archived, got %+v, %v", resumed, err)
+	}
+	if archived, _ := repo.ListArchivedByUser(ctx, "u1", 10, 0); len(archived) != 1 {
+		t.Fatalf("expected the document archived, got %v", archived)
+	}
+}
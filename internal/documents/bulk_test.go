@@ -0,0 +1,220 @@
+package documents_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+func uploadAs(t *testing.T, router http.Handler, authorization, fileName string) string {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fileWriter.Write([]byte("resume text for " + fileName)); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", authorization)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload %s: expected 201, got %d: %s", fileName, resp.Code, resp.Body.String())
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode upload: %v", err)
+	}
+	return created.DocumentID
+}
+
+func postJSON(router http.Handler, path, authorization string, body any) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	} else {
+		addGuestHeader(req)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func listIDs(t *testing.T, router http.Handler, path, authorization string) []string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", authorization)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("list %s: expected 200, got %d: %s", path, resp.Code, resp.Body.String())
+	}
+	var items []struct {
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.DocumentID)
+	}
+	return ids
+}
+
+type bulkResponse struct {
+	JobID     string                 `json:"jobId"`
+	Status    string                 `json:"status"`
+	Results   []documents.BulkResult `json:"results"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+}
+
+func TestBulkArchiveAndDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	token, err := auth.SignJWT(auth.Claims{Sub: "bulk-user"})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	authorization := "Bearer " + token
+	router := app.Router
+
+	first := uploadAs(t, router, authorization, "first.txt")
+	second := uploadAs(t, router, authorization, "second.txt")
+	third := uploadAs(t, router, authorization, "third.txt")
+
+	resp := postJSON(router, "/api/v1/documents/bulk-archive", authorization, map[string]any{"documentIds": []string{third, "missing", third}})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("bulk archive: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var archived bulkResponse
+	_ = json.NewDecoder(resp.Body).Decode(&archived)
+	if archived.Succeeded != 1 || archived.Failed != 1 || len(archived.Results) != 2 ||
+		archived.Results[0].Status != documents.BulkArchived || archived.Results[1].Status != documents.BulkNotFound {
+		t.Fatalf("unexpected archive results %+v", archived)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bulk-jobs/"+archived.JobID, nil)
+	req.Header.Set("Authorization", authorization)
+	jobResp := httptest.NewRecorder()
+	router.ServeHTTP(jobResp, req)
+	var recorded bulkResponse
+	_ = json.NewDecoder(jobResp.Body).Decode(&recorded)
+	if jobResp.Code != http.StatusOK || recorded.Status != documents.BulkJobCompleted || len(recorded.Results) != 2 {
+		t.Fatalf("expected the recorded job, got %d: %+v", jobResp.Code, recorded)
+	}
+	if docs, _ := app.DocumentsService.List(context.Background(), "bulk-user", 20, 0); len(docs) != 2 {
+		t.Fatalf("expected archived documents to leave the list, got %v", docs)
+	}
+	if ids := listIDs(t, router, "/api/v1/documents?archived=true", authorization); len(ids) != 1 || ids[0] != third {
+		t.Fatalf("expected the archived document listed, got %v", ids)
+	}
+	current, err := app.DocumentsService.Current(context.Background(), "bulk-user")
+	if err != nil || current.ID != second {
+		t.Fatalf("expected the newest unarchived document to be current, got %s, %v", current.ID, err)
+	}
+
+	resp = postJSON(router, "/api/v1/documents/bulk-unarchive", authorization, map[string]any{"documentIds": []string{third}})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("bulk unarchive: expected 200, got %d", resp.Code)
+	}
+	if docs, _ := app.DocumentsService.List(context.Background(), "bulk-user", 20, 0); len(docs) != 3 {
+		t.Fatalf("expected the document restored, got %v", docs)
+	}
+
+	doc, _ := app.DocumentsRepo.GetByID(context.Background(), "bulk-user", first)
+	if err := app.AnalysesRepo.Create(context.Background(), analyses.Analysis{
+		ID: "an-bulk-1", DocumentID: first, UserID: "bulk-user", Status: "completed", CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	resp = postJSON(router, "/api/v1/documents/bulk-delete", authorization, map[string]any{"documentIds": []string{first, second}})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("bulk delete: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var deleted bulkResponse
+	_ = json.NewDecoder(resp.Body).Decode(&deleted)
+	if deleted.Succeeded != 2 || deleted.Results[0].Status != documents.BulkDeleted || deleted.Results[0].AnalysesDeleted != 1 {
+		t.Fatalf("unexpected delete results %+v", deleted)
+	}
+	if docs, _ := app.DocumentsService.List(context.Background(), "bulk-user", 20, 0); len(docs) != 1 || docs[0].ID != third {
+		t.Fatalf("expected only the third document left, got %v", docs)
+	}
+	if _, err := app.AnalysesRepo.GetByID(context.Background(), "an-bulk-1"); err == nil {
+		t.Fatalf("expected the document's analysis to be deleted")
+	}
+	if _, err := app.Store.Open(context.Background(), doc.StorageKey); err == nil {
+		t.Fatalf("expected the stored upload to be deleted")
+	}
+
+	// Another user's documents are not found.
+	otherToken, _ := auth.SignJWT(auth.Claims{Sub: "other-user"})
+	resp = postJSON(router, "/api/v1/documents/bulk-delete", "Bearer "+otherToken, map[string]any{"documentIds": []string{third}})
+	var foreign bulkResponse
+	_ = json.NewDecoder(resp.Body).Decode(&foreign)
+	if foreign.Failed != 1 || foreign.Results[0].Status != documents.BulkNotFound {
+		t.Fatalf("expected another user's document not found, got %+v", foreign)
+	}
+}
+
+func TestBulkValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app, err := bootstrap.Build(config.Config{
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	token, _ := auth.SignJWT(auth.Claims{Sub: "bulk-user"})
+
+	if resp := postJSON(app.Router, "/api/v1/documents/bulk-delete", "", map[string]any{"documentIds": []string{"x"}}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected guests to get 401, got %d", resp.Code)
+	}
+	if resp := postJSON(app.Router, "/api/v1/documents/bulk-archive", "Bearer "+token, map[string]any{"documentIds": []string{" "}}); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without ids, got %d", resp.Code)
+	}
+	tooMany := make([]string, documents.MaxBulkDocuments+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("doc-%d", i)
+	}
+	if resp := postJSON(app.Router, "/api/v1/documents/bulk-delete", "Bearer "+token, map[string]any{"documentIds": tooMany}); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 over the limit, got %d", resp.Code)
+	}
+}
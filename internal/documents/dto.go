@@ -9,6 +9,13 @@ type DocumentResponse struct {
 	MimeType   string    `json:"mimeType"`
 	SizeBytes  int64     `json:"sizeBytes"`
 	UploadedAt time.Time `json:"uploadedAt"`
+	// Status is StatusReady once the text is extracted, otherwise StatusUploaded.
+	Status string `json:"status"`
+	// ExpiresAt and RetentionNotice are only set for guests whose data expires.
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	RetentionNotice string     `json:"retentionNotice,omitempty"`
+	// Linked is set when an upload matched an existing document and was linked to it.
+	Linked bool `json:"linked,omitempty"`
 }
 
 func toResponse(doc Document) DocumentResponse {
@@ -18,5 +25,6 @@ func toResponse(doc Document) DocumentResponse {
 		MimeType:   doc.MimeType,
 		SizeBytes:  doc.SizeBytes,
 		UploadedAt: doc.CreatedAt,
+		Status:     doc.Status(),
 	}
 }
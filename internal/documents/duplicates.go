@@ -0,0 +1,203 @@
+package documents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Duplicate match kinds.
+const (
+	DuplicateExact = "exact"
+	DuplicateNear  = "near"
+)
+
+const (
+	// DefaultNearDuplicateThreshold is the estimated text similarity above which two
+	// documents count as near-duplicates.
+	DefaultNearDuplicateThreshold = 0.9
+
+	minHashSize   = 64
+	shingleWords  = 3
+	maxDuplicates = 200
+	// maxSignaturesPerScan bounds how many missing text signatures one scan computes,
+	// so a long backlog cannot slow a single list request down.
+	maxSignaturesPerScan = 20
+)
+
+// Duplicate points at another of the user's documents with the same or nearly the
+// same content.
+type Duplicate struct {
+	DocumentID string  `json:"documentId"`
+	Match      string  `json:"match"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Fingerprint is what duplicate detection needs to know about a document.
+type Fingerprint struct {
+	DocumentID       string
+	Checksum         string
+	TextSignature    string
+	ExtractedTextKey string
+}
+
+// duplicateIndex is implemented by repos that store document fingerprints.
+type duplicateIndex interface {
+	// FindByChecksum returns the user's newest document with the given checksum.
+	FindByChecksum(ctx context.Context, userId, checksum string) (Document, error)
+	// ListFingerprints returns fingerprints for the user's newest documents.
+	ListFingerprints(ctx context.Context, userId string, limit int) ([]Fingerprint, error)
+	// SetTextSignature stores the MinHash signature of a document's extracted text.
+	SetTextSignature(ctx context.Context, userId, documentID, signature string) error
+}
+
+// FindDuplicates maps each of the user's documents to its exact and near duplicates.
+// Documents without duplicates are left out. Repos that cannot store fingerprints
+// report no duplicates.
+func (s *Service) FindDuplicates(ctx context.Context, userId string) (map[string][]Duplicate, error) {
+	idx, ok := s.Repo.(duplicateIndex)
+	if !ok {
+		return map[string][]Duplicate{}, nil
+	}
+	fps, err := idx.ListFingerprints(ctx, userId, maxDuplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	computed := 0
+	for i := range fps {
+		if fps[i].TextSignature != "" || fps[i].ExtractedTextKey == "" || computed == maxSignaturesPerScan {
+			continue
+		}
+		computed++
+		text, err := s.readObject(ctx, fps[i].ExtractedTextKey)
+		if err != nil {
+			log.Printf("duplicate scan: read extracted text document=%s: %v", fps[i].DocumentID, err)
+			continue
+		}
+		fps[i].TextSignature = encodeSignature(textSignature(string(text)))
+		if err := idx.SetTextSignature(ctx, userId, fps[i].DocumentID, fps[i].TextSignature); err != nil {
+			log.Printf("duplicate scan: store signature document=%s: %v", fps[i].DocumentID, err)
+		}
+	}
+
+	threshold := s.NearDuplicateThreshold
+	if threshold <= 0 {
+		threshold = DefaultNearDuplicateThreshold
+	}
+	out := make(map[string][]Duplicate)
+	for i := range fps {
+		for j := i + 1; j < len(fps); j++ {
+			match, similarity := compareFingerprints(fps[i], fps[j])
+			if match == "" || (match == DuplicateNear && similarity < threshold) {
+				continue
+			}
+			out[fps[i].DocumentID] = append(out[fps[i].DocumentID], Duplicate{DocumentID: fps[j].DocumentID, Match: match, Similarity: similarity})
+			out[fps[j].DocumentID] = append(out[fps[j].DocumentID], Duplicate{DocumentID: fps[i].DocumentID, Match: match, Similarity: similarity})
+		}
+	}
+	return out, nil
+}
+
+func compareFingerprints(a, b Fingerprint) (string, float64) {
+	if a.Checksum != "" && a.Checksum == b.Checksum {
+		return DuplicateExact, 1
+	}
+	sigA, errA := decodeSignature(a.TextSignature)
+	sigB, errB := decodeSignature(b.TextSignature)
+	if errA != nil || errB != nil || len(sigA) == 0 || len(sigB) == 0 {
+		return "", 0
+	}
+	return DuplicateNear, signatureSimilarity(sigA, sigB)
+}
+
+// contentChecksum is the hex SHA-256 of an upload's bytes.
+func contentChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// textSignature is a MinHash signature over word shingles of the text. The share
+// of equal positions in two signatures estimates the Jaccard similarity of their
+// shingle sets.
+func textSignature(text string) []uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+	n := min(shingleWords, len(words))
+
+	sig := make([]uint64, minHashSize)
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for start := 0; start+n <= len(words); start++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[start:start+n], " ")))
+		base := h.Sum64()
+		for i := range sig {
+			if v := mix64(base ^ uint64(i+1)*0x9e3779b97f4a7c15); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// mix64 is the splitmix64 finalizer; it derives independent-looking hash functions
+// from one base hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func signatureSimilarity(a, b []uint64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+func encodeSignature(sig []uint64) string {
+	var b strings.Builder
+	for _, v := range sig {
+		fmt.Fprintf(&b, "%016x", v)
+	}
+	return b.String()
+}
+
+func decodeSignature(s string) ([]uint64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if len(s)%16 != 0 {
+		return nil, fmt.Errorf("signature length %d is not a multiple of 16", len(s))
+	}
+	out := make([]uint64, 0, len(s)/16)
+	for i := 0; i < len(s); i += 16 {
+		v, err := strconv.ParseUint(s[i:i+16], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
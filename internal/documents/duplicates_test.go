@@ -0,0 +1,113 @@
+package documents_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+const duplicateResume = `Jane Doe
+Senior backend engineer with eight years of experience building payment systems in Go and Postgres.
+Led the migration of the billing platform to event sourcing and cut reconciliation time in half.
+Mentored six engineers and introduced structured design reviews across three teams.
+Built the fraud scoring service that screens every card transaction in under fifty milliseconds.`
+
+func newDuplicateService(t *testing.T) (*documents.Service, *documents.MemoryRepo) {
+	t.Helper()
+	repo := documents.NewMemoryRepo()
+	return &documents.Service{Store: local.New(t.TempDir()), Repo: repo}, repo
+}
+
+func uploadText(t *testing.T, svc *documents.Service, repo *documents.MemoryRepo, userID, name, content, extracted string, link bool) (documents.Document, bool) {
+	t.Helper()
+	ctx := context.Background()
+	doc, linked, err := svc.UploadWithOptions(ctx, userID, name, "text/plain", strings.NewReader(content), documents.UploadOptions{LinkDuplicates: link})
+	if err != nil {
+		t.Fatalf("upload %s: %v", name, err)
+	}
+	if linked || extracted == "" {
+		return doc, linked
+	}
+	key, _, _, err := svc.Store.Save(ctx, userID, name+".extracted.txt", strings.NewReader(extracted))
+	if err != nil {
+		t.Fatalf("save extracted text: %v", err)
+	}
+	if err := repo.UpdateExtraction(ctx, userID, doc.ID, key, time.Now().UTC()); err != nil {
+		t.Fatalf("update extraction: %v", err)
+	}
+	return doc, linked
+}
+
+func TestFindDuplicatesGroupsExactAndNearCopies(t *testing.T) {
+	svc, repo := newDuplicateService(t)
+	ctx := context.Background()
+
+	original, _ := uploadText(t, svc, repo, "user-1", "resume.txt", duplicateResume, duplicateResume, false)
+	copied, _ := uploadText(t, svc, repo, "user-1", "resume-copy.txt", duplicateResume, duplicateResume, false)
+	// A re-export with different formatting: same words, different bytes.
+	reformatted := strings.ToUpper(strings.ReplaceAll(duplicateResume, "\n", "\n\n"))
+	near, _ := uploadText(t, svc, repo, "user-1", "resume-export.txt", reformatted+"\n", reformatted, false)
+	other, _ := uploadText(t, svc, repo, "user-1", "cover.txt", "Dear hiring manager, I am writing to apply for the data analyst role at your company.", "Dear hiring manager, I am writing to apply for the data analyst role at your company.", false)
+
+	dups, err := svc.FindDuplicates(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("find duplicates: %v", err)
+	}
+	if got := dups[other.ID]; len(got) != 0 {
+		t.Fatalf("expected no duplicates for unrelated document, got %+v", got)
+	}
+
+	matches := map[string]string{}
+	for _, d := range dups[original.ID] {
+		matches[d.DocumentID] = d.Match
+	}
+	if matches[copied.ID] != documents.DuplicateExact {
+		t.Fatalf("expected exact match with the copy, got %+v", dups[original.ID])
+	}
+	if matches[near.ID] != documents.DuplicateNear {
+		t.Fatalf("expected near match with the reformatted export, got %+v", dups[original.ID])
+	}
+	if len(dups[near.ID]) != 2 {
+		t.Fatalf("expected the export to match both copies, got %+v", dups[near.ID])
+	}
+
+	// Other users never see each other's documents as duplicates.
+	uploadText(t, svc, repo, "user-2", "resume.txt", duplicateResume, duplicateResume, false)
+	dups, err = svc.FindDuplicates(ctx, "user-2")
+	if err != nil {
+		t.Fatalf("find duplicates for user-2: %v", err)
+	}
+	if len(dups) != 0 {
+		t.Fatalf("expected no duplicates across users, got %+v", dups)
+	}
+}
+
+func TestUploadLinksExactDuplicates(t *testing.T) {
+	svc, repo := newDuplicateService(t)
+	ctx := context.Background()
+
+	first, linked := uploadText(t, svc, repo, "user-1", "resume.txt", duplicateResume, "", false)
+	if linked {
+		t.Fatalf("first upload must not be linked")
+	}
+	second, linked := uploadText(t, svc, repo, "user-1", "resume-again.txt", duplicateResume, "", true)
+	if !linked || second.ID != first.ID {
+		t.Fatalf("expected upload to link to %s, got %s linked=%v", first.ID, second.ID, linked)
+	}
+	third, linked := uploadText(t, svc, repo, "user-1", "resume-again.txt", duplicateResume, "", false)
+	if linked || third.ID == first.ID {
+		t.Fatalf("expected a new document without linking, got %s linked=%v", third.ID, linked)
+	}
+
+	docs, err := repo.ListByUser(ctx, "user-1", 10, 0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 stored documents, got %d", len(docs))
+	}
+}
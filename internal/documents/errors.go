@@ -8,4 +8,22 @@ var (
 
 	// ErrInvalidInput indicates validation or bad input.
 	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrUnsupportedFormat indicates an export format that is not implemented.
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+
+	// ErrTooLarge indicates a file over the upload size limit.
+	ErrTooLarge = errors.New("file too large")
+
+	// ErrFetchFailed indicates a document could not be downloaded from its URL.
+	ErrFetchFailed = errors.New("document could not be downloaded")
+
+	// ErrContentMismatch indicates an upload's bytes do not match its declared content type.
+	ErrContentMismatch = errors.New("file content does not match declared content type")
+
+	// ErrUnreadableDocument indicates text could not be extracted from the stored file.
+	ErrUnreadableDocument = errors.New("document text could not be extracted")
+
+	// ErrNotPermitted indicates an operation the worker role may not perform.
+	ErrNotPermitted = errors.New("operation not permitted for worker role")
 )
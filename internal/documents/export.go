@@ -0,0 +1,155 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"resume-backend/internal/extract"
+	"resume-backend/resume/export"
+	"resume-backend/resume/model"
+)
+
+// Export formats supported by ExportResume.
+const (
+	ExportFormatJSONResume = "jsonresume"
+	ExportFormatHROpen     = "hropen"
+)
+
+// exportModeJobMatch is the analysis mode that scores against a job description.
+const exportModeJobMatch = "JOB_MATCH"
+
+// ExportAnalysis is the analysis an HR Open export reports as an assessment.
+type ExportAnalysis struct {
+	ID          string
+	Mode        string
+	Result      map[string]any
+	CompletedAt *time.Time
+}
+
+// ExportAnalysisSource finds the analysis to include in an export.
+type ExportAnalysisSource interface {
+	// LatestCompletedForDocument returns the user's newest completed analysis of
+	// the document, or ErrNotFound when there is none.
+	LatestCompletedForDocument(ctx context.Context, userID, documentID string) (ExportAnalysis, error)
+}
+
+// ResumeParser turns extracted resume text into a structured ResumeModel.
+type ResumeParser interface {
+	ParseResume(ctx context.Context, resumeText string) (model.ResumeModel, error)
+}
+
+// ExportResume parses the stored document and renders it in the requested format.
+func (s *Service) ExportResume(ctx context.Context, userId, documentID, format string) (any, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != ExportFormatJSONResume && format != ExportFormatHROpen {
+		return nil, ErrUnsupportedFormat
+	}
+	if s.Parser == nil {
+		return nil, errors.New("resume parser not configured")
+	}
+	if userId == "" || documentID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	doc, err := s.Repo.GetByID(ctx, userId, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := s.documentText(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := s.Parser.ParseResume(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("parse resume: %w", err)
+	}
+	if format == ExportFormatHROpen {
+		assessments, err := s.exportAssessments(ctx, userId, documentID)
+		if err != nil {
+			return nil, err
+		}
+		return export.ToHROpen(parsed, doc.ID, assessments), nil
+	}
+	return export.ToJSONResume(parsed), nil
+}
+
+// exportAssessments reports the document's latest completed analysis, if any.
+func (s *Service) exportAssessments(ctx context.Context, userID, documentID string) ([]export.HROpenAssessment, error) {
+	if s.Analyses == nil {
+		return nil, nil
+	}
+	analysis, err := s.Analyses.LatestCompletedForDocument(ctx, userID, documentID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("latest analysis: %w", err)
+	}
+	return []export.HROpenAssessment{toAssessment(analysis)}, nil
+}
+
+func toAssessment(analysis ExportAnalysis) export.HROpenAssessment {
+	out := export.HROpenAssessment{
+		ID:              export.HROpenIdentifier{Value: analysis.ID, SchemeID: "resume-analyzer"},
+		Type:            analysis.Mode,
+		Scores:          []export.HROpenScore{},
+		MissingKeywords: []string{},
+	}
+	if analysis.CompletedAt != nil {
+		out.CompletedDate = analysis.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	result := analysis.Result
+	addScore := func(kind string, value any) {
+		if score, ok := value.(float64); ok {
+			out.Scores = append(out.Scores, export.HROpenScore{Type: kind, Value: score, Minimum: 0, Maximum: 100})
+		}
+	}
+	addScore("final", result["finalScore"])
+	if analysis.Mode == exportModeJobMatch {
+		addScore("match", result["matchScore"])
+	}
+	if ats, ok := result["ats"].(map[string]any); ok {
+		addScore("ats", ats["score"])
+		missing, _ := ats["missingKeywords"].(map[string]any)
+		for _, group := range []string{"fromJobDescription", "industryCommon"} {
+			keywords, _ := missing[group].([]any)
+			for _, keyword := range keywords {
+				if text, ok := keyword.(string); ok && strings.TrimSpace(text) != "" {
+					out.MissingKeywords = append(out.MissingKeywords, text)
+				}
+			}
+		}
+	}
+	if summary, ok := result["summary"].(map[string]any); ok {
+		out.Summary, _ = summary["overallAssessment"].(string)
+	}
+	return out
+}
+
+// documentText prefers the persisted extraction and falls back to extracting the original upload.
+func (s *Service) documentText(ctx context.Context, doc Document) (string, error) {
+	if doc.ExtractedTextKey != "" {
+		if text, err := s.readObject(ctx, doc.ExtractedTextKey); err == nil && strings.TrimSpace(string(text)) != "" {
+			return string(text), nil
+		}
+	}
+	raw, err := s.readObject(ctx, doc.StorageKey)
+	if err != nil {
+		return "", fmt.Errorf("read document: %w", err)
+	}
+	mimeType := doc.ExtractionMimeType()
+	text, err := extract.ExtractTextFromBytes(ctx, raw, mimeType, doc.FileName)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnreadableDocument, err)
+	}
+	return text, nil
+}
+
+func (s *Service) readObject(ctx context.Context, key string) ([]byte, error) {
+	return readStoredObject(ctx, s.Store, key)
+}
@@ -0,0 +1,235 @@
+package documents_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/config"
+	"resume-backend/resume/model"
+)
+
+type stubParser struct {
+	gotText string
+}
+
+func (p *stubParser) ParseResume(ctx context.Context, resumeText string) (model.ResumeModel, error) {
+	p.gotText = resumeText
+	return model.ResumeModel{
+		Header: model.ResumeHeader{Name: "Jane Doe", Email: "jane@example.com"},
+		Experience: []model.ResumeExperience{
+			{Company: "Acme", Role: "Engineer", Start: "2021-02", End: "Present"},
+		},
+	}, nil
+}
+
+func TestDocumentsExportJSONResume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	parser := &stubParser{}
+	app.DocumentsService.Parser = parser
+	router := app.Router
+
+	docID := uploadDOCX(t, router, "Jane Doe\nEngineer at Acme")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+docID+"/export?format=jsonresume", nil)
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if !strings.Contains(parser.gotText, "Engineer at Acme") {
+		t.Fatalf("expected extracted text to reach parser, got %q", parser.gotText)
+	}
+
+	var out struct {
+		Basics struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"basics"`
+		Work []struct {
+			Name      string `json:"name"`
+			StartDate string `json:"startDate"`
+		} `json:"work"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if out.Basics.Name != "Jane Doe" || len(out.Work) != 1 || out.Work[0].StartDate != "2021-02" {
+		t.Fatalf("unexpected export payload: %+v", out)
+	}
+
+	reqBad := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+docID+"/export?format=pdf", nil)
+	addGuestHeader(reqBad)
+	respBad := httptest.NewRecorder()
+	router.ServeHTTP(respBad, reqBad)
+	if respBad.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unsupported format, got %d", respBad.Code)
+	}
+
+	reqMissing := httptest.NewRequest(http.MethodGet, "/api/v1/documents/missing/export?format=jsonresume", nil)
+	addGuestHeader(reqMissing)
+	respMissing := httptest.NewRecorder()
+	router.ServeHTTP(respMissing, reqMissing)
+	if respMissing.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for missing document, got %d", respMissing.Code)
+	}
+}
+
+func TestDocumentsExportHROpenIncludesLatestAnalysis(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	app.DocumentsService.Parser = &stubParser{}
+	router := app.Router
+
+	docID := uploadDOCX(t, router, "Jane Doe\nEngineer at Acme")
+	completedAt := time.Now().UTC()
+	if err := app.AnalysesRepo.Create(context.Background(), analyses.Analysis{
+		ID:          "analysis-1",
+		DocumentID:  docID,
+		UserID:      "guest:test-guest",
+		Status:      analyses.StatusCompleted,
+		Mode:        analyses.ModeATS,
+		CreatedAt:   completedAt,
+		CompletedAt: &completedAt,
+		Result: map[string]any{
+			"finalScore": 72.0,
+			"ats": map[string]any{
+				"score":           72.0,
+				"missingKeywords": map[string]any{"fromJobDescription": []any{}, "industryCommon": []any{"Kubernetes"}},
+			},
+			"summary": map[string]any{"overallAssessment": "Solid backend profile."},
+		},
+	}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+docID+"/export?format=hropen", nil)
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var out struct {
+		DocumentID struct {
+			Value string `json:"value"`
+		} `json:"documentId"`
+		Person struct {
+			Name struct {
+				Given  string `json:"given"`
+				Family string `json:"family"`
+			} `json:"name"`
+		} `json:"person"`
+		Profiles []struct {
+			Employment []struct {
+				Organization struct {
+					Name string `json:"name"`
+				} `json:"organization"`
+			} `json:"employment"`
+		} `json:"profiles"`
+		Assessments []struct {
+			Type   string `json:"type"`
+			Scores []struct {
+				Type  string  `json:"type"`
+				Value float64 `json:"value"`
+			} `json:"scores"`
+			Summary         string   `json:"summary"`
+			MissingKeywords []string `json:"missingKeywords"`
+		} `json:"assessments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if out.DocumentID.Value != docID || out.Person.Name.Given != "Jane" || out.Person.Name.Family != "Doe" {
+		t.Fatalf("unexpected candidate: %+v", out)
+	}
+	if len(out.Profiles) != 1 || len(out.Profiles[0].Employment) != 1 || out.Profiles[0].Employment[0].Organization.Name != "Acme" {
+		t.Fatalf("unexpected profiles: %+v", out.Profiles)
+	}
+	if len(out.Assessments) != 1 {
+		t.Fatalf("expected one assessment, got %+v", out.Assessments)
+	}
+	a := out.Assessments[0]
+	if a.Type != "ATS" || len(a.Scores) != 2 || a.Summary != "Solid backend profile." || len(a.MissingKeywords) != 1 {
+		t.Fatalf("unexpected assessment: %+v", a)
+	}
+}
+
+func uploadDOCX(t *testing.T, router http.Handler, text string) string {
+	t.Helper()
+
+	var docx bytes.Buffer
+	zw := zip.NewWriter(&docx)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("create document.xml: %v", err)
+	}
+	var paras strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		paras.WriteString("<w:p><w:r><w:t>" + line + "</w:t></w:r></w:p>")
+	}
+	if _, err := w.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + paras.String() + `</w:body></w:document>`)); err != nil {
+		t.Fatalf("write document.xml: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, err := writer.CreateFormFile("file", "resume.docx")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fileWriter.Write(docx.Bytes()); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", resp.Code)
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+	return created.DocumentID
+}
@@ -0,0 +1,222 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"resume-backend/internal/extract"
+)
+
+const (
+	fetchTimeout      = 20 * time.Second
+	maxFetchRedirects = 5
+	defaultFetchName  = "resume"
+)
+
+// errBlockedAddress is returned when a URL resolves to an address the fetcher
+// must not reach, such as loopback or the cloud metadata service.
+var errBlockedAddress = errors.New("url resolves to a private or reserved address")
+
+// URLFetcher downloads documents from user-supplied URLs. Only http and https
+// URLs are fetched, and connections to private, loopback, link-local and other
+// reserved addresses are refused at dial time, so redirects and DNS answers
+// cannot point the server at internal services.
+type URLFetcher struct {
+	Client *http.Client
+	// MaxBytes caps the download; it defaults to the upload limit.
+	MaxBytes int64
+}
+
+// NewURLFetcher constructs a URLFetcher with SSRF protections.
+func NewURLFetcher() *URLFetcher {
+	return newURLFetcher(publicAddress)
+}
+
+func newURLFetcher(allow func(netip.Addr) bool) *URLFetcher {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !allow(addr.Unmap()) {
+				return errBlockedAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		// Proxies would dial on our behalf and bypass the address check.
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return &URLFetcher{
+		Client: &http.Client{
+			Transport: transport,
+			Timeout:   fetchTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxFetchRedirects {
+					return errors.New("too many redirects")
+				}
+				return checkScheme(req.URL)
+			},
+		},
+		MaxBytes: maxUploadSize,
+	}
+}
+
+// FetchedDocument is a downloaded file, ready to be uploaded.
+type FetchedDocument struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Fetch downloads rawURL. Google Drive and Dropbox share links are rewritten to
+// their direct-download form first. The file must be a supported resume format
+// and no larger than MaxBytes.
+func (f *URLFetcher) Fetch(ctx context.Context, rawURL string) (FetchedDocument, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return FetchedDocument{}, fmt.Errorf("%w: url is not valid", ErrInvalidInput)
+	}
+	if err := checkScheme(u); err != nil {
+		return FetchedDocument{}, err
+	}
+	if u.User != nil {
+		return FetchedDocument{}, fmt.Errorf("%w: url must not contain credentials", ErrInvalidInput)
+	}
+	u = directDownloadURL(u)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return FetchedDocument{}, fmt.Errorf("%w: url is not valid", ErrInvalidInput)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		if errors.Is(err, errBlockedAddress) {
+			return FetchedDocument{}, fmt.Errorf("%w: %v", ErrInvalidInput, errBlockedAddress)
+		}
+		if errors.Is(err, ErrInvalidInput) {
+			return FetchedDocument{}, err
+		}
+		return FetchedDocument{}, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return FetchedDocument{}, fmt.Errorf("%w: remote server returned %d", ErrFetchFailed, resp.StatusCode)
+	}
+
+	maxBytes := f.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = maxUploadSize
+	}
+	if resp.ContentLength > maxBytes {
+		return FetchedDocument{}, fmt.Errorf("%w: file is larger than %d bytes", ErrTooLarge, maxBytes)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return FetchedDocument{}, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return FetchedDocument{}, fmt.Errorf("%w: file is larger than %d bytes", ErrTooLarge, maxBytes)
+	}
+	if len(data) == 0 {
+		return FetchedDocument{}, fmt.Errorf("%w: file is empty", ErrInvalidInput)
+	}
+
+	name := fetchedFileName(resp, resp.Request.URL)
+	detected := extract.DetectMimeType(data, name)
+	if extract.ExtensionForMime(detected) == "" && detected != "text/plain" {
+		// Share pages, sign-in walls and virus-scan interstitials come back as HTML.
+		return FetchedDocument{}, fmt.Errorf("%w: url did not return a resume file (got %s); use a direct or public share link", ErrInvalidInput, detected)
+	}
+	return FetchedDocument{
+		FileName:    name,
+		ContentType: resp.Header.Get("Content-Type"),
+		Data:        data,
+	}, nil
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: only http and https urls are supported", ErrInvalidInput)
+	}
+	return nil
+}
+
+// publicAddress reports whether addr is a public unicast address.
+func publicAddress(addr netip.Addr) bool {
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// reservedPrefixes are global unicast ranges that still must not be fetched.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which can embed private IPv4 addresses
+}
+
+var driveFilePath = regexp.MustCompile(`^/file/d/([A-Za-z0-9_-]+)`)
+
+// directDownloadURL rewrites Google Drive and Dropbox share links to the URLs
+// that return the file itself rather than a preview page.
+func directDownloadURL(u *url.URL) *url.URL {
+	out := *u
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "drive.google.com":
+		if m := driveFilePath.FindStringSubmatch(u.Path); m != nil {
+			out.Path = "/uc"
+			out.RawQuery = url.Values{"export": {"download"}, "id": {m[1]}}.Encode()
+		}
+	case host == "dropbox.com" || host == "www.dropbox.com":
+		q := out.Query()
+		q.Set("dl", "1")
+		out.RawQuery = q.Encode()
+	}
+	return &out
+}
+
+// fetchedFileName takes the name from Content-Disposition, then the final URL's
+// last path segment. Upload corrects the extension from the content.
+func fetchedFileName(resp *http.Response, final *url.URL) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := path.Base(strings.ReplaceAll(params["filename"], `\`, "/")); name != "" && name != "." && name != "/" {
+			return name
+		}
+	}
+	if final != nil {
+		if name := path.Base(final.Path); name != "" && name != "." && name != "/" && name != "uc" {
+			return name
+		}
+	}
+	return defaultFetchName
+}
@@ -1,13 +1,18 @@
 package documents
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"resume-backend/internal/events"
 	"resume-backend/internal/shared/server/middleware"
 	"resume-backend/internal/shared/server/respond"
 )
@@ -17,6 +22,10 @@ const maxUploadSize = 10 << 20 // 10MB
 // Handler wires HTTP handlers to the service.
 type Handler struct {
 	Svc *Service
+	// GuestRetention is how long guest documents are kept; zero means forever.
+	GuestRetention time.Duration
+	// Events receives funnel analytics; nil disables them.
+	Events *events.Emitter
 }
 
 // NewHandler constructs a Handler.
@@ -28,8 +37,17 @@ func NewHandler(svc *Service) *Handler {
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.POST("/documents", h.upload)
 	rg.POST("/documents/from-s3", h.createFromS3)
+	rg.POST("/documents/from-url", h.createFromURL)
+	rg.POST("/documents/bulk-delete", h.bulkDelete)
+	rg.POST("/documents/bulk-archive", h.bulkArchive)
+	rg.POST("/documents/bulk-unarchive", h.bulkUnarchive)
+	rg.GET("/bulk-jobs/:id", h.getBulkJob)
+	rg.POST("/bulk-jobs/:id/resume", h.resumeBulkJob)
 	rg.GET("/documents/current", h.current)
 	rg.GET("/documents", h.list)
+	rg.GET("/documents/:id", h.get)
+	rg.GET("/documents/:id/export", h.export)
+	rg.GET("/documents/:id/lint", h.lint)
 }
 
 func (h *Handler) upload(c *gin.Context) {
@@ -49,18 +67,38 @@ func (h *Handler) upload(c *gin.Context) {
 	}
 	defer file.Close()
 
-	doc, err := h.Svc.Upload(c.Request.Context(), userID, fileHeader.Filename, file)
+	declaredType := fileHeader.Header.Get("Content-Type")
+	opts := UploadOptions{LinkDuplicates: isTrue(c.PostForm("linkDuplicates")) || isTrue(c.Query("linkDuplicates"))}
+	doc, linked, err := h.Svc.UploadWithOptions(c.Request.Context(), userID, fileHeader.Filename, declaredType, file, opts)
 	if err != nil {
 		switch {
+		case errors.Is(err, ErrContentMismatch):
+			respond.Error(c, http.StatusUnsupportedMediaType, "content_type_mismatch", err.Error(), []map[string]string{
+				{"field": "file", "issue": "content_type_mismatch"},
+			})
 		case errors.Is(err, ErrInvalidInput):
 			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
 		default:
-			respond.Error(c, http.StatusInternalServerError, "failed to upload document", err.Error(), nil)
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to upload document", err)
 		}
 		return
 	}
 
-	respond.JSON(c, http.StatusCreated, toResponse(doc))
+	if linked {
+		// The existing document is reused as is: no new upload, extraction or analysis.
+		resp := h.guestResponse(c, doc)
+		resp.Linked = true
+		respond.JSON(c, http.StatusOK, resp)
+		return
+	}
+
+	h.trackUpload(c, doc, "upload")
+	respond.JSON(c, http.StatusCreated, h.guestResponse(c, doc))
+}
+
+func isTrue(v string) bool {
+	parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+	return err == nil && parsed
 }
 
 type createFromS3Request struct {
@@ -106,12 +144,71 @@ func (h *Handler) createFromS3(c *gin.Context) {
 		case errors.Is(err, ErrInvalidInput):
 			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
 		default:
-			respond.Error(c, http.StatusInternalServerError, "failed to create document", err.Error(), nil)
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to create document", err)
 		}
 		return
 	}
 
-	respond.JSON(c, http.StatusCreated, toResponse(doc))
+	h.trackUpload(c, doc, "s3")
+	respond.JSON(c, http.StatusCreated, h.guestResponse(c, doc))
+}
+
+type createFromURLRequest struct {
+	URL            string `json:"url"`
+	LinkDuplicates bool   `json:"linkDuplicates"`
+}
+
+func (h *Handler) createFromURL(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+
+	var req createFromURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if req.URL == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "url is required", nil)
+		return
+	}
+
+	opts := UploadOptions{LinkDuplicates: req.LinkDuplicates}
+	doc, linked, err := h.Svc.UploadFromURL(c.Request.Context(), userID, req.URL, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTooLarge):
+			respond.Error(c, http.StatusRequestEntityTooLarge, "file_too_large", err.Error(), nil)
+		case errors.Is(err, ErrContentMismatch):
+			respond.Error(c, http.StatusUnsupportedMediaType, "content_type_mismatch", err.Error(), []map[string]string{
+				{"field": "url", "issue": "content_type_mismatch"},
+			})
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		case errors.Is(err, ErrFetchFailed):
+			respond.Error(c, http.StatusBadGateway, "fetch_failed", err.Error(), nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to upload document", err)
+		}
+		return
+	}
+
+	if linked {
+		resp := h.guestResponse(c, doc)
+		resp.Linked = true
+		respond.JSON(c, http.StatusOK, resp)
+		return
+	}
+
+	h.trackUpload(c, doc, "url")
+	respond.JSON(c, http.StatusCreated, h.guestResponse(c, doc))
+}
+
+func (h *Handler) trackUpload(c *gin.Context, doc Document, source string) {
+	h.Events.Track(c.Request.Context(), events.DocumentUploaded, doc.UserID, map[string]any{
+		"mime_type":  doc.MimeType,
+		"size_bytes": doc.SizeBytes,
+		"source":     source,
+	})
 }
 
 func (h *Handler) current(c *gin.Context) {
@@ -130,7 +227,25 @@ func (h *Handler) current(c *gin.Context) {
 		return
 	}
 
-	respond.JSON(c, http.StatusOK, toResponse(doc))
+	respond.CachedJSON(c, respond.CacheRevalidate, h.guestResponse(c, doc))
+}
+
+// get returns one of the user's documents.
+func (h *Handler) get(c *gin.Context) {
+	doc, err := h.Svc.Get(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "document not found", nil)
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to fetch document", nil)
+		}
+		return
+	}
+
+	respond.CachedJSON(c, respond.CacheRevalidate, h.guestResponse(c, doc))
 }
 
 func (h *Handler) list(c *gin.Context) {
@@ -167,7 +282,13 @@ func (h *Handler) list(c *gin.Context) {
 		offset = 0
 	}
 
-	docs, err := h.Svc.List(c.Request.Context(), userID, limit, offset)
+	var docs []Document
+	var err error
+	if isTrue(c.Query("archived")) {
+		docs, err = h.Svc.ListArchived(c.Request.Context(), userID, limit, offset)
+	} else {
+		docs, err = h.Svc.List(c.Request.Context(), userID, limit, offset)
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrInvalidInput):
@@ -178,16 +299,171 @@ func (h *Handler) list(c *gin.Context) {
 		return
 	}
 
+	duplicates, err := h.Svc.FindDuplicates(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("list documents: duplicate scan user=%s: %v", userID, err)
+		duplicates = nil
+	}
+
 	resp := make([]gin.H, 0, len(docs))
 	for _, doc := range docs {
+		dups := duplicates[doc.ID]
+		if dups == nil {
+			dups = []Duplicate{}
+		}
 		resp = append(resp, gin.H{
 			"documentId": doc.ID,
 			"fileName":   doc.FileName,
 			"mimeType":   doc.MimeType,
 			"sizeBytes":  doc.SizeBytes,
 			"uploadedAt": doc.CreatedAt,
+			"duplicates": dups,
 		})
 	}
 
-	respond.JSON(c, http.StatusOK, resp)
+	respond.ListJSON(c, respond.CacheRevalidate, resp)
+}
+
+type bulkRequest struct {
+	DocumentIDs []string `json:"documentIds"`
+}
+
+// bulkDelete deletes documents with their analyses and stored files.
+func (h *Handler) bulkDelete(c *gin.Context) {
+	h.bulk(c, func(userID string, ids []string) (BulkJob, error) {
+		return h.Svc.BulkDelete(c.Request.Context(), userID, ids)
+	})
+}
+
+// bulkArchive hides documents from the history list without deleting them.
+func (h *Handler) bulkArchive(c *gin.Context) {
+	h.bulk(c, func(userID string, ids []string) (BulkJob, error) {
+		return h.Svc.BulkArchive(c.Request.Context(), userID, ids, true)
+	})
+}
+
+// bulkUnarchive returns archived documents to the history list.
+func (h *Handler) bulkUnarchive(c *gin.Context) {
+	h.bulk(c, func(userID string, ids []string) (BulkJob, error) {
+		return h.Svc.BulkArchive(c.Request.Context(), userID, ids, false)
+	})
+}
+
+// bulk runs a bulk operation and reports a result per document. The request
+// succeeds even when some documents fail; clients read each result's status
+// and resume the job to retry the failures.
+func (h *Handler) bulk(c *gin.Context, run func(userID string, ids []string) (BulkJob, error)) {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to manage documents in bulk", nil)
+		return
+	}
+	var req bulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	job, err := run(middleware.UserIDFromContext(c), req.DocumentIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to update documents", nil)
+		}
+		return
+	}
+	respond.JSON(c, http.StatusOK, job)
+}
+
+// getBulkJob reports the progress of a recorded bulk job.
+func (h *Handler) getBulkJob(c *gin.Context) {
+	h.bulkJob(c, h.Svc.GetBulkJob)
+}
+
+// resumeBulkJob retries the documents of a bulk job that failed or were never
+// attempted.
+func (h *Handler) resumeBulkJob(c *gin.Context) {
+	h.bulkJob(c, h.Svc.ResumeBulkJob)
+}
+
+func (h *Handler) bulkJob(c *gin.Context, run func(ctx context.Context, userID, jobID string) (BulkJob, error)) {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to manage documents in bulk", nil)
+		return
+	}
+	job, err := run(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "bulk job not found", nil)
+		case errors.Is(err, ErrBulkJobsUnsupported):
+			respond.Error(c, http.StatusNotImplemented, "not_supported", "bulk jobs are not recorded", nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load bulk job", nil)
+		}
+		return
+	}
+	respond.JSON(c, http.StatusOK, job)
+}
+
+func (h *Handler) export(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	documentID := c.Param("id")
+	c.Set("documentId", documentID)
+
+	format := c.DefaultQuery("format", ExportFormatJSONResume)
+	out, err := h.Svc.ExportResume(c.Request.Context(), userID, documentID, format)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnsupportedFormat):
+			respond.Error(c, http.StatusBadRequest, "validation_error", "format is not supported", []map[string]string{
+				{"field": "format", "issue": "unsupported"},
+			})
+		case errors.Is(err, ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "document not found", nil)
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		case errors.Is(err, ErrUnreadableDocument):
+			respond.Error(c, http.StatusUnprocessableEntity, "unreadable_document", "document text could not be extracted", err)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to export document", err)
+		}
+		return
+	}
+
+	respond.JSON(c, http.StatusOK, out)
+}
+
+func (h *Handler) lint(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	documentID := c.Param("id")
+	c.Set("documentId", documentID)
+
+	report, err := h.Svc.Lint(c.Request.Context(), userID, documentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			respond.Error(c, http.StatusNotFound, "not_found", "document not found", nil)
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to lint document", nil)
+		}
+		return
+	}
+
+	respond.JSON(c, http.StatusOK, report)
+}
+
+// guestResponse builds the document response and, for guests, announces when it expires.
+func (h *Handler) guestResponse(c *gin.Context, doc Document) DocumentResponse {
+	resp := toResponse(doc)
+	if h.GuestRetention <= 0 || !middleware.IsGuest(c) {
+		return resp
+	}
+	expiresAt := doc.CreatedAt.Add(h.GuestRetention).UTC()
+	resp.ExpiresAt = &expiresAt
+	resp.RetentionNotice = fmt.Sprintf("Guest documents and their analyses are deleted after %d days. Sign in to keep them.", int(h.GuestRetention/(24*time.Hour)))
+	c.Header("X-Guest-Expires-At", expiresAt.Format(time.RFC3339))
+	return resp
 }
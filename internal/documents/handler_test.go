@@ -86,6 +86,36 @@ func TestDocumentsUploadAndCurrent(t *testing.T) {
 	if current.FileName != "hello.txt" {
 		t.Fatalf("expected fileName hello.txt, got %s", current.FileName)
 	}
+
+	// Fetch by id, then revalidate with the returned ETag.
+	reqByID := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+created.DocumentID, nil)
+	addGuestHeader(reqByID)
+	respByID := httptest.NewRecorder()
+	router.ServeHTTP(respByID, reqByID)
+	if respByID.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", respByID.Code)
+	}
+	etag := respByID.Header().Get("ETag")
+	if etag == "" || respByID.Header().Get("Cache-Control") == "" {
+		t.Fatalf("expected ETag and Cache-Control, got %v", respByID.Header())
+	}
+
+	reqAgain := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+created.DocumentID, nil)
+	reqAgain.Header.Set("If-None-Match", etag)
+	addGuestHeader(reqAgain)
+	respAgain := httptest.NewRecorder()
+	router.ServeHTTP(respAgain, reqAgain)
+	if respAgain.Code != http.StatusNotModified || respAgain.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d with %q", respAgain.Code, respAgain.Body.String())
+	}
+
+	reqMissing := httptest.NewRequest(http.MethodGet, "/api/v1/documents/missing", nil)
+	addGuestHeader(reqMissing)
+	respMissing := httptest.NewRecorder()
+	router.ServeHTTP(respMissing, reqMissing)
+	if respMissing.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", respMissing.Code)
+	}
 }
 
 func addGuestHeader(req *http.Request) {
@@ -0,0 +1,84 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MinInlineTextChars and MaxInlineTextChars bound pasted resume text.
+	MinInlineTextChars = 200
+	MaxInlineTextChars = 100000
+
+	// inlineFileName names documents created from pasted text.
+	inlineFileName = "pasted-resume.txt"
+	inlineMime     = "text/plain"
+)
+
+// CreateFromText records a document for resume text the user pasted instead of
+// uploading a file. The text is stored once and serves as both the upload and
+// the extracted text, so the document is ready to analyze at once. Pasting the
+// same text again returns the existing document and reports it as linked.
+func (s *Service) CreateFromText(ctx context.Context, userId, text string) (Document, bool, error) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if userId == "" || !utf8.ValidString(text) {
+		return Document{}, false, ErrInvalidInput
+	}
+	if n := utf8.RuneCountInString(text); n < MinInlineTextChars || n > MaxInlineTextChars {
+		return Document{}, false, fmt.Errorf("%w: resume text must be %d to %d characters", ErrInvalidInput, MinInlineTextChars, MaxInlineTextChars)
+	}
+
+	data := []byte(text)
+	checksum := contentChecksum(data)
+	if idx, ok := s.Repo.(duplicateIndex); ok {
+		existing, err := idx.FindByChecksum(ctx, userId, checksum)
+		switch {
+		case err == nil:
+			return existing, true, nil
+		case !errors.Is(err, ErrNotFound):
+			return Document{}, false, err
+		}
+	}
+
+	key, size, _, err := s.Store.Save(ctx, userId, inlineFileName, strings.NewReader(text))
+	if err != nil {
+		return Document{}, false, err
+	}
+	storageProvider := s.StorageProvider
+	if storageProvider == "" {
+		storageProvider = "local"
+	}
+	now := time.Now().UTC()
+	doc := Document{
+		ID:               uuid.NewString(),
+		UserID:           userId,
+		FileName:         inlineFileName,
+		OriginalFilename: inlineFileName,
+		MimeType:         inlineMime,
+		ContentType:      inlineMime,
+		VerifiedMime:     inlineMime,
+		SizeBytes:        size,
+		StorageProvider:  storageProvider,
+		StorageKey:       key,
+		Checksum:         checksum,
+		CreatedAt:        now,
+	}
+	if err := s.Repo.Create(ctx, doc); err != nil {
+		return Document{}, false, err
+	}
+	if err := s.Repo.UpdateExtraction(ctx, userId, doc.ID, key, now); err != nil {
+		return Document{}, false, err
+	}
+	doc.ExtractedTextKey = key
+	doc.ExtractedAt = &now
+
+	log.Printf("Created document %s from pasted text for user %s: size=%d", doc.ID, userId, size)
+	return doc, false, nil
+}
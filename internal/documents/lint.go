@@ -0,0 +1,223 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"resume-backend/internal/extract"
+)
+
+// Lint severities, from most to least serious.
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityInfo    = "info"
+)
+
+const (
+	lintLargeFileBytes = 2 << 20 // 2MB
+	lintWordsPerPage   = 450
+	lintMaxPages       = 2
+	lintMinWords       = 150
+)
+
+// lintMaxDocumentXMLBytes caps how much of word/document.xml is inflated,
+// so a small, highly compressed upload cannot exhaust memory.
+const lintMaxDocumentXMLBytes = 8 << 20 // 8MB
+
+// LintFinding is a single deterministic check result.
+type LintFinding struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// LintContact records which contact details were detected.
+type LintContact struct {
+	Email    bool `json:"email"`
+	Phone    bool `json:"phone"`
+	LinkedIn bool `json:"linkedin"`
+}
+
+// LintReport is the result of running deterministic checks against a stored document.
+type LintReport struct {
+	DocumentID     string          `json:"documentId"`
+	SizeBytes      int64           `json:"sizeBytes"`
+	EstimatedPages int             `json:"estimatedPages"`
+	WordCount      int             `json:"wordCount"`
+	Contact        LintContact     `json:"contact"`
+	Sections       map[string]bool `json:"sections"`
+	Findings       []LintFinding   `json:"findings"`
+}
+
+var (
+	lintEmailRe    = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	lintPhoneRe    = regexp.MustCompile(`(?:\+?\d[ \t().\-]*){9,15}\d`)
+	lintLinkedInRe = regexp.MustCompile(`(?i)linkedin\.com/`)
+	lintPDFPageRe  = regexp.MustCompile(`/Type\s*/Page[^s]`)
+	lintPDFImageRe = regexp.MustCompile(`/Subtype\s*/Image`)
+)
+
+// lintSections maps a section key to the headings that introduce it.
+var lintSections = map[string][]string{
+	"summary":    {"summary", "professional summary", "profile", "about me", "objective"},
+	"experience": {"experience", "work experience", "professional experience", "employment history", "work history"},
+	"education":  {"education", "academic background"},
+	"skills":     {"skills", "technical skills", "core competencies", "key skills"},
+}
+
+// requiredLintSections are reported when missing; the rest are informational.
+var requiredLintSections = []string{"experience", "education", "skills"}
+
+// Lint runs deterministic checks against the stored upload. It never calls the LLM.
+func (s *Service) Lint(ctx context.Context, userId, documentID string) (LintReport, error) {
+	if userId == "" || documentID == "" {
+		return LintReport{}, ErrInvalidInput
+	}
+	doc, err := s.Repo.GetByID(ctx, userId, documentID)
+	if err != nil {
+		return LintReport{}, err
+	}
+	raw, err := s.readObject(ctx, doc.StorageKey)
+	if err != nil {
+		return LintReport{}, fmt.Errorf("read document: %w", err)
+	}
+	mimeType := doc.ExtractionMimeType()
+	text, textErr := extract.ExtractTextFromBytes(ctx, raw, mimeType, doc.FileName)
+	if err := ctx.Err(); err != nil {
+		return LintReport{}, err
+	}
+
+	report := lintDocument(raw, text, textErr)
+	report.DocumentID = doc.ID
+	return report, nil
+}
+
+func lintDocument(raw []byte, text string, textErr error) LintReport {
+	report := LintReport{
+		SizeBytes: int64(len(raw)),
+		Sections:  map[string]bool{},
+		Findings:  []LintFinding{},
+	}
+	add := func(code, severity, message string) {
+		report.Findings = append(report.Findings, LintFinding{Code: code, Severity: severity, Message: message})
+	}
+
+	if report.SizeBytes > lintLargeFileBytes {
+		add("file_large", LintSeverityWarning, "File is larger than 2MB; some applicant tracking systems reject large uploads.")
+	}
+
+	isPDF := bytes.HasPrefix(raw, []byte("%PDF"))
+	isZip := bytes.HasPrefix(raw, []byte("PK"))
+	switch {
+	case isPDF:
+		if lintPDFImageRe.Match(raw) {
+			add("images_detected", LintSeverityWarning, "Images were found; text inside images is invisible to ATS parsers.")
+		}
+	case isZip:
+		lintDOCXLayout(raw, add)
+	}
+
+	if textErr != nil || strings.TrimSpace(text) == "" {
+		add("text_not_extractable", LintSeverityError, "No selectable text was found; scanned or image-only resumes cannot be parsed.")
+		if isPDF {
+			report.EstimatedPages = len(lintPDFPageRe.FindAll(raw, -1))
+		}
+		return report
+	}
+
+	report.WordCount = len(strings.Fields(text))
+	report.EstimatedPages = (report.WordCount + lintWordsPerPage - 1) / lintWordsPerPage
+	if isPDF {
+		if pages := len(lintPDFPageRe.FindAll(raw, -1)); pages > 0 {
+			report.EstimatedPages = pages
+		}
+	}
+	if report.EstimatedPages > lintMaxPages {
+		add("too_long", LintSeverityWarning, fmt.Sprintf("Resume is about %d pages; aim for %d or fewer.", report.EstimatedPages, lintMaxPages))
+	}
+	if report.WordCount < lintMinWords {
+		add("too_short", LintSeverityWarning, "Resume has very little text; add detail to experience and skills.")
+	}
+
+	report.Contact = LintContact{
+		Email:    lintEmailRe.MatchString(text),
+		Phone:    lintPhoneRe.MatchString(text),
+		LinkedIn: lintLinkedInRe.MatchString(text),
+	}
+	if !report.Contact.Email {
+		add("missing_email", LintSeverityError, "No email address found.")
+	}
+	if !report.Contact.Phone {
+		add("missing_phone", LintSeverityWarning, "No phone number found.")
+	}
+	if !report.Contact.LinkedIn {
+		add("missing_linkedin", LintSeverityInfo, "No LinkedIn profile URL found.")
+	}
+
+	for key := range lintSections {
+		report.Sections[key] = false
+	}
+	for _, line := range strings.Split(text, "\n") {
+		heading := strings.ToLower(strings.Trim(strings.TrimSpace(line), ":-|•"))
+		heading = strings.Join(strings.Fields(heading), " ")
+		if heading == "" || len(heading) > 40 {
+			continue
+		}
+		for key, names := range lintSections {
+			for _, name := range names {
+				if heading == name {
+					report.Sections[key] = true
+				}
+			}
+		}
+	}
+	for _, key := range requiredLintSections {
+		if !report.Sections[key] {
+			add("missing_section_"+key, LintSeverityWarning, fmt.Sprintf("No %s section heading found.", key))
+		}
+	}
+	return report
+}
+
+// lintDOCXLayout flags DOCX constructs that ATS parsers commonly drop or scramble.
+func lintDOCXLayout(raw []byte, add func(code, severity, message string)) {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return
+	}
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(rc, lintMaxDocumentXMLBytes+1))
+		rc.Close()
+		if err != nil {
+			return
+		}
+		if len(body) > lintMaxDocumentXMLBytes {
+			add("document_too_large", LintSeverityWarning, "Document body is larger than 8MB uncompressed; layout checks were skipped.")
+			return
+		}
+		xml := string(body)
+		if strings.Contains(xml, "<w:tbl>") || strings.Contains(xml, "<w:tbl ") {
+			add("tables_detected", LintSeverityWarning, "Tables were found; many ATS parsers read table cells out of order.")
+		}
+		if strings.Contains(xml, "<w:drawing") || strings.Contains(xml, "<w:pict") {
+			add("images_detected", LintSeverityWarning, "Images were found; text inside images is invisible to ATS parsers.")
+		}
+		if strings.Contains(xml, "<w:txbxContent") {
+			add("text_boxes_detected", LintSeverityWarning, "Text boxes were found; their content is often skipped by ATS parsers.")
+		}
+		return
+	}
+}
@@ -0,0 +1,178 @@
+package documents_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/config"
+)
+
+func buildDOCX(t *testing.T, body string) []byte {
+	t.Helper()
+	var docx bytes.Buffer
+	zw := zip.NewWriter(&docx)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("create document.xml: %v", err)
+	}
+	if _, err := w.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body + `</w:body></w:document>`)); err != nil {
+		t.Fatalf("write document.xml: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return docx.Bytes()
+}
+
+func paragraphs(lines ...string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString("<w:p><w:r><w:t>" + line + "</w:t></w:r></w:p>")
+	}
+	return b.String()
+}
+
+func uploadRaw(t *testing.T, router http.Handler, fileName string, data []byte) string {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fileWriter.Write(data); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", resp.Code)
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode upload: %v", err)
+	}
+	return created.DocumentID
+}
+
+func getLint(t *testing.T, router http.Handler, docID string) (int, documents.LintReport) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/"+docID+"/lint", nil)
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	var report documents.LintReport
+	if resp.Code == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("decode lint: %v", err)
+		}
+	}
+	return resp.Code, report
+}
+
+func findingCodes(report documents.LintReport) map[string]string {
+	codes := map[string]string{}
+	for _, f := range report.Findings {
+		codes[f.Code] = f.Severity
+	}
+	return codes
+}
+
+func TestDocumentsLint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	router := app.Router
+
+	clean := buildDOCX(t, paragraphs(
+		"Jane Doe",
+		"jane@example.com | +1 (512) 555-0199 | linkedin.com/in/janedoe",
+		"Summary",
+		"Backend engineer.",
+		"Experience",
+		"Engineer at Acme, 2020 - Present",
+		"Education",
+		"BSc Computer Science",
+		"Skills:",
+		"Go, SQL",
+	))
+	code, report := getLint(t, router, uploadRaw(t, router, "clean.docx", clean))
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if !report.Contact.Email || !report.Contact.Phone || !report.Contact.LinkedIn {
+		t.Fatalf("expected all contact details, got %+v", report.Contact)
+	}
+	for _, section := range []string{"summary", "experience", "education", "skills"} {
+		if !report.Sections[section] {
+			t.Fatalf("expected section %s detected, got %+v", section, report.Sections)
+		}
+	}
+	codes := findingCodes(report)
+	if _, ok := codes["tables_detected"]; ok {
+		t.Fatalf("unexpected table finding: %+v", report.Findings)
+	}
+	if codes["too_short"] != documents.LintSeverityWarning || report.EstimatedPages != 1 {
+		t.Fatalf("expected short single-page resume, got pages=%d findings=%+v", report.EstimatedPages, report.Findings)
+	}
+
+	messy := buildDOCX(t, paragraphs("Jane Doe", "Projects")+
+		`<w:tbl><w:tr><w:tc>`+paragraphs("Go")+`</w:tc></w:tr></w:tbl>`+
+		`<w:p><w:r><w:drawing/></w:r></w:p>`)
+	code, report = getLint(t, router, uploadRaw(t, router, "messy.docx", messy))
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	codes = findingCodes(report)
+	for _, want := range []string{"tables_detected", "images_detected", "missing_email", "missing_phone", "missing_section_experience"} {
+		if _, ok := codes[want]; !ok {
+			t.Fatalf("expected finding %s, got %+v", want, report.Findings)
+		}
+	}
+	if codes["missing_email"] != documents.LintSeverityError {
+		t.Fatalf("expected missing email to be an error")
+	}
+
+	// A table past the cap is never inflated, so only the size finding is reported.
+	huge := buildDOCX(t, paragraphs("Jane Doe", strings.Repeat(" ", 9<<20))+`<w:tbl></w:tbl>`)
+	code, report = getLint(t, router, uploadRaw(t, router, "huge.docx", huge))
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	codes = findingCodes(report)
+	if codes["document_too_large"] != documents.LintSeverityWarning {
+		t.Fatalf("expected document_too_large warning, got %+v", report.Findings)
+	}
+	if _, ok := codes["tables_detected"]; ok {
+		t.Fatalf("expected layout checks skipped past the cap, got %+v", report.Findings)
+	}
+
+	if code, _ := getLint(t, router, "missing"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing document, got %d", code)
+	}
+}
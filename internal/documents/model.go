@@ -10,10 +10,43 @@ type Document struct {
 	OriginalFilename string
 	MimeType         string
 	ContentType      string
+	// VerifiedMime is the type detected from the file's bytes at upload; empty when unverified.
+	VerifiedMime     string
 	SizeBytes        int64
 	StorageProvider  string
 	StorageKey       string
 	ExtractedTextKey string
 	ExtractedAt      *time.Time
-	CreatedAt        time.Time
+	// Checksum is the hex SHA-256 of the uploaded bytes; empty for documents registered from S3.
+	Checksum  string
+	CreatedAt time.Time
+}
+
+// Document statuses reported to clients.
+const (
+	// StatusUploaded documents have their text extracted when first analyzed.
+	StatusUploaded = "uploaded"
+	// StatusReady documents have extracted text, so analyses start at once.
+	StatusReady = "ready"
+)
+
+// Status reports whether the document's text has been extracted yet.
+func (d Document) Status() string {
+	if d.ExtractedAt != nil {
+		return StatusReady
+	}
+	return StatusUploaded
+}
+
+// ExtractionMimeType returns the type extraction should trust: the verified type when present,
+// otherwise the recorded or declared one.
+func (d Document) ExtractionMimeType() string {
+	switch {
+	case d.VerifiedMime != "":
+		return d.VerifiedMime
+	case d.MimeType != "":
+		return d.MimeType
+	default:
+		return d.ContentType
+	}
 }
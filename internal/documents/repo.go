@@ -13,3 +13,54 @@ type DocumentsRepo interface {
 	GetByID(ctx context.Context, userId, documentID string) (Document, error)
 	UpdateExtraction(ctx context.Context, userId, documentID, extractedKey string, extractedAt time.Time) error
 }
+
+// ExtractionAuditor is implemented by repos that let extractions be checked
+// against the object store and reset when their text is gone.
+type ExtractionAuditor interface {
+	// ListExtractedSince returns documents extracted at or after since,
+	// newest extraction first.
+	ListExtractedSince(ctx context.Context, since time.Time, limit int) ([]Document, error)
+	// ClearExtraction forgets a document's extracted text so it is extracted
+	// again on next use.
+	ClearExtraction(ctx context.Context, userId, documentID string) error
+}
+
+// ExtractionClaimer is implemented by repos that let one caller at a time
+// extract a document, so concurrent analyses of the same document do not
+// both extract it and overwrite each other's text.
+type ExtractionClaimer interface {
+	// ClaimExtraction claims the document's extraction for claimID until
+	// claimedUntil. It reports false when the document is already extracted
+	// or another claim has not yet lapsed at now.
+	ClaimExtraction(ctx context.Context, userId, documentID, claimID string, now, claimedUntil time.Time) (bool, error)
+	// ReleaseExtraction ends claimID's claim, if it still holds one.
+	ReleaseExtraction(ctx context.Context, userId, documentID, claimID string) error
+}
+
+var (
+	_ ExtractionClaimer = (*MemoryRepo)(nil)
+	_ ExtractionClaimer = (*PGRepo)(nil)
+	_ ExtractionClaimer = (*WorkerRepo)(nil)
+)
+
+// Archiver is implemented by repos that can hide documents from a user's
+// history without deleting them. Archived documents are left out of
+// ListByUser and GetCurrentByUser; GetByID still returns them.
+type Archiver interface {
+	// SetArchived archives a document at archivedAt, or restores it when
+	// archivedAt is nil. It returns ErrNotFound for a missing document.
+	SetArchived(ctx context.Context, userId, documentID string, archivedAt *time.Time) error
+	// ListArchivedByUser lists archived documents, most recently archived first.
+	ListArchivedByUser(ctx context.Context, userId string, limit, offset int) ([]Document, error)
+}
+
+var (
+	_ Archiver = (*MemoryRepo)(nil)
+	_ Archiver = (*PGRepo)(nil)
+)
+
+var (
+	_ ExtractionAuditor = (*MemoryRepo)(nil)
+	_ ExtractionAuditor = (*PGRepo)(nil)
+	_ ExtractionAuditor = (*WorkerRepo)(nil)
+)
@@ -3,20 +3,38 @@ package documents
 import (
 	"context"
 	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 // MemoryRepo is an in-memory implementation of DocumentsRepo.
 type MemoryRepo struct {
-	mu   sync.RWMutex
-	data map[string][]Document // userId -> documents
+	mu          sync.RWMutex
+	data        map[string][]Document      // userId -> documents
+	signatures  map[string]string          // documentId -> text signature
+	unreachable map[string]time.Time       // documentId -> when its upload was found missing
+	archived    map[string]time.Time       // documentId -> when it was archived
+	bulkJobs    map[string]BulkJob         // jobId -> recorded bulk job
+	claims      map[string]extractionClaim // documentId -> live extraction claim
+}
+
+type extractionClaim struct {
+	id    string
+	until time.Time
 }
 
 // NewMemoryRepo constructs a MemoryRepo.
 func NewMemoryRepo() *MemoryRepo {
 	return &MemoryRepo{
-		data: make(map[string][]Document),
+		data:        make(map[string][]Document),
+		signatures:  make(map[string]string),
+		unreachable: make(map[string]time.Time),
+		archived:    make(map[string]time.Time),
+		bulkJobs:    make(map[string]BulkJob),
+		claims:      make(map[string]extractionClaim),
 	}
 }
 
@@ -38,11 +56,13 @@ func (r *MemoryRepo) GetCurrentByUser(ctx context.Context, userId string) (Docum
 	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	docs, ok := r.data[userId]
-	if !ok || len(docs) == 0 {
-		return Document{}, ErrNotFound
+	docs := r.data[userId]
+	for i := len(docs) - 1; i >= 0; i-- {
+		if _, archived := r.archived[docs[i].ID]; !archived {
+			return docs[i], nil
+		}
 	}
-	return docs[len(docs)-1], nil
+	return Document{}, ErrNotFound
 }
 
 // GetByID returns a document by ID for a user.
@@ -96,16 +116,19 @@ func (r *MemoryRepo) ListByUser(ctx context.Context, userId string, limit, offse
 	}
 
 	r.mu.RLock()
-	userDocs := r.data[userId]
+	docs := make([]Document, 0, len(r.data[userId]))
+	for _, doc := range r.data[userId] {
+		if _, archived := r.archived[doc.ID]; !archived {
+			docs = append(docs, doc)
+		}
+	}
 	r.mu.RUnlock()
 
-	if len(userDocs) == 0 || offset >= len(userDocs) {
+	if len(docs) == 0 || offset >= len(docs) {
 		return []Document{}, nil
 	}
 
-	// Copy and sort newest-first by CreatedAt.
-	docs := make([]Document, len(userDocs))
-	copy(docs, userDocs)
+	// Sort newest-first by CreatedAt.
 	sort.Slice(docs, func(i, j int) bool {
 		return docs[i].CreatedAt.After(docs[j].CreatedAt)
 	})
@@ -130,10 +153,388 @@ func (r *MemoryRepo) ClaimGuest(ctx context.Context, guestUserID, authedUserID s
 	if len(guestDocs) == 0 {
 		return 0, nil
 	}
+	claimed := make(map[string]bool, len(guestDocs))
 	for i := range guestDocs {
 		guestDocs[i].UserID = authedUserID
+		claimed[guestDocs[i].ID] = true
 	}
 	r.data[authedUserID] = append(r.data[authedUserID], guestDocs...)
 	delete(r.data, guestUserID)
+	db.OnRollback(ctx, func() { r.moveDocuments(authedUserID, guestUserID, claimed) })
 	return len(guestDocs), nil
 }
+
+// moveDocuments gives the documents with the given IDs from one user to another.
+func (r *MemoryRepo) moveDocuments(from, to string, ids map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kept []Document
+	for _, doc := range r.data[from] {
+		if !ids[doc.ID] {
+			kept = append(kept, doc)
+			continue
+		}
+		doc.UserID = to
+		r.data[to] = append(r.data[to], doc)
+	}
+	if len(kept) == 0 {
+		delete(r.data, from)
+	} else {
+		r.data[from] = kept
+	}
+}
+
+// ListExpiredGuest returns guest-owned documents created before cutoff, oldest first.
+func (r *MemoryRepo) ListExpiredGuest(ctx context.Context, cutoff time.Time, limit int) ([]Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	var out []Document
+	for userID, docs := range r.data {
+		if !strings.HasPrefix(userID, "guest:") {
+			continue
+		}
+		for _, doc := range docs {
+			if doc.CreatedAt.Before(cutoff) {
+				out = append(out, doc)
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// SoftDelete removes a document from the store.
+func (r *MemoryRepo) SoftDelete(ctx context.Context, userId, documentID string, deletedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	docs := r.data[userId]
+	for i := range docs {
+		if docs[i].ID == documentID {
+			doc := docs[i]
+			archivedAt, archived := r.archived[documentID]
+			r.data[userId] = append(docs[:i:i], docs[i+1:]...)
+			delete(r.archived, documentID)
+			db.OnRollback(ctx, func() {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				r.data[userId] = append(r.data[userId], doc)
+				if archived {
+					r.archived[documentID] = archivedAt
+				}
+			})
+			return nil
+		}
+	}
+	return nil
+}
+
+// SetArchived archives a document at archivedAt, or restores it when
+// archivedAt is nil.
+func (r *MemoryRepo) SetArchived(ctx context.Context, userId, documentID string, archivedAt *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, doc := range r.data[userId] {
+		if doc.ID != documentID {
+			continue
+		}
+		if archivedAt == nil {
+			delete(r.archived, documentID)
+		} else {
+			r.archived[documentID] = *archivedAt
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+// ListArchivedByUser lists archived documents, most recently archived first.
+func (r *MemoryRepo) ListArchivedByUser(ctx context.Context, userId string, limit, offset int) ([]Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	var docs []Document
+	for _, doc := range r.data[userId] {
+		if _, archived := r.archived[doc.ID]; archived {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return r.archived[docs[i].ID].After(r.archived[docs[j].ID])
+	})
+	r.mu.RUnlock()
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(docs) {
+		return []Document{}, nil
+	}
+	end := len(docs)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return docs[offset:end], nil
+}
+
+// FindByChecksum returns the user's newest document with the given checksum.
+func (r *MemoryRepo) FindByChecksum(ctx context.Context, userId, checksum string) (Document, error) {
+	if err := ctx.Err(); err != nil {
+		return Document{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var found *Document
+	for i, doc := range r.data[userId] {
+		if checksum != "" && doc.Checksum == checksum && (found == nil || doc.CreatedAt.After(found.CreatedAt)) {
+			found = &r.data[userId][i]
+		}
+	}
+	if found == nil {
+		return Document{}, ErrNotFound
+	}
+	return *found, nil
+}
+
+// ListFingerprints returns fingerprints for the user's newest documents.
+func (r *MemoryRepo) ListFingerprints(ctx context.Context, userId string, limit int) ([]Fingerprint, error) {
+	docs, err := r.ListByUser(ctx, userId, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Fingerprint, 0, len(docs))
+	for _, doc := range docs {
+		out = append(out, Fingerprint{
+			DocumentID:       doc.ID,
+			Checksum:         doc.Checksum,
+			TextSignature:    r.signatures[doc.ID],
+			ExtractedTextKey: doc.ExtractedTextKey,
+		})
+	}
+	return out, nil
+}
+
+// SetTextSignature stores the MinHash signature of a document's extracted text.
+func (r *MemoryRepo) SetTextSignature(ctx context.Context, userId, documentID, signature string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, doc := range r.data[userId] {
+		if doc.ID == documentID {
+			r.signatures[documentID] = signature
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+var _ duplicateIndex = (*MemoryRepo)(nil)
+
+// ListLegacyStorage returns documents not yet moved to S3 and not flagged as
+// unreachable, ordered by id, starting after afterID.
+func (r *MemoryRepo) ListLegacyStorage(ctx context.Context, afterID string, limit int) ([]Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	var out []Document
+	for _, docs := range r.data {
+		for _, doc := range docs {
+			if doc.StorageProvider == "s3" || doc.ID <= afterID {
+				continue
+			}
+			if _, flagged := r.unreachable[doc.ID]; flagged {
+				continue
+			}
+			out = append(out, doc)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// MoveStorage points a document at its copy in another store. An empty
+// extractedKey clears the extraction so the text is extracted again.
+func (r *MemoryRepo) MoveStorage(ctx context.Context, userId, documentID, provider, storageKey, extractedKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	docs := r.data[userId]
+	for i := range docs {
+		if docs[i].ID == documentID {
+			docs[i].StorageProvider = provider
+			docs[i].StorageKey = storageKey
+			docs[i].ExtractedTextKey = extractedKey
+			if extractedKey == "" {
+				docs[i].ExtractedAt = nil
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// MarkStorageUnreachable flags a document whose stored upload could not be found.
+func (r *MemoryRepo) MarkStorageUnreachable(ctx context.Context, userId, documentID string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, doc := range r.data[userId] {
+		if doc.ID == documentID {
+			r.unreachable[documentID] = at
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// ListExtractedSince returns documents extracted at or after since, newest extraction first.
+func (r *MemoryRepo) ListExtractedSince(ctx context.Context, since time.Time, limit int) ([]Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	var out []Document
+	for _, docs := range r.data {
+		for _, doc := range docs {
+			if doc.ExtractedTextKey != "" && doc.ExtractedAt != nil && !doc.ExtractedAt.Before(since) {
+				out = append(out, doc)
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ExtractedAt.After(*out[j].ExtractedAt)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// ClearExtraction forgets a document's extracted text so it is extracted again on next use.
+func (r *MemoryRepo) ClearExtraction(ctx context.Context, userId, documentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	docs := r.data[userId]
+	for i := range docs {
+		if docs[i].ID == documentID {
+			docs[i].ExtractedTextKey = ""
+			docs[i].ExtractedAt = nil
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// ClaimExtraction claims a document's extraction unless it is extracted or
+// claimed by someone else until after now.
+func (r *MemoryRepo) ClaimExtraction(ctx context.Context, userId, documentID, claimID string, now, claimedUntil time.Time) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, doc := range r.data[userId] {
+		if doc.ID != documentID {
+			continue
+		}
+		if doc.ExtractedTextKey != "" {
+			return false, nil
+		}
+		if claim, ok := r.claims[documentID]; ok && claim.until.After(now) {
+			return false, nil
+		}
+		r.claims[documentID] = extractionClaim{id: claimID, until: claimedUntil}
+		return true, nil
+	}
+	return false, ErrNotFound
+}
+
+// ReleaseExtraction ends claimID's extraction claim.
+func (r *MemoryRepo) ReleaseExtraction(ctx context.Context, userId, documentID, claimID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if claim, ok := r.claims[documentID]; ok && claim.id == claimID {
+		delete(r.claims, documentID)
+	}
+	return nil
+}
+
+// CreateBulkJob records a bulk job.
+func (r *MemoryRepo) CreateBulkJob(ctx context.Context, job BulkJob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.Results = append([]BulkResult(nil), job.Results...)
+	r.bulkJobs[job.ID] = job
+	return nil
+}
+
+// GetBulkJob returns a user's bulk job.
+func (r *MemoryRepo) GetBulkJob(ctx context.Context, userId, jobID string) (BulkJob, error) {
+	if err := ctx.Err(); err != nil {
+		return BulkJob{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, ok := r.bulkJobs[jobID]
+	if !ok || job.UserID != userId {
+		return BulkJob{}, ErrNotFound
+	}
+	job.Results = append([]BulkResult(nil), job.Results...)
+	return job, nil
+}
+
+// SaveBulkResult records the result of the document at index.
+func (r *MemoryRepo) SaveBulkResult(ctx context.Context, jobID string, index int, result BulkResult, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.bulkJobs[jobID]
+	if !ok || index < 0 || index >= len(job.Results) {
+		return ErrNotFound
+	}
+	job.Results[index] = result
+	job.UpdatedAt = at
+	r.bulkJobs[jobID] = job
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"time"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 // PGRepo implements DocumentsRepo using Postgres.
@@ -26,8 +28,9 @@ INSERT INTO documents (
     storage_provider,
     storage_key,
     checksum,
-    created_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULL, $10)`
+    created_at,
+    verified_mime
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
 	originalName := doc.OriginalFilename
 	if originalName == "" {
@@ -47,6 +50,16 @@ INSERT INTO documents (
 		storageKey = sql.NullString{String: doc.StorageKey, Valid: true}
 	}
 
+	var verifiedMime sql.NullString
+	if doc.VerifiedMime != "" {
+		verifiedMime = sql.NullString{String: doc.VerifiedMime, Valid: true}
+	}
+
+	var checksum sql.NullString
+	if doc.Checksum != "" {
+		checksum = sql.NullString{String: doc.Checksum, Valid: true}
+	}
+
 	_, err := r.DB.ExecContext(
 		ctx,
 		query,
@@ -59,7 +72,9 @@ INSERT INTO documents (
 		doc.SizeBytes,
 		storageProvider,
 		storageKey,
+		checksum,
 		doc.CreatedAt,
+		verifiedMime,
 	)
 	return err
 }
@@ -67,9 +82,9 @@ INSERT INTO documents (
 // GetCurrentByUser returns the latest document for a user.
 func (r *PGRepo) GetCurrentByUser(ctx context.Context, userId string) (Document, error) {
 	const query = `
-SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
 FROM documents
-WHERE user_id = $1 AND deleted_at IS NULL
+WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL
 ORDER BY created_at DESC
 LIMIT 1`
 	var doc Document
@@ -79,6 +94,7 @@ LIMIT 1`
 	var storageKey sql.NullString
 	var extractedKey sql.NullString
 	var extractedAt sql.NullTime
+	var verifiedMime sql.NullString
 	err := r.DB.QueryRowContext(ctx, query, userId).Scan(
 		&doc.ID,
 		&doc.UserID,
@@ -92,6 +108,7 @@ LIMIT 1`
 		&extractedKey,
 		&extractedAt,
 		&doc.CreatedAt,
+		&verifiedMime,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -114,6 +131,7 @@ LIMIT 1`
 	if extractedKey.Valid {
 		doc.ExtractedTextKey = extractedKey.String
 	}
+	doc.VerifiedMime = verifiedMime.String
 	if extractedAt.Valid {
 		doc.ExtractedAt = &extractedAt.Time
 	}
@@ -123,7 +141,7 @@ LIMIT 1`
 // GetByID fetches a document by ID for a user.
 func (r *PGRepo) GetByID(ctx context.Context, userId, documentID string) (Document, error) {
 	const query = `
-SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
 FROM documents
 WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL
 LIMIT 1`
@@ -134,6 +152,7 @@ LIMIT 1`
 	var storageKey sql.NullString
 	var extractedKey sql.NullString
 	var extractedAt sql.NullTime
+	var verifiedMime sql.NullString
 	err := r.DB.QueryRowContext(ctx, query, userId, documentID).Scan(
 		&doc.ID,
 		&doc.UserID,
@@ -147,6 +166,7 @@ LIMIT 1`
 		&extractedKey,
 		&extractedAt,
 		&doc.CreatedAt,
+		&verifiedMime,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -169,6 +189,7 @@ LIMIT 1`
 	if extractedKey.Valid {
 		doc.ExtractedTextKey = extractedKey.String
 	}
+	doc.VerifiedMime = verifiedMime.String
 	if extractedAt.Valid {
 		doc.ExtractedAt = &extractedAt.Time
 	}
@@ -187,9 +208,9 @@ func (r *PGRepo) ListByUser(ctx context.Context, userId string, limit, offset in
 		offset = 0
 	}
 	const query = `
-SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
 FROM documents
-WHERE user_id = $1 AND deleted_at IS NULL
+WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3`
 
@@ -208,6 +229,7 @@ LIMIT $2 OFFSET $3`
 		var storageKey sql.NullString
 		var extractedKey sql.NullString
 		var extractedAt sql.NullTime
+		var verifiedMime sql.NullString
 		if err := rows.Scan(
 			&doc.ID,
 			&doc.UserID,
@@ -221,6 +243,7 @@ LIMIT $2 OFFSET $3`
 			&extractedKey,
 			&extractedAt,
 			&doc.CreatedAt,
+			&verifiedMime,
 		); err != nil {
 			return nil, err
 		}
@@ -239,6 +262,7 @@ LIMIT $2 OFFSET $3`
 		if extractedKey.Valid {
 			doc.ExtractedTextKey = extractedKey.String
 		}
+		doc.VerifiedMime = verifiedMime.String
 		if extractedAt.Valid {
 			doc.ExtractedAt = &extractedAt.Time
 		}
@@ -263,7 +287,7 @@ func (r *PGRepo) ClaimGuest(ctx context.Context, guestUserID, authedUserID strin
 UPDATE documents
 SET user_id = $1
 WHERE user_id = $2 AND deleted_at IS NULL`
-	res, err := r.DB.ExecContext(ctx, query, authedUserID, guestUserID)
+	res, err := db.ConnFor(ctx, r.DB).ExecContext(ctx, query, authedUserID, guestUserID)
 	if err != nil {
 		return 0, err
 	}
@@ -271,4 +295,422 @@ WHERE user_id = $2 AND deleted_at IS NULL`
 	return int(updated), nil
 }
 
-var _ DocumentsRepo = (*PGRepo)(nil)
+// ListExpiredGuest returns guest-owned documents created before cutoff, oldest first.
+func (r *PGRepo) ListExpiredGuest(ctx context.Context, cutoff time.Time, limit int) ([]Document, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	const query = `
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
+FROM documents
+WHERE user_id LIKE 'guest:%' AND created_at < $1 AND deleted_at IS NULL
+ORDER BY created_at ASC
+LIMIT $2`
+
+	rows, err := r.DB.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDocumentRows(rows)
+}
+
+// scanDocumentRows reads documents selected with the column list of
+// ListExpiredGuest and closes rows.
+func scanDocumentRows(rows *sql.Rows) ([]Document, error) {
+	defer rows.Close()
+
+	var out []Document
+	for rows.Next() {
+		var doc Document
+		var originalName sql.NullString
+		var contentType sql.NullString
+		var storageProvider sql.NullString
+		var storageKey sql.NullString
+		var extractedKey sql.NullString
+		var extractedAt sql.NullTime
+		var verifiedMime sql.NullString
+		if err := rows.Scan(
+			&doc.ID,
+			&doc.UserID,
+			&doc.FileName,
+			&originalName,
+			&doc.MimeType,
+			&contentType,
+			&doc.SizeBytes,
+			&storageProvider,
+			&storageKey,
+			&extractedKey,
+			&extractedAt,
+			&doc.CreatedAt,
+			&verifiedMime,
+		); err != nil {
+			return nil, err
+		}
+		doc.OriginalFilename = originalName.String
+		doc.ContentType = contentType.String
+		doc.StorageProvider = storageProvider.String
+		doc.StorageKey = storageKey.String
+		doc.ExtractedTextKey = extractedKey.String
+		doc.VerifiedMime = verifiedMime.String
+		if extractedAt.Valid {
+			doc.ExtractedAt = &extractedAt.Time
+		}
+		out = append(out, doc)
+	}
+	return out, rows.Err()
+}
+
+// SoftDelete marks a document as deleted.
+func (r *PGRepo) SoftDelete(ctx context.Context, userId, documentID string, deletedAt time.Time) error {
+	const query = `
+UPDATE documents
+SET deleted_at = $1
+WHERE user_id = $2 AND id = $3 AND deleted_at IS NULL`
+	_, err := db.ConnFor(ctx, r.DB).ExecContext(ctx, query, deletedAt, userId, documentID)
+	return err
+}
+
+// SetArchived archives a document at archivedAt, or restores it when
+// archivedAt is nil.
+func (r *PGRepo) SetArchived(ctx context.Context, userId, documentID string, archivedAt *time.Time) error {
+	const query = `
+UPDATE documents
+SET archived_at = $1
+WHERE user_id = $2 AND id = $3 AND deleted_at IS NULL`
+	var at sql.NullTime
+	if archivedAt != nil {
+		at = sql.NullTime{Time: *archivedAt, Valid: true}
+	}
+	res, err := r.DB.ExecContext(ctx, query, at, userId, documentID)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListArchivedByUser lists archived documents, most recently archived first.
+func (r *PGRepo) ListArchivedByUser(ctx context.Context, userId string, limit, offset int) ([]Document, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	const query = `
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
+FROM documents
+WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NOT NULL
+ORDER BY archived_at DESC
+LIMIT $2 OFFSET $3`
+	rows, err := r.DB.QueryContext(ctx, query, userId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanDocumentRows(rows)
+}
+
+// FindByChecksum returns the user's newest document with the given checksum.
+func (r *PGRepo) FindByChecksum(ctx context.Context, userId, checksum string) (Document, error) {
+	const query = `
+SELECT id
+FROM documents
+WHERE user_id = $1 AND checksum = $2 AND deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT 1`
+	var id string
+	if err := r.DB.QueryRowContext(ctx, query, userId, checksum).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Document{}, ErrNotFound
+		}
+		return Document{}, err
+	}
+	return r.GetByID(ctx, userId, id)
+}
+
+// ListFingerprints returns fingerprints for the user's newest documents.
+func (r *PGRepo) ListFingerprints(ctx context.Context, userId string, limit int) ([]Fingerprint, error) {
+	const query = `
+SELECT id, checksum, text_signature, extracted_text_key
+FROM documents
+WHERE user_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Fingerprint
+	for rows.Next() {
+		var fp Fingerprint
+		var checksum, signature, extractedKey sql.NullString
+		if err := rows.Scan(&fp.DocumentID, &checksum, &signature, &extractedKey); err != nil {
+			return nil, err
+		}
+		fp.Checksum = checksum.String
+		fp.TextSignature = signature.String
+		fp.ExtractedTextKey = extractedKey.String
+		out = append(out, fp)
+	}
+	return out, rows.Err()
+}
+
+// SetTextSignature stores the MinHash signature of a document's extracted text.
+func (r *PGRepo) SetTextSignature(ctx context.Context, userId, documentID, signature string) error {
+	const query = `
+UPDATE documents
+SET text_signature = $1
+WHERE user_id = $2 AND id = $3`
+	_, err := r.DB.ExecContext(ctx, query, signature, userId, documentID)
+	return err
+}
+
+var (
+	_ DocumentsRepo  = (*PGRepo)(nil)
+	_ duplicateIndex = (*PGRepo)(nil)
+)
+
+// ListLegacyStorage returns documents not yet moved to S3 and not flagged as
+// unreachable, ordered by id, starting after afterID.
+func (r *PGRepo) ListLegacyStorage(ctx context.Context, afterID string, limit int) ([]Document, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	const query = `
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
+FROM documents
+WHERE (storage_provider IS NULL OR storage_provider <> 's3')
+  AND storage_unreachable_at IS NULL
+  AND deleted_at IS NULL
+  AND id > COALESCE(NULLIF($1, ''), '00000000-0000-0000-0000-000000000000')::uuid
+ORDER BY id ASC
+LIMIT $2`
+
+	rows, err := r.DB.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Document
+	for rows.Next() {
+		var doc Document
+		var originalName sql.NullString
+		var contentType sql.NullString
+		var storageProvider sql.NullString
+		var storageKey sql.NullString
+		var extractedKey sql.NullString
+		var extractedAt sql.NullTime
+		var verifiedMime sql.NullString
+		if err := rows.Scan(
+			&doc.ID,
+			&doc.UserID,
+			&doc.FileName,
+			&originalName,
+			&doc.MimeType,
+			&contentType,
+			&doc.SizeBytes,
+			&storageProvider,
+			&storageKey,
+			&extractedKey,
+			&extractedAt,
+			&doc.CreatedAt,
+			&verifiedMime,
+		); err != nil {
+			return nil, err
+		}
+		doc.OriginalFilename = originalName.String
+		doc.ContentType = contentType.String
+		doc.StorageProvider = storageProvider.String
+		doc.StorageKey = storageKey.String
+		doc.ExtractedTextKey = extractedKey.String
+		doc.VerifiedMime = verifiedMime.String
+		if extractedAt.Valid {
+			doc.ExtractedAt = &extractedAt.Time
+		}
+		out = append(out, doc)
+	}
+	return out, rows.Err()
+}
+
+// MoveStorage points a document at its copy in another store. An empty
+// extractedKey clears the extraction so the text is extracted again.
+func (r *PGRepo) MoveStorage(ctx context.Context, userId, documentID, provider, storageKey, extractedKey string) error {
+	const query = `
+UPDATE documents
+SET storage_provider = $1,
+    storage_key = $2,
+    extracted_text_key = NULLIF($3, ''),
+    extracted_at = CASE WHEN $3 = '' THEN NULL ELSE extracted_at END
+WHERE user_id = $4 AND id = $5 AND deleted_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, query, provider, storageKey, extractedKey, userId, documentID)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkStorageUnreachable flags a document whose stored upload could not be found.
+func (r *PGRepo) MarkStorageUnreachable(ctx context.Context, userId, documentID string, at time.Time) error {
+	const query = `
+UPDATE documents
+SET storage_unreachable_at = $1
+WHERE user_id = $2 AND id = $3 AND deleted_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, query, at, userId, documentID)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListExtractedSince returns documents extracted at or after since, newest
+// extraction first.
+func (r *PGRepo) ListExtractedSince(ctx context.Context, since time.Time, limit int) ([]Document, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	const query = `
+SELECT id, user_id, file_name, original_filename, mime_type, content_type, size_bytes, storage_provider, storage_key, extracted_text_key, extracted_at, created_at, verified_mime
+FROM documents
+WHERE extracted_text_key IS NOT NULL AND extracted_at >= $1 AND deleted_at IS NULL
+ORDER BY extracted_at DESC
+LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDocumentRows(rows)
+}
+
+// ClearExtraction forgets a document's extracted text so it is extracted again
+// on next use.
+func (r *PGRepo) ClearExtraction(ctx context.Context, userId, documentID string) error {
+	const query = `
+UPDATE documents
+SET extracted_text_key = NULL, extracted_at = NULL
+WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, query, userId, documentID)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ClaimExtraction claims a document's extraction unless it is extracted or
+// claimed by someone else until after now. The conditional update is atomic,
+// so of several concurrent callers exactly one wins.
+func (r *PGRepo) ClaimExtraction(ctx context.Context, userId, documentID, claimID string, now, claimedUntil time.Time) (bool, error) {
+	const query = `
+UPDATE documents
+SET extraction_claim_id = $3, extraction_claimed_until = $4
+WHERE user_id = $1 AND id = $2 AND extracted_text_key IS NULL
+  AND (extraction_claimed_until IS NULL OR extraction_claimed_until <= $5)`
+	res, err := r.DB.ExecContext(ctx, query, userId, documentID, claimID, claimedUntil, now)
+	if err != nil {
+		return false, err
+	}
+	claimed, _ := res.RowsAffected()
+	return claimed == 1, nil
+}
+
+// ReleaseExtraction ends claimID's extraction claim.
+func (r *PGRepo) ReleaseExtraction(ctx context.Context, userId, documentID, claimID string) error {
+	const query = `
+UPDATE documents
+SET extraction_claim_id = NULL, extraction_claimed_until = NULL
+WHERE user_id = $1 AND id = $2 AND extraction_claim_id = $3`
+	_, err := r.DB.ExecContext(ctx, query, userId, documentID, claimID)
+	return err
+}
+
+// CreateBulkJob records a bulk job and its results in one transaction.
+func (r *PGRepo) CreateBulkJob(ctx context.Context, job BulkJob) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = tx.ExecContext(ctx, `
+INSERT INTO document_bulk_jobs (id, user_id, kind, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)`, job.ID, job.UserID, job.Kind, job.CreatedAt, job.UpdatedAt); err != nil {
+		return err
+	}
+	for i, result := range job.Results {
+		if _, err = tx.ExecContext(ctx, `
+INSERT INTO document_bulk_job_items (job_id, position, document_id, status, analyses_deleted, error, attempts)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			job.ID, i, result.DocumentID, result.Status, result.AnalysesDeleted, result.Error, result.Attempts); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetBulkJob returns a user's bulk job with its results in request order.
+func (r *PGRepo) GetBulkJob(ctx context.Context, userId, jobID string) (BulkJob, error) {
+	job := BulkJob{ID: jobID, UserID: userId}
+	err := r.DB.QueryRowContext(ctx, `
+SELECT kind, created_at, updated_at
+FROM document_bulk_jobs
+WHERE id = $1 AND user_id = $2`, jobID, userId).Scan(&job.Kind, &job.CreatedAt, &job.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return BulkJob{}, ErrNotFound
+	}
+	if err != nil {
+		return BulkJob{}, err
+	}
+	rows, err := r.DB.QueryContext(ctx, `
+SELECT document_id, status, analyses_deleted, error, attempts
+FROM document_bulk_job_items
+WHERE job_id = $1
+ORDER BY position`, jobID)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var result BulkResult
+		if err := rows.Scan(&result.DocumentID, &result.Status, &result.AnalysesDeleted, &result.Error, &result.Attempts); err != nil {
+			return BulkJob{}, err
+		}
+		job.Results = append(job.Results, result)
+	}
+	return job, rows.Err()
+}
+
+// SaveBulkResult records the result of the document at index.
+func (r *PGRepo) SaveBulkResult(ctx context.Context, jobID string, index int, result BulkResult, at time.Time) error {
+	res, err := r.DB.ExecContext(ctx, `
+UPDATE document_bulk_job_items
+SET status = $1, analyses_deleted = $2, error = $3, attempts = $4
+WHERE job_id = $5 AND position = $6`,
+		result.Status, result.AnalysesDeleted, result.Error, result.Attempts, jobID, index)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrNotFound
+	}
+	_, err = r.DB.ExecContext(ctx, `UPDATE document_bulk_jobs SET updated_at = $1 WHERE id = $2`, at, jobID)
+	return err
+}
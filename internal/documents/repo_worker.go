@@ -0,0 +1,105 @@
+package documents
+
+import (
+	"context"
+	"time"
+)
+
+// WorkerRepo restricts a DocumentsRepo to what the worker needs: reading a
+// document, recording its extraction, registering uploads S3 reported and
+// purging expired guest uploads.
+type WorkerRepo struct {
+	repo DocumentsRepo
+}
+
+var _ DocumentsRepo = (*WorkerRepo)(nil)
+
+// NewWorkerRepo wraps repo for the worker role.
+func NewWorkerRepo(repo DocumentsRepo) *WorkerRepo {
+	return &WorkerRepo{repo: repo}
+}
+
+// Create only registers documents uploaded straight to S3, for the upload event
+// consumer. Every other document is created by the API.
+func (r *WorkerRepo) Create(ctx context.Context, doc Document) error {
+	if doc.StorageProvider != "s3" {
+		return ErrNotPermitted
+	}
+	return r.repo.Create(ctx, doc)
+}
+
+func (r *WorkerRepo) GetCurrentByUser(ctx context.Context, userId string) (Document, error) {
+	return Document{}, ErrNotPermitted
+}
+
+func (r *WorkerRepo) ListByUser(ctx context.Context, userId string, limit, offset int) ([]Document, error) {
+	return nil, ErrNotPermitted
+}
+
+func (r *WorkerRepo) GetByID(ctx context.Context, userId, documentID string) (Document, error) {
+	return r.repo.GetByID(ctx, userId, documentID)
+}
+
+func (r *WorkerRepo) UpdateExtraction(ctx context.Context, userId, documentID, extractedKey string, extractedAt time.Time) error {
+	return r.repo.UpdateExtraction(ctx, userId, documentID, extractedKey, extractedAt)
+}
+
+type guestPurger interface {
+	ListExpiredGuest(ctx context.Context, cutoff time.Time, limit int) ([]Document, error)
+	SoftDelete(ctx context.Context, userId, documentID string, deletedAt time.Time) error
+}
+
+// ListExpiredGuest lets guest retention find expired uploads.
+func (r *WorkerRepo) ListExpiredGuest(ctx context.Context, cutoff time.Time, limit int) ([]Document, error) {
+	purger, ok := r.repo.(guestPurger)
+	if !ok {
+		return nil, ErrNotPermitted
+	}
+	return purger.ListExpiredGuest(ctx, cutoff, limit)
+}
+
+// SoftDelete lets guest retention delete an expired upload.
+func (r *WorkerRepo) SoftDelete(ctx context.Context, userId, documentID string, deletedAt time.Time) error {
+	purger, ok := r.repo.(guestPurger)
+	if !ok {
+		return ErrNotPermitted
+	}
+	return purger.SoftDelete(ctx, userId, documentID, deletedAt)
+}
+
+// ListExtractedSince lets the stuck-state detector check recent extractions.
+func (r *WorkerRepo) ListExtractedSince(ctx context.Context, since time.Time, limit int) ([]Document, error) {
+	auditor, ok := r.repo.(ExtractionAuditor)
+	if !ok {
+		return nil, ErrNotPermitted
+	}
+	return auditor.ListExtractedSince(ctx, since, limit)
+}
+
+// ClearExtraction lets the stuck-state detector reset a document whose
+// extracted text is gone.
+func (r *WorkerRepo) ClearExtraction(ctx context.Context, userId, documentID string) error {
+	auditor, ok := r.repo.(ExtractionAuditor)
+	if !ok {
+		return ErrNotPermitted
+	}
+	return auditor.ClearExtraction(ctx, userId, documentID)
+}
+
+// ClaimExtraction lets a worker take a document's extraction.
+func (r *WorkerRepo) ClaimExtraction(ctx context.Context, userId, documentID, claimID string, now, claimedUntil time.Time) (bool, error) {
+	claimer, ok := r.repo.(ExtractionClaimer)
+	if !ok {
+		return true, nil
+	}
+	return claimer.ClaimExtraction(ctx, userId, documentID, claimID, now, claimedUntil)
+}
+
+// ReleaseExtraction ends a worker's extraction claim.
+func (r *WorkerRepo) ReleaseExtraction(ctx context.Context, userId, documentID, claimID string) error {
+	claimer, ok := r.repo.(ExtractionClaimer)
+	if !ok {
+		return nil
+	}
+	return claimer.ReleaseExtraction(ctx, userId, documentID, claimID)
+}
@@ -0,0 +1,58 @@
+package documents_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/config"
+)
+
+func TestGuestDocumentResponsesAnnounceExpiry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+		GuestRetention:  14 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	uploadDOCX(t, app.Router, "Jane Doe\nEngineer at Acme")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/current", nil)
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	app.Router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("X-Guest-Retention-Days"); got != "14" {
+		t.Fatalf("expected retention days header, got %q", got)
+	}
+	if resp.Header().Get("X-Guest-Expires-At") == "" {
+		t.Fatalf("expected expiry header")
+	}
+
+	var out struct {
+		UploadedAt      time.Time  `json:"uploadedAt"`
+		ExpiresAt       *time.Time `json:"expiresAt"`
+		RetentionNotice string     `json:"retentionNotice"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if out.ExpiresAt == nil || !out.ExpiresAt.Equal(out.UploadedAt.Add(14*24*time.Hour)) {
+		t.Fatalf("expected expiresAt 14 days after upload, got %+v", out)
+	}
+	if out.RetentionNotice == "" {
+		t.Fatalf("expected retention notice for guest")
+	}
+}
@@ -0,0 +1,110 @@
+package documents
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxS3UploadBytes caps files registered from S3 upload events. Presigned PUTs do
+// not sign the length, so the size the client declared is checked again here.
+const MaxS3UploadBytes int64 = 5 << 20
+
+// extractedTextSuffix is appended to a storage key to store its extracted text.
+const extractedTextSuffix = ".extracted.txt"
+
+// S3Upload is an object created in the uploads bucket, as reported by an S3
+// event notification.
+type S3Upload struct {
+	Bucket string
+	Key    string
+	Size   int64
+}
+
+// UploadKey is what a presigned upload key encodes:
+// <prefix><userID>/<documentID>/<fileID>-<fileName>.
+type UploadKey struct {
+	UserID     string
+	DocumentID string
+	FileName   string
+}
+
+type s3Event struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key  string `json:"key"`
+				Size int64  `json:"size"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// ParseS3Event decodes an S3 event notification delivered through SQS and
+// returns its object-created records. The s3:TestEvent S3 sends when the
+// notification is configured, and any other event type, yield no uploads.
+func ParseS3Event(body []byte) ([]S3Upload, error) {
+	var event s3Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	var out []S3Upload
+	for _, record := range event.Records {
+		if !strings.HasPrefix(record.EventName, "ObjectCreated:") {
+			continue
+		}
+		// Keys arrive form-encoded: spaces as '+', other characters escaped.
+		key, err := url.QueryUnescape(record.S3.Object.Key)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, S3Upload{Bucket: record.S3.Bucket.Name, Key: key, Size: record.S3.Object.Size})
+	}
+	return out, nil
+}
+
+// ParseUploadKey splits a key made by the presign endpoint. It reports false for
+// keys outside prefix or laid out differently, which were not uploaded that way.
+func ParseUploadKey(prefix, key string) (UploadKey, bool) {
+	rest, ok := strings.CutPrefix(key, prefix)
+	if !ok {
+		return UploadKey{}, false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 {
+		return UploadKey{}, false
+	}
+	userID, documentID, base := parts[0], parts[1], parts[2]
+	// File IDs are UUIDs, so the name starts after the first 37 characters.
+	if len(base) <= 37 || base[36] != '-' || uuid.Validate(base[:36]) != nil {
+		return UploadKey{}, false
+	}
+	if userID == "" || uuid.Validate(documentID) != nil {
+		return UploadKey{}, false
+	}
+	// Extraction writes the text next to the upload; that is not a new upload.
+	if strings.HasSuffix(base, extractedTextSuffix) {
+		return UploadKey{}, false
+	}
+	return UploadKey{UserID: userID, DocumentID: documentID, FileName: base[37:]}, true
+}
+
+// uploadKeyFromPath parses a presigned key whatever its prefix, for
+// CreateFromS3, which is not told the prefix.
+func uploadKeyFromPath(key string) (UploadKey, bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) < 3 {
+		return UploadKey{}, false
+	}
+	prefix := ""
+	if len(parts) > 3 {
+		prefix = strings.Join(parts[:len(parts)-3], "/") + "/"
+	}
+	return ParseUploadKey(prefix, key)
+}
@@ -0,0 +1,87 @@
+package documents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"resume-backend/internal/documents"
+)
+
+const (
+	uploadDocID  = "0b7c4a52-5f0e-4c1f-9a65-0c1d2b3a4e5f"
+	uploadFileID = "9d8e7f60-1a2b-4c3d-8e9f-a0b1c2d3e4f5"
+)
+
+func TestParseS3EventDecodesCreatedKeys(t *testing.T) {
+	body := `{"Records":[
+		{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"uploads"},"object":{"key":"documents/guest%3Au1/` + uploadDocID + `/` + uploadFileID + `-my+resume.pdf","size":2048}}},
+		{"eventName":"ObjectRemoved:Delete","s3":{"bucket":{"name":"uploads"},"object":{"key":"documents/old.pdf","size":0}}}
+	]}`
+	uploads, err := documents.ParseS3Event([]byte(body))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(uploads) != 1 || uploads[0].Bucket != "uploads" || uploads[0].Size != 2048 {
+		t.Fatalf("uploads = %+v", uploads)
+	}
+	key, ok := documents.ParseUploadKey("documents/", uploads[0].Key)
+	if !ok || key.UserID != "guest:u1" || key.DocumentID != uploadDocID || key.FileName != "my resume.pdf" {
+		t.Fatalf("key = %+v ok=%t", key, ok)
+	}
+
+	if uploads, err := documents.ParseS3Event([]byte(`{"Service":"Amazon S3","Event":"s3:TestEvent"}`)); err != nil || len(uploads) != 0 {
+		t.Fatalf("test event: uploads=%+v err=%v", uploads, err)
+	}
+	for _, key := range []string{
+		"other/u1/" + uploadDocID + "/" + uploadFileID + "-resume.pdf",
+		"documents/u1/not-a-uuid/" + uploadFileID + "-resume.pdf",
+		"documents/u1/" + uploadDocID + "/resume.pdf",
+		"documents/u1/" + uploadDocID + "/" + uploadFileID + "-resume.pdf.extracted.txt",
+	} {
+		if _, ok := documents.ParseUploadKey("documents/", key); ok {
+			t.Fatalf("expected %q to be rejected", key)
+		}
+	}
+}
+
+func TestRegisterS3UploadIsIdempotentWithCreateFromS3(t *testing.T) {
+	ctx := context.Background()
+	svc := &documents.Service{Repo: documents.NewMemoryRepo()}
+	s3Key := "documents/u1/" + uploadDocID + "/" + uploadFileID + "-resume.docx"
+	upload := documents.S3Upload{Bucket: "uploads", Key: s3Key, Size: 4096}
+	key, _ := documents.ParseUploadKey("documents/", s3Key)
+
+	doc, created, err := svc.RegisterS3Upload(ctx, upload, key)
+	if err != nil || !created {
+		t.Fatalf("register: created=%t err=%v", created, err)
+	}
+	if doc.ID != uploadDocID || doc.StorageProvider != "s3" || doc.MimeType != "application/vnd.openxmlformats-officedocument.wordprocessingml.document" {
+		t.Fatalf("doc = %+v", doc)
+	}
+	if doc.Status() != documents.StatusUploaded {
+		t.Fatalf("status = %s, want uploaded before extraction", doc.Status())
+	}
+	if _, created, err := svc.RegisterS3Upload(ctx, upload, key); err != nil || created {
+		t.Fatalf("redelivered event: created=%t err=%v", created, err)
+	}
+
+	// A client that still calls the completion endpoint gets the same document.
+	fromClient, err := svc.CreateFromS3(ctx, "u1", s3Key, "resume.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", 4096)
+	if err != nil || fromClient.ID != uploadDocID {
+		t.Fatalf("create from s3: id=%s err=%v", fromClient.ID, err)
+	}
+	if docs, _ := svc.List(ctx, "u1", 10, 0); len(docs) != 1 {
+		t.Fatalf("expected one document, got %d", len(docs))
+	}
+
+	key.FileName = "resume.exe"
+	if _, _, err := svc.RegisterS3Upload(ctx, upload, key); !errors.Is(err, documents.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for an unsupported type, got %v", err)
+	}
+	upload.Size = documents.MaxS3UploadBytes + 1
+	key.FileName = "resume.pdf"
+	if _, _, err := svc.RegisterS3Upload(ctx, upload, key); !errors.Is(err, documents.ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
@@ -1,14 +1,18 @@
 package documents
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"time"
 
 	"github.com/google/uuid"
 
+	"resume-backend/internal/extract"
+	"resume-backend/internal/shared/storage/db"
 	"resume-backend/internal/shared/storage/object"
 )
 
@@ -17,17 +21,74 @@ type Service struct {
 	Store           object.ObjectStore
 	Repo            DocumentsRepo
 	StorageProvider string
+	Parser          ResumeParser
+	// NearDuplicateThreshold overrides DefaultNearDuplicateThreshold when positive.
+	NearDuplicateThreshold float64
+	// Fetcher downloads documents for UploadFromURL; nil disables it.
+	Fetcher *URLFetcher
+	// Analyses adds the latest analysis to HR Open exports; nil exports the
+	// resume alone.
+	Analyses ExportAnalysisSource
+	// AnalysisDeleter deletes a document's analyses with it in BulkDelete;
+	// nil leaves them.
+	AnalysisDeleter AnalysisDeleter
+	// UnitOfWork deletes a document and its analyses together; nil deletes
+	// them one after the other.
+	UnitOfWork db.UnitOfWork
 }
 
-// Upload saves the file to object storage and records the document.
-func (s *Service) Upload(ctx context.Context, userId, fileName string, r io.Reader) (Document, error) {
+// UploadOptions adjusts how Upload treats a file.
+type UploadOptions struct {
+	// LinkDuplicates returns the user's existing document when the file is byte-for-byte
+	// identical to it, instead of storing a copy that would be extracted and analyzed again.
+	LinkDuplicates bool
+}
+
+// Upload verifies the file's content against declaredType, saves it to object storage
+// and records the document. Files whose extension disagrees with their content are
+// stored under a corrected name; the client's name is kept as OriginalFilename.
+func (s *Service) Upload(ctx context.Context, userId, fileName, declaredType string, r io.Reader) (Document, error) {
+	doc, _, err := s.UploadWithOptions(ctx, userId, fileName, declaredType, r, UploadOptions{})
+	return doc, err
+}
+
+// UploadWithOptions is Upload with options. It reports whether the returned document
+// is an existing one the upload was linked to.
+func (s *Service) UploadWithOptions(ctx context.Context, userId, fileName, declaredType string, r io.Reader, opts UploadOptions) (Document, bool, error) {
 	if fileName == "" {
-		return Document{}, ErrInvalidInput
+		return Document{}, false, ErrInvalidInput
 	}
 
-	storageKey, size, mimeType, err := s.Store.Save(ctx, userId, fileName, r)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return Document{}, err
+		return Document{}, false, fmt.Errorf("read upload: %w", err)
+	}
+	verifiedMime, storedName, err := verifyUpload(fileName, declaredType, data)
+	if err != nil {
+		return Document{}, false, err
+	}
+
+	checksum := contentChecksum(data)
+	if opts.LinkDuplicates {
+		if idx, ok := s.Repo.(duplicateIndex); ok {
+			existing, err := idx.FindByChecksum(ctx, userId, checksum)
+			switch {
+			case err == nil:
+				log.Printf("Linked upload for user %s to existing document %s", userId, existing.ID)
+				return existing, true, nil
+			case !errors.Is(err, ErrNotFound):
+				return Document{}, false, err
+			}
+		}
+	}
+
+	storageKey, size, _, err := s.Store.Save(ctx, userId, storedName, bytes.NewReader(data))
+	if err != nil {
+		return Document{}, false, err
+	}
+	contentType := cleanMimeType(declaredType)
+	if contentType == "" {
+		contentType = verifiedMime
 	}
 
 	storageProvider := s.StorageProvider
@@ -38,23 +99,39 @@ func (s *Service) Upload(ctx context.Context, userId, fileName string, r io.Read
 	doc := Document{
 		ID:               uuid.NewString(),
 		UserID:           userId,
-		FileName:         fileName,
+		FileName:         storedName,
 		OriginalFilename: fileName,
-		MimeType:         mimeType,
-		ContentType:      mimeType,
+		MimeType:         verifiedMime,
+		ContentType:      contentType,
+		VerifiedMime:     verifiedMime,
 		SizeBytes:        size,
 		StorageProvider:  storageProvider,
 		StorageKey:       storageKey,
+		Checksum:         checksum,
 		CreatedAt:        time.Now().UTC(),
 	}
 
-	log.Printf("Uploaded document %s for user %s: size=%d mime=%s", doc.ID, userId, size, mimeType)
+	log.Printf("Uploaded document %s for user %s: size=%d mime=%s", doc.ID, userId, size, verifiedMime)
 
 	if err := s.Repo.Create(ctx, doc); err != nil {
-		return Document{}, err
+		return Document{}, false, err
 	}
 
-	return doc, nil
+	return doc, false, nil
+}
+
+// UploadFromURL downloads a document from a user-supplied URL and uploads it
+// exactly as if the file had been sent directly.
+func (s *Service) UploadFromURL(ctx context.Context, userId, rawURL string, opts UploadOptions) (Document, bool, error) {
+	if s.Fetcher == nil {
+		return Document{}, false, fmt.Errorf("%w: uploads from urls are disabled", ErrInvalidInput)
+	}
+	fetched, err := s.Fetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return Document{}, false, err
+	}
+	log.Printf("Fetched document for user %s: size=%d", userId, len(fetched.Data))
+	return s.UploadWithOptions(ctx, userId, fetched.FileName, fetched.ContentType, bytes.NewReader(fetched.Data), opts)
 }
 
 // CreateFromS3 records a document that already exists in S3.
@@ -63,8 +140,22 @@ func (s *Service) CreateFromS3(ctx context.Context, userId, s3Key, originalFileN
 		return Document{}, ErrInvalidInput
 	}
 
+	// A key from the presign endpoint names the document, which the upload
+	// event consumer may already have registered.
+	id := uuid.NewString()
+	if key, ok := uploadKeyFromPath(s3Key); ok && key.UserID == userId {
+		existing, err := s.Repo.GetByID(ctx, userId, key.DocumentID)
+		switch {
+		case err == nil:
+			return existing, nil
+		case !errors.Is(err, ErrNotFound):
+			return Document{}, err
+		}
+		id = key.DocumentID
+	}
+
 	doc := Document{
-		ID:               uuid.NewString(),
+		ID:               id,
 		UserID:           userId,
 		FileName:         originalFileName,
 		OriginalFilename: originalFileName,
@@ -83,6 +174,51 @@ func (s *Service) CreateFromS3(ctx context.Context, userId, s3Key, originalFileN
 	return doc, nil
 }
 
+// RegisterS3Upload records an object uploaded straight to S3 through a presigned
+// URL, as the upload event consumer sees it. The document takes its ID from the
+// key, so a redelivered event, or a client that also called CreateFromS3, finds
+// the existing row; the bool reports whether a row was created.
+func (s *Service) RegisterS3Upload(ctx context.Context, upload S3Upload, key UploadKey) (Document, bool, error) {
+	contentType := extract.MimeForExtension(key.FileName)
+	switch {
+	case contentType == "":
+		return Document{}, false, fmt.Errorf("%w: unsupported file type %q", ErrInvalidInput, key.FileName)
+	case upload.Size <= 0:
+		return Document{}, false, fmt.Errorf("%w: empty object", ErrInvalidInput)
+	case upload.Size > MaxS3UploadBytes:
+		return Document{}, false, ErrTooLarge
+	}
+
+	existing, err := s.Repo.GetByID(ctx, key.UserID, key.DocumentID)
+	switch {
+	case err == nil:
+		return existing, false, nil
+	case !errors.Is(err, ErrNotFound):
+		return Document{}, false, err
+	}
+
+	doc := Document{
+		ID:               key.DocumentID,
+		UserID:           key.UserID,
+		FileName:         key.FileName,
+		OriginalFilename: key.FileName,
+		MimeType:         contentType,
+		ContentType:      contentType,
+		SizeBytes:        upload.Size,
+		StorageProvider:  "s3",
+		StorageKey:       upload.Key,
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := s.Repo.Create(ctx, doc); err != nil {
+		// The client's CreateFromS3 call may have won the race.
+		if existing, getErr := s.Repo.GetByID(ctx, key.UserID, key.DocumentID); getErr == nil {
+			return existing, false, nil
+		}
+		return Document{}, false, err
+	}
+	return doc, true, nil
+}
+
 // Current returns the current document for a user.
 func (s *Service) Current(ctx context.Context, userId string) (Document, error) {
 	if userId == "" {
@@ -91,6 +227,14 @@ func (s *Service) Current(ctx context.Context, userId string) (Document, error)
 	return s.Repo.GetCurrentByUser(ctx, userId)
 }
 
+// Get returns one of a user's documents.
+func (s *Service) Get(ctx context.Context, userId, id string) (Document, error) {
+	if userId == "" || id == "" {
+		return Document{}, ErrInvalidInput
+	}
+	return s.Repo.GetByID(ctx, userId, id)
+}
+
 // List returns a user's documents ordered newest-first with limit/offset.
 func (s *Service) List(ctx context.Context, userId string, limit, offset int) ([]Document, error) {
 	if userId == "" {
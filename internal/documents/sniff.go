@@ -0,0 +1,79 @@
+package documents
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"resume-backend/internal/extract"
+)
+
+// genericDeclaredTypes carry no claim about the content and are never treated as a mismatch.
+var genericDeclaredTypes = map[string]struct{}{
+	"":                         {},
+	"application/octet-stream": {},
+	"binary/octet-stream":      {},
+}
+
+// declaredAliases maps alternate names clients send to the type DetectMimeType reports.
+var declaredAliases = map[string]string{
+	"application/x-pdf":                  "application/pdf",
+	"application/vnd.ms-word":            "application/msword",
+	"application/x-iwork-pages-sffpages": "application/vnd.apple.pages",
+	"application/x-apple-pages":          "application/vnd.apple.pages",
+}
+
+// zipContainerTypes are formats that some clients label as plain application/zip.
+var zipContainerTypes = map[string]struct{}{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": {},
+	"application/vnd.apple.pages": {},
+}
+
+// verifyUpload sniffs data, rejects it when the declared Content-Type names a different
+// format, and returns the detected type with a file name whose extension matches it.
+func verifyUpload(fileName, declaredType string, data []byte) (string, string, error) {
+	detected := extract.DetectMimeType(data, fileName)
+	declared := cleanMimeType(declaredType)
+	if alias, ok := declaredAliases[declared]; ok {
+		declared = alias
+	}
+
+	if !declaredMatches(declared, detected) {
+		return "", "", fmt.Errorf("%w: declared %s, detected %s", ErrContentMismatch, declared, detected)
+	}
+
+	return detected, correctExtension(fileName, detected), nil
+}
+
+func declaredMatches(declared, detected string) bool {
+	if _, ok := genericDeclaredTypes[declared]; ok {
+		return true
+	}
+	if declared == detected {
+		return true
+	}
+	if declared == "application/zip" {
+		_, ok := zipContainerTypes[detected]
+		return ok
+	}
+	// Text sniffing cannot tell text/csv from text/plain, so any text claim is fine for text.
+	return strings.HasPrefix(declared, "text/") && strings.HasPrefix(detected, "text/")
+}
+
+// correctExtension swaps the extension when it advertises a different format than the
+// content, e.g. a DOCX uploaded as resume.pdf is stored as resume.docx.
+func correctExtension(fileName, detected string) string {
+	want := extract.ExtensionForMime(detected)
+	if want == "" {
+		return fileName
+	}
+	ext := filepath.Ext(fileName)
+	if strings.EqualFold(ext, want) {
+		return fileName
+	}
+	return strings.TrimSuffix(fileName, ext) + want
+}
+
+func cleanMimeType(raw string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(raw, ";")[0]))
+}
@@ -0,0 +1,90 @@
+package documents_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/config"
+)
+
+func postUpload(t *testing.T, router http.Handler, fileName, declaredType string, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+	header.Set("Content-Type", declaredType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatalf("create part: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatalf("write part: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	addGuestHeader(req)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestUploadVerifiesContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app, err := bootstrap.Build(config.Config{
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	docx := buildDOCX(t, paragraphs("Jane Doe", "Engineer"))
+	const docxMime = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+	mismatch := postUpload(t, app.Router, "resume.pdf", "application/pdf", docx)
+	if mismatch.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415 for declared pdf with docx bytes, got %d: %s", mismatch.Code, mismatch.Body.String())
+	}
+
+	spoofed := postUpload(t, app.Router, "resume.pdf", "application/octet-stream", docx)
+	if spoofed.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", spoofed.Code, spoofed.Body.String())
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+		FileName   string `json:"fileName"`
+		MimeType   string `json:"mimeType"`
+	}
+	if err := json.NewDecoder(spoofed.Body).Decode(&created); err != nil {
+		t.Fatalf("decode upload: %v", err)
+	}
+	if created.FileName != "resume.docx" || created.MimeType != docxMime {
+		t.Fatalf("expected renamed docx, got %+v", created)
+	}
+
+	doc, err := app.DocumentsRepo.GetByID(context.Background(), "guest:test-guest", created.DocumentID)
+	if err != nil {
+		t.Fatalf("get document: %v", err)
+	}
+	if doc.VerifiedMime != docxMime || doc.OriginalFilename != "resume.pdf" {
+		t.Fatalf("expected verified mime and original name, got %+v", doc)
+	}
+
+	zipLabelled := postUpload(t, app.Router, "resume.docx", "application/zip", docx)
+	if zipLabelled.Code != http.StatusCreated {
+		t.Fatalf("expected docx labelled application/zip to be accepted, got %d", zipLabelled.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package documents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"resume-backend/internal/extract"
+	"resume-backend/internal/shared/storage/object"
+)
+
+// Resume source strategies: how a stored document is turned into the text a
+// resume is rebuilt from.
+const (
+	// SourceRawText uses a plain-text upload as stored.
+	SourceRawText = "raw_text"
+	// SourceExtractedText uses text extracted from a word-processor document
+	// (DOCX, DOC or Pages), which keeps its reading order and line breaks.
+	SourceExtractedText = "extracted_text"
+	// SourceParsedModel is used for PDFs. Their extracted text has no reliable
+	// structure, so the resume is rebuilt only from the ResumeModel parsed out of
+	// it and none of the original layout is kept.
+	SourceParsedModel = "parsed_model"
+)
+
+// ResumeSource is the text a resume is rebuilt from, with the format of the
+// stored document and the strategy used to read it.
+type ResumeSource struct {
+	Text     string `json:"-"`
+	Format   string `json:"format"`
+	Strategy string `json:"strategy"`
+}
+
+// LoadResumeSource reads a stored document as resume text. The format is
+// sniffed from the stored bytes, so documents recorded with a wrong or generic
+// content type are still read correctly. Binary formats prefer the persisted
+// extraction and fall back to extracting the original.
+func LoadResumeSource(ctx context.Context, store object.ObjectStore, doc Document) (ResumeSource, error) {
+	raw, err := readStoredObject(ctx, store, doc.StorageKey)
+	if err != nil {
+		return ResumeSource{}, fmt.Errorf("read document: %w", err)
+	}
+	format := extract.DetectMimeType(raw, doc.FileName)
+	if strings.HasPrefix(format, "text/") {
+		if !utf8.Valid(raw) {
+			return ResumeSource{}, fmt.Errorf("%w: text is not valid UTF-8", ErrUnreadableDocument)
+		}
+		return ResumeSource{Text: string(raw), Format: format, Strategy: SourceRawText}, nil
+	}
+
+	strategy := SourceExtractedText
+	if format == "application/pdf" {
+		strategy = SourceParsedModel
+	}
+	if doc.ExtractedTextKey != "" {
+		if text, err := readStoredObject(ctx, store, doc.ExtractedTextKey); err == nil && strings.TrimSpace(string(text)) != "" {
+			return ResumeSource{Text: string(text), Format: format, Strategy: strategy}, nil
+		}
+	}
+	text, err := extract.ExtractTextFromBytes(ctx, raw, format, doc.FileName)
+	if err != nil {
+		return ResumeSource{}, fmt.Errorf("%w: %v", ErrUnreadableDocument, err)
+	}
+	if strings.TrimSpace(text) == "" {
+		return ResumeSource{}, fmt.Errorf("%w: no text found", ErrUnreadableDocument)
+	}
+	return ResumeSource{Text: text, Format: format, Strategy: strategy}, nil
+}
+
+func readStoredObject(ctx context.Context, store object.ObjectStore, key string) ([]byte, error) {
+	rc, err := store.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,54 @@
+package documents_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func storeDocument(t *testing.T, store *local.Store, name string, data []byte) documents.Document {
+	t.Helper()
+	key, _, _, err := store.Save(context.Background(), "user-1", name, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("save %s: %v", name, err)
+	}
+	return documents.Document{UserID: "user-1", FileName: name, StorageKey: key}
+}
+
+func TestLoadResumeSourceByFormat(t *testing.T) {
+	ctx := context.Background()
+	store := local.New(t.TempDir()).(*local.Store)
+
+	text := storeDocument(t, store, "resume.txt", []byte("Jane Doe\nBackend engineer"))
+	src, err := documents.LoadResumeSource(ctx, store, text)
+	if err != nil || src.Strategy != documents.SourceRawText || src.Text != "Jane Doe\nBackend engineer" {
+		t.Fatalf("unexpected text source: %+v (%v)", src, err)
+	}
+
+	docx := storeDocument(t, store, "resume.docx", buildDOCX(t, paragraphs("Jane Doe", "Backend engineer")))
+	src, err = documents.LoadResumeSource(ctx, store, docx)
+	if err != nil || src.Strategy != documents.SourceExtractedText || !strings.Contains(src.Text, "Backend engineer") {
+		t.Fatalf("unexpected docx source: %+v (%v)", src, err)
+	}
+
+	// A PDF recorded with a generic name is still read as a PDF, from its persisted extraction.
+	pdf := storeDocument(t, store, "upload", []byte("%PDF-1.4\n%binary"))
+	if _, err := store.SaveWithKey(ctx, "extracted/pdf.txt", "text/plain", strings.NewReader("Jane Doe\nBackend engineer")); err != nil {
+		t.Fatalf("save extraction: %v", err)
+	}
+	pdf.ExtractedTextKey = "extracted/pdf.txt"
+	src, err = documents.LoadResumeSource(ctx, store, pdf)
+	if err != nil || src.Strategy != documents.SourceParsedModel || src.Format != "application/pdf" || !strings.HasPrefix(src.Text, "Jane Doe") {
+		t.Fatalf("unexpected pdf source: %+v (%v)", src, err)
+	}
+
+	pdf.ExtractedTextKey = ""
+	if _, err := documents.LoadResumeSource(ctx, store, pdf); !errors.Is(err, documents.ErrUnreadableDocument) {
+		t.Fatalf("expected ErrUnreadableDocument for an unreadable pdf, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"resume-backend/internal/shared/storage/object"
+)
+
+// LegacyKeyPrefix is prepended to a local storage key to get the key
+// StorageMigration copies it to in the uploads bucket. It sits outside the
+// presigned uploads prefix so the copies do not raise upload events.
+const LegacyKeyPrefix = "legacy-local/"
+
+// Storage migration outcomes.
+const (
+	// MigrationMoved documents were copied to S3 and now read from there.
+	MigrationMoved = "moved"
+	// MigrationUnreachable documents have no upload in local storage and were flagged.
+	MigrationUnreachable = "unreachable"
+	// MigrationFailed documents hit an error and are retried on the next run.
+	MigrationFailed = "failed"
+)
+
+// legacyStorage is implemented by repos that can move documents off local storage.
+type legacyStorage interface {
+	// ListLegacyStorage returns documents not yet in S3 and not flagged as
+	// unreachable, ordered by id, starting after afterID.
+	ListLegacyStorage(ctx context.Context, afterID string, limit int) ([]Document, error)
+	// MoveStorage points a document at its copy in another store. An empty
+	// extractedKey clears the extraction so the text is extracted again.
+	MoveStorage(ctx context.Context, userId, documentID, provider, storageKey, extractedKey string) error
+	// MarkStorageUnreachable flags a document whose upload could not be found.
+	MarkStorageUnreachable(ctx context.Context, userId, documentID string, at time.Time) error
+}
+
+var (
+	_ legacyStorage = (*MemoryRepo)(nil)
+	_ legacyStorage = (*PGRepo)(nil)
+)
+
+// ObjectWriter stores an object under a fixed key in the uploads bucket.
+type ObjectWriter interface {
+	PutObject(ctx context.Context, key, contentType string, data []byte) error
+}
+
+// StorageMigration copies documents uploaded before the move to S3 from local
+// storage into the uploads bucket, so the worker can read them. Documents whose
+// upload is missing are flagged rather than retried forever. Without Apply it
+// only reports what it would do.
+type StorageMigration struct {
+	Repo DocumentsRepo
+	// Local is the store the legacy uploads were written to.
+	Local object.ObjectStore
+	// S3 receives the copies.
+	S3 ObjectWriter
+
+	Apply     bool
+	BatchSize int
+	Now       func() time.Time
+}
+
+// StorageMigrationReport describes what happened to one document.
+type StorageMigrationReport struct {
+	DocumentID string `json:"documentId"`
+	UserID     string `json:"userId"`
+	Outcome    string `json:"outcome"`
+	// StorageKey is the S3 key of the upload for moved documents.
+	StorageKey string `json:"storageKey,omitempty"`
+	// TextCopied is false when a moved document's extracted text was missing and
+	// will be extracted again on first use.
+	TextCopied bool   `json:"textCopied"`
+	Applied    bool   `json:"applied"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Run migrates every legacy document, passing each report to emit. It stops
+// early when ctx is cancelled or emit fails.
+func (m *StorageMigration) Run(ctx context.Context, emit func(StorageMigrationReport) error) error {
+	repo, ok := m.Repo.(legacyStorage)
+	if !ok {
+		return fmt.Errorf("documents repo cannot migrate legacy storage")
+	}
+	if m.Local == nil || m.S3 == nil {
+		return errors.New("local store and s3 writer are required")
+	}
+	batch := m.BatchSize
+	if batch <= 0 {
+		batch = 100
+	}
+
+	afterID := ""
+	for {
+		docs, err := repo.ListLegacyStorage(ctx, afterID, batch)
+		if err != nil {
+			return fmt.Errorf("list legacy documents: %w", err)
+		}
+		for _, doc := range docs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := emit(m.migrate(ctx, repo, doc)); err != nil {
+				return err
+			}
+			afterID = doc.ID
+		}
+		if len(docs) < batch {
+			return nil
+		}
+	}
+}
+
+func (m *StorageMigration) migrate(ctx context.Context, repo legacyStorage, doc Document) StorageMigrationReport {
+	report := StorageMigrationReport{DocumentID: doc.ID, UserID: doc.UserID, Applied: m.Apply}
+	fail := func(err error) StorageMigrationReport {
+		report.Outcome = MigrationFailed
+		report.Error = err.Error()
+		return report
+	}
+
+	raw, err := m.read(ctx, doc.StorageKey)
+	if errors.Is(err, fs.ErrNotExist) {
+		report.Outcome = MigrationUnreachable
+		if m.Apply {
+			if err := repo.MarkStorageUnreachable(ctx, doc.UserID, doc.ID, m.now()); err != nil {
+				return fail(err)
+			}
+		}
+		return report
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	var text []byte
+	if doc.ExtractedTextKey != "" {
+		text, err = m.read(ctx, doc.ExtractedTextKey)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fail(err)
+		}
+		report.TextCopied = err == nil
+	}
+
+	report.Outcome = MigrationMoved
+	report.StorageKey = LegacyKeyPrefix + doc.StorageKey
+	if !m.Apply {
+		return report
+	}
+	contentType := doc.ContentType
+	if contentType == "" {
+		contentType = doc.MimeType
+	}
+	if err := m.S3.PutObject(ctx, report.StorageKey, contentType, raw); err != nil {
+		return fail(err)
+	}
+	extractedKey := ""
+	if report.TextCopied {
+		extractedKey = LegacyKeyPrefix + doc.ExtractedTextKey
+		if err := m.S3.PutObject(ctx, extractedKey, "text/plain; charset=utf-8", text); err != nil {
+			return fail(err)
+		}
+	}
+	if err := repo.MoveStorage(ctx, doc.UserID, doc.ID, "s3", report.StorageKey, extractedKey); err != nil {
+		return fail(err)
+	}
+	return report
+}
+
+func (m *StorageMigration) read(ctx context.Context, key string) ([]byte, error) {
+	if key == "" {
+		return nil, fs.ErrNotExist
+	}
+	body, err := m.Local.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (m *StorageMigration) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now().UTC()
+}
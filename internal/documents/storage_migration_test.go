@@ -0,0 +1,100 @@
+package documents
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	localstore "resume-backend/internal/shared/storage/object/local"
+)
+
+type memoryBucket struct {
+	objects map[string][]byte
+}
+
+func (b *memoryBucket) PutObject(ctx context.Context, key, contentType string, data []byte) error {
+	b.objects[key] = data
+	return nil
+}
+
+func TestStorageMigrationMovesReachableAndFlagsMissingUploads(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := localstore.New(dir)
+	repo := NewMemoryRepo()
+
+	keyA, _, _, err := store.Save(ctx, "u1", "a.docx", strings.NewReader("docx bytes"))
+	if err != nil {
+		t.Fatalf("save a: %v", err)
+	}
+	keyB, _, _, err := store.Save(ctx, "u1", "b.docx", strings.NewReader("other bytes"))
+	if err != nil {
+		t.Fatalf("save b: %v", err)
+	}
+	textA, _, _, err := store.Save(ctx, "u1", "a.txt", strings.NewReader("Jane Doe"))
+	if err != nil {
+		t.Fatalf("save text: %v", err)
+	}
+	extracted := time.Now().UTC()
+	for _, doc := range []Document{
+		{ID: "doc-a", UserID: "u1", StorageProvider: "local", StorageKey: keyA, ExtractedTextKey: textA, ExtractedAt: &extracted},
+		{ID: "doc-b", UserID: "u1", StorageProvider: "local", StorageKey: keyB, ExtractedTextKey: "u1/gone.txt", ExtractedAt: &extracted},
+		{ID: "doc-c", UserID: "u2", StorageProvider: "local", StorageKey: "u2/missing.docx"},
+		{ID: "doc-d", UserID: "u2", StorageProvider: "s3", StorageKey: "uploads/u2/doc-d/resume.docx"},
+	} {
+		if err := repo.Create(ctx, doc); err != nil {
+			t.Fatalf("create %s: %v", doc.ID, err)
+		}
+	}
+
+	bucket := &memoryBucket{objects: map[string][]byte{}}
+	migration := &StorageMigration{Repo: repo, Local: store, S3: bucket, BatchSize: 2}
+	run := func() map[string]StorageMigrationReport {
+		t.Helper()
+		reports := map[string]StorageMigrationReport{}
+		if err := migration.Run(ctx, func(r StorageMigrationReport) error {
+			reports[r.DocumentID] = r
+			return nil
+		}); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		return reports
+	}
+
+	dry := run()
+	if len(dry) != 3 || dry["doc-a"].Outcome != MigrationMoved || dry["doc-c"].Outcome != MigrationUnreachable {
+		t.Fatalf("dry run reports = %+v", dry)
+	}
+	if len(bucket.objects) != 0 {
+		t.Fatalf("dry run wrote %d objects", len(bucket.objects))
+	}
+
+	migration.Apply = true
+	applied := run()
+	if len(applied) != 3 {
+		t.Fatalf("expected 3 reports, got %+v", applied)
+	}
+	if r := applied["doc-a"]; r.Outcome != MigrationMoved || !r.TextCopied || r.StorageKey != LegacyKeyPrefix+keyA {
+		t.Fatalf("doc-a report = %+v", r)
+	}
+	if r := applied["doc-b"]; r.Outcome != MigrationMoved || r.TextCopied {
+		t.Fatalf("doc-b report = %+v", r)
+	}
+	if string(bucket.objects[LegacyKeyPrefix+keyA]) != "docx bytes" || string(bucket.objects[LegacyKeyPrefix+textA]) != "Jane Doe" {
+		t.Fatalf("bucket = %v", bucket.objects)
+	}
+
+	a, _ := repo.GetByID(ctx, "u1", "doc-a")
+	if a.StorageProvider != "s3" || a.StorageKey != LegacyKeyPrefix+keyA || a.ExtractedTextKey != LegacyKeyPrefix+textA || a.ExtractedAt == nil {
+		t.Fatalf("doc-a after move = %+v", a)
+	}
+	b, _ := repo.GetByID(ctx, "u1", "doc-b")
+	if b.StorageProvider != "s3" || b.ExtractedTextKey != "" || b.ExtractedAt != nil {
+		t.Fatalf("doc-b should be re-extracted after the move, got %+v", b)
+	}
+
+	if again := run(); len(again) != 0 {
+		t.Fatalf("moved and flagged documents should not be listed again, got %+v", again)
+	}
+}
@@ -0,0 +1,230 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Options tunes an Emitter.
+type Options struct {
+	// HashSalt keys the distinct ID hash.
+	HashSalt string
+	// BufferSize bounds queued events; events are dropped when it is full.
+	BufferSize int
+	// BatchSize is the most events sent to the sink in one call.
+	BatchSize int
+	// FlushInterval is how often Run sends a partial batch.
+	FlushInterval time.Duration
+}
+
+// DefaultOptions returns the settings used for zero-valued fields.
+func DefaultOptions() Options {
+	return Options{
+		BufferSize:    1024,
+		BatchSize:     50,
+		FlushInterval: 5 * time.Second,
+	}
+}
+
+// maxRemembered bounds the in-process cache of once-only events already sent.
+const maxRemembered = 10000
+
+type pending struct {
+	event Event
+	// once events are only sent the first time a user reaches the step.
+	once bool
+}
+
+// Emitter scrubs funnel events and delivers them to a Sink in the background.
+// A nil *Emitter is valid and discards everything, so callers need no checks.
+type Emitter struct {
+	Sink  Sink
+	Once  OnceStore
+	Now   func() time.Time
+	opts  Options
+	queue chan pending
+
+	mu         sync.Mutex
+	remembered map[string]struct{}
+}
+
+// NewEmitter builds an Emitter. once may be nil, in which case first-time events are
+// deduplicated per process only.
+func NewEmitter(sink Sink, once OnceStore, opts Options) *Emitter {
+	def := DefaultOptions()
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = def.BufferSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = def.BatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = def.FlushInterval
+	}
+	if once == nil {
+		once = NewMemoryOnceStore()
+	}
+	return &Emitter{
+		Sink:       sink,
+		Once:       once,
+		opts:       opts,
+		queue:      make(chan pending, opts.BufferSize),
+		remembered: make(map[string]struct{}),
+	}
+}
+
+// Track queues an event for userID. It never blocks the caller.
+func (e *Emitter) Track(ctx context.Context, name, userID string, props map[string]any) {
+	e.enqueue(name, userID, props, false)
+}
+
+// TrackFirst queues an event that is sent only the first time userID reaches it.
+func (e *Emitter) TrackFirst(ctx context.Context, name, userID string, props map[string]any) {
+	e.enqueue(name, userID, props, true)
+}
+
+// DistinctID returns the pseudonymous ID events for userID are sent under.
+func (e *Emitter) DistinctID(userID string) string {
+	if e == nil {
+		return ""
+	}
+	return HashUserID(e.opts.HashSalt, userID)
+}
+
+func (e *Emitter) enqueue(name, userID string, props map[string]any, once bool) {
+	if e == nil || strings.TrimSpace(userID) == "" {
+		return
+	}
+	if _, ok := allowedProperties[name]; !ok {
+		telemetry.Error("analytics.unknown_event", map[string]any{"event": name})
+		return
+	}
+	distinctID := e.DistinctID(userID)
+	if once && e.seen(distinctID, name) {
+		return
+	}
+	properties := scrubProperties(name, props)
+	properties["is_guest"] = isGuest(userID)
+	ev := Event{
+		ID:         uuid.NewString(),
+		Name:       name,
+		DistinctID: distinctID,
+		Properties: properties,
+		Timestamp:  e.now(),
+	}
+	ev.IdempotencyKey = ev.ID
+	if once {
+		// A step is reached once per user, so its key is stable and a replay from
+		// funnel_milestones delivers the same event again.
+		ev.IdempotencyKey = MilestoneKey(name, distinctID)
+		ev.ID = KeyID(ev.IdempotencyKey)
+	}
+	select {
+	case e.queue <- pending{event: ev, once: once}:
+	default:
+		telemetry.Error("analytics.dropped", map[string]any{"event": name, "reason": "buffer_full"})
+	}
+}
+
+// seen reports whether this process already queued the once-only event.
+func (e *Emitter) seen(distinctID, name string) bool {
+	key := name + "|" + distinctID
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.remembered[key]; ok {
+		return true
+	}
+	if len(e.remembered) >= maxRemembered {
+		e.remembered = make(map[string]struct{})
+	}
+	e.remembered[key] = struct{}{}
+	return false
+}
+
+// Run delivers queued events until ctx is cancelled.
+func (e *Emitter) Run(ctx context.Context) {
+	if e == nil {
+		return
+	}
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pending, 0, e.opts.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case p := <-e.queue:
+			batch = append(batch, p)
+			if len(batch) >= e.opts.BatchSize {
+				e.send(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.send(ctx, batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// Flush synchronously delivers everything queued so far. Short-lived processes such
+// as Lambda handlers call it instead of relying on Run.
+func (e *Emitter) Flush(ctx context.Context) {
+	if e == nil {
+		return
+	}
+	for {
+		batch := make([]pending, 0, e.opts.BatchSize)
+	drain:
+		for len(batch) < e.opts.BatchSize {
+			select {
+			case p := <-e.queue:
+				batch = append(batch, p)
+			default:
+				break drain
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+		e.send(ctx, batch)
+	}
+}
+
+func (e *Emitter) send(ctx context.Context, batch []pending) {
+	out := make([]Event, 0, len(batch))
+	for _, p := range batch {
+		if p.once {
+			first, err := e.Once.MarkFirst(ctx, p.event.DistinctID, p.event.Name)
+			if err != nil {
+				telemetry.Error("analytics.once_failed", map[string]any{"event": p.event.Name, "err": err.Error()})
+				continue
+			}
+			if !first {
+				continue
+			}
+		}
+		out = append(out, p.event)
+	}
+	if len(out) == 0 || e.Sink == nil {
+		return
+	}
+	if err := e.Sink.Send(ctx, out); err != nil {
+		telemetry.Error("analytics.sink_failed", map[string]any{"events": len(out), "err": err.Error()})
+	}
+}
+
+func (e *Emitter) now() time.Time {
+	if e.Now != nil {
+		return e.Now().UTC()
+	}
+	return time.Now().UTC()
+}
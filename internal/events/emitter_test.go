@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Send(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func TestTrackScrubsPropertiesAndHashesUserID(t *testing.T) {
+	sink := &recordingSink{}
+	e := NewEmitter(sink, nil, Options{HashSalt: "salt"})
+	ctx := context.Background()
+
+	e.Track(ctx, DocumentUploaded, "google:12345", map[string]any{
+		"mime_type":  "application/pdf",
+		"size_bytes": int64(2048),
+		"source":     "jane@example.com",
+		"file_name":  "Jane Doe Resume.pdf",
+	})
+	e.Flush(ctx)
+
+	if len(sink.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(sink.events))
+	}
+	ev := sink.events[0]
+	if ev.DistinctID == "" || strings.Contains(ev.DistinctID, "12345") {
+		t.Fatalf("expected hashed distinct id, got %q", ev.DistinctID)
+	}
+	if ev.DistinctID != e.DistinctID("google:12345") {
+		t.Fatalf("expected stable distinct id")
+	}
+	if NewEmitter(sink, nil, Options{HashSalt: "other"}).DistinctID("google:12345") == ev.DistinctID {
+		t.Fatalf("expected the salt to change the distinct id")
+	}
+	want := map[string]any{"mime_type": "application/pdf", "size_bytes": int64(2048), "is_guest": false}
+	if len(ev.Properties) != len(want) {
+		t.Fatalf("unexpected properties %v", ev.Properties)
+	}
+	for k, v := range want {
+		if ev.Properties[k] != v {
+			t.Fatalf("property %s: got %v want %v", k, ev.Properties[k], v)
+		}
+	}
+}
+
+func TestTrackFirstSendsOncePerUser(t *testing.T) {
+	sink := &recordingSink{}
+	once := NewMemoryOnceStore()
+	ctx := context.Background()
+
+	// Two emitters sharing a store behave like two API instances sharing a database.
+	a := NewEmitter(sink, once, Options{HashSalt: "salt"})
+	b := NewEmitter(sink, once, Options{HashSalt: "salt"})
+	a.TrackFirst(ctx, FirstApply, "guest:g1", map[string]any{"template_id": "resume_modern_ats_v1"})
+	a.TrackFirst(ctx, FirstApply, "guest:g1", nil)
+	b.TrackFirst(ctx, FirstApply, "guest:g1", nil)
+	b.TrackFirst(ctx, FirstApply, "guest:g2", nil)
+	a.Flush(ctx)
+	b.Flush(ctx)
+
+	if len(sink.events) != 2 {
+		t.Fatalf("expected one event per user, got %d", len(sink.events))
+	}
+	if sink.events[0].Properties["is_guest"] != true || sink.events[0].Properties["template_id"] != "resume_modern_ats_v1" {
+		t.Fatalf("unexpected properties %v", sink.events[0].Properties)
+	}
+}
+
+func TestNilEmitterAndUnknownEventsAreIgnored(t *testing.T) {
+	var nilEmitter *Emitter
+	nilEmitter.Track(context.Background(), Signup, "google:1", nil)
+	nilEmitter.Flush(context.Background())
+
+	sink := &recordingSink{}
+	e := NewEmitter(sink, nil, Options{})
+	e.Track(context.Background(), "resume_text_viewed", "google:1", nil)
+	e.Track(context.Background(), Signup, "", nil)
+	e.Flush(context.Background())
+	if len(sink.events) != 0 {
+		t.Fatalf("expected nothing to be sent, got %+v", sink.events)
+	}
+}
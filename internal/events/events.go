@@ -0,0 +1,115 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Funnel steps emitted to the product analytics sink.
+const (
+	GuestCreated        = "guest_created"
+	DocumentUploaded    = "document_uploaded"
+	FirstAnalysisViewed = "first_analysis_viewed"
+	Signup              = "signup"
+	FirstApply          = "first_apply"
+	// GuestClaimed links a guest's funnel to the account that claimed its work.
+	GuestClaimed = "guest_claimed"
+)
+
+// Event is a scrubbed analytics event as handed to a Sink.
+type Event struct {
+	// ID is unique per event so sinks can drop duplicate deliveries.
+	ID string
+	// IdempotencyKey names the fact the event records. A replayed event carries
+	// the same key, and the same ID, as the original delivery, so projections
+	// rebuilt from a replay can upsert on it.
+	IdempotencyKey string
+	Name           string
+	// DistinctID is a keyed hash of the user ID; raw IDs never leave the process.
+	DistinctID string
+	Properties map[string]any
+	Timestamp  time.Time
+	// Replayed marks events re-emitted from history rather than as they happened.
+	Replayed bool
+}
+
+// allowedProperties lists the only properties each event may carry. Anything else
+// is dropped before the event is queued.
+var allowedProperties = map[string]map[string]bool{
+	GuestCreated:        {},
+	DocumentUploaded:    {"mime_type": true, "size_bytes": true, "source": true},
+	FirstAnalysisViewed: {"mode": true, "prompt_version": true},
+	Signup:              {"provider": true},
+	FirstApply:          {"template_id": true},
+	GuestClaimed:        {"previous_distinct_id": true, "migrated_documents": true, "migrated_analyses": true},
+}
+
+// maxStringProperty bounds string property values; longer values are dropped.
+const maxStringProperty = 64
+
+// scrubProperties keeps allowlisted properties whose values cannot carry free text.
+func scrubProperties(name string, props map[string]any) map[string]any {
+	allowed := allowedProperties[name]
+	out := make(map[string]any, len(allowed)+1)
+	for key, value := range props {
+		if !allowed[key] {
+			continue
+		}
+		switch v := value.(type) {
+		case bool, int, int32, int64, float64:
+			out[key] = v
+		case string:
+			if safeString(v) {
+				out[key] = v
+			}
+		}
+	}
+	return out
+}
+
+// safeString accepts short identifier-like values such as MIME types and version
+// names, and rejects anything that could be an email address, URL or sentence.
+func safeString(v string) bool {
+	if v == "" || len(v) > maxStringProperty {
+		return false
+	}
+	for _, r := range v {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("_-./+", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// keyNamespace scopes the IDs derived from idempotency keys.
+var keyNamespace = uuid.MustParse("5b0c7a4e-2f7d-4c1e-9b53-0e6f3c8a9d21")
+
+// KeyID derives the stable event ID for an idempotency key. Sinks that require
+// UUIDs, such as PostHog, get the same ID for every delivery of a fact.
+func KeyID(key string) string {
+	return uuid.NewSHA1(keyNamespace, []byte(key)).String()
+}
+
+// MilestoneKey is the idempotency key of a once-only funnel step.
+func MilestoneKey(name, distinctID string) string {
+	return "funnel/" + name + "/" + distinctID
+}
+
+// HashUserID derives a stable pseudonymous distinct ID from a user ID.
+func HashUserID(salt, userID string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(userID))
+	return "u_" + hex.EncodeToString(mac.Sum(nil))[:24]
+}
+
+func isGuest(userID string) bool {
+	return strings.HasPrefix(userID, "guest:")
+}
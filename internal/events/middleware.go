@@ -0,0 +1,18 @@
+package events
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+)
+
+// TrackGuests emits GuestCreated the first time a guest identity makes a request.
+// It must run after middleware.Auth.
+func TrackGuests(e *Emitter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if guest, _ := c.Get("isGuest"); guest == true {
+			e.TrackFirst(c.Request.Context(), GuestCreated, middleware.UserIDFromContext(c), nil)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// OnceStore remembers which once-only funnel steps each distinct ID has reached.
+type OnceStore interface {
+	// MarkFirst records the step and reports whether this was the first time.
+	MarkFirst(ctx context.Context, distinctID, event string) (bool, error)
+}
+
+// MemoryOnceStore keeps milestones in memory.
+type MemoryOnceStore struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewMemoryOnceStore constructs an empty MemoryOnceStore.
+func NewMemoryOnceStore() *MemoryOnceStore {
+	return &MemoryOnceStore{seen: make(map[string]struct{})}
+}
+
+// MarkFirst records the step and reports whether this was the first time.
+func (s *MemoryOnceStore) MarkFirst(ctx context.Context, distinctID, event string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	key := event + "|" + distinctID
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[key]; ok {
+		return false, nil
+	}
+	s.seen[key] = struct{}{}
+	return true, nil
+}
+
+// PGOnceStore keeps milestones in Postgres. Only hashed distinct IDs are stored.
+type PGOnceStore struct {
+	DB *sql.DB
+}
+
+var _ OnceStore = (*PGOnceStore)(nil)
+
+// MarkFirst records the step and reports whether this was the first time.
+func (s *PGOnceStore) MarkFirst(ctx context.Context, distinctID, event string) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, `
+INSERT INTO funnel_milestones (distinct_id, event, reached_at)
+VALUES ($1, $2, now())
+ON CONFLICT (distinct_id, event) DO NOTHING`, distinctID, event)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,278 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Range selects the historical events a replay re-emits. Zero fields match
+// everything.
+type Range struct {
+	// Since and Until bound the event timestamp: Since is inclusive, Until
+	// exclusive.
+	Since time.Time
+	Until time.Time
+	// Names keeps only events with one of these names.
+	Names []string
+}
+
+// Matches reports whether ev falls inside the range.
+func (r Range) Matches(ev Event) bool {
+	if !r.Since.IsZero() && ev.Timestamp.Before(r.Since) {
+		return false
+	}
+	if !r.Until.IsZero() && !ev.Timestamp.Before(r.Until) {
+		return false
+	}
+	if len(r.Names) == 0 {
+		return true
+	}
+	for _, name := range r.Names {
+		if name == ev.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Cursor is the position of the last event a replay read. Sources order events
+// by timestamp, then idempotency key, and resume strictly after the cursor.
+type Cursor struct {
+	At  time.Time `json:"at"`
+	Key string    `json:"key"`
+}
+
+// After reports whether ev sorts after the cursor.
+func (c Cursor) After(ev Event) bool {
+	if c.At.IsZero() && c.Key == "" {
+		return true
+	}
+	return ev.Timestamp.After(c.At) || (ev.Timestamp.Equal(c.At) && ev.IdempotencyKey > c.Key)
+}
+
+// Source reads stored history as events, oldest first.
+type Source interface {
+	// Name identifies the source in replay reports.
+	Name() string
+	// Page returns up to limit events in r after cursor.
+	Page(ctx context.Context, r Range, after Cursor, limit int) ([]Event, error)
+}
+
+// ReplayReport describes one replayed batch.
+type ReplayReport struct {
+	Source string    `json:"source"`
+	Events int       `json:"events"`
+	First  time.Time `json:"first"`
+	Last   time.Time `json:"last"`
+	// Cursor resumes a replay that stopped after this batch.
+	Cursor Cursor `json:"cursor"`
+	Sent   bool   `json:"sent"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Replay re-emits the events of Source that fall in Range to Sink, so downstream
+// projections such as the analytics warehouse can be rebuilt. Every event keeps
+// its idempotency key and is marked Replayed. Without Apply it only counts the
+// events it would send.
+type Replay struct {
+	Source    Source
+	Sink      Sink
+	Range     Range
+	After     Cursor
+	Apply     bool
+	BatchSize int
+}
+
+const defaultReplayBatch = 500
+
+// Run replays page by page, calling report after each batch. It stops at the
+// first sink error so the reported cursor can resume the replay.
+func (r *Replay) Run(ctx context.Context, report func(ReplayReport) error) error {
+	if r.Source == nil {
+		return errors.New("replay source required")
+	}
+	if r.Apply && r.Sink == nil {
+		return errors.New("replay sink required")
+	}
+	batch := r.BatchSize
+	if batch <= 0 {
+		batch = defaultReplayBatch
+	}
+	cursor := r.After
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := r.Source.Page(ctx, r.Range, cursor, batch)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", r.Source.Name(), err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		for i := range page {
+			page[i].Replayed = true
+		}
+		last := page[len(page)-1]
+		rep := ReplayReport{
+			Source: r.Source.Name(),
+			Events: len(page),
+			First:  page[0].Timestamp,
+			Last:   last.Timestamp,
+			Cursor: Cursor{At: last.Timestamp, Key: last.IdempotencyKey},
+		}
+		var sendErr error
+		if r.Apply {
+			if sendErr = r.Sink.Send(ctx, page); sendErr != nil {
+				rep.Error = sendErr.Error()
+				// Report the position before this batch, since it was not delivered.
+				rep.Cursor = cursor
+			} else {
+				rep.Sent = true
+			}
+		}
+		if err := report(rep); err != nil {
+			return err
+		}
+		if sendErr != nil {
+			return sendErr
+		}
+		cursor = rep.Cursor
+		if len(page) < batch {
+			return nil
+		}
+	}
+}
+
+// WriterSink writes events as JSON lines, e.g. to a file a warehouse bulk-loads.
+type WriterSink struct {
+	mu sync.Mutex
+	W  io.Writer
+}
+
+// wireEvent is the JSON line written for one event.
+type wireEvent struct {
+	ID             string         `json:"id"`
+	IdempotencyKey string         `json:"idempotencyKey"`
+	Name           string         `json:"name"`
+	DistinctID     string         `json:"distinctId"`
+	Properties     map[string]any `json:"properties"`
+	Timestamp      time.Time      `json:"timestamp"`
+	Replayed       bool           `json:"replayed,omitempty"`
+}
+
+// Send writes one line per event.
+func (s *WriterSink) Send(ctx context.Context, events []Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := json.NewEncoder(s.W)
+	for _, ev := range events {
+		if err := enc.Encode(wireEvent(ev)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SliceSource replays events held in memory.
+type SliceSource struct {
+	Label  string
+	Events []Event
+}
+
+// Name identifies the source.
+func (s SliceSource) Name() string { return s.Label }
+
+// Page returns up to limit matching events after cursor. Events must already be
+// in cursor order.
+func (s SliceSource) Page(ctx context.Context, r Range, after Cursor, limit int) ([]Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]Event, 0, limit)
+	for _, ev := range s.Events {
+		if !after.After(ev) || !r.Matches(ev) {
+			continue
+		}
+		out = append(out, ev)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// MilestoneSource replays the once-only funnel steps stored by PGOnceStore.
+// Only the step and when it was reached are stored, so replayed milestones carry
+// no properties.
+type MilestoneSource struct {
+	DB *sql.DB
+}
+
+var _ Source = (*MilestoneSource)(nil)
+
+// Name identifies the source.
+func (s *MilestoneSource) Name() string { return "funnel" }
+
+// Page returns up to limit milestones after cursor.
+func (s *MilestoneSource) Page(ctx context.Context, r Range, after Cursor, limit int) ([]Event, error) {
+	where := []string{"TRUE"}
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if !r.Since.IsZero() {
+		where = append(where, "reached_at >= "+arg(r.Since))
+	}
+	if !r.Until.IsZero() {
+		where = append(where, "reached_at < "+arg(r.Until))
+	}
+	if len(r.Names) > 0 {
+		names := make([]string, 0, len(r.Names))
+		for _, name := range r.Names {
+			names = append(names, arg(name))
+		}
+		where = append(where, "event IN ("+strings.Join(names, ", ")+")")
+	}
+	if !after.At.IsZero() {
+		at := arg(after.At)
+		key := arg(after.Key)
+		where = append(where, fmt.Sprintf("(reached_at > %s OR (reached_at = %s AND 'funnel/' || event || '/' || distinct_id > %s))", at, at, key))
+	}
+	query := `
+SELECT distinct_id, event, reached_at
+FROM funnel_milestones
+WHERE ` + strings.Join(where, " AND ") + `
+ORDER BY reached_at, 'funnel/' || event || '/' || distinct_id
+LIMIT ` + arg(limit)
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Event
+	for rows.Next() {
+		var ev Event
+		if err := rows.Scan(&ev.DistinctID, &ev.Name, &ev.Timestamp); err != nil {
+			return nil, err
+		}
+		ev.IdempotencyKey = MilestoneKey(ev.Name, ev.DistinctID)
+		ev.ID = KeyID(ev.IdempotencyKey)
+		ev.Timestamp = ev.Timestamp.UTC()
+		ev.Properties = map[string]any{}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func replayEvents() []Event {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var out []Event
+	for i, name := range []string{Signup, FirstApply, Signup, Signup, FirstApply} {
+		key := MilestoneKey(name, string(rune('a'+i)))
+		out = append(out, Event{ID: KeyID(key), IdempotencyKey: key, Name: name, Timestamp: base.Add(time.Duration(i) * time.Hour)})
+	}
+	return out
+}
+
+func TestReplaySendsRangeInBatches(t *testing.T) {
+	history := replayEvents()
+	sink := &recordingSink{}
+	replay := &Replay{
+		Source:    SliceSource{Label: "test", Events: history},
+		Sink:      sink,
+		Range:     Range{Since: history[1].Timestamp, Until: history[4].Timestamp, Names: []string{Signup}},
+		Apply:     true,
+		BatchSize: 1,
+	}
+	var reports []ReplayReport
+	err := replay.Run(context.Background(), func(r ReplayReport) error {
+		reports = append(reports, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(sink.events) != 2 || sink.events[0].IdempotencyKey != history[2].IdempotencyKey || sink.events[1].IdempotencyKey != history[3].IdempotencyKey {
+		t.Fatalf("unexpected replayed events %+v", sink.events)
+	}
+	if !sink.events[0].Replayed || sink.events[0].ID != history[2].ID {
+		t.Fatalf("expected replayed events to keep their ids, got %+v", sink.events[0])
+	}
+	if len(reports) != 2 || !reports[1].Sent || reports[1].Cursor.Key != history[3].IdempotencyKey {
+		t.Fatalf("unexpected reports %+v", reports)
+	}
+}
+
+func TestReplayDryRunAndSinkFailure(t *testing.T) {
+	history := replayEvents()
+	var count int
+	dry := &Replay{Source: SliceSource{Label: "test", Events: history}}
+	if err := dry.Run(context.Background(), func(r ReplayReport) error {
+		count += r.Events
+		if r.Sent {
+			t.Fatalf("dry run must not send")
+		}
+		return nil
+	}); err != nil || count != len(history) {
+		t.Fatalf("dry run counted %d, err %v", count, err)
+	}
+
+	after := Cursor{At: history[0].Timestamp, Key: history[0].IdempotencyKey}
+	failing := &Replay{Source: SliceSource{Label: "test", Events: history}, Sink: failingSink{}, After: after, Apply: true, BatchSize: 2}
+	var last ReplayReport
+	err := failing.Run(context.Background(), func(r ReplayReport) error {
+		last = r
+		return nil
+	})
+	if err == nil || last.Error == "" || last.Cursor != after {
+		t.Fatalf("expected the failed batch to report the cursor before it, got %+v (err %v)", last, err)
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) Send(context.Context, []Event) error { return errors.New("warehouse down") }
+
+func TestTrackFirstSharesIDWithReplayedMilestone(t *testing.T) {
+	sink := &recordingSink{}
+	e := NewEmitter(sink, nil, Options{HashSalt: "salt"})
+	e.TrackFirst(context.Background(), FirstApply, "google:1", nil)
+	e.Flush(context.Background())
+
+	key := MilestoneKey(FirstApply, e.DistinctID("google:1"))
+	if len(sink.events) != 1 || sink.events[0].IdempotencyKey != key || sink.events[0].ID != KeyID(key) {
+		t.Fatalf("expected the milestone key and its derived id, got %+v", sink.events)
+	}
+}
+
+func TestWriterSinkWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	ev := replayEvents()[0]
+	ev.Replayed = true
+	if err := (&WriterSink{W: &buf}).Send(context.Background(), []Event{ev, ev}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected two lines, got %q", buf.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(lines[0], &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got["idempotencyKey"] != ev.IdempotencyKey || got["replayed"] != true {
+		t.Fatalf("unexpected line %v", got)
+	}
+}
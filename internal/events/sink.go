@@ -0,0 +1,188 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Sink delivers batches of scrubbed events to an analytics backend.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// LogSink writes events to the structured log; useful in development.
+type LogSink struct{}
+
+// Send logs each event.
+func (LogSink) Send(ctx context.Context, events []Event) error {
+	for _, ev := range events {
+		fields := map[string]any{
+			"event":           ev.Name,
+			"distinct_id":     ev.DistinctID,
+			"idempotency_key": ev.IdempotencyKey,
+			"replayed":        ev.Replayed,
+		}
+		for k, v := range ev.Properties {
+			fields["prop_"+k] = v
+		}
+		telemetry.Info("analytics.event", fields)
+	}
+	return nil
+}
+
+const (
+	defaultSegmentEndpoint = "https://api.segment.io/v1/batch"
+	defaultPostHogHost     = "https://us.i.posthog.com"
+	sinkTimeout            = 10 * time.Second
+	libraryName            = "resume-backend"
+)
+
+// SegmentSink posts events to the Segment HTTP tracking API.
+type SegmentSink struct {
+	WriteKey string
+	// Endpoint overrides the batch URL, e.g. for a regional or self-hosted endpoint.
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewSegmentSink builds a SegmentSink. An empty endpoint uses Segment's default.
+func NewSegmentSink(writeKey, endpoint string) *SegmentSink {
+	if strings.TrimSpace(endpoint) == "" {
+		endpoint = defaultSegmentEndpoint
+	}
+	return &SegmentSink{WriteKey: writeKey, Endpoint: endpoint, Client: &http.Client{Timeout: sinkTimeout}}
+}
+
+type segmentBatch struct {
+	Batch []segmentTrack `json:"batch"`
+}
+
+type segmentTrack struct {
+	Type       string         `json:"type"`
+	MessageID  string         `json:"messageId"`
+	UserID     string         `json:"userId"`
+	Event      string         `json:"event"`
+	Properties map[string]any `json:"properties"`
+	Timestamp  string         `json:"timestamp"`
+	Context    map[string]any `json:"context"`
+}
+
+// Send posts one batch request.
+func (s *SegmentSink) Send(ctx context.Context, events []Event) error {
+	body := segmentBatch{Batch: make([]segmentTrack, 0, len(events))}
+	for _, ev := range events {
+		body.Batch = append(body.Batch, segmentTrack{
+			Type:       "track",
+			MessageID:  ev.ID,
+			UserID:     ev.DistinctID,
+			Event:      ev.Name,
+			Properties: deliveryProperties(ev, 0),
+			Timestamp:  ev.Timestamp.Format(time.RFC3339Nano),
+			// Events are sent server-side; a zero IP stops Segment from recording ours
+			// as the user's location.
+			Context: map[string]any{"ip": "0.0.0.0", "library": map[string]string{"name": libraryName}},
+		})
+	}
+	return postJSON(ctx, s.Client, s.Endpoint, body, func(req *http.Request) {
+		req.SetBasicAuth(s.WriteKey, "")
+	})
+}
+
+// PostHogSink posts events to the PostHog batch capture API.
+type PostHogSink struct {
+	APIKey string
+	// Host is the PostHog instance, e.g. https://eu.i.posthog.com.
+	Host   string
+	Client *http.Client
+}
+
+// NewPostHogSink builds a PostHogSink. An empty host uses PostHog's US cloud.
+func NewPostHogSink(apiKey, host string) *PostHogSink {
+	if strings.TrimSpace(host) == "" {
+		host = defaultPostHogHost
+	}
+	return &PostHogSink{APIKey: apiKey, Host: strings.TrimRight(host, "/"), Client: &http.Client{Timeout: sinkTimeout}}
+}
+
+type postHogBatch struct {
+	APIKey string         `json:"api_key"`
+	Batch  []postHogEvent `json:"batch"`
+}
+
+type postHogEvent struct {
+	UUID       string         `json:"uuid"`
+	Event      string         `json:"event"`
+	DistinctID string         `json:"distinct_id"`
+	Properties map[string]any `json:"properties"`
+	Timestamp  string         `json:"timestamp"`
+}
+
+// Send posts one batch request.
+func (s *PostHogSink) Send(ctx context.Context, events []Event) error {
+	body := postHogBatch{APIKey: s.APIKey, Batch: make([]postHogEvent, 0, len(events))}
+	for _, ev := range events {
+		props := deliveryProperties(ev, 3)
+		props["$lib"] = libraryName
+		// Without these PostHog records the server's IP and geolocation on the person.
+		props["$ip"] = nil
+		props["$geoip_disable"] = true
+		body.Batch = append(body.Batch, postHogEvent{
+			UUID:       ev.ID,
+			Event:      ev.Name,
+			DistinctID: ev.DistinctID,
+			Properties: props,
+			Timestamp:  ev.Timestamp.Format(time.RFC3339Nano),
+		})
+	}
+	return postJSON(ctx, s.Client, s.Host+"/batch/", body, nil)
+}
+
+// deliveryProperties copies the event's properties with room for extra entries,
+// flagging replayed events so dashboards can tell a rebuild from live traffic.
+func deliveryProperties(ev Event, extra int) map[string]any {
+	props := make(map[string]any, len(ev.Properties)+extra+1)
+	for k, v := range ev.Properties {
+		props[k] = v
+	}
+	if ev.Replayed {
+		props["replayed"] = true
+	}
+	return props
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any, decorate func(*http.Request)) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if decorate != nil {
+		decorate(req)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("analytics http status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testEvents() []Event {
+	return []Event{{
+		ID:         "evt-1",
+		Name:       Signup,
+		DistinctID: "u_abc",
+		Properties: map[string]any{"provider": "google", "is_guest": false},
+		Timestamp:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}}
+}
+
+func captureServer(t *testing.T, onRequest func(r *http.Request, body map[string]any)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		onRequest(r, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSegmentSinkPostsTrackBatch(t *testing.T) {
+	var got map[string]any
+	var user string
+	srv := captureServer(t, func(r *http.Request, body map[string]any) {
+		user, _, _ = r.BasicAuth()
+		got = body
+	})
+
+	if err := NewSegmentSink("write-key", srv.URL).Send(context.Background(), testEvents()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if user != "write-key" {
+		t.Fatalf("expected write key as basic auth user, got %q", user)
+	}
+	track := got["batch"].([]any)[0].(map[string]any)
+	if track["type"] != "track" || track["event"] != Signup || track["userId"] != "u_abc" || track["messageId"] != "evt-1" {
+		t.Fatalf("unexpected track call %v", track)
+	}
+	if ip := track["context"].(map[string]any)["ip"]; ip != "0.0.0.0" {
+		t.Fatalf("expected ip to be suppressed, got %v", ip)
+	}
+}
+
+func TestPostHogSinkPostsBatchWithoutIP(t *testing.T) {
+	var got map[string]any
+	var path string
+	srv := captureServer(t, func(r *http.Request, body map[string]any) {
+		path = r.URL.Path
+		got = body
+	})
+
+	if err := NewPostHogSink("phc_key", srv.URL+"/").Send(context.Background(), testEvents()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if path != "/batch/" || got["api_key"] != "phc_key" {
+		t.Fatalf("unexpected request path=%s body=%v", path, got)
+	}
+	ev := got["batch"].([]any)[0].(map[string]any)
+	props := ev["properties"].(map[string]any)
+	if ev["distinct_id"] != "u_abc" || props["provider"] != "google" {
+		t.Fatalf("unexpected event %v", ev)
+	}
+	if ip, ok := props["$ip"]; !ok || ip != nil || props["$geoip_disable"] != true {
+		t.Fatalf("expected ip capture disabled, got %v", props)
+	}
+}
+
+func TestSinkReportsHTTPErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	if err := NewPostHogSink("bad", srv.URL).Send(context.Background(), testEvents()); err == nil {
+		t.Fatal("expected an error for a 401 response")
+	}
+}
@@ -0,0 +1,232 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+)
+
+// Compound File Binary (OLE2) is the container used by legacy .doc files.
+// Only the read paths needed to pull named streams out of a file are implemented.
+
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+const (
+	cfbEndOfChain   = 0xFFFFFFFE
+	cfbFreeSector   = 0xFFFFFFFF
+	cfbDirEntrySize = 128
+	cfbTypeStream   = 2
+	cfbTypeRoot     = 5
+	cfbMaxSectors   = 1 << 20
+)
+
+var errInvalidCFB = errors.New("invalid compound file")
+
+type cfbFile struct {
+	data          []byte
+	sectorSize    int
+	miniSectorSz  int
+	miniCutoff    uint32
+	fat           []uint32
+	miniFAT       []uint32
+	miniStream    []byte
+	entries       map[string]cfbEntry
+	rootStart     uint32
+	rootSize      uint64
+	firstMiniFAT  uint32
+	numMiniFATSec uint32
+}
+
+type cfbEntry struct {
+	name  string
+	typ   byte
+	start uint32
+	size  uint64
+}
+
+func isCFB(data []byte) bool {
+	return len(data) >= 512 && bytes.Equal(data[:8], cfbSignature)
+}
+
+func openCFB(data []byte) (*cfbFile, error) {
+	if !isCFB(data) {
+		return nil, errInvalidCFB
+	}
+	sectorShift := binary.LittleEndian.Uint16(data[0x1E:])
+	miniShift := binary.LittleEndian.Uint16(data[0x20:])
+	if sectorShift != 9 && sectorShift != 12 {
+		return nil, fmt.Errorf("%w: sector shift %d", errInvalidCFB, sectorShift)
+	}
+	f := &cfbFile{
+		data:          data,
+		sectorSize:    1 << sectorShift,
+		miniSectorSz:  1 << miniShift,
+		miniCutoff:    binary.LittleEndian.Uint32(data[0x38:]),
+		firstMiniFAT:  binary.LittleEndian.Uint32(data[0x3C:]),
+		numMiniFATSec: binary.LittleEndian.Uint32(data[0x40:]),
+	}
+	if err := f.loadFAT(); err != nil {
+		return nil, err
+	}
+	if err := f.loadDirectory(binary.LittleEndian.Uint32(data[0x30:])); err != nil {
+		return nil, err
+	}
+	if err := f.loadMiniStream(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *cfbFile) sector(id uint32) ([]byte, error) {
+	off := (int(id) + 1) * f.sectorSize
+	if id >= cfbMaxSectors || off < 0 || off+f.sectorSize > len(f.data) {
+		return nil, fmt.Errorf("%w: sector %d out of range", errInvalidCFB, id)
+	}
+	return f.data[off : off+f.sectorSize], nil
+}
+
+func (f *cfbFile) loadFAT() error {
+	numFAT := binary.LittleEndian.Uint32(f.data[0x2C:])
+	difat := make([]uint32, 0, numFAT)
+	for i := 0; i < 109 && uint32(len(difat)) < numFAT; i++ {
+		difat = append(difat, binary.LittleEndian.Uint32(f.data[0x4C+i*4:]))
+	}
+	next := binary.LittleEndian.Uint32(f.data[0x44:])
+	perSector := f.sectorSize/4 - 1
+	for guard := 0; next != cfbEndOfChain && next != cfbFreeSector && uint32(len(difat)) < numFAT; guard++ {
+		if guard > cfbMaxSectors {
+			return fmt.Errorf("%w: difat loop", errInvalidCFB)
+		}
+		sec, err := f.sector(next)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < perSector && uint32(len(difat)) < numFAT; i++ {
+			difat = append(difat, binary.LittleEndian.Uint32(sec[i*4:]))
+		}
+		next = binary.LittleEndian.Uint32(sec[perSector*4:])
+	}
+	for _, id := range difat {
+		sec, err := f.sector(id)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < f.sectorSize; i += 4 {
+			f.fat = append(f.fat, binary.LittleEndian.Uint32(sec[i:]))
+		}
+	}
+	return nil
+}
+
+// chain follows a FAT chain from start and concatenates its sectors.
+func (f *cfbFile) chain(start uint32) ([]byte, error) {
+	var out []byte
+	for id, steps := start, 0; id != cfbEndOfChain; steps++ {
+		if int(id) >= len(f.fat) || steps > len(f.fat) {
+			return nil, fmt.Errorf("%w: broken sector chain", errInvalidCFB)
+		}
+		sec, err := f.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sec...)
+		id = f.fat[id]
+	}
+	return out, nil
+}
+
+func (f *cfbFile) loadDirectory(start uint32) error {
+	raw, err := f.chain(start)
+	if err != nil {
+		return err
+	}
+	f.entries = make(map[string]cfbEntry)
+	for off := 0; off+cfbDirEntrySize <= len(raw); off += cfbDirEntrySize {
+		entry := raw[off : off+cfbDirEntrySize]
+		nameLen := int(binary.LittleEndian.Uint16(entry[0x40:]))
+		if nameLen < 2 || nameLen > 64 {
+			continue
+		}
+		units := make([]uint16, 0, nameLen/2-1)
+		for i := 0; i < nameLen-2; i += 2 {
+			units = append(units, binary.LittleEndian.Uint16(entry[i:]))
+		}
+		e := cfbEntry{
+			name:  string(utf16.Decode(units)),
+			typ:   entry[0x42],
+			start: binary.LittleEndian.Uint32(entry[0x74:]),
+			size:  binary.LittleEndian.Uint64(entry[0x78:]) & 0xFFFFFFFF,
+		}
+		if e.typ == cfbTypeRoot {
+			f.rootStart, f.rootSize = e.start, e.size
+			continue
+		}
+		if e.typ == cfbTypeStream {
+			if _, exists := f.entries[e.name]; !exists {
+				f.entries[e.name] = e
+			}
+		}
+	}
+	return nil
+}
+
+func (f *cfbFile) loadMiniStream() error {
+	if f.numMiniFATSec == 0 || f.firstMiniFAT == cfbEndOfChain {
+		return nil
+	}
+	raw, err := f.chain(f.firstMiniFAT)
+	if err != nil {
+		return err
+	}
+	for i := 0; i+4 <= len(raw); i += 4 {
+		f.miniFAT = append(f.miniFAT, binary.LittleEndian.Uint32(raw[i:]))
+	}
+	stream, err := f.chain(f.rootStart)
+	if err != nil {
+		return err
+	}
+	if uint64(len(stream)) > f.rootSize {
+		stream = stream[:f.rootSize]
+	}
+	f.miniStream = stream
+	return nil
+}
+
+// Stream returns the contents of the named top-level stream.
+func (f *cfbFile) Stream(name string) ([]byte, error) {
+	e, ok := f.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: stream %q not found", errInvalidCFB, name)
+	}
+	var raw []byte
+	if e.size < uint64(f.miniCutoff) {
+		for id, steps := e.start, 0; id != cfbEndOfChain; steps++ {
+			if int(id) >= len(f.miniFAT) || steps > len(f.miniFAT) {
+				return nil, fmt.Errorf("%w: broken mini chain", errInvalidCFB)
+			}
+			off := int(id) * f.miniSectorSz
+			if off+f.miniSectorSz > len(f.miniStream) {
+				return nil, fmt.Errorf("%w: mini sector out of range", errInvalidCFB)
+			}
+			raw = append(raw, f.miniStream[off:off+f.miniSectorSz]...)
+			id = f.miniFAT[id]
+		}
+	} else {
+		var err error
+		if raw, err = f.chain(e.start); err != nil {
+			return nil, err
+		}
+	}
+	if uint64(len(raw)) < e.size {
+		return nil, fmt.Errorf("%w: stream %q truncated", errInvalidCFB, name)
+	}
+	return raw[:e.size], nil
+}
+
+// HasStream reports whether a top-level stream exists.
+func (f *cfbFile) HasStream(name string) bool {
+	_, ok := f.entries[name]
+	return ok
+}
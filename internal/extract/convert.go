@@ -0,0 +1,152 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mimeDOC   = "application/msword"
+	mimePages = "application/vnd.apple.pages"
+)
+
+// ErrConversionUnavailable is returned when no converter can read a document.
+var ErrConversionUnavailable = errors.New("no converter available for document")
+
+// Converter turns a document format we cannot parse directly into plain text.
+type Converter interface {
+	Convert(ctx context.Context, data []byte, fileName string) (string, error)
+}
+
+// ConverterFunc adapts a function to the Converter interface.
+type ConverterFunc func(ctx context.Context, data []byte, fileName string) (string, error)
+
+// Convert calls f.
+func (f ConverterFunc) Convert(ctx context.Context, data []byte, fileName string) (string, error) {
+	return f(ctx, data, fileName)
+}
+
+// builtinConverters are the native parsers; they stay available as fallbacks.
+var builtinConverters = map[string]Converter{
+	mimeDOC:   ConverterFunc(extractDOC),
+	mimePages: ConverterFunc(extractPages),
+}
+
+var (
+	convertersMu sync.RWMutex
+	converters   = map[string]Converter{
+		mimeDOC:   builtinConverters[mimeDOC],
+		mimePages: builtinConverters[mimePages],
+	}
+	// customConverters marks types whose converter came from RegisterConverter.
+	customConverters = map[string]bool{}
+)
+
+// RegisterConverter replaces the converter used for mimeType. Passing nil removes it.
+func RegisterConverter(mimeType string, c Converter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	if c == nil {
+		delete(converters, mimeType)
+		delete(customConverters, mimeType)
+		return
+	}
+	converters[mimeType] = c
+	customConverters[mimeType] = true
+}
+
+// Method names how ExtractTextFromBytes reads a document of mimeType: "pdf" or
+// "docx" for the native parsers, "doc" or "pages" for the built-in converters,
+// "converter" when RegisterConverter replaced one, or "" when unsupported.
+func Method(mimeType string, fileName string) string {
+	normalized := normalizeMimeType(mimeType, fileName, nil)
+	switch normalized {
+	case mimePDF:
+		return "pdf"
+	case mimeDOCX:
+		return "docx"
+	}
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	if _, ok := converters[normalized]; !ok {
+		return ""
+	}
+	if customConverters[normalized] {
+		return "converter"
+	}
+	return strings.TrimPrefix(ExtensionForMime(normalized), ".")
+}
+
+func converterFor(mimeType string) (Converter, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	c, ok := converters[mimeType]
+	return c, ok
+}
+
+// CommandConverter pipes the document through an external tool (for example
+// antiword or a headless office suite) and reads plain text from stdout.
+type CommandConverter struct {
+	Path    string
+	Args    []string
+	Timeout time.Duration
+}
+
+// Convert runs the command with data on stdin.
+func (c CommandConverter) Convert(ctx context.Context, data []byte, fileName string) (string, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, c.Path, c.Args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("convert %s with %s: %w: %s", fileName, c.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// FallbackConverter tries each converter in order and returns the first success.
+type FallbackConverter []Converter
+
+// Convert returns the first successful conversion or the last error.
+func (f FallbackConverter) Convert(ctx context.Context, data []byte, fileName string) (string, error) {
+	err := ErrConversionUnavailable
+	for _, c := range f {
+		text, convErr := c.Convert(ctx, data, fileName)
+		if convErr == nil && strings.TrimSpace(text) != "" {
+			return text, nil
+		}
+		if convErr != nil {
+			err = convErr
+		}
+	}
+	return "", err
+}
+
+// ConfigureCommandConverters registers external tools for legacy formats. Each
+// command is split on whitespace; the built-in parser stays as the fallback.
+func ConfigureCommandConverters(docCommand, pagesCommand string, timeout time.Duration) {
+	for mimeType, command := range map[string]string{mimeDOC: docCommand, mimePages: pagesCommand} {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			continue
+		}
+		builtin := builtinConverters[mimeType]
+		chain := FallbackConverter{CommandConverter{Path: fields[0], Args: fields[1:], Timeout: timeout}}
+		if builtin != nil {
+			chain = append(chain, builtin)
+		}
+		RegisterConverter(mimeType, chain)
+	}
+}
@@ -0,0 +1,221 @@
+package extract
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// Word 97-2003 FIB offsets used to locate the piece table.
+const (
+	fibIdent        = 0xA5EC
+	fibFlagsOffset  = 0x0A
+	fibCcpTextOff   = 0x4C
+	fibFcClxOffset  = 0x01A2
+	fibLcbClxOffset = 0x01A6
+	fibMinSize      = 0x01AA
+
+	fibFlagWhichTable = 0x0200
+	fibFlagEncrypted  = 0x0100
+
+	pcdCompressedBit = 0x40000000
+)
+
+// ErrEncryptedDocument is returned for password-protected legacy Word files.
+var ErrEncryptedDocument = errors.New("document is encrypted")
+
+// extractDOC reads the main document text of a Word 97-2003 binary file via its piece table.
+func extractDOC(ctx context.Context, data []byte, _ string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	cfb, err := openCFB(data)
+	if err != nil {
+		return "", err
+	}
+	word, err := cfb.Stream("WordDocument")
+	if err != nil {
+		return "", err
+	}
+	if len(word) < fibMinSize || binary.LittleEndian.Uint16(word) != fibIdent {
+		return "", errors.New("unsupported word binary format")
+	}
+	flags := binary.LittleEndian.Uint16(word[fibFlagsOffset:])
+	if flags&fibFlagEncrypted != 0 {
+		return "", ErrEncryptedDocument
+	}
+	tableName := "0Table"
+	if flags&fibFlagWhichTable != 0 {
+		tableName = "1Table"
+	}
+	table, err := cfb.Stream(tableName)
+	if err != nil {
+		return "", err
+	}
+
+	ccpText := binary.LittleEndian.Uint32(word[fibCcpTextOff:])
+	fcClx := binary.LittleEndian.Uint32(word[fibFcClxOffset:])
+	lcbClx := binary.LittleEndian.Uint32(word[fibLcbClxOffset:])
+	if uint64(fcClx)+uint64(lcbClx) > uint64(len(table)) {
+		return "", errors.New("piece table out of range")
+	}
+
+	pieces, err := parsePieceTable(table[fcClx : fcClx+lcbClx])
+	if err != nil {
+		return "", err
+	}
+
+	var units []uint16
+	for _, p := range pieces {
+		if p.cpStart >= ccpText {
+			break
+		}
+		count := p.cpEnd - p.cpStart
+		if p.cpEnd > ccpText {
+			count = ccpText - p.cpStart
+		}
+		if p.compressed {
+			end := uint64(p.fc) + uint64(count)
+			if end > uint64(len(word)) {
+				return "", errors.New("text piece out of range")
+			}
+			for _, b := range word[p.fc:end] {
+				units = append(units, cp1252ToUnit(b))
+			}
+			continue
+		}
+		end := uint64(p.fc) + uint64(count)*2
+		if end > uint64(len(word)) {
+			return "", errors.New("text piece out of range")
+		}
+		for i := p.fc; uint64(i) < end; i += 2 {
+			units = append(units, binary.LittleEndian.Uint16(word[i:]))
+		}
+	}
+	return cleanWordText(string(utf16.Decode(units))), nil
+}
+
+type textPiece struct {
+	cpStart    uint32
+	cpEnd      uint32
+	fc         uint32
+	compressed bool
+}
+
+// parsePieceTable walks the Clx structure: zero or more Prc blocks followed by one Pcdt.
+func parsePieceTable(clx []byte) ([]textPiece, error) {
+	for i := 0; i < len(clx); {
+		switch clx[i] {
+		case 0x01:
+			if i+3 > len(clx) {
+				return nil, errors.New("truncated clx prc")
+			}
+			i += 3 + int(binary.LittleEndian.Uint16(clx[i+1:]))
+		case 0x02:
+			if i+5 > len(clx) {
+				return nil, errors.New("truncated clx pcdt")
+			}
+			lcb := int(binary.LittleEndian.Uint32(clx[i+1:]))
+			plc := clx[i+5:]
+			if lcb > len(plc) || lcb < 4 || (lcb-4)%12 != 0 {
+				return nil, errors.New("invalid piece table size")
+			}
+			n := (lcb - 4) / 12
+			pieces := make([]textPiece, 0, n)
+			for k := 0; k < n; k++ {
+				pcd := plc[(n+1)*4+k*8:]
+				fc := binary.LittleEndian.Uint32(pcd[2:])
+				piece := textPiece{
+					cpStart: binary.LittleEndian.Uint32(plc[k*4:]),
+					cpEnd:   binary.LittleEndian.Uint32(plc[(k+1)*4:]),
+					fc:      fc,
+				}
+				if fc&pcdCompressedBit != 0 {
+					piece.compressed = true
+					piece.fc = (fc &^ pcdCompressedBit) / 2
+				}
+				if piece.cpEnd < piece.cpStart {
+					return nil, errors.New("invalid piece boundaries")
+				}
+				pieces = append(pieces, piece)
+			}
+			return pieces, nil
+		default:
+			return nil, fmt.Errorf("unexpected clx marker 0x%02x", clx[i])
+		}
+	}
+	return nil, errors.New("piece table not found")
+}
+
+// cp1252High maps the 0x80-0x9F range, which differs from Latin-1.
+var cp1252High = [32]uint16{
+	0x20AC, 0xFFFD, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0xFFFD, 0x017D, 0xFFFD,
+	0xFFFD, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0xFFFD, 0x017E, 0x0178,
+}
+
+func cp1252ToUnit(b byte) uint16 {
+	if b >= 0x80 && b <= 0x9F {
+		return cp1252High[b-0x80]
+	}
+	return uint16(b)
+}
+
+// cleanWordText maps Word control characters to plain text and drops field instructions.
+func cleanWordText(raw string) string {
+	var buf strings.Builder
+	// fields nest; each entry records whether that field is still in its instruction part.
+	var fields []bool
+	inInstruction := func() bool {
+		for _, instr := range fields {
+			if instr {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range raw {
+		switch r {
+		case 0x13: // field begin
+			fields = append(fields, true)
+			continue
+		case 0x14: // field separator: the displayed result follows
+			if len(fields) > 0 {
+				fields[len(fields)-1] = false
+			}
+			continue
+		case 0x15: // field end
+			if len(fields) > 0 {
+				fields = fields[:len(fields)-1]
+			}
+			continue
+		}
+		if inInstruction() {
+			continue
+		}
+		switch r {
+		case '\r', 0x0B, 0x0C:
+			buf.WriteByte('\n')
+		case 0x07:
+			buf.WriteByte('\t')
+		case 0x1E:
+			buf.WriteByte('-')
+		case 0x1F, 0x01, 0x08:
+		case '\t', '\n':
+			buf.WriteRune(r)
+		default:
+			if r >= 0x20 {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,190 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// buildTestDOC assembles a minimal Word 97 compound file with one compressed
+// (cp1252) piece and one UTF-16 piece so both text encodings are exercised.
+func buildTestDOC(t *testing.T, ascii, unicode string) []byte {
+	t.Helper()
+	const sectorSize = 512
+	const textOffset = 0x800
+
+	word := make([]byte, 4096)
+	binary.LittleEndian.PutUint16(word[0:], fibIdent)
+	binary.LittleEndian.PutUint16(word[fibFlagsOffset:], fibFlagWhichTable)
+	copy(word[textOffset:], ascii)
+	utf16Off := textOffset + len(ascii)
+	if utf16Off%2 != 0 {
+		utf16Off++
+	}
+	units := utf16.Encode([]rune(unicode))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(word[utf16Off+i*2:], u)
+	}
+	ccp := uint32(len(ascii) + len(units))
+	binary.LittleEndian.PutUint32(word[fibCcpTextOff:], ccp)
+
+	var plc bytes.Buffer
+	for _, cp := range []uint32{0, uint32(len(ascii)), ccp} {
+		_ = binary.Write(&plc, binary.LittleEndian, cp)
+	}
+	for _, fc := range []uint32{uint32(textOffset*2) | pcdCompressedBit, uint32(utf16Off)} {
+		pcd := make([]byte, 8)
+		binary.LittleEndian.PutUint32(pcd[2:], fc)
+		plc.Write(pcd)
+	}
+	table := make([]byte, 4096)
+	table[0] = 0x02
+	binary.LittleEndian.PutUint32(table[1:], uint32(plc.Len()))
+	copy(table[5:], plc.Bytes())
+	binary.LittleEndian.PutUint32(word[fibFcClxOffset:], 0)
+	binary.LittleEndian.PutUint32(word[fibLcbClxOffset:], uint32(5+plc.Len()))
+
+	wordSectors := len(word) / sectorSize
+	tableSectors := len(table) / sectorSize
+	wordStart := uint32(2)
+	tableStart := wordStart + uint32(wordSectors)
+
+	fat := make([]uint32, sectorSize/4)
+	for i := range fat {
+		fat[i] = cfbFreeSector
+	}
+	fat[0] = 0xFFFFFFFD
+	fat[1] = cfbEndOfChain
+	chain := func(start uint32, n int) {
+		for i := 0; i < n; i++ {
+			fat[int(start)+i] = start + uint32(i) + 1
+		}
+		fat[int(start)+n-1] = cfbEndOfChain
+	}
+	chain(wordStart, wordSectors)
+	chain(tableStart, tableSectors)
+
+	header := make([]byte, sectorSize)
+	copy(header, cfbSignature)
+	binary.LittleEndian.PutUint16(header[0x18:], 0x3E)
+	binary.LittleEndian.PutUint16(header[0x1A:], 3)
+	binary.LittleEndian.PutUint16(header[0x1C:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[0x1E:], 9)
+	binary.LittleEndian.PutUint16(header[0x20:], 6)
+	binary.LittleEndian.PutUint32(header[0x2C:], 1)
+	binary.LittleEndian.PutUint32(header[0x30:], 1)
+	binary.LittleEndian.PutUint32(header[0x38:], 4096)
+	binary.LittleEndian.PutUint32(header[0x3C:], cfbEndOfChain)
+	binary.LittleEndian.PutUint32(header[0x44:], cfbEndOfChain)
+	for i := 0; i < 109; i++ {
+		binary.LittleEndian.PutUint32(header[0x4C+i*4:], cfbFreeSector)
+	}
+	binary.LittleEndian.PutUint32(header[0x4C:], 0)
+
+	dirEntry := func(name string, typ byte, start uint32, size uint64) []byte {
+		entry := make([]byte, cfbDirEntrySize)
+		units := utf16.Encode([]rune(name))
+		for i, u := range units {
+			binary.LittleEndian.PutUint16(entry[i*2:], u)
+		}
+		binary.LittleEndian.PutUint16(entry[0x40:], uint16((len(units)+1)*2))
+		entry[0x42] = typ
+		binary.LittleEndian.PutUint32(entry[0x74:], start)
+		binary.LittleEndian.PutUint64(entry[0x78:], size)
+		return entry
+	}
+	var dir bytes.Buffer
+	dir.Write(dirEntry("Root Entry", cfbTypeRoot, cfbEndOfChain, 0))
+	dir.Write(dirEntry("WordDocument", cfbTypeStream, wordStart, uint64(len(word))))
+	dir.Write(dirEntry("1Table", cfbTypeStream, tableStart, uint64(len(table))))
+	dir.Write(make([]byte, cfbDirEntrySize))
+
+	var out bytes.Buffer
+	out.Write(header)
+	for _, v := range fat {
+		_ = binary.Write(&out, binary.LittleEndian, v)
+	}
+	out.Write(dir.Bytes())
+	out.Write(word)
+	out.Write(table)
+	return out.Bytes()
+}
+
+func TestExtractTextFromBytes_DOC(t *testing.T) {
+	data := buildTestDOC(t, "Jane Doe\rSenior Engineer \x93Go\x94\r\x13 HYPERLINK x \x14jane@example.com\x15\r", "Résumé ✓")
+
+	for _, mimeType := range []string{"application/msword", "application/octet-stream", ""} {
+		text, err := ExtractTextFromBytes(context.Background(), data, mimeType, "resume.doc")
+		if err != nil {
+			t.Fatalf("mime %q: extract doc: %v", mimeType, err)
+		}
+		want := "Jane Doe\nSenior Engineer “Go”\njane@example.com\nRésumé ✓"
+		if text != want {
+			t.Fatalf("mime %q: unexpected text:\n%q\nwant\n%q", mimeType, text, want)
+		}
+	}
+	if got := DetectMimeType(data, "upload.bin"); got != mimeDOC {
+		t.Fatalf("expected msword detection from content, got %q", got)
+	}
+}
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractTextFromBytes_Pages(t *testing.T) {
+	legacy := buildZip(t, map[string]string{
+		"index.xml": `<sl:document xmlns:sl="http://developer.apple.com/namespaces/sl" xmlns:sf="http://developer.apple.com/namespaces/sf"><sf:text-body><sf:p>Jane Doe</sf:p><sf:p>Engineer at Acme</sf:p></sf:text-body></sl:document>`,
+	})
+	if got := DetectMimeType(legacy, "resume.pages"); got != mimePages {
+		t.Fatalf("expected pages detection, got %q", got)
+	}
+	text, err := ExtractTextFromBytes(context.Background(), legacy, "application/zip", "resume.pages")
+	if err != nil {
+		t.Fatalf("extract pages: %v", err)
+	}
+	if text != "Jane Doe\nEngineer at Acme" {
+		t.Fatalf("unexpected pages text: %q", text)
+	}
+
+	modern := buildZip(t, map[string]string{"Index/Document.iwa": "\x00binary"})
+	if _, err := ExtractTextFromBytes(context.Background(), modern, mimePages, "resume.pages"); err != ErrConversionUnavailable {
+		t.Fatalf("expected ErrConversionUnavailable for iwa-only bundle, got %v", err)
+	}
+}
+
+func TestRegisterConverterOverridesBuiltin(t *testing.T) {
+	builtin, _ := converterFor(mimePages)
+	t.Cleanup(func() { RegisterConverter(mimePages, builtin) })
+
+	modern := buildZip(t, map[string]string{"Index/Document.iwa": "\x00binary"})
+	RegisterConverter(mimePages, FallbackConverter{
+		builtin,
+		ConverterFunc(func(ctx context.Context, data []byte, fileName string) (string, error) {
+			return "converted " + fileName, nil
+		}),
+	})
+	text, err := ExtractTextFromBytes(context.Background(), modern, mimePages, "resume.pages")
+	if err != nil || !strings.HasPrefix(text, "converted") {
+		t.Fatalf("expected fallback converter output, got %q err=%v", text, err)
+	}
+}
@@ -23,6 +23,7 @@ const (
 
 // ExtractText pulls text from a stored object and persists a derived .extracted.txt copy.
 // Libraries used: github.com/ledongthuc/pdf (PDF) and github.com/nguyenthenguyen/docx (DOCX).
+// Legacy .doc and .pages uploads go through the registered Converter for their type.
 func ExtractText(ctx context.Context, store object.ObjectStore, fileKey string, mimeType string, fileName string) (string, error) {
 	if err := ctx.Err(); err != nil {
 		return "", err
@@ -63,9 +64,11 @@ func ExtractTextFromBytes(ctx context.Context, data []byte, mimeType string, fil
 		return extractPDF(data)
 	case mimeDOCX:
 		return extractDOCX(data)
-	default:
-		return "", fmt.Errorf("unsupported mime type: %s", normalized)
 	}
+	if c, ok := converterFor(normalized); ok {
+		return c.Convert(ctx, data, fileName)
+	}
+	return "", fmt.Errorf("unsupported mime type: %s", normalized)
 }
 
 type keySaver interface {
@@ -162,25 +165,28 @@ func stripDocxXML(raw string) string {
 
 func normalizeMimeType(mimeType string, fileName string, data []byte) string {
 	clean := strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
-	if clean != "application/zip" {
-		return clean
-	}
-
-	if mapped := mapOOXMLFromZip(data); mapped != "" {
-		return mapped
-	}
-
-	ext := strings.ToLower(filepath.Ext(fileName))
-	switch ext {
-	case ".docx":
-		return mimeDOCX
-	case ".xlsx":
-		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
-	case ".pptx":
-		return "application/vnd.openxmlformats-officedocument.presentationml.presentation"
-	default:
-		return clean
+	switch clean {
+	case "application/zip":
+		if mapped := mapOOXMLFromZip(data); mapped != "" {
+			return mapped
+		}
+		ext := strings.ToLower(filepath.Ext(fileName))
+		switch ext {
+		case ".docx":
+			return mimeDOCX
+		case ".xlsx":
+			return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		case ".pptx":
+			return "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+		}
+		return detectZipMimeType(data, ext)
+	case "", "application/octet-stream", "application/x-ole-storage", "application/x-cfb", "application/x-iwork-pages-sffpages":
+		// Generic types from upload sniffing; legacy Word and Pages land here.
+		if detected := DetectMimeType(data, fileName); detected != "application/octet-stream" {
+			return detected
+		}
 	}
+	return clean
 }
 
 func mapOOXMLFromZip(data []byte) string {
@@ -4,10 +4,15 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"resume-backend/internal/shared/faults"
+	"resume-backend/internal/shared/storage/object/local"
 )
 
 func TestExtractTextFromBytes_ZipDocxNormalizes(t *testing.T) {
@@ -44,3 +49,48 @@ func TestExtractTextFromBytes_RealZipRejected(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestMethodNamesParser(t *testing.T) {
+	cases := []struct{ mime, name, want string }{
+		{"application/pdf", "cv.pdf", "pdf"},
+		{"application/zip", "cv.docx", "docx"},
+		{"application/msword", "cv.doc", "doc"},
+		{"text/plain", "cv.txt", ""},
+	}
+	for _, tc := range cases {
+		if got := Method(tc.mime, tc.name); got != tc.want {
+			t.Fatalf("Method(%q, %q) = %q, want %q", tc.mime, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExtractTextThroughFaultStore(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "resume", "render", "testdata", "template.docx"))
+	if err != nil {
+		t.Fatalf("read test docx: %v", err)
+	}
+	ctx := context.Background()
+	// Latency alone enables the wrapper without failing any call.
+	store := faults.WrapStore(local.New(t.TempDir()), faults.Config{Latency: time.Millisecond})
+	if _, ok := store.(*faults.Store); !ok {
+		t.Fatalf("expected a fault store, got %T", store)
+	}
+	key, _, _, err := store.Save(ctx, "user-1", "cv.docx", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	text, err := ExtractText(ctx, store, key, mimeDOCX, "cv.docx")
+	if err != nil {
+		t.Fatalf("extract through fault store: %v", err)
+	}
+	rc, err := store.Open(ctx, key+".extracted.txt")
+	if err != nil {
+		t.Fatalf("open extracted text: %v", err)
+	}
+	defer rc.Close()
+	saved, err := io.ReadAll(rc)
+	if err != nil || string(saved) != text {
+		t.Fatalf("expected the extracted text saved, got %q err=%v", saved, err)
+	}
+}
@@ -0,0 +1,73 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DetectMimeType sniffs the document type from its content, using the file
+// extension only to disambiguate containers (zip, OLE) that hold several formats.
+func DetectMimeType(data []byte, fileName string) string {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return mimePDF
+	case isCFB(data):
+		if cfb, err := openCFB(data); err == nil && cfb.HasStream("WordDocument") {
+			return mimeDOC
+		}
+		if ext == ".doc" {
+			return mimeDOC
+		}
+		return "application/x-ole-storage"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return detectZipMimeType(data, ext)
+	}
+	if len(data) == 0 {
+		return "application/octet-stream"
+	}
+	return strings.Split(http.DetectContentType(data), ";")[0]
+}
+
+func detectZipMimeType(data []byte, ext string) string {
+	if mapped := mapOOXMLFromZip(data); mapped != "" {
+		return mapped
+	}
+	if zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err == nil && isPagesBundle(zr) {
+		return mimePages
+	}
+	switch ext {
+	case ".docx":
+		return mimeDOCX
+	case ".pages":
+		return mimePages
+	}
+	return "application/zip"
+}
+
+var mimeExtensions = map[string]string{
+	mimePDF:   ".pdf",
+	mimeDOCX:  ".docx",
+	mimeDOC:   ".doc",
+	mimePages: ".pages",
+}
+
+// ExtensionForMime returns the canonical extension for a supported document type, or "".
+func ExtensionForMime(mimeType string) string {
+	return mimeExtensions[mimeType]
+}
+
+// MimeForExtension returns the supported document type a file name's extension
+// advertises, or "" when the extension is not a supported document type.
+func MimeForExtension(fileName string) string {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for mimeType, known := range mimeExtensions {
+		if known == ext {
+			return mimeType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+// Pages bundles are zip archives. Pages '09 keeps the document as index.xml; newer
+// versions store snappy-compressed protobuf (.iwa) that we cannot read natively, so
+// we fall back to the embedded QuickLook preview PDF when one is present.
+const (
+	pagesIndexXML   = "index.xml"
+	pagesPreviewPDF = "QuickLook/Preview.pdf"
+	pagesIWAPrefix  = "Index/"
+)
+
+// extractPages pulls text from an Apple Pages bundle.
+func extractPages(ctx context.Context, data []byte, _ string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[strings.ReplaceAll(f.Name, "\\", "/")] = f
+	}
+
+	if f, ok := files[pagesIndexXML]; ok {
+		raw, err := readZipFile(f)
+		if err != nil {
+			return "", err
+		}
+		if text := stripDocxXML(string(raw)); text != "" {
+			return text, nil
+		}
+	}
+	for _, name := range []string{pagesPreviewPDF, "preview.pdf"} {
+		if f, ok := files[name]; ok {
+			raw, err := readZipFile(f)
+			if err != nil {
+				return "", err
+			}
+			return extractPDF(raw)
+		}
+	}
+	return "", ErrConversionUnavailable
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// isPagesBundle reports whether a zip archive looks like a Pages document.
+func isPagesBundle(zr *zip.Reader) bool {
+	for _, f := range zr.File {
+		name := strings.ReplaceAll(f.Name, "\\", "/")
+		if name == pagesIndexXML || name == pagesPreviewPDF ||
+			(strings.HasPrefix(name, pagesIWAPrefix) && strings.HasSuffix(name, ".iwa")) {
+			return true
+		}
+	}
+	return false
+}
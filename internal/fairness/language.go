@@ -0,0 +1,36 @@
+package fairness
+
+import (
+	"strings"
+
+	"resume-backend/internal/shared/language"
+)
+
+// LanguageUnknown is reported when the text is too short or no language clearly wins.
+const LanguageUnknown = language.Unknown
+
+// DetectLanguage guesses the dominant language of text from stopword frequencies.
+// Only the language code leaves this function; the text itself is not retained.
+func DetectLanguage(text string) string {
+	return language.Detect(text)
+}
+
+// Length buckets group documents by word count.
+const (
+	LengthShort  = "short"
+	LengthMedium = "medium"
+	LengthLong   = "long"
+)
+
+// LengthBucket classifies a document by its word count.
+func LengthBucket(text string) string {
+	n := len(strings.Fields(text))
+	switch {
+	case n < 300:
+		return LengthShort
+	case n < 800:
+		return LengthMedium
+	default:
+		return LengthLong
+	}
+}
@@ -0,0 +1,18 @@
+package fairness
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLengthBucket(t *testing.T) {
+	if got := LengthBucket(strings.Repeat("word ", 100)); got != LengthShort {
+		t.Fatalf("expected short, got %s", got)
+	}
+	if got := LengthBucket(strings.Repeat("word ", 500)); got != LengthMedium {
+		t.Fatalf("expected medium, got %s", got)
+	}
+	if got := LengthBucket(strings.Repeat("word ", 900)); got != LengthLong {
+		t.Fatalf("expected long, got %s", got)
+	}
+}
@@ -0,0 +1,204 @@
+package fairness
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Segment dimensions reported by the monitor.
+const (
+	DimensionLanguage = "language"
+	DimensionLength   = "length"
+)
+
+// Config tunes the monitoring job.
+type Config struct {
+	// Window is how far back completed analyses are sampled.
+	Window time.Duration
+	// MaxAnalyses caps how many analyses one run reads.
+	MaxAnalyses int
+	// MinSegmentSize suppresses statistics for segments with fewer analyses so small
+	// groups cannot be singled out.
+	MinSegmentSize int
+	// SkewThreshold is the score gap, in points, that raises an alert.
+	SkewThreshold float64
+}
+
+// DefaultConfig returns the settings used when none are supplied.
+func DefaultConfig() Config {
+	return Config{
+		Window:         14 * 24 * time.Hour,
+		MaxAnalyses:    5000,
+		MinSegmentSize: 20,
+		SkewThreshold:  8,
+	}
+}
+
+// AnalysisSource lists completed analyses for sampling.
+type AnalysisSource interface {
+	ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error)
+}
+
+// Monitor periodically aggregates analysis scores by segment. It keeps only the latest
+// aggregate report; no per-user or per-analysis data is retained between runs.
+type Monitor struct {
+	Source  AnalysisSource
+	DocRepo documents.DocumentsRepo
+	Store   object.ObjectStore
+	Config  Config
+	Now     func() time.Time
+
+	mu     sync.RWMutex
+	latest *Report
+}
+
+// NewMonitor constructs a Monitor with DefaultConfig.
+func NewMonitor(source AnalysisSource, docRepo documents.DocumentsRepo, store object.ObjectStore) *Monitor {
+	return &Monitor{Source: source, DocRepo: docRepo, Store: store, Config: DefaultConfig()}
+}
+
+// Latest returns the most recent report, if any run has completed.
+func (m *Monitor) Latest() (Report, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.latest == nil {
+		return Report{}, false
+	}
+	return *m.latest, true
+}
+
+// Stats returns the latest report for the admin stats endpoint, computing one if the
+// job has not run yet in this process.
+func (m *Monitor) Stats(ctx context.Context) (any, error) {
+	if report, ok := m.Latest(); ok {
+		return report, nil
+	}
+	return m.RunOnce(ctx)
+}
+
+// Run recomputes the report every interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			telemetry.Error("fairness.run_failed", map[string]any{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce samples recent completed analyses, segments them and stores the report.
+func (m *Monitor) RunOnce(ctx context.Context) (Report, error) {
+	if m.Source == nil {
+		return Report{}, errors.New("fairness monitor has no analysis source")
+	}
+	cfg := m.config()
+	now := m.now()
+	since := now.Add(-cfg.Window)
+
+	items, err := m.Source.ListCompletedSince(ctx, since, cfg.MaxAnalyses)
+	if err != nil {
+		return Report{}, err
+	}
+
+	samples := make([]sample, 0, len(items))
+	skipped := 0
+	for _, a := range items {
+		s, ok := m.sample(ctx, a)
+		if !ok {
+			skipped++
+			continue
+		}
+		samples = append(samples, s)
+	}
+
+	report := buildReport(samples, cfg)
+	report.GeneratedAt = now
+	report.WindowStart = since
+	report.Skipped = skipped
+
+	m.mu.Lock()
+	m.latest = &report
+	m.mu.Unlock()
+
+	telemetry.Info("fairness.run_completed", map[string]any{
+		"sampled": report.Sampled,
+		"skipped": report.Skipped,
+		"alerts":  report.alertCount(),
+	})
+	return report, nil
+}
+
+// sample reduces an analysis to its score and segment labels.
+func (m *Monitor) sample(ctx context.Context, a analyses.Analysis) (sample, bool) {
+	score, ok := analyses.FinalScore(a)
+	if !ok || m.DocRepo == nil || m.Store == nil {
+		return sample{}, false
+	}
+	doc, err := m.DocRepo.GetByID(ctx, a.UserID, a.DocumentID)
+	if err != nil || doc.ExtractedTextKey == "" {
+		return sample{}, false
+	}
+	text, err := readText(ctx, m.Store, doc.ExtractedTextKey)
+	if err != nil {
+		return sample{}, false
+	}
+	return sample{
+		cohort:   cohortKey{PromptVersion: a.PromptVersion, Model: a.Model, Mode: string(a.Mode)},
+		score:    score,
+		language: DetectLanguage(text),
+		length:   LengthBucket(text),
+		at:       *a.CompletedAt,
+	}, true
+}
+
+func (m *Monitor) config() Config {
+	cfg := m.Config
+	def := DefaultConfig()
+	if cfg.Window <= 0 {
+		cfg.Window = def.Window
+	}
+	if cfg.MaxAnalyses <= 0 {
+		cfg.MaxAnalyses = def.MaxAnalyses
+	}
+	if cfg.MinSegmentSize <= 0 {
+		cfg.MinSegmentSize = def.MinSegmentSize
+	}
+	if cfg.SkewThreshold <= 0 {
+		cfg.SkewThreshold = def.SkewThreshold
+	}
+	return cfg
+}
+
+func (m *Monitor) now() time.Time {
+	if m.Now != nil {
+		return m.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func readText(ctx context.Context, store object.ObjectStore, key string) (string, error) {
+	body, err := store.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
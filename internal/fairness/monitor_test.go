@@ -0,0 +1,158 @@
+package fairness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+const (
+	englishResume = "Led the migration of the billing platform to Go and reduced latency for the payments team. "
+	spanishResume = "Lideré la migración de la plataforma de pagos y reduje la latencia del sistema con el equipo. "
+)
+
+type fixture struct {
+	t        *testing.T
+	docs     *documents.MemoryRepo
+	analyses *analyses.MemoryRepo
+	store    object.ObjectStore
+	now      time.Time
+	n        int
+}
+
+func newFixture(t *testing.T) *fixture {
+	return &fixture{
+		t:        t,
+		docs:     documents.NewMemoryRepo(),
+		analyses: analyses.NewMemoryRepo(),
+		store:    local.New(t.TempDir()),
+		now:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fixture) add(promptVersion, text string, score float64, completedAt time.Time) {
+	f.t.Helper()
+	ctx := context.Background()
+	f.n++
+	userID := fmt.Sprintf("user-%d", f.n)
+	key, _, _, err := f.store.Save(ctx, userID, "resume.txt", bytes.NewReader([]byte(strings.Repeat(text, 6))))
+	if err != nil {
+		f.t.Fatalf("save text: %v", err)
+	}
+	doc := documents.Document{ID: fmt.Sprintf("doc-%d", f.n), UserID: userID, FileName: "resume.pdf", ExtractedTextKey: key, CreatedAt: completedAt}
+	if err := f.docs.Create(ctx, doc); err != nil {
+		f.t.Fatalf("create doc: %v", err)
+	}
+	at := completedAt
+	if err := f.analyses.Create(ctx, analyses.Analysis{
+		ID:            fmt.Sprintf("analysis-%d", f.n),
+		DocumentID:    doc.ID,
+		UserID:        userID,
+		PromptVersion: promptVersion,
+		Model:         "gpt-test",
+		Mode:          analyses.ModeJobMatch,
+		Status:        analyses.StatusCompleted,
+		Result:        map[string]any{"finalScore": score},
+		CompletedAt:   &at,
+	}); err != nil {
+		f.t.Fatalf("create analysis: %v", err)
+	}
+}
+
+func TestMonitorFlagsSkewIntroducedByPromptChange(t *testing.T) {
+	f := newFixture(t)
+	old := f.now.Add(-5 * 24 * time.Hour)
+	recent := f.now.Add(-24 * time.Hour)
+	for i := 0; i < 4; i++ {
+		// Before the change both languages score alike.
+		f.add("v2_2", englishResume, 70, old)
+		f.add("v2_2", spanishResume, 70, old)
+		// After it, Spanish resumes drop sharply.
+		f.add("v2_3", englishResume, 72, recent)
+		f.add("v2_3", spanishResume, 50, recent)
+	}
+	// Far outside the window; must not be sampled.
+	f.add("v2_1", englishResume, 10, f.now.Add(-60*24*time.Hour))
+
+	monitor := NewMonitor(f.analyses, f.docs, f.store)
+	monitor.Config.MinSegmentSize = 3
+	monitor.Now = func() time.Time { return f.now }
+
+	report, err := monitor.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Sampled != 16 || len(report.Cohorts) != 2 {
+		t.Fatalf("expected 16 samples in 2 cohorts, got %d in %d", report.Sampled, len(report.Cohorts))
+	}
+
+	latest := report.Cohorts[0]
+	if latest.PromptVersion != "v2_3" {
+		t.Fatalf("expected newest cohort first, got %s", latest.PromptVersion)
+	}
+	var spanish *SegmentStats
+	for i := range latest.Segments {
+		if latest.Segments[i].Dimension == DimensionLanguage && latest.Segments[i].Value == "es" {
+			spanish = &latest.Segments[i]
+		}
+	}
+	if spanish == nil || spanish.Gap != -11 || spanish.GapShift == nil || *spanish.GapShift != -11 {
+		t.Fatalf("unexpected spanish segment: %+v", spanish)
+	}
+	found := false
+	for _, alert := range latest.Alerts {
+		if alert.Value == "es" && alert.Reason == ReasonGapShift {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected gap shift alert for es, got %+v", latest.Alerts)
+	}
+	if len(report.Cohorts[1].Alerts) != 0 {
+		t.Fatalf("expected no alerts before the change, got %+v", report.Cohorts[1].Alerts)
+	}
+
+	// The report is aggregate-only.
+	payload, _ := json.Marshal(report)
+	if strings.Contains(string(payload), "user-") || strings.Contains(string(payload), "analysis-") {
+		t.Fatalf("report leaks identifiers: %s", payload)
+	}
+	if cached, ok := monitor.Latest(); !ok || cached.Sampled != report.Sampled {
+		t.Fatalf("expected report to be cached")
+	}
+}
+
+func TestMonitorSuppressesSmallSegments(t *testing.T) {
+	f := newFixture(t)
+	for i := 0; i < 5; i++ {
+		f.add("v2_3", englishResume, 80, f.now.Add(-time.Hour))
+	}
+	f.add("v2_3", spanishResume, 20, f.now.Add(-time.Hour))
+
+	monitor := NewMonitor(f.analyses, f.docs, f.store)
+	monitor.Config.MinSegmentSize = 3
+	monitor.Now = func() time.Time { return f.now }
+	report, err := monitor.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for _, seg := range report.Cohorts[0].Segments {
+		if seg.Value == "es" && (!seg.Suppressed || seg.MeanScore != 0 || seg.Histogram != nil) {
+			t.Fatalf("expected small segment to be suppressed: %+v", seg)
+		}
+	}
+	for _, alert := range report.Cohorts[0].Alerts {
+		if alert.Value == "es" {
+			t.Fatalf("suppressed segment must not raise alerts")
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package fairness
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const histogramBuckets = 10
+
+// Report is the aggregate output of one monitoring run.
+type Report struct {
+	GeneratedAt    time.Time      `json:"generatedAt"`
+	WindowStart    time.Time      `json:"windowStart"`
+	Sampled        int            `json:"sampled"`
+	Skipped        int            `json:"skipped"`
+	MinSegmentSize int            `json:"minSegmentSize"`
+	SkewThreshold  float64        `json:"skewThreshold"`
+	Cohorts        []CohortReport `json:"cohorts"`
+}
+
+// CohortReport covers analyses produced by one prompt version, model and mode,
+// newest cohort first so a prompt or model change shows up at the top.
+type CohortReport struct {
+	PromptVersion string         `json:"promptVersion"`
+	Model         string         `json:"model"`
+	Mode          string         `json:"mode"`
+	Count         int            `json:"count"`
+	MeanScore     float64        `json:"meanScore"`
+	Segments      []SegmentStats `json:"segments"`
+	Alerts        []Alert        `json:"alerts,omitempty"`
+}
+
+// SegmentStats is the score distribution of one segment within a cohort. Segments
+// below the minimum size only report their count.
+type SegmentStats struct {
+	Dimension  string  `json:"dimension"`
+	Value      string  `json:"value"`
+	Count      int     `json:"count"`
+	Suppressed bool    `json:"suppressed,omitempty"`
+	MeanScore  float64 `json:"meanScore,omitempty"`
+	P10        float64 `json:"p10,omitempty"`
+	P50        float64 `json:"p50,omitempty"`
+	P90        float64 `json:"p90,omitempty"`
+	// Histogram counts scores in ten-point buckets from 0-9 to 90-100.
+	Histogram []int `json:"histogram,omitempty"`
+	// Gap is the segment mean minus the cohort mean.
+	Gap float64 `json:"gap"`
+	// GapShift is the change in Gap from the previous cohort of the same mode.
+	GapShift *float64 `json:"gapShift,omitempty"`
+}
+
+// Alert flags a segment whose scores look systematically skewed.
+type Alert struct {
+	Dimension string  `json:"dimension"`
+	Value     string  `json:"value"`
+	Reason    string  `json:"reason"`
+	Gap       float64 `json:"gap"`
+	GapShift  float64 `json:"gapShift,omitempty"`
+}
+
+// Alert reasons.
+const (
+	ReasonPersistentGap = "segment_gap"
+	ReasonGapShift      = "gap_shift_after_change"
+)
+
+type cohortKey struct {
+	PromptVersion string
+	Model         string
+	Mode          string
+}
+
+type sample struct {
+	cohort   cohortKey
+	score    float64
+	language string
+	length   string
+	at       time.Time
+}
+
+type segmentKey struct {
+	dimension string
+	value     string
+}
+
+func buildReport(samples []sample, cfg Config) Report {
+	report := Report{
+		Sampled:        len(samples),
+		MinSegmentSize: cfg.MinSegmentSize,
+		SkewThreshold:  cfg.SkewThreshold,
+		Cohorts:        []CohortReport{},
+	}
+
+	byCohort := make(map[cohortKey][]sample)
+	latest := make(map[cohortKey]time.Time)
+	for _, s := range samples {
+		byCohort[s.cohort] = append(byCohort[s.cohort], s)
+		if s.at.After(latest[s.cohort]) {
+			latest[s.cohort] = s.at
+		}
+	}
+	keys := make([]cohortKey, 0, len(byCohort))
+	for key := range byCohort {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return latest[keys[i]].After(latest[keys[j]])
+	})
+
+	for _, key := range keys {
+		report.Cohorts = append(report.Cohorts, buildCohort(key, byCohort[key], cfg))
+	}
+
+	// Compare each cohort with the next older one in the same mode.
+	for i := range report.Cohorts {
+		current := &report.Cohorts[i]
+		for j := i + 1; j < len(report.Cohorts); j++ {
+			if report.Cohorts[j].Mode == current.Mode {
+				applyShift(current, report.Cohorts[j])
+				break
+			}
+		}
+		current.Alerts = alertsFor(*current, cfg.SkewThreshold)
+	}
+	return report
+}
+
+func buildCohort(key cohortKey, samples []sample, cfg Config) CohortReport {
+	scores := make([]float64, len(samples))
+	segments := make(map[segmentKey][]float64)
+	for i, s := range samples {
+		scores[i] = s.score
+		segments[segmentKey{DimensionLanguage, s.language}] = append(segments[segmentKey{DimensionLanguage, s.language}], s.score)
+		segments[segmentKey{DimensionLength, s.length}] = append(segments[segmentKey{DimensionLength, s.length}], s.score)
+	}
+	cohort := CohortReport{
+		PromptVersion: key.PromptVersion,
+		Model:         key.Model,
+		Mode:          key.Mode,
+		Count:         len(samples),
+		MeanScore:     round1(mean(scores)),
+	}
+
+	segKeys := make([]segmentKey, 0, len(segments))
+	for k := range segments {
+		segKeys = append(segKeys, k)
+	}
+	sort.Slice(segKeys, func(i, j int) bool {
+		if segKeys[i].dimension != segKeys[j].dimension {
+			return segKeys[i].dimension < segKeys[j].dimension
+		}
+		return segKeys[i].value < segKeys[j].value
+	})
+	for _, k := range segKeys {
+		values := segments[k]
+		stats := SegmentStats{Dimension: k.dimension, Value: k.value, Count: len(values)}
+		if len(values) < cfg.MinSegmentSize {
+			stats.Suppressed = true
+		} else {
+			sort.Float64s(values)
+			stats.MeanScore = round1(mean(values))
+			stats.P10 = percentile(values, 0.10)
+			stats.P50 = percentile(values, 0.50)
+			stats.P90 = percentile(values, 0.90)
+			stats.Histogram = histogram(values)
+			stats.Gap = round1(mean(values) - mean(scores))
+		}
+		cohort.Segments = append(cohort.Segments, stats)
+	}
+	return cohort
+}
+
+func applyShift(current *CohortReport, previous CohortReport) {
+	prior := make(map[segmentKey]SegmentStats, len(previous.Segments))
+	for _, seg := range previous.Segments {
+		prior[segmentKey{seg.Dimension, seg.Value}] = seg
+	}
+	for i := range current.Segments {
+		seg := &current.Segments[i]
+		before, ok := prior[segmentKey{seg.Dimension, seg.Value}]
+		if !ok || seg.Suppressed || before.Suppressed {
+			continue
+		}
+		shift := round1(seg.Gap - before.Gap)
+		seg.GapShift = &shift
+	}
+}
+
+func alertsFor(cohort CohortReport, threshold float64) []Alert {
+	var alerts []Alert
+	for _, seg := range cohort.Segments {
+		if seg.Suppressed {
+			continue
+		}
+		if seg.GapShift != nil && math.Abs(*seg.GapShift) >= threshold {
+			alerts = append(alerts, Alert{Dimension: seg.Dimension, Value: seg.Value, Reason: ReasonGapShift, Gap: seg.Gap, GapShift: *seg.GapShift})
+			continue
+		}
+		if math.Abs(seg.Gap) >= threshold {
+			alerts = append(alerts, Alert{Dimension: seg.Dimension, Value: seg.Value, Reason: ReasonPersistentGap, Gap: seg.Gap})
+		}
+	}
+	return alerts
+}
+
+func (r Report) alertCount() int {
+	n := 0
+	for _, c := range r.Cohorts {
+		n += len(c.Alerts)
+	}
+	return n
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentile uses nearest-rank on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func histogram(values []float64) []int {
+	buckets := make([]int, histogramBuckets)
+	for _, v := range values {
+		i := int(v / 10)
+		if i < 0 {
+			i = 0
+		}
+		if i >= histogramBuckets {
+			i = histogramBuckets - 1
+		}
+		buckets[i]++
+	}
+	return buckets
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,17 @@
+package featureflags
+
+import "errors"
+
+var (
+	// ErrNotFound indicates no stored rule exists for the flag.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrUnknownFlag indicates a key that no flag was registered under.
+	ErrUnknownFlag = errors.New("unknown feature flag")
+
+	// ErrReadOnly indicates rules cannot be edited because no database is configured.
+	ErrReadOnly = errors.New("feature flag rules are read-only")
+)
@@ -0,0 +1,95 @@
+package featureflags
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the feature flag admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches feature flag routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/feature-flags", h.list)
+	rg.GET("/feature-flags/:key/evaluate", h.evaluate)
+	rg.PUT("/feature-flags/:key", h.set)
+	rg.DELETE("/feature-flags/:key", h.delete)
+}
+
+func (h *Handler) list(c *gin.Context) {
+	respond.JSON(c, http.StatusOK, gin.H{"flags": h.Svc.List(c.Request.Context())})
+}
+
+// evaluate shows what a user or organization gets, for checking a cohort
+// before or after an edit.
+func (h *Handler) evaluate(c *gin.Context) {
+	flag, ok := Lookup(c.Param("key"))
+	if !ok {
+		writeError(c, ErrUnknownFlag)
+		return
+	}
+	target := Target{UserID: c.Query("userId"), OrgID: c.Query("orgId")}
+	respond.JSON(c, http.StatusOK, h.Svc.Evaluate(c.Request.Context(), flag, target))
+}
+
+type setRequest struct {
+	Enabled *bool    `json:"enabled"`
+	Percent int      `json:"percent"`
+	Users   []string `json:"users"`
+	Orgs    []string `json:"orgs"`
+}
+
+func (h *Handler) set(c *gin.Context) {
+	var req setRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	rule, err := h.Svc.Set(c.Request.Context(), middleware.UserIDFromContext(c), Rule{
+		Key:     c.Param("key"),
+		Enabled: req.Enabled,
+		Percent: req.Percent,
+		Users:   req.Users,
+		Orgs:    req.Orgs,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, rule)
+}
+
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.Svc.Delete(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("key")); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownFlag):
+		respond.Error(c, http.StatusNotFound, "not_found", "feature flag not found", nil)
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "no stored rule for this feature flag", nil)
+	case errors.Is(err, ErrReadOnly):
+		respond.Error(c, http.StatusConflict, "read_only", "feature flag rules cannot be edited without a database", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to process feature flag", nil)
+	}
+}
@@ -0,0 +1,158 @@
+package featureflags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Flag is a boolean feature switch. Flags are declared once with Register and
+// passed by value to Service.Enabled, so a typo is a compile error rather than a
+// flag that is silently off.
+type Flag struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	// Default applies when no provider has a rule for the flag, or when the
+	// flags service is not configured.
+	Default bool `json:"default"`
+}
+
+// Flags the services read. New risky features start with Default false and are
+// enabled per cohort through rules.
+var (
+	EnsembleScoring = Register(Flag{
+		Key:         "ensemble_scoring",
+		Description: "Score each analysis twice and store the mean of the two scores.",
+	})
+	StructuredOutput = Register(Flag{
+		Key:         "structured_output",
+		Description: "Ask OpenAI for JSON-schema structured output instead of plain JSON mode.",
+	})
+	RedlineRenderer = Register(Flag{
+		Key:         "redline_renderer",
+		Description: "Allow apply runs to store a tracked-changes copy of the resume.",
+		Default:     true,
+	})
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,63}$`)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Flag{}
+)
+
+// Register declares a flag. It panics on an invalid or duplicate key, since
+// flags are declared at package initialization.
+func Register(flag Flag) Flag {
+	if !keyPattern.MatchString(flag.Key) {
+		panic(fmt.Sprintf("featureflags: invalid key %q", flag.Key))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[flag.Key]; ok {
+		panic(fmt.Sprintf("featureflags: duplicate key %q", flag.Key))
+	}
+	registry[flag.Key] = flag
+	return flag
+}
+
+// Lookup returns the flag registered under key.
+func Lookup(key string) (Flag, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	flag, ok := registry[key]
+	return flag, ok
+}
+
+// All returns every registered flag, sorted by key.
+func All() []Flag {
+	registryMu.RLock()
+	out := make([]Flag, 0, len(registry))
+	for _, flag := range registry {
+		out = append(out, flag)
+	}
+	registryMu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// Target is who a flag is evaluated for. Either field may be empty.
+type Target struct {
+	UserID string
+	OrgID  string
+}
+
+// Rule targets a flag. Listed users and organizations always get the flag;
+// other users are enabled by Percent, a stable hash of the user ID; everyone
+// else gets Enabled, or the flag's default when Enabled is nil.
+type Rule struct {
+	Key       string    `json:"key"`
+	Enabled   *bool     `json:"enabled,omitempty"`
+	Percent   int       `json:"percent,omitempty"`
+	Users     []string  `json:"users,omitempty"`
+	Orgs      []string  `json:"orgs,omitempty"`
+	UpdatedBy string    `json:"updatedBy,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// Validate checks a rule for a registered flag.
+func (r Rule) Validate() error {
+	if _, ok := Lookup(r.Key); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownFlag, r.Key)
+	}
+	if r.Percent < 0 || r.Percent > 100 {
+		return fmt.Errorf("%w: percent must be within 0-100", ErrInvalidInput)
+	}
+	for _, id := range append(slices.Clone(r.Users), r.Orgs...) {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("%w: user and org IDs must not be empty", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// Decision is the outcome of evaluating a flag for a target.
+type Decision struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+	// Source is the provider whose rule decided, or "default".
+	Source string `json:"source"`
+	// Reason is "user", "org", "percent", "rule" or "default".
+	Reason string `json:"reason"`
+}
+
+// evaluate applies rule to target; a nil rule means the flag's default.
+func evaluate(flag Flag, rule *Rule, source string, target Target) Decision {
+	d := Decision{Key: flag.Key, Enabled: flag.Default, Source: "default", Reason: "default"}
+	if rule == nil {
+		return d
+	}
+	d.Source = source
+	switch {
+	case target.UserID != "" && slices.Contains(rule.Users, target.UserID):
+		d.Enabled, d.Reason = true, "user"
+	case target.OrgID != "" && slices.Contains(rule.Orgs, target.OrgID):
+		d.Enabled, d.Reason = true, "org"
+	case target.UserID != "" && rule.Percent > 0 && bucket(flag.Key, target.UserID) < rule.Percent:
+		d.Enabled, d.Reason = true, "percent"
+	case rule.Enabled != nil:
+		d.Enabled, d.Reason = *rule.Enabled, "rule"
+	default:
+		d.Reason = "default"
+	}
+	return d
+}
+
+// bucket maps a user to 0-99. The flag key is part of the hash so the same
+// users are not first in line for every flag.
+func bucket(key, userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,130 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider supplies flag rules from one source.
+type Provider interface {
+	// Name identifies the provider in decisions and logs.
+	Name() string
+	// Rules returns the provider's rules keyed by flag key.
+	Rules(ctx context.Context) (map[string]Rule, error)
+}
+
+// ParseRules decodes a JSON object of flag key to rule, the format of
+// FEATURE_FLAGS and of the remote provider. Unknown keys are rejected so a
+// misspelled flag fails loudly.
+func ParseRules(data []byte) (map[string]Rule, error) {
+	var raw map[string]Rule
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	out := make(map[string]Rule, len(raw))
+	for key, rule := range raw {
+		rule.Key = key
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		out[key] = rule
+	}
+	return out, nil
+}
+
+// EnvProvider serves rules fixed at startup, from the FEATURE_FLAGS variable.
+type EnvProvider struct {
+	rules map[string]Rule
+}
+
+// NewEnvProvider parses FEATURE_FLAGS. An empty value has no rules.
+func NewEnvProvider(raw string) (*EnvProvider, error) {
+	p := &EnvProvider{rules: map[string]Rule{}}
+	if strings.TrimSpace(raw) == "" {
+		return p, nil
+	}
+	rules, err := ParseRules([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("FEATURE_FLAGS: %w", err)
+	}
+	p.rules = rules
+	return p, nil
+}
+
+// Name implements Provider.
+func (p *EnvProvider) Name() string { return "env" }
+
+// Rules implements Provider.
+func (p *EnvProvider) Rules(context.Context) (map[string]Rule, error) {
+	return p.rules, nil
+}
+
+// maxRemoteBody caps the size of a remote rules document.
+const maxRemoteBody = 1 << 20
+
+// RemoteProvider fetches rules from an HTTP endpoint serving the FEATURE_FLAGS
+// JSON format, so flags can be managed outside the service.
+type RemoteProvider struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewRemoteProvider constructs a RemoteProvider with a short timeout.
+func NewRemoteProvider(url string) *RemoteProvider {
+	return &RemoteProvider{URL: url, HTTPClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Name implements Provider.
+func (p *RemoteProvider) Name() string { return "remote" }
+
+// Rules implements Provider.
+func (p *RemoteProvider) Rules(ctx context.Context) (map[string]Rule, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote feature flags: http status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteBody))
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(body)
+}
+
+// RepoProvider serves the rules stored through the admin API.
+type RepoProvider struct {
+	Repo Repo
+}
+
+// Name implements Provider.
+func (p *RepoProvider) Name() string { return "db" }
+
+// Rules implements Provider.
+func (p *RepoProvider) Rules(ctx context.Context) (map[string]Rule, error) {
+	rules, err := p.Repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		out[rule.Key] = rule
+	}
+	return out, nil
+}
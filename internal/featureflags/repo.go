@@ -0,0 +1,12 @@
+package featureflags
+
+import "context"
+
+// Repo persists flag rules edited through the admin API. Rules live in the
+// database so the API and worker processes evaluate the same targeting.
+type Repo interface {
+	List(ctx context.Context) ([]Rule, error)
+	Upsert(ctx context.Context, rule Rule) error
+	// Delete removes a rule, failing with ErrNotFound if there is none.
+	Delete(ctx context.Context, key string) error
+}
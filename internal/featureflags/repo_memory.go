@@ -0,0 +1,71 @@
+package featureflags
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo stores rules in memory.
+type MemoryRepo struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewMemoryRepo constructs an empty MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{rules: map[string]Rule{}}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// List returns every rule, sorted by key.
+func (r *MemoryRepo) List(ctx context.Context) ([]Rule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		out = append(out, cloneRule(rule))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Upsert creates or replaces a rule.
+func (r *MemoryRepo) Upsert(ctx context.Context, rule Rule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[rule.Key] = cloneRule(rule)
+	return nil
+}
+
+// Delete removes a rule.
+func (r *MemoryRepo) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.rules, key)
+	return nil
+}
+
+func cloneRule(rule Rule) Rule {
+	if rule.Enabled != nil {
+		enabled := *rule.Enabled
+		rule.Enabled = &enabled
+	}
+	rule.Users = slices.Clone(rule.Users)
+	rule.Orgs = slices.Clone(rule.Orgs)
+	return rule
+}
@@ -0,0 +1,111 @@
+package featureflags
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// List returns every rule, sorted by key.
+func (r *PGRepo) List(ctx context.Context) ([]Rule, error) {
+	const query = `
+SELECT key, enabled, percent, users, orgs, updated_by, updated_at
+FROM feature_flags
+ORDER BY key`
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rule
+	for rows.Next() {
+		var (
+			rule        Rule
+			enabled     sql.NullBool
+			users, orgs []byte
+		)
+		if err := rows.Scan(&rule.Key, &enabled, &rule.Percent, &users, &orgs, &rule.UpdatedBy, &rule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if enabled.Valid {
+			rule.Enabled = &enabled.Bool
+		}
+		if err := json.Unmarshal(users, &rule.Users); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(orgs, &rule.Orgs); err != nil {
+			return nil, err
+		}
+		out = append(out, rule)
+	}
+	return out, rows.Err()
+}
+
+// Upsert creates or replaces a rule.
+func (r *PGRepo) Upsert(ctx context.Context, rule Rule) error {
+	const query = `
+INSERT INTO feature_flags (
+    key,
+    enabled,
+    percent,
+    users,
+    orgs,
+    updated_by,
+    updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (key) DO UPDATE SET
+    enabled = EXCLUDED.enabled,
+    percent = EXCLUDED.percent,
+    users = EXCLUDED.users,
+    orgs = EXCLUDED.orgs,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = EXCLUDED.updated_at`
+	users, err := json.Marshal(nonNil(rule.Users))
+	if err != nil {
+		return err
+	}
+	orgs, err := json.Marshal(nonNil(rule.Orgs))
+	if err != nil {
+		return err
+	}
+	var enabled sql.NullBool
+	if rule.Enabled != nil {
+		enabled = sql.NullBool{Bool: *rule.Enabled, Valid: true}
+	}
+	_, err = r.DB.ExecContext(ctx, query,
+		rule.Key,
+		enabled,
+		rule.Percent,
+		users,
+		orgs,
+		rule.UpdatedBy,
+		rule.UpdatedAt,
+	)
+	return err
+}
+
+// Delete removes a rule.
+func (r *PGRepo) Delete(ctx context.Context, key string) error {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM feature_flags WHERE key = $1`, key)
+	if err != nil {
+		return err
+	}
+	if deleted, _ := res.RowsAffected(); deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
@@ -0,0 +1,209 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// DefaultCacheTTL is how long provider rules are reused before they are read
+// again, so evaluating a flag per request does not query the database or the
+// remote endpoint each time.
+const DefaultCacheTTL = 30 * time.Second
+
+// Service evaluates flags against the rules of its providers. Providers are
+// consulted in order and the first one with a rule for a flag decides it. A nil
+// Service evaluates every flag to its default.
+type Service struct {
+	Providers []Provider
+	// Repo stores the rules edited through the admin API; nil makes them
+	// read-only. Its provider should be among Providers.
+	Repo Repo
+	// Audit records rule edits; nil skips auditing.
+	Audit    *audit.Service
+	CacheTTL time.Duration
+	Now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedRules
+}
+
+type cachedRules struct {
+	rules     map[string]Rule
+	fetchedAt time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, auditSvc *audit.Service, providers ...Provider) *Service {
+	return &Service{Providers: providers, Repo: repo, Audit: auditSvc, CacheTTL: DefaultCacheTTL}
+}
+
+// Enabled reports whether flag is on for target.
+func (s *Service) Enabled(ctx context.Context, flag Flag, target Target) bool {
+	return s.Evaluate(ctx, flag, target).Enabled
+}
+
+// Evaluate decides flag for target and reports which rule decided it.
+func (s *Service) Evaluate(ctx context.Context, flag Flag, target Target) Decision {
+	if s == nil {
+		return evaluate(flag, nil, "", target)
+	}
+	for _, p := range s.Providers {
+		if rule, ok := s.rules(ctx, p)[flag.Key]; ok {
+			return evaluate(flag, &rule, p.Name(), target)
+		}
+	}
+	return evaluate(flag, nil, "", target)
+}
+
+// rules returns a provider's cached rules, refreshing them once stale. When a
+// refresh fails the last rules read are kept.
+func (s *Service) rules(ctx context.Context, p Provider) map[string]Rule {
+	name := p.Name()
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.cache[name]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < s.cacheTTL() {
+		return cached.rules
+	}
+	rules, err := p.Rules(ctx)
+	if err != nil {
+		telemetry.Error("featureflags.provider_failed", map[string]any{
+			"provider": name,
+			"error":    err.Error(),
+		})
+		rules = cached.rules
+	}
+	s.mu.Lock()
+	if s.cache == nil {
+		s.cache = map[string]cachedRules{}
+	}
+	s.cache[name] = cachedRules{rules: rules, fetchedAt: now}
+	s.mu.Unlock()
+	return rules
+}
+
+// FlagView describes a flag for the admin API.
+type FlagView struct {
+	Flag
+	// Rules holds each provider's rule for the flag, keyed by provider name.
+	Rules map[string]Rule `json:"rules"`
+	// Source is the provider whose rule is in effect, or "default".
+	Source string `json:"source"`
+}
+
+// List describes every registered flag and the rules that apply to it.
+func (s *Service) List(ctx context.Context) []FlagView {
+	flags := All()
+	out := make([]FlagView, 0, len(flags))
+	for _, flag := range flags {
+		view := FlagView{Flag: flag, Rules: map[string]Rule{}, Source: "default"}
+		for _, p := range s.Providers {
+			if rule, ok := s.rules(ctx, p)[flag.Key]; ok {
+				view.Rules[p.Name()] = rule
+				if view.Source == "default" {
+					view.Source = p.Name()
+				}
+			}
+		}
+		out = append(out, view)
+	}
+	return out
+}
+
+// Set stores the database rule for a flag. A rule from a provider ahead of the
+// database still takes precedence.
+func (s *Service) Set(ctx context.Context, actorID string, rule Rule) (Rule, error) {
+	if s.Repo == nil {
+		return Rule{}, ErrReadOnly
+	}
+	rule.Key = strings.TrimSpace(rule.Key)
+	rule.Users = trimIDs(rule.Users)
+	rule.Orgs = trimIDs(rule.Orgs)
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	rule.UpdatedBy = actorID
+	rule.UpdatedAt = s.now()
+	if err := s.Repo.Upsert(ctx, rule); err != nil {
+		return Rule{}, fmt.Errorf("store feature flag rule: %w", err)
+	}
+	s.invalidate()
+	s.record(ctx, "featureflags.set", actorID, map[string]any{
+		"key":     rule.Key,
+		"enabled": rule.Enabled,
+		"percent": rule.Percent,
+		"users":   len(rule.Users),
+		"orgs":    len(rule.Orgs),
+	})
+	return rule, nil
+}
+
+// Delete removes the database rule for a flag.
+func (s *Service) Delete(ctx context.Context, actorID, key string) error {
+	if s.Repo == nil {
+		return ErrReadOnly
+	}
+	if _, ok := Lookup(key); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownFlag, key)
+	}
+	if err := s.Repo.Delete(ctx, key); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("delete feature flag rule: %w", err)
+	}
+	s.invalidate()
+	s.record(ctx, "featureflags.delete", actorID, map[string]any{"key": key})
+	return nil
+}
+
+// invalidate drops cached rules so an edit applies to this process at once;
+// other processes pick it up within the cache TTL.
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
+
+func (s *Service) record(ctx context.Context, action, actorID string, details map[string]any) {
+	telemetry.Info(action, details)
+	if s.Audit != nil {
+		_ = s.Audit.Record(ctx, audit.Entry{
+			Action:      action,
+			ActorUserID: actorID,
+			Details:     details,
+		})
+	}
+}
+
+func (s *Service) cacheTTL() time.Duration {
+	if s.CacheTTL > 0 {
+		return s.CacheTTL
+	}
+	return DefaultCacheTTL
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func trimIDs(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
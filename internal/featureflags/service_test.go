@@ -0,0 +1,164 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestService(providers ...Provider) (*Service, *MemoryRepo, *clock) {
+	clk := &clock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	repo := NewMemoryRepo()
+	svc := NewService(repo, nil, append(providers, &RepoProvider{Repo: repo})...)
+	svc.Now = clk.Now
+	return svc, repo, clk
+}
+
+func TestNilServiceUsesDefaults(t *testing.T) {
+	var svc *Service
+	ctx := context.Background()
+	if svc.Enabled(ctx, EnsembleScoring, Target{UserID: "u1"}) {
+		t.Fatal("ensemble_scoring should default off")
+	}
+	if !svc.Enabled(ctx, RedlineRenderer, Target{UserID: "u1"}) {
+		t.Fatal("redline_renderer should default on")
+	}
+}
+
+func TestRuleTargetsUsersOrgsAndPercent(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+	if _, err := svc.Set(ctx, "admin", Rule{Key: EnsembleScoring.Key, Users: []string{" u-listed "}, Orgs: []string{"org-1"}}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	if d := svc.Evaluate(ctx, EnsembleScoring, Target{UserID: "u-listed"}); !d.Enabled || d.Reason != "user" || d.Source != "db" {
+		t.Fatalf("listed user decision = %+v", d)
+	}
+	if d := svc.Evaluate(ctx, EnsembleScoring, Target{UserID: "u-other", OrgID: "org-1"}); !d.Enabled || d.Reason != "org" {
+		t.Fatalf("listed org decision = %+v", d)
+	}
+	if svc.Enabled(ctx, EnsembleScoring, Target{UserID: "u-other"}) {
+		t.Fatal("unlisted user should keep the default")
+	}
+
+	if _, err := svc.Set(ctx, "admin", Rule{Key: EnsembleScoring.Key, Percent: 30}); err != nil {
+		t.Fatalf("set percent: %v", err)
+	}
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		first := svc.Enabled(ctx, EnsembleScoring, Target{UserID: userID})
+		if first != svc.Enabled(ctx, EnsembleScoring, Target{UserID: userID}) {
+			t.Fatalf("percent rollout is not stable for %s", userID)
+		}
+		if first {
+			enabled++
+		}
+	}
+	if enabled < 230 || enabled > 370 {
+		t.Fatalf("expected about 30%% of users enabled, got %d/1000", enabled)
+	}
+	if svc.Enabled(ctx, EnsembleScoring, Target{}) {
+		t.Fatal("percent rollout needs a user")
+	}
+}
+
+func TestKillSwitchAndDelete(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+	off := false
+	if _, err := svc.Set(ctx, "admin", Rule{Key: RedlineRenderer.Key, Enabled: &off}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if svc.Enabled(ctx, RedlineRenderer, Target{UserID: "u1"}) {
+		t.Fatal("redline_renderer should be switched off")
+	}
+	if err := svc.Delete(ctx, "admin", RedlineRenderer.Key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if !svc.Enabled(ctx, RedlineRenderer, Target{UserID: "u1"}) {
+		t.Fatal("redline_renderer should return to its default after delete")
+	}
+	if err := svc.Delete(ctx, "admin", RedlineRenderer.Key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSetRejectsUnknownFlagsAndBadPercent(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+	if _, err := svc.Set(ctx, "admin", Rule{Key: "no_such_flag"}); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected ErrUnknownFlag, got %v", err)
+	}
+	if _, err := svc.Set(ctx, "admin", Rule{Key: EnsembleScoring.Key, Percent: 101}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+	if _, err := NewEnvProvider(`{"ensemble_scorng":{"enabled":true}}`); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected a misspelled env flag to fail, got %v", err)
+	}
+}
+
+func TestEnvProviderTakesPrecedenceOverDatabase(t *testing.T) {
+	ctx := context.Background()
+	env, err := NewEnvProvider(`{"structured_output":{"enabled":false}}`)
+	if err != nil {
+		t.Fatalf("env provider: %v", err)
+	}
+	svc, _, _ := newTestService(env)
+	on := true
+	if _, err := svc.Set(ctx, "admin", Rule{Key: StructuredOutput.Key, Enabled: &on}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if d := svc.Evaluate(ctx, StructuredOutput, Target{UserID: "u1"}); d.Enabled || d.Source != "env" {
+		t.Fatalf("decision = %+v, want the env rule", d)
+	}
+	views := svc.List(ctx)
+	for _, view := range views {
+		if view.Key == StructuredOutput.Key && (view.Source != "env" || len(view.Rules) != 2) {
+			t.Fatalf("view = %+v, want env source with both rules", view)
+		}
+	}
+}
+
+func TestRemoteProviderIsCachedAndKeptOnFailure(t *testing.T) {
+	ctx := context.Background()
+	calls, failing := 0, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ensemble_scoring":{"orgs":["org-remote"]}}`))
+	}))
+	defer server.Close()
+
+	svc, _, clk := newTestService(NewRemoteProvider(server.URL))
+	target := Target{UserID: "u1", OrgID: "org-remote"}
+	for i := 0; i < 3; i++ {
+		if !svc.Enabled(ctx, EnsembleScoring, target) {
+			t.Fatal("remote rule should enable the org")
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one remote fetch within the cache TTL, got %d", calls)
+	}
+
+	failing = true
+	clk.now = clk.now.Add(DefaultCacheTTL)
+	if !svc.Enabled(ctx, EnsembleScoring, target) {
+		t.Fatal("last remote rules should be kept when a refresh fails")
+	}
+	if calls != 2 {
+		t.Fatalf("expected a refresh after the TTL, got %d fetches", calls)
+	}
+}
@@ -1,6 +1,10 @@
 package generatedresumes
 
-import "time"
+import (
+	"time"
+
+	"resume-backend/resume/render"
+)
 
 // GeneratedResume represents a stored resume generated from an analysis.
 type GeneratedResume struct {
@@ -12,6 +16,13 @@ type GeneratedResume struct {
 	StorageKey string
 	MimeType   string
 	SizeBytes  int64
-	CreatedAt  time.Time
-	DeletedAt  *time.Time
+	// ModelKey is the stored JSON ResumeModel the document was rendered
+	// from, used for other formats such as HTML. Empty for resumes generated
+	// before it was kept.
+	ModelKey  string
+	CreatedAt time.Time
+	DeletedAt *time.Time
+	// RenderWarnings reports content the template left out. Only the
+	// request that rendered the resume sets it; it is not stored.
+	RenderWarnings []render.ContentWarning
 }
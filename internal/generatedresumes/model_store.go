@@ -0,0 +1,37 @@
+package generatedresumes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/resume/model"
+)
+
+// SaveModel stores the ResumeModel a resume was rendered from next to the
+// document and returns its storage key for GeneratedResume.ModelKey.
+func SaveModel(ctx context.Context, store object.ObjectStore, userID, templateID string, resumeModel model.ResumeModel) (string, error) {
+	payload, err := json.Marshal(resumeModel)
+	if err != nil {
+		return "", err
+	}
+	key, _, _, err := store.Save(ctx, userID, "resume_generated_"+templateID+".json", bytes.NewReader(payload))
+	return key, err
+}
+
+// LoadModel reads the ResumeModel stored by SaveModel.
+func LoadModel(ctx context.Context, store object.ObjectStore, key string) (model.ResumeModel, error) {
+	reader, err := store.Open(ctx, key)
+	if err != nil {
+		return model.ResumeModel{}, err
+	}
+	defer reader.Close()
+
+	var resumeModel model.ResumeModel
+	if err := json.NewDecoder(reader).Decode(&resumeModel); err != nil {
+		return model.ResumeModel{}, fmt.Errorf("decode resume model: %w", err)
+	}
+	return resumeModel, nil
+}
@@ -7,4 +7,8 @@ type Repo interface {
 	Create(ctx context.Context, resume GeneratedResume) error
 	GetByID(ctx context.Context, userID, generatedResumeID string) (GeneratedResume, error)
 	ListByUser(ctx context.Context, userID string, limit, offset int) ([]GeneratedResume, error)
+	// ListByDocument and DeleteByDocument let guest retention purge the resumes
+	// generated from an expired document.
+	ListByDocument(ctx context.Context, userID, documentID string) ([]GeneratedResume, error)
+	DeleteByDocument(ctx context.Context, userID, documentID string) (int, error)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sort"
 	"sync"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 // MemoryRepo stores generated resumes in memory and is safe for concurrent use.
@@ -82,3 +84,51 @@ func (r *MemoryRepo) ListByUser(ctx context.Context, userID string, limit, offse
 	}
 	return resumes[offset:end], nil
 }
+
+// ListByDocument returns the user's generated resumes made from a document.
+func (r *MemoryRepo) ListByDocument(ctx context.Context, userID, documentID string) ([]GeneratedResume, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []GeneratedResume
+	for _, resume := range r.byUser[userID] {
+		if resume.DocumentID == documentID {
+			out = append(out, resume)
+		}
+	}
+	return out, nil
+}
+
+// DeleteByDocument removes the user's generated resumes made from a document
+// and returns how many it removed.
+func (r *MemoryRepo) DeleteByDocument(ctx context.Context, userID, documentID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.byUser[userID][:0]
+	var removed []GeneratedResume
+	for _, resume := range r.byUser[userID] {
+		if resume.DocumentID != documentID {
+			kept = append(kept, resume)
+			continue
+		}
+		delete(r.byID, resume.ID)
+		removed = append(removed, resume)
+	}
+	r.byUser[userID] = kept
+	if len(removed) > 0 {
+		db.OnRollback(ctx, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			for _, resume := range removed {
+				r.byID[resume.ID] = resume
+			}
+			r.byUser[userID] = append(r.byUser[userID], removed...)
+		})
+	}
+	return len(removed), nil
+}
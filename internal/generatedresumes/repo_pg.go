@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 // PGRepo implements Repo using Postgres.
@@ -15,8 +17,8 @@ type PGRepo struct {
 func (r *PGRepo) Create(ctx context.Context, resume GeneratedResume) error {
 	const query = `
 INSERT INTO generated_resumes (
-    id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+    id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at, model_key
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 	_, err := r.DB.ExecContext(ctx, query,
 		resume.ID,
 		resume.UserID,
@@ -27,6 +29,7 @@ INSERT INTO generated_resumes (
 		resume.MimeType,
 		resume.SizeBytes,
 		resume.CreatedAt,
+		resume.ModelKey,
 	)
 	return err
 }
@@ -34,7 +37,7 @@ INSERT INTO generated_resumes (
 // GetByID returns a generated resume by ID for a user.
 func (r *PGRepo) GetByID(ctx context.Context, userID, generatedResumeID string) (GeneratedResume, error) {
 	const query = `
-SELECT id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at
+SELECT id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at, model_key
 FROM generated_resumes
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1`
@@ -49,6 +52,7 @@ LIMIT 1`
 		&resume.MimeType,
 		&resume.SizeBytes,
 		&resume.CreatedAt,
+		&resume.ModelKey,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -74,7 +78,7 @@ func (r *PGRepo) ListByUser(ctx context.Context, userID string, limit, offset in
 		offset = 0
 	}
 	const query = `
-SELECT id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at
+SELECT id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at, model_key
 FROM generated_resumes
 WHERE user_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -99,6 +103,7 @@ LIMIT $2 OFFSET $3`
 			&resume.MimeType,
 			&resume.SizeBytes,
 			&resume.CreatedAt,
+			&resume.ModelKey,
 		); err != nil {
 			return nil, err
 		}
@@ -108,3 +113,51 @@ LIMIT $2 OFFSET $3`
 }
 
 var _ Repo = (*PGRepo)(nil)
+
+// ListByDocument returns the user's generated resumes made from a document,
+// including soft-deleted ones whose objects may still be stored.
+func (r *PGRepo) ListByDocument(ctx context.Context, userID, documentID string) ([]GeneratedResume, error) {
+	const query = `
+SELECT id, user_id, document_id, analysis_id, template_id, storage_key, mime_type, size_bytes, created_at, model_key
+FROM generated_resumes
+WHERE user_id = $1 AND document_id::text = $2
+ORDER BY created_at`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []GeneratedResume
+	for rows.Next() {
+		var resume GeneratedResume
+		if err := rows.Scan(
+			&resume.ID,
+			&resume.UserID,
+			&resume.DocumentID,
+			&resume.AnalysisID,
+			&resume.TemplateID,
+			&resume.StorageKey,
+			&resume.MimeType,
+			&resume.SizeBytes,
+			&resume.CreatedAt,
+			&resume.ModelKey,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, resume)
+	}
+	return out, rows.Err()
+}
+
+// DeleteByDocument removes the user's generated resumes made from a document
+// and returns how many rows it removed.
+func (r *PGRepo) DeleteByDocument(ctx context.Context, userID, documentID string) (int, error) {
+	res, err := db.ConnFor(ctx, r.DB).ExecContext(ctx, `DELETE FROM generated_resumes WHERE user_id = $1 AND document_id::text = $2`, userID, documentID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
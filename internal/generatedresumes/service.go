@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -37,6 +36,9 @@ type Service struct {
 	AnalysisRepo AnalysisReader
 	DocRepo      documents.DocumentsRepo
 	Store        object.ObjectStore
+	// LLM rebuilds the resume model from the stored resume text; nil parses it
+	// without the LLM.
+	LLM resumeservice.LLMClient
 }
 
 // CreateFromAnalysis generates and stores a resume from the analysis results.
@@ -70,13 +72,7 @@ func (s *Service) CreateFromAnalysis(ctx context.Context, userID, analysisID, te
 		return GeneratedResume{}, err
 	}
 
-	reader, err := s.Store.Open(ctx, doc.StorageKey)
-	if err != nil {
-		return GeneratedResume{}, err
-	}
-	defer reader.Close()
-
-	raw, err := io.ReadAll(reader)
+	source, err := documents.LoadResumeSource(ctx, s.Store, doc)
 	if err != nil {
 		return GeneratedResume{}, err
 	}
@@ -86,7 +82,7 @@ func (s *Service) CreateFromAnalysis(ctx context.Context, userID, analysisID, te
 		return GeneratedResume{}, ErrInvalidInput
 	}
 
-	execResult, err := resumeservice.ExecuteApply(ctx, string(raw), result, resumeservice.ApplyHeaderInputs{}, false)
+	execResult, err := resumeservice.ExecuteApply(ctx, s.LLM, source.Text, result, resumeservice.ApplyHeaderInputs{}, false)
 	if err != nil {
 		return GeneratedResume{}, err
 	}
@@ -97,6 +93,11 @@ func (s *Service) CreateFromAnalysis(ctx context.Context, userID, analysisID, te
 		return GeneratedResume{}, err
 	}
 
+	modelKey, err := SaveModel(ctx, s.Store, userID, templateID, execResult.Model)
+	if err != nil {
+		return GeneratedResume{}, err
+	}
+
 	resume := GeneratedResume{
 		ID:         uuid.NewString(),
 		UserID:     userID,
@@ -106,6 +107,7 @@ func (s *Service) CreateFromAnalysis(ctx context.Context, userID, analysisID, te
 		StorageKey: storageKey,
 		MimeType:   mimeType,
 		SizeBytes:  size,
+		ModelKey:   modelKey,
 		CreatedAt:  time.Now().UTC(),
 	}
 
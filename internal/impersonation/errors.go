@@ -0,0 +1,17 @@
+package impersonation
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the impersonation session does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrTargetNotAllowed indicates the user cannot be impersonated.
+	ErrTargetNotAllowed = errors.New("user cannot be impersonated")
+
+	// ErrInactive indicates the session expired, was revoked or does not match the token.
+	ErrInactive = errors.New("impersonation session is not active")
+)
@@ -0,0 +1,90 @@
+package impersonation
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Guard enforces impersonation sessions. Ordinary requests pass straight through.
+// Impersonated requests need a live session, are refused mutations unless the
+// session allows them, and are audited with both identities. It must run after
+// middleware.Auth. A nil svc rejects every impersonation token.
+func Guard(svc *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminUserID, impersonationID := middleware.ImpersonationFromContext(c)
+		if adminUserID == "" {
+			c.Next()
+			return
+		}
+		if svc == nil {
+			respond.Error(c, http.StatusUnauthorized, "impersonation_inactive", "impersonation is not available", nil)
+			return
+		}
+
+		ctx := c.Request.Context()
+		targetUserID := middleware.UserIDFromContext(c)
+		session, err := svc.Authorize(ctx, impersonationID, adminUserID, targetUserID)
+		if err != nil {
+			if !errors.Is(err, ErrInactive) {
+				telemetry.Error("impersonation.authorize_failed", map[string]any{"impersonation_id": impersonationID, "error": err.Error()})
+			}
+			respond.Error(c, http.StatusUnauthorized, "impersonation_inactive", "impersonation session has expired or was revoked", nil)
+			return
+		}
+
+		entry := audit.Entry{
+			Action:          ActionRequest,
+			ActorUserID:     session.AdminUserID,
+			SubjectUserID:   session.TargetUserID,
+			ImpersonationID: session.ID,
+			Method:          c.Request.Method,
+			Route:           route(c),
+		}
+		if !session.AllowMutations && isMutation(c.Request.Method) {
+			entry.Status = http.StatusForbidden
+			entry.Details = map[string]any{"blocked": true}
+			_ = svc.Audit.Record(ctx, entry)
+			respond.Error(c, http.StatusForbidden, "impersonation_read_only", "this impersonation session is read-only", nil)
+			return
+		}
+		// Mutations are only allowed once they are on record.
+		if isMutation(c.Request.Method) {
+			entry.Details = map[string]any{"phase": "attempt"}
+			if err := svc.Audit.Record(ctx, entry); err != nil {
+				respond.Error(c, http.StatusServiceUnavailable, "audit_unavailable", "impersonated changes cannot be audited right now", nil)
+				return
+			}
+			entry.Details = nil
+		}
+
+		c.Next()
+
+		entry.Status = c.Writer.Status()
+		_ = svc.Audit.Record(ctx, entry)
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// route prefers the matched route template so entries group by endpoint; the raw
+// path is used for unmatched requests.
+func route(c *gin.Context) string {
+	if full := c.FullPath(); full != "" {
+		return full
+	}
+	return c.Request.URL.Path
+}
@@ -0,0 +1,158 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/users"
+)
+
+func newTestService(t *testing.T) (*Service, *audit.MemoryRepo) {
+	t.Helper()
+	userRepo := users.NewMemoryRepo()
+	for _, u := range []users.User{
+		{ID: "google:user-1", Email: "user@example.com"},
+		{ID: "google:admin-2", Email: "other-admin@example.com"},
+	} {
+		if err := userRepo.Upsert(context.Background(), u); err != nil {
+			t.Fatalf("upsert user: %v", err)
+		}
+	}
+	auditRepo := audit.NewMemoryRepo()
+	svc := NewService(NewMemoryRepo(), audit.NewService(auditRepo), userRepo, []string{"google:admin-1", "google:admin-2"})
+	return svc, auditRepo
+}
+
+func newTestRouter(svc *Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Auth("dev"), Guard(svc))
+	ok := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"userId": middleware.UserIDFromContext(c)})
+	}
+	router.GET("/api/v1/documents", ok)
+	router.POST("/api/v1/documents", ok)
+	return router
+}
+
+func call(router *gin.Engine, method, token string) int {
+	req := httptest.NewRequest(method, "/api/v1/documents", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp.Code
+}
+
+func TestImpersonationIsReadOnlyByDefaultAndAudited(t *testing.T) {
+	svc, auditRepo := newTestService(t)
+	router := newTestRouter(svc)
+	ctx := context.Background()
+
+	session, token, err := svc.Start(ctx, "google:admin-1", StartInput{TargetUserID: "google:user-1", Reason: "ticket 42"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if session.AllowMutations || session.ExpiresAt.Sub(session.CreatedAt) != DefaultTTL {
+		t.Fatalf("unexpected session %+v", session)
+	}
+
+	if code := call(router, http.MethodGet, token); code != http.StatusOK {
+		t.Fatalf("read: expected 200, got %d", code)
+	}
+	if code := call(router, http.MethodPost, token); code != http.StatusForbidden {
+		t.Fatalf("mutation: expected 403, got %d", code)
+	}
+
+	entries, _ := auditRepo.List(ctx, audit.Filter{ImpersonationID: session.ID})
+	if len(entries) != 3 {
+		t.Fatalf("expected start, read and blocked entries, got %+v", entries)
+	}
+	blocked, read, start := entries[0], entries[1], entries[2]
+	if start.Action != ActionStart || start.Details["reason"] != "ticket 42" {
+		t.Fatalf("unexpected start entry %+v", start)
+	}
+	for _, e := range []audit.Entry{read, blocked} {
+		if e.Action != ActionRequest || e.ActorUserID != "google:admin-1" || e.SubjectUserID != "google:user-1" || e.Route != "/api/v1/documents" {
+			t.Fatalf("expected both identities on request entry, got %+v", e)
+		}
+	}
+	if read.Status != http.StatusOK || blocked.Status != http.StatusForbidden || blocked.Details["blocked"] != true {
+		t.Fatalf("unexpected statuses read=%+v blocked=%+v", read, blocked)
+	}
+
+	if _, err := svc.Revoke(ctx, "google:admin-1", session.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if code := call(router, http.MethodGet, token); code != http.StatusUnauthorized {
+		t.Fatalf("after revoke: expected 401, got %d", code)
+	}
+}
+
+func TestImpersonationWithMutationsAllowed(t *testing.T) {
+	svc, auditRepo := newTestService(t)
+	router := newTestRouter(svc)
+	ctx := context.Background()
+
+	session, token, err := svc.Start(ctx, "google:admin-1", StartInput{TargetUserID: "google:user-1", Reason: "fix upload", AllowMutations: true})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if code := call(router, http.MethodPost, token); code != http.StatusOK {
+		t.Fatalf("mutation: expected 200, got %d", code)
+	}
+	entries, _ := auditRepo.List(ctx, audit.Filter{ImpersonationID: session.ID, Action: ActionRequest})
+	if len(entries) != 2 || entries[1].Details["phase"] != "attempt" || entries[0].Status != http.StatusOK {
+		t.Fatalf("expected an attempt entry before the completed request, got %+v", entries)
+	}
+}
+
+func TestImpersonationExpiresAndRejectsBadTargets(t *testing.T) {
+	svc, _ := newTestService(t)
+	router := newTestRouter(svc)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	svc.Now = func() time.Time { return now }
+
+	_, token, err := svc.Start(ctx, "google:admin-1", StartInput{TargetUserID: "google:user-1", Reason: "r", TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if code := call(router, http.MethodGet, token); code != http.StatusUnauthorized {
+		t.Fatalf("expired session: expected 401, got %d", code)
+	}
+
+	cases := []struct {
+		in   StartInput
+		want error
+	}{
+		{StartInput{TargetUserID: "google:admin-2", Reason: "r"}, ErrTargetNotAllowed},
+		{StartInput{TargetUserID: "google:nobody", Reason: "r"}, ErrNotFound},
+		{StartInput{TargetUserID: "google:user-1"}, ErrInvalidInput},
+		{StartInput{TargetUserID: "google:user-1", Reason: "r", TTL: 2 * MaxTTL}, ErrInvalidInput},
+	}
+	for _, tc := range cases {
+		if _, _, err := svc.Start(ctx, "google:admin-1", tc.in); !errors.Is(err, tc.want) {
+			t.Fatalf("Start(%+v): expected %v, got %v", tc.in, tc.want, err)
+		}
+	}
+}
+
+func TestGuardWithoutServiceRejectsImpersonationTokens(t *testing.T) {
+	svc, _ := newTestService(t)
+	_, token, err := svc.Start(context.Background(), "google:admin-1", StartInput{TargetUserID: "google:user-1", Reason: "r"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if code := call(newTestRouter(nil), http.MethodGet, token); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", code)
+	}
+}
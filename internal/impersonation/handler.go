@@ -0,0 +1,92 @@
+package impersonation
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the impersonation admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches impersonation routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/impersonations", h.list)
+	rg.POST("/impersonations", h.start)
+	rg.POST("/impersonations/:id/revoke", h.revoke)
+}
+
+type startRequest struct {
+	UserID         string `json:"userId"`
+	Reason         string `json:"reason"`
+	TTLMinutes     int    `json:"ttlMinutes"`
+	AllowMutations bool   `json:"allowMutations"`
+}
+
+type startResponse struct {
+	Session
+	Token string `json:"token"`
+}
+
+func (h *Handler) start(c *gin.Context) {
+	var req startRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	session, token, err := h.Svc.Start(c.Request.Context(), middleware.UserIDFromContext(c), StartInput{
+		TargetUserID:   req.UserID,
+		Reason:         req.Reason,
+		TTL:            time.Duration(req.TTLMinutes) * time.Minute,
+		AllowMutations: req.AllowMutations,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	respond.JSON(c, http.StatusCreated, startResponse{Session: session, Token: token})
+}
+
+func (h *Handler) list(c *gin.Context) {
+	sessions, err := h.Svc.List(c.Request.Context(), 50)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"impersonations": sessions})
+}
+
+func (h *Handler) revoke(c *gin.Context) {
+	session, err := h.Svc.Revoke(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, session)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "user or impersonation session not found", nil)
+	case errors.Is(err, ErrTargetNotAllowed):
+		respond.Error(c, http.StatusForbidden, "forbidden", "admins cannot be impersonated", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to process impersonation", nil)
+	}
+}
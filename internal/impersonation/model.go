@@ -0,0 +1,20 @@
+package impersonation
+
+import "time"
+
+// Session is one admin's time-limited permission to act as a user.
+type Session struct {
+	ID             string     `json:"id"`
+	AdminUserID    string     `json:"adminUserId"`
+	TargetUserID   string     `json:"targetUserId"`
+	Reason         string     `json:"reason"`
+	AllowMutations bool       `json:"allowMutations"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// Active reports whether the session may still be used at now.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package impersonation
+
+import (
+	"context"
+	"time"
+)
+
+// Repo persists impersonation sessions.
+type Repo interface {
+	Create(ctx context.Context, session Session) error
+	GetByID(ctx context.Context, id string) (Session, error)
+	// List returns sessions newest first.
+	List(ctx context.Context, limit int) ([]Session, error)
+	// Revoke marks an unrevoked session revoked.
+	Revoke(ctx context.Context, id string, revokedAt time.Time) (Session, error)
+}
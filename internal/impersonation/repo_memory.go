@@ -0,0 +1,83 @@
+package impersonation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRepo stores sessions in memory.
+type MemoryRepo struct {
+	mu       sync.RWMutex
+	sessions []Session
+}
+
+// NewMemoryRepo constructs an empty MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Create inserts a session.
+func (r *MemoryRepo) Create(ctx context.Context, session Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+// GetByID returns a session.
+func (r *MemoryRepo) GetByID(ctx context.Context, id string) (Session, error) {
+	if err := ctx.Err(); err != nil {
+		return Session{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sessions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return Session{}, ErrNotFound
+}
+
+// List returns sessions newest first.
+func (r *MemoryRepo) List(ctx context.Context, limit int) ([]Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Session, 0, len(r.sessions))
+	for i := len(r.sessions) - 1; i >= 0; i-- {
+		out = append(out, r.sessions[i])
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Revoke marks an unrevoked session revoked.
+func (r *MemoryRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) (Session, error) {
+	if err := ctx.Err(); err != nil {
+		return Session{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.sessions {
+		if r.sessions[i].ID != id {
+			continue
+		}
+		if r.sessions[i].RevokedAt == nil {
+			at := revokedAt
+			r.sessions[i].RevokedAt = &at
+		}
+		return r.sessions[i], nil
+	}
+	return Session{}, ErrNotFound
+}
@@ -0,0 +1,91 @@
+package impersonation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const sessionColumns = `id, admin_user_id, target_user_id, reason, allow_mutations, expires_at, revoked_at, created_at`
+
+// Create inserts a session.
+func (r *PGRepo) Create(ctx context.Context, session Session) error {
+	const query = `INSERT INTO impersonation_sessions (` + sessionColumns + `) VALUES ($1, $2, $3, $4, $5, $6, NULL, $7)`
+	_, err := r.DB.ExecContext(ctx, query,
+		session.ID,
+		session.AdminUserID,
+		session.TargetUserID,
+		session.Reason,
+		session.AllowMutations,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+	return err
+}
+
+// GetByID returns a session.
+func (r *PGRepo) GetByID(ctx context.Context, id string) (Session, error) {
+	const query = `SELECT ` + sessionColumns + ` FROM impersonation_sessions WHERE id = $1`
+	s, err := scanSession(r.DB.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrNotFound
+	}
+	return s, err
+}
+
+// List returns sessions newest first.
+func (r *PGRepo) List(ctx context.Context, limit int) ([]Session, error) {
+	const query = `SELECT ` + sessionColumns + ` FROM impersonation_sessions ORDER BY created_at DESC LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// Revoke marks an unrevoked session revoked.
+func (r *PGRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) (Session, error) {
+	const query = `
+UPDATE impersonation_sessions
+SET revoked_at = COALESCE(revoked_at, $2)
+WHERE id = $1
+RETURNING ` + sessionColumns
+	s, err := scanSession(r.DB.QueryRowContext(ctx, query, id, revokedAt))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrNotFound
+	}
+	return s, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row rowScanner) (Session, error) {
+	var s Session
+	var revokedAt sql.NullTime
+	if err := row.Scan(&s.ID, &s.AdminUserID, &s.TargetUserID, &s.Reason, &s.AllowMutations, &s.ExpiresAt, &revokedAt, &s.CreatedAt); err != nil {
+		return Session{}, err
+	}
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.Time
+	}
+	return s, nil
+}
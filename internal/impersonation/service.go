@@ -0,0 +1,190 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/audit"
+	sharedauth "resume-backend/internal/shared/auth"
+	"resume-backend/internal/users"
+)
+
+// Audit actions recorded for impersonation.
+const (
+	ActionStart   = "impersonation.start"
+	ActionRevoke  = "impersonation.revoke"
+	ActionRequest = "impersonation.request"
+)
+
+const (
+	// DefaultTTL is the token lifetime when the admin does not ask for one.
+	DefaultTTL = 15 * time.Minute
+	// MaxTTL caps how long a single impersonation can last.
+	MaxTTL = time.Hour
+
+	maxReasonLength = 500
+)
+
+// UserLookup resolves the user being impersonated.
+type UserLookup interface {
+	GetByID(ctx context.Context, userID string) (users.User, error)
+}
+
+// Service mints impersonation tokens and checks them on every request.
+type Service struct {
+	Repo  Repo
+	Audit *audit.Service
+	Users UserLookup
+	// AdminUserIDs cannot be impersonated, so a token never grants admin rights.
+	AdminUserIDs []string
+	Now          func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, auditSvc *audit.Service, lookup UserLookup, adminUserIDs []string) *Service {
+	return &Service{Repo: repo, Audit: auditSvc, Users: lookup, AdminUserIDs: adminUserIDs}
+}
+
+// StartInput describes a requested impersonation.
+type StartInput struct {
+	TargetUserID   string
+	Reason         string
+	TTL            time.Duration
+	AllowMutations bool
+}
+
+// Start opens a session for adminUserID to act as the target and returns its token.
+// The session is only usable once its start has been audited.
+func (s *Service) Start(ctx context.Context, adminUserID string, in StartInput) (Session, string, error) {
+	in.TargetUserID = strings.TrimSpace(in.TargetUserID)
+	in.Reason = strings.TrimSpace(in.Reason)
+	switch {
+	case in.TargetUserID == "":
+		return Session{}, "", fmt.Errorf("%w: userId is required", ErrInvalidInput)
+	case in.Reason == "":
+		return Session{}, "", fmt.Errorf("%w: reason is required", ErrInvalidInput)
+	case len(in.Reason) > maxReasonLength:
+		return Session{}, "", fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidInput, maxReasonLength)
+	case in.TTL < 0 || in.TTL > MaxTTL:
+		return Session{}, "", fmt.Errorf("%w: ttl must be at most %s", ErrInvalidInput, MaxTTL)
+	}
+	if in.TTL == 0 {
+		in.TTL = DefaultTTL
+	}
+	if in.TargetUserID == adminUserID || s.isAdmin(in.TargetUserID) {
+		return Session{}, "", ErrTargetNotAllowed
+	}
+	target, err := s.Users.GetByID(ctx, in.TargetUserID)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return Session{}, "", ErrNotFound
+		}
+		return Session{}, "", err
+	}
+
+	now := s.now()
+	session := Session{
+		ID:             uuid.NewString(),
+		AdminUserID:    adminUserID,
+		TargetUserID:   target.ID,
+		Reason:         in.Reason,
+		AllowMutations: in.AllowMutations,
+		ExpiresAt:      now.Add(in.TTL),
+		CreatedAt:      now,
+	}
+	if err := s.Repo.Create(ctx, session); err != nil {
+		return Session{}, "", err
+	}
+	if err := s.Audit.Record(ctx, audit.Entry{
+		Action:          ActionStart,
+		ActorUserID:     adminUserID,
+		SubjectUserID:   target.ID,
+		ImpersonationID: session.ID,
+		Details: map[string]any{
+			"reason":         session.Reason,
+			"allowMutations": session.AllowMutations,
+			"expiresAt":      session.ExpiresAt,
+		},
+	}); err != nil {
+		_, _ = s.Repo.Revoke(ctx, session.ID, now)
+		return Session{}, "", err
+	}
+
+	token, err := sharedauth.SignJWT(sharedauth.Claims{
+		Sub:     target.ID,
+		Email:   target.Email,
+		Name:    target.FullName,
+		Picture: target.PictureURL,
+		Act:     adminUserID,
+		Sid:     session.ID,
+		Iat:     now.Unix(),
+		Exp:     session.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return Session{}, "", err
+	}
+	return session, token, nil
+}
+
+// Revoke ends a session early.
+func (s *Service) Revoke(ctx context.Context, adminUserID, id string) (Session, error) {
+	session, err := s.Repo.Revoke(ctx, id, s.now())
+	if err != nil {
+		return Session{}, err
+	}
+	_ = s.Audit.Record(ctx, audit.Entry{
+		Action:          ActionRevoke,
+		ActorUserID:     adminUserID,
+		SubjectUserID:   session.TargetUserID,
+		ImpersonationID: session.ID,
+	})
+	return session, nil
+}
+
+// List returns recent sessions, newest first.
+func (s *Service) List(ctx context.Context, limit int) ([]Session, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.Repo.List(ctx, limit)
+}
+
+// Authorize checks that a token's session is live and was issued to this admin for
+// this user.
+func (s *Service) Authorize(ctx context.Context, impersonationID, adminUserID, targetUserID string) (Session, error) {
+	if impersonationID == "" {
+		return Session{}, ErrInactive
+	}
+	session, err := s.Repo.GetByID(ctx, impersonationID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Session{}, ErrInactive
+		}
+		return Session{}, err
+	}
+	if session.AdminUserID != adminUserID || session.TargetUserID != targetUserID || !session.Active(s.now()) {
+		return Session{}, ErrInactive
+	}
+	return session, nil
+}
+
+func (s *Service) isAdmin(userID string) bool {
+	for _, id := range s.AdminUserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
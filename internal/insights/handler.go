@@ -0,0 +1,42 @@
+package insights
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses/roles"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves aggregate insights.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches the insights routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/insights/keywords", h.keywords)
+}
+
+func (h *Handler) keywords(c *gin.Context) {
+	role := strings.ToLower(strings.TrimSpace(c.Query("role")))
+	if !roles.Valid(role) {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "role must be one of "+strings.Join(roles.Categories(), ", "), []map[string]string{
+			{"field": "role", "issue": "invalid"},
+		})
+		return
+	}
+	insights, err := h.Svc.Keywords(c.Request.Context(), role)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to build insights", nil)
+		return
+	}
+	respond.CachedJSON(c, "public, max-age=3600", insights)
+}
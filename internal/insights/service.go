@@ -0,0 +1,339 @@
+// Package insights reports market-level aggregates over all users' analyses.
+// Every figure is computed with bounded per-user contributions, Laplace noise
+// and minimum-count thresholds, so no report reveals whether any one user's
+// analysis was included.
+package insights
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// ErrNoSource is returned when the service has no analyses to aggregate.
+var ErrNoSource = errors.New("insights have no analysis source")
+
+// Config tunes the aggregation.
+type Config struct {
+	// Window is how far back completed analyses are read.
+	Window time.Duration
+	// MaxAnalyses caps how many analyses one run reads.
+	MaxAnalyses int
+	// Refresh is how long a computed report is served before it is rebuilt.
+	// Noise is drawn once per report, so repeated requests cannot average it out.
+	Refresh time.Duration
+	// Epsilon is the privacy budget of one report; smaller is noisier.
+	Epsilon float64
+	// MaxKeywordsPerUser bounds how many keywords one user adds to a role.
+	MaxKeywordsPerUser int
+	// MinUsers suppresses roles with fewer (noisy) users.
+	MinUsers int
+	// MinKeywordUsers suppresses keywords missing for fewer users, counted
+	// both before and after noise. The true count is checked too because the
+	// keyword noise is wide enough to publish a keyword only one user has.
+	MinKeywordUsers int
+	// TopN is how many keywords a role reports.
+	TopN int
+}
+
+// DefaultConfig returns the settings used when none are supplied.
+func DefaultConfig() Config {
+	return Config{
+		Window:             90 * 24 * time.Hour,
+		MaxAnalyses:        20000,
+		Refresh:            6 * time.Hour,
+		Epsilon:            1,
+		MaxKeywordsPerUser: 10,
+		MinUsers:           25,
+		MinKeywordUsers:    10,
+		TopN:               15,
+	}
+}
+
+// AnalysisSource lists completed analyses for aggregation.
+type AnalysisSource interface {
+	ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error)
+}
+
+// KeywordInsight is one commonly missing keyword. Share is the percentage of
+// the role's users whose resumes lacked it.
+type KeywordInsight struct {
+	Keyword string `json:"keyword"`
+	Share   int    `json:"share"`
+}
+
+// RoleInsights are the commonly missing keywords of one role category.
+// SampleSize is the noisy user count rounded to ten. Suppressed is set when
+// too few users analyzed resumes for the role to report anything.
+type RoleInsights struct {
+	Role        string           `json:"role"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+	WindowDays  int              `json:"windowDays"`
+	SampleSize  int              `json:"sampleSize"`
+	Suppressed  bool             `json:"suppressed"`
+	Keywords    []KeywordInsight `json:"keywords"`
+}
+
+// Service builds and caches keyword insights.
+type Service struct {
+	Source AnalysisSource
+	Config Config
+	Now    func() time.Time
+	// Noise draws Laplace noise with the given scale; nil uses math/rand.
+	Noise func(scale float64) float64
+
+	mu      sync.Mutex
+	report  map[string]RoleInsights
+	builtAt time.Time
+	// building is closed when the rebuild in progress finishes; nil when
+	// none is.
+	building chan struct{}
+}
+
+// NewService constructs a Service with DefaultConfig.
+func NewService(source AnalysisSource) *Service {
+	return &Service{Source: source, Config: DefaultConfig()}
+}
+
+// Keywords returns the insights of a role category from the current report,
+// rebuilding the report when it is older than Config.Refresh.
+func (s *Service) Keywords(ctx context.Context, role string) (RoleInsights, error) {
+	if s == nil || s.Source == nil {
+		return RoleInsights{}, ErrNoSource
+	}
+	cfg := s.config()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The report is rebuilt outside the lock. Concurrent callers wait for the
+	// one rebuild rather than drawing noise of their own.
+	for s.report == nil || s.now().Sub(s.builtAt) >= cfg.Refresh {
+		if building := s.building; building != nil {
+			s.mu.Unlock()
+			select {
+			case <-building:
+			case <-ctx.Done():
+				s.mu.Lock()
+				return RoleInsights{}, ctx.Err()
+			}
+			s.mu.Lock()
+			continue
+		}
+		building := make(chan struct{})
+		s.building = building
+		s.mu.Unlock()
+		now := s.now()
+		report, err := s.build(ctx, cfg, now)
+		s.mu.Lock()
+		s.building = nil
+		close(building)
+		if err != nil {
+			return RoleInsights{}, err
+		}
+		s.report, s.builtAt = report, now
+	}
+	if insights, ok := s.report[role]; ok {
+		return insights, nil
+	}
+	return RoleInsights{
+		Role:        role,
+		GeneratedAt: s.builtAt,
+		WindowDays:  windowDays(cfg),
+		Suppressed:  true,
+		Keywords:    []KeywordInsight{},
+	}, nil
+}
+
+// build aggregates the window's analyses by role. Each user counts once per
+// keyword and adds at most MaxKeywordsPerUser keywords per role, which bounds
+// the report's sensitivity to one user; counts then get Laplace noise scaled
+// to that bound.
+func (s *Service) build(ctx context.Context, cfg Config, now time.Time) (map[string]RoleInsights, error) {
+	items, err := s.Source.ListCompletedSince(ctx, now.Add(-cfg.Window), cfg.MaxAnalyses)
+	if err != nil {
+		return nil, err
+	}
+
+	type roleCounts struct {
+		users    map[string]bool
+		keywords map[string]map[string]bool
+	}
+	byRole := map[string]*roleCounts{}
+	for _, a := range items {
+		role := analyses.RoleCategory(a.Result)
+		if role == "" || a.UserID == "" {
+			continue
+		}
+		counts := byRole[role]
+		if counts == nil {
+			counts = &roleCounts{users: map[string]bool{}, keywords: map[string]map[string]bool{}}
+			byRole[role] = counts
+		}
+		counts.users[a.UserID] = true
+		for _, keyword := range missingKeywords(a.Result) {
+			users := counts.keywords[keyword]
+			if users == nil {
+				users = map[string]bool{}
+				counts.keywords[keyword] = users
+			}
+			users[a.UserID] = true
+		}
+	}
+
+	// Half the budget goes to the user counts and half to the keyword counts.
+	userScale := 2 / cfg.Epsilon
+	keywordScale := 2 * float64(cfg.MaxKeywordsPerUser) / cfg.Epsilon
+	report := make(map[string]RoleInsights, len(byRole))
+	for role, counts := range byRole {
+		insights := RoleInsights{Role: role, GeneratedAt: now, WindowDays: windowDays(cfg), Keywords: []KeywordInsight{}}
+		users := float64(len(counts.users)) + s.noise(userScale)
+		if users < float64(cfg.MinUsers) {
+			insights.Suppressed = true
+			report[role] = insights
+			continue
+		}
+		insights.SampleSize = int(math.Round(users/10) * 10)
+
+		perUser := boundContributions(counts.keywords, cfg.MaxKeywordsPerUser)
+		for keyword, n := range perUser {
+			if n < cfg.MinKeywordUsers {
+				continue
+			}
+			noisy := float64(n) + s.noise(keywordScale)
+			if noisy < float64(cfg.MinKeywordUsers) {
+				continue
+			}
+			share := int(math.Round(math.Min(noisy/users, 1) * 100))
+			insights.Keywords = append(insights.Keywords, KeywordInsight{Keyword: keyword, Share: share})
+		}
+		sort.Slice(insights.Keywords, func(i, j int) bool {
+			if insights.Keywords[i].Share != insights.Keywords[j].Share {
+				return insights.Keywords[i].Share > insights.Keywords[j].Share
+			}
+			return insights.Keywords[i].Keyword < insights.Keywords[j].Keyword
+		})
+		if len(insights.Keywords) > cfg.TopN {
+			insights.Keywords = insights.Keywords[:cfg.TopN]
+		}
+		report[role] = insights
+	}
+	telemetry.InfoContext(ctx, "insights.keywords_built", map[string]any{
+		"analyses": len(items),
+		"roles":    len(report),
+	})
+	return report, nil
+}
+
+// boundContributions counts the users missing each keyword, keeping for each
+// user only their max keywords that are most common overall, so one user
+// moves at most max counts by one.
+func boundContributions(keywords map[string]map[string]bool, max int) map[string]int {
+	ordered := make([]string, 0, len(keywords))
+	for keyword := range keywords {
+		ordered = append(ordered, keyword)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if len(keywords[ordered[i]]) != len(keywords[ordered[j]]) {
+			return len(keywords[ordered[i]]) > len(keywords[ordered[j]])
+		}
+		return ordered[i] < ordered[j]
+	})
+	used := map[string]int{}
+	out := map[string]int{}
+	for _, keyword := range ordered {
+		for user := range keywords[keyword] {
+			if used[user] >= max {
+				continue
+			}
+			used[user]++
+			out[keyword]++
+		}
+	}
+	return out
+}
+
+// missingKeywords returns the result's missing keywords, lowercased and
+// without duplicates.
+func missingKeywords(result map[string]any) []string {
+	ats, _ := result["ats"].(map[string]any)
+	missing, _ := ats["missingKeywords"].(map[string]any)
+	seen := map[string]bool{}
+	var out []string
+	for _, field := range []string{"fromJobDescription", "industryCommon"} {
+		items, _ := missing[field].([]any)
+		for _, item := range items {
+			keyword, _ := item.(string)
+			keyword = strings.ToLower(strings.Join(strings.Fields(keyword), " "))
+			if keyword == "" || len(keyword) > 60 || seen[keyword] {
+				continue
+			}
+			seen[keyword] = true
+			out = append(out, keyword)
+		}
+	}
+	return out
+}
+
+func (s *Service) noise(scale float64) float64 {
+	if s.Noise != nil {
+		return s.Noise(scale)
+	}
+	return laplace(scale)
+}
+
+// laplace draws from a Laplace distribution centred on zero.
+func laplace(scale float64) float64 {
+	u := rand.Float64() - 0.5
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
+
+func windowDays(cfg Config) int {
+	return int(cfg.Window / (24 * time.Hour))
+}
+
+func (s *Service) config() Config {
+	cfg := s.Config
+	def := DefaultConfig()
+	if cfg.Window <= 0 {
+		cfg.Window = def.Window
+	}
+	if cfg.MaxAnalyses <= 0 {
+		cfg.MaxAnalyses = def.MaxAnalyses
+	}
+	if cfg.Refresh <= 0 {
+		cfg.Refresh = def.Refresh
+	}
+	if cfg.Epsilon <= 0 {
+		cfg.Epsilon = def.Epsilon
+	}
+	if cfg.MaxKeywordsPerUser <= 0 {
+		cfg.MaxKeywordsPerUser = def.MaxKeywordsPerUser
+	}
+	if cfg.MinUsers <= 0 {
+		cfg.MinUsers = def.MinUsers
+	}
+	if cfg.MinKeywordUsers <= 0 {
+		cfg.MinKeywordUsers = def.MinKeywordUsers
+	}
+	if cfg.TopN <= 0 {
+		cfg.TopN = def.TopN
+	}
+	return cfg
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,211 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+)
+
+type fakeSource struct {
+	items []analyses.Analysis
+	calls int
+}
+
+func (f *fakeSource) ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error) {
+	f.calls++
+	return f.items, nil
+}
+
+func result(role string, missing ...string) map[string]any {
+	keywords := make([]any, len(missing))
+	for i, k := range missing {
+		keywords[i] = k
+	}
+	return map[string]any{
+		"meta": map[string]any{"roleCategory": role},
+		"ats":  map[string]any{"missingKeywords": map[string]any{"fromJobDescription": keywords, "industryCommon": []any{}}},
+	}
+}
+
+func newTestService(items []analyses.Analysis) (*Service, *fakeSource) {
+	source := &fakeSource{items: items}
+	svc := NewService(source)
+	svc.Config.MinUsers = 5
+	svc.Config.MinKeywordUsers = 3
+	svc.Config.MaxKeywordsPerUser = 2
+	svc.Noise = func(float64) float64 { return 0 }
+	return svc, source
+}
+
+func TestKeywordsAreCountedOncePerUserAndThresholded(t *testing.T) {
+	var items []analyses.Analysis
+	for i := 0; i < 10; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		missing := []string{"Kubernetes"}
+		if i < 4 {
+			missing = append(missing, "gRPC")
+		}
+		if i == 0 {
+			missing = append(missing, "Terraform")
+		}
+		// A second analysis by the same user must not count twice.
+		items = append(items,
+			analyses.Analysis{UserID: user, Result: result("backend", missing...)},
+			analyses.Analysis{UserID: user, Result: result("backend", missing...)},
+		)
+	}
+	items = append(items, analyses.Analysis{UserID: "user-x", Result: result("design", "figma")})
+	svc, _ := newTestService(items)
+
+	got, err := svc.Keywords(context.Background(), "backend")
+	if err != nil {
+		t.Fatalf("keywords: %v", err)
+	}
+	if got.Suppressed || got.SampleSize != 10 {
+		t.Fatalf("unexpected report: %+v", got)
+	}
+	want := []KeywordInsight{{Keyword: "kubernetes", Share: 100}, {Keyword: "grpc", Share: 40}}
+	if len(got.Keywords) != len(want) {
+		t.Fatalf("keywords = %+v, want %+v", got.Keywords, want)
+	}
+	for i := range want {
+		if got.Keywords[i] != want[i] {
+			t.Fatalf("keywords = %+v, want %+v", got.Keywords, want)
+		}
+	}
+
+	design, _ := svc.Keywords(context.Background(), "design")
+	if !design.Suppressed || len(design.Keywords) != 0 || design.SampleSize != 0 {
+		t.Fatalf("a role with one user should be suppressed, got %+v", design)
+	}
+	none, _ := svc.Keywords(context.Background(), "mobile")
+	if !none.Suppressed {
+		t.Fatalf("a role without analyses should be suppressed, got %+v", none)
+	}
+}
+
+func TestRareKeywordsAreNotPublishedByNoise(t *testing.T) {
+	var items []analyses.Analysis
+	for i := 0; i < 10; i++ {
+		missing := []string{"Kubernetes"}
+		if i == 0 {
+			missing = append(missing, "Unique Keyword")
+		}
+		items = append(items, analyses.Analysis{UserID: fmt.Sprintf("user-%d", i), Result: result("backend", missing...)})
+	}
+	svc, _ := newTestService(items)
+	// Noise large enough to lift a count of one over the threshold.
+	svc.Noise = func(float64) float64 { return 5 }
+
+	got, err := svc.Keywords(context.Background(), "backend")
+	if err != nil {
+		t.Fatalf("keywords: %v", err)
+	}
+	for _, k := range got.Keywords {
+		if k.Keyword == "unique keyword" {
+			t.Fatalf("a keyword one user has was published: %+v", got.Keywords)
+		}
+	}
+	if len(got.Keywords) != 1 {
+		t.Fatalf("keywords = %+v", got.Keywords)
+	}
+}
+
+func TestSampleAnalysesAreLeftOut(t *testing.T) {
+	ctx := context.Background()
+	repo := analyses.NewMemoryRepo()
+	completedAt := time.Now().UTC()
+	for i := 0; i < 8; i++ {
+		a := analyses.Analysis{
+			ID:          fmt.Sprintf("a-%d", i),
+			UserID:      fmt.Sprintf("user-%d", i),
+			Status:      analyses.StatusCompleted,
+			Result:      result("backend", "Kubernetes"),
+			CompletedAt: &completedAt,
+		}
+		if i >= 5 {
+			a.Provider = analyses.SampleProvider
+			a.Result = result("backend", "Sample Keyword")
+		}
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	svc := NewService(repo)
+	svc.Config.MinUsers = 5
+	svc.Config.MinKeywordUsers = 3
+	svc.Noise = func(float64) float64 { return 0 }
+
+	got, err := svc.Keywords(ctx, "backend")
+	if err != nil {
+		t.Fatalf("keywords: %v", err)
+	}
+	// Kubernetes is missing from every real analysis and no sample one.
+	if len(got.Keywords) != 1 || got.Keywords[0].Keyword != "kubernetes" || got.Keywords[0].Share != 100 {
+		t.Fatalf("expected only the five real analyses, got %+v", got.Keywords)
+	}
+}
+
+func TestContributionsAreBoundedPerUser(t *testing.T) {
+	keywords := map[string]map[string]bool{
+		"a": {"u1": true, "u2": true},
+		"b": {"u1": true, "u2": true},
+		"c": {"u1": true},
+	}
+	got := boundContributions(keywords, 2)
+	if got["a"] != 2 || got["b"] != 2 || got["c"] != 0 {
+		t.Fatalf("bounded counts = %v", got)
+	}
+}
+
+func TestReportIsCachedSoNoiseIsNotRedrawn(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	svc, source := newTestService(nil)
+	svc.Now = func() time.Time { return now }
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Keywords(context.Background(), "backend"); err != nil {
+			t.Fatalf("keywords: %v", err)
+		}
+	}
+	if source.calls != 1 {
+		t.Fatalf("expected one build, got %d", source.calls)
+	}
+	now = now.Add(svc.Config.Refresh)
+	_, _ = svc.Keywords(context.Background(), "backend")
+	if source.calls != 2 {
+		t.Fatalf("expected a rebuild after the refresh interval, got %d builds", source.calls)
+	}
+}
+
+func TestLaplaceNoiseIsCentred(t *testing.T) {
+	sum := 0.0
+	const n = 20000
+	for i := 0; i < n; i++ {
+		sum += laplace(2)
+	}
+	if mean := sum / n; mean < -0.2 || mean > 0.2 {
+		t.Fatalf("mean = %f", mean)
+	}
+}
+
+func TestHandlerRejectsUnknownRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService(nil)
+	router := gin.New()
+	NewHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+
+	for query, want := range map[string]int{"?role=Backend": http.StatusOK, "?role=astronaut": http.StatusBadRequest, "": http.StatusBadRequest} {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/insights/keywords"+query, nil))
+		if resp.Code != want {
+			t.Errorf("%q: status %d, want %d", query, resp.Code, want)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+//go:build integration
+// +build integration
+
+// Package integration runs the API and worker against real Postgres and LocalStack
+// (S3 + SQS) containers. It needs a reachable Docker daemon:
+//
+//	go test -tags integration ./internal/integration/...
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/gin-gonic/gin"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/storage/db"
+	"resume-backend/internal/workerproc"
+)
+
+const (
+	testBucket = "resume-integration"
+	testQueue  = "resume-integration-analyses"
+	awsRegion  = "us-east-1"
+	guestID    = "integration-guest"
+)
+
+var (
+	databaseURL string
+	queueURL    string
+	awsEndpoint string
+)
+
+func TestMain(m *testing.M) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("docker pool: %v", err)
+	}
+	pool.MaxWait = 2 * time.Minute
+	if err := pool.Client.Ping(); err != nil {
+		log.Fatalf("docker unavailable: %v", err)
+	}
+
+	pg, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "16-alpine",
+		Env:        []string{"POSTGRES_PASSWORD=secret", "POSTGRES_USER=resume", "POSTGRES_DB=resume"},
+	}, autoRemove)
+	if err != nil {
+		log.Fatalf("start postgres: %v", err)
+	}
+	stack, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "localstack/localstack",
+		Tag:        "3",
+		Env:        []string{"SERVICES=s3,sqs"},
+	}, autoRemove)
+	if err != nil {
+		purge(pool, pg)
+		log.Fatalf("start localstack: %v", err)
+	}
+
+	databaseURL = fmt.Sprintf("postgres://resume:secret@%s/resume?sslmode=disable", pg.GetHostPort("5432/tcp"))
+	awsEndpoint = "http://" + stack.GetHostPort("4566/tcp")
+
+	code := 1
+	if err := setup(pool); err != nil {
+		log.Printf("integration setup: %v", err)
+	} else {
+		code = m.Run()
+	}
+
+	purge(pool, pg, stack)
+	os.Exit(code)
+}
+
+func autoRemove(hc *docker.HostConfig) {
+	hc.AutoRemove = true
+	hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+}
+
+func purge(pool *dockertest.Pool, resources ...*dockertest.Resource) {
+	for _, r := range resources {
+		if err := pool.Purge(r); err != nil {
+			log.Printf("purge %s: %v", r.Container.Name, err)
+		}
+	}
+}
+
+func setup(pool *dockertest.Pool) error {
+	ctx := context.Background()
+
+	var sqlDB *sql.DB
+	if err := pool.Retry(func() error {
+		var err error
+		sqlDB, err = db.Connect(ctx, databaseURL, db.DefaultMigrateOptions())
+		return err
+	}); err != nil {
+		return fmt.Errorf("connect postgres: %w", err)
+	}
+	defer sqlDB.Close()
+	if err := db.RunMigrations(ctx, sqlDB); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(awsRegion),
+		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("test", "test", "")),
+	)
+	if err != nil {
+		return err
+	}
+	s3Client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(awsEndpoint)
+		o.UsePathStyle = true
+	})
+	sqsClient := sqs.NewFromConfig(awsCfg, func(o *sqs.Options) {
+		o.BaseEndpoint = aws.String(awsEndpoint)
+	})
+
+	if err := pool.Retry(func() error {
+		_, err := s3Client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(testBucket)})
+		return err
+	}); err != nil {
+		return fmt.Errorf("create bucket: %w", err)
+	}
+	out, err := sqsClient.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: aws.String(testQueue)})
+	if err != nil {
+		return fmt.Errorf("create queue: %w", err)
+	}
+	queueURL = aws.ToString(out.QueueUrl)
+
+	env := map[string]string{
+		"DATABASE_URL":           databaseURL,
+		"AWS_ENDPOINT_URL":       awsEndpoint,
+		"AWS_ACCESS_KEY_ID":      "test",
+		"AWS_SECRET_ACCESS_KEY":  "test",
+		"AWS_REGION":             awsRegion,
+		"S3_BUCKET":              testBucket,
+		"RA_S3_FORCE_PATH_STYLE": "true",
+		"RA_SQS_QUEUE_URL":       queueURL,
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixtureLLM returns the canned v2_3 analysis used by the analyses package tests.
+type fixtureLLM struct {
+	payload []byte
+}
+
+func (f fixtureLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	return json.RawMessage(f.payload), nil
+}
+
+// fixtureResumeLLM returns a fixed ResumeModel for the apply pipeline.
+type fixtureResumeLLM struct{}
+
+func (fixtureResumeLLM) Complete(ctx context.Context, prompt string) (string, error) {
+	return `{
+		"header": {"name": "Jane Doe", "title": "Backend Engineer", "email": "jane@example.com", "phone": "555-0100", "location": "Austin, TX", "links": []},
+		"summary": ["Backend engineer focused on Go services."],
+		"skills": {"languages": ["Go", "SQL"], "frameworks": [], "databases": ["PostgreSQL"], "cloudDevOps": ["AWS"], "observability": [], "tools": []},
+		"experience": [{"id": "exp-1", "company": "Acme", "role": "Engineer", "location": "Remote", "start": "2020-01", "end": "Present", "highlights": ["Built APIs."]}],
+		"projects": [],
+		"education": [{"institution": "State University", "degree": "BS", "field": "Computer Science", "location": "Austin, TX", "start": "2014-08", "end": "2018-05", "highlights": []}],
+		"achievements": [],
+		"certifications": []
+	}`, nil
+}
+
+func TestUploadAnalyzeApplyDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// The DOCX template is resolved relative to the repository root.
+	t.Chdir(filepath.Join("..", ".."))
+
+	payload, err := os.ReadFile(filepath.Join("internal", "analyses", "testdata", "v2_3_good.json"))
+	if err != nil {
+		t.Fatalf("read llm fixture: %v", err)
+	}
+
+	app, err := bootstrap.Build(config.Config{
+		Env:             "dev",
+		ObjectStoreType: "s3",
+		AWSRegion:       awsRegion,
+		S3Bucket:        testBucket,
+		DatabaseURL:     databaseURL,
+		LLMProvider:     "fixture",
+		LLMModel:        "fixture-model",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	if app.DB == nil {
+		t.Fatalf("expected postgres-backed app")
+	}
+	app.AnalysesService.LLM = fixtureLLM{payload: payload}
+	app.ApplyService.LLM = fixtureResumeLLM{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		runWorker(ctx, t, app)
+	}()
+	defer func() {
+		cancel()
+		<-workerDone
+	}()
+
+	router := app.Router
+
+	docID := uploadResume(t, router)
+
+	analyzeBody, _ := json.Marshal(map[string]string{
+		"mode":           "JOB_MATCH",
+		"jobDescription": strings.Repeat("We need a backend engineer with Go, PostgreSQL and AWS experience. ", 8),
+	})
+	resp := do(t, router, http.MethodPost, "/api/v1/documents/"+docID+"/analyze", bytes.NewReader(analyzeBody), "application/json")
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("analyze: expected 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var started struct {
+		AnalysisID string `json:"analysisId"`
+	}
+	decode(t, resp, &started)
+
+	deadline := time.Now().Add(90 * time.Second)
+	for {
+		resp = do(t, router, http.MethodGet, "/api/v1/analyses/"+started.AnalysisID, nil, "")
+		var status struct {
+			Status       string `json:"status"`
+			ErrorMessage string `json:"errorMessage"`
+		}
+		decode(t, resp, &status)
+		if status.Status == "completed" {
+			break
+		}
+		if status.Status == "failed" {
+			t.Fatalf("analysis failed: %s", status.ErrorMessage)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("analysis did not complete, last status %q", status.Status)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	resp = do(t, router, http.MethodPost, "/api/v1/analyses/"+started.AnalysisID+"/apply", nil, "")
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("apply: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var generated struct {
+		GeneratedResumeID string `json:"generatedResumeId"`
+	}
+	decode(t, resp, &generated)
+	if generated.GeneratedResumeID == "" {
+		t.Fatalf("apply response missing generatedResumeId: %s", resp.Body.String())
+	}
+
+	resp = do(t, router, http.MethodGet, "/api/v1/generated-resumes/"+generated.GeneratedResumeID+"/download", nil, "")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("download: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if _, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len())); err != nil {
+		t.Fatalf("download is not a docx: %v", err)
+	}
+}
+
+// runWorker mirrors cmd/worker's receive/handle/delete loop against LocalStack.
+func runWorker(ctx context.Context, t *testing.T, app *bootstrap.App) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(awsRegion))
+	if err != nil {
+		t.Errorf("worker aws config: %v", err)
+		return
+	}
+	client := sqs.NewFromConfig(awsCfg)
+	for ctx.Err() == nil {
+		out, err := client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     1,
+		})
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return
+			}
+			t.Logf("worker receive: %v", err)
+			time.Sleep(200 * time.Millisecond)
+			continue
+		}
+		for _, msg := range out.Messages {
+			if err := workerproc.HandleMessage(ctx, app, aws.ToString(msg.Body)); err != nil {
+				t.Logf("worker handle: %v", err)
+				continue
+			}
+			_, _ = client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(queueURL),
+				ReceiptHandle: msg.ReceiptHandle,
+			})
+		}
+	}
+}
+
+func uploadResume(t *testing.T, router http.Handler) string {
+	t.Helper()
+
+	var docx bytes.Buffer
+	zw := zip.NewWriter(&docx)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("create document.xml: %v", err)
+	}
+	body := `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>Jane Doe</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>jane@example.com | 555-0100 | Austin, TX</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>Engineer, Acme, 2020-01 to Present. Built APIs in Go on AWS with PostgreSQL.</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>BS Computer Science, State University, 2018.</w:t></w:r></w:p>` +
+		`</w:body></w:document>`
+	if _, err := w.Write([]byte(body)); err != nil {
+		t.Fatalf("write document.xml: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	form := &bytes.Buffer{}
+	mw := multipart.NewWriter(form)
+	fw, err := mw.CreateFormFile("file", "resume.docx")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fw.Write(docx.Bytes()); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("close multipart: %v", err)
+	}
+
+	resp := do(t, router, http.MethodPost, "/api/v1/documents", form, mw.FormDataContentType())
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	decode(t, resp, &created)
+	return created.DocumentID
+}
+
+func do(t *testing.T, router http.Handler, method, path string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("X-Guest-Id", guestID)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func decode(t *testing.T, resp *httptest.ResponseRecorder, out any) {
+	t.Helper()
+	if err := json.Unmarshal(resp.Body.Bytes(), out); err != nil {
+		t.Fatalf("decode response: %v: %s", err, resp.Body.String())
+	}
+}
@@ -0,0 +1,108 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const pushTimeout = 30 * time.Second
+
+// Adapter pushes a completed apply run to one kind of external system.
+type Adapter interface {
+	// Validate checks the settings before the integration is saved.
+	Validate(settings Settings) error
+	Push(ctx context.Context, integration Integration, credential string, payload Payload) error
+}
+
+// DefaultAdapters returns the built-in adapters sharing one HTTP client.
+func DefaultAdapters(client *http.Client) map[Provider]Adapter {
+	if client == nil {
+		client = &http.Client{Timeout: pushTimeout}
+	}
+	return map[Provider]Adapter{
+		ProviderGreenhouse: &GreenhouseAdapter{Client: client},
+		ProviderLever:      &LeverAdapter{Client: client},
+		ProviderWebhook:    &WebhookAdapter{Client: client},
+	}
+}
+
+// StatusError reports a non-2xx response from an external system.
+type StatusError struct {
+	Provider Provider
+	Status   int
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s responded %d: %s", e.Provider, e.Status, e.Body)
+}
+
+// do sends req and returns the response body, or a StatusError for a non-2xx
+// status. Error bodies are truncated so they fit in the delivery record.
+func do(client *http.Client, provider Provider, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		text := strings.TrimSpace(string(body))
+		if len(text) > 200 {
+			text = text[:200]
+		}
+		return nil, &StatusError{Provider: provider, Status: resp.StatusCode, Body: text}
+	}
+	return body, nil
+}
+
+func newRequest(ctx context.Context, method, url, contentType string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// summaryNote is the plain-text analysis summary attached to ATS candidates.
+func summaryNote(payload Payload) string {
+	var b strings.Builder
+	b.WriteString("Resume generated by an apply run")
+	if payload.Status != "" {
+		fmt.Fprintf(&b, " (%s)", strings.ToLower(payload.Status))
+	}
+	b.WriteString(".")
+	if score := payload.Analysis.FinalScore; score != nil {
+		fmt.Fprintf(&b, "\nAnalysis score: %.0f/100", *score)
+		if payload.Analysis.Mode != "" {
+			fmt.Fprintf(&b, " (%s)", payload.Analysis.Mode)
+		}
+		b.WriteString(".")
+	}
+	fmt.Fprintf(&b, "\nApply run: %s", payload.ApplyRunID)
+	return b.String()
+}
+
+// splitName splits a full name into first and last name for APIs that need both.
+func splitName(name string) (string, string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return parts[0], ""
+	default:
+		return strings.Join(parts[:len(parts)-1], " "), parts[len(parts)-1]
+	}
+}
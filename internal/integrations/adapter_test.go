@@ -0,0 +1,153 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordedCall struct {
+	Path        string
+	Query       string
+	ContentType string
+	User        string
+	OnBehalfOf  string
+	Body        []byte
+}
+
+func recordingServer(t *testing.T, respond func(path string) string) (*httptest.Server, func() []recordedCall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []recordedCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		user, _, _ := r.BasicAuth()
+		mu.Lock()
+		calls = append(calls, recordedCall{
+			Path:        r.URL.Path,
+			Query:       r.URL.RawQuery,
+			ContentType: r.Header.Get("Content-Type"),
+			User:        user,
+			OnBehalfOf:  r.Header.Get("On-Behalf-Of"),
+			Body:        body,
+		})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, respond(r.URL.Path))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []recordedCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedCall(nil), calls...)
+	}
+}
+
+func testPayload() Payload {
+	score := 82.0
+	return Payload{
+		Event:      EventApplyRunCompleted,
+		ApplyRunID: "run-1",
+		Status:     "FINAL",
+		Candidate:  Candidate{Name: "Ada King Lovelace", Email: "ada@example.com", Phone: "+44 20 7946 0958"},
+		Analysis:   AnalysisSummary{Mode: "job_match", FinalScore: &score},
+		Resume:     Resume{FileName: "ada.docx", MimeType: docxMimeType, Content: []byte("docx")},
+	}
+}
+
+func TestGreenhouseAdapterCreatesCandidateThenAttachesResumeAndNote(t *testing.T) {
+	server, calls := recordingServer(t, func(path string) string {
+		if path == "/candidates" {
+			return `{"id": 42}`
+		}
+		return `{}`
+	})
+	adapter := &GreenhouseAdapter{Client: server.Client(), BaseURL: server.URL}
+	integration := Integration{Provider: ProviderGreenhouse, Settings: Settings{OnBehalfOf: "7", JobID: "99"}}
+	if err := adapter.Validate(integration.Settings); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := adapter.Push(context.Background(), integration, "harvest-key", testPayload()); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	got := calls()
+	wantPaths := []string{"/candidates", "/candidates/42/attachments", "/candidates/42/activity_feed/notes"}
+	if len(got) != len(wantPaths) {
+		t.Fatalf("expected %d calls, got %+v", len(wantPaths), got)
+	}
+	for i, call := range got {
+		if call.Path != wantPaths[i] || call.User != "harvest-key" || call.OnBehalfOf != "7" {
+			t.Fatalf("call %d: unexpected %+v", i, call)
+		}
+	}
+	var candidate greenhouseCandidate
+	if err := json.Unmarshal(got[0].Body, &candidate); err != nil {
+		t.Fatalf("decode candidate: %v", err)
+	}
+	if candidate.FirstName != "Ada King" || candidate.LastName != "Lovelace" || len(candidate.Applications) != 1 || candidate.Applications[0].JobID != 99 {
+		t.Fatalf("unexpected candidate: %+v", candidate)
+	}
+	var attachment greenhouseAttachment
+	if err := json.Unmarshal(got[1].Body, &attachment); err != nil {
+		t.Fatalf("decode attachment: %v", err)
+	}
+	if attachment.Type != "resume" || string(attachment.Content) != "docx" {
+		t.Fatalf("unexpected attachment: %+v", attachment)
+	}
+	if !strings.Contains(string(got[2].Body), "82/100") {
+		t.Fatalf("expected score in note, got %s", got[2].Body)
+	}
+}
+
+func TestGreenhouseAdapterRejectsNonNumericUser(t *testing.T) {
+	adapter := &GreenhouseAdapter{}
+	if err := adapter.Validate(Settings{OnBehalfOf: "jane"}); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestLeverAdapterUploadsResumeAsOpportunity(t *testing.T) {
+	server, calls := recordingServer(t, func(path string) string {
+		if path == "/opportunities" {
+			return `{"data": {"id": "opp-1"}}`
+		}
+		return `{}`
+	})
+	adapter := &LeverAdapter{Client: server.Client(), BaseURL: server.URL}
+	integration := Integration{Provider: ProviderLever, Settings: Settings{OnBehalfOf: "user-uuid"}}
+	if err := adapter.Push(context.Background(), integration, "lever-key", testPayload()); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	got := calls()
+	if len(got) != 2 || got[0].Path != "/opportunities" || got[1].Path != "/opportunities/opp-1/notes" {
+		t.Fatalf("unexpected calls: %+v", got)
+	}
+	if got[0].Query != "perform_as=user-uuid" || got[0].User != "lever-key" {
+		t.Fatalf("unexpected auth on create: %+v", got[0])
+	}
+	if !strings.HasPrefix(got[0].ContentType, "multipart/form-data") || !strings.Contains(string(got[0].Body), `name="resumeFile"; filename="ada.docx"`) {
+		t.Fatalf("expected multipart resume upload, got %s", got[0].Body)
+	}
+}
+
+func TestPushReportsProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid job", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	adapter := &GreenhouseAdapter{Client: server.Client(), BaseURL: server.URL}
+	err := adapter.Push(context.Background(), Integration{Settings: Settings{OnBehalfOf: "7"}}, "key", testPayload())
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != http.StatusUnprocessableEntity || statusErr.Body != "invalid job" {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package integrations
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the integration was not found in the organization.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrUnavailable indicates integrations cannot be used because no secrets
+	// store is configured.
+	ErrUnavailable = errors.New("integrations unavailable")
+)
@@ -0,0 +1,139 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultGreenhouseURL = "https://harvest.greenhouse.io/v1"
+
+// GreenhouseAdapter creates a candidate through the Greenhouse Harvest API,
+// attaches the resume and adds the analysis summary as a private note.
+type GreenhouseAdapter struct {
+	Client *http.Client
+	// BaseURL overrides the Harvest API root.
+	BaseURL string
+}
+
+// Validate requires the Greenhouse user to act as; a job ID is optional.
+func (a *GreenhouseAdapter) Validate(settings Settings) error {
+	if strings.TrimSpace(settings.OnBehalfOf) == "" {
+		return fmt.Errorf("%w: onBehalfOf is required for greenhouse", ErrInvalidInput)
+	}
+	if _, err := strconv.ParseInt(settings.OnBehalfOf, 10, 64); err != nil {
+		return fmt.Errorf("%w: onBehalfOf must be a Greenhouse user ID", ErrInvalidInput)
+	}
+	if settings.JobID != "" {
+		if _, err := strconv.ParseInt(settings.JobID, 10, 64); err != nil {
+			return fmt.Errorf("%w: jobId must be a Greenhouse job ID", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+type greenhouseContact struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+type greenhouseApplication struct {
+	JobID int64 `json:"job_id"`
+}
+
+type greenhouseCandidate struct {
+	FirstName      string                  `json:"first_name"`
+	LastName       string                  `json:"last_name"`
+	EmailAddresses []greenhouseContact     `json:"email_addresses,omitempty"`
+	PhoneNumbers   []greenhouseContact     `json:"phone_numbers,omitempty"`
+	Applications   []greenhouseApplication `json:"applications,omitempty"`
+}
+
+type greenhouseAttachment struct {
+	Filename    string `json:"filename"`
+	Type        string `json:"type"`
+	Content     []byte `json:"content"`
+	ContentType string `json:"content_type"`
+}
+
+type greenhouseNote struct {
+	UserID     int64  `json:"user_id"`
+	Body       string `json:"body"`
+	Visibility string `json:"visibility"`
+}
+
+// Push creates the candidate, then attaches the resume and the summary note.
+func (a *GreenhouseAdapter) Push(ctx context.Context, integration Integration, credential string, payload Payload) error {
+	userID, err := strconv.ParseInt(integration.Settings.OnBehalfOf, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: onBehalfOf must be a Greenhouse user ID", ErrInvalidInput)
+	}
+	first, last := splitName(payload.Candidate.Name)
+	candidate := greenhouseCandidate{FirstName: first, LastName: last}
+	if payload.Candidate.Email != "" {
+		candidate.EmailAddresses = []greenhouseContact{{Value: payload.Candidate.Email, Type: "personal"}}
+	}
+	if payload.Candidate.Phone != "" {
+		candidate.PhoneNumbers = []greenhouseContact{{Value: payload.Candidate.Phone, Type: "mobile"}}
+	}
+	if integration.Settings.JobID != "" {
+		jobID, err := strconv.ParseInt(integration.Settings.JobID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: jobId must be a Greenhouse job ID", ErrInvalidInput)
+		}
+		candidate.Applications = []greenhouseApplication{{JobID: jobID}}
+	}
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := a.post(ctx, credential, userID, "/candidates", candidate, &created); err != nil {
+		return fmt.Errorf("create candidate: %w", err)
+	}
+	if created.ID == 0 {
+		return fmt.Errorf("create candidate: greenhouse returned no candidate id")
+	}
+
+	candidatePath := fmt.Sprintf("/candidates/%d", created.ID)
+	attachment := greenhouseAttachment{
+		Filename:    payload.Resume.FileName,
+		Type:        "resume",
+		Content:     payload.Resume.Content,
+		ContentType: payload.Resume.MimeType,
+	}
+	if err := a.post(ctx, credential, userID, candidatePath+"/attachments", attachment, nil); err != nil {
+		return fmt.Errorf("attach resume: %w", err)
+	}
+	note := greenhouseNote{UserID: userID, Body: summaryNote(payload), Visibility: "private"}
+	if err := a.post(ctx, credential, userID, candidatePath+"/activity_feed/notes", note, nil); err != nil {
+		return fmt.Errorf("add note: %w", err)
+	}
+	return nil
+}
+
+func (a *GreenhouseAdapter) post(ctx context.Context, credential string, userID int64, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	base := a.BaseURL
+	if base == "" {
+		base = defaultGreenhouseURL
+	}
+	req, err := newRequest(ctx, http.MethodPost, strings.TrimRight(base, "/")+path, "application/json", payload)
+	if err != nil {
+		return err
+	}
+	// Harvest authenticates with the API key as the basic auth user name and
+	// records every write against the On-Behalf-Of user.
+	req.SetBasicAuth(credential, "")
+	req.Header.Set("On-Behalf-Of", strconv.FormatInt(userID, 10))
+	respBody, err := do(a.Client, ProviderGreenhouse, req)
+	if err != nil || out == nil {
+		return err
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,108 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/usage"
+)
+
+// Handler exposes organization integration endpoints.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches integration routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/orgs/:orgId/integrations", h.create)
+	rg.GET("/orgs/:orgId/integrations", h.list)
+	rg.DELETE("/orgs/:orgId/integrations/:id", h.delete)
+}
+
+type createRequest struct {
+	Provider   Provider `json:"provider"`
+	Name       string   `json:"name"`
+	Settings   Settings `json:"settings"`
+	Credential string   `json:"credential"`
+}
+
+func (h *Handler) create(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	var req createRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	integration, err := h.Svc.Create(c.Request.Context(), c.Param("orgId"), middleware.UserIDFromContext(c), CreateInput{
+		Provider:   req.Provider,
+		Name:       req.Name,
+		Settings:   req.Settings,
+		Credential: req.Credential,
+	})
+	if err != nil {
+		writeError(c, err, "failed to create integration")
+		return
+	}
+	respond.JSON(c, http.StatusCreated, integration)
+}
+
+func (h *Handler) list(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	items, err := h.Svc.List(c.Request.Context(), c.Param("orgId"), middleware.UserIDFromContext(c))
+	if err != nil {
+		writeError(c, err, "failed to list integrations")
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) delete(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	if err := h.Svc.Delete(c.Request.Context(), c.Param("orgId"), middleware.UserIDFromContext(c), c.Param("id")); err != nil {
+		writeError(c, err, "failed to delete integration")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func requireLogin(c *gin.Context) bool {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to manage integrations", nil)
+		return false
+	}
+	return true
+}
+
+// writeError hides whether an organization exists from non-members.
+func writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, usage.ErrOrgNotFound), errors.Is(err, usage.ErrNotOrgMember):
+		respond.Error(c, http.StatusNotFound, "not_found", "organization not found", nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "integration not found", nil)
+	case errors.Is(err, ErrUnavailable):
+		respond.Error(c, http.StatusServiceUnavailable, "integrations_unavailable", "integrations are not configured on this server", nil)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		respond.Error(c, http.StatusRequestTimeout, "timeout", "request canceled", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", message, nil)
+	}
+}
@@ -0,0 +1,191 @@
+package integrations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/integrations"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/usage"
+	"resume-backend/resume/model"
+)
+
+func newTestApp(t *testing.T) *bootstrap.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	return app
+}
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func doRequest(router http.Handler, method, path, authorization string, body any) *httptest.ResponseRecorder {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+type listedIntegration struct {
+	ID           string `json:"id"`
+	Provider     string `json:"provider"`
+	Credential   string `json:"credential"`
+	LastDelivery *struct {
+		ApplyRunID string `json:"applyRunId"`
+		Status     string `json:"status"`
+		Error      string `json:"error"`
+	} `json:"lastDelivery"`
+}
+
+func listIntegrations(t *testing.T, router http.Handler, authorization string) []listedIntegration {
+	t.Helper()
+	resp := doRequest(router, http.MethodGet, "/api/v1/orgs/acme/integrations", authorization, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var body struct {
+		Items []listedIntegration `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	return body.Items
+}
+
+func TestWebhookIntegrationReceivesSignedApplyRuns(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	if _, err := app.UsageService.SetOrgQuota(ctx, "acme", "Team", 100, 0); err != nil {
+		t.Fatalf("set org quota: %v", err)
+	}
+	for _, userID := range []string{"recruiter-a", "recruiter-b"} {
+		if _, err := app.UsageService.AddOrgMember(ctx, "acme", userID); err != nil {
+			t.Fatalf("add member: %v", err)
+		}
+	}
+	alice := bearer(t, "recruiter-a")
+	bob := bearer(t, "recruiter-b")
+
+	const secret = "whsec-test"
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	create := map[string]any{
+		"provider":   "webhook",
+		"name":       "Internal ATS",
+		"settings":   map[string]string{"url": receiver.URL},
+		"credential": secret,
+	}
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/orgs/acme/integrations", bearer(t, "outsider"), create); resp.Code != http.StatusNotFound {
+		t.Fatalf("non-member create: expected 404, got %d", resp.Code)
+	}
+	bad := map[string]any{"provider": "greenhouse", "credential": "key"}
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/orgs/acme/integrations", alice, bad); resp.Code != http.StatusBadRequest {
+		t.Fatalf("greenhouse without onBehalfOf: expected 400, got %d", resp.Code)
+	}
+	resp := doRequest(app.Router, http.MethodPost, "/api/v1/orgs/acme/integrations", alice, create)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if strings.Contains(resp.Body.String(), secret) {
+		t.Fatalf("credential leaked in response: %s", resp.Body.String())
+	}
+
+	app.IntegrationsService.ApplyRunCompleted(ctx, usage.ApplyCompletion{
+		OrgID:      "acme",
+		UserID:     "recruiter-b",
+		ApplyRunID: "run-1",
+		Status:     usage.ApplyRunStatusFinal,
+		FileName:   "resume.docx",
+		Docx:       []byte("docx-bytes"),
+		Header:     model.ResumeHeader{Name: "Ada Lovelace", Email: "ada@example.com"},
+	})
+	app.IntegrationsService.Wait()
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not delivered")
+	}
+	signature := req.Header.Get(integrations.SignatureHeader)
+	ts, _, _ := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		t.Fatalf("unexpected signature %q", signature)
+	}
+	if want := integrations.Sign(secret, time.Unix(unix, 0), body); signature != want {
+		t.Fatalf("signature mismatch: got %q want %q", signature, want)
+	}
+	if req.Header.Get(integrations.EventHeader) != integrations.EventApplyRunCompleted {
+		t.Fatalf("unexpected event header %q", req.Header.Get(integrations.EventHeader))
+	}
+	var payload integrations.Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.ApplyRunID != "run-1" || payload.Candidate.Email != "ada@example.com" || string(payload.Resume.Content) != "docx-bytes" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	items := listIntegrations(t, app.Router, bob)
+	if len(items) != 1 || items[0].LastDelivery == nil || items[0].LastDelivery.Status != integrations.DeliveryDelivered || items[0].LastDelivery.ApplyRunID != "run-1" {
+		t.Fatalf("expected delivered status, got %+v", items)
+	}
+
+	if resp := doRequest(app.Router, http.MethodDelete, "/api/v1/orgs/acme/integrations/"+items[0].ID, bob, nil); resp.Code != http.StatusNoContent {
+		t.Fatalf("delete: expected 204, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if items := listIntegrations(t, app.Router, alice); len(items) != 0 {
+		t.Fatalf("expected no integrations after delete, got %+v", items)
+	}
+}
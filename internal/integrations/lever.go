@@ -0,0 +1,98 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultLeverURL = "https://api.lever.co/v1"
+
+// LeverAdapter creates an opportunity through the Lever API with the resume
+// attached, then adds the analysis summary as a note.
+type LeverAdapter struct {
+	Client *http.Client
+	// BaseURL overrides the Lever API root.
+	BaseURL string
+}
+
+// Validate requires the Lever user to perform the calls as.
+func (a *LeverAdapter) Validate(settings Settings) error {
+	if strings.TrimSpace(settings.OnBehalfOf) == "" {
+		return fmt.Errorf("%w: onBehalfOf is required for lever", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Push creates the opportunity, then adds the summary note.
+func (a *LeverAdapter) Push(ctx context.Context, integration Integration, credential string, payload Payload) error {
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	fields := [][2]string{
+		{"name", payload.Candidate.Name},
+		{"emails[]", payload.Candidate.Email},
+		{"phones[]", payload.Candidate.Phone},
+		{"location", payload.Candidate.Location},
+		{"sources[]", "Resume Analyzer"},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	file, err := writer.CreateFormFile("resumeFile", payload.Resume.FileName)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(payload.Resume.Content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	body, err := a.send(ctx, credential, integration.Settings.OnBehalfOf, "/opportunities", writer.FormDataContentType(), form.Bytes())
+	if err != nil {
+		return fmt.Errorf("create opportunity: %w", err)
+	}
+	if err := json.Unmarshal(body, &created); err != nil || created.Data.ID == "" {
+		return fmt.Errorf("create opportunity: lever returned no opportunity id")
+	}
+
+	note, err := json.Marshal(map[string]string{"value": summaryNote(payload)})
+	if err != nil {
+		return err
+	}
+	path := "/opportunities/" + url.PathEscape(created.Data.ID) + "/notes"
+	if _, err := a.send(ctx, credential, integration.Settings.OnBehalfOf, path, "application/json", note); err != nil {
+		return fmt.Errorf("add note: %w", err)
+	}
+	return nil
+}
+
+func (a *LeverAdapter) send(ctx context.Context, credential, performAs, path, contentType string, body []byte) ([]byte, error) {
+	base := a.BaseURL
+	if base == "" {
+		base = defaultLeverURL
+	}
+	target := strings.TrimRight(base, "/") + path + "?perform_as=" + url.QueryEscape(performAs)
+	req, err := newRequest(ctx, http.MethodPost, target, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(credential, "")
+	return do(a.Client, ProviderLever, req)
+}
@@ -0,0 +1,93 @@
+package integrations
+
+import "time"
+
+// Provider names the external system an integration pushes to.
+type Provider string
+
+const (
+	ProviderGreenhouse Provider = "greenhouse"
+	ProviderLever      Provider = "lever"
+	// ProviderWebhook posts a signed JSON payload to any HTTPS endpoint.
+	ProviderWebhook Provider = "webhook"
+)
+
+// EventApplyRunCompleted is sent when an apply run produces a resume.
+const EventApplyRunCompleted = "apply_run.completed"
+
+// Delivery outcomes recorded on an integration.
+const (
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Settings are the non-secret options of an integration.
+type Settings struct {
+	// URL is the webhook endpoint. Only used by ProviderWebhook.
+	URL string `json:"url,omitempty"`
+	// JobID is the Greenhouse job new candidates apply to.
+	JobID string `json:"jobId,omitempty"`
+	// OnBehalfOf is the Greenhouse or Lever user the API calls are made as.
+	OnBehalfOf string `json:"onBehalfOf,omitempty"`
+}
+
+// Integration pushes an organization's completed apply runs to an external ATS.
+// The credential (API key or webhook signing secret) lives in the secrets store
+// under SecretRef.
+type Integration struct {
+	ID           string    `json:"id"`
+	OrgID        string    `json:"orgId"`
+	Provider     Provider  `json:"provider"`
+	Name         string    `json:"name"`
+	Settings     Settings  `json:"settings"`
+	SecretRef    string    `json:"-"`
+	Enabled      bool      `json:"enabled"`
+	CreatedBy    string    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastDelivery *Delivery `json:"lastDelivery,omitempty"`
+}
+
+// Delivery is the outcome of the latest push of an integration.
+type Delivery struct {
+	ApplyRunID string    `json:"applyRunId"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Payload is what an apply run completion sends to an external system.
+type Payload struct {
+	Event             string          `json:"event"`
+	DeliveryID        string          `json:"deliveryId"`
+	OrgID             string          `json:"orgId"`
+	ApplyRunID        string          `json:"applyRunId"`
+	AnalysisID        string          `json:"analysisId"`
+	DocumentVersionID string          `json:"documentVersionId"`
+	Status            string          `json:"status"`
+	Candidate         Candidate       `json:"candidate"`
+	Analysis          AnalysisSummary `json:"analysis"`
+	Resume            Resume          `json:"resume"`
+	OccurredAt        time.Time       `json:"occurredAt"`
+}
+
+// Candidate holds the contact details rendered on the resume.
+type Candidate struct {
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// AnalysisSummary is the part of the analysis shared with the ATS.
+type AnalysisSummary struct {
+	Mode          string   `json:"mode,omitempty"`
+	PromptVersion string   `json:"promptVersion,omitempty"`
+	FinalScore    *float64 `json:"finalScore,omitempty"`
+}
+
+// Resume is the generated DOCX. Content is base64 encoded in JSON.
+type Resume struct {
+	FileName string `json:"fileName"`
+	MimeType string `json:"mimeType"`
+	Content  []byte `json:"content"`
+}
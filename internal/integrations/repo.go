@@ -0,0 +1,14 @@
+package integrations
+
+import "context"
+
+// Repo persists integrations.
+type Repo interface {
+	Create(ctx context.Context, integration Integration) error
+	// Get returns ErrNotFound unless the integration belongs to the organization.
+	Get(ctx context.Context, orgID, id string) (Integration, error)
+	// List returns the organization's integrations, oldest first.
+	List(ctx context.Context, orgID string) ([]Integration, error)
+	Delete(ctx context.Context, orgID, id string) error
+	RecordDelivery(ctx context.Context, id string, delivery Delivery) error
+}
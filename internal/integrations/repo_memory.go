@@ -0,0 +1,93 @@
+package integrations
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo stores integrations in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu   sync.RWMutex
+	byID map[string]Integration
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{byID: make(map[string]Integration)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Create stores a new integration.
+func (r *MemoryRepo) Create(ctx context.Context, integration Integration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byID[integration.ID] = integration
+	return nil
+}
+
+// Get returns one of the organization's integrations.
+func (r *MemoryRepo) Get(ctx context.Context, orgID, id string) (Integration, error) {
+	if err := ctx.Err(); err != nil {
+		return Integration{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	integration, ok := r.byID[id]
+	if !ok || integration.OrgID != orgID {
+		return Integration{}, ErrNotFound
+	}
+	return integration, nil
+}
+
+// List returns the organization's integrations, oldest first.
+func (r *MemoryRepo) List(ctx context.Context, orgID string) ([]Integration, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Integration, 0)
+	for _, integration := range r.byID {
+		if integration.OrgID == orgID {
+			out = append(out, integration)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Delete removes one of the organization's integrations.
+func (r *MemoryRepo) Delete(ctx context.Context, orgID, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	integration, ok := r.byID[id]
+	if !ok || integration.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(r.byID, id)
+	return nil
+}
+
+// RecordDelivery stores the outcome of the latest push.
+func (r *MemoryRepo) RecordDelivery(ctx context.Context, id string, delivery Delivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	integration, ok := r.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	integration.LastDelivery = &delivery
+	r.byID[id] = integration
+	return nil
+}
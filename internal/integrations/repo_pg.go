@@ -0,0 +1,140 @@
+package integrations
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const integrationColumns = `id, org_id, provider, name, settings, secret_ref, enabled, created_by, created_at,
+       last_delivery_run_id, last_delivery_status, last_delivery_error, last_delivery_at`
+
+// Create inserts an integration.
+func (r *PGRepo) Create(ctx context.Context, integration Integration) error {
+	settings, err := json.Marshal(integration.Settings)
+	if err != nil {
+		return err
+	}
+	const query = `
+INSERT INTO org_integrations (id, org_id, provider, name, settings, secret_ref, enabled, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = r.DB.ExecContext(ctx, query,
+		integration.ID,
+		integration.OrgID,
+		string(integration.Provider),
+		integration.Name,
+		settings,
+		integration.SecretRef,
+		integration.Enabled,
+		integration.CreatedBy,
+		integration.CreatedAt,
+	)
+	return err
+}
+
+// Get returns one of the organization's integrations.
+func (r *PGRepo) Get(ctx context.Context, orgID, id string) (Integration, error) {
+	const query = `SELECT ` + integrationColumns + ` FROM org_integrations WHERE org_id = $1 AND id = $2`
+	integration, err := scanIntegration(r.DB.QueryRowContext(ctx, query, orgID, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Integration{}, ErrNotFound
+	}
+	return integration, err
+}
+
+// List returns the organization's integrations, oldest first.
+func (r *PGRepo) List(ctx context.Context, orgID string) ([]Integration, error) {
+	const query = `SELECT ` + integrationColumns + ` FROM org_integrations WHERE org_id = $1 ORDER BY created_at`
+	rows, err := r.DB.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]Integration, 0)
+	for rows.Next() {
+		integration, err := scanIntegration(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, integration)
+	}
+	return out, rows.Err()
+}
+
+// Delete removes one of the organization's integrations.
+func (r *PGRepo) Delete(ctx context.Context, orgID, id string) error {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM org_integrations WHERE org_id = $1 AND id = $2`, orgID, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordDelivery stores the outcome of the latest push.
+func (r *PGRepo) RecordDelivery(ctx context.Context, id string, delivery Delivery) error {
+	const query = `
+UPDATE org_integrations
+SET last_delivery_run_id = $2, last_delivery_status = $3, last_delivery_error = $4, last_delivery_at = $5
+WHERE id = $1`
+	_, err := r.DB.ExecContext(ctx, query, id, delivery.ApplyRunID, delivery.Status, delivery.Error, delivery.At)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIntegration(row rowScanner) (Integration, error) {
+	var integration Integration
+	var provider string
+	var settings []byte
+	var runID, status, deliveryErr sql.NullString
+	var deliveredAt sql.NullTime
+	if err := row.Scan(
+		&integration.ID,
+		&integration.OrgID,
+		&provider,
+		&integration.Name,
+		&settings,
+		&integration.SecretRef,
+		&integration.Enabled,
+		&integration.CreatedBy,
+		&integration.CreatedAt,
+		&runID,
+		&status,
+		&deliveryErr,
+		&deliveredAt,
+	); err != nil {
+		return Integration{}, err
+	}
+	integration.Provider = Provider(provider)
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &integration.Settings); err != nil {
+			return Integration{}, err
+		}
+	}
+	if deliveredAt.Valid {
+		integration.LastDelivery = &Delivery{
+			ApplyRunID: runID.String,
+			Status:     status.String,
+			Error:      deliveryErr.String,
+			At:         deliveredAt.Time,
+		}
+	}
+	return integration, nil
+}
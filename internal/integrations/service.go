@@ -0,0 +1,282 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/shared/secrets"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/internal/usage"
+)
+
+const (
+	maxNameLength       = 100
+	maxCredentialLength = 4096
+	docxMimeType        = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// MemberChecker reports whether a user belongs to an organization. It returns an
+// error, such as usage.ErrNotOrgMember, when they do not.
+type MemberChecker interface {
+	CheckOrgMember(ctx context.Context, orgID, userID string) error
+}
+
+// AnalysisLookup loads the analysis an apply run was built from.
+type AnalysisLookup interface {
+	GetByID(ctx context.Context, analysisID string) (analyses.Analysis, error)
+}
+
+// Service manages organization integrations and pushes completed apply runs to
+// them. Pushes run in the background; Wait blocks until they finish.
+type Service struct {
+	Repo     Repo
+	Secrets  *secrets.Store
+	Members  MemberChecker
+	Analyses AnalysisLookup
+	Adapters map[Provider]Adapter
+	Now      func() time.Time
+
+	wg sync.WaitGroup
+}
+
+// NewService constructs a Service with the default adapters. A nil secrets store
+// disables integrations.
+func NewService(repo Repo, secretStore *secrets.Store, members MemberChecker, analysisLookup AnalysisLookup) *Service {
+	return &Service{
+		Repo:     repo,
+		Secrets:  secretStore,
+		Members:  members,
+		Analyses: analysisLookup,
+		Adapters: DefaultAdapters(nil),
+	}
+}
+
+var _ usage.ApplyNotifier = (*Service)(nil)
+
+// CreateInput configures a new integration. Credential is the provider API key,
+// or the signing secret for a webhook.
+type CreateInput struct {
+	Provider   Provider
+	Name       string
+	Settings   Settings
+	Credential string
+}
+
+// Create validates and stores an integration, keeping its credential in the
+// secrets store.
+func (s *Service) Create(ctx context.Context, orgID, userID string, in CreateInput) (Integration, error) {
+	if s.Secrets == nil {
+		return Integration{}, ErrUnavailable
+	}
+	if err := s.Members.CheckOrgMember(ctx, orgID, userID); err != nil {
+		return Integration{}, err
+	}
+	adapter, ok := s.Adapters[in.Provider]
+	if !ok {
+		return Integration{}, fmt.Errorf("%w: unsupported provider %q", ErrInvalidInput, in.Provider)
+	}
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" {
+		in.Name = string(in.Provider)
+	}
+	if len(in.Name) > maxNameLength {
+		return Integration{}, fmt.Errorf("%w: name must be at most %d characters", ErrInvalidInput, maxNameLength)
+	}
+	in.Settings.URL = strings.TrimSpace(in.Settings.URL)
+	in.Settings.JobID = strings.TrimSpace(in.Settings.JobID)
+	in.Settings.OnBehalfOf = strings.TrimSpace(in.Settings.OnBehalfOf)
+	if err := adapter.Validate(in.Settings); err != nil {
+		return Integration{}, err
+	}
+	if in.Credential == "" || len(in.Credential) > maxCredentialLength {
+		return Integration{}, fmt.Errorf("%w: credential is required", ErrInvalidInput)
+	}
+
+	ref, err := s.Secrets.Put(ctx, in.Credential)
+	if err != nil {
+		return Integration{}, err
+	}
+	integration := Integration{
+		ID:        uuid.NewString(),
+		OrgID:     orgID,
+		Provider:  in.Provider,
+		Name:      in.Name,
+		Settings:  in.Settings,
+		SecretRef: ref,
+		Enabled:   true,
+		CreatedBy: userID,
+		CreatedAt: s.now(),
+	}
+	if err := s.Repo.Create(ctx, integration); err != nil {
+		_ = s.Secrets.Delete(ctx, ref)
+		return Integration{}, err
+	}
+	return integration, nil
+}
+
+// List returns the organization's integrations. Credentials are never returned.
+func (s *Service) List(ctx context.Context, orgID, userID string) ([]Integration, error) {
+	if err := s.Members.CheckOrgMember(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.Repo.List(ctx, orgID)
+}
+
+// Delete removes an integration and its credential.
+func (s *Service) Delete(ctx context.Context, orgID, userID, id string) error {
+	if err := s.Members.CheckOrgMember(ctx, orgID, userID); err != nil {
+		return err
+	}
+	integration, err := s.Repo.Get(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.Delete(ctx, orgID, id); err != nil {
+		return err
+	}
+	if s.Secrets != nil {
+		if err := s.Secrets.Delete(ctx, integration.SecretRef); err != nil {
+			telemetry.ErrorContext(ctx, "integrations.secret_delete_failed", map[string]any{
+				"integration_id": id,
+				"error":          err.Error(),
+			})
+		}
+	}
+	return nil
+}
+
+// ApplyRunCompleted pushes the run to every enabled integration of the
+// organization when the user is one of its members. It returns immediately.
+func (s *Service) ApplyRunCompleted(ctx context.Context, completion usage.ApplyCompletion) {
+	if s.Secrets == nil {
+		return
+	}
+	if err := s.Members.CheckOrgMember(ctx, completion.OrgID, completion.UserID); err != nil {
+		return
+	}
+	integrations, err := s.Repo.List(ctx, completion.OrgID)
+	if err != nil {
+		telemetry.ErrorContext(ctx, "integrations.list_failed", map[string]any{
+			"org_id": completion.OrgID,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	payload := s.payload(ctx, completion)
+	// Deliveries outlive the request that triggered them.
+	background := context.WithoutCancel(ctx)
+	for _, integration := range integrations {
+		if !integration.Enabled {
+			continue
+		}
+		s.wg.Add(1)
+		go func(integration Integration) {
+			defer s.wg.Done()
+			pushCtx, cancel := context.WithTimeout(background, pushTimeout)
+			defer cancel()
+			s.deliver(pushCtx, integration, payload)
+		}(integration)
+	}
+}
+
+// Wait blocks until background deliveries have finished.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Service) payload(ctx context.Context, completion usage.ApplyCompletion) Payload {
+	payload := Payload{
+		Event:             EventApplyRunCompleted,
+		OrgID:             completion.OrgID,
+		ApplyRunID:        completion.ApplyRunID,
+		AnalysisID:        completion.AnalysisID,
+		DocumentVersionID: completion.DocumentVersionID,
+		Status:            completion.Status,
+		Candidate: Candidate{
+			Name:     completion.Header.Name,
+			Email:    completion.Header.Email,
+			Phone:    completion.Header.Phone,
+			Location: completion.Header.Location,
+		},
+		Resume: Resume{
+			FileName: completion.FileName,
+			MimeType: docxMimeType,
+			Content:  completion.Docx,
+		},
+		OccurredAt: s.now(),
+	}
+	if s.Analyses == nil || completion.AnalysisID == "" {
+		return payload
+	}
+	analysis, err := s.Analyses.GetByID(ctx, completion.AnalysisID)
+	if err != nil {
+		if !errors.Is(err, analyses.ErrNotFound) {
+			telemetry.ErrorContext(ctx, "integrations.analysis_lookup_failed", map[string]any{
+				"analysis_id": completion.AnalysisID,
+				"error":       err.Error(),
+			})
+		}
+		return payload
+	}
+	payload.Analysis = AnalysisSummary{Mode: string(analysis.Mode), PromptVersion: analysis.PromptVersion}
+	if score, ok := analyses.FinalScore(analysis); ok {
+		payload.Analysis.FinalScore = &score
+	}
+	return payload
+}
+
+func (s *Service) deliver(ctx context.Context, integration Integration, payload Payload) {
+	payload.DeliveryID = uuid.NewString()
+	delivery := Delivery{ApplyRunID: payload.ApplyRunID, Status: DeliveryDelivered}
+
+	err := s.push(ctx, integration, payload)
+	delivery.At = s.now()
+	fields := map[string]any{
+		"integration_id": integration.ID,
+		"org_id":         integration.OrgID,
+		"provider":       string(integration.Provider),
+		"apply_run_id":   payload.ApplyRunID,
+		"delivery_id":    payload.DeliveryID,
+	}
+	if err != nil {
+		delivery.Status = DeliveryFailed
+		delivery.Error = err.Error()
+		fields["error"] = err.Error()
+		telemetry.Error("integrations.push_failed", fields)
+	} else {
+		telemetry.Info("integrations.pushed", fields)
+	}
+	if err := s.Repo.RecordDelivery(ctx, integration.ID, delivery); err != nil {
+		telemetry.Error("integrations.record_delivery_failed", map[string]any{
+			"integration_id": integration.ID,
+			"error":          err.Error(),
+		})
+	}
+}
+
+func (s *Service) push(ctx context.Context, integration Integration, payload Payload) error {
+	adapter, ok := s.Adapters[integration.Provider]
+	if !ok {
+		return fmt.Errorf("unsupported provider %q", integration.Provider)
+	}
+	credential, err := s.Secrets.Get(ctx, integration.SecretRef)
+	if err != nil {
+		return fmt.Errorf("load credential: %w", err)
+	}
+	return adapter.Push(ctx, integration, credential, payload)
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,70 @@
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Headers set on webhook deliveries.
+const (
+	SignatureHeader = "X-Resume-Signature"
+	EventHeader     = "X-Resume-Event"
+	DeliveryHeader  = "X-Resume-Delivery"
+)
+
+// WebhookAdapter posts the payload as JSON to the configured URL, signed with the
+// integration's secret.
+type WebhookAdapter struct {
+	Client *http.Client
+	// AllowInsecure accepts http:// URLs, for local development.
+	AllowInsecure bool
+}
+
+// Validate requires an absolute HTTPS URL.
+func (a *WebhookAdapter) Validate(settings Settings) error {
+	u, err := url.Parse(settings.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute URL", ErrInvalidInput)
+	}
+	if u.Scheme != "https" && !(a.AllowInsecure && u.Scheme == "http") {
+		return fmt.Errorf("%w: url must use https", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Push delivers the payload once; any non-2xx response is an error.
+func (a *WebhookAdapter) Push(ctx context.Context, integration Integration, credential string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := newRequest(ctx, http.MethodPost, integration.Settings.URL, "application/json", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(SignatureHeader, Sign(credential, time.Now(), body))
+	req.Header.Set(EventHeader, payload.Event)
+	req.Header.Set(DeliveryHeader, payload.DeliveryID)
+	_, err = do(a.Client, ProviderWebhook, req)
+	return err
+}
+
+// Sign returns the X-Resume-Signature value for body: "t=<unix seconds>,v1=<hex>",
+// where the hex digest is HMAC-SHA256 over "<unix seconds>.<body>". Receivers
+// recompute it with the shared secret and should reject stale timestamps.
+func Sign(secret string, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,8 @@
+package jobdescriptions
+
+import "errors"
+
+var (
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrInvalidLLMOutput = errors.New("invalid llm output")
+)
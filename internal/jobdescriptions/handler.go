@@ -0,0 +1,76 @@
+package jobdescriptions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Handler exposes job description tooling over HTTP.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches job description routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/job-descriptions/extract-keywords", h.extractKeywords)
+}
+
+type extractKeywordsRequest struct {
+	JobDescription string `json:"jobDescription"`
+}
+
+func (h *Handler) extractKeywords(c *gin.Context) {
+	var req extractKeywordsRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid JSON body", nil)
+		return
+	}
+	if strings.TrimSpace(req.JobDescription) == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "jobDescription is required", []map[string]string{
+			{"field": "jobDescription", "issue": "required"},
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.JobDescription) > MaxJobDescriptionRunes {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "jobDescription too long", []map[string]string{
+			{"field": "jobDescription", "issue": "max_length"},
+		})
+		return
+	}
+
+	out, err := h.Svc.ExtractKeywords(c.Request.Context(), req.JobDescription)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", "invalid input", nil)
+		case errors.Is(err, ErrInvalidLLMOutput):
+			respond.Error(c, http.StatusBadGateway, "invalid_llm_output", "invalid model output", nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to extract keywords", nil)
+		}
+		return
+	}
+
+	telemetry.Info("jd.keywords.extracted", map[string]any{
+		"request_id":     middleware.RequestIDFromContext(c),
+		"must_have":      len(out.MustHave),
+		"nice_to_have":   len(out.NiceToHave),
+		"experience":     len(out.ExperienceRequirements),
+		"certifications": len(out.Certifications),
+	})
+	respond.JSON(c, http.StatusOK, out)
+}
@@ -0,0 +1,117 @@
+package jobdescriptions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+)
+
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (s *scriptedLLM) Complete(ctx context.Context, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	if len(s.responses) == 0 {
+		return "", errors.New("no response scripted")
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+const validExtraction = `{
+  "mustHave": ["Go", "PostgreSQL", "go", "Kubernetes"],
+  "niceToHave": ["Terraform", "Kubernetes"],
+  "experienceRequirements": [{"skill": "backend development", "minYears": 5, "maxYears": null, "required": true}],
+  "certifications": [{"name": "AWS Solutions Architect", "required": false}]
+}`
+
+func setupRouter(llm LLMClient) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Auth("dev"))
+	NewHandler(NewService(llm)).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func postExtract(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/job-descriptions/extract-keywords", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Guest-Id", "test-guest")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestExtractKeywordsReturnsCleanedResult(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{"```json\n" + validExtraction + "\n```"}}
+	resp := postExtract(setupRouter(llm), `{"jobDescription":"Senior Go engineer, 5+ years backend development."}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var out KeywordExtraction
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.Join(out.MustHave, ",") != "Go,PostgreSQL,Kubernetes" {
+		t.Fatalf("unexpected mustHave: %v", out.MustHave)
+	}
+	if strings.Join(out.NiceToHave, ",") != "Terraform" {
+		t.Fatalf("expected niceToHave without mustHave duplicates, got %v", out.NiceToHave)
+	}
+	if len(out.ExperienceRequirements) != 1 || out.ExperienceRequirements[0].MinYears != 5 || out.ExperienceRequirements[0].MaxYears != nil {
+		t.Fatalf("unexpected experience: %+v", out.ExperienceRequirements)
+	}
+	if len(out.Certifications) != 1 || out.Certifications[0].Required {
+		t.Fatalf("unexpected certifications: %+v", out.Certifications)
+	}
+	if !strings.HasSuffix(llm.prompts[0], "5+ years backend development.") {
+		t.Fatalf("expected job description appended to prompt")
+	}
+}
+
+func TestExtractKeywordsRetriesOnSchemaViolation(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{`{"mustHave": ["Go"], "niceToHave": []}`, validExtraction}}
+	out, err := NewService(llm).ExtractKeywords(context.Background(), "Go engineer")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if len(out.MustHave) != 3 {
+		t.Fatalf("expected retry result, got %+v", out)
+	}
+	if len(llm.prompts) != 2 || !strings.Contains(llm.prompts[1], "experienceRequirements is required") {
+		t.Fatalf("expected retry prompt with validation feedback, got %v", llm.prompts)
+	}
+}
+
+func TestExtractKeywordsRejectsInvalidOutput(t *testing.T) {
+	bad := `{"mustHave": [], "niceToHave": [], "experienceRequirements": [{"skill": "go", "minYears": 90, "maxYears": null, "required": true}], "certifications": []}`
+	llm := &scriptedLLM{responses: []string{bad, `{"extra": true}`}}
+	resp := postExtract(setupRouter(llm), `{"jobDescription":"Go engineer"}`)
+	if resp.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestExtractKeywordsValidatesRequest(t *testing.T) {
+	router := setupRouter(&scriptedLLM{})
+	if resp := postExtract(router, `{"jobDescription":"   "}`); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty jd, got %d", resp.Code)
+	}
+	long, _ := json.Marshal(map[string]string{"jobDescription": strings.Repeat("a", MaxJobDescriptionRunes+1)})
+	if resp := postExtract(router, string(long)); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for long jd, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,23 @@
+package jobdescriptions
+
+// KeywordExtraction is the structured requirement set pulled from a job description.
+type KeywordExtraction struct {
+	MustHave               []string                `json:"mustHave"`
+	NiceToHave             []string                `json:"niceToHave"`
+	ExperienceRequirements []ExperienceRequirement `json:"experienceRequirements"`
+	Certifications         []Certification         `json:"certifications"`
+}
+
+// ExperienceRequirement is an explicit years-of-experience requirement.
+type ExperienceRequirement struct {
+	Skill    string `json:"skill"`
+	MinYears int    `json:"minYears"`
+	MaxYears *int   `json:"maxYears"`
+	Required bool   `json:"required"`
+}
+
+// Certification is a named certification or license mentioned by the posting.
+type Certification struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+}
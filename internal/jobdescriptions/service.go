@@ -0,0 +1,192 @@
+package jobdescriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"resume-backend/llm/prompts"
+)
+
+const (
+	// MaxJobDescriptionRunes matches the analysis endpoint limit.
+	MaxJobDescriptionRunes = 50000
+	maxKeywords            = 40
+	maxKeywordRunes        = 60
+	maxExperienceYears     = 50
+)
+
+// LLMClient completes a single prompt.
+type LLMClient interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// Service extracts structured keywords from job descriptions.
+type Service struct {
+	LLM LLMClient
+}
+
+// NewService constructs a Service.
+func NewService(llm LLMClient) *Service {
+	return &Service{LLM: llm}
+}
+
+// rawExtraction uses pointers so missing keys can be told apart from empty arrays.
+type rawExtraction struct {
+	MustHave               *[]string                `json:"mustHave"`
+	NiceToHave             *[]string                `json:"niceToHave"`
+	ExperienceRequirements *[]ExperienceRequirement `json:"experienceRequirements"`
+	Certifications         *[]Certification         `json:"certifications"`
+}
+
+// ExtractKeywords asks the LLM for a keyword breakdown and validates it against the schema.
+// A response that fails validation is retried once with the validation error as feedback.
+func (s *Service) ExtractKeywords(ctx context.Context, jobDescription string) (KeywordExtraction, error) {
+	jobDescription = strings.TrimSpace(jobDescription)
+	if jobDescription == "" || utf8.RuneCountInString(jobDescription) > MaxJobDescriptionRunes {
+		return KeywordExtraction{}, ErrInvalidInput
+	}
+	if s.LLM == nil {
+		return KeywordExtraction{}, errors.New("llm client is not configured")
+	}
+
+	prompt := strings.TrimSpace(prompts.JDKeywords) + "\n" + jobDescription
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		attemptPrompt := prompt
+		if lastErr != nil {
+			attemptPrompt += "\n\nYour previous output was rejected: " + lastErr.Error() + ". Return corrected JSON only."
+		}
+		raw, err := s.LLM.Complete(ctx, attemptPrompt)
+		if err != nil {
+			return KeywordExtraction{}, fmt.Errorf("llm complete: %w", err)
+		}
+		out, err := parseExtraction(raw)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return out, nil
+	}
+	return KeywordExtraction{}, fmt.Errorf("%w: %v", ErrInvalidLLMOutput, lastErr)
+}
+
+func parseExtraction(raw string) (KeywordExtraction, error) {
+	payload, err := extractJSONObject(raw)
+	if err != nil {
+		return KeywordExtraction{}, err
+	}
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.DisallowUnknownFields()
+	var parsed rawExtraction
+	if err := dec.Decode(&parsed); err != nil {
+		return KeywordExtraction{}, fmt.Errorf("schema: %w", err)
+	}
+	switch {
+	case parsed.MustHave == nil:
+		return KeywordExtraction{}, errors.New("schema: mustHave is required")
+	case parsed.NiceToHave == nil:
+		return KeywordExtraction{}, errors.New("schema: niceToHave is required")
+	case parsed.ExperienceRequirements == nil:
+		return KeywordExtraction{}, errors.New("schema: experienceRequirements is required")
+	case parsed.Certifications == nil:
+		return KeywordExtraction{}, errors.New("schema: certifications is required")
+	}
+
+	mustHave, err := cleanKeywords("mustHave", *parsed.MustHave, nil)
+	if err != nil {
+		return KeywordExtraction{}, err
+	}
+	niceToHave, err := cleanKeywords("niceToHave", *parsed.NiceToHave, mustHave)
+	if err != nil {
+		return KeywordExtraction{}, err
+	}
+
+	experience := make([]ExperienceRequirement, 0, len(*parsed.ExperienceRequirements))
+	for i, req := range *parsed.ExperienceRequirements {
+		req.Skill = strings.TrimSpace(req.Skill)
+		if req.Skill == "" {
+			return KeywordExtraction{}, fmt.Errorf("schema: experienceRequirements[%d].skill is required", i)
+		}
+		if req.MinYears < 0 || req.MinYears > maxExperienceYears {
+			return KeywordExtraction{}, fmt.Errorf("schema: experienceRequirements[%d].minYears out of range", i)
+		}
+		if req.MaxYears != nil && (*req.MaxYears < req.MinYears || *req.MaxYears > maxExperienceYears) {
+			return KeywordExtraction{}, fmt.Errorf("schema: experienceRequirements[%d].maxYears out of range", i)
+		}
+		experience = append(experience, req)
+	}
+
+	certs := make([]Certification, 0, len(*parsed.Certifications))
+	seen := map[string]struct{}{}
+	for i, cert := range *parsed.Certifications {
+		cert.Name = strings.TrimSpace(cert.Name)
+		if cert.Name == "" {
+			return KeywordExtraction{}, fmt.Errorf("schema: certifications[%d].name is required", i)
+		}
+		key := strings.ToLower(cert.Name)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		certs = append(certs, cert)
+	}
+
+	return KeywordExtraction{
+		MustHave:               mustHave,
+		NiceToHave:             niceToHave,
+		ExperienceRequirements: experience,
+		Certifications:         certs,
+	}, nil
+}
+
+// cleanKeywords trims and dedupes keywords, dropping any already present in exclude.
+func cleanKeywords(field string, keywords []string, exclude []string) ([]string, error) {
+	if len(keywords) > maxKeywords {
+		return nil, fmt.Errorf("schema: %s has more than %d items", field, maxKeywords)
+	}
+	seen := make(map[string]struct{}, len(keywords)+len(exclude))
+	for _, kw := range exclude {
+		seen[strings.ToLower(kw)] = struct{}{}
+	}
+	out := make([]string, 0, len(keywords))
+	for i, kw := range keywords {
+		kw = strings.Join(strings.Fields(kw), " ")
+		if kw == "" {
+			return nil, fmt.Errorf("schema: %s[%d] is empty", field, i)
+		}
+		if utf8.RuneCountInString(kw) > maxKeywordRunes {
+			return nil, fmt.Errorf("schema: %s[%d] is not a keyword", field, i)
+		}
+		key := strings.ToLower(kw)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, kw)
+	}
+	return out, nil
+}
+
+func extractJSONObject(raw string) (string, error) {
+	payload := strings.TrimSpace(raw)
+	if payload == "" {
+		return "", errors.New("empty llm response")
+	}
+	if json.Valid([]byte(payload)) {
+		return payload, nil
+	}
+	start := strings.Index(payload, "{")
+	end := strings.LastIndex(payload, "}")
+	if start == -1 || end <= start {
+		return "", errors.New("no json object found")
+	}
+	candidate := payload[start : end+1]
+	if !json.Valid([]byte(candidate)) {
+		return "", errors.New("invalid json object")
+	}
+	return candidate, nil
+}
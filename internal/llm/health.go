@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+// HealthProbe is the outcome of a minimal provider call.
+type HealthProbe struct {
+	Model      string
+	Latency    time.Duration
+	StatusCode int
+	// AuthValid is false when the provider rejected the credentials.
+	AuthValid bool
+	RateLimit RateLimit
+}
+
+// RateLimit is the provider's remaining allowance as of a probe. Zero limits
+// were not reported.
+type RateLimit struct {
+	LimitRequests     int
+	RemainingRequests int
+	LimitTokens       int
+	RemainingTokens   int
+}
+
+// Headroom returns the smaller remaining share of the request and token
+// limits, from 0 to 1, or -1 when neither limit was reported.
+func (r RateLimit) Headroom() float64 {
+	headroom := -1.0
+	for _, pair := range [][2]int{{r.RemainingRequests, r.LimitRequests}, {r.RemainingTokens, r.LimitTokens}} {
+		if pair[1] <= 0 {
+			continue
+		}
+		share := float64(pair[0]) / float64(pair[1])
+		if headroom < 0 || share < headroom {
+			headroom = share
+		}
+	}
+	return headroom
+}
+
+// Prober is implemented by clients that can check the provider with a minimal
+// request. An empty model probes the client's own model.
+type Prober interface {
+	Probe(ctx context.Context, model string) (HealthProbe, error)
+}
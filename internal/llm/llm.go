@@ -16,47 +16,31 @@ type AnalyzeInput struct {
 	ResumeText     string
 	JobDescription string
 	PromptVersion  string
-	TargetRole     string
-}
-
-type fixJSONKey struct{}
-type extraSystemKey struct{}
-type promptHashKey struct{}
-
-// WithFixJSON returns a context signaling a fix-JSON retry with the given raw output.
-func WithFixJSON(ctx context.Context, raw string) context.Context {
-	return context.WithValue(ctx, fixJSONKey{}, raw)
-}
-
-// FixJSONFromContext returns the raw JSON to repair, if any.
-func FixJSONFromContext(ctx context.Context) (string, bool) {
-	val := ctx.Value(fixJSONKey{})
-	raw, ok := val.(string)
-	return raw, ok
-}
-
-// WithExtraSystemMessage returns a context that adds an extra system message to the prompt.
-func WithExtraSystemMessage(ctx context.Context, message string) context.Context {
-	return context.WithValue(ctx, extraSystemKey{}, message)
-}
-
-// ExtraSystemMessageFromContext returns the extra system message, if any.
-func ExtraSystemMessageFromContext(ctx context.Context) (string, bool) {
-	val := ctx.Value(extraSystemKey{})
-	msg, ok := val.(string)
-	return msg, ok
-}
-
-// WithPromptHashCapture attaches a sink for the prompt hash computed by the LLM client.
-func WithPromptHashCapture(ctx context.Context, out *string) context.Context {
-	return context.WithValue(ctx, promptHashKey{}, out)
-}
-
-// PromptHashSinkFromContext returns the prompt hash sink, if any.
-func PromptHashSinkFromContext(ctx context.Context) (*string, bool) {
-	val := ctx.Value(promptHashKey{})
-	ptr, ok := val.(*string)
-	return ptr, ok
+	// TargetRole is the role the candidate is aiming for, if they named one.
+	TargetRole string
+	// TargetRoleKeywords are keywords typical for TargetRole, from the role
+	// taxonomy. Analyses without a job description are evaluated against them.
+	TargetRoleKeywords []string
+	// SupportingDocuments carries non-resume material used only as evidence.
+	SupportingDocuments []SupportingDocument
+	// Quantification is the deterministic metric scan of the experience bullets, when available.
+	Quantification *QuantificationSignal
+}
+
+// QuantificationSignal tells the model how many experience bullets already carry a metric.
+type QuantificationSignal struct {
+	Score               int
+	QuantifiedBullets   int
+	TotalBullets        int
+	UnquantifiedBullets []string
+}
+
+// SupportingDocument is extra candidate material (portfolio, cover letter) sent alongside the resume.
+type SupportingDocument struct {
+	DocumentID string
+	Kind       string
+	FileName   string
+	Text       string
 }
 
 // ErrNotImplemented is returned by the placeholder client.
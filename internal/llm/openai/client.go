@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
 )
 
 var apiURL = "https://api.openai.com/v1/chat/completions"
@@ -28,6 +29,8 @@ type Client struct {
 	temperature   float32
 	noTemp0Models map[string]struct{}
 	httpClient    *http.Client
+	// endpoint overrides apiURL, for example with a region-pinned host.
+	endpoint string
 }
 
 // NewClient constructs a new OpenAI client.
@@ -62,6 +65,21 @@ func NewClient(apiKey, model string) (*Client, error) {
 	}, nil
 }
 
+// WithEndpoint returns a copy of c that sends requests to url, such as a
+// region-pinned Chat Completions endpoint.
+func (c *Client) WithEndpoint(url string) *Client {
+	out := *c
+	out.endpoint = strings.TrimSpace(url)
+	return &out
+}
+
+func (c *Client) url() string {
+	if c.endpoint != "" {
+		return c.endpoint
+	}
+	return apiURL
+}
+
 type chatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
@@ -75,7 +93,14 @@ type chatRequest struct {
 }
 
 type responseFormat struct {
-	Type string `json:"type"`
+	Type       string            `json:"type"`
+	JSONSchema *jsonSchemaFormat `json:"json_schema,omitempty"`
+}
+
+type jsonSchemaFormat struct {
+	Name   string          `json:"name"`
+	Strict bool            `json:"strict"`
+	Schema json.RawMessage `json:"schema"`
 }
 
 type chatResponse struct {
@@ -100,20 +125,23 @@ func (c *Client) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (jso
 		return nil, fmt.Errorf("LLM_MODEL is required for OpenAI")
 	}
 
-	rawFix, hasFix := llm.FixJSONFromContext(ctx)
+	rawFix, hasFix := ctxmeta.FixJSON(ctx)
 	if hasFix {
 		return c.analyzeFixJSON(ctx, input, rawFix)
 	}
 
 	messages := BuildPrompt(input.PromptVersion, input.ResumeText, input.JobDescription, c.model)
-	if extra, ok := llm.ExtraSystemMessageFromContext(ctx); ok && strings.TrimSpace(extra) != "" {
+	messages = withSupportingDocuments(messages, input.SupportingDocuments)
+	messages = withQuantification(messages, input.Quantification)
+	messages = withTargetRole(messages, input.TargetRole, input.TargetRoleKeywords)
+	if extra, ok := ctxmeta.ExtraSystemMessage(ctx); ok && strings.TrimSpace(extra) != "" {
 		messages = prependSystemMessage(messages, extra)
 	}
 	raw, usage, err := c.analyzeOnce(ctx, input, messages)
 	if err != nil {
 		return nil, err
 	}
-	logUsage(c.model, input.PromptVersion, usage)
+	logUsage(ctx, c.model, input.PromptVersion, usage)
 
 	if json.Valid(raw) {
 		return raw, nil
@@ -124,7 +152,7 @@ func (c *Client) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (jso
 	if err != nil {
 		return nil, err
 	}
-	logUsage(c.model, input.PromptVersion, usage)
+	logUsage(ctx, c.model, input.PromptVersion, usage)
 	if !json.Valid(raw) {
 		return nil, fmt.Errorf("invalid JSON from OpenAI")
 	}
@@ -137,7 +165,7 @@ func (c *Client) analyzeFixJSON(ctx context.Context, input llm.AnalyzeInput, raw
 	if err != nil {
 		return nil, err
 	}
-	logUsage(c.model, input.PromptVersion, usage)
+	logUsage(ctx, c.model, input.PromptVersion, usage)
 	if !json.Valid(rawResp) {
 		return nil, fmt.Errorf("invalid JSON from OpenAI")
 	}
@@ -145,7 +173,7 @@ func (c *Client) analyzeFixJSON(ctx context.Context, input llm.AnalyzeInput, raw
 }
 
 func (c *Client) analyzeOnce(ctx context.Context, input llm.AnalyzeInput, messages []Message) (json.RawMessage, *chatResponseUsage, error) {
-	if sink, ok := llm.PromptHashSinkFromContext(ctx); ok && sink != nil {
+	if sink, ok := ctxmeta.PromptHashSink(ctx); ok && sink != nil {
 		prompt := promptStringFromMessages(messages)
 		*sink = hashPromptString(prompt)
 	}
@@ -158,11 +186,9 @@ func (c *Client) analyzeOnceWithTemp(ctx context.Context, input llm.AnalyzeInput
 		reqMessages = append(reqMessages, chatMessage{Role: m.Role, Content: m.Content})
 	}
 	reqBody := chatRequest{
-		Model:    c.model,
-		Messages: reqMessages,
-		ResponseFormat: responseFormat{
-			Type: "json_object",
-		},
+		Model:          c.model,
+		Messages:       reqMessages,
+		ResponseFormat: analysisResponseFormat(ctx),
 	}
 	tempSent := false
 	if !omitTemperature && temp != 0 {
@@ -178,7 +204,7 @@ func (c *Client) analyzeOnceWithTemp(ctx context.Context, input llm.AnalyzeInput
 		return nil, nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(), bytes.NewReader(payload))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -248,11 +274,14 @@ func toUsage(raw *struct {
 	}
 }
 
-func logUsage(model, promptVersion string, usage *chatResponseUsage) {
+// logUsage logs a response's token counts and adds them to the context's token
+// counter, which LLM spend budgets are charged from.
+func logUsage(ctx context.Context, model, promptVersion string, usage *chatResponseUsage) {
 	if usage == nil {
 		log.Printf("llm response model=%s prompt_version=%s", model, promptVersion)
 		return
 	}
+	ctxmeta.AddTokenUsage(ctx, usage.PromptTokens, usage.CompletionTokens)
 	log.Printf("llm response model=%s prompt_version=%s prompt_tokens=%d completion_tokens=%d total_tokens=%d",
 		model, promptVersion, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
 }
@@ -1,6 +1,12 @@
 package openai
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"resume-backend/internal/shared/ctxmeta"
+)
 
 func TestIsGPT5(t *testing.T) {
 	tests := []struct {
@@ -24,3 +30,17 @@ func TestIsGPT5(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalysisResponseFormat(t *testing.T) {
+	if got := analysisResponseFormat(context.Background()); got.Type != "json_object" || got.JSONSchema != nil {
+		t.Fatalf("default format = %+v, want json_object", got)
+	}
+	got := analysisResponseFormat(ctxmeta.WithStructuredOutput(context.Background()))
+	if got.Type != "json_schema" || got.JSONSchema == nil {
+		t.Fatalf("structured format = %+v, want json_schema", got)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(got.JSONSchema.Schema, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+}
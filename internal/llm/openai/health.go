@@ -0,0 +1,79 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"resume-backend/internal/llm"
+)
+
+var _ llm.Prober = (*Client)(nil)
+
+type probeRequest struct {
+	Model               string        `json:"model"`
+	Messages            []chatMessage `json:"messages"`
+	MaxCompletionTokens int           `json:"max_completion_tokens"`
+}
+
+// Probe sends a one-token chat request to model, or the client's model when
+// empty, and reports its latency, whether the API key was accepted and the
+// rate-limit headers. Any non-2xx response is returned as an error alongside
+// the probe.
+func (c *Client) Probe(ctx context.Context, model string) (llm.HealthProbe, error) {
+	if strings.TrimSpace(model) == "" {
+		model = c.model
+	}
+	probe := llm.HealthProbe{Model: model}
+	payload, err := json.Marshal(probeRequest{
+		Model:               model,
+		Messages:            []chatMessage{{Role: "user", Content: "ping"}},
+		MaxCompletionTokens: 1,
+	})
+	if err != nil {
+		return probe, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(), bytes.NewReader(payload))
+	if err != nil {
+		return probe, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	probe.Latency = time.Since(start)
+	if err != nil {
+		return probe, fmt.Errorf("openai probe: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	probe.StatusCode = resp.StatusCode
+	probe.AuthValid = resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden
+	probe.RateLimit = llm.RateLimit{
+		LimitRequests:     headerInt(resp.Header, "x-ratelimit-limit-requests"),
+		RemainingRequests: headerInt(resp.Header, "x-ratelimit-remaining-requests"),
+		LimitTokens:       headerInt(resp.Header, "x-ratelimit-limit-tokens"),
+		RemainingTokens:   headerInt(resp.Header, "x-ratelimit-remaining-tokens"),
+	}
+	if resp.StatusCode >= 300 {
+		var parsed chatResponse
+		if json.Unmarshal(body, &parsed) == nil && parsed.Error != nil {
+			return probe, fmt.Errorf("openai http status %d: %s (%s)", resp.StatusCode, parsed.Error.Message, parsed.Error.Type)
+		}
+		return probe, fmt.Errorf("openai http status %d", resp.StatusCode)
+	}
+	return probe, nil
+}
+
+func headerInt(h http.Header, key string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(h.Get(key)))
+	return n
+}
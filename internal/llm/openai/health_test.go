@@ -0,0 +1,67 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbeReportsRateLimitHeadroom(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "450")
+		w.Header().Set("x-ratelimit-limit-tokens", "200000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "50000")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"p"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "gpt-5")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	probe, err := client.WithEndpoint(server.URL).Probe(context.Background(), "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if payload["model"] != "gpt-4o-mini" || payload["max_completion_tokens"] != float64(1) {
+		t.Fatalf("unexpected probe request: %v", payload)
+	}
+	if !probe.AuthValid || probe.StatusCode != http.StatusOK {
+		t.Fatalf("probe = %+v", probe)
+	}
+	if got := probe.RateLimit.Headroom(); got != 0.25 {
+		t.Fatalf("headroom = %v, want 0.25", got)
+	}
+}
+
+func TestProbeFlagsRejectedKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("bad-key", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	probe, err := client.WithEndpoint(server.URL).Probe(context.Background(), "")
+	if err == nil {
+		t.Fatal("expected an error for a rejected key")
+	}
+	if probe.AuthValid || probe.StatusCode != http.StatusUnauthorized || probe.Model != "gpt-4o-mini" {
+		t.Fatalf("probe = %+v", probe)
+	}
+	if probe.RateLimit.Headroom() != -1 {
+		t.Fatalf("expected no rate-limit headroom, got %v", probe.RateLimit.Headroom())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"resume-backend/internal/analyses/scoring"
 	"resume-backend/internal/llm"
 )
 
@@ -18,6 +19,21 @@ const (
 	systemPromptStrict  = "You are a resume analysis engine. Respond with JSON only. Output must match the schema exactly."
 	systemPromptV2      = "You are a resume analysis engine. Respond with JSON only. No markdown. Never omit keys. Output must match the schema exactly."
 	systemPromptFixJSON = "You are a JSON repair tool. Return only valid JSON that matches the schema exactly."
+
+	developerSupportingDocuments = "Supporting documents follow the resume. Use them only as evidence for claims the resume makes: " +
+		"an evidence snippet may be quoted from a supporting document when the resume lacks one, and such claims count as supported. " +
+		"Do not rewrite supporting documents, do not treat them as resume sections, and never invent experience that appears in none of the documents."
+
+	developerQuantification = "A deterministic scan of the experience bullets follows the resume. Ground ats.scoreBreakdown.impact in its quantification score: " +
+		"the fewer bullets carry numbers, percentages or currency, the smaller impact's share should be. " +
+		"Prefer the listed unquantified bullets when choosing bulletRewrites, and use placeholders for any figure the resume does not state."
+
+	developerTargetRole = "The candidate's target role follows the resume. When no job description is provided, evaluate fit against this role: " +
+		"ground the keyword and experience fit scores in what the role usually requires, and fill missingKeywords.industryCommon from the listed role keywords the resume lacks, " +
+		"plus other terms the role commonly expects. When a job description is provided, it takes precedence and the target role is context only."
+
+	// maxQuantificationBullets caps how many unquantified bullets are listed in the prompt.
+	maxQuantificationBullets = 15
 )
 
 // BuildPrompt creates the chat messages for a resume analysis request.
@@ -63,6 +79,7 @@ func resolvePromptTemplate(promptVersion string, jobDescription string, model st
 		"{{PROMPT_VERSION}}", usedVersion,
 		"{{MODEL}}", model,
 		"{{JOB_DESCRIPTION_PROVIDED}}", jobDescriptionProvided,
+		"{{SCORING_COMPONENTS}}", scoring.Current().PromptRules(),
 	)
 	return usedVersion, replacer.Replace(template)
 }
@@ -75,6 +92,80 @@ func buildUserPrompt(resumeText, jobDescription string) string {
 	return fmt.Sprintf("Resume Text:\n%s\n\nJob Description:\n%s", resumeText, jd)
 }
 
+// withSupportingDocuments appends supporting material to the user turn and explains how to use it.
+func withSupportingDocuments(messages []Message, docs []llm.SupportingDocument) []Message {
+	if len(docs) == 0 {
+		return messages
+	}
+	var b strings.Builder
+	b.WriteString("\n\nSupporting Documents:")
+	for _, doc := range docs {
+		fmt.Fprintf(&b, "\n\n[%s id=%s file=%s]\n%s", doc.Kind, doc.DocumentID, doc.FileName, doc.Text)
+	}
+
+	out := make([]Message, 0, len(messages)+1)
+	for _, msg := range messages {
+		if msg.Role == "user" {
+			out = append(out, Message{Role: "developer", Content: developerSupportingDocuments})
+			msg.Content += b.String()
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+// withQuantification appends the metric scan to the user turn and explains how to use it.
+func withQuantification(messages []Message, q *llm.QuantificationSignal) []Message {
+	if q == nil || q.TotalBullets == 0 {
+		return messages
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nQuantification Scan:\nscore=%d quantifiedBullets=%d totalBullets=%d", q.Score, q.QuantifiedBullets, q.TotalBullets)
+	if len(q.UnquantifiedBullets) > 0 {
+		b.WriteString("\nUnquantified bullets:")
+		for i, bullet := range q.UnquantifiedBullets {
+			if i == maxQuantificationBullets {
+				break
+			}
+			fmt.Fprintf(&b, "\n- %s", bullet)
+		}
+	}
+
+	out := make([]Message, 0, len(messages)+1)
+	for _, msg := range messages {
+		if msg.Role == "user" {
+			out = append(out, Message{Role: "developer", Content: developerQuantification})
+			msg.Content += b.String()
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+// withTargetRole appends the target role and its typical keywords to the user
+// turn and explains how to use them.
+func withTargetRole(messages []Message, role string, keywords []string) []Message {
+	role = strings.TrimSpace(role)
+	if role == "" {
+		return messages
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nTarget Role:\n%s", role)
+	if len(keywords) > 0 {
+		fmt.Fprintf(&b, "\nTypical keywords: %s", strings.Join(keywords, ", "))
+	}
+
+	out := make([]Message, 0, len(messages)+1)
+	for _, msg := range messages {
+		if msg.Role == "user" {
+			out = append(out, Message{Role: "developer", Content: developerTargetRole})
+			msg.Content += b.String()
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
 func fixUserPrompt(raw []byte) string {
 	return fmt.Sprintf("Fix this JSON to match the schema exactly. Output JSON only:\n%s", string(raw))
 }
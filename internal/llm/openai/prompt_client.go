@@ -12,6 +12,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"resume-backend/internal/shared/ctxmeta"
 )
 
 // PromptClient implements prompt completion for JSON outputs.
@@ -19,6 +21,8 @@ type PromptClient struct {
 	apiKey     string
 	model      string
 	httpClient *http.Client
+	// endpoint overrides apiURL, for example with a region-pinned host.
+	endpoint string
 }
 
 // NewPromptClient constructs a prompt client for JSON completions.
@@ -44,6 +48,20 @@ func NewPromptClient(apiKey, model string) (*PromptClient, error) {
 	}, nil
 }
 
+// WithEndpoint returns a copy of c that sends requests to url.
+func (c *PromptClient) WithEndpoint(url string) *PromptClient {
+	out := *c
+	out.endpoint = strings.TrimSpace(url)
+	return &out
+}
+
+func (c *PromptClient) url() string {
+	if c.endpoint != "" {
+		return c.endpoint
+	}
+	return apiURL
+}
+
 // Complete returns the raw model response for the prompt.
 func (c *PromptClient) Complete(ctx context.Context, prompt string) (string, error) {
 	if strings.TrimSpace(c.model) == "" {
@@ -69,7 +87,7 @@ func (c *PromptClient) Complete(ctx context.Context, prompt string) (string, err
 		return "", err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(), bytes.NewReader(payload))
 	if err != nil {
 		return "", err
 	}
@@ -106,6 +124,9 @@ func (c *PromptClient) Complete(ctx context.Context, prompt string) (string, err
 	if len(parsed.Choices) == 0 {
 		return "", fmt.Errorf("openai response missing choices")
 	}
+	if parsed.Usage != nil {
+		ctxmeta.AddTokenUsage(ctx, parsed.Usage.PromptTokens, parsed.Usage.CompletionTokens)
+	}
 
 	content := strings.TrimSpace(parsed.Choices[0].Message.Content)
 	if content == "" {
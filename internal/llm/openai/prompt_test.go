@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"strings"
+	"testing"
+
+	"resume-backend/internal/analyses/scoring"
+	"resume-backend/internal/llm"
+)
+
+func TestWithSupportingDocumentsAppendsToUserTurn(t *testing.T) {
+	base := BuildPrompt("v2_3", "resume text", "jd", "gpt-4o-mini")
+	if got := withSupportingDocuments(base, nil); len(got) != len(base) {
+		t.Fatalf("expected prompt unchanged without supporting docs")
+	}
+
+	messages := withSupportingDocuments(base, []llm.SupportingDocument{
+		{DocumentID: "doc-2", Kind: "portfolio", FileName: "portfolio.pdf", Text: "Case study: checkout redesign"},
+	})
+	if len(messages) != len(base)+1 {
+		t.Fatalf("expected one extra developer message, got %d", len(messages))
+	}
+	user := messages[len(messages)-1]
+	if user.Role != "user" || !strings.Contains(user.Content, "[portfolio id=doc-2 file=portfolio.pdf]") || !strings.Contains(user.Content, "checkout redesign") {
+		t.Fatalf("unexpected user message: %q", user.Content)
+	}
+	if !strings.HasPrefix(user.Content, "Resume Text:\nresume text") {
+		t.Fatalf("expected resume text to stay first, got %q", user.Content)
+	}
+	if messages[len(messages)-2].Content != developerSupportingDocuments {
+		t.Fatalf("expected supporting instructions before the user turn")
+	}
+}
+
+func TestWithQuantificationListsUnquantifiedBullets(t *testing.T) {
+	base := BuildPrompt("v2_3", "resume text", "jd", "gpt-4o-mini")
+	if got := withQuantification(base, &llm.QuantificationSignal{}); len(got) != len(base) {
+		t.Fatalf("expected prompt unchanged without bullets")
+	}
+
+	messages := withQuantification(base, &llm.QuantificationSignal{
+		Score:               50,
+		QuantifiedBullets:   1,
+		TotalBullets:        2,
+		UnquantifiedBullets: []string{"Improved onboarding flow"},
+	})
+	if len(messages) != len(base)+1 {
+		t.Fatalf("expected one extra developer message, got %d", len(messages))
+	}
+	user := messages[len(messages)-1]
+	if !strings.Contains(user.Content, "score=50 quantifiedBullets=1 totalBullets=2") || !strings.Contains(user.Content, "- Improved onboarding flow") {
+		t.Fatalf("unexpected user message: %q", user.Content)
+	}
+	if messages[len(messages)-2].Content != developerQuantification {
+		t.Fatalf("expected quantification instructions before the user turn")
+	}
+}
+
+func TestWithTargetRoleListsRoleKeywords(t *testing.T) {
+	base := BuildPrompt("v2_3", "resume text", "", "gpt-4o-mini")
+	if got := withTargetRole(base, "  ", []string{"Swift"}); len(got) != len(base) {
+		t.Fatalf("expected prompt unchanged without a target role")
+	}
+
+	messages := withTargetRole(base, "iOS Developer", []string{"Swift", "Kotlin"})
+	if len(messages) != len(base)+1 {
+		t.Fatalf("expected one extra developer message, got %d", len(messages))
+	}
+	user := messages[len(messages)-1]
+	if !strings.Contains(user.Content, "Target Role:\niOS Developer\nTypical keywords: Swift, Kotlin") {
+		t.Fatalf("unexpected user message: %q", user.Content)
+	}
+	if messages[len(messages)-2].Content != developerTargetRole {
+		t.Fatalf("expected target role instructions before the user turn")
+	}
+}
+
+func TestBuildPromptInjectsScoringConfig(t *testing.T) {
+	messages := BuildPrompt("v2_3", "resume text", "job description", "gpt-4o-mini")
+	developer := messages[1].Content
+	if strings.Contains(developer, "{{SCORING_COMPONENTS}}") {
+		t.Fatal("scoring placeholder left in the prompt")
+	}
+	if !strings.Contains(developer, scoring.Current().PromptRules()) {
+		t.Fatalf("expected the scoring rules in the prompt:\n%s", developer)
+	}
+}
@@ -0,0 +1,44 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+// analysisSchema is the part of the analysis output shared by every prompt
+// version. It is not strict: version-specific fields are still checked by each
+// pipeline's validation, so a schema mismatch only surfaces there.
+var analysisSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["summary", "ats", "issues", "bulletRewrites", "missingInformation", "actionPlan"],
+  "properties": {
+    "meta": {"type": "object"},
+    "summary": {"type": "object"},
+    "ats": {
+      "type": "object",
+      "required": ["score"],
+      "properties": {"score": {"type": "number", "minimum": 0, "maximum": 100}}
+    },
+    "issues": {"type": "array", "items": {"type": "object"}},
+    "bulletRewrites": {"type": "array", "items": {"type": "object"}},
+    "missingInformation": {"type": "array"},
+    "actionPlan": {"type": "object"}
+  }
+}`)
+
+// analysisResponseFormat is JSON mode, or the analysis JSON schema when the
+// caller asked for structured output.
+func analysisResponseFormat(ctx context.Context) responseFormat {
+	if !ctxmeta.StructuredOutput(ctx) {
+		return responseFormat{Type: "json_object"}
+	}
+	return responseFormat{
+		Type: "json_schema",
+		JSONSchema: &jsonSchemaFormat{
+			Name:   "resume_analysis",
+			Schema: analysisSchema,
+		},
+	}
+}
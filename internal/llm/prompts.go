@@ -13,6 +13,8 @@ var (
 	promptV2_2 string
 	//go:embed prompts/v2_3.txt
 	promptV2_3 string
+	//go:embed prompts/delta_v1.txt
+	promptDeltaV1 string
 )
 
 // PromptTemplate returns the prompt template text and whether the version was recognized.
@@ -20,6 +22,8 @@ func PromptTemplate(version string) (string, bool) {
 	switch version {
 	case "v2_3":
 		return promptV2_3, true
+	case "delta_v1":
+		return promptDeltaV1, true
 	case "v2_2":
 		return promptV2_2, true
 	case "v2_1":
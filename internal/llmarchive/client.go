@@ -0,0 +1,86 @@
+package llmarchive
+
+import (
+	"context"
+	"encoding/json"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+// Client archives the AnalyzeResume calls made through Base, whichever
+// provider serves them.
+type Client struct {
+	Base    llm.Client
+	Archive *Service
+}
+
+// Wrap returns base unchanged when archive is nil.
+func Wrap(base llm.Client, archive *Service) llm.Client {
+	if base == nil || !archive.Enabled() {
+		return base
+	}
+	return &Client{Base: base, Archive: archive}
+}
+
+// AnalyzeResume delegates to Base, then archives the input and the response.
+func (c *Client) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	out, err := c.Base.AnalyzeResume(ctx, input)
+	c.Archive.record(ctx, Call{
+		Interaction:   InteractionAnalysis,
+		PromptVersion: input.PromptVersion,
+		Prompt:        analysisPrompt(ctx, input),
+		Response:      string(out),
+		Err:           err,
+	})
+	return out, err
+}
+
+// analysisPrompt serializes what the provider builds the analysis prompt
+// from: the input and any extra system message, such as a fix-JSON retry's.
+func analysisPrompt(ctx context.Context, input llm.AnalyzeInput) string {
+	payload := struct {
+		Input              llm.AnalyzeInput `json:"input"`
+		ExtraSystemMessage string           `json:"extraSystemMessage,omitempty"`
+		FixJSON            string           `json:"fixJson,omitempty"`
+	}{Input: input}
+	payload.ExtraSystemMessage, _ = ctxmeta.ExtraSystemMessage(ctx)
+	payload.FixJSON, _ = ctxmeta.FixJSON(ctx)
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+// PromptClient completes JSON prompts, such as apply runs and job
+// description extraction.
+type PromptClient interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// Prompts archives the Complete calls made through Base.
+type Prompts struct {
+	Base    PromptClient
+	Archive *Service
+}
+
+// WrapPrompts returns base unchanged when archive is nil.
+func WrapPrompts(base PromptClient, archive *Service) PromptClient {
+	if base == nil || !archive.Enabled() {
+		return base
+	}
+	return &Prompts{Base: base, Archive: archive}
+}
+
+// Complete delegates to Base, then archives the exact prompt and response.
+func (p *Prompts) Complete(ctx context.Context, prompt string) (string, error) {
+	out, err := p.Base.Complete(ctx, prompt)
+	p.Archive.record(ctx, Call{
+		Interaction: InteractionPrompt,
+		Prompt:      prompt,
+		Response:    out,
+		Err:         err,
+	})
+	return out, err
+}
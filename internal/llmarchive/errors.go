@@ -0,0 +1,15 @@
+package llmarchive
+
+import "errors"
+
+var (
+	// ErrNotFound indicates no archive policy is set.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidPolicy indicates an unknown mode or a negative retention.
+	ErrInvalidPolicy = errors.New("invalid llm archive policy")
+
+	// ErrKeysRequired indicates full archiving was requested without
+	// LLM_ARCHIVE_KEYS, so prompts and responses could not be sealed.
+	ErrKeysRequired = errors.New("llm archive keys not configured")
+)
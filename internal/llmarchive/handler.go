@@ -0,0 +1,135 @@
+package llmarchive
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the LLM archive admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches archive routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/llm-archive/records", h.listRecords)
+	rg.GET("/llm-archive/users/:id", h.getUser)
+	rg.PUT("/llm-archive/users/:id", h.setUser)
+	rg.GET("/llm-archive/orgs/:orgId", h.getOrg)
+	rg.PUT("/llm-archive/orgs/:orgId", h.setOrg)
+}
+
+type setRequest struct {
+	Mode          string `json:"mode"`
+	RetentionDays int    `json:"retentionDays"`
+}
+
+// listRecords returns archived calls filtered by ?ownerId= and ?analysisId=.
+// One of them is required so reads stay scoped to a subject.
+func (h *Handler) listRecords(c *gin.Context) {
+	filter := Filter{
+		OwnerID:    strings.TrimSpace(c.Query("ownerId")),
+		AnalysisID: strings.TrimSpace(c.Query("analysisId")),
+	}
+	if filter.OwnerID == "" && filter.AnalysisID == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "ownerId or analysisId is required", nil)
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "limit must be a positive integer", nil)
+			return
+		}
+		filter.Limit = limit
+	}
+	records, err := h.Svc.List(c.Request.Context(), middleware.UserIDFromContext(c), filter)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load llm archive", nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"records": records})
+}
+
+// getUser returns the mode and retention a user's calls are archived with and
+// the user's own policy, if any.
+func (h *Handler) getUser(c *gin.Context) {
+	userID := c.Param("id")
+	mode, retention, err := h.Svc.PolicyFor(c.Request.Context(), userID)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to resolve llm archive policy", nil)
+		return
+	}
+	body := gin.H{"userId": userID, "mode": mode, "retentionDays": int(retention.Hours() / 24)}
+	policy, err := h.Svc.GetPolicy(c.Request.Context(), KindUser, userID)
+	switch {
+	case err == nil:
+		body["policy"] = policy
+	case !errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load llm archive policy", nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, body)
+}
+
+func (h *Handler) getOrg(c *gin.Context) {
+	policy, err := h.Svc.GetPolicy(c.Request.Context(), KindOrg, c.Param("orgId"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, policy)
+}
+
+func (h *Handler) setUser(c *gin.Context) {
+	var req setRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid JSON body", nil)
+		return
+	}
+	policy, err := h.Svc.SetUser(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"), req.Mode, req.RetentionDays)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, policy)
+}
+
+func (h *Handler) setOrg(c *gin.Context) {
+	var req setRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid JSON body", nil)
+		return
+	}
+	policy, err := h.Svc.SetOrg(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("orgId"), req.Mode, req.RetentionDays)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, policy)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidPolicy):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrKeysRequired):
+		respond.Error(c, http.StatusConflict, "archive_keys_required", "full archiving requires LLM_ARCHIVE_KEYS", nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "no llm archive policy set", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to update llm archive policy", nil)
+	}
+}
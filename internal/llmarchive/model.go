@@ -0,0 +1,86 @@
+package llmarchive
+
+import (
+	"strings"
+	"time"
+)
+
+// Mode is how much of an LLM interaction is archived.
+type Mode string
+
+const (
+	// ModeOff archives nothing.
+	ModeOff Mode = "off"
+	// ModeHashes archives SHA-256 digests of the prompt and response, enough to
+	// prove what was sent without retaining it.
+	ModeHashes Mode = "hashes"
+	// ModeFull also archives the prompt and response, sealed under the archive
+	// keys.
+	ModeFull Mode = "full"
+)
+
+// ParseMode normalizes a mode name such as "FULL" or "hashes". Empty is off.
+func ParseMode(raw string) (Mode, error) {
+	mode := Mode(strings.ToLower(strings.TrimSpace(raw)))
+	switch mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeHashes, ModeFull:
+		return mode, nil
+	}
+	return "", ErrInvalidPolicy
+}
+
+// Subject kinds a policy can be set for.
+const (
+	KindUser = "user"
+	KindOrg  = "org"
+)
+
+// Policy overrides the deployment's archive mode and retention for a user or
+// an organization. An organization's policy applies to all of its members.
+type Policy struct {
+	Kind      string `json:"kind"`
+	SubjectID string `json:"subjectId"`
+	Mode      Mode   `json:"mode"`
+	// RetentionDays is how long records are kept; zero uses the deployment's
+	// retention.
+	RetentionDays int       `json:"retentionDays"`
+	UpdatedBy     string    `json:"updatedBy"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Interactions a record can hold.
+const (
+	// InteractionAnalysis is an AnalyzeResume call. Its prompt is the
+	// structured input the provider renders with the named prompt version.
+	InteractionAnalysis = "analysis"
+	// InteractionPrompt is a Complete call; its prompt is the exact text sent.
+	InteractionPrompt = "prompt"
+)
+
+// Record is one archived LLM call. Prompt and Response are set only in full
+// mode and are sealed at rest.
+type Record struct {
+	ID             string    `json:"id"`
+	Interaction    string    `json:"interaction"`
+	Mode           Mode      `json:"mode"`
+	OwnerID        string    `json:"ownerId,omitempty"`
+	AnalysisID     string    `json:"analysisId,omitempty"`
+	RequestID      string    `json:"requestId,omitempty"`
+	PromptVersion  string    `json:"promptVersion,omitempty"`
+	PromptSHA256   string    `json:"promptSha256"`
+	ResponseSHA256 string    `json:"responseSha256,omitempty"`
+	Prompt         string    `json:"prompt,omitempty"`
+	Response       string    `json:"response,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+// Filter narrows List results. Empty fields match everything.
+type Filter struct {
+	OwnerID    string
+	AnalysisID string
+	Limit      int
+}
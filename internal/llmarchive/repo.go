@@ -0,0 +1,20 @@
+package llmarchive
+
+import (
+	"context"
+	"time"
+)
+
+// Repo persists archive policies and records. Records arrive already sealed.
+type Repo interface {
+	GetPolicy(ctx context.Context, kind, subjectID string) (Policy, error)
+	UpsertPolicy(ctx context.Context, policy Policy) error
+	ListPolicies(ctx context.Context, kind string) ([]Policy, error)
+
+	Insert(ctx context.Context, record Record) error
+	// List returns matching records, newest first.
+	List(ctx context.Context, filter Filter) ([]Record, error)
+	// DeleteExpired deletes up to limit records that expired at or before now
+	// and returns how many it deleted.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error)
+}
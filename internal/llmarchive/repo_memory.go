@@ -0,0 +1,124 @@
+package llmarchive
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepo stores policies and records in memory and is safe for concurrent
+// use.
+type MemoryRepo struct {
+	mu       sync.RWMutex
+	policies map[string]Policy
+	records  []Record
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{policies: make(map[string]Policy)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+func memoryKey(kind, subjectID string) string {
+	return kind + "\x00" + subjectID
+}
+
+// GetPolicy returns the policy of a user or organization.
+func (r *MemoryRepo) GetPolicy(ctx context.Context, kind, subjectID string) (Policy, error) {
+	if err := ctx.Err(); err != nil {
+		return Policy{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	policy, ok := r.policies[memoryKey(kind, subjectID)]
+	if !ok {
+		return Policy{}, ErrNotFound
+	}
+	return policy, nil
+}
+
+// UpsertPolicy creates or replaces a policy.
+func (r *MemoryRepo) UpsertPolicy(ctx context.Context, policy Policy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policies[memoryKey(policy.Kind, policy.SubjectID)] = policy
+	return nil
+}
+
+// ListPolicies returns all user or all organization policies.
+func (r *MemoryRepo) ListPolicies(ctx context.Context, kind string) ([]Policy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Policy
+	for _, policy := range r.policies {
+		if policy.Kind == kind {
+			out = append(out, policy)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SubjectID < out[j].SubjectID })
+	return out, nil
+}
+
+// Insert stores a record.
+func (r *MemoryRepo) Insert(ctx context.Context, record Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	return nil
+}
+
+// List returns matching records, newest first.
+func (r *MemoryRepo) List(ctx context.Context, filter Filter) ([]Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Record
+	for i := len(r.records) - 1; i >= 0; i-- {
+		record := r.records[i]
+		if filter.OwnerID != "" && record.OwnerID != filter.OwnerID {
+			continue
+		}
+		if filter.AnalysisID != "" && record.AnalysisID != filter.AnalysisID {
+			continue
+		}
+		out = append(out, record)
+		if filter.Limit > 0 && len(out) == filter.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// DeleteExpired deletes up to limit records that expired at or before now.
+func (r *MemoryRepo) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.records[:0]
+	deleted := 0
+	for _, record := range r.records {
+		if !record.ExpiresAt.After(now) && (limit <= 0 || deleted < limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, record)
+	}
+	r.records = kept
+	return deleted, nil
+}
@@ -0,0 +1,212 @@
+package llmarchive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// GetPolicy returns the policy of a user or organization.
+func (r *PGRepo) GetPolicy(ctx context.Context, kind, subjectID string) (Policy, error) {
+	const query = `
+SELECT kind, subject_id, mode, retention_days, updated_by, updated_at
+FROM llm_archive_policies
+WHERE kind = $1 AND subject_id = $2`
+	var policy Policy
+	err := r.DB.QueryRowContext(ctx, query, kind, subjectID).Scan(
+		&policy.Kind,
+		&policy.SubjectID,
+		&policy.Mode,
+		&policy.RetentionDays,
+		&policy.UpdatedBy,
+		&policy.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Policy{}, ErrNotFound
+	}
+	if err != nil {
+		return Policy{}, err
+	}
+	return policy, nil
+}
+
+// UpsertPolicy creates or replaces a policy.
+func (r *PGRepo) UpsertPolicy(ctx context.Context, policy Policy) error {
+	const query = `
+INSERT INTO llm_archive_policies (
+    kind,
+    subject_id,
+    mode,
+    retention_days,
+    updated_by,
+    updated_at
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (kind, subject_id) DO UPDATE SET
+    mode = EXCLUDED.mode,
+    retention_days = EXCLUDED.retention_days,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = EXCLUDED.updated_at`
+	_, err := r.DB.ExecContext(ctx, query,
+		policy.Kind,
+		policy.SubjectID,
+		string(policy.Mode),
+		policy.RetentionDays,
+		policy.UpdatedBy,
+		policy.UpdatedAt,
+	)
+	return err
+}
+
+// ListPolicies returns all user or all organization policies.
+func (r *PGRepo) ListPolicies(ctx context.Context, kind string) ([]Policy, error) {
+	const query = `
+SELECT kind, subject_id, mode, retention_days, updated_by, updated_at
+FROM llm_archive_policies
+WHERE kind = $1
+ORDER BY subject_id`
+	rows, err := r.DB.QueryContext(ctx, query, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Policy
+	for rows.Next() {
+		var policy Policy
+		if err := rows.Scan(
+			&policy.Kind,
+			&policy.SubjectID,
+			&policy.Mode,
+			&policy.RetentionDays,
+			&policy.UpdatedBy,
+			&policy.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, policy)
+	}
+	return out, rows.Err()
+}
+
+// Insert stores a record.
+func (r *PGRepo) Insert(ctx context.Context, record Record) error {
+	const query = `
+INSERT INTO llm_archive_records (
+    id,
+    interaction,
+    mode,
+    owner_id,
+    analysis_id,
+    request_id,
+    prompt_version,
+    prompt_sha256,
+    response_sha256,
+    prompt,
+    response,
+    error,
+    created_at,
+    expires_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	_, err := r.DB.ExecContext(ctx, query,
+		record.ID,
+		record.Interaction,
+		string(record.Mode),
+		record.OwnerID,
+		record.AnalysisID,
+		record.RequestID,
+		record.PromptVersion,
+		record.PromptSHA256,
+		record.ResponseSHA256,
+		record.Prompt,
+		record.Response,
+		record.Error,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+	return err
+}
+
+// List returns matching records, newest first.
+func (r *PGRepo) List(ctx context.Context, filter Filter) ([]Record, error) {
+	var where []string
+	var args []any
+	add := func(column, value string) {
+		if value == "" {
+			return
+		}
+		args = append(args, value)
+		where = append(where, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	add("owner_id", filter.OwnerID)
+	add("analysis_id", filter.AnalysisID)
+
+	query := `
+SELECT id, interaction, mode, owner_id, analysis_id, request_id, prompt_version,
+       prompt_sha256, response_sha256, prompt, response, error, created_at, expires_at
+FROM llm_archive_records`
+	if len(where) > 0 {
+		query += "\nWHERE " + strings.Join(where, " AND ")
+	}
+	query += "\nORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf("\nLIMIT $%d", len(args))
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Record
+	for rows.Next() {
+		var record Record
+		if err := rows.Scan(
+			&record.ID,
+			&record.Interaction,
+			&record.Mode,
+			&record.OwnerID,
+			&record.AnalysisID,
+			&record.RequestID,
+			&record.PromptVersion,
+			&record.PromptSHA256,
+			&record.ResponseSHA256,
+			&record.Prompt,
+			&record.Response,
+			&record.Error,
+			&record.CreatedAt,
+			&record.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, record)
+	}
+	return out, rows.Err()
+}
+
+// DeleteExpired deletes up to limit records that expired at or before now.
+func (r *PGRepo) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	const query = `
+DELETE FROM llm_archive_records
+WHERE id IN (
+    SELECT id FROM llm_archive_records
+    WHERE expires_at <= $1
+    ORDER BY expires_at
+    LIMIT $2
+)`
+	res, err := r.DB.ExecContext(ctx, query, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
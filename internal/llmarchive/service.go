@@ -0,0 +1,339 @@
+// Package llmarchive keeps a compliance archive of LLM calls. Each call is
+// archived according to the policy of its data owner: nothing, SHA-256 digests
+// of the prompt and response, or the prompt and response themselves sealed
+// under dedicated archive keys. Records expire after the policy's retention.
+package llmarchive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/fieldcrypt"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/internal/usage"
+)
+
+// DefaultBatchSize bounds how many records a single purge pass deletes.
+const DefaultBatchSize = 500
+
+// Column names the archive keys bind sealed values to.
+const (
+	promptColumn   = "llm_archive_records.prompt"
+	responseColumn = "llm_archive_records.response"
+)
+
+// MemberChecker confirms organization membership. usage.Service implements it.
+type MemberChecker interface {
+	CheckOrgMember(ctx context.Context, orgID, userID string) error
+}
+
+// Service resolves archive policies, writes records and purges expired ones.
+// A nil Service archives nothing.
+type Service struct {
+	Repo Repo
+	// Codec seals full-mode prompts and responses. Without it full mode is
+	// refused.
+	Codec   *fieldcrypt.Codec
+	Members MemberChecker
+	// Audit records policy changes and archive reads; nil skips auditing.
+	Audit *audit.Service
+	// Mode and Retention apply to owners without a policy, and to guests.
+	Mode      Mode
+	Retention time.Duration
+	Now       func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, codec *fieldcrypt.Codec, members MemberChecker, mode Mode, retention time.Duration) *Service {
+	return &Service{Repo: repo, Codec: codec, Members: members, Mode: mode, Retention: retention}
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// Enabled reports whether the archive has a store. Calls are still skipped
+// for owners whose policy is off.
+func (s *Service) Enabled() bool {
+	return s != nil && s.Repo != nil
+}
+
+// PolicyFor returns the mode and retention that apply to ownerID. An
+// organization's policy wins over the member's own; owners with neither, and
+// guests, get the deployment's.
+func (s *Service) PolicyFor(ctx context.Context, ownerID string) (Mode, time.Duration, error) {
+	if ownerID == "" || strings.HasPrefix(ownerID, "guest:") {
+		return s.Mode, s.Retention, nil
+	}
+	orgs, err := s.Repo.ListPolicies(ctx, KindOrg)
+	if err != nil {
+		return "", 0, err
+	}
+	for _, org := range orgs {
+		member, err := s.isMember(ctx, org.SubjectID, ownerID)
+		if err != nil {
+			return "", 0, err
+		}
+		if member {
+			return org.Mode, s.retention(org), nil
+		}
+	}
+	policy, err := s.Repo.GetPolicy(ctx, KindUser, ownerID)
+	if errors.Is(err, ErrNotFound) {
+		return s.Mode, s.Retention, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return policy.Mode, s.retention(policy), nil
+}
+
+func (s *Service) retention(policy Policy) time.Duration {
+	if policy.RetentionDays > 0 {
+		return time.Duration(policy.RetentionDays) * 24 * time.Hour
+	}
+	return s.Retention
+}
+
+// Call is an LLM call to archive.
+type Call struct {
+	Interaction   string
+	PromptVersion string
+	Prompt        string
+	Response      string
+	Err           error
+}
+
+// Record archives call under the policy of the data owner on ctx. It is a
+// no-op when the policy is off.
+func (s *Service) Record(ctx context.Context, call Call) error {
+	if !s.Enabled() {
+		return nil
+	}
+	owner := ctxmeta.DataOwner(ctx)
+	mode, retention, err := s.PolicyFor(ctx, owner)
+	if err != nil {
+		return fmt.Errorf("resolve llm archive policy: %w", err)
+	}
+	if mode == ModeOff || mode == "" {
+		return nil
+	}
+	now := s.now()
+	record := Record{
+		ID:            uuid.NewString(),
+		Interaction:   call.Interaction,
+		Mode:          mode,
+		OwnerID:       owner,
+		AnalysisID:    ctxmeta.AnalysisID(ctx),
+		RequestID:     ctxmeta.RequestID(ctx),
+		PromptVersion: call.PromptVersion,
+		PromptSHA256:  digest(call.Prompt),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(retention),
+	}
+	if call.Err != nil {
+		record.Error = call.Err.Error()
+	} else {
+		record.ResponseSHA256 = digest(call.Response)
+	}
+	if mode == ModeFull {
+		if s.Codec == nil {
+			return ErrKeysRequired
+		}
+		if record.Prompt, err = s.Codec.Encrypt(promptColumn, call.Prompt); err != nil {
+			return fmt.Errorf("seal llm archive prompt: %w", err)
+		}
+		if record.Response, err = s.Codec.Encrypt(responseColumn, call.Response); err != nil {
+			return fmt.Errorf("seal llm archive response: %w", err)
+		}
+	}
+	return s.Repo.Insert(ctx, record)
+}
+
+func digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// record archives call in the background of an LLM call: failures are logged
+// rather than failing the call, and the write outlives ctx's cancellation.
+func (s *Service) record(ctx context.Context, call Call) {
+	if !s.Enabled() {
+		return
+	}
+	writeCtx, cancel := context.WithTimeout(ctxmeta.Detach(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.Record(writeCtx, call); err != nil {
+		telemetry.ErrorContext(ctx, "llm_archive.write_failed", map[string]any{
+			"interaction": call.Interaction,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// List returns matching records with full-mode prompts and responses opened.
+// Reads are audited against actorID.
+func (s *Service) List(ctx context.Context, actorID string, filter Filter) ([]Record, error) {
+	if !s.Enabled() {
+		return []Record{}, nil
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	records, err := s.Repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Record, 0, len(records))
+	for _, record := range records {
+		if record.Prompt, err = s.Codec.Decrypt(promptColumn, record.Prompt); err != nil {
+			return nil, err
+		}
+		if record.Response, err = s.Codec.Decrypt(responseColumn, record.Response); err != nil {
+			return nil, err
+		}
+		out = append(out, record)
+	}
+	if s.Audit != nil {
+		_ = s.Audit.Record(ctx, audit.Entry{
+			Action:        "llm_archive.read",
+			ActorUserID:   actorID,
+			SubjectUserID: filter.OwnerID,
+			Details: map[string]any{
+				"analysisId": filter.AnalysisID,
+				"records":    len(out),
+			},
+		})
+	}
+	return out, nil
+}
+
+// GetPolicy returns the policy of a user or organization.
+func (s *Service) GetPolicy(ctx context.Context, kind, subjectID string) (Policy, error) {
+	return s.Repo.GetPolicy(ctx, kind, subjectID)
+}
+
+// SetUser sets a user's archive policy.
+func (s *Service) SetUser(ctx context.Context, actorID, userID, mode string, retentionDays int) (Policy, error) {
+	if strings.TrimSpace(userID) == "" || strings.HasPrefix(userID, "guest:") {
+		return Policy{}, fmt.Errorf("%w: guests use the default policy", ErrInvalidPolicy)
+	}
+	return s.set(ctx, actorID, KindUser, userID, mode, retentionDays)
+}
+
+// SetOrg sets an organization's archive policy, which applies to all of its
+// members.
+func (s *Service) SetOrg(ctx context.Context, actorID, orgID, mode string, retentionDays int) (Policy, error) {
+	if strings.TrimSpace(orgID) == "" {
+		return Policy{}, fmt.Errorf("%w: organization is required", ErrInvalidPolicy)
+	}
+	return s.set(ctx, actorID, KindOrg, orgID, mode, retentionDays)
+}
+
+func (s *Service) set(ctx context.Context, actorID, kind, subjectID, rawMode string, retentionDays int) (Policy, error) {
+	mode, err := ParseMode(rawMode)
+	if err != nil {
+		return Policy{}, fmt.Errorf("%w: mode must be off, hashes or full", ErrInvalidPolicy)
+	}
+	if retentionDays < 0 {
+		return Policy{}, fmt.Errorf("%w: retentionDays must not be negative", ErrInvalidPolicy)
+	}
+	if mode == ModeFull && s.Codec == nil {
+		return Policy{}, ErrKeysRequired
+	}
+	policy := Policy{
+		Kind:          kind,
+		SubjectID:     subjectID,
+		Mode:          mode,
+		RetentionDays: retentionDays,
+		UpdatedBy:     actorID,
+		UpdatedAt:     s.now(),
+	}
+	if err := s.Repo.UpsertPolicy(ctx, policy); err != nil {
+		return Policy{}, err
+	}
+	if s.Audit != nil {
+		entry := audit.Entry{
+			Action:      "llm_archive.policy_set",
+			ActorUserID: actorID,
+			Details: map[string]any{
+				"kind":          kind,
+				"subjectId":     subjectID,
+				"mode":          string(mode),
+				"retentionDays": retentionDays,
+			},
+		}
+		if kind == KindUser {
+			entry.SubjectUserID = subjectID
+		}
+		_ = s.Audit.Record(ctx, entry)
+	}
+	return policy, nil
+}
+
+// PurgeExpired deletes records past their retention, up to limit per call.
+func (s *Service) PurgeExpired(ctx context.Context, limit int) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+	if limit <= 0 {
+		limit = DefaultBatchSize
+	}
+	return s.Repo.DeleteExpired(ctx, s.now(), limit)
+}
+
+// Run purges expired records every interval until ctx is done. Each pass
+// deletes batches until none are left.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	if !s.Enabled() || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := s.PurgeExpired(ctx, DefaultBatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					telemetry.Error("llm_archive.purge_failed", map[string]any{"error": err.Error()})
+				}
+				break
+			}
+			if n < DefaultBatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) isMember(ctx context.Context, orgID, userID string) (bool, error) {
+	if s.Members == nil {
+		return false, nil
+	}
+	err := s.Members.CheckOrgMember(ctx, orgID, userID)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, usage.ErrNotOrgMember), errors.Is(err, usage.ErrOrgNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
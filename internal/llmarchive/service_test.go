@@ -0,0 +1,185 @@
+package llmarchive_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/llmarchive"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/fieldcrypt"
+	"resume-backend/internal/usage"
+)
+
+func newService(t *testing.T, mode llmarchive.Mode) (*llmarchive.Service, *llmarchive.MemoryRepo) {
+	t.Helper()
+	ctx := context.Background()
+	members := usage.NewService()
+	if _, err := members.SetOrgQuota(ctx, "acme", "Team", 100, 0); err != nil {
+		t.Fatalf("set org quota: %v", err)
+	}
+	if _, err := members.AddOrgMember(ctx, "acme", "member-1"); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	codec, err := fieldcrypt.New([]fieldcrypt.Key{{ID: "a1", Material: bytes.Repeat([]byte{3}, fieldcrypt.KeySize)}})
+	if err != nil {
+		t.Fatalf("codec: %v", err)
+	}
+	repo := llmarchive.NewMemoryRepo()
+	return llmarchive.NewService(repo, codec, members, mode, 30*24*time.Hour), repo
+}
+
+type stubPrompts struct {
+	out string
+	err error
+}
+
+func (s stubPrompts) Complete(context.Context, string) (string, error) {
+	return s.out, s.err
+}
+
+func TestPolicyResolutionAndModes(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newService(t, llmarchive.ModeHashes)
+
+	if _, err := svc.SetOrg(ctx, "admin-1", "acme", "full", 7); err != nil {
+		t.Fatalf("SetOrg: %v", err)
+	}
+	// The organization's policy wins over the member's own.
+	if _, err := svc.SetUser(ctx, "admin-1", "member-1", "off", 0); err != nil {
+		t.Fatalf("SetUser member: %v", err)
+	}
+	if _, err := svc.SetUser(ctx, "admin-1", "solo", "off", 0); err != nil {
+		t.Fatalf("SetUser solo: %v", err)
+	}
+
+	prompts := llmarchive.WrapPrompts(stubPrompts{out: `{"ok":true}`}, svc)
+	for _, owner := range []string{"member-1", "solo", "guest:abc", "other"} {
+		ctx := ctxmeta.WithRequestID(ctxmeta.WithUserID(ctx, owner), "req-"+owner)
+		if _, err := prompts.Complete(ctx, "exact prompt for "+owner); err != nil {
+			t.Fatalf("Complete(%s): %v", owner, err)
+		}
+	}
+
+	records, _ := repo.List(ctx, llmarchive.Filter{})
+	byOwner := map[string]llmarchive.Record{}
+	for _, record := range records {
+		byOwner[record.OwnerID] = record
+	}
+	if _, ok := byOwner["solo"]; ok || len(records) != 3 {
+		t.Fatalf("expected solo's calls not to be archived, got %+v", records)
+	}
+	full := byOwner["member-1"]
+	if full.Mode != llmarchive.ModeFull || !fieldcrypt.IsEncrypted(full.Prompt) || !fieldcrypt.IsEncrypted(full.Response) {
+		t.Fatalf("expected a sealed full record, got %+v", full)
+	}
+	if got := full.ExpiresAt.Sub(full.CreatedAt); got != 7*24*time.Hour {
+		t.Fatalf("expected the org retention, got %s", got)
+	}
+	hashed := byOwner["guest:abc"]
+	if hashed.Mode != llmarchive.ModeHashes || hashed.Prompt != "" || hashed.Response != "" || len(hashed.PromptSHA256) != 64 || hashed.RequestID != "req-guest:abc" {
+		t.Fatalf("expected a hashes-only record, got %+v", hashed)
+	}
+	if got := hashed.ExpiresAt.Sub(hashed.CreatedAt); got != 30*24*time.Hour {
+		t.Fatalf("expected the default retention, got %s", got)
+	}
+
+	opened, err := svc.List(ctx, "admin-1", llmarchive.Filter{OwnerID: "member-1"})
+	if err != nil || len(opened) != 1 {
+		t.Fatalf("List = %+v, %v", opened, err)
+	}
+	if opened[0].Prompt != "exact prompt for member-1" || opened[0].Response != `{"ok":true}` {
+		t.Fatalf("expected opened bodies, got %+v", opened[0])
+	}
+}
+
+func TestSetRejectsInvalidPolicies(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newService(t, llmarchive.ModeOff)
+	if _, err := svc.SetUser(ctx, "admin-1", "u1", "verbose", 0); !errors.Is(err, llmarchive.ErrInvalidPolicy) {
+		t.Fatalf("expected invalid mode, got %v", err)
+	}
+	if _, err := svc.SetUser(ctx, "admin-1", "u1", "hashes", -1); !errors.Is(err, llmarchive.ErrInvalidPolicy) {
+		t.Fatalf("expected invalid retention, got %v", err)
+	}
+	if _, err := svc.SetUser(ctx, "admin-1", "guest:abc", "hashes", 0); !errors.Is(err, llmarchive.ErrInvalidPolicy) {
+		t.Fatalf("expected guests to be rejected, got %v", err)
+	}
+	svc.Codec = nil
+	if _, err := svc.SetOrg(ctx, "admin-1", "acme", "full", 0); !errors.Is(err, llmarchive.ErrKeysRequired) {
+		t.Fatalf("expected ErrKeysRequired without keys, got %v", err)
+	}
+}
+
+type stubLLM struct {
+	err error
+}
+
+func (s stubLLM) AnalyzeResume(context.Context, llm.AnalyzeInput) (json.RawMessage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return json.RawMessage(`{"score":80}`), nil
+}
+
+func TestClientArchivesAnalysesAndFailures(t *testing.T) {
+	svc, repo := newService(t, llmarchive.ModeFull)
+	ctx := ctxmeta.WithAnalysisID(ctxmeta.WithDataOwner(context.Background(), "owner-1"), "an-1")
+	input := llm.AnalyzeInput{ResumeText: "resume", JobDescription: "jd", PromptVersion: "v3"}
+
+	if _, err := llmarchive.Wrap(stubLLM{}, svc).AnalyzeResume(ctx, input); err != nil {
+		t.Fatalf("AnalyzeResume: %v", err)
+	}
+	failure := errors.New("provider timeout")
+	if _, err := llmarchive.Wrap(stubLLM{err: failure}, svc).AnalyzeResume(ctx, input); !errors.Is(err, failure) {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+
+	records, err := svc.List(ctx, "admin-1", llmarchive.Filter{AnalysisID: "an-1"})
+	if err != nil || len(records) != 2 {
+		t.Fatalf("List = %+v, %v", records, err)
+	}
+	failed, ok := records[0], records[1]
+	if failed.Error != "provider timeout" || failed.ResponseSHA256 != "" {
+		t.Fatalf("expected the failed call first, got %+v", failed)
+	}
+	if ok.Interaction != llmarchive.InteractionAnalysis || ok.PromptVersion != "v3" || ok.Response != `{"score":80}` || !strings.Contains(ok.Prompt, `"ResumeText":"resume"`) {
+		t.Fatalf("unexpected analysis record %+v", ok)
+	}
+
+	// Archive failures never fail the call.
+	stored, _ := repo.List(context.Background(), llmarchive.Filter{})
+	svc.Codec = nil
+	if _, err := llmarchive.Wrap(stubLLM{}, svc).AnalyzeResume(ctx, input); err != nil {
+		t.Fatalf("expected the call to succeed without archive keys, got %v", err)
+	}
+	if again, _ := repo.List(context.Background(), llmarchive.Filter{}); len(again) != len(stored) {
+		t.Fatalf("expected nothing archived without keys")
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	ctx := ctxmeta.WithUserID(context.Background(), "u1")
+	svc, repo := newService(t, llmarchive.ModeHashes)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	if err := svc.Record(ctx, llmarchive.Call{Interaction: llmarchive.InteractionPrompt, Prompt: "p"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	now = now.Add(29 * 24 * time.Hour)
+	if n, err := svc.PurgeExpired(ctx, 0); err != nil || n != 0 {
+		t.Fatalf("expected nothing purged before expiry, got %d, %v", n, err)
+	}
+	now = now.Add(24 * time.Hour)
+	if n, err := svc.PurgeExpired(ctx, 0); err != nil || n != 1 {
+		t.Fatalf("expected the record purged, got %d, %v", n, err)
+	}
+	if records, _ := repo.List(ctx, llmarchive.Filter{}); len(records) != 0 {
+		t.Fatalf("expected an empty archive, got %+v", records)
+	}
+}
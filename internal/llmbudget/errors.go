@@ -0,0 +1,7 @@
+package llmbudget
+
+import "errors"
+
+// ErrExceeded indicates a daily LLM budget is spent and new analyses are rejected
+// until it resets.
+var ErrExceeded = errors.New("daily llm budget exceeded")
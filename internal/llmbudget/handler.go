@@ -0,0 +1,40 @@
+package llmbudget
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the LLM budget admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches budget routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/llm-budget", h.get)
+}
+
+// get reports today's spend against the global budget and, with ?orgId=, the
+// organization's.
+func (h *Handler) get(c *gin.Context) {
+	statuses, err := h.Svc.Statuses(c.Request.Context(), strings.TrimSpace(c.Query("orgId")))
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load llm spend", nil)
+		return
+	}
+	action := ""
+	if h.Svc.Enabled() {
+		action = normalizeAction(h.Svc.Action)
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"action": action, "budgets": statuses})
+}
@@ -0,0 +1,75 @@
+package llmbudget
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// GlobalScope is the scope of the budget shared by every analysis.
+const GlobalScope = "global"
+
+// OrgScope returns the scope of an organization's budget.
+func OrgScope(orgID string) string {
+	return "org:" + orgID
+}
+
+// Actions taken on new analyses once a budget is spent.
+const (
+	// ActionDefer creates analyses with status budget_deferred and queues them
+	// when the day resets.
+	ActionDefer = "defer"
+	// ActionReject refuses new analyses with ErrExceeded.
+	ActionReject = "reject"
+)
+
+// dayLayout formats the UTC day a spend is counted under.
+const dayLayout = "2006-01-02"
+
+// Spend is a scope's LLM usage on one UTC day.
+type Spend struct {
+	Scope            string
+	Day              string
+	PromptTokens     int64
+	CompletionTokens int64
+	// CostMicroUSD is the cost in millionths of a dollar, so sums stay exact.
+	CostMicroUSD int64
+	UpdatedAt    time.Time
+}
+
+// Pricing converts token counts to dollars.
+type Pricing struct {
+	PromptPer1K     float64
+	CompletionPer1K float64
+}
+
+func (p Pricing) costMicroUSD(promptTokens, completionTokens int64) int64 {
+	usd := float64(promptTokens)/1000*p.PromptPer1K + float64(completionTokens)/1000*p.CompletionPer1K
+	return int64(math.Round(usd * 1e6))
+}
+
+// Status is a scope's budget for the current day.
+type Status struct {
+	Scope            string    `json:"scope"`
+	Day              string    `json:"day"`
+	PromptTokens     int64     `json:"promptTokens"`
+	CompletionTokens int64     `json:"completionTokens"`
+	SpentUSD         float64   `json:"spentUsd"`
+	BudgetUSD        float64   `json:"budgetUsd"`
+	Exceeded         bool      `json:"exceeded"`
+	ResetsAt         time.Time `json:"resetsAt"`
+}
+
+// ResetsAt returns when the budget day containing now ends: the next UTC
+// midnight.
+func ResetsAt(now time.Time) time.Time {
+	day := now.UTC().Truncate(24 * time.Hour)
+	return day.Add(24 * time.Hour)
+}
+
+func normalizeAction(action string) string {
+	if strings.EqualFold(strings.TrimSpace(action), ActionReject) {
+		return ActionReject
+	}
+	return ActionDefer
+}
@@ -0,0 +1,12 @@
+package llmbudget
+
+import "context"
+
+// Repo persists daily spend. It is shared by the API, which checks budgets, and
+// the worker, which charges analyses to them.
+type Repo interface {
+	// Add adds to a scope's spend for the day, creating the row if needed.
+	Add(ctx context.Context, spend Spend) error
+	// Get returns a scope's spend for the day, or a zero Spend if there is none.
+	Get(ctx context.Context, scope, day string) (Spend, error)
+}
@@ -0,0 +1,49 @@
+package llmbudget
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryRepo stores spend in memory.
+type MemoryRepo struct {
+	mu     sync.Mutex
+	spends map[string]Spend
+}
+
+// NewMemoryRepo constructs an empty MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{spends: map[string]Spend{}}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Add adds to a scope's spend for the day.
+func (r *MemoryRepo) Add(ctx context.Context, spend Spend) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := spend.Scope + "|" + spend.Day
+	current := r.spends[key]
+	current.Scope, current.Day, current.UpdatedAt = spend.Scope, spend.Day, spend.UpdatedAt
+	current.PromptTokens += spend.PromptTokens
+	current.CompletionTokens += spend.CompletionTokens
+	current.CostMicroUSD += spend.CostMicroUSD
+	r.spends[key] = current
+	return nil
+}
+
+// Get returns a scope's spend for the day.
+func (r *MemoryRepo) Get(ctx context.Context, scope, day string) (Spend, error) {
+	if err := ctx.Err(); err != nil {
+		return Spend{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if spend, ok := r.spends[scope+"|"+day]; ok {
+		return spend, nil
+	}
+	return Spend{Scope: scope, Day: day}, nil
+}
@@ -0,0 +1,61 @@
+package llmbudget
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// Add adds to a scope's spend for the day in one statement, so concurrent
+// workers do not lose each other's charges.
+func (r *PGRepo) Add(ctx context.Context, spend Spend) error {
+	const query = `
+INSERT INTO llm_spend (
+    scope,
+    day,
+    prompt_tokens,
+    completion_tokens,
+    cost_micro_usd,
+    updated_at
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (scope, day) DO UPDATE SET
+    prompt_tokens = llm_spend.prompt_tokens + EXCLUDED.prompt_tokens,
+    completion_tokens = llm_spend.completion_tokens + EXCLUDED.completion_tokens,
+    cost_micro_usd = llm_spend.cost_micro_usd + EXCLUDED.cost_micro_usd,
+    updated_at = EXCLUDED.updated_at`
+	_, err := r.DB.ExecContext(ctx, query,
+		spend.Scope,
+		spend.Day,
+		spend.PromptTokens,
+		spend.CompletionTokens,
+		spend.CostMicroUSD,
+		spend.UpdatedAt,
+	)
+	return err
+}
+
+// Get returns a scope's spend for the day.
+func (r *PGRepo) Get(ctx context.Context, scope, day string) (Spend, error) {
+	const query = `
+SELECT prompt_tokens, completion_tokens, cost_micro_usd, updated_at
+FROM llm_spend
+WHERE scope = $1 AND day = $2`
+	spend := Spend{Scope: scope, Day: day}
+	err := r.DB.QueryRowContext(ctx, query, scope, day).Scan(
+		&spend.PromptTokens,
+		&spend.CompletionTokens,
+		&spend.CostMicroUSD,
+		&spend.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return spend, nil
+	}
+	return spend, err
+}
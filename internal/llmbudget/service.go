@@ -0,0 +1,159 @@
+package llmbudget
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Service tracks LLM spend per UTC day against a global budget and a budget per
+// organization. The caps are soft: analyses already queued still run and are
+// charged, so spend can end a little over budget. A nil Service or one without
+// budgets never reports a budget as exceeded.
+type Service struct {
+	Repo    Repo
+	Pricing Pricing
+	// GlobalDailyUSD and OrgDailyUSD are the daily budgets; zero disables one.
+	GlobalDailyUSD float64
+	OrgDailyUSD    float64
+	// Action is ActionDefer or ActionReject.
+	Action string
+	Now    func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, pricing Pricing, globalDailyUSD, orgDailyUSD float64, action string) *Service {
+	return &Service{
+		Repo:           repo,
+		Pricing:        pricing,
+		GlobalDailyUSD: globalDailyUSD,
+		OrgDailyUSD:    orgDailyUSD,
+		Action:         normalizeAction(action),
+	}
+}
+
+// Enabled reports whether any budget is set.
+func (s *Service) Enabled() bool {
+	return s != nil && s.Repo != nil && (s.GlobalDailyUSD > 0 || s.OrgDailyUSD > 0)
+}
+
+// Defers reports whether new analyses over budget are deferred rather than
+// rejected.
+func (s *Service) Defers() bool {
+	return s != nil && normalizeAction(s.Action) == ActionDefer
+}
+
+// Check returns the first exceeded budget that applies to an analysis charged
+// to orgID, global first. With no exceeded budget the returned Status has
+// Exceeded unset. orgID may be empty.
+func (s *Service) Check(ctx context.Context, orgID string) (Status, error) {
+	if !s.Enabled() {
+		return Status{}, nil
+	}
+	statuses, err := s.Statuses(ctx, orgID)
+	if err != nil {
+		return Status{}, err
+	}
+	for _, status := range statuses {
+		if status.Exceeded {
+			return status, nil
+		}
+	}
+	return Status{}, nil
+}
+
+// Statuses reports today's spend against each budget that applies to orgID.
+func (s *Service) Statuses(ctx context.Context, orgID string) ([]Status, error) {
+	if !s.Enabled() {
+		return []Status{}, nil
+	}
+	now := s.now()
+	out := []Status{}
+	if s.GlobalDailyUSD > 0 {
+		status, err := s.status(ctx, GlobalScope, s.GlobalDailyUSD, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, status)
+	}
+	if orgID != "" && s.OrgDailyUSD > 0 {
+		status, err := s.status(ctx, OrgScope(orgID), s.OrgDailyUSD, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+func (s *Service) status(ctx context.Context, scope string, budget float64, now time.Time) (Status, error) {
+	spend, err := s.Repo.Get(ctx, scope, now.Format(dayLayout))
+	if err != nil {
+		return Status{}, fmt.Errorf("load llm spend %s: %w", scope, err)
+	}
+	spent := float64(spend.CostMicroUSD) / 1e6
+	return Status{
+		Scope:            scope,
+		Day:              now.Format(dayLayout),
+		PromptTokens:     spend.PromptTokens,
+		CompletionTokens: spend.CompletionTokens,
+		SpentUSD:         spent,
+		BudgetUSD:        budget,
+		Exceeded:         spent >= budget,
+		ResetsAt:         ResetsAt(now),
+	}, nil
+}
+
+// Record charges the tokens counted in usage to the global budget and, when
+// orgID is set, to the organization's.
+func (s *Service) Record(ctx context.Context, orgID string, usage *ctxmeta.TokenUsage) error {
+	if s == nil || s.Repo == nil || usage == nil {
+		return nil
+	}
+	prompt := atomic.LoadInt64(&usage.PromptTokens)
+	completion := atomic.LoadInt64(&usage.CompletionTokens)
+	if prompt == 0 && completion == 0 {
+		return nil
+	}
+	now := s.now()
+	spend := Spend{
+		Day:              now.Format(dayLayout),
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+		CostMicroUSD:     s.Pricing.costMicroUSD(prompt, completion),
+		UpdatedAt:        now,
+	}
+	scopes := []string{GlobalScope}
+	if orgID != "" {
+		scopes = append(scopes, OrgScope(orgID))
+	}
+	for _, scope := range scopes {
+		spend.Scope = scope
+		if err := s.Repo.Add(ctx, spend); err != nil {
+			return fmt.Errorf("record llm spend %s: %w", scope, err)
+		}
+	}
+	telemetry.InfoContext(ctx, "llmbudget.recorded", map[string]any{
+		"org_id":            orgID,
+		"prompt_tokens":     prompt,
+		"completion_tokens": completion,
+		"cost_micro_usd":    spend.CostMicroUSD,
+	})
+	return nil
+}
+
+// NextReset returns when today's budgets reset.
+func (s *Service) NextReset() time.Time {
+	return ResetsAt(s.now())
+}
+
+func (s *Service) now() time.Time {
+	if s != nil && s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,75 @@
+package llmbudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+func TestRecordChargesGlobalAndOrgBudgets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 4, 22, 0, 0, 0, time.UTC)
+	svc := NewService(NewMemoryRepo(), Pricing{PromptPer1K: 0.5, CompletionPer1K: 1.5}, 10, 2, "")
+	svc.Now = func() time.Time { return now }
+
+	if status, err := svc.Check(ctx, "org-1"); err != nil || status.Exceeded {
+		t.Fatalf("fresh budgets: status=%+v err=%v", status, err)
+	}
+	// 2,000 prompt tokens at $0.50/1K plus 1,000 completion tokens at $1.50/1K is $2.50.
+	if err := svc.Record(ctx, "org-1", &ctxmeta.TokenUsage{PromptTokens: 2000, CompletionTokens: 1000}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	status, err := svc.Check(ctx, "org-1")
+	if err != nil || !status.Exceeded || status.Scope != OrgScope("org-1") || status.SpentUSD != 2.5 {
+		t.Fatalf("org budget: status=%+v err=%v", status, err)
+	}
+	if !status.ResetsAt.Equal(time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("resetsAt = %s, want next UTC midnight", status.ResetsAt)
+	}
+	if status, _ := svc.Check(ctx, "org-2"); status.Exceeded {
+		t.Fatalf("another org should be under budget, got %+v", status)
+	}
+	if status, _ := svc.Check(ctx, ""); status.Exceeded {
+		t.Fatalf("the global budget should have room, got %+v", status)
+	}
+
+	// The next UTC day starts from zero.
+	now = now.Add(3 * time.Hour)
+	if status, _ := svc.Check(ctx, "org-1"); status.Exceeded {
+		t.Fatalf("budget should reset at midnight, got %+v", status)
+	}
+}
+
+func TestGlobalBudgetAppliesToEveryScope(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryRepo(), Pricing{PromptPer1K: 1}, 1, 0, ActionReject)
+	if err := svc.Record(ctx, "", &ctxmeta.TokenUsage{PromptTokens: 1000}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	status, err := svc.Check(ctx, "org-1")
+	if err != nil || !status.Exceeded || status.Scope != GlobalScope {
+		t.Fatalf("status=%+v err=%v", status, err)
+	}
+	if svc.Defers() {
+		t.Fatal("reject action should not defer")
+	}
+}
+
+func TestNilOrUnsetServiceNeverExceeds(t *testing.T) {
+	ctx := context.Background()
+	var nilSvc *Service
+	if status, err := nilSvc.Check(ctx, "org-1"); err != nil || status.Exceeded {
+		t.Fatalf("nil service: status=%+v err=%v", status, err)
+	}
+	if err := nilSvc.Record(ctx, "org-1", &ctxmeta.TokenUsage{PromptTokens: 10}); err != nil {
+		t.Fatalf("nil record: %v", err)
+	}
+	unset := NewService(NewMemoryRepo(), Pricing{PromptPer1K: 1}, 0, 0, "")
+	_ = unset.Record(ctx, "", &ctxmeta.TokenUsage{PromptTokens: 1_000_000})
+	if status, _ := unset.Check(ctx, ""); status.Exceeded {
+		t.Fatal("no budget set should never be exceeded")
+	}
+}
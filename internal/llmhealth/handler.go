@@ -0,0 +1,40 @@
+package llmhealth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the LLM health admin API.
+type Handler struct {
+	Monitor *Monitor
+}
+
+// NewHandler constructs a Handler. A nil monitor means the configured
+// provider cannot be probed.
+func NewHandler(m *Monitor) *Handler {
+	return &Handler{Monitor: m}
+}
+
+// RegisterRoutes attaches health routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/llm/health", h.health)
+}
+
+// health probes the provider now and reports the result; 503 when the probe
+// failed.
+func (h *Handler) health(c *gin.Context) {
+	if h.Monitor == nil {
+		respond.Error(c, http.StatusServiceUnavailable, "llm_probe_unavailable", "the configured llm provider does not support health probes", nil)
+		return
+	}
+	status := h.Monitor.Check(c.Request.Context())
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	respond.JSON(c, code, status)
+}
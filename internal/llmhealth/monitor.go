@@ -0,0 +1,249 @@
+// Package llmhealth probes the LLM provider with minimal requests and opens a
+// circuit breaker in front of analysis calls while the provider is down.
+package llmhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+const (
+	// DefaultModel is probed when Monitor.Model is empty; it is among the
+	// cheapest chat models.
+	DefaultModel = "gpt-4o-mini"
+	// DefaultFailureThreshold is how many probes in a row must fail before the
+	// breaker opens.
+	DefaultFailureThreshold = 3
+	// DefaultTimeout bounds a single probe.
+	DefaultTimeout = 10 * time.Second
+)
+
+// ErrCircuitOpen is returned instead of calling the provider while recent
+// probes have failed.
+var ErrCircuitOpen = errors.New("llm provider unavailable: circuit open")
+
+// Auth states reported by a probe.
+const (
+	AuthValid   = "valid"
+	AuthInvalid = "invalid"
+	AuthUnknown = "unknown"
+)
+
+// Breaker states.
+const (
+	BreakerClosed = "closed"
+	BreakerOpen   = "open"
+)
+
+// Status is the outcome of the latest probe and the breaker state it left.
+type Status struct {
+	Provider  string           `json:"provider"`
+	Model     string           `json:"model"`
+	Healthy   bool             `json:"healthy"`
+	LatencyMs int64            `json:"latencyMs"`
+	Auth      string           `json:"auth"`
+	RateLimit *RateLimitStatus `json:"rateLimit,omitempty"`
+	// Breaker is open while ConsecutiveFailures is at least the threshold.
+	Breaker             string    `json:"breaker"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	CheckedAt           time.Time `json:"checkedAt"`
+	Error               string    `json:"error,omitempty"`
+}
+
+// RateLimitStatus is the provider's remaining allowance. Headroom is the
+// smaller remaining share of the two limits, from 0 to 1.
+type RateLimitStatus struct {
+	LimitRequests     int     `json:"limitRequests,omitempty"`
+	RemainingRequests int     `json:"remainingRequests,omitempty"`
+	LimitTokens       int     `json:"limitTokens,omitempty"`
+	RemainingTokens   int     `json:"remainingTokens,omitempty"`
+	Headroom          float64 `json:"headroom"`
+}
+
+// Monitor probes the provider and keeps the breaker state. A nil Monitor
+// reports healthy and never opens.
+type Monitor struct {
+	Prober   llm.Prober
+	Provider string
+	Model    string
+	// FailureThreshold is how many probes in a row must fail before the
+	// breaker opens; one successful probe closes it again.
+	FailureThreshold int
+	Timeout          time.Duration
+	Now              func() time.Time
+
+	mu       sync.Mutex
+	last     *Status
+	failures int
+}
+
+// NewMonitor constructs a Monitor with the default model, threshold and timeout.
+func NewMonitor(prober llm.Prober, provider string) *Monitor {
+	return &Monitor{Prober: prober, Provider: provider}
+}
+
+// Check probes the provider now, updates the breaker and the llm_* gauges and
+// returns the new status. Rate limiting counts as healthy: the key works and
+// headroom reports the exhaustion.
+func (m *Monitor) Check(ctx context.Context) Status {
+	model := m.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	probe, err := m.Prober.Probe(probeCtx, model)
+	cancel()
+
+	status := Status{
+		Provider:  m.Provider,
+		Model:     probe.Model,
+		LatencyMs: probe.Latency.Milliseconds(),
+		Auth:      AuthUnknown,
+		CheckedAt: m.now(),
+	}
+	if probe.StatusCode != 0 {
+		status.Auth = AuthValid
+		if !probe.AuthValid {
+			status.Auth = AuthInvalid
+		}
+	}
+	headroom := probe.RateLimit.Headroom()
+	if headroom >= 0 {
+		status.RateLimit = &RateLimitStatus{
+			LimitRequests:     probe.RateLimit.LimitRequests,
+			RemainingRequests: probe.RateLimit.RemainingRequests,
+			LimitTokens:       probe.RateLimit.LimitTokens,
+			RemainingTokens:   probe.RateLimit.RemainingTokens,
+			Headroom:          headroom,
+		}
+	}
+	status.Healthy = err == nil || probe.StatusCode == 429
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	m.mu.Lock()
+	wasOpen := m.openLocked()
+	if status.Healthy {
+		m.failures = 0
+	} else {
+		m.failures++
+	}
+	status.ConsecutiveFailures = m.failures
+	status.Breaker = BreakerClosed
+	if m.openLocked() {
+		status.Breaker = BreakerOpen
+	}
+	m.last = &status
+	m.mu.Unlock()
+
+	metrics.SetLLMProviderUp(status.Healthy)
+	metrics.SetLLMProbe(probe.Latency, headroom)
+	if isOpen := status.Breaker == BreakerOpen; isOpen != wasOpen {
+		fields := map[string]any{"provider": status.Provider, "model": status.Model, "failures": status.ConsecutiveFailures, "error": status.Error}
+		if isOpen {
+			telemetry.ErrorContext(ctx, "llm.breaker.opened", fields)
+		} else {
+			telemetry.InfoContext(ctx, "llm.breaker.closed", fields)
+		}
+	}
+	return status
+}
+
+// Run probes every interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Last returns the latest status, if a probe has run.
+func (m *Monitor) Last() (Status, bool) {
+	if m == nil {
+		return Status{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.last == nil {
+		return Status{}, false
+	}
+	return *m.last, true
+}
+
+// Open reports whether the breaker is open.
+func (m *Monitor) Open() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.openLocked()
+}
+
+// Ready is the readiness check: the breaker is closed.
+func (m *Monitor) Ready() bool {
+	return !m.Open()
+}
+
+// Stats reports the latest status for the admin stats endpoint.
+func (m *Monitor) Stats(ctx context.Context) (any, error) {
+	status, ok := m.Last()
+	if !ok {
+		return map[string]any{"provider": m.Provider, "breaker": BreakerClosed, "checkedAt": nil}, nil
+	}
+	return status, nil
+}
+
+func (m *Monitor) openLocked() bool {
+	threshold := m.FailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	return m.failures >= threshold
+}
+
+func (m *Monitor) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now().UTC()
+}
+
+// Guard wraps client so calls fail fast with ErrCircuitOpen while the breaker
+// is open. A nil Monitor returns client unchanged.
+func (m *Monitor) Guard(client llm.Client) llm.Client {
+	if m == nil || client == nil {
+		return client
+	}
+	return guardedClient{base: client, monitor: m}
+}
+
+type guardedClient struct {
+	base    llm.Client
+	monitor *Monitor
+}
+
+func (g guardedClient) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	if g.monitor.Open() {
+		return nil, ErrCircuitOpen
+	}
+	return g.base.AnalyzeResume(ctx, input)
+}
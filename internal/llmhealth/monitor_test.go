@@ -0,0 +1,128 @@
+package llmhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/llm"
+)
+
+type fakeProber struct {
+	probe llm.HealthProbe
+	err   error
+}
+
+func (f *fakeProber) Probe(ctx context.Context, model string) (llm.HealthProbe, error) {
+	probe := f.probe
+	probe.Model = model
+	return probe, f.err
+}
+
+type countingLLM struct {
+	calls int
+}
+
+func (c *countingLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	c.calls++
+	return json.RawMessage(`{}`), nil
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	prober := &fakeProber{probe: llm.HealthProbe{StatusCode: http.StatusOK, AuthValid: true, Latency: 120 * time.Millisecond}}
+	monitor := NewMonitor(prober, "openai")
+	monitor.FailureThreshold = 2
+	base := &countingLLM{}
+	client := monitor.Guard(base)
+
+	if status := monitor.Check(ctx); !status.Healthy || status.Breaker != BreakerClosed || status.Auth != AuthValid || status.Model != DefaultModel || status.LatencyMs != 120 {
+		t.Fatalf("healthy probe: %+v", status)
+	}
+
+	prober.probe = llm.HealthProbe{StatusCode: http.StatusInternalServerError, AuthValid: true}
+	prober.err = errors.New("openai http status 500")
+	if status := monitor.Check(ctx); status.Healthy || status.Breaker != BreakerClosed || status.ConsecutiveFailures != 1 {
+		t.Fatalf("one failure should not open the breaker: %+v", status)
+	}
+	if _, err := client.AnalyzeResume(ctx, llm.AnalyzeInput{}); err != nil || base.calls != 1 {
+		t.Fatalf("closed breaker should call through, err=%v calls=%d", err, base.calls)
+	}
+
+	if status := monitor.Check(ctx); status.Breaker != BreakerOpen || monitor.Ready() {
+		t.Fatalf("second failure should open the breaker: %+v", status)
+	}
+	if _, err := client.AnalyzeResume(ctx, llm.AnalyzeInput{}); !errors.Is(err, ErrCircuitOpen) || base.calls != 1 {
+		t.Fatalf("open breaker should fail fast, err=%v calls=%d", err, base.calls)
+	}
+
+	prober.probe = llm.HealthProbe{StatusCode: http.StatusOK, AuthValid: true}
+	prober.err = nil
+	if status := monitor.Check(ctx); status.Breaker != BreakerClosed || !monitor.Ready() {
+		t.Fatalf("a successful probe should close the breaker: %+v", status)
+	}
+}
+
+func TestRateLimitedProbeCountsAsHealthy(t *testing.T) {
+	prober := &fakeProber{
+		probe: llm.HealthProbe{
+			StatusCode: http.StatusTooManyRequests,
+			AuthValid:  true,
+			RateLimit:  llm.RateLimit{LimitRequests: 500, RemainingRequests: 0},
+		},
+		err: errors.New("openai http status 429"),
+	}
+	monitor := NewMonitor(prober, "openai")
+	monitor.FailureThreshold = 1
+
+	status := monitor.Check(context.Background())
+	if !status.Healthy || status.Breaker != BreakerClosed {
+		t.Fatalf("rate limiting should not open the breaker: %+v", status)
+	}
+	if status.RateLimit == nil || status.RateLimit.Headroom != 0 {
+		t.Fatalf("rate limit = %+v", status.RateLimit)
+	}
+
+	prober.probe = llm.HealthProbe{StatusCode: http.StatusUnauthorized}
+	prober.err = errors.New("openai http status 401")
+	if status := monitor.Check(context.Background()); status.Healthy || status.Auth != AuthInvalid || status.Breaker != BreakerOpen {
+		t.Fatalf("rejected key: %+v", status)
+	}
+}
+
+func TestHealthEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prober := &fakeProber{probe: llm.HealthProbe{StatusCode: http.StatusOK, AuthValid: true}}
+
+	call := func(m *Monitor) *httptest.ResponseRecorder {
+		router := gin.New()
+		NewHandler(m).RegisterRoutes(router.Group("/admin"))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/llm/health", nil))
+		return resp
+	}
+
+	monitor := NewMonitor(prober, "openai")
+	if resp := call(monitor); resp.Code != http.StatusOK {
+		t.Fatalf("healthy provider: status %d body %s", resp.Code, resp.Body)
+	}
+	prober.probe = llm.HealthProbe{}
+	prober.err = errors.New("dial tcp: connection refused")
+	resp := call(monitor)
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("failed probe: status %d", resp.Code)
+	}
+	var body Status
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.Auth != AuthUnknown || body.Error == "" {
+		t.Fatalf("body = %s (%v)", resp.Body, err)
+	}
+	if resp := call(nil); resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("no monitor: status %d", resp.Code)
+	}
+}
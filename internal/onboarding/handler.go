@@ -0,0 +1,50 @@
+package onboarding
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Handler serves onboarding routes.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches the onboarding routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/onboarding/sample-analysis", h.createSample)
+}
+
+// createSample works for guests and signed-in users alike; a guest's sample
+// moves with the rest of their data when they sign in.
+func (h *Handler) createSample(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := middleware.UserIDFromContext(c)
+	sample, err := h.Svc.CreateSample(ctx, userID)
+	if err != nil {
+		telemetry.ErrorContext(ctx, "onboarding.sample_failed", map[string]any{"user_id": userID, "error": err.Error()})
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to create sample analysis", nil)
+		return
+	}
+	status := http.StatusOK
+	if sample.Created {
+		status = http.StatusCreated
+	}
+	respond.JSON(c, status, gin.H{
+		"documentId": sample.Document.ID,
+		"analysisId": sample.Analysis.ID,
+		"status":     sample.Analysis.Status,
+		"result":     sample.Analysis.Result,
+		"created":    sample.Created,
+	})
+}
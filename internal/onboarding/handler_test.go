@@ -0,0 +1,63 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+func TestCreateSampleForGuestIsIdempotent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := local.New(t.TempDir())
+	docRepo := documents.NewMemoryRepo()
+	analysisRepo := analyses.NewMemoryRepo()
+	// No LLM client, queue or usage service: the sample must not need them.
+	analysisSvc := &analyses.Service{Repo: analysisRepo, DocRepo: docRepo, Store: store}
+	docSvc := &documents.Service{Store: store, Repo: docRepo}
+
+	router := gin.New()
+	router.Use(middleware.Auth("dev"))
+	NewHandler(NewService(docSvc, analysisSvc)).RegisterRoutes(router.Group("/api/v1"))
+
+	post := func() (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/onboarding/sample-analysis", nil)
+		req.Header.Set("X-Guest-Id", "g1")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		var body map[string]any
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode %s: %v", resp.Body, err)
+		}
+		return resp.Code, body
+	}
+
+	code, first := post()
+	if code != http.StatusCreated || first["status"] != analyses.StatusCompleted || first["result"] == nil || first["created"] != true {
+		t.Fatalf("first call: status %d body %v", code, first)
+	}
+	code, second := post()
+	if code != http.StatusOK || second["created"] != false {
+		t.Fatalf("second call: status %d body %v", code, second)
+	}
+	if second["documentId"] != first["documentId"] || second["analysisId"] != first["analysisId"] {
+		t.Fatalf("expected the same sample, got %v then %v", first, second)
+	}
+
+	docs, err := docRepo.ListByUser(context.Background(), "guest:g1", 10, 0)
+	if err != nil || len(docs) != 1 || docs[0].OriginalFilename != SampleFileName {
+		t.Fatalf("expected one sample document, got %+v (err %v)", docs, err)
+	}
+	stored, err := analysisRepo.GetByID(context.Background(), first["analysisId"].(string))
+	if err != nil || stored.Provider != analyses.SampleProvider || stored.UserID != "guest:g1" {
+		t.Fatalf("expected the sample analysis in the guest's account, got %+v (err %v)", stored, err)
+	}
+}
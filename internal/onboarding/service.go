@@ -0,0 +1,88 @@
+// Package onboarding copies a bundled sample resume and its recorded analysis
+// into a new account, so clients can show a fully populated experience before
+// the user uploads anything of their own.
+package onboarding
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+)
+
+// SampleFileName is the name the sample resume is stored under.
+const SampleFileName = "sample_resume.docx"
+
+// sampleJobDescription is the posting the recorded analysis was run against.
+const sampleJobDescription = "Senior Backend Engineer, Payments Platform. You will design and operate the Go services that move money for millions of merchants: ledgers, payouts and reconciliation. We run on AWS with Kubernetes and PostgreSQL, and every service owns its SLOs. You have 5+ years building distributed systems, have run services across multiple regions, take part in incident response, and enjoy mentoring. Experience with Kafka and PCI DSS is a plus."
+
+//go:embed sample
+var sampleFiles embed.FS
+
+// DocumentUploader stores uploaded documents.
+type DocumentUploader interface {
+	UploadWithOptions(ctx context.Context, userID, fileName, declaredType string, r io.Reader, opts documents.UploadOptions) (documents.Document, bool, error)
+}
+
+// SampleAnalyzer stores recorded output as a completed analysis.
+type SampleAnalyzer interface {
+	CreateSampleAnalysis(ctx context.Context, userID, documentID string, sample analyses.SampleAnalysis) (analyses.Analysis, bool, error)
+}
+
+// Service creates onboarding samples.
+type Service struct {
+	Documents DocumentUploader
+	Analyses  SampleAnalyzer
+}
+
+// NewService constructs a Service.
+func NewService(uploader DocumentUploader, analyzer SampleAnalyzer) *Service {
+	return &Service{Documents: uploader, Analyses: analyzer}
+}
+
+// Sample is the sample document and analysis in a user's account.
+type Sample struct {
+	Document documents.Document
+	Analysis analyses.Analysis
+	// Created is false when the user already had the sample.
+	Created bool
+}
+
+// CreateSample copies the sample resume and its analysis into userID's
+// account. Calling it again returns the copy made the first time. It consumes
+// no usage and makes no LLM calls.
+func (s *Service) CreateSample(ctx context.Context, userID string) (Sample, error) {
+	if userID == "" {
+		return Sample{}, errors.New("userID is required")
+	}
+	resume, err := sampleFiles.ReadFile("sample/resume.docx")
+	if err != nil {
+		return Sample{}, fmt.Errorf("read sample resume: %w", err)
+	}
+	output, err := sampleFiles.ReadFile("sample/analysis.json")
+	if err != nil {
+		return Sample{}, fmt.Errorf("read sample analysis: %w", err)
+	}
+
+	// Linking duplicates keeps repeated calls on the one sample document.
+	doc, _, err := s.Documents.UploadWithOptions(ctx, userID, SampleFileName, "", bytes.NewReader(resume), documents.UploadOptions{LinkDuplicates: true})
+	if err != nil {
+		return Sample{}, fmt.Errorf("upload sample resume: %w", err)
+	}
+	analysis, created, err := s.Analyses.CreateSampleAnalysis(ctx, userID, doc.ID, analyses.SampleAnalysis{
+		Mode:           analyses.ModeJobMatch,
+		PromptVersion:  "v2_3",
+		JobDescription: sampleJobDescription,
+		Output:         json.RawMessage(output),
+	})
+	if err != nil {
+		return Sample{}, fmt.Errorf("create sample analysis: %w", err)
+	}
+	return Sample{Document: doc, Analysis: analysis, Created: created}, nil
+}
@@ -0,0 +1,30 @@
+package pipelinestatus
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the pipeline status admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches the pipeline status route to an admin-only router
+// group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/pipeline", h.snapshot)
+}
+
+// snapshot always answers 200; failed sections are marked unavailable.
+func (h *Handler) snapshot(c *gin.Context) {
+	respond.JSON(c, http.StatusOK, h.Svc.Snapshot(c.Request.Context()))
+}
@@ -0,0 +1,120 @@
+package pipelinestatus
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLatencyWindow is how far back storage latency samples count.
+	DefaultLatencyWindow = 15 * time.Minute
+	// DefaultLatencySamples bounds the samples kept per operation.
+	DefaultLatencySamples = 1000
+)
+
+// LatencySummary describes the recent calls of one storage operation.
+type LatencySummary struct {
+	Count  int   `json:"count"`
+	Errors int   `json:"errors"`
+	P50Ms  int64 `json:"p50Ms"`
+	P90Ms  int64 `json:"p90Ms"`
+	P99Ms  int64 `json:"p99Ms"`
+}
+
+type latencySample struct {
+	at     time.Time
+	d      time.Duration
+	failed bool
+}
+
+// StorageLatency keeps the latest storage call durations per operation. Its
+// Observe method fits residency.Store's Observe hook.
+type StorageLatency struct {
+	// Window defaults to DefaultLatencyWindow.
+	Window time.Duration
+	// MaxSamples defaults to DefaultLatencySamples.
+	MaxSamples int
+	Now        func() time.Time
+
+	mu      sync.Mutex
+	samples map[string][]latencySample
+}
+
+// NewStorageLatency constructs a StorageLatency with the default window.
+func NewStorageLatency() *StorageLatency {
+	return &StorageLatency{}
+}
+
+// Observe records one call of op.
+func (l *StorageLatency) Observe(op string, d time.Duration, err error) {
+	limit := l.MaxSamples
+	if limit <= 0 {
+		limit = DefaultLatencySamples
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.samples == nil {
+		l.samples = map[string][]latencySample{}
+	}
+	kept := append(l.samples[op], latencySample{at: l.now(), d: d, failed: err != nil})
+	if len(kept) > limit {
+		kept = slices.Delete(kept, 0, len(kept)-limit)
+	}
+	l.samples[op] = kept
+}
+
+// Summary reports the percentiles of each operation's calls within the window.
+// Operations without recent calls are left out.
+func (l *StorageLatency) Summary() map[string]LatencySummary {
+	window := l.Window
+	if window <= 0 {
+		window = DefaultLatencyWindow
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cutoff := l.now().Add(-window)
+	out := map[string]LatencySummary{}
+	for op, samples := range l.samples {
+		var (
+			durations []time.Duration
+			errors    int
+		)
+		for _, s := range samples {
+			if s.at.Before(cutoff) {
+				continue
+			}
+			durations = append(durations, s.d)
+			if s.failed {
+				errors++
+			}
+		}
+		if len(durations) == 0 {
+			continue
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		out[op] = LatencySummary{
+			Count:  len(durations),
+			Errors: errors,
+			P50Ms:  percentile(durations, 0.5).Milliseconds(),
+			P90Ms:  percentile(durations, 0.9).Milliseconds(),
+			P99Ms:  percentile(durations, 0.99).Milliseconds(),
+		}
+	}
+	return out
+}
+
+// percentile is the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(idx, 0)]
+}
+
+func (l *StorageLatency) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now().UTC()
+}
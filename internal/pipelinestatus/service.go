@@ -0,0 +1,211 @@
+// Package pipelinestatus assembles the on-call view of the analysis pipeline:
+// queue backlog, in-flight analyses, recent error codes, LLM provider health
+// and storage latency, in one snapshot instead of several consoles.
+package pipelinestatus
+
+import (
+	"context"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/llmhealth"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// DefaultErrorWindow is how far back failed analyses are counted.
+const DefaultErrorWindow = time.Hour
+
+// unavailable is reported in place of a section whose source failed.
+const unavailable = "unavailable"
+
+// Queue is one queue shown in the snapshot.
+type Queue struct {
+	Name string
+	// Depth reports the waiting messages; nil when the backend cannot.
+	Depth queue.DepthReporter
+	// Age reports the oldest waiting message; nil when the backend cannot.
+	Age queue.AgeReporter
+	// Analyses marks the queue that carries analysis jobs. Without an Age
+	// reporter its oldest message age is that of the oldest queued analysis.
+	Analyses bool
+}
+
+// QueueFor describes client under name with whichever reporters it implements.
+func QueueFor(name string, client queue.Client) Queue {
+	q := Queue{Name: name}
+	q.Depth, _ = client.(queue.DepthReporter)
+	q.Age, _ = client.(queue.AgeReporter)
+	return q
+}
+
+// AnalysisCounter counts analyses by pipeline stage.
+type AnalysisCounter interface {
+	PipelineCounts(ctx context.Context, failedSince time.Time) (analyses.PipelineCounts, error)
+}
+
+// Snapshot is the pipeline status at one moment. A section whose source
+// failed carries error "unavailable" instead of its values.
+type Snapshot struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Queues      []QueueStatus  `json:"queues"`
+	InFlight    InFlightStatus `json:"inFlight"`
+	Errors      ErrorStatus    `json:"errors"`
+	LLM         any            `json:"llm"`
+	Storage     StorageStatus  `json:"storage"`
+}
+
+// QueueStatus is the backlog of one queue. Fields the backend cannot report
+// are omitted.
+type QueueStatus struct {
+	Name                    string `json:"name"`
+	Depth                   *int   `json:"depth,omitempty"`
+	OldestMessageAgeSeconds *int64 `json:"oldestMessageAgeSeconds,omitempty"`
+	Error                   string `json:"error,omitempty"`
+}
+
+// InFlightStatus counts unfinished analyses by stage.
+type InFlightStatus struct {
+	Stages map[string]int `json:"stages,omitempty"`
+	Total  int            `json:"total"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// ErrorStatus counts analyses that failed within the window by error code.
+type ErrorStatus struct {
+	WindowMinutes int            `json:"windowMinutes"`
+	Codes         map[string]int `json:"codes,omitempty"`
+	Total         int            `json:"total"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// StorageStatus holds storage latency percentiles by operation.
+type StorageStatus struct {
+	WindowMinutes int                       `json:"windowMinutes"`
+	Operations    map[string]LatencySummary `json:"operations"`
+}
+
+// Service builds snapshots from whichever sources are configured.
+type Service struct {
+	Queues   []Queue
+	Analyses AnalysisCounter
+	// LLM is nil when the provider cannot be probed.
+	LLM     *llmhealth.Monitor
+	Storage *StorageLatency
+	// ErrorWindow defaults to DefaultErrorWindow.
+	ErrorWindow time.Duration
+	Now         func() time.Time
+}
+
+// Snapshot reports the pipeline status now. It reads cached health and
+// latency rather than probing, so it is cheap enough to poll.
+func (s *Service) Snapshot(ctx context.Context) Snapshot {
+	now := s.now()
+	window := s.ErrorWindow
+	if window <= 0 {
+		window = DefaultErrorWindow
+	}
+	snap := Snapshot{
+		GeneratedAt: now,
+		Queues:      []QueueStatus{},
+		Errors:      ErrorStatus{WindowMinutes: int(window / time.Minute)},
+		LLM:         s.llmStatus(),
+		Storage:     s.storageStatus(),
+	}
+
+	var oldestQueued *time.Time
+	if s.Analyses != nil {
+		counts, err := s.Analyses.PipelineCounts(ctx, now.Add(-window))
+		if err != nil {
+			s.logFailure(ctx, "analyses", err)
+			snap.InFlight.Error = unavailable
+			snap.Errors.Error = unavailable
+		} else {
+			snap.InFlight.Stages = counts.InFlight
+			for _, n := range counts.InFlight {
+				snap.InFlight.Total += n
+			}
+			snap.Errors.Codes = counts.ErrorCodes
+			for _, n := range counts.ErrorCodes {
+				snap.Errors.Total += n
+			}
+			oldestQueued = counts.OldestQueuedAt
+		}
+	} else {
+		snap.InFlight.Error = unavailable
+		snap.Errors.Error = unavailable
+	}
+
+	for _, q := range s.Queues {
+		snap.Queues = append(snap.Queues, s.queueStatus(ctx, q, now, oldestQueued))
+	}
+	return snap
+}
+
+func (s *Service) queueStatus(ctx context.Context, q Queue, now time.Time, oldestQueued *time.Time) QueueStatus {
+	status := QueueStatus{Name: q.Name}
+	if q.Depth != nil {
+		depth, err := q.Depth.ApproximateDepth(ctx)
+		if err != nil {
+			s.logFailure(ctx, "queue."+q.Name, err)
+			status.Error = unavailable
+		} else {
+			status.Depth = &depth
+		}
+	}
+	switch {
+	case q.Age != nil:
+		age, ok, err := q.Age.OldestMessageAge(ctx)
+		if err != nil {
+			s.logFailure(ctx, "queue."+q.Name, err)
+			status.Error = unavailable
+		} else if ok {
+			status.OldestMessageAgeSeconds = seconds(age)
+		}
+	case q.Analyses && oldestQueued != nil:
+		status.OldestMessageAgeSeconds = seconds(now.Sub(*oldestQueued))
+	}
+	return status
+}
+
+func (s *Service) llmStatus() any {
+	if s.LLM == nil {
+		return map[string]any{"probed": false}
+	}
+	status, ok := s.LLM.Last()
+	if !ok {
+		return map[string]any{"provider": s.LLM.Provider, "probed": false}
+	}
+	return status
+}
+
+func (s *Service) storageStatus() StorageStatus {
+	status := StorageStatus{WindowMinutes: int(DefaultLatencyWindow / time.Minute), Operations: map[string]LatencySummary{}}
+	if s.Storage == nil {
+		return status
+	}
+	if s.Storage.Window > 0 {
+		status.WindowMinutes = int(s.Storage.Window / time.Minute)
+	}
+	status.Operations = s.Storage.Summary()
+	return status
+}
+
+func (s *Service) logFailure(ctx context.Context, section string, err error) {
+	telemetry.ErrorContext(ctx, "pipeline_status.section_failed", map[string]any{
+		"section": section,
+		"error":   err.Error(),
+	})
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now().UTC()
+}
+
+func seconds(d time.Duration) *int64 {
+	v := int64(max(d, 0) / time.Second)
+	return &v
+}
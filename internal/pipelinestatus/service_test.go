@@ -0,0 +1,144 @@
+package pipelinestatus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/queue"
+)
+
+type fixedDepth struct {
+	depth int
+	err   error
+}
+
+func (d fixedDepth) ApproximateDepth(ctx context.Context) (int, error) {
+	return d.depth, d.err
+}
+
+type fixedAge time.Duration
+
+func (a fixedAge) OldestMessageAge(ctx context.Context) (time.Duration, bool, error) {
+	return time.Duration(a), true, nil
+}
+
+type fixedCounts struct {
+	counts analyses.PipelineCounts
+	err    error
+	since  time.Time
+}
+
+func (f *fixedCounts) PipelineCounts(ctx context.Context, failedSince time.Time) (analyses.PipelineCounts, error) {
+	f.since = failedSince
+	return f.counts, f.err
+}
+
+func TestSnapshotCombinesSources(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	oldest := now.Add(-90 * time.Second)
+	counter := &fixedCounts{counts: analyses.PipelineCounts{
+		InFlight:       map[string]int{"queued": 3, "processing": 2, "budget_deferred": 0},
+		OldestQueuedAt: &oldest,
+		ErrorCodes:     map[string]int{"LLM_TIMEOUT": 4, "EXTRACT_FAILED": 1},
+	}}
+	latency := &StorageLatency{Now: func() time.Time { return now }}
+	for _, ms := range []int{10, 20, 30, 40, 500} {
+		latency.Observe("open", time.Duration(ms)*time.Millisecond, nil)
+	}
+	latency.Observe("save", 80*time.Millisecond, errors.New("throttled"))
+
+	svc := &Service{
+		Queues: []Queue{
+			{Name: "analysis", Depth: fixedDepth{depth: 7}, Analyses: true},
+			{Name: "extract", Depth: fixedDepth{err: errors.New("sqs down")}, Age: fixedAge(5 * time.Second)},
+		},
+		Analyses: counter,
+		Storage:  latency,
+		Now:      func() time.Time { return now },
+	}
+	snap := svc.Snapshot(context.Background())
+
+	if !counter.since.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("errors counted since %v", counter.since)
+	}
+	analysisQueue := snap.Queues[0]
+	if *analysisQueue.Depth != 7 || *analysisQueue.OldestMessageAgeSeconds != 90 {
+		t.Fatalf("analysis queue = %+v", analysisQueue)
+	}
+	extractQueue := snap.Queues[1]
+	if extractQueue.Depth != nil || extractQueue.Error != unavailable || *extractQueue.OldestMessageAgeSeconds != 5 {
+		t.Fatalf("extract queue = %+v", extractQueue)
+	}
+	if snap.InFlight.Total != 5 || snap.Errors.Total != 5 || snap.Errors.Codes["LLM_TIMEOUT"] != 4 || snap.Errors.WindowMinutes != 60 {
+		t.Fatalf("in flight %+v errors %+v", snap.InFlight, snap.Errors)
+	}
+	open := snap.Storage.Operations["open"]
+	if open.Count != 5 || open.P50Ms != 30 || open.P90Ms != 500 || open.P99Ms != 500 {
+		t.Fatalf("open latency = %+v", open)
+	}
+	if save := snap.Storage.Operations["save"]; save.Count != 1 || save.Errors != 1 {
+		t.Fatalf("save latency = %+v", save)
+	}
+	if llm, ok := snap.LLM.(map[string]any); !ok || llm["probed"] != false {
+		t.Fatalf("llm = %v", snap.LLM)
+	}
+}
+
+func TestSnapshotMarksFailedSectionsUnavailable(t *testing.T) {
+	svc := &Service{
+		Queues:   []Queue{QueueFor("analysis", queue.NewMemoryQueue(1))},
+		Analyses: &fixedCounts{err: errors.New("db down")},
+	}
+	snap := svc.Snapshot(context.Background())
+	if snap.InFlight.Error != unavailable || snap.Errors.Error != unavailable {
+		t.Fatalf("expected analyses sections unavailable, got %+v %+v", snap.InFlight, snap.Errors)
+	}
+	if q := snap.Queues[0]; q.Depth == nil || *q.Depth != 0 || q.Error != "" {
+		t.Fatalf("memory queue = %+v", q)
+	}
+}
+
+func TestStorageLatencyDropsOldSamples(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	latency := &StorageLatency{MaxSamples: 2, Now: func() time.Time { return now }}
+	latency.Observe("open", time.Second, nil)
+	now = now.Add(DefaultLatencyWindow + time.Minute)
+	if got := latency.Summary(); len(got) != 0 {
+		t.Fatalf("expected samples outside the window dropped, got %+v", got)
+	}
+	for _, ms := range []int{1, 2, 3} {
+		latency.Observe("open", time.Duration(ms)*time.Millisecond, nil)
+	}
+	if got := latency.Summary()["open"]; got.Count != 2 || got.P50Ms != 2 {
+		t.Fatalf("expected the two latest samples, got %+v", got)
+	}
+}
+
+func TestHandlerServesSnapshot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(&Service{}).RegisterRoutes(router.Group("/admin"))
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/pipeline", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status %d body %s", resp.Code, resp.Body)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, key := range []string{"generatedAt", "queues", "inFlight", "errors", "llm", "storage"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("missing %s in %s", key, resp.Body)
+		}
+	}
+}
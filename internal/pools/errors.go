@@ -0,0 +1,18 @@
+package pools
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the pool was not found in the organization.
+	ErrNotFound = errors.New("not found")
+
+	// ErrDocumentNotFound indicates the document is not in the pool or, when
+	// adding, is not one of the caller's documents.
+	ErrDocumentNotFound = errors.New("document not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrConflict indicates the organization already has a pool with that name.
+	ErrConflict = errors.New("pool already exists")
+)
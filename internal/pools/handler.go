@@ -0,0 +1,174 @@
+package pools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/internal/usage"
+)
+
+// Handler exposes candidate pool endpoints.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches pool routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/orgs/:orgId/pools", h.createPool)
+	rg.GET("/orgs/:orgId/pools", h.listPools)
+	rg.GET("/orgs/:orgId/pools/:poolId/documents", h.listDocuments)
+	rg.PUT("/orgs/:orgId/pools/:poolId/documents/:documentId", h.putDocument)
+	rg.DELETE("/orgs/:orgId/pools/:poolId/documents/:documentId", h.removeDocument)
+	rg.GET("/orgs/:orgId/pools/:poolId/analyses", h.listAnalyses)
+}
+
+type createPoolRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *Handler) createPool(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	var req createPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	pool, err := h.Svc.CreatePool(c.Request.Context(), c.Param("orgId"), middleware.UserIDFromContext(c), req.Name)
+	if err != nil {
+		writeError(c, err, "failed to create pool")
+		return
+	}
+	respond.JSON(c, http.StatusCreated, pool)
+}
+
+func (h *Handler) listPools(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	pools, err := h.Svc.ListPools(c.Request.Context(), c.Param("orgId"), middleware.UserIDFromContext(c))
+	if err != nil {
+		writeError(c, err, "failed to list pools")
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"items": pools})
+}
+
+type putDocumentRequest struct {
+	Tags Tags `json:"tags"`
+}
+
+func (h *Handler) putDocument(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	var req putDocumentRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+			return
+		}
+	}
+	entry, err := h.Svc.AddDocument(
+		c.Request.Context(),
+		c.Param("orgId"),
+		c.Param("poolId"),
+		middleware.UserIDFromContext(c),
+		c.Param("documentId"),
+		req.Tags,
+	)
+	if err != nil {
+		writeError(c, err, "failed to add document to pool")
+		return
+	}
+	respond.JSON(c, http.StatusOK, entry)
+}
+
+func (h *Handler) removeDocument(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	err := h.Svc.RemoveDocument(
+		c.Request.Context(),
+		c.Param("orgId"),
+		c.Param("poolId"),
+		middleware.UserIDFromContext(c),
+		c.Param("documentId"),
+	)
+	if err != nil {
+		writeError(c, err, "failed to remove document from pool")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) listDocuments(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	entries, err := h.Svc.ListDocuments(c.Request.Context(), c.Param("orgId"), c.Param("poolId"), middleware.UserIDFromContext(c), filterFromQuery(c))
+	if err != nil {
+		writeError(c, err, "failed to list pool documents")
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"items": entries})
+}
+
+func (h *Handler) listAnalyses(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	items, err := h.Svc.ListAnalyses(c.Request.Context(), c.Param("orgId"), c.Param("poolId"), middleware.UserIDFromContext(c), filterFromQuery(c))
+	if err != nil {
+		writeError(c, err, "failed to list pool analyses")
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"items": items})
+}
+
+func filterFromQuery(c *gin.Context) Filter {
+	return Filter{
+		Role:     c.Query("role"),
+		Location: c.Query("location"),
+		Stage:    c.Query("stage"),
+	}
+}
+
+func requireLogin(c *gin.Context) bool {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to use candidate pools", nil)
+		return false
+	}
+	return true
+}
+
+// writeError hides whether an organization exists from non-members.
+func writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, usage.ErrOrgNotFound), errors.Is(err, usage.ErrNotOrgMember):
+		respond.Error(c, http.StatusNotFound, "not_found", "organization not found", nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "pool not found", nil)
+	case errors.Is(err, ErrDocumentNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "document not found", nil)
+	case errors.Is(err, ErrConflict):
+		respond.Error(c, http.StatusConflict, "conflict", "a pool with that name already exists", nil)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		respond.Error(c, http.StatusRequestTimeout, "timeout", "request canceled", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", message, nil)
+	}
+}
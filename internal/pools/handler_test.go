@@ -0,0 +1,223 @@
+package pools_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+func newTestApp(t *testing.T) *bootstrap.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	return app
+}
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func doRequest(router http.Handler, method, path, authorization string, body any) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func uploadText(t *testing.T, router http.Handler, authorization, content string) string {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, err := writer.CreateFormFile("file", "resume.txt")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fileWriter.Write([]byte(content)); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", authorization)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode upload response: %v", err)
+	}
+	return created.DocumentID
+}
+
+type entryItem struct {
+	DocumentID string `json:"documentId"`
+	Tags       struct {
+		Role     string `json:"role"`
+		Location string `json:"location"`
+		Stage    string `json:"stage"`
+	} `json:"tags"`
+	Analysis *struct {
+		AnalysisID string   `json:"analysisId"`
+		Status     string   `json:"status"`
+		FinalScore *float64 `json:"finalScore"`
+	} `json:"analysis"`
+}
+
+func listItems(t *testing.T, router http.Handler, path, authorization string) []entryItem {
+	t.Helper()
+	resp := doRequest(router, http.MethodGet, path, authorization, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected 200, got %d: %s", path, resp.Code, resp.Body.String())
+	}
+	var body struct {
+		Items []entryItem `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	return body.Items
+}
+
+func TestCandidatePoolsAreSharedWithinOrg(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+	if _, err := app.UsageService.SetOrgQuota(ctx, "acme", "Team", 100, 0); err != nil {
+		t.Fatalf("set org quota: %v", err)
+	}
+	for _, userID := range []string{"recruiter-a", "recruiter-b", "recruiter-c"} {
+		if _, err := app.UsageService.AddOrgMember(ctx, "acme", userID); err != nil {
+			t.Fatalf("add member: %v", err)
+		}
+	}
+	alice := bearer(t, "recruiter-a")
+	bob := bearer(t, "recruiter-b")
+	carol := bearer(t, "recruiter-c")
+	outsider := bearer(t, "outsider")
+
+	resp := doRequest(app.Router, http.MethodPost, "/api/v1/orgs/acme/pools", alice, map[string]any{"name": "Backend Q3"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create pool: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var pool struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &pool); err != nil {
+		t.Fatalf("decode pool: %v", err)
+	}
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/orgs/acme/pools", bob, map[string]any{"name": "backend q3"}); resp.Code != http.StatusConflict {
+		t.Fatalf("duplicate pool name: expected 409, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodGet, "/api/v1/orgs/acme/pools", outsider, nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("outsider list pools: expected 404, got %d", resp.Code)
+	}
+
+	base := "/api/v1/orgs/acme/pools/" + pool.ID
+	docA := uploadText(t, app.Router, alice, "Go engineer resume")
+	docB := uploadText(t, app.Router, bob, "Data engineer resume")
+
+	if resp := doRequest(app.Router, http.MethodPut, base+"/documents/"+docA, bob, map[string]any{"tags": map[string]any{"stage": "screen"}}); resp.Code != http.StatusNotFound {
+		t.Fatalf("pooling another member's document: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodPut, base+"/documents/"+docA, alice, map[string]any{"tags": map[string]any{"role": "Backend", "location": "Berlin", "stage": "screen"}}); resp.Code != http.StatusOK {
+		t.Fatalf("add document: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := doRequest(app.Router, http.MethodPut, base+"/documents/"+docB, bob, map[string]any{"tags": map[string]any{"role": "Data", "stage": "onsite"}}); resp.Code != http.StatusOK {
+		t.Fatalf("add document: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	if items := listItems(t, app.Router, base+"/documents", bob); len(items) != 2 {
+		t.Fatalf("expected 2 pooled documents, got %d", len(items))
+	}
+	items := listItems(t, app.Router, base+"/documents?role=backend", bob)
+	if len(items) != 1 || items[0].DocumentID != docA || items[0].Tags.Location != "Berlin" {
+		t.Fatalf("expected role filter to match alice's document case-insensitively, got %+v", items)
+	}
+
+	// Moving a candidate to the next stage replaces its tags.
+	if resp := doRequest(app.Router, http.MethodPut, base+"/documents/"+docA, alice, map[string]any{"tags": map[string]any{"role": "Backend", "stage": "onsite"}}); resp.Code != http.StatusOK {
+		t.Fatalf("retag document: expected 200, got %d", resp.Code)
+	}
+	if items := listItems(t, app.Router, base+"/documents?stage=onsite", bob); len(items) != 2 {
+		t.Fatalf("expected both documents at onsite, got %d", len(items))
+	}
+
+	now := time.Now().UTC()
+	if err := app.AnalysesRepo.Create(ctx, analyses.Analysis{
+		ID:          "11111111-1111-1111-1111-111111111111",
+		DocumentID:  docA,
+		UserID:      "recruiter-a",
+		Mode:        analyses.ModeJobMatch,
+		Status:      analyses.StatusCompleted,
+		Result:      map[string]any{"finalScore": 82.0},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CompletedAt: &now,
+	}); err != nil {
+		t.Fatalf("create analysis: %v", err)
+	}
+	items = listItems(t, app.Router, base+"/analyses?role=Backend", carol)
+	if len(items) != 1 || items[0].Analysis == nil || items[0].Analysis.FinalScore == nil || *items[0].Analysis.FinalScore != 82 {
+		t.Fatalf("expected alice's analysis score to be shared with the org, got %+v", items)
+	}
+	items = listItems(t, app.Router, base+"/analyses?role=Data", carol)
+	if len(items) != 1 || items[0].Analysis != nil {
+		t.Fatalf("expected unanalyzed document without analysis, got %+v", items)
+	}
+
+	if resp := doRequest(app.Router, http.MethodDelete, base+"/documents/"+docB, alice, nil); resp.Code != http.StatusNoContent {
+		t.Fatalf("remove document: expected 204, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodDelete, base+"/documents/"+docB, alice, nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("remove missing document: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodGet, base+"/documents", outsider, nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("outsider list documents: expected 404, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,70 @@
+package pools
+
+import (
+	"strings"
+	"time"
+)
+
+// Pool is a named collection of candidate documents shared by an organization.
+type Pool struct {
+	ID            string    `json:"id"`
+	OrgID         string    `json:"orgId"`
+	Name          string    `json:"name"`
+	CreatedBy     string    `json:"createdBy"`
+	CreatedAt     time.Time `json:"createdAt"`
+	DocumentCount int       `json:"documentCount"`
+}
+
+// Tags describe a candidate within a pool.
+type Tags struct {
+	Role     string `json:"role,omitempty"`
+	Location string `json:"location,omitempty"`
+	// Stage is the screening stage, e.g. "screen" or "onsite".
+	Stage string `json:"stage,omitempty"`
+}
+
+// Entry is a document in a pool. OwnerUserID is the member who uploaded it.
+type Entry struct {
+	PoolID      string    `json:"poolId"`
+	DocumentID  string    `json:"documentId"`
+	OwnerUserID string    `json:"ownerUserId"`
+	FileName    string    `json:"fileName"`
+	AddedBy     string    `json:"addedBy"`
+	Tags        Tags      `json:"tags"`
+	AddedAt     time.Time `json:"addedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Filter narrows pool entries by tag. Empty fields match everything; matching
+// ignores case.
+type Filter struct {
+	Role     string
+	Location string
+	Stage    string
+}
+
+func (f Filter) matches(e Entry) bool {
+	return tagMatches(f.Role, e.Tags.Role) &&
+		tagMatches(f.Location, e.Tags.Location) &&
+		tagMatches(f.Stage, e.Tags.Stage)
+}
+
+func tagMatches(want, got string) bool {
+	return want == "" || strings.EqualFold(want, got)
+}
+
+// AnalysisSummary is the latest analysis of a pooled document.
+type AnalysisSummary struct {
+	AnalysisID  string     `json:"analysisId"`
+	Status      string     `json:"status"`
+	Mode        string     `json:"mode"`
+	FinalScore  *float64   `json:"finalScore"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// EntryAnalysis pairs a pool entry with its latest analysis, if any.
+type EntryAnalysis struct {
+	Entry
+	Analysis *AnalysisSummary `json:"analysis"`
+}
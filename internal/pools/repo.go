@@ -0,0 +1,20 @@
+package pools
+
+import "context"
+
+// Repo persists candidate pools and their entries.
+type Repo interface {
+	// CreatePool returns ErrConflict if the organization has a pool with the same
+	// name, ignoring case.
+	CreatePool(ctx context.Context, pool Pool) error
+	// ListPools returns the organization's pools by name, with document counts.
+	ListPools(ctx context.Context, orgID string) ([]Pool, error)
+	GetPool(ctx context.Context, orgID, poolID string) (Pool, error)
+	// UpsertEntry adds a document to a pool, or replaces its tags if it is already
+	// there. AddedBy and AddedAt of an existing entry are kept.
+	UpsertEntry(ctx context.Context, entry Entry) (Entry, error)
+	// RemoveEntry returns ErrDocumentNotFound if the document is not in the pool.
+	RemoveEntry(ctx context.Context, poolID, documentID string) error
+	// ListEntries returns matching entries, most recently updated first.
+	ListEntries(ctx context.Context, poolID string, filter Filter) ([]Entry, error)
+}
@@ -0,0 +1,129 @@
+package pools
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryRepo stores pools in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu      sync.RWMutex
+	pools   map[string]Pool
+	entries map[string]map[string]Entry
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{
+		pools:   make(map[string]Pool),
+		entries: make(map[string]map[string]Entry),
+	}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// CreatePool stores a new pool.
+func (r *MemoryRepo) CreatePool(ctx context.Context, pool Pool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.pools {
+		if existing.OrgID == pool.OrgID && strings.EqualFold(existing.Name, pool.Name) {
+			return ErrConflict
+		}
+	}
+	r.pools[pool.ID] = pool
+	return nil
+}
+
+// ListPools returns the organization's pools by name, with document counts.
+func (r *MemoryRepo) ListPools(ctx context.Context, orgID string) ([]Pool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Pool, 0)
+	for _, pool := range r.pools {
+		if pool.OrgID != orgID {
+			continue
+		}
+		pool.DocumentCount = len(r.entries[pool.ID])
+		out = append(out, pool)
+	}
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	return out, nil
+}
+
+// GetPool returns one of the organization's pools.
+func (r *MemoryRepo) GetPool(ctx context.Context, orgID, poolID string) (Pool, error) {
+	if err := ctx.Err(); err != nil {
+		return Pool{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pool, ok := r.pools[poolID]
+	if !ok || pool.OrgID != orgID {
+		return Pool{}, ErrNotFound
+	}
+	pool.DocumentCount = len(r.entries[poolID])
+	return pool, nil
+}
+
+// UpsertEntry adds a document to a pool or replaces its tags.
+func (r *MemoryRepo) UpsertEntry(ctx context.Context, entry Entry) (Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return Entry{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pools[entry.PoolID]; !ok {
+		return Entry{}, ErrNotFound
+	}
+	entries, ok := r.entries[entry.PoolID]
+	if !ok {
+		entries = make(map[string]Entry)
+		r.entries[entry.PoolID] = entries
+	}
+	if existing, ok := entries[entry.DocumentID]; ok {
+		entry.AddedBy = existing.AddedBy
+		entry.AddedAt = existing.AddedAt
+	}
+	entries[entry.DocumentID] = entry
+	return entry, nil
+}
+
+// RemoveEntry removes a document from a pool.
+func (r *MemoryRepo) RemoveEntry(ctx context.Context, poolID, documentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[poolID][documentID]; !ok {
+		return ErrDocumentNotFound
+	}
+	delete(r.entries[poolID], documentID)
+	return nil
+}
+
+// ListEntries returns matching entries, most recently updated first.
+func (r *MemoryRepo) ListEntries(ctx context.Context, poolID string, filter Filter) ([]Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Entry, 0)
+	for _, entry := range r.entries[poolID] {
+		if filter.matches(entry) {
+			out = append(out, entry)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
@@ -0,0 +1,164 @@
+package pools
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// CreatePool inserts a new pool.
+func (r *PGRepo) CreatePool(ctx context.Context, pool Pool) error {
+	const query = `
+INSERT INTO candidate_pools (id, org_id, name, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT DO NOTHING`
+	res, err := r.DB.ExecContext(ctx, query, pool.ID, pool.OrgID, pool.Name, pool.CreatedBy, pool.CreatedAt)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+const poolColumns = `
+SELECT p.id::text, p.org_id, p.name, p.created_by, p.created_at,
+       (SELECT COUNT(*) FROM candidate_pool_documents d WHERE d.pool_id = p.id)
+FROM candidate_pools p`
+
+// ListPools returns the organization's pools by name, with document counts.
+func (r *PGRepo) ListPools(ctx context.Context, orgID string) ([]Pool, error) {
+	rows, err := r.DB.QueryContext(ctx, poolColumns+`
+WHERE p.org_id = $1
+ORDER BY lower(p.name)`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Pool, 0)
+	for rows.Next() {
+		var pool Pool
+		if err := rows.Scan(&pool.ID, &pool.OrgID, &pool.Name, &pool.CreatedBy, &pool.CreatedAt, &pool.DocumentCount); err != nil {
+			return nil, err
+		}
+		out = append(out, pool)
+	}
+	return out, rows.Err()
+}
+
+// GetPool returns one of the organization's pools.
+func (r *PGRepo) GetPool(ctx context.Context, orgID, poolID string) (Pool, error) {
+	var pool Pool
+	err := r.DB.QueryRowContext(ctx, poolColumns+`
+WHERE p.org_id = $1 AND p.id::text = $2`, orgID, poolID).
+		Scan(&pool.ID, &pool.OrgID, &pool.Name, &pool.CreatedBy, &pool.CreatedAt, &pool.DocumentCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Pool{}, ErrNotFound
+		}
+		return Pool{}, err
+	}
+	return pool, nil
+}
+
+// UpsertEntry adds a document to a pool or replaces its tags.
+func (r *PGRepo) UpsertEntry(ctx context.Context, entry Entry) (Entry, error) {
+	const query = `
+INSERT INTO candidate_pool_documents (
+    pool_id, document_id, owner_user_id, added_by, role, location, stage, added_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (pool_id, document_id) DO UPDATE SET
+    role = EXCLUDED.role,
+    location = EXCLUDED.location,
+    stage = EXCLUDED.stage,
+    updated_at = EXCLUDED.updated_at
+RETURNING added_by, added_at`
+	err := r.DB.QueryRowContext(ctx, query,
+		entry.PoolID,
+		entry.DocumentID,
+		entry.OwnerUserID,
+		entry.AddedBy,
+		entry.Tags.Role,
+		entry.Tags.Location,
+		entry.Tags.Stage,
+		entry.AddedAt,
+		entry.UpdatedAt,
+	).Scan(&entry.AddedBy, &entry.AddedAt)
+	if err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// RemoveEntry removes a document from a pool.
+func (r *PGRepo) RemoveEntry(ctx context.Context, poolID, documentID string) error {
+	const query = `
+DELETE FROM candidate_pool_documents
+WHERE pool_id::text = $1 AND document_id::text = $2`
+	res, err := r.DB.ExecContext(ctx, query, poolID, documentID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrDocumentNotFound
+	}
+	return nil
+}
+
+// ListEntries returns matching entries, most recently updated first.
+func (r *PGRepo) ListEntries(ctx context.Context, poolID string, filter Filter) ([]Entry, error) {
+	const query = `
+SELECT e.pool_id::text, e.document_id::text, e.owner_user_id, d.file_name, e.added_by,
+       e.role, e.location, e.stage, e.added_at, e.updated_at
+FROM candidate_pool_documents e
+JOIN documents d ON d.id = e.document_id
+WHERE e.pool_id::text = $1
+  AND d.deleted_at IS NULL
+  AND ($2 = '' OR lower(e.role) = lower($2))
+  AND ($3 = '' OR lower(e.location) = lower($3))
+  AND ($4 = '' OR lower(e.stage) = lower($4))
+ORDER BY e.updated_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, poolID, filter.Role, filter.Location, filter.Stage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Entry, 0)
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(
+			&e.PoolID,
+			&e.DocumentID,
+			&e.OwnerUserID,
+			&e.FileName,
+			&e.AddedBy,
+			&e.Tags.Role,
+			&e.Tags.Location,
+			&e.Tags.Stage,
+			&e.AddedAt,
+			&e.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
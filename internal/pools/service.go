@@ -0,0 +1,190 @@
+package pools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+)
+
+const (
+	maxPoolNameLength = 100
+	maxTagLength      = 64
+)
+
+// MemberChecker reports whether a user belongs to an organization. It returns an
+// error, such as usage.ErrNotOrgMember, when they do not.
+type MemberChecker interface {
+	CheckOrgMember(ctx context.Context, orgID, userID string) error
+}
+
+// AnalysisSource looks up the latest analysis of pooled documents. The analyses
+// repos implement it.
+type AnalysisSource interface {
+	LatestByDocuments(ctx context.Context, documentIDs []string) (map[string]analyses.Analysis, error)
+}
+
+// Service manages an organization's candidate pools. Every call is made on behalf
+// of a member; pools are visible to, and editable by, all members of the
+// organization.
+type Service struct {
+	Repo    Repo
+	Members MemberChecker
+	Docs    documents.DocumentsRepo
+	// Analyses backs ListAnalyses; when nil, entries are listed without analyses.
+	Analyses AnalysisSource
+	Now      func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, members MemberChecker, docs documents.DocumentsRepo, analysisSource AnalysisSource) *Service {
+	return &Service{Repo: repo, Members: members, Docs: docs, Analyses: analysisSource}
+}
+
+// CreatePool creates a named pool in the organization.
+func (s *Service) CreatePool(ctx context.Context, orgID, userID, name string) (Pool, error) {
+	if err := s.Members.CheckOrgMember(ctx, orgID, userID); err != nil {
+		return Pool{}, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxPoolNameLength {
+		return Pool{}, fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidInput, maxPoolNameLength)
+	}
+	pool := Pool{
+		ID:        uuid.NewString(),
+		OrgID:     orgID,
+		Name:      name,
+		CreatedBy: userID,
+		CreatedAt: s.now(),
+	}
+	if err := s.Repo.CreatePool(ctx, pool); err != nil {
+		return Pool{}, err
+	}
+	return pool, nil
+}
+
+// ListPools returns the organization's pools.
+func (s *Service) ListPools(ctx context.Context, orgID, userID string) ([]Pool, error) {
+	if err := s.Members.CheckOrgMember(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.Repo.ListPools(ctx, orgID)
+}
+
+// AddDocument puts one of the user's documents in a pool with the given tags. If
+// the document is already pooled, its tags are replaced.
+func (s *Service) AddDocument(ctx context.Context, orgID, poolID, userID, documentID string, tags Tags) (Entry, error) {
+	if _, err := s.pool(ctx, orgID, poolID, userID); err != nil {
+		return Entry{}, err
+	}
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return Entry{}, err
+	}
+	doc, err := s.Docs.GetByID(ctx, userID, documentID)
+	if err != nil {
+		if errors.Is(err, documents.ErrNotFound) {
+			return Entry{}, ErrDocumentNotFound
+		}
+		return Entry{}, err
+	}
+	now := s.now()
+	return s.Repo.UpsertEntry(ctx, Entry{
+		PoolID:      poolID,
+		DocumentID:  doc.ID,
+		OwnerUserID: doc.UserID,
+		FileName:    doc.FileName,
+		AddedBy:     userID,
+		Tags:        tags,
+		AddedAt:     now,
+		UpdatedAt:   now,
+	})
+}
+
+// RemoveDocument takes a document out of a pool. Any member may remove any entry.
+func (s *Service) RemoveDocument(ctx context.Context, orgID, poolID, userID, documentID string) error {
+	if _, err := s.pool(ctx, orgID, poolID, userID); err != nil {
+		return err
+	}
+	return s.Repo.RemoveEntry(ctx, poolID, documentID)
+}
+
+// ListDocuments returns the pool's entries that match filter.
+func (s *Service) ListDocuments(ctx context.Context, orgID, poolID, userID string, filter Filter) ([]Entry, error) {
+	if _, err := s.pool(ctx, orgID, poolID, userID); err != nil {
+		return nil, err
+	}
+	return s.Repo.ListEntries(ctx, poolID, filter)
+}
+
+// ListAnalyses returns the pool's matching entries with the latest analysis of
+// each document. Only a summary of each analysis is shared with the organization.
+func (s *Service) ListAnalyses(ctx context.Context, orgID, poolID, userID string, filter Filter) ([]EntryAnalysis, error) {
+	entries, err := s.ListDocuments(ctx, orgID, poolID, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	latest := map[string]analyses.Analysis{}
+	if s.Analyses != nil && len(entries) > 0 {
+		ids := make([]string, 0, len(entries))
+		for _, e := range entries {
+			ids = append(ids, e.DocumentID)
+		}
+		if latest, err = s.Analyses.LatestByDocuments(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]EntryAnalysis, 0, len(entries))
+	for _, e := range entries {
+		item := EntryAnalysis{Entry: e}
+		if a, ok := latest[e.DocumentID]; ok {
+			item.Analysis = summarize(a)
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (s *Service) pool(ctx context.Context, orgID, poolID, userID string) (Pool, error) {
+	if err := s.Members.CheckOrgMember(ctx, orgID, userID); err != nil {
+		return Pool{}, err
+	}
+	return s.Repo.GetPool(ctx, orgID, poolID)
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func normalizeTags(tags Tags) (Tags, error) {
+	for _, v := range []*string{&tags.Role, &tags.Location, &tags.Stage} {
+		*v = strings.TrimSpace(*v)
+		if len(*v) > maxTagLength {
+			return Tags{}, fmt.Errorf("%w: tags must be at most %d characters", ErrInvalidInput, maxTagLength)
+		}
+	}
+	return tags, nil
+}
+
+func summarize(a analyses.Analysis) *AnalysisSummary {
+	summary := &AnalysisSummary{
+		AnalysisID:  a.ID,
+		Status:      a.Status,
+		Mode:        string(a.Mode),
+		CreatedAt:   a.CreatedAt,
+		CompletedAt: a.CompletedAt,
+	}
+	if score, ok := analyses.FinalScore(a); ok {
+		summary.FinalScore = &score
+	}
+	return summary
+}
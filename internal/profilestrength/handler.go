@@ -0,0 +1,34 @@
+package profilestrength
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the profile strength summary.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches the profile strength route to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/users/me/profile-strength", h.get)
+}
+
+func (h *Handler) get(c *gin.Context) {
+	summary, err := h.Svc.Summarize(c.Request.Context(), middleware.UserIDFromContext(c))
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to build profile strength", nil)
+		return
+	}
+	respond.CachedJSON(c, respond.CacheRevalidate, summary)
+}
@@ -0,0 +1,61 @@
+package profilestrength
+
+import "time"
+
+// Trend directions of a score against the previous analysis of the same
+// document.
+const (
+	TrendUp   = "up"
+	TrendDown = "down"
+	TrendFlat = "flat"
+	// TrendNew marks a document analyzed only once.
+	TrendNew = "new"
+)
+
+// Summary is the dashboard payload for one user.
+type Summary struct {
+	// Best is the highest latest score across the user's documents; nil
+	// before any analysis has completed.
+	Best            *DocumentScore   `json:"best"`
+	TopIssues       []OpenIssue      `json:"topIssues"`
+	KeywordCoverage *KeywordCoverage `json:"keywordCoverage"`
+	// Documents lists the latest score of each analyzed document, most
+	// recently analyzed first.
+	Documents []DocumentScore `json:"documents"`
+}
+
+// DocumentScore is the latest score of one document and how it moved.
+type DocumentScore struct {
+	DocumentID    string    `json:"documentId"`
+	AnalysisID    string    `json:"analysisId"`
+	Mode          string    `json:"mode"`
+	Score         float64   `json:"score"`
+	PreviousScore *float64  `json:"previousScore,omitempty"`
+	Trend         string    `json:"trend"`
+	AnalyzedAt    time.Time `json:"analyzedAt"`
+}
+
+// OpenIssue is an issue from the latest analysis of a document that the user
+// has not annotated.
+type OpenIssue struct {
+	DocumentID string `json:"documentId"`
+	AnalysisID string `json:"analysisId"`
+	Severity   string `json:"severity"`
+	Section    string `json:"section"`
+	Problem    string `json:"problem"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// KeywordCoverage compares the user's resume with the role they are
+// targeting, taken from their latest analysis against a job description.
+type KeywordCoverage struct {
+	DocumentID string    `json:"documentId"`
+	AnalysisID string    `json:"analysisId"`
+	AnalyzedAt time.Time `json:"analyzedAt"`
+	// SkillsScore and RoleFit are the analysis' score breakdown entries.
+	SkillsScore *float64 `json:"skillsScore,omitempty"`
+	RoleFit     *float64 `json:"roleFit,omitempty"`
+	// MissingKeywords are the job description keywords the resume lacks,
+	// without those the user dismissed.
+	MissingKeywords []string `json:"missingKeywords"`
+}
@@ -0,0 +1,270 @@
+// Package profilestrength aggregates a user's latest analyses into the
+// summary their dashboard home screen shows, so clients do not have to fetch
+// and combine every analysis themselves.
+package profilestrength
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+)
+
+const (
+	// scanAnalyses bounds how many of the newest analyses are read.
+	scanAnalyses = 200
+	// scanDocuments bounds how many current documents are considered.
+	scanDocuments = 100
+	// topIssues is how many open issues the summary lists.
+	topIssues = 3
+	// flatBand is the score change, in points, still reported as flat.
+	flatBand = 1.0
+)
+
+// AnalysisSource lists a user's analyses and merges their annotations.
+type AnalysisSource interface {
+	List(ctx context.Context, userID string, limit, offset int) ([]analyses.Analysis, error)
+	AnnotatedResult(ctx context.Context, analysis analyses.Analysis) map[string]any
+}
+
+// DocumentLister lists a user's current documents, without archived or
+// deleted ones.
+type DocumentLister interface {
+	List(ctx context.Context, userID string, limit, offset int) ([]documents.Document, error)
+}
+
+// Service builds profile strength summaries.
+type Service struct {
+	Analyses  AnalysisSource
+	Documents DocumentLister
+}
+
+// NewService constructs a Service.
+func NewService(analysisSource AnalysisSource, documentLister DocumentLister) *Service {
+	return &Service{Analyses: analysisSource, Documents: documentLister}
+}
+
+// scored is a completed analysis with its headline score.
+type scored struct {
+	analysis analyses.Analysis
+	score    float64
+}
+
+// Summarize builds the summary for userID from the completed analyses of the
+// documents they still have.
+func (s *Service) Summarize(ctx context.Context, userID string) (Summary, error) {
+	if userID == "" {
+		return Summary{}, errors.New("user id required")
+	}
+	docs, err := s.Documents.List(ctx, userID, scanDocuments, 0)
+	if err != nil {
+		return Summary{}, err
+	}
+	current := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		current[doc.ID] = true
+	}
+	items, err := s.Analyses.List(ctx, userID, scanAnalyses, 0)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// items are newest first, so the first two per document are its latest
+	// and previous scores.
+	var order []string
+	history := map[string][]scored{}
+	var target *analyses.Analysis
+	for i := range items {
+		a := items[i]
+		if a.Status != analyses.StatusCompleted || !current[a.DocumentID] {
+			continue
+		}
+		if target == nil && strings.TrimSpace(a.JobDescription) != "" {
+			target = &items[i]
+		}
+		score, ok := analyses.FinalScore(a)
+		if !ok || len(history[a.DocumentID]) >= 2 {
+			continue
+		}
+		if history[a.DocumentID] == nil {
+			order = append(order, a.DocumentID)
+		}
+		history[a.DocumentID] = append(history[a.DocumentID], scored{analysis: a, score: score})
+	}
+
+	summary := Summary{TopIssues: []OpenIssue{}, Documents: make([]DocumentScore, 0, len(order))}
+	var issues []rankedIssue
+	for _, documentID := range order {
+		entry := documentScore(history[documentID])
+		summary.Documents = append(summary.Documents, entry)
+		if summary.Best == nil || entry.Score > summary.Best.Score {
+			best := entry
+			summary.Best = &best
+		}
+		latest := history[documentID][0].analysis
+		issues = append(issues, openIssues(latest, s.Analyses.AnnotatedResult(ctx, latest))...)
+	}
+	summary.TopIssues = pickTopIssues(issues, topIssues)
+	if target != nil {
+		summary.KeywordCoverage = keywordCoverage(*target, s.Analyses.AnnotatedResult(ctx, *target))
+	}
+	return summary, nil
+}
+
+func documentScore(history []scored) DocumentScore {
+	latest := history[0]
+	entry := DocumentScore{
+		DocumentID: latest.analysis.DocumentID,
+		AnalysisID: latest.analysis.ID,
+		Mode:       string(latest.analysis.Mode),
+		Score:      latest.score,
+		Trend:      TrendNew,
+		AnalyzedAt: analyzedAt(latest.analysis),
+	}
+	if len(history) > 1 {
+		previous := history[1].score
+		entry.PreviousScore = &previous
+		entry.Trend = trend(latest.score, previous)
+	}
+	return entry
+}
+
+func trend(latest, previous float64) string {
+	switch delta := latest - previous; {
+	case math.Abs(delta) <= flatBand:
+		return TrendFlat
+	case delta > 0:
+		return TrendUp
+	default:
+		return TrendDown
+	}
+}
+
+func analyzedAt(a analyses.Analysis) time.Time {
+	if a.CompletedAt != nil {
+		return *a.CompletedAt
+	}
+	return a.CreatedAt
+}
+
+type rankedIssue struct {
+	OpenIssue
+	priority int
+}
+
+var severityRank = map[string]int{
+	string(analyses.IssueSeverityCritical): 0,
+	string(analyses.IssueSeverityHigh):     1,
+	string(analyses.IssueSeverityMedium):   2,
+	string(analyses.IssueSeverityLow):      3,
+}
+
+// openIssues lists the issues of result the user has not annotated.
+func openIssues(a analyses.Analysis, result map[string]any) []rankedIssue {
+	list, _ := result["issues"].([]any)
+	out := make([]rankedIssue, 0, len(list))
+	for _, raw := range list {
+		item, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, annotated := item["userAnnotation"]; annotated {
+			continue
+		}
+		problem, _ := item["problem"].(string)
+		if strings.TrimSpace(problem) == "" {
+			continue
+		}
+		issue := rankedIssue{OpenIssue: OpenIssue{DocumentID: a.DocumentID, AnalysisID: a.ID, Problem: problem}}
+		issue.Severity, _ = item["severity"].(string)
+		issue.Section, _ = item["section"].(string)
+		issue.Suggestion, _ = item["suggestion"].(string)
+		if priority, ok := item["priority"].(float64); ok {
+			issue.priority = int(priority)
+		}
+		out = append(out, issue)
+	}
+	return out
+}
+
+// pickTopIssues orders issues by severity, then priority, and keeps the
+// first n, listing a problem repeated across documents once.
+func pickTopIssues(issues []rankedIssue, n int) []OpenIssue {
+	sort.SliceStable(issues, func(i, j int) bool {
+		si, sj := rankOf(issues[i].Severity), rankOf(issues[j].Severity)
+		if si != sj {
+			return si < sj
+		}
+		return priorityOf(issues[i].priority) < priorityOf(issues[j].priority)
+	})
+	out := make([]OpenIssue, 0, n)
+	seen := map[string]bool{}
+	for _, issue := range issues {
+		key := strings.ToLower(strings.TrimSpace(issue.Problem))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, issue.OpenIssue)
+		if len(out) == n {
+			break
+		}
+	}
+	return out
+}
+
+func rankOf(severity string) int {
+	if rank, ok := severityRank[strings.ToLower(severity)]; ok {
+		return rank
+	}
+	return len(severityRank)
+}
+
+// priorityOf sorts a missing priority after every set one.
+func priorityOf(priority int) int {
+	if priority <= 0 {
+		return math.MaxInt
+	}
+	return priority
+}
+
+func keywordCoverage(a analyses.Analysis, result map[string]any) *KeywordCoverage {
+	coverage := &KeywordCoverage{
+		DocumentID:      a.DocumentID,
+		AnalysisID:      a.ID,
+		AnalyzedAt:      analyzedAt(a),
+		MissingKeywords: []string{},
+	}
+	ats, _ := result["ats"].(map[string]any)
+	if breakdown, ok := ats["scoreBreakdown"].(map[string]any); ok {
+		coverage.SkillsScore = floatField(breakdown, "skills")
+		coverage.RoleFit = floatField(breakdown, "roleFit")
+	}
+	var fromJD any
+	switch missing := ats["missingKeywords"].(type) {
+	case map[string]any:
+		fromJD = missing["fromJobDescription"]
+	case []any:
+		fromJD = missing
+	}
+	list, _ := fromJD.([]any)
+	for _, item := range list {
+		if keyword, ok := item.(string); ok && strings.TrimSpace(keyword) != "" {
+			coverage.MissingKeywords = append(coverage.MissingKeywords, keyword)
+		}
+	}
+	return coverage
+}
+
+func floatField(m map[string]any, key string) *float64 {
+	value, ok := m[key].(float64)
+	if !ok {
+		return nil
+	}
+	return &value
+}
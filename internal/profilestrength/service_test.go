@@ -0,0 +1,116 @@
+package profilestrength
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+)
+
+type fakeAnalyses struct {
+	items []analyses.Analysis
+}
+
+func (f fakeAnalyses) List(_ context.Context, _ string, _, _ int) ([]analyses.Analysis, error) {
+	return f.items, nil
+}
+
+func (f fakeAnalyses) AnnotatedResult(_ context.Context, a analyses.Analysis) map[string]any {
+	return a.Result
+}
+
+type fakeDocuments []documents.Document
+
+func (f fakeDocuments) List(_ context.Context, _ string, _, _ int) ([]documents.Document, error) {
+	return f, nil
+}
+
+func completed(id, documentID string, score float64, at time.Time, result map[string]any) analyses.Analysis {
+	if result == nil {
+		result = map[string]any{}
+	}
+	result["finalScore"] = score
+	return analyses.Analysis{
+		ID:          id,
+		DocumentID:  documentID,
+		Status:      analyses.StatusCompleted,
+		Mode:        analyses.ModeATS,
+		Result:      result,
+		CompletedAt: &at,
+	}
+}
+
+func issue(severity, problem string, priority float64) map[string]any {
+	return map[string]any{"severity": severity, "section": "experience", "problem": problem, "priority": priority}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	annotated := issue("critical", "Dismissed problem", 1)
+	annotated["userAnnotation"] = map[string]any{"kind": "not_applicable"}
+
+	jobMatch := completed("a4", "doc-b", 64, now.Add(-time.Hour), map[string]any{
+		"issues": []any{issue("high", "No metrics in bullets", 2), issue("low", "Long summary", 1)},
+		"ats": map[string]any{
+			"scoreBreakdown":  map[string]any{"skills": 55.0, "roleFit": 60.0},
+			"missingKeywords": map[string]any{"fromJobDescription": []any{"Kubernetes", "Terraform"}, "industryCommon": []any{"CI/CD"}},
+		},
+	})
+	jobMatch.Mode = analyses.ModeJobMatch
+	jobMatch.JobDescription = "Platform engineer"
+
+	items := []analyses.Analysis{
+		completed("a5", "doc-a", 81, now, map[string]any{
+			"issues": []any{annotated, issue("medium", "Missing dates", 3), issue("high", "no metrics in bullets", 1)},
+		}),
+		jobMatch,
+		{ID: "a3", DocumentID: "doc-a", Status: analyses.StatusProcessing},
+		completed("a2", "doc-a", 72, now.Add(-48*time.Hour), nil),
+		completed("a1", "doc-b", 64.5, now.Add(-72*time.Hour), nil),
+		// doc-gone was deleted, so its analyses are ignored.
+		completed("a0", "doc-gone", 99, now.Add(-96*time.Hour), nil),
+	}
+	svc := NewService(fakeAnalyses{items: items}, fakeDocuments{{ID: "doc-a"}, {ID: "doc-b"}})
+
+	summary, err := svc.Summarize(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if summary.Best == nil || summary.Best.AnalysisID != "a5" || summary.Best.Trend != TrendUp || *summary.Best.PreviousScore != 72 {
+		t.Fatalf("unexpected best score %+v", summary.Best)
+	}
+	if len(summary.Documents) != 2 || summary.Documents[1].DocumentID != "doc-b" || summary.Documents[1].Trend != TrendFlat {
+		t.Fatalf("unexpected document scores %+v", summary.Documents)
+	}
+
+	var problems []string
+	for _, issue := range summary.TopIssues {
+		problems = append(problems, issue.Problem)
+	}
+	// The annotated issue is skipped and the repeated problem is listed once,
+	// from the higher-priority copy.
+	if len(problems) != 3 || problems[0] != "no metrics in bullets" || problems[1] != "Missing dates" || problems[2] != "Long summary" {
+		t.Fatalf("unexpected top issues %q", problems)
+	}
+
+	coverage := summary.KeywordCoverage
+	if coverage == nil || coverage.AnalysisID != "a4" || *coverage.RoleFit != 60 || *coverage.SkillsScore != 55 {
+		t.Fatalf("unexpected keyword coverage %+v", coverage)
+	}
+	if len(coverage.MissingKeywords) != 2 || coverage.MissingKeywords[0] != "Kubernetes" {
+		t.Fatalf("expected only job description keywords, got %q", coverage.MissingKeywords)
+	}
+}
+
+func TestSummarizeWithoutAnalyses(t *testing.T) {
+	svc := NewService(fakeAnalyses{}, fakeDocuments{})
+	summary, err := svc.Summarize(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if summary.Best != nil || summary.KeywordCoverage != nil || len(summary.TopIssues) != 0 || summary.Documents == nil {
+		t.Fatalf("unexpected empty summary %+v", summary)
+	}
+}
@@ -1,8 +1,23 @@
 package queue
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Client sends messages to a queue backend.
 type Client interface {
 	Send(ctx context.Context, msg Message) error
 }
+
+// DepthReporter is implemented by clients that can report how many messages are
+// waiting to be received.
+type DepthReporter interface {
+	ApproximateDepth(ctx context.Context) (int, error)
+}
+
+// AgeReporter is implemented by clients that can report how long the oldest
+// waiting message has been queued. ok is false when the queue is empty.
+type AgeReporter interface {
+	OldestMessageAge(ctx context.Context) (age time.Duration, ok bool, err error)
+}
@@ -0,0 +1,141 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultDedupWindow is how long a claimed delivery suppresses re-deliveries
+// of the same message. It should stay below the queue's visibility timeout so
+// a message left behind by a crashed worker is still processed.
+const DefaultDedupWindow = 2 * time.Minute
+
+// DedupKey identifies one message for one analysis. SQS keeps the message ID
+// across re-deliveries.
+type DedupKey struct {
+	MessageID  string
+	AnalysisID string
+}
+
+// Deduper remembers recently handled messages so duplicate deliveries can be
+// skipped before any work is done.
+type Deduper interface {
+	// Claim records key and reports whether it was unclaimed, or its claim is
+	// older than the window.
+	Claim(ctx context.Context, key DedupKey) (bool, error)
+	// Release forgets key, so a redelivery after a failure is processed.
+	Release(ctx context.Context, key DedupKey) error
+}
+
+// MemoryDedup is a Deduper local to one process.
+type MemoryDedup struct {
+	Window time.Duration
+	Now    func() time.Time
+
+	mu      sync.Mutex
+	claimed map[DedupKey]time.Time
+}
+
+var _ Deduper = (*MemoryDedup)(nil)
+
+// NewMemoryDedup constructs a MemoryDedup with the given window.
+func NewMemoryDedup(window time.Duration) *MemoryDedup {
+	return &MemoryDedup{Window: window, claimed: make(map[DedupKey]time.Time)}
+}
+
+func (d *MemoryDedup) Claim(ctx context.Context, key DedupKey) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	now := dedupNow(d.Now)
+	cutoff := now.Add(-dedupWindow(d.Window))
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if at, ok := d.claimed[key]; ok && at.After(cutoff) {
+		return false, nil
+	}
+	for k, at := range d.claimed {
+		if !at.After(cutoff) {
+			delete(d.claimed, k)
+		}
+	}
+	d.claimed[key] = now
+	return true, nil
+}
+
+func (d *MemoryDedup) Release(ctx context.Context, key DedupKey) error {
+	d.mu.Lock()
+	delete(d.claimed, key)
+	d.mu.Unlock()
+	return nil
+}
+
+// PGDedup is a Deduper shared by every worker through Postgres.
+type PGDedup struct {
+	DB     *sql.DB
+	Window time.Duration
+	Now    func() time.Time
+
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+var _ Deduper = (*PGDedup)(nil)
+
+// Claim inserts key, or takes over a claim older than the window. Expired
+// claims are purged at most once per window.
+func (d *PGDedup) Claim(ctx context.Context, key DedupKey) (bool, error) {
+	now := dedupNow(d.Now)
+	cutoff := now.Add(-dedupWindow(d.Window))
+	const query = `
+INSERT INTO queue_message_dedup (message_id, analysis_id, claimed_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (message_id, analysis_id) DO UPDATE
+SET claimed_at = EXCLUDED.claimed_at
+WHERE queue_message_dedup.claimed_at <= $4
+RETURNING message_id`
+	var id string
+	err := d.DB.QueryRowContext(ctx, query, key.MessageID, key.AnalysisID, now, cutoff).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	d.purge(ctx, now, cutoff)
+	return true, nil
+}
+
+func (d *PGDedup) Release(ctx context.Context, key DedupKey) error {
+	_, err := d.DB.ExecContext(ctx, `DELETE FROM queue_message_dedup WHERE message_id = $1 AND analysis_id = $2`, key.MessageID, key.AnalysisID)
+	return err
+}
+
+func (d *PGDedup) purge(ctx context.Context, now, cutoff time.Time) {
+	d.mu.Lock()
+	if now.Sub(d.lastPurged) < dedupWindow(d.Window) {
+		d.mu.Unlock()
+		return
+	}
+	d.lastPurged = now
+	d.mu.Unlock()
+	// Best effort: leftover rows only cost space until the next purge.
+	_, _ = d.DB.ExecContext(ctx, `DELETE FROM queue_message_dedup WHERE claimed_at <= $1`, cutoff)
+}
+
+func dedupWindow(window time.Duration) time.Duration {
+	if window <= 0 {
+		return DefaultDedupWindow
+	}
+	return window
+}
+
+func dedupNow(now func() time.Time) time.Time {
+	if now != nil {
+		return now()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,36 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryDedupSuppressesWithinTheWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dedup := NewMemoryDedup(time.Minute)
+	dedup.Now = func() time.Time { return now }
+	key := DedupKey{MessageID: "m1", AnalysisID: "a1"}
+
+	if ok, _ := dedup.Claim(ctx, key); !ok {
+		t.Fatal("first delivery should be claimed")
+	}
+	if ok, _ := dedup.Claim(ctx, key); ok {
+		t.Fatal("a re-delivery within the window should be a duplicate")
+	}
+	if ok, _ := dedup.Claim(ctx, DedupKey{MessageID: "m1", AnalysisID: "a2"}); !ok {
+		t.Fatal("another analysis under the same message ID is not a duplicate")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := dedup.Claim(ctx, key); !ok {
+		t.Fatal("a re-delivery after the window should be claimed again")
+	}
+	if err := dedup.Release(ctx, key); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if ok, _ := dedup.Claim(ctx, key); !ok {
+		t.Fatal("a released delivery should be claimable")
+	}
+}
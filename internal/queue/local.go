@@ -0,0 +1,222 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLocalVisibility is how long a received PGQueue message stays hidden
+// from other consumers before it is delivered again.
+const DefaultLocalVisibility = 20 * time.Minute
+
+// defaultLocalPollInterval is how often PGQueue looks for messages while the
+// queue is empty.
+const defaultLocalPollInterval = time.Second
+
+// Delivery is one received message of a LocalQueue.
+type Delivery struct {
+	ID   string
+	Body string
+	// Attempts counts deliveries of the message, this one included.
+	Attempts int
+}
+
+// LocalQueue is a queue the process both sends to and consumes, used by the
+// standalone profile in place of SQS.
+type LocalQueue interface {
+	Client
+	DepthReporter
+	// Receive blocks until a message is available or ctx is done.
+	Receive(ctx context.Context) (Delivery, error)
+	// Ack removes a handled message.
+	Ack(ctx context.Context, d Delivery) error
+	// Retry makes a message available again after delay.
+	Retry(ctx context.Context, d Delivery, delay time.Duration) error
+}
+
+var (
+	_ LocalQueue  = (*MemoryQueue)(nil)
+	_ LocalQueue  = (*PGQueue)(nil)
+	_ AgeReporter = (*PGQueue)(nil)
+)
+
+// MemoryQueue is a LocalQueue held in process memory. Messages are lost on
+// restart, so it suits a standalone deployment without a database.
+type MemoryQueue struct {
+	bodies chan Delivery
+
+	mu     sync.Mutex
+	nextID int
+}
+
+// NewMemoryQueue constructs a MemoryQueue that buffers up to size messages;
+// Send blocks while it is full.
+func NewMemoryQueue(size int) *MemoryQueue {
+	if size <= 0 {
+		size = 1024
+	}
+	return &MemoryQueue{bodies: make(chan Delivery, size)}
+}
+
+func (q *MemoryQueue) Send(ctx context.Context, msg Message) error {
+	payload, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	q.nextID++
+	id := strconv.Itoa(q.nextID)
+	q.mu.Unlock()
+	return q.put(ctx, Delivery{ID: id, Body: string(payload)})
+}
+
+func (q *MemoryQueue) ApproximateDepth(ctx context.Context) (int, error) {
+	return len(q.bodies), ctx.Err()
+}
+
+func (q *MemoryQueue) Receive(ctx context.Context) (Delivery, error) {
+	select {
+	case d := <-q.bodies:
+		d.Attempts++
+		return d, nil
+	case <-ctx.Done():
+		return Delivery{}, ctx.Err()
+	}
+}
+
+// Ack is a no-op: a received message has already left the queue.
+func (q *MemoryQueue) Ack(ctx context.Context, d Delivery) error {
+	return nil
+}
+
+func (q *MemoryQueue) Retry(ctx context.Context, d Delivery, delay time.Duration) error {
+	time.AfterFunc(delay, func() {
+		// Detached from ctx, which usually ends with the failed attempt.
+		_ = q.put(context.Background(), d)
+	})
+	return nil
+}
+
+func (q *MemoryQueue) put(ctx context.Context, d Delivery) error {
+	select {
+	case q.bodies <- d:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PGQueue is a LocalQueue stored in Postgres, so queued messages survive a
+// restart and several processes can consume one queue. Like SQS, a received
+// message is hidden for Visibility and delivered again unless it is acked.
+type PGQueue struct {
+	DB *sql.DB
+	// Name separates queues that share the table.
+	Name string
+	// Visibility defaults to DefaultLocalVisibility.
+	Visibility time.Duration
+	// PollInterval is how often an empty queue is checked; it defaults to a second.
+	PollInterval time.Duration
+}
+
+func (q *PGQueue) Send(ctx context.Context, msg Message) error {
+	payload, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	const query = `
+INSERT INTO local_queue_messages (queue, body, available_at, created_at)
+VALUES ($1, $2, $3, $3)`
+	_, err = q.DB.ExecContext(ctx, query, q.Name, string(payload), time.Now().UTC())
+	return err
+}
+
+func (q *PGQueue) ApproximateDepth(ctx context.Context) (int, error) {
+	const query = `SELECT COUNT(*) FROM local_queue_messages WHERE queue = $1 AND available_at <= $2`
+	var depth int
+	err := q.DB.QueryRowContext(ctx, query, q.Name, time.Now().UTC()).Scan(&depth)
+	return depth, err
+}
+
+// OldestMessageAge reports the age of the oldest message in the queue,
+// including messages received but not yet acked.
+func (q *PGQueue) OldestMessageAge(ctx context.Context) (time.Duration, bool, error) {
+	const query = `SELECT MIN(created_at) FROM local_queue_messages WHERE queue = $1`
+	var oldest sql.NullTime
+	if err := q.DB.QueryRowContext(ctx, query, q.Name).Scan(&oldest); err != nil {
+		return 0, false, err
+	}
+	if !oldest.Valid {
+		return 0, false, nil
+	}
+	return time.Since(oldest.Time), true, nil
+}
+
+func (q *PGQueue) Receive(ctx context.Context) (Delivery, error) {
+	poll := q.PollInterval
+	if poll <= 0 {
+		poll = defaultLocalPollInterval
+	}
+	for {
+		d, ok, err := q.receiveOne(ctx)
+		if err != nil || ok {
+			return d, err
+		}
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Delivery{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// receiveOne takes the oldest available message and hides it for the
+// visibility timeout. SKIP LOCKED lets concurrent consumers take different
+// messages.
+func (q *PGQueue) receiveOne(ctx context.Context) (Delivery, bool, error) {
+	visibility := q.Visibility
+	if visibility <= 0 {
+		visibility = DefaultLocalVisibility
+	}
+	now := time.Now().UTC()
+	const query = `
+UPDATE local_queue_messages
+SET available_at = $3, attempts = attempts + 1
+WHERE id = (
+	SELECT id FROM local_queue_messages
+	WHERE queue = $1 AND available_at <= $2
+	ORDER BY available_at, id
+	FOR UPDATE SKIP LOCKED
+	LIMIT 1
+)
+RETURNING id, body, attempts`
+	var (
+		d  Delivery
+		id int64
+	)
+	err := q.DB.QueryRowContext(ctx, query, q.Name, now, now.Add(visibility)).Scan(&id, &d.Body, &d.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Delivery{}, false, nil
+	}
+	if err != nil {
+		return Delivery{}, false, err
+	}
+	d.ID = strconv.FormatInt(id, 10)
+	return d, true, nil
+}
+
+func (q *PGQueue) Ack(ctx context.Context, d Delivery) error {
+	_, err := q.DB.ExecContext(ctx, `DELETE FROM local_queue_messages WHERE id = $1`, d.ID)
+	return err
+}
+
+func (q *PGQueue) Retry(ctx context.Context, d Delivery, delay time.Duration) error {
+	_, err := q.DB.ExecContext(ctx, `UPDATE local_queue_messages SET available_at = $2 WHERE id = $1`, d.ID, time.Now().UTC().Add(delay))
+	return err
+}
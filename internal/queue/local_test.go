@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMemoryQueueRedeliversRetriedMessages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	q := NewMemoryQueue(4)
+	if err := q.Send(ctx, Message{AnalysisID: "a1"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if depth, _ := q.ApproximateDepth(ctx); depth != 1 {
+		t.Fatalf("depth = %d, want 1", depth)
+	}
+
+	d, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	msg, err := DecodeMessage([]byte(d.Body))
+	if err != nil || msg.AnalysisID != "a1" || d.Attempts != 1 {
+		t.Fatalf("delivery = %+v (%v)", d, err)
+	}
+	if err := q.Retry(ctx, d, 10*time.Millisecond); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	again, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatalf("receive retried: %v", err)
+	}
+	if again.ID != d.ID || again.Attempts != 2 {
+		t.Fatalf("retried delivery = %+v", again)
+	}
+
+	short, stop := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer stop()
+	if _, err := q.Receive(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("empty queue: expected deadline, got %v", err)
+	}
+}
+
+func TestPGQueueReceivesAndAcks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	q := &PGQueue{DB: db, Name: "analysis", PollInterval: time.Millisecond}
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO local_queue_messages").
+		WithArgs("analysis", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("UPDATE local_queue_messages").
+		WithArgs("analysis", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("UPDATE local_queue_messages").
+		WithArgs("analysis", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body", "attempts"}).AddRow(int64(7), `{"analysisId":"a1"}`, 1))
+	mock.ExpectExec("DELETE FROM local_queue_messages").
+		WithArgs("7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := q.Send(ctx, Message{AnalysisID: "a1"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	d, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if d.ID != "7" || d.Attempts != 1 {
+		t.Fatalf("delivery = %+v", d)
+	}
+	if err := q.Ack(ctx, d); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestPGQueueReportsOldestMessageAge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	q := &PGQueue{DB: db, Name: "analysis"}
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT MIN\\(created_at\\) FROM local_queue_messages").
+		WithArgs("analysis").
+		WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(time.Now().Add(-time.Minute)))
+	mock.ExpectQuery("SELECT MIN\\(created_at\\) FROM local_queue_messages").
+		WithArgs("analysis").
+		WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(nil))
+
+	age, ok, err := q.OldestMessageAge(ctx)
+	if err != nil || !ok || age < time.Minute || age > 2*time.Minute {
+		t.Fatalf("age = %v ok=%v err=%v", age, ok, err)
+	}
+	if _, ok, err := q.OldestMessageAge(ctx); err != nil || ok {
+		t.Fatalf("empty queue: ok=%v err=%v", ok, err)
+	}
+}
@@ -2,12 +2,27 @@ package queue
 
 import "encoding/json"
 
+// Stages route a message to the worker that handles it.
+const (
+	StageAnalysis = "analysis"
+	StageExtract  = "extract"
+)
+
+// EventDocumentExtracted marks an analysis message sent once the extract stage finished.
+const EventDocumentExtracted = "document.extracted"
+
 // Message is the payload sent to downstream queue consumers.
 type Message struct {
 	AnalysisID string `json:"analysisId"`
 	RequestID  string `json:"requestId"`
 	EnqueuedAt string `json:"enqueuedAt"`
 	Version    int    `json:"version"`
+	// SupportingDocumentIDs lists documents that supplement the primary resume, if any.
+	SupportingDocumentIDs []string `json:"supportingDocumentIds,omitempty"`
+	// Stage selects the handling worker; empty means StageAnalysis.
+	Stage string `json:"stage,omitempty"`
+	// Event names what produced the message, if anything other than a new analysis.
+	Event string `json:"event,omitempty"`
 }
 
 // EncodeMessage returns the JSON representation of a message.
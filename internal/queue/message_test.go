@@ -11,6 +11,7 @@ func TestMessageRoundTrip(t *testing.T) {
 		RequestID:  "request-456",
 		EnqueuedAt: "2026-01-30T22:00:00Z",
 		Version:    1,
+		Stage:      StageExtract,
 	}
 
 	payload, err := EncodeMessage(msg)
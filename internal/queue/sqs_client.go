@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
 const sqsRegion = "us-east-1"
@@ -19,13 +21,17 @@ type SQSClient struct {
 	queueURL string
 }
 
-// NewSQSClient constructs an SQS-backed queue client.
+// NewSQSClient constructs an SQS-backed queue client for RA_SQS_QUEUE_URL.
 func NewSQSClient(ctx context.Context) (*SQSClient, error) {
 	queueURL := strings.TrimSpace(os.Getenv("RA_SQS_QUEUE_URL"))
 	if queueURL == "" {
 		return nil, fmt.Errorf("RA_SQS_QUEUE_URL is required")
 	}
+	return NewSQSClientForURL(ctx, queueURL)
+}
 
+// NewSQSClientForURL constructs an SQS-backed queue client for queueURL.
+func NewSQSClientForURL(ctx context.Context, queueURL string) (*SQSClient, error) {
 	_ = strings.TrimSpace(os.Getenv("AWS_REGION"))
 
 	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(sqsRegion))
@@ -56,4 +62,23 @@ func (s *SQSClient) Send(ctx context.Context, msg Message) error {
 	return nil
 }
 
-var _ Client = (*SQSClient)(nil)
+// ApproximateDepth returns the approximate number of visible messages in the queue.
+func (s *SQSClient) ApproximateDepth(ctx context.Context) (int, error) {
+	out, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(s.queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("sqs get queue attributes: %w", err)
+	}
+	depth, err := strconv.Atoi(out.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)])
+	if err != nil {
+		return 0, fmt.Errorf("sqs queue depth: %w", err)
+	}
+	return depth, nil
+}
+
+var (
+	_ Client        = (*SQSClient)(nil)
+	_ DepthReporter = (*SQSClient)(nil)
+)
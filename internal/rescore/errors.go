@@ -0,0 +1,14 @@
+package rescore
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the batch does not exist, or no batch is queued.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrInvalidTransition indicates the batch cannot move to the requested state.
+	ErrInvalidTransition = errors.New("invalid batch transition")
+)
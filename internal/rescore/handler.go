@@ -0,0 +1,131 @@
+package rescore
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// ActionCreateBatch is the audit action for a queued re-scoring batch.
+const ActionCreateBatch = "rescore.batch_created"
+
+// Handler serves the re-scoring admin API.
+type Handler struct {
+	Svc *Service
+	// Audit records queued batches; nil skips auditing.
+	Audit *audit.Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches re-scoring routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/rescore-batches", h.create)
+	rg.GET("/rescore-batches", h.list)
+	rg.GET("/rescore-batches/:id", h.get)
+	rg.GET("/rescore-batches/:id/results", h.results)
+	rg.POST("/rescore-batches/:id/cancel", h.cancel)
+}
+
+func (h *Handler) create(c *gin.Context) {
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	ctx := c.Request.Context()
+	actor := middleware.UserIDFromContext(c)
+	b, err := h.Svc.Create(ctx, req, TriggerAdmin, actor)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if h.Audit != nil {
+		_ = h.Audit.Record(ctx, audit.Entry{
+			Action:      ActionCreateBatch,
+			ActorUserID: actor,
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Details: map[string]any{
+				"batchId":       b.ID,
+				"promptVersion": b.PromptVersion,
+				"model":         b.Model,
+				"maxAnalyses":   b.MaxAnalyses,
+			},
+		})
+	}
+	respond.JSON(c, http.StatusAccepted, b)
+}
+
+func (h *Handler) list(c *gin.Context) {
+	items, err := h.Svc.List(c.Request.Context(), 20)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if items == nil {
+		items = []Batch{}
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"batches": items})
+}
+
+func (h *Handler) get(c *gin.Context) {
+	b, summary, err := h.Svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"batch": b, "summary": summary})
+}
+
+func (h *Handler) results(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "limit must be a non-negative integer", nil)
+			return
+		}
+		limit = parsed
+	}
+	items, err := h.Svc.Results(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if items == nil {
+		items = []Result{}
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"results": items})
+}
+
+func (h *Handler) cancel(c *gin.Context) {
+	b, err := h.Svc.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, b)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "re-scoring batch not found", nil)
+	case errors.Is(err, ErrInvalidTransition):
+		respond.Error(c, http.StatusConflict, "invalid_transition", "the batch has already finished", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to process re-scoring batch", nil)
+	}
+}
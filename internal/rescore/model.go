@@ -0,0 +1,86 @@
+package rescore
+
+import "time"
+
+// Status is the lifecycle state of a batch.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Finished reports whether the batch will not run again.
+func (s Status) Finished() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCanceled
+}
+
+// What started a batch.
+const (
+	TriggerAdmin    = "admin"
+	TriggerSchedule = "schedule"
+)
+
+// Result statuses.
+const (
+	ResultCompleted = "completed"
+	ResultFailed    = "failed"
+)
+
+// Batch re-scores a cohort of completed analyses with a prompt version and
+// model, for evaluation. Its results go to the shadow table only.
+type Batch struct {
+	ID            string `json:"id"`
+	PromptVersion string `json:"promptVersion"`
+	// Model is the LLM model to score with; empty uses the configured one.
+	Model string `json:"model,omitempty"`
+	// Mode limits the cohort to one analysis mode; empty takes all modes.
+	Mode string `json:"mode,omitempty"`
+	// Since and MaxAnalyses select the cohort: the most recently completed
+	// analyses since then, up to MaxAnalyses.
+	Since       time.Time `json:"since"`
+	MaxAnalyses int       `json:"maxAnalyses"`
+	// RequestsPerMinute throttles the batch to respect provider limits.
+	RequestsPerMinute int    `json:"requestsPerMinute"`
+	Trigger           string `json:"trigger"`
+	CreatedBy         string `json:"createdBy,omitempty"`
+	Status            Status `json:"status"`
+	// Total is the cohort size, set when the batch starts. Processed counts
+	// analyses scored or failed so far.
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Failed      int        `json:"failed"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Result is the shadow outcome of re-scoring one analysis.
+type Result struct {
+	BatchID       string         `json:"batchId"`
+	AnalysisID    string         `json:"analysisId"`
+	PromptVersion string         `json:"promptVersion"`
+	Model         string         `json:"model,omitempty"`
+	Status        string         `json:"status"`
+	OriginalScore *float64       `json:"originalScore,omitempty"`
+	ShadowScore   *float64       `json:"shadowScore,omitempty"`
+	Result        map[string]any `json:"result,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	DurationMs    int64          `json:"durationMs"`
+	CreatedAt     time.Time      `json:"createdAt"`
+}
+
+// Summary compares shadow scores with the stored ones over a batch's results.
+type Summary struct {
+	Compared int `json:"compared"`
+	// MeanDelta is the mean of shadow minus original score; MeanAbsDelta and
+	// MaxAbsDelta show how far scores moved either way.
+	MeanDelta    float64 `json:"meanDelta"`
+	MeanAbsDelta float64 `json:"meanAbsDelta"`
+	MaxAbsDelta  float64 `json:"maxAbsDelta"`
+}
@@ -0,0 +1,26 @@
+package rescore
+
+import (
+	"context"
+	"time"
+)
+
+// Repo persists batches and their shadow results.
+type Repo interface {
+	CreateBatch(ctx context.Context, b Batch) error
+	GetBatch(ctx context.Context, id string) (Batch, error)
+	// ListBatches returns batches newest first.
+	ListBatches(ctx context.Context, limit int) ([]Batch, error)
+	// ClaimQueued moves the oldest queued batch to running and returns it, or
+	// ErrNotFound when none is queued. A running batch not updated since
+	// staleBefore counts as queued: its worker stopped without handing it back.
+	// Only one worker claims a batch.
+	ClaimQueued(ctx context.Context, now, staleBefore time.Time) (Batch, error)
+	// UpdateBatch saves a batch's status and progress. It returns
+	// ErrInvalidTransition if the stored batch has already finished.
+	UpdateBatch(ctx context.Context, b Batch) error
+	// SaveResult inserts or replaces the result of one analysis.
+	SaveResult(ctx context.Context, r Result) error
+	// ListResults returns a batch's results in analysis ID order.
+	ListResults(ctx context.Context, batchID string, limit int) ([]Result, error)
+}
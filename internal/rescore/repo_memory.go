@@ -0,0 +1,140 @@
+package rescore
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepo stores batches and results in memory.
+type MemoryRepo struct {
+	mu      sync.RWMutex
+	batches []Batch
+	results map[string]map[string]Result
+}
+
+// NewMemoryRepo constructs an empty MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{results: make(map[string]map[string]Result)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// CreateBatch inserts a batch.
+func (r *MemoryRepo) CreateBatch(ctx context.Context, b Batch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, b)
+	return nil
+}
+
+// GetBatch returns a batch by ID.
+func (r *MemoryRepo) GetBatch(ctx context.Context, id string) (Batch, error) {
+	if err := ctx.Err(); err != nil {
+		return Batch{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, b := range r.batches {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return Batch{}, ErrNotFound
+}
+
+// ListBatches returns batches newest first.
+func (r *MemoryRepo) ListBatches(ctx context.Context, limit int) ([]Batch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Batch, 0, len(r.batches))
+	for i := len(r.batches) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, r.batches[i])
+	}
+	return out, nil
+}
+
+// ClaimQueued moves the oldest queued batch to running.
+func (r *MemoryRepo) ClaimQueued(ctx context.Context, now, staleBefore time.Time) (Batch, error) {
+	if err := ctx.Err(); err != nil {
+		return Batch{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, b := range r.batches {
+		stale := b.Status == StatusRunning && b.UpdatedAt.Before(staleBefore)
+		if b.Status != StatusQueued && !stale {
+			continue
+		}
+		b.Status = StatusRunning
+		b.UpdatedAt = now
+		if b.StartedAt == nil {
+			b.StartedAt = &now
+		}
+		r.batches[i] = b
+		return b, nil
+	}
+	return Batch{}, ErrNotFound
+}
+
+// UpdateBatch saves a batch's status and progress.
+func (r *MemoryRepo) UpdateBatch(ctx context.Context, b Batch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.batches {
+		if existing.ID != b.ID {
+			continue
+		}
+		if existing.Status.Finished() {
+			return ErrInvalidTransition
+		}
+		r.batches[i] = b
+		return nil
+	}
+	return ErrNotFound
+}
+
+// SaveResult inserts or replaces the result of one analysis.
+func (r *MemoryRepo) SaveResult(ctx context.Context, res Result) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.results[res.BatchID] == nil {
+		r.results[res.BatchID] = make(map[string]Result)
+	}
+	r.results[res.BatchID][res.AnalysisID] = res
+	return nil
+}
+
+// ListResults returns a batch's results in analysis ID order.
+func (r *MemoryRepo) ListResults(ctx context.Context, batchID string, limit int) ([]Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Result, 0, len(r.results[batchID]))
+	for _, res := range r.results[batchID] {
+		out = append(out, res)
+	}
+	r.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].AnalysisID < out[j].AnalysisID })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
@@ -0,0 +1,249 @@
+package rescore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const batchColumns = `id, prompt_version, model, mode, since, max_analyses, requests_per_minute, trigger, created_by,
+    status, total, processed, failed, error, created_at, updated_at, started_at, completed_at`
+
+// CreateBatch inserts a batch.
+func (r *PGRepo) CreateBatch(ctx context.Context, b Batch) error {
+	const query = `
+INSERT INTO rescore_batches (` + batchColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+	_, err := r.DB.ExecContext(ctx, query,
+		b.ID,
+		b.PromptVersion,
+		b.Model,
+		b.Mode,
+		b.Since,
+		b.MaxAnalyses,
+		b.RequestsPerMinute,
+		b.Trigger,
+		b.CreatedBy,
+		string(b.Status),
+		b.Total,
+		b.Processed,
+		b.Failed,
+		b.Error,
+		b.CreatedAt,
+		b.UpdatedAt,
+		nullTime(b.StartedAt),
+		nullTime(b.CompletedAt),
+	)
+	return err
+}
+
+// GetBatch returns a batch by ID.
+func (r *PGRepo) GetBatch(ctx context.Context, id string) (Batch, error) {
+	const query = `SELECT ` + batchColumns + ` FROM rescore_batches WHERE id = $1`
+	b, err := scanBatch(r.DB.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Batch{}, ErrNotFound
+	}
+	return b, err
+}
+
+// ListBatches returns batches newest first.
+func (r *PGRepo) ListBatches(ctx context.Context, limit int) ([]Batch, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	const query = `SELECT ` + batchColumns + ` FROM rescore_batches ORDER BY created_at DESC LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Batch
+	for rows.Next() {
+		b, err := scanBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// ClaimQueued moves the oldest queued or stale running batch to running. SKIP
+// LOCKED keeps two workers from claiming the same batch.
+func (r *PGRepo) ClaimQueued(ctx context.Context, now, staleBefore time.Time) (Batch, error) {
+	const query = `
+UPDATE rescore_batches
+SET status = $1, updated_at = $2, started_at = COALESCE(started_at, $2)
+WHERE id = (
+    SELECT id FROM rescore_batches
+    WHERE status = $3 OR (status = $1 AND updated_at < $4)
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + batchColumns
+	b, err := scanBatch(r.DB.QueryRowContext(ctx, query, string(StatusRunning), now, string(StatusQueued), staleBefore))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Batch{}, ErrNotFound
+	}
+	return b, err
+}
+
+// UpdateBatch saves a batch's status and progress unless it has finished.
+func (r *PGRepo) UpdateBatch(ctx context.Context, b Batch) error {
+	const query = `
+UPDATE rescore_batches
+SET status = $1,
+    total = $2,
+    processed = $3,
+    failed = $4,
+    error = $5,
+    updated_at = $6,
+    completed_at = $7
+WHERE id = $8 AND status NOT IN ('completed', 'failed', 'canceled')`
+	res, err := r.DB.ExecContext(ctx, query,
+		string(b.Status),
+		b.Total,
+		b.Processed,
+		b.Failed,
+		b.Error,
+		b.UpdatedAt,
+		nullTime(b.CompletedAt),
+		b.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		if _, err := r.GetBatch(ctx, b.ID); err != nil {
+			return err
+		}
+		return ErrInvalidTransition
+	}
+	return nil
+}
+
+// SaveResult inserts or replaces the result of one analysis.
+func (r *PGRepo) SaveResult(ctx context.Context, res Result) error {
+	var result any
+	if res.Result != nil {
+		encoded, err := json.Marshal(res.Result)
+		if err != nil {
+			return err
+		}
+		result = string(encoded)
+	}
+	const query = `
+INSERT INTO analysis_rescores (batch_id, analysis_id, prompt_version, model, status, original_score, shadow_score, result, error, duration_ms, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (batch_id, analysis_id) DO UPDATE SET
+    status = EXCLUDED.status,
+    original_score = EXCLUDED.original_score,
+    shadow_score = EXCLUDED.shadow_score,
+    result = EXCLUDED.result,
+    error = EXCLUDED.error,
+    duration_ms = EXCLUDED.duration_ms,
+    created_at = EXCLUDED.created_at`
+	_, err := r.DB.ExecContext(ctx, query,
+		res.BatchID,
+		res.AnalysisID,
+		res.PromptVersion,
+		res.Model,
+		res.Status,
+		nullFloat(res.OriginalScore),
+		nullFloat(res.ShadowScore),
+		result,
+		res.Error,
+		res.DurationMs,
+		res.CreatedAt,
+	)
+	return err
+}
+
+// ListResults returns a batch's results in analysis ID order.
+func (r *PGRepo) ListResults(ctx context.Context, batchID string, limit int) ([]Result, error) {
+	if limit <= 0 {
+		limit = maxAnalysesLimit
+	}
+	const query = `
+SELECT batch_id, analysis_id, prompt_version, model, status, original_score, shadow_score, result, error, duration_ms, created_at
+FROM analysis_rescores
+WHERE batch_id = $1
+ORDER BY analysis_id
+LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, batchID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Result
+	for rows.Next() {
+		var res Result
+		var original, shadow sql.NullFloat64
+		var result sql.NullString
+		if err := rows.Scan(&res.BatchID, &res.AnalysisID, &res.PromptVersion, &res.Model, &res.Status,
+			&original, &shadow, &result, &res.Error, &res.DurationMs, &res.CreatedAt); err != nil {
+			return nil, err
+		}
+		if original.Valid {
+			res.OriginalScore = &original.Float64
+		}
+		if shadow.Valid {
+			res.ShadowScore = &shadow.Float64
+		}
+		if result.Valid {
+			if err := json.Unmarshal([]byte(result.String), &res.Result); err != nil {
+				res.Result = nil
+			}
+		}
+		out = append(out, res)
+	}
+	return out, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBatch(row rowScanner) (Batch, error) {
+	var b Batch
+	var status string
+	var startedAt, completedAt sql.NullTime
+	if err := row.Scan(&b.ID, &b.PromptVersion, &b.Model, &b.Mode, &b.Since, &b.MaxAnalyses, &b.RequestsPerMinute,
+		&b.Trigger, &b.CreatedBy, &status, &b.Total, &b.Processed, &b.Failed, &b.Error,
+		&b.CreatedAt, &b.UpdatedAt, &startedAt, &completedAt); err != nil {
+		return Batch{}, err
+	}
+	b.Status = Status(status)
+	if startedAt.Valid {
+		b.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		b.CompletedAt = &completedAt.Time
+	}
+	return b, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func nullFloat(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
@@ -0,0 +1,475 @@
+package rescore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/telemetry"
+)
+
+const (
+	defaultMaxAnalyses       = 100
+	maxAnalysesLimit         = 1000
+	defaultRequestsPerMinute = 30
+	maxRequestsPerMinute     = 600
+	defaultSinceDays         = 30
+	maxSinceDays             = 365
+	maxResultErrorLen        = 500
+	// staleAfter is how long a running batch may go without progress before
+	// another worker takes it over. It is well above one throttled analysis.
+	staleAfter = 30 * time.Minute
+)
+
+// Scorer scores a completed analysis again without touching its stored result.
+// *analyses.Service implements it.
+type Scorer interface {
+	ShadowRun(ctx context.Context, analysisID string, opts analyses.ShadowOptions) (analyses.ShadowResult, error)
+}
+
+// CohortSource lists the completed analyses a batch can re-score.
+type CohortSource interface {
+	ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error)
+}
+
+// ClientFactory returns an analysis LLM client that uses model.
+type ClientFactory func(model string) (llm.Client, error)
+
+// CreateRequest describes a batch to queue.
+type CreateRequest struct {
+	PromptVersion string `json:"promptVersion"`
+	Model         string `json:"model"`
+	Mode          string `json:"mode"`
+	// SinceDays selects analyses completed in the last SinceDays days.
+	SinceDays         int `json:"sinceDays"`
+	MaxAnalyses       int `json:"maxAnalyses"`
+	RequestsPerMinute int `json:"requestsPerMinute"`
+}
+
+// Schedule queues a batch once a day, from Hour (UTC) on.
+type Schedule struct {
+	Hour    int
+	Request CreateRequest
+}
+
+// Service queues and runs re-scoring batches. Batches are created by the API and
+// run by the worker, one at a time, throttled to the batch's request rate.
+type Service struct {
+	Repo    Repo
+	Scorer  Scorer
+	Source  CohortSource
+	Clients ClientFactory
+	// Schedule, when set, makes Run queue a nightly batch.
+	Schedule *Schedule
+	Now      func() time.Time
+
+	// wait pauses between analyses; tests replace it.
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, scorer Scorer, source CohortSource) *Service {
+	return &Service{Repo: repo, Scorer: scorer, Source: source}
+}
+
+// Create validates req and queues a batch for the worker.
+func (s *Service) Create(ctx context.Context, req CreateRequest, trigger, createdBy string) (Batch, error) {
+	version := strings.TrimSpace(req.PromptVersion)
+	if version == "" {
+		return Batch{}, fmt.Errorf("%w: promptVersion is required", ErrInvalidInput)
+	}
+	if _, ok := llm.PromptTemplate(version); !ok {
+		return Batch{}, fmt.Errorf("%w: unknown prompt version %q", ErrInvalidInput, version)
+	}
+	model := strings.TrimSpace(req.Model)
+	if model != "" && s.Clients == nil {
+		return Batch{}, fmt.Errorf("%w: model overrides are not configured", ErrInvalidInput)
+	}
+	var mode string
+	if strings.TrimSpace(req.Mode) != "" {
+		parsed, err := analyses.ParseMode(req.Mode)
+		if err != nil {
+			return Batch{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		mode = string(parsed)
+	}
+	sinceDays, err := bounded("sinceDays", req.SinceDays, defaultSinceDays, maxSinceDays)
+	if err != nil {
+		return Batch{}, err
+	}
+	maxAnalyses, err := bounded("maxAnalyses", req.MaxAnalyses, defaultMaxAnalyses, maxAnalysesLimit)
+	if err != nil {
+		return Batch{}, err
+	}
+	rpm, err := bounded("requestsPerMinute", req.RequestsPerMinute, defaultRequestsPerMinute, maxRequestsPerMinute)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	now := s.now()
+	b := Batch{
+		ID:                uuid.NewString(),
+		PromptVersion:     version,
+		Model:             model,
+		Mode:              mode,
+		Since:             now.AddDate(0, 0, -sinceDays),
+		MaxAnalyses:       maxAnalyses,
+		RequestsPerMinute: rpm,
+		Trigger:           trigger,
+		CreatedBy:         createdBy,
+		Status:            StatusQueued,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.Repo.CreateBatch(ctx, b); err != nil {
+		return Batch{}, err
+	}
+	telemetry.Info("rescore.batch_queued", map[string]any{
+		"batch_id":       b.ID,
+		"prompt_version": b.PromptVersion,
+		"model":          b.Model,
+		"trigger":        b.Trigger,
+	})
+	return b, nil
+}
+
+// Get returns a batch with a comparison of its shadow and original scores.
+func (s *Service) Get(ctx context.Context, id string) (Batch, Summary, error) {
+	b, err := s.Repo.GetBatch(ctx, id)
+	if err != nil {
+		return Batch{}, Summary{}, err
+	}
+	results, err := s.Repo.ListResults(ctx, id, maxAnalysesLimit)
+	if err != nil {
+		return Batch{}, Summary{}, err
+	}
+	return b, summarize(results), nil
+}
+
+// List returns recent batches, newest first.
+func (s *Service) List(ctx context.Context, limit int) ([]Batch, error) {
+	return s.Repo.ListBatches(ctx, limit)
+}
+
+// Results returns a batch's shadow results.
+func (s *Service) Results(ctx context.Context, id string, limit int) ([]Result, error) {
+	if _, err := s.Repo.GetBatch(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxAnalysesLimit {
+		limit = maxAnalysesLimit
+	}
+	return s.Repo.ListResults(ctx, id, limit)
+}
+
+// Cancel stops a queued or running batch. A running batch stops after the
+// analysis it is scoring.
+func (s *Service) Cancel(ctx context.Context, id string) (Batch, error) {
+	b, err := s.Repo.GetBatch(ctx, id)
+	if err != nil {
+		return Batch{}, err
+	}
+	if b.Status.Finished() {
+		return Batch{}, ErrInvalidTransition
+	}
+	now := s.now()
+	b.Status = StatusCanceled
+	b.UpdatedAt = now
+	b.CompletedAt = &now
+	if err := s.Repo.UpdateBatch(ctx, b); err != nil {
+		return Batch{}, err
+	}
+	return b, nil
+}
+
+// Run queues the nightly batch when due and runs queued batches, checking again
+// every interval until ctx is done.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.scheduleNightly(ctx); err != nil && ctx.Err() == nil {
+			telemetry.Error("rescore.schedule_failed", map[string]any{"error": err.Error()})
+		}
+		for ctx.Err() == nil {
+			_, err := s.RunNext(ctx)
+			if errors.Is(err, ErrNotFound) {
+				break
+			}
+			if err != nil && ctx.Err() == nil {
+				telemetry.Error("rescore.batch_failed", map[string]any{"error": err.Error()})
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNext claims the oldest queued batch and runs it. It returns ErrNotFound
+// when no batch is queued.
+func (s *Service) RunNext(ctx context.Context) (Batch, error) {
+	now := s.now()
+	b, err := s.Repo.ClaimQueued(ctx, now, now.Add(-staleAfter))
+	if err != nil {
+		return Batch{}, err
+	}
+	return s.run(ctx, b)
+}
+
+func (s *Service) run(ctx context.Context, b Batch) (Batch, error) {
+	opts := analyses.ShadowOptions{PromptVersion: b.PromptVersion}
+	if b.Model != "" {
+		if s.Clients == nil {
+			return s.fail(ctx, b, errors.New("model overrides are not configured"))
+		}
+		client, err := s.Clients(b.Model)
+		if err != nil {
+			return s.fail(ctx, b, fmt.Errorf("llm client for %s: %w", b.Model, err))
+		}
+		opts.Client = client
+	}
+	cohort, err := s.cohort(ctx, b)
+	if err != nil {
+		return s.fail(ctx, b, fmt.Errorf("select cohort: %w", err))
+	}
+	// A batch requeued at shutdown resumes where it stopped.
+	existing, err := s.Repo.ListResults(ctx, b.ID, maxAnalysesLimit)
+	if err != nil {
+		return s.fail(ctx, b, fmt.Errorf("list results: %w", err))
+	}
+	done := make(map[string]bool, len(existing))
+	b.Processed, b.Failed = 0, 0
+	for _, res := range existing {
+		done[res.AnalysisID] = true
+		b.Processed++
+		if res.Status == ResultFailed {
+			b.Failed++
+		}
+	}
+	b.Total = len(cohort)
+	b.UpdatedAt = s.now()
+	if err := s.Repo.UpdateBatch(ctx, b); err != nil {
+		return b, err
+	}
+
+	pause := time.Minute / time.Duration(b.RequestsPerMinute)
+	first := true
+	for _, analysisID := range cohort {
+		if done[analysisID] {
+			continue
+		}
+		if !first {
+			if err := s.pause(ctx, pause); err != nil {
+				return s.requeue(b)
+			}
+		}
+		first = false
+
+		res := s.score(ctx, b, analysisID, opts)
+		if ctx.Err() != nil {
+			// Shutdown interrupted the call; score it again when the batch resumes.
+			return s.requeue(b)
+		}
+		if err := s.Repo.SaveResult(ctx, res); err != nil {
+			return s.fail(ctx, b, fmt.Errorf("save result: %w", err))
+		}
+		b.Processed++
+		if res.Status == ResultFailed {
+			b.Failed++
+		}
+		b.UpdatedAt = s.now()
+		if err := s.Repo.UpdateBatch(ctx, b); errors.Is(err, ErrInvalidTransition) {
+			// Canceled while running.
+			return s.Repo.GetBatch(ctx, b.ID)
+		} else if err != nil {
+			return b, err
+		}
+	}
+
+	now := s.now()
+	b.Status = StatusCompleted
+	b.UpdatedAt = now
+	b.CompletedAt = &now
+	if err := s.Repo.UpdateBatch(ctx, b); err != nil {
+		if errors.Is(err, ErrInvalidTransition) {
+			return s.Repo.GetBatch(ctx, b.ID)
+		}
+		return b, err
+	}
+	telemetry.Info("rescore.batch_completed", map[string]any{
+		"batch_id":  b.ID,
+		"total":     b.Total,
+		"processed": b.Processed,
+		"failed":    b.Failed,
+	})
+	return b, nil
+}
+
+// cohort returns the IDs of the analyses a batch re-scores: the most recently
+// completed since the batch's start date, up to its limit, of its mode if set.
+func (s *Service) cohort(ctx context.Context, b Batch) ([]string, error) {
+	if s.Source == nil {
+		return nil, errors.New("no analysis source configured")
+	}
+	items, err := s.Source.ListCompletedSince(ctx, b.Since, b.MaxAnalyses)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(items))
+	for _, a := range items {
+		if b.Mode != "" && string(a.Mode) != b.Mode {
+			continue
+		}
+		out = append(out, a.ID)
+	}
+	return out, nil
+}
+
+func (s *Service) score(ctx context.Context, b Batch, analysisID string, opts analyses.ShadowOptions) Result {
+	res := Result{
+		BatchID:       b.ID,
+		AnalysisID:    analysisID,
+		PromptVersion: b.PromptVersion,
+		Model:         b.Model,
+		Status:        ResultCompleted,
+	}
+	shadow, err := s.Scorer.ShadowRun(ctx, analysisID, opts)
+	res.CreatedAt = s.now()
+	if err != nil {
+		res.Status = ResultFailed
+		res.Error = truncate(err.Error(), maxResultErrorLen)
+		return res
+	}
+	res.Result = shadow.Result
+	res.ShadowScore = shadow.Score
+	res.OriginalScore = shadow.OriginalScore
+	res.DurationMs = shadow.DurationMs
+	return res
+}
+
+func (s *Service) fail(ctx context.Context, b Batch, cause error) (Batch, error) {
+	now := s.now()
+	b.Status = StatusFailed
+	b.Error = cause.Error()
+	b.UpdatedAt = now
+	b.CompletedAt = &now
+	if err := s.Repo.UpdateBatch(ctx, b); err != nil && !errors.Is(err, ErrInvalidTransition) {
+		return b, err
+	}
+	return b, cause
+}
+
+// requeue hands an interrupted batch back to the queue so the next worker
+// resumes it. The run's context is already done, so a fresh one is used.
+func (s *Service) requeue(b Batch) (Batch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	b.Status = StatusQueued
+	b.UpdatedAt = s.now()
+	if err := s.Repo.UpdateBatch(ctx, b); err != nil && !errors.Is(err, ErrInvalidTransition) {
+		return b, err
+	}
+	return b, context.Canceled
+}
+
+// scheduleNightly queues the scheduled batch unless one was already queued
+// today.
+func (s *Service) scheduleNightly(ctx context.Context) error {
+	if s.Schedule == nil {
+		return nil
+	}
+	now := s.now().UTC()
+	if now.Hour() < s.Schedule.Hour {
+		return nil
+	}
+	today := now.Truncate(24 * time.Hour)
+	recent, err := s.Repo.ListBatches(ctx, 50)
+	if err != nil {
+		return err
+	}
+	for _, b := range recent {
+		if b.Trigger == TriggerSchedule && !b.CreatedAt.Before(today) {
+			return nil
+		}
+	}
+	_, err = s.Create(ctx, s.Schedule.Request, TriggerSchedule, "")
+	return err
+}
+
+func (s *Service) pause(ctx context.Context, d time.Duration) error {
+	if s.wait != nil {
+		return s.wait(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func summarize(results []Result) Summary {
+	var out Summary
+	var sum, sumAbs float64
+	for _, res := range results {
+		if res.ShadowScore == nil || res.OriginalScore == nil {
+			continue
+		}
+		delta := *res.ShadowScore - *res.OriginalScore
+		out.Compared++
+		sum += delta
+		sumAbs += math.Abs(delta)
+		out.MaxAbsDelta = math.Max(out.MaxAbsDelta, math.Abs(delta))
+	}
+	if out.Compared > 0 {
+		out.MeanDelta = sum / float64(out.Compared)
+		out.MeanAbsDelta = sumAbs / float64(out.Compared)
+	}
+	return out
+}
+
+func bounded(field string, value, fallback, limit int) (int, error) {
+	switch {
+	case value < 0:
+		return 0, fmt.Errorf("%w: %s must not be negative", ErrInvalidInput, field)
+	case value == 0:
+		return fallback, nil
+	case value > limit:
+		return 0, fmt.Errorf("%w: %s must be at most %d", ErrInvalidInput, field, limit)
+	}
+	return value, nil
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
@@ -0,0 +1,218 @@
+package rescore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+type fakeScorer struct {
+	mu     sync.Mutex
+	scores map[string]float64
+	calls  []string
+	// onCall runs before each score, for example to cancel the batch.
+	onCall func(analysisID string)
+}
+
+func (f *fakeScorer) ShadowRun(ctx context.Context, analysisID string, opts analyses.ShadowOptions) (analyses.ShadowResult, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, analysisID)
+	f.mu.Unlock()
+	if f.onCall != nil {
+		f.onCall(analysisID)
+	}
+	score, ok := f.scores[analysisID]
+	if !ok {
+		return analyses.ShadowResult{}, errors.New("llm output invalid")
+	}
+	original := 70.0
+	return analyses.ShadowResult{
+		PromptVersion: opts.PromptVersion,
+		Result:        map[string]any{"finalScore": score},
+		Score:         &score,
+		OriginalScore: &original,
+	}, nil
+}
+
+type fakeSource struct{ items []analyses.Analysis }
+
+func (f fakeSource) ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error) {
+	if limit < len(f.items) {
+		return f.items[:limit], nil
+	}
+	return f.items, nil
+}
+
+func newTestService(scorer *fakeScorer, items ...analyses.Analysis) (*Service, *clock, *[]time.Duration) {
+	clk := &clock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	svc := NewService(NewMemoryRepo(), scorer, fakeSource{items: items})
+	svc.Now = clk.Now
+	var pauses []time.Duration
+	svc.wait = func(ctx context.Context, d time.Duration) error {
+		pauses = append(pauses, d)
+		return ctx.Err()
+	}
+	return svc, clk, &pauses
+}
+
+func cohort(ids ...string) []analyses.Analysis {
+	out := make([]analyses.Analysis, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, analyses.Analysis{ID: id, Mode: analyses.ModeATS, Status: analyses.StatusCompleted})
+	}
+	return out
+}
+
+func TestCreateValidatesRequest(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService(&fakeScorer{})
+
+	for name, req := range map[string]CreateRequest{
+		"missing version":  {},
+		"unknown version":  {PromptVersion: "v9_9"},
+		"model override":   {PromptVersion: "v2_3", Model: "gpt-4o"},
+		"unknown mode":     {PromptVersion: "v2_3", Mode: "cover_letter"},
+		"too many":         {PromptVersion: "v2_3", MaxAnalyses: maxAnalysesLimit + 1},
+		"negative rate":    {PromptVersion: "v2_3", RequestsPerMinute: -1},
+		"window too large": {PromptVersion: "v2_3", SinceDays: maxSinceDays + 1},
+	} {
+		if _, err := svc.Create(ctx, req, TriggerAdmin, "admin-1"); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+
+	b, err := svc.Create(ctx, CreateRequest{PromptVersion: "v2_3"}, TriggerAdmin, "admin-1")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if b.Status != StatusQueued || b.MaxAnalyses != defaultMaxAnalyses || b.RequestsPerMinute != defaultRequestsPerMinute {
+		t.Fatalf("unexpected batch: %+v", b)
+	}
+}
+
+func TestRunNextScoresCohortIntoShadowTable(t *testing.T) {
+	ctx := context.Background()
+	scorer := &fakeScorer{scores: map[string]float64{"a1": 80, "a2": 60}}
+	svc, _, pauses := newTestService(scorer, cohort("a1", "a2", "a3")...)
+
+	queued, err := svc.Create(ctx, CreateRequest{PromptVersion: "v2_3", RequestsPerMinute: 60}, TriggerAdmin, "admin-1")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	b, err := svc.RunNext(ctx)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if b.ID != queued.ID || b.Status != StatusCompleted || b.Total != 3 || b.Processed != 3 || b.Failed != 1 {
+		t.Fatalf("unexpected batch: %+v", b)
+	}
+	if len(*pauses) != 2 || (*pauses)[0] != time.Second {
+		t.Fatalf("expected a one-second pause between analyses, got %v", *pauses)
+	}
+
+	results, err := svc.Results(ctx, b.ID, 0)
+	if err != nil || len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d (%v)", len(results), err)
+	}
+	_, summary, err := svc.Get(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if summary.Compared != 2 || summary.MeanDelta != 0 || summary.MeanAbsDelta != 10 || summary.MaxAbsDelta != 10 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if _, err := svc.RunNext(ctx); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected no queued batch, got %v", err)
+	}
+}
+
+func TestRunNextStopsWhenCanceled(t *testing.T) {
+	ctx := context.Background()
+	scorer := &fakeScorer{scores: map[string]float64{"a1": 80, "a2": 60, "a3": 75}}
+	svc, _, _ := newTestService(scorer, cohort("a1", "a2", "a3")...)
+
+	queued, err := svc.Create(ctx, CreateRequest{PromptVersion: "v2_3"}, TriggerAdmin, "admin-1")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	scorer.onCall = func(analysisID string) {
+		if analysisID == "a1" {
+			if _, err := svc.Cancel(ctx, queued.ID); err != nil {
+				t.Errorf("cancel: %v", err)
+			}
+		}
+	}
+	b, err := svc.RunNext(ctx)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if b.Status != StatusCanceled || len(scorer.calls) != 1 {
+		t.Fatalf("expected batch canceled after one analysis, got %+v with calls %v", b, scorer.calls)
+	}
+	if _, err := svc.Cancel(ctx, queued.ID); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestRunNextRequeuesOnShutdownAndResumes(t *testing.T) {
+	scorer := &fakeScorer{scores: map[string]float64{"a1": 80, "a2": 60}}
+	svc, _, _ := newTestService(scorer, cohort("a1", "a2")...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := svc.Create(ctx, CreateRequest{PromptVersion: "v2_3"}, TriggerAdmin, "admin-1"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	scorer.onCall = func(string) { cancel() }
+	b, err := svc.RunNext(ctx)
+	if !errors.Is(err, context.Canceled) || b.Status != StatusQueued {
+		t.Fatalf("expected requeued batch, got %+v (%v)", b, err)
+	}
+
+	scorer.onCall = nil
+	scorer.calls = nil
+	b, err = svc.RunNext(context.Background())
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if b.Status != StatusCompleted || b.Processed != 2 || len(scorer.calls) != 2 {
+		t.Fatalf("expected the interrupted analysis scored again, got %+v with calls %v", b, scorer.calls)
+	}
+}
+
+func TestScheduleNightlyQueuesOncePerDay(t *testing.T) {
+	ctx := context.Background()
+	svc, clk, _ := newTestService(&fakeScorer{})
+	svc.Schedule = &Schedule{Hour: 14, Request: CreateRequest{PromptVersion: "v2_3", SinceDays: 1}}
+
+	count := func() int {
+		batches, err := svc.List(ctx, 50)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		return len(batches)
+	}
+	if err := svc.scheduleNightly(ctx); err != nil || count() != 0 {
+		t.Fatalf("expected nothing before the scheduled hour, got %d (%v)", count(), err)
+	}
+	clk.now = clk.now.Add(3 * time.Hour)
+	for i := 0; i < 2; i++ {
+		if err := svc.scheduleNightly(ctx); err != nil {
+			t.Fatalf("schedule: %v", err)
+		}
+	}
+	if count() != 1 {
+		t.Fatalf("expected one scheduled batch, got %d", count())
+	}
+	clk.now = clk.now.Add(24 * time.Hour)
+	if err := svc.scheduleNightly(ctx); err != nil || count() != 2 {
+		t.Fatalf("expected a batch the next day, got %d (%v)", count(), err)
+	}
+}
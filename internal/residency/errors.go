@@ -0,0 +1,23 @@
+package residency
+
+import "errors"
+
+var (
+	// ErrNotFound indicates no residency is assigned.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidRegion indicates an unknown residency region.
+	ErrInvalidRegion = errors.New("invalid residency region")
+
+	// ErrConflict indicates a user and one of their organizations would be
+	// pinned to different regions.
+	ErrConflict = errors.New("residency conflicts with organization")
+
+	// ErrCrossRegion indicates data pinned to one region was accessed on behalf
+	// of a user pinned to another.
+	ErrCrossRegion = errors.New("cross-region data access rejected")
+
+	// ErrRegionUnavailable indicates no storage bucket or LLM endpoint is
+	// configured for a region.
+	ErrRegionUnavailable = errors.New("residency region not configured")
+)
@@ -0,0 +1,104 @@
+package residency
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the residency admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches residency routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/residency/users/:id", h.getUser)
+	rg.PUT("/residency/users/:id", h.setUser)
+	rg.GET("/residency/orgs/:orgId", h.getOrg)
+	rg.PUT("/residency/orgs/:orgId", h.setOrg)
+}
+
+type setRequest struct {
+	Region string `json:"region"`
+}
+
+// getUser returns the region a user's data resolves to and the user's own
+// assignment, if any.
+func (h *Handler) getUser(c *gin.Context) {
+	userID := c.Param("id")
+	region, err := h.Svc.RegionFor(c.Request.Context(), userID)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to resolve residency", nil)
+		return
+	}
+	body := gin.H{"userId": userID, "region": region}
+	assignment, err := h.Svc.Get(c.Request.Context(), KindUser, userID)
+	switch {
+	case err == nil:
+		body["assignment"] = assignment
+	case !errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load residency", nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, body)
+}
+
+func (h *Handler) getOrg(c *gin.Context) {
+	assignment, err := h.Svc.Get(c.Request.Context(), KindOrg, c.Param("orgId"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, assignment)
+}
+
+func (h *Handler) setUser(c *gin.Context) {
+	var req setRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid JSON body", nil)
+		return
+	}
+	assignment, err := h.Svc.SetUser(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"), req.Region)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, assignment)
+}
+
+func (h *Handler) setOrg(c *gin.Context) {
+	var req setRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid JSON body", nil)
+		return
+	}
+	assignment, err := h.Svc.SetOrg(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("orgId"), req.Region)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, assignment)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidRegion):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrConflict):
+		respond.Error(c, http.StatusConflict, "residency_conflict", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "no residency assigned", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to update residency", nil)
+	}
+}
@@ -0,0 +1,111 @@
+package residency_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func putRegion(router http.Handler, path, authorization, region string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(map[string]string{"region": region})
+	req := httptest.NewRequest(http.MethodPut, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", authorization)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestAdminPinsOrgUploadsToRegion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	storeDir := t.TempDir()
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   storeDir,
+		Env:             "dev",
+		ObjectStoreType: "local",
+		AdminUserIDs:    []string{"admin-1"},
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := app.UsageService.SetOrgQuota(ctx, "acme", "Team", 100, 0); err != nil {
+		t.Fatalf("set org quota: %v", err)
+	}
+	if _, err := app.UsageService.AddOrgMember(ctx, "acme", "recruiter-a"); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	admin := bearer(t, "admin-1")
+
+	if resp := putRegion(app.Router, "/api/v1/admin/residency/orgs/acme", bearer(t, "recruiter-a"), "eu"); resp.Code != http.StatusForbidden {
+		t.Fatalf("non-admin: expected 403, got %d", resp.Code)
+	}
+	if resp := putRegion(app.Router, "/api/v1/admin/residency/orgs/acme", admin, "apac"); resp.Code != http.StatusBadRequest {
+		t.Fatalf("unknown region: expected 400, got %d", resp.Code)
+	}
+	if resp := putRegion(app.Router, "/api/v1/admin/residency/orgs/acme", admin, "eu"); resp.Code != http.StatusOK {
+		t.Fatalf("pin org: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := putRegion(app.Router, "/api/v1/admin/residency/users/recruiter-a", admin, "us"); resp.Code != http.StatusConflict {
+		t.Fatalf("pin member elsewhere: expected 409, got %d", resp.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/residency/users/recruiter-a", nil)
+	req.Header.Set("Authorization", admin)
+	resp := httptest.NewRecorder()
+	app.Router.ServeHTTP(resp, req)
+	var resolved struct {
+		Region string `json:"region"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &resolved); err != nil || resolved.Region != "eu" {
+		t.Fatalf("expected member to resolve to eu, got %s", resp.Body.String())
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, _ := writer.CreateFormFile("file", "resume.txt")
+	_, _ = fileWriter.Write([]byte("Go engineer resume"))
+	_ = writer.Close()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", bearer(t, "recruiter-a"))
+	resp = httptest.NewRecorder()
+	app.Router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		DocumentID string `json:"documentId"`
+	}
+	_ = json.Unmarshal(resp.Body.Bytes(), &created)
+	doc, err := app.DocumentsRepo.GetByID(ctx, "recruiter-a", created.DocumentID)
+	if err != nil {
+		t.Fatalf("load document: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storeDir, "eu", doc.StorageKey[len("eu:"):])); err != nil {
+		t.Fatalf("expected upload in the eu store, key=%q: %v", doc.StorageKey, err)
+	}
+}
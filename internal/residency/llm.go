@@ -0,0 +1,69 @@
+package residency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+// LLM sends each analysis to the endpoint pinned to its data owner's region.
+// Calls without a data owner use the default region.
+type LLM struct {
+	// Regions resolves owners to regions; nil routes everything to Default.
+	Regions *Service
+	Default Region
+	Clients map[Region]llm.Client
+}
+
+var _ llm.Client = (*LLM)(nil)
+
+// AnalyzeResume delegates to the client of the data owner's region.
+func (l *LLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	region, err := ownerRegion(ctx, l.Regions, l.Default)
+	if err != nil {
+		return nil, err
+	}
+	client, ok := l.Clients[region]
+	if !ok || client == nil {
+		return nil, fmt.Errorf("%w: no LLM endpoint for %s", ErrRegionUnavailable, region)
+	}
+	return client.AnalyzeResume(ctx, input)
+}
+
+// PromptClient completes JSON prompts, such as apply runs and job description
+// extraction, on the endpoint of the data owner's region.
+type PromptClient interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// PromptLLM routes PromptClient calls by region like LLM.
+type PromptLLM struct {
+	Regions *Service
+	Default Region
+	Clients map[Region]PromptClient
+}
+
+var _ PromptClient = (*PromptLLM)(nil)
+
+// Complete delegates to the client of the data owner's region.
+func (l *PromptLLM) Complete(ctx context.Context, prompt string) (string, error) {
+	region, err := ownerRegion(ctx, l.Regions, l.Default)
+	if err != nil {
+		return "", err
+	}
+	client, ok := l.Clients[region]
+	if !ok || client == nil {
+		return "", fmt.Errorf("%w: no LLM endpoint for %s", ErrRegionUnavailable, region)
+	}
+	return client.Complete(ctx, prompt)
+}
+
+func ownerRegion(ctx context.Context, regions *Service, def Region) (Region, error) {
+	if regions == nil {
+		return def, nil
+	}
+	return regions.RegionFor(ctx, ctxmeta.DataOwner(ctx))
+}
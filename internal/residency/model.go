@@ -0,0 +1,44 @@
+package residency
+
+import (
+	"strings"
+	"time"
+)
+
+// Region is where a user's documents are stored and processed.
+type Region string
+
+const (
+	RegionUS Region = "us"
+	RegionEU Region = "eu"
+)
+
+// Regions lists the supported regions.
+var Regions = []Region{RegionUS, RegionEU}
+
+// ParseRegion normalizes a region name such as "EU" or "us".
+func ParseRegion(raw string) (Region, error) {
+	region := Region(strings.ToLower(strings.TrimSpace(raw)))
+	for _, known := range Regions {
+		if region == known {
+			return region, nil
+		}
+	}
+	return "", ErrInvalidRegion
+}
+
+// Subject kinds a region can be assigned to.
+const (
+	KindUser = "user"
+	KindOrg  = "org"
+)
+
+// Assignment pins a user's or an organization's data to a region. An
+// organization's assignment applies to all of its members.
+type Assignment struct {
+	Kind      string    `json:"kind"`
+	SubjectID string    `json:"subjectId"`
+	Region    Region    `json:"region"`
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
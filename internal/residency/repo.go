@@ -0,0 +1,10 @@
+package residency
+
+import "context"
+
+// Repo persists residency assignments.
+type Repo interface {
+	Get(ctx context.Context, kind, subjectID string) (Assignment, error)
+	Upsert(ctx context.Context, assignment Assignment) error
+	ListByKind(ctx context.Context, kind string) ([]Assignment, error)
+}
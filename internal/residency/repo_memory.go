@@ -0,0 +1,66 @@
+package residency
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo stores assignments in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu          sync.RWMutex
+	assignments map[string]Assignment
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{assignments: make(map[string]Assignment)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+func memoryKey(kind, subjectID string) string {
+	return kind + "\x00" + subjectID
+}
+
+// Get returns the assignment of a user or organization.
+func (r *MemoryRepo) Get(ctx context.Context, kind, subjectID string) (Assignment, error) {
+	if err := ctx.Err(); err != nil {
+		return Assignment{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	assignment, ok := r.assignments[memoryKey(kind, subjectID)]
+	if !ok {
+		return Assignment{}, ErrNotFound
+	}
+	return assignment, nil
+}
+
+// Upsert creates or replaces an assignment.
+func (r *MemoryRepo) Upsert(ctx context.Context, assignment Assignment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.assignments[memoryKey(assignment.Kind, assignment.SubjectID)] = assignment
+	return nil
+}
+
+// ListByKind returns all user or all organization assignments.
+func (r *MemoryRepo) ListByKind(ctx context.Context, kind string) ([]Assignment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Assignment
+	for _, assignment := range r.assignments {
+		if assignment.Kind == kind {
+			out = append(out, assignment)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].SubjectID < out[j].SubjectID })
+	return out, nil
+}
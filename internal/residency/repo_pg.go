@@ -0,0 +1,90 @@
+package residency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+// Get returns the assignment of a user or organization.
+func (r *PGRepo) Get(ctx context.Context, kind, subjectID string) (Assignment, error) {
+	const query = `
+SELECT kind, subject_id, region, updated_by, updated_at
+FROM data_residency
+WHERE kind = $1 AND subject_id = $2`
+	var assignment Assignment
+	err := r.DB.QueryRowContext(ctx, query, kind, subjectID).Scan(
+		&assignment.Kind,
+		&assignment.SubjectID,
+		&assignment.Region,
+		&assignment.UpdatedBy,
+		&assignment.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Assignment{}, ErrNotFound
+	}
+	if err != nil {
+		return Assignment{}, err
+	}
+	return assignment, nil
+}
+
+// Upsert creates or replaces an assignment.
+func (r *PGRepo) Upsert(ctx context.Context, assignment Assignment) error {
+	const query = `
+INSERT INTO data_residency (
+    kind,
+    subject_id,
+    region,
+    updated_by,
+    updated_at
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (kind, subject_id) DO UPDATE SET
+    region = EXCLUDED.region,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = EXCLUDED.updated_at`
+	_, err := r.DB.ExecContext(ctx, query,
+		assignment.Kind,
+		assignment.SubjectID,
+		string(assignment.Region),
+		assignment.UpdatedBy,
+		assignment.UpdatedAt,
+	)
+	return err
+}
+
+// ListByKind returns all user or all organization assignments.
+func (r *PGRepo) ListByKind(ctx context.Context, kind string) ([]Assignment, error) {
+	const query = `
+SELECT kind, subject_id, region, updated_by, updated_at
+FROM data_residency
+WHERE kind = $1
+ORDER BY subject_id`
+	rows, err := r.DB.QueryContext(ctx, query, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Assignment
+	for rows.Next() {
+		var assignment Assignment
+		if err := rows.Scan(
+			&assignment.Kind,
+			&assignment.SubjectID,
+			&assignment.Region,
+			&assignment.UpdatedBy,
+			&assignment.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, assignment)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,178 @@
+package residency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/usage"
+)
+
+// MemberChecker confirms organization membership. usage.Service implements it.
+type MemberChecker interface {
+	CheckOrgMember(ctx context.Context, orgID, userID string) error
+}
+
+// Service assigns regions to users and organizations and resolves the region a
+// user's data lives in.
+type Service struct {
+	Repo    Repo
+	Members MemberChecker
+	// Audit records region changes; nil skips auditing.
+	Audit *audit.Service
+	// Default is the region of users with no assignment, and of guests.
+	Default Region
+	Now     func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, members MemberChecker, auditSvc *audit.Service, def Region) *Service {
+	return &Service{Repo: repo, Members: members, Audit: auditSvc, Default: def}
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// RegionFor returns the region userID's data lives in. An organization's
+// assignment wins over the member's own; users with neither, and guests, get
+// the default region.
+func (s *Service) RegionFor(ctx context.Context, userID string) (Region, error) {
+	if userID == "" || strings.HasPrefix(userID, "guest:") {
+		return s.Default, nil
+	}
+	orgs, err := s.Repo.ListByKind(ctx, KindOrg)
+	if err != nil {
+		return "", err
+	}
+	for _, org := range orgs {
+		member, err := s.isMember(ctx, org.SubjectID, userID)
+		if err != nil {
+			return "", err
+		}
+		if member {
+			return org.Region, nil
+		}
+	}
+	assignment, err := s.Repo.Get(ctx, KindUser, userID)
+	if errors.Is(err, ErrNotFound) {
+		return s.Default, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return assignment.Region, nil
+}
+
+// Get returns the assignment of a user or organization.
+func (s *Service) Get(ctx context.Context, kind, subjectID string) (Assignment, error) {
+	return s.Repo.Get(ctx, kind, subjectID)
+}
+
+// SetUser pins a user's data to region. It fails with ErrConflict when one of the
+// user's organizations is pinned elsewhere.
+func (s *Service) SetUser(ctx context.Context, actorID, userID, region string) (Assignment, error) {
+	parsed, err := ParseRegion(region)
+	if err != nil {
+		return Assignment{}, err
+	}
+	if strings.TrimSpace(userID) == "" || strings.HasPrefix(userID, "guest:") {
+		return Assignment{}, fmt.Errorf("%w: guests cannot be assigned a region", ErrInvalidRegion)
+	}
+	orgs, err := s.Repo.ListByKind(ctx, KindOrg)
+	if err != nil {
+		return Assignment{}, err
+	}
+	for _, org := range orgs {
+		if org.Region == parsed {
+			continue
+		}
+		member, err := s.isMember(ctx, org.SubjectID, userID)
+		if err != nil {
+			return Assignment{}, err
+		}
+		if member {
+			return Assignment{}, fmt.Errorf("%w: organization %s is pinned to %s", ErrConflict, org.SubjectID, org.Region)
+		}
+	}
+	return s.set(ctx, actorID, KindUser, userID, parsed)
+}
+
+// SetOrg pins an organization's data, and that of all its members, to region. It
+// fails with ErrConflict when a member is pinned elsewhere.
+func (s *Service) SetOrg(ctx context.Context, actorID, orgID, region string) (Assignment, error) {
+	parsed, err := ParseRegion(region)
+	if err != nil {
+		return Assignment{}, err
+	}
+	if strings.TrimSpace(orgID) == "" {
+		return Assignment{}, fmt.Errorf("%w: organization is required", ErrInvalidRegion)
+	}
+	users, err := s.Repo.ListByKind(ctx, KindUser)
+	if err != nil {
+		return Assignment{}, err
+	}
+	for _, user := range users {
+		if user.Region == parsed {
+			continue
+		}
+		member, err := s.isMember(ctx, orgID, user.SubjectID)
+		if err != nil {
+			return Assignment{}, err
+		}
+		if member {
+			return Assignment{}, fmt.Errorf("%w: member %s is pinned to %s", ErrConflict, user.SubjectID, user.Region)
+		}
+	}
+	return s.set(ctx, actorID, KindOrg, orgID, parsed)
+}
+
+func (s *Service) set(ctx context.Context, actorID, kind, subjectID string, region Region) (Assignment, error) {
+	assignment := Assignment{
+		Kind:      kind,
+		SubjectID: subjectID,
+		Region:    region,
+		UpdatedBy: actorID,
+		UpdatedAt: s.now(),
+	}
+	if err := s.Repo.Upsert(ctx, assignment); err != nil {
+		return Assignment{}, err
+	}
+	if s.Audit != nil {
+		entry := audit.Entry{
+			Action:      "residency.set",
+			ActorUserID: actorID,
+			Details: map[string]any{
+				"kind":      kind,
+				"subjectId": subjectID,
+				"region":    string(region),
+			},
+		}
+		if kind == KindUser {
+			entry.SubjectUserID = subjectID
+		}
+		_ = s.Audit.Record(ctx, entry)
+	}
+	return assignment, nil
+}
+
+func (s *Service) isMember(ctx context.Context, orgID, userID string) (bool, error) {
+	if s.Members == nil {
+		return false, nil
+	}
+	err := s.Members.CheckOrgMember(ctx, orgID, userID)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, usage.ErrNotOrgMember), errors.Is(err, usage.ErrOrgNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
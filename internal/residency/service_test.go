@@ -0,0 +1,168 @@
+package residency_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/residency"
+	"resume-backend/internal/shared/ctxmeta"
+	localstore "resume-backend/internal/shared/storage/object/local"
+	"resume-backend/internal/usage"
+)
+
+func newService(t *testing.T) *residency.Service {
+	t.Helper()
+	ctx := context.Background()
+	members := usage.NewService()
+	if _, err := members.SetOrgQuota(ctx, "acme", "Team", 100, 0); err != nil {
+		t.Fatalf("set org quota: %v", err)
+	}
+	if _, err := members.AddOrgMember(ctx, "acme", "eu-member"); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	return residency.NewService(residency.NewMemoryRepo(), members, nil, residency.RegionUS)
+}
+
+func TestOrgResidencyAppliesToMembers(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t)
+
+	if _, err := svc.SetOrg(ctx, "admin-1", "acme", "mars"); !errors.Is(err, residency.ErrInvalidRegion) {
+		t.Fatalf("expected invalid region, got %v", err)
+	}
+	if _, err := svc.SetOrg(ctx, "admin-1", "acme", "EU"); err != nil {
+		t.Fatalf("set org: %v", err)
+	}
+	for userID, want := range map[string]residency.Region{
+		"eu-member":   residency.RegionEU,
+		"us-user":     residency.RegionUS,
+		"guest:abc":   residency.RegionUS,
+		"unknown-org": residency.RegionUS,
+	} {
+		got, err := svc.RegionFor(ctx, userID)
+		if err != nil || got != want {
+			t.Fatalf("RegionFor(%s): expected %s, got %s err=%v", userID, want, got, err)
+		}
+	}
+
+	if _, err := svc.SetUser(ctx, "admin-1", "eu-member", "us"); !errors.Is(err, residency.ErrConflict) {
+		t.Fatalf("expected conflict pinning a member away from the org region, got %v", err)
+	}
+	if _, err := svc.SetUser(ctx, "admin-1", "guest:abc", "eu"); !errors.Is(err, residency.ErrInvalidRegion) {
+		t.Fatalf("expected guests to be rejected, got %v", err)
+	}
+	if _, err := svc.SetUser(ctx, "admin-1", "us-user", "eu"); err != nil {
+		t.Fatalf("set user: %v", err)
+	}
+	if region, _ := svc.RegionFor(ctx, "us-user"); region != residency.RegionEU {
+		t.Fatalf("expected user assignment to apply, got %s", region)
+	}
+}
+
+func TestStoreRejectsCrossRegionAccess(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t)
+	if _, err := svc.SetOrg(ctx, "admin-1", "acme", "eu"); err != nil {
+		t.Fatalf("set org: %v", err)
+	}
+	dir := t.TempDir()
+	store := residency.NewStore(residency.RegionUS, localstore.New(filepath.Join(dir, "us")))
+	store.Regions = svc
+
+	// Without an EU bucket, EU data cannot be written anywhere.
+	if _, _, _, err := store.Save(ctx, "eu-member", "resume.txt", strings.NewReader("resume")); !errors.Is(err, residency.ErrRegionUnavailable) {
+		t.Fatalf("expected region unavailable, got %v", err)
+	}
+	store.Stores[residency.RegionEU] = localstore.New(filepath.Join(dir, "eu"))
+
+	euKey, _, _, err := store.Save(ctx, "eu-member", "resume.txt", strings.NewReader("eu resume"))
+	if err != nil {
+		t.Fatalf("save eu: %v", err)
+	}
+	if !strings.HasPrefix(euKey, "eu:") {
+		t.Fatalf("expected eu key to carry its region, got %q", euKey)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "eu", strings.TrimPrefix(euKey, "eu:"))); err != nil {
+		t.Fatalf("expected object in the eu store: %v", err)
+	}
+	usKey, _, _, err := store.Save(ctx, "us-user", "resume.txt", strings.NewReader("us resume"))
+	if err != nil || strings.Contains(usKey, ":") {
+		t.Fatalf("expected an untagged default-region key, got %q err=%v", usKey, err)
+	}
+
+	read := func(ctx context.Context, key string) (string, error) {
+		rc, err := store.Open(ctx, key)
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		return string(body), err
+	}
+	if body, err := read(ctxmeta.WithUserID(ctx, "eu-member"), euKey); err != nil || body != "eu resume" {
+		t.Fatalf("owner read: got %q err=%v", body, err)
+	}
+	if _, err := read(ctxmeta.WithUserID(ctx, "us-user"), euKey); !errors.Is(err, residency.ErrCrossRegion) {
+		t.Fatalf("expected cross-region read to be rejected, got %v", err)
+	}
+	if _, err := read(ctxmeta.WithUserID(ctx, "eu-member"), usKey); !errors.Is(err, residency.ErrCrossRegion) {
+		t.Fatalf("expected cross-region read to be rejected, got %v", err)
+	}
+	// An admin acting on the owner's data reads in the owner's region.
+	adminCtx := ctxmeta.WithDataOwner(ctxmeta.WithUserID(ctx, "admin-1"), "eu-member")
+	if _, err := read(adminCtx, euKey); err != nil {
+		t.Fatalf("read as data owner: %v", err)
+	}
+	if _, err := store.SaveWithKey(ctxmeta.WithUserID(ctx, "us-user"), euKey+".extracted.txt", "text/plain", strings.NewReader("x")); !errors.Is(err, residency.ErrCrossRegion) {
+		t.Fatalf("expected cross-region write to be rejected, got %v", err)
+	}
+	if err := store.Delete(ctxmeta.WithUserID(ctx, "us-user"), euKey); !errors.Is(err, residency.ErrCrossRegion) {
+		t.Fatalf("expected cross-region delete to be rejected, got %v", err)
+	}
+}
+
+type recordingLLM struct {
+	name  string
+	calls *[]string
+}
+
+func (r recordingLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	*r.calls = append(*r.calls, r.name)
+	return json.RawMessage(`{}`), nil
+}
+
+func TestLLMUsesOwnerRegionEndpoint(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t)
+	if _, err := svc.SetOrg(ctx, "admin-1", "acme", "eu"); err != nil {
+		t.Fatalf("set org: %v", err)
+	}
+	var calls []string
+	router := &residency.LLM{
+		Regions: svc,
+		Default: residency.RegionUS,
+		Clients: map[residency.Region]llm.Client{residency.RegionUS: recordingLLM{name: "us", calls: &calls}},
+	}
+
+	euCtx := ctxmeta.WithDataOwner(ctx, "eu-member")
+	if _, err := router.AnalyzeResume(euCtx, llm.AnalyzeInput{}); !errors.Is(err, residency.ErrRegionUnavailable) {
+		t.Fatalf("expected eu analysis without an eu endpoint to be rejected, got %v", err)
+	}
+	router.Clients[residency.RegionEU] = recordingLLM{name: "eu", calls: &calls}
+	if _, err := router.AnalyzeResume(euCtx, llm.AnalyzeInput{}); err != nil {
+		t.Fatalf("eu analysis: %v", err)
+	}
+	if _, err := router.AnalyzeResume(ctxmeta.WithUserID(ctx, "us-user"), llm.AnalyzeInput{}); err != nil {
+		t.Fatalf("us analysis: %v", err)
+	}
+	if strings.Join(calls, ",") != "eu,us" {
+		t.Fatalf("expected calls routed eu then us, got %v", calls)
+	}
+}
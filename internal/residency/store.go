@@ -0,0 +1,162 @@
+package residency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/storage/object"
+)
+
+// keySeparator ends the region tag of a storage key. Keys written to the
+// default region carry no tag, so keys stored before residency existed keep
+// resolving to the default bucket.
+const keySeparator = ":"
+
+// Store routes objects to the bucket of their owner's region. Reads, keyed
+// writes and deletes are rejected with ErrCrossRegion when the key's region
+// differs from the region of the data owner on the context. Calls without a data
+// owner, such as share link downloads and retention sweeps, are not checked.
+type Store struct {
+	// Regions resolves owners to regions; nil routes everything to Default.
+	Regions *Service
+	Default Region
+	Stores  map[Region]object.ObjectStore
+	// Observe, when set, is called with the duration of every call made to a
+	// region's store: op is save, open, save_with_key or delete.
+	Observe func(op string, d time.Duration, err error)
+}
+
+var _ object.ObjectStore = (*Store)(nil)
+
+// NewStore constructs a Store whose default region is served by base.
+func NewStore(def Region, base object.ObjectStore) *Store {
+	return &Store{Default: def, Stores: map[Region]object.ObjectStore{def: base}}
+}
+
+// Save writes the object to the bucket of userId's region.
+func (s *Store) Save(ctx context.Context, userId string, fileName string, r io.Reader) (string, int64, string, error) {
+	region, err := s.regionFor(ctx, userId)
+	if err != nil {
+		return "", 0, "", err
+	}
+	store, err := s.storeFor(region)
+	if err != nil {
+		return "", 0, "", err
+	}
+	start := time.Now()
+	key, size, mimeType, err := store.Save(ctx, userId, fileName, r)
+	s.observe("save", start, err)
+	if err != nil {
+		return "", 0, "", err
+	}
+	return s.encodeKey(region, key), size, mimeType, nil
+}
+
+// Open reads an object from the bucket its key belongs to.
+func (s *Store) Open(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+	store, key, err := s.authorize(ctx, storageKey)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rc, err := store.Open(ctx, key)
+	s.observe("open", start, err)
+	return rc, err
+}
+
+// SaveWithKey writes to a specific key when the region's store supports it.
+func (s *Store) SaveWithKey(ctx context.Context, storageKey string, contentType string, r io.Reader) (int64, error) {
+	store, key, err := s.authorize(ctx, storageKey)
+	if err != nil {
+		return 0, err
+	}
+	saver, ok := store.(interface {
+		SaveWithKey(ctx context.Context, storageKey string, contentType string, r io.Reader) (int64, error)
+	})
+	if !ok {
+		return 0, fmt.Errorf("storage save with key not supported by %T", store)
+	}
+	start := time.Now()
+	n, err := saver.SaveWithKey(ctx, key, contentType, r)
+	s.observe("save_with_key", start, err)
+	return n, err
+}
+
+// Delete removes an object when the region's store supports deletes.
+func (s *Store) Delete(ctx context.Context, storageKey string) error {
+	store, key, err := s.authorize(ctx, storageKey)
+	if err != nil {
+		return err
+	}
+	deleter, ok := store.(interface {
+		Delete(ctx context.Context, storageKey string) error
+	})
+	if !ok {
+		return fmt.Errorf("storage delete not supported by %T", store)
+	}
+	start := time.Now()
+	err = deleter.Delete(ctx, key)
+	s.observe("delete", start, err)
+	return err
+}
+
+func (s *Store) observe(op string, start time.Time, err error) {
+	if s.Observe != nil {
+		s.Observe(op, time.Since(start), err)
+	}
+}
+
+// authorize returns the store and untagged key for storageKey after checking
+// that the data owner on ctx lives in the key's region.
+func (s *Store) authorize(ctx context.Context, storageKey string) (object.ObjectStore, string, error) {
+	region, key := s.decodeKey(storageKey)
+	if owner := ctxmeta.DataOwner(ctx); owner != "" {
+		ownerRegion, err := s.regionFor(ctx, owner)
+		if err != nil {
+			return nil, "", err
+		}
+		if ownerRegion != region {
+			return nil, "", fmt.Errorf("%w: %s data requested for a %s user", ErrCrossRegion, region, ownerRegion)
+		}
+	}
+	store, err := s.storeFor(region)
+	if err != nil {
+		return nil, "", err
+	}
+	return store, key, nil
+}
+
+func (s *Store) regionFor(ctx context.Context, userID string) (Region, error) {
+	if s.Regions == nil {
+		return s.Default, nil
+	}
+	return s.Regions.RegionFor(ctx, userID)
+}
+
+func (s *Store) storeFor(region Region) (object.ObjectStore, error) {
+	store, ok := s.Stores[region]
+	if !ok || store == nil {
+		return nil, fmt.Errorf("%w: no storage for %s", ErrRegionUnavailable, region)
+	}
+	return store, nil
+}
+
+func (s *Store) encodeKey(region Region, key string) string {
+	if region == s.Default {
+		return key
+	}
+	return string(region) + keySeparator + key
+}
+
+func (s *Store) decodeKey(storageKey string) (Region, string) {
+	if tag, key, ok := strings.Cut(storageKey, keySeparator); ok {
+		if region, err := ParseRegion(tag); err == nil && string(region) == tag {
+			return region, key
+		}
+	}
+	return s.Default, storageKey
+}
@@ -0,0 +1,15 @@
+package resumes
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the resume was not found for the user.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrIncomplete indicates the resume lacks what a rendered document needs,
+	// such as a way to contact the candidate.
+	ErrIncomplete = errors.New("resume incomplete")
+)
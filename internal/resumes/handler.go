@@ -0,0 +1,177 @@
+package resumes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+	"resume-backend/resume/model"
+)
+
+// Handler exposes the resume builder endpoints. Analyzing a built resume is
+// served by the analyses handler.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches builder routes to the router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/resumes", h.create)
+	rg.GET("/resumes", h.list)
+	rg.GET("/resumes/:id", h.get)
+	rg.PUT("/resumes/:id", h.update)
+	rg.GET("/resumes/:id/docx", h.download)
+}
+
+type resumeRequest struct {
+	Title  string             `json:"title"`
+	Resume *model.ResumeModel `json:"resume"`
+}
+
+func (r resumeRequest) input() Input {
+	return Input{Title: r.Title, Model: *r.Resume}
+}
+
+func bindResume(c *gin.Context) (resumeRequest, bool) {
+	var req resumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return req, false
+	}
+	if req.Resume == nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "resume is required", []map[string]string{
+			{"field": "resume", "issue": "required"},
+		})
+		return req, false
+	}
+	return req, true
+}
+
+func (h *Handler) create(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	req, ok := bindResume(c)
+	if !ok {
+		return
+	}
+	resume, err := h.Svc.Create(c.Request.Context(), middleware.UserIDFromContext(c), req.input())
+	if err != nil {
+		writeError(c, err, "failed to create resume")
+		return
+	}
+	respond.JSON(c, http.StatusCreated, resume)
+}
+
+func (h *Handler) list(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	items, err := h.Svc.List(c.Request.Context(), middleware.UserIDFromContext(c))
+	if err != nil {
+		writeError(c, err, "failed to list resumes")
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) get(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	resume, err := h.Svc.Get(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err, "failed to fetch resume")
+		return
+	}
+	respond.CachedJSON(c, respond.CacheRevalidate, resume)
+}
+
+func (h *Handler) update(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	req, ok := bindResume(c)
+	if !ok {
+		return
+	}
+	resume, err := h.Svc.Update(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"), req.input())
+	if err != nil {
+		writeError(c, err, "failed to update resume")
+		return
+	}
+	respond.JSON(c, http.StatusOK, resume)
+}
+
+// download renders the resume now; nothing rendered is stored. Content the
+// template left out is listed in X-Render-Warnings as code:section pairs.
+func (h *Handler) download(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	resume, data, warnings, err := h.Svc.Render(c.Request.Context(), middleware.UserIDFromContext(c), c.Param("id"), c.Query("locale"))
+	if err != nil {
+		writeError(c, err, "failed to render resume")
+		return
+	}
+	if len(warnings) > 0 {
+		pairs := make([]string, 0, len(warnings))
+		for _, w := range warnings {
+			pairs = append(pairs, w.Code+":"+w.Section)
+		}
+		c.Header("X-Render-Warnings", strings.Join(pairs, ", "))
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", downloadName(resume)))
+	c.Data(http.StatusOK, docxMimeType, data)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// downloadName builds a file name from the title, falling back to the
+// candidate's name.
+func downloadName(resume Resume) string {
+	base := resume.Title
+	if base == "" {
+		base = resume.Model.Header.Name
+	}
+	base = strings.Trim(unsafeFileChars.ReplaceAllString(base, "_"), "_.")
+	if base == "" {
+		base = "resume"
+	}
+	return base + ".docx"
+}
+
+func requireLogin(c *gin.Context) bool {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to use the resume builder", nil)
+		return false
+	}
+	return true
+}
+
+func writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "resume not found", nil)
+	case errors.Is(err, ErrIncomplete):
+		respond.Error(c, http.StatusUnprocessableEntity, "resume_incomplete", err.Error(), nil)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		respond.Error(c, http.StatusRequestTimeout, "timeout", "request canceled", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", message, nil)
+	}
+}
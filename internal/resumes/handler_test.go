@@ -0,0 +1,236 @@
+package resumes_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+type stubQueue struct {
+	messages []queue.Message
+}
+
+func (s *stubQueue) Send(ctx context.Context, msg queue.Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func newTestApp(t *testing.T) *bootstrap.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	// The renderer loads its template relative to the repository root.
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(cwd, "..", "..")); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	app.AnalysesService.JobQueue = &stubQueue{}
+	return app
+}
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func doRequest(router http.Handler, method, path, authorization string, body any) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	} else {
+		req.Header.Set("X-Guest-Id", "builder-guest")
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func builtResume(email string) map[string]any {
+	return map[string]any{
+		"header":  map[string]any{"name": "Ada Lovelace", "title": "Staff Engineer", "email": email},
+		"summary": []string{"Engineer who builds reliable payment and ledger systems for fast-growing teams."},
+		"skills":  map[string]any{"languages": []string{"Go", "SQL"}, "tools": []string{"Postgres", "Kubernetes"}},
+		"experience": []map[string]any{{
+			"company": "Analytical Engines Ltd",
+			"role":    "Staff Engineer",
+			"start":   "2020-01",
+			"end":     "Present",
+			"highlights": []string{
+				"Led a team of 6 engineers to ship the billing platform used by 2 million customers.",
+				"Cut ledger reconciliation time from 4 hours to 20 minutes by rewriting the batch pipeline.",
+			},
+		}},
+	}
+}
+
+type resumeBody struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	DocumentID string `json:"documentId"`
+	Resume     struct {
+		Header struct {
+			Email string `json:"email"`
+		} `json:"header"`
+	} `json:"resume"`
+}
+
+func decode[T any](t *testing.T, resp *httptest.ResponseRecorder) T {
+	t.Helper()
+	var out T
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response: %v: %s", err, resp.Body.String())
+	}
+	return out
+}
+
+func TestBuildRenderAndAnalyzeResume(t *testing.T) {
+	app := newTestApp(t)
+	ada := bearer(t, "builder-ada")
+
+	resp := doRequest(app.Router, http.MethodPost, "/api/v1/resumes", ada, map[string]any{
+		"title":  "Ada - platform roles",
+		"resume": builtResume(""),
+	})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	created := decode[resumeBody](t, resp)
+	path := "/api/v1/resumes/" + created.ID
+
+	// A draft without contact details is stored but cannot be rendered yet.
+	if resp := doRequest(app.Router, http.MethodGet, path+"/docx", ada, nil); resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("docx of incomplete draft: expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(app.Router, http.MethodPut, path, ada, map[string]any{
+		"title":  "Ada - platform roles",
+		"resume": builtResume("ada@example.com"),
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("update: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := decode[resumeBody](t, doRequest(app.Router, http.MethodGet, path, ada, nil)); got.Resume.Header.Email != "ada@example.com" {
+		t.Fatalf("expected updated email, got %+v", got)
+	}
+
+	resp = doRequest(app.Router, http.MethodGet, path+"/docx?locale=es", ada, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("docx: expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if disposition := resp.Header().Get("Content-Disposition"); !strings.Contains(disposition, "Ada_-_platform_roles.docx") {
+		t.Fatalf("unexpected Content-Disposition %q", disposition)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len())); err != nil {
+		t.Fatalf("expected a DOCX archive: %v", err)
+	}
+
+	resp = doRequest(app.Router, http.MethodPost, path+"/analyze", ada, map[string]any{"mode": "ATS"})
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("analyze: expected 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	started := decode[struct {
+		AnalysisID string `json:"analysisId"`
+		DocumentID string `json:"documentId"`
+		ResumeID   string `json:"resumeId"`
+	}](t, resp)
+	if started.AnalysisID == "" || started.DocumentID == "" || started.ResumeID != created.ID {
+		t.Fatalf("unexpected analyze response %+v", started)
+	}
+	if got := decode[resumeBody](t, doRequest(app.Router, http.MethodGet, path, ada, nil)); got.DocumentID != started.DocumentID {
+		t.Fatalf("expected resume to record document %s, got %q", started.DocumentID, got.DocumentID)
+	}
+
+	// Analyzing the unchanged resume again maps to the same document.
+	resp = doRequest(app.Router, http.MethodPost, path+"/analyze", ada, map[string]any{"mode": "ATS"})
+	if resp.Code != http.StatusOK && resp.Code != http.StatusAccepted {
+		t.Fatalf("re-analyze: expected 200 or 202, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if again := decode[struct {
+		DocumentID string `json:"documentId"`
+	}](t, resp); again.DocumentID != started.DocumentID {
+		t.Fatalf("expected document %s to be reused, got %s", started.DocumentID, again.DocumentID)
+	}
+}
+
+func TestResumeBuilderScopesAndValidates(t *testing.T) {
+	app := newTestApp(t)
+	ada := bearer(t, "builder-ada")
+	bob := bearer(t, "builder-bob")
+
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/resumes", "", map[string]any{"resume": builtResume("guest@example.com")}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("guest create: expected 401, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/resumes", ada, map[string]any{"title": "No content"}); resp.Code != http.StatusBadRequest {
+		t.Fatalf("missing resume: expected 400, got %d", resp.Code)
+	}
+	invalid := builtResume("ada@example.com")
+	invalid["header"].(map[string]any)["name"] = ""
+	if resp := doRequest(app.Router, http.MethodPost, "/api/v1/resumes", ada, map[string]any{"resume": invalid}); resp.Code != http.StatusBadRequest {
+		t.Fatalf("missing name: expected 400, got %d", resp.Code)
+	}
+
+	resp := doRequest(app.Router, http.MethodPost, "/api/v1/resumes", ada, map[string]any{"resume": builtResume("ada@example.com")})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	path := "/api/v1/resumes/" + decode[resumeBody](t, resp).ID
+
+	if resp := doRequest(app.Router, http.MethodGet, path, bob, nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("other user get: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodPost, path+"/analyze", bob, map[string]any{"mode": "ATS"}); resp.Code != http.StatusNotFound {
+		t.Fatalf("other user analyze: expected 404, got %d", resp.Code)
+	}
+	if resp := doRequest(app.Router, http.MethodGet, path+"/docx?locale=xx", ada, nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("unknown locale: expected 400, got %d", resp.Code)
+	}
+	list := decode[struct {
+		Items []resumeBody `json:"items"`
+	}](t, doRequest(app.Router, http.MethodGet, "/api/v1/resumes", bob, nil))
+	if len(list.Items) != 0 {
+		t.Fatalf("expected bob to see no resumes, got %d", len(list.Items))
+	}
+}
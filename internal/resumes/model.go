@@ -0,0 +1,21 @@
+package resumes
+
+import (
+	"time"
+
+	"resume-backend/resume/model"
+)
+
+// Resume is a resume written in the product instead of uploaded. Its content
+// is kept as a ResumeModel and rendered to DOCX on demand.
+type Resume struct {
+	ID     string            `json:"id"`
+	UserID string            `json:"-"`
+	Title  string            `json:"title"`
+	Model  model.ResumeModel `json:"resume"`
+	// DocumentID is the document last created from the rendered resume for
+	// analysis; empty until the resume is first analyzed.
+	DocumentID string    `json:"documentId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,15 @@
+package resumes
+
+import "context"
+
+// Repo persists built resumes. Every lookup is scoped to the owning user.
+type Repo interface {
+	Create(ctx context.Context, resume Resume) error
+	GetByID(ctx context.Context, userID, resumeID string) (Resume, error)
+	// ListByUser returns the user's resumes, most recently updated first.
+	ListByUser(ctx context.Context, userID string) ([]Resume, error)
+	// Update replaces the title and content; ErrNotFound if it does not exist.
+	Update(ctx context.Context, resume Resume) error
+	// SetDocument records the document last created from the resume.
+	SetDocument(ctx context.Context, userID, resumeID, documentID string) error
+}
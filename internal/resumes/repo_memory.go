@@ -0,0 +1,96 @@
+package resumes
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryRepo stores resumes in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu      sync.RWMutex
+	resumes map[string]Resume
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{resumes: make(map[string]Resume)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Create stores a new resume.
+func (r *MemoryRepo) Create(ctx context.Context, resume Resume) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resumes[resume.ID] = resume
+	return nil
+}
+
+// GetByID returns one of the user's resumes.
+func (r *MemoryRepo) GetByID(ctx context.Context, userID, resumeID string) (Resume, error) {
+	if err := ctx.Err(); err != nil {
+		return Resume{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	resume, ok := r.resumes[resumeID]
+	if !ok || resume.UserID != userID {
+		return Resume{}, ErrNotFound
+	}
+	return resume, nil
+}
+
+// ListByUser returns the user's resumes, most recently updated first.
+func (r *MemoryRepo) ListByUser(ctx context.Context, userID string) ([]Resume, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	out := make([]Resume, 0)
+	for _, resume := range r.resumes {
+		if resume.UserID == userID {
+			out = append(out, resume)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
+
+// Update replaces a resume's title and content.
+func (r *MemoryRepo) Update(ctx context.Context, resume Resume) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.resumes[resume.ID]
+	if !ok || existing.UserID != resume.UserID {
+		return ErrNotFound
+	}
+	existing.Title = resume.Title
+	existing.Model = resume.Model
+	existing.UpdatedAt = resume.UpdatedAt
+	r.resumes[resume.ID] = existing
+	return nil
+}
+
+// SetDocument records the document last created from the resume.
+func (r *MemoryRepo) SetDocument(ctx context.Context, userID, resumeID, documentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.resumes[resumeID]
+	if !ok || existing.UserID != userID {
+		return ErrNotFound
+	}
+	existing.DocumentID = documentID
+	r.resumes[resumeID] = existing
+	return nil
+}
@@ -0,0 +1,196 @@
+package resumes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"resume-backend/internal/shared/fieldcrypt"
+	"resume-backend/resume/model"
+)
+
+// Codec names for the contact fields encrypted inside the model column.
+const (
+	emailField = "built_resumes.model.header.email"
+	phoneField = "built_resumes.model.header.phone"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+	// Codec encrypts the header email and phone inside the stored model; nil
+	// stores them in the clear.
+	Codec *fieldcrypt.Codec
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const resumeColumns = `id::text, user_id, title, model, document_id, created_at, updated_at`
+
+// Create inserts a resume.
+func (r *PGRepo) Create(ctx context.Context, resume Resume) error {
+	content, err := r.encodeModel(resume.Model)
+	if err != nil {
+		return err
+	}
+	const query = `
+INSERT INTO built_resumes (id, user_id, title, model, document_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = r.DB.ExecContext(ctx, query,
+		resume.ID,
+		resume.UserID,
+		resume.Title,
+		content,
+		resume.DocumentID,
+		resume.CreatedAt,
+		resume.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID returns one of the user's resumes.
+func (r *PGRepo) GetByID(ctx context.Context, userID, resumeID string) (Resume, error) {
+	const query = `SELECT ` + resumeColumns + ` FROM built_resumes WHERE user_id = $1 AND id::text = $2`
+	resume, err := r.scanResume(r.DB.QueryRowContext(ctx, query, userID, resumeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Resume{}, ErrNotFound
+	}
+	return resume, err
+}
+
+// ListByUser returns the user's resumes, most recently updated first.
+func (r *PGRepo) ListByUser(ctx context.Context, userID string) ([]Resume, error) {
+	const query = `SELECT ` + resumeColumns + ` FROM built_resumes WHERE user_id = $1 ORDER BY updated_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]Resume, 0)
+	for rows.Next() {
+		resume, err := r.scanResume(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resume)
+	}
+	return out, rows.Err()
+}
+
+// Update replaces a resume's title and content.
+func (r *PGRepo) Update(ctx context.Context, resume Resume) error {
+	content, err := r.encodeModel(resume.Model)
+	if err != nil {
+		return err
+	}
+	const query = `
+UPDATE built_resumes
+SET title = $3, model = $4, updated_at = $5
+WHERE user_id = $1 AND id::text = $2`
+	res, err := r.DB.ExecContext(ctx, query, resume.UserID, resume.ID, resume.Title, content, resume.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res)
+}
+
+// SetDocument records the document last created from the resume.
+func (r *PGRepo) SetDocument(ctx context.Context, userID, resumeID, documentID string) error {
+	const query = `UPDATE built_resumes SET document_id = $3 WHERE user_id = $1 AND id::text = $2`
+	res, err := r.DB.ExecContext(ctx, query, userID, resumeID, documentID)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res)
+}
+
+func expectOneRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *PGRepo) scanResume(row rowScanner) (Resume, error) {
+	var resume Resume
+	var content []byte
+	if err := row.Scan(
+		&resume.ID,
+		&resume.UserID,
+		&resume.Title,
+		&content,
+		&resume.DocumentID,
+		&resume.CreatedAt,
+		&resume.UpdatedAt,
+	); err != nil {
+		return Resume{}, err
+	}
+	if err := json.Unmarshal(content, &resume.Model); err != nil {
+		return Resume{}, err
+	}
+	if err := r.openContact(&resume.Model); err != nil {
+		return Resume{}, err
+	}
+	return resume, nil
+}
+
+// EncryptedColumns lists the columns the PII backfill rewrites.
+func (r *PGRepo) EncryptedColumns() []fieldcrypt.Column {
+	return []fieldcrypt.Column{{
+		Table:  "built_resumes",
+		Name:   "model",
+		Select: "model::text",
+		Assign: "$1::jsonb",
+		Rewrite: func(stored string) (string, bool, error) {
+			var content model.ResumeModel
+			if err := json.Unmarshal([]byte(stored), &content); err != nil {
+				return "", false, err
+			}
+			email, emailChanged, err := r.Codec.Rotate(emailField, content.Header.Email)
+			if err != nil {
+				return "", false, err
+			}
+			phone, phoneChanged, err := r.Codec.Rotate(phoneField, content.Header.Phone)
+			if err != nil {
+				return "", false, err
+			}
+			if !emailChanged && !phoneChanged {
+				return stored, false, nil
+			}
+			content.Header.Email, content.Header.Phone = email, phone
+			encoded, err := json.Marshal(content)
+			return string(encoded), err == nil, err
+		},
+	}}
+}
+
+// encodeModel marshals content with its contact fields sealed.
+func (r *PGRepo) encodeModel(content model.ResumeModel) ([]byte, error) {
+	var err error
+	if content.Header.Email, err = r.Codec.Encrypt(emailField, content.Header.Email); err != nil {
+		return nil, err
+	}
+	if content.Header.Phone, err = r.Codec.Encrypt(phoneField, content.Header.Phone); err != nil {
+		return nil, err
+	}
+	return json.Marshal(content)
+}
+
+// openContact decrypts the contact fields of a stored model in place.
+func (r *PGRepo) openContact(content *model.ResumeModel) error {
+	var err error
+	if content.Header.Email, err = r.Codec.Decrypt(emailField, content.Header.Email); err != nil {
+		return err
+	}
+	content.Header.Phone, err = r.Codec.Decrypt(phoneField, content.Header.Phone)
+	return err
+}
@@ -0,0 +1,180 @@
+package resumes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/documents"
+	"resume-backend/internal/extract"
+	"resume-backend/resume/model"
+	"resume-backend/resume/render"
+)
+
+const (
+	maxTitleLength = 120
+	// maxModelBytes caps the encoded resume content.
+	maxModelBytes = 256 << 10
+
+	docxMimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// DocumentCreator records a ready-to-analyze document from resume text. The
+// documents service implements it.
+type DocumentCreator interface {
+	CreateFromText(ctx context.Context, userID, text string) (documents.Document, bool, error)
+}
+
+// Input is the editable part of a resume.
+type Input struct {
+	Title string
+	Model model.ResumeModel
+}
+
+// Service manages resumes written in the builder.
+type Service struct {
+	Repo Repo
+	// Documents backs CreateDocument; when nil, built resumes cannot be analyzed.
+	Documents DocumentCreator
+	Now       func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, docs DocumentCreator) *Service {
+	return &Service{Repo: repo, Documents: docs}
+}
+
+// Create stores a new resume for the user.
+func (s *Service) Create(ctx context.Context, userID string, in Input) (Resume, error) {
+	if err := validateInput(&in); err != nil {
+		return Resume{}, err
+	}
+	now := s.now()
+	resume := Resume{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Title:     in.Title,
+		Model:     in.Model,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.Repo.Create(ctx, resume); err != nil {
+		return Resume{}, err
+	}
+	return resume, nil
+}
+
+// Get returns one of the user's resumes.
+func (s *Service) Get(ctx context.Context, userID, resumeID string) (Resume, error) {
+	return s.Repo.GetByID(ctx, userID, resumeID)
+}
+
+// List returns the user's resumes, most recently updated first.
+func (s *Service) List(ctx context.Context, userID string) ([]Resume, error) {
+	return s.Repo.ListByUser(ctx, userID)
+}
+
+// Update replaces the title and content of one of the user's resumes.
+func (s *Service) Update(ctx context.Context, userID, resumeID string, in Input) (Resume, error) {
+	if err := validateInput(&in); err != nil {
+		return Resume{}, err
+	}
+	resume, err := s.Repo.GetByID(ctx, userID, resumeID)
+	if err != nil {
+		return Resume{}, err
+	}
+	resume.Title = in.Title
+	resume.Model = in.Model
+	resume.UpdatedAt = s.now()
+	if err := s.Repo.Update(ctx, resume); err != nil {
+		return Resume{}, err
+	}
+	return resume, nil
+}
+
+// Render renders one of the user's resumes to DOCX with its section headings
+// in locale.
+func (s *Service) Render(ctx context.Context, userID, resumeID, locale string) (Resume, []byte, []render.ContentWarning, error) {
+	resume, err := s.Repo.GetByID(ctx, userID, resumeID)
+	if err != nil {
+		return Resume{}, nil, nil, err
+	}
+	data, warnings, err := renderDocx(resume.Model, locale)
+	if err != nil {
+		return Resume{}, nil, nil, err
+	}
+	return resume, data, warnings, nil
+}
+
+// CreateDocument renders one of the user's resumes and records the text of the
+// rendered document as a document ready to analyze. An unchanged resume maps
+// to the same document; the result reports when it already existed.
+func (s *Service) CreateDocument(ctx context.Context, userID, resumeID string) (documents.Document, bool, error) {
+	if s.Documents == nil {
+		return documents.Document{}, false, errors.New("resume documents are not configured")
+	}
+	resume, data, _, err := s.Render(ctx, userID, resumeID, "")
+	if err != nil {
+		return documents.Document{}, false, err
+	}
+	text, err := extract.ExtractTextFromBytes(ctx, data, docxMimeType, "resume.docx")
+	if err != nil {
+		return documents.Document{}, false, fmt.Errorf("read rendered resume: %w", err)
+	}
+	doc, linked, err := s.Documents.CreateFromText(ctx, userID, text)
+	if err != nil {
+		if errors.Is(err, documents.ErrInvalidInput) {
+			return documents.Document{}, false, fmt.Errorf("%w: the rendered resume must have %d to %d characters of text to analyze", ErrIncomplete, documents.MinInlineTextChars, documents.MaxInlineTextChars)
+		}
+		return documents.Document{}, false, err
+	}
+	if resume.DocumentID != doc.ID {
+		if err := s.Repo.SetDocument(ctx, userID, resumeID, doc.ID); err != nil {
+			return documents.Document{}, false, err
+		}
+	}
+	return doc, linked, nil
+}
+
+// renderDocx checks the resume has what a document needs before rendering, so
+// a draft missing them is reported as incomplete rather than failing to render.
+func renderDocx(content model.ResumeModel, locale string) ([]byte, []render.ContentWarning, error) {
+	if strings.TrimSpace(content.Header.Email) == "" && strings.TrimSpace(content.Header.Phone) == "" {
+		return nil, nil, fmt.Errorf("%w: an email or phone number is required", ErrIncomplete)
+	}
+	data, warnings, err := render.RenderResumeWithReport(content, render.RenderOptions{Locale: locale})
+	if errors.Is(err, render.ErrUnknownLocale) {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return data, warnings, err
+}
+
+func validateInput(in *Input) error {
+	in.Title = strings.TrimSpace(in.Title)
+	if len(in.Title) > maxTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", ErrInvalidInput, maxTitleLength)
+	}
+	if err := in.Model.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	encoded, err := json.Marshal(in.Model)
+	if err != nil {
+		return err
+	}
+	if len(encoded) > maxModelBytes {
+		return fmt.Errorf("%w: resume must be at most %d KB", ErrInvalidInput, maxModelBytes>>10)
+	}
+	return nil
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,233 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/generatedresumes"
+	"resume-backend/internal/shared/storage/db"
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/internal/usage"
+)
+
+// DefaultBatchSize bounds how many documents a single cleanup pass purges.
+const DefaultBatchSize = 100
+
+// ErrUnsupported is returned when a dependency cannot list, delete or purge guest data.
+var ErrUnsupported = errors.New("guest retention not supported by configured repos")
+
+type guestDocumentPurger interface {
+	ListExpiredGuest(ctx context.Context, cutoff time.Time, limit int) ([]documents.Document, error)
+	SoftDelete(ctx context.Context, userId, documentID string, deletedAt time.Time) error
+}
+
+type documentAnalysisDeleter interface {
+	SoftDeleteByDocument(ctx context.Context, userID, documentID string, deletedAt time.Time) (int, error)
+}
+
+type objectDeleter interface {
+	Delete(ctx context.Context, storageKey string) error
+}
+
+// Service purges guest-owned documents and analyses once they outlive the TTL.
+type Service struct {
+	DocRepo      documents.DocumentsRepo
+	AnalysisRepo analyses.Repo
+	Store        object.ObjectStore
+	// GeneratedResumes and Applies, when set, also purge the resumes and apply
+	// output made from each document, which hold the same personal data.
+	GeneratedResumes generatedresumes.Repo
+	Applies          *usage.Service
+	// UnitOfWork deletes each document's rows together; nil deletes them one
+	// after the other.
+	UnitOfWork db.UnitOfWork
+	TTL        time.Duration
+	Now        func() time.Time
+}
+
+// Result summarizes a cleanup pass.
+type Result struct {
+	Documents        int `json:"documents"`
+	Analyses         int `json:"analyses"`
+	GeneratedResumes int `json:"generatedResumes"`
+	ApplyRecords     int `json:"applyRecords"`
+	Objects          int `json:"objects"`
+}
+
+// NewService constructs a Service. A zero TTL disables cleanup.
+func NewService(docRepo documents.DocumentsRepo, analysisRepo analyses.Repo, store object.ObjectStore, ttl time.Duration) *Service {
+	return &Service{DocRepo: docRepo, AnalysisRepo: analysisRepo, Store: store, TTL: ttl}
+}
+
+// Enabled reports whether guest data expires.
+func (s *Service) Enabled() bool {
+	return s != nil && s.TTL > 0
+}
+
+// PurgeExpiredGuests deletes stored objects for expired guest documents and the
+// resumes and apply output made from them, then the generated resume and apply
+// rows, then soft-deletes their analyses and the documents themselves. Objects go first so a failed pass is
+// retried on the next run instead of leaking files behind a deleted row.
+func (s *Service) PurgeExpiredGuests(ctx context.Context, limit int) (Result, error) {
+	if !s.Enabled() {
+		return Result{}, nil
+	}
+	docPurger, ok := s.DocRepo.(guestDocumentPurger)
+	if !ok {
+		return Result{}, ErrUnsupported
+	}
+	analysisDeleter, ok := s.AnalysisRepo.(documentAnalysisDeleter)
+	if !ok {
+		return Result{}, ErrUnsupported
+	}
+	deleter, _ := s.Store.(objectDeleter)
+	if limit <= 0 {
+		limit = DefaultBatchSize
+	}
+
+	now := s.now()
+	expired, err := docPurger.ListExpiredGuest(ctx, now.Add(-s.TTL), limit)
+	if err != nil {
+		return Result{}, fmt.Errorf("list expired guest documents: %w", err)
+	}
+
+	var res Result
+	for _, doc := range expired {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		keys, err := s.objectKeys(ctx, doc)
+		if err != nil {
+			return res, fmt.Errorf("list objects document=%s: %w", doc.ID, err)
+		}
+		if deleter != nil {
+			purged, err := purgeObjects(ctx, deleter, keys)
+			res.Objects += purged
+			if err != nil {
+				return res, fmt.Errorf("purge objects document=%s: %w", doc.ID, err)
+			}
+		}
+		rows, err := s.purgeRows(ctx, docPurger, analysisDeleter, doc, now)
+		if err != nil {
+			return res, err
+		}
+		res.GeneratedResumes += rows.GeneratedResumes
+		res.ApplyRecords += rows.ApplyRecords
+		res.Analyses += rows.Analyses
+		res.Documents++
+	}
+
+	if res.Documents > 0 {
+		telemetry.Info("retention.guest_purged", map[string]any{
+			"documents":         res.Documents,
+			"analyses":          res.Analyses,
+			"generated_resumes": res.GeneratedResumes,
+			"apply_records":     res.ApplyRecords,
+			"objects":           res.Objects,
+		})
+	}
+	return res, nil
+}
+
+// Run purges expired guest data every interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	if !s.Enabled() || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.PurgeExpiredGuests(ctx, DefaultBatchSize); err != nil && ctx.Err() == nil {
+			telemetry.Error("retention.guest_purge_failed", map[string]any{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeRows deletes the rows of one expired document in a single unit of work,
+// so a failure never leaves analyses without their document or the reverse.
+func (s *Service) purgeRows(ctx context.Context, docPurger guestDocumentPurger, analysisDeleter documentAnalysisDeleter, doc documents.Document, now time.Time) (Result, error) {
+	var rows Result
+	err := db.Run(ctx, s.UnitOfWork, func(ctx context.Context) error {
+		rows = Result{}
+		if s.GeneratedResumes != nil {
+			deleted, err := s.GeneratedResumes.DeleteByDocument(ctx, doc.UserID, doc.ID)
+			if err != nil {
+				return fmt.Errorf("delete generated resumes document=%s: %w", doc.ID, err)
+			}
+			rows.GeneratedResumes = deleted
+		}
+		if s.Applies != nil {
+			deleted, err := s.Applies.DeleteApplyData(ctx, doc.UserID, doc.ID)
+			if err != nil {
+				return fmt.Errorf("delete apply data document=%s: %w", doc.ID, err)
+			}
+			rows.ApplyRecords = deleted
+		}
+		deleted, err := analysisDeleter.SoftDeleteByDocument(ctx, doc.UserID, doc.ID, now)
+		if err != nil {
+			return fmt.Errorf("delete analyses document=%s: %w", doc.ID, err)
+		}
+		rows.Analyses = deleted
+		if err := docPurger.SoftDelete(ctx, doc.UserID, doc.ID, now); err != nil {
+			return fmt.Errorf("delete document=%s: %w", doc.ID, err)
+		}
+		return nil
+	})
+	return rows, err
+}
+
+// objectKeys lists every stored object made from doc: the upload, its extracted
+// text, generated resumes and rendered apply versions.
+func (s *Service) objectKeys(ctx context.Context, doc documents.Document) ([]string, error) {
+	keys := []string{doc.StorageKey, doc.ExtractedTextKey}
+	if s.GeneratedResumes != nil {
+		resumes, err := s.GeneratedResumes.ListByDocument(ctx, doc.UserID, doc.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, resume := range resumes {
+			keys = append(keys, resume.StorageKey, resume.ModelKey)
+		}
+	}
+	if s.Applies != nil {
+		versions, err := s.Applies.ListDocumentVersions(ctx, doc.UserID, doc.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			keys = append(keys, version.StorageKey)
+		}
+	}
+	return keys, nil
+}
+
+func purgeObjects(ctx context.Context, deleter objectDeleter, keys []string) (int, error) {
+	purged := 0
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := deleter.Delete(ctx, key); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
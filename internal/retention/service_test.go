@@ -0,0 +1,140 @@
+package retention
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/generatedresumes"
+	"resume-backend/internal/shared/storage/object/local"
+	"resume-backend/internal/usage"
+)
+
+func TestPurgeExpiredGuestsDeletesRowsAndObjects(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := local.New(dir)
+	docRepo := documents.NewMemoryRepo()
+	analysisRepo := analyses.NewMemoryRepo()
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	seed := func(userID, docID string, createdAt time.Time) string {
+		t.Helper()
+		key, _, _, err := store.Save(ctx, userID, "resume.txt", strings.NewReader("resume"))
+		if err != nil {
+			t.Fatalf("save object: %v", err)
+		}
+		if err := docRepo.Create(ctx, documents.Document{ID: docID, UserID: userID, StorageKey: key, CreatedAt: createdAt}); err != nil {
+			t.Fatalf("create document: %v", err)
+		}
+		if err := analysisRepo.Create(ctx, analyses.Analysis{ID: "analysis-" + docID, DocumentID: docID, UserID: userID, Status: analyses.StatusCompleted, CreatedAt: createdAt}); err != nil {
+			t.Fatalf("create analysis: %v", err)
+		}
+		return key
+	}
+
+	generatedRepo := generatedresumes.NewMemoryRepo()
+	usageSvc := usage.NewService()
+	// seedOutput stores a generated resume and an apply run's rendered version
+	// and rewrites for the document, returning the two object keys.
+	seedOutput := func(userID, docID string) []string {
+		t.Helper()
+		resumeKey, _, _, err := store.Save(ctx, userID, "generated.docx", strings.NewReader("generated"))
+		if err != nil {
+			t.Fatalf("save generated resume: %v", err)
+		}
+		if err := generatedRepo.Create(ctx, generatedresumes.GeneratedResume{ID: "generated-" + docID, UserID: userID, DocumentID: docID, StorageKey: resumeKey}); err != nil {
+			t.Fatalf("create generated resume: %v", err)
+		}
+		versionKey, _, _, err := store.Save(ctx, userID, "applied.docx", strings.NewReader("applied"))
+		if err != nil {
+			t.Fatalf("save version: %v", err)
+		}
+		runID := "run-" + docID
+		if err := usageSvc.CreateApplyRun(ctx, usage.ApplyRun{ID: runID, UserID: userID, AnalysisID: "analysis-" + docID}); err != nil {
+			t.Fatalf("create apply run: %v", err)
+		}
+		if err := usageSvc.CreateDocumentVersion(ctx, usage.DocumentVersion{ID: "version-" + docID, DocumentID: docID, UserID: userID, ApplyRunID: runID, StorageKey: versionKey}); err != nil {
+			t.Fatalf("create version: %v", err)
+		}
+		if err := usageSvc.RecordAppliedRewrites(ctx, []usage.AppliedRewrite{{ID: "rewrite-" + docID, DocumentID: docID, UserID: userID, ApplyRunID: runID, Before: "Led team", After: "Led a team of 5"}}); err != nil {
+			t.Fatalf("record rewrites: %v", err)
+		}
+		return []string{resumeKey, versionKey}
+	}
+
+	expiredKey := seed("guest:old", "doc-old", now.Add(-15*24*time.Hour))
+	freshKey := seed("guest:new", "doc-new", now.Add(-time.Hour))
+	userKey := seed("user-1", "doc-user", now.Add(-60*24*time.Hour))
+	expiredOutput := seedOutput("guest:old", "doc-old")
+	freshOutput := seedOutput("guest:new", "doc-new")
+
+	svc := NewService(docRepo, analysisRepo, store, 14*24*time.Hour)
+	svc.GeneratedResumes = generatedRepo
+	svc.Applies = usageSvc
+	svc.Now = func() time.Time { return now }
+
+	res, err := svc.PurgeExpiredGuests(ctx, 0)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	// The apply records are the version, the rewrite and the run.
+	if res != (Result{Documents: 1, Analyses: 1, GeneratedResumes: 1, ApplyRecords: 3, Objects: 3}) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	if _, err := docRepo.GetByID(ctx, "guest:old", "doc-old"); err != documents.ErrNotFound {
+		t.Fatalf("expected expired guest document to be deleted, got %v", err)
+	}
+	if _, err := analysisRepo.GetByID(ctx, "analysis-doc-old"); err != analyses.ErrNotFound {
+		t.Fatalf("expected expired guest analysis to be deleted, got %v", err)
+	}
+	for _, key := range append([]string{expiredKey}, expiredOutput...) {
+		if _, err := os.Stat(filepath.Join(dir, key)); !os.IsNotExist(err) {
+			t.Fatalf("expected expired object %s to be purged, got %v", key, err)
+		}
+	}
+	if _, err := generatedRepo.GetByID(ctx, "guest:old", "generated-doc-old"); err != generatedresumes.ErrNotFound {
+		t.Fatalf("expected expired generated resume to be deleted, got %v", err)
+	}
+	if versions, _ := usageSvc.ListDocumentVersions(ctx, "guest:old", "doc-old"); len(versions) != 0 {
+		t.Fatalf("expected expired versions to be deleted, got %+v", versions)
+	}
+	if rewrites, _ := usageSvc.ListAppliedRewrites(ctx, "guest:old", "doc-old"); len(rewrites) != 0 {
+		t.Fatalf("expected expired rewrites to be deleted, got %+v", rewrites)
+	}
+	if _, err := usageSvc.GetApplyRun(ctx, "guest:old", "run-doc-old"); err != usage.ErrApplyRunNotFound {
+		t.Fatalf("expected expired apply run to be deleted, got %v", err)
+	}
+	if _, err := generatedRepo.GetByID(ctx, "guest:new", "generated-doc-new"); err != nil {
+		t.Fatalf("expected fresh generated resume to be kept: %v", err)
+	}
+	for _, key := range append([]string{freshKey, userKey}, freshOutput...) {
+		if _, err := os.Stat(filepath.Join(dir, key)); err != nil {
+			t.Fatalf("expected object %s to be kept: %v", key, err)
+		}
+	}
+	if _, err := docRepo.GetByID(ctx, "user-1", "doc-user"); err != nil {
+		t.Fatalf("expected signed-in user's document to be kept: %v", err)
+	}
+
+	again, err := svc.PurgeExpiredGuests(ctx, 0)
+	if err != nil || again != (Result{}) {
+		t.Fatalf("expected idempotent second pass, got %+v err=%v", again, err)
+	}
+}
+
+func TestPurgeExpiredGuestsDisabled(t *testing.T) {
+	svc := NewService(documents.NewMemoryRepo(), analyses.NewMemoryRepo(), nil, 0)
+	if svc.Enabled() {
+		t.Fatalf("expected zero TTL to disable cleanup")
+	}
+	if res, err := svc.PurgeExpiredGuests(context.Background(), 0); err != nil || res != (Result{}) {
+		t.Fatalf("expected no-op, got %+v err=%v", res, err)
+	}
+}
@@ -0,0 +1,20 @@
+package rollout
+
+import "errors"
+
+var (
+	// ErrNotFound indicates no rollout exists.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrActiveRollout indicates another rollout is still running or paused.
+	ErrActiveRollout = errors.New("a rollout is already active")
+
+	// ErrInvalidTransition indicates the rollout cannot move to the requested state.
+	ErrInvalidTransition = errors.New("invalid rollout transition")
+
+	// ErrConflict indicates the rollout changed concurrently.
+	ErrConflict = errors.New("rollout changed concurrently")
+)
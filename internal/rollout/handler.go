@@ -0,0 +1,168 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the prompt rollout admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches rollout routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/prompt-rollout", h.current)
+	rg.GET("/prompt-rollout/history", h.history)
+	rg.POST("/prompt-rollout", h.start)
+	rg.POST("/prompt-rollout/pause", h.action(h.Svc.Pause))
+	rg.POST("/prompt-rollout/resume", h.action(h.Svc.Resume))
+	rg.POST("/prompt-rollout/advance", h.action(h.Svc.Advance))
+	rg.POST("/prompt-rollout/rollback", h.rollback)
+}
+
+// view adds derived health figures to a rollout.
+type view struct {
+	Rollout
+	Percent int        `json:"percent"`
+	Health  healthView `json:"health"`
+}
+
+type healthView struct {
+	CandidateSchemaFailureRate float64 `json:"candidateSchemaFailureRate"`
+	CandidateSanitizationRate  float64 `json:"candidateSanitizationRate"`
+	CandidateMeanDurationMs    float64 `json:"candidateMeanDurationMs"`
+	BaselineSchemaFailureRate  float64 `json:"baselineSchemaFailureRate"`
+	BaselineSanitizationRate   float64 `json:"baselineSanitizationRate"`
+	BaselineMeanDurationMs     float64 `json:"baselineMeanDurationMs"`
+	MinSamples                 int     `json:"minSamples"`
+	MaxSchemaFailureRate       float64 `json:"maxSchemaFailureRate"`
+	MaxSanitizationRate        float64 `json:"maxSanitizationRate"`
+	MaxDurationRatio           float64 `json:"maxDurationRatio"`
+	MinStageDurationSeconds    int     `json:"minStageDurationSeconds"`
+}
+
+func newView(ro Rollout, p Policy) view {
+	return view{
+		Rollout: ro,
+		Percent: ro.Percent(),
+		Health: healthView{
+			CandidateSchemaFailureRate: ro.Candidate.SchemaFailureRate(),
+			CandidateSanitizationRate:  ro.Candidate.SanitizationRate(),
+			CandidateMeanDurationMs:    ro.Candidate.MeanDurationMs(),
+			BaselineSchemaFailureRate:  ro.Baseline.SchemaFailureRate(),
+			BaselineSanitizationRate:   ro.Baseline.SanitizationRate(),
+			BaselineMeanDurationMs:     ro.Baseline.MeanDurationMs(),
+			MinSamples:                 p.MinSamples,
+			MaxSchemaFailureRate:       p.MaxSchemaFailureRate,
+			MaxSanitizationRate:        p.MaxSanitizationRate,
+			MaxDurationRatio:           p.MaxDurationRatio,
+			MinStageDurationSeconds:    int(p.MinStageDuration / time.Second),
+		},
+	}
+}
+
+func (h *Handler) current(c *gin.Context) {
+	ro, err := h.Svc.Current(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, newView(ro, h.Svc.policy()))
+}
+
+func (h *Handler) history(c *gin.Context) {
+	items, err := h.Svc.List(c.Request.Context(), 20)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	out := make([]view, 0, len(items))
+	for _, ro := range items {
+		out = append(out, newView(ro, h.Svc.policy()))
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"rollouts": out})
+}
+
+type startRequest struct {
+	BaselineVersion  string `json:"baselineVersion"`
+	CandidateVersion string `json:"candidateVersion"`
+}
+
+func (h *Handler) start(c *gin.Context) {
+	var req startRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	if req.CandidateVersion == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "candidateVersion is required", []map[string]string{
+			{"field": "candidateVersion", "issue": "required"},
+		})
+		return
+	}
+	ro, err := h.Svc.Start(c.Request.Context(), req.BaselineVersion, req.CandidateVersion)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusCreated, newView(ro, h.Svc.policy()))
+}
+
+type rollbackRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *Handler) rollback(c *gin.Context) {
+	var req rollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	ro, err := h.Svc.Rollback(c.Request.Context(), req.Reason)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	respond.JSON(c, http.StatusOK, newView(ro, h.Svc.policy()))
+}
+
+func (h *Handler) action(fn func(ctx context.Context) (Rollout, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ro, err := fn(c.Request.Context())
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		respond.JSON(c, http.StatusOK, newView(ro, h.Svc.policy()))
+	}
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrNotFound):
+		respond.Error(c, http.StatusNotFound, "not_found", "no prompt rollout found", nil)
+	case errors.Is(err, ErrActiveRollout):
+		respond.Error(c, http.StatusConflict, "rollout_active", "a prompt rollout is already active", nil)
+	case errors.Is(err, ErrInvalidTransition):
+		respond.Error(c, http.StatusConflict, "invalid_transition", "the rollout cannot make that transition in its current state", nil)
+	case errors.Is(err, ErrConflict):
+		respond.Error(c, http.StatusConflict, "conflict", "the rollout changed; reload and retry", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to process prompt rollout", nil)
+	}
+}
@@ -0,0 +1,149 @@
+package rollout
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of a rollout.
+type Status string
+
+const (
+	StatusRunning    Status = "running"
+	StatusPaused     Status = "paused"
+	StatusCompleted  Status = "completed"
+	StatusRolledBack Status = "rolled_back"
+)
+
+// Active reports whether the rollout still splits traffic.
+func (s Status) Active() bool {
+	return s == StatusRunning || s == StatusPaused
+}
+
+// DefaultStages are the percentages of new analyses sent to the candidate
+// version unless SetStages replaced them.
+var DefaultStages = []int{1, 10, 50, 100}
+
+var (
+	stagesMu sync.RWMutex
+	stages   = DefaultStages
+)
+
+// Stages returns the current stage percentages.
+func Stages() []int {
+	stagesMu.RLock()
+	defer stagesMu.RUnlock()
+	return stages
+}
+
+// SetStages replaces the stage percentages. They must increase strictly, lie in
+// 1-100 and end at 100. Nil restores DefaultStages. A running rollout keeps its
+// stage index, so it moves to the new percentage of that stage.
+func SetStages(percentages []int) error {
+	if percentages == nil {
+		percentages = DefaultStages
+	}
+	if err := ValidateStages(percentages); err != nil {
+		return err
+	}
+	copied := append([]int(nil), percentages...)
+	stagesMu.Lock()
+	stages = copied
+	stagesMu.Unlock()
+	return nil
+}
+
+// ValidateStages checks stage percentages without applying them.
+func ValidateStages(percentages []int) error {
+	if len(percentages) == 0 || percentages[len(percentages)-1] != 100 {
+		return fmt.Errorf("rollout stages must end at 100")
+	}
+	prev := 0
+	for _, p := range percentages {
+		if p <= prev || p > 100 {
+			return fmt.Errorf("rollout stages must increase within 1-100, got %v", percentages)
+		}
+		prev = p
+	}
+	return nil
+}
+
+// Arm identifies which side of the rollout an analysis ran on.
+type Arm string
+
+const (
+	ArmBaseline  Arm = "baseline"
+	ArmCandidate Arm = "candidate"
+)
+
+// ArmStats counts finished analyses for one prompt version.
+type ArmStats struct {
+	Finished        int     `json:"finished"`
+	Failed          int     `json:"failed"`
+	SchemaFailures  int     `json:"schemaFailures"`
+	Sanitized       int     `json:"sanitized"`
+	DurationMsTotal float64 `json:"durationMsTotal"`
+}
+
+// Completed is the number of analyses that produced a result.
+func (a ArmStats) Completed() int {
+	return a.Finished - a.Failed
+}
+
+// SchemaFailureRate is the share of finished analyses that failed schema validation.
+func (a ArmStats) SchemaFailureRate() float64 {
+	return ratio(float64(a.SchemaFailures), float64(a.Finished))
+}
+
+// SanitizationRate is the share of completed analyses that needed the sanitization fallback.
+func (a ArmStats) SanitizationRate() float64 {
+	return ratio(float64(a.Sanitized), float64(a.Completed()))
+}
+
+// MeanDurationMs is the average processing time across finished analyses.
+func (a ArmStats) MeanDurationMs() float64 {
+	return ratio(a.DurationMsTotal, float64(a.Finished))
+}
+
+func ratio(n, d float64) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return n / d
+}
+
+// Rollout shifts new analyses from a baseline prompt version to a candidate.
+// Candidate stats cover the current stage only; baseline stats accumulate over
+// the whole rollout so there is still a comparison point at 100%.
+type Rollout struct {
+	ID               string     `json:"id"`
+	BaselineVersion  string     `json:"baselineVersion"`
+	CandidateVersion string     `json:"candidateVersion"`
+	Status           Status     `json:"status"`
+	StageIndex       int        `json:"stageIndex"`
+	StageStartedAt   time.Time  `json:"stageStartedAt"`
+	Candidate        ArmStats   `json:"candidate"`
+	Baseline         ArmStats   `json:"baseline"`
+	Reason           string     `json:"reason,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	EndedAt          *time.Time `json:"endedAt,omitempty"`
+}
+
+// Percent is the share of new analyses currently assigned to the candidate.
+func (r Rollout) Percent() int {
+	current := Stages()
+	switch {
+	case r.Status == StatusCompleted:
+		return 100
+	case r.Status == StatusRolledBack:
+		return 0
+	case r.StageIndex < 0:
+		return 0
+	case r.StageIndex >= len(current):
+		return 100
+	default:
+		return current[r.StageIndex]
+	}
+}
@@ -0,0 +1,37 @@
+package rollout
+
+import (
+	"context"
+
+	"resume-backend/internal/analyses"
+)
+
+// Repo persists rollouts. State lives in the database so the API and worker
+// processes see the same stage.
+type Repo interface {
+	// Create inserts a rollout, failing with ErrActiveRollout if one is active.
+	Create(ctx context.Context, r Rollout) error
+	// Latest returns the most recently created rollout.
+	Latest(ctx context.Context) (Rollout, error)
+	List(ctx context.Context, limit int) ([]Rollout, error)
+	// Update replaces the rollout's state if it is still in the expected status and
+	// stage, returning ErrConflict otherwise.
+	Update(ctx context.Context, r Rollout, expectedStatus Status, expectedStage int) error
+	// RecordOutcome adds one outcome to an active rollout's counters and returns the
+	// updated rollout.
+	RecordOutcome(ctx context.Context, id string, arm Arm, outcome analyses.PromptOutcome) (Rollout, error)
+}
+
+func applyOutcome(stats *ArmStats, outcome analyses.PromptOutcome) {
+	stats.Finished++
+	if outcome.Failed {
+		stats.Failed++
+	}
+	if outcome.SchemaFailure {
+		stats.SchemaFailures++
+	}
+	if outcome.Sanitized {
+		stats.Sanitized++
+	}
+	stats.DurationMsTotal += outcome.DurationMs
+}
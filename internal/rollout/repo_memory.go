@@ -0,0 +1,116 @@
+package rollout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"resume-backend/internal/analyses"
+)
+
+// MemoryRepo stores rollouts in memory.
+type MemoryRepo struct {
+	mu       sync.RWMutex
+	rollouts []Rollout
+}
+
+// NewMemoryRepo constructs an empty MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Create inserts a rollout.
+func (r *MemoryRepo) Create(ctx context.Context, ro Rollout) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.rollouts {
+		if existing.Status.Active() {
+			return ErrActiveRollout
+		}
+	}
+	r.rollouts = append(r.rollouts, ro)
+	return nil
+}
+
+// Latest returns the most recently created rollout.
+func (r *MemoryRepo) Latest(ctx context.Context) (Rollout, error) {
+	if err := ctx.Err(); err != nil {
+		return Rollout{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.rollouts) == 0 {
+		return Rollout{}, ErrNotFound
+	}
+	return r.rollouts[len(r.rollouts)-1], nil
+}
+
+// List returns rollouts newest first.
+func (r *MemoryRepo) List(ctx context.Context, limit int) ([]Rollout, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Rollout, 0, len(r.rollouts))
+	for i := len(r.rollouts) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, r.rollouts[i])
+	}
+	return out, nil
+}
+
+// Update replaces a rollout if it is still in the expected status and stage.
+func (r *MemoryRepo) Update(ctx context.Context, ro Rollout, expectedStatus Status, expectedStage int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ro.ID)
+	if i < 0 {
+		return ErrNotFound
+	}
+	if r.rollouts[i].Status != expectedStatus || r.rollouts[i].StageIndex != expectedStage {
+		return ErrConflict
+	}
+	r.rollouts[i] = ro
+	return nil
+}
+
+// RecordOutcome adds an outcome to an active rollout.
+func (r *MemoryRepo) RecordOutcome(ctx context.Context, id string, arm Arm, outcome analyses.PromptOutcome) (Rollout, error) {
+	if err := ctx.Err(); err != nil {
+		return Rollout{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(id)
+	if i < 0 || !r.rollouts[i].Status.Active() {
+		return Rollout{}, ErrNotFound
+	}
+	ro := &r.rollouts[i]
+	if arm == ArmCandidate {
+		applyOutcome(&ro.Candidate, outcome)
+	} else {
+		applyOutcome(&ro.Baseline, outcome)
+	}
+	ro.UpdatedAt = time.Now().UTC()
+	return *ro, nil
+}
+
+func (r *MemoryRepo) indexOf(id string) int {
+	for i := range r.rollouts {
+		if r.rollouts[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
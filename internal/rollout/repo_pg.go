@@ -0,0 +1,212 @@
+package rollout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"resume-backend/internal/analyses"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const rolloutColumns = `id, baseline_version, candidate_version, status, stage_index, stage_started_at,
+    candidate_finished, candidate_failed, candidate_schema_failures, candidate_sanitized, candidate_duration_ms,
+    baseline_finished, baseline_failed, baseline_schema_failures, baseline_sanitized, baseline_duration_ms,
+    reason, created_at, updated_at, ended_at`
+
+// Create inserts a rollout unless another one is active.
+func (r *PGRepo) Create(ctx context.Context, ro Rollout) error {
+	const query = `
+INSERT INTO prompt_rollouts (` + rolloutColumns + `)
+SELECT $1, $2, $3, $4, $5, $6, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, $7, $8, $9, NULL
+WHERE NOT EXISTS (
+    SELECT 1 FROM prompt_rollouts WHERE status IN ('running', 'paused')
+)`
+	res, err := r.DB.ExecContext(ctx, query,
+		ro.ID,
+		ro.BaselineVersion,
+		ro.CandidateVersion,
+		string(ro.Status),
+		ro.StageIndex,
+		ro.StageStartedAt,
+		ro.Reason,
+		ro.CreatedAt,
+		ro.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if inserted, _ := res.RowsAffected(); inserted == 0 {
+		return ErrActiveRollout
+	}
+	return nil
+}
+
+// Latest returns the most recently created rollout.
+func (r *PGRepo) Latest(ctx context.Context) (Rollout, error) {
+	const query = `SELECT ` + rolloutColumns + ` FROM prompt_rollouts ORDER BY created_at DESC LIMIT 1`
+	ro, err := scanRollout(r.DB.QueryRowContext(ctx, query))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Rollout{}, ErrNotFound
+	}
+	return ro, err
+}
+
+// List returns rollouts newest first.
+func (r *PGRepo) List(ctx context.Context, limit int) ([]Rollout, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	const query = `SELECT ` + rolloutColumns + ` FROM prompt_rollouts ORDER BY created_at DESC LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rollout
+	for rows.Next() {
+		ro, err := scanRollout(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ro)
+	}
+	return out, rows.Err()
+}
+
+// Update replaces the rollout state guarded by the expected status and stage.
+func (r *PGRepo) Update(ctx context.Context, ro Rollout, expectedStatus Status, expectedStage int) error {
+	const query = `
+UPDATE prompt_rollouts
+SET status = $1,
+    stage_index = $2,
+    stage_started_at = $3,
+    candidate_finished = $4,
+    candidate_failed = $5,
+    candidate_schema_failures = $6,
+    candidate_sanitized = $7,
+    candidate_duration_ms = $8,
+    reason = $9,
+    updated_at = $10,
+    ended_at = $11
+WHERE id = $12 AND status = $13 AND stage_index = $14`
+	var endedAt sql.NullTime
+	if ro.EndedAt != nil {
+		endedAt = sql.NullTime{Time: *ro.EndedAt, Valid: true}
+	}
+	res, err := r.DB.ExecContext(ctx, query,
+		string(ro.Status),
+		ro.StageIndex,
+		ro.StageStartedAt,
+		ro.Candidate.Finished,
+		ro.Candidate.Failed,
+		ro.Candidate.SchemaFailures,
+		ro.Candidate.Sanitized,
+		ro.Candidate.DurationMsTotal,
+		ro.Reason,
+		ro.UpdatedAt,
+		endedAt,
+		ro.ID,
+		string(expectedStatus),
+		expectedStage,
+	)
+	if err != nil {
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// RecordOutcome increments the arm's counters in a single statement so concurrent
+// workers do not lose updates.
+func (r *PGRepo) RecordOutcome(ctx context.Context, id string, arm Arm, outcome analyses.PromptOutcome) (Rollout, error) {
+	query := `
+UPDATE prompt_rollouts
+SET candidate_finished = candidate_finished + 1,
+    candidate_failed = candidate_failed + $1,
+    candidate_schema_failures = candidate_schema_failures + $2,
+    candidate_sanitized = candidate_sanitized + $3,
+    candidate_duration_ms = candidate_duration_ms + $4,
+    updated_at = $5
+WHERE id = $6 AND status IN ('running', 'paused')
+RETURNING ` + rolloutColumns
+	if arm == ArmBaseline {
+		query = `
+UPDATE prompt_rollouts
+SET baseline_finished = baseline_finished + 1,
+    baseline_failed = baseline_failed + $1,
+    baseline_schema_failures = baseline_schema_failures + $2,
+    baseline_sanitized = baseline_sanitized + $3,
+    baseline_duration_ms = baseline_duration_ms + $4,
+    updated_at = $5
+WHERE id = $6 AND status IN ('running', 'paused')
+RETURNING ` + rolloutColumns
+	}
+	ro, err := scanRollout(r.DB.QueryRowContext(ctx, query,
+		boolInt(outcome.Failed),
+		boolInt(outcome.SchemaFailure),
+		boolInt(outcome.Sanitized),
+		outcome.DurationMs,
+		time.Now().UTC(),
+		id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Rollout{}, ErrNotFound
+	}
+	return ro, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRollout(row rowScanner) (Rollout, error) {
+	var ro Rollout
+	var status string
+	var endedAt sql.NullTime
+	if err := row.Scan(
+		&ro.ID,
+		&ro.BaselineVersion,
+		&ro.CandidateVersion,
+		&status,
+		&ro.StageIndex,
+		&ro.StageStartedAt,
+		&ro.Candidate.Finished,
+		&ro.Candidate.Failed,
+		&ro.Candidate.SchemaFailures,
+		&ro.Candidate.Sanitized,
+		&ro.Candidate.DurationMsTotal,
+		&ro.Baseline.Finished,
+		&ro.Baseline.Failed,
+		&ro.Baseline.SchemaFailures,
+		&ro.Baseline.Sanitized,
+		&ro.Baseline.DurationMsTotal,
+		&ro.Reason,
+		&ro.CreatedAt,
+		&ro.UpdatedAt,
+		&endedAt,
+	); err != nil {
+		return Rollout{}, err
+	}
+	ro.Status = Status(status)
+	if endedAt.Valid {
+		ro.EndedAt = &endedAt.Time
+	}
+	return ro, nil
+}
+
+func boolInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,386 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/llm"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Policy holds the health thresholds that drive automatic promotion and rollback.
+type Policy struct {
+	// MinSamples is how many candidate analyses a stage needs before it is judged.
+	MinSamples int
+	// MinStageDuration is how long a healthy stage runs before promotion.
+	MinStageDuration time.Duration
+	// MaxSchemaFailureRate rolls back when exceeded by the candidate.
+	MaxSchemaFailureRate float64
+	// MaxSanitizationRate rolls back when too many candidate results needed the
+	// sanitization fallback to pass content validation.
+	MaxSanitizationRate float64
+	// MaxDurationRatio rolls back when the candidate's mean duration exceeds the
+	// baseline's by this factor. It is skipped until the baseline has MinSamples.
+	MaxDurationRatio float64
+}
+
+// DefaultPolicy returns the thresholds used when none are supplied.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinSamples:           50,
+		MinStageDuration:     time.Hour,
+		MaxSchemaFailureRate: 0.05,
+		MaxSanitizationRate:  0.2,
+		MaxDurationRatio:     1.5,
+	}
+}
+
+// Service runs canary rollouts of prompt versions.
+type Service struct {
+	Repo   Repo
+	Policy Policy
+	Now    func() time.Time
+}
+
+// NewService constructs a Service with DefaultPolicy.
+func NewService(repo Repo) *Service {
+	return &Service{Repo: repo, Policy: DefaultPolicy()}
+}
+
+var _ analyses.PromptRollout = (*Service)(nil)
+
+// Start begins a rollout from baseline to candidate at the first stage. An empty
+// baseline means the version new analyses currently get by default.
+func (s *Service) Start(ctx context.Context, baseline, candidate string) (Rollout, error) {
+	baseline = strings.TrimSpace(baseline)
+	candidate = strings.TrimSpace(candidate)
+	if baseline == "" {
+		baseline = s.SelectPromptVersion(ctx, "")
+		if baseline == "" {
+			baseline = analyses.DefaultPromptVersion
+		}
+	}
+	if baseline == "" || candidate == "" || baseline == candidate {
+		return Rollout{}, fmt.Errorf("%w: baseline and candidate must be different versions", ErrInvalidInput)
+	}
+	for _, version := range []string{baseline, candidate} {
+		if _, ok := llm.PromptTemplate(version); !ok {
+			return Rollout{}, fmt.Errorf("%w: unknown prompt version %q", ErrInvalidInput, version)
+		}
+	}
+	now := s.now()
+	ro := Rollout{
+		ID:               uuid.NewString(),
+		BaselineVersion:  baseline,
+		CandidateVersion: candidate,
+		Status:           StatusRunning,
+		StageStartedAt:   now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.Repo.Create(ctx, ro); err != nil {
+		return Rollout{}, err
+	}
+	telemetry.Info("rollout.started", map[string]any{
+		"rollout_id": ro.ID,
+		"baseline":   baseline,
+		"candidate":  candidate,
+		"percent":    ro.Percent(),
+	})
+	return ro, nil
+}
+
+// Current returns the most recent rollout.
+func (s *Service) Current(ctx context.Context) (Rollout, error) {
+	return s.Repo.Latest(ctx)
+}
+
+// List returns recent rollouts, newest first.
+func (s *Service) List(ctx context.Context, limit int) ([]Rollout, error) {
+	return s.Repo.List(ctx, limit)
+}
+
+// Pause freezes the current traffic split; outcomes are still counted but no
+// automatic promotion or rollback happens.
+func (s *Service) Pause(ctx context.Context) (Rollout, error) {
+	return s.transition(ctx, func(ro *Rollout) error {
+		if ro.Status != StatusRunning {
+			return ErrInvalidTransition
+		}
+		ro.Status = StatusPaused
+		return nil
+	})
+}
+
+// Resume restarts automatic evaluation of a paused rollout.
+func (s *Service) Resume(ctx context.Context) (Rollout, error) {
+	return s.transition(ctx, func(ro *Rollout) error {
+		if ro.Status != StatusPaused {
+			return ErrInvalidTransition
+		}
+		ro.Status = StatusRunning
+		return nil
+	})
+}
+
+// Advance promotes the rollout to its next stage without waiting for the policy.
+func (s *Service) Advance(ctx context.Context) (Rollout, error) {
+	return s.transition(ctx, func(ro *Rollout) error {
+		if !ro.Status.Active() {
+			return ErrInvalidTransition
+		}
+		s.promote(ro, "advanced manually")
+		return nil
+	})
+}
+
+// Rollback sends all new analyses back to the baseline version.
+func (s *Service) Rollback(ctx context.Context, reason string) (Rollout, error) {
+	if strings.TrimSpace(reason) == "" {
+		reason = "rolled back manually"
+	}
+	return s.transition(ctx, func(ro *Rollout) error {
+		if !ro.Status.Active() {
+			return ErrInvalidTransition
+		}
+		s.rollback(ro, reason)
+		return nil
+	})
+}
+
+// SelectPromptVersion assigns a new analysis to the baseline or candidate. The
+// assignment is a stable hash of key, so the same key keeps its arm while the
+// percentage only grows.
+func (s *Service) SelectPromptVersion(ctx context.Context, key string) string {
+	ro, err := s.Repo.Latest(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			telemetry.Error("rollout.select_failed", map[string]any{"error": err.Error()})
+		}
+		return ""
+	}
+	switch ro.Status {
+	case StatusCompleted:
+		return ro.CandidateVersion
+	case StatusRolledBack:
+		return ro.BaselineVersion
+	}
+	if bucket(key) < ro.Percent() {
+		return ro.CandidateVersion
+	}
+	return ro.BaselineVersion
+}
+
+// RecordOutcome counts a finished analysis against the active rollout and applies
+// any promotion or rollback the policy calls for.
+func (s *Service) RecordOutcome(ctx context.Context, promptVersion string, outcome analyses.PromptOutcome) {
+	ro, err := s.Repo.Latest(ctx)
+	if err != nil || !ro.Status.Active() {
+		return
+	}
+	var arm Arm
+	switch promptVersion {
+	case ro.CandidateVersion:
+		arm = ArmCandidate
+	case ro.BaselineVersion:
+		arm = ArmBaseline
+	default:
+		return
+	}
+	updated, err := s.Repo.RecordOutcome(ctx, ro.ID, arm, outcome)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			telemetry.Error("rollout.record_failed", map[string]any{"rollout_id": ro.ID, "error": err.Error()})
+		}
+		return
+	}
+	if _, err := s.evaluate(ctx, updated); err != nil && !errors.Is(err, ErrConflict) {
+		telemetry.Error("rollout.evaluate_failed", map[string]any{"rollout_id": ro.ID, "error": err.Error()})
+	}
+}
+
+// Evaluate applies the policy to the current rollout, promoting stages whose
+// minimum duration has elapsed even when no new outcomes arrive.
+func (s *Service) Evaluate(ctx context.Context) (Rollout, error) {
+	ro, err := s.Repo.Latest(ctx)
+	if err != nil {
+		return Rollout{}, err
+	}
+	return s.evaluate(ctx, ro)
+}
+
+// Run evaluates the rollout every interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := s.Evaluate(ctx); err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrConflict) && ctx.Err() == nil {
+			telemetry.Error("rollout.evaluate_failed", map[string]any{"error": err.Error()})
+		}
+	}
+}
+
+// Stats reports the current rollout for the admin stats endpoint.
+func (s *Service) Stats(ctx context.Context) (any, error) {
+	ro, err := s.Repo.Latest(ctx)
+	if errors.Is(err, ErrNotFound) {
+		return map[string]any{"active": false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newView(ro, s.policy()), nil
+}
+
+func (s *Service) evaluate(ctx context.Context, ro Rollout) (Rollout, error) {
+	if ro.Status != StatusRunning {
+		return ro, nil
+	}
+	expectedStatus, expectedStage := ro.Status, ro.StageIndex
+	switch decision, reason := assess(ro, s.policy(), s.now()); decision {
+	case decisionRollback:
+		s.rollback(&ro, reason)
+	case decisionPromote:
+		s.promote(&ro, reason)
+	default:
+		return ro, nil
+	}
+	if err := s.Repo.Update(ctx, ro, expectedStatus, expectedStage); err != nil {
+		return Rollout{}, err
+	}
+	return ro, nil
+}
+
+// transition applies change to the active rollout.
+func (s *Service) transition(ctx context.Context, change func(ro *Rollout) error) (Rollout, error) {
+	ro, err := s.Repo.Latest(ctx)
+	if err != nil {
+		return Rollout{}, err
+	}
+	expectedStatus, expectedStage := ro.Status, ro.StageIndex
+	if err := change(&ro); err != nil {
+		return Rollout{}, err
+	}
+	ro.UpdatedAt = s.now()
+	if err := s.Repo.Update(ctx, ro, expectedStatus, expectedStage); err != nil {
+		return Rollout{}, err
+	}
+	return ro, nil
+}
+
+func (s *Service) promote(ro *Rollout, reason string) {
+	now := s.now()
+	ro.UpdatedAt = now
+	ro.Reason = reason
+	if ro.StageIndex >= len(Stages())-1 {
+		ro.Status = StatusCompleted
+		ro.EndedAt = &now
+		telemetry.Info("rollout.completed", map[string]any{
+			"rollout_id": ro.ID,
+			"candidate":  ro.CandidateVersion,
+		})
+		return
+	}
+	ro.StageIndex++
+	ro.StageStartedAt = now
+	ro.Candidate = ArmStats{}
+	telemetry.Info("rollout.promoted", map[string]any{
+		"rollout_id": ro.ID,
+		"candidate":  ro.CandidateVersion,
+		"percent":    ro.Percent(),
+		"reason":     reason,
+	})
+}
+
+func (s *Service) rollback(ro *Rollout, reason string) {
+	now := s.now()
+	ro.Status = StatusRolledBack
+	ro.Reason = reason
+	ro.UpdatedAt = now
+	ro.EndedAt = &now
+	telemetry.Error("rollout.rolled_back", map[string]any{
+		"rollout_id": ro.ID,
+		"candidate":  ro.CandidateVersion,
+		"baseline":   ro.BaselineVersion,
+		"reason":     reason,
+	})
+}
+
+func (s *Service) policy() Policy {
+	p := s.Policy
+	def := DefaultPolicy()
+	if p.MinSamples <= 0 {
+		p.MinSamples = def.MinSamples
+	}
+	if p.MinStageDuration <= 0 {
+		p.MinStageDuration = def.MinStageDuration
+	}
+	if p.MaxSchemaFailureRate <= 0 {
+		p.MaxSchemaFailureRate = def.MaxSchemaFailureRate
+	}
+	if p.MaxSanitizationRate <= 0 {
+		p.MaxSanitizationRate = def.MaxSanitizationRate
+	}
+	if p.MaxDurationRatio <= 0 {
+		p.MaxDurationRatio = def.MaxDurationRatio
+	}
+	return p
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+type decision int
+
+const (
+	decisionHold decision = iota
+	decisionPromote
+	decisionRollback
+)
+
+// assess judges the current stage. Unhealthy signals win over elapsed time, and
+// nothing is decided until the candidate has enough samples.
+func assess(ro Rollout, p Policy, now time.Time) (decision, string) {
+	cand := ro.Candidate
+	if cand.Finished < p.MinSamples {
+		return decisionHold, ""
+	}
+	if rate := cand.SchemaFailureRate(); rate > p.MaxSchemaFailureRate {
+		return decisionRollback, fmt.Sprintf("schema failure rate %.1f%% exceeds %.1f%%", rate*100, p.MaxSchemaFailureRate*100)
+	}
+	if rate := cand.SanitizationRate(); rate > p.MaxSanitizationRate {
+		return decisionRollback, fmt.Sprintf("sanitization rate %.1f%% exceeds %.1f%%", rate*100, p.MaxSanitizationRate*100)
+	}
+	if ro.Baseline.Finished >= p.MinSamples && ro.Baseline.MeanDurationMs() > 0 {
+		if r := cand.MeanDurationMs() / ro.Baseline.MeanDurationMs(); r > p.MaxDurationRatio {
+			return decisionRollback, fmt.Sprintf("mean duration is %.2fx baseline, above %.2fx", r, p.MaxDurationRatio)
+		}
+	}
+	if now.Sub(ro.StageStartedAt) < p.MinStageDuration {
+		return decisionHold, ""
+	}
+	return decisionPromote, fmt.Sprintf("healthy at %d%% over %d analyses", ro.Percent(), cand.Finished)
+}
+
+// bucket maps key to 0-99.
+func bucket(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
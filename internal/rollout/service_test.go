@@ -0,0 +1,182 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestService() (*Service, *clock) {
+	clk := &clock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	svc := NewService(NewMemoryRepo())
+	svc.Policy.MinSamples = 10
+	svc.Policy.MinStageDuration = time.Hour
+	svc.Now = clk.Now
+	return svc, clk
+}
+
+func record(svc *Service, version string, n int, outcome analyses.PromptOutcome) {
+	for i := 0; i < n; i++ {
+		svc.RecordOutcome(context.Background(), version, outcome)
+	}
+}
+
+func TestRolloutPromotesThroughStagesWhenHealthy(t *testing.T) {
+	ctx := context.Background()
+	svc, clk := newTestService()
+
+	ro, err := svc.Start(ctx, "", "v2_2")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if ro.BaselineVersion != analyses.DefaultPromptVersion || ro.Percent() != 1 {
+		t.Fatalf("unexpected rollout: %+v", ro)
+	}
+	if _, err := svc.Start(ctx, "v2_3", "v2_1"); !errors.Is(err, ErrActiveRollout) {
+		t.Fatalf("expected ErrActiveRollout, got %v", err)
+	}
+
+	for _, want := range []int{10, 50, 100} {
+		record(svc, "v2_2", 10, analyses.PromptOutcome{DurationMs: 1000})
+		// Enough samples, but the stage has not run long enough yet.
+		if cur, _ := svc.Current(ctx); cur.Percent() == want {
+			t.Fatalf("promoted to %d%% before the minimum stage duration", want)
+		}
+		clk.now = clk.now.Add(time.Hour)
+		cur, err := svc.Evaluate(ctx)
+		if err != nil {
+			t.Fatalf("evaluate: %v", err)
+		}
+		if cur.Percent() != want || cur.Candidate.Finished != 0 {
+			t.Fatalf("expected fresh stage at %d%%, got %d%% with %+v", want, cur.Percent(), cur.Candidate)
+		}
+	}
+
+	record(svc, "v2_2", 10, analyses.PromptOutcome{DurationMs: 1000})
+	clk.now = clk.now.Add(time.Hour)
+	done, err := svc.Evaluate(ctx)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if done.Status != StatusCompleted || done.EndedAt == nil {
+		t.Fatalf("expected completed rollout, got %+v", done)
+	}
+	if got := svc.SelectPromptVersion(ctx, "any"); got != "v2_2" {
+		t.Fatalf("expected candidate to become the default, got %q", got)
+	}
+}
+
+func TestRolloutRollsBackOnSchemaFailures(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService()
+	if _, err := svc.Start(ctx, "v2_3", "v2_2"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	record(svc, "v2_2", 8, analyses.PromptOutcome{DurationMs: 1000})
+	record(svc, "v2_2", 2, analyses.PromptOutcome{Failed: true, SchemaFailure: true, DurationMs: 1000})
+
+	ro, err := svc.Current(ctx)
+	if err != nil {
+		t.Fatalf("current: %v", err)
+	}
+	if ro.Status != StatusRolledBack || ro.Reason == "" {
+		t.Fatalf("expected automatic rollback, got %+v", ro)
+	}
+	for i := 0; i < 100; i++ {
+		if got := svc.SelectPromptVersion(ctx, fmt.Sprintf("analysis-%d", i)); got != "v2_3" {
+			t.Fatalf("expected baseline after rollback, got %q", got)
+		}
+	}
+	// A new rollout may start once the previous one has ended.
+	if _, err := svc.Start(ctx, "v2_3", "v2_2"); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+}
+
+func TestRolloutRollsBackOnSanitizationAndDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate analyses.PromptOutcome
+	}{
+		{name: "sanitization", candidate: analyses.PromptOutcome{Sanitized: true, DurationMs: 1000}},
+		{name: "duration", candidate: analyses.PromptOutcome{DurationMs: 2000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, _ := newTestService()
+			if _, err := svc.Start(ctx, "v2_3", "v2_2"); err != nil {
+				t.Fatalf("start: %v", err)
+			}
+			record(svc, "v2_3", 10, analyses.PromptOutcome{DurationMs: 1000})
+			record(svc, "v2_2", 10, tt.candidate)
+			ro, _ := svc.Current(ctx)
+			if ro.Status != StatusRolledBack {
+				t.Fatalf("expected rollback, got %s (%+v)", ro.Status, ro.Candidate)
+			}
+		})
+	}
+}
+
+func TestRolloutPauseHoldsSplit(t *testing.T) {
+	ctx := context.Background()
+	svc, clk := newTestService()
+	if _, err := svc.Start(ctx, "v2_3", "v2_2"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := svc.Advance(ctx); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if _, err := svc.Pause(ctx); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if _, err := svc.Pause(ctx); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+
+	record(svc, "v2_2", 10, analyses.PromptOutcome{Failed: true, SchemaFailure: true})
+	clk.now = clk.now.Add(2 * time.Hour)
+	ro, err := svc.Evaluate(ctx)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if ro.Status != StatusPaused || ro.Percent() != 10 || ro.Candidate.Finished != 10 {
+		t.Fatalf("expected paused rollout at 10%% still counting outcomes, got %+v", ro)
+	}
+
+	candidates := 0
+	for i := 0; i < 1000; i++ {
+		if svc.SelectPromptVersion(ctx, fmt.Sprintf("analysis-%d", i)) == "v2_2" {
+			candidates++
+		}
+	}
+	if candidates < 60 || candidates > 140 {
+		t.Fatalf("expected roughly 10%% candidate assignments, got %d/1000", candidates)
+	}
+
+	ro, err = svc.Resume(ctx)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if ro, _ = svc.Evaluate(ctx); ro.Status != StatusRolledBack {
+		t.Fatalf("expected rollback once resumed, got %s", ro.Status)
+	}
+}
+
+func TestStartRejectsUnknownVersions(t *testing.T) {
+	svc, _ := newTestService()
+	for _, tc := range [][2]string{{"v2_3", "v2_3"}, {"v2_3", "v9"}, {"nope", "v2_2"}} {
+		if _, err := svc.Start(context.Background(), tc[0], tc[1]); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("Start(%q, %q): expected ErrInvalidInput, got %v", tc[0], tc[1], err)
+		}
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 
 	"github.com/gin-gonic/gin"
-)
 
-type ctxKey string
+	"resume-backend/internal/shared/ctxmeta"
+)
 
-const userIDKey ctxKey = "user_id"
+const userIDKey = "user_id"
 
 // authMiddleware ensures a user ID is present in the request context.
 func authMiddleware() gin.HandlerFunc {
@@ -19,11 +19,10 @@ func authMiddleware() gin.HandlerFunc {
 		}
 
 		// Store in gin context for handlers that access it directly.
-		c.Set(string(userIDKey), userID)
+		c.Set(userIDKey, userID)
 
 		// Store in request context for downstream services.
-		ctx := context.WithValue(c.Request.Context(), userIDKey, userID)
-		c.Request = c.Request.WithContext(ctx)
+		c.Request = c.Request.WithContext(ctxmeta.WithUserID(c.Request.Context(), userID))
 
 		c.Next()
 	}
@@ -31,11 +30,5 @@ func authMiddleware() gin.HandlerFunc {
 
 // UserIDFromContext fetches the user ID from a context populated by authMiddleware.
 func UserIDFromContext(ctx context.Context) string {
-	if ctx == nil {
-		return ""
-	}
-	if v, ok := ctx.Value(userIDKey).(string); ok {
-		return v
-	}
-	return ""
+	return ctxmeta.UserID(ctx)
 }
@@ -20,6 +20,10 @@ type Claims struct {
 	Picture string `json:"picture,omitempty"`
 	Exp     int64  `json:"exp,omitempty"`
 	Iat     int64  `json:"iat,omitempty"`
+	// Act is the admin acting as Sub on an impersonation token; Sid names the
+	// impersonation session it belongs to.
+	Act string `json:"act,omitempty"`
+	Sid string `json:"sid,omitempty"`
 }
 
 var (
@@ -111,6 +115,13 @@ func sign(input string, secret []byte) string {
 	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 }
 
+// CheckSecret resolves the signing secret once so misconfiguration surfaces at startup
+// instead of on the first authenticated request.
+func CheckSecret() error {
+	_, err := secretKey()
+	return err
+}
+
 func secretKey() ([]byte, error) {
 	secret := strings.TrimSpace(os.Getenv("JWT_SECRET"))
 	env := strings.ToLower(strings.TrimSpace(os.Getenv("ENV")))
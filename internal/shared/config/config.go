@@ -3,7 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds application configuration.
@@ -25,6 +27,191 @@ type Config struct {
 	GoogleClientSecret string
 	GoogleRedirectURL  string
 	UIRedirectURL      string
+	// GuestRetention is how long guest-owned documents and analyses are kept. Zero disables expiry.
+	GuestRetention time.Duration
+	// UsageSoftLimitPercent is the share of a plan's limit that triggers upgrade nudges.
+	UsageSoftLimitPercent int
+	// AdminUserIDs are the signed-in user IDs allowed to call /api/v1/admin endpoints.
+	AdminUserIDs []string
+	// FairnessMonitoring enables the aggregate score-skew monitoring job.
+	FairnessMonitoring bool
+	// DeltaAnalysisMinSimilarity is the percent of unchanged resume lines above which a
+	// re-analysis only evaluates the edited sections. Zero disables delta analysis.
+	DeltaAnalysisMinSimilarity int
+	// AnalyticsSink selects where funnel events go: "", "log", "segment" or "posthog".
+	AnalyticsSink string
+	// AnalyticsKey is the Segment write key or PostHog project API key.
+	AnalyticsKey string
+	// AnalyticsHost overrides the sink's default endpoint.
+	AnalyticsHost string
+	// AnalyticsHashSalt keys the hash that replaces user IDs in analytics events.
+	AnalyticsHashSalt string
+	// BackpressureShedGuests rejects new guest analyses with 503 while the queue is overloaded.
+	BackpressureShedGuests bool
+	// SecretsKey is the base64-encoded 32-byte key that encrypts stored integration credentials.
+	SecretsKey string
+	// PIIKeys lists the data keys that encrypt PII columns as comma-separated
+	// "id:base64" entries, the key for new writes first. Empty stores PII in
+	// the clear.
+	PIIKeys string
+	// PIIKeyWrapping is "kms" when PIIKeys holds KMS-wrapped data keys, or
+	// "none" for raw keys in development. It also applies to LLMArchiveKeys.
+	PIIKeyWrapping string
+	// LLMArchiveMode is how LLM calls of owners without an archive policy are
+	// archived: "off", "hashes" or "full".
+	LLMArchiveMode string
+	// LLMArchiveRetention is how long archived LLM calls are kept when their
+	// policy sets no retention.
+	LLMArchiveRetention time.Duration
+	// LLMArchiveKeys lists the data keys that seal archived prompts and
+	// responses, in the PIIKeys format. Full archiving requires them.
+	LLMArchiveKeys string
+	// DefaultResidency is the region ("us" or "eu") of users and organizations
+	// with no residency assignment. Its bucket and LLM endpoint are the unsuffixed
+	// S3_BUCKET and OpenAI settings.
+	DefaultResidency string
+	// ShutdownDrainDelay is how long a stopping process keeps serving after its
+	// readiness probe starts failing.
+	ShutdownDrainDelay time.Duration
+	// ShutdownGracePeriod is how long in-flight requests and jobs may run once
+	// shutdown begins.
+	ShutdownGracePeriod time.Duration
+	// Role is the binary these settings were loaded for (RoleAPI or RoleWorker).
+	// Empty means an operator tool with the full repo surface.
+	Role string
+	// RoleDatabaseURLs are per-role DSNs from DATABASE_URL_<ROLE>. WithRole uses
+	// them in place of DatabaseURL so each binary can connect as its own DB user.
+	RoleDatabaseURLs map[string]string
+	// FeatureFlags is a JSON object of feature flag rules that take precedence
+	// over rules edited through the admin API.
+	FeatureFlags string
+	// FeatureFlagsURL serves flag rules in the FeatureFlags format; empty
+	// disables the remote provider.
+	FeatureFlagsURL string
+	// LLMDailyBudgetUSD caps the LLM spend of all analyses per UTC day; zero
+	// disables the cap.
+	LLMDailyBudgetUSD float64
+	// LLMOrgDailyBudgetUSD caps each organization's LLM spend per UTC day; zero
+	// disables the cap.
+	LLMOrgDailyBudgetUSD float64
+	// LLMBudgetAction is "defer" to hold new analyses until the day resets once a
+	// budget is spent, or "reject" to refuse them.
+	LLMBudgetAction string
+	// ApplyMode selects how the apply flow rebuilds resumes: ApplyModeLLM,
+	// ApplyModeDeterministic, or ApplyModeAuto to use the LLM only when a
+	// provider is configured.
+	ApplyMode string
+	// LLMPromptPricePer1K and LLMCompletionPricePer1K convert token counts to
+	// dollars, in USD per 1,000 tokens.
+	LLMPromptPricePer1K     float64
+	LLMCompletionPricePer1K float64
+	// LLMInputTokenLimit caps the resume and job description tokens sent per
+	// analysis; zero uses the analyses default.
+	LLMInputTokenLimit int
+	// LLMHealthModel is the cheap model the provider health probe calls.
+	LLMHealthModel string
+	// LLMHealthProbeInterval is how often the background probe runs; zero
+	// disables it, leaving only on-demand probes from the admin endpoint.
+	LLMHealthProbeInterval time.Duration
+	// LLMHealthFailureThreshold is how many probes in a row must fail before
+	// the circuit breaker opens and analyses fail fast.
+	LLMHealthFailureThreshold int
+	// LLMHealthGatesReadiness makes the readiness endpoint report not-ready while the breaker
+	// is open.
+	LLMHealthGatesReadiness bool
+	// QueueDedupWindow is how long workers skip re-deliveries of a queue
+	// message they already claimed; zero disables deduplication.
+	QueueDedupWindow time.Duration
+	// CompressResponses gzips response bodies for clients that accept it.
+	CompressResponses bool
+	// CompressMinBytes is the smallest response body that is compressed.
+	CompressMinBytes int
+	// CompressLevel is the gzip level, 1 (fastest) to 9 (smallest); zero uses
+	// the gzip default.
+	CompressLevel int
+	// StorageReadFallback lets document reads try the other object store when
+	// the document's own provider does not have the object, while legacy local
+	// uploads are being moved to S3.
+	StorageReadFallback bool
+	// StuckScanInterval is how often workers scan for records stuck in an
+	// inconsistent state; zero disables the scan.
+	StuckScanInterval time.Duration
+	// StuckRemediate lets the scan repair what it finds instead of only
+	// alerting.
+	StuckRemediate bool
+	// StuckAnalysisAfter is how long an analysis may process without progress
+	// before it counts as stalled.
+	StuckAnalysisAfter time.Duration
+	// StuckApplyRunAfter is how long an apply run may stay planned before it
+	// counts as stalled.
+	StuckApplyRunAfter time.Duration
+	// AbuseMode selects free-tier abuse detection for guests: "off", "shadow"
+	// to flag clusters for review without blocking, or "enforce".
+	AbuseMode string
+	// AbuseWindow is how far back guest identities and LLM requests are
+	// counted per IP and device fingerprint.
+	AbuseWindow time.Duration
+	// AbuseMaxGuestsPerDevice and AbuseMaxGuestsPerIP bound the guest
+	// identities seen from one device or IP within the window.
+	AbuseMaxGuestsPerDevice int
+	AbuseMaxGuestsPerIP     int
+	// AbuseMaxRequestsPerDevice and AbuseMaxRequestsPerIP are the shadow
+	// limits on LLM requests by all guests of one device or IP together.
+	AbuseMaxRequestsPerDevice int
+	AbuseMaxRequestsPerIP     int
+	// AbuseReviewTTL is how long an admin's confirm or dismiss applies.
+	AbuseReviewTTL time.Duration
+	// AbuseHashKey keys the hashes stored in place of IPs and fingerprints.
+	// It is required outside dev unless AbuseMode is "off".
+	AbuseHashKey string
+	// TrustedProxies lists the proxy IPs and CIDRs whose X-Forwarded-For
+	// header is believed. Empty trusts none, so the client IP is the
+	// connection's address, which on Lambda is API Gateway's source IP.
+	TrustedProxies []string
+	// PromptSunset overrides the built-in prompt version sunset policies, as
+	// comma-separated version=status entries: "v1=deprecated:v2_3,v2=blocked".
+	PromptSunset string
+	// Profile selects a deployment profile from RA_PROFILE. Empty is the
+	// default AWS deployment; ProfileStandalone runs without any AWS service.
+	Profile string
+	// LocalQueueVisibility is how long a standalone job stays hidden from other
+	// consumers once received; a crashed consumer's job is retried after it.
+	LocalQueueVisibility time.Duration
+	// LocalWorkerConcurrency is how many jobs the standalone API process
+	// handles at once.
+	LocalWorkerConcurrency int
+}
+
+const (
+	RoleAPI    = "api"
+	RoleWorker = "worker"
+)
+
+// ProfileStandalone is the self-hosted profile: queued jobs go through a
+// Postgres table (or memory without a database) and are handled inside the
+// API process, documents are stored on the local disk, and PII keys are not
+// KMS-wrapped.
+const ProfileStandalone = "standalone"
+
+// Apply modes.
+const (
+	ApplyModeAuto          = "auto"
+	ApplyModeLLM           = "llm"
+	ApplyModeDeterministic = "deterministic"
+)
+
+// Standalone reports whether c uses the standalone profile.
+func (c Config) Standalone() bool {
+	return c.Profile == ProfileStandalone
+}
+
+// WithRole returns a copy of c for role, using the role's DSN when one is set.
+func (c Config) WithRole(role string) Config {
+	c.Role = role
+	if dsn := strings.TrimSpace(c.RoleDatabaseURLs[role]); dsn != "" {
+		c.DatabaseURL = dsn
+	}
+	return c
 }
 
 // Load reads configuration from environment variables with sensible defaults.
@@ -39,24 +226,106 @@ func Load() Config {
 		log.Printf("DATABASE_URL is required in production")
 	}
 
-	return Config{
-		Port:               getEnv("PORT", "8080"),
-		CORSAllowOrigin:    splitAndTrim(getEnv("CORS_ALLOW_ORIGINS", "http://localhost:5173")),
-		ObjectStoreType:    normalizeStoreType(getEnv("OBJECT_STORE", "local")),
-		LocalStoreDir:      getEnv("LOCAL_STORE_DIR", "./data"),
-		AWSRegion:          getEnv("AWS_REGION", ""),
-		S3Bucket:           getEnv("S3_BUCKET", ""),
-		S3Prefix:           getEnv("S3_PREFIX", ""),
-		SSEKMSKeyID:        getEnv("SSE_KMS_KEY_ID", ""),
-		LLMProvider:        getEnv("LLM_PROVIDER", "openai"),
-		LLMModel:           getEnv("LLM_MODEL", ""),
-		AnalysisVersion:    getEnv("ANALYSIS_VERSION", "gpt-5-mini:v1"),
-		DatabaseURL:        dbURL,
-		Env:                env,
-		GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-		GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-		GoogleRedirectURL:  getEnv("GOOGLE_REDIRECT_URL", ""),
-		UIRedirectURL:      getEnv("UI_REDIRECT_URL", ""),
+	cfg := Config{
+		Port:                       getEnv("PORT", "8080"),
+		CORSAllowOrigin:            splitAndTrim(getEnv("CORS_ALLOW_ORIGINS", "http://localhost:5173")),
+		ObjectStoreType:            normalizeStoreType(getEnv("OBJECT_STORE", "local")),
+		LocalStoreDir:              getEnv("LOCAL_STORE_DIR", "./data"),
+		AWSRegion:                  getEnv("AWS_REGION", ""),
+		S3Bucket:                   getEnv("S3_BUCKET", ""),
+		S3Prefix:                   getEnv("S3_PREFIX", ""),
+		SSEKMSKeyID:                getEnv("SSE_KMS_KEY_ID", ""),
+		LLMProvider:                getEnv("LLM_PROVIDER", "openai"),
+		LLMModel:                   getEnv("LLM_MODEL", ""),
+		AnalysisVersion:            getEnv("ANALYSIS_VERSION", "gpt-5-mini:v1"),
+		DatabaseURL:                dbURL,
+		Env:                        env,
+		GoogleClientID:             getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:         getEnv("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURL:          getEnv("GOOGLE_REDIRECT_URL", ""),
+		UIRedirectURL:              getEnv("UI_REDIRECT_URL", ""),
+		GuestRetention:             time.Duration(getEnvInt("GUEST_RETENTION_DAYS", 14)) * 24 * time.Hour,
+		UsageSoftLimitPercent:      getEnvInt("USAGE_SOFT_LIMIT_PERCENT", 80),
+		AdminUserIDs:               splitAndTrim(getEnv("ADMIN_USER_IDS", "")),
+		FairnessMonitoring:         getEnvBool("FAIRNESS_MONITORING_ENABLED", false),
+		DeltaAnalysisMinSimilarity: getEnvInt("DELTA_ANALYSIS_MIN_SIMILARITY", 90),
+		AnalyticsSink:              strings.ToLower(getEnv("ANALYTICS_SINK", "")),
+		AnalyticsKey:               getEnv("ANALYTICS_KEY", ""),
+		AnalyticsHost:              getEnv("ANALYTICS_HOST", ""),
+		AnalyticsHashSalt:          getEnv("ANALYTICS_HASH_SALT", ""),
+		BackpressureShedGuests:     getEnvBool("BACKPRESSURE_SHED_GUESTS", false),
+		SecretsKey:                 getEnv("SECRETS_KEY", ""),
+		PIIKeys:                    getEnv("PII_KEYS", ""),
+		PIIKeyWrapping:             strings.ToLower(getEnv("PII_KEY_WRAPPING", "kms")),
+		LLMArchiveMode:             strings.ToLower(getEnv("LLM_ARCHIVE_MODE", "off")),
+		LLMArchiveRetention:        time.Duration(getEnvInt("LLM_ARCHIVE_RETENTION_DAYS", 90)) * 24 * time.Hour,
+		LLMArchiveKeys:             getEnv("LLM_ARCHIVE_KEYS", ""),
+		DefaultResidency:           strings.ToLower(getEnv("DEFAULT_RESIDENCY", "us")),
+		ShutdownDrainDelay:         time.Duration(getEnvInt("RA_SHUTDOWN_DRAIN_DELAY_SECONDS", 0)) * time.Second,
+		ShutdownGracePeriod:        time.Duration(getEnvInt("RA_SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second,
+		FeatureFlags:               getEnv("FEATURE_FLAGS", ""),
+		FeatureFlagsURL:            getEnv("FEATURE_FLAGS_URL", ""),
+		LLMDailyBudgetUSD:          getEnvFloat("LLM_DAILY_BUDGET_USD", 0),
+		LLMOrgDailyBudgetUSD:       getEnvFloat("LLM_ORG_DAILY_BUDGET_USD", 0),
+		LLMBudgetAction:            strings.ToLower(getEnv("LLM_BUDGET_ACTION", "defer")),
+		ApplyMode:                  strings.ToLower(getEnv("APPLY_MODE", ApplyModeAuto)),
+		LLMPromptPricePer1K:        getEnvFloat("LLM_PROMPT_PRICE_PER_1K_USD", 0.00025),
+		LLMCompletionPricePer1K:    getEnvFloat("LLM_COMPLETION_PRICE_PER_1K_USD", 0.002),
+		StorageReadFallback:        getEnvBool("STORAGE_READ_FALLBACK", true),
+		LLMInputTokenLimit:         getEnvInt("LLM_INPUT_TOKEN_LIMIT", 0),
+		LLMHealthModel:             getEnv("LLM_HEALTH_MODEL", "gpt-4o-mini"),
+		LLMHealthProbeInterval:     time.Duration(getEnvInt("LLM_HEALTH_PROBE_SECONDS", 60)) * time.Second,
+		LLMHealthFailureThreshold:  getEnvInt("LLM_HEALTH_FAILURE_THRESHOLD", 3),
+		LLMHealthGatesReadiness:    getEnvBool("LLM_HEALTH_READINESS", false),
+		QueueDedupWindow:           time.Duration(getEnvInt("QUEUE_DEDUP_WINDOW_SECONDS", 120)) * time.Second,
+		CompressResponses:          getEnvBool("COMPRESS_RESPONSES", true),
+		CompressMinBytes:           getEnvInt("COMPRESS_MIN_BYTES", 1024),
+		CompressLevel:              getEnvInt("COMPRESS_LEVEL", 0),
+		StuckScanInterval:          time.Duration(getEnvInt("STUCK_SCAN_MINUTES", 15)) * time.Minute,
+		StuckRemediate:             getEnvBool("STUCK_REMEDIATE", false),
+		StuckAnalysisAfter:         time.Duration(getEnvInt("STUCK_ANALYSIS_MINUTES", 30)) * time.Minute,
+		StuckApplyRunAfter:         time.Duration(getEnvInt("STUCK_APPLY_RUN_MINUTES", 60)) * time.Minute,
+		AbuseMode:                  strings.ToLower(getEnv("ABUSE_MODE", "shadow")),
+		AbuseWindow:                time.Duration(getEnvInt("ABUSE_WINDOW_HOURS", 24)) * time.Hour,
+		AbuseMaxGuestsPerDevice:    getEnvInt("ABUSE_MAX_GUESTS_PER_DEVICE", 3),
+		AbuseMaxGuestsPerIP:        getEnvInt("ABUSE_MAX_GUESTS_PER_IP", 25),
+		AbuseMaxRequestsPerDevice:  getEnvInt("ABUSE_MAX_REQUESTS_PER_DEVICE", 10),
+		AbuseMaxRequestsPerIP:      getEnvInt("ABUSE_MAX_REQUESTS_PER_IP", 60),
+		AbuseReviewTTL:             time.Duration(getEnvInt("ABUSE_REVIEW_DAYS", 30)) * 24 * time.Hour,
+		AbuseHashKey:               getEnv("ABUSE_HASH_KEY", ""),
+		TrustedProxies:             splitAndTrim(getEnv("TRUSTED_PROXIES", "")),
+		PromptSunset:               getEnv("PROMPT_SUNSET", ""),
+		RoleDatabaseURLs: map[string]string{
+			RoleAPI:    os.Getenv("DATABASE_URL_API"),
+			RoleWorker: os.Getenv("DATABASE_URL_WORKER"),
+		},
+		Profile:                normalizeProfile(getEnv("RA_PROFILE", "")),
+		LocalQueueVisibility:   time.Duration(getEnvInt("LOCAL_QUEUE_VISIBILITY_SECONDS", 1200)) * time.Second,
+		LocalWorkerConcurrency: getEnvInt("LOCAL_WORKER_CONCURRENCY", 2),
+	}
+	if cfg.Standalone() {
+		applyStandalone(&cfg)
+	}
+	return cfg
+}
+
+// applyStandalone overrides the settings that would reach AWS. Settings that
+// are only read where they are used (SQS and upload bucket URLs) are ignored
+// by bootstrap instead.
+func applyStandalone(cfg *Config) {
+	if cfg.ObjectStoreType != "local" {
+		log.Printf("RA_PROFILE=standalone stores documents locally; ignoring OBJECT_STORE=%s", cfg.ObjectStoreType)
+		cfg.ObjectStoreType = "local"
+	}
+	if cfg.PIIKeyWrapping == "kms" {
+		if os.Getenv("PII_KEY_WRAPPING") != "" {
+			log.Printf("RA_PROFILE=standalone has no KMS; using PII_KEY_WRAPPING=none")
+		}
+		cfg.PIIKeyWrapping = "none"
+	}
+	cfg.StorageReadFallback = false
+	if cfg.LocalWorkerConcurrency < 1 {
+		cfg.LocalWorkerConcurrency = 1
 	}
 }
 
@@ -67,6 +336,45 @@ func getEnv(key, def string) string {
 	return def
 }
 
+func getEnvInt(key string, def int) int {
+	val := strings.TrimSpace(os.Getenv(key))
+	if val == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil || parsed < 0 {
+		log.Printf("invalid %s=%q, using %d", key, val, def)
+		return def
+	}
+	return parsed
+}
+
+func getEnvFloat(key string, def float64) float64 {
+	val := strings.TrimSpace(os.Getenv(key))
+	if val == "" {
+		return def
+	}
+	parsed, err := strconv.ParseFloat(val, 64)
+	if err != nil || parsed < 0 {
+		log.Printf("invalid %s=%q, using %g", key, val, def)
+		return def
+	}
+	return parsed
+}
+
+func getEnvBool(key string, def bool) bool {
+	val := strings.TrimSpace(os.Getenv(key))
+	if val == "" {
+		return def
+	}
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %t", key, val, def)
+		return def
+	}
+	return parsed
+}
+
 func splitAndTrim(raw string) []string {
 	parts := strings.Split(raw, ",")
 	var out []string
@@ -78,6 +386,17 @@ func splitAndTrim(raw string) []string {
 	return out
 }
 
+// IsDevLike reports whether env is a local development environment, where
+// production safeguards are relaxed and fault injection is allowed.
+func IsDevLike(env string) bool {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "dev", "local":
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizeEnv(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "production", "prod":
@@ -93,6 +412,18 @@ func normalizeEnv(raw string) string {
 	}
 }
 
+func normalizeProfile(raw string) string {
+	switch profile := strings.ToLower(strings.TrimSpace(raw)); profile {
+	case "":
+		return ""
+	case ProfileStandalone:
+		return ProfileStandalone
+	default:
+		log.Printf("unknown RA_PROFILE=%q, using the default profile", raw)
+		return ""
+	}
+}
+
 func normalizeStoreType(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "s3":
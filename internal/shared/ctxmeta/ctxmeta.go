@@ -0,0 +1,219 @@
+// Package ctxmeta holds the request and analysis metadata that travels on a
+// context.Context: identifiers that end up in telemetry, and the directives the
+// analysis pipeline passes to the LLM client.
+package ctxmeta
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type key int
+
+const (
+	requestIDKey key = iota
+	userIDKey
+	analysisIDKey
+	traceIDKey
+	fixJSONKey
+	extraSystemKey
+	promptHashKey
+	sanitizedKey
+	dataOwnerKey
+	structuredOutputKey
+	tokenUsageKey
+)
+
+// WithRequestID attaches a request ID. Empty IDs leave ctx unchanged.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return withString(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID, or "".
+func RequestID(ctx context.Context) string {
+	return stringValue(ctx, requestIDKey)
+}
+
+// WithUserID attaches the acting user's ID. Empty IDs leave ctx unchanged.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return withString(ctx, userIDKey, userID)
+}
+
+// UserID returns the acting user's ID, or "".
+func UserID(ctx context.Context) string {
+	return stringValue(ctx, userIDKey)
+}
+
+// WithDataOwner attaches the ID of the user whose documents are being read or
+// processed, when that is not the acting user, such as in the worker or an admin
+// replay. Empty IDs leave ctx unchanged.
+func WithDataOwner(ctx context.Context, userID string) context.Context {
+	return withString(ctx, dataOwnerKey, userID)
+}
+
+// DataOwner returns the data owner's ID, falling back to the acting user's ID.
+func DataOwner(ctx context.Context) string {
+	if owner := stringValue(ctx, dataOwnerKey); owner != "" {
+		return owner
+	}
+	return UserID(ctx)
+}
+
+// WithAnalysisID attaches the ID of the analysis being worked on. Empty IDs leave
+// ctx unchanged.
+func WithAnalysisID(ctx context.Context, analysisID string) context.Context {
+	return withString(ctx, analysisIDKey, analysisID)
+}
+
+// AnalysisID returns the analysis ID, or "".
+func AnalysisID(ctx context.Context) string {
+	return stringValue(ctx, analysisIDKey)
+}
+
+// WithTraceID attaches a distributed trace ID. Empty IDs leave ctx unchanged.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return withString(ctx, traceIDKey, traceID)
+}
+
+// TraceID returns the trace ID, or "".
+func TraceID(ctx context.Context) string {
+	return stringValue(ctx, traceIDKey)
+}
+
+// Fields returns the identifiers on ctx as telemetry fields. Missing identifiers
+// are left out.
+func Fields(ctx context.Context) map[string]any {
+	fields := make(map[string]any, 4)
+	for name, k := range map[string]key{
+		"request_id":  requestIDKey,
+		"user_id":     userIDKey,
+		"analysis_id": analysisIDKey,
+		"trace_id":    traceIDKey,
+	} {
+		if v := stringValue(ctx, k); v != "" {
+			fields[name] = v
+		}
+	}
+	return fields
+}
+
+// Detach returns a background context that carries ctx's identifiers but not its
+// deadline, cancellation or LLM directives. Use it for work that must finish after
+// the request that started it.
+func Detach(ctx context.Context) context.Context {
+	out := context.Background()
+	for _, k := range []key{requestIDKey, userIDKey, analysisIDKey, traceIDKey, dataOwnerKey} {
+		out = withString(out, k, stringValue(ctx, k))
+	}
+	return out
+}
+
+// WithFixJSON marks a fix-JSON retry: the LLM client should repair raw instead of
+// analyzing again.
+func WithFixJSON(ctx context.Context, raw string) context.Context {
+	return context.WithValue(ctx, fixJSONKey, raw)
+}
+
+// FixJSON returns the raw output to repair, if this is a fix-JSON retry.
+func FixJSON(ctx context.Context) (string, bool) {
+	return lookupString(ctx, fixJSONKey)
+}
+
+// WithExtraSystemMessage adds a system message to the next prompt.
+func WithExtraSystemMessage(ctx context.Context, message string) context.Context {
+	return context.WithValue(ctx, extraSystemKey, message)
+}
+
+// ExtraSystemMessage returns the extra system message, if any.
+func ExtraSystemMessage(ctx context.Context) (string, bool) {
+	return lookupString(ctx, extraSystemKey)
+}
+
+// WithStructuredOutput asks the LLM client to constrain output to the analysis
+// JSON schema rather than any JSON object.
+func WithStructuredOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, structuredOutputKey, true)
+}
+
+// StructuredOutput reports whether WithStructuredOutput was applied.
+func StructuredOutput(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	on, _ := ctx.Value(structuredOutputKey).(bool)
+	return on
+}
+
+// WithPromptHashCapture attaches a sink for the prompt hash computed by the LLM client.
+func WithPromptHashCapture(ctx context.Context, out *string) context.Context {
+	return context.WithValue(ctx, promptHashKey, out)
+}
+
+// PromptHashSink returns the prompt hash sink, if any.
+func PromptHashSink(ctx context.Context) (*string, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	out, ok := ctx.Value(promptHashKey).(*string)
+	return out, ok && out != nil
+}
+
+// WithSanitizedCapture attaches a flag that MarkSanitized sets when output had to
+// go through the content-sanitization fallback.
+func WithSanitizedCapture(ctx context.Context, out *bool) context.Context {
+	return context.WithValue(ctx, sanitizedKey, out)
+}
+
+// MarkSanitized sets the flag attached by WithSanitizedCapture, if any.
+func MarkSanitized(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if out, ok := ctx.Value(sanitizedKey).(*bool); ok && out != nil {
+		*out = true
+	}
+}
+
+// TokenUsage counts the LLM tokens spent while a context was in use. It is safe
+// to add to from concurrent calls.
+type TokenUsage struct {
+	PromptTokens     int64
+	CompletionTokens int64
+}
+
+// WithTokenCapture attaches a counter that AddTokenUsage adds to.
+func WithTokenCapture(ctx context.Context, out *TokenUsage) context.Context {
+	return context.WithValue(ctx, tokenUsageKey, out)
+}
+
+// AddTokenUsage adds an LLM response's token counts to the counter attached by
+// WithTokenCapture, if any.
+func AddTokenUsage(ctx context.Context, promptTokens, completionTokens int) {
+	if ctx == nil {
+		return
+	}
+	if out, ok := ctx.Value(tokenUsageKey).(*TokenUsage); ok && out != nil {
+		atomic.AddInt64(&out.PromptTokens, int64(promptTokens))
+		atomic.AddInt64(&out.CompletionTokens, int64(completionTokens))
+	}
+}
+
+func withString(ctx context.Context, k key, value string) context.Context {
+	if ctx == nil || value == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, k, value)
+}
+
+func stringValue(ctx context.Context, k key) string {
+	value, _ := lookupString(ctx, k)
+	return value
+}
+
+func lookupString(ctx context.Context, k key) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	value, ok := ctx.Value(k).(string)
+	return value, ok
+}
@@ -0,0 +1,61 @@
+package ctxmeta
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIdentifiers(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithUserID(ctx, "user-1")
+	ctx = WithAnalysisID(ctx, "analysis-1")
+	ctx = WithTraceID(ctx, "trace-1")
+	ctx = WithRequestID(ctx, "")
+
+	if RequestID(ctx) != "req-1" || UserID(ctx) != "user-1" || AnalysisID(ctx) != "analysis-1" || TraceID(ctx) != "trace-1" {
+		t.Fatalf("unexpected identifiers: %v", Fields(ctx))
+	}
+	fields := Fields(ctx)
+	if len(fields) != 4 || fields["request_id"] != "req-1" || fields["trace_id"] != "trace-1" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if got := Fields(WithUserID(context.Background(), "u")); len(got) != 1 {
+		t.Fatalf("expected only user_id, got %v", got)
+	}
+}
+
+func TestDetachKeepsIdentifiersOnly(t *testing.T) {
+	parent, cancel := context.WithCancel(WithAnalysisID(WithRequestID(context.Background(), "req-1"), "analysis-1"))
+	parent = WithFixJSON(parent, "{")
+	cancel()
+
+	detached := Detach(parent)
+	if detached.Err() != nil {
+		t.Fatalf("expected detached context to outlive its parent")
+	}
+	if RequestID(detached) != "req-1" || AnalysisID(detached) != "analysis-1" {
+		t.Fatalf("expected identifiers to carry over, got %v", Fields(detached))
+	}
+	if _, ok := FixJSON(detached); ok {
+		t.Fatalf("expected LLM directives to be dropped")
+	}
+}
+
+func TestCaptures(t *testing.T) {
+	var sanitized bool
+	var hash string
+	ctx := WithPromptHashCapture(WithSanitizedCapture(context.Background(), &sanitized), &hash)
+
+	MarkSanitized(ctx)
+	MarkSanitized(context.Background())
+	if !sanitized {
+		t.Fatalf("expected sanitized flag to be set")
+	}
+	sink, ok := PromptHashSink(ctx)
+	if !ok || sink != &hash {
+		t.Fatalf("expected prompt hash sink")
+	}
+	if _, ok := ExtraSystemMessage(ctx); ok {
+		t.Fatalf("expected no extra system message")
+	}
+}
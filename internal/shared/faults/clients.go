@@ -0,0 +1,121 @@
+package faults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/storage/object"
+)
+
+// ErrLLMTimeout mimics a provider timeout so injected LLM failures take the retry path.
+var ErrLLMTimeout = fmt.Errorf("%w: llm timeout: %w", ErrInjected, context.DeadlineExceeded)
+
+// Store injects faults into an object.ObjectStore.
+type Store struct {
+	Base   object.ObjectStore
+	Config Config
+}
+
+// WrapStore returns base unchanged when cfg injects nothing.
+func WrapStore(base object.ObjectStore, cfg Config) object.ObjectStore {
+	if base == nil || !cfg.Enabled() {
+		return base
+	}
+	return &Store{Base: base, Config: cfg}
+}
+
+// Save injects a fault before delegating to the wrapped store.
+func (s *Store) Save(ctx context.Context, userId string, fileName string, r io.Reader) (string, int64, string, error) {
+	if err := s.Config.inject(ctx, "storage save"); err != nil {
+		return "", 0, "", err
+	}
+	return s.Base.Save(ctx, userId, fileName, r)
+}
+
+// Open injects a fault before delegating to the wrapped store.
+func (s *Store) Open(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+	if err := s.Config.inject(ctx, "storage open"); err != nil {
+		return nil, err
+	}
+	return s.Base.Open(ctx, storageKey)
+}
+
+// SaveWithKey injects a fault before delegating to the wrapped store when it
+// supports writes to a specific key, as extraction and rendering need.
+func (s *Store) SaveWithKey(ctx context.Context, storageKey string, contentType string, r io.Reader) (int64, error) {
+	if err := s.Config.inject(ctx, "storage save"); err != nil {
+		return 0, err
+	}
+	saver, ok := s.Base.(interface {
+		SaveWithKey(ctx context.Context, storageKey string, contentType string, r io.Reader) (int64, error)
+	})
+	if !ok {
+		return 0, fmt.Errorf("storage save with key not supported by %T", s.Base)
+	}
+	return saver.SaveWithKey(ctx, storageKey, contentType, r)
+}
+
+// Delete injects a fault before delegating to the wrapped store when it supports deletes.
+func (s *Store) Delete(ctx context.Context, storageKey string) error {
+	if err := s.Config.inject(ctx, "storage delete"); err != nil {
+		return err
+	}
+	deleter, ok := s.Base.(interface {
+		Delete(ctx context.Context, storageKey string) error
+	})
+	if !ok {
+		return fmt.Errorf("storage delete not supported by %T", s.Base)
+	}
+	return deleter.Delete(ctx, storageKey)
+}
+
+// Queue injects faults into a queue.Client.
+type Queue struct {
+	Base   queue.Client
+	Config Config
+}
+
+// WrapQueue returns base unchanged when cfg injects nothing.
+func WrapQueue(base queue.Client, cfg Config) queue.Client {
+	if base == nil || !cfg.Enabled() {
+		return base
+	}
+	return &Queue{Base: base, Config: cfg}
+}
+
+// Send injects a fault before delegating to the wrapped queue.
+func (q *Queue) Send(ctx context.Context, msg queue.Message) error {
+	if err := q.Config.inject(ctx, "queue send"); err != nil {
+		return err
+	}
+	return q.Base.Send(ctx, msg)
+}
+
+// LLM injects faults into an llm.Client. Failures default to ErrLLMTimeout.
+type LLM struct {
+	Base   llm.Client
+	Config Config
+}
+
+// WrapLLM returns base unchanged when cfg injects nothing.
+func WrapLLM(base llm.Client, cfg Config) llm.Client {
+	if base == nil || !cfg.Enabled() {
+		return base
+	}
+	if cfg.Err == nil {
+		cfg.Err = ErrLLMTimeout
+	}
+	return &LLM{Base: base, Config: cfg}
+}
+
+// AnalyzeResume injects a fault before delegating to the wrapped client.
+func (l *LLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	if err := l.Config.inject(ctx, "llm analyze"); err != nil {
+		return nil, err
+	}
+	return l.Base.AnalyzeResume(ctx, input)
+}
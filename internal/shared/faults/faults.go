@@ -0,0 +1,77 @@
+// Package faults wraps storage, queue and LLM clients with configurable error
+// and latency injection. Wrappers are only wired in dev and local environments.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInjected is the default error returned by an injected failure.
+var ErrInjected = errors.New("injected fault")
+
+// Config controls how often and how slowly a wrapped client fails.
+type Config struct {
+	// ErrorRate is the probability in [0,1] that a call fails.
+	ErrorRate float64
+	// Latency is added before every call, including ones that fail.
+	Latency time.Duration
+	// Err is returned for injected failures. Nil means ErrInjected.
+	Err error
+}
+
+// Enabled reports whether the config injects anything.
+func (c Config) Enabled() bool {
+	return c.ErrorRate > 0 || c.Latency > 0
+}
+
+// randFloat is swapped in tests to make injection deterministic.
+var randFloat = rand.Float64
+
+// inject waits for the configured latency and then decides whether op fails.
+func (c Config) inject(ctx context.Context, op string) error {
+	if c.Latency > 0 {
+		timer := time.NewTimer(c.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if c.ErrorRate <= 0 {
+		return nil
+	}
+	if c.ErrorRate < 1 && randFloat() >= c.ErrorRate {
+		return nil
+	}
+	base := c.Err
+	if base == nil {
+		base = ErrInjected
+	}
+	return fmt.Errorf("%s: %w", op, base)
+}
+
+// ConfigFromEnv reads RA_FAULT_<COMPONENT>_ERROR_RATE and
+// RA_FAULT_<COMPONENT>_LATENCY_MS, e.g. RA_FAULT_STORE_ERROR_RATE=0.2.
+func ConfigFromEnv(component string) Config {
+	prefix := "RA_FAULT_" + strings.ToUpper(component) + "_"
+	var cfg Config
+	if raw := strings.TrimSpace(os.Getenv(prefix + "ERROR_RATE")); raw != "" {
+		if rate, err := strconv.ParseFloat(raw, 64); err == nil {
+			cfg.ErrorRate = min(max(rate, 0), 1)
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv(prefix + "LATENCY_MS")); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			cfg.Latency = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return cfg
+}
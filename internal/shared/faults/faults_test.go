@@ -0,0 +1,113 @@
+package faults
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"resume-backend/internal/llm"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/storage/object/local"
+)
+
+type countingQueue struct {
+	sent int
+}
+
+func (q *countingQueue) Send(ctx context.Context, msg queue.Message) error {
+	q.sent++
+	return nil
+}
+
+type okLLM struct{}
+
+func (okLLM) AnalyzeResume(ctx context.Context, input llm.AnalyzeInput) (json.RawMessage, error) {
+	return json.RawMessage(`{}`), nil
+}
+
+func TestWrapReturnsBaseWhenDisabled(t *testing.T) {
+	store := local.New(t.TempDir())
+	if got := WrapStore(store, Config{}); got != store {
+		t.Fatalf("expected unwrapped store")
+	}
+	q := &countingQueue{}
+	if got := WrapQueue(q, Config{}); got != q {
+		t.Fatalf("expected unwrapped queue")
+	}
+	if got := WrapLLM(okLLM{}, Config{}); got != (okLLM{}) {
+		t.Fatalf("expected unwrapped llm")
+	}
+}
+
+func TestInjectRespectsErrorRate(t *testing.T) {
+	orig := randFloat
+	defer func() { randFloat = orig }()
+
+	q := &countingQueue{}
+	wrapped := WrapQueue(q, Config{ErrorRate: 0.5})
+
+	randFloat = func() float64 { return 0.9 }
+	if err := wrapped.Send(context.Background(), queue.Message{}); err != nil {
+		t.Fatalf("expected pass-through, got %v", err)
+	}
+	randFloat = func() float64 { return 0.1 }
+	err := wrapped.Send(context.Background(), queue.Message{})
+	if !errors.Is(err, ErrInjected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if q.sent != 1 {
+		t.Fatalf("expected one delivered message, got %d", q.sent)
+	}
+}
+
+func TestStoreInjectsCustomError(t *testing.T) {
+	custom := errors.New("bucket unavailable")
+	wrapped := WrapStore(local.New(t.TempDir()), Config{ErrorRate: 1, Err: custom})
+	if _, _, _, err := wrapped.Save(context.Background(), "user-1", "a.txt", bytes.NewReader([]byte("x"))); !errors.Is(err, custom) {
+		t.Fatalf("expected custom save error, got %v", err)
+	}
+	if _, err := wrapped.Open(context.Background(), "missing"); !errors.Is(err, custom) {
+		t.Fatalf("expected custom open error, got %v", err)
+	}
+	if _, err := wrapped.(*Store).SaveWithKey(context.Background(), "a.txt", "text/plain", bytes.NewReader([]byte("x"))); !errors.Is(err, custom) {
+		t.Fatalf("expected custom save with key error, got %v", err)
+	}
+}
+
+func TestLLMDefaultsToTimeout(t *testing.T) {
+	wrapped := WrapLLM(okLLM{}, Config{ErrorRate: 1})
+	_, err := wrapped.AnalyzeResume(context.Background(), llm.AnalyzeInput{})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrInjected) {
+		t.Fatalf("expected injected timeout, got %v", err)
+	}
+}
+
+func TestLatencyHonorsContext(t *testing.T) {
+	wrapped := WrapStore(local.New(t.TempDir()), Config{Latency: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	rc, err := wrapped.Open(ctx, "missing")
+	if rc != nil {
+		_, _ = io.Copy(io.Discard, rc)
+		rc.Close()
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline, got %v", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("RA_FAULT_STORE_ERROR_RATE", "1.5")
+	t.Setenv("RA_FAULT_STORE_LATENCY_MS", "25")
+	cfg := ConfigFromEnv("store")
+	if cfg.ErrorRate != 1 || cfg.Latency != 25*time.Millisecond {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if ConfigFromEnv("queue").Enabled() {
+		t.Fatalf("expected queue faults disabled")
+	}
+}
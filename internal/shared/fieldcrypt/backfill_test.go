@@ -0,0 +1,104 @@
+package fieldcrypt
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// sealedAs matches a value sealed under keyID that opens to want.
+type sealedAs struct {
+	codec *Codec
+	keyID string
+	want  string
+}
+
+func (m sealedAs) Match(v driver.Value) bool {
+	stored, ok := v.(string)
+	if !ok {
+		return false
+	}
+	keyID, _, _ := parse(stored)
+	plain, err := m.codec.Decrypt("users.email", stored)
+	return keyID == m.keyID && err == nil && plain == m.want
+}
+
+func TestBackfillSealsPlaintextAndRetiredKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	old, _ := New([]Key{testKey("k1", 1)})
+	codec, _ := New([]Key{testKey("k2", 2), testKey("k1", 1)})
+	underK1, _ := old.Encrypt("users.email", "grace@example.com")
+	underK2, _ := codec.Encrypt("users.email", "linus@example.com")
+
+	mock.ExpectQuery(`SELECT id::text, email FROM users WHERE email IS NOT NULL ORDER BY id LIMIT \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).
+			AddRow("u1", "ada@example.com").
+			AddRow("u2", underK1))
+	mock.ExpectExec(`UPDATE users SET email = \$1 WHERE id = \$2 AND email = \$3`).
+		WithArgs(sealedAs{codec: codec, keyID: "k2", want: "ada@example.com"}, "u1", "ada@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// u2 changed after it was read, so the write is skipped.
+	mock.ExpectExec(`UPDATE users SET email = \$1 WHERE id = \$2 AND email = \$3`).
+		WithArgs(sealedAs{codec: codec, keyID: "k2", want: "grace@example.com"}, "u2", underK1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id::text, email FROM users WHERE email IS NOT NULL AND id > \$2 ORDER BY id LIMIT \$1`).
+		WithArgs(2, "u2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).
+			AddRow("u3", underK2))
+
+	backfill := &Backfill{DB: db, Codec: codec, Columns: []Column{{Table: "users", Name: "email"}}, Apply: true, BatchSize: 2}
+	var reports []BackfillReport
+	if err := backfill.Run(context.Background(), func(r BackfillReport) error {
+		reports = append(reports, r)
+		return nil
+	}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+	want := BackfillReport{Table: "users", Column: "email", Scanned: 3, Rewritten: 1, Skipped: 1, Applied: true}
+	if len(reports) != 1 || reports[0] != want {
+		t.Fatalf("reports = %+v, want %+v", reports, want)
+	}
+}
+
+func TestBackfillDryRunCountsWithoutWriting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	codec, _ := New([]Key{testKey("k2", 2)})
+	// k0 was removed from the key list before its values were re-sealed.
+	removed, _ := New([]Key{testKey("k0", 7)})
+	unknown, _ := removed.Encrypt("users.email", "gone@example.com")
+	mock.ExpectQuery(`SELECT id::text, email FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).
+			AddRow("u1", "ada@example.com").
+			AddRow("u2", unknown))
+
+	backfill := &Backfill{DB: db, Codec: codec, Columns: []Column{{Table: "users", Name: "email"}}}
+	var got BackfillReport
+	if err := backfill.Run(context.Background(), func(r BackfillReport) error {
+		got = r
+		return nil
+	}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+	if got.Scanned != 2 || got.Rewritten != 1 || got.Failed != 1 || got.Applied {
+		t.Fatalf("unexpected dry run report %+v", got)
+	}
+}
@@ -0,0 +1,221 @@
+// Package fieldcrypt encrypts individual PII columns before repos write them.
+// Values are sealed with AES-256-GCM under a data key and stored as text
+// tagged with the key's ID, so keys can be rotated while older values stay
+// readable. Values written before encryption was enabled have no tag and are
+// read back as they are until the backfill rewrites them.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length of a data key in bytes.
+const KeySize = 32
+
+// prefix marks an encrypted value: prefix + keyID + ":" + base64(nonce|sealed).
+const prefix = "pii:v1:"
+
+var (
+	// ErrInvalidKey indicates a malformed data key or key list.
+	ErrInvalidKey = errors.New("invalid field encryption key")
+
+	// ErrUnknownKey indicates a value sealed under a key that is not configured.
+	ErrUnknownKey = errors.New("unknown field encryption key")
+)
+
+// Codec seals and opens column values. A nil Codec stores values in the
+// clear, so repos work unchanged when encryption is not configured.
+type Codec struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// Key is a data key and the ID values sealed under it are tagged with.
+type Key struct {
+	ID       string
+	Material []byte
+}
+
+// New constructs a Codec. The first key seals new values; the rest only open
+// values written before a rotation.
+func New(keys []Key) (*Codec, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", ErrInvalidKey)
+	}
+	c := &Codec{active: keys[0].ID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" || strings.ContainsAny(key.ID, ": ,") {
+			return nil, fmt.Errorf("%w: key id %q must be non-empty without ':', ',' or spaces", ErrInvalidKey, key.ID)
+		}
+		if _, dup := c.keys[key.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicate key id %q", ErrInvalidKey, key.ID)
+		}
+		if len(key.Material) != KeySize {
+			return nil, fmt.Errorf("%w: key %q must be %d bytes", ErrInvalidKey, key.ID, KeySize)
+		}
+		block, err := aes.NewCipher(key.Material)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[key.ID] = aead
+	}
+	return c, nil
+}
+
+// ActiveKeyID returns the ID of the key new values are sealed under.
+func (c *Codec) ActiveKeyID() string {
+	if c == nil {
+		return ""
+	}
+	return c.active
+}
+
+// Encrypt seals value for column, a stable name such as "users.email" that is
+// bound to the ciphertext so it cannot be copied into another column. Empty
+// values stay empty.
+func (c *Codec) Encrypt(column, value string) (string, error) {
+	if c == nil || value == "" {
+		return value, nil
+	}
+	aead := c.keys[c.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(column))
+	return prefix + c.active + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value read from column. Values without the encryption tag
+// are returned unchanged.
+func (c *Codec) Decrypt(column, stored string) (string, error) {
+	keyID, payload, ok := parse(stored)
+	if !ok {
+		return stored, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("%w: %s value is encrypted but no keys are configured", ErrUnknownKey, column)
+	}
+	aead, known := c.keys[keyID]
+	if !known {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("decode %s: %w", column, err)
+	}
+	size := aead.NonceSize()
+	if len(sealed) < size {
+		return "", fmt.Errorf("decode %s: sealed value is truncated", column)
+	}
+	plain, err := aead.Open(nil, sealed[:size], sealed[size:], []byte(column))
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", column, err)
+	}
+	return string(plain), nil
+}
+
+// Rotate re-seals stored under the active key. It reports false when the
+// value is empty or already sealed under the active key.
+func (c *Codec) Rotate(column, stored string) (string, bool, error) {
+	if c == nil || stored == "" {
+		return stored, false, nil
+	}
+	if keyID, _, ok := parse(stored); ok && keyID == c.active {
+		return stored, false, nil
+	}
+	plain, err := c.Decrypt(column, stored)
+	if err != nil {
+		return "", false, err
+	}
+	sealed, err := c.Encrypt(column, plain)
+	if err != nil {
+		return "", false, err
+	}
+	return sealed, true, nil
+}
+
+// IsEncrypted reports whether stored carries the encryption tag.
+func IsEncrypted(stored string) bool {
+	_, _, ok := parse(stored)
+	return ok
+}
+
+func parse(stored string) (keyID, payload string, ok bool) {
+	rest, found := strings.CutPrefix(stored, prefix)
+	if !found {
+		return "", "", false
+	}
+	keyID, payload, found = strings.Cut(rest, ":")
+	if !found || keyID == "" {
+		return "", "", false
+	}
+	return keyID, payload, true
+}
+
+// Unwrapper decrypts a wrapped data key, for example with a KMS key.
+type Unwrapper interface {
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// PlainKeys is an Unwrapper for unwrapped keys, for development setups
+// without a KMS.
+type PlainKeys struct{}
+
+// Unwrap returns wrapped unchanged.
+func (PlainKeys) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	return wrapped, nil
+}
+
+// ParseKeys decodes a comma-separated list of "id:base64" entries and unwraps
+// each key. The first entry is the active key.
+func ParseKeys(ctx context.Context, raw string, unwrap Unwrapper) ([]Key, error) {
+	var keys []Key
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: entry must be id:base64", ErrInvalidKey)
+		}
+		wrapped, err := decodeBase64(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q must be base64 encoded", ErrInvalidKey, id)
+		}
+		material, err := unwrap.Unwrap(ctx, wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("unwrap key %q: %w", id, err)
+		}
+		keys = append(keys, Key{ID: strings.TrimSpace(id), Material: material})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", ErrInvalidKey)
+	}
+	return keys, nil
+}
+
+func decodeBase64(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	var lastErr error
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		decoded, err := enc.DecodeString(raw)
+		if err == nil {
+			return decoded, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
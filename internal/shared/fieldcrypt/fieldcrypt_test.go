@@ -0,0 +1,134 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(id string, fill byte) Key {
+	return Key{ID: id, Material: bytes.Repeat([]byte{fill}, KeySize)}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	codec, err := New([]Key{testKey("k1", 1)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	sealed, err := codec.Encrypt("users.email", "ada@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(sealed, "pii:v1:k1:") || strings.Contains(sealed, "ada@example.com") {
+		t.Fatalf("unexpected sealed value %q", sealed)
+	}
+	again, _ := codec.Encrypt("users.email", "ada@example.com")
+	if again == sealed {
+		t.Fatalf("expected a fresh nonce per value")
+	}
+	plain, err := codec.Decrypt("users.email", sealed)
+	if err != nil || plain != "ada@example.com" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+	// The column is bound to the ciphertext.
+	if _, err := codec.Decrypt("analyses.job_description", sealed); err == nil {
+		t.Fatalf("expected a value moved to another column not to open")
+	}
+	if empty, _ := codec.Encrypt("users.email", ""); empty != "" {
+		t.Fatalf("expected empty values to stay empty, got %q", empty)
+	}
+}
+
+func TestCodecReadsPlaintextAndNilCodecPassesThrough(t *testing.T) {
+	codec, _ := New([]Key{testKey("k1", 1)})
+	if plain, err := codec.Decrypt("users.email", "legacy@example.com"); err != nil || plain != "legacy@example.com" {
+		t.Fatalf("expected plaintext passthrough, got %q, %v", plain, err)
+	}
+
+	var none *Codec
+	stored, err := none.Encrypt("users.email", "ada@example.com")
+	if err != nil || stored != "ada@example.com" {
+		t.Fatalf("expected nil codec to store plaintext, got %q, %v", stored, err)
+	}
+	sealed, _ := codec.Encrypt("users.email", "ada@example.com")
+	if _, err := none.Decrypt("users.email", sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey without keys, got %v", err)
+	}
+}
+
+func TestCodecRotation(t *testing.T) {
+	old, _ := New([]Key{testKey("k1", 1)})
+	sealed, _ := old.Encrypt("users.email", "ada@example.com")
+
+	rotated, err := New([]Key{testKey("k2", 2), testKey("k1", 1)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if plain, err := rotated.Decrypt("users.email", sealed); err != nil || plain != "ada@example.com" {
+		t.Fatalf("expected retired key to still open values, got %q, %v", plain, err)
+	}
+	resealed, changed, err := rotated.Rotate("users.email", sealed)
+	if err != nil || !changed || !strings.HasPrefix(resealed, "pii:v1:k2:") {
+		t.Fatalf("Rotate = %q, %v, %v", resealed, changed, err)
+	}
+	if _, changed, _ := rotated.Rotate("users.email", resealed); changed {
+		t.Fatalf("expected values under the active key to be left alone")
+	}
+	if fresh, changed, _ := rotated.Rotate("users.email", "legacy@example.com"); !changed || !IsEncrypted(fresh) {
+		t.Fatalf("expected plaintext to be sealed, got %q", fresh)
+	}
+
+	k2Only, _ := New([]Key{testKey("k2", 2)})
+	if _, err := k2Only.Decrypt("users.email", sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey once k1 is removed, got %v", err)
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	cases := map[string][]Key{
+		"none":      nil,
+		"short":     {{ID: "k1", Material: []byte("short")}},
+		"empty id":  {testKey("", 1)},
+		"colon id":  {testKey("k:1", 1)},
+		"duplicate": {testKey("k1", 1), testKey("k1", 2)},
+	}
+	for name, keys := range cases {
+		if _, err := New(keys); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("%s: expected ErrInvalidKey, got %v", name, err)
+		}
+	}
+}
+
+type reverseUnwrapper struct{}
+
+func (reverseUnwrapper) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	out := make([]byte, len(wrapped))
+	for i, b := range wrapped {
+		out[len(wrapped)-1-i] = b
+	}
+	return out, nil
+}
+
+func TestParseKeys(t *testing.T) {
+	k2 := make([]byte, KeySize)
+	k2[0] = 9
+	raw := " k2:" + base64.StdEncoding.EncodeToString(k2) + ", k1:" + base64.RawURLEncoding.EncodeToString(make([]byte, KeySize))
+	keys, err := ParseKeys(context.Background(), raw, reverseUnwrapper{})
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != "k2" || keys[1].ID != "k1" {
+		t.Fatalf("unexpected keys %+v", keys)
+	}
+	if keys[0].Material[KeySize-1] != 9 {
+		t.Fatalf("expected keys to be unwrapped")
+	}
+	for _, bad := range []string{"", "k1", "k1:not base64!"} {
+		if _, err := ParseKeys(context.Background(), bad, PlainKeys{}); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("ParseKeys(%q): expected ErrInvalidKey, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// KMSKeys unwraps data keys generated with KMS GenerateDataKey. The
+// ciphertext blob names its KMS key, so no key ID is needed here.
+type KMSKeys struct {
+	Client *kms.Client
+}
+
+// NewKMSKeys constructs a KMSKeys from the default AWS configuration.
+func NewKMSKeys(ctx context.Context, region string) (*KMSKeys, error) {
+	loadOpts := []func(*awsconfig.LoadOptions) error{}
+	if region != "" {
+		loadOpts = append(loadOpts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return &KMSKeys{Client: kms.NewFromConfig(cfg)}, nil
+}
+
+// Unwrap decrypts a wrapped data key with KMS.
+func (k *KMSKeys) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := k.Client.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: wrapped})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
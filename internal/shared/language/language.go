@@ -0,0 +1,74 @@
+// Package language guesses the language of resume and job description text.
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Unknown is reported when the text is too short or no language clearly wins.
+const Unknown = "unknown"
+
+// English is the language the base prompts and guardrails are written in.
+const English = "en"
+
+const (
+	minLanguageWords = 40
+	// minStopwordShare is the fraction of words that must be stopwords of the winner.
+	minStopwordShare = 0.05
+)
+
+// stopwords are short function words that dominate running text in each language.
+// Resumes are terse, so the lists stick to words that survive bullet-point style.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "for", "with", "on", "a", "an", "by", "as", "at", "from", "using", "team", "led"},
+	"es": {"de", "la", "el", "en", "y", "los", "las", "del", "con", "para", "por", "una", "un", "al", "equipo", "como"},
+	"fr": {"de", "la", "le", "les", "et", "des", "en", "du", "pour", "avec", "une", "un", "dans", "sur", "au", "équipe"},
+	"de": {"und", "der", "die", "das", "mit", "von", "für", "im", "in", "den", "zur", "bei", "ein", "eine", "auf", "des"},
+	"pt": {"de", "e", "em", "da", "do", "com", "para", "os", "as", "uma", "um", "na", "no", "dos", "das", "equipe"},
+	"it": {"di", "e", "il", "la", "in", "per", "con", "del", "della", "delle", "dei", "una", "un", "nel", "sulla", "gli"},
+	"nl": {"en", "van", "de", "het", "een", "met", "voor", "in", "op", "aan", "bij", "te", "als", "door", "team", "naar"},
+}
+
+var stopwordIndex = buildStopwordIndex()
+
+func buildStopwordIndex() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			index[w] = append(index[w], lang)
+		}
+	}
+	return index
+}
+
+// Detect guesses the dominant language of text from stopword frequencies.
+// Only the language code leaves this function; the text itself is not retained.
+func Detect(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) < minLanguageWords {
+		return Unknown
+	}
+
+	scores := make(map[string]int)
+	for _, w := range words {
+		for _, lang := range stopwordIndex[w] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, runnerUp := "", 0, 0
+	for lang, score := range scores {
+		if score > bestScore {
+			best, bestScore, runnerUp = lang, score, bestScore
+		} else if score > runnerUp {
+			runnerUp = score
+		}
+	}
+	if float64(bestScore) < minStopwordShare*float64(len(words)) || bestScore == runnerUp {
+		return Unknown
+	}
+	return best
+}
@@ -0,0 +1,31 @@
+package language
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	english := strings.Repeat("Led the migration of the billing platform to Go and reduced latency for the payments team with a new caching layer. ", 4)
+	spanish := strings.Repeat("Lideré la migración de la plataforma de pagos y reduje la latencia del sistema con un equipo de cinco personas para los clientes. ", 4)
+	german := strings.Repeat("Leitung der Migration der Plattform mit einem Team von fünf Entwicklern und Verbesserung der Latenz für die Kunden im Zahlungsbereich. ", 4)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "english", text: english, want: "en"},
+		{name: "spanish", text: spanish, want: "es"},
+		{name: "german", text: german, want: "de"},
+		{name: "too short", text: "Go engineer", want: Unknown},
+		{name: "no stopwords", text: strings.Repeat("kubernetes terraform golang postgres ", 20), want: Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Fatalf("Detect = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
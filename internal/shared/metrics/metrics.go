@@ -13,17 +13,39 @@ import (
 )
 
 var (
-	analysisStartedTotal   atomic.Uint64
-	analysisCompletedTotal atomic.Uint64
-	analysisFailedTotal    atomic.Uint64
-	analysisJobsReceivedTotal            atomic.Uint64
-	analysisJobsCompletedTotal           atomic.Uint64
-	analysisJobsFailedTotal              atomic.Uint64
+	analysisStartedTotal                  atomic.Uint64
+	analysisCompletedTotal                atomic.Uint64
+	analysisFailedTotal                   atomic.Uint64
+	analysisJobsReceivedTotal             atomic.Uint64
+	analysisJobsCompletedTotal            atomic.Uint64
+	analysisJobsFailedTotal               atomic.Uint64
 	analysisJobsDeletedUnrecoverableTotal atomic.Uint64
+	analysisJobsDuplicateSkippedTotal     atomic.Uint64
+	workerBatchesBudgetExhaustedTotal     atomic.Uint64
+	workerRecordsDeferredTotal            atomic.Uint64
+	workerRecordsTimedOutTotal            atomic.Uint64
+	stuckStatesDetectedTotal              atomic.Uint64
+	stuckStatesRemediatedTotal            atomic.Uint64
+	stuckStatesOpen                       atomic.Int64
+	abuseFlagsRaisedTotal                 atomic.Uint64
+	abuseRequestsShadowedTotal            atomic.Uint64
+	abuseRequestsBlockedTotal             atomic.Uint64
 
 	analysisDuration = newHistogram([]float64{100, 250, 500, 1000, 2000, 5000, 10000, 30000, 60000})
+
+	analysisQueueDepth          atomic.Int64
+	analysisCompletionLatencyMs atomic.Int64
+
+	llmProviderUp               atomic.Int64
+	llmProbeLatencyMs           atomic.Int64
+	llmRateLimitHeadroomPercent atomic.Int64
 )
 
+func init() {
+	llmProviderUp.Store(1)
+	llmRateLimitHeadroomPercent.Store(-1)
+}
+
 // IncAnalysisStarted increments the started counter.
 func IncAnalysisStarted() {
 	analysisStartedTotal.Add(1)
@@ -59,6 +81,47 @@ func IncAnalysisJobsDeletedUnrecoverable() {
 	analysisJobsDeletedUnrecoverableTotal.Add(1)
 }
 
+// IncAnalysisJobsDuplicateSkipped increments the skipped duplicate deliveries counter.
+func IncAnalysisJobsDuplicateSkipped() {
+	analysisJobsDuplicateSkippedTotal.Add(1)
+}
+
+// RecordWorkerBudgetExhausted records a batch that ran out of time with
+// deferred records still unprocessed.
+func RecordWorkerBudgetExhausted(deferred int) {
+	workerBatchesBudgetExhaustedTotal.Add(1)
+	workerRecordsDeferredTotal.Add(uint64(deferred))
+}
+
+// IncWorkerRecordsTimedOut increments the counter of records cut short by the
+// batch's time budget.
+func IncWorkerRecordsTimedOut() {
+	workerRecordsTimedOutTotal.Add(1)
+}
+
+// RecordStuckStateScan records a stuck-state scan: how many inconsistent
+// records it found and how many of those it repaired.
+func RecordStuckStateScan(found, remediated int) {
+	stuckStatesDetectedTotal.Add(uint64(found))
+	stuckStatesRemediatedTotal.Add(uint64(remediated))
+	stuckStatesOpen.Store(int64(found - remediated))
+}
+
+// IncAbuseFlagRaised counts a cluster newly flagged for abuse review.
+func IncAbuseFlagRaised() {
+	abuseFlagsRaisedTotal.Add(1)
+}
+
+// IncAbuseRequestShadowed counts a guest request enforce mode would have blocked.
+func IncAbuseRequestShadowed() {
+	abuseRequestsShadowedTotal.Add(1)
+}
+
+// IncAbuseRequestBlocked counts a guest request blocked as abuse.
+func IncAbuseRequestBlocked() {
+	abuseRequestsBlockedTotal.Add(1)
+}
+
 // ObserveAnalysisDurationMs records an analysis duration in milliseconds.
 func ObserveAnalysisDurationMs(value float64) {
 	if value < 0 {
@@ -67,6 +130,81 @@ func ObserveAnalysisDurationMs(value float64) {
 	analysisDuration.Observe(value)
 }
 
+// SetAnalysisQueueDepth records the latest observed analysis queue depth.
+func SetAnalysisQueueDepth(depth int) {
+	analysisQueueDepth.Store(int64(depth))
+}
+
+// SetAnalysisCompletionLatencyMs records the latest rolling queue-to-completion latency.
+func SetAnalysisCompletionLatencyMs(value float64) {
+	analysisCompletionLatencyMs.Store(int64(value))
+}
+
+// SetLLMProviderUp records whether the latest LLM provider probe succeeded.
+func SetLLMProviderUp(up bool) {
+	if up {
+		llmProviderUp.Store(1)
+	} else {
+		llmProviderUp.Store(0)
+	}
+}
+
+// LLMProviderUp reports the latest LLM provider probe result; true until a
+// probe has run.
+func LLMProviderUp() bool {
+	return llmProviderUp.Load() == 1
+}
+
+// SetLLMProbe records the latency and rate-limit headroom of an LLM provider
+// probe. A negative headroom means the provider reported no limits.
+func SetLLMProbe(latency time.Duration, headroom float64) {
+	llmProbeLatencyMs.Store(latency.Milliseconds())
+	if headroom < 0 {
+		llmRateLimitHeadroomPercent.Store(-1)
+		return
+	}
+	llmRateLimitHeadroomPercent.Store(int64(headroom * 100))
+}
+
+// RequestSample is one served HTTP request, as delivered to subscribers.
+type RequestSample struct {
+	// Route is the method and route template, e.g. "GET /api/v1/analyses/:id".
+	Route    string
+	Status   int
+	Duration time.Duration
+	At       time.Time
+}
+
+var (
+	subscribersMu sync.RWMutex
+	subscribers   []func(RequestSample)
+)
+
+// SubscribeRequests registers fn to receive every observed request. fn runs on the
+// request goroutine, so it must be quick.
+func SubscribeRequests(fn func(RequestSample)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// ObserveRequest passes a served request to subscribers. Requests that matched no
+// route are ignored.
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		return
+	}
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+	if len(subscribers) == 0 {
+		return
+	}
+	sample := RequestSample{Route: method + " " + route, Status: status, Duration: duration, At: time.Now().UTC()}
+	for _, fn := range subscribers {
+		fn(sample)
+	}
+}
+
 // Handler exposes metrics in Prometheus text format.
 func Handler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -85,7 +223,22 @@ func Render() string {
 	writeCounter(&buf, "analysis_jobs_completed_total", "Total analysis jobs completed", analysisJobsCompletedTotal.Load())
 	writeCounter(&buf, "analysis_jobs_failed_total", "Total analysis jobs failed", analysisJobsFailedTotal.Load())
 	writeCounter(&buf, "analysis_jobs_deleted_unrecoverable_total", "Total analysis jobs deleted due to unrecoverable payloads", analysisJobsDeletedUnrecoverableTotal.Load())
+	writeCounter(&buf, "analysis_jobs_duplicate_skipped_total", "Total duplicate analysis job deliveries skipped", analysisJobsDuplicateSkippedTotal.Load())
+	writeCounter(&buf, "worker_batches_budget_exhausted_total", "Total worker batches that ran out of time before every record was processed", workerBatchesBudgetExhaustedTotal.Load())
+	writeCounter(&buf, "worker_records_deferred_total", "Total worker records returned unprocessed because the batch ran out of time", workerRecordsDeferredTotal.Load())
+	writeCounter(&buf, "worker_records_timed_out_total", "Total worker records cut short by the batch time budget", workerRecordsTimedOutTotal.Load())
+	writeCounter(&buf, "stuck_states_detected_total", "Total inconsistent records found by stuck-state scans", stuckStatesDetectedTotal.Load())
+	writeCounter(&buf, "stuck_states_remediated_total", "Total inconsistent records repaired by stuck-state scans", stuckStatesRemediatedTotal.Load())
+	writeGauge(&buf, "stuck_states_open", "Inconsistent records left unrepaired by the latest stuck-state scan", stuckStatesOpen.Load())
+	writeCounter(&buf, "abuse_flags_raised_total", "Total guest clusters flagged for abuse review", abuseFlagsRaisedTotal.Load())
+	writeCounter(&buf, "abuse_requests_shadowed_total", "Total guest requests over an abuse threshold let through in shadow mode", abuseRequestsShadowedTotal.Load())
+	writeCounter(&buf, "abuse_requests_blocked_total", "Total guest requests blocked as abuse", abuseRequestsBlockedTotal.Load())
 	writeHistogram(&buf, "analysis_duration_ms", "Analysis duration in milliseconds", analysisDuration.Snapshot())
+	writeGauge(&buf, "analysis_queue_depth", "Approximate analysis jobs waiting in the queue", analysisQueueDepth.Load())
+	writeGauge(&buf, "analysis_completion_latency_ms", "Rolling p90 time from analysis creation to completion", analysisCompletionLatencyMs.Load())
+	writeGauge(&buf, "llm_provider_up", "Whether the latest LLM provider probe succeeded", llmProviderUp.Load())
+	writeGauge(&buf, "llm_probe_latency_ms", "Latency of the latest LLM provider probe", llmProbeLatencyMs.Load())
+	writeGauge(&buf, "llm_rate_limit_headroom_percent", "Smallest remaining share of the provider rate limits, or -1 when unreported", llmRateLimitHeadroomPercent.Load())
 	return buf.String()
 }
 
@@ -141,6 +294,12 @@ func writeCounter(buf *bytes.Buffer, name, help string, value uint64) {
 	fmt.Fprintf(buf, "%s %d\n", name, value)
 }
 
+func writeGauge(buf *bytes.Buffer, name, help string, value int64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(buf, "%s %d\n", name, value)
+}
+
 func writeHistogram(buf *bytes.Buffer, name, help string, snap histogramSnapshot) {
 	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
 	fmt.Fprintf(buf, "# TYPE %s histogram\n", name)
@@ -0,0 +1,85 @@
+package runtimeconfig_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/rollout"
+	"resume-backend/internal/shared/config"
+	"resume-backend/internal/shared/runtimeconfig"
+)
+
+func TestReloadRetunesRunningApp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+	write(`{"rateLimits":{"DEFAULT":{"rate":0.001,"burst":1}},"rolloutStages":[20,100]}`)
+	t.Setenv("RA_RUNTIME_CONFIG_FILE", path)
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	t.Cleanup(func() { _ = app.ApplyRuntimeSettings(runtimeconfig.Settings{}) })
+	if app.RuntimeConfig == nil {
+		t.Fatalf("expected a runtime config watcher")
+	}
+
+	health := func(guestID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+		req.Header.Set("X-Guest-Id", guestID)
+		resp := httptest.NewRecorder()
+		app.Router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+	if got := health("guest-1"); got != http.StatusOK {
+		t.Fatalf("first request: expected 200, got %d", got)
+	}
+	if got := health("guest-1"); got != http.StatusTooManyRequests {
+		t.Fatalf("expected the startup document's burst of 1 to apply, got %d", got)
+	}
+	if got := (rollout.Rollout{Status: rollout.StatusRunning}).Percent(); got != 20 {
+		t.Fatalf("expected first rollout stage of 20%%, got %d", got)
+	}
+
+	// Dropping the overrides restores the defaults without a restart.
+	write(`{"logLevel":"info"}`)
+	if err := app.RuntimeConfig.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	// Buckets drained under the old rule refill at the new rate, so use a fresh guest.
+	for i := 0; i < 8; i++ {
+		if got := health("guest-2"); got != http.StatusOK {
+			t.Fatalf("request %d: expected the default burst after reload, got %d", i, got)
+		}
+	}
+	if got := (rollout.Rollout{Status: rollout.StatusRunning}).Percent(); got != rollout.DefaultStages[0] {
+		t.Fatalf("expected default rollout stages after reload, got %d", got)
+	}
+
+	write(`{"rolloutStages":[50]}`)
+	if err := app.RuntimeConfig.Reload(context.Background()); err == nil {
+		t.Fatalf("expected stages not ending at 100 to be rejected")
+	}
+	if got := (rollout.Rollout{Status: rollout.StatusRunning}).Percent(); got != rollout.DefaultStages[0] {
+		t.Fatalf("expected the rejected document to leave stages unchanged, got %d", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Package runtimeconfig reloads operational settings that can change without a
+// restart: the log level, rate limits, rollout stages and guardrail term lists.
+// Structural settings such as the database, buckets and queues stay in config
+// and still need a deploy.
+package runtimeconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"resume-backend/internal/rollout"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// Settings is the reloadable document. Omitted fields fall back to the built-in
+// defaults, so removing a key from the document undoes an earlier override.
+type Settings struct {
+	// LogLevel is "info" or "error".
+	LogLevel string `json:"logLevel,omitempty"`
+	// RateLimits overrides rate limit rules by group, such as DEFAULT or POLLING.
+	RateLimits map[string]RateLimit `json:"rateLimits,omitempty"`
+	// RolloutStages are the candidate percentages of a prompt rollout.
+	RolloutStages []int `json:"rolloutStages,omitempty"`
+	// ForbiddenImpactTerms replaces the analysis content guardrail list. An
+	// empty list disables it.
+	ForbiddenImpactTerms []string `json:"forbiddenImpactTerms,omitempty"`
+	// ResultLimits caps the lists kept in a normalized analysis result.
+	ResultLimits *ResultLimits `json:"resultLimits,omitempty"`
+}
+
+// ResultLimits are the per-list caps; omitted or zero fields keep the default.
+type ResultLimits struct {
+	Issues          int `json:"issues,omitempty"`
+	BulletRewrites  int `json:"bulletRewrites,omitempty"`
+	Recommendations int `json:"recommendations,omitempty"`
+}
+
+// RateLimit is a token bucket refilled at Rate per second up to Burst.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Parse decodes and validates a settings document. Unknown keys are rejected so
+// a typo does not silently leave a setting unchanged.
+func Parse(data []byte) (Settings, error) {
+	var settings Settings
+	if len(bytes.TrimSpace(data)) == 0 {
+		return settings, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&settings); err != nil {
+		return Settings{}, fmt.Errorf("decode runtime config: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+// Validate checks every setting, so a document is applied entirely or not at all.
+func (s Settings) Validate() error {
+	if _, err := telemetry.ParseLevel(s.LogLevel); err != nil {
+		return err
+	}
+	for group, limit := range s.RateLimits {
+		if group == "" || limit.Rate <= 0 || limit.Burst <= 0 {
+			return fmt.Errorf("rate limit %q needs a positive rate and burst", group)
+		}
+	}
+	if l := s.ResultLimits; l != nil && (l.Issues < 0 || l.BulletRewrites < 0 || l.Recommendations < 0) {
+		return fmt.Errorf("result limits must not be negative")
+	}
+	if s.RolloutStages != nil {
+		if err := rollout.ValidateStages(s.RolloutStages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
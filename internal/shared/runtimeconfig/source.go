@@ -0,0 +1,97 @@
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Source fetches the raw settings document.
+type Source interface {
+	Load(ctx context.Context) ([]byte, error)
+	// Describe names the source in logs.
+	Describe() string
+}
+
+// FileSource reads the document from a file, for example one rendered by
+// Terraform into a mounted volume.
+type FileSource struct {
+	Path string
+}
+
+// Load reads the file.
+func (s FileSource) Load(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(s.Path)
+}
+
+// Describe returns the file path.
+func (s FileSource) Describe() string {
+	return "file:" + s.Path
+}
+
+// defaultExtensionEndpoint is the AWS Parameters and Secrets Lambda Extension,
+// which caches SSM parameters locally without an SDK dependency.
+const defaultExtensionEndpoint = "http://localhost:2773"
+
+// SSMSource reads the document from an SSM parameter through the AWS
+// Parameters and Secrets extension.
+type SSMSource struct {
+	Parameter string
+	// Endpoint is the extension's base URL; empty uses localhost:2773.
+	Endpoint string
+	// Token authenticates to the extension; it is the function's session token.
+	Token  string
+	Client *http.Client
+}
+
+// Load fetches and decrypts the parameter value.
+func (s SSMSource) Load(ctx context.Context) ([]byte, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = defaultExtensionEndpoint
+	}
+	query := url.Values{"name": {s.Parameter}, "withDecryption": {"true"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/systemsmanager/parameters/get?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Aws-Parameters-Secrets-Token", s.Token)
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ssm parameter %s: status %d", s.Parameter, resp.StatusCode)
+	}
+	var out struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("ssm parameter %s: %w", s.Parameter, err)
+	}
+	return []byte(out.Parameter.Value), nil
+}
+
+// Describe returns the parameter name.
+func (s SSMSource) Describe() string {
+	return "ssm:" + s.Parameter
+}
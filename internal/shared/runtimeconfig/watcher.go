@@ -0,0 +1,144 @@
+package runtimeconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"resume-backend/internal/shared/telemetry"
+)
+
+const defaultPollInterval = time.Minute
+
+// Watcher loads settings from a Source and applies them when they change.
+type Watcher struct {
+	Source Source
+	// Apply installs validated settings.
+	Apply func(Settings) error
+	// Interval is how often the source is polled.
+	Interval time.Duration
+	Now      func() time.Time
+
+	mu        sync.Mutex
+	applied   string
+	lastCheck time.Time
+}
+
+// FromEnv builds a Watcher from RA_RUNTIME_CONFIG_FILE or
+// RA_RUNTIME_CONFIG_SSM_PARAMETER, polled every RA_RUNTIME_CONFIG_POLL_SECONDS.
+// It returns nil when neither source is set.
+func FromEnv(apply func(Settings) error) *Watcher {
+	var source Source
+	if path := strings.TrimSpace(os.Getenv("RA_RUNTIME_CONFIG_FILE")); path != "" {
+		source = FileSource{Path: path}
+	} else if name := strings.TrimSpace(os.Getenv("RA_RUNTIME_CONFIG_SSM_PARAMETER")); name != "" {
+		ssm := SSMSource{Parameter: name, Token: os.Getenv("AWS_SESSION_TOKEN")}
+		if port := strings.TrimSpace(os.Getenv("PARAMETERS_SECRETS_EXTENSION_HTTP_PORT")); port != "" {
+			ssm.Endpoint = "http://localhost:" + port
+		}
+		source = ssm
+	}
+	if source == nil {
+		return nil
+	}
+	interval := defaultPollInterval
+	if raw := strings.TrimSpace(os.Getenv("RA_RUNTIME_CONFIG_POLL_SECONDS")); raw != "" {
+		if secs, err := strconv.Atoi(raw); err == nil && secs > 0 {
+			interval = time.Duration(secs) * time.Second
+		}
+	}
+	return &Watcher{Source: source, Apply: apply, Interval: interval}
+}
+
+func (w *Watcher) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+// Reload fetches the document and applies it if it changed since the last
+// successful apply. An invalid document is logged and returned, and the
+// previous settings stay in effect.
+func (w *Watcher) Reload(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastCheck = w.now()
+
+	data, err := w.Source.Load(ctx)
+	if err != nil {
+		w.logFailure(err)
+		return err
+	}
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:8])
+	if version == w.applied {
+		return nil
+	}
+	settings, err := Parse(data)
+	if err == nil {
+		err = w.Apply(settings)
+	}
+	if err != nil {
+		w.logFailure(err)
+		return err
+	}
+	w.applied = version
+	telemetry.Info("runtime_config.applied", map[string]any{
+		"source":  w.Source.Describe(),
+		"version": version,
+	})
+	return nil
+}
+
+// Refresh reloads when Interval has passed since the last check. Lambda
+// handlers call it per invocation because background goroutines are frozen
+// between invocations.
+func (w *Watcher) Refresh(ctx context.Context) {
+	w.mu.Lock()
+	due := w.now().Sub(w.lastCheck) >= w.interval()
+	w.mu.Unlock()
+	if due {
+		_ = w.Reload(ctx)
+	}
+}
+
+// Run polls every Interval and reloads immediately on SIGHUP until ctx ends.
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	ticker := time.NewTicker(w.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			_ = w.Reload(ctx)
+		case <-ticker.C:
+			_ = w.Reload(ctx)
+		}
+	}
+}
+
+func (w *Watcher) interval() time.Duration {
+	if w.Interval > 0 {
+		return w.Interval
+	}
+	return defaultPollInterval
+}
+
+func (w *Watcher) logFailure(err error) {
+	telemetry.Error("runtime_config.reload_failed", map[string]any{
+		"source": w.Source.Describe(),
+		"error":  err.Error(),
+	})
+}
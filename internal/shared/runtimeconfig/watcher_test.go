@@ -0,0 +1,129 @@
+package runtimeconfig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherAppliesOnlyValidChangedDocuments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+	var applied []Settings
+	w := &Watcher{Source: FileSource{Path: path}, Apply: func(s Settings) error {
+		applied = append(applied, s)
+		return nil
+	}}
+	ctx := context.Background()
+
+	write(`{"logLevel":"error","rateLimits":{"DEFAULT":{"rate":1,"burst":2}}}`)
+	if err := w.Reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(applied) != 1 || applied[0].LogLevel != "error" || applied[0].RateLimits["DEFAULT"].Burst != 2 {
+		t.Fatalf("unexpected applied settings: %+v", applied)
+	}
+	if err := w.Reload(ctx); err != nil || len(applied) != 1 {
+		t.Fatalf("expected an unchanged document to be skipped, applied=%d err=%v", len(applied), err)
+	}
+
+	for _, bad := range []string{
+		`{"logLevel":"verbose"}`,
+		`{"rolloutStages":[10,5,100]}`,
+		`{"rateLimits":{"DEFAULT":{"rate":0,"burst":1}}}`,
+		`{"resultLimits":{"issues":-1}}`,
+		`{"logLevle":"error"}`,
+		`not json`,
+	} {
+		write(bad)
+		if err := w.Reload(ctx); err == nil {
+			t.Fatalf("expected %s to be rejected", bad)
+		}
+	}
+	if len(applied) != 1 {
+		t.Fatalf("expected invalid documents not to be applied, got %d applies", len(applied))
+	}
+
+	write(`{"rolloutStages":[5,25,100]}`)
+	if err := w.Reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(applied) != 2 || applied[1].LogLevel != "" || len(applied[1].RolloutStages) != 3 {
+		t.Fatalf("expected omitted settings to fall back to defaults, got %+v", applied[1])
+	}
+}
+
+func TestWatcherRefreshHonorsInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	loads := 0
+	w := &Watcher{
+		Source:   countingSource{FileSource{Path: path}, &loads},
+		Apply:    func(Settings) error { return nil },
+		Interval: time.Minute,
+		Now:      func() time.Time { return now },
+	}
+	w.Refresh(context.Background())
+	now = now.Add(30 * time.Second)
+	w.Refresh(context.Background())
+	if loads != 1 {
+		t.Fatalf("expected one load within the interval, got %d", loads)
+	}
+	now = now.Add(time.Minute)
+	w.Refresh(context.Background())
+	if loads != 2 {
+		t.Fatalf("expected a load after the interval, got %d", loads)
+	}
+}
+
+type countingSource struct {
+	FileSource
+	loads *int
+}
+
+func (s countingSource) Load(ctx context.Context) ([]byte, error) {
+	*s.loads++
+	return s.FileSource.Load(ctx)
+}
+
+func TestSSMSourceUsesExtension(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Aws-Parameters-Secrets-Token") != "session-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/systemsmanager/parameters/get" || r.URL.Query().Get("name") != "/resume/runtime" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Parameter":{"Name":"/resume/runtime","Value":"{\"logLevel\":\"error\"}"}}`))
+	}))
+	defer server.Close()
+
+	src := SSMSource{Parameter: "/resume/runtime", Endpoint: server.URL, Token: "session-token"}
+	data, err := src.Load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	settings, err := Parse(data)
+	if err != nil || settings.LogLevel != "error" {
+		t.Fatalf("expected parameter value to parse, got %+v err=%v", settings, err)
+	}
+
+	src.Token = "wrong"
+	if _, err := src.Load(context.Background()); err == nil {
+		t.Fatalf("expected a rejected token to fail")
+	}
+}
@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps sealed secrets in memory and is safe for concurrent use.
+type MemoryBackend struct {
+	mu     sync.RWMutex
+	sealed map[string][]byte
+}
+
+// NewMemoryBackend constructs an empty MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{sealed: make(map[string][]byte)}
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+// Put stores a sealed secret.
+func (b *MemoryBackend) Put(ctx context.Context, ref string, sealed []byte, _ time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sealed[ref] = append([]byte(nil), sealed...)
+	return nil
+}
+
+// Get returns a sealed secret.
+func (b *MemoryBackend) Get(ctx context.Context, ref string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	sealed, ok := b.sealed[ref]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), sealed...), nil
+}
+
+// Delete removes a sealed secret.
+func (b *MemoryBackend) Delete(ctx context.Context, ref string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sealed, ref)
+	return nil
+}
+
+// PGBackend stores sealed secrets in Postgres.
+type PGBackend struct {
+	DB *sql.DB
+}
+
+var _ Backend = (*PGBackend)(nil)
+
+// Put stores a sealed secret.
+func (b *PGBackend) Put(ctx context.Context, ref string, sealed []byte, createdAt time.Time) error {
+	const query = `INSERT INTO secrets (ref, sealed, created_at) VALUES ($1, $2, $3)`
+	_, err := b.DB.ExecContext(ctx, query, ref, sealed, createdAt)
+	return err
+}
+
+// Get returns a sealed secret.
+func (b *PGBackend) Get(ctx context.Context, ref string) ([]byte, error) {
+	var sealed []byte
+	err := b.DB.QueryRowContext(ctx, `SELECT sealed FROM secrets WHERE ref = $1`, ref).Scan(&sealed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return sealed, err
+}
+
+// Delete removes a sealed secret.
+func (b *PGBackend) Delete(ctx context.Context, ref string) error {
+	_, err := b.DB.ExecContext(ctx, `DELETE FROM secrets WHERE ref = $1`, ref)
+	return err
+}
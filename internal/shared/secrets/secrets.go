@@ -0,0 +1,128 @@
+// Package secrets keeps third-party credentials encrypted at rest. Values are
+// sealed with AES-256-GCM before they reach a Backend, so neither the database
+// nor its backups hold them in the clear.
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeySize is the length of the encryption key in bytes.
+const KeySize = 32
+
+var (
+	// ErrNotFound indicates no secret exists for the reference.
+	ErrNotFound = errors.New("secret not found")
+
+	// ErrInvalidKey indicates the configured encryption key is malformed.
+	ErrInvalidKey = errors.New("invalid secrets key")
+)
+
+// Backend persists sealed secrets.
+type Backend interface {
+	Put(ctx context.Context, ref string, sealed []byte, createdAt time.Time) error
+	// Get returns ErrNotFound for unknown references.
+	Get(ctx context.Context, ref string) ([]byte, error)
+	Delete(ctx context.Context, ref string) error
+}
+
+// Store seals and opens secrets. Callers keep only the returned reference.
+type Store struct {
+	backend Backend
+	aead    cipher.AEAD
+	Now     func() time.Time
+}
+
+// New constructs a Store that encrypts with key, which must be KeySize bytes.
+func New(key []byte, backend Backend) (*Store, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: key must be %d bytes", ErrInvalidKey, KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{backend: backend, aead: aead}, nil
+}
+
+// ParseKey decodes a base64 (standard or URL) encoded key.
+func ParseKey(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(raw); err == nil {
+			if len(key) != KeySize {
+				return nil, fmt.Errorf("%w: key must be %d bytes", ErrInvalidKey, KeySize)
+			}
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: key must be base64 encoded", ErrInvalidKey)
+}
+
+// GenerateKey returns a random key, for development setups without a configured one.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Put seals value and returns the reference to store in its place.
+func (s *Store) Put(ctx context.Context, value string) (string, error) {
+	ref := uuid.NewString()
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// The reference is bound as additional data so a sealed value cannot be
+	// swapped under another reference.
+	sealed := s.aead.Seal(nonce, nonce, []byte(value), []byte(ref))
+	if err := s.backend.Put(ctx, ref, sealed, s.now()); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+// Get opens the secret stored under ref.
+func (s *Store) Get(ctx context.Context, ref string) (string, error) {
+	sealed, err := s.backend.Get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	size := s.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("sealed secret is truncated")
+	}
+	plain, err := s.aead.Open(nil, sealed[:size], sealed[size:], []byte(ref))
+	if err != nil {
+		return "", fmt.Errorf("open secret: %w", err)
+	}
+	return string(plain), nil
+}
+
+// Delete removes the secret stored under ref. Unknown references are ignored.
+func (s *Store) Delete(ctx context.Context, ref string) error {
+	return s.backend.Delete(ctx, ref)
+}
+
+func (s *Store) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
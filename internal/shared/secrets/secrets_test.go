@@ -0,0 +1,71 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStoreSealsValuesAtRest(t *testing.T) {
+	ctx := context.Background()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	backend := NewMemoryBackend()
+	store, err := New(key, backend)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	ref, err := store.Put(ctx, "gh-api-key")
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	sealed, err := backend.Get(ctx, ref)
+	if err != nil {
+		t.Fatalf("backend get: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("gh-api-key")) {
+		t.Fatalf("expected the backend to hold ciphertext only")
+	}
+	got, err := store.Get(ctx, ref)
+	if err != nil || got != "gh-api-key" {
+		t.Fatalf("expected round trip, got %q, %v", got, err)
+	}
+
+	// A sealed value moved under another reference must not open.
+	other, _ := store.Put(ctx, "other")
+	if err := backend.Put(ctx, other, sealed, store.now()); err != nil {
+		t.Fatalf("backend put: %v", err)
+	}
+	if _, err := store.Get(ctx, other); err == nil {
+		t.Fatalf("expected swapped ciphertext to be rejected")
+	}
+
+	otherKey, _ := GenerateKey()
+	wrong, _ := New(otherKey, backend)
+	if _, err := wrong.Get(ctx, ref); err == nil {
+		t.Fatalf("expected a different key to fail")
+	}
+
+	if err := store.Delete(ctx, ref); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, ref); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	if _, err := ParseKey("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="); err != nil {
+		t.Fatalf("expected 32-byte key to parse: %v", err)
+	}
+	if _, err := ParseKey("c2hvcnQ="); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected short key to be rejected, got %v", err)
+	}
+	if _, err := ParseKey("not base64!"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected malformed key to be rejected, got %v", err)
+	}
+}
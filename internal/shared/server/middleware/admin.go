@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// RequireAdmin allows only signed-in users whose ID is in adminIDs. Guests are always
+// rejected, and with no admins configured every request is.
+func RequireAdmin(adminIDs []string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(adminIDs))
+	for _, id := range adminIDs {
+		allowed[id] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		if IsGuest(c) {
+			respond.Error(c, http.StatusForbidden, "forbidden", "admin access required", nil)
+			return
+		}
+		// An impersonation token never carries admin rights, whoever it impersonates.
+		if adminID, _ := ImpersonationFromContext(c); adminID != "" {
+			respond.Error(c, http.StatusForbidden, "forbidden", "admin access required", nil)
+			return
+		}
+		if _, ok := allowed[UserIDFromContext(c)]; !ok {
+			respond.Error(c, http.StatusForbidden, "forbidden", "admin access required", nil)
+			return
+		}
+		c.Next()
+	}
+}
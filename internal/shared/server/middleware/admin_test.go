@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/auth"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Auth("dev"))
+	router.GET("/api/v1/admin/stats", RequireAdmin([]string{"admin-1"}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	call := func(setup func(*http.Request)) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stats", nil)
+		setup(req)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+	bearer := func(sub string) func(*http.Request) {
+		token, err := auth.SignJWT(auth.Claims{Sub: sub})
+		if err != nil {
+			t.Fatalf("sign jwt: %v", err)
+		}
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	if code := call(bearer("admin-1")); code != http.StatusOK {
+		t.Fatalf("admin: expected 200, got %d", code)
+	}
+	if code := call(bearer("user-2")); code != http.StatusForbidden {
+		t.Fatalf("non-admin: expected 403, got %d", code)
+	}
+	impersonated, err := auth.SignJWT(auth.Claims{Sub: "admin-1", Act: "admin-2", Sid: "imp-1"})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	if code := call(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+impersonated) }); code != http.StatusForbidden {
+		t.Fatalf("impersonation token: expected 403, got %d", code)
+	}
+	// A guest whose header mimics an admin ID is still a guest.
+	if code := call(func(r *http.Request) { r.Header.Set("X-Guest-Id", "admin-1") }); code != http.StatusForbidden {
+		t.Fatalf("guest: expected 403, got %d", code)
+	}
+}
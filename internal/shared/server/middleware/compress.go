@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCompressMinBytes is the smallest response body worth compressing;
+// below it the gzip framing outweighs the savings.
+const DefaultCompressMinBytes = 1024
+
+// CompressConfig controls response compression.
+type CompressConfig struct {
+	// MinBytes is the smallest body that is compressed; zero uses
+	// DefaultCompressMinBytes.
+	MinBytes int
+	// Level is the gzip level; zero uses gzip.DefaultCompression.
+	Level int
+}
+
+// Compress gzips response bodies for clients that accept it. The body is held
+// back until MinBytes have been written, so small responses go out unchanged
+// with their Content-Length. Handlers that flush, such as streamed lists, are
+// compressed from the first flush. Bodies that are already compressed, like
+// images and DOCX files, are passed through.
+func Compress(cfg CompressConfig) gin.HandlerFunc {
+	minBytes := cfg.MinBytes
+	if minBytes <= 0 {
+		minBytes = DefaultCompressMinBytes
+	}
+	level := cfg.Level
+	if level == 0 || level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+		w := &compressWriter{ResponseWriter: c.Writer, minBytes: minBytes, level: level}
+		c.Writer = w
+		defer func() {
+			w.finish()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding value allows gzip.
+func acceptsGzip(header string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		allowed := true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v <= 0 {
+				allowed = false
+			}
+		}
+		switch name {
+		case "gzip", "x-gzip":
+			return allowed
+		case "*":
+			wildcard = allowed
+		}
+	}
+	return wildcard
+}
+
+// compressWriter buffers the start of a body until it knows whether the body
+// is large enough to compress.
+type compressWriter struct {
+	gin.ResponseWriter
+	minBytes int
+	level    int
+
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minBytes {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports whether the handler has written any of the body, including
+// bytes still held back.
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide picks compression, when large and the response allows it, and writes
+// out the held-back bytes.
+func (w *compressWriter) decide(large bool) error {
+	w.decided = true
+	if large && w.compressible() {
+		header := w.Header()
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", "gzip")
+		gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+		if err != nil {
+			return err
+		}
+		w.gz = gz
+	}
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.gz != nil {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+func (w *compressWriter) compressible() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "image/svg"):
+		return true
+	case strings.HasPrefix(contentType, "image/"),
+		strings.HasPrefix(contentType, "video/"),
+		strings.HasPrefix(contentType, "audio/"),
+		strings.HasPrefix(contentType, "text/event-stream"),
+		strings.HasPrefix(contentType, "application/zip"),
+		strings.HasPrefix(contentType, "application/gzip"),
+		strings.HasPrefix(contentType, "application/pdf"),
+		strings.HasPrefix(contentType, "application/vnd.openxmlformats"):
+		return false
+	}
+	return true
+}
+
+// finish writes out a body that never reached the threshold and closes the
+// gzip stream.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
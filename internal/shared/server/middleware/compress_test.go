@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+func compressRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compress(CompressConfig{MinBytes: 64}))
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": strings.Repeat("resume ", 200)})
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(strings.Repeat("x", 200)))
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		items := make([]int, respond.StreamThreshold+5)
+		for i := range items {
+			items[i] = i
+		}
+		respond.ListJSON(c, respond.CacheRevalidate, items)
+	})
+	router.GET("/error", func(c *gin.Context) {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "bad", nil)
+	})
+	return router
+}
+
+func getCompressed(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func gunzip(t *testing.T, body io.Reader) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	return out
+}
+
+func TestCompressGzipsLargeBodies(t *testing.T) {
+	router := compressRouter()
+
+	resp := getCompressed(router, "/large", "br;q=1.0, gzip;q=0.8")
+	if resp.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip, got headers %v", resp.Header())
+	}
+	if resp.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary: Accept-Encoding, got %q", resp.Header().Get("Vary"))
+	}
+	var body map[string]string
+	if err := json.Unmarshal(gunzip(t, resp.Body), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if !strings.HasPrefix(body["text"], "resume resume") {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
+
+func TestCompressPassesThroughWhenNotWorthIt(t *testing.T) {
+	router := compressRouter()
+	cases := []struct {
+		path, accept string
+	}{
+		{"/small", "gzip"},
+		{"/large", ""},
+		{"/large", "gzip;q=0"},
+		{"/large", "identity"},
+		{"/image", "gzip"},
+		{"/error", "gzip"},
+	}
+	for _, tc := range cases {
+		resp := getCompressed(router, tc.path, tc.accept)
+		if enc := resp.Header().Get("Content-Encoding"); enc != "" {
+			t.Fatalf("%s with %q: expected no encoding, got %q", tc.path, tc.accept, enc)
+		}
+		if resp.Body.Len() == 0 {
+			t.Fatalf("%s with %q: expected a body", tc.path, tc.accept)
+		}
+	}
+}
+
+func TestCompressStreamsLists(t *testing.T) {
+	router := compressRouter()
+
+	resp := getCompressed(router, "/stream", "gzip")
+	if resp.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip, got headers %v", resp.Header())
+	}
+	if resp.Header().Get("ETag") != "" {
+		t.Fatalf("expected no ETag on a streamed list")
+	}
+	var items []int
+	if err := json.Unmarshal(gunzip(t, resp.Body), &items); err != nil {
+		t.Fatalf("decode streamed list: %v", err)
+	}
+	if len(items) != respond.StreamThreshold+5 || items[len(items)-1] != len(items)-1 {
+		t.Fatalf("unexpected items %v", items)
+	}
+}
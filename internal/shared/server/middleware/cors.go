@@ -25,8 +25,8 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 				h.Set("Vary", "Origin")
 				h.Set("Access-Control-Allow-Credentials", "true")
 				h.Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
-				h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Guest-Id, X-Retry-Analysis, X-User-Id, X-Request-Id")
-				h.Set("Access-Control-Expose-Headers", "X-Request-Id")
+				h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Guest-Id, X-Retry-Analysis, X-User-Id, X-Request-Id, X-Org-Id, X-Device-Fingerprint")
+				h.Set("Access-Control-Expose-Headers", "X-Request-Id, X-Render-Warnings, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-Usage-Remaining")
 				h.Set("Access-Control-Max-Age", "600")
 			}
 		}
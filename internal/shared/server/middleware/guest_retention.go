@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestRetentionHeader advertises how many days guest data is kept.
+const GuestRetentionHeader = "X-Guest-Retention-Days"
+
+// GuestRetention tells guests how long their documents and analyses are kept.
+// It must run after Auth so the guest flag is set.
+func GuestRetention(ttl time.Duration) gin.HandlerFunc {
+	days := strconv.Itoa(int(ttl / (24 * time.Hour)))
+	return func(c *gin.Context) {
+		if ttl > 0 && IsGuest(c) {
+			c.Writer.Header().Set(GuestRetentionHeader, days)
+		}
+		c.Next()
+	}
+}
+
+// IsGuest reports whether the auth middleware identified the caller as a guest.
+func IsGuest(c *gin.Context) bool {
+	if c == nil {
+		return false
+	}
+	val, _ := c.Get("isGuest")
+	guest, _ := val.(bool)
+	return guest
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGuestRetentionHeaderOnlyForGuests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Auth("dev"), GuestRetention(14*24*time.Hour))
+	router.GET("/api/v1/documents/current", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/current", nil)
+	req.Header.Set("X-Guest-Id", "guest-123")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if got := resp.Header().Get(GuestRetentionHeader); got != "14" {
+		t.Fatalf("expected retention header 14, got %q", got)
+	}
+
+	disabled := gin.New()
+	disabled.Use(Auth("dev"), GuestRetention(0))
+	disabled.GET("/api/v1/documents/current", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	resp = httptest.NewRecorder()
+	disabled.ServeHTTP(resp, req)
+	if got := resp.Header().Get(GuestRetentionHeader); got != "" {
+		t.Fatalf("expected no retention header when disabled, got %q", got)
+	}
+}
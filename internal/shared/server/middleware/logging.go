@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"resume-backend/internal/shared/metrics"
 	"resume-backend/internal/shared/telemetry"
 )
 
@@ -21,12 +22,14 @@ func Logging() gin.HandlerFunc {
 		c.Next()
 		latency := time.Since(start)
 		status := c.Writer.Status()
+		metrics.ObserveRequest(c.Request.Method, c.FullPath(), status, latency)
 		reqID := RequestIDFromContext(c)
 
 		userID, _ := c.Get(userIDKey)
 		isGuest, _ := c.Get("isGuest")
 		documentID, _ := c.Get("documentId")
 		analysisID, _ := c.Get("analysisId")
+		impersonatorID, _ := ImpersonationFromContext(c)
 		statusTransition := ""
 		if raw, ok := c.Get("statusTransition"); ok {
 			if s, ok := raw.(string); ok {
@@ -45,6 +48,7 @@ func Logging() gin.HandlerFunc {
 			"document_id":       documentID,
 			"analysis_id":       analysisID,
 			"is_guest":          isGuest,
+			"impersonator_id":   impersonatorID,
 			"client_ip":         c.ClientIP(),
 			"user_agent":        c.Request.UserAgent(),
 		})
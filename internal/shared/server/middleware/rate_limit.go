@@ -21,12 +21,46 @@ type RateLimitRule struct {
 }
 
 type RateLimitConfig struct {
-	Rules        map[string]RateLimitRule
+	Rules map[string]RateLimitRule
+	// Dynamic, when set, replaces Rules and can be changed while serving.
+	Dynamic      *RateLimitRules
 	DefaultGroup string
 	GroupFor     func(*gin.Context) string
 	Limiter      *RateLimiter
 }
 
+// RateLimitRules is a rule set that can be swapped at runtime, for example by
+// a config reload.
+type RateLimitRules struct {
+	mu    sync.RWMutex
+	rules map[string]RateLimitRule
+}
+
+func NewRateLimitRules(rules map[string]RateLimitRule) *RateLimitRules {
+	r := &RateLimitRules{}
+	r.Set(rules)
+	return r
+}
+
+// Set replaces all rules.
+func (r *RateLimitRules) Set(rules map[string]RateLimitRule) {
+	copied := make(map[string]RateLimitRule, len(rules))
+	for group, rule := range rules {
+		copied[group] = rule
+	}
+	r.mu.Lock()
+	r.rules = copied
+	r.mu.Unlock()
+}
+
+// Get returns the rule for group.
+func (r *RateLimitRules) Get(group string) (RateLimitRule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rule, ok := r.rules[group]
+	return rule, ok
+}
+
 type RateLimiter struct {
 	mu      sync.Mutex
 	buckets map[string]*rateBucket
@@ -62,7 +96,13 @@ func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 				group = g
 			}
 		}
-		rule, ok := cfg.Rules[group]
+		var rule RateLimitRule
+		var ok bool
+		if cfg.Dynamic != nil {
+			rule, ok = cfg.Dynamic.Get(group)
+		} else {
+			rule, ok = cfg.Rules[group]
+		}
 		if !ok {
 			c.Next()
 			return
@@ -72,12 +112,13 @@ func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 			principal = strings.TrimSpace(c.ClientIP())
 		}
 		key := principal + "|" + group
-		allowed, retryAfter := cfg.Limiter.Allow(key, rule)
-		if allowed {
+		status := cfg.Limiter.Take(key, rule)
+		writeRateLimitHeaders(c, status)
+		if status.Allowed {
 			c.Next()
 			return
 		}
-		retryAfterMs := int(retryAfter / time.Millisecond)
+		retryAfterMs := int(status.RetryAfter / time.Millisecond)
 		if retryAfterMs <= 0 {
 			retryAfterMs = 1000
 		}
@@ -94,12 +135,48 @@ func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 	}
 }
 
+// Rate limit headers written on every limited response. Reset is the number of
+// seconds until the bucket is full again.
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimitStatus is the outcome of one Take and the bucket state after it.
+type RateLimitStatus struct {
+	Allowed bool
+	// Limit is the bucket size; zero when the rule does not limit.
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is set when the request was refused.
+	RetryAfter time.Duration
+}
+
+func writeRateLimitHeaders(c *gin.Context, status RateLimitStatus) {
+	if status.Limit <= 0 {
+		return
+	}
+	c.Header(RateLimitLimitHeader, strconv.Itoa(status.Limit))
+	c.Header(RateLimitRemainingHeader, strconv.Itoa(status.Remaining))
+	c.Header(RateLimitResetHeader, strconv.Itoa(int(math.Ceil(status.Reset.Seconds()))))
+}
+
 func (l *RateLimiter) Allow(key string, rule RateLimitRule) (bool, time.Duration) {
+	status := l.Take(key, rule)
+	return status.Allowed, status.RetryAfter
+}
+
+// Take spends a token from key's bucket if one is left and reports the
+// bucket's state afterwards.
+func (l *RateLimiter) Take(key string, rule RateLimitRule) RateLimitStatus {
 	if l == nil {
-		return true, 0
+		return RateLimitStatus{Allowed: true}
 	}
 	if rule.Rate <= 0 || rule.Burst <= 0 {
-		return true, 0
+		return RateLimitStatus{Allowed: true}
 	}
 	now := l.now()
 	l.mu.Lock()
@@ -117,15 +194,19 @@ func (l *RateLimiter) Allow(key string, rule RateLimitRule) (bool, time.Duration
 		bucket.tokens = math.Min(float64(rule.Burst), bucket.tokens+elapsed*rule.Rate)
 		bucket.last = now
 	}
+	status := RateLimitStatus{Limit: rule.Burst}
 	if bucket.tokens >= 1 {
 		bucket.tokens -= 1
-		return true, 0
-	}
-	needed := 1 - bucket.tokens
-	waitSec := needed / rule.Rate
-	if waitSec < 0 {
-		waitSec = 0
+		status.Allowed = true
+	} else {
+		needed := 1 - bucket.tokens
+		waitSec := needed / rule.Rate
+		if waitSec < 0 {
+			waitSec = 0
+		}
+		status.RetryAfter = time.Duration(math.Ceil(waitSec*1000.0)) * time.Millisecond
 	}
-	retryAfter := time.Duration(math.Ceil(waitSec*1000.0)) * time.Millisecond
-	return false, retryAfter
+	status.Remaining = int(math.Floor(bucket.tokens))
+	status.Reset = time.Duration(math.Ceil((float64(rule.Burst)-bucket.tokens)/rule.Rate*1000.0)) * time.Millisecond
+	return status
 }
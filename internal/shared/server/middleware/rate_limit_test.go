@@ -122,3 +122,50 @@ func TestRateLimit429IncludesRetryAfter(t *testing.T) {
 		t.Fatalf("expected retryAfterMs in response")
 	}
 }
+
+func TestRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(func() time.Time { return now })
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userId", "user-1")
+		c.Next()
+	})
+	r.Use(RateLimit(RateLimitConfig{
+		Limiter: limiter,
+		Rules:   map[string]RateLimitRule{"DEFAULT": {Rate: 0.5, Burst: 2}},
+	}))
+	r.POST("/analyze", func(c *gin.Context) { c.Status(http.StatusAccepted) })
+
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/analyze", nil))
+		return w
+	}
+	want := []struct {
+		code      int
+		remaining string
+		reset     string
+	}{
+		{http.StatusAccepted, "1", "2"},
+		{http.StatusAccepted, "0", "4"},
+		{http.StatusTooManyRequests, "0", "4"},
+	}
+	for i, w := range want {
+		resp := do()
+		if resp.Code != w.code {
+			t.Fatalf("request %d: expected %d, got %d", i, w.code, resp.Code)
+		}
+		h := resp.Header()
+		if h.Get(RateLimitLimitHeader) != "2" || h.Get(RateLimitRemainingHeader) != w.remaining || h.Get(RateLimitResetHeader) != w.reset {
+			t.Fatalf("request %d: unexpected headers limit=%q remaining=%q reset=%q", i, h.Get(RateLimitLimitHeader), h.Get(RateLimitRemainingHeader), h.Get(RateLimitResetHeader))
+		}
+	}
+
+	now = now.Add(2 * time.Second)
+	if resp := do(); resp.Code != http.StatusAccepted || resp.Header().Get(RateLimitRemainingHeader) != "0" {
+		t.Fatalf("expected a refilled token to be spent, got %d remaining=%q", resp.Code, resp.Header().Get(RateLimitRemainingHeader))
+	}
+}
@@ -3,10 +3,13 @@ package middleware
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"resume-backend/internal/shared/ctxmeta"
 )
 
 const requestIDKey = "requestId"
@@ -20,6 +23,10 @@ func RequestID() gin.HandlerFunc {
 		}
 		c.Set(requestIDKey, id)
 		c.Writer.Header().Set("X-Request-Id", id)
+
+		ctx := ctxmeta.WithRequestID(c.Request.Context(), id)
+		ctx = ctxmeta.WithTraceID(ctx, traceIDFromHeader(c.GetHeader("traceparent")))
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
@@ -36,6 +43,19 @@ func RequestIDFromContext(c *gin.Context) string {
 	return ""
 }
 
+// traceIDFromHeader extracts the trace ID from a W3C traceparent header
+// ("00-<trace-id>-<parent-id>-<flags>"). It returns "" for malformed headers.
+func traceIDFromHeader(traceparent string) string {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
+
 func generateRequestID() string {
 	// Prefer uuid for readability; fall back to random/ts.
 	if u, err := uuid.NewRandom(); err == nil {
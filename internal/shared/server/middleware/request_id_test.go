@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+func TestRequestIDPropagatesToRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var requestID, userID, traceID string
+	router := gin.New()
+	router.Use(RequestID(), Auth("dev"))
+	router.GET("/test", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		requestID, userID, traceID = ctxmeta.RequestID(ctx), ctxmeta.UserID(ctx), ctxmeta.TraceID(ctx)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("X-Guest-Id", "guest1")
+	req.Header.Set("traceparent", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if requestID != "req-1" {
+		t.Fatalf("expected request id on context, got %q", requestID)
+	}
+	if userID != "guest:guest1" {
+		t.Fatalf("expected guest user id on context, got %q", userID)
+	}
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected trace id from traceparent, got %q", traceID)
+	}
+}
+
+func TestTraceIDFromHeaderRejectsMalformed(t *testing.T) {
+	for _, header := range []string{"", "garbage", "00-xyz-00f067aa0ba902b7-01", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"} {
+		if got := traceIDFromHeader(header); got != "" {
+			t.Fatalf("expected no trace id for %q, got %q", header, got)
+		}
+	}
+}
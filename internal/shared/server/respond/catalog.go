@@ -0,0 +1,113 @@
+package respond
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogEntry describes one error code the API can return. A code returned
+// with more than one status has an entry per status.
+type CatalogEntry struct {
+	Code   string `json:"code"`
+	Status int    `json:"status"`
+	// Retryable is true when the same request may succeed later unchanged.
+	Retryable bool `json:"retryable"`
+	// MessageKey names the user-facing copy for the code in the frontend.
+	MessageKey string `json:"messageKey"`
+	// Message is the code's message in the negotiated language. It is set
+	// only by the ErrorCatalog endpoint.
+	Message string `json:"message,omitempty"`
+}
+
+// catalog lists every code passed to Error, plus the rate limiter's
+// rate_limited. catalog_test.go fails when a handler uses a code or status that
+// is missing here.
+var catalog = []CatalogEntry{
+	{Code: "analysis_not_completed", Status: http.StatusConflict},
+	{Code: "analysis_pending", Status: http.StatusConflict, Retryable: true},
+	{Code: "archive_keys_required", Status: http.StatusConflict},
+	{Code: "audit_unavailable", Status: http.StatusServiceUnavailable, Retryable: true},
+	{Code: "auth_failed", Status: http.StatusBadGateway, Retryable: true},
+	{Code: "auth_not_configured", Status: http.StatusInternalServerError},
+	{Code: "conflict", Status: http.StatusConflict},
+	{Code: "content_type_mismatch", Status: http.StatusUnsupportedMediaType},
+	{Code: "document_not_ready", Status: http.StatusConflict, Retryable: true},
+	{Code: "feature_disabled", Status: http.StatusForbidden},
+	{Code: "fetch_failed", Status: http.StatusBadGateway, Retryable: true},
+	{Code: "file_too_large", Status: http.StatusRequestEntityTooLarge},
+	{Code: "forbidden", Status: http.StatusForbidden},
+	{Code: "impersonation_inactive", Status: http.StatusUnauthorized},
+	{Code: "impersonation_read_only", Status: http.StatusForbidden},
+	{Code: "integrations_unavailable", Status: http.StatusServiceUnavailable, Retryable: true},
+	{Code: "internal", Status: http.StatusInternalServerError, Retryable: true},
+	{Code: "internal_error", Status: http.StatusInternalServerError, Retryable: true},
+	{Code: "invalid_analysis", Status: http.StatusBadRequest},
+	{Code: "invalid_llm_output", Status: http.StatusBadGateway, Retryable: true},
+	{Code: "invalid_request", Status: http.StatusBadRequest},
+	{Code: "invalid_resume_model", Status: http.StatusBadGateway, Retryable: true},
+	{Code: "invalid_transition", Status: http.StatusConflict},
+	{Code: "job_description_needs_confirmation", Status: http.StatusUnprocessableEntity},
+	{Code: "limit_reached", Status: http.StatusTooManyRequests},
+	{Code: "llm_budget_exceeded", Status: http.StatusTooManyRequests, Retryable: true},
+	{Code: "llm_probe_unavailable", Status: http.StatusServiceUnavailable, Retryable: true},
+	{Code: "login_required", Status: http.StatusUnauthorized},
+	{Code: "missing_required_fields", Status: http.StatusBadRequest},
+	{Code: "needs_input", Status: http.StatusUnprocessableEntity},
+	{Code: "not_found", Status: http.StatusNotFound},
+	{Code: "not_supported", Status: http.StatusNotImplemented},
+	{Code: "overloaded", Status: http.StatusServiceUnavailable, Retryable: true},
+	{Code: "plan_required", Status: http.StatusForbidden},
+	{Code: "rate_limited", Status: http.StatusTooManyRequests, Retryable: true},
+	{Code: "read_only", Status: http.StatusConflict},
+	{Code: "replay_unavailable", Status: http.StatusConflict},
+	{Code: "residency_conflict", Status: http.StatusConflict},
+	{Code: "resume_incomplete", Status: http.StatusUnprocessableEntity},
+	{Code: "retry_required", Status: http.StatusConflict},
+	{Code: "rollout_active", Status: http.StatusConflict},
+	{Code: "share_link_exhausted", Status: http.StatusGone},
+	{Code: "share_link_expired", Status: http.StatusGone},
+	{Code: "share_link_revoked", Status: http.StatusGone},
+	{Code: "stuck_scan_failed", Status: http.StatusInternalServerError, Retryable: true},
+	{Code: "template_invalid", Status: http.StatusUnprocessableEntity},
+	{Code: "timeout", Status: http.StatusRequestTimeout, Retryable: true},
+	{Code: "unauthorized", Status: http.StatusUnauthorized},
+	{Code: "unreadable_document", Status: http.StatusUnprocessableEntity},
+	{Code: "unsupported_pipeline", Status: http.StatusUnprocessableEntity},
+	{Code: "validation_error", Status: http.StatusBadRequest},
+	{Code: "validation_error", Status: http.StatusRequestEntityTooLarge},
+}
+
+// Catalog returns the error catalog ordered by code, then status. Every
+// entry's MessageKey is "errors.<code>".
+func Catalog() []CatalogEntry {
+	out := make([]CatalogEntry, len(catalog))
+	for i, entry := range catalog {
+		entry.MessageKey = "errors." + entry.Code
+		out[i] = entry
+	}
+	slices.SortFunc(out, func(a, b CatalogEntry) int {
+		if c := strings.Compare(a.Code, b.Code); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Status, b.Status)
+	})
+	return out
+}
+
+// ErrorCatalog serves the error catalog with each code's message in the
+// language negotiated from Accept-Language. It changes only with a deploy, so
+// clients may cache it per language.
+func ErrorCatalog(c *gin.Context) {
+	lang := requestLanguage(c)
+	items := Catalog()
+	for i := range items {
+		items[i].Message, _ = Message(items[i].Code, lang)
+	}
+	c.Header("Content-Language", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	CachedJSON(c, "public, max-age=3600", gin.H{"language": lang, "items": items})
+}
@@ -0,0 +1,154 @@
+package respond
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestCatalogCoversHandlerCodes parses every non-test file under internal/ and
+// checks that each literal code passed with an http.Status constant to Error,
+// or to a local fail helper wrapping it, has a catalog entry with that status.
+func TestCatalogCoversHandlerCodes(t *testing.T) {
+	known := map[string]bool{}
+	for _, entry := range catalog {
+		known[entry.Code+" "+strconv.Itoa(entry.Status)] = true
+	}
+
+	root := filepath.Join("..", "..", "..")
+	found := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isErrorCall(call.Fun) {
+				return true
+			}
+			for i := 0; i+1 < len(call.Args); i++ {
+				status, ok := httpStatus(call.Args[i])
+				if !ok {
+					continue
+				}
+				lit, ok := call.Args[i+1].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					break
+				}
+				code, _ := strconv.Unquote(lit.Value)
+				found++
+				if !known[code+" "+strconv.Itoa(status)] {
+					t.Errorf("%s: code %q with status %d is missing from the error catalog", path, code, status)
+				}
+				break
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk sources: %v", err)
+	}
+	if found < 100 {
+		t.Fatalf("expected to find the handlers' error calls, found %d", found)
+	}
+}
+
+func isErrorCall(fun ast.Expr) bool {
+	switch f := fun.(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := f.X.(*ast.Ident)
+		return ok && pkg.Name == "respond" && f.Sel.Name == "Error"
+	case *ast.Ident:
+		return f.Name == "Error" || f.Name == "fail"
+	}
+	return false
+}
+
+var statusCodes = map[string]int{
+	"StatusBadRequest":            http.StatusBadRequest,
+	"StatusUnauthorized":          http.StatusUnauthorized,
+	"StatusForbidden":             http.StatusForbidden,
+	"StatusNotFound":              http.StatusNotFound,
+	"StatusRequestTimeout":        http.StatusRequestTimeout,
+	"StatusConflict":              http.StatusConflict,
+	"StatusGone":                  http.StatusGone,
+	"StatusRequestEntityTooLarge": http.StatusRequestEntityTooLarge,
+	"StatusUnsupportedMediaType":  http.StatusUnsupportedMediaType,
+	"StatusUnprocessableEntity":   http.StatusUnprocessableEntity,
+	"StatusTooManyRequests":       http.StatusTooManyRequests,
+	"StatusInternalServerError":   http.StatusInternalServerError,
+	"StatusNotImplemented":        http.StatusNotImplemented,
+	"StatusBadGateway":            http.StatusBadGateway,
+	"StatusServiceUnavailable":    http.StatusServiceUnavailable,
+}
+
+func httpStatus(expr ast.Expr) (int, bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return 0, false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "http" || !strings.HasPrefix(sel.Sel.Name, "Status") {
+		return 0, false
+	}
+	status, ok := statusCodes[sel.Sel.Name]
+	if !ok {
+		return -1, true
+	}
+	return status, true
+}
+
+func TestErrorCatalogEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/meta/error-codes", ErrorCatalog)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/meta/error-codes", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Items []CatalogEntry `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Items) != len(catalog) {
+		t.Fatalf("expected %d entries, got %d", len(catalog), len(body.Items))
+	}
+	for i, entry := range body.Items {
+		if entry.MessageKey != "errors."+entry.Code {
+			t.Fatalf("unexpected message key: %+v", entry)
+		}
+		if i > 0 && body.Items[i-1].Code > entry.Code {
+			t.Fatalf("expected entries ordered by code, got %s before %s", body.Items[i-1].Code, entry.Code)
+		}
+	}
+	var notFound CatalogEntry
+	for _, entry := range body.Items {
+		if entry.Code == "not_found" {
+			notFound = entry
+		}
+	}
+	if notFound.Status != http.StatusNotFound || notFound.Retryable {
+		t.Fatalf("unexpected not_found entry: %+v", notFound)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Fatalf("expected an ETag on the catalog")
+	}
+}
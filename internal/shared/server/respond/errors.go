@@ -21,7 +21,10 @@ type ErrorResponse struct {
 	Error ErrorBody `json:"error"`
 }
 
-// Error sends a standardized error response.
+// Error sends a standardized error response. When the request's
+// Accept-Language negotiates a language other than English and the code has a
+// message in it, that message replaces the handler's English one; the code is
+// unchanged either way.
 func Error(c *gin.Context, status int, code, message string, details interface{}) {
 	if c.Writer.Written() {
 		c.Abort()
@@ -50,6 +53,13 @@ func Error(c *gin.Context, status int, code, message string, details interface{}
 	}
 	telemetry.Error("http.error", fields)
 
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	if lang := requestLanguage(c); lang != DefaultLanguage {
+		if localized, ok := messages[code][lang]; ok {
+			message = localized
+			c.Header("Content-Language", lang)
+		}
+	}
 	c.Header("Content-Type", "application/json; charset=utf-8")
 	c.AbortWithStatusJSON(status, ErrorResponse{
 		Error: ErrorBody{
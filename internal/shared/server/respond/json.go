@@ -1,11 +1,20 @@
 package respond
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CacheRevalidate is the Cache-Control value for per-user reads that change
+// over time: only the client may cache them, and it must revalidate with the
+// ETag before reuse.
+const CacheRevalidate = "private, no-cache"
+
 // JSON writes a JSON response with the given status.
 func JSON(c *gin.Context, status int, payload interface{}) {
 	c.JSON(status, payload)
@@ -15,3 +24,42 @@ func JSON(c *gin.Context, status int, payload interface{}) {
 func OK(c *gin.Context, payload interface{}) {
 	JSON(c, http.StatusOK, payload)
 }
+
+// CachedJSON writes a 200 JSON response with an ETag of the encoded body and
+// the given Cache-Control value. When the request's If-None-Match already
+// names that ETag, it writes 304 Not Modified without a body instead.
+func CachedJSON(c *gin.Context, cacheControl string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "internal_error", "failed to encode response", err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	if ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// ETagMatches reports whether an If-None-Match header value names etag,
+// comparing weakly as RFC 9110 requires for If-None-Match.
+func ETagMatches(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,441 @@
+package respond
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultLanguage is the language handlers write their messages in.
+const DefaultLanguage = "en"
+
+// messages holds the user-facing message for each catalog code by language.
+// Error replaces the handler's English message with the one here when the
+// request negotiates another language; English responses keep the handler's
+// more specific message. catalog_test.go fails when a catalog code lacks a
+// message in any supported language.
+var messages = map[string]map[string]string{
+	"analysis_not_completed": {
+		"en": "The analysis has not completed yet.",
+		"es": "El análisis aún no ha terminado.",
+		"fr": "L'analyse n'est pas encore terminée.",
+		"de": "Die Analyse ist noch nicht abgeschlossen.",
+		"pt": "A análise ainda não foi concluída.",
+	},
+	"analysis_pending": {
+		"en": "The analysis is still running. Try again shortly.",
+		"es": "El análisis sigue en curso. Inténtalo de nuevo en breve.",
+		"fr": "L'analyse est toujours en cours. Réessayez dans un instant.",
+		"de": "Die Analyse läuft noch. Versuche es gleich noch einmal.",
+		"pt": "A análise ainda está em andamento. Tente novamente em instantes.",
+	},
+	"archive_keys_required": {
+		"en": "Archive keys must be configured first.",
+		"es": "Primero hay que configurar las claves del archivo.",
+		"fr": "Les clés d'archivage doivent d'abord être configurées.",
+		"de": "Zuerst müssen die Archivschlüssel eingerichtet werden.",
+		"pt": "As chaves do arquivo precisam ser configuradas primeiro.",
+	},
+	"audit_unavailable": {
+		"en": "The audit log is unavailable. Try again later.",
+		"es": "El registro de auditoría no está disponible. Inténtalo más tarde.",
+		"fr": "Le journal d'audit est indisponible. Réessayez plus tard.",
+		"de": "Das Audit-Protokoll ist nicht verfügbar. Versuche es später erneut.",
+		"pt": "O registro de auditoria está indisponível. Tente novamente mais tarde.",
+	},
+	"auth_failed": {
+		"en": "Sign-in could not be completed. Try again.",
+		"es": "No se pudo completar el inicio de sesión. Inténtalo de nuevo.",
+		"fr": "La connexion n'a pas pu aboutir. Réessayez.",
+		"de": "Die Anmeldung konnte nicht abgeschlossen werden. Versuche es erneut.",
+		"pt": "Não foi possível concluir o login. Tente novamente.",
+	},
+	"auth_not_configured": {
+		"en": "Sign-in is not configured on this server.",
+		"es": "El inicio de sesión no está configurado en este servidor.",
+		"fr": "La connexion n'est pas configurée sur ce serveur.",
+		"de": "Die Anmeldung ist auf diesem Server nicht eingerichtet.",
+		"pt": "O login não está configurado neste servidor.",
+	},
+	"conflict": {
+		"en": "The request conflicts with the current state.",
+		"es": "La solicitud entra en conflicto con el estado actual.",
+		"fr": "La requête est en conflit avec l'état actuel.",
+		"de": "Die Anfrage steht im Konflikt mit dem aktuellen Zustand.",
+		"pt": "A solicitação entra em conflito com o estado atual.",
+	},
+	"content_type_mismatch": {
+		"en": "The file's content does not match its type.",
+		"es": "El contenido del archivo no coincide con su tipo.",
+		"fr": "Le contenu du fichier ne correspond pas à son type.",
+		"de": "Der Inhalt der Datei passt nicht zu ihrem Typ.",
+		"pt": "O conteúdo do arquivo não corresponde ao seu tipo.",
+	},
+	"document_not_ready": {
+		"en": "The document is still being processed. Try again shortly.",
+		"es": "El documento todavía se está procesando. Inténtalo de nuevo en breve.",
+		"fr": "Le document est encore en cours de traitement. Réessayez dans un instant.",
+		"de": "Das Dokument wird noch verarbeitet. Versuche es gleich noch einmal.",
+		"pt": "O documento ainda está sendo processado. Tente novamente em instantes.",
+	},
+	"feature_disabled": {
+		"en": "This feature is not available for your account.",
+		"es": "Esta función no está disponible para tu cuenta.",
+		"fr": "Cette fonctionnalité n'est pas disponible pour votre compte.",
+		"de": "Diese Funktion ist für dein Konto nicht verfügbar.",
+		"pt": "Este recurso não está disponível para a sua conta.",
+	},
+	"fetch_failed": {
+		"en": "The file could not be downloaded. Try again.",
+		"es": "No se pudo descargar el archivo. Inténtalo de nuevo.",
+		"fr": "Le fichier n'a pas pu être téléchargé. Réessayez.",
+		"de": "Die Datei konnte nicht heruntergeladen werden. Versuche es erneut.",
+		"pt": "Não foi possível baixar o arquivo. Tente novamente.",
+	},
+	"file_too_large": {
+		"en": "The file is too large.",
+		"es": "El archivo es demasiado grande.",
+		"fr": "Le fichier est trop volumineux.",
+		"de": "Die Datei ist zu groß.",
+		"pt": "O arquivo é grande demais.",
+	},
+	"forbidden": {
+		"en": "You do not have access to this resource.",
+		"es": "No tienes acceso a este recurso.",
+		"fr": "Vous n'avez pas accès à cette ressource.",
+		"de": "Du hast keinen Zugriff auf diese Ressource.",
+		"pt": "Você não tem acesso a este recurso.",
+	},
+	"impersonation_inactive": {
+		"en": "The support session has ended.",
+		"es": "La sesión de soporte ha terminado.",
+		"fr": "La session d'assistance est terminée.",
+		"de": "Die Support-Sitzung ist beendet.",
+		"pt": "A sessão de suporte foi encerrada.",
+	},
+	"impersonation_read_only": {
+		"en": "Support sessions are read-only.",
+		"es": "Las sesiones de soporte son de solo lectura.",
+		"fr": "Les sessions d'assistance sont en lecture seule.",
+		"de": "Support-Sitzungen sind schreibgeschützt.",
+		"pt": "As sessões de suporte são somente leitura.",
+	},
+	"integrations_unavailable": {
+		"en": "Integrations are unavailable. Try again later.",
+		"es": "Las integraciones no están disponibles. Inténtalo más tarde.",
+		"fr": "Les intégrations sont indisponibles. Réessayez plus tard.",
+		"de": "Integrationen sind nicht verfügbar. Versuche es später erneut.",
+		"pt": "As integrações estão indisponíveis. Tente novamente mais tarde.",
+	},
+	"internal": {
+		"en": "Something went wrong. Try again.",
+		"es": "Algo salió mal. Inténtalo de nuevo.",
+		"fr": "Une erreur s'est produite. Réessayez.",
+		"de": "Etwas ist schiefgelaufen. Versuche es erneut.",
+		"pt": "Algo deu errado. Tente novamente.",
+	},
+	"internal_error": {
+		"en": "Something went wrong. Try again.",
+		"es": "Algo salió mal. Inténtalo de nuevo.",
+		"fr": "Une erreur s'est produite. Réessayez.",
+		"de": "Etwas ist schiefgelaufen. Versuche es erneut.",
+		"pt": "Algo deu errado. Tente novamente.",
+	},
+	"invalid_analysis": {
+		"en": "The analysis cannot be used for this request.",
+		"es": "El análisis no se puede usar para esta solicitud.",
+		"fr": "L'analyse ne peut pas être utilisée pour cette requête.",
+		"de": "Die Analyse kann für diese Anfrage nicht verwendet werden.",
+		"pt": "A análise não pode ser usada nesta solicitação.",
+	},
+	"invalid_llm_output": {
+		"en": "The analysis produced an unusable result. Try again.",
+		"es": "El análisis produjo un resultado inutilizable. Inténtalo de nuevo.",
+		"fr": "L'analyse a produit un résultat inutilisable. Réessayez.",
+		"de": "Die Analyse hat ein unbrauchbares Ergebnis geliefert. Versuche es erneut.",
+		"pt": "A análise gerou um resultado inutilizável. Tente novamente.",
+	},
+	"invalid_request": {
+		"en": "The request is invalid.",
+		"es": "La solicitud no es válida.",
+		"fr": "La requête n'est pas valide.",
+		"de": "Die Anfrage ist ungültig.",
+		"pt": "A solicitação é inválida.",
+	},
+	"invalid_resume_model": {
+		"en": "The resume could not be rebuilt. Try again.",
+		"es": "No se pudo reconstruir el currículum. Inténtalo de nuevo.",
+		"fr": "Le CV n'a pas pu être reconstruit. Réessayez.",
+		"de": "Der Lebenslauf konnte nicht neu erstellt werden. Versuche es erneut.",
+		"pt": "Não foi possível reconstruir o currículo. Tente novamente.",
+	},
+	"invalid_transition": {
+		"en": "This change is not allowed in the current state.",
+		"es": "Este cambio no está permitido en el estado actual.",
+		"fr": "Ce changement n'est pas autorisé dans l'état actuel.",
+		"de": "Diese Änderung ist im aktuellen Zustand nicht erlaubt.",
+		"pt": "Esta alteração não é permitida no estado atual.",
+	},
+	"job_description_needs_confirmation": {
+		"en": "Confirm the job description before analyzing.",
+		"es": "Confirma la descripción del puesto antes de analizar.",
+		"fr": "Confirmez l'offre d'emploi avant l'analyse.",
+		"de": "Bestätige die Stellenbeschreibung vor der Analyse.",
+		"pt": "Confirme a descrição da vaga antes de analisar.",
+	},
+	"limit_reached": {
+		"en": "You have reached your plan's limit.",
+		"es": "Has alcanzado el límite de tu plan.",
+		"fr": "Vous avez atteint la limite de votre offre.",
+		"de": "Du hast das Limit deines Tarifs erreicht.",
+		"pt": "Você atingiu o limite do seu plano.",
+	},
+	"llm_budget_exceeded": {
+		"en": "Analysis capacity is used up for today. Try again later.",
+		"es": "La capacidad de análisis de hoy está agotada. Inténtalo más tarde.",
+		"fr": "La capacité d'analyse du jour est épuisée. Réessayez plus tard.",
+		"de": "Die Analysekapazität für heute ist aufgebraucht. Versuche es später erneut.",
+		"pt": "A capacidade de análise de hoje se esgotou. Tente novamente mais tarde.",
+	},
+	"llm_probe_unavailable": {
+		"en": "The analysis provider check is unavailable.",
+		"es": "La comprobación del proveedor de análisis no está disponible.",
+		"fr": "La vérification du fournisseur d'analyse est indisponible.",
+		"de": "Die Prüfung des Analyseanbieters ist nicht verfügbar.",
+		"pt": "A verificação do provedor de análise está indisponível.",
+	},
+	"login_required": {
+		"en": "Sign in to continue.",
+		"es": "Inicia sesión para continuar.",
+		"fr": "Connectez-vous pour continuer.",
+		"de": "Melde dich an, um fortzufahren.",
+		"pt": "Entre na sua conta para continuar.",
+	},
+	"missing_required_fields": {
+		"en": "Some required fields are missing.",
+		"es": "Faltan algunos campos obligatorios.",
+		"fr": "Certains champs obligatoires sont manquants.",
+		"de": "Einige Pflichtfelder fehlen.",
+		"pt": "Alguns campos obrigatórios estão faltando.",
+	},
+	"needs_input": {
+		"en": "More information is needed to continue.",
+		"es": "Se necesita más información para continuar.",
+		"fr": "Des informations supplémentaires sont nécessaires pour continuer.",
+		"de": "Zum Fortfahren werden weitere Angaben benötigt.",
+		"pt": "São necessárias mais informações para continuar.",
+	},
+	"not_found": {
+		"en": "Not found.",
+		"es": "No encontrado.",
+		"fr": "Introuvable.",
+		"de": "Nicht gefunden.",
+		"pt": "Não encontrado.",
+	},
+	"not_supported": {
+		"en": "This operation is not supported here.",
+		"es": "Esta operación no es compatible aquí.",
+		"fr": "Cette opération n'est pas prise en charge ici.",
+		"de": "Dieser Vorgang wird hier nicht unterstützt.",
+		"pt": "Esta operação não é suportada aqui.",
+	},
+	"overloaded": {
+		"en": "The service is busy. Try again shortly.",
+		"es": "El servicio está ocupado. Inténtalo de nuevo en breve.",
+		"fr": "Le service est surchargé. Réessayez dans un instant.",
+		"de": "Der Dienst ist ausgelastet. Versuche es gleich noch einmal.",
+		"pt": "O serviço está ocupado. Tente novamente em instantes.",
+	},
+	"plan_required": {
+		"en": "Your plan does not include this feature.",
+		"es": "Tu plan no incluye esta función.",
+		"fr": "Votre offre n'inclut pas cette fonctionnalité.",
+		"de": "Dein Tarif enthält diese Funktion nicht.",
+		"pt": "O seu plano não inclui este recurso.",
+	},
+	"rate_limited": {
+		"en": "Too many requests. Slow down and try again.",
+		"es": "Demasiadas solicitudes. Espera un poco e inténtalo de nuevo.",
+		"fr": "Trop de requêtes. Patientez puis réessayez.",
+		"de": "Zu viele Anfragen. Warte kurz und versuche es erneut.",
+		"pt": "Muitas solicitações. Aguarde um pouco e tente novamente.",
+	},
+	"read_only": {
+		"en": "This item is read-only.",
+		"es": "Este elemento es de solo lectura.",
+		"fr": "Cet élément est en lecture seule.",
+		"de": "Dieses Element ist schreibgeschützt.",
+		"pt": "Este item é somente leitura.",
+	},
+	"replay_unavailable": {
+		"en": "This analysis cannot be replayed.",
+		"es": "Este análisis no se puede repetir.",
+		"fr": "Cette analyse ne peut pas être rejouée.",
+		"de": "Diese Analyse kann nicht wiederholt werden.",
+		"pt": "Esta análise não pode ser repetida.",
+	},
+	"residency_conflict": {
+		"en": "The data residency region cannot be changed now.",
+		"es": "La región de residencia de datos no se puede cambiar ahora.",
+		"fr": "La région de résidence des données ne peut pas être modifiée pour le moment.",
+		"de": "Die Region für die Datenspeicherung kann jetzt nicht geändert werden.",
+		"pt": "A região de residência dos dados não pode ser alterada agora.",
+	},
+	"resume_incomplete": {
+		"en": "The resume is missing information needed for this step.",
+		"es": "Al currículum le falta información necesaria para este paso.",
+		"fr": "Il manque au CV des informations nécessaires à cette étape.",
+		"de": "Dem Lebenslauf fehlen Angaben, die für diesen Schritt nötig sind.",
+		"pt": "Faltam ao currículo informações necessárias para esta etapa.",
+	},
+	"retry_required": {
+		"en": "Start a new attempt to continue.",
+		"es": "Inicia un nuevo intento para continuar.",
+		"fr": "Lancez une nouvelle tentative pour continuer.",
+		"de": "Starte einen neuen Versuch, um fortzufahren.",
+		"pt": "Inicie uma nova tentativa para continuar.",
+	},
+	"rollout_active": {
+		"en": "A rollout is already in progress.",
+		"es": "Ya hay un despliegue en curso.",
+		"fr": "Un déploiement est déjà en cours.",
+		"de": "Es läuft bereits ein Rollout.",
+		"pt": "Já existe uma implantação em andamento.",
+	},
+	"share_link_exhausted": {
+		"en": "This share link has reached its view limit.",
+		"es": "Este enlace compartido alcanzó su límite de vistas.",
+		"fr": "Ce lien de partage a atteint sa limite de consultations.",
+		"de": "Dieser Freigabelink hat sein Aufruflimit erreicht.",
+		"pt": "Este link de compartilhamento atingiu o limite de visualizações.",
+	},
+	"share_link_expired": {
+		"en": "This share link has expired.",
+		"es": "Este enlace compartido ha caducado.",
+		"fr": "Ce lien de partage a expiré.",
+		"de": "Dieser Freigabelink ist abgelaufen.",
+		"pt": "Este link de compartilhamento expirou.",
+	},
+	"share_link_revoked": {
+		"en": "This share link has been revoked.",
+		"es": "Este enlace compartido fue revocado.",
+		"fr": "Ce lien de partage a été révoqué.",
+		"de": "Dieser Freigabelink wurde widerrufen.",
+		"pt": "Este link de compartilhamento foi revogado.",
+	},
+	"stuck_scan_failed": {
+		"en": "The stuck-state scan failed. Try again.",
+		"es": "La revisión de estados bloqueados falló. Inténtalo de nuevo.",
+		"fr": "L'analyse des états bloqués a échoué. Réessayez.",
+		"de": "Die Prüfung auf hängende Zustände ist fehlgeschlagen. Versuche es erneut.",
+		"pt": "A verificação de estados travados falhou. Tente novamente.",
+	},
+	"template_invalid": {
+		"en": "The template is not valid.",
+		"es": "La plantilla no es válida.",
+		"fr": "Le modèle n'est pas valide.",
+		"de": "Die Vorlage ist ungültig.",
+		"pt": "O modelo não é válido.",
+	},
+	"timeout": {
+		"en": "The request took too long. Try again.",
+		"es": "La solicitud tardó demasiado. Inténtalo de nuevo.",
+		"fr": "La requête a pris trop de temps. Réessayez.",
+		"de": "Die Anfrage hat zu lange gedauert. Versuche es erneut.",
+		"pt": "A solicitação demorou demais. Tente novamente.",
+	},
+	"unauthorized": {
+		"en": "Sign in to continue.",
+		"es": "Inicia sesión para continuar.",
+		"fr": "Connectez-vous pour continuer.",
+		"de": "Melde dich an, um fortzufahren.",
+		"pt": "Entre na sua conta para continuar.",
+	},
+	"unreadable_document": {
+		"en": "The document's text could not be read.",
+		"es": "No se pudo leer el texto del documento.",
+		"fr": "Le texte du document n'a pas pu être lu.",
+		"de": "Der Text des Dokuments konnte nicht gelesen werden.",
+		"pt": "Não foi possível ler o texto do documento.",
+	},
+	"unsupported_pipeline": {
+		"en": "This analysis type is not supported.",
+		"es": "Este tipo de análisis no es compatible.",
+		"fr": "Ce type d'analyse n'est pas pris en charge.",
+		"de": "Diese Analyseart wird nicht unterstützt.",
+		"pt": "Este tipo de análise não é suportado.",
+	},
+	"validation_error": {
+		"en": "Some of the request's values are invalid.",
+		"es": "Algunos valores de la solicitud no son válidos.",
+		"fr": "Certaines valeurs de la requête ne sont pas valides.",
+		"de": "Einige Werte der Anfrage sind ungültig.",
+		"pt": "Alguns valores da solicitação são inválidos.",
+	},
+}
+
+// SupportedLanguages returns the languages error messages are available in.
+func SupportedLanguages() []string {
+	return []string{"de", "en", "es", "fr", "pt"}
+}
+
+// Message returns the message for code in language, falling back to English.
+// ok is false when the code has no catalog message at all.
+func Message(code, language string) (string, bool) {
+	byLanguage, ok := messages[code]
+	if !ok {
+		return "", false
+	}
+	if msg, ok := byLanguage[language]; ok {
+		return msg, true
+	}
+	msg, ok := byLanguage[DefaultLanguage]
+	return msg, ok
+}
+
+// NegotiateLanguage picks the supported language the Accept-Language header
+// ranks highest, matching on the primary subtag so "pt-BR" selects "pt". Ties
+// keep header order; an empty or unmatched header selects DefaultLanguage.
+func NegotiateLanguage(header string) string {
+	type candidate struct {
+		language string
+		q        float64
+		pos      int
+	}
+	supported := map[string]bool{}
+	for _, lang := range SupportedLanguages() {
+		supported[lang] = true
+	}
+	var candidates []candidate
+	for i, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !supported[primary] {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{language: primary, q: q, pos: i})
+	}
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].language
+}
+
+// requestLanguage negotiates the language of c's Accept-Language header.
+func requestLanguage(c *gin.Context) string {
+	return NegotiateLanguage(c.GetHeader("Accept-Language"))
+}
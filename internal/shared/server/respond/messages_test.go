@@ -0,0 +1,101 @@
+package respond
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMessagesCoverCatalog(t *testing.T) {
+	for _, entry := range catalog {
+		for _, lang := range SupportedLanguages() {
+			if messages[entry.Code][lang] == "" {
+				t.Errorf("code %q has no %s message", entry.Code, lang)
+			}
+		}
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":                        "en",
+		"es":                      "es",
+		"pt-BR,pt;q=0.9,en;q=0.8": "pt",
+		"en-US,en;q=0.9,fr;q=0.8": "en",
+		"ja,de;q=0.5":             "de",
+		"fr;q=0.4, es;q=0.7":      "es",
+		"de;q=0, ja":              "en",
+		"*":                       "en",
+		"es;q=bogus, fr;q=0.1":    "fr",
+		"FR-ca":                   "fr",
+	}
+	for header, want := range cases {
+		if got := NegotiateLanguage(header); got != want {
+			t.Errorf("NegotiateLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestErrorLocalizesMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/x", func(c *gin.Context) {
+		Error(c, http.StatusBadRequest, "validation_error", "jobDescription too short", nil)
+	})
+
+	serve := func(acceptLanguage string) (*httptest.ResponseRecorder, ErrorBody) {
+		req := httptest.NewRequest(http.MethodGet, "/x", nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return rec, body.Error
+	}
+
+	rec, body := serve("")
+	if body.Code != "validation_error" || body.Message != "jobDescription too short" || rec.Header().Get("Content-Language") != "" {
+		t.Fatalf("expected the handler's English message, got %+v", body)
+	}
+
+	rec, body = serve("es-MX,es;q=0.9")
+	if body.Code != "validation_error" || body.Message != messages["validation_error"]["es"] {
+		t.Fatalf("expected the Spanish message, got %+v", body)
+	}
+	if rec.Header().Get("Content-Language") != "es" || rec.Header().Get("Vary") != "Accept-Language" {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+}
+
+func TestErrorCatalogEndpointLocalizes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/meta/error-codes", ErrorCatalog)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/meta/error-codes", nil)
+	req.Header.Set("Accept-Language", "de-DE")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var body struct {
+		Language string         `json:"language"`
+		Items    []CatalogEntry `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Language != "de" || rec.Header().Get("Content-Language") != "de" {
+		t.Fatalf("expected German, got %q", body.Language)
+	}
+	for _, entry := range body.Items {
+		if entry.Message != messages[entry.Code]["de"] {
+			t.Fatalf("unexpected message for %s: %q", entry.Code, entry.Message)
+		}
+	}
+}
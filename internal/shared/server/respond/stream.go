@@ -0,0 +1,60 @@
+package respond
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// StreamThreshold is the list length from which ListJSON streams items
+	// instead of encoding the whole body first.
+	StreamThreshold = 100
+	// streamFlushEvery is how many items are written between flushes.
+	streamFlushEvery = 50
+)
+
+// ListJSON writes a 200 JSON array. Short lists go through CachedJSON and get
+// an ETag; from StreamThreshold items on, the array is encoded item by item
+// and flushed as it goes, so the whole body is never held in memory. Streamed
+// lists carry no ETag because the body is not known when headers are sent.
+func ListJSON[T any](c *gin.Context, cacheControl string, items []T) {
+	if len(items) < StreamThreshold {
+		CachedJSON(c, cacheControl, items)
+		return
+	}
+	StreamJSONArray(c, cacheControl, items)
+}
+
+// StreamJSONArray writes items as a 200 JSON array, one element at a time. An
+// encoding error after the first byte cannot be reported to the client, so it
+// aborts the response and is left to the client's JSON parser to detect.
+func StreamJSONArray[T any](c *gin.Context, cacheControl string, items []T) {
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := c.Writer
+	if _, err := w.WriteString("["); err != nil {
+		c.Abort()
+		return
+	}
+	enc := json.NewEncoder(w)
+	for i, item := range items {
+		if i > 0 {
+			if _, err := w.WriteString(","); err != nil {
+				c.Abort()
+				return
+			}
+		}
+		if err := enc.Encode(item); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if (i+1)%streamFlushEvery == 0 {
+			w.Flush()
+		}
+	}
+	_, _ = w.WriteString("]")
+}
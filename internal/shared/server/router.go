@@ -1,16 +1,31 @@
 package server
 
 import (
+	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"resume-backend/internal/abuse"
 	"resume-backend/internal/account"
+	"resume-backend/internal/admin"
 	"resume-backend/internal/analyses"
 	"resume-backend/internal/applies"
+	"resume-backend/internal/artifacts"
+	"resume-backend/internal/assist"
 	googleauth "resume-backend/internal/auth"
+	"resume-backend/internal/badges"
 	"resume-backend/internal/documents"
+	"resume-backend/internal/events"
+	"resume-backend/internal/impersonation"
+	"resume-backend/internal/insights"
+	"resume-backend/internal/integrations"
+	"resume-backend/internal/jobdescriptions"
+	"resume-backend/internal/onboarding"
+	"resume-backend/internal/pools"
+	"resume-backend/internal/profilestrength"
+	"resume-backend/internal/resumes"
 	"resume-backend/internal/shared/config"
 	"resume-backend/internal/shared/metrics"
 	"resume-backend/internal/shared/server/middleware"
@@ -27,39 +42,94 @@ type RouterDeps struct {
 	AnalysisHandler *analyses.Handler
 	ApplyHandler    *applies.Handler
 	DocumentHandler *documents.Handler
+	JobDescHandler  *jobdescriptions.Handler
+	// AssistHandler serves the interactive editing helpers.
+	AssistHandler   *assist.Handler
+	ArtifactHandler *artifacts.Handler
+	BadgeHandler    *badges.Handler
+	AdminHandler    *admin.Handler
 	UsageHandler    *usage.Handler
 	UserHandler     *users.Handler
+	PoolsHandler    *pools.Handler
+	// ResumesHandler serves the in-product resume builder.
+	ResumesHandler *resumes.Handler
+	// IntegrationsHandler manages per-organization ATS integrations.
+	IntegrationsHandler *integrations.Handler
+	// ProfileHandler serves the dashboard profile strength summary.
+	ProfileHandler *profilestrength.Handler
+	// OnboardingHandler copies the sample analysis into new accounts.
+	OnboardingHandler *onboarding.Handler
+	// Abuse checks guests on LLM-spending routes; nil disables the check.
+	Abuse *abuse.Detector
+	// InsightsHandler serves aggregate keyword insights; nil disables them.
+	InsightsHandler *insights.Handler
 	GoogleAuth      *googleauth.GoogleService
+	// Events, when set, records first-seen guests.
+	Events *events.Emitter
+	// Impersonation validates and audits impersonation tokens; nil rejects them.
+	Impersonation *impersonation.Service
+	// Readiness backs the readiness probe; nil always reports ready.
+	Readiness *Readiness
+	// RateLimits holds the rate limit rules; nil uses DefaultRateLimits.
+	RateLimits *middleware.RateLimitRules
+}
+
+// DefaultRateLimits returns the per-principal rate limit rules by group.
+func DefaultRateLimits() map[string]middleware.RateLimitRule {
+	return map[string]middleware.RateLimitRule{
+		"DEFAULT": {Rate: 4, Burst: 8},
+		"POLLING": {Rate: 12, Burst: 24},
+	}
 }
 
 // NewRouter constructs the Gin engine with middleware and routes registered.
 func NewRouter(deps RouterDeps) *gin.Engine {
 	cfg := deps.Config
+	rateLimits := deps.RateLimits
+	if rateLimits == nil {
+		rateLimits = middleware.NewRateLimitRules(DefaultRateLimits())
+	}
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Printf("server: TRUSTED_PROXIES: %v; trusting no proxies", err)
+		_ = r.SetTrustedProxies(nil)
+	}
 
 	r.Use(
 		middleware.RequestID(),
 		middleware.Logging(),
+	)
+	if cfg.CompressResponses {
+		r.Use(middleware.Compress(middleware.CompressConfig{MinBytes: cfg.CompressMinBytes, Level: cfg.CompressLevel}))
+	}
+	r.Use(
 		middleware.Recovery(),
 		middleware.CORS(cfg.CORSAllowOrigin),
 		middleware.Auth(cfg.Env),
+		impersonation.Guard(deps.Impersonation),
+		middleware.GuestRetention(cfg.GuestRetention),
 		middleware.RateLimit(middleware.RateLimitConfig{
 			DefaultGroup: "DEFAULT",
 			GroupFor:     rateLimitGroupFor,
-			Rules: map[string]middleware.RateLimitRule{
-				"DEFAULT": {Rate: 4, Burst: 8},
-				"POLLING": {Rate: 12, Burst: 24},
-			},
+			Dynamic:      rateLimits,
 		}),
+		deps.Abuse.Guard(spendsLLM),
 	)
 
+	if deps.Events != nil {
+		r.Use(events.TrackGuests(deps.Events))
+	}
+
 	r.GET("/metrics", metrics.Handler())
 
 	api := r.Group("/api/v1")
 	api.GET("/health", func(c *gin.Context) {
 		respond.JSON(c, http.StatusOK, gin.H{"ok": true})
 	})
+	api.GET("/meta/error-codes", respond.ErrorCatalog)
+	api.GET("/meta/scoring", analyses.ScoringConfig)
+	r.GET(ReadyPath, gin.WrapH(deps.Readiness))
 	deps.GoogleAuth.RegisterRoutes(api)
 	uploads.RegisterRoutes(api)
 	deps.DocumentHandler.RegisterRoutes(api)
@@ -68,6 +138,39 @@ func NewRouter(deps RouterDeps) *gin.Engine {
 	deps.UserHandler.RegisterRoutes(api)
 	deps.UsageHandler.RegisterRoutes(api)
 	deps.ApplyHandler.RegisterRoutes(api)
+	if deps.JobDescHandler != nil {
+		deps.JobDescHandler.RegisterRoutes(api)
+	}
+	if deps.AssistHandler != nil {
+		deps.AssistHandler.RegisterRoutes(api)
+	}
+	if deps.ArtifactHandler != nil {
+		deps.ArtifactHandler.RegisterRoutes(api)
+	}
+	if deps.BadgeHandler != nil {
+		deps.BadgeHandler.RegisterRoutes(api)
+	}
+	if deps.PoolsHandler != nil {
+		deps.PoolsHandler.RegisterRoutes(api)
+	}
+	if deps.ResumesHandler != nil {
+		deps.ResumesHandler.RegisterRoutes(api)
+	}
+	if deps.IntegrationsHandler != nil {
+		deps.IntegrationsHandler.RegisterRoutes(api)
+	}
+	if deps.ProfileHandler != nil {
+		deps.ProfileHandler.RegisterRoutes(api)
+	}
+	if deps.OnboardingHandler != nil {
+		deps.OnboardingHandler.RegisterRoutes(api)
+	}
+	if deps.InsightsHandler != nil {
+		deps.InsightsHandler.RegisterRoutes(api)
+	}
+	if deps.AdminHandler != nil {
+		deps.AdminHandler.RegisterRoutes(api)
+	}
 	if cfg.Env == "dev" {
 		dev := api.Group("/dev")
 		deps.UsageHandler.RegisterDevRoutes(dev)
@@ -86,13 +189,37 @@ func rateLimitGroupFor(c *gin.Context) string {
 	switch c.FullPath() {
 	case "/api/v1/analyses/:id",
 		"/api/v1/documents/:id",
-		"/api/v1/documents/:id/status":
+		"/api/v1/documents/:id/status",
+		// Embedded badges are fetched by shared image proxies.
+		"/api/v1/badge/:file":
 		return "POLLING"
 	default:
 		return "DEFAULT"
 	}
 }
 
+// spendsLLM reports whether the request starts LLM work, the routes guests
+// farm the free tier for.
+func spendsLLM(c *gin.Context) bool {
+	if c.Request.Method != http.MethodPost {
+		return false
+	}
+	switch c.FullPath() {
+	case "/api/v1/documents/:id/analyze",
+		"/api/v1/analyses/inline",
+		"/api/v1/analyses/:id/replay",
+		"/api/v1/resumes/:id/analyze",
+		"/api/v1/analyses/:id/apply",
+		"/api/v1/analyses/:id/apply/plan",
+		"/api/v1/apply-runs/:id/execute",
+		"/api/v1/assist/quantify-bullet",
+		"/api/v1/job-descriptions/extract-keywords":
+		return true
+	default:
+		return false
+	}
+}
+
 // Addr normalizes the listen address.
 func Addr(port string) string {
 	if port == "" {
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReadyPath is the readiness probe polled by load balancers and ECS health checks.
+const ReadyPath = "/api/v1/ready"
+
+// Readiness reports whether a process should receive new traffic. It starts
+// ready and flips to not-ready once draining begins; it never flips back.
+// Checks added with AddCheck are reported alongside, and required ones must
+// pass too.
+type Readiness struct {
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	checks []readinessCheck
+}
+
+type readinessCheck struct {
+	name     string
+	required bool
+	ready    func() bool
+}
+
+// AddCheck reports ready under name in the probe response. A required check
+// that fails also makes the process not ready.
+func (r *Readiness) AddCheck(name string, required bool, ready func() bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, readinessCheck{name: name, required: required, ready: ready})
+}
+
+// Drain marks the process as not ready.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+// Ready reports whether draining has not started yet and every required check
+// passes.
+func (r *Readiness) Ready() bool {
+	if r == nil || r.draining.Load() {
+		return false
+	}
+	ready, _ := r.runChecks()
+	return ready
+}
+
+func (r *Readiness) runChecks() (bool, map[string]bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.checks) == 0 {
+		return true, nil
+	}
+	ready := true
+	results := make(map[string]bool, len(r.checks))
+	for _, check := range r.checks {
+		ok := check.ready()
+		results[check.name] = ok
+		if check.required && !ok {
+			ready = false
+		}
+	}
+	return ready, results
+}
+
+// ServeHTTP answers 200 while ready and 503 while draining or while a required
+// check fails.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	status := http.StatusOK
+	if !r.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	body := map[string]any{"ready": status == http.StatusOK}
+	if r != nil {
+		if _, checks := r.runChecks(); checks != nil {
+			body["checks"] = checks
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// ShutdownOptions control how Serve drains a server once its context ends.
+type ShutdownOptions struct {
+	// Readiness is flipped to not-ready as soon as shutdown starts.
+	Readiness *Readiness
+	// DrainDelay keeps accepting requests after readiness flips so load
+	// balancers can stop routing here before the listener closes.
+	DrainDelay time.Duration
+	// GracePeriod is how long in-flight requests, such as streaming downloads,
+	// may run after the listener closes before their connections are dropped.
+	GracePeriod time.Duration
+}
+
+// Serve runs srv until ctx is canceled, then drains it: readiness flips, new
+// connections are accepted for DrainDelay, and in-flight requests get up to
+// GracePeriod to finish. It returns nil after a clean drain and
+// context.DeadlineExceeded when connections had to be closed forcibly.
+func Serve(ctx context.Context, srv *http.Server, opts ShutdownOptions) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return serveListener(ctx, srv, ln, opts)
+}
+
+func serveListener(ctx context.Context, srv *http.Server, ln net.Listener, opts ShutdownOptions) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	if opts.Readiness != nil {
+		opts.Readiness.Drain()
+	}
+	if opts.DrainDelay > 0 {
+		log.Printf("shutdown requested, not ready; closing listener in %s", opts.DrainDelay)
+		select {
+		case err := <-errCh:
+			return err
+		case <-time.After(opts.DrainDelay):
+		}
+	}
+
+	log.Printf("draining connections for up to %s", opts.GracePeriod)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.GracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Streams still open after the grace period are cut off.
+		_ = srv.Close()
+		return err
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadinessFlipsWhenDraining(t *testing.T) {
+	ready := &Readiness{}
+	resp := httptest.NewRecorder()
+	ready.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 before drain, got %d", resp.Code)
+	}
+
+	ready.Drain()
+	resp = httptest.NewRecorder()
+	ready.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while draining, got %d", resp.Code)
+	}
+}
+
+func TestReadinessRequiredChecks(t *testing.T) {
+	ready := &Readiness{}
+	llmUp, cacheUp := false, false
+	ready.AddCheck("cache", false, func() bool { return cacheUp })
+	resp := httptest.NewRecorder()
+	ready.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), `"cache":false`) {
+		t.Fatalf("an optional check should be reported but not fail readiness, got %d %s", resp.Code, resp.Body.String())
+	}
+
+	ready.AddCheck("llm", true, func() bool { return llmUp })
+	if ready.Ready() {
+		t.Fatal("a failing required check should make the process not ready")
+	}
+	llmUp = true
+	if !ready.Ready() {
+		t.Fatal("expected ready once the required check passes")
+	}
+}
+
+// streamServer starts a server whose /stream handler writes one chunk, waits
+// for release and then writes the rest.
+func streamServer(t *testing.T, ctx context.Context, release <-chan struct{}, opts ShutdownOptions) (string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(ReadyPath, opts.Readiness)
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "first,")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = io.WriteString(w, "second")
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- serveListener(ctx, &http.Server{Handler: mux}, ln, opts)
+	}()
+	return "http://" + ln.Addr().String(), done
+}
+
+func startStream(t *testing.T, base string) *http.Response {
+	t.Helper()
+	resp, err := http.Get(base + "/stream")
+	if err != nil {
+		t.Fatalf("start stream: %v", err)
+	}
+	first := make([]byte, len("first,"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		t.Fatalf("read first chunk: %v", err)
+	}
+	return resp
+}
+
+func TestServeLetsInFlightStreamsFinish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	release := make(chan struct{})
+	ready := &Readiness{}
+	base, done := streamServer(t, ctx, release, ShutdownOptions{
+		Readiness:   ready,
+		DrainDelay:  200 * time.Millisecond,
+		GracePeriod: 5 * time.Second,
+	})
+
+	stream := startStream(t, base)
+	defer stream.Body.Close()
+	cancel()
+
+	// During the drain delay the listener is still open but the probe fails.
+	deadline := time.Now().Add(time.Second)
+	for ready.Ready() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	probe, err := http.Get(base + ReadyPath)
+	if err != nil {
+		t.Fatalf("probe during drain delay: %v", err)
+	}
+	probe.Body.Close()
+	if probe.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 from readiness probe, got %d", probe.StatusCode)
+	}
+
+	close(release)
+	rest, err := io.ReadAll(stream.Body)
+	if err != nil || string(rest) != "second" {
+		t.Fatalf("expected stream to complete, got %q err=%v", rest, err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server did not stop")
+	}
+}
+
+func TestServeClosesStreamsAfterGracePeriod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+	base, done := streamServer(t, ctx, release, ShutdownOptions{
+		Readiness:   &Readiness{},
+		GracePeriod: 100 * time.Millisecond,
+	})
+
+	stream := startStream(t, base)
+	defer stream.Body.Close()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server did not stop after grace period")
+	}
+	if rest, _ := io.ReadAll(stream.Body); string(rest) == "second" {
+		t.Fatalf("expected stream to be cut off")
+	}
+}
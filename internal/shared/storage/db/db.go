@@ -213,3 +213,29 @@ func readEnvDuration(key string) (time.Duration, bool) {
 	}
 	return val, true
 }
+
+// Prewarm opens up to conns pooled connections and returns them to the idle pool,
+// so the first requests after a cold start do not pay for TCP and TLS handshakes.
+func Prewarm(ctx context.Context, db *sql.DB, conns int) error {
+	if db == nil || conns <= 0 {
+		return nil
+	}
+	held := make([]*sql.Conn, 0, conns)
+	defer func() {
+		for _, conn := range held {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < conns; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("prewarm conn %d: %w", i, err)
+		}
+		held = append(held, conn)
+		if err := conn.PingContext(ctx); err != nil {
+			return fmt.Errorf("prewarm ping %d: %w", i, err)
+		}
+	}
+	logPoolStats(db, "db prewarm")
+	return nil
+}
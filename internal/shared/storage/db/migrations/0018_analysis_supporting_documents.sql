@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE analyses
+    ADD COLUMN IF NOT EXISTS supporting_documents JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+-- +goose Down
+ALTER TABLE analyses
+    DROP COLUMN IF EXISTS supporting_documents;
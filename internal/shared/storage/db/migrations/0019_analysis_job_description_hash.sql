@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE analyses
+    ADD COLUMN IF NOT EXISTS job_description_hash TEXT NOT NULL DEFAULT '';
+
+-- Approximates HashJobDescription, which splits on all Unicode whitespace.
+-- Run `go run ./cmd/admin rehash-job-descriptions -apply` to fix rows where they differ.
+UPDATE analyses
+SET job_description_hash = encode(
+    sha256(convert_to(regexp_replace(btrim(COALESCE(job_description, '')), '\s+', ' ', 'g'), 'UTF8')),
+    'hex'
+)
+WHERE job_description_hash = '';
+
+CREATE INDEX IF NOT EXISTS idx_analyses_document_jd_mode
+    ON analyses (user_id, document_id, job_description_hash, mode, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_analyses_document_jd_mode;
+
+ALTER TABLE analyses
+    DROP COLUMN IF EXISTS job_description_hash;
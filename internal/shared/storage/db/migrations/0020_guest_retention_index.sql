@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_documents_guest_created_at
+    ON documents (created_at)
+    WHERE user_id LIKE 'guest:%' AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_guest_created_at;
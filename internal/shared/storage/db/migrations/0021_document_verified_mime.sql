@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS verified_mime TEXT;
+
+-- +goose Down
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS verified_mime;
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS artifact_share_links (
+    id UUID PRIMARY KEY,
+    token_hash TEXT NOT NULL UNIQUE,
+    artifact_type TEXT NOT NULL,
+    artifact_id UUID NOT NULL,
+    owner_user_id TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    max_downloads INTEGER NOT NULL DEFAULT 0,
+    download_count INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT artifact_share_links_type_check CHECK (artifact_type IN ('generated_resume','document')),
+    CONSTRAINT artifact_share_links_max_check CHECK (max_downloads >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS artifact_downloads (
+    id UUID PRIMARY KEY,
+    artifact_type TEXT NOT NULL,
+    artifact_id UUID NOT NULL,
+    owner_user_id TEXT NOT NULL,
+    downloader_user_id TEXT,
+    client_ip TEXT,
+    via TEXT NOT NULL,
+    share_link_id UUID REFERENCES artifact_share_links(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT artifact_downloads_type_check CHECK (artifact_type IN ('generated_resume','document')),
+    CONSTRAINT artifact_downloads_via_check CHECK (via IN ('session','share_link'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_artifact_downloads_owner_artifact
+    ON artifact_downloads (owner_user_id, artifact_type, artifact_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_artifact_downloads_owner_artifact;
+DROP TABLE IF EXISTS artifact_downloads;
+DROP TABLE IF EXISTS artifact_share_links;
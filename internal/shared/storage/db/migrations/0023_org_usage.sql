@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS org_usage (
+    org_id TEXT PRIMARY KEY,
+    plan TEXT NOT NULL,
+    limit_amount INT NOT NULL,
+    seat_limit INT NOT NULL DEFAULT 0,
+    used INT NOT NULL DEFAULT 0,
+    resets_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS org_usage_members (
+    org_id TEXT NOT NULL REFERENCES org_usage(org_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL,
+    seat_limit INT NOT NULL DEFAULT 0,
+    used INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_usage_members_user ON org_usage_members (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS org_usage_members;
+DROP TABLE IF EXISTS org_usage;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS prompt_rollouts (
+    id TEXT PRIMARY KEY,
+    baseline_version TEXT NOT NULL,
+    candidate_version TEXT NOT NULL,
+    status TEXT NOT NULL,
+    stage_index INT NOT NULL DEFAULT 0,
+    stage_started_at TIMESTAMPTZ NOT NULL,
+    candidate_finished INT NOT NULL DEFAULT 0,
+    candidate_failed INT NOT NULL DEFAULT 0,
+    candidate_schema_failures INT NOT NULL DEFAULT 0,
+    candidate_sanitized INT NOT NULL DEFAULT 0,
+    candidate_duration_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    baseline_finished INT NOT NULL DEFAULT 0,
+    baseline_failed INT NOT NULL DEFAULT 0,
+    baseline_schema_failures INT NOT NULL DEFAULT 0,
+    baseline_sanitized INT NOT NULL DEFAULT 0,
+    baseline_duration_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ
+);
+
+-- At most one rollout may split traffic at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_rollouts_active
+    ON prompt_rollouts ((true))
+    WHERE status IN ('running', 'paused');
+
+CREATE INDEX IF NOT EXISTS idx_prompt_rollouts_created ON prompt_rollouts (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS prompt_rollouts;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Once-only analytics funnel steps. distinct_id is a keyed hash, never a raw user ID.
+CREATE TABLE IF NOT EXISTS funnel_milestones (
+    distinct_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    reached_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (distinct_id, event)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS funnel_milestones;
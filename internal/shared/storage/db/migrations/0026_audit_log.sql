@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    action TEXT NOT NULL,
+    actor_user_id TEXT NOT NULL,
+    subject_user_id TEXT NOT NULL DEFAULT '',
+    impersonation_id TEXT NOT NULL DEFAULT '',
+    method TEXT NOT NULL DEFAULT '',
+    route TEXT NOT NULL DEFAULT '',
+    status INT NOT NULL DEFAULT 0,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_subject ON audit_log (subject_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_impersonation ON audit_log (impersonation_id, created_at DESC)
+    WHERE impersonation_id <> '';
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
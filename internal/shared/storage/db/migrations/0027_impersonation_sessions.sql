@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id TEXT PRIMARY KEY,
+    admin_user_id TEXT NOT NULL,
+    target_user_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    allow_mutations BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_created ON impersonation_sessions (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS impersonation_sessions;
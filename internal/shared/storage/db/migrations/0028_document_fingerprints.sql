@@ -0,0 +1,8 @@
+-- +goose Up
+-- MinHash signature of the extracted text, computed lazily for near-duplicate detection.
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS text_signature TEXT;
+CREATE INDEX IF NOT EXISTS idx_documents_user_checksum ON documents (user_id, checksum) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_user_checksum;
+ALTER TABLE documents DROP COLUMN IF EXISTS text_signature;
@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS candidate_pools (
+    id UUID PRIMARY KEY,
+    org_id TEXT NOT NULL REFERENCES org_usage(org_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_candidate_pools_org_name ON candidate_pools (org_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS candidate_pool_documents (
+    pool_id UUID NOT NULL REFERENCES candidate_pools(id) ON DELETE CASCADE,
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    owner_user_id TEXT NOT NULL,
+    added_by TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    stage TEXT NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (pool_id, document_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_pool_documents_document ON candidate_pool_documents (document_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS candidate_pool_documents;
+DROP TABLE IF EXISTS candidate_pools;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS secrets (
+    ref TEXT PRIMARY KEY,
+    sealed BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS org_integrations (
+    id TEXT PRIMARY KEY,
+    org_id TEXT NOT NULL REFERENCES org_usage(org_id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    name TEXT NOT NULL,
+    settings JSONB NOT NULL DEFAULT '{}'::jsonb,
+    secret_ref TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    last_delivery_run_id TEXT,
+    last_delivery_status TEXT,
+    last_delivery_error TEXT,
+    last_delivery_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_integrations_org ON org_integrations (org_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS org_integrations;
+DROP TABLE IF EXISTS secrets;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS data_residency (
+    kind TEXT NOT NULL,
+    subject_id TEXT NOT NULL,
+    region TEXT NOT NULL,
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (kind, subject_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS data_residency;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS learning_plan BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE analyses DROP COLUMN IF EXISTS learning_plan;
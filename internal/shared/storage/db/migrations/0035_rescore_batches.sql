@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rescore_batches (
+    id TEXT PRIMARY KEY,
+    prompt_version TEXT NOT NULL,
+    model TEXT NOT NULL DEFAULT '',
+    mode TEXT NOT NULL DEFAULT '',
+    since TIMESTAMPTZ NOT NULL,
+    max_analyses INT NOT NULL,
+    requests_per_minute INT NOT NULL,
+    trigger TEXT NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS rescore_batches_status_created_idx ON rescore_batches(status, created_at);
+
+-- Shadow results of a batch. They are never read back into analyses.
+CREATE TABLE IF NOT EXISTS analysis_rescores (
+    batch_id TEXT NOT NULL REFERENCES rescore_batches(id) ON DELETE CASCADE,
+    analysis_id TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    model TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    original_score DOUBLE PRECISION,
+    shadow_score DOUBLE PRECISION,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (batch_id, analysis_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS analysis_rescores;
+DROP TABLE IF EXISTS rescore_batches;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Bullet rewrites written into a document by executed apply runs. Later
+-- analyses of the document mark matching suggestions as already applied.
+CREATE TABLE IF NOT EXISTS applied_rewrites (
+    id TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    apply_run_id TEXT NOT NULL,
+    section TEXT NOT NULL DEFAULT '',
+    before_text TEXT NOT NULL,
+    after_text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS applied_rewrites_document_text_idx
+    ON applied_rewrites(document_id, md5(before_text), md5(after_text));
+CREATE INDEX IF NOT EXISTS applied_rewrites_user_document_idx ON applied_rewrites(user_id, document_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS applied_rewrites;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Feature flag rules edited through the admin API. Flags without a row use
+-- their FEATURE_FLAGS, remote or built-in default.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key TEXT PRIMARY KEY,
+    enabled BOOLEAN,
+    percent INT NOT NULL DEFAULT 0,
+    users JSONB NOT NULL DEFAULT '[]'::jsonb,
+    orgs JSONB NOT NULL DEFAULT '[]'::jsonb,
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS feature_flags;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Daily LLM spend per budget scope: "global" or "org:<id>". Day is the UTC
+-- date the spend is counted under.
+CREATE TABLE IF NOT EXISTS llm_spend (
+    scope TEXT NOT NULL,
+    day DATE NOT NULL,
+    prompt_tokens BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    cost_micro_usd BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, day)
+);
+
+-- The organization an analysis is charged to, for its org budget.
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS org_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS analyses_budget_deferred_idx
+    ON analyses (created_at)
+    WHERE status = 'budget_deferred' AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS analyses_budget_deferred_idx;
+ALTER TABLE analyses DROP COLUMN IF EXISTS org_id;
+DROP TABLE IF EXISTS llm_spend;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Set on documents whose local-storage upload could not be found when legacy
+-- uploads were moved to S3, so the move skips them and operators can follow up.
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS storage_unreachable_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS documents_legacy_storage_idx
+    ON documents (id)
+    WHERE (storage_provider IS NULL OR storage_provider <> 's3')
+      AND storage_unreachable_at IS NULL
+      AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS documents_legacy_storage_idx;
+ALTER TABLE documents DROP COLUMN IF EXISTS storage_unreachable_at;
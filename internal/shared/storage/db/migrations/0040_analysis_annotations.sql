@@ -0,0 +1,24 @@
+-- +goose Up
+-- Users' corrections to analysis results, such as marking an issue not
+-- applicable or a missing keyword as a false positive. They are merged into the
+-- result when it is rendered; the stored result is never rewritten.
+CREATE TABLE IF NOT EXISTS analysis_annotations (
+    id UUID PRIMARY KEY,
+    analysis_id UUID NOT NULL,
+    target TEXT NOT NULL,
+    -- ref identifies the item within its target: the index of an issue or
+    -- bullet rewrite, or the lower-cased keyword.
+    ref TEXT NOT NULL,
+    item_index INTEGER,
+    keyword TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    prompt_version TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS analysis_annotations_item_idx ON analysis_annotations(analysis_id, target, ref);
+CREATE INDEX IF NOT EXISTS analysis_annotations_created_at_idx ON analysis_annotations(created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS analysis_annotations;
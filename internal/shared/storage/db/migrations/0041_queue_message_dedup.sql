@@ -0,0 +1,15 @@
+-- +goose Up
+-- Recently claimed queue deliveries, so workers skip duplicate SQS deliveries
+-- of the same message. Rows older than the dedup window are purged by the
+-- workers themselves.
+CREATE TABLE IF NOT EXISTS queue_message_dedup (
+    message_id TEXT NOT NULL,
+    analysis_id TEXT NOT NULL,
+    claimed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (message_id, analysis_id)
+);
+
+CREATE INDEX IF NOT EXISTS queue_message_dedup_claimed_at_idx ON queue_message_dedup(claimed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS queue_message_dedup;
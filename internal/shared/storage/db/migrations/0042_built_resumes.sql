@@ -0,0 +1,18 @@
+-- +goose Up
+-- Resumes written in the in-product builder. The content is a ResumeModel
+-- rendered to DOCX on demand; document_id is the document last created from
+-- the rendered resume for analysis.
+CREATE TABLE IF NOT EXISTS built_resumes (
+    id UUID PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    model JSONB NOT NULL,
+    document_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_built_resumes_user_updated ON built_resumes (user_id, updated_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS built_resumes;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Per-user and per-organization overrides of LLM_ARCHIVE_MODE and
+-- LLM_ARCHIVE_RETENTION_DAYS.
+CREATE TABLE IF NOT EXISTS llm_archive_policies (
+    kind TEXT NOT NULL,
+    subject_id TEXT NOT NULL,
+    mode TEXT NOT NULL,
+    retention_days INTEGER NOT NULL DEFAULT 0,
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (kind, subject_id)
+);
+
+-- Archived LLM calls. prompt and response are only set in full mode and hold
+-- values sealed under the archive keys, never plaintext.
+CREATE TABLE IF NOT EXISTS llm_archive_records (
+    id UUID PRIMARY KEY,
+    interaction TEXT NOT NULL,
+    mode TEXT NOT NULL,
+    owner_id TEXT NOT NULL DEFAULT '',
+    analysis_id TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    prompt_version TEXT NOT NULL DEFAULT '',
+    prompt_sha256 TEXT NOT NULL,
+    response_sha256 TEXT NOT NULL DEFAULT '',
+    prompt TEXT NOT NULL DEFAULT '',
+    response TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS llm_archive_records_owner_idx ON llm_archive_records(owner_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS llm_archive_records_analysis_idx ON llm_archive_records(analysis_id) WHERE analysis_id <> '';
+CREATE INDEX IF NOT EXISTS llm_archive_records_expires_at_idx ON llm_archive_records(expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS llm_archive_records;
+DROP TABLE IF EXISTS llm_archive_policies;
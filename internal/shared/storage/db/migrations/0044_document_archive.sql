@@ -0,0 +1,13 @@
+-- +goose Up
+-- Set on documents a user archived. Archived documents are left out of the
+-- history list and are never the current document, but stay readable by ID and
+-- keep their analyses.
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS documents_archived_idx
+    ON documents (user_id, archived_at DESC)
+    WHERE archived_at IS NOT NULL AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS documents_archived_idx;
+ALTER TABLE documents DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Caps the highlights a template shows under each role; 0 means no cap. The
+-- sections a template supports are derived from its validated tokens.
+ALTER TABLE resume_templates ADD COLUMN IF NOT EXISTS max_highlights_per_role INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE resume_templates DROP COLUMN IF EXISTS max_highlights_per_role;
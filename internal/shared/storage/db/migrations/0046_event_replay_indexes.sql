@@ -0,0 +1,8 @@
+-- +goose Up
+-- Event replays page through history in time order.
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_funnel_milestones_reached ON funnel_milestones (reached_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_funnel_milestones_reached;
+DROP INDEX IF EXISTS idx_audit_log_created;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Reuse is keyed on the supporting documents too. Rows without any get the empty
+-- hash HashSupportingDocuments returns; older rows with supporting documents
+-- stay NULL and are never reused.
+ALTER TABLE analyses
+    ADD COLUMN IF NOT EXISTS supporting_documents_hash TEXT;
+
+UPDATE analyses
+SET supporting_documents_hash = ''
+WHERE supporting_documents IS NULL OR supporting_documents = '[]'::jsonb;
+
+DROP INDEX IF EXISTS idx_analyses_document_jd_mode;
+CREATE INDEX IF NOT EXISTS idx_analyses_document_reuse
+    ON analyses (user_id, document_id, job_description_hash, mode, supporting_documents_hash, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_analyses_document_reuse;
+CREATE INDEX IF NOT EXISTS idx_analyses_document_jd_mode
+    ON analyses (user_id, document_id, job_description_hash, mode, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+ALTER TABLE analyses
+    DROP COLUMN IF EXISTS supporting_documents_hash;
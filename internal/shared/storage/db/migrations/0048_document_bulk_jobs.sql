@@ -0,0 +1,27 @@
+-- +goose Up
+-- Bulk document requests and the outcome of each document, so a request cut
+-- short by a failure can be resumed without redoing what already succeeded.
+CREATE TABLE IF NOT EXISTS document_bulk_jobs (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS document_bulk_jobs_user_idx ON document_bulk_jobs(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS document_bulk_job_items (
+    job_id TEXT NOT NULL REFERENCES document_bulk_jobs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    document_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    analyses_deleted INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (job_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_bulk_job_items;
+DROP TABLE IF EXISTS document_bulk_jobs;
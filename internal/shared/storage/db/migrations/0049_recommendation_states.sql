@@ -0,0 +1,14 @@
+-- +goose Up
+-- Users' progress on recommendations, keyed by the recommendation's stable ID
+-- so the state carries over when the same resume is analyzed again. A
+-- recommendation without a row is open.
+CREATE TABLE IF NOT EXISTS recommendation_states (
+    user_id TEXT NOT NULL,
+    recommendation_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, recommendation_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS recommendation_states;
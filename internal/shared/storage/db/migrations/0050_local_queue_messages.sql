@@ -0,0 +1,17 @@
+-- +goose Up
+-- Queue messages of the standalone profile, which runs without SQS. A message
+-- is hidden until available_at; receiving one moves available_at past the
+-- visibility timeout, and handling it deletes the row.
+CREATE TABLE IF NOT EXISTS local_queue_messages (
+    id BIGSERIAL PRIMARY KEY,
+    queue TEXT NOT NULL,
+    body TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    available_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS local_queue_messages_available_idx ON local_queue_messages(queue, available_at, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS local_queue_messages;
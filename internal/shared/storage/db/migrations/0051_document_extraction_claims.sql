@@ -0,0 +1,10 @@
+-- +goose Up
+-- Extraction claims let one worker at a time extract a document. A claim is
+-- live until extraction_claimed_until; the others wait for extracted_text_key
+-- and reuse it, and take over once a crashed worker's claim has lapsed.
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS extraction_claim_id TEXT;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS extraction_claimed_until TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE documents DROP COLUMN IF EXISTS extraction_claimed_until;
+ALTER TABLE documents DROP COLUMN IF EXISTS extraction_claim_id;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS target_role TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE analyses DROP COLUMN IF EXISTS target_role;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE generated_resumes ADD COLUMN IF NOT EXISTS model_key TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE generated_resumes DROP COLUMN IF EXISTS model_key;
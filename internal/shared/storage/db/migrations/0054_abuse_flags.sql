@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS abuse_flags (
+    id TEXT PRIMARY KEY,
+    cluster_key TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    rule TEXT NOT NULL,
+    guest_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+    guest_count INT NOT NULL DEFAULT 0,
+    request_count INT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    reviewed_by TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- A cluster has at most one flag waiting for review.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_abuse_flags_open
+    ON abuse_flags (cluster_key)
+    WHERE status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_abuse_flags_cluster ON abuse_flags (cluster_key, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_abuse_flags_created ON abuse_flags (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS abuse_flags;
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Conn runs statements. *sql.DB and *sql.Tx implement it.
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// UnitOfWork runs fn so that the writes of every repo joining it land together
+// or not at all. Repos join through the context passed to fn: Postgres repos
+// with ConnFor or InTx, memory repos with OnRollback. A unit started inside
+// another joins the outer one.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Run runs fn in uow, or directly when uow is nil.
+func Run(ctx context.Context, uow UnitOfWork, fn func(ctx context.Context) error) error {
+	if uow == nil {
+		return fn(ctx)
+	}
+	return uow.Do(ctx, fn)
+}
+
+type txKey struct{}
+
+// TxUnitOfWork runs each unit in one Postgres transaction.
+type TxUnitOfWork struct {
+	DB *sql.DB
+}
+
+// Do commits the transaction when fn succeeds and rolls it back otherwise.
+func (u TxUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := u.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConnFor returns the transaction of the unit of work in ctx, or pool outside
+// one. Repo writes that may be part of a unit run on it.
+func ConnFor(ctx context.Context, pool *sql.DB) Conn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// InTx runs fn in the transaction of the unit of work in ctx, or in a
+// transaction of its own outside one. Repo methods whose statements must land
+// together use it instead of BeginTx, so they can also join a larger unit.
+func InTx(ctx context.Context, pool *sql.DB, fn func(conn Conn) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+type journalKey struct{}
+
+// journal holds the undo steps of a memory unit of work, in write order.
+type journal struct {
+	mu   sync.Mutex
+	undo []func()
+}
+
+// MemoryUnitOfWork is the in-memory counterpart of TxUnitOfWork. Units run one
+// at a time; memory repos register how to undo each write with OnRollback, and
+// a failed unit runs those steps newest first. Writes made outside a unit are
+// not isolated from one in progress, which is fine for dev and tests.
+type MemoryUnitOfWork struct {
+	mu sync.Mutex
+}
+
+// Do undoes the writes fn registered when it fails.
+func (u *MemoryUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(journalKey{}).(*journal); ok {
+		return fn(ctx)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	j := &journal{}
+	if err := fn(context.WithValue(ctx, journalKey{}, j)); err != nil {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		for i := len(j.undo) - 1; i >= 0; i-- {
+			j.undo[i]()
+		}
+		return err
+	}
+	return nil
+}
+
+// OnRollback registers undo to run if the memory unit of work in ctx fails.
+// Outside a unit it does nothing. undo runs after the write returned, so it
+// must take the repo's lock itself.
+func OnRollback(ctx context.Context, undo func()) {
+	j, ok := ctx.Value(journalKey{}).(*journal)
+	if !ok {
+		return
+	}
+	j.mu.Lock()
+	j.undo = append(j.undo, undo)
+	j.mu.Unlock()
+}
+
+var (
+	_ UnitOfWork = TxUnitOfWork{}
+	_ UnitOfWork = (*MemoryUnitOfWork)(nil)
+)
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestTxUnitOfWorkCommitsJoinedWrites(t *testing.T) {
+	pool, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer pool.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE documents").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM b").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	uow := TxUnitOfWork{DB: pool}
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := ConnFor(ctx, pool).ExecContext(ctx, "UPDATE documents SET user_id = $1", "u1"); err != nil {
+			return err
+		}
+		// InTx and nested units join the outer transaction instead of
+		// beginning their own.
+		return uow.Do(ctx, func(ctx context.Context) error {
+			return InTx(ctx, pool, func(conn Conn) error {
+				if _, err := conn.ExecContext(ctx, "DELETE FROM a"); err != nil {
+					return err
+				}
+				_, err := conn.ExecContext(ctx, "DELETE FROM b")
+				return err
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTxUnitOfWorkRollsBackOnError(t *testing.T) {
+	pool, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer pool.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE documents").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE analyses").WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	err = TxUnitOfWork{DB: pool}.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := ConnFor(ctx, pool).ExecContext(ctx, "UPDATE documents"); err != nil {
+			return err
+		}
+		_, err := ConnFor(ctx, pool).ExecContext(ctx, "UPDATE analyses")
+		return err
+	})
+	if err == nil {
+		t.Fatal("expected the unit to fail")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryUnitOfWorkUndoesInReverseOrder(t *testing.T) {
+	var undone []string
+	uow := &MemoryUnitOfWork{}
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		OnRollback(ctx, func() { undone = append(undone, "first") })
+		return uow.Do(ctx, func(ctx context.Context) error {
+			OnRollback(ctx, func() { undone = append(undone, "second") })
+			return errors.New("boom")
+		})
+	})
+	if err == nil || len(undone) != 2 || undone[0] != "second" || undone[1] != "first" {
+		t.Fatalf("expected both writes undone newest first, got %v err=%v", undone, err)
+	}
+
+	undone = nil
+	if err := uow.Do(context.Background(), func(ctx context.Context) error {
+		OnRollback(ctx, func() { undone = append(undone, "kept") })
+		return nil
+	}); err != nil || len(undone) != 0 {
+		t.Fatalf("expected a successful unit to keep its writes, got %v err=%v", undone, err)
+	}
+
+	// Outside a unit there is nothing to roll back.
+	OnRollback(context.Background(), func() { t.Fatal("undo ran outside a unit") })
+}
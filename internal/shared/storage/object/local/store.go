@@ -128,6 +128,23 @@ func (s *Store) SaveWithKey(ctx context.Context, storageKey string, contentType
 	return written, nil
 }
 
+// Delete removes a stored object. Missing objects are not an error.
+func (s *Store) Delete(ctx context.Context, storageKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	clean := filepath.Clean(storageKey)
+	if strings.HasPrefix(clean, "..") || filepath.IsAbs(clean) {
+		return fmt.Errorf("invalid storage key")
+	}
+
+	if err := os.Remove(filepath.Join(s.baseDir, clean)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 func randomID() string {
 	var b [16]byte
 	if _, err := rand.Read(b[:]); err != nil {
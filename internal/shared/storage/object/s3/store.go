@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path"
 	"strings"
 	"time"
@@ -46,13 +47,23 @@ func New(ctx context.Context, region, bucket, prefix, kmsKeyID string) (object.O
 	}
 
 	return &Store{
-		client:   s3.NewFromConfig(cfg),
+		client:   s3.NewFromConfig(cfg, ClientOptions()...),
 		bucket:   bucket,
 		prefix:   normalizePrefix(prefix),
 		kmsKeyID: strings.TrimSpace(kmsKeyID),
 	}, nil
 }
 
+// ClientOptions returns S3 client options derived from the environment.
+// RA_S3_FORCE_PATH_STYLE=true enables path-style addressing for S3-compatible
+// endpoints such as LocalStack or MinIO (set AWS_ENDPOINT_URL to point at them).
+func ClientOptions() []func(*s3.Options) {
+	if !strings.EqualFold(strings.TrimSpace(os.Getenv("RA_S3_FORCE_PATH_STYLE")), "true") {
+		return nil
+	}
+	return []func(*s3.Options){func(o *s3.Options) { o.UsePathStyle = true }}
+}
+
 // Save uploads the reader contents to S3 under the user's namespace.
 func (s *Store) Save(ctx context.Context, userId string, fileName string, r io.Reader) (string, int64, string, error) {
 	sanitizedName, err := util.SanitizeFileName(fileName)
@@ -148,6 +159,22 @@ func (s *Store) SaveWithKey(ctx context.Context, storageKey string, contentType
 	return counter.n, nil
 }
 
+// Delete removes a stored object. S3 treats deleting a missing key as success.
+func (s *Store) Delete(ctx context.Context, storageKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	objectKey := applyPrefix(s.prefix, storageKey)
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectKey),
+	}); err != nil {
+		return fmt.Errorf("s3 delete object bucket=%s key=%s: %w", s.bucket, objectKey, err)
+	}
+	return nil
+}
+
 type countingReader struct {
 	r io.Reader
 	n int64
@@ -1,12 +1,49 @@
 package telemetry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"resume-backend/internal/shared/ctxmeta"
 )
 
+// output overrides the destination for tests; nil writes to the current os.Stdout.
+var output io.Writer
+
+// Level is the minimum severity that is written.
+type Level int32
+
+const (
+	LevelInfo Level = iota
+	LevelError
+)
+
+// minLevel can be changed at runtime; info lines are dropped at LevelError.
+var minLevel atomic.Int32
+
+// ParseLevel reads "info" or "error". Empty means info.
+func ParseLevel(raw string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "info":
+		return LevelInfo, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q", raw)
+	}
+}
+
+// SetLevel changes the minimum level for subsequent log lines.
+func SetLevel(level Level) {
+	minLevel.Store(int32(level))
+}
+
 type logEntry struct {
 	TS     string         `json:"ts"`
 	Level  string         `json:"level"`
@@ -24,7 +61,36 @@ func Error(msg string, fields map[string]any) {
 	write("error", msg, fields)
 }
 
+// InfoContext is Info with the request metadata on ctx (request, user, analysis and
+// trace IDs) added to fields. Fields set explicitly win.
+func InfoContext(ctx context.Context, msg string, fields map[string]any) {
+	write("info", msg, withContextFields(ctx, fields))
+}
+
+// ErrorContext is Error with the request metadata on ctx added to fields.
+func ErrorContext(ctx context.Context, msg string, fields map[string]any) {
+	write("error", msg, withContextFields(ctx, fields))
+}
+
+func withContextFields(ctx context.Context, fields map[string]any) map[string]any {
+	meta := ctxmeta.Fields(ctx)
+	if len(meta) == 0 {
+		return fields
+	}
+	for k, v := range fields {
+		meta[k] = v
+	}
+	return meta
+}
+
 func write(level, msg string, fields map[string]any) {
+	if level == "info" && Level(minLevel.Load()) > LevelInfo {
+		return
+	}
+	fields, keep := process(msg, fields)
+	if !keep {
+		return
+	}
 	entry := make(map[string]any, len(fields)+3)
 	entry["ts"] = time.Now().UTC().Format(time.RFC3339)
 	entry["level"] = level
@@ -34,8 +100,15 @@ func write(level, msg string, fields map[string]any) {
 	}
 	data, err := json.Marshal(entry)
 	if err != nil {
-		fmt.Fprintf(os.Stdout, `{"ts":"%s","level":"error","msg":"logger marshal failed","err":%q}`+"\n", time.Now().UTC().Format(time.RFC3339), err.Error())
+		fmt.Fprintf(destination(), `{"ts":"%s","level":"error","msg":"logger marshal failed","err":%q}`+"\n", time.Now().UTC().Format(time.RFC3339), err.Error())
 		return
 	}
-	fmt.Fprintln(os.Stdout, string(data))
+	fmt.Fprintln(destination(), string(data))
+}
+
+func destination() io.Writer {
+	if output != nil {
+		return output
+	}
+	return os.Stdout
 }
@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"resume-backend/internal/shared/ctxmeta"
+)
+
+func TestInfoContextAddsRequestMetadata(t *testing.T) {
+	buf := captureOutput(t)
+	Configure(Options{})
+
+	ctx := ctxmeta.WithRequestID(context.Background(), "req-1")
+	ctx = ctxmeta.WithAnalysisID(ctx, "analysis-from-ctx")
+	InfoContext(ctx, "analysis.status", map[string]any{"analysis_id": "analysis-1", "status": "processing"})
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log line: %v", err)
+	}
+	if entry["request_id"] != "req-1" {
+		t.Fatalf("expected request_id from context, got %v", entry["request_id"])
+	}
+	if entry["analysis_id"] != "analysis-1" {
+		t.Fatalf("expected explicit analysis_id to win, got %v", entry["analysis_id"])
+	}
+	if _, ok := entry["user_id"]; ok {
+		t.Fatalf("expected no user_id when the context has none")
+	}
+}
@@ -0,0 +1,173 @@
+package telemetry
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"resume-backend/internal/shared/config"
+)
+
+// Options controls how events are sampled and scrubbed before they are written.
+type Options struct {
+	// ScrubIdentifiers hashes identifier fields (user, document, email, ip) when true.
+	ScrubIdentifiers bool
+	// HashSalt keys the identifier hash so values cannot be reversed by dictionary lookup.
+	HashSalt string
+	// DefaultSampleRate applies to events without an explicit rate. Nil means
+	// 1.0; zero drops those events.
+	DefaultSampleRate *float64
+	// SampleRates maps event names to a keep probability in [0,1].
+	SampleRates map[string]float64
+}
+
+// identifierFields are hashed when scrubbing is enabled.
+var identifierFields = map[string]struct{}{
+	"user_id":         {},
+	"userId":          {},
+	"document_id":     {},
+	"documentId":      {},
+	"guest_id":        {},
+	"email":           {},
+	"client_ip":       {},
+	"impersonator_id": {},
+	"ip":              {},
+}
+
+var (
+	pipelineMu sync.RWMutex
+	pipeline   Options
+	randFloat  = rand.Float64
+)
+
+// Configure replaces the active pipeline options. Rates above 1 are capped;
+// negative rates are rejected and leave the active options unchanged.
+func Configure(opts Options) error {
+	if opts.DefaultSampleRate != nil {
+		if *opts.DefaultSampleRate < 0 {
+			return fmt.Errorf("telemetry: default sample rate %v is negative", *opts.DefaultSampleRate)
+		}
+		rate := clampRate(*opts.DefaultSampleRate)
+		opts.DefaultSampleRate = &rate
+	}
+	rates := make(map[string]float64, len(opts.SampleRates))
+	for name, rate := range opts.SampleRates {
+		if rate < 0 {
+			return fmt.Errorf("telemetry: sample rate %v for %q is negative", rate, name)
+		}
+		rates[name] = clampRate(rate)
+	}
+	opts.SampleRates = rates
+
+	pipelineMu.Lock()
+	pipeline = opts
+	pipelineMu.Unlock()
+	return nil
+}
+
+// ConfigureFromEnv configures the pipeline for the given app env.
+// Dev-like envs pass identifiers through untouched; everything else hashes them.
+// RA_TELEMETRY_SAMPLE_RATES takes "event=rate" pairs separated by commas,
+// RA_TELEMETRY_DEFAULT_SAMPLE_RATE sets the fallback rate and
+// RA_TELEMETRY_HASH_SALT keys identifier hashing.
+func ConfigureFromEnv(env string) error {
+	opts := Options{
+		ScrubIdentifiers: !config.IsDevLike(env),
+		HashSalt:         os.Getenv("RA_TELEMETRY_HASH_SALT"),
+		SampleRates:      parseSampleRates(os.Getenv("RA_TELEMETRY_SAMPLE_RATES")),
+	}
+	if raw := strings.TrimSpace(os.Getenv("RA_TELEMETRY_DEFAULT_SAMPLE_RATE")); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("telemetry: invalid RA_TELEMETRY_DEFAULT_SAMPLE_RATE %q", raw)
+		}
+		opts.DefaultSampleRate = &rate
+	}
+	return Configure(opts)
+}
+
+func currentOptions() Options {
+	pipelineMu.RLock()
+	defer pipelineMu.RUnlock()
+	return pipeline
+}
+
+// process applies sampling and scrubbing. It returns false when the event is dropped.
+func process(msg string, fields map[string]any) (map[string]any, bool) {
+	opts := currentOptions()
+
+	rate := 1.0
+	if opts.DefaultSampleRate != nil {
+		rate = *opts.DefaultSampleRate
+	}
+	if r, ok := opts.SampleRates[msg]; ok {
+		rate = r
+	}
+	if rate <= 0 {
+		return nil, false
+	}
+	if rate < 1 && randFloat() >= rate {
+		return nil, false
+	}
+
+	out := fields
+	if opts.ScrubIdentifiers || rate < 1 {
+		out = make(map[string]any, len(fields)+1)
+		for k, v := range fields {
+			if _, ok := identifierFields[k]; ok && opts.ScrubIdentifiers {
+				out[k] = hashIdentifier(opts.HashSalt, v)
+				continue
+			}
+			out[k] = v
+		}
+		if rate < 1 {
+			out["sample_rate"] = rate
+		}
+	}
+	return out, true
+}
+
+func hashIdentifier(salt string, value any) any {
+	if value == nil {
+		return nil
+	}
+	raw, ok := value.(string)
+	if !ok {
+		raw = fmt.Sprint(value)
+	}
+	if raw == "" {
+		return raw
+	}
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(raw))
+	return "h:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+func parseSampleRates(raw string) map[string]float64 {
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+		rates[strings.TrimSpace(name)] = rate
+	}
+	return rates
+}
+
+func clampRate(rate float64) float64 {
+	if rate > 1 {
+		return 1
+	}
+	return rate
+}
@@ -0,0 +1,126 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func captureOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	prevOut, prevRand := output, randFloat
+	output = buf
+	t.Cleanup(func() {
+		output = prevOut
+		randFloat = prevRand
+		Configure(Options{})
+	})
+	return buf
+}
+
+func TestScrubIdentifiersInProd(t *testing.T) {
+	buf := captureOutput(t)
+	Configure(Options{ScrubIdentifiers: true, HashSalt: "salt"})
+
+	Info("analysis.start", map[string]any{"user_id": "guest:abc", "document_id": "doc-1", "request_id": "req-1"})
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log line: %v", err)
+	}
+	userID, _ := entry["user_id"].(string)
+	if !strings.HasPrefix(userID, "h:") || strings.Contains(userID, "abc") {
+		t.Fatalf("expected hashed user_id, got %q", userID)
+	}
+	if entry["document_id"] == "doc-1" {
+		t.Fatalf("expected hashed document_id")
+	}
+	if entry["request_id"] != "req-1" {
+		t.Fatalf("expected request_id passthrough, got %v", entry["request_id"])
+	}
+	if userID != hashIdentifier("salt", "guest:abc") {
+		t.Fatalf("expected stable hash")
+	}
+}
+
+func TestDevPassthrough(t *testing.T) {
+	buf := captureOutput(t)
+	Configure(Options{})
+
+	fields := map[string]any{"user_id": "u-1"}
+	Info("analysis.start", fields)
+
+	if !strings.Contains(buf.String(), `"user_id":"u-1"`) {
+		t.Fatalf("expected raw user_id in dev, got %s", buf.String())
+	}
+}
+
+func TestSamplingPerEvent(t *testing.T) {
+	buf := captureOutput(t)
+	Configure(Options{SampleRates: map[string]float64{"request.complete": 0.25, "noisy": 0}})
+
+	randFloat = func() float64 { return 0.5 }
+	Info("request.complete", nil)
+	Info("noisy", nil)
+	if buf.Len() != 0 {
+		t.Fatalf("expected events to be dropped, got %s", buf.String())
+	}
+
+	randFloat = func() float64 { return 0.1 }
+	Info("request.complete", nil)
+	Error("other", nil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `"sample_rate":0.25`) {
+		t.Fatalf("expected sample_rate annotation, got %s", lines[0])
+	}
+}
+
+func TestDefaultSampleRate(t *testing.T) {
+	buf := captureOutput(t)
+	zero := 0.0
+	if err := Configure(Options{DefaultSampleRate: &zero, SampleRates: map[string]float64{"kept": 1}}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	Info("dropped", nil)
+	Info("kept", nil)
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"kept"`) {
+		t.Fatalf("expected only the explicitly sampled event, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := Configure(Options{}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	Info("unset", nil)
+	if !strings.Contains(buf.String(), `"unset"`) {
+		t.Fatalf("expected an unset default rate to keep events, got %s", buf.String())
+	}
+}
+
+func TestConfigureRejectsNegativeRates(t *testing.T) {
+	captureOutput(t)
+	negative := -0.5
+	if err := Configure(Options{DefaultSampleRate: &negative}); err == nil {
+		t.Fatal("expected negative default rate to be rejected")
+	}
+	if err := Configure(Options{SampleRates: map[string]float64{"noisy": -1}}); err == nil {
+		t.Fatal("expected negative event rate to be rejected")
+	}
+
+	t.Setenv("RA_TELEMETRY_DEFAULT_SAMPLE_RATE", "-1")
+	if err := ConfigureFromEnv("production"); err == nil {
+		t.Fatal("expected negative RA_TELEMETRY_DEFAULT_SAMPLE_RATE to be rejected")
+	}
+}
+
+func TestParseSampleRates(t *testing.T) {
+	got := parseSampleRates("a=0.5, b = 1,bad,c=x")
+	if len(got) != 2 || got["a"] != 0.5 || got["b"] != 1 {
+		t.Fatalf("unexpected rates: %v", got)
+	}
+}
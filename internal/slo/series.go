@@ -0,0 +1,65 @@
+package slo
+
+import "time"
+
+// series counts good and total events per minute over a bounded period.
+type series struct {
+	period  int64
+	buckets map[int64]*bucket
+	latest  int64
+}
+
+type bucket struct {
+	good  uint64
+	total uint64
+}
+
+func newSeries(period time.Duration) *series {
+	return &series{period: int64(period / time.Minute), buckets: map[int64]*bucket{}}
+}
+
+func minuteOf(t time.Time) int64 {
+	return t.Unix() / 60
+}
+
+// add records one event. Events older than the period are dropped.
+func (s *series) add(at time.Time, good bool) {
+	minute := minuteOf(at)
+	if minute > s.latest {
+		s.latest = minute
+		s.prune()
+	}
+	if minute <= s.latest-s.period {
+		return
+	}
+	b := s.buckets[minute]
+	if b == nil {
+		b = &bucket{}
+		s.buckets[minute] = b
+	}
+	b.total++
+	if good {
+		b.good++
+	}
+}
+
+func (s *series) prune() {
+	for minute := range s.buckets {
+		if minute <= s.latest-s.period {
+			delete(s.buckets, minute)
+		}
+	}
+}
+
+// sum totals the events in the window ending at now.
+func (s *series) sum(now time.Time, window time.Duration) (good, total uint64) {
+	end := minuteOf(now)
+	start := end - int64(window/time.Minute)
+	for minute, b := range s.buckets {
+		if minute > start && minute <= end {
+			good += b.good
+			total += b.total
+		}
+	}
+	return good, total
+}
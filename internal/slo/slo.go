@@ -0,0 +1,89 @@
+package slo
+
+import "time"
+
+// Objective kinds.
+const (
+	// KindRequestLatency counts a request as good when it was served without a
+	// server error within Threshold.
+	KindRequestLatency = "request_latency"
+	// KindAnalysisCompletion counts an analysis as good when it completed within
+	// Threshold of being created.
+	KindAnalysisCompletion = "analysis_completion"
+)
+
+// Alert severities, from the multi-window burn-rate policy.
+const (
+	SeverityPage   = "page"
+	SeverityTicket = "ticket"
+)
+
+// Objective is one service level objective: the share of events that must be
+// good over Period.
+type Objective struct {
+	Name        string
+	Description string
+	Kind        string
+	// Route selects requests by method and route template, for request
+	// objectives, e.g. "GET /api/v1/analyses/:id".
+	Route     string
+	Threshold time.Duration
+	// Target is the required share of good events, e.g. 0.99.
+	Target float64
+}
+
+// DefaultObjectives returns the objectives tracked when none are configured.
+func DefaultObjectives() []Objective {
+	return []Objective{
+		{
+			Name:        "analysis_read_latency",
+			Description: "99% of GET /analyses/:id requests are served under 300ms",
+			Kind:        KindRequestLatency,
+			Route:       "GET /api/v1/analyses/:id",
+			Threshold:   300 * time.Millisecond,
+			Target:      0.99,
+		},
+		{
+			Name:        "analysis_completion_time",
+			Description: "95% of analyses complete within 2 minutes of creation",
+			Kind:        KindAnalysisCompletion,
+			Threshold:   2 * time.Minute,
+			Target:      0.95,
+		},
+	}
+}
+
+// BurnWindow pairs a long and a short window for one alert severity. Both must
+// burn at least Rate for the alert to fire: the long window proves the burn is
+// significant and the short one that it is still going on.
+type BurnWindow struct {
+	Severity string
+	Long     time.Duration
+	Short    time.Duration
+	Rate     float64
+}
+
+// Policy tunes compliance and alerting.
+type Policy struct {
+	// Period is the compliance window that error budgets are measured over.
+	Period time.Duration
+	// Windows are checked in order; the first that fires sets the severity.
+	Windows []BurnWindow
+	// MinEvents ignores a long window with fewer events, so a handful of slow
+	// requests after a quiet night cannot page anyone.
+	MinEvents int
+}
+
+// DefaultPolicy returns the policy used for zero-valued fields. The burn rates
+// are the usual ones for a 30-day budget: 14.4 spends 2% of it in an hour, 6
+// spends 5% in six hours.
+func DefaultPolicy() Policy {
+	return Policy{
+		Period: 30 * 24 * time.Hour,
+		Windows: []BurnWindow{
+			{Severity: SeverityPage, Long: time.Hour, Short: 5 * time.Minute, Rate: 14.4},
+			{Severity: SeverityTicket, Long: 6 * time.Hour, Short: 30 * time.Minute, Rate: 6},
+		},
+		MinEvents: 20,
+	}
+}
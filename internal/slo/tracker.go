@@ -0,0 +1,315 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// maxCompletionSync caps how many completed analyses one sync reads.
+const maxCompletionSync = 5000
+
+// CompletionSource lists completed analyses, newest first. Analyses complete in
+// the worker, so the API reads them back from the repo rather than observing
+// them.
+type CompletionSource interface {
+	ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error)
+}
+
+// Status is an objective's compliance and burn rates.
+type Status struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Target      float64 `json:"target"`
+	ThresholdMs int64   `json:"thresholdMs"`
+	// Good and Total count events over the compliance period, since the process
+	// started.
+	Good       uint64   `json:"good"`
+	Total      uint64   `json:"total"`
+	Compliance *float64 `json:"compliance,omitempty"`
+	// BudgetRemaining is the share of the period's error budget left; negative
+	// once the objective is missed.
+	BudgetRemaining float64 `json:"budgetRemaining"`
+	// BurnRates maps each alert window to how fast it spends the budget: 1 uses
+	// exactly the budget over the period.
+	BurnRates map[string]float64 `json:"burnRates"`
+	Alert     string             `json:"alert,omitempty"`
+}
+
+// Report is the admin stats section.
+type Report struct {
+	PeriodHours int       `json:"periodHours"`
+	Since       time.Time `json:"since"`
+	Objectives  []Status  `json:"objectives"`
+}
+
+// Tracker measures objectives from the request metrics stream and from
+// completed analyses, and raises alerts when an error budget burns too fast.
+type Tracker struct {
+	Objectives  []Objective
+	Policy      Policy
+	Completions CompletionSource
+	Now         func() time.Time
+
+	mu      sync.Mutex
+	series  map[string]*series
+	alerts  map[string]string
+	started time.Time
+	// cursor is the completion time synced up to; seen holds the analyses
+	// completed exactly then, so they are not counted twice.
+	cursor time.Time
+	seen   map[string]bool
+}
+
+// NewTracker constructs a Tracker with DefaultPolicy.
+func NewTracker(objectives []Objective, completions CompletionSource) *Tracker {
+	return &Tracker{Objectives: objectives, Policy: DefaultPolicy(), Completions: completions, started: time.Now().UTC()}
+}
+
+// Subscribe feeds served requests into the tracker.
+func (t *Tracker) Subscribe() {
+	metrics.SubscribeRequests(t.ObserveRequest)
+}
+
+// ObserveRequest records a request against every objective for its route.
+func (t *Tracker) ObserveRequest(sample metrics.RequestSample) {
+	for _, o := range t.Objectives {
+		if o.Kind != KindRequestLatency || o.Route != sample.Route {
+			continue
+		}
+		good := sample.Status < 500 && sample.Duration <= o.Threshold
+		t.record(o.Name, sample.At, good)
+	}
+}
+
+func (t *Tracker) record(name string, at time.Time, good bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seriesFor(name).add(at, good)
+}
+
+func (t *Tracker) seriesFor(name string) *series {
+	if t.series == nil {
+		t.series = map[string]*series{}
+	}
+	s := t.series[name]
+	if s == nil {
+		s = newSeries(t.policy().Period)
+		t.series[name] = s
+	}
+	return s
+}
+
+// Run syncs completions and checks burn rates every interval until ctx is
+// done.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := t.Evaluate(ctx); err != nil && ctx.Err() == nil {
+			telemetry.Error("slo.evaluate_failed", map[string]any{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats reports every objective for the admin stats endpoint.
+func (t *Tracker) Stats(ctx context.Context) (any, error) {
+	if err := t.syncCompletions(ctx); err != nil {
+		return nil, err
+	}
+	return t.report(), nil
+}
+
+// Evaluate syncs completions, then emits an alert event for each objective
+// whose severity rose and a recovery event for each that stopped alerting.
+func (t *Tracker) Evaluate(ctx context.Context) (Report, error) {
+	if err := t.syncCompletions(ctx); err != nil {
+		return Report{}, err
+	}
+	report := t.report()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.alerts == nil {
+		t.alerts = map[string]string{}
+	}
+	for _, status := range report.Objectives {
+		previous := t.alerts[status.Name]
+		if status.Alert == previous {
+			continue
+		}
+		t.alerts[status.Name] = status.Alert
+		fields := map[string]any{
+			"objective":        status.Name,
+			"severity":         status.Alert,
+			"previous":         previous,
+			"burn_rates":       status.BurnRates,
+			"budget_remaining": status.BudgetRemaining,
+		}
+		switch {
+		case status.Alert == "":
+			telemetry.Info("slo.burn_rate_recovered", fields)
+		case previous == "" || status.Alert == SeverityPage:
+			telemetry.Error("slo.burn_rate_alert", fields)
+		default:
+			// Dropped from page to ticket: still burning, but less urgently.
+			telemetry.Info("slo.burn_rate_alert", fields)
+		}
+	}
+	return report, nil
+}
+
+// syncCompletions records analyses completed since the last sync. The first
+// sync reads back as far as the longest alert window.
+func (t *Tracker) syncCompletions(ctx context.Context) error {
+	objectives := t.completionObjectives()
+	if t.Completions == nil || len(objectives) == 0 {
+		return nil
+	}
+	t.mu.Lock()
+	since := t.cursor
+	if since.IsZero() {
+		since = t.now().Add(-t.longestWindow())
+	}
+	t.mu.Unlock()
+
+	items, err := t.Completions.ListCompletedSince(ctx, since, maxCompletionSync)
+	if err != nil {
+		return fmt.Errorf("list completed analyses: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.seen == nil {
+		t.seen = map[string]bool{}
+	}
+	cursor := t.cursor
+	// Oldest first, so the cursor only moves forward.
+	for i := len(items) - 1; i >= 0; i-- {
+		a := items[i]
+		if a.CompletedAt == nil || a.CompletedAt.Before(t.cursor) {
+			continue
+		}
+		at := a.CompletedAt.UTC()
+		if at.Equal(t.cursor) && t.seen[a.ID] {
+			continue
+		}
+		if at.After(cursor) {
+			cursor = at
+			clear(t.seen)
+		}
+		t.seen[a.ID] = true
+		for _, o := range objectives {
+			t.seriesFor(o.Name).add(at, at.Sub(a.CreatedAt) <= o.Threshold)
+		}
+	}
+	if cursor.After(t.cursor) {
+		t.cursor = cursor
+	} else if t.cursor.IsZero() {
+		t.cursor = since
+	}
+	return nil
+}
+
+func (t *Tracker) completionObjectives() []Objective {
+	var out []Objective
+	for _, o := range t.Objectives {
+		if o.Kind == KindAnalysisCompletion {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func (t *Tracker) report() Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.policy()
+	now := t.now()
+	report := Report{PeriodHours: int(p.Period / time.Hour), Since: t.started}
+	for _, o := range t.Objectives {
+		s := t.seriesFor(o.Name)
+		status := Status{
+			Name:            o.Name,
+			Description:     o.Description,
+			Target:          o.Target,
+			ThresholdMs:     o.Threshold.Milliseconds(),
+			BudgetRemaining: 1,
+			BurnRates:       map[string]float64{},
+		}
+		status.Good, status.Total = s.sum(now, p.Period)
+		budget := 1 - o.Target
+		if status.Total > 0 {
+			compliance := float64(status.Good) / float64(status.Total)
+			status.Compliance = &compliance
+			if budget > 0 {
+				status.BudgetRemaining = 1 - (1-compliance)/budget
+			}
+		}
+		burn := func(window time.Duration) (float64, uint64) {
+			good, total := s.sum(now, window)
+			if total == 0 || budget <= 0 {
+				return 0, total
+			}
+			rate := float64(total-good) / float64(total) / budget
+			status.BurnRates[windowLabel(window)] = rate
+			return rate, total
+		}
+		for _, w := range p.Windows {
+			long, events := burn(w.Long)
+			short, _ := burn(w.Short)
+			if status.Alert == "" && events >= uint64(p.MinEvents) && long >= w.Rate && short >= w.Rate {
+				status.Alert = w.Severity
+			}
+		}
+		report.Objectives = append(report.Objectives, status)
+	}
+	return report
+}
+
+func (t *Tracker) longestWindow() time.Duration {
+	var longest time.Duration
+	for _, w := range t.policy().Windows {
+		longest = max(longest, w.Long)
+	}
+	return longest
+}
+
+func (t *Tracker) policy() Policy {
+	p := t.Policy
+	def := DefaultPolicy()
+	if p.Period <= 0 {
+		p.Period = def.Period
+	}
+	if len(p.Windows) == 0 {
+		p.Windows = def.Windows
+	}
+	if p.MinEvents <= 0 {
+		p.MinEvents = def.MinEvents
+	}
+	return p
+}
+
+func (t *Tracker) now() time.Time {
+	if t.Now != nil {
+		return t.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func windowLabel(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return fmt.Sprintf("%dm", d/time.Minute)
+}
@@ -0,0 +1,157 @@
+package slo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/shared/metrics"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+type fakeCompletions struct{ items []analyses.Analysis }
+
+func (f *fakeCompletions) ListCompletedSince(ctx context.Context, since time.Time, limit int) ([]analyses.Analysis, error) {
+	var out []analyses.Analysis
+	// Newest first, like the repos.
+	for i := len(f.items) - 1; i >= 0; i-- {
+		if !f.items[i].CompletedAt.Before(since) {
+			out = append(out, f.items[i])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCompletions) add(id string, created time.Time, took time.Duration) {
+	completed := created.Add(took)
+	f.items = append(f.items, analyses.Analysis{ID: id, CreatedAt: created, CompletedAt: &completed, Status: analyses.StatusCompleted})
+}
+
+func newTestTracker(source CompletionSource) (*Tracker, *clock) {
+	clk := &clock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	t := NewTracker(DefaultObjectives(), source)
+	t.Now = clk.Now
+	return t, clk
+}
+
+func request(clk *clock, route string, status int, d time.Duration) metrics.RequestSample {
+	return metrics.RequestSample{Route: route, Status: status, Duration: d, At: clk.now}
+}
+
+func statusOf(t *testing.T, report Report, name string) Status {
+	t.Helper()
+	for _, s := range report.Objectives {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("objective %s not reported", name)
+	return Status{}
+}
+
+func TestTrackerPagesOnFastBurnAndRecovers(t *testing.T) {
+	tracker, clk := newTestTracker(nil)
+	const route = "GET /api/v1/analyses/:id"
+
+	// An hour of healthy traffic, then a burst of slow reads.
+	for i := 0; i < 60; i++ {
+		tracker.ObserveRequest(request(clk, route, 200, 50*time.Millisecond))
+		tracker.ObserveRequest(request(clk, "GET /api/v1/documents", 200, time.Second))
+		clk.now = clk.now.Add(time.Minute)
+	}
+	report, err := tracker.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	healthy := statusOf(t, report, "analysis_read_latency")
+	if healthy.Total != 60 || healthy.Alert != "" || healthy.BudgetRemaining != 1 {
+		t.Fatalf("unexpected healthy status: %+v", healthy)
+	}
+
+	for i := 0; i < 20; i++ {
+		tracker.ObserveRequest(request(clk, route, 200, time.Second))
+	}
+	report, _ = tracker.Evaluate(context.Background())
+	burning := statusOf(t, report, "analysis_read_latency")
+	if burning.Alert != SeverityPage || burning.BurnRates["5m"] < 14.4 || burning.BudgetRemaining >= 0 {
+		t.Fatalf("expected a page, got %+v", burning)
+	}
+	if tracker.alerts["analysis_read_latency"] != SeverityPage {
+		t.Fatalf("expected the alert to be remembered")
+	}
+
+	// Healthy again: the 5-minute window clears first and the page drops to a
+	// ticket, then the 30-minute window clears too.
+	clk.now = clk.now.Add(10 * time.Minute)
+	tracker.ObserveRequest(request(clk, route, 200, 50*time.Millisecond))
+	report, _ = tracker.Evaluate(context.Background())
+	if slowing := statusOf(t, report, "analysis_read_latency"); slowing.Alert != SeverityTicket {
+		t.Fatalf("expected a ticket, got %+v", slowing)
+	}
+	for i := 0; i < 40; i++ {
+		clk.now = clk.now.Add(time.Minute)
+		tracker.ObserveRequest(request(clk, route, 200, 50*time.Millisecond))
+	}
+	report, _ = tracker.Evaluate(context.Background())
+	if recovered := statusOf(t, report, "analysis_read_latency"); recovered.Alert != "" {
+		t.Fatalf("expected recovery, got %+v", recovered)
+	}
+}
+
+func TestTrackerCountsServerErrorsAsBad(t *testing.T) {
+	tracker, clk := newTestTracker(nil)
+	tracker.ObserveRequest(request(clk, "GET /api/v1/analyses/:id", 500, time.Millisecond))
+	tracker.ObserveRequest(request(clk, "GET /api/v1/analyses/:id", 404, time.Millisecond))
+
+	status := statusOf(t, tracker.report(), "analysis_read_latency")
+	if status.Good != 1 || status.Total != 2 {
+		t.Fatalf("unexpected counts: %+v", status)
+	}
+}
+
+func TestTrackerSyncsCompletionsOnce(t *testing.T) {
+	source := &fakeCompletions{}
+	tracker, clk := newTestTracker(source)
+	start := clk.now.Add(-30 * time.Minute)
+	source.add("fast", start, 30*time.Second)
+	source.add("slow", start, 5*time.Minute)
+	// Completed before the first sync window: not counted.
+	source.add("old", clk.now.Add(-24*time.Hour), time.Second)
+
+	for i := 0; i < 2; i++ {
+		if _, err := tracker.Stats(context.Background()); err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+	}
+	status := statusOf(t, tracker.report(), "analysis_completion_time")
+	if status.Good != 1 || status.Total != 2 {
+		t.Fatalf("expected 1 of 2 good, got %+v", status)
+	}
+
+	source.add("later", clk.now.Add(-time.Minute), time.Minute)
+	if _, err := tracker.Evaluate(context.Background()); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	status = statusOf(t, tracker.report(), "analysis_completion_time")
+	if status.Good != 2 || status.Total != 3 {
+		t.Fatalf("expected new completion counted once, got %+v", status)
+	}
+}
+
+func TestSeriesDropsEventsOutsidePeriod(t *testing.T) {
+	s := newSeries(time.Hour)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.add(now.Add(-2*time.Hour), true)
+	s.add(now, false)
+	s.add(now.Add(-2*time.Hour), true)
+	if good, total := s.sum(now, time.Hour); good != 0 || total != 1 {
+		t.Fatalf("expected only the recent event, got %d/%d", good, total)
+	}
+	if len(s.buckets) != 1 {
+		t.Fatalf("expected old buckets pruned, got %d", len(s.buckets))
+	}
+}
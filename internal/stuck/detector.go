@@ -0,0 +1,270 @@
+package stuck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/internal/usage"
+)
+
+// Kinds of inconsistent state the detector looks for.
+const (
+	// KindAnalysisStalled is an analysis still processing with no progress
+	// since AnalysisAfter. Remediation fails it with a retryable error.
+	KindAnalysisStalled = "analysis_stalled"
+	// KindDocumentTextMissing is a document whose extracted text key points at
+	// an object the store no longer has. Remediation clears the extraction so
+	// the document is extracted again on next use.
+	KindDocumentTextMissing = "document_text_missing"
+	// KindApplyRunStalled is an apply run left planned for longer than
+	// ApplyRunAfter. These are only reported; the user starts a new run.
+	KindApplyRunStalled = "apply_run_stalled"
+)
+
+// Config tunes what counts as stuck.
+type Config struct {
+	// AnalysisAfter is how long a processing analysis may go without an update.
+	AnalysisAfter time.Duration
+	// ApplyRunAfter is how long an apply run may stay planned.
+	ApplyRunAfter time.Duration
+	// DocumentWindow is how far back extractions are checked against the store.
+	DocumentWindow time.Duration
+	// MaxPerKind caps how many records of each kind one scan reads.
+	MaxPerKind int
+}
+
+// DefaultConfig returns the settings used when none are supplied.
+func DefaultConfig() Config {
+	return Config{
+		AnalysisAfter:  30 * time.Minute,
+		ApplyRunAfter:  time.Hour,
+		DocumentWindow: 24 * time.Hour,
+		MaxPerKind:     200,
+	}
+}
+
+// AnalysisChecker finds and fails stalled analyses and checks extracted text.
+type AnalysisChecker interface {
+	ListStalled(ctx context.Context, before time.Time, limit int) ([]analyses.Analysis, error)
+	FailStalled(ctx context.Context, analysis analyses.Analysis, before time.Time) (bool, error)
+	ExtractedTextMissing(ctx context.Context, doc documents.Document) (bool, error)
+}
+
+// ApplyRunSource lists apply runs that never left the planned status.
+type ApplyRunSource interface {
+	ListStalledApplyRuns(ctx context.Context, createdBefore time.Time, limit int) ([]usage.ApplyRun, error)
+}
+
+// Finding is one record found in an inconsistent state.
+type Finding struct {
+	Kind       string    `json:"kind"`
+	ResourceID string    `json:"resourceId"`
+	UserID     string    `json:"userId,omitempty"`
+	Since      time.Time `json:"since"`
+	Detail     string    `json:"detail,omitempty"`
+	Remediated bool      `json:"remediated"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Report is the outcome of one scan.
+type Report struct {
+	ScannedAt  time.Time `json:"scannedAt"`
+	Remediate  bool      `json:"remediate"`
+	Findings   []Finding `json:"findings"`
+	Remediated int       `json:"remediated"`
+}
+
+// Detector periodically scans for analyses, documents and apply runs left in
+// a state no code path will move them out of. Each finding is logged as a
+// stuck.detected error so alerting picks it up; with Remediate set, the known
+// cases are repaired as well. Any source may be nil to skip its check.
+type Detector struct {
+	Analyses  AnalysisChecker
+	Documents documents.ExtractionAuditor
+	ApplyRuns ApplyRunSource
+	Config    Config
+	Remediate bool
+	Now       func() time.Time
+
+	mu     sync.RWMutex
+	latest *Report
+}
+
+// NewDetector constructs a Detector with DefaultConfig. The document check
+// runs only when docRepo can list extractions.
+func NewDetector(checker AnalysisChecker, docRepo documents.DocumentsRepo, applyRuns ApplyRunSource) *Detector {
+	auditor, _ := docRepo.(documents.ExtractionAuditor)
+	return &Detector{Analyses: checker, Documents: auditor, ApplyRuns: applyRuns, Config: DefaultConfig()}
+}
+
+// Latest returns the report of the most recent scheduled scan, if any.
+func (d *Detector) Latest() (Report, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.latest == nil {
+		return Report{}, false
+	}
+	return *d.latest, true
+}
+
+// Stats returns the latest report for the admin stats endpoint, running a
+// read-only scan if none has run yet in this process.
+func (d *Detector) Stats(ctx context.Context) (any, error) {
+	if report, ok := d.Latest(); ok {
+		return report, nil
+	}
+	return d.Scan(ctx, false)
+}
+
+// Run scans every interval until ctx is cancelled, remediating when Remediate
+// is set.
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := d.Scan(ctx, d.Remediate)
+		if err != nil && ctx.Err() == nil {
+			telemetry.Error("stuck.scan_failed", map[string]any{"error": err.Error()})
+		}
+		if ctx.Err() == nil {
+			metrics.RecordStuckStateScan(len(report.Findings), report.Remediated)
+			d.mu.Lock()
+			d.latest = &report
+			d.mu.Unlock()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan runs every check once. A failing check does not stop the others; its
+// error is returned alongside the findings of the rest.
+func (d *Detector) Scan(ctx context.Context, remediate bool) (Report, error) {
+	cfg := d.config()
+	now := d.now()
+	report := Report{ScannedAt: now, Remediate: remediate, Findings: []Finding{}}
+	var errs []error
+	add := func(f Finding) {
+		if f.Remediated {
+			report.Remediated++
+		}
+		report.Findings = append(report.Findings, f)
+		fields := map[string]any{
+			"kind":        f.Kind,
+			"resource_id": f.ResourceID,
+			"since":       f.Since.Format(time.RFC3339),
+			"remediated":  f.Remediated,
+		}
+		if f.UserID != "" {
+			fields["user_id"] = f.UserID
+		}
+		if f.Detail != "" {
+			fields["detail"] = f.Detail
+		}
+		if f.Error != "" {
+			fields["error"] = f.Error
+		}
+		telemetry.Error("stuck.detected", fields)
+	}
+
+	if d.Analyses != nil {
+		before := now.Add(-cfg.AnalysisAfter)
+		items, err := d.Analyses.ListStalled(ctx, before, cfg.MaxPerKind)
+		if err != nil && !errors.Is(err, analyses.ErrStalledUnsupported) {
+			errs = append(errs, err)
+		}
+		for _, a := range items {
+			f := Finding{Kind: KindAnalysisStalled, ResourceID: a.ID, UserID: a.UserID, Since: a.UpdatedAt, Detail: "processing with no progress"}
+			if remediate {
+				f.Remediated, err = d.Analyses.FailStalled(ctx, a, before)
+				if err != nil {
+					f.Error = err.Error()
+				} else if !f.Remediated {
+					// It made progress between the list and the update.
+					continue
+				}
+			}
+			add(f)
+		}
+	}
+
+	if d.Analyses != nil && d.Documents != nil {
+		docs, err := d.Documents.ListExtractedSince(ctx, now.Add(-cfg.DocumentWindow), cfg.MaxPerKind)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, doc := range docs {
+			missing, err := d.Analyses.ExtractedTextMissing(ctx, doc)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !missing {
+				continue
+			}
+			f := Finding{Kind: KindDocumentTextMissing, ResourceID: doc.ID, UserID: doc.UserID, Detail: "extracted text object is missing"}
+			if doc.ExtractedAt != nil {
+				f.Since = *doc.ExtractedAt
+			}
+			if remediate {
+				if err := d.Documents.ClearExtraction(ctx, doc.UserID, doc.ID); err != nil {
+					f.Error = err.Error()
+				} else {
+					f.Remediated = true
+				}
+			}
+			add(f)
+		}
+	}
+
+	if d.ApplyRuns != nil {
+		runs, err := d.ApplyRuns.ListStalledApplyRuns(ctx, now.Add(-cfg.ApplyRunAfter), cfg.MaxPerKind)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, run := range runs {
+			add(Finding{Kind: KindApplyRunStalled, ResourceID: run.ID, UserID: run.UserID, Since: run.CreatedAt, Detail: "apply run never executed"})
+		}
+	}
+
+	telemetry.Info("stuck.scan_completed", map[string]any{
+		"findings":   len(report.Findings),
+		"remediated": report.Remediated,
+		"remediate":  remediate,
+	})
+	return report, errors.Join(errs...)
+}
+
+func (d *Detector) config() Config {
+	cfg := d.Config
+	def := DefaultConfig()
+	if cfg.AnalysisAfter <= 0 {
+		cfg.AnalysisAfter = def.AnalysisAfter
+	}
+	if cfg.ApplyRunAfter <= 0 {
+		cfg.ApplyRunAfter = def.ApplyRunAfter
+	}
+	if cfg.DocumentWindow <= 0 {
+		cfg.DocumentWindow = def.DocumentWindow
+	}
+	if cfg.MaxPerKind <= 0 {
+		cfg.MaxPerKind = def.MaxPerKind
+	}
+	return cfg
+}
+
+func (d *Detector) now() time.Time {
+	if d.Now != nil {
+		return d.Now().UTC()
+	}
+	return time.Now().UTC()
+}
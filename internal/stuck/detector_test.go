@@ -0,0 +1,145 @@
+package stuck
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/documents"
+	local "resume-backend/internal/shared/storage/object/local"
+	"resume-backend/internal/usage"
+)
+
+type fixture struct {
+	detector     *Detector
+	analysisRepo *analyses.MemoryRepo
+	docRepo      *documents.MemoryRepo
+	now          time.Time
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	store := local.New(t.TempDir())
+	analysisRepo := analyses.NewMemoryRepo()
+	docRepo := documents.NewMemoryRepo()
+	usageSvc := usage.NewService()
+	svc := &analyses.Service{Repo: analysisRepo, DocRepo: docRepo, Store: store}
+
+	stale := now.Add(-2 * time.Hour)
+	for _, a := range []analyses.Analysis{
+		{ID: "stalled", UserID: "user-1", DocumentID: "doc-present", Status: analyses.StatusProcessing, CreatedAt: stale, UpdatedAt: stale},
+		{ID: "busy", UserID: "user-1", DocumentID: "doc-present", Status: analyses.StatusProcessing, CreatedAt: stale, UpdatedAt: now},
+		{ID: "done", UserID: "user-1", DocumentID: "doc-present", Status: analyses.StatusCompleted, CreatedAt: stale, UpdatedAt: stale},
+	} {
+		if err := analysisRepo.Create(ctx, a); err != nil {
+			t.Fatalf("create analysis: %v", err)
+		}
+	}
+
+	key, _, _, err := store.Save(ctx, "user-1", "extracted.txt", strings.NewReader("Go developer"))
+	if err != nil {
+		t.Fatalf("save text: %v", err)
+	}
+	extractedAt := now.Add(-time.Hour)
+	for _, doc := range []documents.Document{
+		{ID: "doc-present", UserID: "user-1", ExtractedTextKey: key, ExtractedAt: &extractedAt, CreatedAt: stale},
+		{ID: "doc-missing", UserID: "user-1", ExtractedTextKey: "user-1/gone.txt", ExtractedAt: &extractedAt, CreatedAt: stale},
+		{ID: "doc-pending", UserID: "user-1", CreatedAt: stale},
+	} {
+		if err := docRepo.Create(ctx, doc); err != nil {
+			t.Fatalf("create document: %v", err)
+		}
+	}
+
+	for _, run := range []usage.ApplyRun{
+		{ID: "run-stalled", UserID: "user-1", AnalysisID: "done", Status: usage.ApplyRunStatusPlanned, CreatedAt: stale},
+		{ID: "run-fresh", UserID: "user-1", AnalysisID: "done", Status: usage.ApplyRunStatusPlanned, CreatedAt: now},
+	} {
+		if err := usageSvc.CreateApplyRun(ctx, run); err != nil {
+			t.Fatalf("create apply run: %v", err)
+		}
+	}
+
+	detector := NewDetector(svc, docRepo, usageSvc)
+	detector.Now = func() time.Time { return now }
+	return fixture{detector: detector, analysisRepo: analysisRepo, docRepo: docRepo, now: now}
+}
+
+func kinds(report Report) map[string]string {
+	out := map[string]string{}
+	for _, f := range report.Findings {
+		out[f.ResourceID] = f.Kind
+	}
+	return out
+}
+
+func TestScanReportsWithoutRemediating(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	report, err := f.detector.Scan(ctx, false)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	want := map[string]string{
+		"stalled":     KindAnalysisStalled,
+		"doc-missing": KindDocumentTextMissing,
+		"run-stalled": KindApplyRunStalled,
+	}
+	got := kinds(report)
+	if len(got) != len(want) {
+		t.Fatalf("expected findings %v, got %v", want, got)
+	}
+	for id, kind := range want {
+		if got[id] != kind {
+			t.Fatalf("expected %s to be %s, got %v", id, kind, got)
+		}
+	}
+	if report.Remediated != 0 {
+		t.Fatalf("expected no remediation, got %d", report.Remediated)
+	}
+
+	analysis, _ := f.analysisRepo.GetByID(ctx, "stalled")
+	if analysis.Status != analyses.StatusProcessing {
+		t.Fatalf("expected analysis untouched, got %s", analysis.Status)
+	}
+}
+
+func TestScanRemediatesKnownCases(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	report, err := f.detector.Scan(ctx, true)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if report.Remediated != 2 {
+		t.Fatalf("expected 2 remediated findings, got %+v", report.Findings)
+	}
+
+	analysis, _ := f.analysisRepo.GetByID(ctx, "stalled")
+	if analysis.Status != analyses.StatusFailed || analysis.ErrorCode != analyses.ErrorCodeStalled || !analysis.ErrorRetryable {
+		t.Fatalf("expected retryable stalled failure, got %+v", analysis)
+	}
+	busy, _ := f.analysisRepo.GetByID(ctx, "busy")
+	if busy.Status != analyses.StatusProcessing {
+		t.Fatalf("expected busy analysis untouched, got %s", busy.Status)
+	}
+	doc, _ := f.docRepo.GetByID(ctx, "user-1", "doc-missing")
+	if doc.ExtractedTextKey != "" || doc.ExtractedAt != nil {
+		t.Fatalf("expected extraction cleared, got %+v", doc)
+	}
+
+	// Apply runs are only reported, so a second scan finds nothing else.
+	report, err = f.detector.Scan(ctx, true)
+	if err != nil {
+		t.Fatalf("rescan: %v", err)
+	}
+	if got := kinds(report); len(got) != 1 || got["run-stalled"] != KindApplyRunStalled {
+		t.Fatalf("expected only the apply run on rescan, got %v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package stuck
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/respond"
+)
+
+// Handler serves the stuck-state admin API.
+type Handler struct {
+	Detector *Detector
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(d *Detector) *Handler {
+	return &Handler{Detector: d}
+}
+
+// RegisterRoutes attaches stuck-state routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/stuck-states", h.scan)
+}
+
+// scan runs a read-only scan now and returns what it found. Remediation is
+// left to the scheduled scan.
+func (h *Handler) scan(c *gin.Context) {
+	report, err := h.Detector.Scan(c.Request.Context(), false)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "stuck_scan_failed", err.Error(), nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, report)
+}
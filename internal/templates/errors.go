@@ -0,0 +1,17 @@
+package templates
+
+import "errors"
+
+var (
+	// ErrNotFound indicates the template was not found.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput indicates validation or bad input.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrConflict indicates a template with that name already exists.
+	ErrConflict = errors.New("template already exists")
+
+	// ErrValidationFailed indicates the template did not pass the validation sandbox.
+	ErrValidationFailed = errors.New("template failed validation")
+)
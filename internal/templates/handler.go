@@ -0,0 +1,115 @@
+package templates
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/shared/server/middleware"
+	"resume-backend/internal/shared/server/respond"
+)
+
+const maxTemplateSize = 5 << 20 // 5MB
+
+// Handler serves the template admin API.
+type Handler struct {
+	Svc *Service
+}
+
+// NewHandler constructs a Handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{Svc: svc}
+}
+
+// RegisterRoutes attaches template routes to an admin-only router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/templates", h.upload)
+	rg.GET("/templates", h.list)
+	rg.GET("/templates/:id", h.get)
+}
+
+func (h *Handler) upload(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTemplateSize+(1<<20))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "file is required", nil)
+		return
+	}
+	if fileHeader.Size > maxTemplateSize {
+		respond.Error(c, http.StatusRequestEntityTooLarge, "validation_error", "template must be at most 5MB", nil)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "unable to read file", nil)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, maxTemplateSize+1))
+	if err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "unable to read file", nil)
+		return
+	}
+
+	maxHighlights := 0
+	if raw := strings.TrimSpace(c.PostForm("maxHighlightsPerRole")); raw != "" {
+		if maxHighlights, err = strconv.Atoi(raw); err != nil {
+			respond.Error(c, http.StatusBadRequest, "validation_error", "maxHighlightsPerRole must be a number", nil)
+			return
+		}
+	}
+
+	tpl, report, err := h.Svc.Upload(c.Request.Context(), middleware.UserIDFromContext(c), UploadInput{
+		Name:                 c.PostForm("name"),
+		Description:          c.PostForm("description"),
+		FileName:             fileHeader.Filename,
+		Content:              content,
+		MaxHighlightsPerRole: maxHighlights,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrValidationFailed):
+			respond.Error(c, http.StatusUnprocessableEntity, "template_invalid", "template failed validation", report)
+		case errors.Is(err, ErrInvalidInput):
+			respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+		case errors.Is(err, ErrConflict):
+			respond.Error(c, http.StatusConflict, "conflict", "a template with that name already exists", nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to publish template", nil)
+		}
+		return
+	}
+	respond.JSON(c, http.StatusCreated, tpl)
+}
+
+func (h *Handler) list(c *gin.Context) {
+	items, err := h.Svc.List(c.Request.Context())
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to list templates", nil)
+		return
+	}
+	builtin, err := h.Svc.Builtin()
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to list templates", nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, gin.H{"templates": items, "builtin": builtin})
+}
+
+func (h *Handler) get(c *gin.Context) {
+	tpl, err := h.Svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			respond.Error(c, http.StatusNotFound, "not_found", "template not found", nil)
+			return
+		}
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load template", nil)
+		return
+	}
+	respond.JSON(c, http.StatusOK, tpl)
+}
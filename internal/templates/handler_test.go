@@ -0,0 +1,219 @@
+package templates_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/shared/auth"
+	"resume-backend/internal/shared/config"
+)
+
+const productionTemplate = "assets/templates/resume_modern_ats_v1.docx"
+
+func newTestApp(t *testing.T) *bootstrap.App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	// The built-in template is read relative to the repo root.
+	t.Chdir(filepath.Join("..", ".."))
+
+	app, err := bootstrap.Build(config.Config{
+		Port:            "0",
+		CORSAllowOrigin: []string{"http://localhost:5173"},
+		LocalStoreDir:   t.TempDir(),
+		Env:             "dev",
+		ObjectStoreType: "local",
+		AdminUserIDs:    []string{"admin-1"},
+	})
+	if err != nil {
+		t.Fatalf("bootstrap build: %v", err)
+	}
+	return app
+}
+
+func bearer(t *testing.T, userID string) string {
+	t.Helper()
+	token, err := auth.SignJWT(auth.Claims{Sub: userID})
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func uploadTemplate(t *testing.T, router http.Handler, authorization, name, fileName string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("name", name); err != nil {
+		t.Fatalf("write name: %v", err)
+	}
+	fileWriter, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fileWriter.Write(content); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/templates", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", authorization)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestAdminTemplateUploadPublishesOnlyValidTemplates(t *testing.T) {
+	app := newTestApp(t)
+	admin := bearer(t, "admin-1")
+	content, err := os.ReadFile(productionTemplate)
+	if err != nil {
+		t.Fatalf("read template: %v", err)
+	}
+
+	if resp := uploadTemplate(t, app.Router, bearer(t, "user-1"), "Modern", "modern.docx", content); resp.Code != http.StatusForbidden {
+		t.Fatalf("non-admin upload: expected 403, got %d", resp.Code)
+	}
+
+	resp := uploadTemplate(t, app.Router, admin, "Modern ATS", "modern.docx", content)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var published struct {
+		ID         string `json:"id"`
+		SHA256     string `json:"sha256"`
+		Validation struct {
+			Checks []struct {
+				Name   string `json:"name"`
+				Passed bool   `json:"passed"`
+			} `json:"checks"`
+		} `json:"validation"`
+		Capabilities struct {
+			Sections []string `json:"sections"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &published); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+	if published.ID == "" || published.SHA256 == "" || len(published.Validation.Checks) != 3 {
+		t.Fatalf("unexpected published template: %s", resp.Body.String())
+	}
+	if len(published.Capabilities.Sections) == 0 {
+		t.Fatalf("expected the template's sections to be listed: %s", resp.Body.String())
+	}
+
+	if resp := uploadTemplate(t, app.Router, admin, "modern ats", "modern.docx", content); resp.Code != http.StatusConflict {
+		t.Fatalf("duplicate name: expected 409, got %d", resp.Code)
+	}
+	if resp := uploadTemplate(t, app.Router, admin, "Notes", "notes.txt", []byte("hello")); resp.Code != http.StatusBadRequest {
+		t.Fatalf("non-docx upload: expected 400, got %d", resp.Code)
+	}
+
+	resp = uploadTemplate(t, app.Router, admin, "Broken", "broken.docx", []byte("not a zip"))
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid template: expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var rejected struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Checks []struct {
+					Name   string   `json:"name"`
+					Passed bool     `json:"passed"`
+					Issues []string `json:"issues"`
+				} `json:"checks"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &rejected); err != nil {
+		t.Fatalf("decode rejection: %v", err)
+	}
+	checks := rejected.Error.Details.Checks
+	if rejected.Error.Code != "template_invalid" || len(checks) != 1 || checks[0].Name != "structure" || checks[0].Passed || len(checks[0].Issues) == 0 {
+		t.Fatalf("expected failed structure check, got %s", resp.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/templates", nil)
+	req.Header.Set("Authorization", admin)
+	list := httptest.NewRecorder()
+	app.Router.ServeHTTP(list, req)
+	if list.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d", list.Code)
+	}
+	var listed struct {
+		Templates []struct {
+			ID string `json:"id"`
+		} `json:"templates"`
+		Builtin struct {
+			ID           string `json:"id"`
+			Capabilities struct {
+				Sections []string `json:"sections"`
+			} `json:"capabilities"`
+		} `json:"builtin"`
+	}
+	if err := json.Unmarshal(list.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(listed.Templates) != 1 || listed.Templates[0].ID != published.ID {
+		t.Fatalf("expected only the valid template to be published, got %s", list.Body.String())
+	}
+	if listed.Builtin.ID != "resume_modern_ats_v1" || len(listed.Builtin.Capabilities.Sections) == 0 {
+		t.Fatalf("expected the built-in template's capabilities, got %s", list.Body.String())
+	}
+}
+
+func TestAdminTemplateUploadCapsHighlights(t *testing.T) {
+	app := newTestApp(t)
+	admin := bearer(t, "admin-1")
+	content, err := os.ReadFile(productionTemplate)
+	if err != nil {
+		t.Fatalf("read template: %v", err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("name", "Compact")
+	_ = writer.WriteField("maxHighlightsPerRole", "3")
+	fileWriter, _ := writer.CreateFormFile("file", "compact.docx")
+	_, _ = fileWriter.Write(content)
+	_ = writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/templates", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", admin)
+	resp := httptest.NewRecorder()
+	app.Router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var published struct {
+		ID           string `json:"id"`
+		Capabilities struct {
+			MaxHighlightsPerRole int `json:"maxHighlightsPerRole"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &published); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+	if published.Capabilities.MaxHighlightsPerRole != 3 {
+		t.Fatalf("expected a cap of 3 highlights, got %s", resp.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/templates/"+published.ID, nil)
+	req.Header.Set("Authorization", admin)
+	got := httptest.NewRecorder()
+	app.Router.ServeHTTP(got, req)
+	if got.Code != http.StatusOK || !bytes.Contains(got.Body.Bytes(), []byte(`"maxHighlightsPerRole":3`)) {
+		t.Fatalf("get: expected the cap to be kept, got %d: %s", got.Code, got.Body.String())
+	}
+}
@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"time"
+
+	"resume-backend/resume/render"
+)
+
+// Template is a published DOCX resume template.
+type Template struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	FileName    string                `json:"fileName"`
+	StorageKey  string                `json:"-"`
+	SizeBytes   int64                 `json:"sizeBytes"`
+	SHA256      string                `json:"sha256"`
+	Validation  render.TemplateReport `json:"validation"`
+	UploadedBy  string                `json:"uploadedBy"`
+	PublishedAt time.Time             `json:"publishedAt"`
+
+	// Capabilities lists the sections the template renders; content for
+	// any other section is left out with a warning.
+	Capabilities render.Capabilities `json:"capabilities"`
+}
+
+// UploadInput is a template submitted for publication.
+type UploadInput struct {
+	Name        string
+	Description string
+	FileName    string
+	Content     []byte
+	// MaxHighlightsPerRole caps the highlights shown under each role; zero
+	// means no cap.
+	MaxHighlightsPerRole int
+}
+
+// Builtin describes the template renders use when none is chosen.
+type Builtin struct {
+	ID           string              `json:"id"`
+	Capabilities render.Capabilities `json:"capabilities"`
+}
@@ -0,0 +1,13 @@
+package templates
+
+import "context"
+
+// Repo persists published template metadata.
+type Repo interface {
+	// Create returns ErrConflict if a template with the same name exists,
+	// ignoring case.
+	Create(ctx context.Context, tpl Template) error
+	GetByID(ctx context.Context, id string) (Template, error)
+	// List returns templates, most recently published first.
+	List(ctx context.Context) ([]Template, error)
+}
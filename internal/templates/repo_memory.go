@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryRepo stores templates in memory and is safe for concurrent use.
+type MemoryRepo struct {
+	mu   sync.RWMutex
+	byID map[string]Template
+}
+
+// NewMemoryRepo constructs a MemoryRepo.
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{byID: make(map[string]Template)}
+}
+
+var _ Repo = (*MemoryRepo)(nil)
+
+// Create stores a new template.
+func (r *MemoryRepo) Create(ctx context.Context, tpl Template) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.byID {
+		if strings.EqualFold(existing.Name, tpl.Name) {
+			return ErrConflict
+		}
+	}
+	r.byID[tpl.ID] = tpl
+	return nil
+}
+
+// GetByID returns a template.
+func (r *MemoryRepo) GetByID(ctx context.Context, id string) (Template, error) {
+	if err := ctx.Err(); err != nil {
+		return Template{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tpl, ok := r.byID[id]
+	if !ok {
+		return Template{}, ErrNotFound
+	}
+	return tpl, nil
+}
+
+// List returns templates, most recently published first.
+func (r *MemoryRepo) List(ctx context.Context) ([]Template, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Template, 0, len(r.byID))
+	for _, tpl := range r.byID {
+		out = append(out, tpl)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PublishedAt.After(out[j].PublishedAt) })
+	return out, nil
+}
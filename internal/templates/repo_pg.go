@@ -0,0 +1,116 @@
+package templates
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"resume-backend/resume/render"
+)
+
+// PGRepo implements Repo using Postgres.
+type PGRepo struct {
+	DB *sql.DB
+}
+
+var _ Repo = (*PGRepo)(nil)
+
+const templateColumns = `id, name, description, file_name, storage_key, size_bytes, sha256, validation, uploaded_by, published_at, max_highlights_per_role`
+
+// Create inserts a template.
+func (r *PGRepo) Create(ctx context.Context, tpl Template) error {
+	validation, err := json.Marshal(tpl.Validation)
+	if err != nil {
+		return err
+	}
+	const query = `
+INSERT INTO resume_templates (` + templateColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT DO NOTHING`
+	res, err := r.DB.ExecContext(ctx, query,
+		tpl.ID,
+		tpl.Name,
+		tpl.Description,
+		tpl.FileName,
+		tpl.StorageKey,
+		tpl.SizeBytes,
+		tpl.SHA256,
+		validation,
+		tpl.UploadedBy,
+		tpl.PublishedAt,
+		tpl.Capabilities.MaxHighlightsPerRole,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// GetByID returns a template.
+func (r *PGRepo) GetByID(ctx context.Context, id string) (Template, error) {
+	const query = `SELECT ` + templateColumns + ` FROM resume_templates WHERE id = $1`
+	tpl, err := scanTemplate(r.DB.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Template{}, ErrNotFound
+	}
+	return tpl, err
+}
+
+// List returns templates, most recently published first.
+func (r *PGRepo) List(ctx context.Context) ([]Template, error) {
+	const query = `SELECT ` + templateColumns + ` FROM resume_templates ORDER BY published_at DESC`
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]Template, 0)
+	for rows.Next() {
+		tpl, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, tpl)
+	}
+	return out, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTemplate(row rowScanner) (Template, error) {
+	var tpl Template
+	var validation []byte
+	var maxHighlights int
+	if err := row.Scan(
+		&tpl.ID,
+		&tpl.Name,
+		&tpl.Description,
+		&tpl.FileName,
+		&tpl.StorageKey,
+		&tpl.SizeBytes,
+		&tpl.SHA256,
+		&validation,
+		&tpl.UploadedBy,
+		&tpl.PublishedAt,
+		&maxHighlights,
+	); err != nil {
+		return Template{}, err
+	}
+	if len(validation) > 0 {
+		if err := json.Unmarshal(validation, &tpl.Validation); err != nil {
+			return Template{}, err
+		}
+	}
+	tpl.Capabilities = render.CapabilitiesFromTokens(tpl.Validation.Tokens, maxHighlights)
+	return tpl, nil
+}
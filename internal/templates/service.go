@@ -0,0 +1,141 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"resume-backend/internal/audit"
+	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/telemetry"
+	"resume-backend/resume/render"
+)
+
+const (
+	maxNameLength        = 100
+	maxDescriptionLength = 500
+	maxHighlightsPerRole = 20
+	// storageOwner is the object store namespace for published templates.
+	storageOwner = "templates"
+)
+
+// Service validates and publishes DOCX templates.
+type Service struct {
+	Repo  Repo
+	Store object.ObjectStore
+	// Audit records publications; nil skips auditing.
+	Audit *audit.Service
+	Now   func() time.Time
+}
+
+// NewService constructs a Service.
+func NewService(repo Repo, store object.ObjectStore, auditSvc *audit.Service) *Service {
+	return &Service{Repo: repo, Store: store, Audit: auditSvc}
+}
+
+// Upload runs a template through the validation sandbox and publishes it if every
+// check passes. A rejected template is not stored; its report is returned with
+// ErrValidationFailed.
+func (s *Service) Upload(ctx context.Context, userID string, in UploadInput) (Template, render.TemplateReport, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+	if in.Name == "" || len(in.Name) > maxNameLength {
+		return Template{}, render.TemplateReport{}, fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidInput, maxNameLength)
+	}
+	if len(in.Description) > maxDescriptionLength {
+		return Template{}, render.TemplateReport{}, fmt.Errorf("%w: description must be at most %d characters", ErrInvalidInput, maxDescriptionLength)
+	}
+	if in.MaxHighlightsPerRole < 0 || in.MaxHighlightsPerRole > maxHighlightsPerRole {
+		return Template{}, render.TemplateReport{}, fmt.Errorf("%w: maxHighlightsPerRole must be 0-%d", ErrInvalidInput, maxHighlightsPerRole)
+	}
+	if !strings.EqualFold(filepath.Ext(in.FileName), ".docx") {
+		return Template{}, render.TemplateReport{}, fmt.Errorf("%w: file must be a .docx", ErrInvalidInput)
+	}
+
+	report := render.ValidateTemplate(in.Content)
+	if !report.Passed() {
+		telemetry.InfoContext(ctx, "templates.rejected", map[string]any{
+			"name":        in.Name,
+			"uploaded_by": userID,
+			"checks":      failedChecks(report),
+		})
+		return Template{}, report, ErrValidationFailed
+	}
+
+	sum := sha256.Sum256(in.Content)
+	key, size, _, err := s.Store.Save(ctx, storageOwner, in.FileName, bytes.NewReader(in.Content))
+	if err != nil {
+		return Template{}, report, err
+	}
+	tpl := Template{
+		ID:           uuid.NewString(),
+		Name:         in.Name,
+		Description:  in.Description,
+		FileName:     in.FileName,
+		StorageKey:   key,
+		SizeBytes:    size,
+		SHA256:       hex.EncodeToString(sum[:]),
+		Validation:   report,
+		Capabilities: render.CapabilitiesFromTokens(report.Tokens, in.MaxHighlightsPerRole),
+		UploadedBy:   userID,
+		PublishedAt:  s.now(),
+	}
+	if err := s.Repo.Create(ctx, tpl); err != nil {
+		return Template{}, report, err
+	}
+	if s.Audit != nil {
+		_ = s.Audit.Record(ctx, audit.Entry{
+			Action:      "templates.publish",
+			ActorUserID: userID,
+			Details: map[string]any{
+				"templateId": tpl.ID,
+				"name":       tpl.Name,
+				"sha256":     tpl.SHA256,
+			},
+		})
+	}
+	return tpl, report, nil
+}
+
+// Builtin describes the built-in template.
+func (s *Service) Builtin() (Builtin, error) {
+	caps, err := render.DefaultCapabilities()
+	if err != nil {
+		return Builtin{}, err
+	}
+	return Builtin{ID: render.DefaultTemplateID, Capabilities: caps}, nil
+}
+
+// List returns the published templates.
+func (s *Service) List(ctx context.Context) ([]Template, error) {
+	return s.Repo.List(ctx)
+}
+
+// Get returns a published template.
+func (s *Service) Get(ctx context.Context, id string) (Template, error) {
+	return s.Repo.GetByID(ctx, id)
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func failedChecks(report render.TemplateReport) []string {
+	var out []string
+	for _, check := range report.Checks {
+		if !check.Passed {
+			out = append(out, check.Name)
+		}
+	}
+	return out
+}
@@ -29,8 +29,9 @@ const (
 )
 
 var allowedContentTypes = map[string]struct{}{
-	"application/pdf":    {},
-	"application/msword": {},
+	"application/pdf":             {},
+	"application/msword":          {},
+	"application/vnd.apple.pages": {},
 	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": {},
 }
 
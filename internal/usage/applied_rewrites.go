@@ -0,0 +1,41 @@
+package usage
+
+import (
+	"context"
+	"time"
+)
+
+// maxAppliedRewrites caps how many applied rewrites are read for one document.
+const maxAppliedRewrites = 500
+
+// AppliedRewrite is a bullet rewrite an executed apply run wrote into a
+// document. Later analyses of the document use it to recognise suggestions the
+// user has already taken.
+type AppliedRewrite struct {
+	ID         string
+	DocumentID string
+	UserID     string
+	ApplyRunID string
+	Section    string
+	Before     string
+	After      string
+	CreatedAt  time.Time
+}
+
+// RecordAppliedRewrites stores rewrites from an apply run. A rewrite already
+// recorded for the document is kept as first recorded.
+func (s *Service) RecordAppliedRewrites(ctx context.Context, rewrites []AppliedRewrite) error {
+	if len(rewrites) == 0 {
+		return nil
+	}
+	return s.store.RecordAppliedRewrites(ctx, rewrites)
+}
+
+// ListAppliedRewrites returns the rewrites applied to a user's document, oldest
+// first.
+func (s *Service) ListAppliedRewrites(ctx context.Context, userID, documentID string) ([]AppliedRewrite, error) {
+	if userID == "" || documentID == "" {
+		return nil, nil
+	}
+	return s.store.ListAppliedRewrites(ctx, userID, documentID, maxAppliedRewrites)
+}
@@ -1,6 +1,11 @@
 package usage
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"resume-backend/resume/model"
+)
 
 const (
 	ApplyRunStatusPlanned = "PLANNED"
@@ -48,3 +53,24 @@ type DocumentVersion struct {
 	StorageKey string
 	CreatedAt  time.Time
 }
+
+// ApplyCompletion describes an apply run that produced a document, for an
+// ApplyNotifier.
+type ApplyCompletion struct {
+	// OrgID is the organization named by the request's X-Org-Id header.
+	OrgID             string
+	UserID            string
+	ApplyRunID        string
+	AnalysisID        string
+	DocumentVersionID string
+	Status            string
+	FileName          string
+	Docx              []byte
+	Header            model.ResumeHeader
+}
+
+// ApplyNotifier is told about apply runs that produced a document. It must not
+// block the request.
+type ApplyNotifier interface {
+	ApplyRunCompleted(ctx context.Context, completion ApplyCompletion)
+}
@@ -2,6 +2,9 @@ package usage
 
 import "time"
 
+// usagePeriod is the length of a usage window for users and organizations.
+const usagePeriod = 7 * 24 * time.Hour
+
 func defaultUsage() Usage {
 	return Usage{
 		Plan:     "Starter",
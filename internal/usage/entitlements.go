@@ -0,0 +1,42 @@
+package usage
+
+import (
+	"context"
+	"strings"
+)
+
+// FeatureLearningPlan is the skill gap learning plan on job-match analyses.
+const FeatureLearningPlan = "learning_plan"
+
+// featurePlans lists the plans that include each gated feature.
+var featurePlans = map[string][]string{
+	FeatureLearningPlan: {"Pro", "Team", "Enterprise"},
+}
+
+// HasFeature reports whether the user's plan includes feature. With a non-empty
+// orgID the organization's plan is checked instead, and the user must be a member.
+func (s *Service) HasFeature(ctx context.Context, userID, orgID, feature string) (bool, error) {
+	var plan string
+	if orgID != "" {
+		quota, err := s.store.GetOrgQuota(ctx, orgID)
+		if err != nil {
+			return false, err
+		}
+		if _, err := s.store.GetOrgMember(ctx, orgID, userID); err != nil {
+			return false, err
+		}
+		plan = quota.Plan
+	} else {
+		u, err := s.store.Get(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		plan = u.Plan
+	}
+	for _, allowed := range featurePlans[feature] {
+		if strings.EqualFold(plan, allowed) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -10,3 +10,15 @@ var ErrApplyRunNotFound = errors.New("apply run not found")
 
 // ErrAnalysisNotFound indicates an analysis record was not found.
 var ErrAnalysisNotFound = errors.New("analysis not found")
+
+// ErrOrgNotFound indicates the organization has no usage quota configured.
+var ErrOrgNotFound = errors.New("organization not found")
+
+// ErrNotOrgMember indicates the user does not belong to the organization.
+var ErrNotOrgMember = errors.New("not an organization member")
+
+// ErrInvalidQuota indicates an organization quota with negative or inconsistent limits.
+var ErrInvalidQuota = errors.New("invalid organization quota")
+
+// ErrFeatureNotEntitled indicates the plan does not include a requested feature.
+var ErrFeatureNotEntitled = errors.New("feature not included in plan")
@@ -0,0 +1,86 @@
+package usage
+
+import (
+	"context"
+	"time"
+)
+
+// minForecastWindow keeps a burst at the very start of a period from projecting an
+// absurd daily rate.
+const minForecastWindow = 24 * time.Hour
+
+// Forecast projects when a user will exhaust their limit at the current pace.
+type Forecast struct {
+	Plan      string    `json:"plan"`
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resetsAt"`
+	// DailyRate is the average consumption per day so far this period.
+	DailyRate float64 `json:"dailyRate"`
+	// ProjectedLimitAt is when the limit is reached at DailyRate; nil when there is no usage yet.
+	ProjectedLimitAt *time.Time `json:"projectedLimitAt,omitempty"`
+	// LimitBeforeReset reports whether the limit is projected to run out before the period resets.
+	LimitBeforeReset bool   `json:"limitBeforeReset"`
+	SoftLimitPercent int    `json:"softLimitPercent,omitempty"`
+	SoftLimitWarning bool   `json:"softLimitWarning"`
+	UpgradeSuggested bool   `json:"upgradeSuggested"`
+	Message          string `json:"message,omitempty"`
+}
+
+// Forecast returns the user's usage forecast for the current period.
+func (s *Service) Forecast(ctx context.Context, userID string) (Forecast, error) {
+	u, err := s.store.EnsurePeriod(ctx, userID)
+	if err != nil {
+		return Forecast{}, err
+	}
+	return s.forecast(u, time.Now().UTC()), nil
+}
+
+// SoftLimitWarning reports whether u has crossed the configured soft limit percentage.
+func (s *Service) SoftLimitWarning(u Usage) bool {
+	if s.SoftLimitPercent <= 0 || u.Limit <= 0 {
+		return false
+	}
+	return u.Used*100 >= u.Limit*s.SoftLimitPercent
+}
+
+func (s *Service) forecast(u Usage, now time.Time) Forecast {
+	f := Forecast{
+		Plan:             u.Plan,
+		Limit:            u.Limit,
+		Used:             u.Used,
+		Remaining:        u.Limit - u.Used,
+		ResetsAt:         u.ResetsAt,
+		SoftLimitPercent: s.SoftLimitPercent,
+		SoftLimitWarning: s.SoftLimitWarning(u),
+	}
+	if f.Remaining < 0 {
+		f.Remaining = 0
+	}
+
+	elapsed := now.Sub(u.ResetsAt.Add(-usagePeriod))
+	if elapsed < minForecastWindow {
+		elapsed = minForecastWindow
+	}
+	if u.Used > 0 {
+		f.DailyRate = float64(u.Used) / elapsed.Hours() * 24
+		projected := now
+		if f.Remaining > 0 {
+			projected = now.Add(time.Duration(float64(f.Remaining) / f.DailyRate * float64(24*time.Hour)))
+		}
+		f.ProjectedLimitAt = &projected
+		f.LimitBeforeReset = projected.Before(u.ResetsAt)
+	}
+
+	f.UpgradeSuggested = f.LimitBeforeReset || f.SoftLimitWarning
+	switch {
+	case f.Remaining == 0:
+		f.Message = "You've used all analyses in your plan for this period. Upgrade to keep going."
+	case f.LimitBeforeReset:
+		f.Message = "At your current pace you'll run out of analyses before your plan resets. Upgrade to avoid interruptions."
+	case f.SoftLimitWarning:
+		f.Message = "You're close to your plan's analysis limit."
+	}
+	return f
+}
@@ -0,0 +1,64 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForecastProjectsLimitFromPeriodRate(t *testing.T) {
+	resetsAt := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	now := resetsAt.Add(-usagePeriod).Add(2 * 24 * time.Hour)
+	svc := &Service{SoftLimitPercent: 80}
+
+	f := svc.forecast(Usage{Plan: "Starter", Limit: 10, Used: 4, ResetsAt: resetsAt}, now)
+	if f.DailyRate != 2 {
+		t.Fatalf("expected 2/day, got %v", f.DailyRate)
+	}
+	want := now.Add(3 * 24 * time.Hour)
+	if f.ProjectedLimitAt == nil || !f.ProjectedLimitAt.Equal(want) {
+		t.Fatalf("expected projection %v, got %v", want, f.ProjectedLimitAt)
+	}
+	if !f.LimitBeforeReset || !f.UpgradeSuggested || f.SoftLimitWarning {
+		t.Fatalf("unexpected flags: %+v", f)
+	}
+	if f.Message == "" {
+		t.Fatalf("expected an upgrade message")
+	}
+}
+
+func TestForecastWithoutUsageOrWithSlowPace(t *testing.T) {
+	resetsAt := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	now := resetsAt.Add(-24 * time.Hour)
+	svc := &Service{SoftLimitPercent: 80}
+
+	idle := svc.forecast(Usage{Limit: 10, ResetsAt: resetsAt}, now)
+	if idle.ProjectedLimitAt != nil || idle.UpgradeSuggested || idle.Message != "" {
+		t.Fatalf("expected no projection without usage: %+v", idle)
+	}
+
+	// One unit over six days will not reach the limit before the reset.
+	slow := svc.forecast(Usage{Limit: 10, Used: 1, ResetsAt: resetsAt}, now)
+	if slow.ProjectedLimitAt == nil || slow.LimitBeforeReset || slow.UpgradeSuggested {
+		t.Fatalf("expected slow pace to stay within the period: %+v", slow)
+	}
+
+	// The first-day burst is averaged over at least a day.
+	burst := svc.forecast(Usage{Limit: 10, Used: 3, ResetsAt: resetsAt}, resetsAt.Add(-usagePeriod).Add(time.Hour))
+	if burst.DailyRate != 3 {
+		t.Fatalf("expected burst rate clamped to 3/day, got %v", burst.DailyRate)
+	}
+}
+
+func TestSoftLimitWarning(t *testing.T) {
+	svc := &Service{SoftLimitPercent: 80}
+	if svc.SoftLimitWarning(Usage{Limit: 10, Used: 7}) {
+		t.Fatalf("70%% should not warn")
+	}
+	if !svc.SoftLimitWarning(Usage{Limit: 10, Used: 8}) {
+		t.Fatalf("80%% should warn")
+	}
+	svc.SoftLimitPercent = 0
+	if svc.SoftLimitWarning(Usage{Limit: 10, Used: 10}) {
+		t.Fatalf("disabled soft limit should not warn")
+	}
+}
@@ -7,17 +7,23 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"resume-backend/internal/documents"
+	"resume-backend/internal/extract"
+	"resume-backend/internal/featureflags"
 	"resume-backend/internal/generatedresumes"
 	"resume-backend/internal/shared/server/middleware"
 	"resume-backend/internal/shared/server/respond"
 	"resume-backend/internal/shared/storage/object"
+	"resume-backend/internal/shared/telemetry"
 	"resume-backend/resume/contract"
+	"resume-backend/resume/model"
+	"resume-backend/resume/render"
 	resumeservice "resume-backend/resume/service"
 )
 
@@ -28,6 +34,14 @@ type Handler struct {
 	DocRepo      documents.DocumentsRepo
 	Store        object.ObjectStore
 	Generated    *generatedresumes.Service
+	// LLM rebuilds the resume model for preflight and execute; nil parses it
+	// without the LLM.
+	LLM resumeservice.LLMClient
+	// Notifier is told about executed apply runs sent with X-Org-Id; nil disables it.
+	Notifier ApplyNotifier
+	// Flags gates the redline renderer per user and organization; nil leaves
+	// it at its default.
+	Flags *featureflags.Service
 }
 
 // NewHandler constructs a Handler.
@@ -44,13 +58,19 @@ func NewHandler(svc *Service, analysisRepo AnalysisRepo, docRepo documents.Docum
 // RegisterRoutes attaches usage routes to the router group.
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/usage", h.getUsage)
+	rg.GET("/usage/forecast", h.getForecast)
+	rg.GET("/usage/orgs/:orgId", h.getOrgUsage)
 	rg.POST("/analyses/:id/apply/plan", h.applyPlan)
+	rg.POST("/apply-runs/:id/preflight", h.preflightApply)
 	rg.POST("/apply-runs/:id/execute", h.executeApply)
+	rg.GET("/apply-runs/:id/diff", h.applyDiff)
 }
 
 // RegisterDevRoutes attaches dev-only usage routes.
 func (h *Handler) RegisterDevRoutes(rg *gin.RouterGroup) {
 	rg.POST("/usage/reset", h.resetUsage)
+	rg.PUT("/usage/orgs/:orgId", h.setOrgQuota)
+	rg.POST("/usage/orgs/:orgId/members", h.addOrgMember)
 }
 
 func (h *Handler) getUsage(c *gin.Context) {
@@ -74,6 +94,21 @@ func (h *Handler) getUsage(c *gin.Context) {
 	})
 }
 
+func (h *Handler) getForecast(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	f, err := h.Svc.Forecast(c.Request.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			respond.Error(c, http.StatusRequestTimeout, "timeout", "request canceled", nil)
+		default:
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to forecast usage", nil)
+		}
+		return
+	}
+	respond.JSON(c, http.StatusOK, f)
+}
+
 func (h *Handler) resetUsage(c *gin.Context) {
 	userID := middleware.UserIDFromContext(c)
 	u, err := h.Svc.Reset(c.Request.Context(), userID)
@@ -95,22 +130,107 @@ func (h *Handler) resetUsage(c *gin.Context) {
 	})
 }
 
+func (h *Handler) getOrgUsage(c *gin.Context) {
+	if middleware.IsGuest(c) {
+		respond.Error(c, http.StatusUnauthorized, "login_required", "Login required to view organization usage", nil)
+		return
+	}
+	userID := middleware.UserIDFromContext(c)
+	summary, err := h.Svc.OrgSummary(c.Request.Context(), c.Param("orgId"), userID)
+	if err != nil {
+		writeOrgError(c, err, "failed to fetch organization usage")
+		return
+	}
+	respond.JSON(c, http.StatusOK, summary)
+}
+
+type setOrgQuotaRequest struct {
+	Plan      string `json:"plan"`
+	Limit     int    `json:"limit"`
+	SeatLimit int    `json:"seatLimit"`
+}
+
+func (h *Handler) setOrgQuota(c *gin.Context) {
+	var req setOrgQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	quota, err := h.Svc.SetOrgQuota(c.Request.Context(), c.Param("orgId"), req.Plan, req.Limit, req.SeatLimit)
+	if err != nil {
+		writeOrgError(c, err, "failed to set organization quota")
+		return
+	}
+	respond.JSON(c, http.StatusOK, quota)
+}
+
+type addOrgMemberRequest struct {
+	UserID string `json:"userId"`
+}
+
+func (h *Handler) addOrgMember(c *gin.Context) {
+	var req addOrgMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid request body", nil)
+		return
+	}
+	member, err := h.Svc.AddOrgMember(c.Request.Context(), c.Param("orgId"), req.UserID)
+	if err != nil {
+		writeOrgError(c, err, "failed to add organization member")
+		return
+	}
+	respond.JSON(c, http.StatusCreated, member)
+}
+
+// writeOrgError hides whether an organization exists from non-members.
+func writeOrgError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidQuota):
+		respond.Error(c, http.StatusBadRequest, "validation_error", err.Error(), nil)
+	case errors.Is(err, ErrOrgNotFound), errors.Is(err, ErrNotOrgMember):
+		respond.Error(c, http.StatusNotFound, "not_found", "organization not found", nil)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		respond.Error(c, http.StatusRequestTimeout, "timeout", "request canceled", nil)
+	default:
+		respond.Error(c, http.StatusInternalServerError, "internal_error", message, nil)
+	}
+}
+
 type applyExecuteRequest struct {
 	Header applyHeaderInput `json:"header"`
 	Strict bool             `json:"strict"`
+	// DryRun returns the changes the run would make without storing anything.
+	DryRun bool `json:"dryRun"`
+	// Redline also stores a copy of the document with the changes as Word
+	// tracked changes.
+	Redline bool `json:"redline"`
+	// Locale selects the language of the section headings, such as "es" or
+	// "pt-BR"; empty renders them in English.
+	Locale string `json:"locale"`
 }
 
 type applyHeaderInput struct {
-	Name     string   `json:"name"`
-	Title    string   `json:"title"`
-	Email    string   `json:"email"`
-	Phone    string   `json:"phone"`
-	Location string   `json:"location"`
-	Links    []string `json:"links"`
+	Name     string `json:"name"`
+	Title    string `json:"title"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Location string `json:"location"`
+	// Links accepts plain URL strings as well as {"label","url"} objects.
+	Links []model.ResumeLink `json:"links"`
 }
 
 const analysisStatusCompleted = "completed"
 
+// flagTarget is the user and, when they belong to it, the organization named
+// by X-Org-Id.
+func (h *Handler) flagTarget(c *gin.Context, userID string) featureflags.Target {
+	target := featureflags.Target{UserID: userID}
+	if orgID := strings.TrimSpace(c.GetHeader("X-Org-Id")); orgID != "" && h.Svc.CheckOrgMember(c.Request.Context(), orgID, userID) == nil {
+		target.OrgID = orgID
+	}
+	return target
+}
+
 func (h *Handler) applyPlan(c *gin.Context) {
 	userID := middleware.UserIDFromContext(c)
 	analysisID := c.Param("id")
@@ -183,84 +303,67 @@ func (h *Handler) executeApply(c *gin.Context) {
 		return
 	}
 
-	run, err := h.Svc.GetApplyRun(c.Request.Context(), userID, applyRunID)
-	if err != nil {
-		switch {
-		case errors.Is(err, ErrApplyRunNotFound):
-			respond.Error(c, http.StatusNotFound, "not_found", "apply run not found", nil)
-		default:
-			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to fetch apply run", nil)
-		}
-		return
-	}
-
-	analysis, err := h.AnalysisRepo.GetByID(c.Request.Context(), run.AnalysisID)
-	if err != nil {
-		switch {
-		case errors.Is(err, ErrAnalysisNotFound):
-			respond.Error(c, http.StatusNotFound, "not_found", "analysis not found", nil)
-		default:
-			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to fetch analysis", nil)
-		}
-		return
+	locale := req.Locale
+	if locale == "" {
+		locale = c.Query("locale")
 	}
-	if analysis.UserID != userID {
-		respond.Error(c, http.StatusNotFound, "not_found", "analysis not found", nil)
-		return
-	}
-	if analysis.Result == nil {
-		respond.Error(c, http.StatusConflict, "analysis_pending", "analysis not complete", nil)
-		return
-	}
-
-	result, err := decodeAnalysisResult(analysis.Result)
+	locale, err := render.NormalizeLocale(locale)
 	if err != nil {
-		respond.Error(c, http.StatusBadRequest, "invalid_analysis", "analysis result is not compatible", nil)
+		respond.Error(c, http.StatusBadRequest, "validation_error", "unsupported locale", gin.H{"supported": render.SupportedLocales()})
 		return
 	}
 
-	doc, err := h.DocRepo.GetByID(c.Request.Context(), userID, analysis.DocumentID)
-	if err != nil {
-		switch {
-		case errors.Is(err, documents.ErrNotFound):
-			respond.Error(c, http.StatusNotFound, "not_found", "document not found", nil)
-		default:
-			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load document", nil)
-		}
+	run, result, doc, source, ok := h.loadApplySource(c, userID, applyRunID)
+	if !ok {
 		return
 	}
 
-	reader, err := h.Store.Open(c.Request.Context(), doc.StorageKey)
-	if err != nil {
-		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to open document", nil)
+	dryRun := req.DryRun || strings.EqualFold(c.Query("dryRun"), "true")
+	redline := req.Redline || strings.EqualFold(c.Query("redline"), "true")
+	if redline && !dryRun && !h.Flags.Enabled(c.Request.Context(), featureflags.RedlineRenderer, h.flagTarget(c, userID)) {
+		respond.Error(c, http.StatusForbidden, "feature_disabled", "redline output is not available for this account", nil)
 		return
 	}
-	defer reader.Close()
-
-	raw, err := io.ReadAll(reader)
-	if err != nil {
-		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to read document", nil)
-		return
+	renderOpts := render.RenderOptions{Locale: locale}
+	var execResult resumeservice.ApplyExecutionResult
+	switch {
+	case dryRun:
+		execResult, err = resumeservice.PreviewApply(c.Request.Context(), h.LLM, source.Text, result, req.Header.inputs(), req.Strict)
+	case redline:
+		execResult, err = resumeservice.ExecuteApplyRedlineWithOptions(c.Request.Context(), h.LLM, source.Text, result, req.Header.inputs(), req.Strict, renderOpts)
+	default:
+		execResult, err = resumeservice.ExecuteApplyWithOptions(c.Request.Context(), h.LLM, source.Text, result, req.Header.inputs(), req.Strict, renderOpts)
 	}
-
-	execResult, err := resumeservice.ExecuteApply(c.Request.Context(), string(raw), result, resumeservice.ApplyHeaderInputs{
-		Name:     req.Header.Name,
-		Title:    req.Header.Title,
-		Email:    req.Header.Email,
-		Phone:    req.Header.Phone,
-		Location: req.Header.Location,
-		Links:    req.Header.Links,
-	}, req.Strict)
 	if err != nil {
 		var missing contract.MissingFieldsError
 		if errors.As(err, &missing) {
 			respond.Error(c, http.StatusBadRequest, "missing_required_fields", "missing required fields", missing.Fields)
 			return
 		}
+		var needsInput resumeservice.MissingInputError
+		if errors.As(err, &needsInput) {
+			respond.Error(c, http.StatusUnprocessableEntity, "needs_input", "contact details are required before the resume can be generated", needsInput.NeedsInput)
+			return
+		}
 		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to execute apply flow", nil)
 		return
 	}
 
+	if dryRun {
+		respond.JSON(c, http.StatusOK, gin.H{
+			"applyRunId":            run.ID,
+			"dryRun":                true,
+			"status":                execResult.Status,
+			"placeholdersRemaining": execResult.PlaceholdersRemaining,
+			"autoFixesApplied":      execResult.AutoFixesApplied,
+			"safeRewritesApplied":   execResult.SafeRewritesApplied,
+			"changes":               execResult.Changes,
+			"plan":                  execResult.Plan,
+			"source":                source,
+		})
+		return
+	}
+
 	fileName := "resume_applied.docx"
 	storageKey, size, mimeType, err := h.Store.Save(c.Request.Context(), userID, fileName, bytes.NewReader(execResult.DocxBytes))
 	if err != nil {
@@ -279,9 +382,31 @@ func (h *Handler) executeApply(c *gin.Context) {
 		StorageKey: storageKey,
 		CreatedAt:  time.Now().UTC(),
 	}
-	if err := h.Svc.CreateDocumentVersion(c.Request.Context(), version); err != nil {
-		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to persist document version", nil)
-		return
+	versions := []DocumentVersion{version}
+
+	// The redline copy is stored as a second version of the same run; the
+	// clean document stays the run's result.
+	var redlineVersionID string
+	if execResult.RedlineDocxBytes != nil {
+		redlineName := "resume_applied_redline.docx"
+		key, size, mimeType, err := h.Store.Save(c.Request.Context(), userID, redlineName, bytes.NewReader(execResult.RedlineDocxBytes))
+		if err != nil {
+			respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to store document", nil)
+			return
+		}
+		redlineVersion := DocumentVersion{
+			ID:         uuid.NewString(),
+			DocumentID: doc.ID,
+			UserID:     userID,
+			ApplyRunID: run.ID,
+			FileName:   redlineName,
+			MimeType:   mimeType,
+			SizeBytes:  size,
+			StorageKey: key,
+			CreatedAt:  time.Now().UTC(),
+		}
+		versions = append(versions, redlineVersion)
+		redlineVersionID = redlineVersion.ID
 	}
 
 	update := ApplyRunUpdate{
@@ -295,21 +420,243 @@ func (h *Handler) executeApply(c *gin.Context) {
 		PlaceholdersRemaining: execResult.PlaceholdersRemaining,
 		DocumentVersionID:     version.ID,
 	}
-	if err := h.Svc.UpdateApplyRun(c.Request.Context(), update); err != nil {
-		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to update apply run", nil)
+	if err := h.Svc.CompleteApplyRun(c.Request.Context(), versions, update); err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to persist apply run", nil)
 		return
 	}
+	h.recordAppliedRewrites(c.Request.Context(), run, doc.ID, execResult.Changes)
+
+	if orgID := strings.TrimSpace(c.GetHeader("X-Org-Id")); orgID != "" && h.Notifier != nil {
+		h.Notifier.ApplyRunCompleted(c.Request.Context(), ApplyCompletion{
+			OrgID:             orgID,
+			UserID:            userID,
+			ApplyRunID:        run.ID,
+			AnalysisID:        run.AnalysisID,
+			DocumentVersionID: version.ID,
+			Status:            execResult.Status,
+			FileName:          fileName,
+			Docx:              execResult.DocxBytes,
+			Header:            execResult.Header,
+		})
+	}
 
-	respond.JSON(c, http.StatusOK, gin.H{
+	response := gin.H{
 		"applyRunId":            run.ID,
 		"documentVersionId":     version.ID,
 		"status":                execResult.Status,
 		"placeholdersRemaining": execResult.PlaceholdersRemaining,
 		"autoFixesApplied":      execResult.AutoFixesApplied,
 		"safeRewritesApplied":   execResult.SafeRewritesApplied,
+		"source":                source,
+	}
+	if redlineVersionID != "" {
+		response["redlineDocumentVersionId"] = redlineVersionID
+	}
+	if len(execResult.RenderWarnings) > 0 {
+		response["renderWarnings"] = execResult.RenderWarnings
+	}
+	h.Svc.WriteRemainingHeader(c.Request.Context(), c, userID, strings.TrimSpace(c.GetHeader("X-Org-Id")))
+	respond.JSON(c, http.StatusOK, response)
+}
+
+// recordAppliedRewrites remembers the bullet rewrites written into the
+// document, so later analyses of it can mark them as already applied. Failing
+// to record them does not fail the apply run.
+func (h *Handler) recordAppliedRewrites(ctx context.Context, run ApplyRun, documentID string, changes []resumeservice.ApplyChange) {
+	now := time.Now().UTC()
+	var rewrites []AppliedRewrite
+	for _, change := range changes {
+		if change.Kind != resumeservice.ChangeSafeRewrite || strings.TrimSpace(change.Before) == "" {
+			continue
+		}
+		rewrites = append(rewrites, AppliedRewrite{
+			ID:         uuid.NewString(),
+			DocumentID: documentID,
+			UserID:     run.UserID,
+			ApplyRunID: run.ID,
+			Section:    change.Section,
+			Before:     change.Before,
+			After:      change.After,
+			CreatedAt:  now,
+		})
+	}
+	if err := h.Svc.RecordAppliedRewrites(ctx, rewrites); err != nil {
+		telemetry.ErrorContext(ctx, "apply.applied_rewrites_failed", map[string]any{
+			"apply_run_id": run.ID,
+			"document_id":  documentID,
+			"error":        err.Error(),
+		})
+	}
+}
+
+type applyPreflightRequest struct {
+	Header applyHeaderInput `json:"header"`
+}
+
+// preflightApply reports which contact details execute would ask for, without
+// running the apply steps.
+func (h *Handler) preflightApply(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	applyRunID := c.Param("id")
+	if applyRunID == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "apply run id is required", nil)
+		return
+	}
+
+	var req applyPreflightRequest
+	if err := decodeOptionalJSON(c.Request.Body, &req); err != nil {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "invalid json body", nil)
+		return
+	}
+
+	run, _, _, source, ok := h.loadApplySource(c, userID, applyRunID)
+	if !ok {
+		return
+	}
+
+	preflight, err := resumeservice.PreflightApply(c.Request.Context(), h.LLM, source.Text, req.Header.inputs())
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to check apply inputs", nil)
+		return
+	}
+
+	respond.JSON(c, http.StatusOK, gin.H{
+		"applyRunId": run.ID,
+		"ready":      preflight.Ready,
+		"needsInput": preflight.NeedsInput,
+		"header":     preflight.Header,
+		"source":     source,
+	})
+}
+
+// applyDiff compares the resume text of an executed apply run's document with
+// the original document's, so the review screen can show what changed.
+func (h *Handler) applyDiff(c *gin.Context) {
+	userID := middleware.UserIDFromContext(c)
+	applyRunID := c.Param("id")
+	if applyRunID == "" {
+		respond.Error(c, http.StatusBadRequest, "validation_error", "apply run id is required", nil)
+		return
+	}
+
+	run, _, doc, source, ok := h.loadApplySource(c, userID, applyRunID)
+	if !ok {
+		return
+	}
+	if run.DocumentVersionID == "" {
+		respond.Error(c, http.StatusConflict, "conflict", "apply run has not generated a document yet", nil)
+		return
+	}
+
+	versions, err := h.Svc.ListDocumentVersions(c.Request.Context(), userID, doc.ID)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to load document versions", nil)
+		return
+	}
+	var version DocumentVersion
+	for _, v := range versions {
+		if v.ID == run.DocumentVersionID {
+			version = v
+			break
+		}
+	}
+	if version.ID == "" {
+		respond.Error(c, http.StatusNotFound, "not_found", "generated document not found", nil)
+		return
+	}
+
+	rc, err := h.Store.Open(c.Request.Context(), version.StorageKey)
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to read generated document", nil)
+		return
+	}
+	raw, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		respond.Error(c, http.StatusInternalServerError, "internal_error", "failed to read generated document", nil)
+		return
+	}
+	generated, err := extract.ExtractTextFromBytes(c.Request.Context(), raw, version.MimeType, version.FileName)
+	if err != nil || strings.TrimSpace(generated) == "" {
+		respond.Error(c, http.StatusUnprocessableEntity, "unreadable_document", "resume text could not be read from the generated document", nil)
+		return
+	}
+
+	diff := resumeservice.DiffResumeText(source.Text, generated)
+	respond.JSON(c, http.StatusOK, gin.H{
+		"applyRunId":        run.ID,
+		"documentVersionId": version.ID,
+		"summary":           diff.Summary,
+		"sections":          diff.Sections,
+		"source":            source,
 	})
 }
 
+// loadApplySource loads an apply run with its analysis result and the resume
+// text read from the original document, as its format allows. It writes the error response and returns false when any of them
+// cannot be used.
+func (h *Handler) loadApplySource(c *gin.Context, userID, applyRunID string) (ApplyRun, resumeservice.AnalysisResultV2_3, documents.Document, documents.ResumeSource, bool) {
+	fail := func(status int, code, message string) (ApplyRun, resumeservice.AnalysisResultV2_3, documents.Document, documents.ResumeSource, bool) {
+		respond.Error(c, status, code, message, nil)
+		return ApplyRun{}, resumeservice.AnalysisResultV2_3{}, documents.Document{}, documents.ResumeSource{}, false
+	}
+
+	run, err := h.Svc.GetApplyRun(c.Request.Context(), userID, applyRunID)
+	if err != nil {
+		if errors.Is(err, ErrApplyRunNotFound) {
+			return fail(http.StatusNotFound, "not_found", "apply run not found")
+		}
+		return fail(http.StatusInternalServerError, "internal_error", "failed to fetch apply run")
+	}
+
+	analysis, err := h.AnalysisRepo.GetByID(c.Request.Context(), run.AnalysisID)
+	if err != nil {
+		if errors.Is(err, ErrAnalysisNotFound) {
+			return fail(http.StatusNotFound, "not_found", "analysis not found")
+		}
+		return fail(http.StatusInternalServerError, "internal_error", "failed to fetch analysis")
+	}
+	if analysis.UserID != userID {
+		return fail(http.StatusNotFound, "not_found", "analysis not found")
+	}
+	if analysis.Result == nil {
+		return fail(http.StatusConflict, "analysis_pending", "analysis not complete")
+	}
+
+	result, err := decodeAnalysisResult(analysis.Result)
+	if err != nil {
+		return fail(http.StatusBadRequest, "invalid_analysis", "analysis result is not compatible")
+	}
+
+	doc, err := h.DocRepo.GetByID(c.Request.Context(), userID, analysis.DocumentID)
+	if err != nil {
+		if errors.Is(err, documents.ErrNotFound) {
+			return fail(http.StatusNotFound, "not_found", "document not found")
+		}
+		return fail(http.StatusInternalServerError, "internal_error", "failed to load document")
+	}
+
+	source, err := documents.LoadResumeSource(c.Request.Context(), h.Store, doc)
+	if err != nil {
+		if errors.Is(err, documents.ErrUnreadableDocument) {
+			return fail(http.StatusUnprocessableEntity, "unreadable_document", "resume text could not be read from the document")
+		}
+		return fail(http.StatusInternalServerError, "internal_error", "failed to read document")
+	}
+	return run, result, doc, source, true
+}
+
+func (in applyHeaderInput) inputs() resumeservice.ApplyHeaderInputs {
+	return resumeservice.ApplyHeaderInputs{
+		Name:     in.Name,
+		Title:    in.Title,
+		Email:    in.Email,
+		Phone:    in.Phone,
+		Location: in.Location,
+		Links:    in.Links,
+	}
+}
+
 func decodeAnalysisResult(result map[string]any) (resumeservice.AnalysisResultV2_3, error) {
 	payload, err := json.Marshal(result)
 	if err != nil {
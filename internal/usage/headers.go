@@ -0,0 +1,39 @@
+package usage
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RemainingHeader carries the units left in the caller's current period, so
+// clients can show their quota without calling GET /usage.
+const RemainingHeader = "X-Usage-Remaining"
+
+// Remaining returns the units left before the limit, never negative.
+func (u Usage) Remaining() int {
+	if u.Used >= u.Limit {
+		return 0
+	}
+	return u.Limit - u.Used
+}
+
+// SetRemainingHeader writes the units left in u to the response.
+func SetRemainingHeader(c *gin.Context, u Usage) {
+	c.Header(RemainingHeader, strconv.Itoa(u.Remaining()))
+}
+
+// WriteRemainingHeader looks up the usage charged for userID, or for the
+// organization when orgID is set, and writes X-Usage-Remaining. Lookup
+// failures leave the header out.
+func (s *Service) WriteRemainingHeader(ctx context.Context, c *gin.Context, userID, orgID string) {
+	if s == nil {
+		return
+	}
+	_, u, err := s.CanConsume(ctx, userID, orgID, 0)
+	if err != nil {
+		return
+	}
+	SetRemainingHeader(c, u)
+}
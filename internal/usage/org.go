@@ -0,0 +1,150 @@
+package usage
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// OrgQuota is an organization's pooled allowance, shared by all members.
+type OrgQuota struct {
+	OrgID string `json:"orgId"`
+	Plan  string `json:"plan"`
+	// Limit is the pooled number of units per period.
+	Limit int `json:"limit"`
+	// SeatLimit caps what a single member may use per period; zero means uncapped.
+	SeatLimit int       `json:"seatLimit"`
+	Used      int       `json:"used"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
+
+// OrgMember tracks one member's consumption against the pool.
+type OrgMember struct {
+	OrgID    string    `json:"-"`
+	UserID   string    `json:"userId"`
+	JoinedAt time.Time `json:"joinedAt"`
+	// SeatLimit is the member's cap for the current period, prorated if they joined
+	// mid-period; zero means uncapped.
+	SeatLimit int `json:"seatLimit"`
+	Used      int `json:"used"`
+}
+
+// OrgSummary reports pooled and per-member usage for an organization.
+type OrgSummary struct {
+	OrgQuota
+	Remaining int         `json:"remaining"`
+	Members   []OrgMember `json:"members"`
+}
+
+// SetOrgQuota creates or updates an organization's plan and limits. Existing
+// consumption and the current period are kept; a changed SeatLimit applies to
+// existing members from the next period.
+func (s *Service) SetOrgQuota(ctx context.Context, orgID, plan string, limit, seatLimit int) (OrgQuota, error) {
+	if orgID == "" || limit < 0 || seatLimit < 0 {
+		return OrgQuota{}, ErrInvalidQuota
+	}
+	if plan == "" {
+		plan = "Team"
+	}
+	return s.store.UpsertOrgQuota(ctx, OrgQuota{
+		OrgID:     orgID,
+		Plan:      plan,
+		Limit:     limit,
+		SeatLimit: seatLimit,
+		ResetsAt:  time.Now().UTC().Add(usagePeriod),
+	})
+}
+
+// AddOrgMember adds userID to the organization. A member who joins mid-period gets a
+// seat cap proportional to the time left in it; adding an existing member is a no-op.
+func (s *Service) AddOrgMember(ctx context.Context, orgID, userID string) (OrgMember, error) {
+	if orgID == "" || userID == "" {
+		return OrgMember{}, ErrInvalidQuota
+	}
+	quota, err := s.store.GetOrgQuota(ctx, orgID)
+	if err != nil {
+		return OrgMember{}, err
+	}
+	now := time.Now().UTC()
+	return s.store.AddOrgMember(ctx, OrgMember{
+		OrgID:     orgID,
+		UserID:    userID,
+		JoinedAt:  now,
+		SeatLimit: proratedSeatLimit(quota.SeatLimit, now, quota.ResetsAt),
+	})
+}
+
+// OrgSummary returns the organization's usage for a member.
+func (s *Service) OrgSummary(ctx context.Context, orgID, userID string) (OrgSummary, error) {
+	quota, err := s.store.GetOrgQuota(ctx, orgID)
+	if err != nil {
+		return OrgSummary{}, err
+	}
+	if _, err := s.store.GetOrgMember(ctx, orgID, userID); err != nil {
+		return OrgSummary{}, err
+	}
+	members, err := s.store.ListOrgMembers(ctx, orgID)
+	if err != nil {
+		return OrgSummary{}, err
+	}
+	remaining := quota.Limit - quota.Used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return OrgSummary{OrgQuota: quota, Remaining: remaining, Members: members}, nil
+}
+
+// CheckOrgMember returns ErrOrgNotFound or ErrNotOrgMember unless userID belongs
+// to the organization.
+func (s *Service) CheckOrgMember(ctx context.Context, orgID, userID string) error {
+	_, err := s.store.GetOrgMember(ctx, orgID, userID)
+	return err
+}
+
+func (s *Service) canConsumeOrg(ctx context.Context, userID, orgID string, n int) (bool, Usage, error) {
+	quota, err := s.store.GetOrgQuota(ctx, orgID)
+	if err != nil {
+		return false, Usage{}, err
+	}
+	member, err := s.store.GetOrgMember(ctx, orgID, userID)
+	if err != nil {
+		return false, Usage{}, err
+	}
+	u := quota.usage()
+	if n <= 0 {
+		return true, u, nil
+	}
+	return orgAllows(quota, member, n), u, nil
+}
+
+func (q OrgQuota) usage() Usage {
+	return Usage{Plan: q.Plan, Limit: q.Limit, Used: q.Used, ResetsAt: q.ResetsAt}
+}
+
+// orgAllows reports whether both the pool and the member's seat have room for n units.
+func orgAllows(quota OrgQuota, member OrgMember, n int) bool {
+	if quota.Used+n > quota.Limit {
+		return false
+	}
+	return member.SeatLimit == 0 || member.Used+n <= member.SeatLimit
+}
+
+// proratedSeatLimit scales a full-period seat cap by the share of the period left
+// at joinedAt, rounding up so a late joiner can always use at least one unit.
+func proratedSeatLimit(seatLimit int, joinedAt, resetsAt time.Time) int {
+	if seatLimit <= 0 {
+		return 0
+	}
+	remaining := resetsAt.Sub(joinedAt)
+	if remaining >= usagePeriod {
+		return seatLimit
+	}
+	if remaining <= 0 {
+		return 1
+	}
+	prorated := int(math.Ceil(float64(seatLimit) * float64(remaining) / float64(usagePeriod)))
+	if prorated < 1 {
+		prorated = 1
+	}
+	return prorated
+}
@@ -0,0 +1,115 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrgPoolIsSharedAndSeatsAreCapped(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService()
+
+	if _, err := svc.SetOrgQuota(ctx, "org-1", "", 5, 3); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	for _, userID := range []string{"alice", "bob"} {
+		member, err := svc.AddOrgMember(ctx, "org-1", userID)
+		if err != nil {
+			t.Fatalf("add member %s: %v", userID, err)
+		}
+		// Joining right after the quota was created is effectively a full period.
+		if member.SeatLimit != 3 {
+			t.Fatalf("expected full seat limit 3 for %s, got %d", userID, member.SeatLimit)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Consume(ctx, "alice", "org-1", 1); err != nil {
+			t.Fatalf("alice consume %d: %v", i+1, err)
+		}
+	}
+	ok, _, err := svc.CanConsume(ctx, "alice", "org-1", 1)
+	if err != nil || ok {
+		t.Fatalf("expected alice to hit her seat cap, ok=%v err=%v", ok, err)
+	}
+	if _, err := svc.Consume(ctx, "alice", "org-1", 1); !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("expected ErrLimitReached for alice, got %v", err)
+	}
+
+	// Bob has seat room but only two units are left in the pool.
+	u, err := svc.Consume(ctx, "bob", "org-1", 2)
+	if err != nil {
+		t.Fatalf("bob consume: %v", err)
+	}
+	if u.Used != 5 || u.Limit != 5 {
+		t.Fatalf("expected pool 5/5, got %d/%d", u.Used, u.Limit)
+	}
+	if _, err := svc.Consume(ctx, "bob", "org-1", 1); !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("expected pool exhaustion, got %v", err)
+	}
+
+	// Personal usage is untouched by org consumption.
+	personal, err := svc.Get(ctx, "bob")
+	if err != nil {
+		t.Fatalf("get personal usage: %v", err)
+	}
+	if personal.Used != 0 {
+		t.Fatalf("expected personal usage 0, got %d", personal.Used)
+	}
+
+	summary, err := svc.OrgSummary(ctx, "org-1", "bob")
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if summary.Used != 5 || summary.Remaining != 0 || len(summary.Members) != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestOrgConsumeRequiresMembership(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService()
+
+	if _, _, err := svc.CanConsume(ctx, "mallory", "missing-org", 1); !errors.Is(err, ErrOrgNotFound) {
+		t.Fatalf("expected ErrOrgNotFound, got %v", err)
+	}
+	if _, err := svc.SetOrgQuota(ctx, "org-2", "Team", 10, 0); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	if _, err := svc.Consume(ctx, "mallory", "org-2", 1); !errors.Is(err, ErrNotOrgMember) {
+		t.Fatalf("expected ErrNotOrgMember, got %v", err)
+	}
+	if _, err := svc.OrgSummary(ctx, "org-2", "mallory"); !errors.Is(err, ErrNotOrgMember) {
+		t.Fatalf("expected ErrNotOrgMember from summary, got %v", err)
+	}
+	if _, err := svc.SetOrgQuota(ctx, "org-2", "Team", -1, 0); !errors.Is(err, ErrInvalidQuota) {
+		t.Fatalf("expected ErrInvalidQuota, got %v", err)
+	}
+}
+
+func TestProratedSeatLimit(t *testing.T) {
+	resetsAt := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	periodStart := resetsAt.Add(-usagePeriod)
+
+	tests := []struct {
+		name      string
+		seatLimit int
+		joinedAt  time.Time
+		want      int
+	}{
+		{name: "uncapped", seatLimit: 0, joinedAt: periodStart.Add(72 * time.Hour), want: 0},
+		{name: "start of period", seatLimit: 14, joinedAt: periodStart, want: 14},
+		{name: "half way", seatLimit: 14, joinedAt: periodStart.Add(usagePeriod / 2), want: 7},
+		{name: "rounds up", seatLimit: 10, joinedAt: periodStart.Add(5 * 24 * time.Hour), want: 3},
+		{name: "last minute", seatLimit: 10, joinedAt: resetsAt.Add(-time.Minute), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proratedSeatLimit(tt.seatLimit, tt.joinedAt, resetsAt); got != tt.want {
+				t.Fatalf("proratedSeatLimit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
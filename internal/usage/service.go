@@ -2,7 +2,9 @@ package usage
 
 import (
 	"context"
+	"time"
 
+	"resume-backend/internal/shared/storage/db"
 	resumeservice "resume-backend/resume/service"
 )
 
@@ -14,12 +16,34 @@ type store interface {
 	CreateApplyRun(ctx context.Context, run ApplyRun) error
 	GetApplyRun(ctx context.Context, userID, runID string) (ApplyRun, error)
 	UpdateApplyRun(ctx context.Context, update ApplyRunUpdate) error
+	// ListApplyRunsByStatus returns runs in status created before the given
+	// time, oldest first.
+	ListApplyRunsByStatus(ctx context.Context, status string, createdBefore time.Time, limit int) ([]ApplyRun, error)
 	CreateDocumentVersion(ctx context.Context, version DocumentVersion) error
+	RecordAppliedRewrites(ctx context.Context, rewrites []AppliedRewrite) error
+	ListAppliedRewrites(ctx context.Context, userID, documentID string, limit int) ([]AppliedRewrite, error)
+	// ListDocumentVersions returns the versions rendered from a document.
+	ListDocumentVersions(ctx context.Context, userID, documentID string) ([]DocumentVersion, error)
+	// DeleteApplyData removes a document's versions, applied rewrites and apply
+	// runs, returning how many rows it removed.
+	DeleteApplyData(ctx context.Context, userID, documentID string) (int, error)
+
+	GetOrgQuota(ctx context.Context, orgID string) (OrgQuota, error)
+	UpsertOrgQuota(ctx context.Context, quota OrgQuota) (OrgQuota, error)
+	AddOrgMember(ctx context.Context, member OrgMember) (OrgMember, error)
+	GetOrgMember(ctx context.Context, orgID, userID string) (OrgMember, error)
+	ListOrgMembers(ctx context.Context, orgID string) ([]OrgMember, error)
+	// ConsumeOrg charges n units to both the pool and the member's seat atomically.
+	ConsumeOrg(ctx context.Context, orgID, userID string, n int) (OrgQuota, error)
 }
 
 // Service manages usage data via an underlying store.
 type Service struct {
 	store store
+	// SoftLimitPercent flags usage at or above this share of the limit; zero disables it.
+	SoftLimitPercent int
+	// UnitOfWork makes CompleteApplyRun atomic; nil writes one row after another.
+	UnitOfWork db.UnitOfWork
 }
 
 // NewService constructs a Service with in-memory store.
@@ -42,8 +66,12 @@ func (s *Service) EnsurePeriod(ctx context.Context, userID string) (Usage, error
 	return s.store.EnsurePeriod(ctx, userID)
 }
 
-// CanConsume reports whether the user can consume n units.
-func (s *Service) CanConsume(ctx context.Context, userID string, n int) (bool, Usage, error) {
+// CanConsume reports whether the user can consume n units. With a non-empty orgID the
+// organization's pool and the user's seat cap are checked instead of personal usage.
+func (s *Service) CanConsume(ctx context.Context, userID, orgID string, n int) (bool, Usage, error) {
+	if orgID != "" {
+		return s.canConsumeOrg(ctx, userID, orgID, n)
+	}
 	u, err := s.store.EnsurePeriod(ctx, userID)
 	if err != nil {
 		return false, Usage{}, err
@@ -57,8 +85,15 @@ func (s *Service) CanConsume(ctx context.Context, userID string, n int) (bool, U
 	return true, u, nil
 }
 
-// Consume increments usage by n if within limit.
-func (s *Service) Consume(ctx context.Context, userID string, n int) (Usage, error) {
+// Consume increments usage by n if within limit, charging the organization when orgID is set.
+func (s *Service) Consume(ctx context.Context, userID, orgID string, n int) (Usage, error) {
+	if orgID != "" {
+		quota, err := s.store.ConsumeOrg(ctx, orgID, userID, n)
+		if err != nil {
+			return Usage{}, err
+		}
+		return quota.usage(), nil
+	}
 	return s.store.Consume(ctx, userID, n)
 }
 
@@ -82,11 +117,42 @@ func (s *Service) UpdateApplyRun(ctx context.Context, update ApplyRunUpdate) err
 	return s.store.UpdateApplyRun(ctx, update)
 }
 
+// ListStalledApplyRuns returns apply runs still planned that were created
+// before the given time, oldest first. A run is planned until it is executed,
+// so these were started and never finished.
+func (s *Service) ListStalledApplyRuns(ctx context.Context, createdBefore time.Time, limit int) ([]ApplyRun, error) {
+	return s.store.ListApplyRunsByStatus(ctx, ApplyRunStatusPlanned, createdBefore, limit)
+}
+
 // CreateDocumentVersion persists a rendered resume version.
 func (s *Service) CreateDocumentVersion(ctx context.Context, version DocumentVersion) error {
 	return s.store.CreateDocumentVersion(ctx, version)
 }
 
+// CompleteApplyRun records the versions an executed run rendered together with
+// the run's outcome, so a run never points at a version that was not stored.
+func (s *Service) CompleteApplyRun(ctx context.Context, versions []DocumentVersion, update ApplyRunUpdate) error {
+	return db.Run(ctx, s.UnitOfWork, func(ctx context.Context) error {
+		for _, version := range versions {
+			if err := s.store.CreateDocumentVersion(ctx, version); err != nil {
+				return err
+			}
+		}
+		return s.store.UpdateApplyRun(ctx, update)
+	})
+}
+
+// ListDocumentVersions returns the versions apply runs rendered from a document.
+func (s *Service) ListDocumentVersions(ctx context.Context, userID, documentID string) ([]DocumentVersion, error) {
+	return s.store.ListDocumentVersions(ctx, userID, documentID)
+}
+
+// DeleteApplyData removes everything apply runs recorded for a document. Delete
+// the versions' stored objects first; their keys are gone afterwards.
+func (s *Service) DeleteApplyData(ctx context.Context, userID, documentID string) (int, error) {
+	return s.store.DeleteApplyData(ctx, userID, documentID)
+}
+
 // BuildApplyPlan generates an ApplyPlan from analysis results.
 func (s *Service) BuildApplyPlan(analysis resumeservice.AnalysisResultV2_3) resumeservice.ApplyPlan {
 	return resumeservice.BuildApplyPlan(analysis)
@@ -0,0 +1,44 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"resume-backend/internal/shared/storage/db"
+)
+
+func TestCompleteApplyRunIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService()
+	svc.UnitOfWork = &db.MemoryUnitOfWork{}
+	now := time.Now().UTC()
+	if err := svc.CreateApplyRun(ctx, ApplyRun{ID: "run-1", UserID: "user-1", AnalysisID: "a-1", Status: ApplyRunStatusPlanned, CreatedAt: now}); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	versions := []DocumentVersion{
+		{ID: "v-1", DocumentID: "doc-1", UserID: "user-1", ApplyRunID: "run-1", StorageKey: "k1", CreatedAt: now},
+		{ID: "v-2", DocumentID: "doc-1", UserID: "user-1", ApplyRunID: "run-1", StorageKey: "k2", CreatedAt: now},
+	}
+
+	// Another user's run is not found, so neither version may be kept.
+	err := svc.CompleteApplyRun(ctx, versions, ApplyRunUpdate{ID: "run-1", UserID: "user-2", Status: "completed", DocumentVersionID: "v-1"})
+	if !errors.Is(err, ErrApplyRunNotFound) {
+		t.Fatalf("expected ErrApplyRunNotFound, got %v", err)
+	}
+	if stored, _ := svc.ListDocumentVersions(ctx, "user-1", "doc-1"); len(stored) != 0 {
+		t.Fatalf("expected the versions to be rolled back, got %+v", stored)
+	}
+
+	if err := svc.CompleteApplyRun(ctx, versions, ApplyRunUpdate{ID: "run-1", UserID: "user-1", Status: "completed", DocumentVersionID: "v-1"}); err != nil {
+		t.Fatalf("complete run: %v", err)
+	}
+	run, err := svc.GetApplyRun(ctx, "user-1", "run-1")
+	if err != nil || run.DocumentVersionID != "v-1" {
+		t.Fatalf("expected the run to point at v-1, got %+v err=%v", run, err)
+	}
+	if stored, _ := svc.ListDocumentVersions(ctx, "user-1", "doc-1"); len(stored) != 2 {
+		t.Fatalf("expected both versions stored, got %+v", stored)
+	}
+}
@@ -2,8 +2,11 @@ package usage
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 type memoryStore struct {
@@ -11,6 +14,9 @@ type memoryStore struct {
 	data             map[string]Usage
 	applyRuns        map[string]ApplyRun
 	documentVersions map[string]DocumentVersion
+	appliedRewrites  map[string][]AppliedRewrite
+	orgs             map[string]OrgQuota
+	orgMembers       map[string]map[string]OrgMember
 }
 
 func newMemoryStore() *memoryStore {
@@ -18,6 +24,9 @@ func newMemoryStore() *memoryStore {
 		data:             make(map[string]Usage),
 		applyRuns:        make(map[string]ApplyRun),
 		documentVersions: make(map[string]DocumentVersion),
+		appliedRewrites:  make(map[string][]AppliedRewrite),
+		orgs:             make(map[string]OrgQuota),
+		orgMembers:       make(map[string]map[string]OrgMember),
 	}
 }
 
@@ -123,6 +132,25 @@ func (s *memoryStore) GetApplyRun(ctx context.Context, userID, runID string) (Ap
 	return run, nil
 }
 
+func (s *memoryStore) ListApplyRunsByStatus(ctx context.Context, status string, createdBefore time.Time, limit int) ([]ApplyRun, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	var out []ApplyRun
+	for _, run := range s.applyRuns {
+		if run.Status == status && run.CreatedAt.Before(createdBefore) {
+			out = append(out, run)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
 func (s *memoryStore) UpdateApplyRun(ctx context.Context, update ApplyRunUpdate) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -133,6 +161,12 @@ func (s *memoryStore) UpdateApplyRun(ctx context.Context, update ApplyRunUpdate)
 	if !ok || run.UserID != update.UserID {
 		return ErrApplyRunNotFound
 	}
+	prev := run
+	db.OnRollback(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.applyRuns[prev.ID] = prev
+	})
 	run.Status = update.Status
 	run.AutoFixesCount = update.AutoFixesCount
 	run.SafeRewritesCount = update.SafeRewritesCount
@@ -151,5 +185,259 @@ func (s *memoryStore) CreateDocumentVersion(ctx context.Context, version Documen
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.documentVersions[version.ID] = version
+	db.OnRollback(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.documentVersions, version.ID)
+	})
 	return nil
 }
+
+func (s *memoryStore) RecordAppliedRewrites(ctx context.Context, rewrites []AppliedRewrite) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range rewrites {
+		existing := s.appliedRewrites[r.DocumentID]
+		duplicate := false
+		for _, e := range existing {
+			if e.Before == r.Before && e.After == r.After {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			s.appliedRewrites[r.DocumentID] = append(existing, r)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) ListAppliedRewrites(ctx context.Context, userID, documentID string, limit int) ([]AppliedRewrite, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []AppliedRewrite
+	for _, r := range s.appliedRewrites[documentID] {
+		if r.UserID != userID {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *memoryStore) ListDocumentVersions(ctx context.Context, userID, documentID string) ([]DocumentVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []DocumentVersion
+	for _, v := range s.documentVersions {
+		if v.UserID == userID && v.DocumentID == documentID {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *memoryStore) DeleteApplyData(ctx context.Context, userID, documentID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		versions []DocumentVersion
+		rewrites []AppliedRewrite
+		runs     []ApplyRun
+	)
+	runIDs := map[string]struct{}{}
+	for id, v := range s.documentVersions {
+		if v.UserID != userID || v.DocumentID != documentID {
+			continue
+		}
+		if v.ApplyRunID != "" {
+			runIDs[v.ApplyRunID] = struct{}{}
+		}
+		delete(s.documentVersions, id)
+		versions = append(versions, v)
+	}
+	kept := s.appliedRewrites[documentID][:0]
+	for _, r := range s.appliedRewrites[documentID] {
+		if r.UserID != userID {
+			kept = append(kept, r)
+			continue
+		}
+		if r.ApplyRunID != "" {
+			runIDs[r.ApplyRunID] = struct{}{}
+		}
+		rewrites = append(rewrites, r)
+	}
+	if len(kept) == 0 {
+		delete(s.appliedRewrites, documentID)
+	} else {
+		s.appliedRewrites[documentID] = kept
+	}
+	for id := range runIDs {
+		if run, ok := s.applyRuns[id]; ok && run.UserID == userID {
+			delete(s.applyRuns, id)
+			runs = append(runs, run)
+		}
+	}
+	db.OnRollback(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, v := range versions {
+			s.documentVersions[v.ID] = v
+		}
+		if len(rewrites) > 0 {
+			s.appliedRewrites[documentID] = append(s.appliedRewrites[documentID], rewrites...)
+		}
+		for _, run := range runs {
+			s.applyRuns[run.ID] = run
+		}
+	})
+	return len(versions) + len(rewrites) + len(runs), nil
+}
+
+func (s *memoryStore) GetOrgQuota(ctx context.Context, orgID string) (OrgQuota, error) {
+	if err := ctx.Err(); err != nil {
+		return OrgQuota{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ensureOrgLocked(orgID)
+}
+
+func (s *memoryStore) UpsertOrgQuota(ctx context.Context, quota OrgQuota) (OrgQuota, error) {
+	if err := ctx.Err(); err != nil {
+		return OrgQuota{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.orgs[quota.OrgID]; ok {
+		quota.Used = existing.Used
+		quota.ResetsAt = existing.ResetsAt
+	}
+	s.orgs[quota.OrgID] = quota
+	return s.ensureOrgLocked(quota.OrgID)
+}
+
+func (s *memoryStore) AddOrgMember(ctx context.Context, member OrgMember) (OrgMember, error) {
+	if err := ctx.Err(); err != nil {
+		return OrgMember{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.orgs[member.OrgID]; !ok {
+		return OrgMember{}, ErrOrgNotFound
+	}
+	members, ok := s.orgMembers[member.OrgID]
+	if !ok {
+		members = make(map[string]OrgMember)
+		s.orgMembers[member.OrgID] = members
+	}
+	if existing, ok := members[member.UserID]; ok {
+		return existing, nil
+	}
+	member.Used = 0
+	members[member.UserID] = member
+	return member, nil
+}
+
+func (s *memoryStore) GetOrgMember(ctx context.Context, orgID, userID string) (OrgMember, error) {
+	if err := ctx.Err(); err != nil {
+		return OrgMember{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.ensureOrgLocked(orgID); err != nil {
+		return OrgMember{}, err
+	}
+	member, ok := s.orgMembers[orgID][userID]
+	if !ok {
+		return OrgMember{}, ErrNotOrgMember
+	}
+	return member, nil
+}
+
+func (s *memoryStore) ListOrgMembers(ctx context.Context, orgID string) ([]OrgMember, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.ensureOrgLocked(orgID); err != nil {
+		return nil, err
+	}
+	members := make([]OrgMember, 0, len(s.orgMembers[orgID]))
+	for _, member := range s.orgMembers[orgID] {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].UserID < members[j].UserID
+		}
+		return members[i].JoinedAt.Before(members[j].JoinedAt)
+	})
+	return members, nil
+}
+
+func (s *memoryStore) ConsumeOrg(ctx context.Context, orgID, userID string, n int) (OrgQuota, error) {
+	if err := ctx.Err(); err != nil {
+		return OrgQuota{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	quota, err := s.ensureOrgLocked(orgID)
+	if err != nil {
+		return OrgQuota{}, err
+	}
+	member, ok := s.orgMembers[orgID][userID]
+	if !ok {
+		return OrgQuota{}, ErrNotOrgMember
+	}
+	if n <= 0 {
+		return quota, nil
+	}
+	if !orgAllows(quota, member, n) {
+		return OrgQuota{}, ErrLimitReached
+	}
+	quota.Used += n
+	member.Used += n
+	s.orgs[orgID] = quota
+	s.orgMembers[orgID][userID] = member
+	return quota, nil
+}
+
+// ensureOrgLocked starts a new period when the current one has ended, restoring every
+// member's full seat cap. Callers must hold s.mu.
+func (s *memoryStore) ensureOrgLocked(orgID string) (OrgQuota, error) {
+	quota, ok := s.orgs[orgID]
+	if !ok {
+		return OrgQuota{}, ErrOrgNotFound
+	}
+	now := time.Now().UTC()
+	if now.Before(quota.ResetsAt) {
+		return quota, nil
+	}
+	quota.Used = 0
+	quota.ResetsAt = now.Add(usagePeriod)
+	s.orgs[orgID] = quota
+	for userID, member := range s.orgMembers[orgID] {
+		member.Used = 0
+		member.SeatLimit = quota.SeatLimit
+		s.orgMembers[orgID][userID] = member
+	}
+	return quota, nil
+}
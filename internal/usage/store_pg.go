@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"time"
+
+	"resume-backend/internal/shared/storage/db"
 )
 
 type pgStore struct {
@@ -139,6 +141,29 @@ LIMIT 1`
 	return run, nil
 }
 
+func (s *pgStore) ListApplyRunsByStatus(ctx context.Context, status string, createdBefore time.Time, limit int) ([]ApplyRun, error) {
+	const query = `
+SELECT id, user_id, analysis_id, status, created_at
+FROM apply_runs
+WHERE status = $1 AND created_at < $2
+ORDER BY created_at
+LIMIT $3`
+	rows, err := s.DB.QueryContext(ctx, query, status, createdBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ApplyRun
+	for rows.Next() {
+		var run ApplyRun
+		if err := rows.Scan(&run.ID, &run.UserID, &run.AnalysisID, &run.Status, &run.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
+
 func (s *pgStore) UpdateApplyRun(ctx context.Context, update ApplyRunUpdate) error {
 	const query = `
 UPDATE apply_runs
@@ -150,7 +175,7 @@ SET status = $1,
     placeholders_remaining = $6,
     document_version_id = $7
 WHERE id = $8 AND user_id = $9`
-	res, err := s.DB.ExecContext(ctx, query,
+	res, err := db.ConnFor(ctx, s.DB).ExecContext(ctx, query,
 		update.Status,
 		update.AutoFixesCount,
 		update.SafeRewritesCount,
@@ -179,7 +204,7 @@ func (s *pgStore) CreateDocumentVersion(ctx context.Context, version DocumentVer
 INSERT INTO document_versions (
     id, document_id, user_id, apply_run_id, file_name, mime_type, size_bytes, storage_key, created_at
 ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	_, err := s.DB.ExecContext(ctx, query,
+	_, err := db.ConnFor(ctx, s.DB).ExecContext(ctx, query,
 		version.ID,
 		version.DocumentID,
 		version.UserID,
@@ -193,6 +218,52 @@ INSERT INTO document_versions (
 	return err
 }
 
+func (s *pgStore) RecordAppliedRewrites(ctx context.Context, rewrites []AppliedRewrite) error {
+	const query = `
+INSERT INTO applied_rewrites (
+    id, document_id, user_id, apply_run_id, section, before_text, after_text, created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (document_id, md5(before_text), md5(after_text)) DO NOTHING`
+	for _, r := range rewrites {
+		if _, err := s.DB.ExecContext(ctx, query,
+			r.ID,
+			r.DocumentID,
+			r.UserID,
+			r.ApplyRunID,
+			r.Section,
+			r.Before,
+			r.After,
+			r.CreatedAt,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *pgStore) ListAppliedRewrites(ctx context.Context, userID, documentID string, limit int) ([]AppliedRewrite, error) {
+	const query = `
+SELECT id, document_id, user_id, apply_run_id, section, before_text, after_text, created_at
+FROM applied_rewrites
+WHERE user_id = $1 AND document_id = $2
+ORDER BY created_at, id
+LIMIT $3`
+	rows, err := s.DB.QueryContext(ctx, query, userID, documentID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []AppliedRewrite
+	for rows.Next() {
+		var r AppliedRewrite
+		if err := rows.Scan(&r.ID, &r.DocumentID, &r.UserID, &r.ApplyRunID, &r.Section, &r.Before, &r.After, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
 func nullableString(value string) sql.NullString {
 	if value == "" {
 		return sql.NullString{}
@@ -200,6 +271,59 @@ func nullableString(value string) sql.NullString {
 	return sql.NullString{String: value, Valid: true}
 }
 
+func (s *pgStore) ListDocumentVersions(ctx context.Context, userID, documentID string) ([]DocumentVersion, error) {
+	const query = `
+SELECT id, document_id, user_id, COALESCE(apply_run_id, ''), file_name, mime_type, size_bytes, storage_key, created_at
+FROM document_versions
+WHERE user_id = $1 AND document_id = $2
+ORDER BY created_at`
+	rows, err := s.DB.QueryContext(ctx, query, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []DocumentVersion
+	for rows.Next() {
+		var v DocumentVersion
+		if err := rows.Scan(&v.ID, &v.DocumentID, &v.UserID, &v.ApplyRunID, &v.FileName, &v.MimeType, &v.SizeBytes, &v.StorageKey, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func (s *pgStore) DeleteApplyData(ctx context.Context, userID, documentID string) (int, error) {
+	// Runs are found through the document's analyses as well as its versions
+	// and rewrites, since planned runs have neither.
+	statements := []string{
+		`DELETE FROM apply_runs
+WHERE user_id = $1 AND (
+    id IN (SELECT apply_run_id FROM document_versions WHERE user_id = $1 AND document_id = $2)
+    OR id IN (SELECT apply_run_id FROM applied_rewrites WHERE user_id = $1 AND document_id = $2)
+    OR analysis_id IN (SELECT id::text FROM analyses WHERE user_id = $1 AND document_id::text = $2)
+)`,
+		`DELETE FROM document_versions WHERE user_id = $1 AND document_id = $2`,
+		`DELETE FROM applied_rewrites WHERE user_id = $1 AND document_id = $2`,
+	}
+	deleted := 0
+	err := db.InTx(ctx, s.DB, func(conn db.Conn) error {
+		for _, stmt := range statements {
+			res, err := conn.ExecContext(ctx, stmt, userID, documentID)
+			if err != nil {
+				return err
+			}
+			n, _ := res.RowsAffected()
+			deleted += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
 func (s *pgStore) ensure(ctx context.Context, userID string) (Usage, error) {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -249,3 +373,182 @@ INSERT INTO usage (user_id, plan, limit_amount, used, resets_at) VALUES ($1, $2,
 	}
 	return u, nil
 }
+
+func (s *pgStore) GetOrgQuota(ctx context.Context, orgID string) (OrgQuota, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return OrgQuota{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	quota, err := s.lockAndEnsureOrg(ctx, tx, orgID)
+	if err != nil {
+		return OrgQuota{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return OrgQuota{}, err
+	}
+	return quota, nil
+}
+
+func (s *pgStore) UpsertOrgQuota(ctx context.Context, quota OrgQuota) (OrgQuota, error) {
+	const query = `
+INSERT INTO org_usage (org_id, plan, limit_amount, seat_limit, used, resets_at)
+VALUES ($1, $2, $3, $4, 0, $5)
+ON CONFLICT (org_id) DO UPDATE
+SET plan = EXCLUDED.plan, limit_amount = EXCLUDED.limit_amount, seat_limit = EXCLUDED.seat_limit`
+	if _, err := s.DB.ExecContext(ctx, query, quota.OrgID, quota.Plan, quota.Limit, quota.SeatLimit, quota.ResetsAt); err != nil {
+		return OrgQuota{}, err
+	}
+	return s.GetOrgQuota(ctx, quota.OrgID)
+}
+
+func (s *pgStore) AddOrgMember(ctx context.Context, member OrgMember) (OrgMember, error) {
+	const query = `
+INSERT INTO org_usage_members (org_id, user_id, joined_at, seat_limit, used)
+SELECT $1, $2, $3, $4, 0
+WHERE EXISTS (SELECT 1 FROM org_usage WHERE org_id = $1)
+ON CONFLICT (org_id, user_id) DO NOTHING`
+	res, err := s.DB.ExecContext(ctx, query, member.OrgID, member.UserID, member.JoinedAt, member.SeatLimit)
+	if err != nil {
+		return OrgMember{}, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return OrgMember{}, err
+	}
+	if affected == 0 {
+		// Either the member already exists or the org does not; the lookup tells which.
+		existing, err := s.GetOrgMember(ctx, member.OrgID, member.UserID)
+		if err != nil {
+			return OrgMember{}, err
+		}
+		return existing, nil
+	}
+	member.Used = 0
+	return member, nil
+}
+
+func (s *pgStore) GetOrgMember(ctx context.Context, orgID, userID string) (OrgMember, error) {
+	if _, err := s.GetOrgQuota(ctx, orgID); err != nil {
+		return OrgMember{}, err
+	}
+	const query = `
+SELECT org_id, user_id, joined_at, seat_limit, used
+FROM org_usage_members
+WHERE org_id = $1 AND user_id = $2`
+	var m OrgMember
+	err := s.DB.QueryRowContext(ctx, query, orgID, userID).Scan(&m.OrgID, &m.UserID, &m.JoinedAt, &m.SeatLimit, &m.Used)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrgMember{}, ErrNotOrgMember
+		}
+		return OrgMember{}, err
+	}
+	return m, nil
+}
+
+func (s *pgStore) ListOrgMembers(ctx context.Context, orgID string) ([]OrgMember, error) {
+	if _, err := s.GetOrgQuota(ctx, orgID); err != nil {
+		return nil, err
+	}
+	const query = `
+SELECT org_id, user_id, joined_at, seat_limit, used
+FROM org_usage_members
+WHERE org_id = $1
+ORDER BY joined_at ASC, user_id ASC`
+	rows, err := s.DB.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []OrgMember
+	for rows.Next() {
+		var m OrgMember
+		if err := rows.Scan(&m.OrgID, &m.UserID, &m.JoinedAt, &m.SeatLimit, &m.Used); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (s *pgStore) ConsumeOrg(ctx context.Context, orgID, userID string, n int) (OrgQuota, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return OrgQuota{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	quota, err := s.lockAndEnsureOrg(ctx, tx, orgID)
+	if err != nil {
+		return OrgQuota{}, err
+	}
+	var member OrgMember
+	err = tx.QueryRowContext(ctx, `
+SELECT org_id, user_id, joined_at, seat_limit, used
+FROM org_usage_members
+WHERE org_id = $1 AND user_id = $2
+FOR UPDATE`, orgID, userID).Scan(&member.OrgID, &member.UserID, &member.JoinedAt, &member.SeatLimit, &member.Used)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrNotOrgMember
+		}
+		return OrgQuota{}, err
+	}
+	if n > 0 {
+		if !orgAllows(quota, member, n) {
+			err = ErrLimitReached
+			return OrgQuota{}, err
+		}
+		quota.Used += n
+		if _, err = tx.ExecContext(ctx, `UPDATE org_usage SET used = used + $1 WHERE org_id = $2`, n, orgID); err != nil {
+			return OrgQuota{}, err
+		}
+		if _, err = tx.ExecContext(ctx, `
+UPDATE org_usage_members SET used = used + $1 WHERE org_id = $2 AND user_id = $3`, n, orgID, userID); err != nil {
+			return OrgQuota{}, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return OrgQuota{}, err
+	}
+	return quota, nil
+}
+
+// lockAndEnsureOrg locks the org row and rolls it and its members into a new period
+// when the current one has ended.
+func (s *pgStore) lockAndEnsureOrg(ctx context.Context, tx *sql.Tx, orgID string) (OrgQuota, error) {
+	quota := OrgQuota{OrgID: orgID}
+	err := tx.QueryRowContext(ctx, `
+SELECT plan, limit_amount, seat_limit, used, resets_at FROM org_usage WHERE org_id = $1 FOR UPDATE`, orgID).
+		Scan(&quota.Plan, &quota.Limit, &quota.SeatLimit, &quota.Used, &quota.ResetsAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrgQuota{}, ErrOrgNotFound
+		}
+		return OrgQuota{}, err
+	}
+
+	now := time.Now().UTC()
+	if now.Before(quota.ResetsAt) {
+		return quota, nil
+	}
+	quota.Used = 0
+	quota.ResetsAt = now.Add(usagePeriod)
+	if _, err := tx.ExecContext(ctx, `UPDATE org_usage SET used = 0, resets_at = $1 WHERE org_id = $2`, quota.ResetsAt, orgID); err != nil {
+		return OrgQuota{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE org_usage_members SET used = 0, seat_limit = $1 WHERE org_id = $2`, quota.SeatLimit, orgID); err != nil {
+		return OrgQuota{}, err
+	}
+	return quota, nil
+}
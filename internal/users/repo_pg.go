@@ -5,10 +5,22 @@ import (
 	"database/sql"
 	"errors"
 	"time"
+
+	"resume-backend/internal/shared/fieldcrypt"
 )
 
+// emailColumn names users.email to the codec.
+const emailColumn = "users.email"
+
 type PGRepo struct {
 	DB *sql.DB
+	// Codec encrypts email addresses; nil stores them in the clear.
+	Codec *fieldcrypt.Codec
+}
+
+// EncryptedColumns lists the columns the PII backfill rewrites.
+func (r *PGRepo) EncryptedColumns() []fieldcrypt.Column {
+	return []fieldcrypt.Column{{Table: "users", Name: "email"}}
 }
 
 func (r *PGRepo) Upsert(ctx context.Context, user User) error {
@@ -22,9 +34,13 @@ ON CONFLICT (id) DO UPDATE SET
   family_name = EXCLUDED.family_name,
   picture_url = EXCLUDED.picture_url,
   updated_at = now()`
-	_, err := r.DB.ExecContext(ctx, query,
+	email, err := r.Codec.Encrypt(emailColumn, user.Email)
+	if err != nil {
+		return err
+	}
+	_, err = r.DB.ExecContext(ctx, query,
 		user.ID,
-		user.Email,
+		email,
 		nullableString(user.FullName),
 		nullableString(user.GivenName),
 		nullableString(user.FamilyName),
@@ -61,6 +77,9 @@ LIMIT 1`
 		}
 		return User{}, err
 	}
+	if user.Email, err = r.Codec.Decrypt(emailColumn, user.Email); err != nil {
+		return User{}, err
+	}
 	if fullName.Valid {
 		user.FullName = fullName.String
 	}
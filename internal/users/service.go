@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"strings"
+
+	"resume-backend/internal/events"
 )
 
 type Service struct {
 	Repo Repo
+	// Events receives funnel analytics; nil disables them.
+	Events *events.Emitter
 }
 
 func NewService(repo Repo) *Service {
@@ -22,7 +26,16 @@ func (s *Service) UpsertFromAuth(ctx context.Context, user User) error {
 	if strings.TrimSpace(user.ID) == "" || strings.TrimSpace(user.Email) == "" {
 		return errors.New("user id and email are required")
 	}
-	return s.Repo.Upsert(ctx, user)
+	_, err := s.Repo.GetByID(ctx, user.ID)
+	isNew := err == ErrNotFound
+	if err := s.Repo.Upsert(ctx, user); err != nil {
+		return err
+	}
+	if isNew {
+		provider, _, _ := strings.Cut(user.ID, ":")
+		s.Events.Track(ctx, events.Signup, user.ID, map[string]any{"provider": provider})
+	}
+	return nil
 }
 
 func (s *Service) GetByID(ctx context.Context, userID string) (User, error) {
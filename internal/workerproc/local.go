@@ -0,0 +1,153 @@
+package workerproc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"resume-backend/internal/analyses"
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
+)
+
+const (
+	defaultLocalRetryDelay  = 30 * time.Second
+	defaultLocalMaxAttempts = 5
+	maxLocalRetryDelay      = 15 * time.Minute
+)
+
+// LocalOptions tunes ConsumeLocal.
+type LocalOptions struct {
+	// Concurrency is how many messages are handled at once; it defaults to one.
+	Concurrency int
+	// RetryDelay is the wait before a failed message is retried. It doubles
+	// with each attempt, up to 15 minutes, and defaults to 30 seconds.
+	RetryDelay time.Duration
+	// MaxAttempts is how often a failing message is tried before it is dropped,
+	// like an SQS redrive policy; it defaults to five.
+	MaxAttempts int
+}
+
+// ConsumeLocal handles analysis jobs from q inside the calling process, as the
+// SQS worker does for the default profile. It returns once ctx is done and
+// every job in progress has finished; jobs are not cancelled with ctx, so a
+// job is never left half done by a shutdown.
+func ConsumeLocal(ctx context.Context, app *bootstrap.App, q queue.LocalQueue, opts LocalOptions) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultLocalRetryDelay
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultLocalMaxAttempts
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				d, err := q.Receive(ctx)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					telemetry.ErrorContext(ctx, "worker.local.receive_failed", map[string]any{"error": err.Error()})
+					sleep(ctx, opts.RetryDelay)
+					continue
+				}
+				handleLocal(context.WithoutCancel(ctx), app, q, d, opts)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func handleLocal(ctx context.Context, app *bootstrap.App, q queue.LocalQueue, d queue.Delivery, opts LocalOptions) {
+	fields := map[string]any{"message_id": d.ID, "attempts": d.Attempts}
+	msg, meta, err := ParseMessage(d.Body)
+	if err != nil {
+		fields["body_len"] = meta.BodyLen
+		fields["error"] = err.Error()
+		telemetry.ErrorContext(ctx, "worker.local.decode_failed", fields)
+		if ackLocal(ctx, q, d, fields) {
+			metrics.IncAnalysisJobsDeletedUnrecoverable()
+		}
+		return
+	}
+	fields["analysis_id"] = msg.AnalysisID
+	fields["request_id"] = msg.RequestID
+
+	err = HandleMessage(WithMessageID(WithParsedMessage(ctx, msg), d.ID), app, d.Body)
+	var procErr ErrProcess
+	switch {
+	case err == nil:
+		if ackLocal(ctx, q, d, fields) {
+			telemetry.InfoContext(ctx, "worker.local.completed", fields)
+			metrics.IncAnalysisJobsCompleted()
+		}
+	case errors.As(err, new(ErrDuplicate)):
+		// Another consumer holds the message and acks it once done.
+		retryLocal(ctx, q, d, opts.RetryDelay, fields)
+	case errors.As(err, &procErr) && errors.Is(procErr.Err, analyses.ErrExtractionPending):
+		telemetry.InfoContext(ctx, "worker.local.awaiting_extraction", fields)
+		retryLocal(ctx, q, d, opts.RetryDelay, fields)
+	default:
+		fields["error"] = err.Error()
+		telemetry.ErrorContext(ctx, "worker.local.failed", fields)
+		metrics.IncAnalysisJobsFailed()
+		if d.Attempts >= opts.MaxAttempts {
+			telemetry.ErrorContext(ctx, "worker.local.dead_lettered", fields)
+			if ackLocal(ctx, q, d, fields) {
+				metrics.IncAnalysisJobsDeletedUnrecoverable()
+			}
+			return
+		}
+		retryLocal(ctx, q, d, localBackoff(opts.RetryDelay, d.Attempts), fields)
+	}
+}
+
+// localBackoff doubles delay for each attempt after the first.
+func localBackoff(delay time.Duration, attempts int) time.Duration {
+	for i := 1; i < attempts && delay < maxLocalRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxLocalRetryDelay)
+}
+
+func ackLocal(ctx context.Context, q queue.LocalQueue, d queue.Delivery, fields map[string]any) bool {
+	if err := q.Ack(ctx, d); err != nil {
+		telemetry.ErrorContext(ctx, "worker.local.ack_failed", withError(fields, err))
+		return false
+	}
+	return true
+}
+
+func retryLocal(ctx context.Context, q queue.LocalQueue, d queue.Delivery, delay time.Duration, fields map[string]any) {
+	if err := q.Retry(ctx, d, delay); err != nil {
+		// The message reappears once its visibility timeout ends.
+		telemetry.ErrorContext(ctx, "worker.local.retry_failed", withError(fields, err))
+	}
+}
+
+func withError(fields map[string]any, err error) map[string]any {
+	out := make(map[string]any, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out["error"] = err.Error()
+	return out
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
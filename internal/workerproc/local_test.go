@@ -0,0 +1,102 @@
+package workerproc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/queue"
+)
+
+type countingProcessor struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	failures int
+	done     chan string
+}
+
+func (p *countingProcessor) ProcessAnalysis(ctx context.Context, analysisID string) error {
+	p.mu.Lock()
+	p.calls[analysisID]++
+	n := p.calls[analysisID]
+	p.mu.Unlock()
+	if n <= p.failures {
+		if n == p.failures {
+			defer func() { p.done <- analysisID }()
+		}
+		return errors.New("llm unavailable")
+	}
+	p.done <- analysisID
+	return nil
+}
+
+func TestConsumeLocalRetriesUntilTheJobSucceeds(t *testing.T) {
+	processor := &countingProcessor{calls: map[string]int{}, failures: 2, done: make(chan string, 8)}
+	app := &bootstrap.App{AnalysisProcessor: processor}
+	q := queue.NewMemoryQueue(8)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		ConsumeLocal(ctx, app, q, LocalOptions{Concurrency: 2, RetryDelay: time.Millisecond, MaxAttempts: 5})
+		close(stopped)
+	}()
+
+	if err := q.Send(ctx, queue.Message{AnalysisID: "a1"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-processor.done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("the job was not retried")
+		}
+	}
+	cancel()
+	<-stopped
+	if got := processor.calls["a1"]; got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+func TestConsumeLocalDropsAJobAfterMaxAttempts(t *testing.T) {
+	processor := &countingProcessor{calls: map[string]int{}, failures: 2, done: make(chan string, 8)}
+	app := &bootstrap.App{AnalysisProcessor: processor}
+	q := queue.NewMemoryQueue(8)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		ConsumeLocal(ctx, app, q, LocalOptions{RetryDelay: time.Millisecond, MaxAttempts: 2})
+		close(stopped)
+	}()
+
+	if err := q.Send(ctx, queue.Message{AnalysisID: "a1"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	select {
+	case <-processor.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the job was not retried")
+	}
+	// Give a wrongly scheduled third attempt time to show up.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-stopped
+	if got := processor.calls["a1"]; got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+func TestLocalBackoffDoublesUpToTheCap(t *testing.T) {
+	if got := localBackoff(time.Second, 1); got != time.Second {
+		t.Fatalf("first retry = %s", got)
+	}
+	if got := localBackoff(time.Second, 3); got != 4*time.Second {
+		t.Fatalf("third retry = %s", got)
+	}
+	if got := localBackoff(time.Minute, 20); got != maxLocalRetryDelay {
+		t.Fatalf("capped retry = %s", got)
+	}
+}
@@ -0,0 +1,81 @@
+package workerproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"resume-backend/internal/bootstrap"
+	"resume-backend/internal/documents"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/telemetry"
+)
+
+// UploadEvents says which S3 objects HandleUploadEvent registers.
+type UploadEvents struct {
+	// Bucket, when set, ignores events from any other bucket.
+	Bucket string
+	// Prefix is the uploads prefix presigned keys start with.
+	Prefix string
+}
+
+// HandleUploadEvent processes an S3 event notification for the uploads bucket.
+// Each object created under the prefix is registered as a document, its text
+// is extracted and the document is marked ready, so clients that upload with a
+// presigned URL need not call POST /documents/from-s3. Objects outside the
+// prefix, or rejected as too large or of an unsupported type, are logged and
+// skipped. An extraction failure is returned so the message is redelivered.
+func HandleUploadEvent(ctx context.Context, app *bootstrap.App, events UploadEvents, body string) error {
+	if app == nil || app.DocumentsService == nil || app.DocumentExtractor == nil {
+		return errors.New("document services not configured")
+	}
+	if strings.TrimSpace(body) == "" {
+		return ErrEmptyBody{Meta: ComputeMeta(body)}
+	}
+	uploads, err := documents.ParseS3Event([]byte(body))
+	if err != nil {
+		return ErrDecode{Meta: ComputeMeta(body), Err: err}
+	}
+
+	for _, upload := range uploads {
+		fields := map[string]any{"bucket": upload.Bucket, "key": upload.Key, "size_bytes": upload.Size}
+		if events.Bucket != "" && upload.Bucket != events.Bucket {
+			telemetry.InfoContext(ctx, "worker.upload.skipped", withReason(fields, "other_bucket"))
+			continue
+		}
+		key, ok := documents.ParseUploadKey(events.Prefix, upload.Key)
+		if !ok {
+			telemetry.InfoContext(ctx, "worker.upload.skipped", withReason(fields, "unrecognized_key"))
+			continue
+		}
+		fields["document_id"] = key.DocumentID
+
+		docCtx := ctxmeta.WithDataOwner(ctx, key.UserID)
+		doc, created, err := app.DocumentsService.RegisterS3Upload(docCtx, upload, key)
+		if errors.Is(err, documents.ErrInvalidInput) || errors.Is(err, documents.ErrTooLarge) {
+			fields["error"] = err.Error()
+			telemetry.ErrorContext(docCtx, "worker.upload.rejected", fields)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("register upload %s: %w", upload.Key, err)
+		}
+		fields["created"] = created
+		telemetry.InfoContext(docCtx, "worker.upload.registered", fields)
+
+		if doc.Status() == documents.StatusReady {
+			continue
+		}
+		if _, err := app.DocumentExtractor.ExtractDocument(docCtx, key.UserID, key.DocumentID); err != nil {
+			return fmt.Errorf("extract upload %s: %w", upload.Key, err)
+		}
+		telemetry.InfoContext(docCtx, "worker.upload.ready", fields)
+	}
+	return nil
+}
+
+func withReason(fields map[string]any, reason string) map[string]any {
+	fields["reason"] = reason
+	return fields
+}
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"strings"
 
-	"resume-backend/internal/analyses"
 	"resume-backend/internal/bootstrap"
 	"resume-backend/internal/queue"
+	"resume-backend/internal/shared/ctxmeta"
+	"resume-backend/internal/shared/metrics"
+	"resume-backend/internal/shared/telemetry"
 )
 
 // MessageMeta captures details useful for logging and diagnostics.
@@ -69,6 +71,17 @@ func (e ErrProcess) Error() string {
 	return "process analysis: " + e.Err.Error()
 }
 
+// ErrDuplicate indicates a delivery of a message another worker claimed within
+// the dedup window. The message must be left on the queue, not deleted: the
+// claiming worker deletes it on success, and it comes back for a retry if that
+// worker fails.
+type ErrDuplicate struct {
+	AnalysisID string
+	RequestID  string
+}
+
+func (e ErrDuplicate) Error() string { return "duplicate delivery" }
+
 // ParseMessage validates and decodes the queue payload.
 func ParseMessage(body string) (queue.Message, MessageMeta, error) {
 	meta := ComputeMeta(body)
@@ -101,8 +114,26 @@ func parsedMessageFromContext(ctx context.Context) (queue.Message, bool) {
 	return msg, ok
 }
 
-// HandleMessage parses, validates, and processes a message payload.
-func HandleMessage(ctx context.Context, app *bootstrap.App, body string) error {
+type messageIDKey struct{}
+
+// WithMessageID stores the queue's message ID in the context, enabling
+// deduplication of re-deliveries in HandleMessage.
+func WithMessageID(ctx context.Context, messageID string) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, messageID)
+}
+
+func messageIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(messageIDKey{}).(string)
+	return id
+}
+
+// HandleMessage parses, validates, and processes a message payload. With a
+// message ID in the context and app.Dedup set, a delivery claimed within the
+// dedup window returns ErrDuplicate without doing any work.
+func HandleMessage(ctx context.Context, app *bootstrap.App, body string) (err error) {
 	if app == nil {
 		return errors.New("analysis service not configured")
 	}
@@ -116,7 +147,6 @@ func HandleMessage(ctx context.Context, app *bootstrap.App, body string) error {
 
 	msg, ok := parsedMessageFromContext(ctx)
 	if !ok {
-		var err error
 		msg, _, err = ParseMessage(body)
 		if err != nil {
 			return err
@@ -127,9 +157,60 @@ func HandleMessage(ctx context.Context, app *bootstrap.App, body string) error {
 		return ErrMissingAnalysisID{Meta: ComputeMeta(body), RequestID: msg.RequestID}
 	}
 
-	ctxWithRequest := analyses.WithRequestID(ctx, msg.RequestID)
+	ctxWithRequest := ctxmeta.WithAnalysisID(ctxmeta.WithRequestID(ctx, msg.RequestID), msg.AnalysisID)
+	release, duplicate := claimDelivery(ctxWithRequest, app.Dedup, msg)
+	if duplicate {
+		return ErrDuplicate{AnalysisID: msg.AnalysisID, RequestID: msg.RequestID}
+	}
+	if release != nil {
+		defer func() {
+			if err != nil {
+				release()
+			}
+		}()
+	}
+	if msg.Stage == queue.StageExtract {
+		if app.AnalysisExtractor == nil {
+			return errors.New("analysis extractor not configured")
+		}
+		if err := app.AnalysisExtractor.ExtractForAnalysis(ctxWithRequest, msg.AnalysisID); err != nil {
+			return ErrProcess{AnalysisID: msg.AnalysisID, RequestID: msg.RequestID, Err: err}
+		}
+		return nil
+	}
 	if err := processor.ProcessAnalysis(ctxWithRequest, msg.AnalysisID); err != nil {
 		return ErrProcess{AnalysisID: msg.AnalysisID, RequestID: msg.RequestID, Err: err}
 	}
 	return nil
 }
+
+// claimDelivery claims the delivery in dedup. It reports a duplicate when the
+// message was already claimed, and otherwise returns a func that releases the
+// claim so a failed attempt can be retried. Dedup errors are logged and the
+// message is processed as if dedup were off.
+func claimDelivery(ctx context.Context, dedup queue.Deduper, msg queue.Message) (func(), bool) {
+	messageID := messageIDFromContext(ctx)
+	if dedup == nil || messageID == "" {
+		return nil, false
+	}
+	key := queue.DedupKey{MessageID: messageID, AnalysisID: msg.AnalysisID}
+	fields := map[string]any{"message_id": messageID, "analysis_id": msg.AnalysisID, "request_id": msg.RequestID}
+	claimed, err := dedup.Claim(ctx, key)
+	if err != nil {
+		fields["error"] = err.Error()
+		telemetry.ErrorContext(ctx, "worker.dedup.claim_failed", fields)
+		return nil, false
+	}
+	if !claimed {
+		metrics.IncAnalysisJobsDuplicateSkipped()
+		telemetry.InfoContext(ctx, "worker.analysis.duplicate_skipped", fields)
+		return nil, true
+	}
+	return func() {
+		// The request context may be done by now; the release must still land.
+		if err := dedup.Release(context.WithoutCancel(ctx), key); err != nil {
+			fields["error"] = err.Error()
+			telemetry.ErrorContext(ctx, "worker.dedup.release_failed", fields)
+		}
+	}, false
+}
@@ -4,3 +4,12 @@ import _ "embed"
 
 //go:embed resume_to_model.txt
 var ResumeToModel string
+
+//go:embed jd_keywords.txt
+var JDKeywords string
+
+//go:embed learning_plan.txt
+var LearningPlan string
+
+//go:embed quantify_bullet.txt
+var QuantifyBullet string
@@ -73,7 +73,7 @@ func applyPlaceholders(resume *model.ResumeModel, missing []string) {
 		resume.Header.Phone = PlaceholderPhone
 	}
 	if _, ok := missingSet["header.linkedin"]; ok {
-		resume.Header.Links = append(resume.Header.Links, PlaceholderLinkedIn)
+		resume.Header.Links = append(resume.Header.Links, model.ResumeLink{URL: PlaceholderLinkedIn})
 	}
 	if _, ok := missingSet["skills"]; ok {
 		resume.Skills = model.ResumeSkills{Tools: []string{PlaceholderSkills}}
@@ -100,9 +100,9 @@ func hasValue(value string) bool {
 	return !strings.HasPrefix(strings.ToUpper(trimmed), PlaceholderPrefix)
 }
 
-func hasLinkedIn(links []string) bool {
+func hasLinkedIn(links []model.ResumeLink) bool {
 	for _, link := range links {
-		trimmed := strings.TrimSpace(link)
+		trimmed := strings.TrimSpace(link.URL)
 		if trimmed == "" {
 			continue
 		}
@@ -0,0 +1,263 @@
+package export
+
+import (
+	"net/url"
+	"strings"
+
+	"resume-backend/resume/model"
+)
+
+// HROpenSchemaVersion is the HR Open Standards Recruiting release whose
+// Candidate JSON ToHROpen follows.
+const HROpenSchemaVersion = "4.3"
+
+// HROpenCandidate is an HR Open Standards Candidate document.
+type HROpenCandidate struct {
+	DocumentID    HROpenIdentifier   `json:"documentId"`
+	SchemaVersion string             `json:"schemaVersion"`
+	Person        HROpenPerson       `json:"person"`
+	Profiles      []HROpenProfile    `json:"profiles"`
+	Assessments   []HROpenAssessment `json:"assessments"`
+}
+
+// HROpenIdentifier identifies a document in the issuing system.
+type HROpenIdentifier struct {
+	Value    string `json:"value"`
+	SchemeID string `json:"schemeId,omitempty"`
+}
+
+// HROpenPerson holds identity and contact details.
+type HROpenPerson struct {
+	Name          HROpenName          `json:"name"`
+	Communication HROpenCommunication `json:"communication"`
+}
+
+// HROpenName is a person's name. Given and Family are only set when the
+// formatted name has at least two words.
+type HROpenName struct {
+	FormattedName string `json:"formattedName"`
+	Given         string `json:"given,omitempty"`
+	Family        string `json:"family,omitempty"`
+}
+
+// HROpenCommunication lists the ways to reach a person.
+type HROpenCommunication struct {
+	Email   []HROpenEmail   `json:"email"`
+	Phone   []HROpenPhone   `json:"phone"`
+	Web     []HROpenWeb     `json:"web"`
+	Address []HROpenAddress `json:"address"`
+}
+
+// HROpenEmail is an email address.
+type HROpenEmail struct {
+	Address string `json:"address"`
+}
+
+// HROpenPhone is a phone number as written on the resume.
+type HROpenPhone struct {
+	FormattedNumber string `json:"formattedNumber"`
+}
+
+// HROpenWeb is a website or network profile.
+type HROpenWeb struct {
+	URL  string `json:"url"`
+	Name string `json:"name,omitempty"`
+}
+
+// HROpenAddress is a postal location.
+type HROpenAddress struct {
+	FormattedAddress string `json:"formattedAddress"`
+	City             string `json:"city,omitempty"`
+}
+
+// HROpenProfile is the candidate's career history and qualifications.
+type HROpenProfile struct {
+	ExecutiveSummary string                `json:"executiveSummary,omitempty"`
+	Employment       []HROpenEmployment    `json:"employment"`
+	Education        []HROpenEducation     `json:"education"`
+	Certifications   []HROpenCertification `json:"certifications"`
+	Qualifications   []HROpenQualification `json:"qualifications"`
+}
+
+// HROpenOrganization names an employer, school or issuer.
+type HROpenOrganization struct {
+	Name string `json:"name"`
+}
+
+// HROpenEmployment is one employer and the positions held there.
+type HROpenEmployment struct {
+	Organization      HROpenOrganization `json:"organization"`
+	PositionHistories []HROpenPosition   `json:"positionHistories"`
+}
+
+// HROpenPosition is a position held at an employer.
+type HROpenPosition struct {
+	Title        string   `json:"title,omitempty"`
+	Location     string   `json:"location,omitempty"`
+	Start        string   `json:"start,omitempty"`
+	End          string   `json:"end,omitempty"`
+	Current      bool     `json:"current"`
+	Descriptions []string `json:"descriptions"`
+}
+
+// HROpenEducation is attendance at a school.
+type HROpenEducation struct {
+	Institution      HROpenOrganization `json:"institution"`
+	EducationDegrees []HROpenDegree     `json:"educationDegrees"`
+	Start            string             `json:"start,omitempty"`
+	End              string             `json:"end,omitempty"`
+	Current          bool               `json:"current"`
+	Descriptions     []string           `json:"descriptions"`
+}
+
+// HROpenDegree is a degree and its field of study.
+type HROpenDegree struct {
+	Name            string   `json:"name,omitempty"`
+	Specializations []string `json:"specializations"`
+}
+
+// HROpenCertification is a certification or license.
+type HROpenCertification struct {
+	Name                string              `json:"name"`
+	IssuingAuthority    *HROpenOrganization `json:"issuingAuthority,omitempty"`
+	EffectiveTimePeriod *HROpenPeriod       `json:"effectiveTimePeriod,omitempty"`
+}
+
+// HROpenPeriod bounds when something is valid.
+type HROpenPeriod struct {
+	ValidFrom string `json:"validFrom,omitempty"`
+	ValidTo   string `json:"validTo,omitempty"`
+}
+
+// HROpenQualification is a skill, grouped by category.
+type HROpenQualification struct {
+	CompetencyName string `json:"competencyName"`
+	Category       string `json:"category,omitempty"`
+}
+
+// HROpenAssessment is the outcome of an analysis of the resume.
+type HROpenAssessment struct {
+	ID              HROpenIdentifier `json:"id"`
+	Type            string           `json:"type"`
+	Scores          []HROpenScore    `json:"scores"`
+	Summary         string           `json:"summary,omitempty"`
+	MissingKeywords []string         `json:"missingKeywords"`
+	CompletedDate   string           `json:"completedDate,omitempty"`
+}
+
+// HROpenScore is a score on a 0-100 scale.
+type HROpenScore struct {
+	Type    string  `json:"type"`
+	Value   float64 `json:"value"`
+	Minimum float64 `json:"minimum"`
+	Maximum float64 `json:"maximum"`
+}
+
+// ToHROpen maps a ResumeModel onto an HR Open Standards Candidate, with the
+// given assessments attached. Placeholder values ("TO-FILL: ...") are dropped.
+// Projects and achievements have no place in the Candidate profile and are
+// left out; they are still in the JSON Resume export.
+func ToHROpen(m model.ResumeModel, documentID string, assessments []HROpenAssessment) HROpenCandidate {
+	if assessments == nil {
+		assessments = []HROpenAssessment{}
+	}
+	profile := HROpenProfile{
+		ExecutiveSummary: strings.Join(cleanList(m.Summary), " "),
+		Employment:       make([]HROpenEmployment, 0, len(m.Experience)),
+		Education:        make([]HROpenEducation, 0, len(m.Education)),
+		Certifications:   make([]HROpenCertification, 0, len(m.Certifications)),
+		Qualifications:   toQualifications(m.Skills),
+	}
+	for _, exp := range m.Experience {
+		profile.Employment = append(profile.Employment, HROpenEmployment{
+			Organization: HROpenOrganization{Name: clean(exp.Company)},
+			PositionHistories: []HROpenPosition{{
+				Title:        clean(exp.Role),
+				Location:     clean(exp.Location),
+				Start:        toDate(exp.Start),
+				End:          toDate(exp.End),
+				Current:      isPresent(exp.End),
+				Descriptions: cleanList(exp.Highlights),
+			}},
+		})
+	}
+	for _, edu := range m.Education {
+		degree := HROpenDegree{Name: clean(edu.Degree), Specializations: cleanList([]string{edu.Field})}
+		profile.Education = append(profile.Education, HROpenEducation{
+			Institution:      HROpenOrganization{Name: clean(edu.Institution)},
+			EducationDegrees: []HROpenDegree{degree},
+			Start:            toDate(edu.Start),
+			End:              toDate(edu.End),
+			Current:          isPresent(edu.End),
+			Descriptions:     cleanList(edu.Highlights),
+		})
+	}
+	for _, cert := range m.Certifications {
+		out := HROpenCertification{Name: clean(cert.Name)}
+		if issuer := clean(cert.Issuer); issuer != "" {
+			out.IssuingAuthority = &HROpenOrganization{Name: issuer}
+		}
+		if from, to := toDate(cert.Date), toDate(cert.Expires); from != "" || to != "" {
+			out.EffectiveTimePeriod = &HROpenPeriod{ValidFrom: from, ValidTo: to}
+		}
+		profile.Certifications = append(profile.Certifications, out)
+	}
+
+	return HROpenCandidate{
+		DocumentID:    HROpenIdentifier{Value: documentID, SchemeID: "resume-analyzer"},
+		SchemaVersion: HROpenSchemaVersion,
+		Person:        toHROpenPerson(m.Header),
+		Profiles:      []HROpenProfile{profile},
+		Assessments:   assessments,
+	}
+}
+
+func toHROpenPerson(header model.ResumeHeader) HROpenPerson {
+	person := HROpenPerson{
+		Name: HROpenName{FormattedName: clean(header.Name)},
+		Communication: HROpenCommunication{
+			Email:   []HROpenEmail{},
+			Phone:   []HROpenPhone{},
+			Web:     []HROpenWeb{},
+			Address: []HROpenAddress{},
+		},
+	}
+	if words := strings.Fields(person.Name.FormattedName); len(words) >= 2 {
+		person.Name.Given = words[0]
+		person.Name.Family = words[len(words)-1]
+	}
+	if email := clean(header.Email); email != "" {
+		person.Communication.Email = append(person.Communication.Email, HROpenEmail{Address: email})
+	}
+	if phone := clean(header.Phone); phone != "" {
+		person.Communication.Phone = append(person.Communication.Phone, HROpenPhone{FormattedNumber: phone})
+	}
+	if loc := clean(header.Location); loc != "" {
+		person.Communication.Address = append(person.Communication.Address, HROpenAddress{
+			FormattedAddress: loc,
+			City:             toLocation(loc).City,
+		})
+	}
+	for _, link := range cleanList(model.LinkURLs(header.Links)) {
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		person.Communication.Web = append(person.Communication.Web, HROpenWeb{URL: link, Name: networkForHost(parsed.Host)})
+	}
+	return person
+}
+
+func toQualifications(skills model.ResumeSkills) []HROpenQualification {
+	out := []HROpenQualification{}
+	for _, group := range toSkills(skills) {
+		for _, keyword := range group.Keywords {
+			out = append(out, HROpenQualification{CompetencyName: keyword, Category: group.Name})
+		}
+	}
+	return out
+}
+
+func isPresent(value string) bool {
+	return strings.EqualFold(strings.TrimSpace(value), "Present")
+}
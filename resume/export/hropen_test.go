@@ -0,0 +1,77 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"resume-backend/resume/model"
+)
+
+func TestToHROpenMapsCandidate(t *testing.T) {
+	m := model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:     "Jane Q Doe",
+			Email:    "jane@example.com",
+			Phone:    "TO-FILL: phone",
+			Location: "Austin, TX",
+			Links:    model.LinksFromURLs("https://www.linkedin.com/in/janedoe/", "https://janedoe.dev"),
+		},
+		Summary: []string{"Builds APIs."},
+		Skills:  model.ResumeSkills{Languages: []string{"Go"}, CloudDevOps: []string{"AWS"}},
+		Experience: []model.ResumeExperience{
+			{Company: "Acme", Role: "Engineer", Start: "2020-01", End: "Present", Highlights: []string{"Cut latency 40%"}},
+		},
+		Education: []model.ResumeEducation{
+			{Institution: "State U", Degree: "BSc", Field: "CS", End: "2019-05"},
+		},
+		Certifications: []model.ResumeCertification{
+			{Name: "CKA", Issuer: "CNCF", Date: "2022-03", Expires: "2025-03"},
+		},
+	}
+	assessment := HROpenAssessment{ID: HROpenIdentifier{Value: "a1"}, Type: "ATS", Scores: []HROpenScore{{Type: "ats", Value: 72, Maximum: 100}}}
+
+	out := ToHROpen(m, "doc-1", []HROpenAssessment{assessment})
+
+	if out.DocumentID.Value != "doc-1" || out.SchemaVersion != HROpenSchemaVersion {
+		t.Fatalf("unexpected document id: %+v", out.DocumentID)
+	}
+	name := out.Person.Name
+	if name.FormattedName != "Jane Q Doe" || name.Given != "Jane" || name.Family != "Doe" {
+		t.Fatalf("unexpected name: %+v", name)
+	}
+	comm := out.Person.Communication
+	if len(comm.Email) != 1 || len(comm.Phone) != 0 || len(comm.Web) != 2 || comm.Web[0].Name != "LinkedIn" {
+		t.Fatalf("unexpected communication: %+v", comm)
+	}
+	if len(comm.Address) != 1 || comm.Address[0].City != "Austin" {
+		t.Fatalf("unexpected address: %+v", comm.Address)
+	}
+	profile := out.Profiles[0]
+	position := profile.Employment[0].PositionHistories[0]
+	if profile.Employment[0].Organization.Name != "Acme" || !position.Current || position.End != "" || position.Start != "2020-01" {
+		t.Fatalf("unexpected employment: %+v", profile.Employment)
+	}
+	if degree := profile.Education[0].EducationDegrees[0]; degree.Name != "BSc" || len(degree.Specializations) != 1 {
+		t.Fatalf("unexpected education: %+v", profile.Education)
+	}
+	cert := profile.Certifications[0]
+	if cert.IssuingAuthority == nil || cert.EffectiveTimePeriod == nil || cert.EffectiveTimePeriod.ValidTo != "2025-03" {
+		t.Fatalf("unexpected certification: %+v", cert)
+	}
+	if len(profile.Qualifications) != 2 || profile.Qualifications[1].Category != "Cloud & DevOps" {
+		t.Fatalf("unexpected qualifications: %+v", profile.Qualifications)
+	}
+	if len(out.Assessments) != 1 || out.Assessments[0].Scores[0].Value != 72 {
+		t.Fatalf("unexpected assessments: %+v", out.Assessments)
+	}
+
+	payload, err := json.Marshal(ToHROpen(model.ResumeModel{}, "doc-2", nil))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var empty map[string]any
+	_ = json.Unmarshal(payload, &empty)
+	if _, ok := empty["assessments"].([]any); !ok {
+		t.Fatalf("expected empty lists rather than null, got %s", payload)
+	}
+}
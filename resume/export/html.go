@@ -0,0 +1,229 @@
+package export
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"resume-backend/resume/model"
+)
+
+// HTMLContentType is the media type of ToHTML output.
+const HTMLContentType = "text/html; charset=utf-8"
+
+//go:embed templates/email.html.tmpl
+var htmlTemplates embed.FS
+
+var emailTemplate = template.Must(template.ParseFS(htmlTemplates, "templates/email.html.tmpl"))
+
+type htmlResume struct {
+	Name     string
+	Title    string
+	Contact  []htmlContact
+	Sections []htmlSection
+}
+
+type htmlContact struct {
+	Text string
+	// URL links the contact when set.
+	URL string
+}
+
+type htmlSection struct {
+	Heading    string
+	Paragraphs []string
+	Entries    []htmlEntry
+}
+
+type htmlEntry struct {
+	Title       string
+	Subtitle    string
+	Dates       string
+	Description string
+	Highlights  []string
+}
+
+// ToHTML renders a ResumeModel as a standalone HTML document that survives
+// being pasted into email bodies and application text boxes: tables for
+// layout, inline styles only, web-safe fonts, no scripts or images.
+// Placeholder values ("TO-FILL: ...") and empty sections are left out.
+func ToHTML(m model.ResumeModel) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := emailTemplate.Execute(&buf, toHTMLResume(m)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toHTMLResume(m model.ResumeModel) htmlResume {
+	out := htmlResume{
+		Name:  clean(m.Header.Name),
+		Title: clean(m.Header.Title),
+	}
+	if email := clean(m.Header.Email); email != "" {
+		out.Contact = append(out.Contact, htmlContact{Text: email, URL: "mailto:" + email})
+	}
+	for _, value := range []string{m.Header.Phone, m.Header.Location} {
+		if v := clean(value); v != "" {
+			out.Contact = append(out.Contact, htmlContact{Text: v})
+		}
+	}
+	for _, link := range m.Header.Links {
+		href := clean(link.URL)
+		if !isWebURL(href) {
+			continue
+		}
+		text := clean(link.Label)
+		if text == "" {
+			text = href
+		}
+		out.Contact = append(out.Contact, htmlContact{Text: text, URL: href})
+	}
+
+	sections := []htmlSection{
+		{Heading: "Summary", Paragraphs: cleanList(m.Summary)},
+		{Heading: "Experience", Entries: experienceEntries(m.Experience)},
+		{Heading: "Projects", Entries: projectEntries(m.Projects)},
+		{Heading: "Education", Entries: educationEntries(m.Education)},
+		{Heading: "Skills", Paragraphs: skillParagraphs(m.Skills)},
+		{Heading: "Certifications", Entries: certificationEntries(m.Certifications)},
+		{Heading: "Achievements", Entries: achievementEntries(m.Achievements)},
+	}
+	for _, section := range sections {
+		if len(section.Paragraphs) > 0 || len(section.Entries) > 0 {
+			out.Sections = append(out.Sections, section)
+		}
+	}
+	return out
+}
+
+func experienceEntries(items []model.ResumeExperience) []htmlEntry {
+	out := make([]htmlEntry, 0, len(items))
+	for _, exp := range items {
+		entry := htmlEntry{
+			Title:      clean(exp.Role),
+			Subtitle:   joinNonEmpty(" · ", clean(exp.Company), clean(exp.Location)),
+			Dates:      dateRange(exp.Start, exp.End),
+			Highlights: cleanList(exp.Highlights),
+		}
+		if entry.Title == "" {
+			entry.Title, entry.Subtitle = entry.Subtitle, ""
+		}
+		out = appendEntry(out, entry)
+	}
+	return out
+}
+
+func projectEntries(items []model.ResumeProject) []htmlEntry {
+	out := make([]htmlEntry, 0, len(items))
+	for _, project := range items {
+		out = appendEntry(out, htmlEntry{
+			Title:       clean(project.Name),
+			Dates:       dateRange(project.Start, project.End),
+			Description: clean(project.Description),
+			Highlights:  cleanList(project.Highlights),
+		})
+	}
+	return out
+}
+
+func educationEntries(items []model.ResumeEducation) []htmlEntry {
+	out := make([]htmlEntry, 0, len(items))
+	for _, edu := range items {
+		entry := htmlEntry{
+			Title:      joinNonEmpty(" in ", clean(edu.Degree), clean(edu.Field)),
+			Subtitle:   joinNonEmpty(" · ", clean(edu.Institution), clean(edu.Location)),
+			Dates:      dateRange(edu.Start, edu.End),
+			Highlights: cleanList(edu.Highlights),
+		}
+		if entry.Title == "" {
+			entry.Title, entry.Subtitle = entry.Subtitle, ""
+		}
+		out = appendEntry(out, entry)
+	}
+	return out
+}
+
+func certificationEntries(items []model.ResumeCertification) []htmlEntry {
+	out := make([]htmlEntry, 0, len(items))
+	for _, cert := range items {
+		out = appendEntry(out, htmlEntry{
+			Title:    clean(cert.Name),
+			Subtitle: clean(cert.Issuer),
+			Dates:    formatMonth(cert.Date),
+		})
+	}
+	return out
+}
+
+func achievementEntries(items []model.ResumeAchievement) []htmlEntry {
+	out := make([]htmlEntry, 0, len(items))
+	for _, achievement := range items {
+		out = appendEntry(out, htmlEntry{
+			Title:      clean(achievement.Title),
+			Dates:      formatMonth(achievement.Date),
+			Highlights: cleanList(achievement.Highlights),
+		})
+	}
+	return out
+}
+
+// appendEntry skips entries left with nothing to show once placeholders are
+// dropped.
+func appendEntry(out []htmlEntry, entry htmlEntry) []htmlEntry {
+	if entry.Title == "" && entry.Description == "" && len(entry.Highlights) == 0 {
+		return out
+	}
+	return append(out, entry)
+}
+
+// skillParagraphs writes one line per skill group. Apply runs put their
+// display lines in Tools alone, which are written as they are.
+func skillParagraphs(skills model.ResumeSkills) []string {
+	groups := toSkills(skills)
+	if len(groups) == 1 && groups[0].Name == "Tools" {
+		return groups[0].Keywords
+	}
+	out := make([]string, 0, len(groups))
+	for _, group := range groups {
+		out = append(out, group.Name+": "+strings.Join(group.Keywords, ", "))
+	}
+	return out
+}
+
+func isWebURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+var monthAbbrev = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+// formatMonth turns a YYYY-MM date into "Jan 2021"; other values are kept.
+func formatMonth(value string) string {
+	value = clean(value)
+	year, month, ok := strings.Cut(value, "-")
+	if !ok || len(year) != 4 || len(month) != 2 {
+		return value
+	}
+	idx := int(month[0]-'0')*10 + int(month[1]-'0') - 1
+	if idx < 0 || idx >= len(monthAbbrev) {
+		return value
+	}
+	return monthAbbrev[idx] + " " + year
+}
+
+func dateRange(start, end string) string {
+	return joinNonEmpty(" – ", formatMonth(start), formatMonth(end))
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, sep)
+}
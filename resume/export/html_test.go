@@ -0,0 +1,65 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"resume-backend/resume/model"
+)
+
+func TestToHTMLIsInlineStyledAndEscaped(t *testing.T) {
+	m := model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:  "Jane <Doe>",
+			Title: "Backend Engineer",
+			Email: "jane@example.com",
+			Phone: "TO-FILL: Phone",
+			Links: []model.ResumeLink{
+				{Label: "LinkedIn", URL: "https://www.linkedin.com/in/janedoe"},
+				{URL: "javascript:alert(1)"},
+			},
+		},
+		Summary: []string{"Builds APIs & ships often."},
+		Skills:  model.ResumeSkills{Tools: []string{"Go, SQL, AWS"}},
+		Experience: []model.ResumeExperience{
+			{Company: "Acme", Role: "Engineer", Location: "Austin", Start: "2020-01", End: "Present", Highlights: []string{"Cut latency 40%"}},
+		},
+		Education: []model.ResumeEducation{
+			{Institution: "TO-FILL: University", Degree: "TO-FILL: Degree"},
+		},
+	}
+
+	out, err := ToHTML(m)
+	if err != nil {
+		t.Fatalf("ToHTML failed: %v", err)
+	}
+	html := string(out)
+
+	for _, want := range []string{
+		"Jane &lt;Doe&gt;",
+		`<a href="mailto:jane@example.com"`,
+		`<a href="https://www.linkedin.com/in/janedoe"`,
+		">LinkedIn</a>",
+		"Builds APIs &amp; ships often.",
+		"Jan 2020 – Present",
+		"Acme · Austin",
+		"<li style=",
+		"Go, SQL, AWS",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in output:\n%s", want, html)
+		}
+	}
+	for _, unwanted := range []string{"<style", "class=", "<script", "javascript:", "TO-FILL", ">Education<"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("unexpected %q in output:\n%s", unwanted, html)
+		}
+	}
+}
+
+func TestSkillParagraphsLabelsGroups(t *testing.T) {
+	got := skillParagraphs(model.ResumeSkills{Languages: []string{"Go", "SQL"}, Tools: []string{"Git"}})
+	if len(got) != 2 || got[0] != "Languages: Go, SQL" || got[1] != "Tools: Git" {
+		t.Fatalf("skill paragraphs = %q", got)
+	}
+}
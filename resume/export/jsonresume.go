@@ -0,0 +1,293 @@
+package export
+
+import (
+	"net/url"
+	"strings"
+
+	"resume-backend/resume/model"
+)
+
+// JSONResumeSchemaURL identifies the JSON Resume schema version emitted by ToJSONResume.
+const JSONResumeSchemaURL = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// JSONResume is a JSON Resume (https://jsonresume.org/schema) document.
+type JSONResume struct {
+	Schema       string                  `json:"$schema"`
+	Basics       JSONResumeBasics        `json:"basics"`
+	Work         []JSONResumeWork        `json:"work"`
+	Education    []JSONResumeEducation   `json:"education"`
+	Awards       []JSONResumeAward       `json:"awards"`
+	Certificates []JSONResumeCertificate `json:"certificates"`
+	Skills       []JSONResumeSkill       `json:"skills"`
+	Projects     []JSONResumeProject     `json:"projects"`
+	Meta         JSONResumeMeta          `json:"meta"`
+}
+
+// JSONResumeBasics holds identity and contact details.
+type JSONResumeBasics struct {
+	Name     string              `json:"name"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles"`
+}
+
+// JSONResumeLocation is the structured location block.
+type JSONResumeLocation struct {
+	Address string `json:"address,omitempty"`
+	City    string `json:"city,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+// JSONResumeProfile is a social or professional network profile.
+type JSONResumeProfile struct {
+	Network  string `json:"network"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url"`
+}
+
+// JSONResumeWork is a work history entry.
+type JSONResumeWork struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Highlights []string `json:"highlights"`
+}
+
+// JSONResumeEducation is an education entry.
+type JSONResumeEducation struct {
+	Institution string   `json:"institution"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Courses     []string `json:"courses"`
+}
+
+// JSONResumeAward is an award or achievement.
+type JSONResumeAward struct {
+	Title   string `json:"title"`
+	Date    string `json:"date,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// JSONResumeCertificate is a certification entry.
+type JSONResumeCertificate struct {
+	Name   string `json:"name"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// JSONResumeSkill groups keywords under a skill name.
+type JSONResumeSkill struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+}
+
+// JSONResumeProject is a notable project.
+type JSONResumeProject struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Highlights  []string `json:"highlights"`
+}
+
+// JSONResumeMeta carries document metadata.
+type JSONResumeMeta struct {
+	Version string `json:"version"`
+}
+
+// ToJSONResume maps a ResumeModel onto the JSON Resume v1.0.0 schema.
+// Placeholder values ("TO-FILL: ...") are dropped rather than exported.
+func ToJSONResume(m model.ResumeModel) JSONResume {
+	out := JSONResume{
+		Schema:       JSONResumeSchemaURL,
+		Basics:       toBasics(m.Header, m.Summary),
+		Work:         make([]JSONResumeWork, 0, len(m.Experience)),
+		Education:    make([]JSONResumeEducation, 0, len(m.Education)),
+		Awards:       make([]JSONResumeAward, 0, len(m.Achievements)),
+		Certificates: make([]JSONResumeCertificate, 0, len(m.Certifications)),
+		Skills:       toSkills(m.Skills),
+		Projects:     make([]JSONResumeProject, 0, len(m.Projects)),
+		Meta:         JSONResumeMeta{Version: "v1.0.0"},
+	}
+
+	for _, exp := range m.Experience {
+		out.Work = append(out.Work, JSONResumeWork{
+			Name:       clean(exp.Company),
+			Position:   clean(exp.Role),
+			Location:   clean(exp.Location),
+			StartDate:  toDate(exp.Start),
+			EndDate:    toDate(exp.End),
+			Highlights: cleanList(exp.Highlights),
+		})
+	}
+	for _, edu := range m.Education {
+		out.Education = append(out.Education, JSONResumeEducation{
+			Institution: clean(edu.Institution),
+			Area:        clean(edu.Field),
+			StudyType:   clean(edu.Degree),
+			StartDate:   toDate(edu.Start),
+			EndDate:     toDate(edu.End),
+			Courses:     cleanList(edu.Highlights),
+		})
+	}
+	for _, achievement := range m.Achievements {
+		out.Awards = append(out.Awards, JSONResumeAward{
+			Title:   clean(achievement.Title),
+			Date:    toDate(achievement.Date),
+			Summary: strings.Join(cleanList(achievement.Highlights), " "),
+		})
+	}
+	for _, cert := range m.Certifications {
+		out.Certificates = append(out.Certificates, JSONResumeCertificate{
+			Name:   clean(cert.Name),
+			Date:   toDate(cert.Date),
+			Issuer: clean(cert.Issuer),
+		})
+	}
+	for _, project := range m.Projects {
+		out.Projects = append(out.Projects, JSONResumeProject{
+			Name:        clean(project.Name),
+			Description: clean(project.Description),
+			StartDate:   toDate(project.Start),
+			EndDate:     toDate(project.End),
+			Highlights:  cleanList(project.Highlights),
+		})
+	}
+	return out
+}
+
+func toBasics(header model.ResumeHeader, summary []string) JSONResumeBasics {
+	basics := JSONResumeBasics{
+		Name:     clean(header.Name),
+		Label:    clean(header.Title),
+		Email:    clean(header.Email),
+		Phone:    clean(header.Phone),
+		Summary:  strings.Join(cleanList(summary), " "),
+		Profiles: []JSONResumeProfile{},
+	}
+	if loc := clean(header.Location); loc != "" {
+		basics.Location = toLocation(loc)
+	}
+	for _, link := range cleanList(model.LinkURLs(header.Links)) {
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		network := networkForHost(parsed.Host)
+		if network == "" {
+			if basics.URL == "" {
+				basics.URL = link
+				continue
+			}
+			network = strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+		}
+		basics.Profiles = append(basics.Profiles, JSONResumeProfile{
+			Network:  network,
+			Username: usernameFromPath(parsed.Path),
+			URL:      link,
+		})
+	}
+	return basics
+}
+
+func toLocation(loc string) *JSONResumeLocation {
+	out := &JSONResumeLocation{Address: loc}
+	if idx := strings.LastIndex(loc, ","); idx > 0 {
+		out.City = strings.TrimSpace(loc[:idx])
+		out.Region = strings.TrimSpace(loc[idx+1:])
+	} else {
+		out.City = loc
+	}
+	return out
+}
+
+var knownNetworks = map[string]string{
+	"linkedin.com":      "LinkedIn",
+	"github.com":        "GitHub",
+	"gitlab.com":        "GitLab",
+	"twitter.com":       "Twitter",
+	"x.com":             "X",
+	"stackoverflow.com": "Stack Overflow",
+	"medium.com":        "Medium",
+	"dribbble.com":      "Dribbble",
+	"behance.net":       "Behance",
+}
+
+func networkForHost(host string) string {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for domain, name := range knownNetworks {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return name
+		}
+	}
+	return ""
+}
+
+func usernameFromPath(p string) string {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if part := strings.TrimSpace(parts[i]); part != "" {
+			return strings.TrimPrefix(part, "@")
+		}
+	}
+	return ""
+}
+
+func toSkills(skills model.ResumeSkills) []JSONResumeSkill {
+	groups := []struct {
+		name  string
+		items []string
+	}{
+		{"Languages", skills.Languages},
+		{"Frameworks", skills.Frameworks},
+		{"Databases", skills.Databases},
+		{"Cloud & DevOps", skills.CloudDevOps},
+		{"Observability", skills.Observability},
+		{"Tools", skills.Tools},
+	}
+	out := make([]JSONResumeSkill, 0, len(groups))
+	for _, group := range groups {
+		keywords := cleanList(group.items)
+		if len(keywords) == 0 {
+			continue
+		}
+		out = append(out, JSONResumeSkill{Name: group.name, Keywords: keywords})
+	}
+	return out
+}
+
+// toDate converts YYYY-MM dates to ISO 8601; "Present" and placeholders become empty,
+// which JSON Resume treats as an ongoing or unknown date.
+func toDate(value string) string {
+	value = clean(value)
+	if strings.EqualFold(value, "Present") {
+		return ""
+	}
+	return value
+}
+
+func clean(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToUpper(value), "TO-FILL:") {
+		return ""
+	}
+	return value
+}
+
+func cleanList(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if v := clean(value); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"resume-backend/resume/model"
+)
+
+func TestToJSONResumeMapsSections(t *testing.T) {
+	m := model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:     "Jane Doe",
+			Title:    "Backend Engineer",
+			Email:    "jane@example.com",
+			Phone:    "TO-FILL: phone",
+			Location: "Austin, TX",
+			Links: model.LinksFromURLs(
+				"https://www.linkedin.com/in/janedoe/",
+				"https://janedoe.dev",
+				"https://github.com/janedoe",
+			),
+		},
+		Summary: []string{"Builds APIs.", "Ships often."},
+		Skills: model.ResumeSkills{
+			Languages:   []string{"Go", "SQL"},
+			CloudDevOps: []string{"AWS"},
+		},
+		Experience: []model.ResumeExperience{
+			{Company: "Acme", Role: "Engineer", Start: "2020-01", End: "Present", Highlights: []string{"Cut latency 40%"}},
+		},
+		Education: []model.ResumeEducation{
+			{Institution: "State U", Degree: "BSc", Field: "CS", End: "2019-05"},
+		},
+		Certifications: []model.ResumeCertification{
+			{Name: "CKA", Issuer: "CNCF", Date: "2022-03"},
+		},
+	}
+
+	out := ToJSONResume(m)
+
+	if out.Basics.Name != "Jane Doe" || out.Basics.Label != "Backend Engineer" {
+		t.Fatalf("unexpected basics: %+v", out.Basics)
+	}
+	if out.Basics.Phone != "" {
+		t.Fatalf("expected placeholder phone to be dropped, got %q", out.Basics.Phone)
+	}
+	if out.Basics.Summary != "Builds APIs. Ships often." {
+		t.Fatalf("unexpected summary: %q", out.Basics.Summary)
+	}
+	if out.Basics.URL != "https://janedoe.dev" {
+		t.Fatalf("expected personal site as url, got %q", out.Basics.URL)
+	}
+	if len(out.Basics.Profiles) != 2 || out.Basics.Profiles[0].Network != "LinkedIn" || out.Basics.Profiles[0].Username != "janedoe" {
+		t.Fatalf("unexpected profiles: %+v", out.Basics.Profiles)
+	}
+	if out.Basics.Location == nil || out.Basics.Location.City != "Austin" || out.Basics.Location.Region != "TX" {
+		t.Fatalf("unexpected location: %+v", out.Basics.Location)
+	}
+	if len(out.Work) != 1 || out.Work[0].StartDate != "2020-01" || out.Work[0].EndDate != "" {
+		t.Fatalf("unexpected work: %+v", out.Work)
+	}
+	if len(out.Education) != 1 || out.Education[0].StudyType != "BSc" || out.Education[0].Area != "CS" {
+		t.Fatalf("unexpected education: %+v", out.Education)
+	}
+	if len(out.Skills) != 2 || out.Skills[1].Name != "Cloud & DevOps" {
+		t.Fatalf("unexpected skills: %+v", out.Skills)
+	}
+	if len(out.Certificates) != 1 || out.Certificates[0].Issuer != "CNCF" {
+		t.Fatalf("unexpected certificates: %+v", out.Certificates)
+	}
+}
+
+func TestToJSONResumeEmitsEmptyArrays(t *testing.T) {
+	payload, err := json.Marshal(ToJSONResume(model.ResumeModel{Header: model.ResumeHeader{Name: "A"}}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, key := range []string{"work", "education", "awards", "certificates", "skills", "projects"} {
+		if _, ok := decoded[key].([]any); !ok {
+			t.Fatalf("expected %s to be an array, got %T", key, decoded[key])
+		}
+	}
+	if decoded["$schema"] != JSONResumeSchemaURL {
+		t.Fatalf("unexpected schema: %v", decoded["$schema"])
+	}
+}
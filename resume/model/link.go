@@ -0,0 +1,61 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ResumeLink is a header link such as a LinkedIn profile or portfolio.
+type ResumeLink struct {
+	Label string `json:"label,omitempty"`
+	URL   string `json:"url"`
+}
+
+// UnmarshalJSON accepts either a {"label","url"} object or a plain URL string,
+// which is how links were stored before they carried labels.
+func (l *ResumeLink) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '"' {
+		var url string
+		if err := json.Unmarshal(trimmed, &url); err != nil {
+			return err
+		}
+		*l = ResumeLink{URL: url}
+		return nil
+	}
+	type plain ResumeLink
+	var decoded plain
+	if err := json.Unmarshal(trimmed, &decoded); err != nil {
+		return err
+	}
+	*l = ResumeLink(decoded)
+	return nil
+}
+
+// Text is how the link reads in a document: "Label: URL", or just the URL.
+func (l ResumeLink) Text() string {
+	url := strings.TrimSpace(l.URL)
+	if label := strings.TrimSpace(l.Label); label != "" && url != "" {
+		return label + ": " + url
+	}
+	return url
+}
+
+// LinksFromURLs wraps unlabelled URLs.
+func LinksFromURLs(urls ...string) []ResumeLink {
+	out := make([]ResumeLink, 0, len(urls))
+	for _, url := range urls {
+		out = append(out, ResumeLink{URL: url})
+	}
+	return out
+}
+
+// LinkURLs returns the URLs of links, in order.
+func LinkURLs(links []ResumeLink) []string {
+	out := make([]string, 0, len(links))
+	for _, link := range links {
+		out = append(out, link.URL)
+	}
+	return out
+}
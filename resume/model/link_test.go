@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestResumeLinkAcceptsStringsAndObjects(t *testing.T) {
+	var header ResumeHeader
+	payload := `{"name":"Ada","links":["https://github.com/ada",{"label":"LinkedIn","url":"https://linkedin.com/in/ada"}]}`
+	if err := json.Unmarshal([]byte(payload), &header); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := []ResumeLink{
+		{URL: "https://github.com/ada"},
+		{Label: "LinkedIn", URL: "https://linkedin.com/in/ada"},
+	}
+	if len(header.Links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), header.Links)
+	}
+	for i := range want {
+		if header.Links[i] != want[i] {
+			t.Fatalf("link %d: expected %+v, got %+v", i, want[i], header.Links[i])
+		}
+	}
+	if got := header.Links[1].Text(); got != "LinkedIn: https://linkedin.com/in/ada" {
+		t.Fatalf("unexpected text %q", got)
+	}
+
+	out, err := json.Marshal(header.Links)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != `[{"url":"https://github.com/ada"},{"label":"LinkedIn","url":"https://linkedin.com/in/ada"}]` {
+		t.Fatalf("unexpected json %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`[42]`), &header.Links); err == nil {
+		t.Fatalf("expected an error for a non-string, non-object link")
+	}
+}
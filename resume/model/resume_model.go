@@ -29,7 +29,7 @@ func (m ResumeModel) Validate() error {
 		return errors.New("sensitive fields like nationality or maritalStatus are not allowed")
 	}
 	for i, link := range m.Header.Links {
-		if !isFullURL(strings.TrimSpace(link)) {
+		if !isFullURL(strings.TrimSpace(link.URL)) {
 			return fmt.Errorf("links[%d] must be a full URL", i)
 		}
 	}
@@ -75,14 +75,14 @@ func (m ResumeModel) Validate() error {
 
 // ResumeHeader captures top-of-resume contact and identity details.
 type ResumeHeader struct {
-	Name          string   `json:"name"`
-	Title         string   `json:"title"`
-	Email         string   `json:"email"`
-	Phone         string   `json:"phone"`
-	Location      string   `json:"location"`
-	Links         []string `json:"links"`
-	Nationality   string   `json:"nationality,omitempty"`
-	MaritalStatus string   `json:"maritalStatus,omitempty"`
+	Name          string       `json:"name"`
+	Title         string       `json:"title"`
+	Email         string       `json:"email"`
+	Phone         string       `json:"phone"`
+	Location      string       `json:"location"`
+	Links         []ResumeLink `json:"links"`
+	Nationality   string       `json:"nationality,omitempty"`
+	MaritalStatus string       `json:"maritalStatus,omitempty"`
 }
 
 // ResumeSkills groups skills by category.
@@ -0,0 +1,248 @@
+package render
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"resume-backend/resume/model"
+)
+
+// DefaultTemplateID identifies the built-in template every render uses.
+const DefaultTemplateID = "resume_modern_ats_v1"
+
+// SectionHighlights is the highlights list under each experience entry. It
+// has no heading of its own, so the headings dictionaries do not name it.
+const SectionHighlights = "highlights"
+
+// sectionOrder lists every section in the order warnings are reported.
+var sectionOrder = []string{
+	SectionSummary,
+	SectionSkills,
+	SectionExperience,
+	SectionHighlights,
+	SectionProjects,
+	SectionEducation,
+	SectionCertifications,
+	SectionAwards,
+}
+
+// sectionLoops maps the {{#NAME}} loops to the section each renders. No loop
+// renders projects yet, so every template drops them.
+var sectionLoops = map[string]string{
+	"SUMMARY":        SectionSummary,
+	"SKILLS":         SectionSkills,
+	"EXPERIENCE":     SectionExperience,
+	"HIGHLIGHTS":     SectionHighlights,
+	"EDUCATION":      SectionEducation,
+	"CERTIFICATIONS": SectionCertifications,
+	"AWARDS":         SectionAwards,
+}
+
+// Codes of the warnings EnforceCapabilities reports.
+const (
+	WarningSectionUnsupported  = "section_unsupported"
+	WarningHighlightsTruncated = "highlights_truncated"
+)
+
+// Capabilities describes what a template can render. Sections lists the
+// sections it has loops for; MaxHighlightsPerRole caps the highlights shown
+// under each experience entry, with zero meaning no cap.
+type Capabilities struct {
+	Sections             []string `json:"sections"`
+	MaxHighlightsPerRole int      `json:"maxHighlightsPerRole,omitempty"`
+}
+
+// Supports reports whether the template renders section.
+func (c Capabilities) Supports(section string) bool {
+	for _, s := range c.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentWarning reports resume content a template could not render.
+type ContentWarning struct {
+	Code    string `json:"code"`
+	Section string `json:"section"`
+	// Dropped counts the entries left out of the document.
+	Dropped int    `json:"dropped"`
+	Message string `json:"message"`
+}
+
+// CapabilitiesFromTokens derives a template's capabilities from the tokens
+// ValidateTemplate found in it.
+func CapabilitiesFromTokens(tokens []string, maxHighlightsPerRole int) Capabilities {
+	found := map[string]bool{}
+	for _, token := range tokens {
+		name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(token, "{{"), "}}"))
+		if !strings.HasPrefix(name, "#") {
+			continue
+		}
+		if section, ok := sectionLoops[name[1:]]; ok {
+			found[section] = true
+		}
+	}
+	// Highlights only render inside an experience entry.
+	if !found[SectionExperience] {
+		delete(found, SectionHighlights)
+	}
+	caps := Capabilities{Sections: []string{}}
+	for _, section := range sectionOrder {
+		if found[section] {
+			caps.Sections = append(caps.Sections, section)
+		}
+	}
+	if maxHighlightsPerRole > 0 && found[SectionHighlights] {
+		caps.MaxHighlightsPerRole = maxHighlightsPerRole
+	}
+	return caps
+}
+
+var (
+	capabilitiesMu    sync.RWMutex
+	capabilitiesCache = map[string]Capabilities{}
+)
+
+// DefaultCapabilities returns the capabilities of the built-in template.
+func DefaultCapabilities() (Capabilities, error) {
+	return templatePathCapabilities(defaultTemplatePath)
+}
+
+func templatePathCapabilities(templatePath string) (Capabilities, error) {
+	key := filepath.Clean(templatePath)
+	capabilitiesMu.RLock()
+	cached, ok := capabilitiesCache[key]
+	capabilitiesMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	reader, err := loadTemplate(key)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	caps, err := zipCapabilities(reader)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	capabilitiesMu.Lock()
+	capabilitiesCache[key] = caps
+	capabilitiesMu.Unlock()
+	return caps, nil
+}
+
+func zipCapabilities(reader *zip.Reader) (Capabilities, error) {
+	for _, file := range reader.File {
+		if normalizeZipName(file.Name) != "word/document.xml" {
+			continue
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return Capabilities{}, err
+		}
+		root, _, err := parseXMLDocument(string(content))
+		if err != nil {
+			return Capabilities{}, fmt.Errorf("parse template document: %w", err)
+		}
+		var tokens []string
+		walkXML(root, func(n *xmlNode) bool {
+			if isElement(n, "p") {
+				tokens = append(tokens, tokenPattern.FindAllString(paragraphText(n), -1)...)
+			}
+			return true
+		})
+		return CapabilitiesFromTokens(tokens, 0), nil
+	}
+	return Capabilities{}, errors.New("template has no word/document.xml")
+}
+
+// EnforceCapabilities returns resume with the content caps cannot render
+// removed, and a warning for each section that lost content. resume itself is
+// not modified.
+func EnforceCapabilities(resume model.ResumeModel, caps Capabilities) (model.ResumeModel, []ContentWarning) {
+	var warnings []ContentWarning
+	drop := func(section string, count int) bool {
+		if count == 0 || caps.Supports(section) {
+			return false
+		}
+		warnings = append(warnings, ContentWarning{
+			Code:    WarningSectionUnsupported,
+			Section: section,
+			Dropped: count,
+			Message: fmt.Sprintf("the template has no %s section; %d %s left out", section, count, entries(count)),
+		})
+		return true
+	}
+
+	if drop(SectionSummary, len(resume.Summary)) {
+		resume.Summary = nil
+	}
+	if drop(SectionSkills, len(flattenSkills(resume.Skills))) {
+		resume.Skills = model.ResumeSkills{}
+	}
+	if drop(SectionExperience, len(resume.Experience)) {
+		resume.Experience = nil
+	}
+	highlights := 0
+	for _, exp := range resume.Experience {
+		highlights += len(exp.Highlights)
+	}
+	if drop(SectionHighlights, highlights) {
+		resume.Experience = trimHighlights(resume.Experience, 0)
+	} else if limit := caps.MaxHighlightsPerRole; limit > 0 {
+		truncated := 0
+		for _, exp := range resume.Experience {
+			if len(exp.Highlights) > limit {
+				truncated += len(exp.Highlights) - limit
+			}
+		}
+		if truncated > 0 {
+			resume.Experience = trimHighlights(resume.Experience, limit)
+			warnings = append(warnings, ContentWarning{
+				Code:    WarningHighlightsTruncated,
+				Section: SectionHighlights,
+				Dropped: truncated,
+				Message: fmt.Sprintf("the template shows at most %d highlights per role; %d %s left out", limit, truncated, entries(truncated)),
+			})
+		}
+	}
+	if drop(SectionProjects, len(resume.Projects)) {
+		resume.Projects = nil
+	}
+	if drop(SectionEducation, len(resume.Education)) {
+		resume.Education = nil
+	}
+	if drop(SectionCertifications, len(resume.Certifications)) {
+		resume.Certifications = nil
+	}
+	if drop(SectionAwards, len(resume.Achievements)) {
+		resume.Achievements = nil
+	}
+	return resume, warnings
+}
+
+// trimHighlights copies experience with at most limit highlights per entry.
+func trimHighlights(experience []model.ResumeExperience, limit int) []model.ResumeExperience {
+	out := make([]model.ResumeExperience, len(experience))
+	for i, exp := range experience {
+		if len(exp.Highlights) > limit {
+			exp.Highlights = exp.Highlights[:limit:limit]
+		}
+		out[i] = exp
+	}
+	return out
+}
+
+func entries(count int) string {
+	if count == 1 {
+		return "entry"
+	}
+	return "entries"
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"resume-backend/resume/model"
+)
+
+func TestCapabilitiesFromTokens(t *testing.T) {
+	caps := CapabilitiesFromTokens([]string{"{{#SUMMARY}}", "{{SUMMARY_ITEM}}", "{{/SUMMARY}}", "{{#HIGHLIGHTS}}", "{{#EDUCATION}}"}, 3)
+	if want := []string{SectionSummary, SectionEducation}; !reflect.DeepEqual(caps.Sections, want) {
+		t.Fatalf("sections = %v, want %v", caps.Sections, want)
+	}
+	// Highlights without an experience loop never render, so no cap applies.
+	if caps.MaxHighlightsPerRole != 0 {
+		t.Fatalf("expected no highlight cap without highlights, got %d", caps.MaxHighlightsPerRole)
+	}
+
+	caps = CapabilitiesFromTokens([]string{"{{#EXPERIENCE}}", "{{#HIGHLIGHTS}}"}, 3)
+	if !caps.Supports(SectionHighlights) || caps.MaxHighlightsPerRole != 3 {
+		t.Fatalf("unexpected capabilities %+v", caps)
+	}
+}
+
+func TestTemplateCapabilitiesReadsLoops(t *testing.T) {
+	caps, err := templatePathCapabilities("../../assets/templates/" + DefaultTemplateID + ".docx")
+	if err != nil {
+		t.Fatalf("capabilities: %v", err)
+	}
+	want := []string{SectionSummary, SectionSkills, SectionExperience, SectionHighlights, SectionEducation, SectionCertifications, SectionAwards}
+	if !reflect.DeepEqual(caps.Sections, want) {
+		t.Fatalf("sections = %v, want %v", caps.Sections, want)
+	}
+}
+
+func TestEnforceCapabilitiesWarnsAboutDroppedContent(t *testing.T) {
+	resume := SampleResume()
+	resume.Experience[0].Highlights = []string{"one", "two", "three"}
+	resume.Projects = []model.ResumeProject{{Name: "Engine"}}
+	caps := Capabilities{
+		Sections:             []string{SectionSummary, SectionSkills, SectionExperience, SectionHighlights, SectionEducation},
+		MaxHighlightsPerRole: 2,
+	}
+
+	out, warnings := EnforceCapabilities(resume, caps)
+	got := map[string]ContentWarning{}
+	for _, w := range warnings {
+		got[w.Section] = w
+	}
+	if w := got[SectionHighlights]; w.Code != WarningHighlightsTruncated || w.Dropped != 1 {
+		t.Fatalf("unexpected highlights warning %+v", w)
+	}
+	for _, section := range []string{SectionProjects, SectionCertifications, SectionAwards} {
+		if w := got[section]; w.Code != WarningSectionUnsupported || w.Dropped == 0 {
+			t.Fatalf("expected %s to be reported as dropped, got %+v", section, w)
+		}
+	}
+	if len(warnings) != 4 {
+		t.Fatalf("expected 4 warnings, got %+v", warnings)
+	}
+	if len(out.Experience[0].Highlights) != 2 || out.Projects != nil || out.Certifications != nil || out.Achievements != nil {
+		t.Fatalf("expected unsupported content to be removed, got %+v", out)
+	}
+	if len(resume.Experience[0].Highlights) != 3 {
+		t.Fatalf("expected the input resume to be left alone")
+	}
+
+	if _, warnings := EnforceCapabilities(SampleResume(), CapabilitiesFromTokens(sampleLoopTokens(), 0)); len(warnings) != 0 {
+		t.Fatalf("expected no warnings when every section is supported, got %+v", warnings)
+	}
+}
+
+func sampleLoopTokens() []string {
+	var tokens []string
+	for loop := range sectionLoops {
+		tokens = append(tokens, "{{#"+loop+"}}")
+	}
+	return tokens
+}
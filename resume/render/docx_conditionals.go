@@ -0,0 +1,238 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"resume-backend/resume/model"
+)
+
+// ErrUnknownFlag is returned when a template or RenderOptions names a flag the
+// renderer does not know.
+var ErrUnknownFlag = errors.New("unknown template flag")
+
+// Option flags are set by the caller through RenderOptions. They default to
+// false.
+const (
+	FlagOpenToRelocation    = "OPEN_TO_RELOCATION"
+	FlagOpenToRemote        = "OPEN_TO_REMOTE"
+	FlagReferencesAvailable = "REFERENCES_AVAILABLE"
+)
+
+var optionFlags = map[string]bool{
+	FlagOpenToRelocation:    true,
+	FlagOpenToRemote:        true,
+	FlagReferencesAvailable: true,
+}
+
+// modelFlags are derived from the ResumeModel being rendered and cannot be set
+// through RenderOptions.
+var modelFlags = map[string]func(model.ResumeModel) bool{
+	"HAS_TITLE":          func(r model.ResumeModel) bool { return hasText(r.Header.Title) },
+	"HAS_EMAIL":          func(r model.ResumeModel) bool { return hasText(r.Header.Email) },
+	"HAS_PHONE":          func(r model.ResumeModel) bool { return hasText(r.Header.Phone) },
+	"HAS_LOCATION":       func(r model.ResumeModel) bool { return hasText(r.Header.Location) },
+	"HAS_LINKS":          func(r model.ResumeModel) bool { return len(r.Header.Links) > 0 },
+	"HAS_SUMMARY":        func(r model.ResumeModel) bool { return len(r.Summary) > 0 },
+	"HAS_SKILLS":         func(r model.ResumeModel) bool { return len(flattenSkills(r.Skills)) > 0 },
+	"HAS_EXPERIENCE":     func(r model.ResumeModel) bool { return len(r.Experience) > 0 },
+	"HAS_EDUCATION":      func(r model.ResumeModel) bool { return len(r.Education) > 0 },
+	"HAS_CERTIFICATIONS": func(r model.ResumeModel) bool { return len(r.Certifications) > 0 },
+	"HAS_AWARDS":         func(r model.ResumeModel) bool { return len(r.Achievements) > 0 },
+}
+
+// RenderOptions tunes a render. Flags sets option flags for {{?FLAG}} sections;
+// only the Flag* constants are accepted. Locale selects the section headings,
+// such as "es" or "de-AT"; empty renders DefaultLocale.
+type RenderOptions struct {
+	Flags  map[string]bool
+	Locale string
+}
+
+// TemplateFlags lists every flag a template may test, sorted.
+func TemplateFlags() []string {
+	out := make([]string, 0, len(modelFlags)+len(optionFlags))
+	for name := range modelFlags {
+		out = append(out, name)
+	}
+	for name := range optionFlags {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func isTemplateFlag(name string) bool {
+	return optionFlags[name] || modelFlags[name] != nil
+}
+
+// resolveFlags evaluates every flag for a render. Unknown option flags fail
+// the render rather than being silently ignored.
+func resolveFlags(resume model.ResumeModel, opts RenderOptions) (map[string]bool, error) {
+	flags := make(map[string]bool, len(modelFlags)+len(optionFlags))
+	for name, eval := range modelFlags {
+		flags[name] = eval(resume)
+	}
+	for name := range optionFlags {
+		flags[name] = false
+	}
+	for name, value := range opts.Flags {
+		if !optionFlags[name] {
+			if modelFlags[name] != nil {
+				return nil, fmt.Errorf("%w: %s is derived from the resume and cannot be set", ErrUnknownFlag, name)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFlag, name)
+		}
+		flags[name] = value
+	}
+	return flags, nil
+}
+
+func hasText(value string) bool {
+	return strings.TrimSpace(value) != ""
+}
+
+var conditionalPattern = regexp.MustCompile(`{{\?([^{}]*)}}`)
+
+// evaluateConditionals resolves {{?FLAG}}...{{/FLAG}} sections before loops
+// are expanded. A section that opens and closes in one paragraph keeps or drops
+// the text between its markers; one that spans paragraphs, tables or loops
+// keeps or drops every block from the opening paragraph to the closing one,
+// including any other text in those two paragraphs.
+// Markers are only plain text, so no expression is ever evaluated.
+func evaluateConditionals(body *xmlNode, flags map[string]bool) error {
+	if body == nil {
+		return nil
+	}
+	var unknown error
+	walkXML(body, func(n *xmlNode) bool {
+		if unknown != nil || !isElement(n, "p") {
+			return unknown == nil
+		}
+		for _, match := range conditionalPattern.FindAllStringSubmatch(paragraphText(n), -1) {
+			if !isTemplateFlag(match[1]) {
+				unknown = fmt.Errorf("%w: %s", ErrUnknownFlag, match[0])
+				return false
+			}
+		}
+		return true
+	})
+	if unknown != nil {
+		return unknown
+	}
+
+	walkXML(body, func(n *xmlNode) bool {
+		if isElement(n, "p") {
+			evaluateInlineConditionals(n, flags)
+		}
+		return true
+	})
+	return evaluateBlockConditionals(body, flags)
+}
+
+// evaluateInlineConditionals resolves sections that close in the paragraph
+// they open in, innermost first.
+func evaluateInlineConditionals(p *xmlNode, flags map[string]bool) {
+	text := paragraphText(p)
+	updated := text
+	for {
+		matches := conditionalPattern.FindAllStringSubmatchIndex(updated, -1)
+		resolved := false
+		for i := len(matches) - 1; i >= 0; i-- {
+			m := matches[i]
+			name := updated[m[2]:m[3]]
+			endTag := "{{/" + name + "}}"
+			end := strings.Index(updated[m[1]:], endTag)
+			if end == -1 {
+				continue
+			}
+			end += m[1]
+			inner := ""
+			if flags[name] {
+				inner = updated[m[1]:end]
+			}
+			updated = updated[:m[0]] + inner + updated[end+len(endTag):]
+			resolved = true
+			break
+		}
+		if !resolved {
+			break
+		}
+	}
+	if updated == text {
+		return
+	}
+	textNodes := collectTextElements(p)
+	setNodeText(textNodes[0], updated)
+	for i := 1; i < len(textNodes); i++ {
+		setNodeText(textNodes[i], "")
+	}
+}
+
+// evaluateBlockConditionals resolves sections whose markers sit in sibling
+// blocks of container, then descends into the blocks that remain.
+func evaluateBlockConditionals(container *xmlNode, flags map[string]bool) error {
+	if container == nil || container.IsText {
+		return nil
+	}
+	container.Children = mergeAdjacentTextNodes(container.Children)
+	for {
+		startIdx, startTag, name := -1, "", ""
+		for idx, child := range container.Children {
+			if match := conditionalPattern.FindStringSubmatch(nodeTextContent(child)); match != nil {
+				startIdx, startTag, name = idx, match[0], match[1]
+				break
+			}
+		}
+		if startIdx == -1 {
+			break
+		}
+		endTag := "{{/" + name + "}}"
+		endIdx := -1
+		for idx := startIdx + 1; idx < len(container.Children); idx++ {
+			if strings.Contains(nodeTextContent(container.Children[idx]), endTag) {
+				endIdx = idx
+				break
+			}
+		}
+		if endIdx == -1 {
+			return fmt.Errorf("conditional %s is never closed; its end must be in the same paragraph or a sibling block", startTag)
+		}
+
+		newChildren := make([]*xmlNode, 0, len(container.Children))
+		newChildren = append(newChildren, container.Children[:startIdx]...)
+		if flags[name] {
+			if keep := removeConditionalMarker(container.Children[startIdx], startTag); keep != nil {
+				newChildren = append(newChildren, keep)
+			}
+			newChildren = append(newChildren, container.Children[startIdx+1:endIdx]...)
+			if keep := removeConditionalMarker(container.Children[endIdx], endTag); keep != nil {
+				newChildren = append(newChildren, keep)
+			}
+		}
+		newChildren = append(newChildren, container.Children[endIdx+1:]...)
+		container.Children = newChildren
+	}
+	for _, child := range container.Children {
+		if isElement(child, "p") {
+			continue
+		}
+		if err := evaluateBlockConditionals(child, flags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeConditionalMarker drops a marker, and its paragraph when nothing else
+// is left in it.
+func removeConditionalMarker(node *xmlNode, marker string) *xmlNode {
+	if node.IsText {
+		node.Text = strings.Replace(node.Text, marker, "", 1)
+		return node
+	}
+	return removeTokensFromNode(node, marker)
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"resume-backend/resume/model"
+)
+
+// conditionalDocument wraps paragraphs, one per line of text, in a minimal
+// document.xml.
+func conditionalDocument(paragraphs ...string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	b.WriteString(`<w:document xmlns:w="` + wmlNamespace + `"><w:body>`)
+	for _, text := range paragraphs {
+		b.WriteString(`<w:p><w:r><w:t xml:space="preserve">` + text + `</w:t></w:r></w:p>`)
+	}
+	b.WriteString(`</w:body></w:document>`)
+	return b.String()
+}
+
+func renderConditional(t *testing.T, resume model.ResumeModel, opts RenderOptions, paragraphs ...string) (string, error) {
+	t.Helper()
+	flags, err := resolveFlags(resume, opts)
+	if err != nil {
+		return "", err
+	}
+	return renderDocumentXMLWithLinks(conditionalDocument(paragraphs...), resume, nil, &renderSettings{flags: flags})
+}
+
+func TestConditionalsInline(t *testing.T) {
+	resume := redlineResume()
+	out, err := renderConditional(t, resume, RenderOptions{Flags: map[string]bool{FlagOpenToRemote: true}},
+		"{{FULL_NAME}}{{?HAS_TITLE}} | {{TITLE}}{{/HAS_TITLE}}",
+		"{{EMAIL}}{{?OPEN_TO_REMOTE}} · Open to remote{{/OPEN_TO_REMOTE}}{{?OPEN_TO_RELOCATION}} · Open to relocation{{/OPEN_TO_RELOCATION}}",
+	)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, ">Ada Lovelace<") {
+		t.Fatalf("expected the title section dropped, got %s", out)
+	}
+	if !strings.Contains(out, "ada@example.com · Open to remote<") || strings.Contains(out, "relocation") {
+		t.Fatalf("expected only the remote section kept, got %s", out)
+	}
+}
+
+func TestConditionalsBlock(t *testing.T) {
+	resume := redlineResume()
+	paragraphs := []string{
+		"{{FULL_NAME}}",
+		"{{EMAIL}}",
+		"{{?HAS_SUMMARY}}",
+		"Summary",
+		"{{#SUMMARY}}",
+		"{{SUMMARY_ITEM}}",
+		"{{/SUMMARY}}",
+		"{{/HAS_SUMMARY}}",
+		"{{?REFERENCES_AVAILABLE}}",
+		"References available on request",
+		"{{/REFERENCES_AVAILABLE}}",
+	}
+	out, err := renderConditional(t, resume, RenderOptions{}, paragraphs...)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, "Engineer focused on reliable systems.") || strings.Contains(out, "References") {
+		t.Fatalf("expected summary kept and references dropped, got %s", out)
+	}
+	if strings.Count(out, "<w:p>") != 4 {
+		t.Fatalf("expected marker paragraphs removed, got %s", out)
+	}
+
+	resume.Summary = nil
+	out, err = renderConditional(t, resume, RenderOptions{Flags: map[string]bool{FlagReferencesAvailable: true}}, paragraphs...)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(out, "Summary") || !strings.Contains(out, "References available on request") {
+		t.Fatalf("expected summary dropped and references kept, got %s", out)
+	}
+}
+
+func TestConditionalsUnknownFlagsFailFast(t *testing.T) {
+	resume := redlineResume()
+	if _, err := renderConditional(t, resume, RenderOptions{}, "{{FULL_NAME}}{{?HAS_PETS}}x{{/HAS_PETS}}"); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected ErrUnknownFlag for a template flag, got %v", err)
+	}
+	if _, err := RenderResumeWithOptions(resume, RenderOptions{Flags: map[string]bool{"OPEN_TO_MARS": true}}); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected ErrUnknownFlag for an option flag, got %v", err)
+	}
+	if _, err := RenderResumeWithOptions(resume, RenderOptions{Flags: map[string]bool{"HAS_SUMMARY": false}}); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected model flags to be read-only, got %v", err)
+	}
+	if _, err := renderConditional(t, resume, RenderOptions{}, "{{FULL_NAME}}", "{{?HAS_SUMMARY}}", "Summary"); err == nil || !strings.Contains(err.Error(), "never closed") {
+		t.Fatalf("expected unclosed conditional error, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"resume-backend/resume/model"
+)
+
+const (
+	linksToken         = "{{LINKS}}"
+	linkSeparator      = " | "
+	hyperlinkRelType   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+	hyperlinkColor     = "0563C1"
+	xmlNamespace       = "http://www.w3.org/XML/1998/namespace"
+	documentRelsPath   = "word/_rels/document.xml.rels"
+	relationshipsClose = "</Relationships>"
+)
+
+func formatLinks(links []model.ResumeLink) string {
+	out := make([]string, 0, len(links))
+	for _, link := range links {
+		if text := link.Text(); text != "" {
+			out = append(out, text)
+		}
+	}
+	return strings.Join(out, linkSeparator)
+}
+
+func contactHandle(links []model.ResumeLink) string {
+	if len(links) > 0 {
+		return links[0].URL
+	}
+	return ""
+}
+
+// hyperlinkTarget returns the URL to link to, or "" for values such as
+// placeholders that must stay plain text.
+func hyperlinkTarget(link model.ResumeLink) string {
+	raw := strings.TrimSpace(link.URL)
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ""
+	}
+	return raw
+}
+
+type hyperlinkRel struct {
+	ID     string
+	Target string
+}
+
+// hyperlinkSet hands out relationship ids for the hyperlinks added while rendering
+// and remembers them so they can be written to document.xml.rels.
+type hyperlinkSet struct {
+	rels  []hyperlinkRel
+	taken map[string]bool
+}
+
+var relationshipIDPattern = regexp.MustCompile(`\bId="([^"]+)"`)
+
+func newHyperlinkSet(relsXML string) *hyperlinkSet {
+	set := &hyperlinkSet{taken: make(map[string]bool)}
+	for _, match := range relationshipIDPattern.FindAllStringSubmatch(relsXML, -1) {
+		set.taken[match[1]] = true
+	}
+	return set
+}
+
+func (s *hyperlinkSet) add(target string) string {
+	for i := len(s.rels) + 1; ; i++ {
+		id := fmt.Sprintf("rIdLink%d", i)
+		if s.taken[id] {
+			continue
+		}
+		s.taken[id] = true
+		s.rels = append(s.rels, hyperlinkRel{ID: id, Target: target})
+		return id
+	}
+}
+
+// addHyperlinkRelationships appends external hyperlink relationships to a
+// document.xml.rels part.
+func addHyperlinkRelationships(relsXML string, rels []hyperlinkRel) (string, error) {
+	if len(rels) == 0 {
+		return relsXML, nil
+	}
+	idx := strings.LastIndex(relsXML, relationshipsClose)
+	if idx == -1 {
+		return "", fmt.Errorf("%s has no closing Relationships tag", documentRelsPath)
+	}
+	var b strings.Builder
+	b.WriteString(relsXML[:idx])
+	for _, rel := range rels {
+		var target strings.Builder
+		if err := xml.EscapeText(&target, []byte(rel.Target)); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, `<Relationship Id="%s" Type="%s" Target="%s" TargetMode="External"/>`, rel.ID, hyperlinkRelType, target.String())
+	}
+	b.WriteString(relsXML[idx:])
+	return b.String(), nil
+}
+
+// renderLinkParagraphs replaces the {{LINKS}} token with one clickable hyperlink per
+// web link. Links that are not web URLs, such as placeholders, stay plain text. The
+// token's paragraph keeps its paragraph properties and the formatting of its first run.
+func renderLinkParagraphs(root *xmlNode, links []model.ResumeLink, set *hyperlinkSet) {
+	if set == nil || !hasHyperlinkTargets(links) {
+		return
+	}
+	walkXML(root, func(n *xmlNode) bool {
+		if !isElement(n, "p") {
+			return true
+		}
+		text := paragraphText(n)
+		idx := strings.Index(text, linksToken)
+		if idx == -1 {
+			return true
+		}
+
+		rPr := firstRunProperties(n)
+		children := make([]*xmlNode, 0, len(links)*2+2)
+		for _, child := range n.Children {
+			if isElement(child, "pPr") {
+				children = append(children, child)
+			}
+		}
+		if prefix := text[:idx]; prefix != "" {
+			children = append(children, newTextRun(rPr, prefix))
+		}
+		first := true
+		for _, link := range links {
+			display := link.Text()
+			if display == "" {
+				continue
+			}
+			if !first {
+				children = append(children, newTextRun(rPr, linkSeparator))
+			}
+			first = false
+			target := hyperlinkTarget(link)
+			if target == "" {
+				children = append(children, newTextRun(rPr, display))
+				continue
+			}
+			children = append(children, &xmlNode{
+				Name: xml.Name{Space: wmlNamespace, Local: "hyperlink"},
+				Attr: []xml.Attr{
+					{Name: xml.Name{Space: relNamespace, Local: "id"}, Value: set.add(target)},
+					{Name: xml.Name{Space: wmlNamespace, Local: "history"}, Value: "1"},
+				},
+				Children: []*xmlNode{newTextRun(hyperlinkRunProperties(rPr), display)},
+			})
+		}
+		if suffix := text[idx+len(linksToken):]; suffix != "" {
+			children = append(children, newTextRun(rPr, suffix))
+		}
+		n.Children = children
+		return true
+	})
+}
+
+func hasHyperlinkTargets(links []model.ResumeLink) bool {
+	for _, link := range links {
+		if hyperlinkTarget(link) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func firstRunProperties(p *xmlNode) *xmlNode {
+	var rPr *xmlNode
+	walkXML(p, func(n *xmlNode) bool {
+		if !isElement(n, "r") {
+			return true
+		}
+		for _, child := range n.Children {
+			if isElement(child, "rPr") {
+				rPr = child
+			}
+		}
+		return false
+	})
+	return rPr
+}
+
+func newTextRun(rPr *xmlNode, text string) *xmlNode {
+	run := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "r"}}
+	if rPr != nil {
+		run.Children = append(run.Children, cloneNode(rPr))
+	}
+	run.Children = append(run.Children, &xmlNode{
+		Name:     xml.Name{Space: wmlNamespace, Local: "t"},
+		Attr:     []xml.Attr{{Name: xml.Name{Space: xmlNamespace, Local: "space"}, Value: "preserve"}},
+		Children: []*xmlNode{{IsText: true, Text: text}},
+	})
+	return run
+}
+
+// hyperlinkRunProperties styles a run as a link: blue and underlined, on top of
+// the surrounding run's formatting.
+func hyperlinkRunProperties(base *xmlNode) *xmlNode {
+	rPr := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "rPr"}}
+	if base != nil {
+		rPr = cloneNode(base)
+	}
+	setRunProperty(rPr, "color", hyperlinkColor)
+	setRunProperty(rPr, "u", "single")
+	return rPr
+}
+
+// runPropertyOrder is the element order CT_RPr requires; Word rejects run
+// properties that are out of order.
+var runPropertyOrder = map[string]int{
+	"rStyle": 0, "rFonts": 1, "b": 2, "bCs": 3, "i": 4, "iCs": 5, "caps": 6, "smallCaps": 7,
+	"strike": 8, "dstrike": 9, "outline": 10, "shadow": 11, "emboss": 12, "imprint": 13,
+	"noProof": 14, "snapToGrid": 15, "vanish": 16, "webHidden": 17, "color": 18, "spacing": 19,
+	"w": 20, "kern": 21, "position": 22, "sz": 23, "szCs": 24, "highlight": 25, "u": 26, "effect": 27,
+}
+
+func setRunProperty(rPr *xmlNode, local, value string) {
+	node := &xmlNode{
+		Name: xml.Name{Space: wmlNamespace, Local: local},
+		Attr: []xml.Attr{{Name: xml.Name{Space: wmlNamespace, Local: "val"}, Value: value}},
+	}
+	rank := runPropertyOrder[local]
+	for i, child := range rPr.Children {
+		if isElement(child, local) {
+			rPr.Children[i] = node
+			return
+		}
+		childRank, known := runPropertyOrder[child.Name.Local]
+		if !child.IsText && (!known || childRank > rank) {
+			rPr.Children = append(rPr.Children[:i], append([]*xmlNode{node}, rPr.Children[i:]...)...)
+			return
+		}
+	}
+	rPr.Children = append(rPr.Children, node)
+}
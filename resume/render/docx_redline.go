@@ -0,0 +1,404 @@
+package render
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"resume-backend/resume/model"
+)
+
+const (
+	defaultRevisionAuthor = "Resume Analyzer"
+	// maxDiffTokens bounds the word diff; longer texts are marked as one
+	// deletion followed by one insertion.
+	maxDiffTokens = 400
+)
+
+// Revision is one edit to show as a tracked change. After is the text as
+// rendered; Before is what it replaced. Lines are compared one paragraph at a
+// time.
+type Revision struct {
+	Before string
+	After  string
+}
+
+// RedlineOptions sets who and when revision marks are attributed to, and the
+// locale of the section headings as in RenderOptions.
+type RedlineOptions struct {
+	Author string
+	Date   time.Time
+	Locale string
+}
+
+// RenderResumeRedline renders a ResumeModel like RenderResume, then marks each
+// revision in the document with Word tracked changes (w:ins and w:del), so the
+// edits can be reviewed and accepted or rejected in Word.
+//
+// A revision is marked on the paragraph that renders its After text. Removals
+// with no rendered text to anchor to, such as dropped personal details, are
+// left out rather than written back into the document.
+func RenderResumeRedline(resume model.ResumeModel, revisions []Revision, opts RedlineOptions) ([]byte, error) {
+	if err := checkRenderable(resume); err != nil {
+		return nil, err
+	}
+	locale, err := NormalizeLocale(opts.Locale)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := loadTemplate(defaultTemplatePath)
+	if err != nil {
+		return nil, err
+	}
+	return renderResumeFromZip(reader, resume, &renderSettings{revisions: newRevisionMarks(revisions, opts), locale: locale})
+}
+
+// revisionMarks adds tracked-change markup for a set of revisions while a
+// document is rendered.
+type revisionMarks struct {
+	revisions []Revision
+	author    string
+	date      string
+	nextID    int
+	marked    map[*xmlNode]bool
+}
+
+func newRevisionMarks(revisions []Revision, opts RedlineOptions) *revisionMarks {
+	author := strings.TrimSpace(opts.Author)
+	if author == "" {
+		author = defaultRevisionAuthor
+	}
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return &revisionMarks{
+		revisions: revisions,
+		author:    author,
+		date:      date.UTC().Format("2006-01-02T15:04:05Z"),
+		marked:    map[*xmlNode]bool{},
+	}
+}
+
+func (m *revisionMarks) apply(body *xmlNode) {
+	if m == nil || body == nil {
+		return
+	}
+	for _, revision := range m.revisions {
+		before := revisionLines(revision.Before)
+		after := revisionLines(revision.After)
+		switch {
+		case len(after) == 0:
+			continue
+		case len(before) <= 1 && len(after) == 1:
+			m.markLine(body, strings.Join(before, ""), after[0])
+		default:
+			m.markLines(body, before, after)
+		}
+	}
+}
+
+// markLine marks a one-line rewrite on the paragraph that renders after,
+// either as its whole text or as part of a plain paragraph such as the contact
+// line.
+func (m *revisionMarks) markLine(body *xmlNode, before, after string) {
+	if before == after {
+		return
+	}
+	if p := m.findParagraph(body, func(text string) bool { return text == after }); p != nil {
+		m.replaceRuns(p, "", m.diffRuns(firstRunProperties(p), before, after), "")
+		return
+	}
+	p := m.findParagraph(body, func(text string) bool { return strings.Count(text, after) == 1 })
+	if p == nil || !plainParagraph(p) {
+		return
+	}
+	text := paragraphText(p)
+	start := strings.Index(text, after)
+	m.replaceRuns(p, text[:start], m.diffRuns(firstRunProperties(p), before, after), text[start+len(after):])
+}
+
+// markLines marks a list rendered one paragraph per line, such as skills:
+// new lines are inserted paragraphs and dropped lines are deleted paragraphs
+// placed after the last line of the list.
+func (m *revisionMarks) markLines(body *xmlNode, before, after []string) {
+	kept := map[string]bool{}
+	for _, line := range before {
+		kept[line] = true
+	}
+	var last *xmlNode
+	for _, line := range after {
+		p := m.findParagraph(body, func(text string) bool { return text == line })
+		if p == nil {
+			continue
+		}
+		m.marked[p] = true
+		last = p
+		if !kept[line] {
+			m.markParagraph(p, "ins")
+			m.replaceRuns(p, "", []*xmlNode{m.revisionRun("ins", firstRunProperties(p), line)}, "")
+		}
+	}
+	if last == nil {
+		return
+	}
+	added := map[string]bool{}
+	for _, line := range after {
+		added[line] = true
+	}
+	var removed []*xmlNode
+	for _, line := range before {
+		if added[line] {
+			continue
+		}
+		p := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "p"}}
+		if pPr := childElement(last, "pPr"); pPr != nil {
+			p.Children = append(p.Children, cloneNode(pPr))
+		}
+		m.markParagraph(p, "del")
+		p.Children = append(p.Children, m.revisionRun("del", firstRunProperties(last), line))
+		removed = append(removed, p)
+	}
+	insertAfter(body, last, removed)
+}
+
+// findParagraph returns the first paragraph not yet marked whose trimmed text
+// matches.
+func (m *revisionMarks) findParagraph(body *xmlNode, match func(string) bool) *xmlNode {
+	var found *xmlNode
+	walkXML(body, func(n *xmlNode) bool {
+		if !isElement(n, "p") {
+			return true
+		}
+		if !m.marked[n] && match(strings.TrimSpace(paragraphText(n))) {
+			found = n
+		}
+		return found == nil
+	})
+	return found
+}
+
+// replaceRuns swaps a paragraph's runs for prefix, the marked runs and suffix,
+// keeping its paragraph properties.
+func (m *revisionMarks) replaceRuns(p *xmlNode, prefix string, marked []*xmlNode, suffix string) {
+	rPr := firstRunProperties(p)
+	children := make([]*xmlNode, 0, len(marked)+3)
+	if pPr := childElement(p, "pPr"); pPr != nil {
+		children = append(children, pPr)
+	}
+	if prefix != "" {
+		children = append(children, newTextRun(rPr, prefix))
+	}
+	children = append(children, marked...)
+	if suffix != "" {
+		children = append(children, newTextRun(rPr, suffix))
+	}
+	p.Children = children
+	m.marked[p] = true
+}
+
+// diffRuns returns runs for a word-level diff of before and after: unchanged
+// text as plain runs, removed text inside w:del and added text inside w:ins.
+func (m *revisionMarks) diffRuns(rPr *xmlNode, before, after string) []*xmlNode {
+	var runs []*xmlNode
+	for _, segment := range diffWords(before, after) {
+		switch segment.op {
+		case diffEqual:
+			runs = append(runs, newTextRun(rPr, segment.text))
+		case diffDelete:
+			runs = append(runs, m.revisionRun("del", rPr, segment.text))
+		case diffInsert:
+			runs = append(runs, m.revisionRun("ins", rPr, segment.text))
+		}
+	}
+	return runs
+}
+
+// revisionRun wraps a run of text in a w:ins or w:del element. Deleted text is
+// written as w:delText, as Word requires.
+func (m *revisionMarks) revisionRun(kind string, rPr *xmlNode, text string) *xmlNode {
+	run := newTextRun(rPr, text)
+	if kind == "del" {
+		for _, child := range run.Children {
+			if isElement(child, "t") {
+				child.Name.Local = "delText"
+			}
+		}
+	}
+	mark := m.revisionElement(kind)
+	mark.Children = []*xmlNode{run}
+	return mark
+}
+
+// markParagraph marks a paragraph's own mark as inserted or deleted, so
+// accepting or rejecting the change adds or removes the whole paragraph.
+func (m *revisionMarks) markParagraph(p *xmlNode, kind string) {
+	pPr := childElement(p, "pPr")
+	if pPr == nil {
+		pPr = &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "pPr"}}
+		p.Children = append([]*xmlNode{pPr}, p.Children...)
+	}
+	rPr := childElement(pPr, "rPr")
+	if rPr == nil {
+		rPr = &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "rPr"}}
+		pPr.Children = append(pPr.Children, rPr)
+	}
+	// w:ins and w:del come first in a paragraph mark's properties. A mark copied
+	// from a neighbouring paragraph is replaced.
+	children := []*xmlNode{m.revisionElement(kind)}
+	for _, child := range rPr.Children {
+		if !isElement(child, "ins") && !isElement(child, "del") {
+			children = append(children, child)
+		}
+	}
+	rPr.Children = children
+}
+
+func (m *revisionMarks) revisionElement(kind string) *xmlNode {
+	m.nextID++
+	return &xmlNode{
+		Name: xml.Name{Space: wmlNamespace, Local: kind},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Space: wmlNamespace, Local: "id"}, Value: strconv.Itoa(m.nextID)},
+			{Name: xml.Name{Space: wmlNamespace, Local: "author"}, Value: m.author},
+			{Name: xml.Name{Space: wmlNamespace, Local: "date"}, Value: m.date},
+		},
+	}
+}
+
+// plainParagraph reports whether a paragraph holds only text runs, so its runs
+// can be rebuilt without losing hyperlinks, tabs or fields.
+func plainParagraph(p *xmlNode) bool {
+	for _, child := range p.Children {
+		switch {
+		case isElement(child, "pPr"), isElement(child, "proofErr"),
+			isElement(child, "bookmarkStart"), isElement(child, "bookmarkEnd"):
+		case isElement(child, "r"):
+			for _, part := range child.Children {
+				if !isElement(part, "rPr") && !isElement(part, "t") {
+					return false
+				}
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func childElement(node *xmlNode, local string) *xmlNode {
+	for _, child := range node.Children {
+		if isElement(child, local) {
+			return child
+		}
+	}
+	return nil
+}
+
+// insertAfter adds nodes right after target, wherever it sits below root.
+func insertAfter(root, target *xmlNode, nodes []*xmlNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	walkXML(root, func(n *xmlNode) bool {
+		for i, child := range n.Children {
+			if child != target {
+				continue
+			}
+			children := make([]*xmlNode, 0, len(n.Children)+len(nodes))
+			children = append(children, n.Children[:i+1]...)
+			children = append(children, nodes...)
+			n.Children = append(children, n.Children[i+1:]...)
+			return false
+		}
+		return true
+	})
+}
+
+func revisionLines(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffSegment struct {
+	op   diffOp
+	text string
+}
+
+var diffTokenPattern = regexp.MustCompile(`\s+|\S+\s*`)
+
+// diffWords diffs two texts word by word, keeping each word's trailing
+// whitespace with it so a changed phrase is marked as one deletion and one
+// insertion. Adjacent segments of the same kind are merged.
+func diffWords(before, after string) []diffSegment {
+	a := diffTokenPattern.FindAllString(before, -1)
+	b := diffTokenPattern.FindAllString(after, -1)
+	var segments []diffSegment
+	add := func(op diffOp, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].op == op {
+			segments[n-1].text += text
+			return
+		}
+		segments = append(segments, diffSegment{op: op, text: text})
+	}
+	if len(a) > maxDiffTokens || len(b) > maxDiffTokens {
+		add(diffDelete, before)
+		add(diffInsert, after)
+		return segments
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(diffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(diffDelete, a[i])
+			i++
+		default:
+			add(diffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(diffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(diffInsert, b[j])
+	}
+	return segments
+}
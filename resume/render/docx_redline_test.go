@@ -0,0 +1,101 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend/resume/model"
+)
+
+func redlineResume() model.ResumeModel {
+	return model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:  "Ada Lovelace",
+			Email: "ada@example.com",
+			Phone: "+44 20 7946 0958",
+		},
+		Summary: []string{"Engineer focused on reliable systems."},
+		Skills:  model.ResumeSkills{Tools: []string{"Go", "Kubernetes"}},
+		Experience: []model.ResumeExperience{{
+			Company:    "Analytical Engines Ltd",
+			Role:       "Staff Engineer",
+			Highlights: []string{"Led a team of 6 engineers to ship the billing platform."},
+		}},
+	}
+}
+
+func TestRenderRedlineMarksRewrittenBullet(t *testing.T) {
+	marks := newRevisionMarks([]Revision{{
+		Before: "Led a team to ship the billing platform.",
+		After:  "Led a team of 6 engineers to ship the billing platform.",
+	}}, RedlineOptions{Author: "Reviewer", Date: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)})
+
+	xmlText, err := renderDocumentXMLWithLinks(productionDocumentXML(t), redlineResume(), nil, &renderSettings{revisions: marks})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	for _, want := range []string{
+		`<w:ins w:id="1" w:author="Reviewer" w:date="2026-01-02T03:04:05Z">`,
+		`of 6 engineers `,
+		`Led a team `,
+	} {
+		if !strings.Contains(xmlText, want) {
+			t.Fatalf("expected %q in document.xml", want)
+		}
+	}
+	if strings.Contains(xmlText, "w:del ") {
+		t.Fatalf("expected a pure insertion, got a deletion")
+	}
+}
+
+func TestRenderRedlineMarksSkillLines(t *testing.T) {
+	marks := newRevisionMarks([]Revision{{
+		Before: "Go\nTerraform",
+		After:  "Go\nKubernetes",
+	}}, RedlineOptions{})
+
+	xmlText, err := renderDocumentXMLWithLinks(productionDocumentXML(t), redlineResume(), nil, &renderSettings{revisions: marks})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(xmlText, `w:author="Resume Analyzer"`) {
+		t.Fatalf("expected default author")
+	}
+	if !strings.Contains(xmlText, `<w:delText xml:space="preserve">Terraform</w:delText>`) {
+		t.Fatalf("expected removed skill as deleted text")
+	}
+	ins := strings.Index(xmlText, "<w:ins ")
+	if ins < 0 || !strings.Contains(xmlText[ins:], ">Kubernetes</w:t>") {
+		t.Fatalf("expected added skill inside an insertion")
+	}
+	if strings.Contains(xmlText, ">Go</w:t></w:r></w:ins>") {
+		t.Fatalf("expected unchanged skill to stay unmarked")
+	}
+}
+
+func TestRenderRedlineSkipsRemovalsWithoutAnchor(t *testing.T) {
+	marks := newRevisionMarks([]Revision{{Before: "Nationality: British"}}, RedlineOptions{})
+
+	xmlText, err := renderDocumentXMLWithLinks(productionDocumentXML(t), redlineResume(), nil, &renderSettings{revisions: marks})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if strings.Contains(xmlText, "Nationality") || strings.Contains(xmlText, "<w:del ") {
+		t.Fatalf("expected removed personal detail to stay out of the document")
+	}
+}
+
+func TestDiffWords(t *testing.T) {
+	got := diffWords("Built the API", "Built a fast API")
+	want := []diffSegment{
+		{op: diffEqual, text: "Built "},
+		{op: diffDelete, text: "the "},
+		{op: diffInsert, text: "a fast "},
+		{op: diffEqual, text: "API"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected diff: %+v", got)
+	}
+}
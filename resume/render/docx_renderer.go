@@ -7,37 +7,140 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-	"reflect"
 	"regexp"
 	"strings"
 
 	"resume-backend/resume/model"
 )
 
-const defaultTemplatePath = "assets/templates/resume_modern_ats_v1.docx"
+const defaultTemplatePath = "assets/templates/" + DefaultTemplateID + ".docx"
 
 // RenderResume renders a ResumeModel into a DOCX byte slice.
 func RenderResume(resume model.ResumeModel) ([]byte, error) {
+	return RenderResumeWithOptions(resume, RenderOptions{})
+}
+
+// RenderResumeWithOptions renders a ResumeModel like RenderResume, evaluating
+// the template's {{?FLAG}} sections against the resume and opts.
+func RenderResumeWithOptions(resume model.ResumeModel, opts RenderOptions) ([]byte, error) {
+	data, _, err := RenderResumeWithReport(resume, opts)
+	return data, err
+}
+
+// RenderResumeWithReport renders like RenderResumeWithOptions and reports the
+// content the template's capabilities left out of the document.
+func RenderResumeWithReport(resume model.ResumeModel, opts RenderOptions) ([]byte, []ContentWarning, error) {
+	if err := checkRenderable(resume); err != nil {
+		return nil, nil, err
+	}
+	flags, err := resolveFlags(resume, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	caps, err := DefaultCapabilities()
+	if err != nil {
+		return nil, nil, err
+	}
+	resume, warnings := EnforceCapabilities(resume, caps)
+	if len(warnings) > 0 {
+		// The flags derived from the resume follow what is actually rendered.
+		if flags, err = resolveFlags(resume, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+	reader, err := loadTemplate(defaultTemplatePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	locale, err := NormalizeLocale(opts.Locale)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := renderResumeFromZip(reader, resume, &renderSettings{flags: flags, locale: locale})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, warnings, nil
+}
+
+// renderSettings carries the per-render state beyond the resume itself. A nil
+// *renderSettings renders with flags derived from the resume alone.
+type renderSettings struct {
+	flags map[string]bool
+	// revisions, when set, are marked as tracked changes in the rendered
+	// document.
+	revisions *revisionMarks
+	// locale selects the section headings; empty is DefaultLocale.
+	locale string
+}
+
+func (s *renderSettings) flagsFor(resume model.ResumeModel) (map[string]bool, error) {
+	if s != nil && s.flags != nil {
+		return s.flags, nil
+	}
+	return resolveFlags(resume, RenderOptions{})
+}
+
+func (s *renderSettings) headings() sectionHeadings {
+	if s == nil {
+		return headingsFor(DefaultLocale)
+	}
+	return headingsFor(s.locale)
+}
+
+func (s *renderSettings) revisionMarks() *revisionMarks {
+	if s == nil {
+		return nil
+	}
+	return s.revisions
+}
+
+func checkRenderable(resume model.ResumeModel) error {
 	if strings.TrimSpace(resume.Header.Name) == "" {
-		return nil, errors.New("full name is required")
+		return errors.New("full name is required")
 	}
 	if strings.TrimSpace(resume.Header.Email) == "" && strings.TrimSpace(resume.Header.Phone) == "" {
-		return nil, errors.New("email or phone is required")
+		return errors.New("email or phone is required")
 	}
-	return renderResumeFromTemplate(defaultTemplatePath, resume)
+	return nil
 }
 
 func renderResumeFromTemplate(templatePath string, resume model.ResumeModel) ([]byte, error) {
-	templateBytes, err := os.ReadFile(filepath.Clean(templatePath))
+	reader, err := loadTemplate(templatePath)
 	if err != nil {
 		return nil, err
 	}
+	return renderResumeFromZip(reader, resume, nil)
+}
 
-	reader, err := zip.NewReader(bytes.NewReader(templateBytes), int64(len(templateBytes)))
-	if err != nil {
-		return nil, err
+// renderResumeFromZip renders the template in reader with settings, which may
+// be nil.
+func renderResumeFromZip(reader *zip.Reader, resume model.ResumeModel, settings *renderSettings) ([]byte, error) {
+	var err error
+	// Hyperlinks need relationships in document.xml.rels, so the document is
+	// rendered before any part is written.
+	var documentFile, relsFile *zip.File
+	for _, file := range reader.File {
+		switch normalizeZipName(file.Name) {
+		case "word/document.xml":
+			documentFile = file
+		case documentRelsPath:
+			relsFile = file
+		}
+	}
+	var relsXML []byte
+	var links *hyperlinkSet
+	if relsFile != nil {
+		if relsXML, err = readZipFile(relsFile); err != nil {
+			return nil, err
+		}
+		links = newHyperlinkSet(string(relsXML))
+	}
+	var documentXML []byte
+	if documentFile != nil {
+		if documentXML, err = renderDocumentXML(documentFile, resume, links, settings); err != nil {
+			return nil, err
+		}
 	}
 
 	var output bytes.Buffer
@@ -45,12 +148,18 @@ func renderResumeFromTemplate(templatePath string, resume model.ResumeModel) ([]
 	defer writer.Close()
 
 	for _, file := range reader.File {
-		if normalizeZipName(file.Name) == "word/document.xml" {
-			updated, err := renderDocumentXML(file, resume)
+		switch file {
+		case documentFile:
+			if err := writeZipFile(writer, file, documentXML); err != nil {
+				return nil, err
+			}
+			continue
+		case relsFile:
+			updated, err := addHyperlinkRelationships(string(relsXML), links.rels)
 			if err != nil {
 				return nil, err
 			}
-			if err := writeZipFile(writer, file, updated); err != nil {
+			if err := writeZipFile(writer, file, []byte(updated)); err != nil {
 				return nil, err
 			}
 			continue
@@ -72,13 +181,13 @@ func renderResumeFromTemplate(templatePath string, resume model.ResumeModel) ([]
 	return output.Bytes(), nil
 }
 
-func renderDocumentXML(file *zip.File, resume model.ResumeModel) ([]byte, error) {
+func renderDocumentXML(file *zip.File, resume model.ResumeModel, links *hyperlinkSet, settings *renderSettings) ([]byte, error) {
 	content, err := readZipFile(file)
 	if err != nil {
 		return nil, err
 	}
 
-	xmlText, err := renderDocumentXMLText(string(content), resume)
+	xmlText, err := renderDocumentXMLWithLinks(string(content), resume, links, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +195,13 @@ func renderDocumentXML(file *zip.File, resume model.ResumeModel) ([]byte, error)
 	return []byte(xmlText), nil
 }
 
+// renderDocumentXMLText renders a bare document.xml. Without a relationships part
+// to add to, links are rendered as plain text.
 func renderDocumentXMLText(xmlText string, resume model.ResumeModel) (string, error) {
+	return renderDocumentXMLWithLinks(xmlText, resume, nil, nil)
+}
+
+func renderDocumentXMLWithLinks(xmlText string, resume model.ResumeModel, hyperlinks *hyperlinkSet, settings *renderSettings) (string, error) {
 	rootStart, rootEnd, err := extractRootTags(xmlText)
 	if err != nil {
 		return "", err
@@ -97,6 +212,16 @@ func renderDocumentXMLText(xmlText string, resume model.ResumeModel) (string, er
 	}
 
 	body := findBodyNode(root)
+	flags, err := settings.flagsFor(resume)
+	if err != nil {
+		return "", err
+	}
+	if err := evaluateConditionals(body, flags); err != nil {
+		return "", err
+	}
+	headings := settings.headings()
+	localizeHeadings(body, headings)
+
 	if err := expandLoopInContainer(body, "SUMMARY", resume.Summary, "{{SUMMARY_ITEM}}"); err != nil {
 		return "", err
 	}
@@ -121,6 +246,7 @@ func renderDocumentXMLText(xmlText string, resume model.ResumeModel) (string, er
 		return "", err
 	}
 
+	renderLinkParagraphs(body, resume.Header.Links, hyperlinks)
 	links := formatLinks(resume.Header.Links)
 
 	replacements := map[string]string{
@@ -139,12 +265,13 @@ func renderDocumentXMLText(xmlText string, resume model.ResumeModel) (string, er
 		"{{HIGHLIGHT_ITEM}}": "",
 	})
 	applyContactPlaceholders(root, resume)
-	removeEmptySections(root, resume)
+	removeEmptySections(root, resume, headings)
 	normalizeParagraphNesting(root)
 	if err := validateNoPlaceholders(root); err != nil {
 		return "", err
 	}
-	enforceHeadingBold(root, []string{"Summary", "Skills", "Experience", "Education"})
+	enforceHeadingBold(root, headings.list(boldSections))
+	settings.revisionMarks().apply(body)
 
 	xmlText, err = encodeXMLDocument(header, root, rootStart, rootEnd)
 	if err != nil {
@@ -300,55 +427,6 @@ func normalizeZipName(name string) string {
 	return strings.ReplaceAll(name, "\\", "/")
 }
 
-func formatLinks(links any) string {
-	switch v := links.(type) {
-	case []string:
-		return strings.Join(v, " | ")
-	default:
-		return formatLinkStructs(v)
-	}
-}
-
-func formatLinkStructs(links any) string {
-	rv := reflect.ValueOf(links)
-	if rv.Kind() != reflect.Slice {
-		return ""
-	}
-
-	out := make([]string, 0, rv.Len())
-	for i := 0; i < rv.Len(); i++ {
-		item := rv.Index(i)
-		if item.Kind() == reflect.Pointer {
-			item = item.Elem()
-		}
-		if item.Kind() != reflect.Struct {
-			return ""
-		}
-
-		labelField := item.FieldByName("Label")
-		urlField := item.FieldByName("URL")
-		if !labelField.IsValid() || !urlField.IsValid() {
-			return ""
-		}
-		if labelField.Kind() != reflect.String || urlField.Kind() != reflect.String {
-			return ""
-		}
-
-		label := labelField.String()
-		url := urlField.String()
-		if url == "" {
-			continue
-		}
-		if label != "" {
-			out = append(out, label+": "+url)
-		} else {
-			out = append(out, url)
-		}
-	}
-
-	return strings.Join(out, " | ")
-}
-
 var tokenPattern = regexp.MustCompile(`{{[^}]+}}`)
 var placeholderPattern = regexp.MustCompile(`(?i)\[(email|phone|handle)\]`)
 var todoPattern = regexp.MustCompile(`(?i)\bTODO\b`)
@@ -429,28 +507,20 @@ func replacePatternInParagraph(p *xmlNode, pattern *regexp.Regexp, value string)
 	}
 }
 
-func contactHandle(links any) string {
-	switch v := links.(type) {
-	case []string:
-		if len(v) > 0 {
-			return v[0]
-		}
-	}
-	return ""
-}
-
-func removeEmptySections(root *xmlNode, resume model.ResumeModel) {
+// removeEmptySections drops the heading paragraphs, in the render's locale, of
+// sections the resume has nothing for.
+func removeEmptySections(root *xmlNode, resume model.ResumeModel, headings sectionHeadings) {
 	sections := []struct {
 		heading string
 		empty   bool
 	}{
-		{"Summary", len(resume.Summary) == 0},
-		{"Skills", len(flattenSkills(resume.Skills)) == 0},
-		{"Experience", len(resume.Experience) == 0},
-		{"Education", len(resume.Education) == 0},
-		{"Certifications", len(resume.Certifications) == 0},
-		{"Awards", len(resume.Achievements) == 0},
-		{"Projects", len(resume.Projects) == 0},
+		{headings[SectionSummary], len(resume.Summary) == 0},
+		{headings[SectionSkills], len(flattenSkills(resume.Skills)) == 0},
+		{headings[SectionExperience], len(resume.Experience) == 0},
+		{headings[SectionEducation], len(resume.Education) == 0},
+		{headings[SectionCertifications], len(resume.Certifications) == 0},
+		{headings[SectionAwards], len(resume.Achievements) == 0},
+		{headings[SectionProjects], len(resume.Projects) == 0},
 	}
 	for _, section := range sections {
 		if !section.empty {
@@ -118,7 +118,7 @@ func TestRenderResumeStyleSmoke(t *testing.T) {
 			Email:    "taylor@example.com",
 			Phone:    "555-555-5555",
 			Location: "Austin, TX",
-			Links:    []string{"https://linkedin.com/in/taylor"},
+			Links:    model.LinksFromURLs("https://linkedin.com/in/taylor"),
 		},
 		Summary: []string{"Summary line one.", "Summary line two."},
 		Skills: model.ResumeSkills{
@@ -310,6 +310,49 @@ func TestRenderResumeProducesValidDocx(t *testing.T) {
 	}
 }
 
+func TestRenderResumeAddsHyperlinkRelationships(t *testing.T) {
+	resume := model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:  "Ada Lovelace",
+			Email: "ada@example.com",
+			Links: []model.ResumeLink{
+				{Label: "Portfolio", URL: "https://example.com/?a=1&b=2"},
+				{URL: "TO-FILL: LinkedIn"},
+			},
+		},
+	}
+
+	docxBytes, err := renderResumeFromTemplate("../../assets/templates/resume_modern_ats_v1.docx", resume)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(docxBytes), int64(len(docxBytes)))
+	if err != nil {
+		t.Fatalf("zip reader failed: %v", err)
+	}
+	var rels string
+	for _, file := range reader.File {
+		if normalizeZipName(file.Name) == documentRelsPath {
+			content, err := readZipFile(file)
+			if err != nil {
+				t.Fatalf("read rels failed: %v", err)
+			}
+			rels = string(content)
+		}
+	}
+	assertContains(t, rels, `Id="rIdLink1"`)
+	assertContains(t, rels, `Target="https://example.com/?a=1&amp;b=2" TargetMode="External"`)
+	assertNotContains(t, rels, "rIdLink2")
+
+	documentXML, err := readDocumentXML(docxBytes)
+	if err != nil {
+		t.Fatalf("read document.xml failed: %v", err)
+	}
+	assertContains(t, documentXML, `<w:hyperlink r:id="rIdLink1"`)
+	assertContains(t, documentXML, "Portfolio: https://example.com/?a=1&amp;b=2")
+	assertContains(t, documentXML, "TO-FILL: LinkedIn")
+}
+
 func TestRenderDocumentXMLSplitTokens(t *testing.T) {
 	content, err := os.ReadFile("testdata/split_tokens_document.xml")
 	if err != nil {
@@ -362,7 +405,7 @@ func TestRenderDocumentXMLSplitHeaderTokens(t *testing.T) {
 			Email:    "ada@example.com",
 			Phone:    "555-555-5555",
 			Location: "London, UK",
-			Links:    []string{"https://example.com"},
+			Links:    model.LinksFromURLs("https://example.com"),
 		},
 	}
 
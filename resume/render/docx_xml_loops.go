@@ -556,8 +556,9 @@ func ensureRunBold(run *xmlNode) {
 		}
 	}
 	if runProps == nil {
+		// rPr must be the first child of a run; Word rejects runs with properties after text.
 		runProps = &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "rPr"}}
-		run.Children = append(run.Children, runProps)
+		run.Children = append([]*xmlNode{runProps}, run.Children...)
 	}
 	for _, child := range runProps.Children {
 		if isElement(child, "b") {
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestEnsureRunBoldPutsPropertiesFirst(t *testing.T) {
+	text := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "t"}, Children: []*xmlNode{{IsText: true, Text: "Experience"}}}
+	run := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "r"}, Children: []*xmlNode{text}}
+
+	ensureRunBold(run)
+	ensureRunBold(run)
+
+	if len(run.Children) != 2 || !isElement(run.Children[0], "rPr") || run.Children[1] != text {
+		t.Fatalf("expected rPr before the text, got %d children", len(run.Children))
+	}
+	props := run.Children[0].Children
+	if len(props) != 1 || !isElement(props[0], "b") {
+		t.Fatalf("expected a single <w:b/>, got %d properties", len(props))
+	}
+}
+
+func TestEnsureRunBoldKeepsExistingProperties(t *testing.T) {
+	italic := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "i"}}
+	props := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "rPr"}, Children: []*xmlNode{italic}}
+	text := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "t"}}
+	run := &xmlNode{Name: xml.Name{Space: wmlNamespace, Local: "r"}, Children: []*xmlNode{props, text}}
+
+	ensureRunBold(run)
+
+	if len(run.Children) != 2 || run.Children[0] != props {
+		t.Fatalf("expected the existing rPr to be reused")
+	}
+	if len(props.Children) != 2 || props.Children[0] != italic || !isElement(props.Children[1], "b") {
+		t.Fatalf("expected <w:b/> appended after existing properties, got %d", len(props.Children))
+	}
+}
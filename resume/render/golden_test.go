@@ -0,0 +1,211 @@
+//go:build phase2
+// +build phase2
+
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"resume-backend/resume/model"
+)
+
+// Regenerate the golden files after an intended output change with:
+//
+//	go test -tags phase2 ./resume/render -run TestRenderGolden -update
+//
+// and review the diff of testdata/golden before committing it.
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+const (
+	goldenDir          = "testdata/golden"
+	productionTemplate = "../../assets/templates/resume_modern_ats_v1.docx"
+)
+
+// goldenScenario renders a resume either through a full DOCX template or, for
+// document.xml fixtures, through renderDocumentXMLText directly.
+type goldenScenario struct {
+	name       string
+	template   string
+	xmlFixture string
+	resume     model.ResumeModel
+}
+
+func goldenScenarios() []goldenScenario {
+	full := model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:     "Ada Lovelace",
+			Title:    "Staff Engineer",
+			Email:    "ada@example.com",
+			Phone:    "+44 20 7946 0958",
+			Location: "London, UK",
+			Links:    []model.ResumeLink{{Label: "LinkedIn", URL: "https://linkedin.com/in/ada"}, {URL: "https://github.com/ada"}, {URL: "TO-FILL: Portfolio"}},
+		},
+		Summary: []string{"Engineer focused on reliable distributed systems.", "Mentor and technical writer."},
+		Skills: model.ResumeSkills{
+			Languages:  []string{"Go", "Python"},
+			Frameworks: []string{"Gin"},
+			Databases:  []string{"PostgreSQL"},
+			Tools:      []string{"Docker"},
+		},
+		Experience: []model.ResumeExperience{
+			{
+				Company:    "Analytical Engines Ltd",
+				Role:       "Staff Engineer",
+				Location:   "London",
+				Start:      "2020-01",
+				End:        "Present",
+				Highlights: []string{"Cut p99 latency 40% by rewriting the scheduler.", "Led a team of 6 engineers."},
+			},
+			{
+				Company:    "Difference Co",
+				Role:       "Engineer",
+				Start:      "2016-03",
+				End:        "2019-12",
+				Highlights: []string{"Built the billing pipeline."},
+			},
+		},
+		Education: []model.ResumeEducation{
+			{Institution: "University of London", Degree: "BSc", Field: "Mathematics", Start: "2012", End: "2015"},
+		},
+		Certifications: []model.ResumeCertification{
+			{Name: "CKA", Issuer: "CNCF", Date: "2021-05"},
+		},
+		Achievements: []model.ResumeAchievement{
+			{Title: "Speaker", Date: "2022-06", Highlights: []string{"GopherCon EU talk on scheduler design."}},
+		},
+	}
+
+	return []goldenScenario{
+		{name: "full_resume", template: productionTemplate, resume: full},
+		{
+			name:     "minimal_resume",
+			template: productionTemplate,
+			resume: model.ResumeModel{
+				Header: model.ResumeHeader{Name: "Grace Hopper", Email: "grace@example.com"},
+			},
+		},
+		{name: "split_tokens", xmlFixture: "testdata/split_tokens_document.xml", resume: full},
+		{name: "split_header_tokens", xmlFixture: "testdata/split_header_tokens_document.xml", resume: full},
+		{name: "split_experience_tokens", xmlFixture: "testdata/split_experience_tokens_document.xml", resume: full},
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	for _, scenario := range goldenScenarios() {
+		t.Run(scenario.name, func(t *testing.T) {
+			got := normalizeDocumentXML(renderGoldenScenario(t, scenario))
+			path := filepath.Join(goldenDir, scenario.name+".xml")
+
+			if *update {
+				if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+					t.Fatalf("create golden dir: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatalf("write golden file: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update to create it): %v", err)
+			}
+			if diff := firstDifference(string(want), got); diff != "" {
+				t.Fatalf("%s does not match the rendered output (run with -update if the change is intended):\n%s", path, diff)
+			}
+		})
+	}
+}
+
+func renderGoldenScenario(t *testing.T, scenario goldenScenario) string {
+	t.Helper()
+	if scenario.xmlFixture != "" {
+		content, err := os.ReadFile(scenario.xmlFixture)
+		if err != nil {
+			t.Fatalf("read fixture failed: %v", err)
+		}
+		rendered, err := renderDocumentXMLText(string(content), scenario.resume)
+		if err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+		return rendered
+	}
+
+	docxBytes, err := renderResumeFromTemplate(scenario.template, scenario.resume)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	documentXML, err := readDocumentXML(docxBytes)
+	if err != nil {
+		t.Fatalf("read document.xml failed: %v", err)
+	}
+	return documentXML
+}
+
+var (
+	// Revision-session ids and paragraph ids change whenever Word saves a template.
+	rsidAttrPattern = regexp.MustCompile(`\s(?:w:rsid[A-Za-z]*|w14:paraId|w14:textId)="[^"]*"`)
+	// Tracked-change and comment timestamps.
+	dateAttrPattern = regexp.MustCompile(`\sw:date="[^"]*"`)
+	tagGapPattern   = regexp.MustCompile(`>\s*<`)
+)
+
+// normalizeDocumentXML removes attributes that differ between otherwise identical
+// renders and puts every tag on its own line so golden diffs stay readable.
+func normalizeDocumentXML(xmlText string) string {
+	out := strings.ReplaceAll(xmlText, "\r\n", "\n")
+	out = rsidAttrPattern.ReplaceAllString(out, "")
+	out = dateAttrPattern.ReplaceAllString(out, ` w:date=""`)
+	out = tagGapPattern.ReplaceAllString(out, ">\n<")
+	return strings.TrimSpace(out) + "\n"
+}
+
+// firstDifference describes the first differing line with a little context, or
+// returns "" when the texts are equal.
+func firstDifference(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		var b strings.Builder
+		for j := max(0, i-3); j < i; j++ {
+			b.WriteString("    " + wantLines[j] + "\n")
+		}
+		b.WriteString("  - " + w + "\n")
+		b.WriteString("  + " + g + "\n")
+		b.WriteString("  (line " + itoa(i+1) + ")")
+		return b.String()
+	}
+	return ""
+}
+
+func TestNormalizeDocumentXML(t *testing.T) {
+	a := `<w:p w:rsidR="00A1B2C3" w:rsidRDefault="00D4E5F6"><w:ins w:id="1" w:author="x" w:date="2024-01-02T03:04:05Z"><w:r><w:t>Hi</w:t></w:r></w:ins></w:p>`
+	b := `<w:p w:rsidR="11111111" w:rsidRDefault="22222222">  <w:ins w:id="1" w:author="x" w:date="2025-06-07T08:09:10Z"><w:r><w:t>Hi</w:t></w:r></w:ins></w:p>`
+	if normalizeDocumentXML(a) != normalizeDocumentXML(b) {
+		t.Fatalf("expected rsid and date differences to normalize away:\n%s\n%s", normalizeDocumentXML(a), normalizeDocumentXML(b))
+	}
+	if strings.Contains(normalizeDocumentXML(a), "rsid") {
+		t.Fatalf("expected rsid attributes to be removed")
+	}
+	if firstDifference("a\nb\n", "a\nc\n") == "" {
+		t.Fatalf("expected a difference to be reported")
+	}
+}
@@ -0,0 +1,166 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownLocale is returned when a render asks for a locale without a
+// headings dictionary.
+var ErrUnknownLocale = errors.New("unknown render locale")
+
+// DefaultLocale is used when a render names no locale. The bundled templates
+// are written with its headings.
+const DefaultLocale = "en"
+
+// Section keys used by the headings dictionaries.
+const (
+	SectionSummary        = "summary"
+	SectionSkills         = "skills"
+	SectionExperience     = "experience"
+	SectionEducation      = "education"
+	SectionCertifications = "certifications"
+	SectionAwards         = "awards"
+	SectionProjects       = "projects"
+)
+
+// boldSections are the headings every render forces to bold, whatever the
+// template's run formatting.
+var boldSections = []string{SectionSummary, SectionSkills, SectionExperience, SectionEducation}
+
+// sectionHeadings maps a section key to its heading in one locale.
+type sectionHeadings map[string]string
+
+// headingsByLocale holds the section headings for each supported locale.
+// Every locale must name every section.
+var headingsByLocale = map[string]sectionHeadings{
+	"en": {
+		SectionSummary:        "Summary",
+		SectionSkills:         "Skills",
+		SectionExperience:     "Experience",
+		SectionEducation:      "Education",
+		SectionCertifications: "Certifications",
+		SectionAwards:         "Awards",
+		SectionProjects:       "Projects",
+	},
+	"es": {
+		SectionSummary:        "Resumen",
+		SectionSkills:         "Habilidades",
+		SectionExperience:     "Experiencia",
+		SectionEducation:      "Educación",
+		SectionCertifications: "Certificaciones",
+		SectionAwards:         "Premios",
+		SectionProjects:       "Proyectos",
+	},
+	"fr": {
+		SectionSummary:        "Profil",
+		SectionSkills:         "Compétences",
+		SectionExperience:     "Expérience",
+		SectionEducation:      "Formation",
+		SectionCertifications: "Certifications",
+		SectionAwards:         "Distinctions",
+		SectionProjects:       "Projets",
+	},
+	"de": {
+		SectionSummary:        "Profil",
+		SectionSkills:         "Kenntnisse",
+		SectionExperience:     "Berufserfahrung",
+		SectionEducation:      "Ausbildung",
+		SectionCertifications: "Zertifikate",
+		SectionAwards:         "Auszeichnungen",
+		SectionProjects:       "Projekte",
+	},
+	"pt": {
+		SectionSummary:        "Resumo",
+		SectionSkills:         "Competências",
+		SectionExperience:     "Experiência",
+		SectionEducation:      "Formação",
+		SectionCertifications: "Certificações",
+		SectionAwards:         "Prêmios",
+		SectionProjects:       "Projetos",
+	},
+}
+
+// SupportedLocales lists the locales a render may ask for, sorted.
+func SupportedLocales() []string {
+	out := make([]string, 0, len(headingsByLocale))
+	for locale := range headingsByLocale {
+		out = append(out, locale)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// NormalizeLocale reduces a locale tag such as "es-MX" or "pt_BR" to the
+// language it selects headings by. An empty tag is DefaultLocale.
+func NormalizeLocale(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if tag == "" {
+		return DefaultLocale, nil
+	}
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := headingsByLocale[tag]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownLocale, raw)
+	}
+	return tag, nil
+}
+
+func headingsFor(locale string) sectionHeadings {
+	if headings, ok := headingsByLocale[locale]; ok {
+		return headings
+	}
+	return headingsByLocale[DefaultLocale]
+}
+
+func (h sectionHeadings) list(sections []string) []string {
+	out := make([]string, 0, len(sections))
+	for _, section := range sections {
+		out = append(out, h[section])
+	}
+	return out
+}
+
+// localizeHeadings rewrites template heading paragraphs, written in
+// DefaultLocale, into the target headings. A heading set in capitals keeps
+// them. It runs before loops are expanded, so only template text is changed.
+func localizeHeadings(root *xmlNode, target sectionHeadings) {
+	source := headingsByLocale[DefaultLocale]
+	if root == nil || target == nil {
+		return
+	}
+	walkXML(root, func(n *xmlNode) bool {
+		if !isElement(n, "p") {
+			return true
+		}
+		text := strings.TrimSpace(paragraphText(n))
+		for section, heading := range source {
+			if !strings.EqualFold(text, heading) || target[section] == heading {
+				continue
+			}
+			localized := target[section]
+			if text == strings.ToUpper(text) {
+				localized = strings.ToUpper(localized)
+			}
+			setParagraphText(n, localized)
+			break
+		}
+		return true
+	})
+}
+
+// setParagraphText puts text in the paragraph's first text element and empties
+// the rest, keeping the first run's formatting.
+func setParagraphText(p *xmlNode, text string) {
+	textNodes := collectTextElements(p)
+	if len(textNodes) == 0 {
+		return
+	}
+	setNodeText(textNodes[0], text)
+	for i := 1; i < len(textNodes); i++ {
+		setNodeText(textNodes[i], "")
+	}
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	cases := map[string]string{
+		"":      DefaultLocale,
+		"es":    "es",
+		"es-MX": "es",
+		"pt_BR": "pt",
+		" DE ":  "de",
+	}
+	for raw, want := range cases {
+		got, err := NormalizeLocale(raw)
+		if err != nil || got != want {
+			t.Fatalf("NormalizeLocale(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	if _, err := NormalizeLocale("xx-YY"); !errors.Is(err, ErrUnknownLocale) {
+		t.Fatalf("expected ErrUnknownLocale, got %v", err)
+	}
+}
+
+func TestHeadingsCoverEverySection(t *testing.T) {
+	for locale, headings := range headingsByLocale {
+		for section := range headingsByLocale[DefaultLocale] {
+			if strings.TrimSpace(headings[section]) == "" {
+				t.Fatalf("locale %s has no heading for %s", locale, section)
+			}
+		}
+	}
+}
+
+func TestRenderLocalizesSectionHeadings(t *testing.T) {
+	xmlText, err := renderDocumentXMLWithLinks(productionDocumentXML(t), redlineResume(), nil, &renderSettings{locale: "es"})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	root, _, err := parseXMLDocument(xmlText)
+	if err != nil {
+		t.Fatalf("parse rendered xml: %v", err)
+	}
+	var paragraphs []string
+	walkXML(root, func(n *xmlNode) bool {
+		if isElement(n, "p") {
+			paragraphs = append(paragraphs, paragraphText(n))
+		}
+		return true
+	})
+	text := strings.Join(paragraphs, "\n")
+	for _, want := range []string{"Resumen", "Habilidades", "Experiencia"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected heading %q in:\n%s", want, text)
+		}
+	}
+	// The resume has no education, certifications or awards, so their
+	// localized headings are removed along with the English ones.
+	for _, unwanted := range []string{"Summary", "Skills", "Experience", "Educación", "Education", "Certificaciones", "Premios"} {
+		if strings.Contains(text, unwanted) {
+			t.Fatalf("expected no %q in:\n%s", unwanted, text)
+		}
+	}
+
+	idx := strings.Index(xmlText, ">Experiencia<")
+	if idx == -1 {
+		t.Fatalf("expected Experiencia heading text node")
+	}
+	start := strings.LastIndex(xmlText[:idx], "<w:p>")
+	if start == -1 {
+		start = strings.LastIndex(xmlText[:idx], "<w:p ")
+	}
+	if !strings.Contains(xmlText[start:idx], "<w:b") {
+		t.Fatalf("expected Experiencia heading to be bold")
+	}
+}
@@ -0,0 +1,48 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	templateMu    sync.RWMutex
+	templateCache = map[string]*zip.Reader{}
+)
+
+// PreloadDefaultTemplate reads and parses the default DOCX template so the first
+// render in a fresh process does not pay for disk IO and zip parsing.
+func PreloadDefaultTemplate() error {
+	_, err := loadTemplate(defaultTemplatePath)
+	return err
+}
+
+// loadTemplate returns the parsed template for path, caching it for the process lifetime.
+// zip.Reader is safe for concurrent use because every File.Open reads through ReaderAt.
+func loadTemplate(templatePath string) (*zip.Reader, error) {
+	key := filepath.Clean(templatePath)
+
+	templateMu.RLock()
+	cached, ok := templateCache[key]
+	templateMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	templateBytes, err := os.ReadFile(key)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(templateBytes), int64(len(templateBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	templateMu.Lock()
+	templateCache[key] = reader
+	templateMu.Unlock()
+	return reader, nil
+}
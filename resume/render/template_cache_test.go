@@ -0,0 +1,20 @@
+package render
+
+import "testing"
+
+func TestLoadTemplateCachesParsedZip(t *testing.T) {
+	first, err := loadTemplate("testdata/template.docx")
+	if err != nil {
+		t.Fatalf("load template: %v", err)
+	}
+	second, err := loadTemplate("./testdata/template.docx")
+	if err != nil {
+		t.Fatalf("load template again: %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected cached reader to be reused")
+	}
+	if _, err := loadTemplate("testdata/missing.docx"); err == nil {
+		t.Fatalf("expected error for missing template")
+	}
+}
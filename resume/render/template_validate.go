@@ -0,0 +1,301 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"resume-backend/resume/model"
+)
+
+// Limits applied to uploaded templates before any part is decompressed.
+const (
+	maxTemplateParts            = 200
+	maxTemplateUncompressedSize = 20 << 20 // 20MB
+)
+
+// Names of the checks reported by ValidateTemplate.
+const (
+	CheckStructure = "structure"
+	CheckTokens    = "tokens"
+	CheckRender    = "render"
+)
+
+// TemplateCheck is the outcome of one validation step.
+type TemplateCheck struct {
+	Name   string   `json:"name"`
+	Passed bool     `json:"passed"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// TemplateReport is the result of running a template through ValidateTemplate.
+// Tokens lists the template tokens found in document.xml.
+type TemplateReport struct {
+	Checks []TemplateCheck `json:"checks"`
+	Tokens []string        `json:"tokens"`
+}
+
+// Passed reports whether every check passed.
+func (r TemplateReport) Passed() bool {
+	if len(r.Checks) == 0 {
+		return false
+	}
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// loopSections are the {{#NAME}}...{{/NAME}} blocks the renderer expands.
+var loopSections = map[string]bool{
+	"SUMMARY":        true,
+	"SKILLS":         true,
+	"EXPERIENCE":     true,
+	"HIGHLIGHTS":     true,
+	"EDUCATION":      true,
+	"CERTIFICATIONS": true,
+	"AWARDS":         true,
+}
+
+// knownTokens are the value tokens the renderer replaces.
+var knownTokens = map[string]bool{
+	"FULL_NAME": true, "TITLE": true, "EMAIL": true, "PHONE": true, "LOCATION": true, "LINKS": true,
+	"SUMMARY_ITEM": true, "SKILL_ITEM": true, "HIGHLIGHT_ITEM": true,
+	"EXP_COMPANY": true, "EXP_ROLE": true, "EXP_LOCATION": true, "EXP_START": true, "EXP_END": true,
+	"EDU_INSTITUTION": true, "EDU_DEGREE": true, "EDU_FIELD": true, "EDU_LOCATION": true, "EDU_START": true, "EDU_END": true,
+	"CERT_NAME": true, "CERT_ISSUER": true, "CERT_DATE": true, "CERT_EXPIRES": true,
+	"AWARD_TITLE": true, "AWARD_DATE": true,
+}
+
+// ValidateTemplate checks an uploaded DOCX template without touching the
+// template cache. It inspects the package structure, lints the tokens in
+// document.xml and renders SampleResume with it. Later checks are skipped when
+// the structure check fails. A panic while rendering fails the render check
+// instead of crashing the caller.
+func ValidateTemplate(templateBytes []byte) TemplateReport {
+	report := TemplateReport{Tokens: []string{}}
+
+	reader, document, issues := checkTemplateStructure(templateBytes)
+	report.Checks = append(report.Checks, newTemplateCheck(CheckStructure, issues))
+	if len(issues) > 0 {
+		return report
+	}
+
+	tokens, issues := lintTemplateTokens(reader, document)
+	report.Tokens = tokens
+	report.Checks = append(report.Checks, newTemplateCheck(CheckTokens, issues))
+
+	var renderIssues []string
+	if err := renderSample(reader); err != nil {
+		renderIssues = append(renderIssues, err.Error())
+	}
+	report.Checks = append(report.Checks, newTemplateCheck(CheckRender, renderIssues))
+	return report
+}
+
+func newTemplateCheck(name string, issues []string) TemplateCheck {
+	return TemplateCheck{Name: name, Passed: len(issues) == 0, Issues: issues}
+}
+
+func checkTemplateStructure(templateBytes []byte) (*zip.Reader, *xmlNode, []string) {
+	reader, err := zip.NewReader(bytes.NewReader(templateBytes), int64(len(templateBytes)))
+	if err != nil {
+		return nil, nil, []string{"not a DOCX (zip) package"}
+	}
+	if len(reader.File) > maxTemplateParts {
+		return nil, nil, []string{fmt.Sprintf("package has %d parts; at most %d are allowed", len(reader.File), maxTemplateParts)}
+	}
+
+	var issues []string
+	var total uint64
+	var documentFile *zip.File
+	hasContentTypes := false
+	for _, file := range reader.File {
+		name := normalizeZipName(file.Name)
+		total += file.UncompressedSize64
+		switch {
+		case name == "[Content_Types].xml":
+			hasContentTypes = true
+		case name == "word/document.xml":
+			documentFile = file
+		case strings.HasSuffix(strings.ToLower(name), "vbaproject.bin"):
+			issues = append(issues, "macro-enabled templates are not allowed")
+		case strings.HasPrefix(name, "/") || strings.Contains(name, ".."):
+			issues = append(issues, fmt.Sprintf("invalid part name %q", name))
+		}
+	}
+	if total > maxTemplateUncompressedSize {
+		return nil, nil, []string{fmt.Sprintf("package expands to %d bytes; at most %d are allowed", total, maxTemplateUncompressedSize)}
+	}
+	if !hasContentTypes {
+		issues = append(issues, "missing [Content_Types].xml")
+	}
+	if documentFile == nil {
+		return nil, nil, append(issues, "missing word/document.xml")
+	}
+	if len(issues) > 0 {
+		return nil, nil, issues
+	}
+
+	content, err := readZipFile(documentFile)
+	if err != nil {
+		return nil, nil, []string{"word/document.xml cannot be read"}
+	}
+	root, _, err := parseXMLDocument(string(content))
+	if err != nil {
+		return nil, nil, []string{fmt.Sprintf("word/document.xml is not valid XML: %v", err)}
+	}
+	if findBodyNode(root) == nil {
+		return nil, nil, []string{"word/document.xml has no <w:body>"}
+	}
+	return reader, root, nil
+}
+
+// lintTemplateTokens reads tokens from paragraph text so that tokens Word split
+// across runs are seen whole, as the renderer sees them.
+func lintTemplateTokens(reader *zip.Reader, document *xmlNode) ([]string, []string) {
+	var issues []string
+	seen := map[string]bool{}
+	var open []string
+	walkXML(document, func(n *xmlNode) bool {
+		if !isElement(n, "p") {
+			return true
+		}
+		for _, token := range tokenPattern.FindAllString(paragraphText(n), -1) {
+			seen[token] = true
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(token, "{{"), "}}"))
+			switch {
+			case strings.HasPrefix(name, "#"):
+				section := name[1:]
+				if !loopSections[section] {
+					issues = append(issues, "unknown section "+token)
+					continue
+				}
+				open = append(open, section)
+			case strings.HasPrefix(name, "?"):
+				flag := name[1:]
+				if !isTemplateFlag(flag) {
+					issues = append(issues, "unknown flag "+token)
+					continue
+				}
+				open = append(open, flag)
+			case strings.HasPrefix(name, "/"):
+				section := name[1:]
+				if len(open) == 0 || open[len(open)-1] != section {
+					issues = append(issues, "unexpected section end "+token)
+					continue
+				}
+				open = open[:len(open)-1]
+			case !knownTokens[name]:
+				issues = append(issues, "unknown token "+token)
+			}
+		}
+		return true
+	})
+	for _, section := range open {
+		if isTemplateFlag(section) {
+			issues = append(issues, "conditional {{?"+section+"}} is never closed")
+			continue
+		}
+		issues = append(issues, "section {{#"+section+"}} is never closed")
+	}
+	if !seen["{{FULL_NAME}}"] {
+		issues = append(issues, "missing required token {{FULL_NAME}}")
+	}
+	if !seen["{{EMAIL}}"] && !seen["{{PHONE}}"] {
+		issues = append(issues, "missing contact token {{EMAIL}} or {{PHONE}}")
+	}
+
+	// Only document.xml is rendered, so tokens in headers, footers or other
+	// parts would reach the candidate's resume verbatim.
+	for _, file := range reader.File {
+		name := normalizeZipName(file.Name)
+		if name == "word/document.xml" || !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			issues = append(issues, name+" cannot be read")
+			continue
+		}
+		if token := tokenPattern.FindString(string(content)); token != "" {
+			issues = append(issues, fmt.Sprintf("%s contains %s; tokens are only rendered in word/document.xml", name, token))
+		}
+	}
+
+	tokens := make([]string, 0, len(seen))
+	for token := range seen {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens, issues
+}
+
+func renderSample(reader *zip.Reader) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("render panicked: %v", r)
+		}
+	}()
+	// Every option flag is set, so conditional sections are rendered too.
+	opts := RenderOptions{Flags: map[string]bool{}}
+	for name := range optionFlags {
+		opts.Flags[name] = true
+	}
+	resume := SampleResume()
+	flags, err := resolveFlags(resume, opts)
+	if err != nil {
+		return err
+	}
+	output, err := renderResumeFromZip(reader, resume, &renderSettings{flags: flags})
+	if err != nil {
+		return err
+	}
+	if _, err := zip.NewReader(bytes.NewReader(output), int64(len(output))); err != nil {
+		return fmt.Errorf("rendered document is not a valid package: %w", err)
+	}
+	return nil
+}
+
+// SampleResume is a resume that fills every section a template can render.
+func SampleResume() model.ResumeModel {
+	return model.ResumeModel{
+		Header: model.ResumeHeader{
+			Name:     "Ada Lovelace",
+			Title:    "Staff Engineer",
+			Email:    "ada@example.com",
+			Phone:    "+44 20 7946 0958",
+			Location: "London, UK",
+			Links:    []model.ResumeLink{{Label: "LinkedIn", URL: "https://linkedin.com/in/ada"}, {URL: "https://github.com/ada"}},
+		},
+		Summary: []string{"Engineer focused on reliable distributed systems."},
+		Skills: model.ResumeSkills{
+			Languages:  []string{"Go", "Python"},
+			Databases:  []string{"PostgreSQL"},
+			Frameworks: []string{"Gin"},
+		},
+		Experience: []model.ResumeExperience{
+			{
+				Company:    "Analytical Engines Ltd",
+				Role:       "Staff Engineer",
+				Location:   "London",
+				Start:      "2020-01",
+				End:        "Present",
+				Highlights: []string{"Cut p99 latency 40% by rewriting the scheduler.", "Led a team of 6 engineers."},
+			},
+		},
+		Education: []model.ResumeEducation{
+			{Institution: "University of London", Degree: "BSc", Field: "Mathematics", Location: "London", Start: "2012", End: "2015"},
+		},
+		Certifications: []model.ResumeCertification{
+			{Name: "CKA", Issuer: "CNCF", Date: "2021-05", Expires: "2024-05"},
+		},
+		Achievements: []model.ResumeAchievement{
+			{Title: "Speaker", Date: "2022-06"},
+		},
+	}
+}
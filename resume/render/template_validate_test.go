@@ -0,0 +1,189 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+const productionTemplatePath = "../../assets/templates/resume_modern_ats_v1.docx"
+
+// rewriteTemplate copies the production template, replacing or adding the given
+// parts; a nil value drops the part.
+func rewriteTemplate(t *testing.T, parts map[string][]byte) []byte {
+	t.Helper()
+	src, err := os.ReadFile(productionTemplatePath)
+	if err != nil {
+		t.Fatalf("read template: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(src), int64(len(src)))
+	if err != nil {
+		t.Fatalf("open template: %v", err)
+	}
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	written := map[string]bool{}
+	for _, file := range reader.File {
+		name := normalizeZipName(file.Name)
+		content, ok := parts[name]
+		if !ok {
+			if content, err = readZipFile(file); err != nil {
+				t.Fatalf("read %s: %v", name, err)
+			}
+		}
+		written[name] = true
+		if content == nil {
+			continue
+		}
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		w.Write(content)
+	}
+	for name, content := range parts {
+		if written[name] || content == nil {
+			continue
+		}
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		w.Write(content)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return out.Bytes()
+}
+
+func productionDocumentXML(t *testing.T) string {
+	t.Helper()
+	reader, err := loadTemplate(productionTemplatePath)
+	if err != nil {
+		t.Fatalf("load template: %v", err)
+	}
+	for _, file := range reader.File {
+		if normalizeZipName(file.Name) == "word/document.xml" {
+			content, err := readZipFile(file)
+			if err != nil {
+				t.Fatalf("read document.xml: %v", err)
+			}
+			return string(content)
+		}
+	}
+	t.Fatalf("template has no document.xml")
+	return ""
+}
+
+func failedCheck(report TemplateReport, name string) *TemplateCheck {
+	for i := range report.Checks {
+		if report.Checks[i].Name == name && !report.Checks[i].Passed {
+			return &report.Checks[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateTemplateAcceptsProductionTemplate(t *testing.T) {
+	report := ValidateTemplate(rewriteTemplate(t, nil))
+	if !report.Passed() {
+		t.Fatalf("expected production template to pass, got %+v", report.Checks)
+	}
+	if len(report.Checks) != 3 {
+		t.Fatalf("expected 3 checks, got %+v", report.Checks)
+	}
+	if len(report.Tokens) == 0 || report.Tokens[0] != "{{#AWARDS}}" {
+		t.Fatalf("expected sorted tokens, got %v", report.Tokens)
+	}
+}
+
+func TestValidateTemplateRejectsBrokenTemplates(t *testing.T) {
+	document := productionDocumentXML(t)
+
+	cases := []struct {
+		name  string
+		input []byte
+		check string
+		issue string
+	}{
+		{
+			name:  "not a zip",
+			input: []byte("plain text"),
+			check: CheckStructure,
+			issue: "not a DOCX",
+		},
+		{
+			name:  "missing document",
+			input: rewriteTemplate(t, map[string][]byte{"word/document.xml": nil}),
+			check: CheckStructure,
+			issue: "missing word/document.xml",
+		},
+		{
+			name:  "macros",
+			input: rewriteTemplate(t, map[string][]byte{"word/vbaProject.bin": []byte("x")}),
+			check: CheckStructure,
+			issue: "macro-enabled",
+		},
+		{
+			name:  "unknown token",
+			input: rewriteTemplate(t, map[string][]byte{"word/document.xml": []byte(strings.Replace(document, "{{TITLE}}", "{{JOB_TITLE}}", 1))}),
+			check: CheckTokens,
+			issue: "unknown token {{JOB_TITLE}}",
+		},
+		{
+			name:  "unclosed section",
+			input: rewriteTemplate(t, map[string][]byte{"word/document.xml": []byte(strings.Replace(document, "{{/AWARDS}}", "", 1))}),
+			check: CheckTokens,
+			issue: "section {{#AWARDS}} is never closed",
+		},
+		{
+			name:  "placeholder text",
+			input: rewriteTemplate(t, map[string][]byte{"word/document.xml": []byte(strings.Replace(document, "{{TITLE}}", "TODO", 1))}),
+			check: CheckRender,
+			issue: "TODO placeholders",
+		},
+		{
+			name:  "token in footer",
+			input: rewriteTemplate(t, map[string][]byte{"word/footer1.xml": []byte(`<w:ftr><w:p><w:r><w:t>{{FULL_NAME}}</w:t></w:r></w:p></w:ftr>`)}),
+			check: CheckTokens,
+			issue: "word/footer1.xml contains {{FULL_NAME}}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := ValidateTemplate(tc.input)
+			if report.Passed() {
+				t.Fatalf("expected template to be rejected")
+			}
+			check := failedCheck(report, tc.check)
+			if check == nil {
+				t.Fatalf("expected %s check to fail, got %+v", tc.check, report.Checks)
+			}
+			if !strings.Contains(strings.Join(check.Issues, "\n"), tc.issue) {
+				t.Fatalf("expected issue containing %q, got %v", tc.issue, check.Issues)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateConditionals(t *testing.T) {
+	document := productionDocumentXML(t)
+	withFlag := strings.Replace(document, "{{TITLE}}", "{{?HAS_TITLE}}{{TITLE}}{{/HAS_TITLE}}", 1)
+	if report := ValidateTemplate(rewriteTemplate(t, map[string][]byte{"word/document.xml": []byte(withFlag)})); !report.Passed() {
+		t.Fatalf("expected a known flag to pass, got %+v", report.Checks)
+	}
+
+	unknown := strings.Replace(document, "{{TITLE}}", "{{?HAS_PETS}}{{TITLE}}{{/HAS_PETS}}", 1)
+	report := ValidateTemplate(rewriteTemplate(t, map[string][]byte{"word/document.xml": []byte(unknown)}))
+	check := failedCheck(report, CheckTokens)
+	if check == nil || !strings.Contains(strings.Join(check.Issues, "; "), "unknown flag {{?HAS_PETS}}") {
+		t.Fatalf("expected unknown flag issue, got %+v", report.Checks)
+	}
+	if failedCheck(report, CheckRender) == nil {
+		t.Fatalf("expected the render check to fail too, got %+v", report.Checks)
+	}
+}
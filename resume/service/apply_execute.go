@@ -22,45 +22,141 @@ type ApplyHeaderInputs struct {
 	Email    string
 	Phone    string
 	Location string
-	Links    []string
+	Links    []model.ResumeLink
+}
+
+// Apply change kinds.
+const (
+	ChangeAutoFix     = "auto_fix"
+	ChangeSafeRewrite = "safe_rewrite"
+	ChangeHeaderInput = "header_input"
+	ChangeSkills      = "skills"
+)
+
+// ApplyChange is one edit the apply flow makes to the resume text.
+type ApplyChange struct {
+	Kind    string `json:"kind"`
+	Section string `json:"section"`
+	Field   string `json:"field,omitempty"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
 }
 
 // ApplyExecutionResult represents the outcome of an apply execution.
 type ApplyExecutionResult struct {
-	DocxBytes             []byte
+	DocxBytes []byte
+	// RedlineDocxBytes is set by ExecuteApplyRedline: the same document with
+	// each change marked as a Word tracked change.
+	RedlineDocxBytes      []byte
 	AutoFixesApplied      int
 	SafeRewritesApplied   int
 	PlaceholdersRemaining int
 	Status                string
 	Plan                  ApplyPlan
+	Changes               []ApplyChange
+	// Header is the contact header as rendered.
+	Header model.ResumeHeader
+	// Model is the resume as rendered; ExecuteApply and ExecuteApplyRedline
+	// set it.
+	Model model.ResumeModel
+	// RenderWarnings reports content the template could not render.
+	RenderWarnings []render.ContentWarning
 }
 
 // ExecuteApply regenerates a resume with fixes and rewrites applied.
-func ExecuteApply(ctx context.Context, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool) (ApplyExecutionResult, error) {
-	plan := BuildApplyPlan(analysis)
+func ExecuteApply(ctx context.Context, client LLMClient, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool) (ApplyExecutionResult, error) {
+	return ExecuteApplyWithOptions(ctx, client, resumeText, analysis, headerInputs, strict, render.RenderOptions{})
+}
 
-	resumeModel, err := BuildResumeModel(ctx, resumeText)
+// ExecuteApplyWithOptions runs ExecuteApply, rendering the document with opts,
+// such as the locale of its section headings.
+func ExecuteApplyWithOptions(ctx context.Context, client LLMClient, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool, opts render.RenderOptions) (ApplyExecutionResult, error) {
+	result, resumeModel, err := prepareApply(ctx, client, resumeText, analysis, headerInputs, strict)
 	if err != nil {
 		return ApplyExecutionResult{}, err
 	}
 
-	autoFixesApplied := applyAutoFixes(&resumeModel, plan.AutoFixes)
-	safeRewritesApplied := applySafeRewrites(&resumeModel, plan.SafeRewrites)
-	applyHeaderInputs(&resumeModel, headerInputs)
-	applySkills(&resumeModel, analysis)
-
-	if err := contract.Enforce(&resumeModel, strict); err != nil {
+	docxBytes, warnings, err := render.RenderResumeWithReport(resumeModel, opts)
+	if err != nil {
 		return ApplyExecutionResult{}, err
 	}
+	result.DocxBytes = docxBytes
+	result.RenderWarnings = warnings
+	result.Header = resumeModel.Header
+	result.Model = resumeModel
+	return result, nil
+}
 
-	if err := resumeModel.Validate(); err != nil {
+// ExecuteApplyRedline runs ExecuteApply and also renders the result with its
+// changes as tracked changes, so they can be reviewed in Word against the
+// original wording.
+func ExecuteApplyRedline(ctx context.Context, client LLMClient, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool) (ApplyExecutionResult, error) {
+	return ExecuteApplyRedlineWithOptions(ctx, client, resumeText, analysis, headerInputs, strict, render.RenderOptions{})
+}
+
+// ExecuteApplyRedlineWithOptions runs ExecuteApplyRedline, rendering both
+// documents with opts.
+func ExecuteApplyRedlineWithOptions(ctx context.Context, client LLMClient, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool, opts render.RenderOptions) (ApplyExecutionResult, error) {
+	result, resumeModel, err := prepareApply(ctx, client, resumeText, analysis, headerInputs, strict)
+	if err != nil {
 		return ApplyExecutionResult{}, err
 	}
 
-	docxBytes, err := render.RenderResume(resumeModel)
+	docxBytes, warnings, err := render.RenderResumeWithReport(resumeModel, opts)
 	if err != nil {
 		return ApplyExecutionResult{}, err
 	}
+	revisions := make([]render.Revision, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		revisions = append(revisions, render.Revision{Before: change.Before, After: change.After})
+	}
+	redlineBytes, err := render.RenderResumeRedline(resumeModel, revisions, render.RedlineOptions{Locale: opts.Locale})
+	if err != nil {
+		return ApplyExecutionResult{}, err
+	}
+	result.DocxBytes = docxBytes
+	result.RedlineDocxBytes = redlineBytes
+	result.RenderWarnings = warnings
+	result.Header = resumeModel.Header
+	result.Model = resumeModel
+	return result, nil
+}
+
+// PreviewApply runs the same steps as ExecuteApply but stops before rendering, so
+// callers can show the changes without producing a document.
+func PreviewApply(ctx context.Context, client LLMClient, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool) (ApplyExecutionResult, error) {
+	result, _, err := prepareApply(ctx, client, resumeText, analysis, headerInputs, strict)
+	return result, err
+}
+
+func prepareApply(ctx context.Context, client LLMClient, resumeText string, analysis AnalysisResultV2_3, headerInputs ApplyHeaderInputs, strict bool) (ApplyExecutionResult, model.ResumeModel, error) {
+	plan := BuildApplyPlan(analysis)
+
+	resumeModel, err := ResumeModelFromText(ctx, client, resumeText)
+	if err != nil {
+		return ApplyExecutionResult{}, model.ResumeModel{}, err
+	}
+
+	changes := make([]ApplyChange, 0)
+	autoFixesApplied := applyAutoFixes(&resumeModel, plan.AutoFixes, &changes)
+	safeRewritesApplied := applySafeRewrites(&resumeModel, plan.SafeRewrites, &changes)
+	detected := resumeModel.Header
+	applyHeaderInputs(&resumeModel, headerInputs, &changes)
+	applySkills(&resumeModel, analysis, &changes)
+	// Checked before placeholders are filled in, so a missing email or phone is
+	// reported instead of rendered as a placeholder or dropped from the document.
+	needsInput := checkRequiredHeader(resumeModel.Header, detected)
+
+	if err := contract.Enforce(&resumeModel, strict); err != nil {
+		return ApplyExecutionResult{}, model.ResumeModel{}, err
+	}
+	if len(needsInput) > 0 {
+		return ApplyExecutionResult{}, model.ResumeModel{}, MissingInputError{NeedsInput: needsInput}
+	}
+
+	if err := resumeModel.Validate(); err != nil {
+		return ApplyExecutionResult{}, model.ResumeModel{}, err
+	}
 
 	placeholdersRemaining := countPlaceholders(plan.BlockedRewrites)
 	status := ApplyResultFinal
@@ -69,16 +165,16 @@ func ExecuteApply(ctx context.Context, resumeText string, analysis AnalysisResul
 	}
 
 	return ApplyExecutionResult{
-		DocxBytes:             docxBytes,
 		AutoFixesApplied:      autoFixesApplied,
 		SafeRewritesApplied:   safeRewritesApplied,
 		PlaceholdersRemaining: placeholdersRemaining,
 		Status:                status,
 		Plan:                  plan,
-	}, nil
+		Changes:               changes,
+	}, resumeModel, nil
 }
 
-func applySkills(resumeModel *model.ResumeModel, analysis AnalysisResultV2_3) {
+func applySkills(resumeModel *model.ResumeModel, analysis AnalysisResultV2_3, changes *[]ApplyChange) {
 	skillLines := skills.BuildSkillLines(
 		resumeModel.Skills,
 		analysis.ATS.MissingKeywords.IndustryCommon,
@@ -89,41 +185,62 @@ func applySkills(resumeModel *model.ResumeModel, analysis AnalysisResultV2_3) {
 	if len(skillLines) == 0 {
 		return
 	}
+	before := strings.Join(flattenSkills(resumeModel.Skills), "\n")
 	resumeModel.Skills = model.ResumeSkills{Tools: skillLines}
+	if after := strings.Join(skillLines, "\n"); after != before {
+		*changes = append(*changes, ApplyChange{Kind: ChangeSkills, Section: "skills", Before: before, After: after})
+	}
 }
 
-func applyHeaderInputs(resumeModel *model.ResumeModel, inputs ApplyHeaderInputs) {
-	if inputs.Name != "" {
-		resumeModel.Header.Name = inputs.Name
-	}
-	if inputs.Title != "" {
-		resumeModel.Header.Title = inputs.Title
-	}
-	if inputs.Email != "" {
-		resumeModel.Header.Email = inputs.Email
+func flattenSkills(s model.ResumeSkills) []string {
+	var out []string
+	for _, group := range [][]string{s.Languages, s.Frameworks, s.Databases, s.CloudDevOps, s.Observability, s.Tools} {
+		out = append(out, group...)
 	}
-	if inputs.Phone != "" {
-		resumeModel.Header.Phone = inputs.Phone
-	}
-	if inputs.Location != "" {
-		resumeModel.Header.Location = inputs.Location
+	return out
+}
+
+func applyHeaderInputs(resumeModel *model.ResumeModel, inputs ApplyHeaderInputs, changes *[]ApplyChange) {
+	set := func(field string, target *string, value string) {
+		if value == "" || *target == value {
+			return
+		}
+		*changes = append(*changes, ApplyChange{Kind: ChangeHeaderInput, Section: "header", Field: field, Before: *target, After: value})
+		*target = value
 	}
+	set("name", &resumeModel.Header.Name, inputs.Name)
+	set("title", &resumeModel.Header.Title, inputs.Title)
+	set("email", &resumeModel.Header.Email, inputs.Email)
+	set("phone", &resumeModel.Header.Phone, inputs.Phone)
+	set("location", &resumeModel.Header.Location, inputs.Location)
 	if len(inputs.Links) > 0 {
+		before := joinLinks(resumeModel.Header.Links)
+		if after := joinLinks(inputs.Links); after != before {
+			*changes = append(*changes, ApplyChange{Kind: ChangeHeaderInput, Section: "header", Field: "links", Before: before, After: after})
+		}
 		resumeModel.Header.Links = inputs.Links
 	}
 }
 
-func applyAutoFixes(resumeModel *model.ResumeModel, autoFixes []AnalysisIssue) int {
+func joinLinks(links []model.ResumeLink) string {
+	texts := make([]string, 0, len(links))
+	for _, link := range links {
+		texts = append(texts, link.Text())
+	}
+	return strings.Join(texts, "\n")
+}
+
+func applyAutoFixes(resumeModel *model.ResumeModel, autoFixes []AnalysisIssue, changes *[]ApplyChange) int {
 	applied := 0
 	for _, issue := range autoFixes {
-		if applySensitiveHeaderFix(resumeModel, issue) {
+		if applySensitiveHeaderFix(resumeModel, issue, changes) {
 			applied++
 		}
 	}
 	return applied
 }
 
-func applySensitiveHeaderFix(resumeModel *model.ResumeModel, issue AnalysisIssue) bool {
+func applySensitiveHeaderFix(resumeModel *model.ResumeModel, issue AnalysisIssue, changes *[]ApplyChange) bool {
 	section := strings.ToLower(issue.Section)
 	problem := strings.ToLower(issue.Problem)
 	if !strings.Contains(section, "personal") &&
@@ -134,10 +251,12 @@ func applySensitiveHeaderFix(resumeModel *model.ResumeModel, issue AnalysisIssue
 
 	changed := false
 	if resumeModel.Header.Nationality != "" {
+		*changes = append(*changes, ApplyChange{Kind: ChangeAutoFix, Section: "header", Field: "nationality", Before: resumeModel.Header.Nationality})
 		resumeModel.Header.Nationality = ""
 		changed = true
 	}
 	if resumeModel.Header.MaritalStatus != "" {
+		*changes = append(*changes, ApplyChange{Kind: ChangeAutoFix, Section: "header", Field: "maritalStatus", Before: resumeModel.Header.MaritalStatus})
 		resumeModel.Header.MaritalStatus = ""
 		changed = true
 	}
@@ -147,6 +266,7 @@ func applySensitiveHeaderFix(resumeModel *model.ResumeModel, issue AnalysisIssue
 		for _, line := range resumeModel.Summary {
 			lower := strings.ToLower(line)
 			if strings.Contains(lower, "nationality") || strings.Contains(lower, "marital") {
+				*changes = append(*changes, ApplyChange{Kind: ChangeAutoFix, Section: "summary", Before: line})
 				changed = true
 				continue
 			}
@@ -157,13 +277,14 @@ func applySensitiveHeaderFix(resumeModel *model.ResumeModel, issue AnalysisIssue
 	return changed
 }
 
-func applySafeRewrites(resumeModel *model.ResumeModel, rewrites []BulletRewrite) int {
+func applySafeRewrites(resumeModel *model.ResumeModel, rewrites []BulletRewrite, changes *[]ApplyChange) int {
 	applied := 0
 	for _, rewrite := range rewrites {
 		if rewrite.Before == "" || rewrite.After == "" {
 			continue
 		}
 		if applyRewriteToHighlights(resumeModel, rewrite.Before, rewrite.After) {
+			*changes = append(*changes, ApplyChange{Kind: ChangeSafeRewrite, Section: rewrite.Section, Before: rewrite.Before, After: rewrite.After})
 			applied++
 		}
 	}
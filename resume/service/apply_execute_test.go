@@ -46,11 +46,7 @@ func TestExecuteApplyRewritesAndDraftStatus(t *testing.T) {
 		`"experience":[{"id":"exp_1","company":"Acme","role":"Dev","location":"","start":"2020-01","end":"Present","highlights":["Old bullet"]}],` +
 		`"projects":[],"education":[],"achievements":[],"certifications":[]}`
 
-	prevClient := Client
-	Client = &mockApplyLLM{response: llmResponse}
-	defer func() {
-		Client = prevClient
-	}()
+	client := &mockApplyLLM{response: llmResponse}
 
 	analysis := AnalysisResultV2_3{
 		Issues: []AnalysisIssue{
@@ -82,8 +78,9 @@ func TestExecuteApplyRewritesAndDraftStatus(t *testing.T) {
 		},
 	}
 
-	result, err := ExecuteApply(context.Background(), "sample resume text", analysis, ApplyHeaderInputs{
+	result, err := ExecuteApply(context.Background(), client, "sample resume text", analysis, ApplyHeaderInputs{
 		Email: "user@example.com",
+		Phone: "+1 555 010 2030",
 	}, false)
 	if err != nil {
 		t.Fatalf("ExecuteApply failed: %v", err)
@@ -109,20 +106,58 @@ func TestExecuteApplyRewritesAndDraftStatus(t *testing.T) {
 	assertNotContains(t, documentXML, "Old bullet")
 	assertNotContains(t, documentXML, "Nationality: India")
 	assertContains(t, documentXML, "user@example.com")
+
+	rewrites := 0
+	for _, change := range result.Changes {
+		if change.Kind == ChangeSafeRewrite {
+			rewrites++
+			if change.Before != "Old bullet" || change.After != "New bullet" {
+				t.Fatalf("unexpected rewrite change: %+v", change)
+			}
+		}
+	}
+	if rewrites != 1 {
+		t.Fatalf("expected 1 rewrite change, got %d", rewrites)
+	}
+
+	preview, err := PreviewApply(context.Background(), client, "sample resume text", analysis, ApplyHeaderInputs{
+		Email: "user@example.com",
+		Phone: "+1 555 010 2030",
+	}, false)
+	if err != nil {
+		t.Fatalf("PreviewApply failed: %v", err)
+	}
+	if preview.DocxBytes != nil {
+		t.Fatalf("expected preview to skip rendering")
+	}
+	if len(preview.Changes) != len(result.Changes) {
+		t.Fatalf("expected preview to report %d changes, got %d", len(result.Changes), len(preview.Changes))
+	}
+
+	redline, err := ExecuteApplyRedline(context.Background(), client, "sample resume text", analysis, ApplyHeaderInputs{
+		Email: "user@example.com",
+		Phone: "+1 555 010 2030",
+	}, false)
+	if err != nil {
+		t.Fatalf("ExecuteApplyRedline failed: %v", err)
+	}
+	redlineXML, err := readDocumentXML(redline.RedlineDocxBytes)
+	if err != nil {
+		t.Fatalf("read redline document.xml failed: %v", err)
+	}
+	assertContains(t, redlineXML, `<w:delText xml:space="preserve">Old </w:delText>`)
+	assertContains(t, redlineXML, `<w:t xml:space="preserve">New </w:t>`)
+	assertNotContains(t, redlineXML, "Nationality: India")
 }
 
 func TestExecuteApplyStrictModeMissingContact(t *testing.T) {
 	llmResponse := `{"header":{"name":"Test User","title":"","email":"","phone":"","location":"","links":[]},"summary":[],"skills":{"languages":[],"frameworks":[],"databases":[],"cloudDevOps":[],"observability":[],"tools":[]},"experience":[],"projects":[],"education":[],"achievements":[],"certifications":[]}`
 
-	prevClient := Client
-	Client = &mockApplyLLM{response: llmResponse}
-	defer func() {
-		Client = prevClient
-	}()
+	client := &mockApplyLLM{response: llmResponse}
 
 	analysis := AnalysisResultV2_3{}
 
-	_, err := ExecuteApply(context.Background(), "sample resume text", analysis, ApplyHeaderInputs{}, true)
+	_, err := ExecuteApply(context.Background(), client, "sample resume text", analysis, ApplyHeaderInputs{}, true)
 	if err == nil {
 		t.Fatalf("expected strict mode error")
 	}
@@ -135,6 +170,21 @@ func TestExecuteApplyStrictModeMissingContact(t *testing.T) {
 	}
 }
 
+func TestExecuteApplyRequiresContactInput(t *testing.T) {
+	llmResponse := `{"header":{"name":"Test User","title":"","email":"","phone":"","location":"","links":[]},"summary":[],"skills":{"languages":[],"frameworks":[],"databases":[],"cloudDevOps":[],"observability":[],"tools":[]},"experience":[],"projects":[],"education":[],"achievements":[],"certifications":[]}`
+
+	client := &mockApplyLLM{response: llmResponse}
+
+	_, err := ExecuteApply(context.Background(), client, "sample resume text", AnalysisResultV2_3{}, ApplyHeaderInputs{Email: "user@example.com"}, false)
+	var missing MissingInputError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected MissingInputError, got %v", err)
+	}
+	if len(missing.NeedsInput) != 1 || missing.NeedsInput[0].Field != "header.phone" {
+		t.Fatalf("expected only the phone to be missing, got %+v", missing.NeedsInput)
+	}
+}
+
 func readDocumentXML(docxBytes []byte) (string, error) {
 	reader, err := zip.NewReader(bytes.NewReader(docxBytes), int64(len(docxBytes)))
 	if err != nil {
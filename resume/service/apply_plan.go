@@ -1,6 +1,10 @@
 package service
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // AnalysisResultV2_3 captures the analysis output needed for apply plan generation.
 type AnalysisResultV2_3 struct {
@@ -45,6 +49,44 @@ type ApplyPlan struct {
 	SafeRewrites    []BulletRewrite `json:"safeRewrites"`
 	NeedsInput      []string        `json:"needsInput"`
 	BlockedRewrites []BulletRewrite `json:"blockedRewrites"`
+	// Items explains, for every issue and rewrite, what the apply step will do with it.
+	Items []PlanItem `json:"items"`
+}
+
+// Plan item kinds.
+const (
+	PlanItemIssue   = "issue"
+	PlanItemRewrite = "rewrite"
+)
+
+// Plan item outcomes.
+const (
+	OutcomeAutoFix     = "auto_fix"
+	OutcomeSafeRewrite = "safe_rewrite"
+	OutcomeNeedsInput  = "needs_input"
+	OutcomeBlocked     = "blocked"
+	OutcomeSkipped     = "skipped"
+)
+
+// Reason codes attached to plan items that are not applied automatically.
+const (
+	ReasonNotAutoFixable     = "not_auto_fixable"
+	ReasonRequiresUserInput  = "requires_user_input"
+	ReasonPlaceholdersNeeded = "placeholders_needed"
+	ReasonMetricsNotInResume = "metrics_not_in_resume"
+	ReasonClaimNotSupported  = "claim_not_supported"
+)
+
+// PlanItem explains the outcome for one analysis issue or bullet rewrite.
+type PlanItem struct {
+	Kind        string   `json:"kind"`
+	Section     string   `json:"section"`
+	Outcome     string   `json:"outcome"`
+	Reasons     []string `json:"reasons"`
+	Explanation string   `json:"explanation"`
+	Problem     string   `json:"problem,omitempty"`
+	Before      string   `json:"before,omitempty"`
+	After       string   `json:"after,omitempty"`
 }
 
 // BuildApplyPlan derives an ApplyPlan from the v2_3 analysis output.
@@ -56,11 +98,13 @@ func BuildApplyPlan(analysis AnalysisResultV2_3) ApplyPlan {
 
 	autoFixes := make([]AnalysisIssue, 0, len(issues))
 	needsInput := make([]string, 0)
+	items := make([]PlanItem, 0, len(issues)+len(analysis.BulletRewrites))
 	seenInputs := make(map[string]struct{})
 	for _, issue := range issues {
 		if issue.AutoFixable {
 			autoFixes = append(autoFixes, issue)
 		}
+		items = append(items, explainIssue(issue))
 		for _, input := range issue.RequiresUserInput {
 			if _, ok := seenInputs[input]; ok {
 				continue
@@ -79,6 +123,7 @@ func BuildApplyPlan(analysis AnalysisResultV2_3) ApplyPlan {
 		if len(rewrite.PlaceholdersNeeded) > 0 {
 			blockedRewrites = append(blockedRewrites, rewrite)
 		}
+		items = append(items, explainRewrite(rewrite))
 	}
 
 	return ApplyPlan{
@@ -86,7 +131,73 @@ func BuildApplyPlan(analysis AnalysisResultV2_3) ApplyPlan {
 		SafeRewrites:    safeRewrites,
 		NeedsInput:      needsInput,
 		BlockedRewrites: blockedRewrites,
+		Items:           items,
+	}
+}
+
+func explainIssue(issue AnalysisIssue) PlanItem {
+	item := PlanItem{
+		Kind:    PlanItemIssue,
+		Section: issue.Section,
+		Problem: issue.Problem,
+		Reasons: []string{},
+	}
+	if !issue.AutoFixable {
+		item.Reasons = append(item.Reasons, ReasonNotAutoFixable)
+	}
+	if len(issue.RequiresUserInput) > 0 {
+		item.Reasons = append(item.Reasons, ReasonRequiresUserInput)
+	}
+	switch {
+	case issue.AutoFixable && len(issue.RequiresUserInput) == 0:
+		item.Outcome = OutcomeAutoFix
+		item.Explanation = "This will be fixed automatically."
+	case issue.AutoFixable:
+		item.Outcome = OutcomeAutoFix
+		item.Explanation = fmt.Sprintf("This will be fixed automatically; the result improves if you also provide: %s.", strings.Join(issue.RequiresUserInput, ", "))
+	case len(issue.RequiresUserInput) > 0:
+		item.Outcome = OutcomeNeedsInput
+		item.Explanation = fmt.Sprintf("This can't be fixed without details only you know: %s.", strings.Join(issue.RequiresUserInput, ", "))
+	default:
+		item.Outcome = OutcomeSkipped
+		item.Explanation = "This needs a judgement call, so it is left for you to edit by hand."
+	}
+	return item
+}
+
+func explainRewrite(rewrite BulletRewrite) PlanItem {
+	item := PlanItem{
+		Kind:    PlanItemRewrite,
+		Section: rewrite.Section,
+		Before:  rewrite.Before,
+		After:   rewrite.After,
+		Reasons: []string{},
+	}
+	var why []string
+	if len(rewrite.PlaceholdersNeeded) > 0 {
+		item.Reasons = append(item.Reasons, ReasonPlaceholdersNeeded)
+		why = append(why, fmt.Sprintf("it needs values you haven't provided (%s)", strings.Join(rewrite.PlaceholdersNeeded, ", ")))
+	}
+	if rewrite.MetricsSource != "resume" {
+		item.Reasons = append(item.Reasons, ReasonMetricsNotInResume)
+		why = append(why, "its numbers don't come from your resume")
+	}
+	if rewrite.ClaimSupport != "supported" {
+		item.Reasons = append(item.Reasons, ReasonClaimNotSupported)
+		why = append(why, "your resume doesn't back up what it claims")
+	}
+	switch {
+	case len(why) == 0:
+		item.Outcome = OutcomeSafeRewrite
+		item.Explanation = "This rewrite only uses facts from your resume, so it will be applied."
+	case len(rewrite.PlaceholdersNeeded) > 0:
+		item.Outcome = OutcomeBlocked
+		item.Explanation = "This rewrite is blocked because " + strings.Join(why, " and ") + "."
+	default:
+		item.Outcome = OutcomeSkipped
+		item.Explanation = "This rewrite won't be applied because " + strings.Join(why, " and ") + "."
 	}
+	return item
 }
 
 func isSafeRewrite(rewrite BulletRewrite) bool {
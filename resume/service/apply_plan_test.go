@@ -82,3 +82,50 @@ func TestBuildApplyPlanFiltersAndOrders(t *testing.T) {
 		t.Fatalf("expected blockedRewrite section B, got %q", plan.BlockedRewrites[0].Section)
 	}
 }
+
+func TestBuildApplyPlanExplainsEachItem(t *testing.T) {
+	analysis := AnalysisResultV2_3{
+		Issues: []AnalysisIssue{
+			{Section: "Summary", Problem: "Too long", Priority: 1, AutoFixable: true},
+			{Section: "Experience", Problem: "No metrics", Priority: 2, RequiresUserInput: []string{"team_size"}},
+		},
+		BulletRewrites: []BulletRewrite{
+			{Section: "A", Before: "a", After: "a2", MetricsSource: "resume", ClaimSupport: "supported"},
+			{Section: "B", Before: "b", After: "b2 with X", MetricsSource: "placeholder", ClaimSupport: "supported", PlaceholdersNeeded: []string{"X"}},
+			{Section: "C", Before: "c", After: "c2", MetricsSource: "resume", ClaimSupport: "unsupported"},
+		},
+	}
+
+	plan := BuildApplyPlan(analysis)
+
+	if len(plan.Items) != 5 {
+		t.Fatalf("expected 5 plan items, got %d", len(plan.Items))
+	}
+	cases := []struct {
+		outcome string
+		reasons []string
+	}{
+		{OutcomeAutoFix, nil},
+		{OutcomeNeedsInput, []string{ReasonNotAutoFixable, ReasonRequiresUserInput}},
+		{OutcomeSafeRewrite, nil},
+		{OutcomeBlocked, []string{ReasonPlaceholdersNeeded, ReasonMetricsNotInResume}},
+		{OutcomeSkipped, []string{ReasonClaimNotSupported}},
+	}
+	for i, tc := range cases {
+		item := plan.Items[i]
+		if item.Outcome != tc.outcome {
+			t.Fatalf("item %d: expected outcome %q, got %q", i, tc.outcome, item.Outcome)
+		}
+		if len(item.Reasons) != len(tc.reasons) {
+			t.Fatalf("item %d: expected reasons %v, got %v", i, tc.reasons, item.Reasons)
+		}
+		for j, reason := range tc.reasons {
+			if item.Reasons[j] != reason {
+				t.Fatalf("item %d: expected reasons %v, got %v", i, tc.reasons, item.Reasons)
+			}
+		}
+		if item.Explanation == "" {
+			t.Fatalf("item %d: expected an explanation", i)
+		}
+	}
+}
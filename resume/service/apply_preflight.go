@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"resume-backend/resume/contract"
+	"resume-backend/resume/model"
+)
+
+// Reasons a required header field needs input.
+const (
+	InputMissing = "missing"
+	InputInvalid = "invalid"
+)
+
+// RequiredInput is a header field the user must supply before an apply run can
+// render a resume.
+type RequiredInput struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Detected is the value found in the resume, if any.
+	Detected string `json:"detected,omitempty"`
+}
+
+// ApplyPreflight reports whether an apply run has the contact details it needs.
+type ApplyPreflight struct {
+	Ready      bool            `json:"ready"`
+	NeedsInput []RequiredInput `json:"needsInput"`
+	Header     ApplyHeaderView `json:"header"`
+}
+
+// ApplyHeaderView is the header the apply run would render, after user inputs.
+type ApplyHeaderView struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// MissingInputError is returned by ExecuteApply and PreviewApply when required
+// header fields are neither in the resume nor in the request.
+type MissingInputError struct {
+	NeedsInput []RequiredInput
+}
+
+func (e MissingInputError) Error() string {
+	fields := make([]string, 0, len(e.NeedsInput))
+	for _, input := range e.NeedsInput {
+		fields = append(fields, input.Field)
+	}
+	return "apply needs input: " + strings.Join(fields, ", ")
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+	digitPattern = regexp.MustCompile(`\d`)
+)
+
+// minPhoneDigits rejects fragments such as an extension or a year mistaken for a phone.
+const minPhoneDigits = 7
+
+// PreflightApply parses the resume, applies the header inputs and reports which
+// required contact fields are still missing or malformed.
+func PreflightApply(ctx context.Context, client LLMClient, resumeText string, headerInputs ApplyHeaderInputs) (ApplyPreflight, error) {
+	resumeModel, err := ResumeModelFromText(ctx, client, resumeText)
+	if err != nil {
+		return ApplyPreflight{}, err
+	}
+	detected := resumeModel.Header
+	applyHeaderInputs(&resumeModel, headerInputs, new([]ApplyChange))
+
+	needsInput := checkRequiredHeader(resumeModel.Header, detected)
+	return ApplyPreflight{
+		Ready:      len(needsInput) == 0,
+		NeedsInput: needsInput,
+		Header: ApplyHeaderView{
+			Name:  resumeModel.Header.Name,
+			Email: resumeModel.Header.Email,
+			Phone: resumeModel.Header.Phone,
+		},
+	}, nil
+}
+
+// checkRequiredHeader validates the header that will be rendered. detected is the
+// header as parsed from the resume, before user inputs, and is echoed back so the
+// client can prefill its form.
+func checkRequiredHeader(header, detected model.ResumeHeader) []RequiredInput {
+	needsInput := make([]RequiredInput, 0)
+	check := func(field, value, detectedValue, label string, valid func(string) bool) {
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "" || strings.HasPrefix(strings.ToUpper(value), contract.PlaceholderPrefix):
+			needsInput = append(needsInput, RequiredInput{Field: field, Reason: InputMissing, Message: label + " was not found in the resume; provide it in the request."})
+		case valid != nil && !valid(value):
+			needsInput = append(needsInput, RequiredInput{Field: field, Reason: InputInvalid, Message: label + " does not look valid; provide a corrected value.", Detected: strings.TrimSpace(detectedValue)})
+		}
+	}
+	check("header.name", header.Name, detected.Name, "Full name", nil)
+	check("header.email", header.Email, detected.Email, "Email", emailPattern.MatchString)
+	check("header.phone", header.Phone, detected.Phone, "Phone", func(v string) bool {
+		return len(digitPattern.FindAllString(v, -1)) >= minPhoneDigits
+	})
+	return needsInput
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+const preflightResume = `{"header":{"name":"Ada Lovelace","title":"","email":"ada@example","phone":"","location":"","links":[]},` +
+	`"summary":[],"skills":{"languages":["Go"],"frameworks":[],"databases":[],"cloudDevOps":[],"observability":[],"tools":[]},` +
+	`"experience":[],"projects":[],"education":[],"achievements":[],"certifications":[]}`
+
+func TestPreflightApplyReportsMissingContact(t *testing.T) {
+	client := &mockLLMClient{responses: []string{preflightResume, preflightResume}}
+
+	preflight, err := PreflightApply(context.Background(), client, "resume text", ApplyHeaderInputs{})
+	if err != nil {
+		t.Fatalf("PreflightApply failed: %v", err)
+	}
+	if preflight.Ready {
+		t.Fatalf("expected preflight not to be ready")
+	}
+	reasons := map[string]string{}
+	for _, input := range preflight.NeedsInput {
+		reasons[input.Field] = input.Reason
+	}
+	if len(reasons) != 2 || reasons["header.email"] != InputInvalid || reasons["header.phone"] != InputMissing {
+		t.Fatalf("unexpected needsInput: %+v", preflight.NeedsInput)
+	}
+	if preflight.NeedsInput[0].Detected != "ada@example" {
+		t.Fatalf("expected detected email to be echoed, got %+v", preflight.NeedsInput[0])
+	}
+
+	preflight, err = PreflightApply(context.Background(), client, "resume text", ApplyHeaderInputs{Email: "ada@example.com", Phone: "+44 20 7946 0958"})
+	if err != nil {
+		t.Fatalf("PreflightApply failed: %v", err)
+	}
+	if !preflight.Ready || len(preflight.NeedsInput) != 0 {
+		t.Fatalf("expected request inputs to complete the header, got %+v", preflight)
+	}
+	if preflight.Header.Email != "ada@example.com" {
+		t.Fatalf("expected request email in header, got %q", preflight.Header.Email)
+	}
+}
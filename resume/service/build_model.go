@@ -15,12 +15,9 @@ type LLMClient interface {
 	Complete(ctx context.Context, prompt string) (string, error)
 }
 
-// Client is the configured LLM client used by BuildResumeModel.
-var Client LLMClient
-
-// BuildResumeModel builds a ResumeModel by calling the LLM and validating output.
-func BuildResumeModel(ctx context.Context, resumeText string) (model.ResumeModel, error) {
-	if Client == nil {
+// BuildResumeModel builds a ResumeModel by calling client and validating output.
+func BuildResumeModel(ctx context.Context, client LLMClient, resumeText string) (model.ResumeModel, error) {
+	if client == nil {
 		return model.ResumeModel{}, errors.New("llm client is not configured")
 	}
 
@@ -28,7 +25,7 @@ func BuildResumeModel(ctx context.Context, resumeText string) (model.ResumeModel
 
 	var lastErr error
 	for attempt := 0; attempt < 2; attempt++ {
-		raw, err := Client.Complete(ctx, prompt)
+		raw, err := client.Complete(ctx, prompt)
 		if err != nil {
 			return model.ResumeModel{}, err
 		}
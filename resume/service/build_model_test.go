@@ -34,13 +34,7 @@ func TestBuildResumeModelRetriesOnSchemaViolation(t *testing.T) {
 		},
 	}
 
-	prevClient := Client
-	Client = mock
-	defer func() {
-		Client = prevClient
-	}()
-
-	resumeModel, err := BuildResumeModel(context.Background(), "Sample resume text")
+	resumeModel, err := BuildResumeModel(context.Background(), mock, "Sample resume text")
 	if err != nil {
 		t.Fatalf("expected success, got error: %v", err)
 	}
@@ -53,13 +47,7 @@ func TestBuildResumeModelRetriesOnSchemaViolation(t *testing.T) {
 }
 
 func TestBuildResumeModelFailsWithoutClient(t *testing.T) {
-	prevClient := Client
-	Client = nil
-	defer func() {
-		Client = prevClient
-	}()
-
-	_, err := BuildResumeModel(context.Background(), "Sample resume text")
+	_, err := BuildResumeModel(context.Background(), nil, "Sample resume text")
 	if err == nil {
 		t.Fatal("expected error when client is not configured")
 	}
@@ -74,13 +62,7 @@ func TestBuildResumeModelValidatesOutput(t *testing.T) {
 		responses: []string{response},
 	}
 
-	prevClient := Client
-	Client = mock
-	defer func() {
-		Client = prevClient
-	}()
-
-	got, err := BuildResumeModel(context.Background(), "Sample resume text")
+	got, err := BuildResumeModel(context.Background(), mock, "Sample resume text")
 	if err != nil {
 		t.Fatalf("expected success, got error: %v", err)
 	}
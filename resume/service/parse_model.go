@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"resume-backend/resume/model"
+)
+
+// ResumeModelFromText builds the ResumeModel the apply flow works on: with
+// client when one is configured, otherwise with ParseResumeModel.
+func ResumeModelFromText(ctx context.Context, client LLMClient, resumeText string) (model.ResumeModel, error) {
+	if client == nil {
+		return ParseResumeModel(resumeText)
+	}
+	return BuildResumeModel(ctx, client, resumeText)
+}
+
+// ParseResumeModel builds a ResumeModel from plain resume text without an LLM.
+// Sections are found by their headings, the header is the text above the
+// first heading, and bullets keep their exact wording so the analysis's
+// rewrites still match them. Anything it cannot place is left out rather than
+// guessed.
+func ParseResumeModel(resumeText string) (model.ResumeModel, error) {
+	p := resumeTextParser{}
+	for _, raw := range strings.Split(strings.ReplaceAll(resumeText, "\r\n", "\n"), "\n") {
+		p.line(raw)
+	}
+	p.flushSummary()
+	resumeModel := p.model
+	resumeModel.Header.Links = normalizeLinks(resumeModel.Header.Links)
+	for i := range resumeModel.Experience {
+		resumeModel.Experience[i].ID = fmt.Sprintf("exp_%d", i+1)
+	}
+	if strings.TrimSpace(resumeModel.Header.Name) == "" {
+		return model.ResumeModel{}, errors.New("resume text has no name line")
+	}
+	if err := resumeModel.Validate(); err != nil {
+		return model.ResumeModel{}, err
+	}
+	return resumeModel, nil
+}
+
+// Resume sections recognised by heading. Text above the first heading is the
+// header.
+const (
+	sectionSummary        = "summary"
+	sectionExperience     = "experience"
+	sectionEducation      = "education"
+	sectionSkills         = "skills"
+	sectionProjects       = "projects"
+	sectionCertifications = "certifications"
+	sectionAchievements   = "achievements"
+	sectionIgnored        = "ignored"
+)
+
+var sectionHeadings = map[string]string{
+	"summary":                     sectionSummary,
+	"professional summary":        sectionSummary,
+	"profile":                     sectionSummary,
+	"professional profile":        sectionSummary,
+	"about":                       sectionSummary,
+	"about me":                    sectionSummary,
+	"objective":                   sectionSummary,
+	"career objective":            sectionSummary,
+	"experience":                  sectionExperience,
+	"work experience":             sectionExperience,
+	"professional experience":     sectionExperience,
+	"relevant experience":         sectionExperience,
+	"employment":                  sectionExperience,
+	"employment history":          sectionExperience,
+	"work history":                sectionExperience,
+	"education":                   sectionEducation,
+	"education and training":      sectionEducation,
+	"skills":                      sectionSkills,
+	"technical skills":            sectionSkills,
+	"core skills":                 sectionSkills,
+	"key skills":                  sectionSkills,
+	"core competencies":           sectionSkills,
+	"projects":                    sectionProjects,
+	"personal projects":           sectionProjects,
+	"selected projects":           sectionProjects,
+	"certifications":              sectionCertifications,
+	"certificates":                sectionCertifications,
+	"licenses and certifications": sectionCertifications,
+	"achievements":                sectionAchievements,
+	"awards":                      sectionAchievements,
+	"honors and awards":           sectionAchievements,
+	"accomplishments":             sectionAchievements,
+	"interests":                   sectionIgnored,
+	"hobbies":                     sectionIgnored,
+	"languages":                   sectionIgnored,
+	"references":                  sectionIgnored,
+	"volunteering":                sectionIgnored,
+	"publications":                sectionIgnored,
+}
+
+var (
+	monthDatePattern   = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+(\d{4})\b`)
+	numericDatePattern = regexp.MustCompile(`\b(\d{4})-(0[1-9]|1[0-2])\b|\b(0?[1-9]|1[0-2])/(\d{4})\b`)
+	presentPattern     = regexp.MustCompile(`(?i)\b(present|current|now)\b`)
+	degreePattern      = regexp.MustCompile(`(?i)\b(bachelor|master|doctor|associate|diploma|degree|ph\.?d|mba|b\.?sc?|m\.?sc?|b\.?a|m\.?a|b\.?eng|m\.?eng|b\.?tech|m\.?tech)\b`)
+	bulletPrefixes     = []string{"-", "•", "*", "▪", "·", "◦", "‣", "–"}
+)
+
+var months = map[string]string{
+	"jan": "01", "feb": "02", "mar": "03", "apr": "04", "may": "05", "jun": "06",
+	"jul": "07", "aug": "08", "sep": "09", "sept": "09", "oct": "10", "nov": "11", "dec": "12",
+}
+
+type resumeTextParser struct {
+	model   model.ResumeModel
+	section string
+	// summary collects the current summary paragraph across wrapped lines.
+	summary []string
+}
+
+func (p *resumeTextParser) line(raw string) {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		p.flushSummary()
+		return
+	}
+	if section, ok := sectionHeadings[headingKey(text)]; ok {
+		p.flushSummary()
+		p.section = section
+		return
+	}
+	bullet, isBullet := stripBullet(text)
+	switch p.section {
+	case "":
+		p.headerLine(text)
+	case sectionSummary:
+		if isBullet {
+			p.flushSummary()
+			p.model.Summary = append(p.model.Summary, bullet)
+			return
+		}
+		p.summary = append(p.summary, text)
+	case sectionExperience:
+		p.experienceLine(text, bullet, isBullet)
+	case sectionEducation:
+		p.educationLine(text, bullet, isBullet)
+	case sectionSkills:
+		p.skillsLine(bullet)
+	case sectionProjects:
+		p.projectLine(text, bullet, isBullet)
+	case sectionCertifications:
+		start, _, rest := extractDates(bullet)
+		name, issuer := splitPair(rest)
+		p.model.Certifications = append(p.model.Certifications, model.ResumeCertification{Name: name, Issuer: issuer, Date: start})
+	case sectionAchievements:
+		start, _, rest := extractDates(bullet)
+		p.model.Achievements = append(p.model.Achievements, model.ResumeAchievement{Title: rest, Date: start})
+	}
+}
+
+func (p *resumeTextParser) flushSummary() {
+	if len(p.summary) == 0 {
+		return
+	}
+	p.model.Summary = append(p.model.Summary, strings.Join(p.summary, " "))
+	p.summary = nil
+}
+
+func (p *resumeTextParser) headerLine(text string) {
+	header := &p.model.Header
+	contact := false
+	var rest []string
+	for _, part := range splitFields(text) {
+		value := stripLabel(part)
+		switch {
+		case header.Email == "" && emailPattern.MatchString(value):
+			header.Email = value
+			contact = true
+		case header.Phone == "" && looksLikePhone(value):
+			header.Phone = value
+			contact = true
+		case looksLikeURL(value):
+			header.Links = append(header.Links, model.ResumeLink{URL: value})
+			contact = true
+		default:
+			rest = append(rest, part)
+		}
+	}
+	// A line with contact details is a name line only when no name came
+	// before it, as in a one-line header; otherwise its other fields are a
+	// location rather than a title.
+	named := false
+	for _, part := range rest {
+		switch {
+		case header.Name == "":
+			header.Name = part
+			named = true
+		case header.Title == "" && (!contact || named) && !strings.Contains(part, ","):
+			header.Title = part
+		case header.Location == "":
+			header.Location = part
+		}
+	}
+}
+
+func (p *resumeTextParser) experienceLine(text, bullet string, isBullet bool) {
+	entries := p.model.Experience
+	if isBullet {
+		if len(entries) == 0 {
+			p.model.Experience = append(p.model.Experience, model.ResumeExperience{})
+			entries = p.model.Experience
+		}
+		last := &entries[len(entries)-1]
+		last.Highlights = append(last.Highlights, bullet)
+		return
+	}
+	if len(entries) > 0 {
+		last := &entries[len(entries)-1]
+		if n := len(last.Highlights); n > 0 && startsLower(text) {
+			last.Highlights[n-1] += " " + text
+			return
+		}
+		start, end, rest := extractDates(text)
+		if len(last.Highlights) == 0 && (last.Start == "" && start != "" || last.Company == "") {
+			// A second line of the same entry: its dates, company or location.
+			if start != "" && last.Start == "" {
+				last.Start, last.End = start, end
+			}
+			if rest != "" {
+				if last.Company == "" {
+					last.Company, last.Location = splitPair(rest)
+				} else if last.Location == "" {
+					last.Location = rest
+				}
+			}
+			return
+		}
+	}
+	start, end, rest := extractDates(text)
+	entry := model.ResumeExperience{Start: start, End: end}
+	parts := splitEntry(rest)
+	if len(parts) > 0 {
+		entry.Role = parts[0]
+	}
+	if len(parts) > 1 {
+		entry.Company = parts[1]
+	}
+	if len(parts) > 2 {
+		entry.Location = strings.Join(parts[2:], ", ")
+	}
+	p.model.Experience = append(p.model.Experience, entry)
+}
+
+func (p *resumeTextParser) educationLine(text, bullet string, isBullet bool) {
+	if isBullet && len(p.model.Education) > 0 {
+		last := &p.model.Education[len(p.model.Education)-1]
+		last.Highlights = append(last.Highlights, bullet)
+		return
+	}
+	start, end, rest := extractDates(bullet)
+	var last *model.ResumeEducation
+	if n := len(p.model.Education); n > 0 {
+		last = &p.model.Education[n-1]
+	}
+	if degreePattern.MatchString(rest) {
+		degree, field := rest, ""
+		if i := strings.Index(strings.ToLower(rest), " in "); i > 0 {
+			degree, field = strings.TrimSpace(rest[:i]), strings.TrimSpace(rest[i+4:])
+		}
+		if last == nil || last.Degree != "" {
+			p.model.Education = append(p.model.Education, model.ResumeEducation{})
+			last = &p.model.Education[len(p.model.Education)-1]
+		}
+		last.Degree, last.Field = degree, field
+	} else if rest != "" {
+		if last == nil || last.Institution != "" {
+			p.model.Education = append(p.model.Education, model.ResumeEducation{})
+			last = &p.model.Education[len(p.model.Education)-1]
+		}
+		last.Institution, last.Location = splitPair(rest)
+	}
+	if last != nil && start != "" && last.Start == "" {
+		last.Start, last.End = start, end
+	}
+}
+
+func (p *resumeTextParser) skillsLine(text string) {
+	if i := strings.Index(text, ":"); i > 0 && i < len(text)-1 {
+		text = text[i+1:]
+	}
+	for _, skill := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || r == '•' || r == '·'
+	}) {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			p.model.Skills.Tools = append(p.model.Skills.Tools, skill)
+		}
+	}
+}
+
+func (p *resumeTextParser) projectLine(text, bullet string, isBullet bool) {
+	if n := len(p.model.Projects); n > 0 {
+		last := &p.model.Projects[n-1]
+		if isBullet {
+			last.Highlights = append(last.Highlights, bullet)
+			return
+		}
+		// The line after a project's name describes it, unless it carries
+		// dates of its own and so names the next project.
+		if last.Description == "" && len(last.Highlights) == 0 {
+			if start, _, _ := extractDates(text); start == "" {
+				last.Description = text
+				return
+			}
+		}
+	}
+	start, end, rest := extractDates(bullet)
+	p.model.Projects = append(p.model.Projects, model.ResumeProject{Name: rest, Start: start, End: end})
+}
+
+// headingKey normalizes a line for the heading lookup: lower case, without
+// trailing colons or decoration.
+func headingKey(text string) string {
+	if len(text) > 40 {
+		return ""
+	}
+	key := strings.ToLower(strings.Trim(text, " :-_=*#"))
+	key = strings.ReplaceAll(key, "&", "and")
+	return strings.Join(strings.Fields(key), " ")
+}
+
+func stripBullet(text string) (string, bool) {
+	for _, prefix := range bulletPrefixes {
+		rest, ok := strings.CutPrefix(text, prefix)
+		if !ok || strings.TrimSpace(rest) == "" {
+			continue
+		}
+		// A hyphen or asterisk only marks a bullet when a space follows it.
+		if (prefix == "-" || prefix == "*") && rest[0] != ' ' {
+			continue
+		}
+		return strings.TrimSpace(rest), true
+	}
+	return text, false
+}
+
+// extractDates finds a start and end date in text and returns the text
+// without them. Dates become YYYY-MM or Present; bare years are left in place
+// since the model has no way to hold them.
+func extractDates(text string) (start, end, rest string) {
+	type match struct {
+		at    int
+		end   int
+		value string
+	}
+	var found []match
+	for _, loc := range monthDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		month := months[strings.ToLower(text[loc[2]:loc[3]])]
+		found = append(found, match{loc[0], loc[1], text[loc[4]:loc[5]] + "-" + month})
+	}
+	for _, loc := range numericDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		if loc[2] >= 0 {
+			found = append(found, match{loc[0], loc[1], text[loc[2]:loc[3]] + "-" + text[loc[4]:loc[5]]})
+		} else {
+			month := text[loc[6]:loc[7]]
+			if len(month) == 1 {
+				month = "0" + month
+			}
+			found = append(found, match{loc[0], loc[1], text[loc[8]:loc[9]] + "-" + month})
+		}
+	}
+	if len(found) == 0 {
+		return "", "", text
+	}
+	for _, loc := range presentPattern.FindAllStringIndex(text, -1) {
+		found = append(found, match{loc[0], loc[1], "Present"})
+	}
+	first, last := found[0], found[0]
+	for _, m := range found {
+		if m.at < first.at {
+			first = m
+		}
+		if m.at > last.at {
+			last = m
+		}
+	}
+	start = first.value
+	if last.at != first.at {
+		end = last.value
+	}
+	rest = strings.TrimSpace(text[:first.at] + " " + text[last.end:])
+	rest = strings.Trim(rest, " ,|()–—-")
+	return start, end, rest
+}
+
+// splitFields splits a header line on the separators resumes put between
+// contact details.
+func splitFields(text string) []string {
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return r == '|' || r == '•' || r == '·' || r == ';' || r == '\t'
+	})
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// splitEntry splits an experience line such as "Engineer at Acme, Berlin" or
+// "Engineer | Acme" into its parts, role first.
+func splitEntry(text string) []string {
+	for _, sep := range []string{" | ", " at ", " @ ", " — ", " – ", " - "} {
+		if strings.Contains(text, sep) {
+			parts := strings.SplitN(text, sep, 2)
+			out := []string{strings.TrimSpace(parts[0])}
+			company, location := splitPair(parts[1])
+			out = append(out, company)
+			if location != "" {
+				out = append(out, location)
+			}
+			return out
+		}
+	}
+	parts := strings.SplitN(text, ", ", 3)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if len(parts) == 1 && parts[0] == "" {
+		return nil
+	}
+	return parts
+}
+
+// splitPair splits "Acme, Berlin" or "Acme — Berlin" into its two halves.
+func splitPair(text string) (string, string) {
+	for _, sep := range []string{" | ", " — ", " – ", " - ", ", "} {
+		if first, second, ok := strings.Cut(text, sep); ok {
+			return strings.TrimSpace(first), strings.TrimSpace(second)
+		}
+	}
+	return strings.TrimSpace(text), ""
+}
+
+// stripLabel drops a "Email:" style label in front of a contact value.
+func stripLabel(part string) string {
+	if label, value, ok := strings.Cut(part, ":"); ok && !strings.Contains(label, " ") && !strings.HasPrefix(value, "//") {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(part)
+}
+
+func looksLikePhone(value string) bool {
+	if len(digitPattern.FindAllString(value, -1)) < minPhoneDigits {
+		return false
+	}
+	for _, r := range value {
+		if !unicode.IsDigit(r) && !strings.ContainsRune("+()-. x", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func looksLikeURL(value string) bool {
+	if strings.ContainsAny(value, " @") {
+		return false
+	}
+	lower := strings.ToLower(value)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "www.") {
+		return true
+	}
+	host, _, _ := strings.Cut(lower, "/")
+	return strings.Contains(host, ".") && strings.Contains(lower, "/")
+}
+
+// normalizeLinks makes links full URLs and labels the well-known sites.
+func normalizeLinks(links []model.ResumeLink) []model.ResumeLink {
+	for i, link := range links {
+		url := link.URL
+		lower := strings.ToLower(url)
+		if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			url = "https://" + url
+		}
+		links[i].URL = url
+		switch {
+		case strings.Contains(lower, "linkedin.com"):
+			links[i].Label = "LinkedIn"
+		case strings.Contains(lower, "github.com"):
+			links[i].Label = "GitHub"
+		}
+	}
+	return links
+}
+
+func startsLower(text string) bool {
+	for _, r := range text {
+		return unicode.IsLower(r)
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+const plainResume = `Jane Doe
+Senior Backend Engineer
+jane@example.com | +1 (555) 123-4567 | Berlin, Germany | linkedin.com/in/janedoe
+
+SUMMARY
+Backend engineer with eight years of experience
+building payment systems.
+
+EXPERIENCE
+Senior Engineer, Acme Corp, Berlin
+Jan 2021 – Present
+• Cut checkout latency by 40% by caching tax lookups
+• Led the migration to Postgres, moving 2TB
+  without downtime
+
+Engineer at Initech | 03/2017 - 12/2020
+- Built the billing service
+
+EDUCATION
+Technical University of Munich
+BSc in Computer Science, Sep 2012 – Jul 2016
+
+SKILLS
+Languages: Go, Python, SQL
+Tools: Docker; Kubernetes
+
+INTERESTS
+Climbing
+`
+
+func TestParseResumeModel(t *testing.T) {
+	resumeModel, err := ParseResumeModel(plainResume)
+	if err != nil {
+		t.Fatalf("ParseResumeModel failed: %v", err)
+	}
+	header := resumeModel.Header
+	if header.Name != "Jane Doe" || header.Title != "Senior Backend Engineer" || header.Email != "jane@example.com" ||
+		header.Phone != "+1 (555) 123-4567" || header.Location != "Berlin, Germany" {
+		t.Fatalf("header = %+v", header)
+	}
+	if len(header.Links) != 1 || header.Links[0].URL != "https://linkedin.com/in/janedoe" || header.Links[0].Label != "LinkedIn" {
+		t.Fatalf("links = %+v", header.Links)
+	}
+	if len(resumeModel.Summary) != 1 || resumeModel.Summary[0] != "Backend engineer with eight years of experience building payment systems." {
+		t.Fatalf("summary = %q", resumeModel.Summary)
+	}
+
+	if len(resumeModel.Experience) != 2 {
+		t.Fatalf("experience = %+v", resumeModel.Experience)
+	}
+	acme := resumeModel.Experience[0]
+	if acme.ID != "exp_1" || acme.Role != "Senior Engineer" || acme.Company != "Acme Corp" || acme.Location != "Berlin" ||
+		acme.Start != "2021-01" || acme.End != "Present" {
+		t.Fatalf("first entry = %+v", acme)
+	}
+	if len(acme.Highlights) != 2 || acme.Highlights[1] != "Led the migration to Postgres, moving 2TB without downtime" {
+		t.Fatalf("first entry highlights = %q", acme.Highlights)
+	}
+	initech := resumeModel.Experience[1]
+	if initech.Role != "Engineer" || initech.Company != "Initech" || initech.Start != "2017-03" || initech.End != "2020-12" ||
+		len(initech.Highlights) != 1 {
+		t.Fatalf("second entry = %+v", initech)
+	}
+
+	if len(resumeModel.Education) != 1 {
+		t.Fatalf("education = %+v", resumeModel.Education)
+	}
+	edu := resumeModel.Education[0]
+	if edu.Institution != "Technical University of Munich" || edu.Degree != "BSc" || edu.Field != "Computer Science" ||
+		edu.Start != "2012-09" || edu.End != "2016-07" {
+		t.Fatalf("education entry = %+v", edu)
+	}
+	if got := resumeModel.Skills.Tools; len(got) != 5 || got[0] != "Go" || got[4] != "Kubernetes" {
+		t.Fatalf("skills = %q", got)
+	}
+}
+
+func TestParseResumeModelOneLineHeader(t *testing.T) {
+	resumeModel, err := ParseResumeModel("Jane Doe | Senior Engineer | jane@x.com | 555-123-4567 | Berlin, Germany\n\nEXPERIENCE\nEngineer, Acme\n- Built the billing service")
+	if err != nil {
+		t.Fatalf("ParseResumeModel failed: %v", err)
+	}
+	header := resumeModel.Header
+	if header.Name != "Jane Doe" || header.Title != "Senior Engineer" || header.Email != "jane@x.com" ||
+		header.Phone != "555-123-4567" || header.Location != "Berlin, Germany" {
+		t.Fatalf("header = %+v", header)
+	}
+}
+
+func TestParseResumeModelRequiresName(t *testing.T) {
+	if _, err := ParseResumeModel("EXPERIENCE\n- Built things"); err == nil {
+		t.Fatal("expected an error without a name line")
+	}
+}
+
+func TestPreviewApplyWithoutClientAppliesRewrites(t *testing.T) {
+	analysis := AnalysisResultV2_3{
+		BulletRewrites: []BulletRewrite{{
+			Section:       "experience",
+			Before:        "Built the billing service",
+			After:         "Built the billing service handling 1M invoices a month",
+			MetricsSource: "resume",
+			ClaimSupport:  "supported",
+		}},
+	}
+
+	result, err := PreviewApply(context.Background(), nil, plainResume, analysis, ApplyHeaderInputs{}, false)
+	if err != nil {
+		t.Fatalf("PreviewApply failed: %v", err)
+	}
+	if result.SafeRewritesApplied != 1 {
+		t.Fatalf("expected the rewrite to match the parsed bullet, got %+v", result.Changes)
+	}
+}
@@ -0,0 +1,315 @@
+package service
+
+import "strings"
+
+// Diff operations on a line of resume text.
+const (
+	DiffEqual   = "equal"
+	DiffInsert  = "insert"
+	DiffDelete  = "delete"
+	DiffReplace = "replace"
+)
+
+// Section statuses in a TextDiff.
+const (
+	SectionUnchanged = "unchanged"
+	SectionChanged   = "changed"
+	SectionAdded     = "added"
+	SectionRemoved   = "removed"
+)
+
+const (
+	// headerSection names the text above the first heading.
+	headerSection = "header"
+	// replaceSimilarity is the word overlap at which a deleted and an
+	// inserted line are reported as one rewritten line.
+	replaceSimilarity = 0.5
+	// maxDiffCells bounds the table a section's line diff may build; larger
+	// sections are reported as wholly rewritten.
+	maxDiffCells = 1 << 20
+)
+
+// TextDiff compares the original resume text with a generated one, section by
+// section and line by line.
+type TextDiff struct {
+	Summary  TextDiffSummary `json:"summary"`
+	Sections []SectionDiff   `json:"sections"`
+}
+
+// TextDiffSummary counts the line operations across all sections.
+type TextDiffSummary struct {
+	Unchanged int `json:"unchanged"`
+	Inserted  int `json:"inserted"`
+	Deleted   int `json:"deleted"`
+	Replaced  int `json:"replaced"`
+}
+
+// SectionDiff is one section's lines. Section is the recognised section key,
+// so "Work Experience" and "EXPERIENCE" compare as the same section; Heading
+// is the heading as the generated text writes it, or the original's for a
+// removed section.
+type SectionDiff struct {
+	Section string     `json:"section"`
+	Heading string     `json:"heading,omitempty"`
+	Status  string     `json:"status"`
+	Lines   []LineDiff `json:"lines"`
+}
+
+// LineDiff is one operation. Before is set for equal, delete and replace;
+// After for equal, insert and replace. Bullet marks lines written as bullets
+// on either side; bullet markers are left out of Before and After.
+type LineDiff struct {
+	Op     string `json:"op"`
+	Bullet bool   `json:"bullet,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// DiffResumeText diffs the original and generated resume text. Sections keep
+// the generated order, followed by any the generated text dropped. Lines
+// compare case-insensitively, ignoring spacing and bullet markers.
+func DiffResumeText(original, generated string) TextDiff {
+	before := splitDiffSections(original)
+	after := splitDiffSections(generated)
+
+	byKey := make(map[string]diffSection, len(before))
+	for _, sec := range before {
+		byKey[sec.key] = sec
+	}
+	diff := TextDiff{Sections: []SectionDiff{}}
+	seen := make(map[string]bool, len(after))
+	for _, sec := range after {
+		seen[sec.key] = true
+		old, ok := byKey[sec.key]
+		out := SectionDiff{Section: sec.key, Heading: sec.heading}
+		if !ok {
+			out.Status = SectionAdded
+		}
+		out.Lines = diffLines(old.lines, sec.lines)
+		if out.Status == "" {
+			out.Status = SectionUnchanged
+			for _, line := range out.Lines {
+				if line.Op != DiffEqual {
+					out.Status = SectionChanged
+					break
+				}
+			}
+		}
+		diff.add(out)
+	}
+	for _, sec := range before {
+		if seen[sec.key] {
+			continue
+		}
+		diff.add(SectionDiff{
+			Section: sec.key,
+			Heading: sec.heading,
+			Status:  SectionRemoved,
+			Lines:   diffLines(sec.lines, nil),
+		})
+	}
+	return diff
+}
+
+func (d *TextDiff) add(sec SectionDiff) {
+	if len(sec.Lines) == 0 && sec.Status == SectionUnchanged {
+		return
+	}
+	for _, line := range sec.Lines {
+		switch line.Op {
+		case DiffEqual:
+			d.Summary.Unchanged++
+		case DiffInsert:
+			d.Summary.Inserted++
+		case DiffDelete:
+			d.Summary.Deleted++
+		case DiffReplace:
+			d.Summary.Replaced++
+		}
+	}
+	d.Sections = append(d.Sections, sec)
+}
+
+type diffSection struct {
+	key     string
+	heading string
+	lines   []diffLine
+}
+
+type diffLine struct {
+	text   string
+	bullet bool
+	// norm is the text the comparison uses.
+	norm string
+}
+
+// splitDiffSections splits text at the headings ParseResumeModel recognises.
+// A heading seen twice, as in a resume with two experience blocks, continues
+// its first section.
+func splitDiffSections(text string) []diffSection {
+	sections := []diffSection{{key: headerSection}}
+	index := map[string]int{headerSection: 0}
+	current := 0
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		if key, ok := diffSectionKey(line); ok {
+			if i, ok := index[key]; ok {
+				current = i
+				continue
+			}
+			index[key] = len(sections)
+			current = len(sections)
+			sections = append(sections, diffSection{key: key, heading: strings.TrimSuffix(line, ":")})
+			continue
+		}
+		body, bullet := stripBullet(line)
+		sections[current].lines = append(sections[current].lines, diffLine{
+			text:   body,
+			bullet: bullet,
+			norm:   strings.ToLower(strings.Join(strings.Fields(body), " ")),
+		})
+	}
+	if len(sections[0].lines) == 0 {
+		sections = sections[1:]
+	}
+	return sections
+}
+
+// diffSectionKey returns the section a heading line starts. Headings the
+// parser ignores keep their own name so they still pair up with themselves.
+func diffSectionKey(line string) (string, bool) {
+	key := headingKey(line)
+	section, ok := sectionHeadings[key]
+	if !ok {
+		return "", false
+	}
+	if section == sectionIgnored {
+		return key, true
+	}
+	return section, true
+}
+
+// diffLines aligns two sections' lines on their longest common subsequence,
+// then reports a deleted line and an inserted one that share most of their
+// words as a single replace.
+func diffLines(before, after []diffLine) []LineDiff {
+	n, m := len(before), len(after)
+	if n*m > maxDiffCells {
+		return pairChanges(before, after)
+	}
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if before[i].norm == after[j].norm {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []LineDiff
+	var deleted, inserted []diffLine
+	flush := func() {
+		out = append(out, pairChanges(deleted, inserted)...)
+		deleted, inserted = deleted[:0], inserted[:0]
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && before[i].norm == after[j].norm:
+			flush()
+			out = append(out, LineDiff{
+				Op:     DiffEqual,
+				Bullet: before[i].bullet || after[j].bullet,
+				Before: before[i].text,
+				After:  after[j].text,
+			})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			inserted = append(inserted, after[j])
+			j++
+		default:
+			deleted = append(deleted, before[i])
+			i++
+		}
+	}
+	flush()
+	return out
+}
+
+// pairChanges reports a run of deleted and inserted lines. Each inserted line
+// replaces the most similar deleted line that is similar enough; the deleted
+// lines left over come first.
+func pairChanges(deleted, inserted []diffLine) []LineDiff {
+	if len(deleted) == 0 && len(inserted) == 0 {
+		return nil
+	}
+	paired := make([]int, len(inserted))
+	used := make([]bool, len(deleted))
+	for j, ins := range inserted {
+		paired[j] = -1
+		best := replaceSimilarity
+		for i, del := range deleted {
+			if used[i] {
+				continue
+			}
+			if score := wordOverlap(del.norm, ins.norm); score >= best {
+				best, paired[j] = score, i
+			}
+		}
+		if paired[j] >= 0 {
+			used[paired[j]] = true
+		}
+	}
+
+	var out []LineDiff
+	for i, del := range deleted {
+		if !used[i] {
+			out = append(out, LineDiff{Op: DiffDelete, Bullet: del.bullet, Before: del.text})
+		}
+	}
+	for j, ins := range inserted {
+		if i := paired[j]; i >= 0 {
+			out = append(out, LineDiff{
+				Op:     DiffReplace,
+				Bullet: deleted[i].bullet || ins.bullet,
+				Before: deleted[i].text,
+				After:  ins.text,
+			})
+			continue
+		}
+		out = append(out, LineDiff{Op: DiffInsert, Bullet: ins.bullet, After: ins.text})
+	}
+	return out
+}
+
+// wordOverlap is the Dice coefficient of two lines' word sets.
+func wordOverlap(a, b string) float64 {
+	wordsA := strings.Fields(a)
+	wordsB := strings.Fields(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(wordsA))
+	for _, w := range wordsA {
+		set[strings.Trim(w, ".,;:()")] = true
+	}
+	shared := 0
+	seen := make(map[string]bool, len(wordsB))
+	for _, w := range wordsB {
+		w = strings.Trim(w, ".,;:()")
+		if set[w] && !seen[w] {
+			shared++
+		}
+		seen[w] = true
+	}
+	return 2 * float64(shared) / float64(len(set)+len(seen))
+}
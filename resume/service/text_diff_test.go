@@ -0,0 +1,100 @@
+package service
+
+import "testing"
+
+func TestDiffResumeText(t *testing.T) {
+	generated := `Jane Doe
+Senior Backend Engineer
+jane@example.com | +1 (555) 123-4567 | Berlin, Germany | linkedin.com/in/janedoe
+
+Professional Summary
+Backend engineer with eight years of experience
+building payment systems.
+
+Work Experience
+Senior Engineer, Acme Corp, Berlin
+Jan 2021 – Present
+Cut checkout latency by 40% by caching tax lookups for 3M orders a month
+• Led the migration to Postgres, moving 2TB
+  without downtime
+Engineer at Initech | 03/2017 - 12/2020
+- Built the billing service
+- Introduced contract tests for partner APIs
+
+Skills
+Languages: Go, Python, SQL
+Tools: Docker; Kubernetes
+
+Certifications
+AWS Solutions Architect
+`
+	diff := DiffResumeText(plainResume, generated)
+
+	sections := map[string]SectionDiff{}
+	var order []string
+	for _, sec := range diff.Sections {
+		sections[sec.Section] = sec
+		order = append(order, sec.Section)
+	}
+	wantOrder := []string{"header", sectionSummary, sectionExperience, sectionSkills, sectionCertifications, sectionEducation, "interests"}
+	if len(order) != len(wantOrder) {
+		t.Fatalf("sections = %v, want %v", order, wantOrder)
+	}
+	for i := range wantOrder {
+		if order[i] != wantOrder[i] {
+			t.Fatalf("sections = %v, want %v", order, wantOrder)
+		}
+	}
+
+	for key, status := range map[string]string{
+		"header":              SectionUnchanged,
+		sectionSummary:        SectionUnchanged,
+		sectionExperience:     SectionChanged,
+		sectionSkills:         SectionUnchanged,
+		sectionCertifications: SectionAdded,
+		sectionEducation:      SectionRemoved,
+		"interests":           SectionRemoved,
+	} {
+		if got := sections[key].Status; got != status {
+			t.Errorf("%s status = %q, want %q", key, got, status)
+		}
+	}
+	if got := sections[sectionExperience].Heading; got != "Work Experience" {
+		t.Errorf("experience heading = %q", got)
+	}
+
+	var replaced, inserted []LineDiff
+	for _, line := range sections[sectionExperience].Lines {
+		switch line.Op {
+		case DiffReplace:
+			replaced = append(replaced, line)
+		case DiffInsert:
+			inserted = append(inserted, line)
+		case DiffDelete:
+			t.Errorf("unexpected delete %+v", line)
+		}
+	}
+	if len(replaced) != 1 || replaced[0].Before != "Cut checkout latency by 40% by caching tax lookups" ||
+		replaced[0].After != "Cut checkout latency by 40% by caching tax lookups for 3M orders a month" || !replaced[0].Bullet {
+		t.Fatalf("replaced = %+v", replaced)
+	}
+	if len(inserted) != 1 || inserted[0].After != "Introduced contract tests for partner APIs" || !inserted[0].Bullet {
+		t.Fatalf("inserted = %+v", inserted)
+	}
+
+	if diff.Summary.Replaced != 1 || diff.Summary.Inserted != 2 || diff.Summary.Deleted != 3 {
+		t.Fatalf("summary = %+v", diff.Summary)
+	}
+}
+
+func TestDiffResumeTextIdentical(t *testing.T) {
+	diff := DiffResumeText(plainResume, plainResume)
+	if diff.Summary.Inserted+diff.Summary.Deleted+diff.Summary.Replaced != 0 || diff.Summary.Unchanged == 0 {
+		t.Fatalf("summary = %+v", diff.Summary)
+	}
+	for _, sec := range diff.Sections {
+		if sec.Status != SectionUnchanged {
+			t.Fatalf("section %s is %s", sec.Section, sec.Status)
+		}
+	}
+}